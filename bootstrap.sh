@@ -197,6 +197,37 @@ install_consul() {
 }
 
 
+# Download and install nats-server, link nats-server binary into our root.
+install_nats_server() {
+  local version="$1"
+  local dist="$2"
+
+  case $(uname) in
+    Linux)  local platform=linux; local ext=tar.gz;;
+    Darwin) local platform=darwin; local ext=zip;;
+    *)   echo "ERROR: unsupported platform for nats-server"; exit 1;;
+  esac
+
+  case $(get_arch) in
+      aarch64)  local target=arm64;;
+      x86_64)  local target=amd64;;
+      arm64)  local target=arm64;;
+      *)   echo "ERROR: unsupported architecture for nats-server"; exit 1;;
+  esac
+
+  file="nats-server-${version}-${platform}-${target}.${ext}"
+
+  "${VTROOT}/tools/wget-retry" "https://github.com/nats-io/nats-server/releases/download/$version/$file"
+  if [ "$ext" = "tar.gz" ]; then
+    tar xzf "$file"
+  else
+    unzip "$file"
+  fi
+  rm "$file"
+  ln -snf "$dist/nats-server-${version}-${platform}-${target}/nats-server" "$VTROOT/bin/nats-server"
+}
+
+
 # Download and install toxiproxy, link toxiproxy binary into our root.
 install_toxiproxy() {
   local version="$1"
@@ -243,6 +274,9 @@ install_all() {
     install_dep "Consul" "1.11.4" "$VTROOT/dist/consul" install_consul
   fi
 
+  # nats-server
+  install_dep "nats-server" "v2.10.4" "$VTROOT/dist/nats-server" install_nats_server
+
   # toxiproxy
   install_dep "toxiproxy" "v2.5.0" "$VTROOT/dist/toxiproxy" install_toxiproxy
 
@@ -16,3 +16,6 @@ var MycnfMySQL57 string
 
 //go:embed mycnf/mysql80.cnf
 var MycnfMySQL80 string
+
+//go:embed mycnf/mysql84.cnf
+var MycnfMySQL84 string
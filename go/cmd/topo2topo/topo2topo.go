@@ -18,8 +18,10 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/pflag"
 
@@ -46,6 +48,8 @@ var (
 	doShardReplications bool
 	doTablets           bool
 	doRoutingRules      bool
+	mirror              bool
+	mirrorInterval      = 10 * time.Second
 )
 
 func init() {
@@ -62,6 +66,8 @@ func init() {
 		fs.BoolVar(&doShardReplications, "do-shard-replications", doShardReplications, "copies the shard replication information")
 		fs.BoolVar(&doTablets, "do-tablets", doTablets, "copies the tablet information")
 		fs.BoolVar(&doRoutingRules, "do-routing-rules", doRoutingRules, "copies the routing rules")
+		fs.BoolVar(&mirror, "mirror", mirror, "continuously mirrors the global topology to the destination topology, the standby, until it is promoted with PromoteTopo")
+		fs.DurationVar(&mirrorInterval, "mirror_interval", mirrorInterval, "how often the standby is synced with --mirror")
 
 		acl.RegisterFlags(fs)
 	})
@@ -94,6 +100,10 @@ func main() {
 		compareTopos(ctx, fromTS, toTS)
 		return
 	}
+	if mirror {
+		mirrorTopos(ctx, fromTS, toTS)
+		return
+	}
 	copyTopos(ctx, fromTS, toTS)
 }
 
@@ -115,6 +125,16 @@ func copyTopos(ctx context.Context, fromTS, toTS *topo.Server) {
 	}
 }
 
+func mirrorTopos(ctx context.Context, fromTS, toTS *topo.Server) {
+	source := fmt.Sprintf("%v %v %v", fromImplementation, fromServerAddress, fromRoot)
+	err := helpers.NewTopoMirror(fromTS, toTS, source).Run(ctx, mirrorInterval)
+	if errors.Is(err, helpers.ErrTopoPromoted) {
+		log.Infof("The standby topology was promoted, stopping the mirror")
+		return
+	}
+	log.Exitf("Topo mirror failed: %v", err)
+}
+
 func compareTopos(ctx context.Context, fromTS, toTS *topo.Server) {
 	var err error
 	if doKeyspaces {
@@ -0,0 +1,169 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// vstream2kafka publishes the row events of a vtgate VStream to Kafka.
+package main
+
+import (
+	"context"
+	"errors"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/pflag"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"vitess.io/vitess/go/exit"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vstreamkafka"
+	"vitess.io/vitess/go/vt/vtgate/vtgateconn"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+var (
+	server            string
+	keyspace          string
+	tabletType        = topodatapb.TabletType_REPLICA
+	filter            string
+	copyTables        bool
+	brokers           []string
+	topicPrefix       = "vitess"
+	encoding          = vstreamkafka.EncodingAvro
+	schemaRegistryURL string
+	partitionBy       = vstreamkafka.PartitionByPrimaryKey
+	produceTimeout    = 30 * time.Second
+	compression       = "none"
+	positionFile      string
+	retryDelay        = 5 * time.Second
+
+	tlsEnabled             bool
+	tlsCA                  string
+	tlsCert                string
+	tlsKey                 string
+	tlsServerName          string
+	saslMechanism          string
+	saslUser               string
+	saslPassword           string
+	schemaRegistryUser     string
+	schemaRegistryPassword string
+)
+
+func registerFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&server, "server", server, "vtgate server to stream from")
+	fs.StringVar(&keyspace, "keyspace", keyspace, "keyspace to stream, which can be a regular expression like /.*, or empty to stream all the keyspaces")
+	fs.Var((*topoproto.TabletTypeFlag)(&tabletType), "tablet-type", "type of the tablets to stream from")
+	fs.StringVar(&filter, "filter", filter, "JSON binlogdata.Filter whose rules select the tables, columns and rows to stream; all the tables are streamed by default")
+	fs.BoolVar(&copyTables, "copy", copyTables, "publish the existing rows of the tables before their changes, when there is no saved position")
+	fs.StringSliceVar(&brokers, "brokers", brokers, "bootstrap brokers of the Kafka cluster")
+	fs.StringVar(&topicPrefix, "topic-prefix", topicPrefix, "prefix of the topics, which are named <prefix>.<keyspace>.<table>")
+	fs.StringVar(&encoding, "encoding", encoding, "encoding of the messages: avro or protobuf")
+	fs.StringVar(&schemaRegistryURL, "schema-registry-url", schemaRegistryURL, "URL of the schema registry where the schemas of the messages are registered")
+	fs.StringVar(&partitionBy, "partition-by", partitionBy, "partitioning of the messages: primary_key, or keyspace_id when the rules of the filter select keyspace_id()")
+	fs.DurationVar(&produceTimeout, "produce-timeout", produceTimeout, "timeout of the produce requests to Kafka")
+	fs.StringVar(&compression, "compression", compression, "compression of the messages: none, gzip, snappy, lz4 or zstd")
+	fs.StringVar(&positionFile, "position-file", positionFile, "file where the position of the stream is saved after each published transaction, and resumed from on restart")
+	fs.DurationVar(&retryDelay, "retry-delay", retryDelay, "delay before resuming the stream after an error")
+	fs.BoolVar(&tlsEnabled, "tls", tlsEnabled, "connect to the Kafka brokers, and to the schema registry when its URL is https, with TLS")
+	fs.StringVar(&tlsCA, "tls-ca", tlsCA, "file of the CA that verifies the certificates of the brokers, instead of the system roots")
+	fs.StringVar(&tlsCert, "tls-cert", tlsCert, "file of the client certificate presented to the brokers")
+	fs.StringVar(&tlsKey, "tls-key", tlsKey, "file of the key of the client certificate")
+	fs.StringVar(&tlsServerName, "tls-server-name", tlsServerName, "name the certificates of the brokers are verified against, instead of their host names")
+	fs.StringVar(&saslMechanism, "sasl-mechanism", saslMechanism, "SASL mechanism that authenticates to the brokers: PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512")
+	fs.StringVar(&saslUser, "sasl-user", saslUser, "SASL user")
+	fs.StringVar(&saslPassword, "sasl-password", saslPassword, "SASL password")
+	fs.StringVar(&schemaRegistryUser, "schema-registry-user", schemaRegistryUser, "user of the basic authentication to the schema registry")
+	fs.StringVar(&schemaRegistryPassword, "schema-registry-password", schemaRegistryPassword, "password of the basic authentication to the schema registry")
+}
+
+func init() {
+	servenv.OnParseFor("vstream2kafka", registerFlags)
+}
+
+func main() {
+	defer exit.Recover()
+	defer logutil.Flush()
+
+	servenv.ParseFlags("vstream2kafka")
+	servenv.Init()
+
+	if server == "" {
+		log.Exitf("--server is required")
+	}
+	config := vstreamkafka.Config{
+		Keyspace:          keyspace,
+		TabletType:        tabletType,
+		Copy:              copyTables,
+		Brokers:           brokers,
+		TopicPrefix:       topicPrefix,
+		Encoding:          encoding,
+		SchemaRegistryURL: schemaRegistryURL,
+		PartitionBy:       partitionBy,
+		Timeout:           produceTimeout,
+		Compression:       compression,
+		PositionFile:      positionFile,
+
+		TLS:                    tlsEnabled,
+		TLSCA:                  tlsCA,
+		TLSCert:                tlsCert,
+		TLSKey:                 tlsKey,
+		TLSServerName:          tlsServerName,
+		SASLMechanism:          saslMechanism,
+		SASLUser:               saslUser,
+		SASLPassword:           saslPassword,
+		SchemaRegistryUser:     schemaRegistryUser,
+		SchemaRegistryPassword: schemaRegistryPassword,
+	}
+	if filter != "" {
+		config.Filter = &binlogdatapb.Filter{}
+		if err := protojson.Unmarshal([]byte(filter), config.Filter); err != nil {
+			log.Exitf("Invalid --filter: %v", err)
+		}
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	conn, err := vtgateconn.DialProtocol(ctx, vtgateconn.GetVTGateProtocol(), server)
+	if err != nil {
+		log.Exitf("Cannot connect to vtgate %s: %v", server, err)
+	}
+	defer conn.Close()
+
+	connector, err := vstreamkafka.NewConnector(config, conn)
+	if err != nil {
+		log.Exitf("Cannot create the connector: %v", err)
+	}
+	defer connector.Close()
+
+	for {
+		err := connector.Run(ctx)
+		if ctx.Err() != nil || errors.Is(err, context.Canceled) {
+			return
+		}
+		log.Errorf("Stream failed, resuming in %v: %v", retryDelay, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryDelay):
+		}
+	}
+}
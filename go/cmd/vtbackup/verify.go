@@ -0,0 +1,341 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// verifyLatestBackup runs a restore drill of the latest full backup of the
+// shard, unless it already passed one, and records the result in its MANIFEST.
+// It returns an error if the drill fails or finds mismatches.
+func verifyLatestBackup(ctx context.Context, topoServer *topo.Server, backupStorage backupstorage.BackupStorage, backupDir string) error {
+	backups, err := backupStorage.ListBackups(ctx, backupDir)
+	if err != nil {
+		return fmt.Errorf("can't list backups: %v", err)
+	}
+	bh, manifest := lastCompleteFullBackup(ctx, backups)
+	if bh == nil {
+		return fmt.Errorf("no complete full backup to verify in %v", backupDir)
+	}
+	if manifest.Verification != nil && manifest.Verification.Passed() {
+		log.Infof("Backup %v was already verified at %v. Not verifying it again.", bh.Name(), manifest.Verification.Time)
+		return nil
+	}
+
+	log.Infof("Verifying backup %v", bh.Name())
+	verifyAt := time.Now()
+	verification := &mysqlctl.BackupVerification{}
+	if err := restoreDrill(ctx, topoServer, bh, verification); err != nil {
+		verification.Error = err.Error()
+	}
+	verification.Time = time.Now().UTC().Format(time.RFC3339)
+	durationByPhase.Set("VerifyBackup", int64(time.Since(verifyAt).Seconds()))
+
+	if err := mysqlctl.RecordBackupVerification(ctx, backupStorage, bh, verification); err != nil {
+		return fmt.Errorf("can't record verification of backup %v: %v", bh.Name(), err)
+	}
+	switch {
+	case verification.Error != "":
+		return fmt.Errorf("restore drill of backup %v failed: %v", bh.Name(), verification.Error)
+	case len(verification.Mismatches) > 0:
+		return fmt.Errorf("restore drill of backup %v found tables which differ from replica %v: %v", bh.Name(), verification.Replica, strings.Join(verification.Mismatches, ", "))
+	}
+	log.Infof("Backup %v verified: %d tables match replica %v at position %v", bh.Name(), verification.TablesChecked, verification.Replica, verification.Position)
+	return nil
+}
+
+// lastCompleteFullBackup returns the most recent backup which has a MANIFEST
+// and is not incremental, along with its MANIFEST.
+func lastCompleteFullBackup(ctx context.Context, backups []backupstorage.BackupHandle) (backupstorage.BackupHandle, *mysqlctl.BackupManifest) {
+	for i := len(backups) - 1; i >= 0; i-- {
+		manifest, err := mysqlctl.GetBackupManifest(ctx, backups[i])
+		if err != nil {
+			log.Warningf("Ignoring backup %v because it's incomplete: %v", backups[i].Name(), err)
+			continue
+		}
+		if manifest.Incremental {
+			continue
+		}
+		return backups[i], manifest
+	}
+	return nil, nil
+}
+
+// restoreDrill restores the backup to a scratch mysqld, catches it up with the
+// primary, and then stops a replica and the scratch mysqld at the same
+// position to compare the checksums and row counts of their tables.
+func restoreDrill(ctx context.Context, topoServer *topo.Server, bh backupstorage.BackupHandle, verification *mysqlctl.BackupVerification) error {
+	backupTime, err := parseBackupTime(bh.Name())
+	if err != nil {
+		return err
+	}
+	replica, err := verificationReplica(ctx, topoServer)
+	if err != nil {
+		return err
+	}
+	verification.Replica = topoproto.TabletAliasString(replica.Alias)
+
+	tabletAlias, mysqld, mycnf, cleanup, err := startScratchMysqld(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	dbName := initDbNameOverride
+	if dbName == "" {
+		dbName = fmt.Sprintf("vt_%s", initKeyspace)
+	}
+	restoreAt := time.Now()
+	manifest, err := mysqlctl.Restore(ctx, mysqlctl.RestoreParams{
+		Cnf:                 mycnf,
+		Mysqld:              mysqld,
+		Logger:              logutil.NewConsoleLogger(),
+		Concurrency:         concurrency,
+		HookExtraEnv:        map[string]string{"TABLET_ALIAS": topoproto.TabletAliasString(tabletAlias)},
+		DeleteBeforeRestore: true,
+		DbName:              dbName,
+		Keyspace:            initKeyspace,
+		Shard:               initShard,
+		StartTime:           backupTime,
+		Stats:               backupstats.RestoreStats(),
+	})
+	if err != nil {
+		return fmt.Errorf("can't restore backup: %v", err)
+	}
+	durationByPhase.Set("VerifyRestoreBackup", int64(time.Since(restoreAt).Seconds()))
+
+	// Catch up with the primary first, so that the replica only has to be
+	// stopped for the last few transactions.
+	catchUpCtx, catchUpCancel := context.WithTimeout(ctx, verifyBackupTimeout)
+	defer catchUpCancel()
+	if err := resetReplication(catchUpCtx, manifest.Position, mysqld); err != nil {
+		return fmt.Errorf("error resetting replication: %v", err)
+	}
+	if err := startReplication(catchUpCtx, mysqld, topoServer); err != nil {
+		return fmt.Errorf("error starting replication: %v", err)
+	}
+	tmc := tmclient.NewTabletManagerClient()
+	defer tmc.Close()
+	primaryPos, err := getPrimaryPosition(catchUpCtx, tmc, topoServer)
+	if err != nil {
+		return err
+	}
+	if err := mysqld.WaitSourcePos(catchUpCtx, primaryPos); err != nil {
+		return fmt.Errorf("restored backup did not catch up with the primary position %v: %v", primaryPos, err)
+	}
+	if err := mysqld.StopReplication(nil); err != nil {
+		return fmt.Errorf("can't stop replication: %v", err)
+	}
+	status, err := mysqld.ReplicationStatus()
+	if err != nil {
+		return fmt.Errorf("can't get replication status: %v", err)
+	}
+
+	// Stop the replica at or after our position, and bring us to the same
+	// position, so that both hold the same data while we compare them.
+	posStr, err := tmc.StopReplicationMinimum(catchUpCtx, replica.Tablet, replication.EncodePosition(status.Position), verifyBackupTimeout)
+	if err != nil {
+		return fmt.Errorf("can't stop replication on replica %v: %v", verification.Replica, err)
+	}
+	defer restartReplicaReplication(topoServer, tmc, replica)
+	replicaPos, err := replication.DecodePosition(posStr)
+	if err != nil {
+		return fmt.Errorf("can't decode replica position %q: %v", posStr, err)
+	}
+	verification.Position = posStr
+	if err := mysqld.StartReplicationUntilAfter(catchUpCtx, replicaPos); err != nil {
+		return fmt.Errorf("can't start replication until %v: %v", posStr, err)
+	}
+	if err := mysqld.WaitSourcePos(catchUpCtx, replicaPos); err != nil {
+		return fmt.Errorf("restored backup did not catch up with the replica position %v: %v", posStr, err)
+	}
+
+	compareAt := time.Now()
+	if err := compareTables(ctx, mysqld, tmc, replica.Tablet, dbName, verification); err != nil {
+		return err
+	}
+	durationByPhase.Set("VerifyCompareTables", int64(time.Since(compareAt).Seconds()))
+	return nil
+}
+
+// verificationReplica returns the tablet given by --verify-backup-replica, or
+// else an RDONLY or REPLICA tablet of the shard, preferring RDONLY tablets to
+// stay out of the way of serving traffic.
+func verificationReplica(ctx context.Context, topoServer *topo.Server) (*topo.TabletInfo, error) {
+	if verifyBackupReplica != "" {
+		alias, err := topoproto.ParseTabletAlias(verifyBackupReplica)
+		if err != nil {
+			return nil, err
+		}
+		return topoServer.GetTablet(ctx, alias)
+	}
+
+	tablets, err := topoServer.GetTabletMapForShard(ctx, initKeyspace, initShard)
+	if err != nil && !topo.IsErrType(err, topo.PartialResult) {
+		return nil, fmt.Errorf("can't list the tablets of shard %v/%v: %v", initKeyspace, initShard, err)
+	}
+	candidates := make([]*topo.TabletInfo, 0, len(tablets))
+	for _, ti := range tablets {
+		if ti.Type == topodatapb.TabletType_RDONLY || ti.Type == topodatapb.TabletType_REPLICA {
+			candidates = append(candidates, ti)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("shard %v/%v has no REPLICA or RDONLY tablet to compare the restored backup with", initKeyspace, initShard)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Type != candidates[j].Type {
+			return candidates[i].Type == topodatapb.TabletType_RDONLY
+		}
+		return topoproto.TabletAliasString(candidates[i].Alias) < topoproto.TabletAliasString(candidates[j].Alias)
+	})
+	return candidates[0], nil
+}
+
+// restartReplicaReplication restarts replication on the replica once we're
+// done comparing, with semi-sync enabled if the durability policy requires it.
+func restartReplicaReplication(topoServer *topo.Server, tmc tmclient.TabletManagerClient, replica *topo.TabletInfo) {
+	// Be careful not to use the original context, because we don't want to
+	// leave replication stopped just because we timed out on other things.
+	ctx, cancel := context.WithTimeout(context.Background(), operationTimeout)
+	defer cancel()
+
+	semiSync := false
+	if ki, err := topoServer.GetKeyspace(ctx, initKeyspace); err != nil {
+		log.Warningf("Can't read keyspace %v to restart replication on replica %v with the right semi-sync setting: %v", initKeyspace, topoproto.TabletAliasString(replica.Alias), err)
+	} else if durability, err := reparentutil.GetDurabilityPolicy(ki.DurabilityPolicy); err != nil {
+		log.Warningf("Can't get durability policy of keyspace %v: %v", initKeyspace, err)
+	} else if si, err := topoServer.GetShard(ctx, initKeyspace, initShard); err != nil {
+		log.Warningf("Can't read shard %v/%v: %v", initKeyspace, initShard, err)
+	} else if primary, err := topoServer.GetTablet(ctx, si.PrimaryAlias); err != nil {
+		log.Warningf("Can't read primary tablet %v: %v", topoproto.TabletAliasString(si.PrimaryAlias), err)
+	} else {
+		semiSync = reparentutil.IsReplicaSemiSync(durability, primary.Tablet, replica.Tablet)
+	}
+
+	if err := tmc.StartReplication(ctx, replica.Tablet, semiSync); err != nil {
+		log.Errorf("Failed to restart replication on replica %v: %v", topoproto.TabletAliasString(replica.Alias), err)
+	}
+}
+
+// compareTables compares the row counts and checksums of the tables of the
+// database on the scratch mysqld with the ones on the replica, and records
+// the tables which differ.
+func compareTables(ctx context.Context, mysqld mysqlctl.MysqlDaemon, tmc tmclient.TabletManagerClient, replica *topodatapb.Tablet, dbName string, verification *mysqlctl.BackupVerification) error {
+	fetchLocal := func(query string) (*sqltypes.Result, error) {
+		return mysqld.FetchSuperQuery(ctx, query)
+	}
+	fetchReplica := func(query string) (*sqltypes.Result, error) {
+		qr, err := tmc.ExecuteFetchAsDba(ctx, replica, false /* usePool */, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+			Query:   []byte(query),
+			MaxRows: 100000,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return sqltypes.Proto3ToResult(qr), nil
+	}
+
+	tablesQuery := fmt.Sprintf("SELECT table_name FROM information_schema.tables WHERE table_schema = %s AND table_type = 'BASE TABLE' ORDER BY table_name", sqltypes.EncodeStringSQL(dbName))
+	localTables, err := fetchColumn(fetchLocal, tablesQuery, 0)
+	if err != nil {
+		return fmt.Errorf("can't list the tables of the restored backup: %v", err)
+	}
+	replicaTables, err := fetchColumn(fetchReplica, tablesQuery, 0)
+	if err != nil {
+		return fmt.Errorf("can't list the tables of the replica: %v", err)
+	}
+
+	onReplica := make(map[string]bool, len(replicaTables))
+	for _, table := range replicaTables {
+		onReplica[table] = true
+	}
+	for _, table := range localTables {
+		if !onReplica[table] {
+			verification.Mismatches = append(verification.Mismatches, fmt.Sprintf("%s.%s: not on the replica", dbName, table))
+			continue
+		}
+		delete(onReplica, table)
+
+		name := sqlescape.EscapeID(dbName) + "." + sqlescape.EscapeID(table)
+		for _, check := range []struct {
+			what   string
+			query  string
+			column int
+		}{
+			{"row count", "SELECT COUNT(*) FROM " + name, 0},
+			{"checksum", "CHECKSUM TABLE " + name, 1},
+		} {
+			local, err := fetchColumn(fetchLocal, check.query, check.column)
+			if err != nil {
+				return fmt.Errorf("can't get the %s of %s in the restored backup: %v", check.what, name, err)
+			}
+			remote, err := fetchColumn(fetchReplica, check.query, check.column)
+			if err != nil {
+				return fmt.Errorf("can't get the %s of %s on the replica: %v", check.what, name, err)
+			}
+			if strings.Join(local, ",") != strings.Join(remote, ",") {
+				verification.Mismatches = append(verification.Mismatches, fmt.Sprintf("%s.%s: %s %v in the backup, %v on the replica", dbName, table, check.what, local, remote))
+				break
+			}
+		}
+		verification.TablesChecked++
+	}
+	for _, table := range replicaTables {
+		if onReplica[table] {
+			verification.Mismatches = append(verification.Mismatches, fmt.Sprintf("%s.%s: not in the backup", dbName, table))
+		}
+	}
+	return nil
+}
+
+// fetchColumn returns the values of a column of the result of the query.
+func fetchColumn(fetch func(query string) (*sqltypes.Result, error), query string, column int) ([]string, error) {
+	qr, err := fetch(query)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]string, 0, len(qr.Rows))
+	for _, row := range qr.Rows {
+		if column >= len(row) {
+			return nil, fmt.Errorf("unexpected result for %q: %v", query, row)
+		}
+		values = append(values, row[column].ToString())
+	}
+	return values, nil
+}
@@ -43,6 +43,16 @@ The process vtbackup follows to take a new backup is as follows:
  5. Wait until replication is caught up to the goal position or beyond.
  6. Stop mysqld and take a new backup.
 
+With --verify-backup, vtbackup then runs a restore drill of the latest backup,
+unless it already passed one:
+ 1. Restore the backup to a scratch mysqld instance.
+ 2. Catch up on replication from the shard primary.
+ 3. Stop replication on a live replica at or after that position, and replicate
+    up to the exact position of the replica.
+ 4. Compare the checksums and row counts of the tables of both, restart
+    replication on the replica, and record the result of the drill in the
+    MANIFEST of the backup, which ValidateBackup reports.
+
 Aside from additional replication load while vtbackup's mysqld catches up on
 new transactions, the shard should be otherwise unaffected. Existing tablets
 will continue to serve, and no new tablets will appear in topology, meaning no
@@ -109,6 +119,14 @@ var (
 	allowFirstBackup    bool
 	restartBeforeBackup bool
 	upgradeSafe         bool
+	verifyBackup        bool
+	verifyBackupReplica string
+	verifyBackupTimeout = 1 * time.Hour
+	// backup scheduling flags
+	backupSlotsCell             string
+	maxConcurrentBackupsPerCell int
+	backupStartStagger          time.Duration
+	backupSlotTimeout           time.Duration
 	// vttablet-like flags
 	initDbNameOverride string
 	initKeyspace       string
@@ -133,11 +151,18 @@ var (
 func registerFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&minBackupInterval, "min_backup_interval", minBackupInterval, "Only take a new backup if it's been at least this long since the most recent backup.")
 	fs.DurationVar(&minRetentionTime, "min_retention_time", minRetentionTime, "Keep each old backup for at least this long before removing it. Set to 0 to disable pruning of old backups.")
-	fs.IntVar(&minRetentionCount, "min_retention_count", minRetentionCount, "Always keep at least this many of the most recent backups in this backup storage location, even if some are older than the min_retention_time. This must be at least 1 since a backup must always exist to allow new backups to be made")
+	fs.IntVar(&minRetentionCount, "min_retention_count", minRetentionCount, "Always keep at least this many of the most recent complete full backups in this backup storage location, along with the incremental backups taken since, even if some are older than the min_retention_time. This must be at least 1 since a backup must always exist to allow new backups to be made")
 	fs.BoolVar(&initialBackup, "initial_backup", initialBackup, "Instead of restoring from backup, initialize an empty database with the provided init_db_sql_file and upload a backup of that for the shard, if the shard has no backups yet. This can be used to seed a brand new shard with an initial, empty backup. If any backups already exist for the shard, this will be considered a successful no-op. This can only be done before the shard exists in topology (i.e. before any tablets are deployed).")
 	fs.BoolVar(&allowFirstBackup, "allow_first_backup", allowFirstBackup, "Allow this job to take the first backup of an existing shard.")
 	fs.BoolVar(&restartBeforeBackup, "restart_before_backup", restartBeforeBackup, "Perform a mysqld clean/full restart after applying binlogs, but before taking the backup. Only makes sense to work around xtrabackup bugs.")
 	fs.BoolVar(&upgradeSafe, "upgrade-safe", upgradeSafe, "Whether to use innodb_fast_shutdown=0 for the backup so it is safe to use for MySQL upgrades.")
+	fs.BoolVar(&verifyBackup, "verify-backup", verifyBackup, "Run a restore drill of the latest backup, unless it already passed one: restore it to a scratch mysqld, replicate up to the position of a live replica, compare the checksums and row counts of their tables, and record the result in the backup MANIFEST. Old backups are not pruned if the drill fails.")
+	fs.StringVar(&verifyBackupReplica, "verify-backup-replica", verifyBackupReplica, "Alias of the tablet to compare the restored backup with in --verify-backup mode. Defaults to an RDONLY, or else a REPLICA, tablet of the shard. Its replication is stopped while the tables are compared.")
+	fs.DurationVar(&verifyBackupTimeout, "verify-backup-timeout", verifyBackupTimeout, "How long to wait, in --verify-backup mode, for the restored backup to catch up with the primary and then with the replica it is compared with.")
+	fs.StringVar(&backupSlotsCell, "backup-slots-cell", backupSlotsCell, "Cell whose topo server is used to coordinate the vtbackup instances of all shards sharing the backup storage, with --max-concurrent-backups-per-cell and --backup-start-stagger.")
+	fs.IntVar(&maxConcurrentBackupsPerCell, "max-concurrent-backups-per-cell", maxConcurrentBackupsPerCell, "Maximum number of vtbackup instances taking or verifying a backup at the same time in --backup-slots-cell. Others wait for one of them to finish. 0 means no limit.")
+	fs.DurationVar(&backupStartStagger, "backup-start-stagger", backupStartStagger, "Minimum time between the starts of two backups in --backup-slots-cell, to spread the load on the backup storage.")
+	fs.DurationVar(&backupSlotTimeout, "backup-slot-timeout", backupSlotTimeout, "How long to wait for a backup slot in --backup-slots-cell before giving up. 0 means no limit.")
 	// vttablet-like flags
 	fs.StringVar(&initDbNameOverride, "init_db_name_override", initDbNameOverride, "(init parameter) override the name of the db used by vttablet")
 	fs.StringVar(&initKeyspace, "init_keyspace", initKeyspace, "(init parameter) keyspace to use for this tablet")
@@ -191,6 +216,10 @@ func main() {
 		log.Errorf("min_retention_count must be at least 1 to allow restores to succeed")
 		exit.Return(1)
 	}
+	if (maxConcurrentBackupsPerCell > 0 || backupStartStagger > 0) && backupSlotsCell == "" {
+		log.Errorf("--backup-slots-cell is required with --max-concurrent-backups-per-cell and --backup-start-stagger")
+		exit.Return(1)
+	}
 
 	// Open connection backup storage.
 	backupStorage, err := backupstorage.GetBackupStorage()
@@ -212,6 +241,17 @@ func main() {
 		log.Errorf("Can't take backup: %v", err)
 		exit.Return(1)
 	}
+	// Wait for our turn before loading the backup storage, if the backups of
+	// the cell are coordinated.
+	if backupSlotsCell != "" && (doBackup || verifyBackup) {
+		release, err := acquireBackupSlot(ctx, topoServer)
+		if err != nil {
+			log.Errorf("Can't acquire a backup slot: %v", err)
+			exit.Return(1)
+		}
+		defer release()
+	}
+
 	if doBackup {
 		if err := takeBackup(ctx, topoServer, backupStorage); err != nil {
 			log.Errorf("Failed to take backup: %v", err)
@@ -219,6 +259,15 @@ func main() {
 		}
 	}
 
+	// Verify the latest backup. Skip pruning if it fails, so that we don't
+	// delete the older backups we may have to fall back on.
+	if verifyBackup {
+		if err := verifyLatestBackup(ctx, topoServer, backupStorage, backupDir); err != nil {
+			log.Errorf("Backup verification failed: %v", err)
+			exit.Return(1)
+		}
+	}
+
 	// Prune old backups.
 	if err := pruneBackups(ctx, backupStorage, backupDir); err != nil {
 		log.Errorf("Couldn't prune old backups: %v", err)
@@ -235,54 +284,30 @@ func main() {
 	log.Info("Exiting.")
 }
 
-func takeBackup(ctx context.Context, topoServer *topo.Server, backupStorage backupstorage.BackupStorage) error {
-	// This is an imaginary tablet alias. The value doesn't matter for anything,
-	// except that we generate a random UID to ensure the target backup
-	// directory is unique if multiple vtbackup instances are launched for the
-	// same shard, at exactly the same second, pointed at the same backup
-	// storage location.
-	bigN, err := rand.Int(rand.Reader, big.NewInt(math.MaxUint32))
-	if err != nil {
-		return fmt.Errorf("can't generate random tablet UID: %v", err)
-	}
-	tabletAlias := &topodatapb.TabletAlias{
-		Cell: "vtbackup",
-		Uid:  uint32(bigN.Uint64()),
+// acquireBackupSlot waits for a slot to take or verify a backup in
+// --backup-slots-cell, and returns the function releasing it.
+func acquireBackupSlot(ctx context.Context, topoServer *topo.Server) (func(), error) {
+	if backupSlotTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, backupSlotTimeout)
+		defer cancel()
 	}
 
-	// Clean up our temporary data dir if we exit for any reason, to make sure
-	// every invocation of vtbackup starts with a clean slate, and it does not
-	// accumulate garbage (and run out of disk space) if it's restarted.
-	tabletDir := mysqlctl.TabletDir(tabletAlias.Uid)
-	defer func() {
-		log.Infof("Removing temporary tablet directory: %v", tabletDir)
-		if err := os.RemoveAll(tabletDir); err != nil {
-			log.Warningf("Failed to remove temporary tablet directory: %v", err)
-		}
-	}()
-
-	// Start up mysqld as if we are mysqlctld provisioning a fresh tablet.
-	mysqld, mycnf, err := mysqlctl.CreateMysqldAndMycnf(tabletAlias.Uid, mysqlSocket, mysqlPort)
+	waitAt := time.Now()
+	release, err := topoServer.AcquireBackupSlot(ctx, backupSlotsCell, initKeyspace, initShard, maxConcurrentBackupsPerCell, backupStartStagger)
 	if err != nil {
-		return fmt.Errorf("failed to initialize mysql config: %v", err)
+		return nil, err
 	}
-	initCtx, initCancel := context.WithTimeout(ctx, mysqlTimeout)
-	defer initCancel()
-	initMysqldAt := time.Now()
-	if err := mysqld.Init(initCtx, mycnf, initDBSQLFile); err != nil {
-		return fmt.Errorf("failed to initialize mysql data dir and start mysqld: %v", err)
+	durationByPhase.Set("WaitForBackupSlot", int64(time.Since(waitAt).Seconds()))
+	return release, nil
+}
+
+func takeBackup(ctx context.Context, topoServer *topo.Server, backupStorage backupstorage.BackupStorage) error {
+	tabletAlias, mysqld, mycnf, cleanup, err := startScratchMysqld(ctx)
+	if err != nil {
+		return err
 	}
-	durationByPhase.Set("InitMySQLd", int64(time.Since(initMysqldAt).Seconds()))
-	// Shut down mysqld when we're done.
-	defer func() {
-		// Be careful not to use the original context, because we don't want to
-		// skip shutdown just because we timed out waiting for other things.
-		mysqlShutdownCtx, mysqlShutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer mysqlShutdownCancel()
-		if err := mysqld.Shutdown(mysqlShutdownCtx, mycnf, false); err != nil {
-			log.Errorf("failed to shutdown mysqld: %v", err)
-		}
-	}()
+	defer cleanup()
 
 	extraEnv := map[string]string{
 		"TABLET_ALIAS": topoproto.TabletAliasString(tabletAlias),
@@ -517,6 +542,62 @@ func takeBackup(ctx context.Context, topoServer *topo.Server, backupStorage back
 	return nil
 }
 
+// startScratchMysqld starts up mysqld as if we are mysqlctld provisioning a
+// fresh tablet. It returns a function which shuts mysqld down and removes its
+// data, to be called when we're done.
+func startScratchMysqld(ctx context.Context) (*topodatapb.TabletAlias, *mysqlctl.Mysqld, *mysqlctl.Mycnf, func(), error) {
+	// This is an imaginary tablet alias. The value doesn't matter for anything,
+	// except that we generate a random UID to ensure the target backup
+	// directory is unique if multiple vtbackup instances are launched for the
+	// same shard, at exactly the same second, pointed at the same backup
+	// storage location.
+	bigN, err := rand.Int(rand.Reader, big.NewInt(math.MaxUint32))
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("can't generate random tablet UID: %v", err)
+	}
+	tabletAlias := &topodatapb.TabletAlias{
+		Cell: "vtbackup",
+		Uid:  uint32(bigN.Uint64()),
+	}
+
+	// Clean up our temporary data dir if we exit for any reason, to make sure
+	// every invocation of vtbackup starts with a clean slate, and it does not
+	// accumulate garbage (and run out of disk space) if it's restarted.
+	tabletDir := mysqlctl.TabletDir(tabletAlias.Uid)
+	removeTabletDir := func() {
+		log.Infof("Removing temporary tablet directory: %v", tabletDir)
+		if err := os.RemoveAll(tabletDir); err != nil {
+			log.Warningf("Failed to remove temporary tablet directory: %v", err)
+		}
+	}
+
+	mysqld, mycnf, err := mysqlctl.CreateMysqldAndMycnf(tabletAlias.Uid, mysqlSocket, mysqlPort)
+	if err != nil {
+		removeTabletDir()
+		return nil, nil, nil, nil, fmt.Errorf("failed to initialize mysql config: %v", err)
+	}
+	initCtx, initCancel := context.WithTimeout(ctx, mysqlTimeout)
+	defer initCancel()
+	initMysqldAt := time.Now()
+	if err := mysqld.Init(initCtx, mycnf, initDBSQLFile); err != nil {
+		removeTabletDir()
+		return nil, nil, nil, nil, fmt.Errorf("failed to initialize mysql data dir and start mysqld: %v", err)
+	}
+	durationByPhase.Set("InitMySQLd", int64(time.Since(initMysqldAt).Seconds()))
+
+	cleanup := func() {
+		// Be careful not to use the original context, because we don't want to
+		// skip shutdown just because we timed out waiting for other things.
+		mysqlShutdownCtx, mysqlShutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer mysqlShutdownCancel()
+		if err := mysqld.Shutdown(mysqlShutdownCtx, mycnf, false); err != nil {
+			log.Errorf("failed to shutdown mysqld: %v", err)
+		}
+		removeTabletDir()
+	}
+	return tabletAlias, mysqld, mycnf, cleanup, nil
+}
+
 func resetReplication(ctx context.Context, pos replication.Position, mysqld mysqlctl.MysqlDaemon) error {
 	cmds := []string{
 		"STOP SLAVE",
@@ -618,40 +699,15 @@ func pruneBackups(ctx context.Context, backupStorage backupstorage.BackupStorage
 		log.Info("Pruning of old backups is disabled.")
 		return nil
 	}
-	backups, err := backupStorage.ListBackups(ctx, backupDir)
-	if err != nil {
-		return fmt.Errorf("can't list backups: %v", err)
-	}
-	numBackups := len(backups)
-	if numBackups <= minRetentionCount {
-		log.Infof("Found %v backups. Not pruning any since this is within the min_retention_count of %v.", numBackups, minRetentionCount)
-		return nil
+	policy := mysqlctl.BackupRetentionPolicy{
+		KeepFullBackups:  minRetentionCount,
+		MinRetentionTime: minRetentionTime,
 	}
-	// We have more than the minimum retention count, so we could afford to
-	// prune some. See if any are beyond the minimum retention time.
-	// ListBackups returns them sorted by oldest first.
-	for _, backup := range backups {
-		backupTime, err := parseBackupTime(backup.Name())
-		if err != nil {
-			return err
-		}
-		if time.Since(backupTime) < minRetentionTime {
-			// The oldest remaining backup is not old enough to prune.
-			log.Infof("Oldest backup taken at %v has not reached min_retention_time of %v. Nothing left to prune.", backupTime, minRetentionTime)
-			break
-		}
-		// Remove the backup.
-		log.Infof("Removing old backup %v from %v, since it's older than min_retention_time of %v", backup.Name(), backupDir, minRetentionTime)
-		if err := backupStorage.RemoveBackup(ctx, backupDir, backup.Name()); err != nil {
-			return fmt.Errorf("couldn't remove backup %v from %v: %v", backup.Name(), backupDir, err)
-		}
-		// We successfully removed one backup. Can we afford to prune any more?
-		numBackups--
-		if numBackups == minRetentionCount {
-			log.Infof("Successfully pruned backup count to min_retention_count of %v.", minRetentionCount)
-			break
-		}
+	purged, kept, err := mysqlctl.PurgeBackups(ctx, logutil.NewConsoleLogger(), backupStorage, backupDir, policy, false)
+	if err != nil {
+		return err
 	}
+	log.Infof("Pruned %v old backups from %v, kept %v (%v).", len(purged), backupDir, len(kept), policy)
 	return nil
 }
 
@@ -18,6 +18,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -43,12 +44,14 @@ import (
 var (
 	actionTimeout = time.Hour
 	server        string
+	format        = "text"
 )
 
 func init() {
 	servenv.OnParse(func(fs *pflag.FlagSet) {
 		fs.DurationVar(&actionTimeout, "action_timeout", actionTimeout, "timeout for the total command")
 		fs.StringVar(&server, "server", server, "server to use for connection")
+		fs.StringVar(&format, "format", format, "output format to use; valid choices are (text, json). In json mode, a single result object describing the command's status, output and warnings is printed once the command completes.")
 
 		acl.RegisterFlags(fs)
 	})
@@ -92,6 +95,19 @@ func main() {
 
 	checkDeprecations(args)
 
+	switch format {
+	case "text":
+	case "json":
+		if err := runJSON(ctx, args); err != nil {
+			log.Error(err)
+			os.Exit(1)
+		}
+		return
+	default:
+		log.Errorf("invalid --format %q; valid choices are (text, json)", format)
+		os.Exit(1)
+	}
+
 	err := vtctlclient.RunCommandAndWait(ctx, server, args, func(e *logutilpb.Event) {
 		logutil.LogEvent(logger, e)
 	})
@@ -106,3 +122,19 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// runJSON runs the command and prints a single vtctlclient.CommandResult as
+// JSON once it completes. The command's error, if any, is returned after the
+// result has been printed.
+func runJSON(ctx context.Context, args []string) error {
+	collector := vtctlclient.NewResultCollector(args)
+	err := vtctlclient.RunCommandAndWait(ctx, server, args, collector.Recv)
+
+	data, merr := json.MarshalIndent(collector.Finish(err), "", "  ")
+	if merr != nil {
+		return merr
+	}
+
+	fmt.Printf("%s\n", data)
+	return err
+}
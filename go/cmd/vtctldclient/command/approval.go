@@ -0,0 +1,86 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/vt/vtctld/approval"
+)
+
+var (
+	// GenerateApprovalToken generates an approval token locally, without
+	// contacting a vtctld.
+	GenerateApprovalToken = &cobra.Command{
+		Use:   "GenerateApprovalToken --approver <name> --secret-file <path> [--ttl <duration>] <command> <target>",
+		Short: "Generates a token approving a high-risk command, for vtctlds running with --approval-hook=token.",
+		Long: `Generates a token approving a high-risk command, for vtctlds running with --approval-hook=token.
+
+The target is the keyspace/shard of an EmergencyReparentShard, or the keyspace
+of a recursive DeleteKeyspace. The token is signed with the approver's secret,
+read from the given file, and must be passed by whoever runs the command, with
+--approval-token. Approvers cannot approve their own commands.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  commandGenerateApprovalToken,
+		Annotations: map[string]string{
+			skipClientCreationKey: "true",
+		},
+	}
+)
+
+var generateApprovalTokenOptions = struct {
+	Approver   string
+	SecretFile string
+	TTL        time.Duration
+}{}
+
+func commandGenerateApprovalToken(cmd *cobra.Command, args []string) error {
+	command := cmd.Flags().Arg(0)
+	target := cmd.Flags().Arg(1)
+
+	if !approval.MayRequireApproval(command) {
+		return fmt.Errorf("%s does not require approval", command)
+	}
+
+	cli.FinishedParsing(cmd)
+
+	secret, err := os.ReadFile(generateApprovalTokenOptions.SecretFile)
+	if err != nil {
+		return fmt.Errorf("cannot read the approver's secret: %w", err)
+	}
+
+	expiry := time.Now().Add(generateApprovalTokenOptions.TTL)
+	fmt.Fprintln(cmd.OutOrStdout(), approval.NewToken(generateApprovalTokenOptions.Approver, strings.TrimSpace(string(secret)), command, target, expiry))
+
+	return nil
+}
+
+func init() {
+	GenerateApprovalToken.Flags().StringVar(&generateApprovalTokenOptions.Approver, "approver", "", "Name of the approver, as listed in the approvers file of the vtctld.")
+	GenerateApprovalToken.Flags().StringVar(&generateApprovalTokenOptions.SecretFile, "secret-file", "", "Path to a file holding the approver's secret.")
+	GenerateApprovalToken.Flags().DurationVar(&generateApprovalTokenOptions.TTL, "ttl", 15*time.Minute, "How long the token is valid for.")
+	GenerateApprovalToken.MarkFlagRequired("approver")
+	GenerateApprovalToken.MarkFlagRequired("secret-file")
+	Root.AddCommand(GenerateApprovalToken)
+}
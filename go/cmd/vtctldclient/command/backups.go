@@ -30,14 +30,21 @@ import (
 	"vitess.io/vitess/go/vt/mysqlctl"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
 var (
 	// Backup makes a Backup gRPC call to a vtctld.
 	Backup = &cobra.Command{
-		Use:                   "Backup [--concurrency <concurrency>] [--allow-primary] [--incremental-from-pos=<pos>|auto] [--upgrade-safe] <tablet_alias>",
-		Short:                 "Uses the BackupStorage service on the given tablet to create and store a new backup.",
+		Use:   "Backup [--concurrency <concurrency>] [--allow-primary] [--incremental-from-pos=<pos>|auto] [--upgrade-safe] <tablet_alias>",
+		Short: "Uses the BackupStorage service on the given tablet to create and store a new backup.",
+		Long: `Uses the BackupStorage service on the given tablet to create and store a new backup.
+
+With --incremental-from-pos, the builtin backup engine takes an incremental backup, which consists of the binary logs
+of the tablet from the given position onwards. With --incremental-from-pos=auto, the backup starts at the position of
+the last successful full or incremental backup. Incremental backups are used by RestoreFromBackup's point in time
+recovery.`,
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandBackup,
@@ -61,6 +68,22 @@ If no replica-type tablet can be found, the backup can be taken on the primary i
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandGetBackups,
 	}
+	// PurgeBackups makes a PurgeBackups gRPC call to a vtctld.
+	PurgeBackups = &cobra.Command{
+		Use:   "PurgeBackups [--keep-full-backups <count>] [--min-retention-time <duration>] [--dry-run] <keyspace/shard>",
+		Short: "Removes the backups of the given shard that are no longer needed according to a retention policy.",
+		Long: `Removes the backups of the given shard that are no longer needed according to a retention policy.
+
+The policy keeps the --keep-full-backups most recent complete full backups, along with the incremental backups taken
+since, and the backups younger than --min-retention-time. The latest complete full backup is always kept, and so is the
+latest one that passed its restore drill if that one failed it. When neither flag is given, the retention policy set on
+the vtctld with --backup-retention-keep-full-backups and --backup-retention-min-time is used.
+
+Use --dry-run to list the backups which would be purged without removing them.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandPurgeBackups,
+	}
 	// RemoveBackup makes a RemoveBackup gRPC call to a vtctld.
 	RemoveBackup = &cobra.Command{
 		Use:                   "RemoveBackup <keyspace/shard> <backup name>",
@@ -71,12 +94,36 @@ If no replica-type tablet can be found, the backup can be taken on the primary i
 	}
 	// RestoreFromBackup makes a RestoreFromBackup gRPC call to a vtctld.
 	RestoreFromBackup = &cobra.Command{
-		Use:                   "RestoreFromBackup [--backup-timestamp|-t <YYYY-mm-DD.HHMMSS>] [--restore-to-pos <pos>] [--dry-run] <tablet_alias>",
-		Short:                 "Stops mysqld on the specified tablet and restores the data from either the latest backup or closest before `backup-timestamp`.",
+		Use:   "RestoreFromBackup [--backup-timestamp|-t <YYYY-mm-DD.HHMMSS>] [--restore-to-pos <pos> | --restore-to-timestamp <RFC3339>] [--dry-run] [--concurrency <concurrency>] <tablet_alias>",
+		Short: "Stops mysqld on the specified tablet and restores the data from either the latest backup or closest before `backup-timestamp`.",
+		Long: `Stops mysqld on the specified tablet and restores the data from either the latest backup or closest before ` + "`backup-timestamp`" + `.
+
+With --restore-to-pos or --restore-to-timestamp, the restore is a point in time recovery: it restores the full backup
+closest before the requested point, and then replays the binary logs of the incremental backups taken since, up to the
+given GTID position, or up to (and excluding) the given timestamp. Use --dry-run to validate that such a path of
+backups exists without restoring any data.
+
+The files of each backup are restored concurrently, by --concurrency workers or by the --restore_concurrency of the
+tablet if omitted. The progress of the restore (bytes and files restored, and an ETA for backups which record the size
+of their files) is printed periodically along with the logs of the tablet.`,
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandRestoreFromBackup,
 	}
+	// ValidateBackup makes a ValidateBackup gRPC call to a vtctld.
+	ValidateBackup = &cobra.Command{
+		Use:   "ValidateBackup [--backup-name <name>] <keyspace/shard>",
+		Short: "Validates that the files of a backup can be read from the BackupStorage used by vtctld and match their checksums.",
+		Long: `Validates that the files of a backup can be read from the BackupStorage used by vtctld and match their checksums.
+
+The latest complete backup of the shard is validated unless --backup-name is given. Only the builtin backup engine
+records the checksums of its files, so only the MANIFEST of the backups of other engines is checked. The result of the
+last restore drill of the backup, which vtbackup runs with --verify-backup, is included in the output, and a failed
+drill is reported as a problem.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandValidateBackup,
+	}
 )
 
 var backupOptions = struct {
@@ -87,7 +134,7 @@ var backupOptions = struct {
 }{}
 
 func commandBackup(cmd *cobra.Command, args []string) error {
-	tabletAlias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
+	tabletAlias, err := parseTabletAlias(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -109,7 +156,7 @@ func commandBackup(cmd *cobra.Command, args []string) error {
 		resp, err := stream.Recv()
 		switch err {
 		case nil:
-			fmt.Printf("%s/%s (%s): %v\n", resp.Keyspace, resp.Shard, topoproto.TabletAliasString(resp.TabletAlias), resp.Event)
+			fmt.Fprintf(cmd.OutOrStdout(), "%s/%s (%s): %v\n", resp.Keyspace, resp.Shard, topoproto.TabletAliasString(resp.TabletAlias), resp.Event)
 		case io.EOF:
 			return nil
 		default:
@@ -126,7 +173,7 @@ var backupShardOptions = struct {
 }{}
 
 func commandBackupShard(cmd *cobra.Command, args []string) error {
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -149,7 +196,7 @@ func commandBackupShard(cmd *cobra.Command, args []string) error {
 		resp, err := stream.Recv()
 		switch err {
 		case nil:
-			fmt.Printf("%s/%s (%s): %v\n", resp.Keyspace, resp.Shard, topoproto.TabletAliasString(resp.TabletAlias), resp.Event)
+			fmt.Fprintf(cmd.OutOrStdout(), "%s/%s (%s): %v\n", resp.Keyspace, resp.Shard, topoproto.TabletAliasString(resp.TabletAlias), resp.Event)
 		case io.EOF:
 			return nil
 		default:
@@ -164,7 +211,7 @@ var getBackupsOptions = struct {
 }{}
 
 func commandGetBackups(cmd *cobra.Command, args []string) error {
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -186,7 +233,7 @@ func commandGetBackups(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		fmt.Printf("%s\n", data)
+		fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 		return nil
 	}
 
@@ -195,13 +242,47 @@ func commandGetBackups(cmd *cobra.Command, args []string) error {
 		names[i] = b.Name
 	}
 
-	fmt.Printf("%s\n", strings.Join(names, "\n"))
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", strings.Join(names, "\n"))
+
+	return nil
+}
+
+var purgeBackupsOptions = struct {
+	KeepFullBackups  uint32
+	MinRetentionTime time.Duration
+	DryRun           bool
+}{}
+
+func commandPurgeBackups(cmd *cobra.Command, args []string) error {
+	keyspace, shard, err := parseKeyspaceShard(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.PurgeBackups(commandCtx, &vtctldatapb.PurgeBackupsRequest{
+		Keyspace:         keyspace,
+		Shard:            shard,
+		KeepFullBackups:  purgeBackupsOptions.KeepFullBackups,
+		MinRetentionTime: protoutil.DurationToProto(purgeBackupsOptions.MinRetentionTime),
+		DryRun:           purgeBackupsOptions.DryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
 
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 	return nil
 }
 
 func commandRemoveBackup(cmd *cobra.Command, args []string) error {
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -223,10 +304,11 @@ var restoreFromBackupOptions = struct {
 	RestoreToPos       string
 	RestoreToTimestamp string
 	DryRun             bool
+	Concurrency        uint64
 }{}
 
 func commandRestoreFromBackup(cmd *cobra.Command, args []string) error {
-	alias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
+	alias, err := parseTabletAlias(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -248,6 +330,7 @@ func commandRestoreFromBackup(cmd *cobra.Command, args []string) error {
 		RestoreToPos:       restoreFromBackupOptions.RestoreToPos,
 		RestoreToTimestamp: logutil.TimeToProto(restoreToTimestamp),
 		DryRun:             restoreFromBackupOptions.DryRun,
+		Concurrency:        restoreFromBackupOptions.Concurrency,
 	}
 
 	if restoreFromBackupOptions.BackupTimestamp != "" {
@@ -270,7 +353,11 @@ func commandRestoreFromBackup(cmd *cobra.Command, args []string) error {
 		resp, err := stream.Recv()
 		switch err {
 		case nil:
-			fmt.Printf("%s/%s (%s): %v\n", resp.Keyspace, resp.Shard, topoproto.TabletAliasString(resp.TabletAlias), resp.Event)
+			if resp.Progress != nil {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s/%s (%s): %s\n", resp.Keyspace, resp.Shard, topoproto.TabletAliasString(resp.TabletAlias), formatRestoreProgress(resp.Progress))
+				continue
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s/%s (%s): %v\n", resp.Keyspace, resp.Shard, topoproto.TabletAliasString(resp.TabletAlias), resp.Event)
 		case io.EOF:
 			return nil
 		default:
@@ -279,6 +366,57 @@ func commandRestoreFromBackup(cmd *cobra.Command, args []string) error {
 	}
 }
 
+var validateBackupOptions = struct {
+	BackupName string
+}{}
+
+func commandValidateBackup(cmd *cobra.Command, args []string) error {
+	keyspace, shard, err := parseKeyspaceShard(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.ValidateBackup(commandCtx, &vtctldatapb.ValidateBackupRequest{
+		Keyspace:   keyspace,
+		Shard:      shard,
+		BackupName: validateBackupOptions.BackupName,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+	if len(resp.Results) > 0 {
+		return fmt.Errorf("found %d problems with backup %s", len(resp.Results), resp.BackupName)
+	}
+
+	return nil
+}
+
+// formatRestoreProgress formats the progress of a restore, whose total size and
+// ETA are unknown for backups which do not record the size of their files.
+func formatRestoreProgress(progress *tabletmanagerdatapb.RestoreProgress) string {
+	var s strings.Builder
+	fmt.Fprintf(&s, "restore progress of %s: ", progress.BackupName)
+	if progress.TotalBytes > 0 {
+		fmt.Fprintf(&s, "%.02f%% (%d/%d bytes)", 100*float64(progress.BytesRestored)/float64(progress.TotalBytes), progress.BytesRestored, progress.TotalBytes)
+	} else {
+		fmt.Fprintf(&s, "%d bytes", progress.BytesRestored)
+	}
+	fmt.Fprintf(&s, ", %d/%d files", progress.FilesRestored, progress.TotalFiles)
+	if progress.EtaSeconds >= 0 {
+		fmt.Fprintf(&s, ", ETA %v", time.Duration(progress.EtaSeconds)*time.Second)
+	}
+	return s.String()
+}
+
 func init() {
 	Backup.Flags().BoolVar(&backupOptions.AllowPrimary, "allow-primary", false, "Allow the primary of a shard to be used for the backup. WARNING: If using the builtin backup engine, this will shutdown mysqld on the primary and stop writes for the duration of the backup.")
 	Backup.Flags().Uint64Var(&backupOptions.Concurrency, "concurrency", 4, "Specifies the number of compression/checksum jobs to run simultaneously.")
@@ -297,11 +435,20 @@ func init() {
 	GetBackups.Flags().BoolVarP(&getBackupsOptions.OutputJSON, "json", "j", false, "Output backup info in JSON format rather than a list of backups.")
 	Root.AddCommand(GetBackups)
 
+	PurgeBackups.Flags().Uint32Var(&purgeBackupsOptions.KeepFullBackups, "keep-full-backups", 0, "Number of most recent complete full backups to keep, along with the incremental backups taken since.")
+	PurgeBackups.Flags().DurationVar(&purgeBackupsOptions.MinRetentionTime, "min-retention-time", 0, "Keep the backups younger than this, whatever their number.")
+	PurgeBackups.Flags().BoolVar(&purgeBackupsOptions.DryRun, "dry-run", false, "Only list the backups which would be purged, without removing them.")
+	Root.AddCommand(PurgeBackups)
+
 	Root.AddCommand(RemoveBackup)
 
 	RestoreFromBackup.Flags().StringVarP(&restoreFromBackupOptions.BackupTimestamp, "backup-timestamp", "t", "", "Use the backup taken at, or closest before, this timestamp. Omit to use the latest backup. Timestamp format is \"YYYY-mm-DD.HHMMSS\".")
 	RestoreFromBackup.Flags().StringVar(&restoreFromBackupOptions.RestoreToPos, "restore-to-pos", "", "Run a point in time recovery that ends with the given position. This will attempt to use one full backup followed by zero or more incremental backups")
 	RestoreFromBackup.Flags().StringVar(&restoreFromBackupOptions.RestoreToTimestamp, "restore-to-timestamp", "", "Run a point in time recovery that restores up to, and excluding, given timestamp in RFC3339 format (`2006-01-02T15:04:05Z07:00`). This will attempt to use one full backup followed by zero or more incremental backups")
 	RestoreFromBackup.Flags().BoolVar(&restoreFromBackupOptions.DryRun, "dry-run", false, "Only validate restore steps, do not actually restore data")
+	RestoreFromBackup.Flags().Uint64Var(&restoreFromBackupOptions.Concurrency, "concurrency", 0, "Number of files to restore in parallel. Defaults to the --restore_concurrency of the tablet.")
 	Root.AddCommand(RestoreFromBackup)
+
+	ValidateBackup.Flags().StringVar(&validateBackupOptions.BackupName, "backup-name", "", "Name of the backup to validate. Omit to validate the latest complete backup.")
+	Root.AddCommand(ValidateBackup)
 }
@@ -31,7 +31,7 @@ import (
 var (
 	// AddCellInfo makes an AddCellInfo gRPC call to a vtctld.
 	AddCellInfo = &cobra.Command{
-		Use:   "AddCellInfo --root <root> [--server-address <addr>] <cell>",
+		Use:   "AddCellInfo --root <root> [--server-address <addr>] [--if-not-exists] <cell>",
 		Short: "Registers a local topology service in a new cell by creating the CellInfo.",
 		Long: `Registers a local topology service in a new cell by creating the CellInfo
 with the provided parameters.
@@ -119,21 +119,30 @@ If a value is empty, it is ignored.`,
 	}
 )
 
-var addCellInfoOptions topodatapb.CellInfo
+var (
+	addCellInfoOptions     topodatapb.CellInfo
+	addCellInfoIfNotExists bool
+)
 
 func commandAddCellInfo(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
 	cell := cmd.Flags().Arg(0)
-	_, err := client.AddCellInfo(commandCtx, &vtctldatapb.AddCellInfoRequest{
-		Name:     cell,
-		CellInfo: &addCellInfoOptions,
+	resp, err := client.AddCellInfo(commandCtx, &vtctldatapb.AddCellInfoRequest{
+		Name:        cell,
+		CellInfo:    &addCellInfoOptions,
+		IfNotExists: addCellInfoIfNotExists,
 	})
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Created cell: %s\n", cell)
+	if resp.AlreadyExists {
+		fmt.Fprintf(cmd.OutOrStdout(), "Cell %s already exists, unchanged\n", cell)
+		return nil
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Created cell: %s\n", cell)
 	return nil
 }
 
@@ -151,7 +160,7 @@ func commandAddCellsAlias(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("Created cells alias: %s (cells = %v)\n", alias, addCellsAliasOptions.Cells)
+	fmt.Fprintf(cmd.OutOrStdout(), "Created cells alias: %s (cells = %v)\n", alias, addCellsAliasOptions.Cells)
 	return nil
 }
 
@@ -171,7 +180,7 @@ func commandDeleteCellInfo(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("Deleted cell %s\n", cell)
+	fmt.Fprintf(cmd.OutOrStdout(), "Deleted cell %s\n", cell)
 	return nil
 }
 
@@ -186,7 +195,7 @@ func commandDeleteCellsAlias(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("Delete cells alias %s\n", alias)
+	fmt.Fprintf(cmd.OutOrStdout(), "Delete cells alias %s\n", alias)
 	return nil
 }
 
@@ -198,7 +207,7 @@ func commandGetCellInfoNames(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", strings.Join(resp.Names, "\n"))
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", strings.Join(resp.Names, "\n"))
 
 	return nil
 }
@@ -218,7 +227,7 @@ func commandGetCellInfo(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 
 	return nil
 }
@@ -236,7 +245,7 @@ func commandGetCellsAliases(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 
 	return nil
 }
@@ -260,7 +269,7 @@ func commandUpdateCellInfo(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("Updated cell %s. New CellInfo:\n%s\n", resp.Name, data)
+	fmt.Fprintf(cmd.OutOrStdout(), "Updated cell %s. New CellInfo:\n%s\n", resp.Name, data)
 	return nil
 }
 
@@ -283,7 +292,7 @@ func commandUpdateCellsAlias(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("Updated cells alias %s. New CellsAlias:\n%s\n", resp.Name, data)
+	fmt.Fprintf(cmd.OutOrStdout(), "Updated cells alias %s. New CellsAlias:\n%s\n", resp.Name, data)
 	return nil
 }
 
@@ -291,6 +300,7 @@ func init() {
 	AddCellInfo.Flags().StringVarP(&addCellInfoOptions.ServerAddress, "server-address", "a", "", "The address the topology server will connect to for this cell.")
 	AddCellInfo.Flags().StringVarP(&addCellInfoOptions.Root, "root", "r", "", "The root path the topology server will use for this cell.")
 	AddCellInfo.MarkFlagRequired("root")
+	AddCellInfo.Flags().BoolVar(&addCellInfoIfNotExists, "if-not-exists", false, "Succeeds without making any changes if the cell already exists.")
 	Root.AddCommand(AddCellInfo)
 
 	AddCellsAlias.Flags().StringSliceVarP(&addCellsAliasOptions.Cells, "cells", "c", nil, "The list of cell names that are members of this alias.")
@@ -0,0 +1,144 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/vt/vtctl/clusterspec"
+)
+
+var (
+	// Plan diffs a cluster spec against the live topology.
+	Plan = &cobra.Command{
+		Use:   "Plan [--json] <spec file>",
+		Short: "Shows the changes needed to bring the topology in line with a cluster spec, without making them.",
+		Long: `Shows the changes needed to bring the topology in line with a cluster spec, without making them.
+
+The spec is a YAML or JSON file describing keyspaces, their shards and vschemas,
+and the routing rules of the cluster:
+
+  keyspaces:
+    - name: commerce
+      durability_policy: semi_sync
+      shards: ["0"]
+      vschema:
+        tables:
+          product: {}
+    - name: customer
+      shards: ["-80", "80-"]
+  routing_rules:
+    rules:
+      - from_table: product
+        to_tables: ["commerce.product"]
+
+Keyspaces and shards missing from the topology are created, and durability
+policies, vschemas and routing rules that differ from the spec are updated.
+Nothing is ever deleted, and the vschemas and routing rules the spec does not
+mention are left alone.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandPlan,
+	}
+	// Apply makes the changes needed to bring the topology in line with a
+	// cluster spec.
+	Apply = &cobra.Command{
+		Use:   "Apply <spec file>",
+		Short: "Makes the changes needed to bring the topology in line with a cluster spec.",
+		Long: `Makes the changes needed to bring the topology in line with a cluster spec.
+
+Apply computes the same plan as Plan, against the topology as it is when Apply
+runs, and then makes its changes in order. It stops at the first change that
+fails; running Apply again picks up from there. See Plan for the format of the
+spec.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandApply,
+	}
+)
+
+var planOptions = struct {
+	JSON bool
+}{}
+
+func commandPlan(cmd *cobra.Command, args []string) error {
+	spec, err := clusterspec.Load(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	plan, err := clusterspec.ComputePlan(commandCtx, client, spec)
+	if err != nil {
+		return err
+	}
+
+	if planOptions.JSON {
+		data, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+		return nil
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), plan)
+	return nil
+}
+
+func commandApply(cmd *cobra.Command, args []string) error {
+	spec, err := clusterspec.Load(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	plan, err := clusterspec.ComputePlan(commandCtx, client, spec)
+	if err != nil {
+		return err
+	}
+
+	if plan.Empty() {
+		fmt.Fprintln(cmd.OutOrStdout(), plan)
+		return nil
+	}
+
+	for i, change := range plan.Changes {
+		if err := change.Apply(commandCtx, client); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "[%d/%d] %s\n", i+1, len(plan.Changes), change)
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), "Apply complete.")
+	return nil
+}
+
+func init() {
+	Plan.Flags().BoolVar(&planOptions.JSON, "json", false, "Output the plan in JSON instead of human-readable text.")
+	Root.AddCommand(Plan)
+
+	Root.AddCommand(Apply)
+}
@@ -19,6 +19,8 @@ package command
 import (
 	"errors"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 	"time"
 
@@ -31,6 +33,7 @@ import (
 	"vitess.io/vitess/go/mysql"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
@@ -40,7 +43,7 @@ import (
 var (
 	// CreateKeyspace makes a CreateKeyspace gRPC call to a vtctld.
 	CreateKeyspace = &cobra.Command{
-		Use:   "CreateKeyspace <keyspace> [--force|-f] [--type KEYSPACE_TYPE] [--base-keyspace KEYSPACE --snapshot-timestamp TIME] [--served-from DB_TYPE:KEYSPACE ...] [--durability-policy <policy_name>] [--sidecar-db-name <db_name>]",
+		Use:   "CreateKeyspace <keyspace> [--force|-f] [--if-not-exists] [--type KEYSPACE_TYPE] [--base-keyspace KEYSPACE --snapshot-timestamp TIME] [--served-from DB_TYPE:KEYSPACE ...] [--durability-policy <policy_name>] [--sidecar-db-name <db_name>]",
 		Short: "Creates the specified keyspace in the topology.",
 		Long: `Creates the specified keyspace in the topology.
 	
@@ -52,7 +55,7 @@ as well as a snapshot time.`,
 	}
 	// DeleteKeyspace makes a DeleteKeyspace gRPC call to a vtctld.
 	DeleteKeyspace = &cobra.Command{
-		Use:   "DeleteKeyspace [--recursive|-r] [--force|-f] <keyspace>",
+		Use:   "DeleteKeyspace [--recursive|-r] [--force|-f] [--dry-run] <keyspace>",
 		Short: "Deletes the specified keyspace from the topology.",
 		Long: `Deletes the specified keyspace from the topology.
 
@@ -91,7 +94,7 @@ Otherwise, the keyspace must be empty (have no shards), or returns an error.`,
 	}
 	// RemoveKeyspaceCell makes a RemoveKeyspaceCell gRPC call to a vtctld.
 	RemoveKeyspaceCell = &cobra.Command{
-		Use:                   "RemoveKeyspaceCell [--force|-f] [--recursive|-r] <keyspace> <cell>",
+		Use:                   "RemoveKeyspaceCell [--force|-f] [--recursive|-r] [--dry-run] <keyspace> <cell>",
 		Short:                 "Removes the specified cell from the Cells list for all shards in the specified keyspace (by calling RemoveShardCell on every shard). It also removes the SrvKeyspace for that keyspace in that cell.",
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(2),
@@ -113,13 +116,28 @@ SetKeyspaceDurabilityPolicy --durability-policy='semi_sync' customer`,
 	}
 	// ValidateSchemaKeyspace makes a ValidateSchemaKeyspace gRPC call to a vtctld.
 	ValidateSchemaKeyspace = &cobra.Command{
-		Use:                   "ValidateSchemaKeyspace [--exclude-tables=<exclude_tables>] [--include-views] [--skip-no-primary] [--include-vschema] <keyspace>",
-		Short:                 "Validates that the schema on the primary tablet for shard 0 matches the schema on all other tablets in the keyspace.",
+		Use:   "ValidateSchemaKeyspace [--exclude-tables=<exclude_tables>] [--include-views] [--skip-no-primary] [--include-vschema] [--concurrency=<concurrency>] [--progress] <keyspace>",
+		Short: "Validates that the schema on the primary tablet for shard 0 matches the schema on all other tablets in the keyspace.",
+		Long: `Validates that the schema on the primary tablet for shard 0 matches the schema on all other tablets in the keyspace.
+
+Tablets are validated concurrently, up to --concurrency at a time. With --progress, the outcome of each tablet is printed to stderr as soon as it is validated, and the results are printed once all tablets have been validated.`,
 		DisableFlagsInUseLine: true,
 		Aliases:               []string{"validateschemakeyspace"},
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandValidateSchemaKeyspace,
 	}
+	// ValidatePermissionsKeyspace makes a ValidatePermissionsKeyspace gRPC call to a vtctld.
+	ValidatePermissionsKeyspace = &cobra.Command{
+		Use:   "ValidatePermissionsKeyspace [--concurrency=<concurrency>] [--progress] <keyspace>",
+		Short: "Validates that the permissions on the primary tablet of the first shard match those of all of the other tablets in the keyspace.",
+		Long: `Validates that the permissions on the primary tablet of the first shard match those of all of the other tablets in the keyspace.
+
+Tablets are validated concurrently, up to --concurrency at a time. With --progress, the outcome of each tablet is printed to stderr as soon as it is validated, and the results are printed once all tablets have been validated.`,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"validatepermissionskeyspace"},
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandValidatePermissionsKeyspace,
+	}
 	// ValidateVersionKeyspace makes a ValidateVersionKeyspace gRPC call to a vtctld.
 	ValidateVersionKeyspace = &cobra.Command{
 		Use:                   "ValidateVersionKeyspace <keyspace>",
@@ -133,6 +151,7 @@ SetKeyspaceDurabilityPolicy --durability-policy='semi_sync' customer`,
 
 var createKeyspaceOptions = struct {
 	Force             bool
+	IfNotExists       bool
 	AllowEmptyVSchema bool
 
 	ServedFromsMap cli.StringMapValue
@@ -148,6 +167,7 @@ var createKeyspaceOptions = struct {
 
 func commandCreateKeyspace(cmd *cobra.Command, args []string) error {
 	name := cmd.Flags().Arg(0)
+	reportKeyspace(name)
 
 	switch topodatapb.KeyspaceType(createKeyspaceOptions.KeyspaceType) {
 	case topodatapb.KeyspaceType_NORMAL, topodatapb.KeyspaceType_SNAPSHOT:
@@ -195,6 +215,7 @@ func commandCreateKeyspace(cmd *cobra.Command, args []string) error {
 	req := &vtctldatapb.CreateKeyspaceRequest{
 		Name:              name,
 		Force:             createKeyspaceOptions.Force,
+		IfNotExists:       createKeyspaceOptions.IfNotExists,
 		AllowEmptyVSchema: createKeyspaceOptions.AllowEmptyVSchema,
 		Type:              topodatapb.KeyspaceType(createKeyspaceOptions.KeyspaceType),
 		BaseKeyspace:      createKeyspaceOptions.BaseKeyspace,
@@ -225,7 +246,12 @@ func commandCreateKeyspace(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("Successfully created keyspace %s. Result:\n%s\n", name, data)
+	if resp.AlreadyExists {
+		fmt.Fprintf(cmd.OutOrStdout(), "Keyspace %s already exists, unchanged. Result:\n%s\n", name, data)
+		return nil
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Successfully created keyspace %s. Result:\n%s\n", name, data)
 
 	return nil
 }
@@ -233,23 +259,31 @@ func commandCreateKeyspace(cmd *cobra.Command, args []string) error {
 var deleteKeyspaceOptions = struct {
 	Recursive bool
 	Force     bool
+	DryRun    bool
 }{}
 
 func commandDeleteKeyspace(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
 	ks := cmd.Flags().Arg(0)
-	_, err := client.DeleteKeyspace(commandCtx, &vtctldatapb.DeleteKeyspaceRequest{
+	reportKeyspace(ks)
+	resp, err := client.DeleteKeyspace(commandCtx, &vtctldatapb.DeleteKeyspaceRequest{
 		Keyspace:  ks,
 		Recursive: deleteKeyspaceOptions.Recursive,
 		Force:     deleteKeyspaceOptions.Force,
+		DryRun:    deleteKeyspaceOptions.DryRun,
 	})
 
 	if err != nil {
 		return fmt.Errorf("DeleteKeyspace(%v) error: %w; please check the topo", ks, err)
 	}
 
-	fmt.Printf("Successfully deleted keyspace %v.\n", ks)
+	if deleteKeyspaceOptions.DryRun {
+		fmt.Fprintf(cmd.OutOrStdout(), "Dry run results for deleting keyspace %v:\n%s\n", ks, strings.Join(resp.DryRunResults, "\n"))
+		return nil
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Successfully deleted keyspace %v.\n", ks)
 
 	return nil
 }
@@ -258,6 +292,7 @@ func commandFindAllShardsInKeyspace(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
 	ks := cmd.Flags().Arg(0)
+	reportKeyspace(ks)
 	resp, err := client.FindAllShardsInKeyspace(commandCtx, &vtctldatapb.FindAllShardsInKeyspaceRequest{
 		Keyspace: ks,
 	})
@@ -271,7 +306,7 @@ func commandFindAllShardsInKeyspace(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 	return nil
 }
 
@@ -279,6 +314,7 @@ func commandGetKeyspace(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
 	ks := cmd.Flags().Arg(0)
+	reportKeyspace(ks)
 	resp, err := client.GetKeyspace(commandCtx, &vtctldatapb.GetKeyspaceRequest{
 		Keyspace: ks,
 	})
@@ -291,7 +327,7 @@ func commandGetKeyspace(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 
 	return nil
 }
@@ -309,7 +345,7 @@ func commandGetKeyspaces(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 
 	return nil
 }
@@ -317,26 +353,34 @@ func commandGetKeyspaces(cmd *cobra.Command, args []string) error {
 var removeKeyspaceCellOptions = struct {
 	Force     bool
 	Recursive bool
+	DryRun    bool
 }{}
 
 func commandRemoveKeyspaceCell(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
 	keyspace := cmd.Flags().Arg(0)
+	reportKeyspace(keyspace)
 	cell := cmd.Flags().Arg(1)
 
-	_, err := client.RemoveKeyspaceCell(commandCtx, &vtctldatapb.RemoveKeyspaceCellRequest{
+	resp, err := client.RemoveKeyspaceCell(commandCtx, &vtctldatapb.RemoveKeyspaceCellRequest{
 		Keyspace:  keyspace,
 		Cell:      cell,
 		Force:     removeKeyspaceCellOptions.Force,
 		Recursive: removeKeyspaceCellOptions.Recursive,
+		DryRun:    removeKeyspaceCellOptions.DryRun,
 	})
 
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Successfully removed keyspace %s from cell %s\n", keyspace, cell)
+	if removeKeyspaceCellOptions.DryRun {
+		fmt.Fprintf(cmd.OutOrStdout(), "Dry run results for removing keyspace %s from cell %s:\n%s\n", keyspace, cell, strings.Join(resp.DryRunResults, "\n"))
+		return nil
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Successfully removed keyspace %s from cell %s\n", keyspace, cell)
 
 	return nil
 }
@@ -347,6 +391,7 @@ var setKeyspaceDurabilityPolicyOptions = struct {
 
 func commandSetKeyspaceDurabilityPolicy(cmd *cobra.Command, args []string) error {
 	keyspace := cmd.Flags().Arg(0)
+	reportKeyspace(keyspace)
 	cli.FinishedParsing(cmd)
 
 	resp, err := client.SetKeyspaceDurabilityPolicy(commandCtx, &vtctldatapb.SetKeyspaceDurabilityPolicyRequest{
@@ -362,29 +407,98 @@ func commandSetKeyspaceDurabilityPolicy(cmd *cobra.Command, args []string) error
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 	return nil
 }
 
+// printValidateProgress prints the outcome of validating a single tablet to
+// stderr.
+func printValidateProgress(progress *vtctldatapb.ValidateProgress) {
+	alias := topoproto.TabletAliasString(progress.TabletAlias)
+	if len(progress.Results) == 0 {
+		fmt.Fprintf(os.Stderr, "[%d/%d] %s (%s): ok\n", progress.Completed, progress.Total, progress.Shard, alias)
+		return
+	}
+
+	for _, result := range progress.Results {
+		fmt.Fprintf(os.Stderr, "[%d/%d] %s (%s): %s\n", progress.Completed, progress.Total, progress.Shard, alias, result)
+	}
+}
+
+var validatePermissionsKeyspaceOptions = struct {
+	Concurrency uint32
+	Progress    bool
+}{}
+
+func commandValidatePermissionsKeyspace(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	ks := cmd.Flags().Arg(0)
+	reportKeyspace(ks)
+	stream, err := client.ValidatePermissionsKeyspace(commandCtx, &vtctldatapb.ValidatePermissionsKeyspaceRequest{
+		Keyspace:    ks,
+		Concurrency: validatePermissionsKeyspaceOptions.Concurrency,
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		switch err {
+		case nil:
+			if resp.Progress != nil {
+				if validatePermissionsKeyspaceOptions.Progress {
+					printValidateProgress(resp.Progress)
+				}
+				continue
+			}
+
+			data, err := cli.MarshalJSON(&vtctldatapb.ValidateKeyspaceResponse{
+				Results:        resp.Results,
+				ResultsByShard: resp.ResultsByShard,
+			})
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+		case io.EOF:
+			return nil
+		default:
+			return err
+		}
+	}
+}
+
 var validateSchemaKeyspaceOptions = struct {
 	ExcludeTables  []string
 	IncludeViews   bool
 	SkipNoPrimary  bool
 	IncludeVSchema bool
+	Concurrency    uint32
+	Progress       bool
 }{}
 
 func commandValidateSchemaKeyspace(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
 	ks := cmd.Flags().Arg(0)
-	resp, err := client.ValidateSchemaKeyspace(commandCtx, &vtctldatapb.ValidateSchemaKeyspaceRequest{
+	reportKeyspace(ks)
+	req := &vtctldatapb.ValidateSchemaKeyspaceRequest{
 		Keyspace:       ks,
 		ExcludeTables:  validateSchemaKeyspaceOptions.ExcludeTables,
 		IncludeVschema: validateSchemaKeyspaceOptions.IncludeVSchema,
 		SkipNoPrimary:  validateSchemaKeyspaceOptions.SkipNoPrimary,
 		IncludeViews:   validateSchemaKeyspaceOptions.IncludeViews,
-	})
+		Concurrency:    validateSchemaKeyspaceOptions.Concurrency,
+	}
+
+	if validateSchemaKeyspaceOptions.Progress {
+		return validateSchemaKeyspaceStream(cmd, req)
+	}
 
+	resp, err := client.ValidateSchemaKeyspace(commandCtx, req)
 	if err != nil {
 		return err
 	}
@@ -394,14 +508,47 @@ func commandValidateSchemaKeyspace(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 	return nil
 }
 
+func validateSchemaKeyspaceStream(cmd *cobra.Command, req *vtctldatapb.ValidateSchemaKeyspaceRequest) error {
+	stream, err := client.ValidateSchemaKeyspaceStream(commandCtx, req)
+	if err != nil {
+		return err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		switch err {
+		case nil:
+			if resp.Progress != nil {
+				printValidateProgress(resp.Progress)
+				continue
+			}
+
+			data, err := cli.MarshalJSON(&vtctldatapb.ValidateSchemaKeyspaceResponse{
+				Results:        resp.Results,
+				ResultsByShard: resp.ResultsByShard,
+			})
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+		case io.EOF:
+			return nil
+		default:
+			return err
+		}
+	}
+}
+
 func commandValidateVersionKeyspace(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
 	ks := cmd.Flags().Arg(0)
+	reportKeyspace(ks)
 	resp, err := client.ValidateVersionKeyspace(commandCtx, &vtctldatapb.ValidateVersionKeyspaceRequest{
 		Keyspace: ks,
 	})
@@ -415,12 +562,13 @@ func commandValidateVersionKeyspace(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 	return nil
 }
 
 func init() {
 	CreateKeyspace.Flags().BoolVarP(&createKeyspaceOptions.Force, "force", "f", false, "Proceeds even if the keyspace already exists. Does not overwrite the existing keyspace record.")
+	CreateKeyspace.Flags().BoolVar(&createKeyspaceOptions.IfNotExists, "if-not-exists", false, "Succeeds without making any changes if the keyspace already exists.")
 	CreateKeyspace.Flags().BoolVarP(&createKeyspaceOptions.AllowEmptyVSchema, "allow-empty-vschema", "e", false, "Allows a new keyspace to have no vschema.")
 	CreateKeyspace.Flags().Var(&createKeyspaceOptions.ServedFromsMap, "served-from", "Specifies a set of db_type:keyspace pairs used to serve traffic for the keyspace.")
 	CreateKeyspace.Flags().Var(&createKeyspaceOptions.KeyspaceType, "type", "The type of the keyspace.")
@@ -432,6 +580,7 @@ func init() {
 
 	DeleteKeyspace.Flags().BoolVarP(&deleteKeyspaceOptions.Recursive, "recursive", "r", false, "Recursively delete all shards in the keyspace, and all tablets in those shards.")
 	DeleteKeyspace.Flags().BoolVarP(&deleteKeyspaceOptions.Force, "force", "f", false, "Delete the keyspace even if it cannot be locked; this should only be used for cleanup operations.")
+	DeleteKeyspace.Flags().BoolVar(&deleteKeyspaceOptions.DryRun, "dry-run", false, "Print the topo mutations that would be performed, without actually deleting anything.")
 	Root.AddCommand(DeleteKeyspace)
 
 	Root.AddCommand(FindAllShardsInKeyspace)
@@ -440,6 +589,7 @@ func init() {
 
 	RemoveKeyspaceCell.Flags().BoolVarP(&removeKeyspaceCellOptions.Force, "force", "f", false, "Proceed even if the cell's topology server cannot be reached. The assumption is that you turned down the entire cell, and just need to update the global topo data.")
 	RemoveKeyspaceCell.Flags().BoolVarP(&removeKeyspaceCellOptions.Recursive, "recursive", "r", false, "Also delete all tablets in that cell beloning to the specified keyspace.")
+	RemoveKeyspaceCell.Flags().BoolVar(&removeKeyspaceCellOptions.DryRun, "dry-run", false, "Print the topo mutations that would be performed, without actually removing anything.")
 	Root.AddCommand(RemoveKeyspaceCell)
 
 	SetKeyspaceDurabilityPolicy.Flags().StringVar(&setKeyspaceDurabilityPolicyOptions.DurabilityPolicy, "durability-policy", "none", "Type of durability to enforce for this keyspace. Default is none. Other values include 'semi_sync' and others as dictated by registered plugins.")
@@ -449,7 +599,13 @@ func init() {
 	ValidateSchemaKeyspace.Flags().BoolVar(&validateSchemaKeyspaceOptions.IncludeVSchema, "include-vschema", false, "Includes VSchema validation in validation results.")
 	ValidateSchemaKeyspace.Flags().BoolVar(&validateSchemaKeyspaceOptions.SkipNoPrimary, "skip-no-primary", false, "Skips validation on whether or not a primary exists in shards.")
 	ValidateSchemaKeyspace.Flags().StringSliceVar(&validateSchemaKeyspaceOptions.ExcludeTables, "exclude-tables", []string{}, "Tables to exclude during schema comparison.")
+	ValidateSchemaKeyspace.Flags().Uint32Var(&validateSchemaKeyspaceOptions.Concurrency, "concurrency", 0, "Maximum number of tablets to validate concurrently. 0 means no limit.")
+	ValidateSchemaKeyspace.Flags().BoolVar(&validateSchemaKeyspaceOptions.Progress, "progress", false, "Prints the outcome of each tablet to stderr as soon as it is validated.")
 	Root.AddCommand(ValidateSchemaKeyspace)
 
+	ValidatePermissionsKeyspace.Flags().Uint32Var(&validatePermissionsKeyspaceOptions.Concurrency, "concurrency", 0, "Maximum number of tablets to validate concurrently. 0 means no limit.")
+	ValidatePermissionsKeyspace.Flags().BoolVar(&validatePermissionsKeyspaceOptions.Progress, "progress", false, "Prints the outcome of each tablet to stderr as soon as it is validated.")
+	Root.AddCommand(ValidatePermissionsKeyspace)
+
 	Root.AddCommand(ValidateVersionKeyspace)
 }
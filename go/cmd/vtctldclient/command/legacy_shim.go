@@ -43,7 +43,7 @@ var (
 		Args:                  cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cli.FinishedParsing(cmd)
-			return runLegacyCommand(args)
+			return runLegacyCommand(cmd, args)
 		},
 		Long: strings.TrimSpace(`
 LegacyVtctlCommand uses the legacy vtctl grpc client to make an ExecuteVtctlCommand
@@ -76,25 +76,33 @@ LegacyVtctlCommand -- AddCellInfo --server_address "localhost:5678" --root "/vit
 	}
 )
 
-func runLegacyCommand(args []string) error {
+func runLegacyCommand(cmd *cobra.Command, args []string) error {
 	// Duplicated (mostly) from go/cmd/vtctlclient/main.go.
 	logger := logutil.NewConsoleLogger()
+	recv := func(e *logutilpb.Event) {
+		logutil.LogEvent(logger, e)
+	}
+	if currentJSONOutput != nil {
+		// The events, including the entities the command reports, are part
+		// of its result.
+		recv = currentJSONOutput.collector.Recv
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), actionTimeout)
 	defer cancel()
 
-	err := vtctlclient.RunCommandAndWait(ctx, server, args, func(e *logutilpb.Event) {
-		logutil.LogEvent(logger, e)
-	})
+	err := vtctlclient.RunCommandAndWait(ctx, server, args, recv)
 	if err != nil {
 		if strings.Contains(err.Error(), "flag: help requested") {
 			// Help is caught by SetHelpFunc, so we don't want to indicate this as an error.
 			return nil
 		}
 
-		errStr := strings.Replace(err.Error(), "remote error: ", "", -1)
-		fmt.Printf("%s Error: %s\n", flag.Arg(0), errStr)
-		log.Error(err)
+		if currentJSONOutput == nil {
+			errStr := strings.Replace(err.Error(), "remote error: ", "", -1)
+			fmt.Fprintf(cmd.OutOrStdout(), "%s Error: %s\n", flag.Arg(0), errStr)
+			log.Error(err)
+		}
 	}
 
 	return err
@@ -0,0 +1,171 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/protoutil"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	// GetMaintenanceWindows makes a GetMaintenanceWindows gRPC call to a vtctld.
+	GetMaintenanceWindows = &cobra.Command{
+		Use:                   "GetMaintenanceWindows",
+		Short:                 "Outputs the keyspaces, shards and cells in maintenance as JSON.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		RunE:                  commandGetMaintenanceWindows,
+	}
+	// StartMaintenance makes a StartMaintenance gRPC call to a vtctld.
+	StartMaintenance = &cobra.Command{
+		Use:   "StartMaintenance [--ttl <duration>] [--reason <reason>] {<keyspace> | <keyspace/shard> | --cell <cell>}",
+		Short: "Puts a keyspace, a shard or a cell into maintenance, and outputs the maintenance window as JSON.",
+		Long: `Puts a keyspace, a shard or a cell into maintenance, and outputs the maintenance window as JSON.
+
+VTOrc doesn't run any recovery in a keyspace, a shard or a cell in maintenance,
+until the maintenance is stopped with StopMaintenance or it expires after --ttl.
+Starting the maintenance of a target already in maintenance replaces its window,
+to extend it for instance.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.MaximumNArgs(1),
+		RunE:                  commandStartMaintenance,
+	}
+	// StopMaintenance makes a StopMaintenance gRPC call to a vtctld.
+	StopMaintenance = &cobra.Command{
+		Use:                   "StopMaintenance {<keyspace> | <keyspace/shard> | --cell <cell>}",
+		Short:                 "Ends the maintenance of a keyspace, a shard or a cell.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.MaximumNArgs(1),
+		RunE:                  commandStopMaintenance,
+	}
+)
+
+var maintenanceOptions = struct {
+	Cell   string
+	Reason string
+	TTL    time.Duration
+}{}
+
+// parseMaintenanceTarget returns the keyspace and shard of the maintenance
+// target given as argument, if any, and checks the target is either that
+// argument or --cell.
+func parseMaintenanceTarget(cmd *cobra.Command) (keyspace string, shard string, err error) {
+	switch {
+	case cmd.Flags().NArg() == 0 && maintenanceOptions.Cell == "":
+		return "", "", fmt.Errorf("a keyspace, a shard or --cell is required")
+	case cmd.Flags().NArg() == 1 && maintenanceOptions.Cell != "":
+		return "", "", fmt.Errorf("a keyspace or a shard cannot be used with --cell")
+	case cmd.Flags().NArg() == 0:
+		return "", "", nil
+	}
+
+	target := cmd.Flags().Arg(0)
+	if !strings.Contains(target, "/") {
+		reportKeyspace(target)
+		return target, "", nil
+	}
+	return parseKeyspaceShard(target)
+}
+
+func commandGetMaintenanceWindows(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.GetMaintenanceWindows(commandCtx, &vtctldatapb.GetMaintenanceWindowsRequest{})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp.Windows)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+
+	return nil
+}
+
+func commandStartMaintenance(cmd *cobra.Command, args []string) error {
+	keyspace, shard, err := parseMaintenanceTarget(cmd)
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.StartMaintenance(commandCtx, &vtctldatapb.StartMaintenanceRequest{
+		Keyspace: keyspace,
+		Shard:    shard,
+		Cell:     maintenanceOptions.Cell,
+		Reason:   maintenanceOptions.Reason,
+		Ttl:      protoutil.DurationToProto(maintenanceOptions.TTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp.Window)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+
+	return nil
+}
+
+func commandStopMaintenance(cmd *cobra.Command, args []string) error {
+	keyspace, shard, err := parseMaintenanceTarget(cmd)
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	_, err = client.StopMaintenance(commandCtx, &vtctldatapb.StopMaintenanceRequest{
+		Keyspace: keyspace,
+		Shard:    shard,
+		Cell:     maintenanceOptions.Cell,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), "Maintenance stopped")
+
+	return nil
+}
+
+func init() {
+	Root.AddCommand(GetMaintenanceWindows)
+
+	StartMaintenance.Flags().StringVar(&maintenanceOptions.Cell, "cell", "", "The cell to put into maintenance.")
+	StartMaintenance.Flags().StringVar(&maintenanceOptions.Reason, "reason", "", "Why the maintenance is happening, displayed with the maintenance window.")
+	StartMaintenance.Flags().DurationVar(&maintenanceOptions.TTL, "ttl", time.Hour, "How long the maintenance lasts, unless it is stopped before.")
+	Root.AddCommand(StartMaintenance)
+
+	StopMaintenance.Flags().StringVar(&maintenanceOptions.Cell, "cell", "", "The cell in maintenance.")
+	Root.AddCommand(StopMaintenance)
+}
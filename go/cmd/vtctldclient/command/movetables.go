@@ -134,8 +134,12 @@ See the --help output for each command for more details.`,
 
 	// MoveTablesStatus makes a GetWorkflows gRPC call to a vtctld.
 	MoveTablesStatus = &cobra.Command{
-		Use:                   "status",
-		Short:                 "Show the current status for a MoveTables VReplication workflow.",
+		Use:   "status",
+		Short: "Show the current status for a MoveTables VReplication workflow.",
+		Long: `Show the current status for a MoveTables VReplication workflow.
+
+While the tables are copied, the status includes the number of rows copied by each stream and its copy rate,
+the number of rows left to copy, estimated from the table statistics, and the estimated time to copy them.`,
 		Example:               `vtctldclient --server localhost:15999 MoveTables --workflow commerce2customer --target-keyspace customer status`,
 		DisableFlagsInUseLine: true,
 		Aliases:               []string{"Status", "progress", "Progress"},
@@ -222,6 +226,7 @@ var (
 	moveTablesCreateOptions = struct {
 		Workflow                     string
 		SourceKeyspace               string
+		ExternalClusterName          string
 		Cells                        []string
 		TabletTypes                  []topodatapb.TabletType
 		TabletTypesInPreferenceOrder bool
@@ -243,6 +248,7 @@ var (
 		Timeout                   time.Duration
 		DryRun                    bool
 		InitializeTargetSequences bool
+		Shards                    []string
 		Direction                 workflow.TrafficSwitchDirection
 	}{}
 )
@@ -261,6 +267,8 @@ func commandMoveTablesCreate(cmd *cobra.Command, args []string) error {
 	}
 
 	cli.FinishedParsing(cmd)
+	reportKeyspace(moveTablesOptions.TargetKeyspace)
+	reportKeyspace(moveTablesCreateOptions.SourceKeyspace)
 
 	tsp := tabletmanagerdatapb.TabletSelectionPreference_ANY
 	if moveTablesCreateOptions.TabletTypesInPreferenceOrder {
@@ -271,6 +279,7 @@ func commandMoveTablesCreate(cmd *cobra.Command, args []string) error {
 		Workflow:                  moveTablesOptions.Workflow,
 		TargetKeyspace:            moveTablesOptions.TargetKeyspace,
 		SourceKeyspace:            moveTablesCreateOptions.SourceKeyspace,
+		ExternalClusterName:       moveTablesCreateOptions.ExternalClusterName,
 		SourceShards:              moveTablesCreateOptions.SourceShards,
 		SourceTimeZone:            moveTablesCreateOptions.SourceTimeZone,
 		Cells:                     moveTablesCreateOptions.Cells,
@@ -309,7 +318,7 @@ func commandMoveTablesCreate(cmd *cobra.Command, args []string) error {
 		}
 		output = tout.Bytes()
 	}
-	fmt.Printf("%s\n", output)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", output)
 
 	return nil
 }
@@ -323,6 +332,7 @@ func commandMoveTablesCancel(cmd *cobra.Command, args []string) error {
 	}
 
 	cli.FinishedParsing(cmd)
+	reportKeyspace(moveTablesOptions.TargetKeyspace)
 
 	req := &vtctldatapb.WorkflowDeleteRequest{
 		Keyspace:         moveTablesOptions.TargetKeyspace,
@@ -348,7 +358,7 @@ func commandMoveTablesCancel(cmd *cobra.Command, args []string) error {
 	} else {
 		output = []byte(resp.Summary + "\n")
 	}
-	fmt.Printf("%s\n", output)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", output)
 
 	return nil
 }
@@ -362,6 +372,7 @@ func commandMoveTablesComplete(cmd *cobra.Command, args []string) error {
 	}
 
 	cli.FinishedParsing(cmd)
+	reportKeyspace(moveTablesOptions.TargetKeyspace)
 
 	req := &vtctldatapb.MoveTablesCompleteRequest{
 		Workflow:         moveTablesOptions.Workflow,
@@ -393,13 +404,14 @@ func commandMoveTablesComplete(cmd *cobra.Command, args []string) error {
 		}
 		output = tout.Bytes()
 	}
-	fmt.Printf("%s\n", output)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", output)
 
 	return nil
 }
 
 func commandMoveTablesStatus(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
+	reportKeyspace(moveTablesOptions.TargetKeyspace)
 
 	req := &vtctldatapb.WorkflowStatusRequest{
 		Keyspace: moveTablesOptions.TargetKeyspace,
@@ -415,13 +427,14 @@ func commandMoveTablesStatus(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 
 	return nil
 }
 
 func commandMoveTablesShow(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
+	reportKeyspace(moveTablesOptions.TargetKeyspace)
 
 	req := &vtctldatapb.GetWorkflowsRequest{
 		Keyspace: moveTablesOptions.TargetKeyspace,
@@ -437,7 +450,7 @@ func commandMoveTablesShow(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 
 	return nil
 }
@@ -451,6 +464,7 @@ func commandMoveTablesSwitchTraffic(cmd *cobra.Command, args []string) error {
 	}
 
 	cli.FinishedParsing(cmd)
+	reportKeyspace(moveTablesOptions.TargetKeyspace)
 
 	req := &vtctldatapb.WorkflowSwitchTrafficRequest{
 		Keyspace:                  moveTablesOptions.TargetKeyspace,
@@ -461,6 +475,7 @@ func commandMoveTablesSwitchTraffic(cmd *cobra.Command, args []string) error {
 		DryRun:                    moveTablesSwitchTrafficOptions.DryRun,
 		EnableReverseReplication:  moveTablesSwitchTrafficOptions.EnableReverseReplication,
 		InitializeTargetSequences: moveTablesSwitchTrafficOptions.InitializeTargetSequences,
+		Shards:                    moveTablesSwitchTrafficOptions.Shards,
 		Direction:                 int32(moveTablesSwitchTrafficOptions.Direction),
 	}
 	resp, err := client.WorkflowSwitchTraffic(commandCtx, req)
@@ -487,7 +502,7 @@ func commandMoveTablesSwitchTraffic(cmd *cobra.Command, args []string) error {
 		}
 		output = tout.Bytes()
 	}
-	fmt.Printf("%s\n", output)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", output)
 
 	return nil
 }
@@ -512,6 +527,7 @@ func init() {
 
 	MoveTablesCreate.PersistentFlags().StringVar(&moveTablesCreateOptions.SourceKeyspace, "source-keyspace", "", "Keyspace where the tables are being moved from (required)")
 	MoveTablesCreate.MarkPersistentFlagRequired("source-keyspace")
+	MoveTablesCreate.Flags().StringVar(&moveTablesCreateOptions.ExternalClusterName, "external-cluster-name", "", "Name of the external Vitess cluster, mounted with the vtctl Mount command, where the source keyspace is")
 	MoveTablesCreate.Flags().StringSliceVarP(&moveTablesCreateOptions.Cells, "cells", "c", nil, "Cells and/or CellAliases to copy table data from")
 	MoveTablesCreate.Flags().StringSliceVar(&moveTablesCreateOptions.SourceShards, "source-shards", nil, "Source shards to copy data from when performing a partial MoveTables (experimental)")
 	MoveTablesCreate.Flags().StringVar(&moveTablesCreateOptions.SourceTimeZone, "source-time-zone", "", "Specifying this causes any DATETIME fields to be converted from the given time zone into UTC")
@@ -541,6 +557,7 @@ func init() {
 	MoveTablesSwitchTraffic.Flags().BoolVar(&moveTablesSwitchTrafficOptions.EnableReverseReplication, "enable-reverse-replication", true, "Setup replication going back to the original source keyspace to support rolling back the traffic cutover")
 	MoveTablesSwitchTraffic.Flags().BoolVar(&moveTablesSwitchTrafficOptions.DryRun, "dry-run", false, "Print the actions that would be taken and report any known errors that would have occurred")
 	MoveTablesSwitchTraffic.Flags().BoolVar(&moveTablesSwitchTrafficOptions.InitializeTargetSequences, "initialize-target-sequences", false, "When moving tables from an unsharded keyspace to a sharded keyspace, initialize any sequences that are being used on the target when switching writes.")
+	MoveTablesSwitchTraffic.Flags().StringSliceVar(&moveTablesSwitchTrafficOptions.Shards, "shards", nil, "Shards of a partial MoveTables workflow to switch traffic for. By default, the shards whose traffic was not switched yet are switched.")
 	MoveTables.AddCommand(MoveTablesSwitchTraffic)
 
 	MoveTablesReverseTraffic.Flags().StringSliceVarP(&moveTablesSwitchTrafficOptions.Cells, "cells", "c", nil, "Cells and/or CellAliases to switch traffic in")
@@ -549,5 +566,6 @@ func init() {
 	MoveTablesReverseTraffic.Flags().DurationVar(&moveTablesSwitchTrafficOptions.MaxReplicationLagAllowed, "max-replication-lag-allowed", maxReplicationLagDefault, "Allow traffic to be switched only if VReplication lag is below this")
 	MoveTablesReverseTraffic.Flags().BoolVar(&moveTablesSwitchTrafficOptions.EnableReverseReplication, "enable-reverse-replication", true, "Setup replication going back to the original target keyspace to support switching traffic again")
 	MoveTablesReverseTraffic.Flags().BoolVar(&moveTablesSwitchTrafficOptions.DryRun, "dry-run", false, "Print the actions that would be taken and report any known errors that would have occurred")
+	MoveTablesReverseTraffic.Flags().StringSliceVar(&moveTablesSwitchTrafficOptions.Shards, "shards", nil, "Shards of a partial MoveTables workflow to reverse traffic for. By default, the shards whose traffic was switched are reversed.")
 	MoveTables.AddCommand(MoveTablesReverseTraffic)
 }
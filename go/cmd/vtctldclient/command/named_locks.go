@@ -0,0 +1,154 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/protoutil"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	// AcquireNamedLock makes an AcquireNamedLock gRPC call to a vtctld.
+	AcquireNamedLock = &cobra.Command{
+		Use:   "AcquireNamedLock --owner <owner> [--ttl <duration>] [--wait-timeout <duration>] <name>",
+		Short: "Acquires a named lock in the global topo, and outputs it as JSON.",
+		Long: `Acquires a named lock in the global topo, and outputs it as JSON.
+
+Named locks let applications coordinate through the topo of the cluster. A lock is
+held by its owner until it is released with ReleaseNamedLock, or until it expires
+after --ttl if it is not renewed with RenewNamedLock. Acquiring a lock the owner
+already holds renews it.
+
+If another owner holds the lock, the command waits for up to --wait-timeout for it
+to be released or to expire, and fails otherwise.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandAcquireNamedLock,
+	}
+	// ReleaseNamedLock makes a ReleaseNamedLock gRPC call to a vtctld.
+	ReleaseNamedLock = &cobra.Command{
+		Use:                   "ReleaseNamedLock --owner <owner> <name>",
+		Short:                 "Releases a named lock held by the owner.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandReleaseNamedLock,
+	}
+	// RenewNamedLock makes a RenewNamedLock gRPC call to a vtctld.
+	RenewNamedLock = &cobra.Command{
+		Use:   "RenewNamedLock --owner <owner> [--ttl <duration>] <name>",
+		Short: "Extends a named lock held by the owner, and outputs it as JSON.",
+		Long: `Extends a named lock held by the owner, and outputs it as JSON.
+
+The lock expires after --ttl from now, unless it is renewed again. The command fails
+if the lock already expired.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandRenewNamedLock,
+	}
+)
+
+var namedLockOptions = struct {
+	Owner       string
+	TTL         time.Duration
+	WaitTimeout time.Duration
+}{}
+
+func commandAcquireNamedLock(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.AcquireNamedLock(commandCtx, &vtctldatapb.AcquireNamedLockRequest{
+		Name:        cmd.Flags().Arg(0),
+		Owner:       namedLockOptions.Owner,
+		Ttl:         protoutil.DurationToProto(namedLockOptions.TTL),
+		WaitTimeout: protoutil.DurationToProto(namedLockOptions.WaitTimeout),
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp.Lock)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+
+	return nil
+}
+
+func commandReleaseNamedLock(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	name := cmd.Flags().Arg(0)
+	_, err := client.ReleaseNamedLock(commandCtx, &vtctldatapb.ReleaseNamedLockRequest{
+		Name:  name,
+		Owner: namedLockOptions.Owner,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Released named lock %s\n", name)
+
+	return nil
+}
+
+func commandRenewNamedLock(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.RenewNamedLock(commandCtx, &vtctldatapb.RenewNamedLockRequest{
+		Name:  cmd.Flags().Arg(0),
+		Owner: namedLockOptions.Owner,
+		Ttl:   protoutil.DurationToProto(namedLockOptions.TTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp.Lock)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+
+	return nil
+}
+
+func init() {
+	AcquireNamedLock.Flags().StringVar(&namedLockOptions.Owner, "owner", "", "The owner acquiring the lock.")
+	AcquireNamedLock.MarkFlagRequired("owner")
+	AcquireNamedLock.Flags().DurationVar(&namedLockOptions.TTL, "ttl", time.Minute, "How long the lock is held without being renewed.")
+	AcquireNamedLock.Flags().DurationVar(&namedLockOptions.WaitTimeout, "wait-timeout", 0, "How long to wait for the lock if another owner holds it.")
+	Root.AddCommand(AcquireNamedLock)
+
+	ReleaseNamedLock.Flags().StringVar(&namedLockOptions.Owner, "owner", "", "The owner holding the lock.")
+	ReleaseNamedLock.MarkFlagRequired("owner")
+	Root.AddCommand(ReleaseNamedLock)
+
+	RenewNamedLock.Flags().StringVar(&namedLockOptions.Owner, "owner", "", "The owner holding the lock.")
+	RenewNamedLock.MarkFlagRequired("owner")
+	RenewNamedLock.Flags().DurationVar(&namedLockOptions.TTL, "ttl", time.Minute, "How long the lock is held from now without being renewed again.")
+	Root.AddCommand(RenewNamedLock)
+}
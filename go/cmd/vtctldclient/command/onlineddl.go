@@ -17,7 +17,7 @@ package command
 
 import (
 	"fmt"
-	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -67,6 +67,19 @@ var (
 		Args:                  cobra.ExactArgs(2),
 		RunE:                  commandOnlineDDLRetry,
 	}
+	OnlineDDLProgress = &cobra.Command{
+		Use:   "progress [--interval <duration>] [--json] <keyspace> <uuid>",
+		Short: "Streams the progress of a schema migration on each shard, until it completes, fails or is cancelled.",
+		Long: `Streams the progress of a schema migration on each shard, until it completes, fails or is cancelled.
+
+Every --interval, prints the status, progress, rows copied, row-copy throughput, ETA and vreplication
+lag of the migration on each shard. The command also stops when interrupted, or when --action_timeout elapses.`,
+		Example: `OnlineDDL progress test_keyspace 82fa54ac_e83e_11ea_96b7_f875a4d24e90
+OnlineDDL progress --interval 30s --json test_keyspace 82fa54ac_e83e_11ea_96b7_f875a4d24e90`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  commandOnlineDDLProgress,
+	}
 	OnlineDDLShow = &cobra.Command{
 		Use:   "show",
 		Short: "Display information about online DDL operations.",
@@ -82,10 +95,28 @@ OnlineDDL show test_keyspace failed`,
 		Args:                  cobra.RangeArgs(1, 2),
 		RunE:                  commandOnlineDDLShow,
 	}
+	// UpdateOnlineDDLSchedulerConfig makes an UpdateOnlineDDLSchedulerConfig gRPC call to a vtctld.
+	UpdateOnlineDDLSchedulerConfig = &cobra.Command{
+		Use:   "UpdateOnlineDDLSchedulerConfig [--max-concurrent-migrations=<int>] [--priority-class=<name>=<priority> ...] [--remove-priority-class=<name> ...] [--launch-window=<cron expression>] <keyspace>",
+		Short: "Update the Online DDL scheduler configuration of the given keyspace (across all cells).",
+		Long: `Update the Online DDL scheduler configuration of the given keyspace (across all cells).
+
+The configuration limits the number of migrations running concurrently on each shard, orders the
+migrations by the priority of their --priority-class ddl strategy flag, and launches the migrations
+submitted with --postpone-launch during the minutes matching the launch window, a cron-like
+"minute hour day-of-month month day-of-week" expression in UTC.`,
+		Example: `UpdateOnlineDDLSchedulerConfig --max-concurrent-migrations 2 --priority-class urgent=10 --priority-class batch=-1 commerce
+UpdateOnlineDDLSchedulerConfig --launch-window "* 1-5 * * 1-5" commerce
+UpdateOnlineDDLSchedulerConfig --remove-priority-class batch --launch-window "" commerce`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandUpdateOnlineDDLSchedulerConfig,
+	}
 )
 
 func commandOnlineDDLCancel(cmd *cobra.Command, args []string) error {
 	keyspace := cmd.Flags().Arg(0)
+	reportKeyspace(keyspace)
 	uuid := cmd.Flags().Arg(1)
 
 	switch {
@@ -110,12 +141,13 @@ func commandOnlineDDLCancel(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 	return nil
 }
 
 func commandOnlineDDLCleanup(cmd *cobra.Command, args []string) error {
 	keyspace := cmd.Flags().Arg(0)
+	reportKeyspace(keyspace)
 	uuid := cmd.Flags().Arg(1)
 	if !schema.IsOnlineDDLUUID(uuid) {
 		return fmt.Errorf("%s is not a valid UUID", uuid)
@@ -136,12 +168,13 @@ func commandOnlineDDLCleanup(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 	return nil
 }
 
 func commandOnlineDDLRetry(cmd *cobra.Command, args []string) error {
 	keyspace := cmd.Flags().Arg(0)
+	reportKeyspace(keyspace)
 	uuid := cmd.Flags().Arg(1)
 	if !schema.IsOnlineDDLUUID(uuid) {
 		return fmt.Errorf("%s is not a valid UUID", uuid)
@@ -162,10 +195,106 @@ func commandOnlineDDLRetry(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 	return nil
 }
 
+var onlineDDLProgressArgs = struct {
+	Interval time.Duration
+	JSON     bool
+}{}
+
+// onlineDDLShardProgress is the progress of a schema migration on a single shard
+type onlineDDLShardProgress struct {
+	Shard                  string  `json:"shard"`
+	Status                 string  `json:"status"`
+	Progress               float32 `json:"progress"`
+	RowsCopied             uint64  `json:"rows_copied"`
+	TableRows              int64   `json:"table_rows"`
+	RowsCopiedPerSecond    float32 `json:"rows_copied_per_second"`
+	EtaSeconds             int64   `json:"eta_seconds"`
+	VreplicationLagSeconds int64   `json:"vreplication_lag_seconds"`
+}
+
+func commandOnlineDDLProgress(cmd *cobra.Command, args []string) error {
+	keyspace := cmd.Flags().Arg(0)
+	reportKeyspace(keyspace)
+	uuid := cmd.Flags().Arg(1)
+	if !schema.IsOnlineDDLUUID(uuid) {
+		return fmt.Errorf("%s is not a valid UUID", uuid)
+	}
+	if onlineDDLProgressArgs.Interval <= 0 {
+		return fmt.Errorf("--interval must be positive, got %v", onlineDDLProgressArgs.Interval)
+	}
+
+	cli.FinishedParsing(cmd)
+
+	ticker := time.NewTicker(onlineDDLProgressArgs.Interval)
+	defer ticker.Stop()
+
+	for {
+		resp, err := client.GetSchemaMigrations(commandCtx, &vtctldatapb.GetSchemaMigrationsRequest{
+			Keyspace: keyspace,
+			Uuid:     uuid,
+		})
+		if err != nil {
+			return err
+		}
+		if len(resp.Migrations) == 0 {
+			return fmt.Errorf("migration %s not found in keyspace %s", uuid, keyspace)
+		}
+
+		shardProgresses := make([]*onlineDDLShardProgress, 0, len(resp.Migrations))
+		isDone := true
+		for _, migration := range resp.Migrations {
+			shardProgresses = append(shardProgresses, &onlineDDLShardProgress{
+				Shard:                  migration.Shard,
+				Status:                 schematools.SchemaMigrationStatusName(migration.Status),
+				Progress:               migration.Progress,
+				RowsCopied:             migration.RowsCopied,
+				TableRows:              migration.TableRows,
+				RowsCopiedPerSecond:    migration.RowsCopiedPerSecond,
+				EtaSeconds:             migration.EtaSeconds,
+				VreplicationLagSeconds: migration.VreplicationLagSeconds,
+			})
+			switch migration.Status {
+			case vtctldatapb.SchemaMigration_COMPLETE, vtctldatapb.SchemaMigration_FAILED, vtctldatapb.SchemaMigration_CANCELLED:
+			default:
+				isDone = false
+			}
+		}
+		sort.Slice(shardProgresses, func(i, j int) bool {
+			return shardProgresses[i].Shard < shardProgresses[j].Shard
+		})
+
+		switch {
+		case onlineDDLProgressArgs.JSON:
+			data, err := cli.MarshalJSON(shardProgresses)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+		default:
+			res, err := sqltypes.MarshalResult(shardProgresses)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), time.Now().Format(time.RFC3339))
+			cli.WriteQueryResultTable(cmd.OutOrStdout(), res)
+		}
+
+		if isDone {
+			return nil
+		}
+
+		select {
+		case <-commandCtx.Done():
+			return commandCtx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
 var onlineDDLShowArgs = struct {
 	JSON     bool
 	OrderStr string
@@ -189,6 +318,7 @@ func commandOnlineDDLShow(cmd *cobra.Command, args []string) error {
 	}
 
 	cli.FinishedParsing(cmd)
+	reportKeyspace(cmd.Flags().Arg(0))
 
 	req := &vtctldatapb.GetSchemaMigrationsRequest{
 		Keyspace: cmd.Flags().Arg(0),
@@ -223,15 +353,55 @@ func commandOnlineDDLShow(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return err
 		}
-		fmt.Printf("%s\n", data)
+		fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 	default:
 		res, err := sqltypes.MarshalResult(schematools.MarshallableSchemaMigrations(resp.Migrations))
 		if err != nil {
 			return err
 		}
 
-		cli.WriteQueryResultTable(os.Stdout, res)
+		cli.WriteQueryResultTable(cmd.OutOrStdout(), res)
+	}
+	return nil
+}
+
+var updateOnlineDDLSchedulerConfigOptions = struct {
+	MaxConcurrentMigrations int32
+	PriorityClasses         map[string]int
+	RemovePriorityClasses   []string
+	LaunchWindow            string
+}{}
+
+func commandUpdateOnlineDDLSchedulerConfig(cmd *cobra.Command, args []string) error {
+	keyspace := cmd.Flags().Arg(0)
+	reportKeyspace(keyspace)
+	cli.FinishedParsing(cmd)
+
+	req := &vtctldatapb.UpdateOnlineDDLSchedulerConfigRequest{
+		Keyspace:                   keyspace,
+		MaxConcurrentMigrations:    updateOnlineDDLSchedulerConfigOptions.MaxConcurrentMigrations,
+		MaxConcurrentMigrationsSet: cmd.Flags().Changed("max-concurrent-migrations"),
+		RemovePriorityClasses:      updateOnlineDDLSchedulerConfigOptions.RemovePriorityClasses,
+		LaunchWindow:               updateOnlineDDLSchedulerConfigOptions.LaunchWindow,
+		LaunchWindowSet:            cmd.Flags().Changed("launch-window"),
+	}
+	if len(updateOnlineDDLSchedulerConfigOptions.PriorityClasses) > 0 {
+		req.PriorityClasses = make(map[string]int32, len(updateOnlineDDLSchedulerConfigOptions.PriorityClasses))
+		for name, priority := range updateOnlineDDLSchedulerConfigOptions.PriorityClasses {
+			req.PriorityClasses[name] = int32(priority)
+		}
+	}
+
+	resp, err := client.UpdateOnlineDDLSchedulerConfig(commandCtx, req)
+	if err != nil {
+		return err
 	}
+
+	data, err := cli.MarshalJSON(resp.Config)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 	return nil
 }
 
@@ -240,6 +410,10 @@ func init() {
 	OnlineDDL.AddCommand(OnlineDDLCleanup)
 	OnlineDDL.AddCommand(OnlineDDLRetry)
 
+	OnlineDDLProgress.Flags().DurationVar(&onlineDDLProgressArgs.Interval, "interval", 5*time.Second, "How often to print the progress of the migration.")
+	OnlineDDLProgress.Flags().BoolVar(&onlineDDLProgressArgs.JSON, "json", false, "Output one JSON document per interval instead of human-readable tables.")
+	OnlineDDL.AddCommand(OnlineDDLProgress)
+
 	OnlineDDLShow.Flags().BoolVar(&onlineDDLShowArgs.JSON, "json", false, "Output JSON instead of human-readable table.")
 	OnlineDDLShow.Flags().StringVar(&onlineDDLShowArgs.OrderStr, "order", "asc", "Sort the results by `id` property of the Schema migration.")
 	OnlineDDLShow.Flags().Uint64Var(&onlineDDLShowArgs.Limit, "limit", 0, "Limit number of rows returned in output.")
@@ -247,4 +421,10 @@ func init() {
 
 	OnlineDDL.AddCommand(OnlineDDLShow)
 	Root.AddCommand(OnlineDDL)
+
+	UpdateOnlineDDLSchedulerConfig.Flags().Int32Var(&updateOnlineDDLSchedulerConfigOptions.MaxConcurrentMigrations, "max-concurrent-migrations", 0, "Maximum number of migrations running concurrently on each shard. 0 leaves the limit to the --max_concurrent_online_ddl flag of the tablets.")
+	UpdateOnlineDDLSchedulerConfig.Flags().StringToIntVar(&updateOnlineDDLSchedulerConfigOptions.PriorityClasses, "priority-class", nil, "Priority class to add or update, as <name>=<priority>. Migrations of a higher priority are scheduled first. Can be repeated.")
+	UpdateOnlineDDLSchedulerConfig.Flags().StringSliceVar(&updateOnlineDDLSchedulerConfigOptions.RemovePriorityClasses, "remove-priority-class", nil, "Priority class to remove. Can be repeated.")
+	UpdateOnlineDDLSchedulerConfig.Flags().StringVar(&updateOnlineDDLSchedulerConfigOptions.LaunchWindow, "launch-window", "", "Cron-like expression, in UTC, of the minutes during which migrations submitted with --postpone-launch are launched. An empty value removes the launch window.")
+	Root.AddCommand(UpdateOnlineDDLSchedulerConfig)
 }
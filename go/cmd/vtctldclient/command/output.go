@@ -0,0 +1,199 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/vtctlclient"
+
+	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// jsonOutput collects what a command run with --format=json writes to its
+// output, along with the entities it reports, to print them as a single
+// vtctlclient.CommandResult once the command completes.
+type jsonOutput struct {
+	cmd       *cobra.Command
+	collector *vtctlclient.ResultCollector
+	stdout    io.Writer
+}
+
+// Write is part of the io.Writer interface.
+func (out *jsonOutput) Write(p []byte) (int, error) {
+	out.collector.Recv(&logutilpb.Event{
+		Level: logutilpb.Level_CONSOLE,
+		Value: string(p),
+	})
+	return len(p), nil
+}
+
+// currentJSONOutput is the output of the command being run, if it runs with
+// --format=json.
+var currentJSONOutput *jsonOutput
+
+// startJSONOutput sets the output of the given command to its result, until
+// FinishJSONOutput is called.
+func startJSONOutput(cmd *cobra.Command, args []string) error {
+	out := &jsonOutput{
+		cmd:       cmd,
+		collector: vtctlclient.NewResultCollector(commandArgs(cmd, args)),
+		stdout:    cmd.OutOrStdout(),
+	}
+
+	cmd.SetOut(out)
+	currentJSONOutput = out
+	return nil
+}
+
+// FinishJSONOutput prints the result of the command that ran with
+// --format=json, given the error it returned, and restores the command's
+// output. It returns false if there is no such result to print, which is the
+// case if the command ran with --format=text or failed before it could run.
+func FinishJSONOutput(err error) bool {
+	out := currentJSONOutput
+	if out == nil {
+		return false
+	}
+
+	currentJSONOutput = nil
+	out.cmd.SetOut(nil)
+
+	data, merr := json.MarshalIndent(out.collector.Finish(err), "", "  ")
+	if merr != nil {
+		fmt.Fprintf(os.Stderr, "cannot marshal command result: %v\n", merr)
+		return false
+	}
+
+	fmt.Fprintf(out.stdout, "%s\n", data)
+	return true
+}
+
+// commandArgs returns the name of a command, without the root command, along
+// with the flags set on it and its positional arguments.
+func commandArgs(cmd *cobra.Command, args []string) []string {
+	command := strings.Fields(cmd.CommandPath())[1:]
+	cmd.LocalFlags().Visit(func(f *pflag.Flag) {
+		command = append(command, fmt.Sprintf("--%s=%s", f.Name, f.Value.String()))
+	})
+
+	return append(command, args...)
+}
+
+// reportAffected adds entities the command being run acts on to its result,
+// if it runs with --format=json.
+func reportAffected(affected *vtctlclient.AffectedEntities) {
+	if currentJSONOutput != nil {
+		currentJSONOutput.collector.Report(affected)
+	}
+}
+
+// reportKeyspace reports that the command being run acts on a keyspace.
+func reportKeyspace(keyspace string) {
+	affected := &vtctlclient.AffectedEntities{}
+	affected.AddKeyspace(keyspace)
+	reportAffected(affected)
+}
+
+// reportShard reports that the command being run acts on a shard.
+func reportShard(keyspace, shard string) {
+	affected := &vtctlclient.AffectedEntities{}
+	affected.AddShard(keyspace, shard)
+	reportAffected(affected)
+}
+
+// reportTablet reports that the command being run acts on a tablet.
+func reportTablet(alias *topodatapb.TabletAlias) {
+	affected := &vtctlclient.AffectedEntities{}
+	affected.AddTablet(topoproto.TabletAliasString(alias))
+	reportAffected(affected)
+}
+
+// parseKeyspaceShard parses a keyspace/shard, and reports that the command
+// being run acts on that shard.
+func parseKeyspaceShard(param string) (string, string, error) {
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(param)
+	if err != nil {
+		return "", "", err
+	}
+
+	reportShard(keyspace, shard)
+	return keyspace, shard, nil
+}
+
+// parseKeyspaceShards parses keyspace/shard positional arguments, and reports
+// that the command being run acts on those shards.
+func parseKeyspaceShards(args []string) ([]*vtctldatapb.Shard, error) {
+	shards, err := cli.ParseKeyspaceShards(args)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, shard := range shards {
+		reportShard(shard.Keyspace, shard.Name)
+	}
+	return shards, nil
+}
+
+// parseTabletAlias parses a tablet alias, and reports that the command being
+// run acts on that tablet.
+func parseTabletAlias(param string) (*topodatapb.TabletAlias, error) {
+	alias, err := topoproto.ParseTabletAlias(param)
+	if err != nil {
+		return nil, err
+	}
+
+	reportTablet(alias)
+	return alias, nil
+}
+
+// tabletAliasesFromPosArgs parses tablet alias positional arguments, and
+// reports that the command being run acts on those tablets.
+func tabletAliasesFromPosArgs(args []string) ([]*topodatapb.TabletAlias, error) {
+	aliases, err := cli.TabletAliasesFromPosArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, alias := range aliases {
+		reportTablet(alias)
+	}
+	return aliases, nil
+}
+
+// reportKeyspaceOrShard reports that the command being run acts on a shard if
+// one is given, or else on the whole keyspace, as the commands that filter
+// tablets by --keyspace and --shard do.
+func reportKeyspaceOrShard(keyspace, shard string) {
+	if shard != "" {
+		reportShard(keyspace, shard)
+		return
+	}
+
+	reportKeyspace(keyspace)
+}
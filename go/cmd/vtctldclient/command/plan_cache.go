@@ -0,0 +1,133 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	// GetTabletPlanCache makes a GetTabletPlanCache gRPC call to a vtctld.
+	GetTabletPlanCache = &cobra.Command{
+		Use:   "GetTabletPlanCache [--table <table> ...] [--fingerprint <fingerprint> ...] <tablet_alias>",
+		Short: "Outputs a JSON structure with the query plans cached by the tablet, the most used first.",
+		Long: `Outputs a JSON structure with the query plans cached by the tablet, the most used first.
+
+If --table is passed, only the plans of the queries on the tables are returned. If --fingerprint
+is passed, only the plans of the queries with the fingerprints are returned. A query can be passed
+in place of its fingerprint.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandGetTabletPlanCache,
+	}
+	// InvalidateTabletPlanCache makes an InvalidateTabletPlanCache gRPC call to a vtctld.
+	InvalidateTabletPlanCache = &cobra.Command{
+		Use:   "InvalidateTabletPlanCache {--table <table> ... || --fingerprint <fingerprint> ... || --all} <tablet_alias>",
+		Short: "Removes query plans from the plan cache of the tablet, and outputs their number.",
+		Long: `Removes query plans from the plan cache of the tablet, and outputs their number.
+
+The plans of the queries on the tables passed with --table and the plans of the queries with the
+fingerprints passed with --fingerprint are removed, or all the plans with --all. The removed plans
+are built again the next time their queries are executed.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandInvalidateTabletPlanCache,
+	}
+)
+
+var planCacheOptions = struct {
+	Tables       []string
+	Fingerprints []string
+	All          bool
+}{}
+
+func commandGetTabletPlanCache(cmd *cobra.Command, args []string) error {
+	alias, err := parseTabletAlias(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.GetTabletPlanCache(commandCtx, &vtctldatapb.GetTabletPlanCacheRequest{
+		TabletAlias:  alias,
+		Tables:       planCacheOptions.Tables,
+		Fingerprints: planCacheOptions.Fingerprints,
+	})
+	if err != nil {
+		return err
+	}
+
+	entries := resp.Entries
+	if entries == nil {
+		entries = []*tabletmanagerdatapb.PlanCacheEntry{}
+	}
+	data, err := cli.MarshalJSON(entries)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+
+	return nil
+}
+
+func commandInvalidateTabletPlanCache(cmd *cobra.Command, args []string) error {
+	if !planCacheOptions.All && len(planCacheOptions.Tables) == 0 && len(planCacheOptions.Fingerprints) == 0 {
+		return fmt.Errorf("one of the table, fingerprint or all flags must be specified when calling the InvalidateTabletPlanCache command")
+	}
+
+	alias, err := parseTabletAlias(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.InvalidateTabletPlanCache(commandCtx, &vtctldatapb.InvalidateTabletPlanCacheRequest{
+		TabletAlias:  alias,
+		Tables:       planCacheOptions.Tables,
+		Fingerprints: planCacheOptions.Fingerprints,
+		All:          planCacheOptions.All,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Invalidated %d query plans of tablet %s\n", resp.Invalidated, topoproto.TabletAliasString(alias))
+
+	return nil
+}
+
+func init() {
+	GetTabletPlanCache.Flags().StringSliceVar(&planCacheOptions.Tables, "table", nil, "Only return the plans of the queries on the tables.")
+	GetTabletPlanCache.Flags().StringSliceVar(&planCacheOptions.Fingerprints, "fingerprint", nil, "Only return the plans of the queries with the fingerprints.")
+	Root.AddCommand(GetTabletPlanCache)
+
+	InvalidateTabletPlanCache.Flags().StringSliceVar(&planCacheOptions.Tables, "table", nil, "Remove the plans of the queries on the tables.")
+	InvalidateTabletPlanCache.Flags().StringSliceVar(&planCacheOptions.Fingerprints, "fingerprint", nil, "Remove the plans of the queries with the fingerprints.")
+	InvalidateTabletPlanCache.Flags().BoolVar(&planCacheOptions.All, "all", false, "Remove all the plans.")
+	Root.AddCommand(InvalidateTabletPlanCache)
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	// GetPlanHints makes a GetVSchema gRPC call to a vtctld and prints the plan hints of the keyspace.
+	GetPlanHints = &cobra.Command{
+		Use:                   "GetPlanHints <keyspace>",
+		Short:                 "Prints a JSON representation of the plan hints of a keyspace's VSchema.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandGetPlanHints,
+	}
+	// ApplyPlanHint adds or replaces a plan hint in the VSchema of a keyspace.
+	ApplyPlanHint = &cobra.Command{
+		Use:   "ApplyPlanHint {--table=<table> || --query=<query>} [--force-scatter || --vindex=<vindex>] [--disable-rewrite=<rewrite> ...] [--skip-rebuild] [--dry-run] <keyspace> <name>",
+		Short: "Adds the plan hint to the VSchema of the keyspace, replacing the plan hint with the same name.",
+		Long: `Adds the plan hint to the VSchema of the keyspace, replacing the plan hint with the same name.
+
+The plan hint applies to the queries on the table, to the queries with the same fingerprint as the query, or to both.
+The only rewrite that can be disabled is merge_joins, which merges the two sides of a join into a single route.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  commandApplyPlanHint,
+	}
+	// DeletePlanHint removes a plan hint from the VSchema of a keyspace.
+	DeletePlanHint = &cobra.Command{
+		Use:                   "DeletePlanHint [--skip-rebuild] [--dry-run] <keyspace> <name>",
+		Short:                 "Removes the plan hint from the VSchema of the keyspace.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  commandDeletePlanHint,
+	}
+)
+
+var planHintOptions = struct {
+	Table          string
+	Query          string
+	ForceScatter   bool
+	Vindex         string
+	DisableRewrite []string
+	DryRun         bool
+	SkipRebuild    bool
+}{}
+
+func commandGetPlanHints(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+	reportKeyspace(cmd.Flags().Arg(0))
+
+	resp, err := client.GetVSchema(commandCtx, &vtctldatapb.GetVSchemaRequest{
+		Keyspace: cmd.Flags().Arg(0),
+	})
+	if err != nil {
+		return err
+	}
+
+	hints := resp.VSchema.PlanHints
+	if hints == nil {
+		hints = []*vschemapb.PlanHint{}
+	}
+	data, err := cli.MarshalJSON(hints)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+
+	return nil
+}
+
+func commandApplyPlanHint(cmd *cobra.Command, args []string) error {
+	if planHintOptions.Table == "" && planHintOptions.Query == "" {
+		return fmt.Errorf("one of the table or query flags must be specified when calling the ApplyPlanHint command")
+	}
+
+	hint := &vschemapb.PlanHint{
+		Name:            cmd.Flags().Arg(1),
+		Table:           planHintOptions.Table,
+		Query:           planHintOptions.Query,
+		ForceScatter:    planHintOptions.ForceScatter,
+		Vindex:          planHintOptions.Vindex,
+		DisableRewrites: planHintOptions.DisableRewrite,
+	}
+
+	cli.FinishedParsing(cmd)
+
+	return updatePlanHints(cmd, cmd.Flags().Arg(0), func(hints []*vschemapb.PlanHint) []*vschemapb.PlanHint {
+		for i, h := range hints {
+			if h.Name == hint.Name {
+				hints[i] = hint
+				return hints
+			}
+		}
+		return append(hints, hint)
+	})
+}
+
+func commandDeletePlanHint(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	keyspace := cmd.Flags().Arg(0)
+	name := cmd.Flags().Arg(1)
+	found := false
+	err := updatePlanHints(cmd, keyspace, func(hints []*vschemapb.PlanHint) []*vschemapb.PlanHint {
+		res := make([]*vschemapb.PlanHint, 0, len(hints))
+		for _, h := range hints {
+			if h.Name == name {
+				found = true
+				continue
+			}
+			res = append(res, h)
+		}
+		if !found {
+			return nil
+		}
+		return res
+	})
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("plan hint %s not found in keyspace %s", name, keyspace)
+	}
+	return nil
+}
+
+// updatePlanHints applies the plan hints returned by update to the VSchema of the
+// keyspace, and prints them. If update returns nil, the VSchema is left untouched.
+// The VSchema is read and written in two calls, so concurrent changes to the
+// VSchema of the keyspace can be lost.
+func updatePlanHints(cmd *cobra.Command, keyspace string, update func(hints []*vschemapb.PlanHint) []*vschemapb.PlanHint) error {
+	resp, err := client.GetVSchema(commandCtx, &vtctldatapb.GetVSchemaRequest{
+		Keyspace: keyspace,
+	})
+	if err != nil {
+		return err
+	}
+
+	vschema := resp.VSchema
+	hints := update(vschema.PlanHints)
+	if hints == nil {
+		return nil
+	}
+	vschema.PlanHints = hints
+
+	res, err := client.ApplyVSchema(commandCtx, &vtctldatapb.ApplyVSchemaRequest{
+		Keyspace:    keyspace,
+		VSchema:     vschema,
+		SkipRebuild: planHintOptions.SkipRebuild,
+		DryRun:      planHintOptions.DryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(res.VSchema.PlanHints)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "New plan hints:\n%s\n", data)
+	return nil
+}
+
+func init() {
+	Root.AddCommand(GetPlanHints)
+
+	ApplyPlanHint.Flags().StringVar(&planHintOptions.Table, "table", "", "The table of the keyspace the plan hint applies to.")
+	ApplyPlanHint.Flags().StringVar(&planHintOptions.Query, "query", "", "The query the plan hint applies to, matched on its fingerprint.")
+	ApplyPlanHint.Flags().BoolVar(&planHintOptions.ForceScatter, "force-scatter", false, "Route the queries on the table to all the shards.")
+	ApplyPlanHint.Flags().StringVar(&planHintOptions.Vindex, "vindex", "", "The only vindex of the table used to route the queries.")
+	ApplyPlanHint.Flags().StringSliceVar(&planHintOptions.DisableRewrite, "disable-rewrite", nil, "The rewrites the planner must not apply to the queries (merge_joins).")
+	ApplyPlanHint.Flags().BoolVar(&planHintOptions.DryRun, "dry-run", false, "If set, do not save the altered vschema, simply echo the plan hints to console.")
+	ApplyPlanHint.Flags().BoolVar(&planHintOptions.SkipRebuild, "skip-rebuild", false, "Skip rebuilding the SrvSchema objects.")
+	Root.AddCommand(ApplyPlanHint)
+
+	DeletePlanHint.Flags().BoolVar(&planHintOptions.DryRun, "dry-run", false, "If set, do not save the altered vschema, simply echo the plan hints to console.")
+	DeletePlanHint.Flags().BoolVar(&planHintOptions.SkipRebuild, "skip-rebuild", false, "Skip rebuilding the SrvSchema objects.")
+	Root.AddCommand(DeletePlanHint)
+}
@@ -23,7 +23,6 @@ import (
 
 	"vitess.io/vitess/go/cmd/vtctldclient/cli"
 	"vitess.io/vitess/go/sqltypes"
-	"vitess.io/vitess/go/vt/topo/topoproto"
 
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
@@ -57,7 +56,7 @@ var executeFetchAsAppOptions = struct {
 }
 
 func commandExecuteFetchAsApp(cmd *cobra.Command, args []string) error {
-	alias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
+	alias, err := parseTabletAlias(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -84,7 +83,7 @@ func commandExecuteFetchAsApp(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		fmt.Printf("%s\n", data)
+		fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 	default:
 		cli.WriteQueryResultTable(cmd.OutOrStdout(), qr)
 	}
@@ -102,7 +101,7 @@ var executeFetchAsDBAOptions = struct {
 }
 
 func commandExecuteFetchAsDBA(cmd *cobra.Command, args []string) error {
-	alias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
+	alias, err := parseTabletAlias(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -130,7 +129,7 @@ func commandExecuteFetchAsDBA(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		fmt.Printf("%s\n", data)
+		fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 	default:
 		cli.WriteQueryResultTable(cmd.OutOrStdout(), qr)
 	}
@@ -65,6 +65,20 @@ EmergencyReparentShard should be used instead.
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandPlannedReparentShard,
 	}
+	// ReparentPreflight makes a ReparentPreflight gRPC call to a vtctld.
+	ReparentPreflight = &cobra.Command{
+		Use:   "ReparentPreflight <keyspace/shard>",
+		Short: "Checks whether a PlannedReparentShard with the same options can go ahead, without changing anything.",
+		Long: `Checks whether a PlannedReparentShard with the same options can go ahead, without changing anything.
+
+The checks cover the reachability of every tablet of the shard, the selection and the durability policy compliance of
+the primary-elect, the replication health and lag of the replicas, their semi-sync configuration, and errant GTIDs.
+Each check either passes, warns, or fails. The report is printed as JSON, and the command exits with an error if any
+check failed.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandReparentPreflight,
+	}
 	// ReparentTablet makes a ReparentTablet gRPC call to a vtctld.
 	ReparentTablet = &cobra.Command{
 		Use:                   "ReparentTablet <alias>",
@@ -98,7 +112,7 @@ var emergencyReparentShardOptions = struct {
 }{}
 
 func commandEmergencyReparentShard(cmd *cobra.Command, args []string) error {
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -109,14 +123,14 @@ func commandEmergencyReparentShard(cmd *cobra.Command, args []string) error {
 	)
 
 	if emergencyReparentShardOptions.NewPrimaryAliasStr != "" {
-		newPrimaryAlias, err = topoproto.ParseTabletAlias(emergencyReparentShardOptions.NewPrimaryAliasStr)
+		newPrimaryAlias, err = parseTabletAlias(emergencyReparentShardOptions.NewPrimaryAliasStr)
 		if err != nil {
 			return err
 		}
 	}
 
 	for i, aliasStr := range emergencyReparentShardOptions.IgnoreReplicaAliasStrList {
-		alias, err := topoproto.ParseTabletAlias(aliasStr)
+		alias, err := parseTabletAlias(aliasStr)
 		if err != nil {
 			return err
 		}
@@ -140,7 +154,7 @@ func commandEmergencyReparentShard(cmd *cobra.Command, args []string) error {
 	}
 
 	for _, event := range resp.Events {
-		fmt.Println(logutil.EventString(event))
+		fmt.Fprintln(cmd.OutOrStdout(), logutil.EventString(event))
 	}
 
 	return nil
@@ -152,12 +166,12 @@ var initShardPrimaryOptions = struct {
 }{}
 
 func commandInitShardPrimary(cmd *cobra.Command, args []string) error {
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
 
-	tabletAlias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(1))
+	tabletAlias, err := parseTabletAlias(cmd.Flags().Arg(1))
 	if err != nil {
 		return err
 	}
@@ -189,7 +203,7 @@ var plannedReparentShardOptions = struct {
 }{}
 
 func commandPlannedReparentShard(cmd *cobra.Command, args []string) error {
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -200,14 +214,14 @@ func commandPlannedReparentShard(cmd *cobra.Command, args []string) error {
 	)
 
 	if plannedReparentShardOptions.NewPrimaryAliasStr != "" {
-		newPrimaryAlias, err = topoproto.ParseTabletAlias(plannedReparentShardOptions.NewPrimaryAliasStr)
+		newPrimaryAlias, err = parseTabletAlias(plannedReparentShardOptions.NewPrimaryAliasStr)
 		if err != nil {
 			return err
 		}
 	}
 
 	if plannedReparentShardOptions.AvoidPrimaryAliasStr != "" {
-		avoidPrimaryAlias, err = topoproto.ParseTabletAlias(plannedReparentShardOptions.AvoidPrimaryAliasStr)
+		avoidPrimaryAlias, err = parseTabletAlias(plannedReparentShardOptions.AvoidPrimaryAliasStr)
 		if err != nil {
 			return err
 		}
@@ -227,14 +241,72 @@ func commandPlannedReparentShard(cmd *cobra.Command, args []string) error {
 	}
 
 	for _, event := range resp.Events {
-		fmt.Println(logutil.EventString(event))
+		fmt.Fprintln(cmd.OutOrStdout(), logutil.EventString(event))
+	}
+
+	return nil
+}
+
+var reparentPreflightOptions = struct {
+	NewPrimaryAliasStr   string
+	AvoidPrimaryAliasStr string
+	WaitReplicasTimeout  time.Duration
+}{}
+
+func commandReparentPreflight(cmd *cobra.Command, args []string) error {
+	keyspace, shard, err := parseKeyspaceShard(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	var (
+		newPrimaryAlias   *topodatapb.TabletAlias
+		avoidPrimaryAlias *topodatapb.TabletAlias
+	)
+
+	if reparentPreflightOptions.NewPrimaryAliasStr != "" {
+		newPrimaryAlias, err = parseTabletAlias(reparentPreflightOptions.NewPrimaryAliasStr)
+		if err != nil {
+			return err
+		}
+	}
+
+	if reparentPreflightOptions.AvoidPrimaryAliasStr != "" {
+		avoidPrimaryAlias, err = parseTabletAlias(reparentPreflightOptions.AvoidPrimaryAliasStr)
+		if err != nil {
+			return err
+		}
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.ReparentPreflight(commandCtx, &vtctldatapb.ReparentPreflightRequest{
+		Keyspace:            keyspace,
+		Shard:               shard,
+		NewPrimary:          newPrimaryAlias,
+		AvoidPrimary:        avoidPrimaryAlias,
+		WaitReplicasTimeout: protoutil.DurationToProto(reparentPreflightOptions.WaitReplicasTimeout),
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+
+	if !resp.Ok {
+		return fmt.Errorf("reparent preflight checks failed for %s", topoproto.KeyspaceShardString(keyspace, shard))
 	}
 
 	return nil
 }
 
 func commandReparentTablet(cmd *cobra.Command, args []string) error {
-	alias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
+	alias, err := parseTabletAlias(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -251,13 +323,13 @@ func commandReparentTablet(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 
 	return nil
 }
 
 func commandTabletExternallyReparented(cmd *cobra.Command, args []string) error {
-	alias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
+	alias, err := parseTabletAlias(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -274,7 +346,7 @@ func commandTabletExternallyReparented(cmd *cobra.Command, args []string) error
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 
 	return nil
 }
@@ -296,6 +368,11 @@ func init() {
 	PlannedReparentShard.Flags().StringVar(&plannedReparentShardOptions.AvoidPrimaryAliasStr, "avoid-primary", "", "Alias of a tablet that should not be the primary; i.e. \"reparent to any other tablet if this one is the primary\".")
 	Root.AddCommand(PlannedReparentShard)
 
+	ReparentPreflight.Flags().DurationVar(&reparentPreflightOptions.WaitReplicasTimeout, "wait-replicas-timeout", topo.RemoteOperationTimeout, "Wait replicas timeout the PlannedReparentShard would be run with. The primary-elect fails the check if it lags further behind.")
+	ReparentPreflight.Flags().StringVar(&reparentPreflightOptions.NewPrimaryAliasStr, "new-primary", "", "Alias of a tablet that should be the new primary. If not specified, the check selects the tablet PlannedReparentShard would promote.")
+	ReparentPreflight.Flags().StringVar(&reparentPreflightOptions.AvoidPrimaryAliasStr, "avoid-primary", "", "Alias of a tablet that should not be the primary.")
+	Root.AddCommand(ReparentPreflight)
+
 	Root.AddCommand(ReparentTablet)
 	Root.AddCommand(TabletExternallyReparented)
 }
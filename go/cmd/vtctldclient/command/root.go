@@ -25,11 +25,13 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc/metadata"
 
 	"vitess.io/vitess/go/trace"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/servenv"
 	"vitess.io/vitess/go/vt/vtctl/vtctldclient"
+	"vitess.io/vitess/go/vt/vtctld/approval"
 )
 
 var (
@@ -43,6 +45,9 @@ var (
 
 	server        string
 	actionTimeout time.Duration
+	approvalToken string
+	idToken       string
+	format        = "text"
 
 	// Root is the main entrypoint to the vtctldclient CLI.
 	Root = &cobra.Command{
@@ -52,12 +57,29 @@ var (
 		// command context for every command.
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) (err error) {
 			logutil.PurgeLogs()
+			switch format {
+			case "text":
+			case "json":
+				if _, skip := cmd.Annotations[skipJSONOutputKey]; !skip && currentJSONOutput == nil {
+					if err := startJSONOutput(cmd, args); err != nil {
+						return err
+					}
+				}
+			default:
+				return fmt.Errorf("invalid --format %q; valid choices are (text, json)", format)
+			}
 			traceCloser = trace.StartTracing("vtctldclient")
 			client, err = getClientForCommand(cmd)
 			ctx := cmd.Context()
 			if ctx == nil {
 				ctx = context.Background()
 			}
+			if approvalToken != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, approval.TokenMetadataKey, approvalToken)
+			}
+			if idToken != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+idToken)
+			}
 			commandCtx, commandCancel = context.WithTimeout(ctx, actionTimeout)
 			return err
 		},
@@ -108,6 +130,11 @@ var errNoServer = errors.New("please specify --server <vtctld_host:vtctld_port>
 
 const skipClientCreationKey = "skip_client_creation"
 
+// skipJSONOutputKey marks commands, such as Shell, that do not print a single
+// result with --format=json. The Shell command runs each of its commands with
+// the same root flags instead, so each of them prints its own result.
+const skipJSONOutputKey = "skip_json_output"
+
 // getClientForCommand returns a vtctldclient.VtctldClient for a given command.
 // It validates that --server was passed to the CLI for commands that need it.
 func getClientForCommand(cmd *cobra.Command) (vtctldclient.VtctldClient, error) {
@@ -132,4 +159,7 @@ func getClientForCommand(cmd *cobra.Command) (vtctldclient.VtctldClient, error)
 func init() {
 	Root.PersistentFlags().StringVar(&server, "server", "", "server to use for connection (required)")
 	Root.PersistentFlags().DurationVar(&actionTimeout, "action_timeout", time.Hour, "timeout for the total command")
+	Root.PersistentFlags().StringVar(&approvalToken, "approval-token", "", "Token approving a high-risk command, for vtctlds running with --approval-hook=token (see GenerateApprovalToken).")
+	Root.PersistentFlags().StringVar(&format, "format", format, "Output format to use; valid choices are (text, json). In json mode, a single result object describing the command's status, output and affected entities is printed once the command completes. Commands with their own --format flag use that one instead.")
+	Root.PersistentFlags().StringVar(&idToken, "id-token", "", "OpenID Connect id token authenticating the caller, for vtctlds running with --rbac-oidc-issuer-url.")
 }
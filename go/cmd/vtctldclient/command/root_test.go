@@ -17,6 +17,9 @@ limitations under the License.
 package command_test
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"os"
 	"testing"
 
@@ -25,7 +28,10 @@ import (
 
 	"vitess.io/vitess/go/cmd/vtctldclient/command"
 	"vitess.io/vitess/go/vt/vtctl/localvtctldclient"
+	"vitess.io/vitess/go/vt/vtctl/vtctlclient"
 
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 	vtctlservicepb "vitess.io/vitess/go/vt/proto/vtctlservice"
 )
 
@@ -33,6 +39,19 @@ type emptyLocalServer struct {
 	vtctlservicepb.UnimplementedVtctldServer
 }
 
+type keyspaceLocalServer struct {
+	vtctlservicepb.UnimplementedVtctldServer
+}
+
+func (s *keyspaceLocalServer) GetKeyspace(ctx context.Context, req *vtctldatapb.GetKeyspaceRequest) (*vtctldatapb.GetKeyspaceResponse, error) {
+	return &vtctldatapb.GetKeyspaceResponse{
+		Keyspace: &vtctldatapb.Keyspace{
+			Name:     req.Keyspace,
+			Keyspace: &topodatapb.Keyspace{DurabilityPolicy: "none"},
+		},
+	}, nil
+}
+
 func TestRoot(t *testing.T) {
 	t.Run("error on unknown subcommand", func(t *testing.T) {
 		args := append([]string{}, os.Args...)
@@ -51,4 +70,63 @@ func TestRoot(t *testing.T) {
 		require.Error(t, err, "root command should error on unknown command")
 		assert.Contains(t, err.Error(), "unknown command")
 	})
+
+	t.Run("json format", func(t *testing.T) {
+		args := append([]string{}, os.Args...)
+		protocol := command.VtctldClientProtocol
+
+		t.Cleanup(func() {
+			os.Args = append([]string{}, args...)
+			command.VtctldClientProtocol = protocol
+			command.Root.SetOut(nil)
+			_ = command.Root.PersistentFlags().Set("format", "text")
+		})
+
+		tests := []struct {
+			name   string
+			server vtctlservicepb.VtctldServer
+			status string
+			result string
+			err    string
+		}{
+			{
+				name:   "ok",
+				server: &keyspaceLocalServer{},
+				status: vtctlclient.CommandStatusOK,
+				result: "testkeyspace",
+			},
+			{
+				name:   "error",
+				server: &emptyLocalServer{},
+				status: vtctlclient.CommandStatusError,
+				err:    "method GetKeyspace not implemented",
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				localvtctldclient.SetServer(tt.server)
+				command.VtctldClientProtocol = "local"
+				os.Args = []string{"vtctldclient", "--format=json", "GetKeyspace", "testkeyspace"}
+
+				var out bytes.Buffer
+				command.Root.SetOut(&out)
+
+				err := command.Root.Execute()
+				assert.True(t, command.FinishJSONOutput(err))
+
+				data := out.Bytes()
+				var result vtctlclient.CommandResult
+				require.NoError(t, json.Unmarshal(data, &result), "output: %s", data)
+				assert.Equal(t, []string{"GetKeyspace", "testkeyspace"}, result.Command)
+				assert.Equal(t, tt.status, result.Status)
+				assert.Contains(t, string(result.Result), tt.result)
+				assert.Contains(t, result.Error, tt.err)
+				require.NotNil(t, result.Affected)
+				assert.Equal(t, []string{"testkeyspace"}, result.Affected.Keyspaces)
+
+				assert.False(t, command.FinishJSONOutput(nil), "the result should only be printed once")
+			})
+		}
+	})
 }
@@ -93,16 +93,16 @@ func commandApplyRoutingRules(cmd *cobra.Command, args []string) error {
 	}
 
 	if applyRoutingRulesOptions.DryRun {
-		fmt.Printf("[DRY RUN] Would have saved new RoutingRules object:\n%s\n", data)
+		fmt.Fprintf(cmd.OutOrStdout(), "[DRY RUN] Would have saved new RoutingRules object:\n%s\n", data)
 
 		if applyRoutingRulesOptions.SkipRebuild {
-			fmt.Println("[DRY RUN] Would not have rebuilt VSchema graph, would have required operator to run RebuildVSchemaGraph for changes to take effect")
+			fmt.Fprintln(cmd.OutOrStdout(), "[DRY RUN] Would not have rebuilt VSchema graph, would have required operator to run RebuildVSchemaGraph for changes to take effect")
 		} else {
-			fmt.Print("[DRY RUN] Would have rebuilt the VSchema graph")
+			fmt.Fprint(cmd.OutOrStdout(), "[DRY RUN] Would have rebuilt the VSchema graph")
 			if len(applyRoutingRulesOptions.Cells) == 0 {
-				fmt.Print(" in all cells\n")
+				fmt.Fprint(cmd.OutOrStdout(), " in all cells\n")
 			} else {
-				fmt.Printf(" in the following cells: %s.\n", strings.Join(applyRoutingRulesOptions.Cells, ", "))
+				fmt.Fprintf(cmd.OutOrStdout(), " in the following cells: %s.\n", strings.Join(applyRoutingRulesOptions.Cells, ", "))
 			}
 		}
 
@@ -118,10 +118,10 @@ func commandApplyRoutingRules(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("New RoutingRules object:\n%s\nIf this is not what you expected, check the input data (as JSON parsing will skip unexpected fields).\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "New RoutingRules object:\n%s\nIf this is not what you expected, check the input data (as JSON parsing will skip unexpected fields).\n", data)
 
 	if applyRoutingRulesOptions.SkipRebuild {
-		fmt.Println("Skipping rebuild of VSchema graph, will need to run RebuildVSchemaGraph for changes to take effect.")
+		fmt.Fprintln(cmd.OutOrStdout(), "Skipping rebuild of VSchema graph, will need to run RebuildVSchemaGraph for changes to take effect.")
 	}
 
 	return nil
@@ -140,7 +140,7 @@ func commandGetRoutingRules(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 
 	return nil
 }
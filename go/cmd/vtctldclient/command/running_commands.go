@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	// CancelCommand makes a CancelCommand gRPC call to a vtctld.
+	CancelCommand = &cobra.Command{
+		Use:   "CancelCommand <id>",
+		Short: "Cancels a vtctl command running in the vtctld.",
+		Long: `Cancels a vtctl command running in the vtctld, as listed by GetRunningCommands.
+
+The context of the command is canceled, which aborts the server-side operation
+at its next cancellation point.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandCancelCommand,
+	}
+	// GetRunningCommands makes a GetRunningCommands gRPC call to a vtctld.
+	GetRunningCommands = &cobra.Command{
+		Use:                   "GetRunningCommands",
+		Short:                 "Lists the vtctl commands currently running in the vtctld.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		RunE:                  commandGetRunningCommands,
+	}
+)
+
+func commandCancelCommand(cmd *cobra.Command, args []string) error {
+	id, err := strconv.ParseInt(cmd.Flags().Arg(0), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid command id %s: %w", cmd.Flags().Arg(0), err)
+	}
+
+	cli.FinishedParsing(cmd)
+
+	_, err = client.CancelCommand(commandCtx, &vtctldatapb.CancelCommandRequest{
+		Id: id,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Successfully canceled command %d\n", id)
+
+	return nil
+}
+
+func commandGetRunningCommands(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.GetRunningCommands(commandCtx, &vtctldatapb.GetRunningCommandsRequest{})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+
+	return nil
+}
+
+func init() {
+	Root.AddCommand(CancelCommand)
+	Root.AddCommand(GetRunningCommands)
+}
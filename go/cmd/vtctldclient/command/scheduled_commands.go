@@ -0,0 +1,165 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/protoutil"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	// CancelScheduledCommand makes a CancelScheduledCommand gRPC call to a vtctld.
+	CancelScheduledCommand = &cobra.Command{
+		Use:                   "CancelScheduledCommand <id>",
+		Short:                 "Cancels a scheduled vtctl command that has not started yet.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandCancelScheduledCommand,
+	}
+	// GetScheduledCommands makes a GetScheduledCommands gRPC call to a vtctld.
+	GetScheduledCommands = &cobra.Command{
+		Use:                   "GetScheduledCommands",
+		Short:                 "Lists the scheduled vtctl commands, pending or finished, in the order they are to run.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		RunE:                  commandGetScheduledCommands,
+	}
+	// ScheduleCommand makes a ScheduleCommand gRPC call to a vtctld.
+	ScheduleCommand = &cobra.Command{
+		Use:   "ScheduleCommand {--run-at <time> | --run-after <duration>} [--command-timeout <duration>] -- <command> [flags ...] [args ...]",
+		Short: "Schedules a legacy vtctl command for a vtctld to run at a later time.",
+		Long: `Schedules a legacy vtctl command for a vtctld to run at a later time.
+
+The command is stored in the topo, and run by the first vtctld to notice it is
+due, so it runs even if the vtctld it was scheduled with is down at that time.
+The caller must be allowed to run the command, and high-risk commands must be
+approved, when the command is scheduled.
+
+The command's name and arguments are not checked until it runs: its outcome and
+output can be checked with GetScheduledCommands. As with LegacyVtctlCommand, a
+double-dash ("--") must separate the command from the flags of ScheduleCommand.`,
+		Example: `ScheduleCommand --run-at 2023-08-01T02:00:00Z -- PlannedReparentShard --new_primary zone1-101 commerce/0
+ScheduleCommand --run-after 2h -- ChangeTabletType zone1-102 drained`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.MinimumNArgs(1),
+		RunE:                  commandScheduleCommand,
+	}
+)
+
+func commandCancelScheduledCommand(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.CancelScheduledCommand(commandCtx, &vtctldatapb.CancelScheduledCommandRequest{
+		Id: cmd.Flags().Arg(0),
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp.ScheduledCommand)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+
+	return nil
+}
+
+func commandGetScheduledCommands(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.GetScheduledCommands(commandCtx, &vtctldatapb.GetScheduledCommandsRequest{})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+
+	return nil
+}
+
+var scheduleCommandOptions = struct {
+	RunAt          string
+	RunAfter       time.Duration
+	CommandTimeout time.Duration
+}{}
+
+func commandScheduleCommand(cmd *cobra.Command, args []string) error {
+	runAtSet, runAfterSet := cmd.Flags().Changed("run-at"), cmd.Flags().Changed("run-after")
+	if runAtSet == runAfterSet {
+		return errors.New("exactly one of --run-at and --run-after must be passed")
+	}
+
+	req := &vtctldatapb.ScheduleCommandRequest{
+		Args: cmd.Flags().Args(),
+	}
+
+	if runAtSet {
+		runAt, err := time.Parse(time.RFC3339, scheduleCommandOptions.RunAt)
+		if err != nil {
+			return fmt.Errorf("invalid --run-at %q, expected an RFC 3339 time such as 2006-01-02T15:04:05Z: %w", scheduleCommandOptions.RunAt, err)
+		}
+
+		req.RunAt = protoutil.TimeToProto(runAt)
+	} else {
+		req.RunAfter = protoutil.DurationToProto(scheduleCommandOptions.RunAfter)
+	}
+
+	if scheduleCommandOptions.CommandTimeout > 0 {
+		req.ActionTimeout = protoutil.DurationToProto(scheduleCommandOptions.CommandTimeout)
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.ScheduleCommand(commandCtx, req)
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp.ScheduledCommand)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+
+	return nil
+}
+
+func init() {
+	Root.AddCommand(CancelScheduledCommand)
+	Root.AddCommand(GetScheduledCommands)
+
+	ScheduleCommand.Flags().StringVar(&scheduleCommandOptions.RunAt, "run-at", "", "When to run the command, as an RFC 3339 time such as 2006-01-02T15:04:05Z.")
+	ScheduleCommand.Flags().DurationVar(&scheduleCommandOptions.RunAfter, "run-after", 0, "How long from now to run the command.")
+	ScheduleCommand.Flags().DurationVar(&scheduleCommandOptions.CommandTimeout, "command-timeout", 0, "Time limit for the command to run, once it starts. Defaults to 1h.")
+	Root.AddCommand(ScheduleCommand)
+}
@@ -30,7 +30,6 @@ import (
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/schema"
 	"vitess.io/vitess/go/vt/sqlparser"
-	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver"
 
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
@@ -40,7 +39,7 @@ import (
 var (
 	// ApplySchema makes an ApplySchema gRPC call to a vtctld.
 	ApplySchema = &cobra.Command{
-		Use:   "ApplySchema [--ddl-strategy <strategy>] [--uuid <uuid> ...] [--migration-context <context>] [--wait-replicas-timeout <duration>] [--caller-id <caller_id>] {--sql-file <file> | --sql <sql>} <keyspace>",
+		Use:   "ApplySchema [--ddl-strategy <strategy>] [--uuid <uuid> ...] [--migration-context <context>] [--wait-replicas-timeout <duration>] [--caller-id <caller_id>] [--plan] {--sql-file <file> | --sql <sql> | --changelog-file <file>} <keyspace>",
 		Short: "Applies the schema change to the specified keyspace on every primary, running in parallel on all shards. The changes are then propagated to replicas via replication.",
 		Long: `Applies the schema change to the specified keyspace on every primary, running in parallel on all shards. The changes are then propagated to replicas via replication.
 
@@ -48,6 +47,13 @@ If --allow-long-unavailability is set, schema changes affecting a large number o
 --ddl-strategy is used to instruct migrations via vreplication, gh-ost or pt-osc with optional parameters.
 --migration-context allows the user to specify a custom migration context for online DDL migrations.
 If --skip-preflight, SQL goes directly to shards without going through sanity checks.
+If --plan is set, the schema change is not applied. Instead, the statements which would run on each shard are printed, along with the destructive changes (dropped tables, columns and partitions) which a --declarative strategy requires --allow-destructive for.
+--changelog-file applies an ordered changelog, in which each entry is a single statement preceded by a "--changeset <id>" line. Each shard records the IDs of the entries applied to it in its sidecar database, and entries which were already applied are skipped, so the same, growing, changelog can be applied again and again:
+
+	--changeset 0001-create-customer
+	CREATE TABLE customer (id bigint NOT NULL, PRIMARY KEY (id));
+	--changeset 0002-customer-email
+	ALTER TABLE customer ADD COLUMN email varchar(128);
 
 The --uuid and --sql flags are repeatable, so they can be passed multiple times to build a list of values.
 For --uuid, this is used like "--uuid $first_uuid --uuid $second_uuid".
@@ -104,9 +110,15 @@ var applySchemaOptions = struct {
 	SkipPreflight           bool
 	CallerID                string
 	BatchSize               int64
+	Plan                    bool
+	ChangelogFile           string
 }{}
 
 func commandApplySchema(cmd *cobra.Command, args []string) error {
+	if applySchemaOptions.ChangelogFile != "" {
+		return applySchemaChangelog(cmd)
+	}
+
 	var allSQL string
 	if applySchemaOptions.SQLFile != "" {
 		if len(applySchemaOptions.SQL) != 0 {
@@ -136,6 +148,7 @@ func commandApplySchema(cmd *cobra.Command, args []string) error {
 	}
 
 	ks := cmd.Flags().Arg(0)
+	reportKeyspace(ks)
 
 	resp, err := client.ApplySchema(commandCtx, &vtctldatapb.ApplySchemaRequest{
 		Keyspace:            ks,
@@ -147,12 +160,63 @@ func commandApplySchema(cmd *cobra.Command, args []string) error {
 		WaitReplicasTimeout: protoutil.DurationToProto(applySchemaOptions.WaitReplicasTimeout),
 		CallerId:            cid,
 		BatchSize:           applySchemaOptions.BatchSize,
+		Plan:                applySchemaOptions.Plan,
+	})
+	if err != nil {
+		return err
+	}
+
+	if applySchemaOptions.Plan {
+		data, err := cli.MarshalJSON(resp.ShardPlans)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+		return nil
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), strings.Join(resp.UuidList, "\n"))
+	return nil
+}
+
+func applySchemaChangelog(cmd *cobra.Command) error {
+	if len(applySchemaOptions.SQL) != 0 || applySchemaOptions.SQLFile != "" {
+		return errors.New("--changelog-file cannot be used with --sql or --sql-file.") // nolint
+	}
+	if applySchemaOptions.Plan {
+		return errors.New("--changelog-file cannot be used with --plan.") // nolint
+	}
+
+	data, err := os.ReadFile(applySchemaOptions.ChangelogFile)
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+	reportKeyspace(cmd.Flags().Arg(0))
+
+	var cid *vtrpc.CallerID
+	if applySchemaOptions.CallerID != "" {
+		cid = &vtrpc.CallerID{Principal: applySchemaOptions.CallerID}
+	}
+
+	resp, err := client.ApplySchema(commandCtx, &vtctldatapb.ApplySchemaRequest{
+		Keyspace:            cmd.Flags().Arg(0),
+		DdlStrategy:         applySchemaOptions.DDLStrategy,
+		Changelog:           string(data),
+		SkipPreflight:       true,
+		UuidList:            applySchemaOptions.UUIDList,
+		MigrationContext:    applySchemaOptions.MigrationContext,
+		WaitReplicasTimeout: protoutil.DurationToProto(applySchemaOptions.WaitReplicasTimeout),
+		CallerId:            cid,
+		BatchSize:           applySchemaOptions.BatchSize,
 	})
 	if err != nil {
 		return err
 	}
 
-	fmt.Println(strings.Join(resp.UuidList, "\n"))
+	fmt.Fprintln(cmd.OutOrStdout(), strings.Join(resp.UuidList, "\n"))
 	return nil
 }
 
@@ -170,7 +234,7 @@ func commandGetSchema(cmd *cobra.Command, args []string) error {
 		return errors.New("can only pass one of --table-names-only and --table-sizes-only")
 	}
 
-	alias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
+	alias, err := parseTabletAlias(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -197,7 +261,7 @@ func commandGetSchema(cmd *cobra.Command, args []string) error {
 			names[i] = td.Name
 		}
 
-		fmt.Printf("%s\n", strings.Join(names, "\n"))
+		fmt.Fprintf(cmd.OutOrStdout(), "%s\n", strings.Join(names, "\n"))
 
 		return nil
 	}
@@ -207,13 +271,13 @@ func commandGetSchema(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 
 	return nil
 }
 
 func commandReloadSchema(cmd *cobra.Command, args []string) error {
-	tabletAlias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
+	tabletAlias, err := parseTabletAlias(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -239,6 +303,7 @@ var reloadSchemaKeyspaceOptions = struct {
 
 func commandReloadSchemaKeyspace(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
+	reportKeyspace(cmd.Flags().Arg(0))
 
 	logger := logutil.NewConsoleLogger()
 	resp, err := client.ReloadSchemaKeyspace(commandCtx, &vtctldatapb.ReloadSchemaKeyspaceRequest{
@@ -263,7 +328,7 @@ var reloadSchemaShardOptions = struct {
 }
 
 func commandReloadSchemaShard(cmd *cobra.Command, args []string) error {
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -299,6 +364,8 @@ func init() {
 	ApplySchema.Flags().StringArrayVar(&applySchemaOptions.SQL, "sql", nil, "Semicolon-delimited, repeatable SQL commands to apply. Exactly one of --sql|--sql-file is required.")
 	ApplySchema.Flags().StringVar(&applySchemaOptions.SQLFile, "sql-file", "", "Path to a file containing semicolon-delimited SQL commands to apply. Exactly one of --sql|--sql-file is required.")
 	ApplySchema.Flags().Int64Var(&applySchemaOptions.BatchSize, "batch-size", 0, "How many queries to batch together. Only applicable when all queries are CREATE TABLE|VIEW")
+	ApplySchema.Flags().StringVar(&applySchemaOptions.ChangelogFile, "changelog-file", "", "Path to a changelog file, of which each entry is a statement preceded by a '--changeset <id>' line. Entries which were already applied to a shard are skipped.")
+	ApplySchema.Flags().BoolVar(&applySchemaOptions.Plan, "plan", false, "Does not apply the schema change. Prints the statements which would run on each shard, diffed against the current schema of the shard for --declarative strategies.")
 
 	Root.AddCommand(ApplySchema)
 
@@ -112,7 +112,7 @@ func commandGetSrvKeyspaceNames(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 	return nil
 }
 
@@ -120,6 +120,7 @@ func commandGetSrvKeyspaces(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
 	keyspace := cmd.Flags().Arg(0)
+	reportKeyspace(keyspace)
 	cells := cmd.Flags().Args()[1:]
 
 	resp, err := client.GetSrvKeyspaces(commandCtx, &vtctldatapb.GetSrvKeyspacesRequest{
@@ -135,7 +136,7 @@ func commandGetSrvKeyspaces(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 
 	return nil
 }
@@ -157,7 +158,7 @@ func commandGetSrvVSchema(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 
 	return nil
 }
@@ -184,7 +185,7 @@ func commandGetSrvVSchemas(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 
 	return nil
 }
@@ -199,6 +200,7 @@ func commandRebuildKeyspaceGraph(cmd *cobra.Command, args []string) error {
 
 	keyspaces := cmd.Flags().Args()
 	for _, ks := range keyspaces {
+		reportKeyspace(ks)
 		_, err := client.RebuildKeyspaceGraph(commandCtx, &vtctldatapb.RebuildKeyspaceGraphRequest{
 			Keyspace:     ks,
 			Cells:        rebuildKeyspaceGraphOptions.Cells,
@@ -226,7 +228,7 @@ func commandRebuildVSchemaGraph(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Println("RebuildVSchemaGraph: ok")
+	fmt.Fprintln(cmd.OutOrStdout(), "RebuildVSchemaGraph: ok")
 
 	return nil
 }
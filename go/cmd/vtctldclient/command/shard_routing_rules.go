@@ -97,16 +97,16 @@ func commandApplyShardRoutingRules(cmd *cobra.Command, args []string) error {
 	}
 
 	if applyShardRoutingRulesOptions.DryRun {
-		fmt.Printf("[DRY RUN] Would have saved new ShardRoutingRules object:\n%s\n", data)
+		fmt.Fprintf(cmd.OutOrStdout(), "[DRY RUN] Would have saved new ShardRoutingRules object:\n%s\n", data)
 
 		if applyRoutingRulesOptions.SkipRebuild {
-			fmt.Println("[DRY RUN] Would not have rebuilt VSchema graph, would have required operator to run RebuildVSchemaGraph for changes to take effect.")
+			fmt.Fprintln(cmd.OutOrStdout(), "[DRY RUN] Would not have rebuilt VSchema graph, would have required operator to run RebuildVSchemaGraph for changes to take effect.")
 		} else {
-			fmt.Print("[DRY RUN] Would have rebuilt the VSchema graph")
+			fmt.Fprint(cmd.OutOrStdout(), "[DRY RUN] Would have rebuilt the VSchema graph")
 			if len(applyRoutingRulesOptions.Cells) == 0 {
-				fmt.Print(" in all cells\n")
+				fmt.Fprint(cmd.OutOrStdout(), " in all cells\n")
 			} else {
-				fmt.Printf(" in the following cells: %s.\n", strings.Join(applyShardRoutingRulesOptions.Cells, ", "))
+				fmt.Fprintf(cmd.OutOrStdout(), " in the following cells: %s.\n", strings.Join(applyShardRoutingRulesOptions.Cells, ", "))
 			}
 		}
 
@@ -122,10 +122,10 @@ func commandApplyShardRoutingRules(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("New ShardRoutingRules object:\n%s\nIf this is not what you expected, check the input data (as JSON parsing will skip unexpected fields).\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "New ShardRoutingRules object:\n%s\nIf this is not what you expected, check the input data (as JSON parsing will skip unexpected fields).\n", data)
 
 	if applyRoutingRulesOptions.SkipRebuild {
-		fmt.Println("Skipping rebuild of VSchema graph as requested, you will need to run RebuildVSchemaGraph for the changes to take effect.")
+		fmt.Fprintln(cmd.OutOrStdout(), "Skipping rebuild of VSchema graph as requested, you will need to run RebuildVSchemaGraph for the changes to take effect.")
 	}
 
 	return nil
@@ -144,7 +144,7 @@ func commandGetShardRoutingRules(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 
 	return nil
 }
@@ -19,6 +19,7 @@ package command
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -34,7 +35,7 @@ import (
 var (
 	// CreateShard makes a CreateShard gRPC request to a vtctld.
 	CreateShard = &cobra.Command{
-		Use:                   "CreateShard [--force|-f] [--include-parent|-p] <keyspace/shard>",
+		Use:                   "CreateShard [--force|-f] [--include-parent|-p] [--if-not-exists] <keyspace/shard>",
 		Short:                 "Creates the specified shard in the topology.",
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(1),
@@ -42,7 +43,7 @@ var (
 	}
 	// DeleteShards makes a DeleteShards gRPC request to a vtctld.
 	DeleteShards = &cobra.Command{
-		Use:   "DeleteShards [--recursive|-r] [--even-if-serving] [--force|-f] <keyspace/shard> [<keyspace/shard> ...]",
+		Use:   "DeleteShards [--recursive|-r] [--even-if-serving] [--force|-f] [--dry-run] <keyspace/shard> [<keyspace/shard> ...]",
 		Short: "Deletes the specified shards from the topology.",
 		Long: `Deletes the specified shards from the topology.
 
@@ -78,7 +79,7 @@ that shard.`,
 				return err
 			}
 
-			fmt.Printf("%s\n", data)
+			fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 			return nil
 		},
 		Annotations: map[string]string{
@@ -95,7 +96,7 @@ that shard.`,
 	}
 	// RemoveShardCell makes a RemoveShardCell gRPC request to a vtctld.
 	RemoveShardCell = &cobra.Command{
-		Use:                   "RemoveShardCell [--force|-f] [--recursive|-r] <keyspace/shard> <cell>",
+		Use:                   "RemoveShardCell [--force|-f] [--recursive|-r] [--dry-run] <keyspace/shard> <cell>",
 		Short:                 "Remove the specified cell from the specified shard's Cells list.",
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(2),
@@ -200,10 +201,11 @@ Use ctrl-C to interrupt the command and see partial results if needed.`,
 var createShardOptions = struct {
 	Force         bool
 	IncludeParent bool
+	IfNotExists   bool
 }{}
 
 func commandCreateShard(cmd *cobra.Command, args []string) error {
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -215,6 +217,7 @@ func commandCreateShard(cmd *cobra.Command, args []string) error {
 		ShardName:     shard,
 		Force:         createShardOptions.Force,
 		IncludeParent: createShardOptions.IncludeParent,
+		IfNotExists:   createShardOptions.IfNotExists,
 	})
 	if err != nil {
 		return err
@@ -225,7 +228,7 @@ func commandCreateShard(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 
 	return nil
 }
@@ -234,34 +237,41 @@ var deleteShardsOptions = struct {
 	Recursive     bool
 	EvenIfServing bool
 	Force         bool
+	DryRun        bool
 }{}
 
 func commandDeleteShards(cmd *cobra.Command, args []string) error {
-	shards, err := cli.ParseKeyspaceShards(cmd.Flags().Args())
+	shards, err := parseKeyspaceShards(cmd.Flags().Args())
 	if err != nil {
 		return err
 	}
 
 	cli.FinishedParsing(cmd)
 
-	_, err = client.DeleteShards(commandCtx, &vtctldatapb.DeleteShardsRequest{
+	resp, err := client.DeleteShards(commandCtx, &vtctldatapb.DeleteShardsRequest{
 		Shards:        shards,
 		EvenIfServing: deleteShardsOptions.EvenIfServing,
 		Recursive:     deleteShardsOptions.Recursive,
 		Force:         deleteShardsOptions.Force,
+		DryRun:        deleteShardsOptions.DryRun,
 	})
 
 	if err != nil {
 		return fmt.Errorf("%w: while deleting %d shards; please inspect the topo", err, len(shards))
 	}
 
-	fmt.Printf("Successfully deleted %d shards\n", len(shards))
+	if deleteShardsOptions.DryRun {
+		fmt.Fprintf(cmd.OutOrStdout(), "Dry run results for deleting %d shards:\n%s\n", len(shards), strings.Join(resp.DryRunResults, "\n"))
+		return nil
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Successfully deleted %d shards\n", len(shards))
 
 	return nil
 }
 
 func commandGetShard(cmd *cobra.Command, args []string) error {
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -281,7 +291,7 @@ func commandGetShard(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 
 	return nil
 }
@@ -289,10 +299,11 @@ func commandGetShard(cmd *cobra.Command, args []string) error {
 var removeShardCellOptions = struct {
 	Force     bool
 	Recursive bool
+	DryRun    bool
 }{}
 
 func commandRemoveShardCell(cmd *cobra.Command, args []string) error {
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -301,25 +312,31 @@ func commandRemoveShardCell(cmd *cobra.Command, args []string) error {
 
 	cell := cmd.Flags().Arg(1)
 
-	_, err = client.RemoveShardCell(commandCtx, &vtctldatapb.RemoveShardCellRequest{
+	resp, err := client.RemoveShardCell(commandCtx, &vtctldatapb.RemoveShardCellRequest{
 		Keyspace:  keyspace,
 		ShardName: shard,
 		Cell:      cell,
 		Force:     removeShardCellOptions.Force,
 		Recursive: removeShardCellOptions.Recursive,
+		DryRun:    removeShardCellOptions.DryRun,
 	})
 
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Successfully removed cell %v from shard %s/%s\n", cell, keyspace, shard)
+	if removeShardCellOptions.DryRun {
+		fmt.Fprintf(cmd.OutOrStdout(), "Dry run results for removing cell %v from shard %s/%s:\n%s\n", cell, keyspace, shard, strings.Join(resp.DryRunResults, "\n"))
+		return nil
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Successfully removed cell %v from shard %s/%s\n", cell, keyspace, shard)
 
 	return nil
 }
 
 func commandSetShardIsPrimaryServing(cmd *cobra.Command, args []string) error {
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(cmd.Flags().Arg(0))
 	if err != nil {
 		return fmt.Errorf("cannot parse keyspace/shard: %w", err)
 	}
@@ -345,7 +362,7 @@ func commandSetShardIsPrimaryServing(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 	return nil
 }
 
@@ -357,7 +374,7 @@ var setShardTabletControlOptions = struct {
 }{}
 
 func commandSetShardTabletControl(cmd *cobra.Command, args []string) error {
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(cmd.Flags().Arg(0))
 	if err != nil {
 		return fmt.Errorf("cannot parse keyspace/shard: %w", err)
 	}
@@ -387,17 +404,17 @@ func commandSetShardTabletControl(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 	return nil
 }
 
 func commandShardReplicationAdd(cmd *cobra.Command, args []string) error {
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
 
-	tabletAlias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(1))
+	tabletAlias, err := parseTabletAlias(cmd.Flags().Arg(1))
 	if err != nil {
 		return err
 	}
@@ -414,7 +431,7 @@ func commandShardReplicationAdd(cmd *cobra.Command, args []string) error {
 
 func commandShardReplicationFix(cmd *cobra.Command, args []string) error {
 	cell := cmd.Flags().Arg(0)
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(1))
+	keyspace, shard, err := parseKeyspaceShard(cmd.Flags().Arg(1))
 	if err != nil {
 		return err
 	}
@@ -432,16 +449,16 @@ func commandShardReplicationFix(cmd *cobra.Command, args []string) error {
 
 	switch resp.Error {
 	case nil:
-		fmt.Println("All nodes in the replication graph are valid.")
+		fmt.Fprintln(cmd.OutOrStdout(), "All nodes in the replication graph are valid.")
 	default:
-		fmt.Printf("%s has been fixed for %s.\n", topoproto.ShardReplicationErrorTypeString(resp.Error.Type), topoproto.TabletAliasString(resp.Error.TabletAlias))
+		fmt.Fprintf(cmd.OutOrStdout(), "%s has been fixed for %s.\n", topoproto.ShardReplicationErrorTypeString(resp.Error.Type), topoproto.TabletAliasString(resp.Error.TabletAlias))
 	}
 
 	return nil
 }
 
 func commandShardReplicationPositions(cmd *cobra.Command, args []string) error {
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -466,19 +483,19 @@ func commandShardReplicationPositions(cmd *cobra.Command, args []string) error {
 			line = cli.MarshalTabletAWK(rt.Tablet) + fmt.Sprintf(" %v %v", rt.Status.Position, rt.Status.ReplicationLagSeconds)
 		}
 
-		fmt.Println(line)
+		fmt.Fprintln(cmd.OutOrStdout(), line)
 	}
 
 	return nil
 }
 
 func commandShardReplicationRemove(cmd *cobra.Command, args []string) error {
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
 
-	tabletAlias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(1))
+	tabletAlias, err := parseTabletAlias(cmd.Flags().Arg(1))
 	if err != nil {
 		return err
 	}
@@ -499,7 +516,7 @@ var sourceShardAddOptions = struct {
 }{}
 
 func commandSourceShardAdd(cmd *cobra.Command, args []string) error {
-	ks, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	ks, shard, err := parseKeyspaceShard(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -509,7 +526,7 @@ func commandSourceShardAdd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("Failed to parse SourceShard uid: %w", err) // nolint
 	}
 
-	sks, sshard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(2))
+	sks, sshard, err := parseKeyspaceShard(cmd.Flags().Arg(2))
 	if err != nil {
 		return err
 	}
@@ -539,21 +556,21 @@ func commandSourceShardAdd(cmd *cobra.Command, args []string) error {
 
 	switch resp.Shard {
 	case nil:
-		fmt.Printf("SourceShard with uid %v already exists for %s/%s, not adding it.\n", uid, ks, shard)
+		fmt.Fprintf(cmd.OutOrStdout(), "SourceShard with uid %v already exists for %s/%s, not adding it.\n", uid, ks, shard)
 	default:
 		data, err := cli.MarshalJSON(resp.Shard)
 		if err != nil {
 			return err
 		}
 
-		fmt.Printf("Updated shard record:\n%s\n", data)
+		fmt.Fprintf(cmd.OutOrStdout(), "Updated shard record:\n%s\n", data)
 	}
 
 	return nil
 }
 
 func commandSourceShardDelete(cmd *cobra.Command, args []string) error {
-	ks, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	ks, shard, err := parseKeyspaceShard(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -576,20 +593,20 @@ func commandSourceShardDelete(cmd *cobra.Command, args []string) error {
 
 	switch resp.Shard {
 	case nil:
-		fmt.Printf("No SourceShard with uid %v.\n", uid)
+		fmt.Fprintf(cmd.OutOrStdout(), "No SourceShard with uid %v.\n", uid)
 	default:
 		data, err := cli.MarshalJSON(resp.Shard)
 		if err != nil {
 			return err
 		}
 
-		fmt.Printf("Updated shard record:\n%s\n", data)
+		fmt.Fprintf(cmd.OutOrStdout(), "Updated shard record:\n%s\n", data)
 	}
 	return nil
 }
 
 func commandValidateVersionShard(cmd *cobra.Command, args []string) error {
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -609,18 +626,20 @@ func commandValidateVersionShard(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 	return nil
 }
 
 func init() {
 	CreateShard.Flags().BoolVarP(&createShardOptions.Force, "force", "f", false, "Overwrite an existing shard record, if one exists.")
 	CreateShard.Flags().BoolVarP(&createShardOptions.IncludeParent, "include-parent", "p", false, "Creates the parent keyspace record if does not already exist.")
+	CreateShard.Flags().BoolVar(&createShardOptions.IfNotExists, "if-not-exists", false, "Succeeds without making any changes if the shard already exists.")
 	Root.AddCommand(CreateShard)
 
 	DeleteShards.Flags().BoolVarP(&deleteShardsOptions.Recursive, "recursive", "r", false, "Also delete all tablets belonging to the shard. This is required to delete a non-empty shard.")
 	DeleteShards.Flags().BoolVar(&deleteShardsOptions.EvenIfServing, "even-if-serving", false, "Remove the shard even if it is serving. Use with caution.")
 	DeleteShards.Flags().BoolVarP(&deleteShardsOptions.Force, "force", "f", false, "Remove the shard even if it cannot be locked; this should only be used for cleanup operations.")
+	DeleteShards.Flags().BoolVar(&deleteShardsOptions.DryRun, "dry-run", false, "Print the topo mutations that would be performed, without actually deleting anything.")
 	Root.AddCommand(DeleteShards)
 
 	Root.AddCommand(GetShard)
@@ -628,6 +647,7 @@ func init() {
 
 	RemoveShardCell.Flags().BoolVarP(&removeShardCellOptions.Force, "force", "f", false, "Proceed even if the cell's topology server cannot be reached. The assumption is that you turned down the entire cell, and just need to update the global topo data.")
 	RemoveShardCell.Flags().BoolVarP(&removeShardCellOptions.Recursive, "recursive", "r", false, "Also delete all tablets in that cell beloning to the specified shard.")
+	RemoveShardCell.Flags().BoolVar(&removeShardCellOptions.DryRun, "dry-run", false, "Print the topo mutations that would be performed, without actually removing anything.")
 	Root.AddCommand(RemoveShardCell)
 
 	Root.AddCommand(SetShardIsPrimaryServing)
@@ -0,0 +1,606 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/shlex"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/trace"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/vtctldclient"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	// Shell starts an interactive vtctldclient shell.
+	Shell = &cobra.Command{
+		Use:     "Shell",
+		Aliases: []string{"shell"},
+		Short:   "Starts an interactive shell to run vtctldclient commands in.",
+		Long: `Starts an interactive shell to run vtctldclient commands in.
+
+Each line is run as a vtctldclient command against the same --server, with the
+same root flags. Flags given to a command only apply to that command.
+
+Pressing Tab completes command names, flag names, keyspaces, keyspace/shard
+names, cells and tablet aliases, the latter fetched from the vtctld. When
+several completions are possible, pressing Tab again lists them, along with
+the description of commands. The Up and Down arrow keys go through the command
+history of the session.
+
+Besides the vtctldclient commands, the shell understands:
+
+  help [command]  Prints the help of a command.
+  history         Prints the command history of the session.
+  exit, quit      Exits the shell, as does Ctrl-D.
+
+Ctrl-C cancels the running command. When the standard input is not a terminal,
+the commands are read from it, one per line, which allows running scripts of
+commands.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		RunE:                  commandShell,
+		Annotations: map[string]string{
+			skipJSONOutputKey: "true",
+		},
+	}
+)
+
+const (
+	shellPrompt = "vtctldclient> "
+
+	// completionCacheTTL is how long the topology names fetched for
+	// completion are reused for.
+	completionCacheTTL = 30 * time.Second
+	// completionTimeout bounds the calls made to the vtctld to complete a
+	// word, so that an unreachable vtctld does not hang the shell.
+	completionTimeout = 5 * time.Second
+)
+
+var shellBuiltins = []shellCandidate{
+	{Value: "exit", Help: "Exits the shell."},
+	{Value: "help", Help: "Prints the help of a command."},
+	{Value: "history", Help: "Prints the command history of the session."},
+	{Value: "quit", Help: "Exits the shell."},
+}
+
+func commandShell(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	sh := &shell{
+		root:      Root,
+		cmd:       cmd,
+		completer: newShellCompleter(Root, cmd, client),
+		flags:     snapshotFlags(Root),
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return sh.runScript(ctx, os.Stdin)
+	}
+
+	return sh.runInteractive(ctx, fd)
+}
+
+type shell struct {
+	root *cobra.Command
+	// cmd is the Shell command itself.
+	cmd       *cobra.Command
+	completer *shellCompleter
+	flags     flagSnapshot
+	history   []string
+}
+
+func (sh *shell) runInteractive(ctx context.Context, fd int) error {
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, shellPrompt)
+	t.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+
+		newLine, newPos, matches := sh.completer.Complete(ctx, line, pos)
+		if len(matches) > 0 {
+			fmt.Fprint(t, formatCandidates(matches))
+		}
+
+		return newLine, newPos, true
+	}
+
+	for {
+		// The terminal is only in raw mode while reading a line, so that the
+		// output of the commands is not mangled.
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return err
+		}
+
+		if width, height, err := term.GetSize(fd); err == nil && width > 0 {
+			_ = t.SetSize(width, height)
+		}
+
+		line, err := t.ReadLine()
+		_ = term.Restore(fd, state)
+
+		switch {
+		case errors.Is(err, io.EOF):
+			fmt.Println()
+			return nil
+		case err != nil:
+			return err
+		}
+
+		if exit := sh.runLine(ctx, line); exit {
+			return nil
+		}
+	}
+}
+
+func (sh *shell) runScript(ctx context.Context, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if exit := sh.runLine(ctx, scanner.Text()); exit {
+			return nil
+		}
+	}
+
+	return scanner.Err()
+}
+
+// runLine runs a single line of input, and returns whether the shell should
+// exit.
+func (sh *shell) runLine(ctx context.Context, line string) (exit bool) {
+	args, err := shlex.Split(line)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: cannot parse %q: %v\n", line, err)
+		return false
+	}
+
+	if len(args) == 0 {
+		return false
+	}
+
+	sh.history = append(sh.history, line)
+
+	switch args[0] {
+	case "exit", "quit":
+		return true
+	case "history":
+		for i, l := range sh.history {
+			fmt.Fprintf(sh.cmd.OutOrStdout(), "%5d  %s\n", i+1, l)
+		}
+		return false
+	}
+
+	if err := sh.execute(ctx, args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+
+	return false
+}
+
+// execute runs a vtctldclient command from the shell.
+func (sh *shell) execute(ctx context.Context, args []string) error {
+	if cmd, _, err := sh.root.Find(args); err == nil && cmd == sh.cmd {
+		return errors.New("already in a shell")
+	}
+
+	// The root command's PersistentPreRunE replaces the client, tracer and
+	// command context with the ones of the command being run, so keep the
+	// shell's aside to put them back afterwards.
+	shellClient, shellTraceCloser, shellCtx, shellCancel := client, traceCloser, commandCtx, commandCancel
+	client, traceCloser, commandCtx, commandCancel = nil, nil, nil, nil
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	// cobra only sets the context of a subcommand the first time it runs, so
+	// set the one of this command on all of them.
+	setContext(sh.root, ctx)
+	sh.root.SetArgs(args)
+
+	err := sh.root.ExecuteContext(ctx)
+	if err != nil {
+		// PersistentPostRunE does not run after a failed command, so release
+		// whatever PersistentPreRunE set up for it.
+		if commandCancel != nil {
+			commandCancel()
+		}
+		if client != nil {
+			_ = client.Close()
+		}
+		if traceCloser != nil {
+			trace.LogErrorsWhenClosing(traceCloser)
+		}
+	}
+
+	if FinishJSONOutput(err) {
+		// The error, if any, is part of the printed result.
+		err = nil
+	}
+
+	client, traceCloser, commandCtx, commandCancel = shellClient, shellTraceCloser, shellCtx, shellCancel
+	sh.root.SetArgs(nil)
+
+	if rerr := sh.flags.restore(sh.root); rerr != nil && err == nil {
+		err = rerr
+	}
+
+	return err
+}
+
+func setContext(cmd *cobra.Command, ctx context.Context) {
+	cmd.SetContext(ctx)
+	for _, sub := range cmd.Commands() {
+		setContext(sub, ctx)
+	}
+}
+
+// flagSnapshot records the values of the flags of a command tree, so that the
+// flags passed to a command run from the shell do not carry over to the next
+// ones.
+type flagSnapshot map[*pflag.Flag]flagValue
+
+type flagValue struct {
+	value   string
+	slice   []string
+	changed bool
+}
+
+func visitFlags(cmd *cobra.Command, fn func(f *pflag.Flag)) {
+	cmd.PersistentFlags().VisitAll(fn)
+	cmd.Flags().VisitAll(fn)
+	for _, sub := range cmd.Commands() {
+		visitFlags(sub, fn)
+	}
+}
+
+func snapshotFlags(root *cobra.Command) flagSnapshot {
+	snapshot := flagSnapshot{}
+	visitFlags(root, func(f *pflag.Flag) {
+		v := flagValue{
+			value:   f.Value.String(),
+			changed: f.Changed,
+		}
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			v.slice = append([]string{}, sv.GetSlice()...)
+		}
+
+		snapshot[f] = v
+	})
+
+	return snapshot
+}
+
+// restore sets the flags of the command tree back to their recorded values.
+// Flags that were not recorded, such as the help flags cobra adds as commands
+// get run, are set back to their default value.
+func (snapshot flagSnapshot) restore(root *cobra.Command) error {
+	var errs []error
+	visitFlags(root, func(f *pflag.Flag) {
+		v, ok := snapshot[f]
+		if !ok {
+			v = flagValue{value: f.DefValue}
+			if _, isSlice := f.Value.(pflag.SliceValue); isSlice {
+				v.slice = parseSliceDefault(f.DefValue)
+			}
+		}
+
+		if f.Value.String() != v.value {
+			var err error
+			if sv, ok := f.Value.(pflag.SliceValue); ok {
+				err = sv.Replace(v.slice)
+			} else {
+				err = f.Value.Set(v.value)
+			}
+
+			if err != nil {
+				errs = append(errs, fmt.Errorf("cannot reset --%s: %w", f.Name, err))
+			}
+		}
+
+		f.Changed = v.changed
+	})
+
+	return errors.Join(errs...)
+}
+
+// parseSliceDefault parses the default value of a pflag slice flag, which is
+// formatted as "[a,b,c]".
+func parseSliceDefault(def string) []string {
+	def = strings.TrimSuffix(strings.TrimPrefix(def, "["), "]")
+	if def == "" {
+		return []string{}
+	}
+
+	return strings.Split(def, ",")
+}
+
+// shellCandidate is a possible completion of a word, with an optional
+// description.
+type shellCandidate struct {
+	Value string
+	Help  string
+}
+
+func formatCandidates(candidates []shellCandidate) string {
+	width := 0
+	for _, c := range candidates {
+		if c.Help != "" && len(c.Value) > width {
+			width = len(c.Value)
+		}
+	}
+
+	var lines []string
+	var values []string
+	for _, c := range candidates {
+		if c.Help == "" {
+			values = append(values, c.Value)
+			continue
+		}
+
+		lines = append(lines, fmt.Sprintf("%-*s  %s", width, c.Value, c.Help))
+	}
+
+	if len(values) > 0 {
+		lines = append(lines, strings.Join(values, "  "))
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// shellCompleter completes the words of shell lines, using the command tree
+// and the names of the topology objects it fetches from the vtctld.
+type shellCompleter struct {
+	root     *cobra.Command
+	shellCmd *cobra.Command
+	client   vtctldclient.VtctldClient
+
+	m         sync.Mutex
+	fetchedAt time.Time
+	// names holds the keyspaces, cells and tablet aliases.
+	names []string
+	// shards holds the keyspace/shard names, by keyspace.
+	shards map[string][]string
+	now    func() time.Time
+}
+
+func newShellCompleter(root *cobra.Command, shellCmd *cobra.Command, client vtctldclient.VtctldClient) *shellCompleter {
+	return &shellCompleter{
+		root:     root,
+		shellCmd: shellCmd,
+		client:   client,
+		now:      time.Now,
+	}
+}
+
+// Complete completes the word before pos in line. It returns the new line and
+// cursor position, and the candidates to show when the word cannot be
+// completed any further.
+func (sc *shellCompleter) Complete(ctx context.Context, line string, pos int) (newLine string, newPos int, matches []shellCandidate) {
+	start := strings.LastIndexAny(line[:pos], " \t") + 1
+	word := line[start:pos]
+
+	words, err := shlex.Split(line[:start])
+	if err != nil {
+		return line, pos, nil
+	}
+
+	for _, c := range sc.candidates(ctx, words, word) {
+		if strings.HasPrefix(c.Value, word) {
+			matches = append(matches, c)
+		}
+	}
+
+	var completion string
+	switch len(matches) {
+	case 0:
+		return line, pos, nil
+	case 1:
+		completion = matches[0].Value
+		if pos == len(line) || line[pos] != ' ' {
+			completion += " "
+		}
+	default:
+		completion = commonPrefix(matches)
+	}
+
+	newLine = line[:start] + completion + line[pos:]
+	newPos = start + len(completion)
+
+	if len(matches) == 1 || completion != word {
+		return newLine, newPos, nil
+	}
+
+	return newLine, newPos, matches
+}
+
+func commonPrefix(candidates []shellCandidate) string {
+	prefix := candidates[0].Value
+	for _, c := range candidates[1:] {
+		for !strings.HasPrefix(c.Value, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+
+	return prefix
+}
+
+// candidates returns the possible values of a word that follows the given
+// ones.
+func (sc *shellCompleter) candidates(ctx context.Context, words []string, word string) []shellCandidate {
+	isHelp := len(words) > 0 && words[0] == "help"
+	if isHelp {
+		words = words[1:]
+	}
+
+	cmd, rest, err := sc.root.Find(words)
+	if err != nil {
+		cmd, rest = nil, nil
+	}
+
+	switch {
+	case cmd != nil && strings.HasPrefix(word, "-"):
+		return flagCandidates(cmd)
+	case cmd != nil && len(rest) == 0 && cmd.HasAvailableSubCommands():
+		var candidates []shellCandidate
+		for _, sub := range cmd.Commands() {
+			if sub.IsAvailableCommand() && sub != sc.shellCmd {
+				candidates = append(candidates, shellCandidate{Value: sub.Name(), Help: sub.Short})
+			}
+		}
+
+		if cmd == sc.root && !isHelp {
+			candidates = append(candidates, shellBuiltins...)
+			sort.Slice(candidates, func(i, j int) bool { return candidates[i].Value < candidates[j].Value })
+		}
+
+		return candidates
+	case isHelp:
+		return nil
+	case strings.Contains(word, "/"):
+		keyspace, _, _ := strings.Cut(word, "/")
+		return valueCandidates(sc.shardNames(ctx, keyspace))
+	default:
+		return valueCandidates(sc.topoNames(ctx))
+	}
+}
+
+func flagCandidates(cmd *cobra.Command) []shellCandidate {
+	var candidates []shellCandidate
+	add := func(f *pflag.Flag) {
+		if !f.Hidden && f.Deprecated == "" {
+			candidates = append(candidates, shellCandidate{Value: "--" + f.Name})
+		}
+	}
+
+	cmd.LocalFlags().VisitAll(add)
+	cmd.InheritedFlags().VisitAll(add)
+
+	return candidates
+}
+
+func valueCandidates(values []string) []shellCandidate {
+	candidates := make([]shellCandidate, 0, len(values))
+	for _, v := range values {
+		candidates = append(candidates, shellCandidate{Value: v})
+	}
+
+	return candidates
+}
+
+// expireCache drops the fetched names if they are too old. sc.m must be held.
+func (sc *shellCompleter) expireCache() {
+	if sc.now().Sub(sc.fetchedAt) < completionCacheTTL {
+		return
+	}
+
+	sc.fetchedAt = sc.now()
+	sc.names = nil
+	sc.shards = map[string][]string{}
+}
+
+// topoNames returns the names of the keyspaces, cells and tablets. Errors
+// fetching them are ignored, as they only make for fewer completions.
+func (sc *shellCompleter) topoNames(ctx context.Context) []string {
+	sc.m.Lock()
+	defer sc.m.Unlock()
+
+	sc.expireCache()
+	if sc.names != nil || sc.client == nil {
+		return sc.names
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, completionTimeout)
+	defer cancel()
+
+	names := []string{}
+	if resp, err := sc.client.GetKeyspaces(ctx, &vtctldatapb.GetKeyspacesRequest{}); err == nil {
+		for _, ks := range resp.Keyspaces {
+			names = append(names, ks.Name)
+		}
+	}
+
+	if resp, err := sc.client.GetCellInfoNames(ctx, &vtctldatapb.GetCellInfoNamesRequest{}); err == nil {
+		names = append(names, resp.Names...)
+	}
+
+	if resp, err := sc.client.GetTablets(ctx, &vtctldatapb.GetTabletsRequest{}); err == nil {
+		for _, tablet := range resp.Tablets {
+			names = append(names, topoproto.TabletAliasString(tablet.Alias))
+		}
+	}
+
+	sort.Strings(names)
+	sc.names = names
+
+	return sc.names
+}
+
+// shardNames returns the keyspace/shard names of the shards of a keyspace.
+func (sc *shellCompleter) shardNames(ctx context.Context, keyspace string) []string {
+	sc.m.Lock()
+	defer sc.m.Unlock()
+
+	sc.expireCache()
+	if shards, ok := sc.shards[keyspace]; ok || sc.client == nil {
+		return shards
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, completionTimeout)
+	defer cancel()
+
+	shards := []string{}
+	if resp, err := sc.client.FindAllShardsInKeyspace(ctx, &vtctldatapb.FindAllShardsInKeyspaceRequest{Keyspace: keyspace}); err == nil {
+		for _, shard := range resp.Shards {
+			shards = append(shards, topoproto.KeyspaceShardString(keyspace, shard.Name))
+		}
+	}
+
+	sort.Strings(shards)
+	sc.shards[keyspace] = shards
+
+	return shards
+}
+
+func init() {
+	Root.AddCommand(Shell)
+}
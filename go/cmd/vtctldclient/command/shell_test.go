@@ -0,0 +1,234 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"vitess.io/vitess/go/vt/vtctl/vtctldclient"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+type shellTestClient struct {
+	vtctldclient.VtctldClient
+	calls int
+}
+
+func (c *shellTestClient) GetKeyspaces(ctx context.Context, req *vtctldatapb.GetKeyspacesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetKeyspacesResponse, error) {
+	c.calls++
+	return &vtctldatapb.GetKeyspacesResponse{
+		Keyspaces: []*vtctldatapb.Keyspace{{Name: "commerce"}, {Name: "customer"}},
+	}, nil
+}
+
+func (c *shellTestClient) GetCellInfoNames(ctx context.Context, req *vtctldatapb.GetCellInfoNamesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetCellInfoNamesResponse, error) {
+	return &vtctldatapb.GetCellInfoNamesResponse{Names: []string{"zone1"}}, nil
+}
+
+func (c *shellTestClient) GetTablets(ctx context.Context, req *vtctldatapb.GetTabletsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetTabletsResponse, error) {
+	return &vtctldatapb.GetTabletsResponse{
+		Tablets: []*topodatapb.Tablet{
+			{Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}},
+			{Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 101}},
+		},
+	}, nil
+}
+
+func (c *shellTestClient) FindAllShardsInKeyspace(ctx context.Context, req *vtctldatapb.FindAllShardsInKeyspaceRequest, opts ...grpc.CallOption) (*vtctldatapb.FindAllShardsInKeyspaceResponse, error) {
+	return &vtctldatapb.FindAllShardsInKeyspaceResponse{
+		Shards: map[string]*vtctldatapb.Shard{
+			"-80": {Keyspace: req.Keyspace, Name: "-80"},
+			"80-": {Keyspace: req.Keyspace, Name: "80-"},
+		},
+	}, nil
+}
+
+func newShellTestTree() (root, shellCmd *cobra.Command) {
+	root = &cobra.Command{Use: "vtctldclient"}
+	root.PersistentFlags().String("server", "", "")
+
+	getTablet := &cobra.Command{Use: "GetTablet", Short: "Outputs a tablet.", Run: func(cmd *cobra.Command, args []string) {}}
+	getTablets := &cobra.Command{Use: "GetTablets", Short: "Looks up tablets.", Run: func(cmd *cobra.Command, args []string) {}}
+	getTablets.Flags().String("keyspace", "", "")
+	getTablets.Flags().StringSlice("cell", nil, "")
+	getTablets.Flags().Bool("strict", false, "")
+
+	watch := &cobra.Command{Use: "Watch"}
+	watch.AddCommand(&cobra.Command{Use: "Keyspace", Short: "Streams a keyspace.", Run: func(cmd *cobra.Command, args []string) {}})
+	watch.AddCommand(&cobra.Command{Use: "Shard", Short: "Streams a shard.", Run: func(cmd *cobra.Command, args []string) {}})
+
+	shellCmd = &cobra.Command{Use: "Shell", Run: func(cmd *cobra.Command, args []string) {}}
+
+	root.AddCommand(getTablet, getTablets, watch, shellCmd)
+	return root, shellCmd
+}
+
+func TestShellComplete(t *testing.T) {
+	root, shellCmd := newShellTestTree()
+
+	tests := []struct {
+		name            string
+		line            string
+		pos             int
+		expectedLine    string
+		expectedMatches []string
+	}{
+		{
+			name:         "unique command",
+			line:         "GetTablets",
+			expectedLine: "GetTablets ",
+		},
+		{
+			name:            "ambiguous command",
+			line:            "GetTablet",
+			expectedLine:    "GetTablet",
+			expectedMatches: []string{"GetTablet", "GetTablets"},
+		},
+		{
+			name:         "common prefix",
+			line:         "Ge",
+			expectedLine: "GetTablet",
+		},
+		{
+			name:         "builtin",
+			line:         "hist",
+			expectedLine: "history ",
+		},
+		{
+			name:         "shell is not offered",
+			line:         "Sh",
+			expectedLine: "Sh",
+		},
+		{
+			name:            "subcommand",
+			line:            "Watch ",
+			expectedLine:    "Watch ",
+			expectedMatches: []string{"Keyspace", "Shard"},
+		},
+		{
+			name:         "help",
+			line:         "help Wa",
+			expectedLine: "help Watch ",
+		},
+		{
+			name:            "flag",
+			line:            "GetTablets --s",
+			expectedLine:    "GetTablets --s",
+			expectedMatches: []string{"--strict", "--server"},
+		},
+		{
+			name:         "keyspace",
+			line:         "GetTablets --keyspace comm",
+			expectedLine: "GetTablets --keyspace commerce ",
+		},
+		{
+			name:         "tablet alias",
+			line:         "GetTablet zone1-",
+			expectedLine: "GetTablet zone1-000000010",
+		},
+		{
+			name:            "shard",
+			line:            "Watch Shard customer/",
+			expectedLine:    "Watch Shard customer/",
+			expectedMatches: []string{"customer/-80", "customer/80-"},
+		},
+		{
+			name:         "middle of the line",
+			line:         "GetTablets --keyspace cus --strict",
+			pos:          len("GetTablets --keyspace cus"),
+			expectedLine: "GetTablets --keyspace customer --strict",
+		},
+		{
+			name:         "no match",
+			line:         "GetTablets --keyspace unknown",
+			expectedLine: "GetTablets --keyspace unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc := newShellCompleter(root, shellCmd, &shellTestClient{})
+
+			pos := tt.pos
+			if pos == 0 {
+				pos = len(tt.line)
+			}
+
+			line, _, matches := sc.Complete(context.Background(), tt.line, pos)
+			assert.Equal(t, tt.expectedLine, line)
+
+			var values []string
+			for _, m := range matches {
+				values = append(values, m.Value)
+			}
+			assert.Equal(t, tt.expectedMatches, values)
+		})
+	}
+}
+
+func TestShellCompleterCache(t *testing.T) {
+	root, shellCmd := newShellTestTree()
+	client := &shellTestClient{}
+	sc := newShellCompleter(root, shellCmd, client)
+
+	now := time.Now()
+	sc.now = func() time.Time { return now }
+
+	sc.Complete(context.Background(), "GetTablet z", len("GetTablet z"))
+	sc.Complete(context.Background(), "GetTablet c", len("GetTablet c"))
+	assert.Equal(t, 1, client.calls)
+
+	now = now.Add(completionCacheTTL)
+	sc.Complete(context.Background(), "GetTablet z", len("GetTablet z"))
+	assert.Equal(t, 2, client.calls)
+}
+
+func TestFlagSnapshot(t *testing.T) {
+	root, _ := newShellTestTree()
+	root.SetOut(io.Discard)
+	require.NoError(t, root.PersistentFlags().Set("server", "localhost:15999"))
+
+	snapshot := snapshotFlags(root)
+
+	root.SetArgs([]string{"GetTablets", "--server", "other:15999", "--keyspace", "commerce", "--cell", "zone1,zone2", "--strict", "--help"})
+	require.NoError(t, root.Execute())
+	require.NoError(t, snapshot.restore(root))
+
+	server := root.PersistentFlags().Lookup("server")
+	assert.Equal(t, "localhost:15999", server.Value.String())
+	assert.True(t, server.Changed)
+
+	getTablets, _, err := root.Find([]string{"GetTablets"})
+	require.NoError(t, err)
+
+	for _, name := range []string{"keyspace", "cell", "strict", "help"} {
+		f := getTablets.Flags().Lookup(name)
+		require.NotNil(t, f, name)
+		assert.Equal(t, f.DefValue, f.Value.String(), name)
+		assert.False(t, f.Changed, name)
+	}
+}
@@ -18,6 +18,8 @@ package command
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -26,6 +28,7 @@ import (
 
 	"vitess.io/vitess/go/cmd/vtctldclient/cli"
 	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
@@ -34,6 +37,20 @@ import (
 )
 
 var (
+	// AddTabletTag makes an AddTabletTag gRPC call to a vtctld.
+	AddTabletTag = &cobra.Command{
+		Use:   "AddTabletTag <alias> <key>=<value> [<key>=<value> ...]",
+		Short: "Sets tags on the specified tablet.",
+		Long: `Sets tags on the specified tablet.
+
+Tags with the same keys as existing tags of the tablet replace them, and the
+tablet's other tags are left as they are. The tags persist across restarts of
+the tablet, but the tablet's --init_tags are applied again when it starts.`,
+		Example:               "AddTabletTag zone1-0000000100 hardware=nvme",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.MinimumNArgs(2),
+		RunE:                  commandAddTabletTag,
+	}
 	// ChangeTabletType makes a ChangeTabletType gRPC call to a vtctld.
 	ChangeTabletType = &cobra.Command{
 		Use:   "ChangeTabletType [--dry-run] <alias> <tablet-type>",
@@ -46,9 +63,24 @@ NOTE: This command automatically updates the serving graph.`,
 		Args:                  cobra.ExactArgs(2),
 		RunE:                  commandChangeTabletType,
 	}
+	// ChangeTabletTypeByFilter makes a ChangeTabletTypeByFilter gRPC call to a vtctld.
+	ChangeTabletTypeByFilter = &cobra.Command{
+		Use:   "ChangeTabletTypeByFilter --keyspace <keyspace> [--shard <shard>] [--cell <cell1> ...] --from <tablet-type> --to <tablet-type> [--dry-run] [--concurrency <concurrency>]",
+		Short: "Changes the db type of all the tablets matching the given filter, if possible.",
+		Long: `Changes the db type of all the tablets matching the given filter, if possible.
+
+The tablets of the keyspace, optionally limited to a shard and to cells, that are
+currently of the --from type are changed to the --to type, as with ChangeTabletType.
+The outcome for each tablet is printed as JSON, and the command fails if any tablet
+could not be changed.`,
+		Example:               "ChangeTabletTypeByFilter --keyspace commerce --shard -80 --cell zone1 --from rdonly --to replica",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		RunE:                  commandChangeTabletTypeByFilter,
+	}
 	// DeleteTablets makes a DeleteTablets gRPC call to a vtctld.
 	DeleteTablets = &cobra.Command{
-		Use:                   "DeleteTablets <alias> [ <alias> ... ]",
+		Use:                   "DeleteTablets [--allow-primary|-p] [--dry-run] <alias> [ <alias> ... ]",
 		Short:                 "Deletes tablet(s) from the topology.",
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.MinimumNArgs(1),
@@ -73,6 +105,25 @@ Note: hook names may not contain slash (/) characters.
 		Args:                  cobra.MinimumNArgs(2),
 		RunE:                  commandExecuteHook,
 	}
+	// FixErrantGTID makes a FixErrantGTID gRPC call to a vtctld.
+	FixErrantGTID = &cobra.Command{
+		Use:   "FixErrantGTID [--mode {reconcile|reset}] [--tablet-type <tablet-type>] [--dry-run] <alias>",
+		Short: "Fixes the errant GTIDs of a replica, and brings it back from the quarantine VTOrc put it in.",
+		Long: `Fixes the errant GTIDs of a replica, and brings it back from the quarantine VTOrc put it in.
+
+The errant GTIDs are the transactions the replica executed that the primary of its
+shard did not. With --mode reconcile, they are kept on the replica and marked as
+purged on the other tablets of the shard. With --mode reset, they are dropped from
+the GTID set of the replica, by resetting its binary logs; the replica must not have
+any data changes from these transactions that the rest of the shard lacks.
+
+The replica is then changed to --tablet-type, if set, and its quarantine tag is
+removed. The errant GTID set is printed as JSON.`,
+		Example:               "FixErrantGTID --mode reset --tablet-type replica zone1-0000000100",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandFixErrantGTID,
+	}
 	// GetFullStatus makes a FullStatus gRPC call to a vttablet.
 	GetFullStatus = &cobra.Command{
 		Use:                   "GetFullStatus <alias>",
@@ -99,7 +150,7 @@ Note: hook names may not contain slash (/) characters.
 	}
 	// GetTablets makes a GetTablets gRPC call to a vtctld.
 	GetTablets = &cobra.Command{
-		Use:   "GetTablets [--strict] [{--cell $c1 [--cell $c2 ...] [--tablet-type $t1] [--keyspace $ks [--shard $shard]], --tablet-alias $alias}]",
+		Use:   "GetTablets [--strict] [{--cell $c1 [--cell $c2 ...] [--tablet-type $t1] [--keyspace $ks [--shard $shard]], --tablet-alias $alias}] [--tag $k=$v ...] [{--page-size $n [--page-token $token], --stream}]",
 		Short: "Looks up tablets according to filter criteria.",
 		Long: fmt.Sprintf(`Looks up tablets according to the filter criteria.
 
@@ -118,6 +169,13 @@ Passing --cell limits the set of tablets to those in the specified cells. The
 --cell flag accepts a CSV argument (e.g. --cell "c1,c2") and may be repeated
 (e.g. --cell "c1" --cell "c2").
 
+Passing --tag limits the set of tablets to those that have all the given tags.
+
+For large clusters, --page-size limits the number of tablets returned, ordered
+by tablet alias. If more tablets are left, a page token is printed to stderr,
+to be passed with --page-token to get the next page. Alternatively, --stream
+fetches all the tablets over several messages.
+
 Valid output formats are "awk" and "json".`, strings.Join(topoproto.MakeUniqueStringTypeList(topoproto.AllTabletTypes), "\", \"")),
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.NoArgs,
@@ -148,6 +206,19 @@ Valid output formats are "awk" and "json".`, strings.Join(topoproto.MakeUniqueSt
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandRefreshState,
 	}
+	// RefreshStateByFilter makes a RefreshStateByFilter gRPC call to a vtctld.
+	RefreshStateByFilter = &cobra.Command{
+		Use:   "RefreshStateByFilter --keyspace <keyspace> [--shard <shard>] [--cell <cell1> ...] [--tablet-type <tablet-type>] [--concurrency <concurrency>]",
+		Short: "Reloads the tablet record on all the tablets matching the given filter.",
+		Long: `Reloads the tablet record on all the tablets matching the given filter.
+
+The outcome for each tablet is printed as JSON, and the command fails if any tablet
+could not be refreshed.`,
+		Example:               "RefreshStateByFilter --keyspace commerce --cell zone1 --tablet-type rdonly",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		RunE:                  commandRefreshStateByFilter,
+	}
 	// RefreshStateByShard makes a RefreshStateByShard gRPC call to a vtcld.
 	RefreshStateByShard = &cobra.Command{
 		Use:                   "RefreshStateByShard [--cell <cell1> ...] <keyspace/shard>",
@@ -156,6 +227,15 @@ Valid output formats are "awk" and "json".`, strings.Join(topoproto.MakeUniqueSt
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandRefreshStateByShard,
 	}
+	// RemoveTabletTag makes a RemoveTabletTag gRPC call to a vtctld.
+	RemoveTabletTag = &cobra.Command{
+		Use:                   "RemoveTabletTag <alias> <key> [<key> ...]",
+		Short:                 "Removes tags from the specified tablet.",
+		Example:               "RemoveTabletTag zone1-0000000100 hardware",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.MinimumNArgs(2),
+		RunE:                  commandRemoveTabletTag,
+	}
 	// RunHealthCheck makes a RunHealthCheck gRPC call to a vtctld.
 	RunHealthCheck = &cobra.Command{
 		Use:                   "RunHealthCheck <tablet_alias>",
@@ -210,8 +290,61 @@ Note that, in the SleepTablet implementation, the value should be positively-sig
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandStopReplication,
 	}
+	// UpgradeMysql makes an UpgradeMysql gRPC call to a vtctld.
+	UpgradeMysql = &cobra.Command{
+		Use:   "UpgradeMysql [--mysql-root <path>] [--expected-version <version>] [--allow-primary] <alias>",
+		Short: "Upgrades mysqld in place on the specified tablet, reporting each step.",
+		Long: `Upgrades mysqld in place on the specified tablet.
+
+The tablet is drained and replication is stopped, mysqld is shut down cleanly and
+started from the MySQL distribution in --mysql-root, or from the binaries already
+upgraded in place if it is omitted. mysql_upgrade is then run, mysqld is restarted,
+and the tablet rejoins its shard with its original type and replication settings.
+
+Downgrades are refused. --mysql-root must be listed in the --upgrade-mysql-allowed-roots
+flag of the tablet. If the tablet was started by mysqlctld, --mysql-root cannot
+be used: restart mysqlctld with the new VT_MYSQL_ROOT instead.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandUpgradeMysql,
+	}
 )
 
+func commandAddTabletTag(cmd *cobra.Command, args []string) error {
+	alias, err := parseTabletAlias(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	tags := make(map[string]string, len(args)-1)
+	for _, arg := range cmd.Flags().Args()[1:] {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("invalid tag %q, expected <key>=<value>", arg)
+		}
+
+		tags[key] = value
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.AddTabletTag(commandCtx, &vtctldatapb.AddTabletTagRequest{
+		TabletAlias: alias,
+		Tags:        tags,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp.Tablet.Tags)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+	return nil
+}
+
 var changeTabletTypeOptions = struct {
 	DryRun bool
 }{}
@@ -220,7 +353,7 @@ func commandChangeTabletType(cmd *cobra.Command, args []string) error {
 	aliasStr := cmd.Flags().Arg(0)
 	typeStr := cmd.Flags().Arg(1)
 
-	alias, err := topoproto.ParseTabletAlias(aliasStr)
+	alias, err := parseTabletAlias(aliasStr)
 	if err != nil {
 		return err
 	}
@@ -242,43 +375,98 @@ func commandChangeTabletType(cmd *cobra.Command, args []string) error {
 	}
 
 	if resp.WasDryRun {
-		fmt.Println("--- DRY RUN ---")
+		fmt.Fprintln(cmd.OutOrStdout(), "--- DRY RUN ---")
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "- %v\n", cli.MarshalTabletAWK(resp.BeforeTablet))
+	fmt.Fprintf(cmd.OutOrStdout(), "+ %v\n", cli.MarshalTabletAWK(resp.AfterTablet))
+
+	return nil
+}
+
+var changeTabletTypeByFilterOptions = struct {
+	Keyspace    string
+	Shard       string
+	Cells       []string
+	FromType    topodatapb.TabletType
+	ToType      topodatapb.TabletType
+	DryRun      bool
+	Concurrency uint32
+}{}
+
+func commandChangeTabletTypeByFilter(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+	reportKeyspaceOrShard(changeTabletTypeByFilterOptions.Keyspace, changeTabletTypeByFilterOptions.Shard)
+
+	resp, err := client.ChangeTabletTypeByFilter(commandCtx, &vtctldatapb.ChangeTabletTypeByFilterRequest{
+		Keyspace:    changeTabletTypeByFilterOptions.Keyspace,
+		Shard:       changeTabletTypeByFilterOptions.Shard,
+		Cells:       changeTabletTypeByFilterOptions.Cells,
+		FromType:    changeTabletTypeByFilterOptions.FromType,
+		ToType:      changeTabletTypeByFilterOptions.ToType,
+		DryRun:      changeTabletTypeByFilterOptions.DryRun,
+		Concurrency: changeTabletTypeByFilterOptions.Concurrency,
+	})
+	if err != nil {
+		return err
 	}
 
-	fmt.Printf("- %v\n", cli.MarshalTabletAWK(resp.BeforeTablet))
-	fmt.Printf("+ %v\n", cli.MarshalTabletAWK(resp.AfterTablet))
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+
+	var failed int
+	for _, result := range resp.Results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to change the type of %d out of %d tablets", failed, len(resp.Results))
+	}
 
 	return nil
 }
 
 var deleteTabletsOptions = struct {
 	AllowPrimary bool
+	DryRun       bool
 }{}
 
 func commandDeleteTablets(cmd *cobra.Command, args []string) error {
-	aliases, err := cli.TabletAliasesFromPosArgs(cmd.Flags().Args())
+	aliases, err := tabletAliasesFromPosArgs(cmd.Flags().Args())
 	if err != nil {
 		return err
 	}
 
 	cli.FinishedParsing(cmd)
 
-	_, err = client.DeleteTablets(commandCtx, &vtctldatapb.DeleteTabletsRequest{
+	resp, err := client.DeleteTablets(commandCtx, &vtctldatapb.DeleteTabletsRequest{
 		TabletAliases: aliases,
 		AllowPrimary:  deleteTabletsOptions.AllowPrimary,
+		DryRun:        deleteTabletsOptions.DryRun,
 	})
 
 	if err != nil {
 		return fmt.Errorf("%w: while deleting %d tablets; please inspect the topo", err, len(aliases))
 	}
 
-	fmt.Printf("Successfully deleted %d tablets\n", len(aliases))
+	if deleteTabletsOptions.DryRun {
+		fmt.Fprintf(cmd.OutOrStdout(), "Dry run results for deleting %d tablets:\n%s\n", len(aliases), strings.Join(resp.DryRunResults, "\n"))
+		return nil
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Successfully deleted %d tablets\n", len(aliases))
 
 	return nil
 }
 
 func commandExecuteHook(cmd *cobra.Command, args []string) error {
-	tabletAlias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
+	tabletAlias, err := parseTabletAlias(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -307,13 +495,55 @@ func commandExecuteHook(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+	return nil
+}
+
+var fixErrantGTIDOptions = struct {
+	Mode       string
+	TabletType topodatapb.TabletType
+	DryRun     bool
+}{}
+
+func commandFixErrantGTID(cmd *cobra.Command, args []string) error {
+	alias, err := parseTabletAlias(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	mode, ok := vtctldatapb.FixErrantGTIDRequest_Mode_value[strings.ToUpper(fixErrantGTIDOptions.Mode)]
+	if !ok {
+		return fmt.Errorf("invalid --mode %s, must be one of reconcile or reset", fixErrantGTIDOptions.Mode)
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.FixErrantGTID(commandCtx, &vtctldatapb.FixErrantGTIDRequest{
+		TabletAlias: alias,
+		Mode:        vtctldatapb.FixErrantGTIDRequest_Mode(mode),
+		TabletType:  fixErrantGTIDOptions.TabletType,
+		DryRun:      fixErrantGTIDOptions.DryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	if fixErrantGTIDOptions.DryRun {
+		fmt.Fprintln(cmd.OutOrStdout(), "--- DRY RUN ---")
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 	return nil
 }
 
 func commandGetFullStatus(cmd *cobra.Command, args []string) error {
 	aliasStr := cmd.Flags().Arg(0)
-	alias, err := topoproto.ParseTabletAlias(aliasStr)
+	alias, err := parseTabletAlias(aliasStr)
 	if err != nil {
 		return err
 	}
@@ -330,13 +560,13 @@ func commandGetFullStatus(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 
 	return nil
 }
 
 func commandGetPermissions(cmd *cobra.Command, args []string) error {
-	alias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
+	alias, err := parseTabletAlias(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -353,14 +583,14 @@ func commandGetPermissions(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	fmt.Printf("%s\n", p)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", p)
 
 	return nil
 }
 
 func commandGetTablet(cmd *cobra.Command, args []string) error {
 	aliasStr := cmd.Flags().Arg(0)
-	alias, err := topoproto.ParseTabletAlias(aliasStr)
+	alias, err := parseTabletAlias(aliasStr)
 	if err != nil {
 		return err
 	}
@@ -377,7 +607,7 @@ func commandGetTablet(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 
 	return nil
 }
@@ -390,6 +620,11 @@ var getTabletsOptions = struct {
 
 	TabletAliasStrings []string
 
+	Tags      map[string]string
+	PageSize  uint32
+	PageToken string
+	Stream    bool
+
 	Format string
 	Strict bool
 }{}
@@ -418,7 +653,7 @@ func commandGetTablets(cmd *cobra.Command, args []string) error {
 		}
 
 		var err error
-		aliases, err = cli.TabletAliasesFromPosArgs(getTabletsOptions.TabletAliasStrings)
+		aliases, err = tabletAliasesFromPosArgs(getTabletsOptions.TabletAliasStrings)
 		if err != nil {
 			return err
 		}
@@ -428,39 +663,91 @@ func commandGetTablets(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("--shard (= %s) cannot be passed without also passing --keyspace", getTabletsOptions.Shard)
 	}
 
+	if getTabletsOptions.Stream && getTabletsOptions.PageToken != "" {
+		return fmt.Errorf("--page-token (= %s) cannot be passed when using --stream", getTabletsOptions.PageToken)
+	}
+
 	cli.FinishedParsing(cmd)
+	reportKeyspaceOrShard(getTabletsOptions.Keyspace, getTabletsOptions.Shard)
 
-	resp, err := client.GetTablets(commandCtx, &vtctldatapb.GetTabletsRequest{
+	req := &vtctldatapb.GetTabletsRequest{
 		TabletAliases: aliases,
 		Cells:         getTabletsOptions.Cells,
 		TabletType:    getTabletsOptions.TabletType,
 		Keyspace:      getTabletsOptions.Keyspace,
 		Shard:         getTabletsOptions.Shard,
 		Strict:        getTabletsOptions.Strict,
-	})
-	if err != nil {
-		return err
+		Tags:          getTabletsOptions.Tags,
+		PageSize:      getTabletsOptions.PageSize,
+		PageToken:     getTabletsOptions.PageToken,
+	}
+
+	var (
+		tablets       []*topodatapb.Tablet
+		nextPageToken string
+	)
+
+	if getTabletsOptions.Stream {
+		stream, err := client.GetTabletsStream(commandCtx, req)
+		if err != nil {
+			return err
+		}
+
+	recv:
+		for {
+			resp, err := stream.Recv()
+			switch err {
+			case nil:
+			case io.EOF:
+				break recv
+			default:
+				return err
+			}
+
+			// Print tablets as they come when we do not need to build a
+			// single JSON document out of them.
+			if format == "awk" {
+				for _, t := range resp.Tablets {
+					fmt.Fprintln(cmd.OutOrStdout(), cli.MarshalTabletAWK(t))
+				}
+				continue
+			}
+
+			tablets = append(tablets, resp.Tablets...)
+		}
+	} else {
+		resp, err := client.GetTablets(commandCtx, req)
+		if err != nil {
+			return err
+		}
+
+		tablets = resp.Tablets
+		nextPageToken = resp.NextPageToken
 	}
 
 	switch format {
 	case "awk":
-		for _, t := range resp.Tablets {
-			fmt.Println(cli.MarshalTabletAWK(t))
+		for _, t := range tablets {
+			fmt.Fprintln(cmd.OutOrStdout(), cli.MarshalTabletAWK(t))
 		}
 	case "json":
-		data, err := cli.MarshalJSON(resp.Tablets)
+		data, err := cli.MarshalJSON(tablets)
 		if err != nil {
 			return err
 		}
 
-		fmt.Printf("%s\n", data)
+		fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+	}
+
+	if nextPageToken != "" {
+		fmt.Fprintf(os.Stderr, "next page token: %s\n", nextPageToken)
 	}
 
 	return nil
 }
 
 func commandGetTabletVersion(cmd *cobra.Command, args []string) error {
-	alias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
+	alias, err := parseTabletAlias(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -474,12 +761,12 @@ func commandGetTabletVersion(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Println(resp.Version)
+	fmt.Fprintln(cmd.OutOrStdout(), resp.Version)
 	return nil
 }
 
 func commandPingTablet(cmd *cobra.Command, args []string) error {
-	alias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
+	alias, err := parseTabletAlias(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -493,7 +780,7 @@ func commandPingTablet(cmd *cobra.Command, args []string) error {
 }
 
 func commandRefreshState(cmd *cobra.Command, args []string) error {
-	alias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
+	alias, err := parseTabletAlias(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -507,7 +794,51 @@ func commandRefreshState(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("Refreshed state on %s\n", topoproto.TabletAliasString(alias))
+	fmt.Fprintf(cmd.OutOrStdout(), "Refreshed state on %s\n", topoproto.TabletAliasString(alias))
+	return nil
+}
+
+var refreshStateByFilterOptions = struct {
+	Keyspace    string
+	Shard       string
+	Cells       []string
+	TabletType  topodatapb.TabletType
+	Concurrency uint32
+}{}
+
+func commandRefreshStateByFilter(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+	reportKeyspaceOrShard(refreshStateByFilterOptions.Keyspace, refreshStateByFilterOptions.Shard)
+
+	resp, err := client.RefreshStateByFilter(commandCtx, &vtctldatapb.RefreshStateByFilterRequest{
+		Keyspace:    refreshStateByFilterOptions.Keyspace,
+		Shard:       refreshStateByFilterOptions.Shard,
+		Cells:       refreshStateByFilterOptions.Cells,
+		TabletType:  refreshStateByFilterOptions.TabletType,
+		Concurrency: refreshStateByFilterOptions.Concurrency,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+
+	var failed int
+	for _, result := range resp.Results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to refresh the state of %d out of %d tablets", failed, len(resp.Results))
+	}
+
 	return nil
 }
 
@@ -516,7 +847,7 @@ var refreshStateByShardOptions = struct {
 }{}
 
 func commandRefreshStateByShard(cmd *cobra.Command, args []string) error {
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -542,12 +873,37 @@ func commandRefreshStateByShard(cmd *cobra.Command, args []string) error {
 		msg.WriteString("State refresh was partial; some tablets in the shard may not have succeeded.\n")
 	}
 
-	fmt.Print(msg.String())
+	fmt.Fprint(cmd.OutOrStdout(), msg.String())
+	return nil
+}
+
+func commandRemoveTabletTag(cmd *cobra.Command, args []string) error {
+	alias, err := parseTabletAlias(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.RemoveTabletTag(commandCtx, &vtctldatapb.RemoveTabletTagRequest{
+		TabletAlias: alias,
+		Keys:        cmd.Flags().Args()[1:],
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp.Tablet.Tags)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 	return nil
 }
 
 func commandRunHealthCheck(cmd *cobra.Command, args []string) error {
-	alias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
+	alias, err := parseTabletAlias(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -561,7 +917,7 @@ func commandRunHealthCheck(cmd *cobra.Command, args []string) error {
 }
 
 func commandSetWritable(cmd *cobra.Command, args []string) error {
-	alias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
+	alias, err := parseTabletAlias(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -581,7 +937,7 @@ func commandSetWritable(cmd *cobra.Command, args []string) error {
 }
 
 func commandSleepTablet(cmd *cobra.Command, args []string) error {
-	alias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
+	alias, err := parseTabletAlias(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -601,7 +957,7 @@ func commandSleepTablet(cmd *cobra.Command, args []string) error {
 }
 
 func commandStartReplication(cmd *cobra.Command, args []string) error {
-	alias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
+	alias, err := parseTabletAlias(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -615,7 +971,7 @@ func commandStartReplication(cmd *cobra.Command, args []string) error {
 }
 
 func commandStopReplication(cmd *cobra.Command, args []string) error {
-	alias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
+	alias, err := parseTabletAlias(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
@@ -628,14 +984,72 @@ func commandStopReplication(cmd *cobra.Command, args []string) error {
 	return err
 }
 
+var upgradeMysqlOptions = struct {
+	MysqlRoot       string
+	ExpectedVersion string
+	AllowPrimary    bool
+}{}
+
+func commandUpgradeMysql(cmd *cobra.Command, args []string) error {
+	alias, err := parseTabletAlias(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	stream, err := client.UpgradeMysql(commandCtx, &vtctldatapb.UpgradeMysqlRequest{
+		TabletAlias:     alias,
+		MysqlRoot:       upgradeMysqlOptions.MysqlRoot,
+		ExpectedVersion: upgradeMysqlOptions.ExpectedVersion,
+		AllowPrimary:    upgradeMysqlOptions.AllowPrimary,
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		switch err {
+		case nil:
+			fmt.Fprintln(cmd.OutOrStdout(), logutil.EventString(resp.Event))
+		case io.EOF:
+			return nil
+		default:
+			return err
+		}
+	}
+}
+
 func init() {
+	Root.AddCommand(AddTabletTag)
+
 	ChangeTabletType.Flags().BoolVarP(&changeTabletTypeOptions.DryRun, "dry-run", "d", false, "Shows the proposed change without actually executing it.")
 	Root.AddCommand(ChangeTabletType)
 
+	ChangeTabletTypeByFilter.Flags().StringVarP(&changeTabletTypeByFilterOptions.Keyspace, "keyspace", "k", "", "Keyspace of the tablets to change.")
+	ChangeTabletTypeByFilter.Flags().StringVarP(&changeTabletTypeByFilterOptions.Shard, "shard", "s", "", "If specified, only change the tablets of this shard.")
+	ChangeTabletTypeByFilter.Flags().StringSliceVarP(&changeTabletTypeByFilterOptions.Cells, "cell", "c", nil, "If specified, only change the tablets in these cells.")
+	ChangeTabletTypeByFilter.Flags().Var((*topoproto.TabletTypeFlag)(&changeTabletTypeByFilterOptions.FromType), "from", "Only change the tablets currently of this type (e.g. rdonly).")
+	ChangeTabletTypeByFilter.Flags().Var((*topoproto.TabletTypeFlag)(&changeTabletTypeByFilterOptions.ToType), "to", "Type to change the tablets to (e.g. replica).")
+	ChangeTabletTypeByFilter.Flags().BoolVarP(&changeTabletTypeByFilterOptions.DryRun, "dry-run", "d", false, "Shows the proposed changes without actually executing them.")
+	ChangeTabletTypeByFilter.Flags().Uint32Var(&changeTabletTypeByFilterOptions.Concurrency, "concurrency", 8, "Maximum number of tablets to change at the same time. Set to 0 for no limit.")
+	ChangeTabletTypeByFilter.MarkFlagRequired("keyspace")
+	ChangeTabletTypeByFilter.MarkFlagRequired("from")
+	ChangeTabletTypeByFilter.MarkFlagRequired("to")
+	Root.AddCommand(ChangeTabletTypeByFilter)
+
 	DeleteTablets.Flags().BoolVarP(&deleteTabletsOptions.AllowPrimary, "allow-primary", "p", false, "Allow the primary tablet of a shard to be deleted. Use with caution.")
+	DeleteTablets.Flags().BoolVar(&deleteTabletsOptions.DryRun, "dry-run", false, "Print the topo mutations that would be performed, without actually deleting anything.")
 	Root.AddCommand(DeleteTablets)
 
 	Root.AddCommand(ExecuteHook)
+
+	FixErrantGTID.Flags().StringVar(&fixErrantGTIDOptions.Mode, "mode", "reconcile", "How to fix the errant GTIDs; valid choices are (reconcile, reset).")
+	FixErrantGTID.Flags().Var((*topoproto.TabletTypeFlag)(&fixErrantGTIDOptions.TabletType), "tablet-type", "Type to change the replica to once fixed (e.g. replica). If not set, the type of the replica is left unchanged.")
+	FixErrantGTID.Flags().BoolVarP(&fixErrantGTIDOptions.DryRun, "dry-run", "d", false, "Only prints the errant GTID set, without fixing anything.")
+	Root.AddCommand(FixErrantGTID)
+
 	Root.AddCommand(GetFullStatus)
 	Root.AddCommand(GetPermissions)
 	Root.AddCommand(GetTablet)
@@ -647,18 +1061,37 @@ func init() {
 	GetTablets.Flags().StringVarP(&getTabletsOptions.Shard, "shard", "s", "", "Shard to filter tablets by.")
 	GetTablets.Flags().StringVar(&getTabletsOptions.Format, "format", "awk", "Output format to use; valid choices are (json, awk).")
 	GetTablets.Flags().BoolVar(&getTabletsOptions.Strict, "strict", false, "Require all cells to return successful tablet data. Without --strict, tablet listings may be partial.")
+	GetTablets.Flags().StringToStringVar(&getTabletsOptions.Tags, "tag", nil, "Tags to filter tablets by, as key=value pairs. Tablets must have all the given tags.")
+	GetTablets.Flags().Uint32Var(&getTabletsOptions.PageSize, "page-size", 0, "Maximum number of tablets to return. With --stream, maximum number of tablets per message instead. 0 means no limit.")
+	GetTablets.Flags().StringVar(&getTabletsOptions.PageToken, "page-token", "", "Token of the page of tablets to return, as printed by the previous page.")
+	GetTablets.Flags().BoolVar(&getTabletsOptions.Stream, "stream", false, "Fetch the tablets over several messages, for clusters with too many tablets to fit in a single response.")
 	Root.AddCommand(GetTablets)
 
 	Root.AddCommand(GetTabletVersion)
 	Root.AddCommand(PingTablet)
 	Root.AddCommand(RefreshState)
 
+	RefreshStateByFilter.Flags().StringVarP(&refreshStateByFilterOptions.Keyspace, "keyspace", "k", "", "Keyspace of the tablets to refresh.")
+	RefreshStateByFilter.Flags().StringVarP(&refreshStateByFilterOptions.Shard, "shard", "s", "", "If specified, only refresh the tablets of this shard.")
+	RefreshStateByFilter.Flags().StringSliceVarP(&refreshStateByFilterOptions.Cells, "cell", "c", nil, "If specified, only refresh the tablets in these cells.")
+	RefreshStateByFilter.Flags().Var((*topoproto.TabletTypeFlag)(&refreshStateByFilterOptions.TabletType), "tablet-type", "If specified, only refresh the tablets of this type (e.g. primary or replica).")
+	RefreshStateByFilter.Flags().Uint32Var(&refreshStateByFilterOptions.Concurrency, "concurrency", 8, "Maximum number of tablets to refresh at the same time. Set to 0 for no limit.")
+	RefreshStateByFilter.MarkFlagRequired("keyspace")
+	Root.AddCommand(RefreshStateByFilter)
+
 	RefreshStateByShard.Flags().StringSliceVarP(&refreshStateByShardOptions.Cells, "cells", "c", nil, "If specified, only call RefreshState on tablets in the specified cells. If empty, all cells are considered.")
 	Root.AddCommand(RefreshStateByShard)
 
+	Root.AddCommand(RemoveTabletTag)
+
 	Root.AddCommand(RunHealthCheck)
 	Root.AddCommand(SetWritable)
 	Root.AddCommand(SleepTablet)
 	Root.AddCommand(StartReplication)
 	Root.AddCommand(StopReplication)
+
+	UpgradeMysql.Flags().StringVar(&upgradeMysqlOptions.MysqlRoot, "mysql-root", "", "Path of the MySQL distribution to upgrade to, used as the new VT_MYSQL_ROOT of the tablet. Omit if the MySQL binaries were upgraded in place.")
+	UpgradeMysql.Flags().StringVar(&upgradeMysqlOptions.ExpectedVersion, "expected-version", "", "Version the upgraded mysqld is expected to have (e.g. 8.0.36). The upgrade is refused before anything is changed if the binaries have another version.")
+	UpgradeMysql.Flags().BoolVar(&upgradeMysqlOptions.AllowPrimary, "allow-primary", false, "Allow the upgrade of a primary tablet. Use with caution, the shard cannot accept writes during the upgrade.")
+	Root.AddCommand(UpgradeMysql)
 }
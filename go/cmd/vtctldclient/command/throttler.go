@@ -50,6 +50,7 @@ var (
 
 func commandUpdateThrottlerConfig(cmd *cobra.Command, args []string) error {
 	keyspace := cmd.Flags().Arg(0)
+	reportKeyspace(keyspace)
 	cli.FinishedParsing(cmd)
 
 	if throttledAppRule.Name != "" && unthrottledAppRule.Name != "" {
@@ -0,0 +1,114 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	// ConcludeTransaction makes a ConcludeTransaction gRPC call to a vtctld.
+	ConcludeTransaction = &cobra.Command{
+		Use:   "ConcludeTransaction <dtid>",
+		Short: "Resolves a distributed transaction stuck in the COMMIT or ROLLBACK state.",
+		Long: `Resolves a distributed transaction stuck in the COMMIT or ROLLBACK state.
+
+The prepared transactions of the participants are committed or rolled back, following the decision
+recorded by the metadata manager, and then the metadata of the transaction is deleted. Transactions
+in the PREPARE state cannot be concluded: they are rolled back by the watchdog of their metadata
+manager once abandoned.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandConcludeTransaction,
+	}
+	// GetUnresolvedTransactions makes a GetUnresolvedTransactions gRPC call to a vtctld.
+	GetUnresolvedTransactions = &cobra.Command{
+		Use:   "GetUnresolvedTransactions [--abandon-age <duration>] <keyspace>",
+		Short: "Outputs a JSON structure with the distributed transactions of the keyspace that are not resolved yet.",
+		Long: `Outputs a JSON structure with the distributed transactions of the keyspace that are not resolved yet.
+
+The transactions are read from the primaries of the shards of the keyspace that are their metadata
+managers. If --abandon-age is passed, only the transactions created before that duration are returned.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandGetUnresolvedTransactions,
+	}
+)
+
+func commandConcludeTransaction(cmd *cobra.Command, args []string) error {
+	dtid := cmd.Flags().Arg(0)
+
+	cli.FinishedParsing(cmd)
+
+	_, err := client.ConcludeTransaction(commandCtx, &vtctldatapb.ConcludeTransactionRequest{
+		Dtid: dtid,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Successfully concluded distributed transaction %s\n", dtid)
+
+	return nil
+}
+
+var getUnresolvedTransactionsOptions = struct {
+	AbandonAge time.Duration
+}{}
+
+func commandGetUnresolvedTransactions(cmd *cobra.Command, args []string) error {
+	keyspace := cmd.Flags().Arg(0)
+	reportKeyspace(keyspace)
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.GetUnresolvedTransactions(commandCtx, &vtctldatapb.GetUnresolvedTransactionsRequest{
+		Keyspace:   keyspace,
+		AbandonAge: int64(getUnresolvedTransactionsOptions.AbandonAge.Seconds()),
+	})
+	if err != nil {
+		return err
+	}
+
+	transactions := resp.Transactions
+	if transactions == nil {
+		transactions = []*querypb.TransactionMetadata{}
+	}
+	data, err := cli.MarshalJSON(transactions)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+
+	return nil
+}
+
+func init() {
+	Root.AddCommand(ConcludeTransaction)
+
+	GetUnresolvedTransactions.Flags().DurationVar(&getUnresolvedTransactionsOptions.AbandonAge, "abandon-age", 0, "Only return the transactions created before this duration, e.g. the --twopc_abandon_age of the tablets.")
+	Root.AddCommand(GetUnresolvedTransactions)
+}
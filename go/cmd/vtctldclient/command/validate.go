@@ -24,7 +24,6 @@ import (
 	"github.com/spf13/cobra"
 
 	"vitess.io/vitess/go/cmd/vtctldclient/cli"
-	"vitess.io/vitess/go/vt/topo/topoproto"
 
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
@@ -72,11 +71,11 @@ func commandValidate(cmd *cobra.Command, args []string) error {
 
 	buf := &strings.Builder{}
 	if err := consumeValidationResults(resp, buf); err != nil {
-		fmt.Printf("Validation results:\n%s", buf.String() /* note: this should have a trailing newline already */)
+		fmt.Fprintf(cmd.OutOrStdout(), "Validation results:\n%s", buf.String() /* note: this should have a trailing newline already */)
 		return err
 	}
 
-	fmt.Println("Validation complete; no issues found.")
+	fmt.Fprintln(cmd.OutOrStdout(), "Validation complete; no issues found.")
 	return nil
 }
 
@@ -88,6 +87,7 @@ func commandValidateKeyspace(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
 	keyspace := cmd.Flags().Arg(0)
+	reportKeyspace(keyspace)
 	resp, err := client.ValidateKeyspace(commandCtx, &vtctldatapb.ValidateKeyspaceRequest{
 		Keyspace:    keyspace,
 		PingTablets: validateKeyspaceOptions.PingTablets,
@@ -98,11 +98,11 @@ func commandValidateKeyspace(cmd *cobra.Command, args []string) error {
 
 	buf := &strings.Builder{}
 	if err := consumeKeyspaceValidationResults(keyspace, resp, buf); err != nil {
-		fmt.Printf("Validation results:\n%s", buf.String() /* note: this should have a trailing newline already */)
+		fmt.Fprintf(cmd.OutOrStdout(), "Validation results:\n%s", buf.String() /* note: this should have a trailing newline already */)
 		return err
 	}
 
-	fmt.Printf("Validation of %s complete; no issues found.\n", keyspace)
+	fmt.Fprintf(cmd.OutOrStdout(), "Validation of %s complete; no issues found.\n", keyspace)
 	return nil
 }
 
@@ -111,7 +111,7 @@ var validateShardOptions = struct {
 }{}
 
 func commandValidateShard(cmd *cobra.Command, args []string) error {
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(cmd.Flags().Arg(0))
 	if err != nil {
 		return fmt.Errorf("could not parse <keyspace/shard> from %s: %w", cmd.Flags().Arg(0), err)
 	}
@@ -129,11 +129,11 @@ func commandValidateShard(cmd *cobra.Command, args []string) error {
 
 	buf := &strings.Builder{}
 	if err := consumeShardValidationResults(keyspace, shard, resp, buf); err != nil {
-		fmt.Printf("Validation results:\n%s", buf.String() /* note: this should have a trailing newline already */)
+		fmt.Fprintf(cmd.OutOrStdout(), "Validation results:\n%s", buf.String() /* note: this should have a trailing newline already */)
 		return err
 	}
 
-	fmt.Printf("Validation of %s/%s complete; no issues found.\n", keyspace, shard)
+	fmt.Fprintf(cmd.OutOrStdout(), "Validation of %s/%s complete; no issues found.\n", keyspace, shard)
 	return nil
 }
 
@@ -108,6 +108,7 @@ func commandApplyVSchema(cmd *cobra.Command, args []string) error {
 	}
 
 	cli.FinishedParsing(cmd)
+	reportKeyspace(cmd.Flags().Arg(0))
 
 	res, err := client.ApplyVSchema(commandCtx, req)
 	if err != nil {
@@ -117,7 +118,7 @@ func commandApplyVSchema(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	fmt.Printf("New VSchema object:\n%s\nIf this is not what you expected, check the input data (as JSON parsing will skip unexpected fields).\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "New VSchema object:\n%s\nIf this is not what you expected, check the input data (as JSON parsing will skip unexpected fields).\n", data)
 	return nil
 }
 
@@ -125,6 +126,7 @@ func commandGetVSchema(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
 	keyspace := cmd.Flags().Arg(0)
+	reportKeyspace(keyspace)
 
 	resp, err := client.GetVSchema(commandCtx, &vtctldatapb.GetVSchemaRequest{
 		Keyspace: keyspace,
@@ -138,7 +140,7 @@ func commandGetVSchema(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 
 	return nil
 }
@@ -0,0 +1,168 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/vt/vtorc/grpcvtorcclient"
+
+	vtorcdatapb "vitess.io/vitess/go/vt/proto/vtorcdata"
+)
+
+var (
+	// GetShardReplicationAnalysis makes a GetShardReplicationAnalysis gRPC call to a VTOrc.
+	GetShardReplicationAnalysis = &cobra.Command{
+		Use:   "GetShardReplicationAnalysis --vtorc-server <vtorc_host:vtorc_port> [<keyspace>[/<shard>]]",
+		Short: "Outputs a JSON structure with the analysis of the tablets by VTOrc, with the problems it found.",
+		Long: `Outputs a JSON structure with the analysis of the tablets by VTOrc, with the problems it found.
+
+The command connects to the gRPC port of the VTOrc passed with --vtorc-server instead of a vtctld.
+If a keyspace, or a keyspace and a shard, is passed, only the analysis of their tablets is returned.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.MaximumNArgs(1),
+		RunE:                  commandGetShardReplicationAnalysis,
+		Annotations: map[string]string{
+			skipClientCreationKey: "true",
+		},
+	}
+	// GetRecentRecoveries makes a GetRecentRecoveries gRPC call to a VTOrc.
+	GetRecentRecoveries = &cobra.Command{
+		Use:   "GetRecentRecoveries --vtorc-server <vtorc_host:vtorc_port> [--unacknowledged-only] [--page <page>] [<keyspace>[/<shard>]]",
+		Short: "Outputs a JSON structure with the most recent recoveries of VTOrc.",
+		Long: `Outputs a JSON structure with the most recent recoveries of VTOrc, the most recent first.
+
+The command connects to the gRPC port of the VTOrc passed with --vtorc-server instead of a vtctld.
+If a keyspace, or a keyspace and a shard, is passed, only their recoveries are returned. The
+recoveries are returned a page at a time, the older ones being in the next pages.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.MaximumNArgs(1),
+		RunE:                  commandGetRecentRecoveries,
+		Annotations: map[string]string{
+			skipClientCreationKey: "true",
+		},
+	}
+)
+
+var vtorcOptions = struct {
+	Server             string
+	UnacknowledgedOnly bool
+	Page               int32
+}{}
+
+var errNoVTOrcServer = errors.New("please specify --vtorc-server <vtorc_host:vtorc_port> to specify the VTOrc to connect to")
+
+// parseVTOrcKeyspaceShard parses the optional <keyspace>[/<shard>] argument
+// of the VTOrc commands.
+func parseVTOrcKeyspaceShard(cmd *cobra.Command) (keyspace string, shard string, err error) {
+	if cmd.Flags().NArg() == 0 {
+		return "", "", nil
+	}
+	arg := cmd.Flags().Arg(0)
+	if !strings.Contains(arg, "/") {
+		reportKeyspace(arg)
+		return arg, "", nil
+	}
+	return parseKeyspaceShard(arg)
+}
+
+func commandGetShardReplicationAnalysis(cmd *cobra.Command, args []string) error {
+	if vtorcOptions.Server == "" {
+		return errNoVTOrcServer
+	}
+	keyspace, shard, err := parseVTOrcKeyspaceShard(cmd)
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	vtorcClient, err := grpcvtorcclient.New(vtorcOptions.Server)
+	if err != nil {
+		return err
+	}
+	defer vtorcClient.Close()
+
+	resp, err := vtorcClient.GetShardReplicationAnalysis(commandCtx, &vtorcdatapb.GetShardReplicationAnalysisRequest{
+		Keyspace: keyspace,
+		Shard:    shard,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+
+	return nil
+}
+
+func commandGetRecentRecoveries(cmd *cobra.Command, args []string) error {
+	if vtorcOptions.Server == "" {
+		return errNoVTOrcServer
+	}
+	keyspace, shard, err := parseVTOrcKeyspaceShard(cmd)
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	vtorcClient, err := grpcvtorcclient.New(vtorcOptions.Server)
+	if err != nil {
+		return err
+	}
+	defer vtorcClient.Close()
+
+	resp, err := vtorcClient.GetRecentRecoveries(commandCtx, &vtorcdatapb.GetRecentRecoveriesRequest{
+		Keyspace:           keyspace,
+		Shard:              shard,
+		UnacknowledgedOnly: vtorcOptions.UnacknowledgedOnly,
+		Page:               vtorcOptions.Page,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+
+	return nil
+}
+
+func init() {
+	GetShardReplicationAnalysis.Flags().StringVar(&vtorcOptions.Server, "vtorc-server", "", "Address of the gRPC port of the VTOrc to connect to (required).")
+	Root.AddCommand(GetShardReplicationAnalysis)
+
+	GetRecentRecoveries.Flags().StringVar(&vtorcOptions.Server, "vtorc-server", "", "Address of the gRPC port of the VTOrc to connect to (required).")
+	GetRecentRecoveries.Flags().BoolVar(&vtorcOptions.UnacknowledgedOnly, "unacknowledged-only", false, "Only return the recoveries that were not acknowledged.")
+	GetRecentRecoveries.Flags().Int32Var(&vtorcOptions.Page, "page", 0, "Page of recoveries to return, from the most recent ones.")
+	Root.AddCommand(GetRecentRecoveries)
+}
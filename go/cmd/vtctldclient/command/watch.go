@@ -0,0 +1,170 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	// Watch is the parent command of the Watch* commands, which stream
+	// topology records as they change.
+	Watch = &cobra.Command{
+		Use:   "Watch <Keyspace|Shard|SrvVSchema> [args]",
+		Short: "Streams a topology record every time it changes.",
+		Long: `Streams a topology record every time it changes.
+
+The record is first printed as it is when the watch is set, and then again every
+time it changes, as one JSON document per change. The watch runs until the
+command is interrupted, --action_timeout elapses, or the record is deleted.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.MinimumNArgs(1),
+	}
+	// WatchKeyspace makes a WatchKeyspace gRPC call to a vtctld.
+	WatchKeyspace = &cobra.Command{
+		Use:                   "Keyspace <keyspace>",
+		Short:                 "Streams the keyspace record every time it changes.",
+		Example:               "Watch Keyspace commerce",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandWatchKeyspace,
+	}
+	// WatchShard makes a WatchShard gRPC call to a vtctld.
+	WatchShard = &cobra.Command{
+		Use:                   "Shard <keyspace/shard>",
+		Short:                 "Streams the shard record every time it changes.",
+		Example:               "Watch Shard commerce/0",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandWatchShard,
+	}
+	// WatchSrvVSchema makes a WatchSrvVSchema gRPC call to a vtctld.
+	WatchSrvVSchema = &cobra.Command{
+		Use:                   "SrvVSchema <cell>",
+		Short:                 "Streams the SrvVSchema of the given cell every time it changes.",
+		Example:               "Watch SrvVSchema zone1",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandWatchSrvVSchema,
+	}
+)
+
+func commandWatchKeyspace(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+	reportKeyspace(cmd.Flags().Arg(0))
+
+	stream, err := client.WatchKeyspace(commandCtx, &vtctldatapb.WatchKeyspaceRequest{
+		Keyspace: cmd.Flags().Arg(0),
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		switch err {
+		case nil:
+			if err := printWatchedRecord(cmd, resp.Keyspace); err != nil {
+				return err
+			}
+		case io.EOF:
+			return nil
+		default:
+			return err
+		}
+	}
+}
+
+func commandWatchShard(cmd *cobra.Command, args []string) error {
+	keyspace, shard, err := parseKeyspaceShard(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	stream, err := client.WatchShard(commandCtx, &vtctldatapb.WatchShardRequest{
+		Keyspace: keyspace,
+		Shard:    shard,
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		switch err {
+		case nil:
+			if err := printWatchedRecord(cmd, resp.Shard); err != nil {
+				return err
+			}
+		case io.EOF:
+			return nil
+		default:
+			return err
+		}
+	}
+}
+
+func commandWatchSrvVSchema(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	stream, err := client.WatchSrvVSchema(commandCtx, &vtctldatapb.WatchSrvVSchemaRequest{
+		Cell: cmd.Flags().Arg(0),
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		switch err {
+		case nil:
+			if err := printWatchedRecord(cmd, resp.SrvVSchema); err != nil {
+				return err
+			}
+		case io.EOF:
+			return nil
+		default:
+			return err
+		}
+	}
+}
+
+func printWatchedRecord(cmd *cobra.Command, record any) error {
+	data, err := cli.MarshalJSON(record)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+	return nil
+}
+
+func init() {
+	Watch.AddCommand(WatchKeyspace)
+	Watch.AddCommand(WatchShard)
+	Watch.AddCommand(WatchSrvVSchema)
+	Root.AddCommand(Watch)
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -139,6 +140,12 @@ See the --help output for each command for more details.`,
 					return fmt.Errorf("invalid on-ddl value: %s", workflowUpdateOptions.OnDDL)
 				}
 			} // Simulated NULL will need to be handled in command
+			if cmd.Flags().Lookup("max-replication-lag").Changed { // Validate the provided value
+				changes = true
+				if lag := workflowUpdateOptions.MaxReplicationLag; lag < 0 || lag%time.Second != 0 {
+					return fmt.Errorf("invalid max-replication-lag value: %v, it must be a non-negative whole number of seconds", lag)
+				}
+			} // Simulated NULL will need to be handled in command
 			if !changes {
 				return fmt.Errorf("no configuration options specified to update")
 			}
@@ -156,6 +163,7 @@ func commandGetWorkflows(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
 	ks := cmd.Flags().Arg(0)
+	reportKeyspace(ks)
 
 	resp, err := client.GetWorkflows(commandCtx, &vtctldatapb.GetWorkflowsRequest{
 		Keyspace:   ks,
@@ -171,7 +179,7 @@ func commandGetWorkflows(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 
 	return nil
 }
@@ -191,11 +199,13 @@ var (
 		TabletTypes                  []topodatapb.TabletType
 		TabletTypesInPreferenceOrder bool
 		OnDDL                        string
+		MaxReplicationLag            time.Duration
 	}{}
 )
 
 func commandWorkflowDelete(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
+	reportKeyspace(workflowOptions.Keyspace)
 
 	req := &vtctldatapb.WorkflowDeleteRequest{
 		Keyspace:         workflowOptions.Keyspace,
@@ -218,13 +228,14 @@ func commandWorkflowDelete(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 
 	return nil
 }
 
 func commandWorkflowShow(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
+	reportKeyspace(workflowOptions.Keyspace)
 
 	req := &vtctldatapb.GetWorkflowsRequest{
 		Keyspace: workflowOptions.Keyspace,
@@ -249,13 +260,14 @@ func commandWorkflowShow(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 
 	return nil
 }
 
 func commandWorkflowUpdate(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
+	reportKeyspace(workflowOptions.Keyspace)
 
 	// We've already validated any provided value, if one WAS provided.
 	// Now we need to do the mapping from the string representation to
@@ -265,6 +277,11 @@ func commandWorkflowUpdate(cmd *cobra.Command, args []string) error {
 		onddl = val
 	}
 
+	maxReplicationLag := int64(textutil.SimulatedNullInt) // Simulated NULL when no value provided
+	if cmd.Flags().Lookup("max-replication-lag").Changed {
+		maxReplicationLag = int64(workflowUpdateOptions.MaxReplicationLag.Seconds())
+	}
+
 	// Simulated NULL when no value is provided.
 	tsp := tabletmanagerdatapb.TabletSelectionPreference_UNKNOWN
 	if cmd.Flags().Lookup("tablet-types-in-order").Changed {
@@ -283,6 +300,7 @@ func commandWorkflowUpdate(cmd *cobra.Command, args []string) error {
 			TabletTypes:               workflowUpdateOptions.TabletTypes,
 			TabletSelectionPreference: tsp,
 			OnDdl:                     binlogdatapb.OnDDLAction(onddl),
+			MaxReplicationLag:         maxReplicationLag,
 		},
 	}
 
@@ -301,13 +319,14 @@ func commandWorkflowUpdate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 
 	return nil
 }
 
 func commandWorkflowUpdateState(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
+	reportKeyspace(workflowOptions.Keyspace)
 
 	var state binlogdatapb.VReplicationWorkflowState
 	switch strings.ToLower(cmd.Name()) {
@@ -323,11 +342,12 @@ func commandWorkflowUpdateState(cmd *cobra.Command, args []string) error {
 	req := &vtctldatapb.WorkflowUpdateRequest{
 		Keyspace: workflowOptions.Keyspace,
 		TabletRequest: &tabletmanagerdatapb.UpdateVReplicationWorkflowRequest{
-			Workflow:    workflowUpdateOptions.Workflow,
-			Cells:       textutil.SimulatedNullStringSlice,
-			TabletTypes: []topodatapb.TabletType{topodatapb.TabletType(textutil.SimulatedNullInt)},
-			OnDdl:       binlogdatapb.OnDDLAction(textutil.SimulatedNullInt),
-			State:       state,
+			Workflow:          workflowUpdateOptions.Workflow,
+			Cells:             textutil.SimulatedNullStringSlice,
+			TabletTypes:       []topodatapb.TabletType{topodatapb.TabletType(textutil.SimulatedNullInt)},
+			OnDdl:             binlogdatapb.OnDDLAction(textutil.SimulatedNullInt),
+			State:             state,
+			MaxReplicationLag: int64(textutil.SimulatedNullInt),
 		},
 	}
 
@@ -346,7 +366,7 @@ func commandWorkflowUpdateState(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
 
 	return nil
 }
@@ -385,5 +405,6 @@ func init() {
 	WorkflowUpdate.Flags().VarP((*topoproto.TabletTypeListFlag)(&workflowUpdateOptions.TabletTypes), "tablet-types", "t", "New source tablet types to replicate from (e.g. PRIMARY,REPLICA,RDONLY)")
 	WorkflowUpdate.Flags().BoolVar(&workflowUpdateOptions.TabletTypesInPreferenceOrder, "tablet-types-in-order", true, "When performing source tablet selection, look for candidates in the type order as they are listed in the tablet-types flag")
 	WorkflowUpdate.Flags().StringVar(&workflowUpdateOptions.OnDDL, "on-ddl", "", "New instruction on what to do when DDL is encountered in the VReplication stream. Possible values are IGNORE, STOP, EXEC, and EXEC_IGNORE")
+	WorkflowUpdate.Flags().DurationVar(&workflowUpdateOptions.MaxReplicationLag, "max-replication-lag", 0, "New replication lag of the replicas of the target shards at which the workflow is throttled, instead of the threshold of the tablet throttler. 0 uses the threshold of the tablet throttler")
 	Workflow.AddCommand(WorkflowUpdate)
 }
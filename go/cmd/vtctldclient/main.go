@@ -52,7 +52,15 @@ func main() {
 	_flag.TrickGlog()
 
 	// back to your regularly scheduled cobra programming
-	if err := command.Root.Execute(); err != nil {
+	err := command.Root.Execute()
+	if command.FinishJSONOutput(err) {
+		// The error, if any, is part of the printed result.
+		if err != nil {
+			exit.Return(1)
+		}
+		return
+	}
+	if err != nil {
 		log.Error(err)
 		exit.Return(1)
 	}
@@ -24,7 +24,10 @@ import (
 // addStatusParts adds UI parts to the /debug/status page of VTOrc
 func addStatusParts() {
 	servenv.AddStatusPart("Recent Recoveries", logic.TopologyRecoveriesTemplate, func() any {
-		recoveries, _ := logic.ReadRecentRecoveries(false, 0)
+		recoveries, _ := logic.ReadRecentRecoveries("", "", false, 0)
 		return recoveries
 	})
+	servenv.AddStatusPart("Maintenance Windows", logic.MaintenanceWindowsTemplate, func() any {
+		return logic.GetMaintenanceWindows()
+	})
 }
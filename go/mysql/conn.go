@@ -940,6 +940,14 @@ func (c *Conn) handleNextCommand(handler Handler) bool {
 	if c.IsMarkedForClose() {
 		return false
 	}
+	// once the listener is shut down, the connections are closed as soon as they are
+	// out of a transaction, to let the clients reconnect to another server.
+	if c.listener != nil && c.listener.shutdown.Load() && c.StatusFlags&ServerStatusInTrans == 0 &&
+		data[0] != ComPing && data[0] != ComQuit {
+		c.recycleReadPacket()
+		c.writeErrorAndLog(sqlerror.ERServerShutdown, sqlerror.SSNetError, "Server shutdown in progress")
+		return false
+	}
 
 	switch data[0] {
 	case ComQuit:
@@ -1246,7 +1254,7 @@ func (c *Conn) handleComPrepare(handler Handler, data []byte) (kontinue bool) {
 		}
 		if len(queries) != 1 {
 			log.Errorf("Conn %v: can not prepare multiple statements", c)
-			return c.writeErrorPacketFromErrorAndLog(err)
+			return c.writeErrorPacketFromErrorAndLog(errPrepareMultipleStatements)
 		}
 	} else {
 		queries = []string{query}
@@ -1351,6 +1359,8 @@ func (c *Conn) handleComPing() bool {
 
 var errEmptyStatement = sqlerror.NewSQLError(sqlerror.EREmptyQuery, sqlerror.SSClientError, "Query was empty")
 
+var errPrepareMultipleStatements = sqlerror.NewSQLError(sqlerror.ERParseError, sqlerror.SSClientError, "cannot prepare multiple statements")
+
 func (c *Conn) handleComQuery(handler Handler, data []byte) (kontinue bool) {
 	c.startWriterBuffering()
 	defer func() {
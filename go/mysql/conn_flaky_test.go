@@ -911,6 +911,29 @@ func TestMultiStatementOnSplitError(t *testing.T) {
 	require.EqualValues(t, data[0], ErrPacket) // we should see the error here
 }
 
+func TestMultiStatementPrepare(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	sConn.Capabilities |= CapabilityClientMultiStatements
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+
+	err := cConn.writePacket(preparePacket(t, "select 1;select 2"))
+	require.NoError(t, err)
+
+	handler := &testRun{t: t, err: fmt.Errorf("not used")}
+	res := sConn.handleNextCommand(handler)
+	require.True(t, res, "we should not break the connection because of execution errors")
+
+	// Multiple statements can not be prepared together.
+	data, err := cConn.ReadPacket()
+	require.NoError(t, err)
+	require.EqualValues(t, ErrPacket, data[0])
+	require.EqualError(t, ParseErrorPacket(data), "cannot prepare multiple statements (errno 1064) (sqlstate 42000)")
+}
+
 func TestInitDbAgainstWrongDbDoesNotDropConnection(t *testing.T) {
 	listener, sConn, cConn := createSocketPair(t)
 	sConn.Capabilities |= CapabilityClientMultiStatements
@@ -55,6 +55,8 @@ const (
 	MySQLUpgradeInServerFlavorCapability
 	DynamicRedoLogCapacityFlavorCapability // supported in MySQL 8.0.30 and above: https://dev.mysql.com/doc/relnotes/mysql/8.0/en/news-8-0-30.html
 	DisableRedoLogFlavorCapability         // supported in MySQL 8.0.21 and above: https://dev.mysql.com/doc/relnotes/mysql/8.0/en/news-8-0-21.html
+	ReplicaTerminologyCapability           // supported in MySQL 8.0.26 and above: https://dev.mysql.com/doc/relnotes/mysql/8.0/en/news-8-0-26.html
+	BinaryLogStatusCapability              // supported in MySQL 8.2.0 and above: https://dev.mysql.com/doc/relnotes/mysql/8.2/en/news-8-2-0.html
 )
 
 const (
@@ -67,13 +69,18 @@ const (
 	mysql57VersionPrefix = "5.7."
 	// mysql80VersionPrefix is the prefix for 8.0 mysql version, such as 8.0.19
 	mysql80VersionPrefix = "8.0."
+	// mysql81VersionPrefix is the prefix for the 8.1 innovation release, which
+	// still supports the replication commands of 8.0.
+	mysql81VersionPrefix = "8.1."
 )
 
 // flavor is the abstract interface for a flavor.
 // Flavors are auto-detected upon connection using the server version.
 // We have two major implementations (the main difference is the GTID
 // handling):
-// 1. Oracle MySQL 5.6, 5.7, 8.0, ...
+// 1. Oracle MySQL 5.6, 5.7, 8.0, ... Starting with 8.2, which includes
+// the 8.4 LTS and 9.x releases, only the source and replica terminology of
+// the replication commands is supported.
 // 2. MariaDB 10.X
 type flavor interface {
 	// primaryGTIDSet returns the current GTIDSet of a server.
@@ -216,8 +223,10 @@ func GetFlavor(serverVersion string, flavorFunc func() flavor) (f flavor, capabl
 		}
 	case strings.HasPrefix(serverVersion, mysql57VersionPrefix):
 		f = mysqlFlavor57{}
-	case strings.HasPrefix(serverVersion, mysql80VersionPrefix):
+	case strings.HasPrefix(serverVersion, mysql80VersionPrefix), strings.HasPrefix(serverVersion, mysql81VersionPrefix):
 		f = mysqlFlavor80{}
+	case isMySQL82OrLater(serverVersion):
+		f = mysqlFlavor82{}
 	default:
 		f = mysqlFlavor56{}
 	}
@@ -227,6 +236,13 @@ func GetFlavor(serverVersion string, flavorFunc func() flavor) (f flavor, capabl
 		}, canonicalVersion
 }
 
+// isMySQL82OrLater returns true if the server version is a MySQL 8.2 or later
+// version, such as 8.4.0 or 9.0.1.
+func isMySQL82OrLater(serverVersion string) bool {
+	atLeast, err := ServerVersionAtLeast(serverVersion, 8, 2)
+	return err == nil && atLeast
+}
+
 // fillFlavor fills in c.Flavor. If the params specify the flavor,
 // that is used. Otherwise, we auto-detect.
 //
@@ -389,30 +405,35 @@ func (c *Conn) SetReplicationPositionCommands(pos replication.Position) []string
 // It is guaranteed to be called with replication stopped.
 // It should not start or stop replication.
 func (c *Conn) SetReplicationSourceCommand(params *ConnParams, host string, port int32, connectRetry int) string {
+	// MySQL 8.2 and above only support CHANGE REPLICATION SOURCE TO.
+	command, source := "CHANGE MASTER TO", "MASTER"
+	if _, ok := c.flavor.(mysqlFlavor82); ok {
+		command, source = "CHANGE REPLICATION SOURCE TO", "SOURCE"
+	}
 	args := []string{
-		fmt.Sprintf("MASTER_HOST = '%s'", host),
-		fmt.Sprintf("MASTER_PORT = %d", port),
-		fmt.Sprintf("MASTER_USER = '%s'", params.Uname),
-		fmt.Sprintf("MASTER_PASSWORD = '%s'", params.Pass),
-		fmt.Sprintf("MASTER_CONNECT_RETRY = %d", connectRetry),
+		fmt.Sprintf("%s_HOST = '%s'", source, host),
+		fmt.Sprintf("%s_PORT = %d", source, port),
+		fmt.Sprintf("%s_USER = '%s'", source, params.Uname),
+		fmt.Sprintf("%s_PASSWORD = '%s'", source, params.Pass),
+		fmt.Sprintf("%s_CONNECT_RETRY = %d", source, connectRetry),
 	}
 	if params.SslEnabled() {
-		args = append(args, "MASTER_SSL = 1")
+		args = append(args, source+"_SSL = 1")
 	}
 	if params.SslCa != "" {
-		args = append(args, fmt.Sprintf("MASTER_SSL_CA = '%s'", params.SslCa))
+		args = append(args, fmt.Sprintf("%s_SSL_CA = '%s'", source, params.SslCa))
 	}
 	if params.SslCaPath != "" {
-		args = append(args, fmt.Sprintf("MASTER_SSL_CAPATH = '%s'", params.SslCaPath))
+		args = append(args, fmt.Sprintf("%s_SSL_CAPATH = '%s'", source, params.SslCaPath))
 	}
 	if params.SslCert != "" {
-		args = append(args, fmt.Sprintf("MASTER_SSL_CERT = '%s'", params.SslCert))
+		args = append(args, fmt.Sprintf("%s_SSL_CERT = '%s'", source, params.SslCert))
 	}
 	if params.SslKey != "" {
-		args = append(args, fmt.Sprintf("MASTER_SSL_KEY = '%s'", params.SslKey))
+		args = append(args, fmt.Sprintf("%s_SSL_KEY = '%s'", source, params.SslKey))
 	}
 	args = append(args, c.flavor.changeReplicationSourceArg())
-	return "CHANGE MASTER TO\n  " + strings.Join(args, ",\n  ")
+	return command + "\n  " + strings.Join(args, ",\n  ")
 }
 
 // resultToMap is a helper function used by ShowReplicationStatus.
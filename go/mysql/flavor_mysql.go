@@ -40,9 +40,17 @@ type mysqlFlavor80 struct {
 	mysqlFlavor
 }
 
+// mysqlFlavor82 is the flavor of MySQL 8.2 and above, including the 8.4 LTS
+// and 9.x releases, which removed the replication commands using the master
+// and slave terminology.
+type mysqlFlavor82 struct {
+	mysqlFlavor80
+}
+
 var _ flavor = (*mysqlFlavor56)(nil)
 var _ flavor = (*mysqlFlavor57)(nil)
 var _ flavor = (*mysqlFlavor80)(nil)
+var _ flavor = (*mysqlFlavor82)(nil)
 
 // primaryGTIDSet is part of the Flavor interface.
 func (mysqlFlavor) primaryGTIDSet(c *Conn) (replication.GTIDSet, error) {
@@ -397,7 +405,153 @@ func (mysqlFlavor80) supportsCapability(serverVersion string, capability FlavorC
 		return ServerVersionAtLeast(serverVersion, 8, 0, 30)
 	case DisableRedoLogFlavorCapability:
 		return ServerVersionAtLeast(serverVersion, 8, 0, 21)
+	case ReplicaTerminologyCapability:
+		return ServerVersionAtLeast(serverVersion, 8, 0, 26)
 	default:
 		return false, nil
 	}
 }
+
+func (mysqlFlavor82) startReplicationCommand() string {
+	return "START REPLICA"
+}
+
+func (mysqlFlavor82) restartReplicationCommands() []string {
+	return []string{
+		"STOP REPLICA",
+		"RESET REPLICA",
+		"START REPLICA",
+	}
+}
+
+func (mysqlFlavor82) startReplicationUntilAfter(pos replication.Position) string {
+	return fmt.Sprintf("START REPLICA UNTIL SQL_AFTER_GTIDS = '%s'", pos)
+}
+
+func (mysqlFlavor82) startSQLThreadUntilAfter(pos replication.Position) string {
+	return fmt.Sprintf("START REPLICA SQL_THREAD UNTIL SQL_AFTER_GTIDS = '%s'", pos)
+}
+
+func (mysqlFlavor82) stopReplicationCommand() string {
+	return "STOP REPLICA"
+}
+
+func (mysqlFlavor82) stopIOThreadCommand() string {
+	return "STOP REPLICA IO_THREAD"
+}
+
+func (mysqlFlavor82) stopSQLThreadCommand() string {
+	return "STOP REPLICA SQL_THREAD"
+}
+
+func (mysqlFlavor82) startSQLThreadCommand() string {
+	return "START REPLICA SQL_THREAD"
+}
+
+// resetReplicationCommands is part of the Flavor interface.
+func (mysqlFlavor82) resetReplicationCommands(c *Conn) []string {
+	resetCommands := []string{
+		"STOP REPLICA",
+		"RESET REPLICA ALL",           // "ALL" makes it forget source host:port.
+		"RESET BINARY LOGS AND GTIDS", // This will also clear gtid_executed and gtid_purged.
+	}
+	if c.SemiSyncExtensionLoaded() {
+		primary, replica := c.SemiSyncTerms()
+		resetCommands = append(resetCommands, fmt.Sprintf("SET GLOBAL rpl_semi_sync_%s_enabled = false, GLOBAL rpl_semi_sync_%s_enabled = false", primary, replica)) // semi-sync will be enabled if needed when replica is started.
+	}
+	return resetCommands
+}
+
+// resetReplicationParametersCommands is part of the Flavor interface.
+func (mysqlFlavor82) resetReplicationParametersCommands(c *Conn) []string {
+	return []string{
+		"RESET REPLICA ALL", // "ALL" makes it forget source host:port.
+	}
+}
+
+// setReplicationPositionCommands is part of the Flavor interface.
+func (mysqlFlavor82) setReplicationPositionCommands(pos replication.Position) []string {
+	return []string{
+		"RESET BINARY LOGS AND GTIDS", // We must clear gtid_executed before setting gtid_purged.
+		fmt.Sprintf("SET GLOBAL gtid_purged = '%s'", pos),
+	}
+}
+
+// changeReplicationSourceArg is part of the Flavor interface.
+func (mysqlFlavor82) changeReplicationSourceArg() string {
+	return "SOURCE_AUTO_POSITION = 1"
+}
+
+// status is part of the Flavor interface.
+func (mysqlFlavor82) status(c *Conn) (replication.ReplicationStatus, error) {
+	qr, err := c.ExecuteFetch("SHOW REPLICA STATUS", 100, true /* wantfields */)
+	if err != nil {
+		return replication.ReplicationStatus{}, err
+	}
+	if len(qr.Rows) == 0 {
+		// The query returned no data, meaning the server
+		// is not configured as a replica.
+		return replication.ReplicationStatus{}, ErrNotReplica
+	}
+
+	resultMap, err := resultToMap(qr)
+	if err != nil {
+		return replication.ReplicationStatus{}, err
+	}
+
+	return replication.ParseMysqlReplicaStatus(resultMap)
+}
+
+// primaryStatus is part of the Flavor interface.
+func (mysqlFlavor82) primaryStatus(c *Conn) (replication.PrimaryStatus, error) {
+	qr, err := c.ExecuteFetch("SHOW BINARY LOG STATUS", 100, true /* wantfields */)
+	if err != nil {
+		return replication.PrimaryStatus{}, err
+	}
+	if len(qr.Rows) == 0 {
+		// The query returned no data. We don't know how this could happen.
+		return replication.PrimaryStatus{}, ErrNoPrimaryStatus
+	}
+
+	resultMap, err := resultToMap(qr)
+	if err != nil {
+		return replication.PrimaryStatus{}, err
+	}
+
+	return replication.ParseMysqlPrimaryStatus(resultMap)
+}
+
+// waitUntilPositionCommand is part of the Flavor interface.
+func (mysqlFlavor82) waitUntilPositionCommand(ctx context.Context, pos replication.Position) (string, error) {
+	// WAIT_UNTIL_SQL_THREAD_AFTER_GTIDS is removed in MySQL 8.4. Omitting
+	// the timeout of WAIT_FOR_EXECUTED_GTID_SET means waiting indefinitely.
+	timeoutArg := ""
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout := time.Until(deadline)
+		if timeout <= 0 {
+			return "", vterrors.Errorf(vtrpc.Code_DEADLINE_EXCEEDED, "timed out waiting for position %v", pos)
+		}
+
+		// Only whole numbers of seconds are supported.
+		timeoutSeconds := int(timeout.Seconds())
+		if timeoutSeconds == 0 {
+			// We don't want a timeout <1.0s to truncate down to become infinite.
+			timeoutSeconds = 1
+		}
+		timeoutArg = fmt.Sprintf(", %v", timeoutSeconds)
+	}
+
+	// WAIT_FOR_EXECUTED_GTID_SET returns 1 if it times out, which is
+	// translated to the -1 of WAIT_UNTIL_SQL_THREAD_AFTER_GTIDS.
+	return fmt.Sprintf("SELECT IF(WAIT_FOR_EXECUTED_GTID_SET('%s'%s) = 1, -1, 0)", pos, timeoutArg), nil
+}
+
+// supportsCapability is part of the Flavor interface.
+func (mysqlFlavor82) supportsCapability(serverVersion string, capability FlavorCapability) (bool, error) {
+	switch capability {
+	case BinaryLogStatusCapability:
+		return true, nil
+	default:
+		return mysqlFlavor80{}.supportsCapability(serverVersion, capability)
+	}
+}
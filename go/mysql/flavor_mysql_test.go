@@ -17,9 +17,14 @@ limitations under the License.
 package mysql
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/replication"
 )
 
 func TestMysql56SetReplicationSourceCommand(t *testing.T) {
@@ -75,3 +80,109 @@ func TestMysql56SetReplicationSourceCommandSSL(t *testing.T) {
 	assert.Equal(t, want, got, "mysqlFlavor.SetReplicationSourceCommand(%#v, %#v, %#v, %#v) = %#v, want %#v", params, host, port, connectRetry, got, want)
 
 }
+
+func TestMysql82SetReplicationSourceCommand(t *testing.T) {
+	params := &ConnParams{
+		Uname:   "username",
+		Pass:    "password",
+		SslCa:   "ssl-ca",
+		SslCert: "ssl-cert",
+		SslKey:  "ssl-key",
+	}
+	params.EnableSSL()
+	host := "localhost"
+	port := int32(123)
+	connectRetry := 1234
+	want := `CHANGE REPLICATION SOURCE TO
+  SOURCE_HOST = 'localhost',
+  SOURCE_PORT = 123,
+  SOURCE_USER = 'username',
+  SOURCE_PASSWORD = 'password',
+  SOURCE_CONNECT_RETRY = 1234,
+  SOURCE_SSL = 1,
+  SOURCE_SSL_CA = 'ssl-ca',
+  SOURCE_SSL_CERT = 'ssl-cert',
+  SOURCE_SSL_KEY = 'ssl-key',
+  SOURCE_AUTO_POSITION = 1`
+
+	conn := &Conn{flavor: mysqlFlavor82{}}
+	got := conn.SetReplicationSourceCommand(params, host, port, connectRetry)
+	assert.Equal(t, want, got)
+}
+
+func TestMysqlFlavorReplicationCommands(t *testing.T) {
+	pos, err := replication.DecodePosition("MySQL56/3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5")
+	require.NoError(t, err)
+
+	testcases := []struct {
+		version           string
+		start             string
+		stop              string
+		stopIOThread      string
+		startUntilAfter   string
+		setPosition       []string
+		waitUntilPosition string
+	}{
+		{
+			version:           "5.7.38",
+			start:             "START SLAVE",
+			stop:              "STOP SLAVE",
+			stopIOThread:      "STOP SLAVE IO_THREAD",
+			startUntilAfter:   "START SLAVE UNTIL SQL_AFTER_GTIDS = '3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5'",
+			setPosition:       []string{"RESET MASTER", "SET GLOBAL gtid_purged = '3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5'"},
+			waitUntilPosition: "SELECT WAIT_UNTIL_SQL_THREAD_AFTER_GTIDS('3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5', 0)",
+		},
+		{
+			version:           "8.0.36",
+			start:             "START SLAVE",
+			stop:              "STOP SLAVE",
+			stopIOThread:      "STOP SLAVE IO_THREAD",
+			startUntilAfter:   "START SLAVE UNTIL SQL_AFTER_GTIDS = '3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5'",
+			setPosition:       []string{"RESET MASTER", "SET GLOBAL gtid_purged = '3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5'"},
+			waitUntilPosition: "SELECT WAIT_UNTIL_SQL_THREAD_AFTER_GTIDS('3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5', 0)",
+		},
+		{
+			version:           "8.4.0",
+			start:             "START REPLICA",
+			stop:              "STOP REPLICA",
+			stopIOThread:      "STOP REPLICA IO_THREAD",
+			startUntilAfter:   "START REPLICA UNTIL SQL_AFTER_GTIDS = '3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5'",
+			setPosition:       []string{"RESET BINARY LOGS AND GTIDS", "SET GLOBAL gtid_purged = '3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5'"},
+			waitUntilPosition: "SELECT IF(WAIT_FOR_EXECUTED_GTID_SET('3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5') = 1, -1, 0)",
+		},
+		{
+			version:           "9.0.1",
+			start:             "START REPLICA",
+			stop:              "STOP REPLICA",
+			stopIOThread:      "STOP REPLICA IO_THREAD",
+			startUntilAfter:   "START REPLICA UNTIL SQL_AFTER_GTIDS = '3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5'",
+			setPosition:       []string{"RESET BINARY LOGS AND GTIDS", "SET GLOBAL gtid_purged = '3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5'"},
+			waitUntilPosition: "SELECT IF(WAIT_FOR_EXECUTED_GTID_SET('3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5') = 1, -1, 0)",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.version, func(t *testing.T) {
+			f, _, _ := GetFlavor(tc.version, nil)
+			conn := &Conn{flavor: f}
+			assert.Equal(t, tc.start, conn.StartReplicationCommand())
+			assert.Equal(t, tc.stop, conn.StopReplicationCommand())
+			assert.Equal(t, tc.stopIOThread, conn.StopIOThreadCommand())
+			assert.Equal(t, tc.startUntilAfter, conn.StartReplicationUntilAfterCommand(pos))
+			assert.Equal(t, tc.setPosition, conn.SetReplicationPositionCommands(pos))
+			waitUntilPosition, err := conn.WaitUntilPositionCommand(context.Background(), pos)
+			require.NoError(t, err)
+			assert.Equal(t, tc.waitUntilPosition, waitUntilPosition)
+		})
+	}
+}
+
+func TestMysql82WaitUntilPositionCommandTimeout(t *testing.T) {
+	pos, err := replication.DecodePosition("MySQL56/3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	got, err := mysqlFlavor82{}.waitUntilPositionCommand(ctx, pos)
+	require.NoError(t, err)
+	assert.Regexp(t, `^SELECT IF\(WAIT_FOR_EXECUTED_GTID_SET\('3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5', (9|10)\) = 1, -1, 0\)$`, got)
+}
@@ -160,6 +160,46 @@ func TestGetFlavor(t *testing.T) {
 			capability: DisableRedoLogFlavorCapability,
 			isCapable:  false,
 		},
+		{
+			version:    "8.0.25",
+			capability: ReplicaTerminologyCapability,
+			isCapable:  false,
+		},
+		{
+			version:    "8.0.26",
+			capability: ReplicaTerminologyCapability,
+			isCapable:  true,
+		},
+		{
+			version:    "8.0.36",
+			capability: BinaryLogStatusCapability,
+			isCapable:  false,
+		},
+		{
+			version:    "8.4.0",
+			capability: BinaryLogStatusCapability,
+			isCapable:  true,
+		},
+		{
+			version:    "8.4.0",
+			capability: ReplicaTerminologyCapability,
+			isCapable:  true,
+		},
+		{
+			version:    "8.4.0",
+			capability: InstantAddDropColumnFlavorCapability,
+			isCapable:  true,
+		},
+		{
+			version:    "9.0.1",
+			capability: DynamicRedoLogCapacityFlavorCapability,
+			isCapable:  true,
+		},
+		{
+			version:    "9.0.1",
+			capability: MySQLJSONFlavorCapability,
+			isCapable:  true,
+		},
 	}
 	for _, tc := range testcases {
 		name := fmt.Sprintf("%s %v", tc.version, tc.capability)
@@ -171,3 +211,26 @@ func TestGetFlavor(t *testing.T) {
 		})
 	}
 }
+
+func TestGetFlavorVersions(t *testing.T) {
+	testcases := []struct {
+		version string
+		flavor  flavor
+	}{
+		{version: "5.6.51", flavor: mysqlFlavor56{}},
+		{version: "5.7.38-log", flavor: mysqlFlavor57{}},
+		{version: "8.0.36", flavor: mysqlFlavor80{}},
+		{version: "8.1.0", flavor: mysqlFlavor80{}},
+		{version: "8.2.0", flavor: mysqlFlavor82{}},
+		{version: "8.4.0-log", flavor: mysqlFlavor82{}},
+		{version: "9.0.1", flavor: mysqlFlavor82{}},
+		{version: "10.4.27-MariaDB", flavor: mariadbFlavor102{}},
+		{version: "5.5.5-10.1.48-MariaDB", flavor: mariadbFlavor101{}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.version, func(t *testing.T) {
+			f, _, _ := GetFlavor(tc.version, nil)
+			assert.Equal(t, tc.flavor, f)
+		})
+	}
+}
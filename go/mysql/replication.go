@@ -147,3 +147,15 @@ func (c *Conn) SemiSyncExtensionLoaded() bool {
 	}
 	return len(qr.Rows) >= 1
 }
+
+// SemiSyncTerms returns the terms of the primary and the replica in the
+// variables of the loaded semisync plugins: "source" and "replica" for the
+// plugins of MySQL 8.0.26 and above, which are the only ones of MySQL 8.4
+// and above, or else "master" and "slave".
+func (c *Conn) SemiSyncTerms() (primary, replica string) {
+	qr, err := c.ExecuteFetch("SHOW GLOBAL VARIABLES LIKE 'rpl_semi_sync_source_enabled'", 10, false)
+	if err == nil && len(qr.Rows) >= 1 {
+		return "source", "replica"
+	}
+	return "master", "slave"
+}
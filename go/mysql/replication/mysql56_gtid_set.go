@@ -467,6 +467,17 @@ func (set Mysql56GTIDSet) SIDBlock() []byte {
 	return buf.Bytes()
 }
 
+// Count returns the number of GTIDs in the set.
+func (set Mysql56GTIDSet) Count() int64 {
+	var count int64
+	for _, intervals := range set {
+		for _, iv := range intervals {
+			count += iv.end - iv.start + 1
+		}
+	}
+	return count
+}
+
 // Difference will supply the difference between the receiver and supplied Mysql56GTIDSets, and supply the result
 // as a Mysql56GTIDSet.
 func (set Mysql56GTIDSet) Difference(other Mysql56GTIDSet) Mysql56GTIDSet {
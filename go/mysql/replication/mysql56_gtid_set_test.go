@@ -585,6 +585,18 @@ func TestMySQL56GTIDSetLast(t *testing.T) {
 	}
 }
 
+func TestMysql56GTIDSetCount(t *testing.T) {
+	sid1 := SID{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+	sid2 := SID{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 16}
+
+	assert.EqualValues(t, 0, Mysql56GTIDSet{}.Count())
+	assert.EqualValues(t, 1, Mysql56GTIDSet{sid1: []interval{{5, 5}}}.Count())
+	assert.EqualValues(t, 35, Mysql56GTIDSet{
+		sid1: []interval{{1, 10}, {20, 30}},
+		sid2: []interval{{7, 20}},
+	}.Count())
+}
+
 func TestSubtract(t *testing.T) {
 	tests := []struct {
 		name       string
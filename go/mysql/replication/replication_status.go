@@ -19,6 +19,7 @@ package replication
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"vitess.io/vitess/go/vt/log"
 	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
@@ -222,6 +223,21 @@ func (s *ReplicationStatus) FindErrantGTIDs(otherReplicaStatuses []*ReplicationS
 	return diffSet, nil
 }
 
+// ParseMysqlReplicaStatus parses the result of SHOW REPLICA STATUS, whose
+// fields use the source and replica terminology, e.g. Source_Host rather
+// than the Master_Host of SHOW SLAVE STATUS.
+func ParseMysqlReplicaStatus(resultMap map[string]string) (ReplicationStatus, error) {
+	legacyMap := make(map[string]string, len(resultMap))
+	for field, value := range resultMap {
+		field = strings.ReplaceAll(field, "Source", "Master")
+		if strings.HasPrefix(field, "Replica_") {
+			field = "Slave_" + strings.TrimPrefix(field, "Replica_")
+		}
+		legacyMap[field] = value
+	}
+	return ParseMysqlReplicationStatus(legacyMap)
+}
+
 func ParseMysqlReplicationStatus(resultMap map[string]string) (ReplicationStatus, error) {
 	status := ParseReplicationStatus(resultMap)
 	uuidString := resultMap["Master_UUID"]
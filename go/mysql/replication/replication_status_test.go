@@ -187,6 +187,43 @@ func TestMysqlShouldGetRelayLogPosition(t *testing.T) {
 	assert.Equalf(t, got.RelayLogPosition.GTIDSet.String(), want.RelayLogPosition.GTIDSet.String(), "got RelayLogPosition: %v; want RelayLogPosition: %v", got.RelayLogPosition.GTIDSet, want.RelayLogPosition.GTIDSet)
 }
 
+func TestMysqlReplicaStatus(t *testing.T) {
+	resultMap := map[string]string{
+		"Source_Host":           "source-host",
+		"Source_Port":           "3306",
+		"Source_User":           "vt_repl",
+		"Source_Server_Id":      "1",
+		"Source_UUID":           "3e11fa47-71ca-11e1-9e33-c80aa9429562",
+		"Replica_IO_Running":    "Yes",
+		"Replica_SQL_Running":   "No",
+		"Seconds_Behind_Source": "7",
+		"Executed_Gtid_Set":     "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5",
+		"Retrieved_Gtid_Set":    "3e11fa47-71ca-11e1-9e33-c80aa9429562:6-9",
+		"Exec_Source_Log_Pos":   "1307",
+		"Relay_Source_Log_File": "source-bin.000002",
+		"Read_Source_Log_Pos":   "1308",
+		"Source_Log_File":       "source-bin.000003",
+		"Replicate_Do_DB":       "",
+	}
+
+	got, err := ParseMysqlReplicaStatus(resultMap)
+	require.NoError(t, err)
+	assert.Equal(t, "source-host", got.SourceHost)
+	assert.EqualValues(t, 3306, got.SourcePort)
+	assert.Equal(t, "vt_repl", got.SourceUser)
+	assert.EqualValues(t, 1, got.SourceServerID)
+	assert.Equal(t, "3e11fa47-71ca-11e1-9e33-c80aa9429562", got.SourceUUID.String())
+	assert.Equal(t, ReplicationStateRunning, got.IOState)
+	assert.Equal(t, ReplicationStateStopped, got.SQLState)
+	assert.False(t, got.ReplicationLagUnknown)
+	assert.EqualValues(t, 7, got.ReplicationLagSeconds)
+	assert.False(t, got.HasReplicationFilters)
+	assert.Equal(t, "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5", got.Position.GTIDSet.String())
+	assert.Equal(t, "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-9", got.RelayLogPosition.GTIDSet.String())
+	assert.Equal(t, FilePosGTID{File: "source-bin.000002", Pos: 1307}, got.FilePosition.GTIDSet)
+	assert.Equal(t, FilePosGTID{File: "source-bin.000003", Pos: 1308}, got.RelayLogSourceBinlogEquivalentPosition.GTIDSet)
+}
+
 func TestMariadbRetrieveSourceServerId(t *testing.T) {
 	resultMap := map[string]string{
 		"Master_Server_Id": "1",
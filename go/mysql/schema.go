@@ -32,6 +32,18 @@ const (
 		FROM information_schema.STATISTICS
 		WHERE TABLE_SCHEMA = DATABASE() AND LOWER(INDEX_NAME) = 'primary'
 		ORDER BY table_name, SEQ_IN_INDEX`
+	// BaseShowUniqueKeys is the base query for fetching the secondary unique key info.
+	BaseShowUniqueKeys = `
+		SELECT TABLE_NAME as table_name, INDEX_NAME as index_name, COLUMN_NAME as column_name
+		FROM information_schema.STATISTICS
+		WHERE TABLE_SCHEMA = DATABASE() AND NON_UNIQUE = 0 AND LOWER(INDEX_NAME) != 'primary'
+		ORDER BY table_name, index_name, SEQ_IN_INDEX`
+	// BaseShowRoutines is the base query for fetching the stored procedures and functions.
+	BaseShowRoutines = `
+		SELECT ROUTINE_TYPE as routine_type, ROUTINE_NAME as routine_name, CREATED as created, LAST_ALTERED as last_altered, ROUTINE_DEFINITION as routine_definition
+		FROM information_schema.ROUTINES
+		WHERE ROUTINE_SCHEMA = DATABASE()
+		ORDER BY routine_type, routine_name`
 	// ShowRowsRead is the query used to find the number of rows read.
 	ShowRowsRead = "show status like 'Innodb_rows_read'"
 
@@ -152,3 +164,53 @@ func ShowPrimaryRow(tableName, colName string) []sqltypes.Value {
 		sqltypes.MakeTrusted(sqltypes.VarChar, []byte(colName)),
 	}
 }
+
+// ShowUniqueKeysFields contains the fields for a BaseShowUniqueKeys.
+var ShowUniqueKeysFields = []*querypb.Field{{
+	Name: "table_name",
+	Type: sqltypes.VarChar,
+}, {
+	Name: "index_name",
+	Type: sqltypes.VarChar,
+}, {
+	Name: "column_name",
+	Type: sqltypes.VarChar,
+}}
+
+// ShowUniqueKeysRow returns a row for a secondary unique key column.
+func ShowUniqueKeysRow(tableName, indexName, colName string) []sqltypes.Value {
+	return []sqltypes.Value{
+		sqltypes.MakeTrusted(sqltypes.VarChar, []byte(tableName)),
+		sqltypes.MakeTrusted(sqltypes.VarChar, []byte(indexName)),
+		sqltypes.MakeTrusted(sqltypes.VarChar, []byte(colName)),
+	}
+}
+
+// ShowRoutinesFields contains the fields for a BaseShowRoutines.
+var ShowRoutinesFields = []*querypb.Field{{
+	Name: "routine_type",
+	Type: sqltypes.VarChar,
+}, {
+	Name: "routine_name",
+	Type: sqltypes.VarChar,
+}, {
+	Name: "created",
+	Type: sqltypes.Datetime,
+}, {
+	Name: "last_altered",
+	Type: sqltypes.Datetime,
+}, {
+	Name: "routine_definition",
+	Type: sqltypes.Text,
+}}
+
+// ShowRoutinesRow returns a row for a stored procedure or function.
+func ShowRoutinesRow(routineType, routineName, created, lastAltered, definition string) []sqltypes.Value {
+	return []sqltypes.Value{
+		sqltypes.MakeTrusted(sqltypes.VarChar, []byte(routineType)),
+		sqltypes.MakeTrusted(sqltypes.VarChar, []byte(routineName)),
+		sqltypes.MakeTrusted(sqltypes.Datetime, []byte(created)),
+		sqltypes.MakeTrusted(sqltypes.Datetime, []byte(lastAltered)),
+		sqltypes.MakeTrusted(sqltypes.Text, []byte(definition)),
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"crypto/tls"
 	"io"
+	"math/rand"
 	"net"
 	"strings"
 	"sync/atomic"
@@ -212,6 +213,14 @@ type Listener struct {
 	// handled further by the MySQL handler. An non-nil error will stop
 	// processing the connection by the MySQL handler.
 	PreHandleFunc func(context.Context, net.Conn, uint32) (net.Conn, error)
+
+	// xProtocol is true if the listener serves the X Protocol instead of the MySQL protocol.
+	xProtocol bool
+	// The _id of the documents inserted through the X Protocol without one are made of
+	// a random prefix, the start time of the listener and a serial.
+	xDocumentIDPrefix uint16
+	xDocumentIDStart  uint32
+	xDocumentIDSerial atomic.Uint64
 }
 
 // NewFromListener creates a new mysql listener from an existing net.Listener
@@ -273,6 +282,8 @@ type ListenerConfig struct {
 	ConnReadBufferSize  int
 	ConnBufferPooling   bool
 	ConnKeepAlivePeriod time.Duration
+	// XProtocol makes the listener serve the X Protocol of the MySQL document store.
+	XProtocol bool
 }
 
 // NewListenerWithConfig creates new listener using provided config. There are
@@ -300,6 +311,9 @@ func NewListenerWithConfig(cfg ListenerConfig) (*Listener, error) {
 		connReadBufferSize:  cfg.ConnReadBufferSize,
 		connBufferPooling:   cfg.ConnBufferPooling,
 		connKeepAlivePeriod: cfg.ConnKeepAlivePeriod,
+		xProtocol:           cfg.XProtocol,
+		xDocumentIDPrefix:   uint16(rand.Uint32()),
+		xDocumentIDStart:    uint32(time.Now().Unix()),
 	}, nil
 }
 
@@ -337,6 +351,10 @@ func (l *Listener) Accept() {
 				}
 			}
 
+			if l.xProtocol {
+				l.handleX(conn, connectionID, acceptTime)
+				return
+			}
 			l.handle(conn, connectionID, acceptTime)
 		}()
 	}
@@ -547,6 +565,8 @@ func (l *Listener) Close() {
 }
 
 // Shutdown closes listener and fails any Ping requests from existing connections.
+// The other commands of the connections that are not in a transaction fail with
+// ER_SERVER_SHUTDOWN, and the connections are closed.
 // This can be used for graceful shutdown, to let clients know that they should reconnect to another server.
 func (l *Listener) Shutdown() {
 	if l.shutdown.CompareAndSwap(false, true) {
@@ -1439,6 +1439,12 @@ func TestListenerShutdown(t *testing.T) {
 	require.Equal(t, sqlerror.ERServerShutdown, sqlErr.Number())
 	require.Equal(t, sqlerror.SSNetError, sqlErr.SQLState())
 	require.Equal(t, "Server shutdown in progress", sqlErr.Message)
+
+	// the queries of a connection that is not in a transaction fail, and the connection is closed
+	_, err = conn.ExecuteFetch("select 1", 1, false)
+	require.EqualError(t, err, "Server shutdown in progress (errno 1053) (sqlstate 08S01) during query: select 1")
+	_, err = conn.ExecuteFetch("select 1", 1, false)
+	require.Error(t, err)
 }
 
 func TestParseConnAttrs(t *testing.T) {
@@ -87,6 +87,7 @@ const (
 	ERKillDenied                = ErrorCode(1095)
 	ERNoPermissionToCreateUsers = ErrorCode(1211)
 	ERSpecifiedAccessDenied     = ErrorCode(1227)
+	ERNotSupportedAuthMode      = ErrorCode(1251)
 
 	// failed precondition
 	ERNoDb                          = ErrorCode(1046)
@@ -286,6 +287,24 @@ const (
 
 	// server not available
 	ERServerIsntAvailable = ErrorCode(3168)
+
+	// X Protocol errors
+	ERXBadMessage                = ErrorCode(5000)
+	ERXCapabilitiesPrepareFailed = ErrorCode(5001)
+	ERXCapabilityNotFound        = ErrorCode(5002)
+	ERXInvalidProtocolData       = ErrorCode(5003)
+	ERXCmdNumArguments           = ErrorCode(5015)
+	ERXCmdArgumentType           = ErrorCode(5016)
+	ERXBadUpdateData             = ErrorCode(5050)
+	ERXBadMemberToUpdate         = ErrorCode(5053)
+	ERXExprBadOperator           = ErrorCode(5150)
+	ERXExprBadNumArgs            = ErrorCode(5151)
+	ERXExprMissingArg            = ErrorCode(5152)
+	ERXExprBadTypeValue          = ErrorCode(5153)
+	ERXExprBadValue              = ErrorCode(5154)
+	ERXInvalidCollection         = ErrorCode(5156)
+	ERXInvalidAdminCommand       = ErrorCode(5157)
+	ERXInvalidNamespace          = ErrorCode(5162)
 )
 
 // Sql states for errors.
@@ -194,6 +194,7 @@ var stateToMysqlCode = map[vterrors.State]mysqlCode{
 	vterrors.ForbidSchemaChange:           {num: ERForbidSchemaChange, state: SSUnknownSQLState},
 	vterrors.MixOfGroupFuncAndFields:      {num: ERMixOfGroupFuncAndFields, state: SSClientError},
 	vterrors.NetPacketTooLarge:            {num: ERNetPacketTooLarge, state: SSNetError},
+	vterrors.UserLimitReached:             {num: ERUserLimitReached, state: SSClientError},
 	vterrors.NonUniqError:                 {num: ERNonUniq, state: SSConstraintViolation},
 	vterrors.NonUniqTable:                 {num: ERNonUniqTable, state: SSClientError},
 	vterrors.NonUpdateableTable:           {num: ERNonUpdateableTable, state: SSUnknownSQLState},
@@ -210,6 +211,7 @@ var stateToMysqlCode = map[vterrors.State]mysqlCode{
 	vterrors.WrongValue:                   {num: ERWrongValue, state: SSUnknownSQLState},
 	vterrors.WrongFieldWithGroup:          {num: ERWrongFieldWithGroup, state: SSClientError},
 	vterrors.ServerNotAvailable:           {num: ERServerIsntAvailable, state: SSNetError},
+	vterrors.ServerShutdown:               {num: ERServerShutdown, state: SSNetError},
 	vterrors.CantDoThisInTransaction:      {num: ERCantDoThisDuringAnTransaction, state: SSCantDoThisDuringAnTransaction},
 	vterrors.RequiresPrimaryKey:           {num: ERRequiresPrimaryKey, state: SSClientError},
 	vterrors.RowIsReferenced2:             {num: ERRowIsReferenced2, state: SSConstraintViolation},
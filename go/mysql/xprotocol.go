@@ -0,0 +1,854 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/sqltypes"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// This file implements the framing and the messages of the X Protocol, the protocol of the
+// MySQL document store. Every message is a protobuf message of the Mysqlx package, preceded
+// by its length on 4 bytes and its type on one byte. The messages are encoded and decoded
+// with protowire, field by field, as only a few fields of a few messages are needed.
+
+// The types of the client messages.
+const (
+	xClientConCapabilitiesGet       = 1
+	xClientConCapabilitiesSet       = 2
+	xClientConClose                 = 3
+	xClientSessAuthenticateStart    = 4
+	xClientSessAuthenticateContinue = 5
+	xClientSessReset                = 6
+	xClientSessClose                = 7
+	xClientSQLStmtExecute           = 12
+	xClientCrudFind                 = 17
+	xClientCrudInsert               = 18
+	xClientCrudUpdate               = 19
+	xClientCrudDelete               = 20
+	xClientExpectOpen               = 24
+	xClientExpectClose              = 25
+)
+
+// The types of the server messages.
+const (
+	xServerOk                       = 0
+	xServerError                    = 1
+	xServerConCapabilities          = 2
+	xServerSessAuthenticateContinue = 3
+	xServerSessAuthenticateOk       = 4
+	xServerNotice                   = 11
+	xServerColumnMetaData           = 12
+	xServerRow                      = 13
+	xServerFetchDone                = 14
+	xServerSQLStmtExecuteOk         = 17
+)
+
+// The types of the Mysqlx.Datatypes.Scalar values.
+const (
+	xScalarSint   = 1
+	xScalarUint   = 2
+	xScalarNull   = 3
+	xScalarOctets = 4
+	xScalarDouble = 5
+	xScalarFloat  = 6
+	xScalarBool   = 7
+	xScalarString = 8
+)
+
+// The types of the Mysqlx.Datatypes.Any values.
+const (
+	xAnyScalar = 1
+	xAnyObject = 2
+	xAnyArray  = 3
+)
+
+// The content types of the octets, in Mysqlx.Datatypes.Scalar.Octets and Mysqlx.Resultset.ColumnMetaData.
+const (
+	xContentTypeGeometry = 1
+	xContentTypeJSON     = 2
+)
+
+// xMaxMessageSize is the maximum size of a client message, as the default mysqlx_max_allowed_packet.
+const xMaxMessageSize = 64 << 20
+
+// readXMessage reads a message, and returns its type and its payload.
+func readXMessage(r io.Reader) (byte, []byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	length := binary.LittleEndian.Uint32(header[:4])
+	if length == 0 || length > xMaxMessageSize {
+		return 0, nil, sqlerror.NewSQLError(sqlerror.ERNetPacketTooLarge, sqlerror.SSNetError, "Got a packet bigger than 'mysqlx_max_allowed_packet' bytes")
+	}
+	payload := make([]byte, length-1)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	return header[4], payload, nil
+}
+
+// writeXMessage writes a message of the given type.
+func writeXMessage(w io.Writer, typ byte, payload []byte) error {
+	header := make([]byte, 5, 5+len(payload))
+	binary.LittleEndian.PutUint32(header, uint32(len(payload)+1))
+	header[4] = typ
+	_, err := w.Write(append(header, payload...))
+	return err
+}
+
+// xField is a field of a protobuf message: its value is in varint for the varint and
+// fixed wire types, and in bytes for the length-delimited wire type.
+type xField struct {
+	num    protowire.Number
+	varint uint64
+	bytes  []byte
+}
+
+// parseXMessage returns the fields of a protobuf message.
+func parseXMessage(data []byte) ([]xField, error) {
+	var fields []xField
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil, errXBadMessage(protowire.ParseError(n))
+		}
+		data = data[n:]
+		field := xField{num: num}
+		switch typ {
+		case protowire.VarintType:
+			field.varint, n = protowire.ConsumeVarint(data)
+		case protowire.Fixed32Type:
+			var v uint32
+			v, n = protowire.ConsumeFixed32(data)
+			field.varint = uint64(v)
+		case protowire.Fixed64Type:
+			field.varint, n = protowire.ConsumeFixed64(data)
+		case protowire.BytesType:
+			field.bytes, n = protowire.ConsumeBytes(data)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if n < 0 {
+			return nil, errXBadMessage(protowire.ParseError(n))
+		}
+		data = data[n:]
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func errXBadMessage(err error) error {
+	return sqlerror.NewSQLError(sqlerror.ERXBadMessage, sqlerror.SSUnknownSQLState, "Parse error unserializing protobuf message: %v", err)
+}
+
+// xScalar is a Mysqlx.Datatypes.Scalar value.
+type xScalar struct {
+	typ         uint64
+	signed      int64
+	unsigned    uint64
+	double      float64
+	boolean     bool
+	octets      []byte
+	contentType uint64
+}
+
+func parseXScalar(data []byte) (*xScalar, error) {
+	fields, err := parseXMessage(data)
+	if err != nil {
+		return nil, err
+	}
+	s := &xScalar{}
+	for _, f := range fields {
+		switch f.num {
+		case 1:
+			s.typ = f.varint
+		case 2:
+			s.signed = protowire.DecodeZigZag(f.varint)
+		case 3:
+			s.unsigned = f.varint
+		case 5, 9:
+			// Octets and String both have their value in their first field, followed by
+			// the content type of the octets or the collation of the string.
+			inner, err := parseXMessage(f.bytes)
+			if err != nil {
+				return nil, err
+			}
+			for _, in := range inner {
+				switch in.num {
+				case 1:
+					s.octets = in.bytes
+				case 2:
+					if f.num == 5 {
+						s.contentType = in.varint
+					}
+				}
+			}
+		case 6:
+			s.double = math.Float64frombits(f.varint)
+		case 7:
+			s.double = float64(math.Float32frombits(uint32(f.varint)))
+		case 8:
+			s.boolean = f.varint != 0
+		}
+	}
+	return s, nil
+}
+
+func appendXScalar(b []byte, s *xScalar) []byte {
+	b = protowire.AppendTag(b, 1, protowire.VarintType)
+	b = protowire.AppendVarint(b, s.typ)
+	switch s.typ {
+	case xScalarSint:
+		b = protowire.AppendTag(b, 2, protowire.VarintType)
+		b = protowire.AppendVarint(b, protowire.EncodeZigZag(s.signed))
+	case xScalarUint:
+		b = protowire.AppendTag(b, 3, protowire.VarintType)
+		b = protowire.AppendVarint(b, s.unsigned)
+	case xScalarOctets:
+		var octets []byte
+		octets = protowire.AppendTag(octets, 1, protowire.BytesType)
+		octets = protowire.AppendBytes(octets, s.octets)
+		b = protowire.AppendTag(b, 5, protowire.BytesType)
+		b = protowire.AppendBytes(b, octets)
+	case xScalarDouble:
+		b = protowire.AppendTag(b, 6, protowire.Fixed64Type)
+		b = protowire.AppendFixed64(b, math.Float64bits(s.double))
+	case xScalarBool:
+		b = protowire.AppendTag(b, 8, protowire.VarintType)
+		b = protowire.AppendVarint(b, protowire.EncodeBool(s.boolean))
+	case xScalarString:
+		var str []byte
+		str = protowire.AppendTag(str, 1, protowire.BytesType)
+		str = protowire.AppendBytes(str, s.octets)
+		b = protowire.AppendTag(b, 9, protowire.BytesType)
+		b = protowire.AppendBytes(b, str)
+	}
+	return b
+}
+
+// xAny is a Mysqlx.Datatypes.Any value: a scalar, an object or an array.
+type xAny struct {
+	scalar *xScalar
+	object []xObjectField
+	array  []*xAny
+}
+
+type xObjectField struct {
+	key   string
+	value *xAny
+}
+
+func parseXAny(data []byte) (*xAny, error) {
+	fields, err := parseXMessage(data)
+	if err != nil {
+		return nil, err
+	}
+	a := &xAny{}
+	for _, f := range fields {
+		switch f.num {
+		case 2:
+			if a.scalar, err = parseXScalar(f.bytes); err != nil {
+				return nil, err
+			}
+		case 3:
+			object, err := parseXMessage(f.bytes)
+			if err != nil {
+				return nil, err
+			}
+			// An object is always set, even if it has no fields.
+			a.object = []xObjectField{}
+			for _, fld := range object {
+				field, err := parseXMessage(fld.bytes)
+				if err != nil {
+					return nil, err
+				}
+				var of xObjectField
+				for _, ff := range field {
+					switch ff.num {
+					case 1:
+						of.key = string(ff.bytes)
+					case 2:
+						if of.value, err = parseXAny(ff.bytes); err != nil {
+							return nil, err
+						}
+					}
+				}
+				a.object = append(a.object, of)
+			}
+		case 4:
+			array, err := parseXMessage(f.bytes)
+			if err != nil {
+				return nil, err
+			}
+			a.array = []*xAny{}
+			for _, v := range array {
+				value, err := parseXAny(v.bytes)
+				if err != nil {
+					return nil, err
+				}
+				a.array = append(a.array, value)
+			}
+		}
+	}
+	return a, nil
+}
+
+// field returns the value of a field of an object, or nil.
+func (a *xAny) field(key string) *xAny {
+	for _, f := range a.object {
+		if f.key == key {
+			return f.value
+		}
+	}
+	return nil
+}
+
+// str returns the value of a string or octets scalar.
+func (a *xAny) str() (string, bool) {
+	if a == nil || a.scalar == nil || (a.scalar.typ != xScalarString && a.scalar.typ != xScalarOctets) {
+		return "", false
+	}
+	return string(a.scalar.octets), true
+}
+
+func appendXAnyScalar(b []byte, s *xScalar) []byte {
+	b = protowire.AppendTag(b, 1, protowire.VarintType)
+	b = protowire.AppendVarint(b, xAnyScalar)
+	b = protowire.AppendTag(b, 2, protowire.BytesType)
+	return protowire.AppendBytes(b, appendXScalar(nil, s))
+}
+
+func appendXAnyArray(b []byte, values []*xScalar) []byte {
+	var array []byte
+	for _, v := range values {
+		array = protowire.AppendTag(array, 1, protowire.BytesType)
+		array = protowire.AppendBytes(array, appendXAnyScalar(nil, v))
+	}
+	b = protowire.AppendTag(b, 1, protowire.VarintType)
+	b = protowire.AppendVarint(b, xAnyArray)
+	b = protowire.AppendTag(b, 4, protowire.BytesType)
+	return protowire.AppendBytes(b, array)
+}
+
+func xString(s string) *xScalar {
+	return &xScalar{typ: xScalarString, octets: []byte(s)}
+}
+
+// encodeXError encodes a Mysqlx.Error from an error. The errors that are not a
+// SQLError are returned as unknown errors, as by the MySQL protocol.
+func encodeXError(err error, fatal bool) []byte {
+	sqlErr, ok := sqlerror.NewSQLErrorFromError(err).(*sqlerror.SQLError)
+	if !ok {
+		sqlErr = sqlerror.NewSQLError(sqlerror.ERUnknownError, sqlerror.SSUnknownSQLState, "unknown error: %v", err)
+	}
+	var b []byte
+	if fatal {
+		b = protowire.AppendTag(b, 1, protowire.VarintType)
+		b = protowire.AppendVarint(b, 1)
+	}
+	b = protowire.AppendTag(b, 2, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(sqlErr.Number()))
+	b = protowire.AppendTag(b, 3, protowire.BytesType)
+	b = protowire.AppendString(b, sqlErr.Message)
+	b = protowire.AppendTag(b, 4, protowire.BytesType)
+	return protowire.AppendString(b, sqlErr.SQLState())
+}
+
+// encodeXOk encodes a Mysqlx.Ok with an optional message.
+func encodeXOk(msg string) []byte {
+	if msg == "" {
+		return nil
+	}
+	b := protowire.AppendTag(nil, 1, protowire.BytesType)
+	return protowire.AppendString(b, msg)
+}
+
+// The parameters of the session state changed notices.
+const (
+	xNoticeGeneratedInsertID    = 3
+	xNoticeRowsAffected         = 4
+	xNoticeClientIDAssigned     = 7
+	xNoticeProducedMessage      = 10
+	xNoticeGeneratedDocumentIDs = 12
+)
+
+// encodeXSessionStateChanged encodes a Mysqlx.Notice.Frame with a local SessionStateChanged notice.
+func encodeXSessionStateChanged(param uint64, values ...*xScalar) []byte {
+	var notice []byte
+	notice = protowire.AppendTag(notice, 1, protowire.VarintType)
+	notice = protowire.AppendVarint(notice, param)
+	for _, v := range values {
+		notice = protowire.AppendTag(notice, 2, protowire.BytesType)
+		notice = protowire.AppendBytes(notice, appendXScalar(nil, v))
+	}
+
+	var frame []byte
+	// SESSION_STATE_CHANGED
+	frame = protowire.AppendTag(frame, 1, protowire.VarintType)
+	frame = protowire.AppendVarint(frame, 3)
+	// LOCAL
+	frame = protowire.AppendTag(frame, 2, protowire.VarintType)
+	frame = protowire.AppendVarint(frame, 2)
+	frame = protowire.AppendTag(frame, 3, protowire.BytesType)
+	return protowire.AppendBytes(frame, notice)
+}
+
+// encodeXCapabilities encodes a Mysqlx.Connection.Capabilities.
+func encodeXCapabilities(names []string, values [][]byte) []byte {
+	var b []byte
+	for i, name := range names {
+		var capability []byte
+		capability = protowire.AppendTag(capability, 1, protowire.BytesType)
+		capability = protowire.AppendString(capability, name)
+		capability = protowire.AppendTag(capability, 2, protowire.BytesType)
+		capability = protowire.AppendBytes(capability, values[i])
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendBytes(b, capability)
+	}
+	return b
+}
+
+// parseXCapabilitiesSet returns the capabilities of a Mysqlx.Connection.CapabilitiesSet.
+func parseXCapabilitiesSet(data []byte) ([]xObjectField, error) {
+	fields, err := parseXMessage(data)
+	if err != nil {
+		return nil, err
+	}
+	var capabilities []xObjectField
+	for _, f := range fields {
+		if f.num != 1 {
+			continue
+		}
+		caps, err := parseXMessage(f.bytes)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range caps {
+			if c.num != 1 {
+				continue
+			}
+			capability, err := parseXMessage(c.bytes)
+			if err != nil {
+				return nil, err
+			}
+			var of xObjectField
+			for _, cf := range capability {
+				switch cf.num {
+				case 1:
+					of.key = string(cf.bytes)
+				case 2:
+					if of.value, err = parseXAny(cf.bytes); err != nil {
+						return nil, err
+					}
+				}
+			}
+			capabilities = append(capabilities, of)
+		}
+	}
+	return capabilities, nil
+}
+
+// parseXBytesField returns the value of a length-delimited field of a message.
+func parseXBytesField(data []byte, num protowire.Number) ([]byte, error) {
+	fields, err := parseXMessage(data)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range fields {
+		if f.num == num {
+			return f.bytes, nil
+		}
+	}
+	return nil, nil
+}
+
+// xStmtExecute is a Mysqlx.Sql.StmtExecute.
+type xStmtExecute struct {
+	namespace string
+	stmt      string
+	args      []*xAny
+}
+
+func parseXStmtExecute(data []byte) (*xStmtExecute, error) {
+	fields, err := parseXMessage(data)
+	if err != nil {
+		return nil, err
+	}
+	stmt := &xStmtExecute{namespace: "sql"}
+	for _, f := range fields {
+		switch f.num {
+		case 1:
+			stmt.stmt = string(f.bytes)
+		case 2:
+			arg, err := parseXAny(f.bytes)
+			if err != nil {
+				return nil, err
+			}
+			stmt.args = append(stmt.args, arg)
+		case 3:
+			stmt.namespace = string(f.bytes)
+		}
+	}
+	return stmt, nil
+}
+
+func (s *xScalar) String() string {
+	switch s.typ {
+	case xScalarSint:
+		return fmt.Sprintf("%d", s.signed)
+	case xScalarUint:
+		return fmt.Sprintf("%d", s.unsigned)
+	case xScalarNull:
+		return "NULL"
+	case xScalarDouble, xScalarFloat:
+		return fmt.Sprintf("%v", s.double)
+	case xScalarBool:
+		return fmt.Sprintf("%v", s.boolean)
+	default:
+		return string(s.octets)
+	}
+}
+
+// appendXBytesField appends a length-delimited field to a message.
+func appendXBytesField(b []byte, num protowire.Number, value []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, value)
+}
+
+// appendXVarintField appends a varint field to a message.
+func appendXVarintField(b []byte, num protowire.Number, value uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, value)
+}
+
+// The types of the columns of Mysqlx.Resultset.ColumnMetaData.
+const (
+	xColumnSint     = 1
+	xColumnUint     = 2
+	xColumnDouble   = 5
+	xColumnFloat    = 6
+	xColumnBytes    = 7
+	xColumnTime     = 10
+	xColumnDatetime = 12
+	xColumnSet      = 15
+	xColumnEnum     = 16
+	xColumnBit      = 17
+	xColumnDecimal  = 18
+)
+
+// The flags of Mysqlx.Resultset.ColumnMetaData that are common to all the types.
+const (
+	xColumnFlagNotNull       = 0x0010
+	xColumnFlagPrimaryKey    = 0x0020
+	xColumnFlagUniqueKey     = 0x0040
+	xColumnFlagMultipleKey   = 0x0080
+	xColumnFlagAutoIncrement = 0x0100
+)
+
+// xColumnType returns the X Protocol type and content type of a column.
+func xColumnType(typ querypb.Type) (uint64, uint64) {
+	switch {
+	case sqltypes.IsSigned(typ):
+		return xColumnSint, 0
+	case sqltypes.IsUnsigned(typ), typ == sqltypes.Year:
+		return xColumnUint, 0
+	}
+	switch typ {
+	case sqltypes.Float32:
+		return xColumnFloat, 0
+	case sqltypes.Float64:
+		return xColumnDouble, 0
+	case sqltypes.Decimal:
+		return xColumnDecimal, 0
+	case sqltypes.Timestamp, sqltypes.Datetime, sqltypes.Date:
+		return xColumnDatetime, 0
+	case sqltypes.Time:
+		return xColumnTime, 0
+	case sqltypes.Enum:
+		return xColumnEnum, 0
+	case sqltypes.Set:
+		return xColumnSet, 0
+	case sqltypes.Bit:
+		return xColumnBit, 0
+	case sqltypes.TypeJSON:
+		return xColumnBytes, xContentTypeJSON
+	case sqltypes.Geometry:
+		return xColumnBytes, xContentTypeGeometry
+	default:
+		return xColumnBytes, 0
+	}
+}
+
+// encodeXColumnMetaData encodes a Mysqlx.Resultset.ColumnMetaData from a field.
+func encodeXColumnMetaData(field *querypb.Field) []byte {
+	typ, contentType := xColumnType(field.Type)
+	b := appendXVarintField(nil, 1, typ)
+	b = appendXBytesField(b, 2, []byte(field.Name))
+	b = appendXBytesField(b, 3, []byte(field.OrgName))
+	b = appendXBytesField(b, 4, []byte(field.Table))
+	b = appendXBytesField(b, 5, []byte(field.OrgTable))
+	b = appendXBytesField(b, 6, []byte(field.Database))
+	b = appendXBytesField(b, 7, []byte("def"))
+	if typ == xColumnBytes || typ == xColumnEnum || typ == xColumnSet {
+		b = appendXVarintField(b, 8, uint64(field.Charset))
+	}
+	if typ == xColumnDouble || typ == xColumnFloat || typ == xColumnDecimal || typ == xColumnTime || typ == xColumnDatetime {
+		b = appendXVarintField(b, 9, uint64(field.Decimals))
+	}
+	b = appendXVarintField(b, 10, uint64(field.ColumnLength))
+
+	var flags uint64
+	if field.Flags&uint32(querypb.MySqlFlag_NOT_NULL_FLAG) != 0 {
+		flags |= xColumnFlagNotNull
+	}
+	if field.Flags&uint32(querypb.MySqlFlag_PRI_KEY_FLAG) != 0 {
+		flags |= xColumnFlagPrimaryKey
+	}
+	if field.Flags&uint32(querypb.MySqlFlag_UNIQUE_KEY_FLAG) != 0 {
+		flags |= xColumnFlagUniqueKey
+	}
+	if field.Flags&uint32(querypb.MySqlFlag_MULTIPLE_KEY_FLAG) != 0 {
+		flags |= xColumnFlagMultipleKey
+	}
+	if field.Flags&uint32(querypb.MySqlFlag_AUTO_INCREMENT_FLAG) != 0 {
+		flags |= xColumnFlagAutoIncrement
+	}
+	// The first flag depends on the type: zerofill for the integers, unsigned for the
+	// floating point numbers and the timestamp type for the dates.
+	switch typ {
+	case xColumnUint:
+		if field.Flags&uint32(querypb.MySqlFlag_ZEROFILL_FLAG) != 0 {
+			flags |= 0x0001
+		}
+	case xColumnDouble, xColumnFloat, xColumnDecimal:
+		if field.Flags&uint32(querypb.MySqlFlag_UNSIGNED_FLAG) != 0 {
+			flags |= 0x0001
+		}
+	case xColumnDatetime:
+		if field.Type == sqltypes.Timestamp {
+			flags |= 0x0001
+		}
+	}
+	if flags != 0 {
+		b = appendXVarintField(b, 11, flags)
+	}
+	if contentType != 0 {
+		b = appendXVarintField(b, 12, contentType)
+	}
+	return b
+}
+
+// encodeXRow encodes a Mysqlx.Resultset.Row, with the binary encoding of the X Protocol
+// for the values of each type. The NULL values are empty fields.
+func encodeXRow(fields []*querypb.Field, row []sqltypes.Value) ([]byte, error) {
+	var b []byte
+	for i, v := range row {
+		var value []byte
+		if !v.IsNull() {
+			var err error
+			if value, err = encodeXValue(fields[i].Type, v); err != nil {
+				return nil, err
+			}
+		}
+		b = appendXBytesField(b, 1, value)
+	}
+	return b, nil
+}
+
+func encodeXValue(typ querypb.Type, v sqltypes.Value) ([]byte, error) {
+	raw := v.Raw()
+	xtyp, _ := xColumnType(typ)
+	switch xtyp {
+	case xColumnSint:
+		n, err := strconv.ParseInt(string(raw), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return protowire.AppendVarint(nil, protowire.EncodeZigZag(n)), nil
+	case xColumnUint:
+		n, err := strconv.ParseUint(string(raw), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return protowire.AppendVarint(nil, n), nil
+	case xColumnDouble:
+		f, err := strconv.ParseFloat(string(raw), 64)
+		if err != nil {
+			return nil, err
+		}
+		return binary.LittleEndian.AppendUint64(nil, math.Float64bits(f)), nil
+	case xColumnFloat:
+		f, err := strconv.ParseFloat(string(raw), 32)
+		if err != nil {
+			return nil, err
+		}
+		return binary.LittleEndian.AppendUint32(nil, math.Float32bits(float32(f))), nil
+	case xColumnDecimal:
+		return encodeXDecimal(raw)
+	case xColumnDatetime:
+		return encodeXDatetime(raw, typ == sqltypes.Date)
+	case xColumnTime:
+		return encodeXTime(raw)
+	case xColumnBit:
+		var n uint64
+		for _, c := range raw {
+			n = n<<8 | uint64(c)
+		}
+		return protowire.AppendVarint(nil, n), nil
+	case xColumnSet:
+		if len(raw) == 0 {
+			// The empty set is a single 0x01 byte, to tell it apart from a set with an empty element.
+			return []byte{0x01}, nil
+		}
+		var b []byte
+		for _, element := range bytes.Split(raw, []byte{','}) {
+			b = protowire.AppendBytes(b, element)
+		}
+		return b, nil
+	default:
+		// The bytes and the enums are followed by a zero, to tell apart the empty strings from NULL.
+		return append(append([]byte{}, raw...), 0), nil
+	}
+}
+
+// encodeXDecimal encodes a decimal as its scale followed by its digits in BCD, and its sign.
+func encodeXDecimal(raw []byte) ([]byte, error) {
+	sign := byte(0xc)
+	if len(raw) > 0 && (raw[0] == '-' || raw[0] == '+') {
+		if raw[0] == '-' {
+			sign = 0xd
+		}
+		raw = raw[1:]
+	}
+	var scale byte
+	digits := make([]byte, 0, len(raw)+1)
+	for i, c := range raw {
+		switch {
+		case c == '.':
+			scale = byte(len(raw) - i - 1)
+		case c >= '0' && c <= '9':
+			digits = append(digits, c-'0')
+		default:
+			return nil, fmt.Errorf("invalid decimal value %q", raw)
+		}
+	}
+	digits = append(digits, sign)
+	if len(digits)%2 != 0 {
+		digits = append(digits, 0)
+	}
+	b := []byte{scale}
+	for i := 0; i < len(digits); i += 2 {
+		b = append(b, digits[i]<<4|digits[i+1])
+	}
+	return b, nil
+}
+
+// encodeXDatetime encodes a date or a datetime as the varints of its parts.
+func encodeXDatetime(raw []byte, date bool) ([]byte, error) {
+	var parts []uint64
+	for _, part := range bytes.FieldsFunc(raw, func(r rune) bool { return r == '-' || r == ' ' || r == ':' || r == 'T' }) {
+		if i := bytes.IndexByte(part, '.'); i >= 0 {
+			n, err := strconv.ParseUint(string(part[:i]), 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			usec, err := xMicroseconds(part[i+1:])
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, n, usec)
+			continue
+		}
+		n, err := strconv.ParseUint(string(part), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, n)
+	}
+	if len(parts) < 3 || (date && len(parts) != 3) {
+		return nil, fmt.Errorf("invalid datetime value %q", raw)
+	}
+	// The trailing microseconds are omitted when they are zero.
+	if len(parts) == 7 && parts[6] == 0 {
+		parts = parts[:6]
+	}
+	var b []byte
+	for _, part := range parts {
+		b = protowire.AppendVarint(b, part)
+	}
+	return b, nil
+}
+
+// encodeXTime encodes a time as its sign and the varints of its parts.
+func encodeXTime(raw []byte) ([]byte, error) {
+	b := []byte{0x00}
+	if len(raw) > 0 && raw[0] == '-' {
+		b[0] = 0x01
+		raw = raw[1:]
+	}
+	var usec uint64
+	if i := bytes.IndexByte(raw, '.'); i >= 0 {
+		var err error
+		if usec, err = xMicroseconds(raw[i+1:]); err != nil {
+			return nil, err
+		}
+		raw = raw[:i]
+	}
+	parts := bytes.Split(raw, []byte{':'})
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid time value %q", raw)
+	}
+	for _, part := range parts {
+		n, err := strconv.ParseUint(string(part), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		b = protowire.AppendVarint(b, n)
+	}
+	if usec != 0 {
+		b = protowire.AppendVarint(b, usec)
+	}
+	return b, nil
+}
+
+// xMicroseconds returns the microseconds of the fractional part of a time.
+func xMicroseconds(frac []byte) (uint64, error) {
+	if len(frac) > 6 {
+		frac = frac[:6]
+	}
+	usec, err := strconv.ParseUint(string(frac), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	for i := len(frac); i < 6; i++ {
+		usec *= 10
+	}
+	return usec, nil
+}
@@ -0,0 +1,1233 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+)
+
+// This file translates the Mysqlx.Crud messages to SQL statements, as the X Plugin
+// of MySQL does. A collection is a table with a JSON doc column and an _id column
+// generated from the _id member of the documents, see xCollectionTable.
+
+// The data models of the Mysqlx.Crud messages.
+const (
+	xDataModelDocument = 1
+	xDataModelTable    = 2
+)
+
+// The types of Mysqlx.Expr.Expr.
+const (
+	xExprIdent       = 1
+	xExprLiteral     = 2
+	xExprVariable    = 3
+	xExprFuncCall    = 4
+	xExprOperator    = 5
+	xExprPlaceholder = 6
+	xExprObject      = 7
+	xExprArray       = 8
+)
+
+// The types of Mysqlx.Expr.DocumentPathItem.
+const (
+	xPathMember             = 1
+	xPathMemberAsterisk     = 2
+	xPathArrayIndex         = 3
+	xPathArrayIndexAsterisk = 4
+	xPathDoubleAsterisk     = 5
+)
+
+// The operations of Mysqlx.Crud.UpdateOperation.
+const (
+	xUpdateSet         = 1
+	xUpdateItemRemove  = 2
+	xUpdateItemSet     = 3
+	xUpdateItemReplace = 4
+	xUpdateItemMerge   = 5
+	xUpdateArrayInsert = 6
+	xUpdateArrayAppend = 7
+	xUpdateMergePatch  = 8
+)
+
+// xExpr is a Mysqlx.Expr.Expr.
+type xExpr struct {
+	typ        uint64
+	identifier *xColumnIdentifier
+	literal    *xScalar
+	function   *xOperator
+	operator   *xOperator
+	position   uint64
+	object     []xExprObjectField
+	array      []*xExpr
+}
+
+// xOperator is a Mysqlx.Expr.Operator or a Mysqlx.Expr.FunctionCall: a name and parameters.
+type xOperator struct {
+	name   string
+	schema string
+	params []*xExpr
+}
+
+type xExprObjectField struct {
+	key   string
+	value *xExpr
+}
+
+// xColumnIdentifier is a Mysqlx.Expr.ColumnIdentifier: a column and a path in its document.
+type xColumnIdentifier struct {
+	path   []xDocumentPathItem
+	name   string
+	table  string
+	schema string
+}
+
+type xDocumentPathItem struct {
+	typ   uint64
+	value string
+	index uint64
+}
+
+func parseXExpr(data []byte) (*xExpr, error) {
+	fields, err := parseXMessage(data)
+	if err != nil {
+		return nil, err
+	}
+	e := &xExpr{}
+	for _, f := range fields {
+		switch f.num {
+		case 1:
+			e.typ = f.varint
+		case 2:
+			if e.identifier, err = parseXColumnIdentifier(f.bytes); err != nil {
+				return nil, err
+			}
+		case 4:
+			if e.literal, err = parseXScalar(f.bytes); err != nil {
+				return nil, err
+			}
+		case 5:
+			if e.function, err = parseXFunctionCall(f.bytes); err != nil {
+				return nil, err
+			}
+		case 6:
+			if e.operator, err = parseXOperator(f.bytes); err != nil {
+				return nil, err
+			}
+		case 7:
+			e.position = f.varint
+		case 8:
+			if e.object, err = parseXExprObject(f.bytes); err != nil {
+				return nil, err
+			}
+		case 9:
+			values, err := parseXMessage(f.bytes)
+			if err != nil {
+				return nil, err
+			}
+			e.array = []*xExpr{}
+			for _, v := range values {
+				value, err := parseXExpr(v.bytes)
+				if err != nil {
+					return nil, err
+				}
+				e.array = append(e.array, value)
+			}
+		}
+	}
+	return e, nil
+}
+
+func parseXColumnIdentifier(data []byte) (*xColumnIdentifier, error) {
+	fields, err := parseXMessage(data)
+	if err != nil {
+		return nil, err
+	}
+	id := &xColumnIdentifier{}
+	for _, f := range fields {
+		switch f.num {
+		case 1:
+			item, err := parseXMessage(f.bytes)
+			if err != nil {
+				return nil, err
+			}
+			var it xDocumentPathItem
+			for _, i := range item {
+				switch i.num {
+				case 1:
+					it.typ = i.varint
+				case 2:
+					it.value = string(i.bytes)
+				case 3:
+					it.index = i.varint
+				}
+			}
+			id.path = append(id.path, it)
+		case 2:
+			id.name = string(f.bytes)
+		case 3:
+			id.table = string(f.bytes)
+		case 4:
+			id.schema = string(f.bytes)
+		}
+	}
+	return id, nil
+}
+
+func parseXFunctionCall(data []byte) (*xOperator, error) {
+	fields, err := parseXMessage(data)
+	if err != nil {
+		return nil, err
+	}
+	fn := &xOperator{}
+	for _, f := range fields {
+		switch f.num {
+		case 1:
+			name, err := parseXMessage(f.bytes)
+			if err != nil {
+				return nil, err
+			}
+			for _, n := range name {
+				switch n.num {
+				case 1:
+					fn.name = string(n.bytes)
+				case 2:
+					fn.schema = string(n.bytes)
+				}
+			}
+		case 2:
+			param, err := parseXExpr(f.bytes)
+			if err != nil {
+				return nil, err
+			}
+			fn.params = append(fn.params, param)
+		}
+	}
+	return fn, nil
+}
+
+func parseXOperator(data []byte) (*xOperator, error) {
+	fields, err := parseXMessage(data)
+	if err != nil {
+		return nil, err
+	}
+	op := &xOperator{}
+	for _, f := range fields {
+		switch f.num {
+		case 1:
+			op.name = string(f.bytes)
+		case 2:
+			param, err := parseXExpr(f.bytes)
+			if err != nil {
+				return nil, err
+			}
+			op.params = append(op.params, param)
+		}
+	}
+	return op, nil
+}
+
+func parseXExprObject(data []byte) ([]xExprObjectField, error) {
+	fields, err := parseXMessage(data)
+	if err != nil {
+		return nil, err
+	}
+	object := []xExprObjectField{}
+	for _, f := range fields {
+		field, err := parseXMessage(f.bytes)
+		if err != nil {
+			return nil, err
+		}
+		var of xExprObjectField
+		for _, ff := range field {
+			switch ff.num {
+			case 1:
+				of.key = string(ff.bytes)
+			case 2:
+				if of.value, err = parseXExpr(ff.bytes); err != nil {
+					return nil, err
+				}
+			}
+		}
+		object = append(object, of)
+	}
+	return object, nil
+}
+
+// xSQLBuilder builds the SQL expressions of a Crud message.
+type xSQLBuilder struct {
+	// document is true for the collections, and false for the tables.
+	document bool
+	// args are the values of the placeholders.
+	args []*xScalar
+}
+
+var xSimpleName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// path returns the JSON path of a document path.
+func (b *xSQLBuilder) path(items []xDocumentPathItem) string {
+	var sb strings.Builder
+	sb.WriteString("$")
+	for _, item := range items {
+		switch item.typ {
+		case xPathMember:
+			sb.WriteString(".")
+			if xSimpleName.MatchString(item.value) {
+				sb.WriteString(item.value)
+			} else {
+				sb.WriteString(strconv.Quote(item.value))
+			}
+		case xPathMemberAsterisk:
+			sb.WriteString(".*")
+		case xPathArrayIndex:
+			sb.WriteString("[" + strconv.FormatUint(item.index, 10) + "]")
+		case xPathArrayIndexAsterisk:
+			sb.WriteString("[*]")
+		case xPathDoubleAsterisk:
+			sb.WriteString("**")
+		}
+	}
+	return sb.String()
+}
+
+// column returns the SQL expression of a column identifier.
+func (b *xSQLBuilder) column(id *xColumnIdentifier) (string, error) {
+	if b.document && id.name == "" {
+		if len(id.path) == 0 {
+			return "doc", nil
+		}
+		return "json_extract(doc, " + sqltypes.EncodeStringSQL(b.path(id.path)) + ")", nil
+	}
+	if id.name == "" {
+		return "", sqlerror.NewSQLError(sqlerror.ERXExprBadValue, sqlerror.SSUnknownSQLState, "Invalid column name")
+	}
+	column := sqlescape.EscapeID(id.name)
+	if id.table != "" {
+		column = xTableName(id.schema, id.table) + "." + column
+	}
+	if len(id.path) > 0 {
+		return "json_extract(" + column + ", " + sqltypes.EncodeStringSQL(b.path(id.path)) + ")", nil
+	}
+	return column, nil
+}
+
+// placeholder returns the value of a placeholder.
+func (b *xSQLBuilder) placeholder(position uint64) (*xScalar, error) {
+	if position >= uint64(len(b.args)) {
+		return nil, sqlerror.NewSQLError(sqlerror.ERXCmdNumArguments, sqlerror.SSUnknownSQLState, "Invalid number of arguments, expected %d but got %d", position+1, len(b.args))
+	}
+	return b.args[position], nil
+}
+
+// expr returns the SQL of an expression.
+func (b *xSQLBuilder) expr(e *xExpr) (string, error) {
+	switch e.typ {
+	case xExprIdent:
+		if e.identifier == nil {
+			return "", sqlerror.NewSQLError(sqlerror.ERXExprMissingArg, sqlerror.SSUnknownSQLState, "Invalid value for Mysqlx::Expr::Expr_Type %d", e.typ)
+		}
+		return b.column(e.identifier)
+	case xExprLiteral:
+		if e.literal == nil {
+			return "", sqlerror.NewSQLError(sqlerror.ERXExprMissingArg, sqlerror.SSUnknownSQLState, "Invalid value for Mysqlx::Expr::Expr_Type %d", e.typ)
+		}
+		return xSQLLiteral(e.literal), nil
+	case xExprPlaceholder:
+		value, err := b.placeholder(e.position)
+		if err != nil {
+			return "", err
+		}
+		return xSQLLiteral(value), nil
+	case xExprFuncCall:
+		if e.function == nil || !xSimpleName.MatchString(e.function.name) {
+			return "", sqlerror.NewSQLError(sqlerror.ERXExprBadValue, sqlerror.SSUnknownSQLState, "Invalid function name")
+		}
+		params, err := b.exprs(e.function.params)
+		if err != nil {
+			return "", err
+		}
+		name := e.function.name
+		if e.function.schema != "" {
+			name = sqlescape.EscapeID(e.function.schema) + "." + sqlescape.EscapeID(name)
+		}
+		return name + "(" + strings.Join(params, ", ") + ")", nil
+	case xExprOperator:
+		if e.operator == nil {
+			return "", sqlerror.NewSQLError(sqlerror.ERXExprMissingArg, sqlerror.SSUnknownSQLState, "Invalid value for Mysqlx::Expr::Expr_Type %d", e.typ)
+		}
+		return b.operator(e.operator)
+	case xExprObject:
+		var items []string
+		for _, f := range e.object {
+			if f.value == nil {
+				return "", sqlerror.NewSQLError(sqlerror.ERXExprMissingArg, sqlerror.SSUnknownSQLState, "Invalid value for the member '%s'", f.key)
+			}
+			value, err := b.jsonValue(f.value)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, sqltypes.EncodeStringSQL(f.key), value)
+		}
+		return "json_object(" + strings.Join(items, ", ") + ")", nil
+	case xExprArray:
+		var items []string
+		for _, v := range e.array {
+			value, err := b.jsonValue(v)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, value)
+		}
+		return "json_array(" + strings.Join(items, ", ") + ")", nil
+	default:
+		return "", sqlerror.NewSQLError(sqlerror.ERXExprBadTypeValue, sqlerror.SSUnknownSQLState, "Invalid value for Mysqlx::Expr::Expr_Type %d", e.typ)
+	}
+}
+
+func (b *xSQLBuilder) exprs(exprs []*xExpr) ([]string, error) {
+	var sqls []string
+	for _, e := range exprs {
+		sql, err := b.expr(e)
+		if err != nil {
+			return nil, err
+		}
+		sqls = append(sqls, sql)
+	}
+	return sqls, nil
+}
+
+// jsonValue returns the SQL of an expression that is a value of a document. The JSON
+// octets are cast to JSON, so that they are not stored as strings in the documents.
+func (b *xSQLBuilder) jsonValue(e *xExpr) (string, error) {
+	value := e.literal
+	if e.typ == xExprPlaceholder {
+		var err error
+		if value, err = b.placeholder(e.position); err != nil {
+			return "", err
+		}
+	} else if e.typ != xExprLiteral {
+		return b.expr(e)
+	}
+	if value != nil && value.typ == xScalarOctets && value.contentType == xContentTypeJSON {
+		return "cast(" + sqltypes.EncodeStringSQL(string(value.octets)) + " as json)", nil
+	}
+	return b.expr(e)
+}
+
+// jsonOperand returns the SQL of an operand of a JSON function, which must be a JSON value.
+func (b *xSQLBuilder) jsonOperand(e *xExpr) (string, error) {
+	switch e.typ {
+	case xExprIdent, xExprObject, xExprArray:
+		return b.expr(e)
+	}
+	value, err := b.jsonValue(e)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(value, "cast(") {
+		return value, nil
+	}
+	return "cast(" + value + " as json)", nil
+}
+
+// xBinaryOperators are the operators that are the same in SQL, with their SQL name.
+var xBinaryOperators = map[string]string{
+	"==":     "=",
+	"!=":     "!=",
+	">":      ">",
+	">=":     ">=",
+	"<":      "<",
+	"<=":     "<=",
+	"&&":     "and",
+	"||":     "or",
+	"xor":    "xor",
+	"+":      "+",
+	"-":      "-",
+	"*":      "*",
+	"/":      "/",
+	"div":    "div",
+	"%":      "%",
+	"&":      "&",
+	"|":      "|",
+	"^":      "^",
+	"<<":     "<<",
+	">>":     ">>",
+	"is":     "is",
+	"is_not": "is not",
+}
+
+// xUnaryOperators are the unary operators, with their SQL name.
+var xUnaryOperators = map[string]string{
+	"!":          "not ",
+	"not":        "not ",
+	"sign_plus":  "+",
+	"sign_minus": "-",
+	"~":          "~",
+}
+
+var xIntervalUnits = map[string]bool{
+	"MICROSECOND": true, "SECOND": true, "MINUTE": true, "HOUR": true, "DAY": true, "WEEK": true, "MONTH": true,
+	"QUARTER": true, "YEAR": true, "SECOND_MICROSECOND": true, "MINUTE_MICROSECOND": true, "MINUTE_SECOND": true,
+	"HOUR_MICROSECOND": true, "HOUR_SECOND": true, "HOUR_MINUTE": true, "DAY_MICROSECOND": true, "DAY_SECOND": true,
+	"DAY_MINUTE": true, "DAY_HOUR": true, "YEAR_MONTH": true,
+}
+
+var xCastTypes = regexp.MustCompile(`^(?i)(binary|char|date|datetime|decimal|json|signed|time|unsigned)( ?\([0-9, ]+\))?( integer)?$`)
+
+func errXExprNumArgs(op string) error {
+	return sqlerror.NewSQLError(sqlerror.ERXExprBadNumArgs, sqlerror.SSUnknownSQLState, "Invalid number of arguments for operator '%s'", op)
+}
+
+// operator returns the SQL of an operator.
+func (b *xSQLBuilder) operator(op *xOperator) (string, error) {
+	name := strings.ToLower(op.name)
+	if sql, ok := xBinaryOperators[name]; ok {
+		if name == "*" && len(op.params) == 0 {
+			return "*", nil
+		}
+		if len(op.params) != 2 {
+			return "", errXExprNumArgs(op.name)
+		}
+		params, err := b.exprs(op.params)
+		if err != nil {
+			return "", err
+		}
+		return "(" + params[0] + " " + sql + " " + params[1] + ")", nil
+	}
+	if sql, ok := xUnaryOperators[name]; ok {
+		if len(op.params) != 1 {
+			return "", errXExprNumArgs(op.name)
+		}
+		param, err := b.expr(op.params[0])
+		if err != nil {
+			return "", err
+		}
+		return "(" + sql + param + ")", nil
+	}
+
+	switch name {
+	case "like", "not_like", "regexp", "not_regexp":
+		if len(op.params) != 2 && !(strings.HasSuffix(name, "like") && len(op.params) == 3) {
+			return "", errXExprNumArgs(op.name)
+		}
+		params, err := b.exprs(op.params)
+		if err != nil {
+			return "", err
+		}
+		// The members of the documents are JSON strings, which are compared without their quotes.
+		if op.params[0].typ == xExprIdent && len(op.params[0].identifier.path) > 0 {
+			params[0] = "json_unquote(" + params[0] + ")"
+		}
+		sql := "(" + params[0] + " " + strings.ReplaceAll(name, "_", " ") + " " + params[1]
+		if len(params) == 3 {
+			sql += " escape " + params[2]
+		}
+		return sql + ")", nil
+	case "in", "not_in":
+		if len(op.params) < 2 {
+			return "", errXExprNumArgs(op.name)
+		}
+		params, err := b.exprs(op.params)
+		if err != nil {
+			return "", err
+		}
+		return "(" + params[0] + " " + strings.ReplaceAll(name, "_", " ") + " (" + strings.Join(params[1:], ", ") + "))", nil
+	case "cont_in", "not_cont_in":
+		if len(op.params) != 2 {
+			return "", errXExprNumArgs(op.name)
+		}
+		value, err := b.jsonOperand(op.params[0])
+		if err != nil {
+			return "", err
+		}
+		container, err := b.jsonOperand(op.params[1])
+		if err != nil {
+			return "", err
+		}
+		sql := "json_contains(" + container + ", " + value + ")"
+		if name == "not_cont_in" {
+			sql = "not " + sql
+		}
+		return "(" + sql + ")", nil
+	case "between", "not_between":
+		if len(op.params) != 3 {
+			return "", errXExprNumArgs(op.name)
+		}
+		params, err := b.exprs(op.params)
+		if err != nil {
+			return "", err
+		}
+		return "(" + params[0] + " " + strings.ReplaceAll(name, "_", " ") + " " + params[1] + " and " + params[2] + ")", nil
+	case "date_add", "date_sub":
+		if len(op.params) != 3 {
+			return "", errXExprNumArgs(op.name)
+		}
+		params, err := b.exprs(op.params[:2])
+		if err != nil {
+			return "", err
+		}
+		unit := op.params[2]
+		if unit.typ != xExprLiteral || unit.literal == nil || !xIntervalUnits[strings.ToUpper(string(unit.literal.octets))] {
+			return "", sqlerror.NewSQLError(sqlerror.ERXExprBadValue, sqlerror.SSUnknownSQLState, "Invalid value for interval unit")
+		}
+		return name + "(" + params[0] + ", interval " + params[1] + " " + strings.ToUpper(string(unit.literal.octets)) + ")", nil
+	case "cast":
+		if len(op.params) != 2 {
+			return "", errXExprNumArgs(op.name)
+		}
+		value, err := b.expr(op.params[0])
+		if err != nil {
+			return "", err
+		}
+		typ := op.params[1]
+		if typ.typ != xExprLiteral || typ.literal == nil || !xCastTypes.MatchString(string(typ.literal.octets)) {
+			return "", sqlerror.NewSQLError(sqlerror.ERXExprBadValue, sqlerror.SSUnknownSQLState, "Invalid cast type")
+		}
+		return "cast(" + value + " as " + string(typ.literal.octets) + ")", nil
+	default:
+		return "", sqlerror.NewSQLError(sqlerror.ERXExprBadOperator, sqlerror.SSUnknownSQLState, "Invalid operator %s", op.name)
+	}
+}
+
+// xCrud are the parts of the Crud messages that are common to several messages.
+type xCrud struct {
+	schema    string
+	name      string
+	dataModel uint64
+	criteria  *xExpr
+	limit     []xField
+	limitExpr []byte
+	order     [][]byte
+	args      []*xScalar
+}
+
+// parseField parses a common field of a Crud message, from the field numbers of
+// the message. It returns false if the field is not one of the common fields.
+func (crud *xCrud) parseField(f xField, collection, dataModel, criteria, limit, order, args int) (bool, error) {
+	var err error
+	switch int(f.num) {
+	case collection:
+		fields, err := parseXMessage(f.bytes)
+		if err != nil {
+			return true, err
+		}
+		for _, cf := range fields {
+			switch cf.num {
+			case 1:
+				crud.name = string(cf.bytes)
+			case 2:
+				crud.schema = string(cf.bytes)
+			}
+		}
+	case dataModel:
+		crud.dataModel = f.varint
+	case criteria:
+		crud.criteria, err = parseXExpr(f.bytes)
+	case limit:
+		crud.limit, err = parseXMessage(f.bytes)
+	case order:
+		crud.order = append(crud.order, f.bytes)
+	case args:
+		var arg *xScalar
+		if arg, err = parseXScalar(f.bytes); err == nil {
+			crud.args = append(crud.args, arg)
+		}
+	default:
+		return false, nil
+	}
+	return true, err
+}
+
+func (crud *xCrud) builder() (*xSQLBuilder, error) {
+	if crud.name == "" {
+		return nil, sqlerror.NewSQLError(sqlerror.ERXInvalidCollection, sqlerror.SSUnknownSQLState, "Invalid name of table/collection")
+	}
+	return &xSQLBuilder{document: crud.dataModel != xDataModelTable, args: crud.args}, nil
+}
+
+// where returns the WHERE, ORDER BY and LIMIT clauses of a Crud message.
+func (crud *xCrud) where(b *xSQLBuilder, allowOffset bool) (string, error) {
+	var sql string
+	if crud.criteria != nil {
+		criteria, err := b.expr(crud.criteria)
+		if err != nil {
+			return "", err
+		}
+		sql += " where " + criteria
+	}
+	order, err := crud.orderBy(b)
+	if err != nil {
+		return "", err
+	}
+	sql += order
+
+	var rowCount, offset string
+	for _, f := range crud.limit {
+		switch f.num {
+		case 1:
+			rowCount = strconv.FormatUint(f.varint, 10)
+		case 2:
+			offset = strconv.FormatUint(f.varint, 10)
+		}
+	}
+	if crud.limitExpr != nil {
+		fields, err := parseXMessage(crud.limitExpr)
+		if err != nil {
+			return "", err
+		}
+		for _, f := range fields {
+			e, err := parseXExpr(f.bytes)
+			if err != nil {
+				return "", err
+			}
+			value, err := b.expr(e)
+			if err != nil {
+				return "", err
+			}
+			switch f.num {
+			case 1:
+				rowCount = value
+			case 2:
+				offset = value
+			}
+		}
+	}
+	if offset != "" && offset != "0" && !allowOffset {
+		return "", sqlerror.NewSQLError(sqlerror.ERXInvalidProtocolData, sqlerror.SSUnknownSQLState, "Invalid parameter: non-zero offset value not allowed for this operation")
+	}
+	if rowCount != "" {
+		if offset != "" && allowOffset {
+			sql += " limit " + offset + ", " + rowCount
+		} else {
+			sql += " limit " + rowCount
+		}
+	}
+	return sql, nil
+}
+
+func (crud *xCrud) orderBy(b *xSQLBuilder) (string, error) {
+	var items []string
+	for _, o := range crud.order {
+		fields, err := parseXMessage(o)
+		if err != nil {
+			return "", err
+		}
+		var item string
+		desc := false
+		for _, f := range fields {
+			switch f.num {
+			case 1:
+				e, err := parseXExpr(f.bytes)
+				if err != nil {
+					return "", err
+				}
+				if item, err = b.expr(e); err != nil {
+					return "", err
+				}
+			case 2:
+				desc = f.varint == 2
+			}
+		}
+		if desc {
+			item += " desc"
+		} else {
+			item += " asc"
+		}
+		items = append(items, item)
+	}
+	if len(items) == 0 {
+		return "", nil
+	}
+	return " order by " + strings.Join(items, ", "), nil
+}
+
+// xFindSQL returns the SELECT statement of a Mysqlx.Crud.Find.
+func xFindSQL(payload []byte) (string, error) {
+	fields, err := parseXMessage(payload)
+	if err != nil {
+		return "", err
+	}
+	crud := &xCrud{}
+	var projections, grouping [][]byte
+	var groupingCriteria []byte
+	var locking, lockingOptions uint64
+	for _, f := range fields {
+		ok, err := crud.parseField(f, 2, 3, 5, 6, 7, 11)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			continue
+		}
+		switch f.num {
+		case 4:
+			projections = append(projections, f.bytes)
+		case 8:
+			grouping = append(grouping, f.bytes)
+		case 9:
+			groupingCriteria = f.bytes
+		case 12:
+			locking = f.varint
+		case 13:
+			lockingOptions = f.varint
+		case 14:
+			crud.limitExpr = f.bytes
+		}
+	}
+	b, err := crud.builder()
+	if err != nil {
+		return "", err
+	}
+
+	var columns []string
+	for _, p := range projections {
+		projection, err := parseXMessage(p)
+		if err != nil {
+			return "", err
+		}
+		var source *xExpr
+		var alias string
+		for _, f := range projection {
+			switch f.num {
+			case 1:
+				if source, err = parseXExpr(f.bytes); err != nil {
+					return "", err
+				}
+			case 2:
+				alias = string(f.bytes)
+			}
+		}
+		if source == nil {
+			return "", sqlerror.NewSQLError(sqlerror.ERXExprMissingArg, sqlerror.SSUnknownSQLState, "Invalid projection")
+		}
+		column, err := b.expr(source)
+		if err != nil {
+			return "", err
+		}
+		if b.document {
+			// The members of the projected documents are named by their alias, or by the last member of their path.
+			if alias == "" && source.typ == xExprIdent && len(source.identifier.path) > 0 {
+				alias = source.identifier.path[len(source.identifier.path)-1].value
+			}
+			if alias == "" {
+				return "", sqlerror.NewSQLError(sqlerror.ERXExprMissingArg, sqlerror.SSUnknownSQLState, "Invalid projection target name")
+			}
+			columns = append(columns, sqltypes.EncodeStringSQL(alias), column)
+		} else if alias != "" {
+			columns = append(columns, column+" as "+sqlescape.EscapeID(alias))
+		} else {
+			columns = append(columns, column)
+		}
+	}
+
+	sql := "select "
+	switch {
+	case b.document && len(columns) > 0:
+		sql += "json_object(" + strings.Join(columns, ", ") + ") as doc"
+	case b.document:
+		sql += "doc"
+	case len(columns) > 0:
+		sql += strings.Join(columns, ", ")
+	default:
+		sql += "*"
+	}
+	sql += " from " + xTableName(crud.schema, crud.name)
+
+	if crud.criteria != nil {
+		criteria, err := b.expr(crud.criteria)
+		if err != nil {
+			return "", err
+		}
+		sql += " where " + criteria
+	}
+	if len(grouping) > 0 {
+		var groups []string
+		for _, g := range grouping {
+			e, err := parseXExpr(g)
+			if err != nil {
+				return "", err
+			}
+			group, err := b.expr(e)
+			if err != nil {
+				return "", err
+			}
+			groups = append(groups, group)
+		}
+		sql += " group by " + strings.Join(groups, ", ")
+	}
+	if groupingCriteria != nil {
+		e, err := parseXExpr(groupingCriteria)
+		if err != nil {
+			return "", err
+		}
+		having, err := b.expr(e)
+		if err != nil {
+			return "", err
+		}
+		sql += " having " + having
+	}
+	// The criteria are in the WHERE clause already.
+	crud.criteria = nil
+	tail, err := crud.where(b, true)
+	if err != nil {
+		return "", err
+	}
+	sql += tail
+
+	switch locking {
+	case 1:
+		sql += " for share"
+	case 2:
+		sql += " for update"
+	}
+	if locking != 0 {
+		switch lockingOptions {
+		case 1:
+			sql += " nowait"
+		case 2:
+			sql += " skip locked"
+		}
+	}
+	return sql, nil
+}
+
+// xInsertSQL returns the INSERT statement of a Mysqlx.Crud.Insert, and the _id of
+// the documents that are inserted without one, which are generated by newID.
+func xInsertSQL(payload []byte, newID func() string) (string, []string, error) {
+	fields, err := parseXMessage(payload)
+	if err != nil {
+		return "", nil, err
+	}
+	crud := &xCrud{}
+	var columns, rows [][]byte
+	upsert := false
+	for _, f := range fields {
+		ok, err := crud.parseField(f, 1, 2, 0, 0, 0, 5)
+		if err != nil {
+			return "", nil, err
+		}
+		if ok {
+			continue
+		}
+		switch f.num {
+		case 3:
+			columns = append(columns, f.bytes)
+		case 4:
+			rows = append(rows, f.bytes)
+		case 6:
+			upsert = f.varint != 0
+		}
+	}
+	b, err := crud.builder()
+	if err != nil {
+		return "", nil, err
+	}
+	if len(rows) == 0 {
+		return "", nil, sqlerror.NewSQLError(sqlerror.ERXExprMissingArg, sqlerror.SSUnknownSQLState, "Missing row data for Insert")
+	}
+
+	var names []string
+	if b.document {
+		if len(columns) > 0 {
+			return "", nil, sqlerror.NewSQLError(sqlerror.ERXBadMessage, sqlerror.SSUnknownSQLState, "Invalid projection for document operation")
+		}
+		names = []string{"doc"}
+	}
+	for _, c := range columns {
+		column, err := parseXMessage(c)
+		if err != nil {
+			return "", nil, err
+		}
+		for _, f := range column {
+			if f.num == 1 {
+				names = append(names, sqlescape.EscapeID(string(f.bytes)))
+			}
+		}
+	}
+
+	var ids []string
+	var values []string
+	for _, r := range rows {
+		row, err := parseXMessage(r)
+		if err != nil {
+			return "", nil, err
+		}
+		var exprs []*xExpr
+		for _, f := range row {
+			if f.num != 1 {
+				continue
+			}
+			e, err := parseXExpr(f.bytes)
+			if err != nil {
+				return "", nil, err
+			}
+			exprs = append(exprs, e)
+		}
+		if len(names) > 0 && len(exprs) != len(names) {
+			return "", nil, sqlerror.NewSQLError(sqlerror.ERXBadMessage, sqlerror.SSUnknownSQLState, "Wrong number of fields in row being inserted")
+		}
+
+		var items []string
+		if b.document {
+			doc, id, err := b.insertDocument(exprs[0], newID)
+			if err != nil {
+				return "", nil, err
+			}
+			if id != "" {
+				ids = append(ids, id)
+			}
+			items = []string{doc}
+		} else if items, err = b.exprs(exprs); err != nil {
+			return "", nil, err
+		}
+		values = append(values, "("+strings.Join(items, ", ")+")")
+	}
+
+	sql := "insert into " + xTableName(crud.schema, crud.name)
+	if len(names) > 0 {
+		sql += " (" + strings.Join(names, ", ") + ")"
+	}
+	sql += " values " + strings.Join(values, ", ")
+	if upsert {
+		if !b.document {
+			return "", nil, sqlerror.NewSQLError(sqlerror.ERXBadMessage, sqlerror.SSUnknownSQLState, "Unable update on duplicate key for TABLE data model")
+		}
+		sql += " on duplicate key update doc = values(doc)"
+	}
+	return sql, ids, nil
+}
+
+// insertDocument returns the SQL of a document that is inserted, with a new _id if
+// the document does not have one, and the new _id.
+func (b *xSQLBuilder) insertDocument(e *xExpr, newID func() string) (string, string, error) {
+	if e.typ == xExprPlaceholder {
+		value, err := b.placeholder(e.position)
+		if err != nil {
+			return "", "", err
+		}
+		e = &xExpr{typ: xExprLiteral, literal: value}
+	}
+
+	switch e.typ {
+	case xExprObject:
+		for _, f := range e.object {
+			if f.key == "_id" {
+				doc, err := b.expr(e)
+				return doc, "", err
+			}
+		}
+		id := newID()
+		object := append([]xExprObjectField{{key: "_id", value: &xExpr{typ: xExprLiteral, literal: xString(id)}}}, e.object...)
+		doc, err := b.expr(&xExpr{typ: xExprObject, object: object})
+		return doc, id, err
+	case xExprLiteral:
+		if e.literal == nil || (e.literal.typ != xScalarOctets && e.literal.typ != xScalarString) {
+			return "", "", sqlerror.NewSQLError(sqlerror.ERXBadMessage, sqlerror.SSUnknownSQLState, "Invalid document")
+		}
+		var doc map[string]json.RawMessage
+		if err := json.Unmarshal(e.literal.octets, &doc); err != nil {
+			return "", "", sqlerror.NewSQLError(sqlerror.ERXBadMessage, sqlerror.SSUnknownSQLState, "Invalid JSON document: %v", err)
+		}
+		literal := "cast(" + sqltypes.EncodeStringSQL(string(e.literal.octets)) + " as json)"
+		if _, ok := doc["_id"]; ok {
+			return literal, "", nil
+		}
+		id := newID()
+		return "json_insert(" + literal + ", '$._id', " + sqltypes.EncodeStringSQL(id) + ")", id, nil
+	default:
+		doc, err := b.expr(e)
+		if err != nil {
+			return "", "", err
+		}
+		id := newID()
+		return "json_insert(" + doc + ", '$._id', " + sqltypes.EncodeStringSQL(id) + ")", id, nil
+	}
+}
+
+// xUpdateSQL returns the UPDATE statement of a Mysqlx.Crud.Update.
+func xUpdateSQL(payload []byte) (string, error) {
+	fields, err := parseXMessage(payload)
+	if err != nil {
+		return "", err
+	}
+	crud := &xCrud{}
+	var operations [][]byte
+	for _, f := range fields {
+		ok, err := crud.parseField(f, 2, 3, 4, 5, 6, 8)
+		if err != nil {
+			return "", err
+		}
+		if !ok && f.num == 7 {
+			operations = append(operations, f.bytes)
+		}
+		if !ok && f.num == 9 {
+			crud.limitExpr = f.bytes
+		}
+	}
+	b, err := crud.builder()
+	if err != nil {
+		return "", err
+	}
+	if len(operations) == 0 {
+		return "", sqlerror.NewSQLError(sqlerror.ERXBadUpdateData, sqlerror.SSUnknownSQLState, "Invalid update expression list")
+	}
+
+	// The operations on a column are nested, in their order, in a single assignment.
+	var columns []string
+	assignments := map[string]string{}
+	for _, o := range operations {
+		fields, err := parseXMessage(o)
+		if err != nil {
+			return "", err
+		}
+		var source *xColumnIdentifier
+		var operation uint64
+		var value *xExpr
+		for _, f := range fields {
+			switch f.num {
+			case 1:
+				if source, err = parseXColumnIdentifier(f.bytes); err != nil {
+					return "", err
+				}
+			case 2:
+				operation = f.varint
+			case 3:
+				if value, err = parseXExpr(f.bytes); err != nil {
+					return "", err
+				}
+			}
+		}
+		if source == nil {
+			source = &xColumnIdentifier{}
+		}
+
+		column := "doc"
+		if !b.document {
+			if source.name == "" {
+				return "", sqlerror.NewSQLError(sqlerror.ERXBadUpdateData, sqlerror.SSUnknownSQLState, "Invalid column name to update")
+			}
+			column = sqlescape.EscapeID(source.name)
+		} else if source.name != "" || operation == xUpdateSet {
+			return "", sqlerror.NewSQLError(sqlerror.ERXBadUpdateData, sqlerror.SSUnknownSQLState, "Invalid column name to update")
+		}
+		path := b.path(source.path)
+		if b.document && len(source.path) > 0 && source.path[0].typ == xPathMember && source.path[0].value == "_id" {
+			return "", sqlerror.NewSQLError(sqlerror.ERXBadMemberToUpdate, sqlerror.SSUnknownSQLState, "Forbidden update operation on '$._id' member")
+		}
+		current, ok := assignments[column]
+		if !ok {
+			current = column
+			columns = append(columns, column)
+		}
+
+		var assignment string
+		switch operation {
+		case xUpdateSet:
+			if value == nil {
+				return "", sqlerror.NewSQLError(sqlerror.ERXBadUpdateData, sqlerror.SSUnknownSQLState, "Invalid update expression list")
+			}
+			if len(source.path) > 0 {
+				return "", sqlerror.NewSQLError(sqlerror.ERXBadMemberToUpdate, sqlerror.SSUnknownSQLState, "Invalid member location")
+			}
+			assignment, err = b.expr(value)
+		case xUpdateItemRemove:
+			if len(source.path) == 0 {
+				return "", sqlerror.NewSQLError(sqlerror.ERXBadMemberToUpdate, sqlerror.SSUnknownSQLState, "Invalid member location")
+			}
+			assignment = "json_remove(" + current + ", " + sqltypes.EncodeStringSQL(path) + ")"
+		case xUpdateItemSet, xUpdateItemReplace, xUpdateArrayInsert, xUpdateArrayAppend:
+			if value == nil || len(source.path) == 0 {
+				return "", sqlerror.NewSQLError(sqlerror.ERXBadMemberToUpdate, sqlerror.SSUnknownSQLState, "Invalid member location")
+			}
+			function := map[uint64]string{
+				xUpdateItemSet:     "json_set",
+				xUpdateItemReplace: "json_replace",
+				xUpdateArrayInsert: "json_array_insert",
+				xUpdateArrayAppend: "json_array_append",
+			}[operation]
+			var v string
+			if v, err = b.jsonValue(value); err == nil {
+				assignment = function + "(" + current + ", " + sqltypes.EncodeStringSQL(path) + ", " + v + ")"
+			}
+		case xUpdateItemMerge, xUpdateMergePatch:
+			if value == nil {
+				return "", sqlerror.NewSQLError(sqlerror.ERXBadUpdateData, sqlerror.SSUnknownSQLState, "Invalid update expression list")
+			}
+			function := "json_merge_preserve"
+			if operation == xUpdateMergePatch {
+				function = "json_merge_patch"
+			}
+			var v string
+			if v, err = b.jsonOperand(value); err == nil {
+				assignment = function + "(" + current + ", " + v + ")"
+				if b.document {
+					// The _id of the documents can not be changed by a merge.
+					assignment = "json_set(" + assignment + ", '$._id', json_extract(doc, '$._id'))"
+				}
+			}
+		default:
+			return "", sqlerror.NewSQLError(sqlerror.ERXBadUpdateData, sqlerror.SSUnknownSQLState, "Invalid type of update operation for document")
+		}
+		if err != nil {
+			return "", err
+		}
+		assignments[column] = assignment
+	}
+
+	var sets []string
+	for _, column := range columns {
+		sets = append(sets, column+" = "+assignments[column])
+	}
+	tail, err := crud.where(b, false)
+	if err != nil {
+		return "", err
+	}
+	return "update " + xTableName(crud.schema, crud.name) + " set " + strings.Join(sets, ", ") + tail, nil
+}
+
+// xDeleteSQL returns the DELETE statement of a Mysqlx.Crud.Delete.
+func xDeleteSQL(payload []byte) (string, error) {
+	fields, err := parseXMessage(payload)
+	if err != nil {
+		return "", err
+	}
+	crud := &xCrud{}
+	for _, f := range fields {
+		ok, err := crud.parseField(f, 1, 2, 3, 4, 5, 6)
+		if err != nil {
+			return "", err
+		}
+		if !ok && f.num == 7 {
+			crud.limitExpr = f.bytes
+		}
+	}
+	b, err := crud.builder()
+	if err != nil {
+		return "", err
+	}
+	tail, err := crud.where(b, false)
+	if err != nil {
+		return "", err
+	}
+	return "delete from " + xTableName(crud.schema, crud.name) + tail, nil
+}
@@ -0,0 +1,765 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/netutil"
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/tb"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// The authentication mechanisms of the X Protocol.
+const (
+	xAuthMySQL41 = "MYSQL41"
+	xAuthPlain   = "PLAIN"
+)
+
+// xMaxAuthAttempts is the number of authentications a client can try before
+// its connection is closed. The connectors try several mechanisms in turn.
+const xMaxAuthAttempts = 3
+
+// xConn is the state of a connection to an X Protocol listener. The queries go
+// through the Handler of the listener with the Conn, as for the MySQL protocol,
+// but the messages are read and written with the X Protocol framing.
+type xConn struct {
+	c *Conn
+	l *Listener
+	r *bufio.Reader
+	w *bufio.Writer
+}
+
+// handleX is called in a go routine for each client connection of an X Protocol listener.
+func (l *Listener) handleX(conn net.Conn, connectionID uint32, acceptTime time.Time) {
+	if l.connReadTimeout != 0 || l.connWriteTimeout != 0 {
+		conn = netutil.NewConnWithTimeouts(conn, l.connReadTimeout, l.connWriteTimeout)
+	}
+	c := newServerConn(conn, l)
+	c.ConnectionID = connectionID
+	x := &xConn{c: c, l: l}
+	x.reset()
+
+	// Catch panics, and close the connection in any case.
+	defer func() {
+		if x := recover(); x != nil {
+			log.Errorf("mysqlx_server caught panic:\n%v\n%s", x, tb.Stack(4))
+		}
+		c.conn.Close()
+	}()
+
+	// Tell the handler about the connection coming and going.
+	l.handler.NewConnection(c)
+	defer l.handler.ConnectionClosed(c)
+
+	// Adjust the count of open connections
+	defer connCount.Add(-1)
+
+	if !x.authenticate() {
+		return
+	}
+
+	if con, ok := c.conn.(*tls.Conn); ok {
+		tlsVerStr := tlsVersionToString(con.ConnectionState().Version)
+		if tlsVerStr != "" {
+			connCountByTLSVer.Add(tlsVerStr, 1)
+			defer connCountByTLSVer.Add(tlsVerStr, -1)
+		}
+	} else {
+		connCountByTLSVer.Add(versionNoTLS, 1)
+		defer connCountByTLSVer.Add(versionNoTLS, -1)
+	}
+	if c.User != "" {
+		connCountPerUser.Add(c.User, 1)
+		defer connCountPerUser.Add(c.User, -1)
+	}
+
+	// Record how long we took to establish the connection
+	timings.Record(connectTimingKey, acceptTime)
+
+	// Log a warning if it took too long to connect
+	connectTime := time.Since(acceptTime).Nanoseconds()
+	if threshold := l.SlowConnectWarnThreshold.Load(); threshold != 0 && connectTime > threshold {
+		connSlow.Add(1)
+		log.Warningf("Slow connection from %s: %v", c, connectTime)
+	}
+
+	l.handler.ConnectionReady(c)
+
+	for {
+		kontinue := x.handleNextMessage()
+		if !kontinue || c.IsMarkedForClose() {
+			return
+		}
+	}
+}
+
+// reset creates the reader and the writer of the connection, after it is
+// accepted or upgraded to TLS.
+func (x *xConn) reset() {
+	x.r = bufio.NewReaderSize(x.c.conn, connBufferSize)
+	x.w = bufio.NewWriterSize(x.c.conn, connBufferSize)
+}
+
+func (x *xConn) write(typ byte, payload []byte) error {
+	return writeXMessage(x.w, typ, payload)
+}
+
+func (x *xConn) writeOk(msg string) error {
+	return x.write(xServerOk, encodeXOk(msg))
+}
+
+func (x *xConn) writeError(err error, fatal bool) error {
+	return x.write(xServerError, encodeXError(err, fatal))
+}
+
+// authenticate negotiates the capabilities of the connection and authenticates the client.
+// It returns false if the connection must be closed.
+func (x *xConn) authenticate() bool {
+	attempts := 0
+	for {
+		typ, payload, err := readXMessage(x.r)
+		if err != nil {
+			if err != io.EOF {
+				log.Infof("Cannot read X Protocol message from %s: %v, it may not be a valid X Protocol client", x.c, err)
+			}
+			return false
+		}
+
+		done := false
+		switch typ {
+		case xClientConCapabilitiesGet:
+			err = x.write(xServerConCapabilities, x.capabilities())
+		case xClientConCapabilitiesSet:
+			var upgrade bool
+			upgrade, err = x.setCapabilities(payload)
+			if err == nil && upgrade {
+				err = x.upgradeTLS()
+			}
+		case xClientSessAuthenticateStart:
+			if x.l.RequireSecureTransport && !x.c.TLSEnabled() {
+				x.writeError(vterrors.Errorf(vtrpc.Code_UNAVAILABLE, "server does not allow insecure connections, client must use SSL/TLS"), true)
+				x.w.Flush()
+				return false
+			}
+			done, err = x.authenticateStart(payload)
+			attempts++
+			if !done && err == nil && attempts >= xMaxAuthAttempts {
+				x.w.Flush()
+				return false
+			}
+		case xClientConClose:
+			x.writeOk("bye!")
+			x.w.Flush()
+			return false
+		default:
+			err = x.writeError(sqlerror.NewSQLError(sqlerror.ERXBadMessage, sqlerror.SSUnknownSQLState, "Unexpected message received"), false)
+		}
+		if err == nil {
+			err = x.w.Flush()
+		}
+		if err != nil {
+			log.Errorf("Error during X Protocol handshake with %s: %v", x.c, err)
+			return false
+		}
+		if done {
+			return true
+		}
+	}
+}
+
+// capabilities returns the Mysqlx.Connection.Capabilities of the listener.
+func (x *xConn) capabilities() []byte {
+	mechanisms := []*xScalar{xString(xAuthMySQL41)}
+	if x.c.TLSEnabled() || x.l.AllowClearTextWithoutTLS.Load() {
+		mechanisms = append(mechanisms, xString(xAuthPlain))
+	}
+
+	var names []string
+	var values [][]byte
+	if x.l.TLSConfig.Load() != nil {
+		names = append(names, "tls")
+		values = append(values, appendXAnyScalar(nil, &xScalar{typ: xScalarBool, boolean: x.c.TLSEnabled()}))
+	}
+	names = append(names, "authentication.mechanisms", "doc.formats", "node_type", "client.pwd_expire_ok", "client.interactive")
+	values = append(values,
+		appendXAnyArray(nil, mechanisms),
+		appendXAnyScalar(nil, xString("text")),
+		appendXAnyScalar(nil, xString("mysql")),
+		appendXAnyScalar(nil, &xScalar{typ: xScalarBool}),
+		appendXAnyScalar(nil, &xScalar{typ: xScalarBool}),
+	)
+	return encodeXCapabilities(names, values)
+}
+
+// setCapabilities handles a Mysqlx.Connection.CapabilitiesSet. It returns true if
+// the client asked for TLS, after the Ok is written.
+func (x *xConn) setCapabilities(payload []byte) (bool, error) {
+	capabilities, err := parseXCapabilitiesSet(payload)
+	if err != nil {
+		return false, x.writeError(err, false)
+	}
+	upgrade := false
+	for _, capability := range capabilities {
+		switch capability.key {
+		case "tls":
+			if capability.value == nil || capability.value.scalar == nil || !capability.value.scalar.boolean {
+				continue
+			}
+			if x.l.TLSConfig.Load() == nil || x.c.TLSEnabled() {
+				return false, x.writeError(sqlerror.NewSQLError(sqlerror.ERXCapabilitiesPrepareFailed, sqlerror.SSUnknownSQLState, "Capability prepare failed for 'tls'"), false)
+			}
+			upgrade = true
+		case "client.pwd_expire_ok", "client.interactive", "session_connect_attrs":
+		default:
+			return false, x.writeError(sqlerror.NewSQLError(sqlerror.ERXCapabilityNotFound, sqlerror.SSUnknownSQLState, "Capability '%s' doesn't exist", capability.key), false)
+		}
+	}
+	return upgrade, x.writeOk("")
+}
+
+// upgradeTLS runs the TLS handshake, after the Ok of the CapabilitiesSet is written.
+func (x *xConn) upgradeTLS() error {
+	if err := x.w.Flush(); err != nil {
+		return err
+	}
+	conn := tls.Server(x.c.conn, x.l.TLSConfig.Load().(*tls.Config))
+	if err := conn.Handshake(); err != nil {
+		return vterrors.Wrapf(err, "TLS handshake error")
+	}
+	x.c.conn = conn
+	x.c.Capabilities |= CapabilityClientSSL
+	x.reset()
+	return nil
+}
+
+// authMethod returns the method of the auth server with the given name that handles the user.
+func (x *xConn) authMethod(name AuthMethodDescription, user string) AuthMethod {
+	for _, m := range x.l.authServer.AuthMethods() {
+		if m.Name() == name && m.HandleUser(x.c, user) {
+			return m
+		}
+	}
+	return nil
+}
+
+// authenticateStart handles a Mysqlx.Session.AuthenticateStart. It returns true if
+// the client is authenticated, and false with no error if the client can try again.
+func (x *xConn) authenticateStart(payload []byte) (bool, error) {
+	fields, err := parseXMessage(payload)
+	if err != nil {
+		return false, x.writeError(err, true)
+	}
+	var mechanism string
+	var authData []byte
+	for _, f := range fields {
+		switch f.num {
+		case 1:
+			mechanism = string(f.bytes)
+		case 2:
+			authData = f.bytes
+		}
+	}
+
+	var schema, user string
+	var getter Getter
+	switch mechanism {
+	case xAuthMySQL41:
+		schema, user, getter, err = x.authenticateMySQL41()
+	case xAuthPlain:
+		if !x.c.TLSEnabled() && !x.l.AllowClearTextWithoutTLS.Load() {
+			err = sqlerror.NewSQLError(sqlerror.ERNotSupportedAuthMode, sqlerror.SSUnknownSQLState, "Invalid authentication method %s", mechanism)
+			break
+		}
+		schema, user, getter, err = x.authenticatePlain(authData)
+	default:
+		err = sqlerror.NewSQLError(sqlerror.ERNotSupportedAuthMode, sqlerror.SSUnknownSQLState, "Invalid authentication method %s", mechanism)
+	}
+	if err != nil {
+		if _, ok := err.(*sqlerror.SQLError); !ok {
+			return false, err
+		}
+		log.Warningf("Error authenticating user %s using: %s", user, mechanism)
+		return false, x.writeError(err, false)
+	}
+
+	x.c.User = user
+	x.c.UserData = getter
+
+	// Set initial db name.
+	if schema != "" {
+		x.c.schemaName = schema
+		err = x.l.handler.ComQuery(x.c, "use "+sqlescape.EscapeID(schema), func(result *sqltypes.Result) error {
+			return nil
+		})
+		if err != nil {
+			x.writeError(err, true)
+			x.w.Flush()
+			return false, err
+		}
+	}
+
+	if err := x.write(xServerNotice, encodeXSessionStateChanged(xNoticeClientIDAssigned, &xScalar{typ: xScalarUint, unsigned: uint64(x.c.ConnectionID)})); err != nil {
+		return false, err
+	}
+	return true, x.write(xServerSessAuthenticateOk, nil)
+}
+
+// authenticateMySQL41 runs the MYSQL41 challenge-response, which uses the scramble of mysql_native_password.
+func (x *xConn) authenticateMySQL41() (string, string, Getter, error) {
+	var native AuthMethod
+	for _, m := range x.l.authServer.AuthMethods() {
+		if m.Name() == MysqlNativePassword {
+			native = m
+			break
+		}
+	}
+	if native == nil {
+		return "", "", nil, sqlerror.NewSQLError(sqlerror.ERNotSupportedAuthMode, sqlerror.SSUnknownSQLState, "Invalid authentication method %s", xAuthMySQL41)
+	}
+	salt, err := native.AuthPluginData()
+	if err != nil {
+		return "", "", nil, err
+	}
+	// The salt is sent without the trailing zero of the plugin data.
+	continuation := appendXBytesField(nil, 1, salt[:len(salt)-1])
+	if err := x.write(xServerSessAuthenticateContinue, continuation); err != nil {
+		return "", "", nil, err
+	}
+	if err := x.w.Flush(); err != nil {
+		return "", "", nil, err
+	}
+
+	typ, payload, err := readXMessage(x.r)
+	if err != nil {
+		return "", "", nil, err
+	}
+	if typ != xClientSessAuthenticateContinue {
+		return "", "", nil, sqlerror.NewSQLError(sqlerror.ERXBadMessage, sqlerror.SSUnknownSQLState, "Unexpected message received")
+	}
+	authData, err := parseXBytesField(payload, 1)
+	if err != nil {
+		return "", "", nil, err
+	}
+	schema, user, response, err := parseXAuthData(authData)
+	if err != nil {
+		return "", "", nil, err
+	}
+	// The scramble is in hexadecimal, after a '*', and is empty for an empty password.
+	var scramble []byte
+	if len(response) > 0 {
+		if response[0] != '*' {
+			return "", "", nil, sqlerror.NewSQLError(sqlerror.ERAccessDeniedError, sqlerror.SSAccessDeniedError, "Access denied for user '%v'", user)
+		}
+		if scramble, err = hex.DecodeString(string(response[1:])); err != nil {
+			return "", "", nil, sqlerror.NewSQLError(sqlerror.ERAccessDeniedError, sqlerror.SSAccessDeniedError, "Access denied for user '%v'", user)
+		}
+	}
+	if !native.HandleUser(x.c, user) {
+		return "", "", nil, sqlerror.NewSQLError(sqlerror.ERAccessDeniedError, sqlerror.SSAccessDeniedError, "Access denied for user '%v'", user)
+	}
+	getter, err := native.HandleAuthPluginData(x.c, user, salt, scramble, x.c.RemoteAddr())
+	return schema, user, getter, err
+}
+
+// authenticatePlain checks the password sent in clear text, with the clear text method
+// of the auth server if it handles the user, or with its scramble otherwise.
+func (x *xConn) authenticatePlain(authData []byte) (string, string, Getter, error) {
+	schema, user, password, err := parseXAuthData(authData)
+	if err != nil {
+		return "", "", nil, err
+	}
+	if m := x.authMethod(MysqlClearPassword, user); m != nil {
+		getter, err := m.HandleAuthPluginData(x.c, user, nil, append(password, 0), x.c.RemoteAddr())
+		return schema, user, getter, err
+	}
+	if m := x.authMethod(MysqlNativePassword, user); m != nil {
+		salt, err := m.AuthPluginData()
+		if err != nil {
+			return "", "", nil, err
+		}
+		scramble := ScrambleMysqlNativePassword(salt[:len(salt)-1], password)
+		getter, err := m.HandleAuthPluginData(x.c, user, salt, scramble, x.c.RemoteAddr())
+		return schema, user, getter, err
+	}
+	return "", "", nil, sqlerror.NewSQLError(sqlerror.ERAccessDeniedError, sqlerror.SSAccessDeniedError, "Access denied for user '%v'", user)
+}
+
+// parseXAuthData splits the authentication data of the X Protocol, which is the
+// schema, the user and the password or its scramble, separated by zeros.
+func parseXAuthData(data []byte) (string, string, []byte, error) {
+	parts := bytes.SplitN(data, []byte{0}, 3)
+	if len(parts) != 3 {
+		return "", "", nil, sqlerror.NewSQLError(sqlerror.ERXInvalidProtocolData, sqlerror.SSUnknownSQLState, "Invalid authentication data")
+	}
+	return string(parts[0]), string(parts[1]), parts[2], nil
+}
+
+// handleNextMessage reads and handles the next message of an authenticated client.
+// It returns false if the connection must be closed.
+func (x *xConn) handleNextMessage() bool {
+	typ, payload, err := readXMessage(x.r)
+	if err != nil {
+		if err != io.EOF {
+			log.Errorf("Error reading X Protocol message from %s: %v", x.c, err)
+		}
+		return false
+	}
+
+	// The connections that are not in a transaction are closed when the listener is shut down.
+	if x.l.shutdown.Load() && x.c.StatusFlags&ServerStatusInTrans == 0 && typ != xClientConClose && typ != xClientSessClose {
+		x.writeError(sqlerror.NewSQLError(sqlerror.ERServerShutdown, sqlerror.SSNetError, "Server shutdown in progress"), true)
+		x.w.Flush()
+		return false
+	}
+
+	kontinue := true
+	switch typ {
+	case xClientSQLStmtExecute:
+		err = x.handleStmtExecute(payload)
+	case xClientCrudFind, xClientCrudInsert, xClientCrudUpdate, xClientCrudDelete:
+		err = x.handleCrud(typ, payload)
+	case xClientExpectOpen, xClientExpectClose:
+		err = x.writeOk("")
+	case xClientConCapabilitiesGet:
+		err = x.write(xServerConCapabilities, x.capabilities())
+	case xClientSessReset:
+		x.l.handler.ComResetConnection(x.c)
+		err = x.writeOk("")
+	case xClientSessClose, xClientConClose:
+		err = x.writeOk("bye!")
+		kontinue = false
+	default:
+		err = x.writeError(sqlerror.NewSQLError(sqlerror.ERXBadMessage, sqlerror.SSUnknownSQLState, "Unexpected message received"), false)
+	}
+	if err == nil {
+		err = x.w.Flush()
+	}
+	if err != nil {
+		log.Errorf("Error writing X Protocol message to %s: %v", x.c, err)
+		return false
+	}
+	return kontinue
+}
+
+// handleStmtExecute handles a Mysqlx.Sql.StmtExecute, a SQL statement or an admin command.
+func (x *xConn) handleStmtExecute(payload []byte) error {
+	stmt, err := parseXStmtExecute(payload)
+	if err != nil {
+		return x.writeError(err, false)
+	}
+
+	var query string
+	switch stmt.namespace {
+	case "sql", "":
+		query, err = xBindArgs(stmt.stmt, stmt.args)
+	case "mysqlx", "xplugin":
+		if stmt.stmt == "ping" {
+			return x.write(xServerSQLStmtExecuteOk, nil)
+		}
+		query, err = xAdminCommand(stmt.stmt, stmt.args)
+	default:
+		err = sqlerror.NewSQLError(sqlerror.ERXInvalidNamespace, sqlerror.SSUnknownSQLState, "Unknown namespace %s", stmt.namespace)
+	}
+	if err != nil {
+		return x.writeError(err, false)
+	}
+	return x.execute(query, nil)
+}
+
+// handleCrud handles a Mysqlx.Crud message, by translating it to a SQL statement.
+func (x *xConn) handleCrud(typ byte, payload []byte) error {
+	var query string
+	var documentIDs []string
+	var err error
+	switch typ {
+	case xClientCrudFind:
+		query, err = xFindSQL(payload)
+	case xClientCrudInsert:
+		query, documentIDs, err = xInsertSQL(payload, x.l.nextXDocumentID)
+	case xClientCrudUpdate:
+		query, err = xUpdateSQL(payload)
+	case xClientCrudDelete:
+		query, err = xDeleteSQL(payload)
+	}
+	if err != nil {
+		return x.writeError(err, false)
+	}
+	return x.execute(query, documentIDs)
+}
+
+// execute runs a query through the handler and writes its result set, its
+// notices and the StmtExecuteOk. The errors of the query are written to the
+// client, only the errors of the connection are returned.
+func (x *xConn) execute(query string, documentIDs []string) error {
+	var fields []*querypb.Field
+	var rowsAffected, insertID uint64
+	var writeErr error
+	err := x.l.handler.ComQuery(x.c, query, func(qr *sqltypes.Result) error {
+		if fields == nil && len(qr.Fields) > 0 {
+			fields = qr.Fields
+			for _, field := range fields {
+				if writeErr = x.write(xServerColumnMetaData, encodeXColumnMetaData(field)); writeErr != nil {
+					return writeErr
+				}
+			}
+		}
+		for _, row := range qr.Rows {
+			payload, err := encodeXRow(fields, row)
+			if err != nil {
+				return err
+			}
+			if writeErr = x.write(xServerRow, payload); writeErr != nil {
+				return writeErr
+			}
+		}
+		rowsAffected += qr.RowsAffected
+		if qr.InsertID != 0 {
+			insertID = qr.InsertID
+		}
+		return nil
+	})
+	if writeErr != nil {
+		return writeErr
+	}
+	if err != nil {
+		return x.writeError(err, false)
+	}
+
+	if fields != nil {
+		if err := x.write(xServerFetchDone, nil); err != nil {
+			return err
+		}
+	}
+	if err := x.write(xServerNotice, encodeXSessionStateChanged(xNoticeRowsAffected, &xScalar{typ: xScalarUint, unsigned: rowsAffected})); err != nil {
+		return err
+	}
+	if insertID != 0 {
+		if err := x.write(xServerNotice, encodeXSessionStateChanged(xNoticeGeneratedInsertID, &xScalar{typ: xScalarUint, unsigned: insertID})); err != nil {
+			return err
+		}
+	}
+	if len(documentIDs) > 0 {
+		ids := make([]*xScalar, len(documentIDs))
+		for i, id := range documentIDs {
+			ids[i] = &xScalar{typ: xScalarOctets, octets: []byte(id)}
+		}
+		if err := x.write(xServerNotice, encodeXSessionStateChanged(xNoticeGeneratedDocumentIDs, ids...)); err != nil {
+			return err
+		}
+	}
+	return x.write(xServerSQLStmtExecuteOk, nil)
+}
+
+// nextXDocumentID returns a new _id for a document inserted without one. As in MySQL, the ids
+// are a prefix, the start time of the listener and a serial, in hexadecimal, so that they are
+// unique and increasing for a listener. The prefix is random, to tell apart the listeners.
+func (l *Listener) nextXDocumentID() string {
+	return fmt.Sprintf("%04x%08x%016x", l.xDocumentIDPrefix, l.xDocumentIDStart, l.xDocumentIDSerial.Add(1))
+}
+
+// xSQLLiteral returns a scalar as a SQL literal.
+func xSQLLiteral(s *xScalar) string {
+	switch s.typ {
+	case xScalarSint:
+		return strconv.FormatInt(s.signed, 10)
+	case xScalarUint:
+		return strconv.FormatUint(s.unsigned, 10)
+	case xScalarNull:
+		return "null"
+	case xScalarDouble, xScalarFloat:
+		return strconv.FormatFloat(s.double, 'g', -1, 64)
+	case xScalarBool:
+		if s.boolean {
+			return "true"
+		}
+		return "false"
+	case xScalarOctets:
+		if s.contentType == xContentTypeJSON {
+			return "cast(" + sqltypes.EncodeStringSQL(string(s.octets)) + " as json)"
+		}
+		return sqltypes.EncodeStringSQL(string(s.octets))
+	default:
+		return sqltypes.EncodeStringSQL(string(s.octets))
+	}
+}
+
+// xBindArgs replaces the '?' placeholders of a SQL statement with its arguments. The
+// placeholders in the strings, the quoted identifiers and the comments are left as is.
+func xBindArgs(query string, args []*xAny) (string, error) {
+	var b strings.Builder
+	next := 0
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		switch {
+		case ch == '\'' || ch == '"' || ch == '`':
+			end := i + 1
+			for end < len(query) && query[end] != ch {
+				if query[end] == '\\' && ch != '`' {
+					end++
+				}
+				end++
+			}
+			if end >= len(query) {
+				end = len(query) - 1
+			}
+			b.WriteString(query[i : end+1])
+			i = end
+		case ch == '#' || (ch == '-' && strings.HasPrefix(query[i:], "-- ")):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query) - i - 1
+			}
+			b.WriteString(query[i : i+end+1])
+			i += end
+		case ch == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				end = len(query) - i - 4
+			}
+			b.WriteString(query[i : i+end+4])
+			i += end + 3
+		case ch == '?':
+			if next >= len(args) {
+				return "", sqlerror.NewSQLError(sqlerror.ERXCmdNumArguments, sqlerror.SSUnknownSQLState, "Too few arguments")
+			}
+			arg := args[next]
+			if arg.scalar == nil {
+				return "", sqlerror.NewSQLError(sqlerror.ERXCmdArgumentType, sqlerror.SSUnknownSQLState, "Invalid argument type for the placeholder %d", next)
+			}
+			b.WriteString(xSQLLiteral(arg.scalar))
+			next++
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	if next != len(args) {
+		return "", sqlerror.NewSQLError(sqlerror.ERXCmdNumArguments, sqlerror.SSUnknownSQLState, "Too many arguments")
+	}
+	return b.String(), nil
+}
+
+// xAdminArg returns a string argument of an admin command, which is either a field of
+// an object, in the recent connectors, or a positional argument, in the older ones.
+func xAdminArg(args []*xAny, key string, pos int, required bool) (string, error) {
+	var value *xAny
+	if len(args) == 1 && args[0].object != nil {
+		value = args[0].field(key)
+	} else if pos < len(args) {
+		value = args[pos]
+	}
+	if value == nil {
+		if required {
+			return "", sqlerror.NewSQLError(sqlerror.ERXCmdNumArguments, sqlerror.SSUnknownSQLState, "Invalid number of arguments, expected value for '%s'", key)
+		}
+		return "", nil
+	}
+	if value.scalar != nil && (value.scalar.typ == xScalarSint || value.scalar.typ == xScalarUint) {
+		return value.scalar.String(), nil
+	}
+	str, ok := value.str()
+	if !ok {
+		return "", sqlerror.NewSQLError(sqlerror.ERXCmdArgumentType, sqlerror.SSUnknownSQLState, "Invalid type for argument '%s'", key)
+	}
+	return str, nil
+}
+
+// xCollectionTable is the definition of the table of a collection.
+const xCollectionTable = "(doc json, _id varbinary(32) generated always as (json_unquote(json_extract(doc, '$._id'))) stored not null, primary key (_id))"
+
+// xAdminCommand returns the SQL statement of an admin command of the mysqlx namespace.
+func xAdminCommand(command string, args []*xAny) (string, error) {
+	switch command {
+	case "create_collection", "ensure_collection", "drop_collection":
+		schema, err := xAdminArg(args, "schema", 0, false)
+		if err != nil {
+			return "", err
+		}
+		name, err := xAdminArg(args, "name", 1, true)
+		if err != nil {
+			return "", err
+		}
+		if name == "" {
+			return "", sqlerror.NewSQLError(sqlerror.ERXInvalidCollection, sqlerror.SSUnknownSQLState, "Invalid collection name")
+		}
+		table := xTableName(schema, name)
+		switch command {
+		case "create_collection":
+			return "create table " + table + " " + xCollectionTable, nil
+		case "ensure_collection":
+			return "create table if not exists " + table + " " + xCollectionTable, nil
+		default:
+			return "drop table " + table, nil
+		}
+	case "list_objects":
+		schema, err := xAdminArg(args, "schema", 0, false)
+		if err != nil {
+			return "", err
+		}
+		pattern, err := xAdminArg(args, "pattern", 1, false)
+		if err != nil {
+			return "", err
+		}
+		query := "select t.table_name as `name`, case when t.table_type = 'VIEW' then 'VIEW' " +
+			"when (select count(*) from information_schema.columns as c where c.table_schema = t.table_schema and c.table_name = t.table_name and c.column_name in ('doc', '_id')) = 2 then 'COLLECTION' " +
+			"else 'TABLE' end as `type` from information_schema.tables as t where t.table_schema = "
+		if schema != "" {
+			query += sqltypes.EncodeStringSQL(schema)
+		} else {
+			query += "database()"
+		}
+		if pattern != "" {
+			query += " and t.table_name like " + sqltypes.EncodeStringSQL(pattern)
+		}
+		return query + " order by t.table_name", nil
+	case "kill_client":
+		id, err := xAdminArg(args, "id", 0, true)
+		if err != nil {
+			return "", err
+		}
+		if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+			return "", sqlerror.NewSQLError(sqlerror.ERXCmdArgumentType, sqlerror.SSUnknownSQLState, "Invalid type for argument 'id'")
+		}
+		return "kill " + id, nil
+	default:
+		return "", sqlerror.NewSQLError(sqlerror.ERXInvalidAdminCommand, sqlerror.SSUnknownSQLState, "Invalid mysqlx command %s", command)
+	}
+}
+
+// xTableName returns the escaped name of a table, qualified with its schema if any.
+func xTableName(schema, name string) string {
+	if schema == "" {
+		return sqlescape.EscapeID(name)
+	}
+	return sqlescape.EscapeID(schema) + "." + sqlescape.EscapeID(name)
+}
@@ -0,0 +1,411 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"bufio"
+	"encoding/hex"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/sqltypes"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// xTestClient is a minimal X Protocol client.
+type xTestClient struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func newXTestClient(t *testing.T, l *Listener) *xTestClient {
+	conn, err := net.Dial("tcp", l.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return &xTestClient{t: t, conn: conn, r: bufio.NewReader(conn)}
+}
+
+func (c *xTestClient) send(typ byte, payload []byte) {
+	require.NoError(c.t, writeXMessage(c.conn, typ, payload))
+}
+
+func (c *xTestClient) read() (byte, []xField) {
+	typ, payload, err := readXMessage(c.r)
+	require.NoError(c.t, err)
+	fields, err := parseXMessage(payload)
+	require.NoError(c.t, err)
+	return typ, fields
+}
+
+// readUntil reads the messages until a message of the given type, and returns them.
+func (c *xTestClient) readUntil(typ byte) ([]byte, [][]xField) {
+	var types []byte
+	var messages [][]xField
+	for {
+		t, fields := c.read()
+		types = append(types, t)
+		messages = append(messages, fields)
+		if t == typ || t == xServerError {
+			return types, messages
+		}
+	}
+}
+
+func (c *xTestClient) authenticate(user, password, schema string) {
+	start := appendXBytesField(nil, 1, []byte(xAuthMySQL41))
+	c.send(xClientSessAuthenticateStart, start)
+	typ, fields := c.read()
+	require.EqualValues(c.t, xServerSessAuthenticateContinue, typ)
+	salt := fields[0].bytes
+	require.Len(c.t, salt, 20)
+
+	data := schema + "\x00" + user + "\x00"
+	if password != "" {
+		data += "*" + hex.EncodeToString(ScrambleMysqlNativePassword(salt, []byte(password)))
+	}
+	c.send(xClientSessAuthenticateContinue, appendXBytesField(nil, 1, []byte(data)))
+}
+
+func newXTestListener(t *testing.T, th Handler) *Listener {
+	authServer := NewAuthServerStatic("", "", 0)
+	authServer.entries["user1"] = []*AuthServerStaticEntry{{
+		Password: "password1",
+		UserData: "userData1",
+	}}
+	t.Cleanup(authServer.close)
+	l, err := NewListenerWithConfig(ListenerConfig{
+		Protocol:   "tcp",
+		Address:    "127.0.0.1:",
+		AuthServer: authServer,
+		Handler:    th,
+		XProtocol:  true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(l.Close)
+	go l.Accept()
+	return l
+}
+
+func TestXProtocolServer(t *testing.T) {
+	th := &testHandler{}
+	l := newXTestListener(t, th)
+	c := newXTestClient(t, l)
+
+	c.send(xClientConCapabilitiesGet, nil)
+	typ, fields := c.read()
+	require.EqualValues(t, xServerConCapabilities, typ)
+	capabilities, err := parseXCapabilitiesSet(encodeXCapabilitiesSet(fields))
+	require.NoError(t, err)
+	var names []string
+	for _, capability := range capabilities {
+		names = append(names, capability.key)
+	}
+	assert.Contains(t, names, "authentication.mechanisms")
+
+	// unknown capabilities are refused
+	set := encodeXCapabilities([]string{"foo"}, [][]byte{appendXAnyScalar(nil, &xScalar{typ: xScalarBool, boolean: true})})
+	c.send(xClientConCapabilitiesSet, appendXBytesField(nil, 1, set))
+	typ, fields = c.read()
+	require.EqualValues(t, xServerError, typ)
+	assert.EqualValues(t, sqlerror.ERXCapabilityNotFound, fields[0].varint)
+
+	// a wrong password is refused, and the client can try again
+	c.authenticate("user1", "bad", "")
+	typ, fields = c.read()
+	require.EqualValues(t, xServerError, typ)
+	assert.EqualValues(t, sqlerror.ERAccessDeniedError, fields[0].varint)
+
+	c.authenticate("user1", "password1", "db1")
+	types, _ := c.readUntil(xServerSessAuthenticateOk)
+	assert.Equal(t, []byte{xServerNotice, xServerSessAuthenticateOk}, types)
+	conn := th.LastConn()
+	assert.Equal(t, "user1", conn.User)
+	assert.Equal(t, "db1", conn.schemaName)
+
+	// a query with a result set
+	c.send(xClientSQLStmtExecute, appendXBytesField(nil, 1, []byte("userData echo")))
+	types, messages := c.readUntil(xServerSQLStmtExecuteOk)
+	assert.Equal(t, []byte{xServerColumnMetaData, xServerColumnMetaData, xServerRow, xServerFetchDone, xServerNotice, xServerSQLStmtExecuteOk}, types)
+	assert.Equal(t, "user", string(messages[0][1].bytes))
+	assert.Equal(t, "user1\x00", string(messages[2][0].bytes))
+	assert.Equal(t, "userData1\x00", string(messages[2][1].bytes))
+
+	// a query without a result set
+	c.send(xClientSQLStmtExecute, appendXBytesField(nil, 1, []byte("insert")))
+	types, _ = c.readUntil(xServerSQLStmtExecuteOk)
+	assert.Equal(t, []byte{xServerNotice, xServerNotice, xServerSQLStmtExecuteOk}, types)
+
+	// an error
+	th.SetErr(sqlerror.NewSQLError(sqlerror.ERUnknownError, sqlerror.SSUnknownSQLState, "forced error"))
+	c.send(xClientSQLStmtExecute, appendXBytesField(nil, 1, []byte("error")))
+	typ, fields = c.read()
+	require.EqualValues(t, xServerError, typ)
+	assert.EqualValues(t, sqlerror.ERUnknownError, fields[0].varint)
+	assert.Equal(t, "forced error", string(fields[1].bytes))
+
+	// an unknown namespace
+	stmt := appendXBytesField(nil, 1, []byte("select 1"))
+	stmt = appendXBytesField(stmt, 3, []byte("foo"))
+	c.send(xClientSQLStmtExecute, stmt)
+	typ, fields = c.read()
+	require.EqualValues(t, xServerError, typ)
+	assert.EqualValues(t, sqlerror.ERXInvalidNamespace, fields[0].varint)
+
+	c.send(xClientSessClose, nil)
+	typ, _ = c.read()
+	assert.EqualValues(t, xServerOk, typ)
+}
+
+// encodeXCapabilitiesSet encodes a Mysqlx.Connection.CapabilitiesSet from the fields of a Capabilities.
+func encodeXCapabilitiesSet(fields []xField) []byte {
+	var capabilities []byte
+	for _, f := range fields {
+		capabilities = appendXBytesField(capabilities, f.num, f.bytes)
+	}
+	return appendXBytesField(nil, 1, capabilities)
+}
+
+func TestXBindArgs(t *testing.T) {
+	args := []*xAny{
+		{scalar: &xScalar{typ: xScalarSint, signed: -1}},
+		{scalar: xString("it's")},
+		{scalar: &xScalar{typ: xScalarNull}},
+	}
+	query, err := xBindArgs("select ?, '?', `?` /* ? */, ? from t where c = ? -- ?", args)
+	require.NoError(t, err)
+	assert.Equal(t, "select -1, '?', `?` /* ? */, 'it\\'s' from t where c = null -- ?", query)
+
+	_, err = xBindArgs("select ?", nil)
+	assert.ErrorContains(t, err, "Too few arguments")
+	_, err = xBindArgs("select 1", args)
+	assert.ErrorContains(t, err, "Too many arguments")
+}
+
+func TestXAdminCommand(t *testing.T) {
+	object := &xAny{object: []xObjectField{
+		{key: "schema", value: &xAny{scalar: xString("ks")}},
+		{key: "name", value: &xAny{scalar: xString("c1")}},
+	}}
+	query, err := xAdminCommand("create_collection", []*xAny{object})
+	require.NoError(t, err)
+	assert.Equal(t, "create table `ks`.`c1` "+xCollectionTable, query)
+
+	query, err = xAdminCommand("drop_collection", []*xAny{{scalar: xString("ks")}, {scalar: xString("c1")}})
+	require.NoError(t, err)
+	assert.Equal(t, "drop table `ks`.`c1`", query)
+
+	_, err = xAdminCommand("foo", nil)
+	assert.ErrorContains(t, err, "Invalid mysqlx command foo")
+}
+
+// The helpers below encode the Mysqlx.Expr and Mysqlx.Crud messages of the tests.
+
+func xTestCollection(name string) []byte {
+	return appendXBytesField(nil, 1, []byte(name))
+}
+
+func xTestPath(members ...string) []byte {
+	var id []byte
+	for _, m := range members {
+		item := appendXVarintField(nil, 1, xPathMember)
+		item = appendXBytesField(item, 2, []byte(m))
+		id = appendXBytesField(id, 1, item)
+	}
+	e := appendXVarintField(nil, 1, xExprIdent)
+	return appendXBytesField(e, 2, id)
+}
+
+func xTestLiteral(s *xScalar) []byte {
+	e := appendXVarintField(nil, 1, xExprLiteral)
+	return appendXBytesField(e, 4, appendXScalar(nil, s))
+}
+
+func xTestPlaceholder(position uint64) []byte {
+	e := appendXVarintField(nil, 1, xExprPlaceholder)
+	return appendXVarintField(e, 7, position)
+}
+
+func xTestOperator(name string, params ...[]byte) []byte {
+	op := appendXBytesField(nil, 1, []byte(name))
+	for _, p := range params {
+		op = appendXBytesField(op, 2, p)
+	}
+	e := appendXVarintField(nil, 1, xExprOperator)
+	return appendXBytesField(e, 6, op)
+}
+
+func xTestObject(keys []string, values ...[]byte) []byte {
+	var object []byte
+	for i, key := range keys {
+		field := appendXBytesField(nil, 1, []byte(key))
+		field = appendXBytesField(field, 2, values[i])
+		object = appendXBytesField(object, 1, field)
+	}
+	e := appendXVarintField(nil, 1, xExprObject)
+	return appendXBytesField(e, 8, object)
+}
+
+func TestXFindSQL(t *testing.T) {
+	find := appendXBytesField(nil, 2, xTestCollection("c1"))
+	find = appendXVarintField(find, 3, xDataModelDocument)
+	find = appendXBytesField(find, 5, xTestOperator("&&",
+		xTestOperator("==", xTestPath("name"), xTestPlaceholder(0)),
+		xTestOperator("like", xTestPath("address", "city"), xTestLiteral(xString("Par%"))),
+	))
+	find = appendXBytesField(find, 6, appendXVarintField(appendXVarintField(nil, 1, 10), 2, 5))
+	find = appendXBytesField(find, 7, appendXVarintField(appendXBytesField(nil, 1, xTestPath("age")), 2, 2))
+	find = appendXBytesField(find, 11, appendXScalar(nil, xString("bob")))
+
+	query, err := xFindSQL(find)
+	require.NoError(t, err)
+	assert.Equal(t, "select doc from `c1` where ((json_extract(doc, '$.name') = 'bob') and (json_unquote(json_extract(doc, '$.address.city')) like 'Par%')) order by json_extract(doc, '$.age') desc limit 5, 10", query)
+
+	projection := appendXBytesField(nil, 1, xTestPath("name"))
+	find = appendXBytesField(nil, 2, xTestCollection("c1"))
+	find = appendXBytesField(find, 4, projection)
+	find = appendXBytesField(find, 4, appendXBytesField(appendXBytesField(nil, 1, xTestPath("a", "b")), 2, []byte("ab")))
+	find = appendXVarintField(find, 12, 2)
+	query, err = xFindSQL(find)
+	require.NoError(t, err)
+	assert.Equal(t, "select json_object('name', json_extract(doc, '$.name'), 'ab', json_extract(doc, '$.a.b')) as doc from `c1` for update", query)
+
+	// the placeholders must have a value
+	find = appendXBytesField(nil, 2, xTestCollection("c1"))
+	find = appendXBytesField(find, 5, xTestOperator("==", xTestPath("name"), xTestPlaceholder(0)))
+	_, err = xFindSQL(find)
+	assert.ErrorContains(t, err, "Invalid number of arguments")
+}
+
+func TestXInsertSQL(t *testing.T) {
+	id := 0
+	newID := func() string {
+		id++
+		return "id" + string(rune('0'+id))
+	}
+
+	insert := appendXBytesField(nil, 1, xTestCollection("c1"))
+	insert = appendXBytesField(insert, 4, appendXBytesField(nil, 1, xTestObject([]string{"name"}, xTestLiteral(xString("bob")))))
+	insert = appendXBytesField(insert, 4, appendXBytesField(nil, 1, xTestObject([]string{"_id", "name"}, xTestLiteral(xString("x")), xTestLiteral(xString("alice")))))
+	insert = appendXBytesField(insert, 4, appendXBytesField(nil, 1, xTestLiteral(&xScalar{typ: xScalarOctets, octets: []byte(`{"a": 1}`), contentType: xContentTypeJSON})))
+	query, ids, err := xInsertSQL(insert, newID)
+	require.NoError(t, err)
+	assert.Equal(t, "insert into `c1` (doc) values (json_object('_id', 'id1', 'name', 'bob')), (json_object('_id', 'x', 'name', 'alice')), (json_insert(cast('{\\\"a\\\": 1}' as json), '$._id', 'id2'))", query)
+	assert.Equal(t, []string{"id1", "id2"}, ids)
+
+	// the tables have columns
+	column := appendXBytesField(nil, 1, []byte("a"))
+	insert = appendXBytesField(nil, 1, xTestCollection("t1"))
+	insert = appendXVarintField(insert, 2, xDataModelTable)
+	insert = appendXBytesField(insert, 3, column)
+	insert = appendXBytesField(insert, 4, appendXBytesField(nil, 1, xTestLiteral(&xScalar{typ: xScalarUint, unsigned: 1})))
+	query, ids, err = xInsertSQL(insert, newID)
+	require.NoError(t, err)
+	assert.Equal(t, "insert into `t1` (`a`) values (1)", query)
+	assert.Empty(t, ids)
+}
+
+func TestXUpdateSQL(t *testing.T) {
+	source := func(members ...string) []byte {
+		var id []byte
+		for _, m := range members {
+			item := appendXVarintField(nil, 1, xPathMember)
+			item = appendXBytesField(item, 2, []byte(m))
+			id = appendXBytesField(id, 1, item)
+		}
+		return id
+	}
+	operation := func(op uint64, src []byte, value []byte) []byte {
+		b := appendXBytesField(nil, 1, src)
+		b = appendXVarintField(b, 2, op)
+		if value != nil {
+			b = appendXBytesField(b, 3, value)
+		}
+		return b
+	}
+
+	update := appendXBytesField(nil, 2, xTestCollection("c1"))
+	update = appendXBytesField(update, 4, xTestOperator("==", xTestPath("name"), xTestLiteral(xString("bob"))))
+	update = appendXBytesField(update, 5, appendXVarintField(nil, 1, 1))
+	update = appendXBytesField(update, 7, operation(xUpdateItemSet, source("age"), xTestLiteral(&xScalar{typ: xScalarUint, unsigned: 42})))
+	update = appendXBytesField(update, 7, operation(xUpdateItemRemove, source("tmp"), nil))
+	query, err := xUpdateSQL(update)
+	require.NoError(t, err)
+	assert.Equal(t, "update `c1` set doc = json_remove(json_set(doc, '$.age', 42), '$.tmp') where (json_extract(doc, '$.name') = 'bob') limit 1", query)
+
+	// the _id of the documents can not be changed
+	update = appendXBytesField(nil, 2, xTestCollection("c1"))
+	update = appendXBytesField(update, 7, operation(xUpdateItemSet, source("_id"), xTestLiteral(xString("x"))))
+	_, err = xUpdateSQL(update)
+	assert.ErrorContains(t, err, "Forbidden update operation on '$._id' member")
+}
+
+func TestXDeleteSQL(t *testing.T) {
+	del := appendXBytesField(nil, 1, xTestCollection("c1"))
+	del = appendXBytesField(del, 3, xTestOperator("in", xTestPath("age"), xTestLiteral(&xScalar{typ: xScalarUint, unsigned: 1}), xTestLiteral(&xScalar{typ: xScalarUint, unsigned: 2})))
+	query, err := xDeleteSQL(del)
+	require.NoError(t, err)
+	assert.Equal(t, "delete from `c1` where (json_extract(doc, '$.age') in (1, 2))", query)
+
+	del = appendXBytesField(nil, 1, xTestCollection("c1"))
+	del = appendXBytesField(del, 4, appendXVarintField(appendXVarintField(nil, 1, 10), 2, 5))
+	_, err = xDeleteSQL(del)
+	assert.ErrorContains(t, err, "non-zero offset value not allowed")
+}
+
+func TestXRow(t *testing.T) {
+	fields := []*querypb.Field{
+		{Type: sqltypes.Int64},
+		{Type: sqltypes.Uint32},
+		{Type: sqltypes.Float64},
+		{Type: sqltypes.VarChar},
+		{Type: sqltypes.Decimal},
+		{Type: sqltypes.Datetime},
+		{Type: sqltypes.Time},
+		{Type: sqltypes.VarChar},
+	}
+	row := []sqltypes.Value{
+		sqltypes.NewInt64(-2),
+		sqltypes.NewUint32(300),
+		sqltypes.NewFloat64(1.5),
+		sqltypes.NewVarChar("abc"),
+		sqltypes.MakeTrusted(sqltypes.Decimal, []byte("-12.5")),
+		sqltypes.MakeTrusted(sqltypes.Datetime, []byte("2023-01-02 03:04:05.5")),
+		sqltypes.MakeTrusted(sqltypes.Time, []byte("-01:02:03")),
+		sqltypes.NULL,
+	}
+	payload, err := encodeXRow(fields, row)
+	require.NoError(t, err)
+	values, err := parseXMessage(payload)
+	require.NoError(t, err)
+	require.Len(t, values, len(row))
+
+	assert.Equal(t, protowire.AppendVarint(nil, protowire.EncodeZigZag(-2)), values[0].bytes)
+	assert.Equal(t, protowire.AppendVarint(nil, 300), values[1].bytes)
+	assert.Equal(t, []byte{0, 0, 0, 0, 0, 0, 0xf8, 0x3f}, values[2].bytes)
+	assert.Equal(t, []byte("abc\x00"), values[3].bytes)
+	assert.Equal(t, []byte{1, 0x12, 0x5d}, values[4].bytes)
+	assert.Equal(t, []byte{0xe7, 0x0f, 1, 2, 3, 4, 5, 0xa0, 0xc2, 0x1e}, values[5].bytes)
+	assert.Equal(t, []byte{1, 1, 2, 3}, values[6].bytes)
+	assert.Empty(t, values[7].bytes)
+}
@@ -106,6 +106,7 @@ func ResultToProto3(qr *Result) *querypb.QueryResult {
 		Rows:                RowsToProto3(qr.Rows),
 		Info:                qr.Info,
 		SessionStateChanges: qr.SessionStateChanges,
+		ContinuationToken:   qr.ContinuationToken,
 	}
 }
 
@@ -122,6 +123,7 @@ func Proto3ToResult(qr *querypb.QueryResult) *Result {
 		Rows:                proto3ToRows(qr.Fields, qr.Rows),
 		Info:                qr.Info,
 		SessionStateChanges: qr.SessionStateChanges,
+		ContinuationToken:   qr.ContinuationToken,
 	}
 }
 
@@ -139,6 +141,7 @@ func CustomProto3ToResult(fields []*querypb.Field, qr *querypb.QueryResult) *Res
 		Rows:                proto3ToRows(fields, qr.Rows),
 		Info:                qr.Info,
 		SessionStateChanges: qr.SessionStateChanges,
+		ContinuationToken:   qr.ContinuationToken,
 	}
 }
 
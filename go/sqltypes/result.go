@@ -35,6 +35,8 @@ type Result struct {
 	SessionStateChanges string           `json:"session_state_changes"`
 	StatusFlags         uint16           `json:"status_flags"`
 	Info                string           `json:"info"`
+	// ContinuationToken fetches the next page of the results of a paged query.
+	ContinuationToken string `json:"continuation_token,omitempty"`
 }
 
 //goland:noinspection GoUnusedConst
@@ -95,6 +97,7 @@ func (result *Result) Copy() *Result {
 		SessionStateChanges: result.SessionStateChanges,
 		StatusFlags:         result.StatusFlags,
 		Info:                result.Info,
+		ContinuationToken:   result.ContinuationToken,
 	}
 	if result.Fields != nil {
 		out.Fields = make([]*querypb.Field, len(result.Fields))
@@ -120,6 +123,7 @@ func (result *Result) ShallowCopy() *Result {
 		Info:                result.Info,
 		SessionStateChanges: result.SessionStateChanges,
 		Rows:                result.Rows,
+		ContinuationToken:   result.ContinuationToken,
 	}
 }
 
@@ -194,10 +198,11 @@ func (result *Result) Equal(other *Result) bool {
 		return false
 	}
 
-	// Compare Fields, RowsAffected, InsertID, Rows.
+	// Compare Fields, RowsAffected, InsertID, ContinuationToken, Rows.
 	return FieldsEqual(result.Fields, other.Fields) &&
 		result.RowsAffected == other.RowsAffected &&
 		result.InsertID == other.InsertID &&
+		result.ContinuationToken == other.ContinuationToken &&
 		reflect.DeepEqual(result.Rows, other.Rows)
 }
 
@@ -332,6 +337,9 @@ func (result *Result) AppendResult(src *Result) {
 	if src.InsertID != 0 {
 		result.InsertID = src.InsertID
 	}
+	if src.ContinuationToken != "" {
+		result.ContinuationToken = src.ContinuationToken
+	}
 	result.Rows = append(result.Rows, src.Rows...)
 }
 
@@ -20,6 +20,7 @@ package streamlog
 import (
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
 	"net/url"
 	"os"
@@ -27,6 +28,7 @@ import (
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/spf13/pflag"
 
@@ -53,6 +55,9 @@ var (
 	queryLogFilterTag    string
 	queryLogRowThreshold uint64
 	queryLogFormat       = "text"
+	queryLogMode         = QueryLogModeAll
+	queryLogSlowTime     = time.Second
+	queryLogSampleRate   = 1.0
 )
 
 func GetRedactDebugUIQueries() bool {
@@ -87,6 +92,30 @@ func SetQueryLogFormat(newQueryLogFormat string) {
 	queryLogFormat = newQueryLogFormat
 }
 
+func GetQueryLogMode() string {
+	return queryLogMode
+}
+
+func SetQueryLogMode(newQueryLogMode string) {
+	queryLogMode = newQueryLogMode
+}
+
+func GetQueryLogSlowTime() time.Duration {
+	return queryLogSlowTime
+}
+
+func SetQueryLogSlowTime(newQueryLogSlowTime time.Duration) {
+	queryLogSlowTime = newQueryLogSlowTime
+}
+
+func GetQueryLogSampleRate() float64 {
+	return queryLogSampleRate
+}
+
+func SetQueryLogSampleRate(newQueryLogSampleRate float64) {
+	queryLogSampleRate = newQueryLogSampleRate
+}
+
 func init() {
 	servenv.OnParseFor("vtcombo", registerStreamLogFlags)
 	servenv.OnParseFor("vttablet", registerStreamLogFlags)
@@ -106,6 +135,14 @@ func registerStreamLogFlags(fs *pflag.FlagSet) {
 	// QueryLogRowThreshold only log queries returning or affecting this many rows
 	fs.Uint64Var(&queryLogRowThreshold, "querylog-row-threshold", queryLogRowThreshold, "Number of rows a query has to return or affect before being logged; not useful for streaming queries. 0 means all queries will be logged.")
 
+	// QueryLogMode controls which queries are logged: all of them, only the slow ones or only the failed ones
+	fs.StringVar(&queryLogMode, "querylog-mode", queryLogMode, "Mode for logging queries (\"all\", \"slow\" or \"error\"); \"slow\" only logs the queries running longer than --querylog-slow-time.")
+
+	// QueryLogSlowTime is the time a query has to run before being logged by the slow mode
+	fs.DurationVar(&queryLogSlowTime, "querylog-slow-time", queryLogSlowTime, "Time a query has to run before being logged when --querylog-mode is \"slow\".")
+
+	// QueryLogSampleRate is the fraction of the queries that are logged
+	fs.Float64Var(&queryLogSampleRate, "querylog-sample-rate", queryLogSampleRate, "Fraction of the queries to log, between 0 and 1; the other queries are not sent to any query log.")
 }
 
 const (
@@ -114,6 +151,15 @@ const (
 
 	// QueryLogFormatJSON is the format specifier for json querylog output
 	QueryLogFormatJSON = "json"
+
+	// QueryLogModeAll is the mode specifier for logging all queries
+	QueryLogModeAll = "all"
+
+	// QueryLogModeSlow is the mode specifier for only logging the slow queries
+	QueryLogModeSlow = "slow"
+
+	// QueryLogModeError is the mode specifier for only logging the failed queries
+	QueryLogModeError = "error"
 )
 
 // StreamLogger is a non-blocking broadcaster of messages.
@@ -259,9 +305,31 @@ func GetFormatter[T any](logger *StreamLogger[T]) LogFormatter {
 	}
 }
 
+// ShouldSampleQuery returns whether a query should be sent to the query logs,
+// according to the sample rate.
+func ShouldSampleQuery() bool {
+	if queryLogSampleRate >= 1 {
+		return true
+	}
+	if queryLogSampleRate <= 0 {
+		return false
+	}
+	return rand.Float64() < queryLogSampleRate
+}
+
 // ShouldEmitLog returns whether the log with the given SQL query
 // should be emitted or filtered
-func ShouldEmitLog(sql string, rowsAffected, rowsReturned uint64) bool {
+func ShouldEmitLog(sql string, rowsAffected, rowsReturned uint64, totalTime time.Duration, hasError bool) bool {
+	switch queryLogMode {
+	case QueryLogModeSlow:
+		if totalTime < queryLogSlowTime {
+			return false
+		}
+	case QueryLogModeError:
+		if !hasError {
+			return false
+		}
+	}
 	if queryLogRowThreshold > max(rowsAffected, rowsReturned) && queryLogFilterTag == "" {
 		return false
 	}
@@ -44,6 +44,10 @@ var (
 	// GoVtTopoConsultopoPort is used by the go/vt/topo/consultopo package.
 	// Takes four ports.
 	GoVtTopoConsultopoPort = GoVtTopoZk2topoPort + 3
+
+	// GoVtTopoNatstopoPort is used by the go/vt/topo/natstopo package.
+	// Takes one port.
+	GoVtTopoNatstopoPort = GoVtTopoConsultopoPort + 4
 )
 
 // Zookeeper server ID definitions. Unit tests may run at the
@@ -100,6 +100,11 @@ type Stats struct {
 
 	PartialQueryCount     *stats.CountersWithMultiLabels
 	PartialQueryCacheSize *stats.CountersWithMultiLabels
+
+	// The bytes of the compressed events received from the vstreamer,
+	// and of the same events once decompressed.
+	VStreamCompressedBytes   *stats.Counter
+	VStreamUncompressedBytes *stats.Counter
 }
 
 // RecordHeartbeat updates the time the last heartbeat from vstreamer was seen
@@ -167,6 +172,8 @@ func NewStats() *Stats {
 	bps.TableCopyTimings = stats.NewTimings("", "", "Table")
 	bps.PartialQueryCacheSize = stats.NewCountersWithMultiLabels("", "", []string{"type"})
 	bps.PartialQueryCount = stats.NewCountersWithMultiLabels("", "", []string{"type"})
+	bps.VStreamCompressedBytes = stats.NewCounter("", "")
+	bps.VStreamUncompressedBytes = stats.NewCounter("", "")
 	return bps
 }
 
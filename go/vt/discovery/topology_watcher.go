@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"hash/crc32"
+	"maps"
 	"sort"
 	"strings"
 	"sync"
@@ -214,18 +215,20 @@ func (tw *TopologyWatcher) loadTablets() {
 	tw.mu.Lock()
 
 	for alias, newVal := range newTablets {
-		if tw.tabletFilter != nil && !tw.tabletFilter.IsIncluded(newVal.tablet) {
+		if !tw.isIncluded(newVal.tablet) {
 			continue
 		}
 
-		// trust the alias from topo and add it if it doesn't exist
-		if val, ok := tw.tablets[alias]; ok {
-			// check if the host and port have changed. If yes, replace tablet.
+		// trust the alias from topo and add it if it doesn't exist, or if it
+		// was filtered out until now (e.g. because its tags changed)
+		if val, ok := tw.tablets[alias]; ok && tw.isIncluded(val.tablet) {
+			// check if the host and port, or the tags, have changed. If yes,
+			// replace tablet.
 			oldKey := TabletToMapKey(val.tablet)
 			newKey := TabletToMapKey(newVal.tablet)
-			if oldKey != newKey {
+			if oldKey != newKey || !maps.Equal(val.tablet.Tags, newVal.tablet.Tags) {
 				// This is the case where the same tablet alias is now reporting
-				// a different address (host:port) key.
+				// a different address (host:port) key, or different tags.
 				tw.healthcheck.ReplaceTablet(val.tablet, newVal.tablet)
 				topologyWatcherOperations.Add(topologyWatcherOpReplaceTablet, 1)
 			}
@@ -237,11 +240,13 @@ func (tw *TopologyWatcher) loadTablets() {
 	}
 
 	for _, val := range tw.tablets {
-		if tw.tabletFilter != nil && !tw.tabletFilter.IsIncluded(val.tablet) {
+		if !tw.isIncluded(val.tablet) {
 			continue
 		}
 
-		if _, ok := newTablets[val.alias]; !ok {
+		// remove the tablets that are gone from topo, or that are now
+		// filtered out
+		if newVal, ok := newTablets[val.alias]; !ok || !tw.isIncluded(newVal.tablet) {
 			tw.healthcheck.RemoveTablet(val.tablet)
 			topologyWatcherOperations.Add(topologyWatcherOpRemoveTablet, 1)
 		}
@@ -269,6 +274,11 @@ func (tw *TopologyWatcher) loadTablets() {
 
 }
 
+// isIncluded returns whether the tablet passes the filter of the watcher.
+func (tw *TopologyWatcher) isIncluded(tablet *topodata.Tablet) bool {
+	return tw.tabletFilter == nil || tw.tabletFilter.IsIncluded(tablet)
+}
+
 // RefreshLag returns the time since the last refresh
 func (tw *TopologyWatcher) RefreshLag() time.Duration {
 	tw.mu.Lock()
@@ -396,3 +406,47 @@ func (fbk *FilterByKeyspace) IsIncluded(tablet *topodata.Tablet) bool {
 	_, exist := fbk.keyspaces[tablet.Keyspace]
 	return exist
 }
+
+// FilterByTabletTags is a filter that filters tablets by their tags.
+type FilterByTabletTags struct {
+	tags map[string]string
+}
+
+// NewFilterByTabletTags creates a new FilterByTabletTags. All tablets that
+// have all of the given tags will be forwarded to the underlying
+// LegacyTabletRecorder.
+func NewFilterByTabletTags(tags map[string]string) *FilterByTabletTags {
+	return &FilterByTabletTags{
+		tags: maps.Clone(tags),
+	}
+}
+
+// IsIncluded returns true if the tablet has all the tags of the filter.
+func (fbt *FilterByTabletTags) IsIncluded(tablet *topodata.Tablet) bool {
+	return TabletHasTags(tablet, fbt.tags)
+}
+
+// TabletHasTags returns true if the tablet has all of the given tags, with
+// the same values.
+func TabletHasTags(tablet *topodata.Tablet, tags map[string]string) bool {
+	for key, value := range tags {
+		if v, ok := tablet.Tags[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+// TabletFilters is a TabletFilter that only includes the tablets included by
+// all of its filters.
+type TabletFilters []TabletFilter
+
+// IsIncluded returns true if all the filters include the tablet.
+func (tf TabletFilters) IsIncluded(tablet *topodata.Tablet) bool {
+	for _, filter := range tf {
+		if !filter.IsIncluded(tablet) {
+			return false
+		}
+	}
+	return true
+}
@@ -631,3 +631,81 @@ func TestFilterByKeypsaceSkipsIgnoredTablets(t *testing.T) {
 
 	tw.Stop()
 }
+
+func TestFilterByTabletTags(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+
+	f := NewFilterByTabletTags(map[string]string{"zone": "az1", "hardware": "nvme"})
+	for _, test := range []struct {
+		tags     map[string]string
+		expected bool
+	}{
+		{tags: nil, expected: false},
+		{tags: map[string]string{"zone": "az1"}, expected: false},
+		{tags: map[string]string{"zone": "az2", "hardware": "nvme"}, expected: false},
+		{tags: map[string]string{"zone": "az1", "hardware": "nvme"}, expected: true},
+		{tags: map[string]string{"zone": "az1", "hardware": "nvme", "rack": "r1"}, expected: true},
+	} {
+		assert.Equal(t, test.expected, f.IsIncluded(&topodatapb.Tablet{Tags: test.tags}), "tags %v", test.tags)
+	}
+
+	ts := memorytopo.NewServer(ctx, "aa")
+	defer ts.Close()
+	fhc := NewFakeHealthCheck(nil)
+	defer fhc.Close()
+	topologyWatcherOperations.ZeroAll()
+	counts := topologyWatcherOperations.Counts()
+	tw := NewCellTabletsWatcher(context.Background(), ts, fhc, TabletFilters{NewFilterByKeyspace(testKeyspacesToWatch), f}, "aa", 10*time.Minute, true, 5)
+	defer tw.Stop()
+
+	tablet := &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{
+			Cell: "aa",
+			Uid:  1,
+		},
+		Hostname: "host1",
+		PortMap: map[string]int32{
+			"vt": 123,
+		},
+		Keyspace: "ks1",
+		Shard:    "shard",
+		Tags:     map[string]string{"zone": "az1"},
+	}
+	require.NoError(t, ts.CreateTablet(context.Background(), tablet))
+
+	tw.loadTablets()
+	counts = checkOpCounts(t, counts, map[string]int64{"ListTablets": 1, "GetTablet": 1})
+	assert.Empty(t, fhc.GetAllTablets())
+
+	// Once the tablet gets the missing tag, it is added to the healthcheck.
+	updateTags := func(tags map[string]string) {
+		_, err := ts.UpdateTabletFields(context.Background(), tablet.Alias, func(t *topodatapb.Tablet) error {
+			t.Tags = tags
+			return nil
+		})
+		require.NoError(t, err)
+	}
+	updateTags(map[string]string{"zone": "az1", "hardware": "nvme"})
+
+	tw.loadTablets()
+	counts = checkOpCounts(t, counts, map[string]int64{"ListTablets": 1, "GetTablet": 1, "AddTablet": 1})
+	allTablets := fhc.GetAllTablets()
+	require.Contains(t, allTablets, TabletToMapKey(tablet))
+	assert.Equal(t, "nvme", allTablets[TabletToMapKey(tablet)].Tags["hardware"])
+
+	// Changing any other tag replaces the tablet in the healthcheck.
+	updateTags(map[string]string{"zone": "az1", "hardware": "nvme", "rack": "r1"})
+
+	tw.loadTablets()
+	counts = checkOpCounts(t, counts, map[string]int64{"ListTablets": 1, "GetTablet": 1, "ReplaceTablet": 1})
+	allTablets = fhc.GetAllTablets()
+	require.Contains(t, allTablets, TabletToMapKey(tablet))
+	assert.Equal(t, "r1", allTablets[TabletToMapKey(tablet)].Tags["rack"])
+
+	// Removing a tag of the filter removes the tablet from the healthcheck.
+	updateTags(map[string]string{"zone": "az1"})
+
+	tw.loadTablets()
+	checkOpCounts(t, counts, map[string]int64{"ListTablets": 1, "GetTablet": 1, "RemoveTablet": 1})
+	assert.Empty(t, fhc.GetAllTablets())
+}
@@ -47,6 +47,7 @@ var (
 	// every vitess binary that makes grpc client-side calls.
 	grpcclientBinaries = []string{
 		"mysqlctld",
+		"vstream2kafka",
 		"vtadmin",
 		"vtbackup",
 		"vtbench",
@@ -67,7 +68,7 @@ func RegisterFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&keepaliveTimeout, "grpc_keepalive_timeout", keepaliveTimeout, "After having pinged for keepalive check, the client waits for a duration of Timeout and if no activity is seen even after that the connection is closed.")
 	fs.IntVar(&initialConnWindowSize, "grpc_initial_conn_window_size", initialConnWindowSize, "gRPC initial connection window size")
 	fs.IntVar(&initialWindowSize, "grpc_initial_window_size", initialWindowSize, "gRPC initial window size")
-	fs.StringVar(&compression, "grpc_compression", compression, "Which protocol to use for compressing gRPC. Default: nothing. Supported: snappy")
+	fs.StringVar(&compression, "grpc_compression", compression, "Which protocol to use for compressing gRPC. Default: nothing. Supported: snappy, lz4, zstd")
 
 	fs.StringVar(&credsFile, "grpc_auth_static_client_creds", credsFile, "When using grpc_static_auth in the server, this file provides the credentials to use to authenticate with server.")
 }
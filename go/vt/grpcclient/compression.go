@@ -0,0 +1,127 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcclient
+
+import (
+	"io"
+	"time"
+
+	"google.golang.org/grpc/encoding"
+
+	"vitess.io/vitess/go/stats"
+)
+
+var (
+	// The compression ratio of a compressor is GRPCCompressionOutputBytes / GRPCCompressionInputBytes,
+	// and the timings measure its CPU cost.
+	compressionInputBytes  = stats.NewCountersWithSingleLabel("GRPCCompressionInputBytes", "Bytes of the gRPC messages compressed, per compressor", "Compressor")
+	compressionOutputBytes = stats.NewCountersWithSingleLabel("GRPCCompressionOutputBytes", "Compressed bytes of the gRPC messages, per compressor", "Compressor")
+	compressionTimings     = stats.NewTimings("GRPCCompressionTimings", "Time spent compressing gRPC messages, per compressor", "Compressor")
+	decompressionTimings   = stats.NewTimings("GRPCDecompressionTimings", "Time spent decompressing gRPC messages, per compressor", "Compressor")
+)
+
+// meteredCompressor records the bytes and the time of the compression of the messages.
+type meteredCompressor struct {
+	encoding.Compressor
+}
+
+func (c meteredCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	start := time.Now()
+	out := &countingWriter{w: w}
+	cw, err := c.Compressor.Compress(out)
+	if err != nil {
+		return nil, err
+	}
+	return &meteredWriter{WriteCloser: cw, name: c.Name(), out: out, elapsed: time.Since(start)}, nil
+}
+
+func (c meteredCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	start := time.Now()
+	dr, err := c.Compressor.Decompress(r)
+	if err != nil {
+		return nil, err
+	}
+	return &meteredReader{Reader: dr, name: c.Name(), elapsed: time.Since(start)}, nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+type meteredWriter struct {
+	io.WriteCloser
+	name    string
+	out     *countingWriter
+	in      int64
+	elapsed time.Duration
+}
+
+func (w *meteredWriter) Write(p []byte) (int, error) {
+	start := time.Now()
+	n, err := w.WriteCloser.Write(p)
+	w.elapsed += time.Since(start)
+	w.in += int64(n)
+	return n, err
+}
+
+// Close flushes the compressed message, and records its stats.
+func (w *meteredWriter) Close() error {
+	start := time.Now()
+	err := w.WriteCloser.Close()
+	compressionTimings.Add(w.name, w.elapsed+time.Since(start))
+	compressionInputBytes.Add(w.name, w.in)
+	compressionOutputBytes.Add(w.name, w.out.n)
+	return err
+}
+
+type meteredReader struct {
+	io.Reader
+	name    string
+	elapsed time.Duration
+	done    bool
+}
+
+// Read records the time spent decompressing the message once it is read entirely.
+func (r *meteredReader) Read(p []byte) (int, error) {
+	start := time.Now()
+	n, err := r.Reader.Read(p)
+	r.elapsed += time.Since(start)
+	if err == io.EOF && !r.done {
+		r.done = true
+		decompressionTimings.Add(r.name, r.elapsed)
+	}
+	return n, err
+}
+
+// CompressorNames returns the names of the compressors supported by the
+// gRPC clients and servers of Vitess.
+func CompressorNames() []string {
+	return []string{SnappyCompressor{}.Name(), Lz4Compressor{}.Name(), ZstdCompressor{}.Name()}
+}
+
+func init() {
+	encoding.RegisterCompressor(meteredCompressor{SnappyCompressor{}})
+	encoding.RegisterCompressor(meteredCompressor{Lz4Compressor{}})
+	encoding.RegisterCompressor(meteredCompressor{ZstdCompressor{}})
+}
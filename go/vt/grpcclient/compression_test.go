@@ -0,0 +1,109 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcclient
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/encoding"
+
+	"vitess.io/vitess/go/vt/grpccommon"
+)
+
+func TestCompressors(t *testing.T) {
+	message := []byte(strings.Repeat("select * from corder where customer_id = 1; ", 10000))
+	for _, name := range CompressorNames() {
+		t.Run(name, func(t *testing.T) {
+			compressor := encoding.GetCompressor(name)
+			require.NotNil(t, compressor)
+			inputBytes := compressionInputBytes.Counts()[name]
+			outputBytes := compressionOutputBytes.Counts()[name]
+			compressions := compressionTimings.Counts()[name]
+			decompressions := decompressionTimings.Counts()[name]
+
+			// Compress twice to exercise the reuse of the pooled writers.
+			for i := 0; i < 2; i++ {
+				var compressed bytes.Buffer
+				w, err := compressor.Compress(&compressed)
+				require.NoError(t, err)
+				_, err = w.Write(message)
+				require.NoError(t, err)
+				require.NoError(t, w.Close())
+				assert.Less(t, compressed.Len(), len(message)/10)
+
+				r, err := compressor.Decompress(&compressed)
+				require.NoError(t, err)
+				decompressed, err := io.ReadAll(r)
+				require.NoError(t, err)
+				assert.Equal(t, message, decompressed)
+			}
+
+			assert.EqualValues(t, 2*len(message), compressionInputBytes.Counts()[name]-inputBytes)
+			assert.Less(t, compressionOutputBytes.Counts()[name]-outputBytes, int64(len(message)/5))
+			assert.EqualValues(t, 2, compressionTimings.Counts()[name]-compressions)
+			assert.EqualValues(t, 2, decompressionTimings.Counts()[name]-decompressions)
+		})
+	}
+}
+
+func TestAppendCompression(t *testing.T) {
+	defer func(c string) { compression = c }(compression)
+
+	compression = ""
+	opts, err := appendCompression(nil)
+	require.NoError(t, err)
+	assert.Empty(t, opts)
+
+	compression = "zstd"
+	opts, err = appendCompression(nil)
+	require.NoError(t, err)
+	assert.Len(t, opts, 1)
+
+	compression = "brotli"
+	_, err = appendCompression(nil)
+	assert.EqualError(t, err, "unsupported --grpc_compression brotli, expected one of snappy, lz4, zstd")
+}
+
+func TestZstdDecompressMaxMessageSize(t *testing.T) {
+	// A frame of zeros compresses to a few bytes, whatever its size.
+	compress := func(size int) *bytes.Buffer {
+		var compressed bytes.Buffer
+		w, err := ZstdCompressor{}.Compress(&compressed)
+		require.NoError(t, err)
+		_, err = w.Write(make([]byte, size))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		return &compressed
+	}
+
+	r, err := ZstdCompressor{}.Decompress(compress(grpccommon.MaxMessageSize()))
+	require.NoError(t, err)
+	decompressed, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Len(t, decompressed, grpccommon.MaxMessageSize())
+
+	compressed := compress(grpccommon.MaxMessageSize() + 1)
+	assert.Less(t, compressed.Len(), 4096)
+	_, err = ZstdCompressor{}.Decompress(compressed)
+	assert.ErrorIs(t, err, zstd.ErrDecoderSizeExceeded)
+}
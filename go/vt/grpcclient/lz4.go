@@ -0,0 +1,68 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcclient
+
+import (
+	"io"
+	"sync"
+
+	"github.com/pierrec/lz4"
+)
+
+// lz4Writers pools the writers, which each allocate a large hash table.
+var lz4Writers = sync.Pool{
+	New: func() any {
+		w := lz4.NewWriter(nil)
+		// gRPC compresses every message in its own frame, so small blocks avoid
+		// allocating the default 4MB buffers for small results.
+		w.Header.BlockMaxSize = 64 << 10
+		return w
+	},
+}
+
+// Lz4Compressor is a gRPC compressor using the LZ4 algorithm.
+type Lz4Compressor struct{}
+
+// Name is "lz4"
+func (c Lz4Compressor) Name() string {
+	return "lz4"
+}
+
+// Compress wraps with a pooled lz4.Writer
+func (c Lz4Compressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	lw := lz4Writers.Get().(*lz4.Writer)
+	lw.Reset(w)
+	return &lz4Writer{Writer: lw}, nil
+}
+
+// Decompress wraps with a lz4.Reader
+func (c Lz4Compressor) Decompress(r io.Reader) (io.Reader, error) {
+	return lz4.NewReader(r), nil
+}
+
+type lz4Writer struct {
+	*lz4.Writer
+}
+
+// Close flushes the frame and returns the writer to the pool.
+func (w *lz4Writer) Close() error {
+	err := w.Writer.Close()
+	w.Writer.Reset(nil)
+	lz4Writers.Put(w.Writer)
+	w.Writer = nil
+	return err
+}
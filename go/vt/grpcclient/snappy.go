@@ -17,7 +17,9 @@ limitations under the License.
 package grpcclient
 
 import (
+	"fmt"
 	"io"
+	"strings"
 
 	"github.com/golang/snappy"
 
@@ -46,8 +48,11 @@ func (s SnappyCompressor) Decompress(r io.Reader) (io.Reader, error) {
 }
 
 func appendCompression(opts []grpc.DialOption) ([]grpc.DialOption, error) {
-	if compression == "snappy" {
-		compression := grpc.WithDefaultCallOptions(grpc.UseCompressor("snappy"))
+	if compression != "" {
+		if encoding.GetCompressor(compression) == nil {
+			return nil, fmt.Errorf("unsupported --grpc_compression %s, expected one of %s", compression, strings.Join(CompressorNames(), ", "))
+		}
+		compression := grpc.WithDefaultCallOptions(grpc.UseCompressor(compression))
 		opts = append(opts, compression)
 	}
 
@@ -55,6 +60,5 @@ func appendCompression(opts []grpc.DialOption) ([]grpc.DialOption, error) {
 }
 
 func init() {
-	encoding.RegisterCompressor(SnappyCompressor{})
 	RegisterGRPCDialOptions(appendCompression)
 }
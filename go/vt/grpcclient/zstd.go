@@ -0,0 +1,91 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcclient
+
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+
+	"vitess.io/vitess/go/vt/grpccommon"
+)
+
+// The encoder and decoder are shared: EncodeAll and DecodeAll can be called concurrently.
+// The decoder is created on first use, once the flags are parsed, so that the
+// messages decompressed beyond --grpc_max_message_size are rejected.
+var (
+	zstdEncoder, _  = zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+	zstdDecoder     *zstd.Decoder
+	zstdDecoderOnce sync.Once
+)
+
+func getZstdDecoder() *zstd.Decoder {
+	zstdDecoderOnce.Do(func() {
+		zstdDecoder = newZstdDecoder(grpccommon.MaxMessageSize())
+	})
+	return zstdDecoder
+}
+
+// newZstdDecoder returns a decoder that fails to decompress the frames
+// larger than maxSize.
+func newZstdDecoder(maxSize int) *zstd.Decoder {
+	decoder, _ := zstd.NewReader(nil, zstd.WithDecoderConcurrency(0), zstd.WithDecoderMaxMemory(uint64(maxSize)))
+	return decoder
+}
+
+// ZstdCompressor is a gRPC compressor using the Zstandard algorithm.
+type ZstdCompressor struct{}
+
+// Name is "zstd"
+func (c ZstdCompressor) Name() string {
+	return "zstd"
+}
+
+// Compress buffers the message, and compresses it when closed
+func (c ZstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return &zstdWriter{w: w}, nil
+}
+
+// Decompress reads the whole message, and returns a reader on its decompressed bytes.
+// It fails if the message decompresses to more than --grpc_max_message_size.
+func (c ZstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	compressed, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	decompressed, err := getZstdDecoder().DecodeAll(compressed, nil)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(decompressed), nil
+}
+
+type zstdWriter struct {
+	w   io.Writer
+	buf bytes.Buffer
+}
+
+func (w *zstdWriter) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func (w *zstdWriter) Close() error {
+	_, err := w.w.Write(zstdEncoder.EncodeAll(w.buf.Bytes(), nil))
+	return err
+}
@@ -65,6 +65,29 @@ var (
 		},
 	)
 
+	// This is the path of a file holding a shared access signature for the
+	// container
+	sasTokenFile = viperutil.Configure(
+		configKey("sas_token_file"),
+		viperutil.Options[string]{
+			FlagName: "azblob_backup_sas_token_file",
+		},
+	)
+
+	// These select the managed identity to authenticate with
+	useManagedIdentity = viperutil.Configure(
+		configKey("managed_identity.enabled"),
+		viperutil.Options[bool]{
+			FlagName: "azblob_backup_use_managed_identity",
+		},
+	)
+	managedIdentityClientID = viperutil.Configure(
+		configKey("managed_identity.client_id"),
+		viperutil.Options[string]{
+			FlagName: "azblob_backup_managed_identity_client_id",
+		},
+	)
+
 	// This is an optional prefix to prepend to all files
 	storageRoot = viperutil.Configure(
 		configKey("storage_root"),
@@ -77,7 +100,7 @@ var (
 		configKey("buffer_size"),
 		viperutil.Options[int]{
 			Default:  100 << (10 * 2), // 100 MiB
-			FlagName: "azblob_buffer_size",
+			FlagName: "azblob_backup_buffer_size",
 		},
 	)
 
@@ -88,6 +111,14 @@ var (
 			FlagName: "azblob_backup_parallelism",
 		},
 	)
+
+	azBlobChecksums = viperutil.Configure(
+		configKey("checksums"),
+		viperutil.Options[bool]{
+			Default:  true,
+			FlagName: "azblob_backup_checksums",
+		},
+	)
 )
 
 const configKeyPrefix = "backup.storage.azblob"
@@ -97,12 +128,16 @@ var configKey = viperutil.KeyPrefixFunc(configKeyPrefix)
 func registerFlags(fs *pflag.FlagSet) {
 	fs.String("azblob_backup_account_name", accountName.Default(), "Azure Storage Account name for backups; if this flag is unset, the environment variable VT_AZBLOB_ACCOUNT_NAME will be used.")
 	fs.String("azblob_backup_account_key_file", accountKeyFile.Default(), "Path to a file containing the Azure Storage account key; if this flag is unset, the environment variable VT_AZBLOB_ACCOUNT_KEY will be used as the key itself (NOT a file path).")
+	fs.String("azblob_backup_sas_token_file", sasTokenFile.Default(), "Path to a file containing a shared access signature (SAS) token for the container, used instead of the account key; if this flag is unset, the environment variable VT_AZBLOB_SAS_TOKEN will be used as the token itself (NOT a file path).")
+	fs.Bool("azblob_backup_use_managed_identity", useManagedIdentity.Default(), "Authenticate to Azure Storage with the managed identity of the VM, from the Azure Instance Metadata Service, instead of a SAS token or the account key.")
+	fs.String("azblob_backup_managed_identity_client_id", managedIdentityClientID.Default(), "Client ID of the user-assigned managed identity to authenticate with, when the VM has several of them. Defaults to the system-assigned managed identity.")
 	fs.String("azblob_backup_container_name", containerName.Default(), "Azure Blob Container Name.")
 	fs.String("azblob_backup_storage_root", storageRoot.Default(), "Root prefix for all backup-related Azure Blobs; this should exclude both initial and trailing '/' (e.g. just 'a/b' not '/a/b/').")
-	fs.Int("azblob_backup_buffer_size", azBlobBufferSize.Default(), "The memory buffer size to use in bytes, per file or stripe, when streaming to Azure Blob Service.")
+	fs.Int("azblob_backup_buffer_size", azBlobBufferSize.Default(), "The memory buffer size to use in bytes, per file or stripe, when streaming to Azure Blob Service. This is also the size of the blocks of the blobs, which have at most 50000 blocks, so it must be increased to back up files larger than 50000 times this size.")
 	fs.Int("azblob_backup_parallelism", azBlobParallelism.Default(), "Azure Blob operation parallelism (requires extra memory when increased -- a multiple of azblob_backup_buffer_size).")
+	fs.Bool("azblob_backup_checksums", azBlobChecksums.Default(), "Upload each block with its MD5 checksum, verified by Azure Blob Service, and store the MD5 checksum of each file, verified when it is restored.")
 
-	viperutil.BindFlags(fs, accountName, accountKeyFile, containerName, storageRoot, azBlobParallelism)
+	viperutil.BindFlags(fs, accountName, accountKeyFile, sasTokenFile, useManagedIdentity, managedIdentityClientID, containerName, storageRoot, azBlobBufferSize, azBlobParallelism, azBlobChecksums)
 }
 
 func init() {
@@ -117,41 +152,89 @@ const (
 	delimiter         = "/"
 )
 
-// Return a Shared credential from the available credential sources.
-// We will use credentials in the following order
-// 1. Direct Command Line Flag (azblob_backup_account_name, azblob_backup_account_key)
+// Return the account key from the available sources.
+// We will use the key from the following sources, in order
+// 1. The file named by the azblob_backup_account_key_file flag
 // 2. Environment variables
-func azInternalCredentials() (string, string, error) {
-	actName := accountName.Get()
-
-	var actKey string
+func azAccountKey() (string, error) {
 	if keyFile := accountKeyFile.Get(); keyFile != "" {
 		log.Infof("Getting Azure Storage Account key from file: %s", keyFile)
 		dat, err := os.ReadFile(keyFile)
 		if err != nil {
-			return "", "", err
+			return "", err
 		}
-		actKey = string(dat)
-	} else {
-		actKey = os.Getenv("VT_AZBLOB_ACCOUNT_KEY")
+		return string(dat), nil
 	}
+	return os.Getenv("VT_AZBLOB_ACCOUNT_KEY"), nil
+}
 
-	if actName == "" || actKey == "" {
-		return "", "", fmt.Errorf("Azure Storage Account credentials not found in command-line flags or environment variables")
+// Return the SAS token from the available sources.
+// We will use the token from the following sources, in order
+// 1. The file named by the azblob_backup_sas_token_file flag
+// 2. Environment variables
+func azSASToken() (string, error) {
+	if tokenFile := sasTokenFile.Get(); tokenFile != "" {
+		log.Infof("Getting Azure Storage SAS token from file: %s", tokenFile)
+		dat, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimPrefix(strings.TrimSpace(string(dat)), "?"), nil
 	}
-	return actName, actKey, nil
+	return strings.TrimPrefix(os.Getenv("VT_AZBLOB_SAS_TOKEN"), "?"), nil
 }
 
-func azCredentials() (*azblob.SharedKeyCredential, error) {
-	actName, actKey, err := azInternalCredentials()
+// Return a credential from the available credential sources, along with the
+// SAS token to add to the URLs, if any.
+// We will use credentials in the following order
+// 1. The managed identity of the VM, if azblob_backup_use_managed_identity is set
+// 2. A SAS token (azblob_backup_sas_token_file, or the VT_AZBLOB_SAS_TOKEN environment variable)
+// 3. The account key (azblob_backup_account_key_file, or the VT_AZBLOB_ACCOUNT_KEY environment variable)
+func (bs *AZBlobBackupStorage) azCredentials() (azblob.Credential, string, error) {
+	actName := accountName.Get()
+	if actName == "" {
+		return nil, "", fmt.Errorf("Azure Storage Account name not found in command-line flags or environment variables")
+	}
+
+	if useManagedIdentity.Get() {
+		bs.mu.Lock()
+		defer bs.mu.Unlock()
+		// The token credential refreshes its token in the background, so it
+		// is created only once.
+		if bs.tokenCredential == nil {
+			credential, err := newManagedIdentityCredential(context.Background(), managedIdentityClientID.Get())
+			if err != nil {
+				return nil, "", err
+			}
+			bs.tokenCredential = credential
+		}
+		return bs.tokenCredential, "", nil
+	}
+
+	sasToken, err := azSASToken()
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+	if sasToken != "" {
+		return azblob.NewAnonymousCredential(), sasToken, nil
 	}
-	return azblob.NewSharedKeyCredential(actName, actKey)
+
+	actKey, err := azAccountKey()
+	if err != nil {
+		return nil, "", err
+	}
+	if actKey == "" {
+		return nil, "", fmt.Errorf("Azure Storage Account credentials not found in command-line flags or environment variables")
+	}
+	credential, err := azblob.NewSharedKeyCredential(actName, actKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return credential, "", nil
 }
 
-func azServiceURL(credentials *azblob.SharedKeyCredential) azblob.ServiceURL {
-	pipeline := azblob.NewPipeline(credentials, azblob.PipelineOptions{
+func azServiceURL(actName string, credential azblob.Credential, sasToken string) azblob.ServiceURL {
+	pipeline := azblob.NewPipeline(credential, azblob.PipelineOptions{
 		Retry: azblob.RetryOptions{
 			Policy:   azblob.RetryPolicyFixed,
 			MaxTries: defaultRetryCount,
@@ -189,9 +272,10 @@ func azServiceURL(credentials *azblob.SharedKeyCredential) azblob.ServiceURL {
 		},
 	})
 	u := url.URL{
-		Scheme: "https",
-		Host:   credentials.AccountName() + ".blob.core.windows.net",
-		Path:   "/",
+		Scheme:   "https",
+		Host:     actName + ".blob.core.windows.net",
+		Path:     "/",
+		RawQuery: sasToken,
 	}
 	return azblob.NewServiceURL(u, pipeline)
 }
@@ -238,9 +322,13 @@ func (bh *AZBlobBackupHandle) AddFile(ctx context.Context, filename string, file
 	if bh.readOnly {
 		return nil, fmt.Errorf("AddFile cannot be called on read-only backup")
 	}
-	// Error out if the file size it too large ( ~4.75 TB)
-	if filesize > azblob.BlockBlobMaxStageBlockBytes*azblob.BlockBlobMaxBlocks {
-		return nil, fmt.Errorf("filesize (%v) is too large to upload to az blob (max size %v)", filesize, azblob.BlockBlobMaxStageBlockBytes*azblob.BlockBlobMaxBlocks)
+	// Error out if the file does not fit in the blocks of a blob.
+	blockSize, err := blockSize()
+	if err != nil {
+		return nil, err
+	}
+	if filesize > int64(blockSize)*azblob.BlockBlobMaxBlocks {
+		return nil, fmt.Errorf("filesize (%v) is too large to upload to az blob in blocks of %v bytes (max size %v), increase --azblob_backup_buffer_size", filesize, blockSize, int64(blockSize)*azblob.BlockBlobMaxBlocks)
 	}
 
 	obj := objName(bh.dir, bh.name, filename)
@@ -256,10 +344,15 @@ func (bh *AZBlobBackupHandle) AddFile(ctx context.Context, filename string, file
 
 	go func() {
 		defer bh.waitGroup.Done()
-		_, err := azblob.UploadStreamToBlockBlob(bh.ctx, reader, blockBlobURL, azblob.UploadStreamToBlockBlobOptions{
-			BufferSize: azBlobBufferSize.Get(),
-			MaxBuffers: azBlobParallelism.Get(),
-		})
+		var err error
+		if azBlobChecksums.Get() {
+			err = uploadWithChecksums(bh.ctx, reader, blockBlobURL, blockSize, azBlobParallelism.Get())
+		} else {
+			_, err = azblob.UploadStreamToBlockBlob(bh.ctx, reader, blockBlobURL, azblob.UploadStreamToBlockBlobOptions{
+				BufferSize: blockSize,
+				MaxBuffers: azBlobParallelism.Get(),
+			})
+		}
 		if err != nil {
 			reader.CloseWithError(err)
 			bh.RecordError(err)
@@ -307,25 +400,34 @@ func (bh *AZBlobBackupHandle) ReadFile(ctx context.Context, filename string) (io
 	if err != nil {
 		return nil, err
 	}
-	return resp.Body(azblob.RetryReaderOptions{
+	body := resp.Body(azblob.RetryReaderOptions{
 		MaxRetryRequests: defaultRetryCount,
 		NotifyFailedRead: func(failureCount int, lastError error, offset int64, count int64, willRetry bool) {
 			log.Warningf("ReadFile: [azblob] container: %s, directory: %s, filename: %s, error: %v", containerName, objName(bh.dir, ""), filename, lastError)
 		},
 		TreatEarlyCloseAsError: true,
-	}), nil
+	})
+	// Files uploaded with checksums have the MD5 of their contents.
+	if checksum := resp.ContentMD5(); len(checksum) > 0 {
+		return newChecksumReader(body, obj, checksum), nil
+	}
+	return body, nil
 }
 
 // AZBlobBackupStorage structs implements the BackupStorage interface for AZBlob
 type AZBlobBackupStorage struct {
+	// mu protects tokenCredential.
+	mu sync.Mutex
+	// tokenCredential is the credential of the managed identity, if any.
+	tokenCredential azblob.TokenCredential
 }
 
 func (bs *AZBlobBackupStorage) containerURL() (*azblob.ContainerURL, error) {
-	credentials, err := azCredentials()
+	credential, sasToken, err := bs.azCredentials()
 	if err != nil {
 		return nil, err
 	}
-	u := azServiceURL(credentials).NewContainerURL(containerName.Get())
+	u := azServiceURL(accountName.Get(), credential, sasToken).NewContainerURL(containerName.Get())
 	return &u, nil
 }
 
@@ -396,6 +498,12 @@ func (bs *AZBlobBackupStorage) StartBackup(ctx context.Context, dir, name string
 	}, nil
 }
 
+// UpdateBackup implements BackupUpdater. Blobs are replaced atomically, so
+// it returns the same handle as StartBackup.
+func (bs *AZBlobBackupStorage) UpdateBackup(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	return bs.StartBackup(ctx, dir, name)
+}
+
 // RemoveBackup implements BackupStorage.
 func (bs *AZBlobBackupStorage) RemoveBackup(ctx context.Context, dir, name string) error {
 	log.Infof("ListBackups: [azblob] container: %s, directory: %s", containerName, objName(dir, ""))
@@ -460,6 +568,20 @@ func (bs *AZBlobBackupStorage) WithParams(params backupstorage.Params) backupsto
 	return bs
 }
 
+// blockSize returns the size of the blocks of the blobs, which is the size of
+// the upload buffers.
+func blockSize() (int, error) {
+	size := azBlobBufferSize.Get()
+	if size > azblob.BlockBlobMaxStageBlockBytes {
+		return 0, fmt.Errorf("azblob_backup_buffer_size (%v) is larger than the maximum size of a block (%v)", size, azblob.BlockBlobMaxStageBlockBytes)
+	}
+	// As in azblob.UploadStreamToBlockBlob, blocks are at least 1 MiB.
+	if size < 1<<20 {
+		size = 1 << 20
+	}
+	return size, nil
+}
+
 // objName joins path parts into an object name.
 // Unlike path.Join, it doesn't collapse ".." or strip trailing slashes.
 // It also adds the value of the -azblob_backup_storage_root flag if set.
@@ -471,5 +593,5 @@ func objName(parts ...string) string {
 }
 
 func init() {
-	backupstorage.BackupStorageMap["azblob"] = &AZBlobBackupStorage{}
+	backupstorage.RegisterBackupStorage("azblob", &AZBlobBackupStorage{})
 }
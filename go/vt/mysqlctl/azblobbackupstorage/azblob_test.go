@@ -0,0 +1,233 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azblobbackupstorage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBlockBlob records the blocks staged and committed, verifying their MD5
+// as Azure does.
+type fakeBlockBlob struct {
+	mu            sync.Mutex
+	staged        map[string][]byte
+	committed     []byte
+	contentMD5    []byte
+	stageBlockErr error
+}
+
+func (b *fakeBlockBlob) StageBlock(ctx context.Context, base64BlockID string, body io.ReadSeeker, ac azblob.LeaseAccessConditions, transactionalMD5 []byte, cpk azblob.ClientProvidedKeyOptions) (*azblob.BlockBlobStageBlockResponse, error) {
+	if b.stageBlockErr != nil {
+		return nil, b.stageBlockErr
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	if checksum := md5.Sum(data); !bytes.Equal(checksum[:], transactionalMD5) {
+		return nil, fmt.Errorf("Md5Mismatch")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.staged == nil {
+		b.staged = make(map[string][]byte)
+	}
+	b.staged[base64BlockID] = data
+	return &azblob.BlockBlobStageBlockResponse{}, nil
+}
+
+func (b *fakeBlockBlob) CommitBlockList(ctx context.Context, base64BlockIDs []string, h azblob.BlobHTTPHeaders, metadata azblob.Metadata, ac azblob.BlobAccessConditions, tier azblob.AccessTierType, blobTagsMap azblob.BlobTagsMap, cpk azblob.ClientProvidedKeyOptions, immutability azblob.ImmutabilityPolicyOptions) (*azblob.BlockBlobCommitBlockListResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.committed = []byte{}
+	for _, id := range base64BlockIDs {
+		data, ok := b.staged[id]
+		if !ok {
+			return nil, fmt.Errorf("InvalidBlockList")
+		}
+		b.committed = append(b.committed, data...)
+	}
+	b.contentMD5 = h.ContentMD5
+	return &azblob.BlockBlobCommitBlockListResponse{}, nil
+}
+
+func TestUploadWithChecksums(t *testing.T) {
+	ctx := context.Background()
+
+	for _, size := range []int{0, 1, 999, 1000, 1001, 10000, 12345} {
+		t.Run(strconv.Itoa(size), func(t *testing.T) {
+			data := make([]byte, size)
+			for i := range data {
+				data[i] = byte(i * 7)
+			}
+
+			blob := &fakeBlockBlob{}
+			err := uploadWithChecksums(ctx, bytes.NewReader(data), blob, 1000, 3)
+			require.NoError(t, err)
+			assert.Equal(t, data, blob.committed)
+			checksum := md5.Sum(data)
+			assert.Equal(t, checksum[:], blob.contentMD5)
+			assert.Len(t, blob.staged, (size+999)/1000)
+		})
+	}
+
+	t.Run("stage error", func(t *testing.T) {
+		blob := &fakeBlockBlob{stageBlockErr: fmt.Errorf("ServerBusy")}
+		err := uploadWithChecksums(ctx, bytes.NewReader(make([]byte, 10000)), blob, 1000, 2)
+		assert.ErrorContains(t, err, "ServerBusy")
+		assert.Nil(t, blob.committed)
+	})
+}
+
+func TestBlockID(t *testing.T) {
+	// All the block IDs of a blob must have the same length.
+	assert.Equal(t, len(blockID(0)), len(blockID(azblob.BlockBlobMaxBlocks-1)))
+	id, err := base64.StdEncoding.DecodeString(blockID(42))
+	require.NoError(t, err)
+	assert.Equal(t, "00000042", string(id))
+}
+
+func TestChecksumReader(t *testing.T) {
+	data := []byte("backup data")
+	checksum := md5.Sum(data)
+
+	r := newChecksumReader(io.NopCloser(bytes.NewReader(data)), "file", checksum[:])
+	got, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+
+	r = newChecksumReader(io.NopCloser(bytes.NewReader([]byte("corrupted data"))), "file", checksum[:])
+	_, err = io.ReadAll(r)
+	assert.ErrorContains(t, err, "MD5 checksum mismatch for file")
+}
+
+func TestBlockSize(t *testing.T) {
+	defer azBlobBufferSize.Set(azBlobBufferSize.Default())
+
+	azBlobBufferSize.Set(1024)
+	size, err := blockSize()
+	require.NoError(t, err)
+	assert.Equal(t, 1<<20, size)
+
+	azBlobBufferSize.Set(256 << 20)
+	size, err = blockSize()
+	require.NoError(t, err)
+	assert.Equal(t, 256<<20, size)
+
+	azBlobBufferSize.Set(azblob.BlockBlobMaxStageBlockBytes + 1)
+	_, err = blockSize()
+	assert.ErrorContains(t, err, "is larger than the maximum size of a block")
+}
+
+func TestAzCredentials(t *testing.T) {
+	defer func() {
+		accountName.Set(accountName.Default())
+		accountKeyFile.Set(accountKeyFile.Default())
+		sasTokenFile.Set(sasTokenFile.Default())
+	}()
+	t.Setenv("VT_AZBLOB_ACCOUNT_KEY", "")
+	t.Setenv("VT_AZBLOB_SAS_TOKEN", "")
+	bs := &AZBlobBackupStorage{}
+
+	_, _, err := bs.azCredentials()
+	assert.ErrorContains(t, err, "Azure Storage Account name not found")
+
+	accountName.Set("account")
+	_, _, err = bs.azCredentials()
+	assert.ErrorContains(t, err, "Azure Storage Account credentials not found")
+
+	// The account key.
+	keyFile := path.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString([]byte("key"))), 0600))
+	accountKeyFile.Set(keyFile)
+	credential, sasToken, err := bs.azCredentials()
+	require.NoError(t, err)
+	assert.IsType(t, &azblob.SharedKeyCredential{}, credential)
+	assert.Empty(t, sasToken)
+
+	// A SAS token is used instead of the account key.
+	t.Setenv("VT_AZBLOB_SAS_TOKEN", "?sv=2022-11-02&sig=env")
+	_, sasToken, err = bs.azCredentials()
+	require.NoError(t, err)
+	assert.Equal(t, "sv=2022-11-02&sig=env", sasToken)
+
+	tokenFile := path.Join(t.TempDir(), "sas")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("sv=2022-11-02&sig=file\n"), 0600))
+	sasTokenFile.Set(tokenFile)
+	_, sasToken, err = bs.azCredentials()
+	require.NoError(t, err)
+	assert.Equal(t, "sv=2022-11-02&sig=file", sasToken)
+
+	u := azServiceURL("account", azblob.NewAnonymousCredential(), sasToken).NewContainerURL("backups").NewBlockBlobURL("ks/0/MANIFEST")
+	blobURL := u.URL()
+	assert.Equal(t, "account.blob.core.windows.net", blobURL.Host)
+	assert.Equal(t, "/backups/ks/0/MANIFEST", blobURL.Path)
+	assert.Equal(t, "sv=2022-11-02&sig=file", blobURL.RawQuery)
+}
+
+func TestManagedIdentityCredential(t *testing.T) {
+	var requests []*http.Request
+	expiresOn := time.Now().Add(time.Hour)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		requests = append(requests, r)
+		if r.Header.Get("Metadata") != "true" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, `{"access_token": "token-%d", "expires_on": "%d", "resource": "%s", "token_type": "Bearer"}`, len(requests), expiresOn.Unix(), storageResource)
+	}))
+	defer server.Close()
+	defer func(endpoint string) { imdsTokenEndpoint = endpoint }(imdsTokenEndpoint)
+	imdsTokenEndpoint = server.URL + "/"
+
+	credential, err := newManagedIdentityCredential(context.Background(), "client-id")
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", credential.Token())
+
+	// The token is not refreshed until it is about to expire.
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, "token-1", credential.Token())
+	require.Len(t, requests, 1)
+	assert.Equal(t, "client-id", requests[0].URL.Query().Get("client_id"))
+	assert.Equal(t, storageResource, requests[0].URL.Query().Get("resource"))
+
+	imdsTokenEndpoint = server.URL + "/missing"
+	_, err = newManagedIdentityCredential(context.Background(), "")
+	assert.ErrorContains(t, err, "cannot get a managed identity token: 404 Not Found")
+}
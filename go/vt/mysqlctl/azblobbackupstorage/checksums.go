@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azblobbackupstorage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"io"
+	"sync"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+
+	"vitess.io/vitess/go/vt/concurrency"
+)
+
+// blockBlob is the part of azblob.BlockBlobURL used to upload blobs with
+// checksums.
+type blockBlob interface {
+	StageBlock(ctx context.Context, base64BlockID string, body io.ReadSeeker, ac azblob.LeaseAccessConditions, transactionalMD5 []byte, cpk azblob.ClientProvidedKeyOptions) (*azblob.BlockBlobStageBlockResponse, error)
+	CommitBlockList(ctx context.Context, base64BlockIDs []string, h azblob.BlobHTTPHeaders, metadata azblob.Metadata, ac azblob.BlobAccessConditions, tier azblob.AccessTierType, blobTagsMap azblob.BlobTagsMap, cpk azblob.ClientProvidedKeyOptions, immutability azblob.ImmutabilityPolicyOptions) (*azblob.BlockBlobCommitBlockListResponse, error)
+}
+
+// blockID returns the ID of the n-th block of a blob. The IDs of the blocks
+// of a blob must all have the same length.
+func blockID(n int) string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%08d", n)))
+}
+
+// uploadWithChecksums uploads what it reads from reader to blob, in blocks of
+// blockSize bytes, staging up to parallelism blocks at a time. Each block is
+// staged with its MD5, which Azure verifies before accepting it, and the MD5
+// of the whole file is stored as the Content-MD5 of the blob.
+func uploadWithChecksums(ctx context.Context, reader io.Reader, blob blockBlob, blockSize, parallelism int) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	sem := make(chan struct{}, parallelism)
+	var (
+		wg     sync.WaitGroup
+		errors concurrency.FirstErrorRecorder
+		ids    []string
+	)
+	fileHash := md5.New()
+
+	for n := 0; ctx.Err() == nil; n++ {
+		buf := make([]byte, blockSize)
+		size, err := io.ReadFull(reader, buf)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			errors.RecordError(err)
+			break
+		}
+		buf = buf[:size]
+		fileHash.Write(buf)
+		id := blockID(n)
+		ids = append(ids, id)
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			checksum := md5.Sum(buf)
+			if _, err := blob.StageBlock(ctx, id, bytes.NewReader(buf), azblob.LeaseAccessConditions{}, checksum[:], azblob.ClientProvidedKeyOptions{}); err != nil {
+				errors.RecordError(fmt.Errorf("write error: %w", err))
+				cancel()
+			}
+		}()
+
+		// A short read is the last block.
+		if err == io.ErrUnexpectedEOF {
+			break
+		}
+	}
+	wg.Wait()
+
+	if errors.HasErrors() {
+		return errors.Error()
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	_, err := blob.CommitBlockList(ctx, ids, azblob.BlobHTTPHeaders{ContentMD5: fileHash.Sum(nil)}, azblob.Metadata{}, azblob.BlobAccessConditions{}, azblob.AccessTierNone, nil, azblob.ClientProvidedKeyOptions{}, azblob.ImmutabilityPolicyOptions{})
+	return err
+}
+
+// checksumReader verifies the MD5 of a file once it has been read entirely.
+type checksumReader struct {
+	io.ReadCloser
+	name     string
+	hash     hash.Hash
+	checksum []byte
+}
+
+func newChecksumReader(r io.ReadCloser, name string, checksum []byte) *checksumReader {
+	return &checksumReader{
+		ReadCloser: r,
+		name:       name,
+		hash:       md5.New(),
+		checksum:   checksum,
+	}
+}
+
+// Read is part of the io.Reader interface.
+func (r *checksumReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.hash.Write(p[:n])
+	if err == io.EOF {
+		if got := r.hash.Sum(nil); !bytes.Equal(got, r.checksum) {
+			return n, fmt.Errorf("MD5 checksum mismatch for %v: got %x, expected %x", r.name, got, r.checksum)
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azblobbackupstorage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+
+	"vitess.io/vitess/go/vt/log"
+)
+
+// imdsTokenEndpoint is the endpoint of the Azure Instance Metadata Service
+// issuing the tokens of the managed identities of the VM.
+var imdsTokenEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+
+const (
+	// storageResource is the resource the tokens are requested for.
+	storageResource = "https://storage.azure.com/"
+	// tokenRefreshMargin is how long before it expires a token is refreshed.
+	tokenRefreshMargin = 5 * time.Minute
+	// tokenRetryInterval is how long to wait before trying again to refresh
+	// a token after a failure.
+	tokenRetryInterval = 30 * time.Second
+)
+
+// managedIdentityToken is the response of the Azure Instance Metadata
+// Service. Times are in seconds since the epoch, as strings.
+type managedIdentityToken struct {
+	AccessToken string `json:"access_token"`
+	ExpiresOn   string `json:"expires_on"`
+}
+
+// fetchManagedIdentityToken gets a token for Azure Storage from the Azure
+// Instance Metadata Service, for the managed identity with the given client ID,
+// or the system-assigned managed identity if it is empty.
+func fetchManagedIdentityToken(ctx context.Context, clientID string) (string, time.Time, error) {
+	query := url.Values{}
+	query.Set("api-version", "2018-02-01")
+	query.Set("resource", storageResource)
+	if clientID != "" {
+		query.Set("client_id", clientID)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, tokenRetryInterval)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imdsTokenEndpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Metadata", "true")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("cannot get a managed identity token: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("cannot get a managed identity token: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", time.Time{}, fmt.Errorf("cannot get a managed identity token: %v: %s", resp.Status, body)
+	}
+
+	var token managedIdentityToken
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", time.Time{}, fmt.Errorf("cannot parse the managed identity token: %w", err)
+	}
+	expiresOn, err := strconv.ParseInt(token.ExpiresOn, 10, 64)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("cannot parse the expiry of the managed identity token: %w", err)
+	}
+	return token.AccessToken, time.Unix(expiresOn, 0), nil
+}
+
+// newManagedIdentityCredential returns a credential using the tokens of a
+// managed identity, refreshed in the background before they expire.
+func newManagedIdentityCredential(ctx context.Context, clientID string) (azblob.TokenCredential, error) {
+	token, expiresOn, err := fetchManagedIdentityToken(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	return azblob.NewTokenCredential(token, func(credential azblob.TokenCredential) time.Duration {
+		// The refresher is first called right away, with a fresh token.
+		if d := time.Until(expiresOn) - tokenRefreshMargin; d > 0 {
+			return d
+		}
+
+		token, newExpiresOn, err := fetchManagedIdentityToken(context.Background(), clientID)
+		if err != nil {
+			log.Warningf("Failed to refresh the Azure managed identity token, retrying in %v: %v", tokenRetryInterval, err)
+			return tokenRetryInterval
+		}
+		credential.SetToken(token)
+		expiresOn = newExpiresOn
+		if d := time.Until(expiresOn) - tokenRefreshMargin; d > tokenRetryInterval {
+			return d
+		}
+		return tokenRetryInterval
+	}), nil
+}
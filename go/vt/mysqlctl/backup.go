@@ -343,6 +343,9 @@ func ensureRestoredGTIDPurgedMatchesManifest(ctx context.Context, manifest *Back
 	params.Logger.Infof("Restore: @@gtid_purged does not equal manifest's GTID position. Setting @@gtid_purged to %v", gtid)
 	// This is not good. We want to apply a new @@gtid_purged value.
 	query := "RESET MASTER" // required dialect in 5.7
+	if versionString, err := params.Mysqld.GetVersionString(ctx); err == nil {
+		query = resetBinaryLogsCommand(versionString)
+	}
 	if _, err := params.Mysqld.FetchSuperQuery(ctx, query); err != nil {
 		return vterrors.Wrapf(err, "error issuing %v", query)
 	}
@@ -353,6 +356,21 @@ func ensureRestoredGTIDPurgedMatchesManifest(ctx context.Context, manifest *Back
 	return nil
 }
 
+// resetBinaryLogsCommand returns the statement resetting the binary logs and
+// the GTID sets of the server of the given version string: RESET MASTER is
+// removed in MySQL 8.4.
+func resetBinaryLogsCommand(versionString string) string {
+	flavor, version, err := ParseVersionString(versionString)
+	if err != nil {
+		return "RESET MASTER"
+	}
+	capabilities := capabilitySet{flavor: flavor, version: version}
+	if capabilities.hasReplicaTerminologyOnly() {
+		return "RESET BINARY LOGS AND GTIDS"
+	}
+	return "RESET MASTER"
+}
+
 // Restore is the main entry point for backup restore.  If there is no
 // appropriate backup on the BackupStorage, Restore logs an error
 // and returns ErrNoBackup. Any other error is returned.
@@ -19,7 +19,9 @@ package mysqlctl_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"strings"
@@ -40,6 +42,7 @@ import (
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/mysqlctl"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
 	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
 	"vitess.io/vitess/go/vt/proto/topodata"
 	"vitess.io/vitess/go/vt/proto/vttime"
@@ -580,6 +583,118 @@ func TestExecuteRestoreWithTimedOutContext(t *testing.T) {
 	}
 }
 
+// multipartFileBackupHandle is a file backup handle which supports multipart
+// uploads, by writing the files as is.
+type multipartFileBackupHandle struct {
+	backupstorage.BackupHandle
+}
+
+func (bh *multipartFileBackupHandle) AddFileMultipart(ctx context.Context, filename string, filesize int64) (io.WriteCloser, error) {
+	return bh.AddFile(ctx, filename, filesize)
+}
+
+func TestExecuteBackupS3Stream(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+
+	// Set up local backup directory
+	id := fmt.Sprintf("%d", time.Now().UnixNano())
+	backupRoot := fmt.Sprintf("testdata/s3streambackup_test_%s", id)
+	filebackupstorage.FileBackupStorageRoot = backupRoot
+	require.NoError(t, createBackupDir(backupRoot, "innodb", "log", "datadir"))
+	dataDir := path.Join(backupRoot, "datadir")
+	require.NoError(t, createBackupDir(dataDir, "test1"))
+	require.NoError(t, createBackupFiles(path.Join(dataDir, "test1"), 2, "ibd"))
+	defer os.RemoveAll(backupRoot)
+
+	// Set up topo
+	keyspace, shard := "mykeyspace", "-80"
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+
+	require.NoError(t, ts.CreateKeyspace(ctx, keyspace, &topodata.Keyspace{}))
+	require.NoError(t, ts.CreateShard(ctx, keyspace, shard))
+
+	tablet := topo.NewTablet(100, "cell1", "mykeyspace-00-80-0100")
+	tablet.Keyspace = keyspace
+	tablet.Shard = shard
+
+	require.NoError(t, ts.CreateTablet(ctx, tablet))
+
+	_, err := ts.UpdateShardFields(ctx, keyspace, shard, func(si *topo.ShardInfo) error {
+		si.PrimaryAlias = &topodata.TabletAlias{Uid: 100, Cell: "cell1"}
+
+		now := time.Now()
+		si.PrimaryTermStartTime = &vttime.Time{Seconds: int64(now.Second()), Nanoseconds: int32(now.Nanosecond())}
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	fakedb := fakesqldb.New(t)
+	defer fakedb.Close()
+	mysqld := mysqlctl.NewFakeMysqlDaemon(fakedb)
+	defer mysqld.Close()
+	mysqld.ExpectedExecuteSuperQueryList = []string{"STOP SLAVE", "START SLAVE"}
+
+	be := &mysqlctl.S3StreamBackupEngine{}
+	params := mysqlctl.BackupParams{
+		Logger: logutil.NewConsoleLogger(),
+		Mysqld: mysqld,
+		Cnf: &mysqlctl.Mycnf{
+			InnodbDataHomeDir:     path.Join(backupRoot, "innodb"),
+			InnodbLogGroupHomeDir: path.Join(backupRoot, "log"),
+			DataDir:               path.Join(backupRoot, "datadir"),
+		},
+		Stats:        backupstats.NewFakeStats(),
+		Concurrency:  2,
+		HookExtraEnv: map[string]string{},
+		TopoServer:   ts,
+		Keyspace:     keyspace,
+		Shard:        shard,
+	}
+
+	// The engine requires a storage which supports multipart uploads.
+	ok, err := be.ExecuteBackup(ctx, params, filebackupstorage.NewBackupHandle(nil, "", "", false))
+	require.ErrorContains(t, err, "the s3stream backup engine requires a backup storage which supports multipart uploads")
+	assert.False(t, ok)
+
+	bh := &multipartFileBackupHandle{filebackupstorage.NewBackupHandle(nil, "", "", false)}
+	ok, err = be.ExecuteBackup(ctx, params, bh)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// The MANIFEST records the engine, and the SHA-256 checksum of each file.
+	rbh := filebackupstorage.NewBackupHandle(nil, "", "", true)
+	manifest, err := mysqlctl.GetBackupManifest(ctx, rbh)
+	require.NoError(t, err)
+	assert.Equal(t, "s3stream", manifest.BackupMethod)
+	data, err := os.ReadFile(path.Join(backupRoot, "MANIFEST"))
+	require.NoError(t, err)
+	var bm struct {
+		FileEntries []mysqlctl.FileEntry
+	}
+	require.NoError(t, json.Unmarshal(data, &bm))
+	require.Len(t, bm.FileEntries, 2)
+	for _, fe := range bm.FileEntries {
+		assert.Len(t, fe.SHA256, 64)
+	}
+
+	filesChecked, results, err := mysqlctl.ValidateBackupFiles(ctx, logutil.NewMemoryLogger(), rbh)
+	require.NoError(t, err)
+	assert.Equal(t, 2, filesChecked)
+	assert.Empty(t, results)
+
+	// A file which does not match its checksum is reported.
+	bm.FileEntries[1].SHA256 = strings.Repeat("0", 64)
+	data, err = json.Marshal(bm)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path.Join(backupRoot, "MANIFEST"), data, 0644))
+	_, results, err = mysqlctl.ValidateBackupFiles(ctx, logutil.NewMemoryLogger(), rbh)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Contains(t, results[0], "SHA-256 checksum mismatch")
+}
+
 // needInnoDBRedoLogSubdir indicates whether we need to create a redo log subdirectory.
 // Starting with MySQL 8.0.30, the InnoDB redo logs are stored in a subdirectory of the
 // <innodb_log_group_home_dir> (<datadir>/. by default) called "#innodb_redo". See:
@@ -0,0 +1,166 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// BackupRetentionPolicy decides which backups of a shard are kept. Whatever
+// the policy, the latest complete full backup is always kept, along with the
+// latest one that passed its restore drill if that one failed it, and the
+// incremental backups taken since the oldest full backup kept.
+type BackupRetentionPolicy struct {
+	// KeepFullBackups is the number of most recent complete full backups to keep
+	KeepFullBackups int
+
+	// MinRetentionTime is how long backups are kept, whatever their number
+	MinRetentionTime time.Duration
+}
+
+// IsZero returns true if the policy sets neither a number of full backups
+// nor a minimum retention time.
+func (p BackupRetentionPolicy) IsZero() bool {
+	return p.KeepFullBackups == 0 && p.MinRetentionTime == 0
+}
+
+func (p BackupRetentionPolicy) String() string {
+	return fmt.Sprintf("keep %d full backups, and backups younger than %v", p.KeepFullBackups, p.MinRetentionTime)
+}
+
+// PurgeBackups removes the backups of a backup directory which are not kept
+// by the retention policy, and returns the names of the backups purged and
+// kept, oldest first. When dryRun is set, the backups are only listed.
+//
+// Backups whose name cannot be parsed are kept, and so are the incomplete
+// backups newer than the latest complete one, which may still be in
+// progress. Nothing is purged from a directory without a complete full
+// backup.
+func PurgeBackups(ctx context.Context, logger logutil.Logger, bs backupstorage.BackupStorage, dir string, policy BackupRetentionPolicy, dryRun bool) (purged []string, kept []string, err error) {
+	if policy.IsZero() {
+		return nil, nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "the backup retention policy must keep a number of full backups or set a minimum retention time")
+	}
+	if policy.KeepFullBackups < 0 || policy.MinRetentionTime < 0 {
+		return nil, nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "invalid backup retention policy: %v", policy)
+	}
+
+	bhs, err := bs.ListBackups(ctx, dir)
+	if err != nil {
+		return nil, nil, vterrors.Wrap(err, "cannot list backups")
+	}
+
+	keep := backupsToKeep(ctx, logger, dir, bhs, policy, time.Now())
+	for i, bh := range bhs {
+		if keep[i] {
+			kept = append(kept, bh.Name())
+			continue
+		}
+
+		if dryRun {
+			logger.Infof("Would remove backup %v/%v (%v)", dir, bh.Name(), policy)
+		} else {
+			logger.Infof("Removing backup %v/%v (%v)", dir, bh.Name(), policy)
+			if err := bs.RemoveBackup(ctx, dir, bh.Name()); err != nil {
+				return purged, kept, vterrors.Wrapf(err, "cannot remove backup %v/%v", dir, bh.Name())
+			}
+		}
+		purged = append(purged, bh.Name())
+	}
+	return purged, kept, nil
+}
+
+// backupsToKeep returns which of the backups, sorted oldest first, are kept
+// by the policy.
+func backupsToKeep(ctx context.Context, logger logutil.Logger, dir string, bhs []backupstorage.BackupHandle, policy BackupRetentionPolicy, now time.Time) []bool {
+	keep := make([]bool, len(bhs))
+
+	// The manifest of incomplete backups is nil.
+	manifests := make([]*BackupManifest, len(bhs))
+	for i, bh := range bhs {
+		if manifest, err := GetBackupManifest(ctx, bh); err == nil {
+			manifests[i] = manifest
+		}
+	}
+
+	lastComplete, lastFull, lastPassedFull, oldestKeptFull := -1, -1, -1, -1
+	fullBackups := 0
+	for i := len(bhs) - 1; i >= 0; i-- {
+		manifest := manifests[i]
+		if manifest == nil {
+			continue
+		}
+		if lastComplete < 0 {
+			lastComplete = i
+		}
+		if manifest.Incremental {
+			continue
+		}
+
+		if lastFull < 0 {
+			lastFull = i
+			keep[i] = true
+		}
+		if lastPassedFull < 0 && (manifest.Verification == nil || manifest.Verification.Passed()) {
+			lastPassedFull = i
+			keep[i] = true
+		}
+		if fullBackups < policy.KeepFullBackups {
+			fullBackups++
+			keep[i] = true
+		}
+		if keep[i] {
+			oldestKeptFull = i
+		}
+	}
+
+	if lastFull < 0 {
+		logger.Warningf("Not purging any backup from %v since it has no complete full backup", dir)
+		for i := range keep {
+			keep[i] = true
+		}
+		return keep
+	}
+
+	for i, bh := range bhs {
+		if keep[i] {
+			continue
+		}
+
+		backupTime, _, err := ParseBackupName(dir, bh.Name())
+		switch {
+		case err != nil || backupTime == nil:
+			logger.Warningf("Keeping backup %v/%v since the time it was taken cannot be parsed from its name", dir, bh.Name())
+			keep[i] = true
+		case now.Sub(*backupTime) < policy.MinRetentionTime:
+			keep[i] = true
+		case manifests[i] == nil:
+			// Incomplete backups newer than the latest complete one may still
+			// be in progress.
+			keep[i] = i > lastComplete
+		case manifests[i].Incremental:
+			keep[i] = i > oldestKeptFull
+		}
+	}
+	return keep
+}
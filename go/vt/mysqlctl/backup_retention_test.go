@@ -0,0 +1,164 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+)
+
+// retentionTestBackup is a backup taken the given number of days ago.
+type retentionTestBackup struct {
+	days     int
+	manifest *BackupManifest
+}
+
+// addRetentionTestBackup writes a backup taken age ago to ks/0, and returns
+// its name. A nil manifest leaves the backup incomplete.
+func addRetentionTestBackup(t *testing.T, bs backupstorage.BackupStorage, age time.Duration, manifest *BackupManifest) string {
+	ctx := context.Background()
+	name := fmt.Sprintf("%v.zone1-0000000100", time.Now().Add(-age).UTC().Format(BackupTimestampFormat))
+	bh, err := bs.StartBackup(ctx, "ks/0", name)
+	require.NoError(t, err)
+	if manifest != nil {
+		data, err := json.Marshal(manifest)
+		require.NoError(t, err)
+		wc, err := bh.AddFile(ctx, backupManifestFileName, int64(len(data)))
+		require.NoError(t, err)
+		_, err = wc.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, wc.Close())
+	}
+	require.NoError(t, bh.EndBackup(ctx))
+	return name
+}
+
+func TestPurgeBackups(t *testing.T) {
+	day := 24 * time.Hour
+	full := &BackupManifest{BackupMethod: builtinBackupEngineName}
+	incremental := &BackupManifest{BackupMethod: builtinBackupEngineName, Incremental: true}
+	failed := &BackupManifest{BackupMethod: builtinBackupEngineName, Verification: &BackupVerification{Error: "restore failed"}}
+
+	tcs := []struct {
+		name    string
+		policy  BackupRetentionPolicy
+		backups []retentionTestBackup
+		// kept are the indexes of the backups kept
+		kept []int
+	}{
+		{
+			name:    "keep full backups",
+			policy:  BackupRetentionPolicy{KeepFullBackups: 2},
+			backups: []retentionTestBackup{{6, full}, {5, incremental}, {4, full}, {3, incremental}, {2, full}, {1, incremental}},
+			kept:    []int{2, 3, 4, 5},
+		},
+		{
+			name:    "min retention time",
+			policy:  BackupRetentionPolicy{KeepFullBackups: 1, MinRetentionTime: 3*day + time.Hour},
+			backups: []retentionTestBackup{{6, full}, {5, full}, {3, full}, {2, full}, {1, full}},
+			kept:    []int{2, 3, 4},
+		},
+		{
+			name:    "latest full backup always kept",
+			policy:  BackupRetentionPolicy{MinRetentionTime: day},
+			backups: []retentionTestBackup{{6, full}, {5, full}, {4, incremental}, {3, incremental}},
+			kept:    []int{1, 2, 3},
+		},
+		{
+			name:    "latest passed full backup always kept",
+			policy:  BackupRetentionPolicy{KeepFullBackups: 1},
+			backups: []retentionTestBackup{{6, full}, {5, full}, {4, incremental}, {3, failed}, {2, incremental}},
+			kept:    []int{1, 2, 3, 4},
+		},
+		{
+			name:    "incomplete backups",
+			policy:  BackupRetentionPolicy{KeepFullBackups: 1},
+			backups: []retentionTestBackup{{6, full}, {5, nil}, {4, full}, {3, nil}},
+			kept:    []int{2, 3},
+		},
+		{
+			name:    "no complete full backup",
+			policy:  BackupRetentionPolicy{KeepFullBackups: 1},
+			backups: []retentionTestBackup{{6, nil}, {5, incremental}, {4, nil}},
+			kept:    []int{0, 1, 2},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			filebackupstorage.FileBackupStorageRoot = t.TempDir()
+			bs := backupstorage.BackupStorageMap["file"]
+			ctx := context.Background()
+
+			var names, wantKept, wantPurged []string
+			for i, b := range tc.backups {
+				name := addRetentionTestBackup(t, bs, time.Duration(b.days)*day, b.manifest)
+				names = append(names, name)
+				if slices.Contains(tc.kept, i) {
+					wantKept = append(wantKept, name)
+				} else {
+					wantPurged = append(wantPurged, name)
+				}
+			}
+
+			purged, kept, err := PurgeBackups(ctx, logutil.NewMemoryLogger(), bs, "ks/0", tc.policy, true)
+			require.NoError(t, err)
+			assert.Equal(t, wantPurged, purged)
+			assert.Equal(t, wantKept, kept)
+
+			// A dry run does not remove anything
+			bhs, err := bs.ListBackups(ctx, "ks/0")
+			require.NoError(t, err)
+			assert.Len(t, bhs, len(names))
+
+			purged, kept, err = PurgeBackups(ctx, logutil.NewMemoryLogger(), bs, "ks/0", tc.policy, false)
+			require.NoError(t, err)
+			assert.Equal(t, wantPurged, purged)
+			assert.Equal(t, wantKept, kept)
+
+			bhs, err = bs.ListBackups(ctx, "ks/0")
+			require.NoError(t, err)
+			var remaining []string
+			for _, bh := range bhs {
+				remaining = append(remaining, bh.Name())
+			}
+			assert.Equal(t, wantKept, remaining)
+		})
+	}
+}
+
+func TestPurgeBackupsInvalidPolicy(t *testing.T) {
+	ctx := context.Background()
+	logger := logutil.NewMemoryLogger()
+
+	_, _, err := PurgeBackups(ctx, logger, &FakeBackupStorage{}, "ks/0", BackupRetentionPolicy{}, true)
+	assert.ErrorContains(t, err, "must keep a number of full backups or set a minimum retention time")
+
+	_, _, err = PurgeBackups(ctx, logger, &FakeBackupStorage{}, "ks/0", BackupRetentionPolicy{KeepFullBackups: -1}, true)
+	assert.ErrorContains(t, err, "invalid backup retention policy")
+}
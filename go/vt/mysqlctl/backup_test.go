@@ -133,6 +133,26 @@ func TestBackupTriesToParameterizeBackupStorage(t *testing.T) {
 	require.NotNil(t, scopedStats)
 }
 
+func TestRegisterBackupEngine(t *testing.T) {
+	engine := &FakeBackupEngine{}
+	RegisterBackupEngine("test-registered", engine)
+	defer delete(BackupRestoreEngineMap, "test-registered")
+
+	require.Panics(t, func() { RegisterBackupEngine("test-registered", &FakeBackupEngine{}) })
+
+	previousBackupEngineImplementation := backupEngineImplementation
+	defer func() { backupEngineImplementation = previousBackupEngineImplementation }()
+
+	backupEngineImplementation = "test-registered"
+	be, err := GetBackupEngine()
+	require.NoError(t, err)
+	require.Same(t, engine, be)
+
+	backupEngineImplementation = "test-unregistered"
+	_, err = GetBackupEngine()
+	require.ErrorContains(t, err, `unknown BackupEngine implementation "test-unregistered", registered implementations are: builtin, s3stream, test-registered, xtrabackup`)
+}
+
 func TestFindFilesToBackupWithoutRedoLog(t *testing.T) {
 	root := t.TempDir()
 
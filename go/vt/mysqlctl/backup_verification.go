@@ -0,0 +1,155 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// BackupVerification is the result of a restore drill, in which a backup was
+// restored to a scratch instance, caught up with a live replica, and compared
+// with it. It is recorded in the MANIFEST of the backup.
+type BackupVerification struct {
+	// Time is when the backup was verified (RFC 3339 format, UTC)
+	Time string
+
+	// Replica is the alias of the replica the restored data was compared with
+	Replica string
+
+	// Position is the replication position at which the data was compared
+	Position string
+
+	// TablesChecked is the number of tables whose checksum and row count were compared
+	TablesChecked int
+
+	// Mismatches lists the tables which differ between the restored data and the replica
+	Mismatches []string `json:",omitempty"`
+
+	// Error is set when the drill failed before the data could be compared
+	Error string `json:",omitempty"`
+}
+
+// Passed returns true if the restored data matched the replica.
+func (v *BackupVerification) Passed() bool {
+	return v.Error == "" && len(v.Mismatches) == 0
+}
+
+// RecordBackupVerification records the verification in the MANIFEST of the
+// backup. The other fields of the MANIFEST, including the ones specific to
+// the backup engine, are kept as is. The BackupStorage must implement
+// backupstorage.BackupUpdater.
+func RecordBackupVerification(ctx context.Context, bs backupstorage.BackupStorage, bh backupstorage.BackupHandle, verification *BackupVerification) error {
+	updater, ok := bs.(backupstorage.BackupUpdater)
+	if !ok {
+		return vterrors.Errorf(vtrpc.Code_UNIMPLEMENTED, "backup storage %T cannot update existing backups", bs)
+	}
+
+	manifest := map[string]json.RawMessage{}
+	if err := getBackupManifestInto(ctx, bh, &manifest); err != nil {
+		return err
+	}
+	data, err := json.Marshal(verification)
+	if err != nil {
+		return vterrors.Wrap(err, "cannot JSON encode backup verification")
+	}
+	manifest["Verification"] = data
+	data, err = json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot JSON encode %v", backupManifestFileName)
+	}
+
+	ubh, err := updater.UpdateBackup(ctx, bh.Directory(), bh.Name())
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot update backup %v", bh.Name())
+	}
+	wc, err := ubh.AddFile(ctx, backupManifestFileName, int64(len(data)))
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot add %v to backup", backupManifestFileName)
+	}
+	if _, err := wc.Write(data); err != nil {
+		wc.Close()
+		return vterrors.Wrapf(err, "cannot write %v", backupManifestFileName)
+	}
+	if err := wc.Close(); err != nil {
+		return vterrors.Wrapf(err, "cannot close %v", backupManifestFileName)
+	}
+	return ubh.EndBackup(ctx)
+}
+
+// ValidateBackupFiles checks that the files of a backup can be read from the
+// BackupStorage, and that they match the hashes and sizes recorded in the
+// MANIFEST. It returns the number of files checked and the problems found.
+// Only the builtin and s3stream backup engines record the hashes of the
+// files, so only the MANIFEST is checked for the backups of other engines.
+func ValidateBackupFiles(ctx context.Context, logger logutil.Logger, bh backupstorage.BackupHandle) (filesChecked int, results []string, err error) {
+	var bm builtinBackupManifest
+	if err := getBackupManifestInto(ctx, bh, &bm); err != nil {
+		return 0, nil, err
+	}
+	if bm.BackupMethod != "" && bm.BackupMethod != builtinBackupEngineName && bm.BackupMethod != s3StreamBackupEngineName {
+		logger.Infof("Backup %v was taken with the %v engine, which does not record the hashes of its files; only the MANIFEST was checked", bh.Name(), bm.BackupMethod)
+		return 0, nil, nil
+	}
+
+	for i := range bm.FileEntries {
+		if err := ctx.Err(); err != nil {
+			return filesChecked, results, err
+		}
+		fe := &bm.FileEntries[i]
+		if err := validateBackupFile(ctx, bh, fmt.Sprintf("%v", i), fe); err != nil {
+			results = append(results, fmt.Sprintf("%v (%v/%v): %v", i, fe.Base, fe.Name, err))
+		}
+		filesChecked++
+	}
+	logger.Infof("Checked %d files of backup %v, found %d problems", filesChecked, bh.Name(), len(results))
+	return filesChecked, results, nil
+}
+
+func validateBackupFile(ctx context.Context, bh backupstorage.BackupHandle, name string, fe *FileEntry) error {
+	source, err := bh.ReadFile(ctx, name)
+	if err != nil {
+		return vterrors.Wrap(err, "can't open file")
+	}
+	defer source.Close()
+
+	br := newBackupReader(name, 0, source)
+	if fe.SHA256 != "" {
+		br.sha256 = sha256.New()
+	}
+	if _, err := io.Copy(io.Discard, br); err != nil {
+		return vterrors.Wrap(err, "can't read file")
+	}
+	if hash := br.HashString(); hash != fe.Hash {
+		return fmt.Errorf("hash mismatch, got %v expected %v", hash, fe.Hash)
+	}
+	if checksum := br.SHA256String(); checksum != fe.SHA256 {
+		return fmt.Errorf("SHA-256 checksum mismatch, got %v expected %v", checksum, fe.SHA256)
+	}
+	if fe.Size != 0 && br.nn != fe.Size {
+		return fmt.Errorf("size mismatch, got %v expected %v", br.nn, fe.Size)
+	}
+	return nil
+}
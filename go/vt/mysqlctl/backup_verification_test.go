@@ -0,0 +1,136 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"hash/crc32"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+)
+
+func crc32Hex(data string) string {
+	h := crc32.NewIEEE()
+	h.Write([]byte(data))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeTestBackup writes a backup with the given MANIFEST and files, and returns its read-only handle.
+func writeTestBackup(t *testing.T, bs backupstorage.BackupStorage, manifest any, files ...string) backupstorage.BackupHandle {
+	ctx := context.Background()
+	bh, err := bs.StartBackup(ctx, "ks/0", "zone1-0000000100-2023-10-15-10-00-00")
+	require.NoError(t, err)
+	for i, contents := range files {
+		wc, err := bh.AddFile(ctx, string(rune('0'+i)), int64(len(contents)))
+		require.NoError(t, err)
+		_, err = wc.Write([]byte(contents))
+		require.NoError(t, err)
+		require.NoError(t, wc.Close())
+	}
+	data, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	wc, err := bh.AddFile(ctx, backupManifestFileName, int64(len(data)))
+	require.NoError(t, err)
+	_, err = wc.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, wc.Close())
+	require.NoError(t, bh.EndBackup(ctx))
+
+	bhs, err := bs.ListBackups(ctx, "ks/0")
+	require.NoError(t, err)
+	require.Len(t, bhs, 1)
+	return bhs[0]
+}
+
+func TestValidateBackupFiles(t *testing.T) {
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+	bs := backupstorage.BackupStorageMap["file"]
+	ctx := context.Background()
+	logger := logutil.NewMemoryLogger()
+
+	bh := writeTestBackup(t, bs, &builtinBackupManifest{
+		BackupManifest: BackupManifest{BackupMethod: builtinBackupEngineName},
+		FileEntries: []FileEntry{
+			{Base: backupData, Name: "ibdata1", Hash: crc32Hex("first file"), Size: 10},
+			{Base: backupData, Name: "ks/t1.ibd", Hash: crc32Hex("second file"), Size: 11},
+			{Base: backupData, Name: "ks/t2.ibd", Hash: crc32Hex("third file"), Size: 10},
+		},
+	}, "first file", "second file", "third file, truncated")
+
+	filesChecked, results, err := ValidateBackupFiles(ctx, logger, bh)
+	require.NoError(t, err)
+	assert.Equal(t, 3, filesChecked)
+	require.Len(t, results, 1)
+	assert.Contains(t, results[0], "2 (Data/ks/t2.ibd): hash mismatch")
+}
+
+func TestValidateBackupFilesOtherEngine(t *testing.T) {
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+	bs := backupstorage.BackupStorageMap["file"]
+	logger := logutil.NewMemoryLogger()
+
+	bh := writeTestBackup(t, bs, &BackupManifest{BackupMethod: xtrabackupEngineName})
+	filesChecked, results, err := ValidateBackupFiles(context.Background(), logger, bh)
+	require.NoError(t, err)
+	assert.Equal(t, 0, filesChecked)
+	assert.Empty(t, results)
+	assert.Contains(t, logger.String(), "only the MANIFEST was checked")
+}
+
+func TestRecordBackupVerification(t *testing.T) {
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+	bs := backupstorage.BackupStorageMap["file"]
+	ctx := context.Background()
+
+	bh := writeTestBackup(t, bs, map[string]any{
+		"BackupMethod":      xtrabackupEngineName,
+		"NumStripes":        2,
+		"SkipCompress":      true,
+		"CompressionEngine": "pgzip",
+	})
+
+	verification := &BackupVerification{
+		Time:          "2023-10-15T12:00:00Z",
+		Replica:       "zone1-0000000101",
+		Position:      "MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-615",
+		TablesChecked: 3,
+		Mismatches:    []string{"ks.t2"},
+	}
+	assert.False(t, verification.Passed())
+	require.NoError(t, RecordBackupVerification(ctx, bs, bh, verification))
+
+	manifest, err := GetBackupManifest(ctx, bh)
+	require.NoError(t, err)
+	assert.Equal(t, xtrabackupEngineName, manifest.BackupMethod)
+	assert.Equal(t, verification, manifest.Verification)
+
+	// The fields specific to the backup engine are kept
+	var xbm xtraBackupManifest
+	require.NoError(t, getBackupManifestInto(ctx, bh, &xbm))
+	assert.EqualValues(t, 2, xbm.NumStripes)
+
+	err = RecordBackupVerification(ctx, &FakeBackupStorage{}, bh, verification)
+	assert.ErrorContains(t, err, "cannot update existing backups")
+}
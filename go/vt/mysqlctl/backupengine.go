@@ -23,6 +23,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -130,6 +131,9 @@ type RestoreParams struct {
 	DryRun bool
 	// Stats let's restore engines report detailed restore timings.
 	Stats backupstats.Stats
+	// Progress, if set, is called periodically with the progress of the restore
+	// of each backup, and once more when the files of a backup are restored.
+	Progress func(*tabletmanagerdatapb.RestoreProgress)
 }
 
 func (p *RestoreParams) Copy() RestoreParams {
@@ -148,6 +152,7 @@ func (p *RestoreParams) Copy() RestoreParams {
 		RestoreToTimestamp:  p.RestoreToTimestamp,
 		DryRun:              p.DryRun,
 		Stats:               p.Stats,
+		Progress:            p.Progress,
 	}
 }
 
@@ -177,6 +182,28 @@ type BackupRestoreEngine interface {
 // BackupEngine and RestoreEngine.
 var BackupRestoreEngineMap = make(map[string]BackupRestoreEngine)
 
+// RegisterBackupEngine registers a BackupRestoreEngine implementation under the given name.
+// --backup_engine_implementation selects the engine which takes new backups by this name, and
+// the name is recorded as the BackupMethod of the MANIFEST of each backup, so that the same engine
+// restores it. A third party engine registers itself in an init() function, and is linked into
+// the binaries with a plugin import, as the backup storage implementations are.
+func RegisterBackupEngine(name string, engine BackupRestoreEngine) {
+	if _, ok := BackupRestoreEngineMap[name]; ok {
+		panic(fmt.Sprintf("BackupEngine %s is already registered", name))
+	}
+	BackupRestoreEngineMap[name] = engine
+}
+
+// registeredBackupEngineNames returns the sorted names of the registered BackupEngine implementations
+func registeredBackupEngineNames() []string {
+	names := make([]string, 0, len(BackupRestoreEngineMap))
+	for name := range BackupRestoreEngineMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func init() {
 	for _, cmd := range []string{"vtcombo", "vttablet", "vttestserver", "vtctld", "vtbackup"} {
 		servenv.OnParseFor(cmd, registerBackupEngineFlags)
@@ -189,7 +216,7 @@ func isIncrementalBackup(params BackupParams) bool {
 }
 
 func registerBackupEngineFlags(fs *pflag.FlagSet) {
-	fs.StringVar(&backupEngineImplementation, "backup_engine_implementation", backupEngineImplementation, "Specifies which implementation to use for creating new backups (builtin or xtrabackup). Restores will always be done with whichever engine created a given backup.")
+	fs.StringVar(&backupEngineImplementation, "backup_engine_implementation", backupEngineImplementation, "Specifies which implementation to use for creating new backups (builtin, s3stream or xtrabackup). Restores will always be done with whichever engine created a given backup.")
 }
 
 // GetBackupEngine returns the BackupEngine implementation that should be used
@@ -203,7 +230,7 @@ func GetBackupEngine() (BackupEngine, error) {
 	name := backupEngineImplementation
 	be, ok := BackupRestoreEngineMap[name]
 	if !ok {
-		return nil, vterrors.Errorf(vtrpc.Code_NOT_FOUND, "unknown BackupEngine implementation %q", name)
+		return nil, vterrors.Errorf(vtrpc.Code_NOT_FOUND, "unknown BackupEngine implementation %q, registered implementations are: %s", name, strings.Join(registeredBackupEngineNames(), ", "))
 	}
 	return be, nil
 }
@@ -309,6 +336,9 @@ type BackupManifest struct {
 
 	// IncrementalDetails is nil for non-incremental backups
 	IncrementalDetails *IncrementalBackupDetails
+
+	// Verification is the result of the last restore drill of the backup, if any
+	Verification *BackupVerification `json:",omitempty"`
 }
 
 func (m *BackupManifest) HashKey() string {
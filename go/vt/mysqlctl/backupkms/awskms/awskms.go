@@ -0,0 +1,116 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package awskms implements the backupkms.KMS interface with AWS KMS.
+package awskms
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/vt/mysqlctl/backupkms"
+	"vitess.io/vitess/go/vt/servenv"
+)
+
+var (
+	// region is the AWS region of the KMS keys
+	region string
+
+	// endpoint overrides the AWS KMS endpoint, e.g. for a VPC endpoint
+	endpoint string
+)
+
+func registerFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&region, "backup-encryption-aws-kms-region", "", "AWS region of the KMS key which wraps the backup data keys. Defaults to the region of the AWS environment.")
+	fs.StringVar(&endpoint, "backup-encryption-aws-kms-endpoint", "", "endpoint of the AWS KMS service, if not the default one of the region.")
+}
+
+func init() {
+	servenv.OnParseFor("vtbackup", registerFlags)
+	servenv.OnParseFor("vttablet", registerFlags)
+}
+
+// AWSKMS implements backupkms.KMS with AWS KMS. The key ID is the ID, ARN or
+// alias of a symmetric KMS key.
+type AWSKMS struct {
+	mu      sync.Mutex
+	_client *kms.KMS
+}
+
+// WrapKey is part of the backupkms.KMS interface.
+func (k *AWSKMS) WrapKey(ctx context.Context, keyID string, dataKey []byte) ([]byte, error) {
+	c, err := k.client()
+	if err != nil {
+		return nil, err
+	}
+	out, err := c.EncryptWithContext(ctx, &kms.EncryptInput{
+		KeyId:     aws.String(keyID),
+		Plaintext: dataKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.CiphertextBlob, nil
+}
+
+// UnwrapKey is part of the backupkms.KMS interface. The wrapped key records
+// the version of the KMS key it was wrapped with, so that it still unwraps
+// after an automatic or manual rotation of the KMS key.
+func (k *AWSKMS) UnwrapKey(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error) {
+	c, err := k.client()
+	if err != nil {
+		return nil, err
+	}
+	out, err := c.DecryptWithContext(ctx, &kms.DecryptInput{
+		KeyId:          aws.String(keyID),
+		CiphertextBlob: wrappedKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Plaintext, nil
+}
+
+func (k *AWSKMS) client() (*kms.KMS, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k._client == nil {
+		session, err := session.NewSession()
+		if err != nil {
+			return nil, err
+		}
+		awsConfig := aws.Config{}
+		if region != "" {
+			awsConfig.Region = aws.String(region)
+		}
+		if endpoint != "" {
+			awsConfig.Endpoint = aws.String(endpoint)
+		}
+		k._client = kms.New(session, &awsConfig)
+	}
+	return k._client, nil
+}
+
+var _ backupkms.KMS = (*AWSKMS)(nil)
+
+func init() {
+	backupkms.RegisterKMS("aws", &AWSKMS{})
+}
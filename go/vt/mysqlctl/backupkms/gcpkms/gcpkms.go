@@ -0,0 +1,103 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gcpkms implements the backupkms.KMS interface with Google Cloud KMS.
+package gcpkms
+
+import (
+	"context"
+	"encoding/base64"
+	"sync"
+
+	"golang.org/x/oauth2/google"
+	cloudkms "google.golang.org/api/cloudkms/v1"
+	"google.golang.org/api/option"
+
+	"vitess.io/vitess/go/trace"
+	"vitess.io/vitess/go/vt/mysqlctl/backupkms"
+)
+
+// GCPKMS implements backupkms.KMS with Google Cloud KMS. The key ID is the
+// resource name of a symmetric crypto key, i.e.
+// projects/<project>/locations/<location>/keyRings/<key ring>/cryptoKeys/<key>.
+type GCPKMS struct {
+	mu      sync.Mutex
+	_client *cloudkms.Service
+}
+
+// WrapKey is part of the backupkms.KMS interface. The data key is wrapped
+// with the primary version of the crypto key.
+func (k *GCPKMS) WrapKey(ctx context.Context, keyID string, dataKey []byte) ([]byte, error) {
+	c, err := k.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Projects.Locations.KeyRings.CryptoKeys.Encrypt(keyID, &cloudkms.EncryptRequest{
+		Plaintext: base64.StdEncoding.EncodeToString(dataKey),
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(resp.Ciphertext)
+}
+
+// UnwrapKey is part of the backupkms.KMS interface. The wrapped key records
+// the version of the crypto key it was wrapped with, so that it still
+// unwraps after the primary version is rotated, as long as that version is
+// enabled.
+func (k *GCPKMS) UnwrapKey(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error) {
+	c, err := k.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Projects.Locations.KeyRings.CryptoKeys.Decrypt(keyID, &cloudkms.DecryptRequest{
+		Ciphertext: base64.StdEncoding.EncodeToString(wrappedKey),
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(resp.Plaintext)
+}
+
+// client returns the Cloud KMS client instance.
+// If there isn't one yet, it tries to create one.
+func (k *GCPKMS) client(ctx context.Context) (*cloudkms.Service, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k._client == nil {
+		// The context needs to be valid for longer than just
+		// the creation context, so we create a new one, but
+		// keep the span information.
+		ctx = trace.CopySpan(context.Background(), ctx)
+		authClient, err := google.DefaultClient(ctx, cloudkms.CloudkmsScope)
+		if err != nil {
+			return nil, err
+		}
+		client, err := cloudkms.NewService(ctx, option.WithHTTPClient(authClient))
+		if err != nil {
+			return nil, err
+		}
+		k._client = client
+	}
+	return k._client, nil
+}
+
+var _ backupkms.KMS = (*GCPKMS)(nil)
+
+func init() {
+	backupkms.RegisterKMS("gcp", &GCPKMS{})
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backupkms contains the interface to the key management services
+// which protect the data keys of encrypted backups.
+package backupkms
+
+import (
+	"context"
+	"fmt"
+)
+
+// KMS is the interface to a key management service. Backups are encrypted
+// with a data key, which is stored in the backup MANIFEST wrapped by a key
+// that the key management service holds, and which never leaves it.
+type KMS interface {
+	// WrapKey encrypts a data key with the managed key identified by keyID.
+	WrapKey(ctx context.Context, keyID string, dataKey []byte) ([]byte, error)
+
+	// UnwrapKey decrypts a data key which WrapKey wrapped with the managed
+	// key identified by keyID. It must still be able to unwrap data keys
+	// which were wrapped before the managed key was rotated.
+	UnwrapKey(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error)
+}
+
+// KMSMap contains the registered implementations for KMS
+var KMSMap = make(map[string]KMS)
+
+// RegisterKMS registers a KMS implementation under the given name, which
+// --backup-encryption-kms selects. Implementations register themselves in an
+// init() function, and are linked into the binaries with a plugin import.
+func RegisterKMS(name string, kms KMS) {
+	if _, ok := KMSMap[name]; ok {
+		panic(fmt.Sprintf("KMS %s is already registered", name))
+	}
+	KMSMap[name] = kms
+}
+
+// GetKMS returns the KMS implementation registered under the given name.
+func GetKMS(name string) (KMS, error) {
+	kms, ok := KMSMap[name]
+	if !ok {
+		return nil, fmt.Errorf("no registered implementation of KMS named %q", name)
+	}
+	return kms, nil
+}
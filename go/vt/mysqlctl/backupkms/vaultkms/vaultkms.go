@@ -0,0 +1,193 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package vaultkms implements the backupkms.KMS interface with the transit
+// secrets engine of HashiCorp Vault.
+package vaultkms
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	vaultapi "github.com/aquarapid/vaultlib"
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/vt/mysqlctl/backupkms"
+	"vitess.io/vitess/go/vt/servenv"
+)
+
+var (
+	vaultAddr             string
+	vaultTimeout          = 10 * time.Second
+	vaultCACert           string
+	vaultTokenFile        string
+	vaultRoleID           string
+	vaultRoleSecretIDFile string
+	vaultRoleMountPoint   = "approle"
+	vaultTransitMount     = "transit"
+)
+
+func registerFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&vaultAddr, "backup-encryption-vault-addr", vaultAddr, "URL to the Vault server which wraps the backup data keys")
+	fs.DurationVar(&vaultTimeout, "backup-encryption-vault-timeout", vaultTimeout, "Timeout for Vault API operations")
+	fs.StringVar(&vaultCACert, "backup-encryption-vault-tls-ca", vaultCACert, "Path to CA PEM for validating Vault server certificate")
+	fs.StringVar(&vaultTokenFile, "backup-encryption-vault-tokenfile", vaultTokenFile, "Path to file containing Vault auth token; token can also be passed using VAULT_TOKEN environment variable")
+	fs.StringVar(&vaultRoleID, "backup-encryption-vault-roleid", vaultRoleID, "Vault AppRole id; can also be passed using VAULT_ROLEID environment variable")
+	fs.StringVar(&vaultRoleSecretIDFile, "backup-encryption-vault-role-secretidfile", vaultRoleSecretIDFile, "Path to file containing Vault AppRole secret_id; can also be passed using VAULT_SECRETID environment variable")
+	fs.StringVar(&vaultRoleMountPoint, "backup-encryption-vault-role-mountpoint", vaultRoleMountPoint, "Vault AppRole mountpoint; can also be passed using VAULT_MOUNTPOINT environment variable")
+	fs.StringVar(&vaultTransitMount, "backup-encryption-vault-transit-mountpoint", vaultTransitMount, "Mountpoint of the Vault transit secrets engine holding the key which wraps the backup data keys")
+}
+
+func init() {
+	servenv.OnParseFor("vtbackup", registerFlags)
+	servenv.OnParseFor("vttablet", registerFlags)
+}
+
+// VaultKMS implements backupkms.KMS with the transit secrets engine of Vault.
+// The key ID is the name of a transit encryption key.
+type VaultKMS struct {
+	mu      sync.Mutex
+	_client *vaultapi.Client
+}
+
+// transitResponse is the response of the transit encrypt and decrypt endpoints
+type transitResponse struct {
+	Data struct {
+		Ciphertext string `json:"ciphertext"`
+		Plaintext  string `json:"plaintext"`
+	} `json:"data"`
+}
+
+// WrapKey is part of the backupkms.KMS interface.
+func (k *VaultKMS) WrapKey(ctx context.Context, keyID string, dataKey []byte) ([]byte, error) {
+	resp, err := k.transitRequest("encrypt", keyID, map[string]string{
+		"plaintext": base64.StdEncoding.EncodeToString(dataKey),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Data.Ciphertext == "" {
+		return nil, fmt.Errorf("empty ciphertext returned by Vault for key %s", keyID)
+	}
+	return []byte(resp.Data.Ciphertext), nil
+}
+
+// UnwrapKey is part of the backupkms.KMS interface. The wrapped key records
+// the version of the transit key it was wrapped with, e.g. `vault:v2:...`, so
+// that it still unwraps after the transit key is rotated, as long as that
+// version is not below the min_decryption_version of the key.
+func (k *VaultKMS) UnwrapKey(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error) {
+	resp, err := k.transitRequest("decrypt", keyID, map[string]string{
+		"ciphertext": string(wrappedKey),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(resp.Data.Plaintext)
+}
+
+func (k *VaultKMS) transitRequest(operation string, keyID string, payload map[string]string) (*transitResponse, error) {
+	c, err := k.client()
+	if err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/v1/%s/%s/%s", strings.Trim(vaultTransitMount, "/"), operation, keyID)
+	raw, err := c.RawRequest(http.MethodPost, path, payload)
+	if err != nil {
+		return nil, err
+	}
+	resp := &transitResponse{}
+	if err := json.Unmarshal(raw, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (k *VaultKMS) client() (*vaultapi.Client, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k._client != nil {
+		return k._client, nil
+	}
+	if vaultAddr == "" {
+		return nil, errors.New("no Vault server specified, use --backup-encryption-vault-addr")
+	}
+	token, err := readFromFile(vaultTokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("no Vault token in provided filename: %w", err)
+	}
+	secretID, err := readFromFile(vaultRoleSecretIDFile)
+	if err != nil {
+		return nil, fmt.Errorf("no Vault secret_id in provided filename: %w", err)
+	}
+
+	// NewConfig reads the VAULT_* environment variables, and otherwise
+	// defaults to a local server and to skipping TLS verification
+	config := vaultapi.NewConfig()
+	config.Address = vaultAddr
+	config.Timeout = vaultTimeout
+	if os.Getenv("VAULT_SKIP_VERIFY") == "" {
+		config.InsecureSSL = false
+	}
+	if vaultCACert != "" {
+		config.CACert = vaultCACert
+	}
+	if token != "" {
+		config.Token = token
+	}
+	if vaultRoleID != "" {
+		config.AppRoleCredentials.RoleID = vaultRoleID
+	}
+	if secretID != "" {
+		config.AppRoleCredentials.SecretID = secretID
+	}
+	if os.Getenv("VAULT_MOUNTPOINT") == "" {
+		config.AppRoleCredentials.MountPoint = vaultRoleMountPoint
+	}
+
+	client, err := vaultapi.NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+	k._client = client
+	return k._client, nil
+}
+
+func readFromFile(filePath string) (string, error) {
+	if filePath == "" {
+		return "", nil
+	}
+	fileBytes, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(fileBytes)), nil
+}
+
+var _ backupkms.KMS = (*VaultKMS)(nil)
+
+func init() {
+	backupkms.RegisterKMS("vault", &VaultKMS{})
+}
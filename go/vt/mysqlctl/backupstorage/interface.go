@@ -94,6 +94,20 @@ type BackupHandle interface {
 	concurrency.ErrorRecorder
 }
 
+// MultipartBackupHandle is implemented by the BackupHandles of the object
+// storages which can stream a file of a backup directly as a multipart upload,
+// and verify the checksum of each part as it is uploaded.
+type MultipartBackupHandle interface {
+	BackupHandle
+
+	// AddFileMultipart is like AddFile, but the file is uploaded as a
+	// multipart upload: each part is sent with its SHA-256 checksum as soon
+	// as it is written, so that at most one part per file is held in memory.
+	// Close completes the upload and returns its error, if any. The upload
+	// is aborted if it fails, including when the context is canceled.
+	AddFileMultipart(ctx context.Context, filename string, filesize int64) (io.WriteCloser, error)
+}
+
 // BackupStorage is the interface to the storage system
 type BackupStorage interface {
 	// ListBackups returns all the backups in a directory.  The
@@ -128,16 +142,37 @@ type BackupStorage interface {
 	WithParams(Params) BackupStorage
 }
 
+// BackupUpdater is implemented by the BackupStorage implementations which can
+// update the files of an existing backup, e.g. to record the result of its
+// verification in the MANIFEST.
+type BackupUpdater interface {
+	// UpdateBackup returns a read-write handle on an existing backup. AddFile
+	// replaces the file if it exists, and EndBackup must be called once the
+	// files are written. AbortBackup must not be called, since it would remove
+	// the backup.
+	UpdateBackup(ctx context.Context, dir, name string) (BackupHandle, error)
+}
+
 // BackupStorageMap contains the registered implementations for BackupStorage
 var BackupStorageMap = make(map[string]BackupStorage)
 
+// RegisterBackupStorage registers a BackupStorage implementation under the given name, which
+// --backup_storage_implementation selects. A third party implementation registers itself in an
+// init() function, and is linked into the binaries with a plugin import.
+func RegisterBackupStorage(name string, bs BackupStorage) {
+	if _, ok := BackupStorageMap[name]; ok {
+		panic(fmt.Sprintf("BackupStorage %s is already registered", name))
+	}
+	BackupStorageMap[name] = bs
+}
+
 // GetBackupStorage returns the current BackupStorage implementation.
 // Should be called after flags have been initialized.
 // When all operations are done, call BackupStorage.Close() to free resources.
 func GetBackupStorage() (BackupStorage, error) {
 	bs, ok := BackupStorageMap[BackupStorageImplementation]
 	if !ok {
-		return nil, fmt.Errorf("no registered implementation of BackupStorage")
+		return nil, fmt.Errorf("no registered implementation of BackupStorage named %q", BackupStorageImplementation)
 	}
 	return bs, nil
 }
@@ -289,7 +289,7 @@ func FindPITRToTimePath(restoreToTime time.Time, manifests [](*BackupManifest))
 			// Xtrabackup backups are true to the time they complete (the snapshot is taken at the very end).
 			// Therefore the finish time best represents the backup time.
 			compareWithTime = finishedTime
-		case builtinBackupEngineName:
+		case builtinBackupEngineName, s3StreamBackupEngineName:
 			// Builtin takes down the MySQL server. Hence the _start time_ represents the backup time best
 			compareWithTime = startTime
 		default:
@@ -19,6 +19,8 @@ package mysqlctl
 import (
 	"bufio"
 	"context"
+	"crypto/cipher"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -86,6 +88,9 @@ var (
 // required to implement a backup/restore by copying files from and to
 // the correct location / storage bucket
 type BuiltinBackupEngine struct {
+	// multipart is set for the s3stream engine, which uploads the files
+	// with AddFileMultipart and records their SHA-256 checksum.
+	multipart bool
 }
 
 // builtinBackupManifest represents the backup. It lists all the files, the
@@ -122,6 +127,11 @@ type builtinBackupManifest struct {
 	// ExternalDecompressor will be used. If neither are set, the restore will
 	// abort.
 	ExternalDecompressor string
+
+	// Encryption is set when the backup files are encrypted. It holds the
+	// wrapped data key, and the key management service and key which
+	// wrapped it.
+	Encryption *BackupEncryption `json:",omitempty"`
 }
 
 // FileEntry is one file to backup
@@ -136,13 +146,22 @@ type FileEntry struct {
 	// Name is the file name, relative to Base
 	Name string
 
-	// Hash is the hash of the final data (transformed and
-	// compressed if specified) stored in the BackupStorage.
+	// Hash is the hash of the final data (transformed,
+	// compressed and encrypted if specified) stored in the BackupStorage.
 	Hash string
 
 	// ParentPath is an optional prefix to the Base path. If empty, it is ignored. Useful
 	// for writing files in a temporary directory
 	ParentPath string
+
+	// Size is the size of the final data stored in the BackupStorage. It is used to
+	// report the progress of restores, and is zero for older backups.
+	Size int64 `json:",omitempty"`
+
+	// SHA256 is the SHA-256 checksum of the final data stored in the BackupStorage,
+	// hex encoded. It is only recorded by the s3stream engine, and is verified along
+	// with Hash when set.
+	SHA256 string `json:",omitempty"`
 }
 
 func init() {
@@ -576,6 +595,11 @@ func (be *BuiltinBackupEngine) backupFiles(
 	}
 	params.Logger.Infof("found %v files to backup", len(fes))
 
+	encryption, aead, err := newBackupEncryption(ctx)
+	if err != nil {
+		return vterrors.Wrap(err, "can't set up backup encryption")
+	}
+
 	// Backup with the provided concurrency.
 	sema := semaphore.NewWeighted(int64(params.Concurrency))
 	wg := sync.WaitGroup{}
@@ -611,7 +635,7 @@ func (be *BuiltinBackupEngine) backupFiles(
 
 			// Backup the individual file.
 			name := fmt.Sprintf("%v", i)
-			bh.RecordError(be.backupFile(ctx, params, bh, fe, name))
+			bh.RecordError(be.backupFile(ctx, params, bh, fe, name, aead))
 		}(i)
 	}
 
@@ -645,7 +669,7 @@ func (be *BuiltinBackupEngine) backupFiles(
 	bm := &builtinBackupManifest{
 		// Common base fields
 		BackupManifest: BackupManifest{
-			BackupMethod:       builtinBackupEngineName,
+			BackupMethod:       be.name(),
 			Position:           backupPosition,
 			PurgedPosition:     purgedPosition,
 			FromPosition:       fromPosition,
@@ -667,6 +691,7 @@ func (be *BuiltinBackupEngine) backupFiles(
 		SkipCompress:         !backupStorageCompress,
 		CompressionEngine:    CompressionEngineName,
 		ExternalDecompressor: ManifestExternalDecompressorCmd,
+		Encryption:           encryption,
 	}
 	data, err := json.MarshalIndent(bm, "", "  ")
 	if err != nil {
@@ -687,6 +712,7 @@ type backupPipe struct {
 	w *bufio.Writer
 
 	crc32  hash.Hash32
+	sha256 hash.Hash
 	nn     int64
 	done   chan struct{}
 	closed int32
@@ -715,6 +741,9 @@ func newBackupReader(filename string, maxSize int64, r io.Reader) *backupPipe {
 func (bp *backupPipe) Read(p []byte) (int, error) {
 	nn, err := bp.r.Read(p)
 	_, _ = bp.crc32.Write(p[:nn])
+	if bp.sha256 != nil {
+		_, _ = bp.sha256.Write(p[:nn])
+	}
 	atomic.AddInt64(&bp.nn, int64(nn))
 	return nn, err
 }
@@ -722,6 +751,9 @@ func (bp *backupPipe) Read(p []byte) (int, error) {
 func (bp *backupPipe) Write(p []byte) (int, error) {
 	nn, err := bp.w.Write(p)
 	_, _ = bp.crc32.Write(p[:nn])
+	if bp.sha256 != nil {
+		_, _ = bp.sha256.Write(p[:nn])
+	}
 	atomic.AddInt64(&bp.nn, int64(nn))
 	return nn, err
 }
@@ -742,6 +774,14 @@ func (bp *backupPipe) HashString() string {
 	return hex.EncodeToString(bp.crc32.Sum(nil))
 }
 
+// SHA256String returns the SHA-256 checksum of the data, if it is computed.
+func (bp *backupPipe) SHA256String() string {
+	if bp.sha256 == nil {
+		return ""
+	}
+	return hex.EncodeToString(bp.sha256.Sum(nil))
+}
+
 func (bp *backupPipe) ReportProgress(period time.Duration, logger logutil.Logger) {
 	tick := time.NewTicker(period)
 	defer tick.Stop()
@@ -762,8 +802,8 @@ func (bp *backupPipe) ReportProgress(period time.Duration, logger logutil.Logger
 	}
 }
 
-// backupFile backs up an individual file.
-func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle, fe *FileEntry, name string) (finalErr error) {
+// backupFile backs up an individual file, and encrypts it when aead is set.
+func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle, fe *FileEntry, name string, aead cipher.AEAD) (finalErr error) {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	// Open the source file for reading.
@@ -794,7 +834,12 @@ func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupPara
 	// Open the destination file for writing, and a buffer.
 	params.Logger.Infof("Backing up file: %v", fe.Name)
 	openDestAt := time.Now()
-	dest, err := bh.AddFile(ctx, name, fi.Size())
+	var dest io.WriteCloser
+	if be.multipart {
+		dest, err = bh.(backupstorage.MultipartBackupHandle).AddFileMultipart(ctx, name, fi.Size())
+	} else {
+		dest, err = bh.AddFile(ctx, name, fi.Size())
+	}
 	if err != nil {
 		return vterrors.Wrapf(err, "cannot add file: %v,%v", name, fe.Name)
 	}
@@ -814,6 +859,9 @@ func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupPara
 	timedDest := ioutil.NewMeteredWriteCloser(dest, destStats.TimedIncrementBytes)
 
 	bw := newBackupWriter(fe.Name, builtinBackupStorageWriteBufferSize, fi.Size(), timedDest)
+	if be.multipart {
+		bw.sha256 = sha256.New()
+	}
 
 	// We create the following inner function because:
 	// - we must `defer` the compressor's Close() function
@@ -822,6 +870,23 @@ func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupPara
 		var reader io.Reader = br
 		var writer io.Writer = bw
 
+		// Create the encryption pipe, if necessary. It encrypts the
+		// compressed data, and must be closed after the compressor.
+		if aead != nil {
+			encryptor, err := newEncryptingWriter(aead, writer)
+			if err != nil {
+				return vterrors.Wrap(err, "can't create encryptor")
+			}
+			writer = encryptor
+			defer func() {
+				if cerr := encryptor.Close(); cerr != nil {
+					cerr = vterrors.Wrapf(cerr, "failed to close encryptor %v", name)
+					params.Logger.Error(cerr)
+					createAndCopyErr = errors.Join(createAndCopyErr, cerr)
+				}
+			}()
+		}
+
 		// Create the gzip compression pipe, if necessary.
 		if backupStorageCompress {
 			var compressor io.WriteCloser
@@ -877,8 +942,10 @@ func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupPara
 		return vterrors.Wrap(err, "failed to close the source reader")
 	}
 
-	// Save the hash.
+	// Save the hashes and the stored size.
 	fe.Hash = bw.HashString()
+	fe.SHA256 = bw.SHA256String()
+	fe.Size = atomic.LoadInt64(&bw.nn)
 	return nil
 }
 
@@ -978,6 +1045,13 @@ func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreP
 		}()
 	}
 
+	var aead cipher.AEAD
+	if bm.Encryption != nil {
+		if aead, err = bm.Encryption.AEAD(ctx); err != nil {
+			return "", err
+		}
+	}
+
 	if bm.Incremental {
 		createdDir, err = os.MkdirTemp("", "restore-incremental-*")
 		if err != nil {
@@ -985,6 +1059,14 @@ func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreP
 		}
 	}
 	fes := bm.FileEntries
+	progress := newRestoreProgress(bh.Name(), fes)
+	progressDone := make(chan struct{})
+	go progress.reportPeriodically(builtinBackupProgress, params.Logger, params.Progress, progressDone)
+	defer func() {
+		close(progressDone)
+		progress.report(params.Logger, params.Progress)
+	}()
+
 	sema := semaphore.NewWeighted(int64(params.Concurrency))
 	rec := concurrency.AllErrorRecorder{}
 	wg := sync.WaitGroup{}
@@ -1022,18 +1104,21 @@ func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreP
 			// And restore the file.
 			name := fmt.Sprintf("%v", i)
 			params.Logger.Infof("Copying file %v: %v", name, fe.Name)
-			err := be.restoreFile(ctx, params, bh, fe, bm, name)
+			err := be.restoreFile(ctx, params, bh, fe, bm, name, aead, progress)
 			if err != nil {
 				rec.RecordError(vterrors.Wrapf(err, "can't restore file %v to %v", name, fe.Name))
+				return
 			}
+			progress.fileRestored()
 		}(i)
 	}
 	wg.Wait()
 	return createdDir, rec.Error()
 }
 
-// restoreFile restores an individual file.
-func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestoreParams, bh backupstorage.BackupHandle, fe *FileEntry, bm builtinBackupManifest, name string) (finalErr error) {
+// restoreFile restores an individual file, decrypts it when aead is set, and adds
+// the bytes read from the BackupStorage to the progress of the restore.
+func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestoreParams, bh backupstorage.BackupHandle, fe *FileEntry, bm builtinBackupManifest, name string, aead cipher.AEAD, progress *restoreProgress) (finalErr error) {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	// Open the source file for reading.
@@ -1045,7 +1130,7 @@ func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestorePa
 	params.Stats.Scope(stats.Operation("Source:Open")).TimedIncrement(time.Since(openSourceAt))
 
 	readStats := params.Stats.Scope(stats.Operation("Source:Read"))
-	timedSource := ioutil.NewMeteredReader(source, readStats.TimedIncrementBytes)
+	timedSource := ioutil.NewMeteredReader(source, readStats.TimedIncrementBytes, progress.addBytes)
 
 	defer func() {
 		closeSourceAt := time.Now()
@@ -1054,6 +1139,9 @@ func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestorePa
 	}()
 
 	br := newBackupReader(name, 0, timedSource)
+	if fe.SHA256 != "" {
+		br.sha256 = sha256.New()
+	}
 	go br.ReportProgress(builtinBackupProgress, params.Logger)
 	var reader io.Reader = br
 
@@ -1078,6 +1166,11 @@ func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestorePa
 
 	bufferedDest := bufio.NewWriterSize(timedDest, int(builtinBackupFileWriteBufferSize))
 
+	// Create the decrypter if needed.
+	if aead != nil {
+		reader = newDecryptingReader(aead, reader)
+	}
+
 	// Create the uncompresser if needed.
 	if !bm.SkipCompress {
 		var decompressor io.ReadCloser
@@ -1134,6 +1227,9 @@ func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestorePa
 	if hash != fe.Hash {
 		return vterrors.Errorf(vtrpc.Code_INTERNAL, "hash mismatch for %v, got %v expected %v", fe.Name, hash, fe.Hash)
 	}
+	if checksum := br.SHA256String(); checksum != fe.SHA256 {
+		return vterrors.Errorf(vtrpc.Code_INTERNAL, "SHA-256 checksum mismatch for %v, got %v expected %v", fe.Name, checksum, fe.SHA256)
+	}
 
 	// Flush the buffer.
 	if err := bufferedDest.Flush(); err != nil {
@@ -1147,6 +1243,14 @@ func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestorePa
 	return nil
 }
 
+// name returns the name of the engine, recorded as the BackupMethod of the MANIFEST.
+func (be *BuiltinBackupEngine) name() string {
+	if be.multipart {
+		return s3StreamBackupEngineName
+	}
+	return builtinBackupEngineName
+}
+
 // ShouldDrainForBackup satisfies the BackupEngine interface
 // backup requires query service to be stopped, hence true
 func (be *BuiltinBackupEngine) ShouldDrainForBackup(req *tabletmanagerdatapb.BackupRequest) bool {
@@ -1181,5 +1285,5 @@ func getPrimaryPosition(ctx context.Context, tmc tmclient.TabletManagerClient, t
 }
 
 func init() {
-	BackupRestoreEngineMap[builtinBackupEngineName] = &BuiltinBackupEngine{}
+	RegisterBackupEngine(builtinBackupEngineName, &BuiltinBackupEngine{})
 }
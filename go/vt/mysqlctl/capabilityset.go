@@ -20,6 +20,8 @@ limitations under the License.
 
 package mysqlctl
 
+import "strings"
+
 type MySQLFlavor string
 
 // Flavor constants define the type of mysql flavor being used
@@ -52,6 +54,61 @@ func (c *capabilitySet) hasMaria104InstallDb() bool {
 	return c.isMariaDB() && c.version.atLeast(ServerVersion{Major: 10, Minor: 4, Patch: 0})
 }
 
+// hasReplicaTerminologyOnly tests if the server only supports the replication
+// statements with the source and replica terminology, such as RESET REPLICA
+// and RESET BINARY LOGS AND GTIDS, which is the case of MySQL 8.2 and above.
+func (c *capabilitySet) hasReplicaTerminologyOnly() bool {
+	return c.isMySQLLike() && c.version.atLeast(ServerVersion{Major: 8, Minor: 2, Patch: 0})
+}
+
+// initDBReplicationReplacer replaces the replication statements of the init db
+// SQL that MySQL 8.4 removed.
+var initDBReplicationReplacer = strings.NewReplacer(
+	"RESET SLAVE ALL;", "RESET REPLICA ALL;",
+	"RESET MASTER;", "RESET BINARY LOGS AND GTIDS;",
+)
+
+// compatibleInitDBSQL returns the init db SQL with the replication statements
+// that the server no longer supports replaced by their equivalents.
+func (c *capabilitySet) compatibleInitDBSQL(sql string) string {
+	if !c.hasReplicaTerminologyOnly() {
+		return sql
+	}
+	return initDBReplicationReplacer.Replace(sql)
+}
+
+// removedSQLModes are the SQL modes of MySQL 5.7 that MySQL 8.0 removed.
+// Setting a sql_mode containing any of them fails.
+var removedSQLModes = map[string]bool{
+	"DB2":                 true,
+	"MAXDB":               true,
+	"MSSQL":               true,
+	"MYSQL323":            true,
+	"MYSQL40":             true,
+	"ORACLE":              true,
+	"POSTGRESQL":          true,
+	"NO_AUTO_CREATE_USER": true,
+	"NO_FIELD_OPTIONS":    true,
+	"NO_KEY_OPTIONS":      true,
+	"NO_TABLE_OPTIONS":    true,
+}
+
+// compatibleSQLMode returns the sql_mode without the SQL modes that the server
+// no longer supports, e.g. for a sql_mode read from a MySQL 5.7 server.
+func (c *capabilitySet) compatibleSQLMode(sqlMode string) string {
+	if !c.isMySQLLike() || !c.version.atLeast(ServerVersion{Major: 8, Minor: 0, Patch: 0}) {
+		return sqlMode
+	}
+	var modes []string
+	for _, mode := range strings.Split(sqlMode, ",") {
+		if removedSQLModes[strings.ToUpper(strings.TrimSpace(mode))] {
+			continue
+		}
+		modes = append(modes, mode)
+	}
+	return strings.Join(modes, ",")
+}
+
 // IsMySQLLike tests if the server is either MySQL
 // or Percona Server. At least currently, Vitess doesn't
 // make use of any specific Percona Server features.
@@ -318,7 +318,7 @@ func (bs *CephBackupStorage) client() (*minio.Client, error) {
 }
 
 func init() {
-	backupstorage.BackupStorageMap["ceph"] = &CephBackupStorage{}
+	backupstorage.RegisterBackupStorage("ceph", &CephBackupStorage{})
 }
 
 // objName joins path parts into an object name.
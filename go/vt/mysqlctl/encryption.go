@@ -0,0 +1,297 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bufio"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/vt/mysqlctl/backupkms"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+const (
+	// EncryptionAlgorithmAES256GCM encrypts the backup files in chunks with AES-256-GCM
+	EncryptionAlgorithmAES256GCM = "aes-256-gcm"
+
+	// encryptionDataKeySize is the size of the data keys, in bytes
+	encryptionDataKeySize = 32
+	// encryptionChunkSize is the size of the plaintext chunks which are sealed independently
+	encryptionChunkSize = 64 * 1024
+	// encryptionNoncePrefixSize is the size of the random nonce prefix of each file. The rest of
+	// the nonce is the big-endian index of the chunk.
+	encryptionNoncePrefixSize = 8
+)
+
+var (
+	// backupEncryptionKMS is the name of the KMS which wraps the data keys of encrypted
+	// backups. Backups are not encrypted when it is empty.
+	backupEncryptionKMS string
+	// backupEncryptionKeyID identifies the key of the KMS which wraps the data keys
+	backupEncryptionKeyID string
+
+	// encryptionLastChunk and encryptionNotLastChunk are the additional data of the chunks,
+	// such that a file truncated at a chunk boundary does not decrypt
+	encryptionLastChunk    = []byte{1}
+	encryptionNotLastChunk = []byte{0}
+
+	errTruncatedEncryptedFile = errors.New("encrypted backup file is truncated")
+)
+
+func init() {
+	for _, cmd := range []string{"vtbackup", "vtcombo", "vttablet", "vttestserver"} {
+		servenv.OnParseFor(cmd, registerBackupEncryptionFlags)
+	}
+}
+
+func registerBackupEncryptionFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&backupEncryptionKMS, "backup-encryption-kms", backupEncryptionKMS, "name of the key management service which wraps the data keys of encrypted backups, e.g. 'aws', 'gcp' or 'vault'. Backups taken with the builtin backup engine are encrypted when set.")
+	fs.StringVar(&backupEncryptionKeyID, "backup-encryption-key-id", backupEncryptionKeyID, "ID of the key management service key which wraps the data keys of encrypted backups. Restores use the key recorded in the backup MANIFEST, so that this key can be rotated.")
+}
+
+// BackupEncryption describes how the files of a backup are encrypted. Each backup
+// is encrypted with its own data key, which is stored in the MANIFEST wrapped by a
+// key of a key management service.
+type BackupEncryption struct {
+	// Algorithm is the algorithm the files are encrypted with
+	Algorithm string
+
+	// KMS is the name of the key management service which wrapped the data key
+	KMS string
+
+	// KeyID identifies the key which wrapped the data key
+	KeyID string
+
+	// WrappedKey is the wrapped data key
+	WrappedKey []byte
+}
+
+// newBackupEncryption generates the data key of a new backup, and wraps it with the
+// configured key management service. It returns nil when backups are not encrypted.
+func newBackupEncryption(ctx context.Context) (*BackupEncryption, cipher.AEAD, error) {
+	if backupEncryptionKMS == "" {
+		return nil, nil, nil
+	}
+	if backupEncryptionKeyID == "" {
+		return nil, nil, fmt.Errorf("--backup-encryption-key-id is required with --backup-encryption-kms")
+	}
+	kms, err := backupkms.GetKMS(backupEncryptionKMS)
+	if err != nil {
+		return nil, nil, err
+	}
+	dataKey := make([]byte, encryptionDataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, vterrors.Wrap(err, "cannot generate backup data key")
+	}
+	wrappedKey, err := kms.WrapKey(ctx, backupEncryptionKeyID, dataKey)
+	if err != nil {
+		return nil, nil, vterrors.Wrapf(err, "cannot wrap backup data key with %s key %s", backupEncryptionKMS, backupEncryptionKeyID)
+	}
+	aead, err := newEncryptionAEAD(dataKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &BackupEncryption{
+		Algorithm:  EncryptionAlgorithmAES256GCM,
+		KMS:        backupEncryptionKMS,
+		KeyID:      backupEncryptionKeyID,
+		WrappedKey: wrappedKey,
+	}, aead, nil
+}
+
+// AEAD unwraps the data key of the backup with the key management service and key
+// recorded in the MANIFEST, regardless of the current flags.
+func (e *BackupEncryption) AEAD(ctx context.Context) (cipher.AEAD, error) {
+	if e.Algorithm != EncryptionAlgorithmAES256GCM {
+		return nil, fmt.Errorf("unsupported backup encryption algorithm %q", e.Algorithm)
+	}
+	kms, err := backupkms.GetKMS(e.KMS)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := kms.UnwrapKey(ctx, e.KeyID, e.WrappedKey)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot unwrap backup data key with %s key %s", e.KMS, e.KeyID)
+	}
+	return newEncryptionAEAD(dataKey)
+}
+
+func newEncryptionAEAD(dataKey []byte) (cipher.AEAD, error) {
+	if len(dataKey) != encryptionDataKeySize {
+		return nil, fmt.Errorf("invalid backup data key size %d", len(dataKey))
+	}
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptingWriter encrypts a backup file. It writes a random nonce prefix, followed by the
+// sealed chunks of the file. All chunks but the last one hold encryptionChunkSize bytes.
+type encryptingWriter struct {
+	aead    cipher.AEAD
+	w       io.Writer
+	nonce   []byte
+	counter uint32
+	chunk   []byte
+	sealed  []byte
+	closed  bool
+}
+
+func newEncryptingWriter(aead cipher.AEAD, w io.Writer) (*encryptingWriter, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce[:encryptionNoncePrefixSize]); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(nonce[:encryptionNoncePrefixSize]); err != nil {
+		return nil, err
+	}
+	return &encryptingWriter{
+		aead:  aead,
+		w:     w,
+		nonce: nonce,
+		chunk: make([]byte, 0, encryptionChunkSize),
+	}, nil
+}
+
+func (ew *encryptingWriter) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		// A full chunk is only sealed once more data comes, since the last chunk is sealed differently
+		if len(ew.chunk) == encryptionChunkSize {
+			if err := ew.seal(encryptionNotLastChunk); err != nil {
+				return n, err
+			}
+		}
+		copied := copy(ew.chunk[len(ew.chunk):cap(ew.chunk)], p)
+		ew.chunk = ew.chunk[:len(ew.chunk)+copied]
+		p = p[copied:]
+		n += copied
+	}
+	return n, nil
+}
+
+// Close seals the last chunk. It does not close the underlying writer.
+func (ew *encryptingWriter) Close() error {
+	if ew.closed {
+		return nil
+	}
+	ew.closed = true
+	return ew.seal(encryptionLastChunk)
+}
+
+func (ew *encryptingWriter) seal(additionalData []byte) error {
+	if ew.counter == math.MaxUint32 {
+		return fmt.Errorf("backup file is too large to encrypt")
+	}
+	binary.BigEndian.PutUint32(ew.nonce[encryptionNoncePrefixSize:], ew.counter)
+	ew.counter++
+	ew.sealed = ew.aead.Seal(ew.sealed[:0], ew.nonce, ew.chunk, additionalData)
+	ew.chunk = ew.chunk[:0]
+	_, err := ew.w.Write(ew.sealed)
+	return err
+}
+
+// decryptingReader decrypts a backup file written by an encryptingWriter, and fails when the
+// file was tampered with, reordered or truncated.
+type decryptingReader struct {
+	aead      cipher.AEAD
+	r         *bufio.Reader
+	nonce     []byte
+	counter   uint32
+	sealed    []byte
+	opened    []byte
+	plaintext []byte
+	last      bool
+	err       error
+}
+
+func newDecryptingReader(aead cipher.AEAD, r io.Reader) *decryptingReader {
+	return &decryptingReader{
+		aead:   aead,
+		r:      bufio.NewReader(r),
+		sealed: make([]byte, encryptionChunkSize+aead.Overhead()),
+	}
+}
+
+func (dr *decryptingReader) Read(p []byte) (int, error) {
+	for len(dr.plaintext) == 0 {
+		if dr.err != nil {
+			return 0, dr.err
+		}
+		dr.err = dr.open()
+	}
+	n := copy(p, dr.plaintext)
+	dr.plaintext = dr.plaintext[n:]
+	return n, nil
+}
+
+// open reads and decrypts the next chunk
+func (dr *decryptingReader) open() error {
+	if dr.last {
+		return io.EOF
+	}
+	if dr.nonce == nil {
+		nonce := make([]byte, dr.aead.NonceSize())
+		if _, err := io.ReadFull(dr.r, nonce[:encryptionNoncePrefixSize]); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return errTruncatedEncryptedFile
+			}
+			return err
+		}
+		dr.nonce = nonce
+	}
+	n, err := io.ReadFull(dr.r, dr.sealed)
+	switch err {
+	case nil:
+		// A full chunk is the last one if nothing follows it
+		if _, err := dr.r.Peek(1); err == io.EOF {
+			dr.last = true
+		} else if err != nil {
+			return err
+		}
+	case io.ErrUnexpectedEOF:
+		dr.last = true
+	case io.EOF:
+		return errTruncatedEncryptedFile
+	default:
+		return err
+	}
+	additionalData := encryptionNotLastChunk
+	if dr.last {
+		additionalData = encryptionLastChunk
+	}
+	binary.BigEndian.PutUint32(dr.nonce[encryptionNoncePrefixSize:], dr.counter)
+	dr.counter++
+	dr.opened, err = dr.aead.Open(dr.opened[:0], dr.nonce, dr.sealed[:n], additionalData)
+	if err != nil {
+		return vterrors.Wrap(err, "cannot decrypt backup file")
+	}
+	dr.plaintext = dr.opened
+	return nil
+}
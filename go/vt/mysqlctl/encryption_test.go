@@ -0,0 +1,172 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/mysqlctl/backupkms"
+)
+
+// fakeKMS wraps data keys by prefixing them with the key ID, and only knows the keys in keyIDs.
+type fakeKMS struct {
+	keyIDs map[string]bool
+}
+
+func (k *fakeKMS) WrapKey(ctx context.Context, keyID string, dataKey []byte) ([]byte, error) {
+	if !k.keyIDs[keyID] {
+		return nil, fmt.Errorf("unknown key %s", keyID)
+	}
+	return append([]byte(keyID+":"), dataKey...), nil
+}
+
+func (k *fakeKMS) UnwrapKey(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error) {
+	if !k.keyIDs[keyID] || !bytes.HasPrefix(wrappedKey, []byte(keyID+":")) {
+		return nil, fmt.Errorf("cannot unwrap with key %s", keyID)
+	}
+	return wrappedKey[len(keyID)+1:], nil
+}
+
+func TestEncryptingWriterDecryptingReader(t *testing.T) {
+	dataKey := make([]byte, encryptionDataKeySize)
+	_, err := rand.Read(dataKey)
+	require.NoError(t, err)
+	aead, err := newEncryptionAEAD(dataKey)
+	require.NoError(t, err)
+
+	encrypt := func(t *testing.T, data []byte) []byte {
+		var encrypted bytes.Buffer
+		encryptor, err := newEncryptingWriter(aead, &encrypted)
+		require.NoError(t, err)
+		_, err = io.Copy(encryptor, bytes.NewReader(data))
+		require.NoError(t, err)
+		require.NoError(t, encryptor.Close())
+		return encrypted.Bytes()
+	}
+	decrypt := func(encrypted []byte) ([]byte, error) {
+		return io.ReadAll(newDecryptingReader(aead, bytes.NewReader(encrypted)))
+	}
+
+	for _, size := range []int{0, 1, encryptionChunkSize - 1, encryptionChunkSize, encryptionChunkSize + 1, 3 * encryptionChunkSize} {
+		t.Run(fmt.Sprintf("%d bytes", size), func(t *testing.T) {
+			data := make([]byte, size)
+			_, err := rand.Read(data)
+			require.NoError(t, err)
+
+			encrypted := encrypt(t, data)
+			if size > 0 {
+				assert.False(t, bytes.Contains(encrypted, data))
+			}
+			decrypted, err := decrypt(encrypted)
+			require.NoError(t, err)
+			assert.Equal(t, len(data), len(decrypted))
+			assert.True(t, bytes.Equal(data, decrypted))
+		})
+	}
+
+	data := make([]byte, 2*encryptionChunkSize+100)
+	encrypted := encrypt(t, data)
+	sealedChunkSize := encryptionChunkSize + aead.Overhead()
+
+	t.Run("tampered", func(t *testing.T) {
+		tampered := bytes.Clone(encrypted)
+		tampered[len(tampered)/2] ^= 1
+		_, err := decrypt(tampered)
+		assert.ErrorContains(t, err, "cannot decrypt backup file")
+	})
+	t.Run("truncated at a chunk boundary", func(t *testing.T) {
+		_, err := decrypt(encrypted[:encryptionNoncePrefixSize+2*sealedChunkSize])
+		assert.ErrorContains(t, err, "cannot decrypt backup file")
+	})
+	t.Run("truncated within a chunk", func(t *testing.T) {
+		_, err := decrypt(encrypted[:encryptionNoncePrefixSize+sealedChunkSize+10])
+		assert.ErrorContains(t, err, "cannot decrypt backup file")
+	})
+	t.Run("truncated nonce", func(t *testing.T) {
+		_, err := decrypt(encrypted[:encryptionNoncePrefixSize-1])
+		assert.ErrorIs(t, err, errTruncatedEncryptedFile)
+	})
+	t.Run("reordered chunks", func(t *testing.T) {
+		reordered := bytes.Clone(encrypted)
+		first := reordered[encryptionNoncePrefixSize : encryptionNoncePrefixSize+sealedChunkSize]
+		second := bytes.Clone(reordered[encryptionNoncePrefixSize+sealedChunkSize : encryptionNoncePrefixSize+2*sealedChunkSize])
+		copy(reordered[encryptionNoncePrefixSize+sealedChunkSize:], first)
+		copy(reordered[encryptionNoncePrefixSize:], second)
+		_, err := decrypt(reordered)
+		assert.ErrorContains(t, err, "cannot decrypt backup file")
+	})
+}
+
+func TestBackupEncryption(t *testing.T) {
+	kms := &fakeKMS{keyIDs: map[string]bool{"key1": true}}
+	backupkms.RegisterKMS("test-fake", kms)
+	defer delete(backupkms.KMSMap, "test-fake")
+
+	defer func(kmsName, keyID string) {
+		backupEncryptionKMS, backupEncryptionKeyID = kmsName, keyID
+	}(backupEncryptionKMS, backupEncryptionKeyID)
+	ctx := context.Background()
+
+	// Backups are not encrypted by default
+	encryption, aead, err := newBackupEncryption(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, encryption)
+	assert.Nil(t, aead)
+
+	backupEncryptionKMS = "test-fake"
+	_, _, err = newBackupEncryption(ctx)
+	assert.ErrorContains(t, err, "--backup-encryption-key-id is required")
+
+	backupEncryptionKeyID = "key1"
+	encryption, aead, err = newBackupEncryption(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, EncryptionAlgorithmAES256GCM, encryption.Algorithm)
+	assert.Equal(t, "test-fake", encryption.KMS)
+	assert.Equal(t, "key1", encryption.KeyID)
+
+	var encrypted bytes.Buffer
+	encryptor, err := newEncryptingWriter(aead, &encrypted)
+	require.NoError(t, err)
+	_, err = encryptor.Write([]byte("backup file"))
+	require.NoError(t, err)
+	require.NoError(t, encryptor.Close())
+
+	// Rotate the key: restores keep using the key recorded in the MANIFEST
+	kms.keyIDs["key2"] = true
+	backupEncryptionKeyID = "key2"
+	restoreAEAD, err := encryption.AEAD(ctx)
+	require.NoError(t, err)
+	decrypted, err := io.ReadAll(newDecryptingReader(restoreAEAD, &encrypted))
+	require.NoError(t, err)
+	assert.Equal(t, "backup file", string(decrypted))
+
+	encryption.KMS = "test-unregistered"
+	_, err = encryption.AEAD(ctx)
+	assert.ErrorContains(t, err, `no registered implementation of KMS named "test-unregistered"`)
+
+	backupEncryptionKMS = "test-unregistered"
+	_, _, err = newBackupEncryption(ctx)
+	assert.ErrorContains(t, err, `no registered implementation of KMS named "test-unregistered"`)
+}
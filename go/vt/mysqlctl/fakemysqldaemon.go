@@ -174,6 +174,9 @@ type FakeMysqlDaemon struct {
 
 	// Version is the version that will be returned by GetVersionString.
 	Version string
+
+	// MysqlRoot is the MySQL distribution set by SetMysqlRoot.
+	MysqlRoot string
 }
 
 // NewFakeMysqlDaemon returns a FakeMysqlDaemon where mysqld appears
@@ -234,6 +237,15 @@ func (fmd *FakeMysqlDaemon) RunMysqlUpgrade(ctx context.Context) error {
 	return nil
 }
 
+// SetMysqlRoot is part of the MysqlDaemon interface
+func (fmd *FakeMysqlDaemon) SetMysqlRoot(root string) error {
+	if fmd.Running {
+		return fmt.Errorf("fake mysql daemon still running")
+	}
+	fmd.MysqlRoot = root
+	return nil
+}
+
 // ApplyBinlogFile is part of the MysqlDaemon interface
 func (fmd *FakeMysqlDaemon) ApplyBinlogFile(ctx context.Context, req *mysqlctlpb.ApplyBinlogFileRequest) error {
 	return nil
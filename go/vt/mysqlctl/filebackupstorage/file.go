@@ -59,7 +59,9 @@ type FileBackupHandle struct {
 	dir      string
 	name     string
 	readOnly bool
-	errors   concurrency.AllErrorRecorder
+	// update is set for handles on existing backups, which replace files atomically
+	update bool
+	errors concurrency.AllErrorRecorder
 }
 
 func NewBackupHandle(
@@ -110,12 +112,37 @@ func (fbh *FileBackupHandle) AddFile(ctx context.Context, filename string, files
 		return nil, fmt.Errorf("AddFile cannot be called on read-only backup")
 	}
 	p := path.Join(FileBackupStorageRoot, fbh.dir, fbh.name, filename)
-	f, err := os.Create(p)
-	if err != nil {
-		return nil, err
+	var wc io.WriteCloser
+	if fbh.update {
+		f, err := os.Create(p + ".tmp")
+		if err != nil {
+			return nil, err
+		}
+		wc = &renameOnCloseFile{File: f, path: p}
+	} else {
+		f, err := os.Create(p)
+		if err != nil {
+			return nil, err
+		}
+		wc = f
 	}
 	stat := fbh.fbs.params.Stats.Scope(stats.Operation("File:Write"))
-	return ioutil.NewMeteredWriteCloser(f, stat.TimedIncrementBytes), nil
+	return ioutil.NewMeteredWriteCloser(wc, stat.TimedIncrementBytes), nil
+}
+
+// renameOnCloseFile is a temporary file which replaces the file at path when
+// closed, so that readers never see a partially written file.
+type renameOnCloseFile struct {
+	*os.File
+	path string
+}
+
+func (f *renameOnCloseFile) Close() error {
+	if err := f.File.Close(); err != nil {
+		os.Remove(f.File.Name())
+		return err
+	}
+	return os.Rename(f.File.Name(), f.path)
 }
 
 // EndBackup is part of the BackupHandle interface
@@ -199,6 +226,18 @@ func (fbs *FileBackupStorage) StartBackup(ctx context.Context, dir, name string)
 	return NewBackupHandle(fbs, dir, name, false /*readOnly*/), nil
 }
 
+// UpdateBackup is part of the BackupUpdater interface
+func (fbs *FileBackupStorage) UpdateBackup(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	p := path.Join(FileBackupStorageRoot, dir, name)
+	if _, err := os.Stat(p); err != nil {
+		return nil, err
+	}
+
+	bh := NewBackupHandle(fbs, dir, name, false /*readOnly*/).(*FileBackupHandle)
+	bh.update = true
+	return bh, nil
+}
+
 // RemoveBackup is part of the BackupStorage interface
 func (fbs *FileBackupStorage) RemoveBackup(ctx context.Context, dir, name string) error {
 	p := path.Join(FileBackupStorageRoot, dir, name)
@@ -215,5 +254,5 @@ func (fbs *FileBackupStorage) WithParams(params backupstorage.Params) backupstor
 }
 
 func init() {
-	backupstorage.BackupStorageMap["file"] = defaultFileBackupStorage
+	backupstorage.RegisterBackupStorage("file", defaultFileBackupStorage)
 }
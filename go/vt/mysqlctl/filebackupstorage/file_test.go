@@ -192,3 +192,56 @@ func TestFileContents(t *testing.T) {
 		t.Fatalf("rc.Close failed: %v", err)
 	}
 }
+
+func TestUpdateBackup(t *testing.T) {
+	fbs := setupFileBackupStorage(t)
+	ctx := context.Background()
+
+	dir := "keyspace/shard"
+	name := "cell-0001-2015-01-14-10-00-00"
+	if _, err := fbs.(backupstorage.BackupUpdater).UpdateBackup(ctx, dir, name); err == nil {
+		t.Fatalf("was able to UpdateBackup a backup which does not exist")
+	}
+
+	bh, err := fbs.StartBackup(ctx, dir, name)
+	if err != nil {
+		t.Fatalf("fbs.StartBackup failed: %v", err)
+	}
+	writeFile := func(bh backupstorage.BackupHandle, contents string) {
+		wc, err := bh.AddFile(ctx, "MANIFEST", 0)
+		if err != nil {
+			t.Fatalf("bh.AddFile failed: %v", err)
+		}
+		if _, err := wc.Write([]byte(contents)); err != nil {
+			t.Fatalf("wc.Write failed: %v", err)
+		}
+		if err := wc.Close(); err != nil {
+			t.Fatalf("wc.Close failed: %v", err)
+		}
+		if err := bh.EndBackup(ctx); err != nil {
+			t.Fatalf("bh.EndBackup failed: %v", err)
+		}
+	}
+	writeFile(bh, "original contents")
+
+	// replace the file of the existing backup
+	bh, err = fbs.(backupstorage.BackupUpdater).UpdateBackup(ctx, dir, name)
+	if err != nil {
+		t.Fatalf("fbs.UpdateBackup failed: %v", err)
+	}
+	writeFile(bh, "updated")
+
+	bhs, err := fbs.ListBackups(ctx, dir)
+	if err != nil || len(bhs) != 1 {
+		t.Fatalf("ListBackups after update returned wrong return: %v %v", err, bhs)
+	}
+	rc, err := bhs[0].ReadFile(ctx, "MANIFEST")
+	if err != nil {
+		t.Fatalf("bhs[0].ReadFile failed: %v", err)
+	}
+	defer rc.Close()
+	contents, err := io.ReadAll(rc)
+	if err != nil || string(contents) != "updated" {
+		t.Fatalf("ReadFile after update returned wrong result: %v %q", err, contents)
+	}
+}
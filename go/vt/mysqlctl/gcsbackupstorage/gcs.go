@@ -208,6 +208,12 @@ func (bs *GCSBackupStorage) StartBackup(ctx context.Context, dir, name string) (
 	}, nil
 }
 
+// UpdateBackup implements BackupUpdater. Objects are replaced atomically, so
+// it returns the same handle as StartBackup.
+func (bs *GCSBackupStorage) UpdateBackup(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	return bs.StartBackup(ctx, dir, name)
+}
+
 // RemoveBackup implements BackupStorage.
 func (bs *GCSBackupStorage) RemoveBackup(ctx context.Context, dir, name string) error {
 	c, err := bs.client(ctx)
@@ -294,5 +300,5 @@ func objName(parts ...string) string {
 }
 
 func init() {
-	backupstorage.BackupStorageMap["gcs"] = &GCSBackupStorage{}
+	backupstorage.RegisterBackupStorage("gcs", &GCSBackupStorage{})
 }
@@ -35,6 +35,9 @@ type MysqlDaemon interface {
 	Start(ctx context.Context, cnf *Mycnf, mysqldArgs ...string) error
 	Shutdown(ctx context.Context, cnf *Mycnf, waitForMysqld bool) error
 	RunMysqlUpgrade(ctx context.Context) error
+	// SetMysqlRoot makes mysqld start from the MySQL distribution in root
+	// from now on. It is called while mysqld is shut down.
+	SetMysqlRoot(root string) error
 	ApplyBinlogFile(ctx context.Context, req *mysqlctlpb.ApplyBinlogFileRequest) error
 	ReadBinlogFilesTimestamps(ctx context.Context, req *mysqlctlpb.ReadBinlogFilesTimestampsRequest) (*mysqlctlpb.ReadBinlogFilesTimestampsResponse, error)
 	ReinitConfig(ctx context.Context, cnf *Mycnf) error
@@ -17,11 +17,15 @@ limitations under the License.
 package mysqlctlproto
 
 import (
+	"time"
+
 	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/vt/mysqlctl"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 
 	mysqlctlpb "vitess.io/vitess/go/vt/proto/mysqlctl"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
 // BackupHandleToProto returns a BackupInfo proto from a BackupHandle.
@@ -44,3 +48,25 @@ func BackupHandleToProto(bh backupstorage.BackupHandle) *mysqlctlpb.BackupInfo {
 
 	return bi
 }
+
+// BackupVerificationToProto returns a BackupVerification proto from the
+// verification recorded in a backup MANIFEST. Fields which cannot be parsed
+// are left unset.
+func BackupVerificationToProto(v *mysqlctl.BackupVerification) *vtctldatapb.BackupVerification {
+	bv := &vtctldatapb.BackupVerification{
+		Position:      v.Position,
+		TablesChecked: int64(v.TablesChecked),
+		Mismatches:    v.Mismatches,
+		Error:         v.Error,
+	}
+
+	if t, err := time.Parse(time.RFC3339, v.Time); err == nil {
+		bv.Time = protoutil.TimeToProto(t)
+	}
+
+	if alias, err := topoproto.ParseTabletAlias(v.Replica); err == nil {
+		bv.Replica = alias
+	}
+
+	return bv
+}
@@ -23,10 +23,12 @@ import (
 
 	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/mysqlctl"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
 
 	mysqlctlpb "vitess.io/vitess/go/vt/proto/mysqlctl"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
 type backupHandle struct {
@@ -112,3 +114,32 @@ func TestBackupHandleToProto(t *testing.T) {
 		})
 	}
 }
+
+func TestBackupVerificationToProto(t *testing.T) {
+	t.Parallel()
+
+	got := BackupVerificationToProto(&mysqlctl.BackupVerification{
+		Time:          "2021-06-12T15:04:05Z",
+		Replica:       "zone1-0000000101",
+		Position:      "MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-615",
+		TablesChecked: 3,
+		Mismatches:    []string{"ks.t2"},
+	})
+	utils.MustMatch(t, &vtctldatapb.BackupVerification{
+		Time: protoutil.TimeToProto(time.Date(2021, time.June, 12, 15, 4, 5, 0, time.UTC)),
+		Replica: &topodatapb.TabletAlias{
+			Cell: "zone1",
+			Uid:  101,
+		},
+		Position:      "MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-615",
+		TablesChecked: 3,
+		Mismatches:    []string{"ks.t2"},
+	}, got)
+
+	got = BackupVerificationToProto(&mysqlctl.BackupVerification{
+		Time:    "not a time",
+		Replica: "not_an_alias",
+		Error:   "cannot restore backup",
+	})
+	utils.MustMatch(t, &vtctldatapb.BackupVerification{Error: "cannot restore backup"}, got)
+}
@@ -195,15 +195,7 @@ func GetVersionString() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	mysqldPath, err := binaryPath(mysqlRoot, "mysqld")
-	if err != nil {
-		return "", err
-	}
-	_, version, err := execCmd(mysqldPath, []string{"--version"}, nil, mysqlRoot, nil)
-	if err != nil {
-		return "", err
-	}
-	return version, nil
+	return GetMysqldVersion(mysqlRoot)
 }
 
 // ParseVersionString parses the output of mysqld --version into a flavor and version
@@ -658,7 +650,7 @@ func (mysqld *Mysqld) Init(ctx context.Context, cnf *Mycnf, initDBSQLFile string
 		return err
 	}
 	if initDBSQLFile == "" { // default to built-in
-		if err := mysqld.executeMysqlScript(ctx, params, config.DefaultInitDB); err != nil {
+		if err := mysqld.executeMysqlScript(ctx, params, mysqld.capabilities.compatibleInitDBSQL(config.DefaultInitDB)); err != nil {
 			return fmt.Errorf("failed to initialize mysqld: %v", err)
 		}
 		return nil
@@ -674,7 +666,7 @@ func (mysqld *Mysqld) Init(ctx context.Context, cnf *Mycnf, initDBSQLFile string
 	if err != nil {
 		return fmt.Errorf("can't read init_db_sql_file (%v): %v", initDBSQLFile, err)
 	}
-	if err := mysqld.executeMysqlScript(ctx, params, string(script)); err != nil {
+	if err := mysqld.executeMysqlScript(ctx, params, mysqld.capabilities.compatibleInitDBSQL(string(script))); err != nil {
 		return fmt.Errorf("can't run init_db_sql_file (%v): %v", initDBSQLFile, err)
 	}
 	return nil
@@ -812,7 +804,13 @@ func (mysqld *Mysqld) getMycnfTemplate() string {
 				log.Infof("this version of Vitess does not include built-in support for %v %v", mysqld.capabilities.flavor, mysqld.capabilities.version)
 			}
 		case 8:
-			versionConfig = config.MycnfMySQL80
+			if mysqld.capabilities.version.atLeast(ServerVersion{Major: 8, Minor: 4, Patch: 0}) {
+				versionConfig = config.MycnfMySQL84
+			} else {
+				versionConfig = config.MycnfMySQL80
+			}
+		case 9:
+			versionConfig = config.MycnfMySQL84
 		default:
 			log.Infof("this version of Vitess does not include built-in support for %v %v", mysqld.capabilities.flavor, mysqld.capabilities.version)
 		}
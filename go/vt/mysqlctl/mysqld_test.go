@@ -178,3 +178,34 @@ func TestParseBinlogEntryTimestamp(t *testing.T) {
 		})
 	}
 }
+
+func TestCompatibleInitDBSQL(t *testing.T) {
+	sql := "RESET SLAVE ALL;\nRESET MASTER;\nCREATE DATABASE IF NOT EXISTS _vt;\n"
+
+	mysql80 := newCapabilitySet(FlavorMySQL, ServerVersion{8, 0, 36})
+	assert.Equal(t, sql, mysql80.compatibleInitDBSQL(sql))
+
+	mysql84 := newCapabilitySet(FlavorMySQL, ServerVersion{8, 4, 0})
+	assert.Equal(t, "RESET REPLICA ALL;\nRESET BINARY LOGS AND GTIDS;\nCREATE DATABASE IF NOT EXISTS _vt;\n", mysql84.compatibleInitDBSQL(sql))
+
+	mariadb := newCapabilitySet(FlavorMariaDB, ServerVersion{10, 11, 0})
+	assert.Equal(t, sql, mariadb.compatibleInitDBSQL(sql))
+}
+
+func TestCompatibleSQLMode(t *testing.T) {
+	sqlMode := "ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_AUTO_CREATE_USER,NO_ENGINE_SUBSTITUTION"
+
+	mysql57 := newCapabilitySet(FlavorMySQL, ServerVersion{5, 7, 38})
+	assert.Equal(t, sqlMode, mysql57.compatibleSQLMode(sqlMode))
+
+	mysql84 := newCapabilitySet(FlavorMySQL, ServerVersion{8, 4, 0})
+	assert.Equal(t, "ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ENGINE_SUBSTITUTION", mysql84.compatibleSQLMode(sqlMode))
+	assert.Equal(t, "", mysql84.compatibleSQLMode("NO_AUTO_CREATE_USER"))
+}
+
+func TestResetBinaryLogsCommand(t *testing.T) {
+	assert.Equal(t, "RESET MASTER", resetBinaryLogsCommand("mysqld  Ver 8.0.36 for Linux on x86_64 (MySQL Community Server - GPL)"))
+	assert.Equal(t, "RESET BINARY LOGS AND GTIDS", resetBinaryLogsCommand("mysqld  Ver 8.4.0 for Linux on x86_64 (MySQL Community Server - GPL)"))
+	assert.Equal(t, "RESET BINARY LOGS AND GTIDS", resetBinaryLogsCommand("mysqld  Ver 9.0.1 for Linux on x86_64 (MySQL Community Server - GPL)"))
+	assert.Equal(t, "RESET MASTER", resetBinaryLogsCommand("8.4.0"))
+}
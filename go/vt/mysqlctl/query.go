@@ -228,23 +228,26 @@ func (mysqld *Mysqld) fetchStatuses(ctx context.Context, pattern string) (map[st
 
 const (
 	masterPasswordStart = "  MASTER_PASSWORD = '"
+	sourcePasswordStart = "  SOURCE_PASSWORD = '"
 	masterPasswordEnd   = "',\n"
 	passwordStart       = " PASSWORD = '"
 	passwordEnd         = "'"
 )
 
 func redactPassword(input string) string {
-	i := strings.Index(input, masterPasswordStart)
-	// We have primary password in the query, try to redact it
-	if i != -1 {
-		j := strings.Index(input[i+len(masterPasswordStart):], masterPasswordEnd)
-		if j == -1 {
-			return input
+	for _, primaryPasswordStart := range []string{masterPasswordStart, sourcePasswordStart} {
+		i := strings.Index(input, primaryPasswordStart)
+		// We have primary password in the query, try to redact it
+		if i != -1 {
+			j := strings.Index(input[i+len(primaryPasswordStart):], masterPasswordEnd)
+			if j == -1 {
+				return input
+			}
+			input = input[:i+len(primaryPasswordStart)] + strings.Repeat("*", 4) + input[i+len(primaryPasswordStart)+j:]
 		}
-		input = input[:i+len(masterPasswordStart)] + strings.Repeat("*", 4) + input[i+len(masterPasswordStart)+j:]
 	}
 	// We also check if we have any password keyword in the query
-	i = strings.Index(input, passwordStart)
+	i := strings.Index(input, passwordStart)
 	if i == -1 {
 		return input
 	}
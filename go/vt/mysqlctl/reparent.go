@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"vitess.io/vitess/go/constants/sidecar"
+	"vitess.io/vitess/go/mysql"
 	"vitess.io/vitess/go/mysql/replication"
 	"vitess.io/vitess/go/vt/sqlparser"
 
@@ -93,10 +94,16 @@ func (mysqld *Mysqld) Promote(hookExtraEnv map[string]string) (replication.Posit
 	}
 	defer conn.Recycle()
 
+	// RESET SLAVE is removed in MySQL 8.4.
+	resetReplicaCommand := "RESET SLAVE ALL"
+	if replicaTerminology, _ := conn.SupportsCapability(mysql.ReplicaTerminologyCapability); replicaTerminology {
+		resetReplicaCommand = "RESET REPLICA ALL"
+	}
+
 	// Since we handle replication, just stop it.
 	cmds := []string{
 		conn.StopReplicationCommand(),
-		"RESET SLAVE ALL", // "ALL" makes it forget primary host:port.
+		resetReplicaCommand, // "ALL" makes it forget primary host:port.
 		// When using semi-sync and GTID, a replica first connects to the new primary with a given GTID set,
 		// it can take a long time to scan the current binlog file to find the corresponding position.
 		// This can cause commits that occur soon after the primary is promoted to take a long time waiting
@@ -29,6 +29,7 @@ import (
 	"strings"
 	"time"
 
+	"vitess.io/vitess/go/mysql"
 	"vitess.io/vitess/go/mysql/replication"
 	"vitess.io/vitess/go/netutil"
 	"vitess.io/vitess/go/vt/hook"
@@ -547,12 +548,23 @@ func FindReplicas(mysqld MysqlDaemon) ([]string, error) {
 
 // GetBinlogInformation gets the binlog format, whether binlog is enabled and if updates on replica logging is enabled.
 func (mysqld *Mysqld) GetBinlogInformation(ctx context.Context) (string, bool, bool, string, error) {
-	qr, err := mysqld.FetchSuperQuery(ctx, "select @@global.binlog_format, @@global.log_bin, @@global.log_slave_updates, @@global.binlog_row_image")
+	// log_slave_updates is deprecated in favor of log_replica_updates as of MySQL 8.0.26.
+	logReplicaUpdatesVar := "log_slave_updates"
+	conn, err := getPoolReconnect(ctx, mysqld.dbaPool)
+	if err != nil {
+		return "", false, false, "", err
+	}
+	if replicaTerminology, _ := conn.SupportsCapability(mysql.ReplicaTerminologyCapability); replicaTerminology {
+		logReplicaUpdatesVar = "log_replica_updates"
+	}
+	conn.Recycle()
+
+	qr, err := mysqld.FetchSuperQuery(ctx, fmt.Sprintf("select @@global.binlog_format, @@global.log_bin, @@global.%s, @@global.binlog_row_image", logReplicaUpdatesVar))
 	if err != nil {
 		return "", false, false, "", err
 	}
 	if len(qr.Rows) != 1 {
-		return "", false, false, "", errors.New("unable to read global variables binlog_format, log_bin, log_slave_updates, gtid_mode, binlog_rowge")
+		return "", false, false, "", fmt.Errorf("unable to read global variables binlog_format, log_bin, %s, gtid_mode, binlog_rowge", logReplicaUpdatesVar)
 	}
 	res := qr.Named().Row()
 	binlogFormat, err := res.ToString("@@global.binlog_format")
@@ -563,7 +575,7 @@ func (mysqld *Mysqld) GetBinlogInformation(ctx context.Context) (string, bool, b
 	if err != nil {
 		return "", false, false, "", err
 	}
-	logReplicaUpdates, err := res.ToInt64("@@global.log_slave_updates")
+	logReplicaUpdates, err := res.ToInt64("@@global." + logReplicaUpdatesVar)
 	if err != nil {
 		return "", false, false, "", err
 	}
@@ -637,9 +649,10 @@ func (mysqld *Mysqld) SetSemiSyncEnabled(primary, replica bool) error {
 		s = 1
 	}
 
+	primaryTerm, replicaTerm := mysqld.semiSyncTerms(context.TODO())
 	err := mysqld.ExecuteSuperQuery(context.TODO(), fmt.Sprintf(
-		"SET GLOBAL rpl_semi_sync_master_enabled = %v, GLOBAL rpl_semi_sync_slave_enabled = %v",
-		p, s))
+		"SET GLOBAL rpl_semi_sync_%s_enabled = %v, GLOBAL rpl_semi_sync_%s_enabled = %v",
+		primaryTerm, p, replicaTerm, s))
 	if err != nil {
 		return fmt.Errorf("can't set semi-sync mode: %v; make sure plugins are loaded in my.cnf", err)
 	}
@@ -653,8 +666,9 @@ func (mysqld *Mysqld) SemiSyncEnabled() (primary, replica bool) {
 	if err != nil {
 		return false, false
 	}
-	primary = vars["rpl_semi_sync_master_enabled"] == "ON"
-	replica = vars["rpl_semi_sync_slave_enabled"] == "ON"
+	primaryTerm, replicaTerm := mysqld.semiSyncTerms(context.TODO())
+	primary = vars["rpl_semi_sync_"+primaryTerm+"_enabled"] == "ON"
+	replica = vars["rpl_semi_sync_"+replicaTerm+"_enabled"] == "ON"
 	return primary, replica
 }
 
@@ -664,14 +678,16 @@ func (mysqld *Mysqld) SemiSyncStatus() (primary, replica bool) {
 	if err != nil {
 		return false, false
 	}
-	primary = vars["Rpl_semi_sync_master_status"] == "ON"
-	replica = vars["Rpl_semi_sync_slave_status"] == "ON"
+	primaryTerm, replicaTerm := mysqld.semiSyncTerms(context.TODO())
+	primary = vars["Rpl_semi_sync_"+primaryTerm+"_status"] == "ON"
+	replica = vars["Rpl_semi_sync_"+replicaTerm+"_status"] == "ON"
 	return primary, replica
 }
 
 // SemiSyncClients returns the number of semi-sync clients for the primary.
 func (mysqld *Mysqld) SemiSyncClients() uint32 {
-	qr, err := mysqld.FetchSuperQuery(context.TODO(), "SHOW STATUS LIKE 'Rpl_semi_sync_master_clients'")
+	primaryTerm, _ := mysqld.semiSyncTerms(context.TODO())
+	qr, err := mysqld.FetchSuperQuery(context.TODO(), fmt.Sprintf("SHOW STATUS LIKE 'Rpl_semi_sync_%s_clients'", primaryTerm))
 	if err != nil {
 		return 0
 	}
@@ -689,19 +705,21 @@ func (mysqld *Mysqld) SemiSyncSettings() (timeout uint64, numReplicas uint32) {
 	if err != nil {
 		return 0, 0
 	}
-	timeout, _ = strconv.ParseUint(vars["rpl_semi_sync_master_timeout"], 10, 64)
-	numReplicasUint, _ := strconv.ParseUint(vars["rpl_semi_sync_master_wait_for_slave_count"], 10, 32)
+	primaryTerm, replicaTerm := mysqld.semiSyncTerms(context.TODO())
+	timeout, _ = strconv.ParseUint(vars["rpl_semi_sync_"+primaryTerm+"_timeout"], 10, 64)
+	numReplicasUint, _ := strconv.ParseUint(vars["rpl_semi_sync_"+primaryTerm+"_wait_for_"+replicaTerm+"_count"], 10, 32)
 	return timeout, uint32(numReplicasUint)
 }
 
 // SemiSyncReplicationStatus returns whether semi-sync is currently used by replication.
 func (mysqld *Mysqld) SemiSyncReplicationStatus() (bool, error) {
-	qr, err := mysqld.FetchSuperQuery(context.TODO(), "SHOW STATUS LIKE 'rpl_semi_sync_slave_status'")
+	_, replicaTerm := mysqld.semiSyncTerms(context.TODO())
+	qr, err := mysqld.FetchSuperQuery(context.TODO(), fmt.Sprintf("SHOW STATUS LIKE 'rpl_semi_sync_%s_status'", replicaTerm))
 	if err != nil {
 		return false, err
 	}
 	if len(qr.Rows) != 1 {
-		return false, errors.New("no rpl_semi_sync_slave_status variable in mysql")
+		return false, fmt.Errorf("no rpl_semi_sync_%s_status variable in mysql", replicaTerm)
 	}
 	if qr.Rows[0][1].ToString() == "ON" {
 		return true, nil
@@ -709,6 +727,18 @@ func (mysqld *Mysqld) SemiSyncReplicationStatus() (bool, error) {
 	return false, nil
 }
 
+// semiSyncTerms returns the terms of the primary and the replica in the
+// variables and statuses of the loaded semi-sync plugins, which are "source"
+// and "replica" for the plugins of MySQL 8.4, or "master" and "slave".
+func (mysqld *Mysqld) semiSyncTerms(ctx context.Context) (primary, replica string) {
+	conn, err := getPoolReconnect(ctx, mysqld.dbaPool)
+	if err != nil {
+		return "master", "slave"
+	}
+	defer conn.Recycle()
+	return conn.SemiSyncTerms()
+}
+
 // SemiSyncExtensionLoaded returns whether semi-sync plugins are loaded.
 func (mysqld *Mysqld) SemiSyncExtensionLoaded() (bool, error) {
 	qr, err := mysqld.FetchSuperQuery(context.Background(), "SELECT COUNT(*) > 0 AS plugin_loaded FROM information_schema.plugins WHERE plugin_name LIKE 'rpl_semi_sync%'")
@@ -57,6 +57,17 @@ func TestRedactMasterPassword(t *testing.T) {
   MASTER_PASSWORD = 'AAA`)
 }
 
+func TestRedactSourcePassword(t *testing.T) {
+	testRedacted(t, `CHANGE REPLICATION SOURCE TO
+  SOURCE_PASSWORD = 'AAA',
+  SOURCE_CONNECT_RETRY = 1
+`,
+		`CHANGE REPLICATION SOURCE TO
+  SOURCE_PASSWORD = '****',
+  SOURCE_CONNECT_RETRY = 1
+`)
+}
+
 func TestRedactPassword(t *testing.T) {
 	// regular case
 	testRedacted(t, `START xxx USER = 'vt_repl', PASSWORD = 'AAA'`,
@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"sync/atomic"
+	"time"
+
+	"vitess.io/vitess/go/vt/logutil"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+// restoreProgress tracks the progress of the files of a backup being restored
+// concurrently. Bytes are counted as read from the BackupStorage, i.e. before
+// decryption and decompression, so that they compare to the sizes recorded in
+// the MANIFEST.
+type restoreProgress struct {
+	backupName string
+	totalBytes int64
+	totalFiles int64
+	startTime  time.Time
+
+	bytesRestored atomic.Int64
+	filesRestored atomic.Int64
+}
+
+// newRestoreProgress returns the progress of the restore of the given files. The
+// total size is unknown if any of the files does not record its size, which is
+// the case for backups taken before the size was added to the MANIFEST.
+func newRestoreProgress(backupName string, fes []FileEntry) *restoreProgress {
+	rp := &restoreProgress{
+		backupName: backupName,
+		totalFiles: int64(len(fes)),
+		startTime:  time.Now(),
+	}
+	for _, fe := range fes {
+		if fe.Size == 0 {
+			rp.totalBytes = 0
+			break
+		}
+		rp.totalBytes += fe.Size
+	}
+	return rp
+}
+
+// addBytes has the signature of the ioutil.NewMeteredReader callbacks.
+func (rp *restoreProgress) addBytes(n int, _ time.Duration) {
+	rp.bytesRestored.Add(int64(n))
+}
+
+func (rp *restoreProgress) fileRestored() {
+	rp.filesRestored.Add(1)
+}
+
+// snapshot returns the current progress. The ETA extrapolates the throughput
+// so far, and is -1 until it is known.
+func (rp *restoreProgress) snapshot() *tabletmanagerdatapb.RestoreProgress {
+	progress := &tabletmanagerdatapb.RestoreProgress{
+		BackupName:    rp.backupName,
+		BytesRestored: rp.bytesRestored.Load(),
+		TotalBytes:    rp.totalBytes,
+		FilesRestored: rp.filesRestored.Load(),
+		TotalFiles:    rp.totalFiles,
+		EtaSeconds:    -1,
+	}
+	switch {
+	case progress.FilesRestored == progress.TotalFiles:
+		progress.EtaSeconds = 0
+	case progress.TotalBytes > 0 && progress.BytesRestored > 0:
+		elapsed := time.Since(rp.startTime)
+		left := progress.TotalBytes - progress.BytesRestored
+		if left < 0 {
+			left = 0
+		}
+		progress.EtaSeconds = int64(elapsed.Seconds() * float64(left) / float64(progress.BytesRestored))
+	}
+	return progress
+}
+
+// report logs the current progress, and sends it to the progress callback if set.
+func (rp *restoreProgress) report(logger logutil.Logger, callback func(*tabletmanagerdatapb.RestoreProgress)) {
+	progress := rp.snapshot()
+	if progress.TotalBytes > 0 {
+		logger.Infof("Restore progress of %v: %.02f%% (%d/%d bytes, %d/%d files), ETA %v",
+			progress.BackupName, 100.0*float64(progress.BytesRestored)/float64(progress.TotalBytes),
+			progress.BytesRestored, progress.TotalBytes, progress.FilesRestored, progress.TotalFiles,
+			time.Duration(progress.EtaSeconds)*time.Second)
+	} else {
+		logger.Infof("Restore progress of %v: %d bytes, %d/%d files",
+			progress.BackupName, progress.BytesRestored, progress.FilesRestored, progress.TotalFiles)
+	}
+	if callback != nil {
+		callback(progress)
+	}
+}
+
+// reportPeriodically reports the progress every period, until done is closed.
+func (rp *restoreProgress) reportPeriodically(period time.Duration, logger logutil.Logger, callback func(*tabletmanagerdatapb.RestoreProgress), done <-chan struct{}) {
+	tick := time.NewTicker(period)
+	defer tick.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-tick.C:
+			rp.report(logger, callback)
+		}
+	}
+}
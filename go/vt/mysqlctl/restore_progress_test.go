@@ -0,0 +1,77 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+func TestRestoreProgress(t *testing.T) {
+	fes := []FileEntry{{Name: "a", Size: 300}, {Name: "b", Size: 100}}
+	rp := newRestoreProgress("backup1", fes)
+
+	progress := rp.snapshot()
+	assert.Equal(t, "backup1", progress.BackupName)
+	assert.EqualValues(t, 400, progress.TotalBytes)
+	assert.EqualValues(t, 2, progress.TotalFiles)
+	assert.EqualValues(t, -1, progress.EtaSeconds, "the ETA is unknown until some bytes are restored")
+
+	rp.startTime = time.Now().Add(-10 * time.Second)
+	rp.addBytes(100, 0)
+	progress = rp.snapshot()
+	assert.EqualValues(t, 100, progress.BytesRestored)
+	assert.InDelta(t, 30, progress.EtaSeconds, 1)
+
+	rp.addBytes(300, 0)
+	rp.fileRestored()
+	rp.fileRestored()
+	progress = rp.snapshot()
+	assert.EqualValues(t, 400, progress.BytesRestored)
+	assert.EqualValues(t, 2, progress.FilesRestored)
+	assert.EqualValues(t, 0, progress.EtaSeconds)
+
+	logger := logutil.NewMemoryLogger()
+	var reported []*tabletmanagerdatapb.RestoreProgress
+	rp.report(logger, func(progress *tabletmanagerdatapb.RestoreProgress) {
+		reported = append(reported, progress)
+	})
+	require.Len(t, reported, 1)
+	assert.EqualValues(t, 400, reported[0].BytesRestored)
+	assert.Contains(t, logger.String(), "Restore progress of backup1: 100.00% (400/400 bytes, 2/2 files)")
+}
+
+func TestRestoreProgressUnknownSize(t *testing.T) {
+	// Backups taken before the size of the files was recorded
+	rp := newRestoreProgress("backup1", []FileEntry{{Name: "a", Size: 300}, {Name: "b"}})
+	rp.startTime = time.Now().Add(-10 * time.Second)
+	rp.addBytes(100, 0)
+	progress := rp.snapshot()
+	assert.EqualValues(t, 0, progress.TotalBytes)
+	assert.EqualValues(t, -1, progress.EtaSeconds)
+
+	logger := logutil.NewMemoryLogger()
+	rp.report(logger, nil)
+	assert.Contains(t, logger.String(), "Restore progress of backup1: 100 bytes, 0/2 files")
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3backupstorage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"vitess.io/vitess/go/vt/log"
+)
+
+// multipartWriter streams a file to s3 as a multipart upload. The written
+// data is buffered until a part is full, and the part is then uploaded with
+// its SHA-256 checksum, which s3 verifies before accepting it.
+type multipartWriter struct {
+	ctx      context.Context
+	bh       *S3BackupHandle
+	key      *string
+	uploadID *string
+	partSize int64
+
+	buf    []byte
+	parts  []*s3.CompletedPart
+	err    error
+	closed bool
+}
+
+// AddFileMultipart is part of the backupstorage.MultipartBackupHandle interface.
+func (bh *S3BackupHandle) AddFileMultipart(ctx context.Context, filename string, filesize int64) (io.WriteCloser, error) {
+	if bh.readOnly {
+		return nil, fmt.Errorf("AddFileMultipart cannot be called on read-only backup")
+	}
+
+	object := objName(bh.dir, bh.name, filename)
+	out, err := bh.client.CreateMultipartUploadWithContext(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:               &bucket,
+		Key:                  object,
+		ChecksumAlgorithm:    aws.String(s3.ChecksumAlgorithmSha256),
+		ServerSideEncryption: bh.bs.s3SSE.awsAlg,
+		SSECustomerAlgorithm: bh.bs.s3SSE.customerAlg,
+		SSECustomerKey:       bh.bs.s3SSE.customerKey,
+		SSECustomerKeyMD5:    bh.bs.s3SSE.customerMd5,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	partSizeBytes := partSize(filesize)
+	return &multipartWriter{
+		ctx:      ctx,
+		bh:       bh,
+		key:      object,
+		uploadID: out.UploadId,
+		partSize: partSizeBytes,
+		buf:      make([]byte, 0, partSizeBytes),
+	}, nil
+}
+
+// Write is part of the io.Writer interface.
+func (mw *multipartWriter) Write(p []byte) (int, error) {
+	if mw.err != nil {
+		return 0, mw.err
+	}
+	n := 0
+	for len(p) > 0 {
+		chunk := p
+		if free := int(mw.partSize) - len(mw.buf); len(chunk) > free {
+			chunk = chunk[:free]
+		}
+		mw.buf = append(mw.buf, chunk...)
+		n += len(chunk)
+		p = p[len(chunk):]
+		if int64(len(mw.buf)) == mw.partSize {
+			if err := mw.uploadPart(); err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// uploadPart uploads the buffered data as the next part.
+func (mw *multipartWriter) uploadPart() error {
+	checksum := sha256.Sum256(mw.buf)
+	partNumber := int64(len(mw.parts) + 1)
+	out, err := mw.bh.client.UploadPartWithContext(mw.ctx, &s3.UploadPartInput{
+		Bucket:               &bucket,
+		Key:                  mw.key,
+		UploadId:             mw.uploadID,
+		PartNumber:           aws.Int64(partNumber),
+		Body:                 bytes.NewReader(mw.buf),
+		ContentLength:        aws.Int64(int64(len(mw.buf))),
+		ChecksumAlgorithm:    aws.String(s3.ChecksumAlgorithmSha256),
+		ChecksumSHA256:       aws.String(base64.StdEncoding.EncodeToString(checksum[:])),
+		SSECustomerAlgorithm: mw.bh.bs.s3SSE.customerAlg,
+		SSECustomerKey:       mw.bh.bs.s3SSE.customerKey,
+		SSECustomerKeyMD5:    mw.bh.bs.s3SSE.customerMd5,
+	})
+	if err != nil {
+		mw.abort(fmt.Errorf("cannot upload part %d of %v: %w", partNumber, *mw.key, err))
+		return mw.err
+	}
+	mw.parts = append(mw.parts, &s3.CompletedPart{
+		ETag:           out.ETag,
+		PartNumber:     aws.Int64(partNumber),
+		ChecksumSHA256: out.ChecksumSHA256,
+	})
+	mw.buf = mw.buf[:0]
+	return nil
+}
+
+// abort records the error, and aborts the upload so that s3 frees the
+// parts uploaded so far.
+func (mw *multipartWriter) abort(err error) {
+	mw.err = err
+	// The upload is aborted even if the context was canceled.
+	if _, aerr := mw.bh.client.AbortMultipartUploadWithContext(context.Background(), &s3.AbortMultipartUploadInput{
+		Bucket:   &bucket,
+		Key:      mw.key,
+		UploadId: mw.uploadID,
+	}); aerr != nil {
+		log.Warningf("cannot abort the multipart upload of %v: %v", *mw.key, aerr)
+	}
+}
+
+// Close is part of the io.Closer interface. It uploads the last part and
+// completes the upload.
+func (mw *multipartWriter) Close() error {
+	if mw.closed {
+		return mw.err
+	}
+	mw.closed = true
+	if mw.err != nil {
+		return mw.err
+	}
+	// The last part may be smaller than the others, and is uploaded even if
+	// it is empty, since an upload has at least one part.
+	if len(mw.buf) > 0 || len(mw.parts) == 0 {
+		if err := mw.uploadPart(); err != nil {
+			return err
+		}
+	}
+	if _, err := mw.bh.client.CompleteMultipartUploadWithContext(mw.ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:   &bucket,
+		Key:      mw.key,
+		UploadId: mw.uploadID,
+		MultipartUpload: &s3.CompletedMultipartUpload{
+			Parts: mw.parts,
+		},
+	}); err != nil {
+		mw.abort(fmt.Errorf("cannot complete the multipart upload of %v: %w", *mw.key, err))
+		return mw.err
+	}
+	return nil
+}
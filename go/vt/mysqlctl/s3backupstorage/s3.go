@@ -150,16 +150,7 @@ func (bh *S3BackupHandle) AddFile(ctx context.Context, filename string, filesize
 		return nil, fmt.Errorf("AddFile cannot be called on read-only backup")
 	}
 
-	// Calculate s3 upload part size using the source filesize
-	partSizeBytes := s3manager.DefaultUploadPartSize
-	if filesize > 0 {
-		minimumPartSize := float64(filesize) / float64(s3manager.MaxUploadParts)
-		// Round up to ensure large enough partsize
-		calculatedPartSizeBytes := int64(math.Ceil(minimumPartSize))
-		if calculatedPartSizeBytes > partSizeBytes {
-			partSizeBytes = calculatedPartSizeBytes
-		}
-	}
+	partSizeBytes := partSize(filesize)
 
 	reader, writer := io.Pipe()
 	bh.waitGroup.Add(1)
@@ -189,6 +180,21 @@ func (bh *S3BackupHandle) AddFile(ctx context.Context, filename string, filesize
 	return writer, nil
 }
 
+// partSize returns the s3 upload part size of a file, derived from its size
+// so that it fits in the maximum number of parts.
+func partSize(filesize int64) int64 {
+	partSizeBytes := s3manager.DefaultUploadPartSize
+	if filesize > 0 {
+		minimumPartSize := float64(filesize) / float64(s3manager.MaxUploadParts)
+		// Round up to ensure large enough partsize
+		calculatedPartSizeBytes := int64(math.Ceil(minimumPartSize))
+		if calculatedPartSizeBytes > partSizeBytes {
+			partSizeBytes = calculatedPartSizeBytes
+		}
+	}
+	return partSizeBytes
+}
+
 // EndBackup is part of the backupstorage.BackupHandle interface.
 func (bh *S3BackupHandle) EndBackup(ctx context.Context) error {
 	if bh.readOnly {
@@ -225,7 +231,7 @@ func (bh *S3BackupHandle) ReadFile(ctx context.Context, filename string) (io.Rea
 	return out.Body, nil
 }
 
-var _ backupstorage.BackupHandle = (*S3BackupHandle)(nil)
+var _ backupstorage.MultipartBackupHandle = (*S3BackupHandle)(nil)
 
 type S3ServerSideEncryption struct {
 	awsAlg      *string
@@ -347,6 +353,12 @@ func (bs *S3BackupStorage) StartBackup(ctx context.Context, dir, name string) (b
 	}, nil
 }
 
+// UpdateBackup is part of the backupstorage.BackupUpdater interface. Objects
+// are replaced atomically, so it returns the same handle as StartBackup.
+func (bs *S3BackupStorage) UpdateBackup(ctx context.Context, dir, name string) (backupstorage.BackupHandle, error) {
+	return bs.StartBackup(ctx, dir, name)
+}
+
 // RemoveBackup is part of the backupstorage.BackupStorage interface.
 func (bs *S3BackupStorage) RemoveBackup(ctx context.Context, dir, name string) error {
 	log.Infof("RemoveBackup: [s3] dir: %v, name: %v, bucket: %v", dir, name, bucket)
@@ -485,7 +497,7 @@ func objName(parts ...string) *string {
 }
 
 func init() {
-	backupstorage.BackupStorageMap["s3"] = &S3BackupStorage{}
+	backupstorage.RegisterBackupStorage("s3", &S3BackupStorage{})
 
 	logNameMap = logNameToLogLevel{
 		"LogOff":                     aws.LogOff,
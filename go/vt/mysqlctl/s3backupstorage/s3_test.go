@@ -1,10 +1,15 @@
 package s3backupstorage
 
 import (
+	"bytes"
+	"context"
 	"crypto/md5"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"testing"
@@ -13,6 +18,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -46,6 +52,93 @@ func TestAddFileError(t *testing.T) {
 	require.Equal(t, bh.HasErrors(), true, "AddFile() expected bh to record async error but did not")
 }
 
+// s3MultipartClient records the parts of a multipart upload, and fails the
+// parts whose checksum does not match their data as s3 does.
+type s3MultipartClient struct {
+	s3iface.S3API
+	parts     [][]byte
+	completed []*s3.CompletedPart
+	aborted   bool
+	failPart  int64
+}
+
+func (c *s3MultipartClient) CreateMultipartUploadWithContext(ctx aws.Context, in *s3.CreateMultipartUploadInput, _ ...request.Option) (*s3.CreateMultipartUploadOutput, error) {
+	if aws.StringValue(in.ChecksumAlgorithm) != s3.ChecksumAlgorithmSha256 {
+		return nil, errors.New("missing checksum algorithm")
+	}
+	return &s3.CreateMultipartUploadOutput{UploadId: aws.String("upload")}, nil
+}
+
+func (c *s3MultipartClient) UploadPartWithContext(ctx aws.Context, in *s3.UploadPartInput, _ ...request.Option) (*s3.UploadPartOutput, error) {
+	if aws.Int64Value(in.PartNumber) == c.failPart {
+		return nil, errors.New("some error")
+	}
+	data, err := io.ReadAll(in.Body)
+	if err != nil {
+		return nil, err
+	}
+	checksum := sha256.Sum256(data)
+	if want := base64.StdEncoding.EncodeToString(checksum[:]); aws.StringValue(in.ChecksumSHA256) != want {
+		return nil, errors.New("BadDigest")
+	}
+	c.parts = append(c.parts, data)
+	return &s3.UploadPartOutput{ETag: aws.String(fmt.Sprintf("etag%d", len(c.parts))), ChecksumSHA256: in.ChecksumSHA256}, nil
+}
+
+func (c *s3MultipartClient) CompleteMultipartUploadWithContext(ctx aws.Context, in *s3.CompleteMultipartUploadInput, _ ...request.Option) (*s3.CompleteMultipartUploadOutput, error) {
+	c.completed = in.MultipartUpload.Parts
+	return &s3.CompleteMultipartUploadOutput{}, nil
+}
+
+func (c *s3MultipartClient) AbortMultipartUploadWithContext(ctx aws.Context, in *s3.AbortMultipartUploadInput, _ ...request.Option) (*s3.AbortMultipartUploadOutput, error) {
+	c.aborted = true
+	return &s3.AbortMultipartUploadOutput{}, nil
+}
+
+func TestAddFileMultipart(t *testing.T) {
+	client := &s3MultipartClient{}
+	bh := &S3BackupHandle{client: client, bs: &S3BackupStorage{}, dir: "dir", name: "name", readOnly: false}
+
+	data := make([]byte, 2*s3manager.DefaultUploadPartSize+100)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+
+	wc, err := bh.AddFileMultipart(context.Background(), "somefile", int64(len(data)))
+	require.NoError(t, err)
+	// Write in chunks which do not line up with the parts.
+	for chunk := data; len(chunk) > 0; {
+		n := min(len(chunk), 1000003)
+		_, err := wc.Write(chunk[:n])
+		require.NoError(t, err)
+		chunk = chunk[n:]
+	}
+	require.NoError(t, wc.Close())
+
+	require.Len(t, client.parts, 3)
+	require.Equal(t, data, bytes.Join(client.parts, nil))
+	require.Len(t, client.completed, 3)
+	for i, part := range client.completed {
+		assert.EqualValues(t, i+1, aws.Int64Value(part.PartNumber))
+		assert.NotEmpty(t, aws.StringValue(part.ChecksumSHA256))
+	}
+	assert.False(t, client.aborted)
+
+	// A failed part aborts the upload.
+	client = &s3MultipartClient{failPart: 2}
+	bh.client = client
+	wc, err = bh.AddFileMultipart(context.Background(), "somefile", int64(len(data)))
+	require.NoError(t, err)
+	_, err = wc.Write(data)
+	require.ErrorContains(t, err, "cannot upload part 2 of dir/name/somefile: some error")
+	require.ErrorContains(t, wc.Close(), "some error")
+	assert.True(t, client.aborted)
+	assert.Nil(t, client.completed)
+
+	bh.readOnly = true
+	_, err = bh.AddFileMultipart(context.Background(), "somefile", int64(len(data)))
+	require.EqualError(t, err, "AddFileMultipart cannot be called on read-only backup")
+}
+
 func TestNoSSE(t *testing.T) {
 	sseData := S3ServerSideEncryption{}
 	err := sseData.init()
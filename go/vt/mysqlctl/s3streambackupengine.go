@@ -0,0 +1,53 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+const s3StreamBackupEngineName = "s3stream"
+
+// S3StreamBackupEngine takes the same backups as the builtin engine, but
+// streams each file directly to the object storage as a multipart upload:
+// each part is uploaded with its SHA-256 checksum, which the storage verifies,
+// as soon as it is read, compressed and encrypted, so that nothing is staged
+// on the local disk. The SHA-256 checksum of each file is recorded in the
+// MANIFEST, and verified when the backup is restored or validated.
+//
+// It requires a backup storage whose handles implement
+// backupstorage.MultipartBackupHandle, such as s3.
+type S3StreamBackupEngine struct {
+	BuiltinBackupEngine
+}
+
+// ExecuteBackup is part of the BackupEngine interface.
+func (be *S3StreamBackupEngine) ExecuteBackup(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle) (bool, error) {
+	if _, ok := bh.(backupstorage.MultipartBackupHandle); !ok {
+		return false, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "the %v backup engine requires a backup storage which supports multipart uploads, such as s3", s3StreamBackupEngineName)
+	}
+	builtin := &BuiltinBackupEngine{multipart: true}
+	return builtin.ExecuteBackup(ctx, params, bh)
+}
+
+func init() {
+	RegisterBackupEngine(s3StreamBackupEngineName, &S3StreamBackupEngine{})
+}
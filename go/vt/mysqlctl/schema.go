@@ -523,7 +523,7 @@ func (mysqld *Mysqld) ApplySchemaChange(ctx context.Context, dbName string, chan
 	// The session used is closed after applying the schema change so we do not need
 	// to worry about saving and restoring the session state here
 	if change.SQLMode != "" {
-		sql = fmt.Sprintf("SET @@session.sql_mode='%s';\n%s", change.SQLMode, sql)
+		sql = fmt.Sprintf("SET @@session.sql_mode='%s';\n%s", mysqld.capabilities.compatibleSQLMode(change.SQLMode), sql)
 	}
 
 	if !change.AllowReplication {
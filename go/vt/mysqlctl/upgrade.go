@@ -0,0 +1,117 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+)
+
+// serverVersionRegex matches the version reported by a running server in
+// @@global.version, e.g. 8.0.36 or 8.0.36-28.
+var serverVersionRegex = regexp.MustCompile(`^([0-9]+)\.([0-9]+)\.([0-9]+)`)
+
+func (v ServerVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// ParseServerVersion parses the version reported by a running server in
+// @@global.version.
+func ParseServerVersion(version string) (ServerVersion, error) {
+	var ver ServerVersion
+	v := serverVersionRegex.FindStringSubmatch(version)
+	if len(v) != 4 {
+		return ver, fmt.Errorf("could not parse server version from: %s", version)
+	}
+	// The regexp only matches numbers.
+	ver.Major, _ = strconv.Atoi(v[1])
+	ver.Minor, _ = strconv.Atoi(v[2])
+	ver.Patch, _ = strconv.Atoi(v[3])
+	return ver, nil
+}
+
+// GetServerVersion returns the version of the running server.
+func GetServerVersion(ctx context.Context, mysqld MysqlDaemon) (ServerVersion, error) {
+	qr, err := mysqld.FetchSuperQuery(ctx, "SELECT @@global.version")
+	if err != nil {
+		return ServerVersion{}, err
+	}
+	if len(qr.Rows) != 1 {
+		return ServerVersion{}, fmt.Errorf("unexpected result length: %v", len(qr.Rows))
+	}
+	return ParseServerVersion(qr.Rows[0][0].ToString())
+}
+
+// GetMysqldVersion returns the output of mysqld --version for the MySQL
+// distribution in root.
+func GetMysqldVersion(root string) (string, error) {
+	mysqldPath, err := binaryPath(root, "mysqld")
+	if err != nil {
+		return "", err
+	}
+	_, version, err := execCmd(mysqldPath, []string{"--version"}, nil, root, nil)
+	if err != nil {
+		return "", err
+	}
+	return version, nil
+}
+
+// CheckUpgrade returns an error if going from a server running the from
+// version to the to version is a downgrade, which MySQL does not support in
+// place.
+func CheckUpgrade(from, to ServerVersion) error {
+	if !to.atLeast(from) {
+		return fmt.Errorf("cannot downgrade MySQL from %v to %v in place", from, to)
+	}
+	return nil
+}
+
+// SetMysqlRoot is part of the MysqlDaemon interface. It points
+// VT_MYSQL_ROOT, and VT_MYSQL_BASEDIR if it is set, to root, and detects the
+// capabilities of the mysqld found there. root must be a clean absolute path.
+func (mysqld *Mysqld) SetMysqlRoot(root string) error {
+	if !filepath.IsAbs(root) || filepath.Clean(root) != root {
+		return fmt.Errorf("MySQL distribution %v must be a clean absolute path", root)
+	}
+	if socketFile != "" {
+		return fmt.Errorf("cannot change the MySQL distribution of a mysqld managed by mysqlctld, restart mysqlctld with VT_MYSQL_ROOT=%v instead", root)
+	}
+
+	version, err := GetMysqldVersion(root)
+	if err != nil {
+		return err
+	}
+	f, v, err := ParseVersionString(version)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Setenv("VT_MYSQL_ROOT", root); err != nil {
+		return err
+	}
+	if os.Getenv("VT_MYSQL_BASEDIR") != "" {
+		if err := os.Setenv("VT_MYSQL_BASEDIR", root); err != nil {
+			return err
+		}
+	}
+	mysqld.capabilities = newCapabilitySet(f, v)
+	return nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseServerVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    ServerVersion
+		wantErr bool
+	}{
+		{
+			version: "8.0.36",
+			want:    ServerVersion{8, 0, 36},
+		},
+		{
+			version: "8.0.35-27",
+			want:    ServerVersion{8, 0, 35},
+		},
+		{
+			version: "5.7.44-log",
+			want:    ServerVersion{5, 7, 44},
+		},
+		{
+			version: "8.0",
+			wantErr: true,
+		},
+		{
+			version: "mysqld  Ver 8.0.36 for Linux on x86_64",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got, err := ParseServerVersion(tt.version)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCheckUpgrade(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    ServerVersion
+		to      ServerVersion
+		wantErr string
+	}{
+		{
+			name: "minor upgrade",
+			from: ServerVersion{8, 0, 35},
+			to:   ServerVersion{8, 0, 36},
+		},
+		{
+			name: "major upgrade",
+			from: ServerVersion{5, 7, 44},
+			to:   ServerVersion{8, 0, 36},
+		},
+		{
+			name: "same version",
+			from: ServerVersion{8, 0, 36},
+			to:   ServerVersion{8, 0, 36},
+		},
+		{
+			name:    "downgrade",
+			from:    ServerVersion{8, 0, 36},
+			to:      ServerVersion{8, 0, 35},
+			wantErr: "cannot downgrade MySQL from 8.0.36 to 8.0.35 in place",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckUpgrade(tt.from, tt.to)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestGetMysqldVersion(t *testing.T) {
+	root := t.TempDir()
+	_, err := GetMysqldVersion(root)
+	assert.ErrorContains(t, err, "mysqld not found")
+
+	require.NoError(t, os.Mkdir(path.Join(root, "bin"), 0755))
+	script := "#!/bin/sh\necho 'mysqld  Ver 8.0.36 for Linux on x86_64 (MySQL Community Server - GPL)'\n"
+	require.NoError(t, os.WriteFile(path.Join(root, "bin", "mysqld"), []byte(script), 0755))
+
+	version, err := GetMysqldVersion(root)
+	require.NoError(t, err)
+	flavor, v, err := ParseVersionString(version)
+	require.NoError(t, err)
+	assert.Equal(t, FlavorMySQL, flavor)
+	assert.Equal(t, ServerVersion{8, 0, 36}, v)
+}
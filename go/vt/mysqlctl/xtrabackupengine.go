@@ -64,6 +64,11 @@ var (
 	// striping mode
 	xtrabackupStripes         uint
 	xtrabackupStripeBlockSize = uint(102400)
+	// compression done by xtrabackup itself
+	xtrabackupCompress          string
+	xtrabackupCompressLevel     = 1
+	xtrabackupCompressThreads   = uint(1)
+	xtrabackupCompressChunkSize = uint(65536)
 )
 
 const (
@@ -74,6 +79,12 @@ const (
 	xbstream             = "xbstream"
 )
 
+// The compression algorithms of xtrabackup, see --xtrabackup-compress.
+const (
+	xtrabackupCompressZstd = "zstd"
+	xtrabackupCompressLz4  = "lz4"
+)
+
 // xtraBackupManifest represents a backup.
 // It stores the name of the backup file, the replication position,
 // whether the backup is compressed using gzip, and any extra
@@ -116,6 +127,15 @@ type xtraBackupManifest struct {
 	// ExternalDecompressor will be used. If neither are set, the restore will
 	// abort.
 	ExternalDecompressor string
+
+	// XtrabackupCompress is the algorithm xtrabackup compressed the files of
+	// the stream with, if any, in which case the stream is not compressed
+	// again and SkipCompress is true. The restore extracts such backups with
+	// xbstream --decompress.
+	XtrabackupCompress string `json:",omitempty"`
+	// XtrabackupCompressLevel is the zstd level of the compression done by
+	// xtrabackup.
+	XtrabackupCompressLevel int `json:",omitempty"`
 }
 
 func init() {
@@ -133,6 +153,10 @@ func registerXtraBackupEngineFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&xtrabackupUser, "xtrabackup_user", xtrabackupUser, "User that xtrabackup will use to connect to the database server. This user must have all necessary privileges. For details, please refer to xtrabackup documentation.")
 	fs.UintVar(&xtrabackupStripes, "xtrabackup_stripes", xtrabackupStripes, "If greater than 0, use data striping across this many destination files to parallelize data transfer and decompression")
 	fs.UintVar(&xtrabackupStripeBlockSize, "xtrabackup_stripe_block_size", xtrabackupStripeBlockSize, "Size in bytes of each block that gets sent to a given stripe before rotating to the next stripe")
+	fs.StringVar(&xtrabackupCompress, "xtrabackup-compress", xtrabackupCompress, "Compression algorithm xtrabackup applies to the files of the backup stream, zstd or lz4. Requires --xtrabackup_stream_mode=xbstream, and replaces the compression of the stream by Vitess. Restores decompress such backups automatically.")
+	fs.IntVar(&xtrabackupCompressLevel, "xtrabackup-compress-level", xtrabackupCompressLevel, "zstd compression level (1-19) used with --xtrabackup-compress=zstd.")
+	fs.UintVar(&xtrabackupCompressThreads, "xtrabackup-compress-threads", xtrabackupCompressThreads, "Number of threads xtrabackup compresses the files of the backup with, when --xtrabackup-compress is set.")
+	fs.UintVar(&xtrabackupCompressChunkSize, "xtrabackup-compress-chunk-size", xtrabackupCompressChunkSize, "Size in bytes of the blocks xtrabackup compresses independently, when --xtrabackup-compress is set.")
 }
 
 func (be *XtrabackupEngine) backupFileName() string {
@@ -141,7 +165,7 @@ func (be *XtrabackupEngine) backupFileName() string {
 		fileName += "."
 		fileName += xtrabackupStreamMode
 	}
-	if backupStorageCompress {
+	if compressStream() {
 		if ExternalDecompressorCmd != "" {
 			fileName += ExternalCompressorExt
 		} else {
@@ -156,6 +180,59 @@ func (be *XtrabackupEngine) backupFileName() string {
 	return fileName
 }
 
+// compressStream returns true if Vitess compresses the stream of xtrabackup,
+// which it does not when xtrabackup compresses the files itself.
+func compressStream() bool {
+	return backupStorageCompress && xtrabackupCompress == ""
+}
+
+// xtrabackupCompressFlags returns the flags of the compression done by
+// xtrabackup, if any.
+func xtrabackupCompressFlags() ([]string, error) {
+	switch xtrabackupCompress {
+	case "":
+		return nil, nil
+	case xtrabackupCompressZstd, xtrabackupCompressLz4:
+	default:
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "%v is not a valid value for --xtrabackup-compress, supported values are zstd and lz4", xtrabackupCompress)
+	}
+	if xtrabackupStreamMode != xbstream {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "--xtrabackup-compress requires --xtrabackup_stream_mode=xbstream")
+	}
+
+	flags := []string{"--compress=" + xtrabackupCompress}
+	if xtrabackupCompress == xtrabackupCompressZstd {
+		if xtrabackupCompressLevel < 1 || xtrabackupCompressLevel > 19 {
+			return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "--xtrabackup-compress-level must be between 1 and 19, got %d", xtrabackupCompressLevel)
+		}
+		flags = append(flags, fmt.Sprintf("--compress-zstd-level=%d", xtrabackupCompressLevel))
+	}
+	if xtrabackupCompressThreads > 0 {
+		flags = append(flags, fmt.Sprintf("--compress-threads=%d", xtrabackupCompressThreads))
+	}
+	if xtrabackupCompressChunkSize > 0 {
+		flags = append(flags, fmt.Sprintf("--compress-chunk-size=%d", xtrabackupCompressChunkSize))
+	}
+	return flags, nil
+}
+
+// xbstreamFlags returns the flags of the xbstream command extracting the
+// files of a backup. The files compressed by xtrabackup are decompressed by
+// concurrency threads, unless --xbstream_restore_flags already asks for it.
+func xbstreamFlags(bm xtraBackupManifest, tempDir string, concurrency int) []string {
+	flags := []string{"-C", tempDir, "-xv"}
+	if xbstreamRestoreFlags != "" {
+		flags = append(flags, strings.Fields(xbstreamRestoreFlags)...)
+	}
+	if bm.XtrabackupCompress != "" && !strings.Contains(xbstreamRestoreFlags, "--decompress") {
+		flags = append(flags, "--decompress")
+		if concurrency > 0 {
+			flags = append(flags, fmt.Sprintf("--decompress-threads=%d", concurrency))
+		}
+	}
+	return flags
+}
+
 func closeFile(wc io.WriteCloser, fileName string, logger logutil.Logger, finalErr *error) {
 	logger.Infof("Closing backup file %v", fileName)
 	if closeErr := wc.Close(); *finalErr == nil {
@@ -184,12 +261,22 @@ func (be *XtrabackupEngine) executeFullBackup(ctx context.Context, params Backup
 	if xtrabackupUser == "" {
 		return false, vterrors.New(vtrpc.Code_INVALID_ARGUMENT, "xtrabackupUser must be specified.")
 	}
+	if backupEncryptionKMS != "" {
+		return false, vterrors.New(vtrpc.Code_INVALID_ARGUMENT, "backup encryption is not supported in xtrabackup engine, use the builtin engine or unset --backup-encryption-kms.")
+	}
 
 	// an extension is required when using an external compressor
-	if backupStorageCompress && ExternalCompressorCmd != "" && ExternalCompressorExt == "" {
+	if compressStream() && ExternalCompressorCmd != "" && ExternalCompressorExt == "" {
 		return false, vterrors.New(vtrpc.Code_INVALID_ARGUMENT,
 			"flag --external-compressor-extension not provided when using an external compressor")
 	}
+	compressFlags, err := xtrabackupCompressFlags()
+	if err != nil {
+		return false, err
+	}
+	if xtrabackupCompress != "" && backupStorageCompress {
+		params.Logger.Infof("xtrabackup compresses the files of the backup with %v, so Vitess does not compress its stream", xtrabackupCompress)
+	}
 
 	// use a mysql connection to detect flavor at runtime
 	conn, err := params.Mysqld.GetDbaConnection(ctx)
@@ -227,7 +314,7 @@ func (be *XtrabackupEngine) executeFullBackup(ctx context.Context, params Backup
 	// maintaining the contract that a MANIFEST file should only exist if the
 	// backup was created successfully.
 	params.Logger.Infof("Starting backup with %v stripe(s)", numStripes)
-	replicationPosition, err := be.backupFiles(ctx, params, bh, backupFileName, numStripes, flavor)
+	replicationPosition, err := be.backupFiles(ctx, params, bh, backupFileName, numStripes, flavor, compressFlags)
 	if err != nil {
 		return false, err
 	}
@@ -262,7 +349,7 @@ func (be *XtrabackupEngine) executeFullBackup(ctx context.Context, params Backup
 		// XtraBackup-specific fields
 		FileName:        backupFileName,
 		StreamMode:      xtrabackupStreamMode,
-		SkipCompress:    !backupStorageCompress,
+		SkipCompress:    !compressStream(),
 		Params:          xtrabackupBackupFlags,
 		NumStripes:      int32(numStripes),
 		StripeBlockSize: int32(xtrabackupStripeBlockSize),
@@ -270,6 +357,12 @@ func (be *XtrabackupEngine) executeFullBackup(ctx context.Context, params Backup
 		CompressionEngine:    CompressionEngineName,
 		ExternalDecompressor: ManifestExternalDecompressorCmd,
 	}
+	if xtrabackupCompress != "" {
+		bm.XtrabackupCompress = xtrabackupCompress
+		if xtrabackupCompress == xtrabackupCompressZstd {
+			bm.XtrabackupCompressLevel = xtrabackupCompressLevel
+		}
+	}
 
 	data, err := json.MarshalIndent(bm, "", "  ")
 	if err != nil {
@@ -290,6 +383,7 @@ func (be *XtrabackupEngine) backupFiles(
 	backupFileName string,
 	numStripes int,
 	flavor string,
+	compressFlags []string,
 ) (replicationPosition replication.Position, finalErr error) {
 
 	backupProgram := path.Join(xtrabackupEnginePath, xtrabackupBinaryName)
@@ -303,6 +397,7 @@ func (be *XtrabackupEngine) backupFiles(
 	if xtrabackupStreamMode != "" {
 		flagsToExec = append(flagsToExec, "--stream="+xtrabackupStreamMode)
 	}
+	flagsToExec = append(flagsToExec, compressFlags...)
 	if xtrabackupBackupFlags != "" {
 		flagsToExec = append(flagsToExec, strings.Fields(xtrabackupBackupFlags)...)
 	}
@@ -369,7 +464,7 @@ func (be *XtrabackupEngine) backupFiles(
 		writer := io.Writer(buffer)
 
 		// Create the gzip compression pipe, if necessary.
-		if backupStorageCompress {
+		if compressStream() {
 			var compressor io.WriteCloser
 
 			if ExternalCompressorCmd != "" {
@@ -491,7 +586,7 @@ func (be *XtrabackupEngine) ExecuteRestore(ctx context.Context, params RestorePa
 	// copy / extract files
 	params.Logger.Infof("Restore: Extracting files from %v", bm.FileName)
 
-	if err := be.restoreFromBackup(ctx, params.Cnf, bh, bm, params.Logger); err != nil {
+	if err := be.restoreFromBackup(ctx, params.Cnf, bh, bm, params.Concurrency, params.Logger); err != nil {
 		// don't delete the file here because that is how we detect an interrupted restore
 		return nil, err
 	}
@@ -500,7 +595,7 @@ func (be *XtrabackupEngine) ExecuteRestore(ctx context.Context, params RestorePa
 	return &bm.BackupManifest, nil
 }
 
-func (be *XtrabackupEngine) restoreFromBackup(ctx context.Context, cnf *Mycnf, bh backupstorage.BackupHandle, bm xtraBackupManifest, logger logutil.Logger) error {
+func (be *XtrabackupEngine) restoreFromBackup(ctx context.Context, cnf *Mycnf, bh backupstorage.BackupHandle, bm xtraBackupManifest, concurrency int, logger logutil.Logger) error {
 	// first download the file into a tmp dir
 	// and extract all the files
 	tempDir := fmt.Sprintf("%v/%v", cnf.TmpDir, time.Now().UTC().Format("xtrabackup-2006-01-02.150405"))
@@ -526,7 +621,7 @@ func (be *XtrabackupEngine) restoreFromBackup(ctx context.Context, cnf *Mycnf, b
 		}()
 	}
 
-	if err := be.extractFiles(ctx, logger, bh, bm, tempDir); err != nil {
+	if err := be.extractFiles(ctx, logger, bh, bm, tempDir, concurrency); err != nil {
 		logger.Errorf("error extracting backup files: %v", err)
 		return err
 	}
@@ -603,7 +698,7 @@ func (be *XtrabackupEngine) restoreFromBackup(ctx context.Context, cnf *Mycnf, b
 }
 
 // restoreFile extracts all the files from the backup archive
-func (be *XtrabackupEngine) extractFiles(ctx context.Context, logger logutil.Logger, bh backupstorage.BackupHandle, bm xtraBackupManifest, tempDir string) error {
+func (be *XtrabackupEngine) extractFiles(ctx context.Context, logger logutil.Logger, bh backupstorage.BackupHandle, bm xtraBackupManifest, tempDir string, concurrency int) error {
 	// Pull details from the MANIFEST where available, so we can still restore
 	// backups taken with different flags. Some fields were not always present,
 	// so if necessary we default to the flag values.
@@ -714,10 +809,7 @@ func (be *XtrabackupEngine) extractFiles(ctx context.Context, logger logutil.Log
 	case xbstream:
 		// now extract the files by running xbstream
 		xbstreamProgram := path.Join(xtrabackupEnginePath, xbstream)
-		flagsToExec := []string{"-C", tempDir, "-xv"}
-		if xbstreamRestoreFlags != "" {
-			flagsToExec = append(flagsToExec, strings.Fields(xbstreamRestoreFlags)...)
-		}
+		flagsToExec := xbstreamFlags(bm, tempDir, concurrency)
 		xbstreamCmd := exec.CommandContext(ctx, xbstreamProgram, flagsToExec...)
 		logger.Infof("Executing xbstream cmd: %v %v", xbstreamProgram, flagsToExec)
 		xbstreamCmd.Stdin = reader
@@ -956,5 +1048,5 @@ func (be *XtrabackupEngine) ShouldDrainForBackup(req *tabletmanagerdatapb.Backup
 }
 
 func init() {
-	BackupRestoreEngineMap[xtrabackupEngineName] = &XtrabackupEngine{}
+	RegisterBackupEngine(xtrabackupEngineName, &XtrabackupEngine{})
 }
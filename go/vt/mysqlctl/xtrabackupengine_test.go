@@ -23,6 +23,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/vt/logutil"
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
@@ -125,3 +126,57 @@ func TestShouldDrainForBackupXtrabackup(t *testing.T) {
 	assert.False(t, be.ShouldDrainForBackup(nil))
 	assert.False(t, be.ShouldDrainForBackup(&tabletmanagerdatapb.BackupRequest{}))
 }
+
+func TestXtrabackupCompressFlags(t *testing.T) {
+	defer func(compress, streamMode string, level int, threads, chunkSize uint) {
+		xtrabackupCompress = compress
+		xtrabackupStreamMode = streamMode
+		xtrabackupCompressLevel = level
+		xtrabackupCompressThreads = threads
+		xtrabackupCompressChunkSize = chunkSize
+	}(xtrabackupCompress, xtrabackupStreamMode, xtrabackupCompressLevel, xtrabackupCompressThreads, xtrabackupCompressChunkSize)
+
+	xtrabackupCompress = ""
+	flags, err := xtrabackupCompressFlags()
+	require.NoError(t, err)
+	assert.Empty(t, flags)
+
+	xtrabackupCompress = "zstd"
+	_, err = xtrabackupCompressFlags()
+	assert.ErrorContains(t, err, "--xtrabackup-compress requires --xtrabackup_stream_mode=xbstream")
+
+	xtrabackupStreamMode = "xbstream"
+	xtrabackupCompressLevel = 9
+	xtrabackupCompressThreads = 4
+	flags, err = xtrabackupCompressFlags()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"--compress=zstd", "--compress-zstd-level=9", "--compress-threads=4", "--compress-chunk-size=65536"}, flags)
+
+	xtrabackupCompressLevel = 20
+	_, err = xtrabackupCompressFlags()
+	assert.ErrorContains(t, err, "--xtrabackup-compress-level must be between 1 and 19")
+
+	xtrabackupCompress = "lz4"
+	xtrabackupCompressChunkSize = 0
+	flags, err = xtrabackupCompressFlags()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"--compress=lz4", "--compress-threads=4"}, flags)
+
+	xtrabackupCompress = "quicklz"
+	_, err = xtrabackupCompressFlags()
+	assert.ErrorContains(t, err, "quicklz is not a valid value for --xtrabackup-compress")
+}
+
+func TestXbstreamFlags(t *testing.T) {
+	defer func(flags string) { xbstreamRestoreFlags = flags }(xbstreamRestoreFlags)
+
+	xbstreamRestoreFlags = "--parallel=2"
+	assert.Equal(t, []string{"-C", "/tmp/restore", "-xv", "--parallel=2"}, xbstreamFlags(xtraBackupManifest{}, "/tmp/restore", 4))
+
+	bm := xtraBackupManifest{XtrabackupCompress: "zstd", XtrabackupCompressLevel: 9}
+	assert.Equal(t, []string{"-C", "/tmp/restore", "-xv", "--parallel=2", "--decompress", "--decompress-threads=4"}, xbstreamFlags(bm, "/tmp/restore", 4))
+
+	// The decompression asked for with --xbstream_restore_flags is kept as is
+	xbstreamRestoreFlags = "--decompress --decompress-threads=8"
+	assert.Equal(t, []string{"-C", "/tmp/restore", "-xv", "--decompress", "--decompress-threads=8"}, xbstreamFlags(bm, "/tmp/restore", 4))
+}
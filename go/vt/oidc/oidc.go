@@ -0,0 +1,403 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package oidc verifies OpenID Connect id tokens. It is shared by the
+// components that authenticate their callers with an OpenID provider, such as
+// the vtadmin OIDC authenticator and the vtctld rbac policies.
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/vt/log"
+)
+
+// ErrInvalidIDToken is returned when an id token fails validation.
+var ErrInvalidIDToken = errors.New("invalid OIDC id token")
+
+const (
+	// clockSkew is the leeway allowed when checking the exp and nbf claims.
+	clockSkew = time.Minute
+	// keysRefreshInterval is the minimum time between two fetches of the
+	// provider's keys, which are refetched when a token is signed by an
+	// unknown key.
+	keysRefreshInterval = time.Minute
+)
+
+// Discovery is the subset of an OpenID provider's discovery document that is
+// used by this package and its callers.
+type Discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Verifier verifies the id tokens issued by an OpenID provider for a client.
+// Tokens must be signed with RS256, RS384, RS512, ES256 or ES384 by one of the
+// provider's published keys.
+//
+// The provider's discovery document and keys are fetched lazily, on first use.
+type Verifier struct {
+	issuerURL string
+	clientID  string
+	client    *http.Client
+	now       func() time.Time
+
+	m             sync.Mutex
+	discovery     *Discovery
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+}
+
+// NewVerifier returns a Verifier for the id tokens of the provider at
+// issuerURL, issued for clientID. Its discovery document must be served at
+// issuerURL + "/.well-known/openid-configuration". It does not contact the
+// provider.
+func NewVerifier(issuerURL string, clientID string) (*Verifier, error) {
+	if issuerURL == "" || clientID == "" {
+		return nil, fmt.Errorf("oidc verifier requires an issuer url and a client id")
+	}
+
+	return &Verifier{
+		issuerURL: strings.TrimSuffix(issuerURL, "/"),
+		clientID:  clientID,
+		client:    &http.Client{Timeout: 30 * time.Second},
+		now:       time.Now,
+	}, nil
+}
+
+// HTTPClient returns the client the Verifier uses to contact the provider.
+func (v *Verifier) HTTPClient() *http.Client {
+	return v.client
+}
+
+// Verify validates the signature and the standard claims of an id token, and
+// returns its claims.
+func (v *Verifier) Verify(ctx context.Context, token string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidIDToken)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: malformed header: %s", ErrInvalidIDToken, err)
+	}
+
+	if _, ok := jwtAlgorithms[header.Alg]; !ok {
+		return nil, fmt.Errorf("%w: unsupported signing algorithm %q", ErrInvalidIDToken, header.Alg)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature: %s", ErrInvalidIDToken, err)
+	}
+
+	key, err := v.getKey(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyJWTSignature(header.Alg, key, []byte(parts[0]+"."+parts[1]), sig); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidIDToken, err)
+	}
+
+	var claims map[string]any
+	if err := decodeJWTSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: malformed claims: %s", ErrInvalidIDToken, err)
+	}
+
+	discovery, err := v.Discovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if iss, _ := claims["iss"].(string); iss != discovery.Issuer {
+		return nil, fmt.Errorf("%w: unexpected issuer %q", ErrInvalidIDToken, iss)
+	}
+
+	if !audienceContains(claims["aud"], v.clientID) {
+		return nil, fmt.Errorf("%w: audience does not include %s", ErrInvalidIDToken, v.clientID)
+	}
+
+	now := v.now()
+
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("%w: missing exp claim", ErrInvalidIDToken)
+	}
+
+	if now.After(time.Unix(int64(exp), 0).Add(clockSkew)) {
+		return nil, fmt.Errorf("%w: token expired", ErrInvalidIDToken)
+	}
+
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(clockSkew).Before(time.Unix(int64(nbf), 0)) {
+		return nil, fmt.Errorf("%w: token not valid yet", ErrInvalidIDToken)
+	}
+
+	return claims, nil
+}
+
+func decodeJWTSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+func audienceContains(aud any, clientID string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == clientID
+	case []any:
+		for _, a := range aud {
+			if a == clientID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// jwtAlgorithms maps the supported JWT signing algorithms to their hash.
+var jwtAlgorithms = map[string]crypto.Hash{
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+	"ES256": crypto.SHA256,
+	"ES384": crypto.SHA384,
+}
+
+func verifyJWTSignature(alg string, key crypto.PublicKey, signed []byte, sig []byte) error {
+	hash, ok := jwtAlgorithms[alg]
+	if !ok {
+		return fmt.Errorf("unsupported signing algorithm %q", alg)
+	}
+
+	h := hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "RS") {
+			return fmt.Errorf("signing algorithm %s does not match an RSA key", alg)
+		}
+
+		return rsa.VerifyPKCS1v15(key, hash, digest, sig)
+	case *ecdsa.PublicKey:
+		if !strings.HasPrefix(alg, "ES") {
+			return fmt.Errorf("signing algorithm %s does not match an EC key", alg)
+		}
+
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return errors.New("malformed EC signature")
+		}
+
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(key, digest, r, s) {
+			return errors.New("signature verification failed")
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("unsupported key type %T", key)
+	}
+}
+
+// Discovery returns the provider's discovery document, fetching it on first
+// use.
+func (v *Verifier) Discovery(ctx context.Context) (*Discovery, error) {
+	v.m.Lock()
+	defer v.m.Unlock()
+
+	return v.getDiscoveryLocked(ctx)
+}
+
+func (v *Verifier) getDiscoveryLocked(ctx context.Context) (*Discovery, error) {
+	if v.discovery != nil {
+		return v.discovery, nil
+	}
+
+	var discovery Discovery
+	if err := v.getJSON(ctx, v.issuerURL+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, fmt.Errorf("failed to fetch OIDC discovery document: %w", err)
+	}
+
+	if strings.TrimSuffix(discovery.Issuer, "/") != v.issuerURL {
+		return nil, fmt.Errorf("OIDC discovery document issuer %q does not match %q", discovery.Issuer, v.issuerURL)
+	}
+
+	if discovery.JWKSURI == "" {
+		return nil, fmt.Errorf("OIDC discovery document has no jwks_uri")
+	}
+
+	v.discovery = &discovery
+	return v.discovery, nil
+}
+
+// getKey returns the provider's key with the given ID, refetching the keys if
+// it is unknown and they were not fetched recently.
+func (v *Verifier) getKey(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	v.m.Lock()
+	defer v.m.Unlock()
+
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	}
+
+	if v.keys != nil && v.now().Sub(v.keysFetchedAt) < keysRefreshInterval {
+		return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidIDToken, kid)
+	}
+
+	discovery, err := v.getDiscoveryLocked(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var jwks struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := v.getJSON(ctx, discovery.JWKSURI, &jwks); err != nil {
+		return nil, fmt.Errorf("failed to fetch OIDC keys: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
+	for _, k := range jwks.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		key, err := k.publicKey()
+		if err != nil {
+			log.Warningf("[oidc]: skipping OIDC key %q: %s", k.Kid, err)
+			continue
+		}
+
+		keys[k.Kid] = key
+	}
+
+	v.keys = keys
+	v.keysFetchedAt = v.now()
+
+	key, ok := v.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidIDToken, kid)
+	}
+
+	return key, nil
+}
+
+func (v *Verifier) getJSON(ctx context.Context, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: unexpected status %s", url, resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// jwk is a JSON Web Key, as published by OpenID providers.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	// RSA keys
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC keys
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k *jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		exp := new(big.Int).SetBytes(e)
+		if !exp.IsInt64() || exp.Int64() > 1<<31-1 {
+			return nil, errors.New("RSA exponent too large")
+		}
+
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exp.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
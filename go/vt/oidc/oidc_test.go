@@ -0,0 +1,160 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oidc
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/oidc/oidctest"
+)
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	p := oidctest.NewProvider(t, "vtctld")
+	verifier, err := NewVerifier(p.URL+"/", "vtctld")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		token     func() string
+		shouldErr bool
+	}{
+		{
+			name: "RS256",
+			token: func() string {
+				return p.Sign(t, "RS256", "rsa", p.Claims(nil))
+			},
+		},
+		{
+			name: "ES256",
+			token: func() string {
+				return p.Sign(t, "ES256", "ec", p.Claims(nil))
+			},
+		},
+		{
+			name: "audience list",
+			token: func() string {
+				return p.Sign(t, "RS256", "rsa", p.Claims(map[string]any{"aud": []string{"other", "vtctld"}}))
+			},
+		},
+		{
+			name: "expired",
+			token: func() string {
+				return p.Sign(t, "RS256", "rsa", p.Claims(map[string]any{"exp": time.Now().Add(-time.Hour).Unix()}))
+			},
+			shouldErr: true,
+		},
+		{
+			name: "missing exp",
+			token: func() string {
+				return p.Sign(t, "RS256", "rsa", p.Claims(map[string]any{"exp": nil}))
+			},
+			shouldErr: true,
+		},
+		{
+			name: "not valid yet",
+			token: func() string {
+				return p.Sign(t, "RS256", "rsa", p.Claims(map[string]any{"nbf": time.Now().Add(time.Hour).Unix()}))
+			},
+			shouldErr: true,
+		},
+		{
+			name: "wrong audience",
+			token: func() string {
+				return p.Sign(t, "RS256", "rsa", p.Claims(map[string]any{"aud": "other"}))
+			},
+			shouldErr: true,
+		},
+		{
+			name: "wrong issuer",
+			token: func() string {
+				return p.Sign(t, "RS256", "rsa", p.Claims(map[string]any{"iss": "https://evil.example.com"}))
+			},
+			shouldErr: true,
+		},
+		{
+			name: "unknown key",
+			token: func() string {
+				return p.Sign(t, "RS256", "other", p.Claims(nil))
+			},
+			shouldErr: true,
+		},
+		{
+			name: "algorithm mismatch",
+			token: func() string {
+				return p.Sign(t, "ES256", "rsa", p.Claims(nil))
+			},
+			shouldErr: true,
+		},
+		{
+			name: "alg none",
+			token: func() string {
+				return p.Sign(t, "none", "rsa", p.Claims(nil))
+			},
+			shouldErr: true,
+		},
+		{
+			name: "tampered claims",
+			token: func() string {
+				token := p.Sign(t, "RS256", "rsa", p.Claims(nil))
+				other := p.Sign(t, "RS256", "rsa", p.Claims(map[string]any{"email": "admin@example.com"}))
+
+				return strings.Join(append(strings.Split(other, ".")[:2], strings.Split(token, ".")[2]), ".")
+			},
+			shouldErr: true,
+		},
+		{
+			name: "malformed",
+			token: func() string {
+				return "not-a-jwt"
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			claims, err := verifier.Verify(context.Background(), tt.token())
+			if tt.shouldErr {
+				assert.ErrorIs(t, err, ErrInvalidIDToken)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "user@example.com", claims["email"])
+		})
+	}
+}
+
+func TestNewVerifier(t *testing.T) {
+	_, err := NewVerifier("", "vtctld")
+	assert.Error(t, err)
+
+	_, err = NewVerifier("https://accounts.example.com", "")
+	assert.Error(t, err)
+}
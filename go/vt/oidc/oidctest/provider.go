@@ -0,0 +1,174 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package oidctest provides a fake OpenID provider for tests.
+package oidctest
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Provider is an OpenID provider serving its discovery document, its keys and
+// a token endpoint that returns IDToken for the "good-code" authorization code.
+// It publishes an RSA key with ID "rsa" and a P-256 key with ID "ec".
+type Provider struct {
+	*httptest.Server
+
+	// ClientID is the audience of the tokens returned by Claims.
+	ClientID string
+	// IDToken is the id token returned by the token endpoint.
+	IDToken string
+
+	rsaKey *rsa.PrivateKey
+	ecKey  *ecdsa.PrivateKey
+}
+
+// NewProvider starts a Provider issuing tokens for clientID. It is closed when
+// the test ends.
+func NewProvider(t *testing.T, clientID string) *Provider {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	p := &Provider{
+		ClientID: clientID,
+		rsaKey:   rsaKey,
+		ecKey:    ecKey,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.URL,
+			"authorization_endpoint": p.URL + "/authorize",
+			"token_endpoint":         p.URL + "/token",
+			"jwks_uri":               p.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		b64 := base64.RawURLEncoding.EncodeToString
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{
+				{
+					"kty": "RSA",
+					"kid": "rsa",
+					"use": "sig",
+					"n":   b64(rsaKey.N.Bytes()),
+					"e":   b64(big.NewInt(int64(rsaKey.E)).Bytes()),
+				},
+				{
+					"kty": "EC",
+					"kid": "ec",
+					"crv": "P-256",
+					"x":   b64(ecKey.X.FillBytes(make([]byte, 32))),
+					"y":   b64(ecKey.Y.FillBytes(make([]byte, 32))),
+				},
+			},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("code") != "good-code" {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     p.IDToken,
+		})
+	})
+
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+
+	return p
+}
+
+// Claims returns valid claims for a token of the user@example.com user, in the
+// dev and dba groups, with the given overrides applied. A nil override removes
+// the claim.
+func (p *Provider) Claims(overrides map[string]any) map[string]any {
+	claims := map[string]any{
+		"iss":    p.URL,
+		"aud":    p.ClientID,
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"iat":    time.Now().Unix(),
+		"email":  "user@example.com",
+		"groups": []string{"dev", "dba"},
+	}
+
+	for k, v := range overrides {
+		if v == nil {
+			delete(claims, k)
+			continue
+		}
+
+		claims[k] = v
+	}
+
+	return claims
+}
+
+// Sign returns a token with the given claims, signed with the key of the given
+// ID. alg must be RS256 or ES256 to produce a valid signature; any other value
+// produces a token with an empty signature.
+func (p *Provider) Sign(t *testing.T, alg string, kid string, claims map[string]any) string {
+	t.Helper()
+
+	header, err := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	require.NoError(t, err)
+
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := crypto.SHA256.New()
+	digest.Write([]byte(signed))
+
+	var sig []byte
+	switch alg {
+	case "RS256":
+		sig, err = rsa.SignPKCS1v15(rand.Reader, p.rsaKey, crypto.SHA256, digest.Sum(nil))
+		require.NoError(t, err)
+	case "ES256":
+		r, s, err := ecdsa.Sign(rand.Reader, p.ecKey, digest.Sum(nil))
+		require.NoError(t, err)
+
+		sig = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/shlex"
@@ -28,6 +29,8 @@ import (
 var (
 	strategyParserRegexp       = regexp.MustCompile(`^([\S]+)\s+(.*)$`)
 	cutOverThresholdFlagRegexp = regexp.MustCompile(fmt.Sprintf(`^[-]{1,2}%s=(.*?)$`, cutOverThresholdFlag))
+	priorityClassFlagRegexp    = regexp.MustCompile(fmt.Sprintf(`^[-]{1,2}%s=(.*?)$`, priorityClassFlag))
+	dependsOnFlagRegexp        = regexp.MustCompile(fmt.Sprintf(`^[-]{1,2}%s=(.*?)$`, dependsOnFlag))
 )
 
 const (
@@ -46,6 +49,11 @@ const (
 	vreplicationTestSuite  = "vreplication-test-suite"
 	allowForeignKeysFlag   = "unsafe-allow-foreign-keys"
 	analyzeTableFlag       = "analyze-table"
+	coordinatedCutOverFlag = "coordinated-cut-over"
+	priorityClassFlag      = "priority-class"
+	allowDestructiveFlag   = "allow-destructive"
+	revertibleFlag         = "revertible"
+	dependsOnFlag          = "depends-on"
 )
 
 // DDLStrategy suggests how an ALTER TABLE should run (e.g. "direct", "online", "gh-ost" or "pt-osc")
@@ -110,6 +118,19 @@ func ParseDDLStrategy(strategyVariable string) (*DDLStrategySetting, error) {
 	if _, err := setting.CutOverThreshold(); err != nil {
 		return nil, err
 	}
+	if setting.IsRevertibleFlag() && setting.Strategy.IsDirect() {
+		return nil, fmt.Errorf("--%s requires a managed strategy, such as '%s': direct changes are not tracked as migrations, and cannot be reverted", revertibleFlag, DDLStrategyMySQL)
+	}
+	if dependsOn := setting.DependsOn(); len(dependsOn) > 0 {
+		if setting.Strategy.IsDirect() {
+			return nil, fmt.Errorf("--%s requires an online strategy: direct changes are not scheduled", dependsOnFlag)
+		}
+		for _, uuid := range dependsOn {
+			if !IsOnlineDDLUUID(uuid) {
+				return nil, fmt.Errorf("--%s: not a valid migration UUID: '%s'", dependsOnFlag, uuid)
+			}
+		}
+	}
 	return setting, nil
 }
 
@@ -212,6 +233,59 @@ func (setting *DDLStrategySetting) CutOverThreshold() (d time.Duration, err erro
 	return d, err
 }
 
+// isPriorityClassFlag returns true when given option denotes a `--priority-class=[...]` flag
+func isPriorityClassFlag(opt string) (string, bool) {
+	submatch := priorityClassFlagRegexp.FindStringSubmatch(opt)
+	if len(submatch) == 0 {
+		return "", false
+	}
+	return submatch[1], true
+}
+
+// PriorityClass returns the priority class specified in '--priority-class=...', or an empty string if unspecified
+func (setting *DDLStrategySetting) PriorityClass() string {
+	opts, _ := shlex.Split(setting.Options)
+	for _, opt := range opts {
+		if val, isPriorityClass := isPriorityClassFlag(opt); isPriorityClass {
+			// value is possibly quoted
+			if s, err := strconv.Unquote(val); err == nil {
+				val = s
+			}
+			return val
+		}
+	}
+	return ""
+}
+
+// isDependsOnFlag returns true when given option denotes a `--depends-on=[...]` flag
+func isDependsOnFlag(opt string) (string, bool) {
+	submatch := dependsOnFlagRegexp.FindStringSubmatch(opt)
+	if len(submatch) == 0 {
+		return "", false
+	}
+	return submatch[1], true
+}
+
+// DependsOn returns the UUIDs of the migrations specified in '--depends-on=...', which must complete before
+// this migration launches. The flag is repeatable, and takes a comma separated list of UUIDs.
+func (setting *DDLStrategySetting) DependsOn() (uuids []string) {
+	opts, _ := shlex.Split(setting.Options)
+	for _, opt := range opts {
+		if val, isDependsOn := isDependsOnFlag(opt); isDependsOn {
+			// value is possibly quoted
+			if s, err := strconv.Unquote(val); err == nil {
+				val = s
+			}
+			for _, uuid := range strings.Split(val, ",") {
+				if uuid = strings.TrimSpace(uuid); uuid != "" {
+					uuids = append(uuids, uuid)
+				}
+			}
+		}
+	}
+	return uuids
+}
+
 // IsVreplicationTestSuite checks if strategy options include --vreplicatoin-test-suite
 func (setting *DDLStrategySetting) IsVreplicationTestSuite() bool {
 	return setting.hasFlag(vreplicationTestSuite)
@@ -227,6 +301,21 @@ func (setting *DDLStrategySetting) IsAnalyzeTableFlag() bool {
 	return setting.hasFlag(analyzeTableFlag)
 }
 
+// IsCoordinatedCutOverFlag checks if strategy options include --coordinated-cut-over
+func (setting *DDLStrategySetting) IsCoordinatedCutOverFlag() bool {
+	return setting.hasFlag(coordinatedCutOverFlag)
+}
+
+// IsAllowDestructiveFlag checks if strategy options include --allow-destructive
+func (setting *DDLStrategySetting) IsAllowDestructiveFlag() bool {
+	return setting.hasFlag(allowDestructiveFlag)
+}
+
+// IsRevertibleFlag checks if strategy options include --revertible
+func (setting *DDLStrategySetting) IsRevertibleFlag() bool {
+	return setting.hasFlag(revertibleFlag)
+}
+
 // RuntimeOptions returns the options used as runtime flags for given strategy, removing any internal hint options
 func (setting *DDLStrategySetting) RuntimeOptions() []string {
 	opts, _ := shlex.Split(setting.Options)
@@ -235,6 +324,12 @@ func (setting *DDLStrategySetting) RuntimeOptions() []string {
 		if _, ok := isCutOverThresholdFlag(opt); ok {
 			continue
 		}
+		if _, ok := isPriorityClassFlag(opt); ok {
+			continue
+		}
+		if _, ok := isDependsOnFlag(opt); ok {
+			continue
+		}
 		switch {
 		case isFlag(opt, declarativeFlag):
 		case isFlag(opt, skipTopoFlag):
@@ -250,6 +345,9 @@ func (setting *DDLStrategySetting) RuntimeOptions() []string {
 		case isFlag(opt, vreplicationTestSuite):
 		case isFlag(opt, allowForeignKeysFlag):
 		case isFlag(opt, analyzeTableFlag):
+		case isFlag(opt, coordinatedCutOverFlag):
+		case isFlag(opt, allowDestructiveFlag):
+		case isFlag(opt, revertibleFlag):
 		default:
 			validOpts = append(validOpts, opt)
 		}
@@ -117,7 +117,12 @@ func TestParseDDLStrategy(t *testing.T) {
 		fastRangeRotation    bool
 		allowForeignKeys     bool
 		analyzeTable         bool
+		coordinatedCutOver   bool
+		allowDestructive     bool
+		revertible           bool
 		cutOverThreshold     time.Duration
+		priorityClass        string
+		dependsOn            []string
 		runtimeOptions       string
 		err                  error
 	}{
@@ -246,6 +251,44 @@ func TestParseDDLStrategy(t *testing.T) {
 			runtimeOptions:   "",
 			analyzeTable:     true,
 		},
+		{
+			strategyVariable:   "vitess --coordinated-cut-over",
+			strategy:           DDLStrategyVitess,
+			options:            "--coordinated-cut-over",
+			runtimeOptions:     "",
+			coordinatedCutOver: true,
+		},
+		{
+			strategyVariable: "vitess --priority-class=urgent --postpone-launch",
+			strategy:         DDLStrategyVitess,
+			options:          "--priority-class=urgent --postpone-launch",
+			runtimeOptions:   "",
+			isPostponeLaunch: true,
+			priorityClass:    "urgent",
+		},
+		{
+			strategyVariable: "vitess --declarative --allow-destructive",
+			strategy:         DDLStrategyVitess,
+			options:          "--declarative --allow-destructive",
+			runtimeOptions:   "",
+			isDeclarative:    true,
+			allowDestructive: true,
+		},
+		{
+			strategyVariable: "mysql --revertible",
+			strategy:         DDLStrategyMySQL,
+			options:          "--revertible",
+			runtimeOptions:   "",
+			revertible:       true,
+		},
+		{
+			strategyVariable:  "vitess --depends-on=6ce1d1e8_4a1f_11ee_9b67_0a43f95f28a3,6ce1d26a_4a1f_11ee_9b67_0a43f95f28a3 --depends-on='7b4e8a8e_4a1f_11ee_9b67_0a43f95f28a3' --allow-concurrent",
+			strategy:          DDLStrategyVitess,
+			options:           "--depends-on=6ce1d1e8_4a1f_11ee_9b67_0a43f95f28a3,6ce1d26a_4a1f_11ee_9b67_0a43f95f28a3 --depends-on='7b4e8a8e_4a1f_11ee_9b67_0a43f95f28a3' --allow-concurrent",
+			runtimeOptions:    "",
+			isAllowConcurrent: true,
+			dependsOn:         []string{"6ce1d1e8_4a1f_11ee_9b67_0a43f95f28a3", "6ce1d26a_4a1f_11ee_9b67_0a43f95f28a3", "7b4e8a8e_4a1f_11ee_9b67_0a43f95f28a3"},
+		},
 	}
 	for _, ts := range tt {
 		t.Run(ts.strategyVariable, func(t *testing.T) {
@@ -262,9 +305,14 @@ func TestParseDDLStrategy(t *testing.T) {
 			assert.Equal(t, ts.fastRangeRotation, setting.IsFastRangeRotationFlag())
 			assert.Equal(t, ts.allowForeignKeys, setting.IsAllowForeignKeysFlag())
 			assert.Equal(t, ts.analyzeTable, setting.IsAnalyzeTableFlag())
+			assert.Equal(t, ts.coordinatedCutOver, setting.IsCoordinatedCutOverFlag())
+			assert.Equal(t, ts.allowDestructive, setting.IsAllowDestructiveFlag())
+			assert.Equal(t, ts.revertible, setting.IsRevertibleFlag())
 			cutOverThreshold, err := setting.CutOverThreshold()
 			assert.NoError(t, err)
 			assert.Equal(t, ts.cutOverThreshold, cutOverThreshold)
+			assert.Equal(t, ts.priorityClass, setting.PriorityClass())
+			assert.Equal(t, ts.dependsOn, setting.DependsOn())
 
 			runtimeOptions := strings.Join(setting.RuntimeOptions(), " ")
 			assert.Equal(t, ts.runtimeOptions, runtimeOptions)
@@ -282,4 +330,16 @@ func TestParseDDLStrategy(t *testing.T) {
 		_, err := ParseDDLStrategy("online --cut-over-threshold=3")
 		assert.Error(t, err)
 	}
+	{
+		_, err := ParseDDLStrategy("direct --revertible")
+		assert.Error(t, err)
+	}
+	{
+		_, err := ParseDDLStrategy("direct --depends-on=6ce1d1e8_4a1f_11ee_9b67_0a43f95f28a3")
+		assert.Error(t, err)
+	}
+	{
+		_, err := ParseDDLStrategy("vitess --depends-on=not-a-uuid")
+		assert.Error(t, err)
+	}
 }
@@ -0,0 +1,60 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// DestructiveChanges returns a description of the changes of the given statement which lose data:
+// dropped tables, dropped columns and dropped or truncated partitions. Declarative migrations only
+// apply such changes with --allow-destructive.
+func DestructiveChanges(stmt sqlparser.Statement) (changes []string) {
+	switch stmt := stmt.(type) {
+	case *sqlparser.DropTable:
+		for _, table := range stmt.FromTables {
+			changes = append(changes, fmt.Sprintf("table %s dropped", sqlparser.String(table.Name)))
+		}
+	case *sqlparser.AlterTable:
+		tableName := sqlparser.String(stmt.Table.Name)
+		for _, option := range stmt.AlterOptions {
+			if dropColumn, ok := option.(*sqlparser.DropColumn); ok {
+				changes = append(changes, fmt.Sprintf("column %s.%s dropped", tableName, sqlparser.String(dropColumn.Name.Name)))
+			}
+		}
+		if spec := stmt.PartitionSpec; spec != nil {
+			partitionNames := "all"
+			if !spec.IsAll {
+				var names []string
+				for _, name := range spec.Names {
+					names = append(names, name.String())
+				}
+				partitionNames = strings.Join(names, ", ")
+			}
+			switch spec.Action {
+			case sqlparser.DropAction:
+				changes = append(changes, fmt.Sprintf("partitions %s of table %s dropped", partitionNames, tableName))
+			case sqlparser.TruncateAction:
+				changes = append(changes, fmt.Sprintf("partitions %s of table %s truncated", partitionNames, tableName))
+			}
+		}
+	}
+	return changes
+}
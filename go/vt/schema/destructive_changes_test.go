@@ -0,0 +1,73 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+func TestDestructiveChanges(t *testing.T) {
+	tt := []struct {
+		sql     string
+		changes []string
+	}{
+		{
+			sql: "create table t (id int primary key)",
+		},
+		{
+			sql: "alter table t add column i int, modify column j bigint",
+		},
+		{
+			sql:     "alter table t add column i int, drop column j, drop column k",
+			changes: []string{"column t.j dropped", "column t.k dropped"},
+		},
+		{
+			sql:     "alter table t drop partition p1, p2",
+			changes: []string{"partitions p1, p2 of table t dropped"},
+		},
+		{
+			sql:     "alter table t truncate partition p1",
+			changes: []string{"partitions p1 of table t truncated"},
+		},
+		{
+			sql:     "alter table t truncate partition all",
+			changes: []string{"partitions all of table t truncated"},
+		},
+		{
+			sql: "alter table t add partition (partition p3 values less than (30))",
+		},
+		{
+			sql:     "drop table t1, t2",
+			changes: []string{"table t1 dropped", "table t2 dropped"},
+		},
+		{
+			sql: "drop view v",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.sql, func(t *testing.T) {
+			stmt, err := sqlparser.Parse(tc.sql)
+			require.NoError(t, err)
+			assert.Equal(t, tc.changes, DestructiveChanges(stmt))
+		})
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// launchWindowField is one of the fields of a launch window: the set of values it matches, as bits.
+type launchWindowField struct {
+	bits       uint64
+	restricted bool
+}
+
+func (f launchWindowField) matches(value int) bool {
+	return f.bits&(1<<uint(value)) != 0
+}
+
+// LaunchWindow is a cron-like expression of the minutes during which postponed migrations
+// are launched. It has five space separated fields: minute, hour, day of month, month and
+// day of week, in UTC. Each field is `*`, a value, a range `a-b`, a step `*/n` or `a-b/n`,
+// or a comma separated list of them. As in cron, when both the day of month and the day of
+// week are restricted, a day matching either of them is in the window.
+// For example, `* 1-5 * * 1-5` is open from 01:00 to 05:59 UTC on weekdays.
+type LaunchWindow struct {
+	minute, hour, dayOfMonth, month, dayOfWeek launchWindowField
+}
+
+// ParseLaunchWindow parses a launch window expression
+func ParseLaunchWindow(expr string) (*LaunchWindow, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("launch window %q: expected 5 fields (minute hour day-of-month month day-of-week), found %d", expr, len(fields))
+	}
+	w := &LaunchWindow{}
+	for i, spec := range []struct {
+		field    *launchWindowField
+		name     string
+		min, max int
+	}{
+		{&w.minute, "minute", 0, 59},
+		{&w.hour, "hour", 0, 23},
+		{&w.dayOfMonth, "day-of-month", 1, 31},
+		{&w.month, "month", 1, 12},
+		{&w.dayOfWeek, "day-of-week", 0, 7},
+	} {
+		field, err := parseLaunchWindowField(fields[i], spec.min, spec.max)
+		if err != nil {
+			return nil, fmt.Errorf("launch window %q: invalid %s field: %v", expr, spec.name, err)
+		}
+		*spec.field = field
+	}
+	// Both 0 and 7 denote Sunday
+	if w.dayOfWeek.matches(7) {
+		w.dayOfWeek.bits |= 1
+	}
+	return w, nil
+}
+
+func parseLaunchWindowField(s string, min, max int) (field launchWindowField, err error) {
+	for _, part := range strings.Split(s, ",") {
+		rangeSpec, step := part, 1
+		if slash := strings.Index(part, "/"); slash >= 0 {
+			rangeSpec = part[:slash]
+			if step, err = strconv.Atoi(part[slash+1:]); err != nil || step <= 0 {
+				return field, fmt.Errorf("invalid step in %q", part)
+			}
+		}
+		from, to := min, max
+		switch {
+		case rangeSpec == "*":
+			if step == 1 {
+				// A plain `*` does not restrict the field
+				field.bits |= rangeBits(min, max, 1)
+				continue
+			}
+		case strings.Contains(rangeSpec, "-"):
+			bounds := strings.SplitN(rangeSpec, "-", 2)
+			if from, err = strconv.Atoi(bounds[0]); err != nil {
+				return field, fmt.Errorf("invalid value in %q", part)
+			}
+			if to, err = strconv.Atoi(bounds[1]); err != nil {
+				return field, fmt.Errorf("invalid value in %q", part)
+			}
+		default:
+			if from, err = strconv.Atoi(rangeSpec); err != nil {
+				return field, fmt.Errorf("invalid value in %q", part)
+			}
+			to = from
+		}
+		if from < min || to > max || from > to {
+			return field, fmt.Errorf("%q out of range [%d, %d]", part, min, max)
+		}
+		field.bits |= rangeBits(from, to, step)
+		field.restricted = true
+	}
+	return field, nil
+}
+
+func rangeBits(from, to, step int) (bits uint64) {
+	for value := from; value <= to; value += step {
+		bits |= 1 << uint(value)
+	}
+	return bits
+}
+
+// IsOpen returns true when the given time is within the launch window
+func (w *LaunchWindow) IsOpen(t time.Time) bool {
+	t = t.UTC()
+	if !w.minute.matches(t.Minute()) || !w.hour.matches(t.Hour()) || !w.month.matches(int(t.Month())) {
+		return false
+	}
+	dayOfMonth := w.dayOfMonth.matches(t.Day())
+	dayOfWeek := w.dayOfWeek.matches(int(t.Weekday()))
+	if w.dayOfMonth.restricted && w.dayOfWeek.restricted {
+		return dayOfMonth || dayOfWeek
+	}
+	return dayOfMonth && dayOfWeek
+}
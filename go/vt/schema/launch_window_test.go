@@ -0,0 +1,120 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLaunchWindow(t *testing.T) {
+	// 2023-07-03 is a Monday
+	monday := func(hour, minute int) time.Time {
+		return time.Date(2023, time.July, 3, hour, minute, 0, 0, time.UTC)
+	}
+	tt := []struct {
+		expr   string
+		open   []time.Time
+		closed []time.Time
+		err    string
+	}{
+		{
+			expr: "* * * * *",
+			open: []time.Time{monday(0, 0), monday(23, 59)},
+		},
+		{
+			expr:   "* 1-5 * * 1-5",
+			open:   []time.Time{monday(1, 0), monday(5, 59)},
+			closed: []time.Time{monday(0, 59), monday(6, 0), monday(1, 0).AddDate(0, 0, 5)},
+		},
+		{
+			expr:   "*/15 22,23 * * *",
+			open:   []time.Time{monday(22, 0), monday(23, 45)},
+			closed: []time.Time{monday(22, 1), monday(21, 0)},
+		},
+		{
+			expr:   "0-29/10 3 * * *",
+			open:   []time.Time{monday(3, 20)},
+			closed: []time.Time{monday(3, 5), monday(3, 30)},
+		},
+		{
+			// Sunday is both 0 and 7
+			expr:   "* * * * 7",
+			open:   []time.Time{monday(12, 0).AddDate(0, 0, -1)},
+			closed: []time.Time{monday(12, 0)},
+		},
+		{
+			// Either the day of month or the day of week
+			expr:   "* * 1 * 1",
+			open:   []time.Time{monday(12, 0), time.Date(2023, time.July, 1, 12, 0, 0, 0, time.UTC)},
+			closed: []time.Time{monday(12, 0).AddDate(0, 0, 1)},
+		},
+		{
+			expr:   "* * * 8 *",
+			closed: []time.Time{monday(12, 0)},
+		},
+		{
+			// The window is in UTC
+			expr:   "* 1 * * *",
+			open:   []time.Time{monday(1, 0).In(time.FixedZone("UTC+2", 2*3600))},
+			closed: []time.Time{time.Date(2023, time.July, 3, 1, 0, 0, 0, time.FixedZone("UTC+2", 2*3600))},
+		},
+		{
+			expr: "* * * *",
+			err:  "expected 5 fields",
+		},
+		{
+			expr: "60 * * * *",
+			err:  "invalid minute field",
+		},
+		{
+			expr: "* 5-1 * * *",
+			err:  "invalid hour field",
+		},
+		{
+			expr: "* * 0 * *",
+			err:  "invalid day-of-month field",
+		},
+		{
+			expr: "*/0 * * * *",
+			err:  "invalid step",
+		},
+		{
+			expr: "* * * jan *",
+			err:  "invalid month field",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.expr, func(t *testing.T) {
+			w, err := ParseLaunchWindow(tc.expr)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			for _, ts := range tc.open {
+				assert.True(t, w.IsOpen(ts), "expected open at %v", ts)
+			}
+			for _, ts := range tc.closed {
+				assert.False(t, w.IsOpen(ts), "expected closed at %v", ts)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemamanager
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	sqlSelectAppliedChangelogIDs = "SELECT changelog_id FROM %s.schema_changelog WHERE keyspace = %a"
+	sqlInsertChangelogEntry      = "INSERT INTO %s.schema_changelog (keyspace, changelog_id, changelog_sql) VALUES (%a, %a, %a)"
+)
+
+// changelogEntryRegexp matches the line which starts a changelog entry, e.g. `--changeset 0001-create-customer`
+var changelogEntryRegexp = regexp.MustCompile(`^--\s*changeset\s+(\S+)\s*$`)
+
+// ChangelogEntry is a versioned schema change of a changelog
+type ChangelogEntry struct {
+	ID  string
+	SQL string
+}
+
+// ParseChangelog parses an ordered changelog of schema changes. Each entry starts with a `--changeset <id>`
+// line, followed by a single statement. Entry IDs are unique, and identify the entries which were already
+// applied to a keyspace.
+func ParseChangelog(changelog string) (entries []*ChangelogEntry, err error) {
+	ids := map[string]bool{}
+	var id string
+	var lines []string
+	addEntry := func() error {
+		if id == "" {
+			return nil
+		}
+		pieces, err := sqlparser.SplitStatementToPieces(strings.Join(lines, "\n"))
+		if err != nil {
+			return vterrors.Wrapf(err, "changeset %s", id)
+		}
+		var sqls []string
+		for _, piece := range pieces {
+			if piece = strings.TrimSpace(piece); piece != "" {
+				sqls = append(sqls, piece)
+			}
+		}
+		if len(sqls) != 1 {
+			return vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "changeset %s must have exactly one statement, found %d", id, len(sqls))
+		}
+		entries = append(entries, &ChangelogEntry{ID: id, SQL: sqls[0]})
+		return nil
+	}
+	for _, line := range strings.Split(changelog, "\n") {
+		if match := changelogEntryRegexp.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+			if err := addEntry(); err != nil {
+				return nil, err
+			}
+			id = match[1]
+			if ids[id] {
+				return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "duplicate changeset %s", id)
+			}
+			ids[id] = true
+			lines = nil
+			continue
+		}
+		if id == "" {
+			// Only blank lines and comments are allowed before the first entry
+			if trimmed := strings.TrimSpace(line); trimmed != "" && !strings.HasPrefix(trimmed, "--") && !strings.HasPrefix(trimmed, "#") {
+				return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "statement outside of a changeset: %s", trimmed)
+			}
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := addEntry(); err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "changelog has no changeset")
+	}
+	return entries, nil
+}
+
+// SetChangelogIDs sets the changelog IDs of the sqls to execute. An entry is only executed on the shards on
+// which it was not applied yet, and is recorded in the sidecar database of each shard it is applied on.
+func (exec *TabletExecutor) SetChangelogIDs(ids []string) error {
+	idsMap := map[string]bool{}
+	for _, id := range ids {
+		if id == "" {
+			return fmt.Errorf("empty changelog ID")
+		}
+		idsMap[id] = true
+	}
+	if len(idsMap) != len(ids) {
+		return fmt.Errorf("changelog IDs must be unique")
+	}
+	exec.changelogIDs = ids
+	return nil
+}
+
+// hasChangelog returns true when the sqls are changelog entries
+func (exec *TabletExecutor) hasChangelog() bool {
+	return len(exec.changelogIDs) != 0
+}
+
+// readAppliedChangelogIDs returns the IDs of the changelog entries which were applied on each shard
+func (exec *TabletExecutor) readAppliedChangelogIDs(ctx context.Context, sidecarDBName string) (map[string]map[string]bool, error) {
+	parsed := sqlparser.BuildParsedQuery(sqlSelectAppliedChangelogIDs, sqlescape.EscapeID(sidecarDBName), ":keyspace")
+	query, err := parsed.GenerateQuery(map[string]*querypb.BindVariable{
+		"keyspace": sqltypes.StringBindVariable(exec.keyspace),
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	applied := make(map[string]map[string]bool, len(exec.tablets))
+	for _, tablet := range exec.tablets {
+		qr, err := exec.tmc.ExecuteFetchAsDba(ctx, tablet, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+			Query:   []byte(query),
+			MaxRows: math.MaxInt32,
+		})
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "unable to read the applied changelog entries of shard %s", tablet.Shard)
+		}
+		applied[tablet.Shard] = map[string]bool{}
+		for _, row := range sqltypes.Proto3ToResult(qr).Rows {
+			applied[tablet.Shard][row[0].ToString()] = true
+		}
+	}
+	return applied, nil
+}
+
+// pendingChangelogTablets returns the tablets of the shards on which the given changelog entry was not applied
+func (exec *TabletExecutor) pendingChangelogTablets(applied map[string]map[string]bool, id string) (tablets []*topodatapb.Tablet) {
+	for _, tablet := range exec.tablets {
+		if !applied[tablet.Shard][id] {
+			tablets = append(tablets, tablet)
+		}
+	}
+	return tablets
+}
+
+// recordChangelogEntry records the given changelog entry as applied on the shards on which it succeeded
+func (exec *TabletExecutor) recordChangelogEntry(ctx context.Context, sidecarDBName string, tablets []*topodatapb.Tablet, successShards []ShardResult, id string, sql string) error {
+	parsed := sqlparser.BuildParsedQuery(sqlInsertChangelogEntry, sqlescape.EscapeID(sidecarDBName), ":keyspace", ":changelog_id", ":changelog_sql")
+	query, err := parsed.GenerateQuery(map[string]*querypb.BindVariable{
+		"keyspace":      sqltypes.StringBindVariable(exec.keyspace),
+		"changelog_id":  sqltypes.StringBindVariable(id),
+		"changelog_sql": sqltypes.StringBindVariable(sql),
+	}, nil)
+	if err != nil {
+		return err
+	}
+	succeeded := map[string]bool{}
+	for _, result := range successShards {
+		succeeded[result.Shard] = true
+	}
+	for _, tablet := range tablets {
+		if !succeeded[tablet.Shard] {
+			continue
+		}
+		if _, err := exec.tmc.ExecuteFetchAsDba(ctx, tablet, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+			Query:   []byte(query),
+			MaxRows: 0,
+		}); err != nil {
+			return vterrors.Wrapf(err, "unable to record changeset %s as applied on shard %s", id, tablet.Shard)
+		}
+	}
+	return nil
+}
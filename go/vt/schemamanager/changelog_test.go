@@ -0,0 +1,153 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemamanager
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestParseChangelog(t *testing.T) {
+	tt := []struct {
+		name          string
+		changelog     string
+		expectEntries []*ChangelogEntry
+		expectErr     string
+	}{
+		{
+			name: "entries",
+			changelog: `-- the customer schema
+
+--changeset 0001-create-customer
+CREATE TABLE customer (id bigint, PRIMARY KEY (id));
+
+-- changeset 0002
+ALTER TABLE customer
+  ADD COLUMN email varchar(128)
+`,
+			expectEntries: []*ChangelogEntry{
+				{ID: "0001-create-customer", SQL: "CREATE TABLE customer (id bigint, PRIMARY KEY (id))"},
+				{ID: "0002", SQL: "ALTER TABLE customer\n  ADD COLUMN email varchar(128)"},
+			},
+		},
+		{
+			name:      "empty",
+			changelog: "-- nothing here\n",
+			expectErr: "changelog has no changeset",
+		},
+		{
+			name:      "statement outside of a changeset",
+			changelog: "CREATE TABLE t (id int);\n--changeset 1\nDROP TABLE t",
+			expectErr: "statement outside of a changeset",
+		},
+		{
+			name:      "duplicate",
+			changelog: "--changeset 1\nCREATE TABLE t (id int)\n--changeset 1\nDROP TABLE t",
+			expectErr: "duplicate changeset 1",
+		},
+		{
+			name:      "multiple statements",
+			changelog: "--changeset 1\nCREATE TABLE t (id int); DROP TABLE t;",
+			expectErr: "changeset 1 must have exactly one statement, found 2",
+		},
+		{
+			name:      "no statement",
+			changelog: "--changeset 1\n--changeset 2\nDROP TABLE t",
+			expectErr: "changeset 1 must have exactly one statement, found 0",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			entries, err := ParseChangelog(tc.changelog)
+			if tc.expectErr != "" {
+				assert.ErrorContains(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectEntries, entries)
+		})
+	}
+}
+
+// changelogTabletManagerClient serves the changelog entries applied on each shard, and records the
+// queries executed on each shard.
+type changelogTabletManagerClient struct {
+	*fakeTabletManagerClient
+	mu       sync.Mutex
+	applied  map[string][]string
+	executed map[string][]string
+}
+
+func (client *changelogTabletManagerClient) ExecuteFetchAsDba(ctx context.Context, tablet *topodatapb.Tablet, usePool bool, req *tabletmanagerdatapb.ExecuteFetchAsDbaRequest) (*querypb.QueryResult, error) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	query := string(req.Query)
+	if strings.HasPrefix(query, "SELECT changelog_id FROM `_vt`.schema_changelog WHERE keyspace = 'test_keyspace'") {
+		result := sqltypes.MakeTestResult(sqltypes.MakeTestFields("changelog_id", "varchar"), client.applied[tablet.Shard]...)
+		return sqltypes.ResultToProto3(result), nil
+	}
+	client.executed[tablet.Shard] = append(client.executed[tablet.Shard], query)
+	return &querypb.QueryResult{}, nil
+}
+
+func TestTabletExecutorChangelog(t *testing.T) {
+	fakeTmc := &changelogTabletManagerClient{
+		fakeTabletManagerClient: newFakeTabletManagerClient(),
+		applied: map[string][]string{
+			"0": {"1", "2"},
+			"1": {"1"},
+		},
+		executed: map[string][]string{},
+	}
+	executor := NewTabletExecutor("TestTabletExecutorChangelog", newFakeTopo(t), fakeTmc, logutil.NewConsoleLogger(), testWaitReplicasTimeout, 0)
+	require.NoError(t, executor.SetDDLStrategy("direct"))
+	require.NoError(t, executor.SetChangelogIDs([]string{"1", "2"}))
+
+	ctx := context.Background()
+	require.NoError(t, executor.Open(ctx, "test_keyspace"))
+	defer executor.Close()
+
+	result := executor.Execute(ctx, []string{"CREATE TABLE t (id int)", "ALTER TABLE t ADD COLUMN c int"})
+	require.Empty(t, result.ExecutorErr)
+	require.Empty(t, result.FailedShards)
+
+	assert.Empty(t, fakeTmc.executed["0"])
+	assert.Equal(t, []string{
+		"ALTER TABLE t ADD COLUMN c int",
+		"INSERT INTO `_vt`.schema_changelog (keyspace, changelog_id, changelog_sql) VALUES ('test_keyspace', '2', 'ALTER TABLE t ADD COLUMN c int')",
+	}, fakeTmc.executed["1"])
+	assert.Equal(t, []string{
+		"CREATE TABLE t (id int)",
+		"INSERT INTO `_vt`.schema_changelog (keyspace, changelog_id, changelog_sql) VALUES ('test_keyspace', '1', 'CREATE TABLE t (id int)')",
+		"ALTER TABLE t ADD COLUMN c int",
+		"INSERT INTO `_vt`.schema_changelog (keyspace, changelog_id, changelog_sql) VALUES ('test_keyspace', '2', 'ALTER TABLE t ADD COLUMN c int')",
+	}, fakeTmc.executed["2"])
+
+	assert.ErrorContains(t, executor.SetChangelogIDs([]string{"1", "1"}), "changelog IDs must be unique")
+}
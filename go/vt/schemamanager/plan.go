@@ -0,0 +1,194 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemamanager
+
+import (
+	"context"
+	"fmt"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/schemadiff"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// ShardPlan is the list of statements which a schema change runs on a shard
+type ShardPlan struct {
+	Shard string
+	// Statements are the statements which run on the shard. For declarative migrations, these are the
+	// statements resulting from the diff with the current schema of the shard.
+	Statements []string
+	// DestructiveChanges are the changes of declarative migrations which lose data, and which require
+	// --allow-destructive.
+	DestructiveChanges []string
+}
+
+// Plan reads the schema changes of the controller, and returns the statements which Run would apply
+// on every shard, without applying them.
+func Plan(ctx context.Context, controller Controller, executor *TabletExecutor) (plans []*ShardPlan, err error) {
+	if err := controller.Open(ctx); err != nil {
+		log.Errorf("failed to open data sourcer: %v", err)
+		return nil, err
+	}
+	defer controller.Close()
+	sqls, err := controller.Read(ctx)
+	if err != nil {
+		log.Errorf("failed to read data from data sourcer: %v", err)
+		return nil, err
+	}
+	if len(sqls) == 0 {
+		return nil, nil
+	}
+	if err := executor.Open(ctx, controller.Keyspace()); err != nil {
+		log.Errorf("failed to open executor: %v", err)
+		return nil, err
+	}
+	defer executor.Close()
+	if err := executor.Validate(ctx, sqls); err != nil {
+		log.Errorf("validation fail: %v", err)
+		return nil, err
+	}
+	return executor.Plan(ctx, sqls)
+}
+
+// isDeclarative returns true when the ddl_strategy is an Online DDL strategy with --declarative
+func (exec *TabletExecutor) isDeclarative() bool {
+	return !exec.isDirectStrategy() && exec.ddlStrategySetting.IsDeclarative()
+}
+
+// Plan returns the statements which Execute would run on every shard. Declarative statements are
+// diffed with the current schema of the primary tablet of each shard.
+func (exec *TabletExecutor) Plan(ctx context.Context, sqls []string) (plans []*ShardPlan, err error) {
+	if exec.isClosed {
+		return nil, fmt.Errorf("executor is closed")
+	}
+	if !exec.isDirectStrategy() && exec.ddlStrategySetting.IsCoordinatedCutOverFlag() {
+		if sqls, err = mergeAlterTableSQLs(sqls); err != nil {
+			return nil, err
+		}
+	}
+	stmts := make([]sqlparser.Statement, 0, len(sqls))
+	var tables []string
+	for _, sql := range sqls {
+		stmt, err := sqlparser.Parse(sql)
+		if err != nil {
+			return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "failed to parse sql: %s, got error: %v", sql, err)
+		}
+		stmts = append(stmts, stmt)
+		if ddlStmt, ok := stmt.(sqlparser.DDLStatement); ok {
+			for _, table := range ddlStmt.AffectedTables() {
+				tables = append(tables, table.Name.String())
+			}
+		}
+	}
+	for _, tablet := range exec.tablets {
+		plan := &ShardPlan{Shard: tablet.Shard}
+		currentSchema := map[string]string{}
+		if exec.isDeclarative() && len(tables) > 0 {
+			schemaDefinition, err := exec.tmc.GetSchema(ctx, tablet, &tabletmanagerdatapb.GetSchemaRequest{Tables: tables, IncludeViews: true, TableSchemaOnly: true})
+			if err != nil {
+				return nil, vterrors.Wrapf(err, "unable to get schema of shard %s", tablet.Shard)
+			}
+			for _, tableDefinition := range schemaDefinition.TableDefinitions {
+				currentSchema[tableDefinition.Name] = tableDefinition.Schema
+			}
+		}
+		for i, stmt := range stmts {
+			statements, destructiveChanges, err := exec.planStatement(sqls[i], stmt, currentSchema)
+			if err != nil {
+				return nil, vterrors.Wrapf(err, "shard %s", tablet.Shard)
+			}
+			plan.Statements = append(plan.Statements, statements...)
+			plan.DestructiveChanges = append(plan.DestructiveChanges, destructiveChanges...)
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// planStatement returns the statements which a single sql runs on a shard, of which currentSchema has
+// the CREATE statements of the affected tables and views.
+func (exec *TabletExecutor) planStatement(sql string, stmt sqlparser.Statement, currentSchema map[string]string) (statements []string, destructiveChanges []string, err error) {
+	if !exec.isDeclarative() {
+		return []string{sql}, nil, nil
+	}
+	if ddlStmt, ok := stmt.(sqlparser.DDLStatement); ok {
+		switch {
+		case ddlStmt.GetIfExists():
+			return nil, nil, vterrors.Errorf(vtrpc.Code_UNIMPLEMENTED, "IF EXISTS does not work in declarative mode: %s", sql)
+		case ddlStmt.GetIfNotExists():
+			return nil, nil, vterrors.Errorf(vtrpc.Code_UNIMPLEMENTED, "IF NOT EXISTS does not work in declarative mode: %s", sql)
+		case ddlStmt.GetIsReplace():
+			return nil, nil, vterrors.Errorf(vtrpc.Code_UNIMPLEMENTED, "OR REPLACE does not work in declarative mode: %s", sql)
+		}
+	}
+	// As in the tablet's Online DDL executor: a declarative CREATE turns into an ALTER (or into a CREATE
+	// OR REPLACE VIEW) when the table exists, and into nothing when it is unchanged. A declarative DROP
+	// does nothing when the table does not exist.
+	hints := &schemadiff.DiffHints{AutoIncrementStrategy: schemadiff.AutoIncrementApplyHigher}
+	switch stmt := stmt.(type) {
+	case *sqlparser.CreateTable:
+		current, exists := currentSchema[stmt.GetTable().Name.String()]
+		if !exists {
+			return []string{sqlparser.String(stmt)}, nil, nil
+		}
+		diff, err := schemadiff.DiffCreateTablesQueries(current, sqlparser.String(stmt), hints)
+		if err != nil {
+			return nil, nil, err
+		}
+		if diff == nil || diff.IsEmpty() {
+			return nil, nil, nil
+		}
+		return []string{diff.CanonicalStatementString()}, schema.DestructiveChanges(diff.Statement()), nil
+	case *sqlparser.CreateView:
+		current, exists := currentSchema[stmt.GetTable().Name.String()]
+		if !exists {
+			return []string{sqlparser.String(stmt)}, nil, nil
+		}
+		diff, err := schemadiff.DiffCreateViewsQueries(current, sqlparser.String(stmt), hints)
+		if err != nil {
+			return nil, nil, err
+		}
+		if diff == nil || diff.IsEmpty() {
+			return nil, nil, nil
+		}
+		createView := sqlparser.CloneRefOfCreateView(stmt)
+		createView.IsReplace = true
+		return []string{sqlparser.String(createView)}, nil, nil
+	case *sqlparser.DropTable, *sqlparser.DropView:
+		// The statement is shared by all shards
+		ddlStmt := sqlparser.CloneStatement(stmt).(sqlparser.DDLStatement)
+		var remaining sqlparser.TableNames
+		for _, table := range ddlStmt.GetFromTables() {
+			if _, exists := currentSchema[table.Name.String()]; exists {
+				remaining = append(remaining, table)
+			}
+		}
+		if len(remaining) == 0 {
+			return nil, nil, nil
+		}
+		ddlStmt.SetFromTables(remaining)
+		return []string{sqlparser.String(ddlStmt)}, schema.DestructiveChanges(ddlStmt), nil
+	case *sqlparser.AlterTable:
+		return nil, nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "ALTER cannot run in declarative mode: %s", sql)
+	}
+	return []string{sql}, nil, nil
+}
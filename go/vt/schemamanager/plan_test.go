@@ -0,0 +1,107 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemamanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+func TestTabletExecutorPlan(t *testing.T) {
+	ctx := context.Background()
+	tmc := newFakeTabletManagerClient()
+	tmc.AddSchemaDefinition("vt_test_keyspace", &tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+			{Name: "t1", Schema: "create table t1 (id int primary key, i int, j int)"},
+			{Name: "t2", Schema: "create table t2 (id int primary key)"},
+			{Name: "t3", Schema: "create table t3 (id int primary key)"},
+		},
+	})
+	sqls := []string{
+		"create table t1 (id int primary key, i int, k int)",
+		"create table t2 (id int primary key)",
+		"create table t4 (id int primary key)",
+		"drop table t3",
+		"drop table t5",
+	}
+	tt := []struct {
+		ddlStrategy        string
+		sqls               []string
+		statements         []string
+		destructiveChanges []string
+		expectError        string
+	}{
+		{
+			ddlStrategy: "direct",
+			sqls:        sqls,
+			statements:  sqls,
+		},
+		{
+			ddlStrategy: "vitess",
+			sqls:        sqls,
+			statements:  sqls,
+		},
+		{
+			ddlStrategy: "vitess --declarative",
+			sqls:        sqls,
+			statements: []string{
+				"ALTER TABLE `t1` DROP COLUMN `j`, ADD COLUMN `k` int",
+				"create table t4 (\n\tid int primary key\n)",
+				"drop table t3",
+			},
+			destructiveChanges: []string{"column t1.j dropped", "table t3 dropped"},
+		},
+		{
+			ddlStrategy: "vitess --declarative",
+			sqls:        []string{"alter table t1 add column l int"},
+			expectError: "ALTER cannot run in declarative mode",
+		},
+		{
+			ddlStrategy: "vitess --declarative",
+			sqls:        []string{"drop table if exists t1"},
+			expectError: "IF EXISTS does not work in declarative mode",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.ddlStrategy, func(t *testing.T) {
+			executor := NewTabletExecutor("TestTabletExecutorPlan", newFakeTopo(t), tmc, logutil.NewConsoleLogger(), testWaitReplicasTimeout, 0)
+			require.NoError(t, executor.SetDDLStrategy(tc.ddlStrategy))
+			require.NoError(t, executor.Open(ctx, "test_keyspace"))
+			defer executor.Close()
+
+			plans, err := executor.Plan(ctx, tc.sqls)
+			if tc.expectError != "" {
+				assert.ErrorContains(t, err, tc.expectError)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, plans, 3)
+			for i, shard := range []string{"0", "1", "2"} {
+				assert.Equal(t, shard, plans[i].Shard)
+				assert.Equal(t, tc.statements, plans[i].Statements)
+				assert.Equal(t, tc.destructiveChanges, plans[i].DestructiveChanges)
+			}
+		})
+	}
+}
@@ -53,6 +53,7 @@ type TabletExecutor struct {
 	ddlStrategySetting  *schema.DDLStrategySetting
 	uuids               []string
 	batchSize           int64
+	changelogIDs        []string
 }
 
 // NewTabletExecutor creates a new TabletExecutor instance
@@ -194,9 +195,9 @@ func (exec *TabletExecutor) isOnlineSchemaDDL(stmt sqlparser.Statement) (isOnlin
 
 // executeSQL executes a single SQL statement either as online DDL or synchronously on all tablets.
 // In online DDL case, the query may be exploded into multiple queries during
-func (exec *TabletExecutor) executeSQL(ctx context.Context, sql string, providedUUID string, execResult *ExecuteResult) (executedAsynchronously bool, err error) {
+func (exec *TabletExecutor) executeSQL(ctx context.Context, sql string, providedUUID string, tablets []*topodatapb.Tablet, execResult *ExecuteResult) (executedAsynchronously bool, err error) {
 	executeViaFetch := func() (bool, error) {
-		exec.executeOnAllTablets(ctx, execResult, sql, false)
+		exec.executeOnAllTablets(ctx, execResult, tablets, sql, false)
 		return false, nil
 	}
 	if exec.batchSize > 1 {
@@ -217,7 +218,7 @@ func (exec *TabletExecutor) executeSQL(ctx context.Context, sql string, provided
 				return false, err
 			}
 			for _, onlineDDL := range onlineDDLs {
-				exec.executeOnAllTablets(ctx, execResult, onlineDDL.SQL, true)
+				exec.executeOnAllTablets(ctx, execResult, tablets, onlineDDL.SQL, true)
 				if len(execResult.SuccessShards) > 0 {
 					execResult.UUIDs = append(execResult.UUIDs, onlineDDL.UUID)
 					exec.logger.Printf("%s\n", onlineDDL.UUID)
@@ -232,12 +233,12 @@ func (exec *TabletExecutor) executeSQL(ctx context.Context, sql string, provided
 			execResult.ExecutorErr = err.Error()
 			return false, err
 		}
-		exec.executeOnAllTablets(ctx, execResult, onlineDDL.SQL, true)
+		exec.executeOnAllTablets(ctx, execResult, tablets, onlineDDL.SQL, true)
 		execResult.UUIDs = append(execResult.UUIDs, onlineDDL.UUID)
 		exec.logger.Printf("%s\n", onlineDDL.UUID)
 		return true, nil
 	case *sqlparser.AlterMigration:
-		exec.executeOnAllTablets(ctx, execResult, sql, true)
+		exec.executeOnAllTablets(ctx, execResult, tablets, sql, true)
 		return true, nil
 	}
 	// Got here? The statement needs to be executed directly.
@@ -262,6 +263,52 @@ func batchSQLs(sqls []string, batchSize int) (batchedSQLs []string) {
 	return batchedSQLs
 }
 
+// mergeAlterTableSQLs merges the ALTER TABLE statements on a same table into the first of them, so that
+// the migration of the table copies it only once. The other statements keep their order. A table that is
+// both altered and otherwise changed by the statements cannot be merged.
+func mergeAlterTableSQLs(sqls []string) (mergedSQLs []string, err error) {
+	alterTables := map[string]*sqlparser.AlterTable{}
+	alterTableIndexes := map[string]int{}
+	otherTables := map[string]bool{}
+	for _, sql := range sqls {
+		stmt, err := sqlparser.Parse(sql)
+		if err != nil {
+			return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "failed to parse sql: %s, got error: %v", sql, err)
+		}
+		alterTable, ok := stmt.(*sqlparser.AlterTable)
+		if !ok {
+			if ddlStmt, ok := stmt.(sqlparser.DDLStatement); ok {
+				for _, table := range ddlStmt.AffectedTables() {
+					otherTables[table.Name.String()] = true
+				}
+			}
+			mergedSQLs = append(mergedSQLs, sql)
+			continue
+		}
+		table := alterTable.Table.Name.String()
+		mergedAlterTable, ok := alterTables[table]
+		if !ok {
+			alterTables[table] = alterTable
+			alterTableIndexes[table] = len(mergedSQLs)
+			mergedSQLs = append(mergedSQLs, sql)
+			continue
+		}
+		for _, mergeable := range []*sqlparser.AlterTable{mergedAlterTable, alterTable} {
+			if !mergeable.FullyParsed || mergeable.PartitionSpec != nil || mergeable.PartitionOption != nil {
+				return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "cannot merge the ALTER TABLE statements on table %s: %s", table, sqlparser.String(mergeable))
+			}
+		}
+		mergedAlterTable.AlterOptions = append(mergedAlterTable.AlterOptions, alterTable.AlterOptions...)
+		mergedSQLs[alterTableIndexes[table]] = sqlparser.String(mergedAlterTable)
+	}
+	for table := range alterTables {
+		if otherTables[table] {
+			return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "cannot merge the ALTER TABLE statements on table %s: the table is changed by other statements", table)
+		}
+	}
+	return mergedSQLs, nil
+}
+
 // allSQLsAreCreateQueries returns 'true' when all given queries are CREATE TABLE|VIEW
 // This function runs pretty fast even for thousands of tables (its overhead is insignificant compared with
 // the time it would take to apply the changes).
@@ -292,6 +339,20 @@ func (exec *TabletExecutor) Execute(ctx context.Context, sqls []string) *Execute
 		}
 		return &execResult
 	}
+	if !exec.isDirectStrategy() && exec.ddlStrategySetting.IsCoordinatedCutOverFlag() {
+		// The migrations of a --coordinated-cut-over batch alter each table once.
+		mergedSQLs, err := mergeAlterTableSQLs(sqls)
+		if err != nil {
+			return errorExecResult(err)
+		}
+		if exec.hasProvidedUUIDs() && len(mergedSQLs) != len(sqls) {
+			return errorExecResult(fmt.Errorf("--uuid_list cannot be used when ALTER TABLE statements on a same table are merged by --coordinated-cut-over"))
+		}
+		if exec.hasChangelog() && len(mergedSQLs) != len(sqls) {
+			return errorExecResult(fmt.Errorf("a changelog cannot be used when ALTER TABLE statements on a same table are merged by --coordinated-cut-over"))
+		}
+		sqls = mergedSQLs
+	}
 	execResult.Sqls = sqls
 	if exec.isClosed {
 		return errorExecResult(fmt.Errorf("executor is closed"))
@@ -318,6 +379,9 @@ func (exec *TabletExecutor) Execute(ctx context.Context, sqls []string) *Execute
 	if exec.hasProvidedUUIDs() && len(exec.uuids) != len(sqls) {
 		return errorExecResult(fmt.Errorf("provided %v UUIDs do not match number of DDLs %v", len(exec.uuids), len(sqls)))
 	}
+	if exec.hasChangelog() && len(exec.changelogIDs) != len(sqls) {
+		return errorExecResult(fmt.Errorf("provided %v changelog IDs do not match number of DDLs %v", len(exec.changelogIDs), len(sqls)))
+	}
 	providedUUID := ""
 
 	rl := timer.NewRateLimiter(topo.RemoteOperationTimeout / 4)
@@ -377,6 +441,9 @@ func (exec *TabletExecutor) Execute(ctx context.Context, sqls []string) *Execute
 		if exec.hasProvidedUUIDs() {
 			return errorExecResult(fmt.Errorf("--batch-size conflicts with --uuid-list. Batching does not support UUIDs."))
 		}
+		if exec.hasChangelog() {
+			return errorExecResult(fmt.Errorf("--batch-size conflicts with a changelog. Batching does not support changelog IDs."))
+		}
 		allSQLsAreCreate, err := allSQLsAreCreateQueries(sqls)
 		if err != nil {
 			return errorExecResult(err)
@@ -387,6 +454,17 @@ func (exec *TabletExecutor) Execute(ctx context.Context, sqls []string) *Execute
 
 		sqls = batchSQLs(sqls, int(exec.batchSize))
 	}
+	var sidecarDBName string
+	var appliedChangelogIDs map[string]map[string]bool
+	if exec.hasChangelog() {
+		var err error
+		if sidecarDBName, err = exec.ts.GetSidecarDBName(ctx, exec.keyspace); err != nil {
+			return errorExecResult(err)
+		}
+		if appliedChangelogIDs, err = exec.readAppliedChangelogIDs(ctx, sidecarDBName); err != nil {
+			return errorExecResult(err)
+		}
+	}
 	for index, sql := range sqls {
 		// Attempt to renew lease:
 		if err := rl.Do(func() error { return topo.CheckKeyspaceLockedAndRenew(ctx, exec.keyspace) }); err != nil {
@@ -396,10 +474,22 @@ func (exec *TabletExecutor) Execute(ctx context.Context, sqls []string) *Execute
 		if exec.hasProvidedUUIDs() {
 			providedUUID = exec.uuids[index]
 		}
-		executedAsynchronously, err := exec.executeSQL(ctx, sql, providedUUID, &execResult)
+		tablets := exec.tablets
+		if exec.hasChangelog() {
+			if tablets = exec.pendingChangelogTablets(appliedChangelogIDs, exec.changelogIDs[index]); len(tablets) == 0 {
+				exec.logger.Infof("Skipping changeset %s, which was already applied on all shards", exec.changelogIDs[index])
+				continue
+			}
+		}
+		executedAsynchronously, err := exec.executeSQL(ctx, sql, providedUUID, tablets, &execResult)
 		if err != nil {
 			return errorExecResult(err)
 		}
+		if exec.hasChangelog() {
+			if err := exec.recordChangelogEntry(ctx, sidecarDBName, tablets, execResult.SuccessShards, exec.changelogIDs[index], sql); err != nil {
+				return errorExecResult(err)
+			}
+		}
 		if !executedAsynchronously {
 			syncOperationExecuted = true
 		}
@@ -411,14 +501,14 @@ func (exec *TabletExecutor) Execute(ctx context.Context, sqls []string) *Execute
 	return &execResult
 }
 
-// executeOnAllTablets runs a query on all tablets, synchronously. This can be a long running operation.
-func (exec *TabletExecutor) executeOnAllTablets(ctx context.Context, execResult *ExecuteResult, sql string, viaQueryService bool) {
+// executeOnAllTablets runs a query on all given tablets, synchronously. This can be a long running operation.
+func (exec *TabletExecutor) executeOnAllTablets(ctx context.Context, execResult *ExecuteResult, tablets []*topodatapb.Tablet, sql string, viaQueryService bool) {
 	var wg sync.WaitGroup
-	numOfPrimaryTablets := len(exec.tablets)
+	numOfPrimaryTablets := len(tablets)
 	wg.Add(numOfPrimaryTablets)
 	errChan := make(chan ShardWithError, numOfPrimaryTablets)
 	successChan := make(chan ShardResult, numOfPrimaryTablets)
-	for _, tablet := range exec.tablets {
+	for _, tablet := range tablets {
 		go func(tablet *topodatapb.Tablet) {
 			defer wg.Done()
 			exec.executeOneTablet(ctx, tablet, sql, viaQueryService, errChan, successChan)
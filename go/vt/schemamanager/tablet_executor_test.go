@@ -408,3 +408,68 @@ func TestAllSQLsAreCreateQueries(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeAlterTableSQLs(t *testing.T) {
+	tcases := []struct {
+		name      string
+		sqls      []string
+		expect    []string
+		expectErr string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "distinct tables",
+			sqls: []string{
+				"alter table t1 add column i int",
+				"alter table t2 add column i int",
+			},
+			expect: []string{
+				"alter table t1 add column i int",
+				"alter table t2 add column i int",
+			},
+		},
+		{
+			name: "same table",
+			sqls: []string{
+				"alter table t1 add column i int",
+				"create table t3 (id int primary key)",
+				"alter table t2 add column i int",
+				"alter table t1 add key i_idx (i), engine=InnoDB",
+			},
+			expect: []string{
+				"alter table t1 add column i int, add key i_idx (i), engine InnoDB",
+				"create table t3 (id int primary key)",
+				"alter table t2 add column i int",
+			},
+		},
+		{
+			name: "altered and dropped",
+			sqls: []string{
+				"alter table t1 add column i int",
+				"drop table t1",
+			},
+			expectErr: "cannot merge the ALTER TABLE statements on table t1: the table is changed by other statements",
+		},
+		{
+			name: "partitions",
+			sqls: []string{
+				"alter table t1 add column i int",
+				"alter table t1 drop partition p1",
+			},
+			expectErr: "cannot merge the ALTER TABLE statements on table t1: alter table t1 drop partition p1",
+		},
+	}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			mergedSQLs, err := mergeAlterTableSQLs(tcase.sqls)
+			if tcase.expectErr != "" {
+				assert.EqualError(t, err, tcase.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tcase.expect, mergedSQLs)
+		})
+	}
+}
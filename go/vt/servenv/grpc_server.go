@@ -258,9 +258,27 @@ func interceptors() []grpc.ServerOption {
 
 	trace.AddGrpcServerOptions(interceptors.Add)
 
+	for i := range extraStreamInterceptors {
+		interceptors.Add(extraStreamInterceptors[i], extraUnaryInterceptors[i])
+	}
+
 	return interceptors.Build()
 }
 
+var (
+	extraStreamInterceptors []grpc.StreamServerInterceptor
+	extraUnaryInterceptors  []grpc.UnaryServerInterceptor
+)
+
+// AddGRPCServerInterceptors registers a pair of interceptors to install on the
+// gRPC server, after the built-in ones (so, for example, they see the context
+// returned by the auth plugin). It must be called before the gRPC server is
+// created, that is, before servenv.Run.
+func AddGRPCServerInterceptors(s grpc.StreamServerInterceptor, u grpc.UnaryServerInterceptor) {
+	extraStreamInterceptors = append(extraStreamInterceptors, s)
+	extraUnaryInterceptors = append(extraUnaryInterceptors, u)
+}
+
 func serveGRPC() {
 	if grpccommon.EnableGRPCPrometheus() {
 		grpc_prometheus.Register(GRPCServer)
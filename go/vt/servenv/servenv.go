@@ -429,6 +429,7 @@ func init() {
 
 	// These are the binaries that make gRPC calls.
 	for _, cmd := range []string{
+		"vstream2kafka",
 		"vtbackup",
 		"vtcombo",
 		"vtctl",
@@ -70,6 +70,9 @@ CREATE TABLE IF NOT EXISTS schema_migrations
     `is_immediate_operation`          tinyint unsigned NOT NULL DEFAULT '0',
     `reviewed_timestamp`              timestamp        NULL DEFAULT NULL,
     `ready_to_complete_timestamp`     timestamp        NULL DEFAULT NULL,
+    `rows_copied_per_second`          float            NOT NULL DEFAULT '0',
+    `vreplication_lag_seconds`        bigint           NOT NULL DEFAULT '0',
+    `depends_on`                      varchar(1024)    NOT NULL DEFAULT '',
     PRIMARY KEY (`id`),
     UNIQUE KEY `uuid_idx` (`migration_uuid`),
     KEY `keyspace_shard_idx` (`keyspace`(64), `shard`(64)),
@@ -0,0 +1,25 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+CREATE TABLE IF NOT EXISTS schema_changelog
+(
+    `keyspace`          varchar(256)  NOT NULL,
+    `changelog_id`      varchar(256)  NOT NULL,
+    `changelog_sql`     text          NOT NULL,
+    `applied_timestamp` timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (`keyspace`, `changelog_id`)
+) ENGINE = InnoDB
@@ -2516,3 +2516,8 @@ func (v *visitor) visitAllSelects(in SelectStatement, f func(p *Select, idx int)
 	}
 	panic("switch should be exhaustive")
 }
+
+// CTEs returns the common table expressions of the WITH clause
+func (node *With) CTEs() []*CommonTableExpr {
+	return node.ctes
+}
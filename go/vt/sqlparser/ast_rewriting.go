@@ -549,6 +549,10 @@ func (er *astRewriter) sysVarRewrite(cursor *Cursor, node *Variable) {
 		sysvars.Version.Name,
 		sysvars.VersionComment.Name,
 		sysvars.QueryTimeout.Name,
+		sysvars.ScatterConcurrency.Name,
+		sysvars.ShardTimeout.Name,
+		sysvars.AllowPartialScatter.Name,
+		sysvars.SkipReadRetry.Name,
 		sysvars.Workload.Name:
 		found = true
 	}
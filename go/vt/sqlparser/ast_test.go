@@ -730,6 +730,18 @@ func TestSplitStatementToPieces(t *testing.T) {
 	}, {
 		input:  "select * from table1;--comment;\nselect * from table2;",
 		output: "select * from table1;--comment;\nselect * from table2",
+	}, {
+		input:  "select * from table1; -- trailing comment",
+		output: "select * from table1",
+	}, {
+		input:  "select * from table1; /* trailing comment */\n",
+		output: "select * from table1",
+	}, {
+		input:  "/* comment */; select * from table1;\n# comment\nselect * from table2;",
+		output: " select * from table1;\n# comment\nselect * from table2",
+	}, {
+		input:  "/*!40101 SET NAMES utf8 */; select * from table1",
+		output: "/*!40101 SET NAMES utf8 */; select * from table1",
 	}, {
 		input: "CREATE TABLE `total_data` (`id` int(11) NOT NULL AUTO_INCREMENT COMMENT 'id', " +
 			"`region` varchar(32) NOT NULL COMMENT 'region name, like zh; th; kepler'," +
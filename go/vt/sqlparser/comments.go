@@ -37,6 +37,12 @@ const (
 	DirectiveQueryTimeout = "QUERY_TIMEOUT_MS"
 	// DirectiveScatterErrorsAsWarnings enables partial success scatter select queries
 	DirectiveScatterErrorsAsWarnings = "SCATTER_ERRORS_AS_WARNINGS"
+	// DirectiveAllowPartialScatter returns the results of the shards that succeeded when some shards of a scatter select query fail.
+	DirectiveAllowPartialScatter = "ALLOW_PARTIAL_SCATTER"
+	// DirectiveScatterConcurrency limits the number of shards a scatter select query is sent to at the same time.
+	DirectiveScatterConcurrency = "SCATTER_CONCURRENCY"
+	// DirectiveShardTimeout sets a timeout in vtgate for the query sent to each shard. Only supported for SELECTS.
+	DirectiveShardTimeout = "SHARD_TIMEOUT_MS"
 	// DirectiveIgnoreMaxPayloadSize skips payload size validation when set.
 	DirectiveIgnoreMaxPayloadSize = "IGNORE_MAX_PAYLOAD_SIZE"
 	// DirectiveIgnoreMaxMemoryRows skips memory row validation when set.
@@ -56,6 +62,10 @@ const (
 	// DirectivePriority specifies the priority of a workload. It should be an integer between 0 and MaxPriorityValue,
 	// where 0 is the highest priority, and MaxPriorityValue is the lowest one.
 	DirectivePriority = "PRIORITY"
+	// DirectiveDDLStrategy sets the DDL strategy of a DDL statement, overriding @@ddl_strategy.
+	DirectiveDDLStrategy = "DDL_STRATEGY"
+	// DirectiveQueryPool selects the named vttablet query pool running a non-transactional query.
+	DirectiveQueryPool = "QUERY_POOL"
 
 	// MaxPriorityValue specifies the maximum value allowed for the priority query directive. Valid priority values are
 	// between zero and MaxPriorityValue.
@@ -236,7 +246,7 @@ func (c *ParsedComments) ResetDirectives() {
 // Directives parses the comment list for any execution directives
 // of the form:
 //
-//	/*vt+ OPTION_ONE=1 OPTION_TWO OPTION_THREE=abcd */
+//	/*vt+ OPTION_ONE=1 OPTION_TWO OPTION_THREE=abcd OPTION_FOUR='a b' */
 //
 // It returns the map of the directive values or nil if there aren't any.
 func (c *ParsedComments) Directives() *CommentDirectives {
@@ -253,7 +263,7 @@ func (c *ParsedComments) Directives() *CommentDirectives {
 
 			// Split on whitespace and ignore the first and last directive
 			// since they contain the comment start/end
-			directives := strings.Fields(commentStr)
+			directives := directiveFields(commentStr)
 			for i := 1; i < len(directives)-1; i++ {
 				directive, val, ok := strings.Cut(directives[i], "=")
 				if !ok {
@@ -266,6 +276,37 @@ func (c *ParsedComments) Directives() *CommentDirectives {
 	return c._directives
 }
 
+// directiveFields splits the comment on whitespace, except for the whitespace
+// of the quoted values.
+func directiveFields(commentStr string) []string {
+	var fields []string
+	var quote rune
+	start := -1
+	for i, r := range commentStr {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case unicode.IsSpace(r):
+			if start >= 0 {
+				fields = append(fields, commentStr[start:i])
+				start = -1
+			}
+			continue
+		case r == '\'' || r == '"':
+			quote = r
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		fields = append(fields, commentStr[start:])
+	}
+	return fields
+}
+
 func (c *ParsedComments) Length() int {
 	if c == nil {
 		return 0
@@ -310,6 +351,9 @@ func (d *CommentDirectives) GetString(key string, defaultVal string) (string, bo
 	if unquoted, err := strconv.Unquote(val); err == nil {
 		return unquoted, true
 	}
+	if len(val) >= 2 && val[0] == '\'' && val[len(val)-1] == '\'' {
+		return val[1 : len(val)-1], true
+	}
 	return val, true
 }
 
@@ -458,3 +502,17 @@ func GetWorkloadNameFromStatement(statement Statement) string {
 
 	return workloadName
 }
+
+// GetQueryPoolFromStatement gets the name of the vttablet query pool from the provided Statement,
+// using DirectiveQueryPool. It returns an empty string if the statement does not select a pool.
+func GetQueryPoolFromStatement(statement Statement) string {
+	commentedStatement, ok := statement.(Commented)
+	if !ok {
+		return ""
+	}
+
+	directives := commentedStatement.GetParsedComments().Directives()
+	queryPool, _ := directives.GetString(DirectiveQueryPool, "")
+
+	return queryPool
+}
@@ -311,6 +311,13 @@ func TestExtractCommentDirectives(t *testing.T) {
 			"one_opt": "true",
 			"two_opt": "\"false\"",
 		},
+	}, {
+		input: "/*vt+ ONE_OPT='a b' TWO_OPT=\"c  d\" THREE_OPT */",
+		vals: map[string]string{
+			"one_opt":   "'a b'",
+			"two_opt":   "\"c  d\"",
+			"three_opt": "true",
+		},
 	}, {
 		input: "/*vt+ RANGE_OPT=[a:b] ANOTHER ANOTHER_WITH_VALEQ=val= AND_ONE_WITH_EQ== */",
 		vals: map[string]string{
@@ -390,6 +397,21 @@ func TestExtractCommentDirectives(t *testing.T) {
 	assert.False(t, d.IsSet("four"), "d.IsSet(four)")
 	assert.False(t, d.IsSet("five"), "d.IsSet(five)")
 	assert.True(t, d.IsSet("six"), "d.IsSet(six)")
+
+	d = &CommentDirectives{m: map[string]string{
+		"one_opt":   "abc",
+		"two_opt":   "\"a b\"",
+		"three_opt": "'a b'",
+	}}
+
+	for key, want := range map[string]string{"ONE_OPT": "abc", "TWO_OPT": "a b", "THREE_OPT": "a b"} {
+		got, ok := d.GetString(key, "")
+		assert.True(t, ok, key)
+		assert.Equal(t, want, got, key)
+	}
+	got, ok := d.GetString("FOUR_OPT", "default")
+	assert.False(t, ok)
+	assert.Equal(t, "default", got)
 }
 
 func TestSkipQueryPlanCacheDirective(t *testing.T) {
@@ -561,3 +583,24 @@ func TestGetPriorityFromStatement(t *testing.T) {
 		})
 	}
 }
+
+func TestGetQueryPoolFromStatement(t *testing.T) {
+	testCases := []struct {
+		query    string
+		expected string
+	}{
+		{"select * from users", ""},
+		{"select /*vt+ WORKLOAD_NAME=app */ * from users", ""},
+		{"select /*vt+ QUERY_POOL=olap */ * from users", "olap"},
+		{"update /*vt+ QUERY_POOL=admin */ users set name = 1", "admin"},
+		{"set @a = 1", ""},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.query, func(t *testing.T) {
+			stmt, err := Parse(test.query)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, GetQueryPoolFromStatement(stmt))
+		})
+	}
+}
@@ -264,7 +264,8 @@ func SplitStatement(blob string) (string, string, error) {
 }
 
 // SplitStatementToPieces split raw sql statement that may have multi sql pieces to sql pieces
-// returns the sql pieces blob contains; or error if sql cannot be parsed
+// returns the sql pieces blob contains; or error if sql cannot be parsed.
+// The empty pieces, and the pieces with only comments, are not returned.
 func SplitStatementToPieces(blob string) (pieces []string, err error) {
 	// fast path: the vast majority of SQL statements do not have semicolons in them
 	if blob == "" {
@@ -304,6 +305,9 @@ loop:
 				}
 			}
 			break loop
+		case COMMENT:
+			// A piece with only comments, like the comments after the last statement
+			// of a migration file, is empty: it is skipped instead of being executed.
 		default:
 			emptyStatement = false
 		}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"sort"
 
+	"vitess.io/vitess/go/sqltypes"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 )
 
@@ -67,6 +68,30 @@ func QueryMatchesTemplates(query string, queryTemplates []string) (match bool, e
 	return false, nil
 }
 
+// Fingerprint returns the fingerprint of the statement: its canonical form without comments, where
+// all the values are replaced by placeholders. The statements that only differ by their values, or
+// by whether their values were already normalized into bind variables, have the same fingerprint.
+// The statement is not modified.
+func Fingerprint(stmt Statement) string {
+	stmt = CloneStatement(stmt)
+	_ = Normalize(stmt, NewReservedVars("vtg", GetBindvars(stmt)), make(map[string]*querypb.BindVariable))
+	stmt = SafeRewrite(stmt, nil, func(cursor *Cursor) bool {
+		switch node := cursor.Node().(type) {
+		case *ParsedComments:
+			if node != nil {
+				cursor.Replace((*ParsedComments)(nil))
+			}
+		case *Argument:
+			node.Name = "?"
+			node.Type = sqltypes.Unknown
+		case ListArg:
+			cursor.Replace(ListArg("?"))
+		}
+		return true
+	}).(Statement)
+	return CanonicalString(stmt)
+}
+
 // NormalizeAlphabetically rewrites given query such that:
 // - WHERE 'AND' expressions are reordered alphabetically
 func NormalizeAlphabetically(query string) (normalized string, err error) {
@@ -182,6 +182,27 @@ func TestQueryMatchesTemplates(t *testing.T) {
 	}
 }
 
+func TestFingerprint(t *testing.T) {
+	fingerprint := func(sql string) string {
+		stmt, err := Parse(sql)
+		require.NoError(t, err)
+		return Fingerprint(stmt)
+	}
+
+	want := fingerprint("select a from t where id = 1 and name in ('x', 'y') limit 10")
+	assert.Equal(t, "SELECT `a` FROM `t` WHERE `id` = :? AND `name` IN ::? LIMIT :?", want)
+	assert.Equal(t, want, fingerprint("select /* app:foo */ a from t where id = 42 and name in ('z') limit 5"))
+	assert.Equal(t, want, fingerprint("select a from t where id = :id and name in ::names limit :limit"))
+	assert.NotEqual(t, want, fingerprint("select a from t where id = 1 and name in ('x', 'y')"))
+	assert.NotEqual(t, want, fingerprint("select b from t where id = 1 and name in ('x', 'y') limit 10"))
+
+	// The statement is not modified.
+	stmt, err := Parse("select a from t where id = 1")
+	require.NoError(t, err)
+	Fingerprint(stmt)
+	assert.Equal(t, "select a from t where id = 1", String(stmt))
+}
+
 func TestReplaceTableQualifiers(t *testing.T) {
 	origDB := "_vt"
 	tests := []struct {
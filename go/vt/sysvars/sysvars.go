@@ -71,6 +71,10 @@ var (
 	TxReadOnly                  = SystemVariable{Name: "tx_read_only", IsBoolean: true, Default: off}
 	Workload                    = SystemVariable{Name: "workload", IdentifierAsString: true}
 	QueryTimeout                = SystemVariable{Name: "query_timeout"}
+	ScatterConcurrency          = SystemVariable{Name: "scatter_concurrency"}
+	ShardTimeout                = SystemVariable{Name: "shard_timeout"}
+	AllowPartialScatter         = SystemVariable{Name: "allow_partial_scatter", IsBoolean: true, Default: off}
+	SkipReadRetry               = SystemVariable{Name: "skip_read_retry", IsBoolean: true, Default: off}
 
 	// Online DDL
 	DDLStrategy      = SystemVariable{Name: "ddl_strategy", IdentifierAsString: true}
@@ -104,6 +108,10 @@ var (
 		ReadAfterWriteTimeOut,
 		SessionTrackGTIDs,
 		QueryTimeout,
+		ScatterConcurrency,
+		ShardTimeout,
+		AllowPartialScatter,
+		SkipReadRetry,
 	}
 
 	ReadOnly = []SystemVariable{
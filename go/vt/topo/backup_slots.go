@@ -0,0 +1,260 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/trace"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// This file contains the methods coordinating backups across shards, to limit
+// the number of backups running at the same time in a cell, and to stagger
+// their start times.
+
+var (
+	// BackupSlotLease is how long the slot of a backup is held without being
+	// renewed. The slot of a backup that crashed is freed after this long.
+	BackupSlotLease = 1 * time.Minute
+
+	// BackupSlotPollInterval is how often a backup waiting for a free slot
+	// checks again.
+	BackupSlotPollInterval = 10 * time.Second
+)
+
+// BackupSlot describes a backup holding a slot in a cell.
+// It needs to be public as we JSON-serialize it.
+type BackupSlot struct {
+	Keyspace string
+	Shard    string
+	HostName string
+	Started  time.Time
+	Expires  time.Time
+}
+
+// backupSlots is the record of the slots of a cell.
+type backupSlots struct {
+	// LastStarted is when the last backup of the cell took its slot.
+	LastStarted time.Time
+	// Slots are the slots held by running backups, by slot id.
+	Slots map[string]*BackupSlot
+}
+
+// GetBackupSlots returns the slots held by the backups running in a cell, by
+// slot id. Expired slots are not returned.
+func (ts *Server) GetBackupSlots(ctx context.Context, cell string) (map[string]*BackupSlot, error) {
+	conn, err := ts.ConnForCell(ctx, cell)
+	if err != nil {
+		return nil, err
+	}
+
+	slots, err := readBackupSlots(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	slots.prune(time.Now())
+	return slots.Slots, nil
+}
+
+// AcquireBackupSlot blocks until a backup of the shard can start in the cell,
+// which is when fewer than maxConcurrent backups run in the cell (0 means no
+// limit) and at least stagger elapsed since the last one started. It then
+// takes a slot, renewed in the background until the returned release function
+// is called.
+func (ts *Server) AcquireBackupSlot(ctx context.Context, cell, keyspace, shard string, maxConcurrent int, stagger time.Duration) (release func(), err error) {
+	span, ctx := trace.NewSpan(ctx, "TopoServer.AcquireBackupSlot")
+	span.Annotate("cell", cell)
+	span.Annotate("keyspace", keyspace)
+	span.Annotate("shard", shard)
+	defer span.Finish()
+
+	conn, err := ts.ConnForCell(ctx, cell)
+	if err != nil {
+		return nil, err
+	}
+
+	slot := &BackupSlot{
+		Keyspace: keyspace,
+		Shard:    shard,
+		HostName: "unknown",
+	}
+	if h, err := os.Hostname(); err == nil {
+		slot.HostName = h
+	}
+	id := fmt.Sprintf("%v-%v-%v-%d", keyspace, shard, slot.HostName, rand.Uint32())
+
+	for {
+		var wait time.Duration
+		err := updateBackupSlots(ctx, conn, func(slots *backupSlots) {
+			now := time.Now()
+			slots.prune(now)
+			if maxConcurrent > 0 && len(slots.Slots) >= maxConcurrent {
+				wait = BackupSlotPollInterval
+				return
+			}
+			if next := slots.LastStarted.Add(stagger); now.Before(next) {
+				wait = next.Sub(now)
+				return
+			}
+
+			slot.Started = now
+			slot.Expires = now.Add(BackupSlotLease)
+			slots.Slots[id] = slot
+			slots.LastStarted = now
+		})
+		if err != nil {
+			return nil, err
+		}
+		if wait == 0 {
+			break
+		}
+
+		log.Infof("Waiting %v for a backup slot in cell %v", wait, cell)
+		select {
+		case <-ctx.Done():
+			return nil, vterrors.Wrapf(ctx.Err(), "gave up waiting for a backup slot in cell %v", cell)
+		case <-time.After(wait):
+		}
+	}
+	log.Infof("Acquired backup slot %v in cell %v", id, cell)
+
+	// Renew the slot until it is released.
+	renewCtx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(BackupSlotLease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-renewCtx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			err := updateBackupSlots(renewCtx, conn, func(slots *backupSlots) {
+				// The slot is added back if it expired in the meantime.
+				slot.Expires = time.Now().Add(BackupSlotLease)
+				slots.Slots[id] = slot
+			})
+			if err != nil && renewCtx.Err() == nil {
+				log.Warningf("Failed to renew backup slot %v in cell %v: %v", id, cell, err)
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		wg.Wait()
+
+		ctx, cancel := context.WithTimeout(context.Background(), RemoteOperationTimeout)
+		defer cancel()
+		err := updateBackupSlots(ctx, conn, func(slots *backupSlots) {
+			delete(slots.Slots, id)
+		})
+		if err != nil {
+			log.Warningf("Failed to release backup slot %v in cell %v, it will expire in %v: %v", id, cell, BackupSlotLease, err)
+			return
+		}
+		log.Infof("Released backup slot %v in cell %v", id, cell)
+	}, nil
+}
+
+// prune removes the expired slots, which were held by backups that did not
+// release them.
+func (slots *backupSlots) prune(now time.Time) {
+	for id, slot := range slots.Slots {
+		if now.After(slot.Expires) {
+			log.Warningf("Backup slot %v of %v/%v on %v expired", id, slot.Keyspace, slot.Shard, slot.HostName)
+			delete(slots.Slots, id)
+		}
+	}
+}
+
+func backupSlotsFilePath() string {
+	return path.Join(BackupSlotsPath, BackupSlotsFile)
+}
+
+func readBackupSlots(ctx context.Context, conn Conn) (*backupSlots, error) {
+	slots := &backupSlots{}
+	data, _, err := conn.Get(ctx, backupSlotsFilePath())
+	switch {
+	case IsErrType(err, NoNode):
+	case err != nil:
+		return nil, err
+	case len(data) > 0:
+		if err := json.Unmarshal(data, slots); err != nil {
+			return nil, vterrors.Wrapf(err, "bad backup slots data")
+		}
+	}
+	if slots.Slots == nil {
+		slots.Slots = make(map[string]*BackupSlot)
+	}
+	return slots, nil
+}
+
+// updateBackupSlots applies update to the slots of the cell while holding the
+// lock of the backup slots directory.
+func updateBackupSlots(ctx context.Context, conn Conn, update func(slots *backupSlots)) (err error) {
+	// The directory has to exist to be locked.
+	if _, err := conn.Create(ctx, backupSlotsFilePath(), nil); err != nil && !IsErrType(err, NodeExists) {
+		return err
+	}
+
+	j, err := newLock("UpdateBackupSlots").ToJSON()
+	if err != nil {
+		return err
+	}
+	lockCtx, cancel := context.WithTimeout(ctx, getLockTimeout())
+	defer cancel()
+	lockDescriptor, err := conn.Lock(lockCtx, BackupSlotsPath, j)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// Detach from the parent context, the lock has to be released even
+		// if it is done.
+		ctx, cancel := context.WithTimeout(context.Background(), RemoteOperationTimeout)
+		defer cancel()
+		if unlockErr := lockDescriptor.Unlock(ctx); unlockErr != nil && err == nil {
+			err = unlockErr
+		}
+	}()
+
+	slots, err := readBackupSlots(ctx, conn)
+	if err != nil {
+		return err
+	}
+	update(slots)
+	data, err := json.Marshal(slots)
+	if err != nil {
+		return err
+	}
+	_, err = conn.Update(ctx, backupSlotsFilePath(), data, nil)
+	return err
+}
@@ -0,0 +1,374 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+)
+
+var _ Conn = (*CachingConn)(nil)
+
+var (
+	// readCacheMaxStaleness enables the read cache of the topo
+	// connections, when it is not zero.
+	readCacheMaxStaleness time.Duration
+
+	// CachingConnRefreshInterval is the minimum time between two attempts
+	// to refresh a stale entry in the background.
+	CachingConnRefreshInterval = 1 * time.Second
+
+	topoReadCacheReads = stats.NewCountersWithMultiLabels(
+		"TopologyReadCacheReads",
+		"Reads of the topo read cache, by result: hit for fresh data, stale for data served while the topo server is unreachable, and miss for reads of the topo server",
+		[]string{"Operation", "Cell", "Result"})
+)
+
+func init() {
+	for _, cmd := range []string{"vtgate", "vttablet"} {
+		servenv.OnParseFor(cmd, registerReadCacheFlags)
+	}
+}
+
+func registerReadCacheFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(&readCacheMaxStaleness, "topo_read_cache_max_staleness", readCacheMaxStaleness, "If set, the topo files are read from a local cache kept up to date by watches, and directory listings are cached too. When the topo server is unreachable, the cached data is still served for this long.")
+}
+
+// CachingConn is a wrapper for a Conn that serves reads from a local cache.
+//
+// The files read with Get are cached, and kept up to date by watching
+// them. If a watch fails, because the topo server is unreachable for
+// instance, the file is still served from the cache for up to maxStaleness,
+// while the watch is restarted in the background.
+//
+// The results of ListDir and List are only served from the cache if the
+// topo server is unreachable, for up to maxStaleness after they were read.
+//
+// Writes go to the topo server, and remove the file from the cache, so it
+// is read again by the next Get. All the other methods go to the topo
+// server too.
+type CachingConn struct {
+	Conn
+
+	cell         string
+	maxStaleness time.Duration
+
+	// ctx is canceled when the connection is closed, to stop the
+	// watches.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	files    map[string]*cachedFile
+	listings map[string]*cachedListing
+}
+
+// cachedFile is a file in the cache.
+type cachedFile struct {
+	// contents and version are the last known data of the file. version
+	// is nil until the file is read.
+	contents []byte
+	version  Version
+
+	// watching is true while the watch keeping the file up to date is
+	// running. cancel stops it. watchID identifies the current watch.
+	watching bool
+	cancel   context.CancelFunc
+	watchID  int
+
+	// staleSince is when the watch stopped.
+	staleSince time.Time
+
+	// refreshing is true while the watch is being started, and refreshed
+	// is closed when it is done. err is the error of the last attempt.
+	refreshing  bool
+	refreshed   chan struct{}
+	lastRefresh time.Time
+	err         error
+}
+
+// cachedListing is the result of a ListDir or a List call.
+type cachedListing struct {
+	entries []DirEntry
+	kvs     []KVInfo
+	read    time.Time
+}
+
+// NewCachingConn returns a CachingConn serving stale data for up to
+// maxStaleness.
+func NewCachingConn(cell string, conn Conn, maxStaleness time.Duration) *CachingConn {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &CachingConn{
+		Conn:         conn,
+		cell:         cell,
+		maxStaleness: maxStaleness,
+		ctx:          ctx,
+		cancel:       cancel,
+		files:        make(map[string]*cachedFile),
+		listings:     make(map[string]*cachedListing),
+	}
+}
+
+// newCachingConnIfEnabled wraps conn in a CachingConn if the read cache is
+// enabled by the flags.
+func newCachingConnIfEnabled(cell string, conn Conn) Conn {
+	if readCacheMaxStaleness == 0 {
+		return conn
+	}
+	return NewCachingConn(cell, conn, readCacheMaxStaleness)
+}
+
+// Get is part of the Conn interface.
+func (c *CachingConn) Get(ctx context.Context, filePath string) ([]byte, Version, error) {
+	statsKey := []string{"Get", c.cell}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for {
+		entry := c.files[filePath]
+		if entry == nil {
+			entry = &cachedFile{}
+			c.files[filePath] = entry
+		}
+
+		switch {
+		case entry.watching:
+			topoReadCacheReads.Add(append(statsKey, "hit"), 1)
+			return entry.contents, entry.version, nil
+		case entry.version != nil && time.Since(entry.staleSince) <= c.maxStaleness:
+			if !entry.refreshing && time.Since(entry.lastRefresh) >= CachingConnRefreshInterval {
+				c.refresh(filePath, entry)
+			}
+			topoReadCacheReads.Add(append(statsKey, "stale"), 1)
+			return entry.contents, entry.version, nil
+		}
+
+		// We have to wait for the file to be read.
+		if !entry.refreshing {
+			c.refresh(filePath, entry)
+		}
+		refreshed := entry.refreshed
+		c.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			c.mu.Lock()
+			return nil, nil, contextError(ctx, filePath)
+		case <-refreshed:
+		}
+		c.mu.Lock()
+
+		if entry.err == nil && c.files[filePath] != entry {
+			// The file was written while we were reading it, so we
+			// read it again.
+			continue
+		}
+		topoReadCacheReads.Add(append(statsKey, "miss"), 1)
+		if entry.err != nil {
+			return nil, nil, entry.err
+		}
+		return entry.contents, entry.version, nil
+	}
+}
+
+// refresh starts watching a file in the background, to read it and keep
+// it up to date. c.mu must be held.
+func (c *CachingConn) refresh(filePath string, entry *cachedFile) {
+	entry.refreshing = true
+	entry.refreshed = make(chan struct{})
+	entry.lastRefresh = time.Now()
+
+	go func() {
+		watchCtx, cancel := context.WithCancel(c.ctx)
+		current, changes, err := c.Conn.Watch(watchCtx, filePath)
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		defer close(entry.refreshed)
+		entry.refreshing = false
+		entry.err = err
+
+		if err != nil {
+			cancel()
+			// We forget about the file if it doesn't exist, or if we
+			// never read it. Otherwise, it is still served while it is
+			// not too stale.
+			if (IsErrType(err, NoNode) || entry.version == nil) && c.files[filePath] == entry {
+				delete(c.files, filePath)
+			}
+			return
+		}
+		if c.files[filePath] != entry {
+			// The file was written in the meantime.
+			cancel()
+			return
+		}
+
+		entry.contents = current.Contents
+		entry.version = current.Version
+		entry.watching = true
+		entry.cancel = cancel
+		entry.watchID++
+		go c.watch(filePath, entry, entry.watchID, changes)
+	}()
+}
+
+// watch updates the file in the cache until its watch stops.
+func (c *CachingConn) watch(filePath string, entry *cachedFile, watchID int, changes <-chan *WatchData) {
+	for wd := range changes {
+		c.mu.Lock()
+		switch {
+		case entry.watchID != watchID:
+			// A new watch replaced this one, we just drain the channel.
+		case wd.Err == nil:
+			entry.contents = wd.Contents
+			entry.version = wd.Version
+		case IsErrType(wd.Err, NoNode):
+			if c.files[filePath] == entry {
+				delete(c.files, filePath)
+			}
+			c.stopWatching(entry)
+		default:
+			if !IsErrType(wd.Err, Interrupted) {
+				log.Warningf("Watch of %v in cell %v for the topo read cache failed, serving the cached file for up to %v: %v", filePath, c.cell, c.maxStaleness, wd.Err)
+			}
+			c.stopWatching(entry)
+		}
+		c.mu.Unlock()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry.watchID == watchID {
+		c.stopWatching(entry)
+	}
+}
+
+// stopWatching marks a file as stale, and stops its watch. c.mu must be
+// held.
+func (c *CachingConn) stopWatching(entry *cachedFile) {
+	if !entry.watching {
+		return
+	}
+	entry.watching = false
+	entry.staleSince = time.Now()
+	entry.cancel()
+}
+
+// invalidate removes a file from the cache, and stops watching it.
+func (c *CachingConn) invalidate(filePath string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := c.files[filePath]
+	if entry == nil {
+		return
+	}
+	delete(c.files, filePath)
+	c.stopWatching(entry)
+}
+
+// ListDir is part of the Conn interface.
+func (c *CachingConn) ListDir(ctx context.Context, dirPath string, full bool) ([]DirEntry, error) {
+	key := "ListDir:" + strconv.FormatBool(full) + ":" + dirPath
+	listing, err := c.list(ctx, "ListDir", key, func() (*cachedListing, error) {
+		entries, err := c.Conn.ListDir(ctx, dirPath, full)
+		return &cachedListing{entries: entries}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return listing.entries, nil
+}
+
+// List is part of the Conn interface.
+func (c *CachingConn) List(ctx context.Context, filePathPrefix string) ([]KVInfo, error) {
+	listing, err := c.list(ctx, "List", "List:"+filePathPrefix, func() (*cachedListing, error) {
+		kvs, err := c.Conn.List(ctx, filePathPrefix)
+		return &cachedListing{kvs: kvs}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return listing.kvs, nil
+}
+
+// list reads a listing from the topo server, and falls back to the cached
+// listing if the topo server is unreachable.
+func (c *CachingConn) list(ctx context.Context, operation, key string, read func() (*cachedListing, error)) (*cachedListing, error) {
+	statsKey := []string{operation, c.cell}
+
+	listing, err := read()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch {
+	case err == nil:
+		listing.read = time.Now()
+		c.listings[key] = listing
+		topoReadCacheReads.Add(append(statsKey, "miss"), 1)
+		return listing, nil
+	case IsErrType(err, NoNode):
+		delete(c.listings, key)
+		return nil, err
+	}
+
+	// Only the errors of the topo server are hidden by the cache.
+	cached := c.listings[key]
+	if cached == nil || ctx.Err() != nil || time.Since(cached.read) > c.maxStaleness {
+		return nil, err
+	}
+	topoReadCacheReads.Add(append(statsKey, "stale"), 1)
+	return cached, nil
+}
+
+// Create is part of the Conn interface.
+func (c *CachingConn) Create(ctx context.Context, filePath string, contents []byte) (Version, error) {
+	defer c.invalidate(filePath)
+	return c.Conn.Create(ctx, filePath, contents)
+}
+
+// Update is part of the Conn interface.
+func (c *CachingConn) Update(ctx context.Context, filePath string, contents []byte, version Version) (Version, error) {
+	defer c.invalidate(filePath)
+	return c.Conn.Update(ctx, filePath, contents, version)
+}
+
+// Delete is part of the Conn interface.
+func (c *CachingConn) Delete(ctx context.Context, filePath string, version Version) error {
+	defer c.invalidate(filePath)
+	return c.Conn.Delete(ctx, filePath, version)
+}
+
+// Close is part of the Conn interface.
+func (c *CachingConn) Close() {
+	c.cancel()
+	c.Conn.Close()
+}
+
+// contextError returns the topo error of a context that is done.
+func contextError(ctx context.Context, nodePath string) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return NewError(Timeout, nodePath)
+	}
+	return NewError(Interrupted, nodePath)
+}
@@ -0,0 +1,400 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+)
+
+var _ Conn = (*EncryptingConn)(nil)
+
+var (
+	// encryptionKeyProviderName is the name of the key provider of the
+	// encryption of the topo files. The files are not encrypted if it is
+	// empty.
+	encryptionKeyProviderName string
+
+	// encryptionKeyFile is the keys file of the "file" key provider.
+	encryptionKeyFile string
+
+	// encryptionPaths are the prefixes of the paths of the files that are
+	// encrypted.
+	encryptionPaths = []string{KeyspacesPath + "/"}
+
+	encryptionKeyProviders = make(map[string]EncryptionKeyProviderFactory)
+
+	encryptionKeyProviderOnce sync.Once
+	encryptionKeyProvider     EncryptionKeyProvider
+	encryptionKeyProviderErr  error
+)
+
+// encryptedMagic starts the contents of the encrypted files. No protobuf
+// or JSON contents start with a NUL byte.
+var encryptedMagic = []byte("\x00VTENC1")
+
+func init() {
+	for _, cmd := range FlagBinaries {
+		servenv.OnParseFor(cmd, registerEncryptionFlags)
+	}
+	RegisterEncryptionKeyProvider("file", newFileEncryptionKeyProvider)
+}
+
+func registerEncryptionFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&encryptionKeyProviderName, "topo_encryption_key_provider", encryptionKeyProviderName, "If set, the topo files under --topo_encryption_paths are encrypted with AES-GCM, with the keys of this provider. The files written before are still read as they are. Built-in providers: file.")
+	fs.StringVar(&encryptionKeyFile, "topo_encryption_key_file", encryptionKeyFile, `Path to the JSON file of the keys of the "file" topo encryption key provider: {"current_key": "<id>", "keys": {"<id>": "<base64 AES key>"}}. The current key encrypts the files written, the others are only used to read the files encrypted with them.`)
+	fs.StringSliceVar(&encryptionPaths, "topo_encryption_paths", encryptionPaths, "Prefixes of the paths of the topo files encrypted with --topo_encryption_key_provider.")
+}
+
+// EncryptionKeyProvider provides the keys encrypting the topo files.
+type EncryptionKeyProvider interface {
+	// CurrentKey returns the key encrypting the files written, and its
+	// id. The key is 16, 24 or 32 bytes long, for AES-128, AES-192 or
+	// AES-256.
+	CurrentKey(ctx context.Context) (id string, key []byte, err error)
+
+	// Key returns the key with the given id, to decrypt the files
+	// encrypted with it.
+	Key(ctx context.Context, id string) ([]byte, error)
+}
+
+// EncryptionKeyProviderFactory creates an EncryptionKeyProvider from the
+// flags.
+type EncryptionKeyProviderFactory func() (EncryptionKeyProvider, error)
+
+// RegisterEncryptionKeyProvider registers an EncryptionKeyProvider, to use
+// with --topo_encryption_key_provider. If a provider with that name already
+// exists, it log.Fatals out. Call this in the 'init' function of your
+// module.
+func RegisterEncryptionKeyProvider(name string, factory EncryptionKeyProviderFactory) {
+	if encryptionKeyProviders[name] != nil {
+		log.Fatalf("Duplicate topo.EncryptionKeyProvider registration for %v", name)
+	}
+	encryptionKeyProviders[name] = factory
+}
+
+// getEncryptionKeyProvider returns the key provider of the flags, or nil if
+// the topo files are not encrypted.
+func getEncryptionKeyProvider() (EncryptionKeyProvider, error) {
+	if encryptionKeyProviderName == "" {
+		return nil, nil
+	}
+	encryptionKeyProviderOnce.Do(func() {
+		factory, ok := encryptionKeyProviders[encryptionKeyProviderName]
+		if !ok {
+			encryptionKeyProviderErr = NewError(NoImplementation, encryptionKeyProviderName)
+			return
+		}
+		encryptionKeyProvider, encryptionKeyProviderErr = factory()
+	})
+	return encryptionKeyProvider, encryptionKeyProviderErr
+}
+
+// newEncryptingConnIfEnabled wraps conn in an EncryptingConn if the
+// encryption is enabled by the flags.
+func newEncryptingConnIfEnabled(conn Conn) (Conn, error) {
+	provider, err := getEncryptionKeyProvider()
+	if err != nil {
+		return nil, fmt.Errorf("cannot create the topo encryption key provider %v: %v", encryptionKeyProviderName, err)
+	}
+	if provider == nil {
+		return conn, nil
+	}
+	return NewEncryptingConn(conn, provider, encryptionPaths), nil
+}
+
+// EncryptingConn is a wrapper for a Conn that encrypts the contents of the
+// files under some paths with AES-GCM, before they are written, and
+// decrypts them when they are read.
+//
+// The encrypted contents start with a header identifying the key that
+// encrypted them, so the keys can be rotated: the new files are encrypted
+// with the current key, and the others are still decrypted with the key
+// that encrypted them. The contents without that header are read as they
+// are, so the encryption can be enabled on an existing topo.
+//
+// The contents of the locks are not encrypted.
+type EncryptingConn struct {
+	Conn
+
+	provider EncryptionKeyProvider
+	paths    []string
+}
+
+// NewEncryptingConn returns an EncryptingConn encrypting the files whose
+// path starts with one of paths, with the keys of provider.
+func NewEncryptingConn(conn Conn, provider EncryptionKeyProvider, paths []string) *EncryptingConn {
+	return &EncryptingConn{
+		Conn:     conn,
+		provider: provider,
+		paths:    paths,
+	}
+}
+
+func (c *EncryptingConn) encrypted(filePath string) bool {
+	filePath = strings.TrimPrefix(filePath, "/")
+	for _, prefix := range c.paths {
+		if strings.HasPrefix(filePath, strings.TrimPrefix(prefix, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// encrypt returns the encrypted contents of a file, if it is encrypted.
+func (c *EncryptingConn) encrypt(ctx context.Context, filePath string, contents []byte) ([]byte, error) {
+	if !c.encrypted(filePath) {
+		return contents, nil
+	}
+	id, key, err := c.provider.CurrentKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get the current topo encryption key: %v", err)
+	}
+	if len(id) > 255 {
+		return nil, fmt.Errorf("topo encryption key id %v is too long", id)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, fmt.Errorf("bad topo encryption key %v: %v", id, err)
+	}
+
+	// The header is the magic, the length of the key id, the key id and
+	// the nonce.
+	header := make([]byte, 0, len(encryptedMagic)+1+len(id)+aead.NonceSize())
+	header = append(header, encryptedMagic...)
+	header = append(header, byte(len(id)))
+	header = append(header, id...)
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	header = append(header, nonce...)
+	return aead.Seal(header, nonce, contents, nil), nil
+}
+
+// decrypt returns the decrypted contents of a file, if they are encrypted.
+func (c *EncryptingConn) decrypt(ctx context.Context, filePath string, contents []byte) ([]byte, error) {
+	if !bytes.HasPrefix(contents, encryptedMagic) {
+		return contents, nil
+	}
+	data := contents[len(encryptedMagic):]
+	if len(data) < 1 || len(data) < 1+int(data[0]) {
+		return nil, fmt.Errorf("cannot decrypt topo file %v: truncated header", filePath)
+	}
+	id := string(data[1 : 1+int(data[0])])
+	data = data[1+int(data[0]):]
+
+	key, err := c.provider.Key(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get the topo encryption key %v of file %v: %v", id, filePath, err)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, fmt.Errorf("bad topo encryption key %v: %v", id, err)
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, fmt.Errorf("cannot decrypt topo file %v: truncated header", filePath)
+	}
+	plaintext, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt topo file %v with key %v: %v", filePath, id, err)
+	}
+	return plaintext, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Create is part of the Conn interface.
+func (c *EncryptingConn) Create(ctx context.Context, filePath string, contents []byte) (Version, error) {
+	contents, err := c.encrypt(ctx, filePath, contents)
+	if err != nil {
+		return nil, err
+	}
+	return c.Conn.Create(ctx, filePath, contents)
+}
+
+// Update is part of the Conn interface.
+func (c *EncryptingConn) Update(ctx context.Context, filePath string, contents []byte, version Version) (Version, error) {
+	contents, err := c.encrypt(ctx, filePath, contents)
+	if err != nil {
+		return nil, err
+	}
+	return c.Conn.Update(ctx, filePath, contents, version)
+}
+
+// Get is part of the Conn interface.
+func (c *EncryptingConn) Get(ctx context.Context, filePath string) ([]byte, Version, error) {
+	contents, version, err := c.Conn.Get(ctx, filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	contents, err = c.decrypt(ctx, filePath, contents)
+	if err != nil {
+		return nil, nil, err
+	}
+	return contents, version, nil
+}
+
+// List is part of the Conn interface.
+func (c *EncryptingConn) List(ctx context.Context, filePathPrefix string) ([]KVInfo, error) {
+	kvs, err := c.Conn.List(ctx, filePathPrefix)
+	if err != nil {
+		return kvs, err
+	}
+	for i := range kvs {
+		kvs[i].Value, err = c.decrypt(ctx, string(kvs[i].Key), kvs[i].Value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return kvs, nil
+}
+
+// Watch is part of the Conn interface.
+func (c *EncryptingConn) Watch(ctx context.Context, filePath string) (*WatchData, <-chan *WatchData, error) {
+	current, changes, err := c.Conn.Watch(ctx, filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	current.Contents, err = c.decrypt(ctx, filePath, current.Contents)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	decrypted := make(chan *WatchData, 10)
+	go func() {
+		defer close(decrypted)
+		for wd := range changes {
+			if wd.Err == nil {
+				wd.Contents, wd.Err = c.decrypt(ctx, filePath, wd.Contents)
+			}
+			decrypted <- wd
+		}
+	}()
+	return current, decrypted, nil
+}
+
+// WatchRecursive is part of the Conn interface.
+func (c *EncryptingConn) WatchRecursive(ctx context.Context, path string) ([]*WatchDataRecursive, <-chan *WatchDataRecursive, error) {
+	current, changes, err := c.Conn.WatchRecursive(ctx, path)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, wd := range current {
+		wd.Contents, err = c.decrypt(ctx, wd.Path, wd.Contents)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	decrypted := make(chan *WatchDataRecursive, 10)
+	go func() {
+		defer close(decrypted)
+		for wd := range changes {
+			if wd.Err == nil {
+				wd.Contents, wd.Err = c.decrypt(ctx, wd.Path, wd.Contents)
+			}
+			decrypted <- wd
+		}
+	}()
+	return current, decrypted, nil
+}
+
+// fileEncryptionKeyProvider is the "file" EncryptionKeyProvider, reading
+// the keys from --topo_encryption_key_file.
+type fileEncryptionKeyProvider struct {
+	currentKey string
+	keys       map[string][]byte
+}
+
+// fileEncryptionKeys is the format of --topo_encryption_key_file.
+type fileEncryptionKeys struct {
+	CurrentKey string            `json:"current_key"`
+	Keys       map[string]string `json:"keys"`
+}
+
+func newFileEncryptionKeyProvider() (EncryptionKeyProvider, error) {
+	if encryptionKeyFile == "" {
+		return nil, fmt.Errorf("--topo_encryption_key_file is required by the file topo encryption key provider")
+	}
+	data, err := os.ReadFile(encryptionKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	return NewStaticEncryptionKeyProvider(data)
+}
+
+// NewStaticEncryptionKeyProvider returns an EncryptionKeyProvider using the
+// keys of a JSON document, in the format of --topo_encryption_key_file.
+func NewStaticEncryptionKeyProvider(data []byte) (EncryptionKeyProvider, error) {
+	var fk fileEncryptionKeys
+	if err := json.Unmarshal(data, &fk); err != nil {
+		return nil, fmt.Errorf("cannot parse the topo encryption keys: %v", err)
+	}
+	p := &fileEncryptionKeyProvider{
+		currentKey: fk.CurrentKey,
+		keys:       make(map[string][]byte, len(fk.Keys)),
+	}
+	for id, encoded := range fk.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode topo encryption key %v: %v", id, err)
+		}
+		if _, err := newAEAD(key); err != nil {
+			return nil, fmt.Errorf("bad topo encryption key %v: %v", id, err)
+		}
+		p.keys[id] = key
+	}
+	if _, ok := p.keys[p.currentKey]; !ok {
+		return nil, fmt.Errorf("unknown current topo encryption key %q", p.currentKey)
+	}
+	return p, nil
+}
+
+// CurrentKey is part of the EncryptionKeyProvider interface.
+func (p *fileEncryptionKeyProvider) CurrentKey(ctx context.Context) (string, []byte, error) {
+	return p.currentKey, p.keys[p.currentKey], nil
+}
+
+// Key is part of the EncryptionKeyProvider interface.
+func (p *fileEncryptionKeyProvider) Key(ctx context.Context, id string) ([]byte, error) {
+	key, ok := p.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown topo encryption key %v", id)
+	}
+	return key, nil
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+)
+
+var _ Conn = (*FailoverConn)(nil)
+
+var (
+	// topoGlobalStandbyServerAddress is the address of the standby global
+	// topology server.
+	topoGlobalStandbyServerAddress string
+
+	// topoGlobalStandbyRoot is the root path of the standby global
+	// topology server.
+	topoGlobalStandbyRoot string
+
+	// topoGlobalStandbyCheckInterval is how often the standby global
+	// topology server is checked for a promotion.
+	topoGlobalStandbyCheckInterval = 10 * time.Second
+)
+
+func init() {
+	for _, cmd := range []string{"vtgate", "vttablet"} {
+		servenv.OnParseFor(cmd, registerStandbyFlags)
+	}
+}
+
+func registerStandbyFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&topoGlobalStandbyServerAddress, "topo_global_standby_server_address", topoGlobalStandbyServerAddress, "the address of the standby global topology server, using the same implementation as the global topology server. The process switches to it once it is promoted with PromoteTopo.")
+	fs.StringVar(&topoGlobalStandbyRoot, "topo_global_standby_root", topoGlobalStandbyRoot, "the path of the global topology data in the standby global topology server")
+	fs.DurationVar(&topoGlobalStandbyCheckInterval, "topo_global_standby_check_interval", topoGlobalStandbyCheckInterval, "how often the standby global topology server is checked for a promotion")
+}
+
+// FailoverConn is a Conn that uses a primary Conn until a standby Conn is
+// promoted, and then switches to the standby Conn for good.
+//
+// The standby is checked for a Promotion record every checkInterval. When
+// it is found, the primary Conn is closed, which interrupts the watches
+// in progress, so that they are restarted against the standby.
+type FailoverConn struct {
+	standby Conn
+	cancel  context.CancelFunc
+
+	mu       sync.RWMutex
+	conn     Conn
+	promoted bool
+}
+
+// NewFailoverConn returns a FailoverConn using primary until standby is
+// promoted. If standby is already promoted, it is used right away.
+func NewFailoverConn(primary, standby Conn, checkInterval time.Duration) *FailoverConn {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &FailoverConn{
+		standby: standby,
+		cancel:  cancel,
+		conn:    primary,
+	}
+	if !c.checkPromotion(ctx) {
+		go c.watchPromotion(ctx, checkInterval)
+	}
+	return c
+}
+
+// newFailoverConnIfEnabled wraps the global cell conn in a FailoverConn if a
+// standby global topo is configured by the flags.
+func newFailoverConnIfEnabled(factory Factory, conn Conn) (Conn, error) {
+	if topoGlobalStandbyServerAddress == "" {
+		return conn, nil
+	}
+	standby, err := factory.Create(GlobalCell, topoGlobalStandbyServerAddress, topoGlobalStandbyRoot)
+	if err != nil {
+		return nil, err
+	}
+	return NewFailoverConn(conn, standby, topoGlobalStandbyCheckInterval), nil
+}
+
+func (c *FailoverConn) watchPromotion(ctx context.Context, checkInterval time.Duration) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if c.checkPromotion(ctx) {
+			return
+		}
+	}
+}
+
+// checkPromotion switches to the standby if it was promoted, and returns
+// true if it did.
+func (c *FailoverConn) checkPromotion(ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, RemoteOperationTimeout)
+	defer cancel()
+	promotion, err := getPromotion(ctx, c.standby)
+	switch {
+	case err == nil:
+	case IsErrType(err, NoNode):
+		return false
+	default:
+		log.Warningf("Cannot check the standby global topo for a promotion: %v", err)
+		return false
+	}
+
+	log.Infof("The standby global topo was promoted at %v, switching to it", promotion.PromotedAt)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn.Close()
+	c.conn = c.standby
+	c.promoted = true
+	return true
+}
+
+// Promoted returns true if the standby was promoted.
+func (c *FailoverConn) Promoted() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.promoted
+}
+
+func (c *FailoverConn) current() Conn {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.conn
+}
+
+// ListDir is part of the Conn interface.
+func (c *FailoverConn) ListDir(ctx context.Context, dirPath string, full bool) ([]DirEntry, error) {
+	return c.current().ListDir(ctx, dirPath, full)
+}
+
+// Create is part of the Conn interface.
+func (c *FailoverConn) Create(ctx context.Context, filePath string, contents []byte) (Version, error) {
+	return c.current().Create(ctx, filePath, contents)
+}
+
+// Update is part of the Conn interface.
+func (c *FailoverConn) Update(ctx context.Context, filePath string, contents []byte, version Version) (Version, error) {
+	return c.current().Update(ctx, filePath, contents, version)
+}
+
+// Get is part of the Conn interface.
+func (c *FailoverConn) Get(ctx context.Context, filePath string) ([]byte, Version, error) {
+	return c.current().Get(ctx, filePath)
+}
+
+// List is part of the Conn interface.
+func (c *FailoverConn) List(ctx context.Context, filePathPrefix string) ([]KVInfo, error) {
+	return c.current().List(ctx, filePathPrefix)
+}
+
+// Delete is part of the Conn interface.
+func (c *FailoverConn) Delete(ctx context.Context, filePath string, version Version) error {
+	return c.current().Delete(ctx, filePath, version)
+}
+
+// Lock is part of the Conn interface.
+func (c *FailoverConn) Lock(ctx context.Context, dirPath, contents string) (LockDescriptor, error) {
+	return c.current().Lock(ctx, dirPath, contents)
+}
+
+// TryLock is part of the Conn interface.
+func (c *FailoverConn) TryLock(ctx context.Context, dirPath, contents string) (LockDescriptor, error) {
+	return c.current().TryLock(ctx, dirPath, contents)
+}
+
+// Watch is part of the Conn interface.
+func (c *FailoverConn) Watch(ctx context.Context, filePath string) (*WatchData, <-chan *WatchData, error) {
+	return c.current().Watch(ctx, filePath)
+}
+
+// WatchRecursive is part of the Conn interface.
+func (c *FailoverConn) WatchRecursive(ctx context.Context, path string) ([]*WatchDataRecursive, <-chan *WatchDataRecursive, error) {
+	return c.current().WatchRecursive(ctx, path)
+}
+
+// NewLeaderParticipation is part of the Conn interface.
+func (c *FailoverConn) NewLeaderParticipation(name, id string) (LeaderParticipation, error) {
+	return c.current().NewLeaderParticipation(name, id)
+}
+
+// Close is part of the Conn interface.
+func (c *FailoverConn) Close() {
+	c.cancel()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.promoted {
+		c.conn.Close()
+	}
+	c.standby.Close()
+}
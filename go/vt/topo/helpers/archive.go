@@ -0,0 +1,282 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// TopoArchiveVersion is the version of the TopoArchive format written by
+// BackupTopo. RestoreTopo rejects archives with a more recent version.
+const TopoArchiveVersion = 1
+
+// BackupTopo reads the cell infos, and the keyspace, shard, vschema and routing
+// rules records of a topo server into a TopoArchive.
+func BackupTopo(ctx context.Context, ts *topo.Server) (*vtctldatapb.TopoArchive, error) {
+	archive := &vtctldatapb.TopoArchive{
+		Version:   TopoArchiveVersion,
+		CreatedAt: protoutil.TimeToProto(time.Now()),
+		CellInfos: map[string]*topodatapb.CellInfo{},
+		Vschemas:  map[string]*vschemapb.Keyspace{},
+	}
+
+	cells, err := ts.GetCellInfoNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetCellInfoNames: %w", err)
+	}
+
+	for _, cell := range cells {
+		ci, err := ts.GetCellInfo(ctx, cell, true /* strongRead */)
+		if err != nil {
+			return nil, fmt.Errorf("GetCellInfo(%v): %w", cell, err)
+		}
+
+		archive.CellInfos[cell] = ci
+	}
+
+	keyspaces, err := ts.GetKeyspaces(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetKeyspaces: %w", err)
+	}
+
+	for _, keyspace := range keyspaces {
+		ki, err := ts.GetKeyspace(ctx, keyspace)
+		if err != nil {
+			return nil, fmt.Errorf("GetKeyspace(%v): %w", keyspace, err)
+		}
+
+		archive.Keyspaces = append(archive.Keyspaces, &vtctldatapb.Keyspace{
+			Name:     keyspace,
+			Keyspace: ki.Keyspace,
+		})
+
+		vs, err := ts.GetVSchema(ctx, keyspace)
+		switch {
+		case err == nil:
+			archive.Vschemas[keyspace] = vs
+		case topo.IsErrType(err, topo.NoNode):
+			// Nothing to back up.
+		default:
+			return nil, fmt.Errorf("GetVSchema(%v): %w", keyspace, err)
+		}
+
+		shards, err := ts.GetShardNames(ctx, keyspace)
+		if err != nil {
+			return nil, fmt.Errorf("GetShardNames(%v): %w", keyspace, err)
+		}
+
+		for _, shard := range shards {
+			si, err := ts.GetShard(ctx, keyspace, shard)
+			if err != nil {
+				return nil, fmt.Errorf("GetShard(%v, %v): %w", keyspace, shard, err)
+			}
+
+			archive.Shards = append(archive.Shards, &vtctldatapb.Shard{
+				Keyspace: keyspace,
+				Name:     shard,
+				Shard:    si.Shard,
+			})
+		}
+	}
+
+	if archive.RoutingRules, err = ts.GetRoutingRules(ctx); err != nil {
+		return nil, fmt.Errorf("GetRoutingRules: %w", err)
+	}
+
+	if archive.ShardRoutingRules, err = ts.GetShardRoutingRules(ctx); err != nil {
+		return nil, fmt.Errorf("GetShardRoutingRules: %w", err)
+	}
+
+	return archive, nil
+}
+
+// RestoreTopo writes the records of a TopoArchive to a topo server. Records
+// that already exist with the same contents are skipped, so that an
+// interrupted restore can be run again, but records that already exist with
+// different contents fail the restore: it never overwrites existing records.
+//
+// The serving graph (SrvKeyspace and SrvVSchema records) is not restored, and
+// should be rebuilt afterwards.
+func RestoreTopo(ctx context.Context, ts *topo.Server, archive *vtctldatapb.TopoArchive, logger logutil.Logger) error {
+	if archive.Version == 0 || archive.Version > TopoArchiveVersion {
+		return fmt.Errorf("unsupported topo archive version %d, this binary supports versions up to %d", archive.Version, TopoArchiveVersion)
+	}
+
+	for _, cell := range sortedKeys(archive.CellInfos) {
+		ci := archive.CellInfos[cell]
+		existing, err := ts.GetCellInfo(ctx, cell, true /* strongRead */)
+		switch {
+		case err == nil:
+			if err := checkExistingRecord("cell info", cell, existing, ci, logger); err != nil {
+				return err
+			}
+		case topo.IsErrType(err, topo.NoNode):
+			if err := ts.CreateCellInfo(ctx, cell, ci); err != nil {
+				return fmt.Errorf("CreateCellInfo(%v): %w", cell, err)
+			}
+
+			logger.Infof("restored cell info %v", cell)
+		default:
+			return fmt.Errorf("GetCellInfo(%v): %w", cell, err)
+		}
+	}
+
+	for _, ks := range archive.Keyspaces {
+		existing, err := ts.GetKeyspace(ctx, ks.Name)
+		switch {
+		case err == nil:
+			if err := checkExistingRecord("keyspace", ks.Name, existing.Keyspace, ks.Keyspace, logger); err != nil {
+				return err
+			}
+		case topo.IsErrType(err, topo.NoNode):
+			if err := ts.CreateKeyspace(ctx, ks.Name, ks.Keyspace); err != nil {
+				return fmt.Errorf("CreateKeyspace(%v): %w", ks.Name, err)
+			}
+
+			logger.Infof("restored keyspace %v", ks.Name)
+		default:
+			return fmt.Errorf("GetKeyspace(%v): %w", ks.Name, err)
+		}
+	}
+
+	for _, keyspace := range sortedKeys(archive.Vschemas) {
+		vs := archive.Vschemas[keyspace]
+		existing, err := ts.GetVSchema(ctx, keyspace)
+		switch {
+		case err == nil:
+			if err := checkExistingRecord("vschema", keyspace, existing, vs, logger); err != nil {
+				return err
+			}
+		case topo.IsErrType(err, topo.NoNode):
+			if err := ts.SaveVSchema(ctx, keyspace, vs); err != nil {
+				return fmt.Errorf("SaveVSchema(%v): %w", keyspace, err)
+			}
+
+			logger.Infof("restored vschema of keyspace %v", keyspace)
+		default:
+			return fmt.Errorf("GetVSchema(%v): %w", keyspace, err)
+		}
+	}
+
+	for _, shard := range archive.Shards {
+		name := topoproto.KeyspaceShardString(shard.Keyspace, shard.Name)
+
+		existing, err := ts.GetShard(ctx, shard.Keyspace, shard.Name)
+		switch {
+		case err == nil:
+			if err := checkExistingRecord("shard", name, existing.Shard, shard.Shard, logger); err != nil {
+				return err
+			}
+		case topo.IsErrType(err, topo.NoNode):
+			if err := ts.CreateShard(ctx, shard.Keyspace, shard.Name); err != nil {
+				return fmt.Errorf("CreateShard(%v): %w", name, err)
+			}
+
+			// CreateShard computes the fields of a new shard, so overwrite
+			// them with the archived ones.
+			if _, err := ts.UpdateShardFields(ctx, shard.Keyspace, shard.Name, func(si *topo.ShardInfo) error {
+				si.Shard = proto.Clone(shard.Shard).(*topodatapb.Shard)
+				return nil
+			}); err != nil {
+				return fmt.Errorf("UpdateShardFields(%v): %w", name, err)
+			}
+
+			logger.Infof("restored shard %v", name)
+		default:
+			return fmt.Errorf("GetShard(%v): %w", name, err)
+		}
+	}
+
+	if rr := archive.RoutingRules; len(rr.GetRules()) > 0 {
+		existing, err := ts.GetRoutingRules(ctx)
+		if err != nil {
+			return fmt.Errorf("GetRoutingRules: %w", err)
+		}
+
+		if len(existing.Rules) > 0 {
+			if err := checkExistingRecord("routing rules", "", existing, rr, logger); err != nil {
+				return err
+			}
+		} else {
+			if err := ts.SaveRoutingRules(ctx, rr); err != nil {
+				return fmt.Errorf("SaveRoutingRules: %w", err)
+			}
+
+			logger.Infof("restored routing rules")
+		}
+	}
+
+	if srr := archive.ShardRoutingRules; len(srr.GetRules()) > 0 {
+		existing, err := ts.GetShardRoutingRules(ctx)
+		if err != nil {
+			return fmt.Errorf("GetShardRoutingRules: %w", err)
+		}
+
+		if len(existing.Rules) > 0 {
+			if err := checkExistingRecord("shard routing rules", "", existing, srr, logger); err != nil {
+				return err
+			}
+		} else {
+			if err := ts.SaveShardRoutingRules(ctx, srr); err != nil {
+				return fmt.Errorf("SaveShardRoutingRules: %w", err)
+			}
+
+			logger.Infof("restored shard routing rules")
+		}
+	}
+
+	return nil
+}
+
+// checkExistingRecord returns an error if a record that RestoreTopo is about
+// to restore already exists with different contents.
+func checkExistingRecord(kind string, name string, existing proto.Message, archived proto.Message, logger logutil.Logger) error {
+	if name != "" {
+		kind = fmt.Sprintf("%s %s", kind, name)
+	}
+
+	if !proto.Equal(existing, archived) {
+		return fmt.Errorf("%s already exists with different contents than in the archive", kind)
+	}
+
+	logger.Infof("%s already exists, skipping it", kind)
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
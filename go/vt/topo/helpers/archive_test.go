@@ -0,0 +1,123 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+func TestBackupRestoreTopo(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fromTS := memorytopo.NewServer(ctx, "zone1", "zone2")
+	defer fromTS.Close()
+
+	require.NoError(t, fromTS.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{DurabilityPolicy: "semi_sync"}))
+	require.NoError(t, fromTS.CreateKeyspace(ctx, "unsharded", &topodatapb.Keyspace{}))
+	require.NoError(t, fromTS.CreateShard(ctx, "ks", "-80"))
+	require.NoError(t, fromTS.CreateShard(ctx, "ks", "80-"))
+	require.NoError(t, fromTS.CreateShard(ctx, "unsharded", "0"))
+	_, err := fromTS.UpdateShardFields(ctx, "ks", "-80", func(si *topo.ShardInfo) error {
+		si.PrimaryAlias = &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, fromTS.SaveVSchema(ctx, "ks", &vschemapb.Keyspace{
+		Sharded: true,
+		Vindexes: map[string]*vschemapb.Vindex{
+			"hash": {Type: "hash"},
+		},
+	}))
+	require.NoError(t, fromTS.SaveRoutingRules(ctx, &vschemapb.RoutingRules{
+		Rules: []*vschemapb.RoutingRule{{
+			FromTable: "t1",
+			ToTables:  []string{"ks.t1"},
+		}},
+	}))
+	require.NoError(t, fromTS.SaveShardRoutingRules(ctx, &vschemapb.ShardRoutingRules{
+		Rules: []*vschemapb.ShardRoutingRule{{
+			FromKeyspace: "unsharded",
+			ToKeyspace:   "ks",
+			Shard:        "-80",
+		}},
+	}))
+
+	archive, err := BackupTopo(ctx, fromTS)
+	require.NoError(t, err)
+	assert.EqualValues(t, TopoArchiveVersion, archive.Version)
+	assert.Len(t, archive.CellInfos, 2)
+	assert.Len(t, archive.Keyspaces, 2)
+	assert.Len(t, archive.Shards, 3)
+	assert.Len(t, archive.Vschemas, 1)
+
+	// The archive survives a round trip through its JSON encoding.
+	data, err := protojson.Marshal(archive)
+	require.NoError(t, err)
+	decoded := &vtctldatapb.TopoArchive{}
+	require.NoError(t, protojson.Unmarshal(data, decoded))
+
+	toTS := memorytopo.NewServer(ctx)
+	defer toTS.Close()
+
+	logger := logutil.NewMemoryLogger()
+	require.NoError(t, RestoreTopo(ctx, toTS, decoded, logger))
+
+	restored, err := BackupTopo(ctx, toTS)
+	require.NoError(t, err)
+	restored.CreatedAt = archive.CreatedAt
+	utils.MustMatch(t, archive, restored)
+
+	// Restoring again is a no-op.
+	require.NoError(t, RestoreTopo(ctx, toTS, decoded, logger))
+
+	// A record that changed after the restore is not overwritten.
+	_, err = toTS.UpdateShardFields(ctx, "ks", "80-", func(si *topo.ShardInfo) error {
+		si.IsPrimaryServing = false
+		return nil
+	})
+	require.NoError(t, err)
+	err = RestoreTopo(ctx, toTS, decoded, logger)
+	assert.ErrorContains(t, err, "shard ks/80- already exists with different contents")
+}
+
+func TestRestoreTopoVersion(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx)
+	defer ts.Close()
+
+	for _, version := range []uint32{0, TopoArchiveVersion + 1} {
+		err := RestoreTopo(ctx, ts, &vtctldatapb.TopoArchive{Version: version}, logutil.NewMemoryLogger())
+		assert.ErrorContains(t, err, "unsupported topo archive version")
+	}
+}
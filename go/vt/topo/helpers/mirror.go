@@ -0,0 +1,218 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// ErrTopoPromoted is returned by TopoMirror when the destination topo was
+// promoted, and must not be written to anymore.
+var ErrTopoPromoted = errors.New("the destination topo was promoted")
+
+// skippedMirrorNames are the names of the files and directories that are
+// never mirrored: the locks of the topo implementations, and the records of
+// the standby itself.
+var skippedMirrorNames = map[string]bool{
+	"locks":               true,
+	"Lock":                true,
+	"elections":           true,
+	topo.PromotionFile:    true,
+	topo.MirrorStatusFile: true,
+}
+
+// TopoMirror mirrors the global topo of a topo server into the global topo
+// of another one, the standby, so that the standby can be promoted if the
+// source is lost (see topo.Server.Promote).
+//
+// All the files of the global topo are mirrored, except the ephemeral ones
+// and the locks, and the files of the standby that don't exist in the
+// source are deleted. The version of each file of the source is recorded
+// when it is mirrored, so that only the files that changed since are
+// written to the standby.
+type TopoMirror struct {
+	fromTS *topo.Server
+	toTS   *topo.Server
+	source string
+
+	// versions are the versions of the source files that were mirrored,
+	// by path.
+	versions map[string]string
+}
+
+// NewTopoMirror returns a TopoMirror from fromTS to toTS. source describes
+// fromTS in the MirrorStatus written to toTS.
+func NewTopoMirror(fromTS, toTS *topo.Server, source string) *TopoMirror {
+	return &TopoMirror{
+		fromTS:   fromTS,
+		toTS:     toTS,
+		source:   source,
+		versions: make(map[string]string),
+	}
+}
+
+// Run syncs the standby every interval, until ctx is done or the standby
+// is promoted. Errors are logged and the sync is retried at the next
+// interval.
+func (m *TopoMirror) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := m.Sync(ctx)
+		switch {
+		case errors.Is(err, ErrTopoPromoted):
+			return err
+		case err != nil:
+			log.Warningf("Topo mirror sync failed, retrying in %v: %v", interval, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync brings the standby in sync with the source, and records it in the
+// MirrorStatus of the standby. It returns ErrTopoPromoted if the standby
+// was promoted.
+func (m *TopoMirror) Sync(ctx context.Context) error {
+	start := time.Now()
+	fromConn, err := m.fromTS.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		return err
+	}
+	toConn, err := m.toTS.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		return err
+	}
+
+	if _, err := m.toTS.GetPromotion(ctx); err == nil {
+		return ErrTopoPromoted
+	} else if !topo.IsErrType(err, topo.NoNode) {
+		return fmt.Errorf("GetPromotion: %w", err)
+	}
+
+	files, err := mirroredFiles(ctx, fromConn, "/")
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool, len(files))
+	for _, file := range files {
+		seen[file] = true
+		if err := m.syncFile(ctx, fromConn, toConn, file); err != nil {
+			return err
+		}
+	}
+
+	// Delete the files that were deleted from the source.
+	standbyFiles, err := mirroredFiles(ctx, toConn, "/")
+	if err != nil {
+		return err
+	}
+	for _, file := range standbyFiles {
+		if seen[file] {
+			continue
+		}
+		if err := toConn.Delete(ctx, file, nil); err != nil && !topo.IsErrType(err, topo.NoNode) {
+			return fmt.Errorf("Delete(%v): %w", file, err)
+		}
+		delete(m.versions, file)
+		log.Infof("Topo mirror deleted %v", file)
+	}
+
+	return m.toTS.SaveMirrorStatus(ctx, &topo.MirrorStatus{
+		Source:   m.source,
+		LastSync: start.UTC(),
+		Files:    len(files),
+	})
+}
+
+// syncFile copies a file of the source to the standby, if it changed since
+// it was last mirrored.
+func (m *TopoMirror) syncFile(ctx context.Context, fromConn, toConn topo.Conn, file string) error {
+	contents, version, err := fromConn.Get(ctx, file)
+	switch {
+	case err == nil:
+	case topo.IsErrType(err, topo.NoNode):
+		// The file was deleted since it was listed.
+		return nil
+	default:
+		return fmt.Errorf("Get(%v): %w", file, err)
+	}
+	if m.versions[file] == version.String() {
+		return nil
+	}
+
+	existing, _, err := toConn.Get(ctx, file)
+	switch {
+	case err == nil:
+		if bytes.Equal(existing, contents) {
+			m.versions[file] = version.String()
+			return nil
+		}
+	case topo.IsErrType(err, topo.NoNode):
+	default:
+		return fmt.Errorf("Get(%v) on the standby: %w", file, err)
+	}
+	if _, err := toConn.Update(ctx, file, contents, nil); err != nil {
+		return fmt.Errorf("Update(%v): %w", file, err)
+	}
+	m.versions[file] = version.String()
+	log.Infof("Topo mirror updated %v to version %v", file, version)
+	return nil
+}
+
+// mirroredFiles returns the paths of the files under dirPath that are
+// mirrored.
+func mirroredFiles(ctx context.Context, conn topo.Conn, dirPath string) ([]string, error) {
+	entries, err := conn.ListDir(ctx, dirPath, true /* full */)
+	switch {
+	case err == nil:
+	case topo.IsErrType(err, topo.NoNode):
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("ListDir(%v): %w", dirPath, err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.Ephemeral || skippedMirrorNames[entry.Name] {
+			continue
+		}
+		entryPath := path.Join(dirPath, entry.Name)
+		if entry.Type == topo.TypeFile {
+			files = append(files, entryPath)
+			continue
+		}
+		children, err := mirroredFiles(ctx, conn, entryPath)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, children...)
+	}
+	return files, nil
+}
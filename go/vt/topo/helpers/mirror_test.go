@@ -0,0 +1,108 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+func TestTopoMirror(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fromTS := memorytopo.NewServer(ctx, "zone1", "zone2")
+	defer fromTS.Close()
+	toTS := memorytopo.NewServer(ctx)
+	defer toTS.Close()
+
+	require.NoError(t, fromTS.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{DurabilityPolicy: "semi_sync"}))
+	require.NoError(t, fromTS.CreateShard(ctx, "ks", "-80"))
+	require.NoError(t, fromTS.CreateShard(ctx, "ks", "80-"))
+	require.NoError(t, fromTS.SaveVSchema(ctx, "ks", &vschemapb.Keyspace{Sharded: true}))
+
+	mirror := NewTopoMirror(fromTS, toTS, "memorytopo")
+	require.NoError(t, mirror.Sync(ctx))
+
+	cells, err := toTS.GetCellInfoNames(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"zone1", "zone2"}, cells)
+	require.NoError(t, CompareKeyspaces(ctx, fromTS, toTS))
+	require.NoError(t, CompareShards(ctx, fromTS, toTS))
+
+	status, err := toTS.GetMirrorStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "memorytopo", status.Source)
+	assert.Equal(t, 6, status.Files)
+	assert.WithinDuration(t, time.Now(), status.LastSync, time.Minute)
+
+	// The changes of the source are mirrored, and only them.
+	_, err = fromTS.UpdateShardFields(ctx, "ks", "-80", func(si *topo.ShardInfo) error {
+		si.PrimaryAlias = &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, fromTS.DeleteShard(ctx, "ks", "80-"))
+	_, unchangedVersion, err := globalConn(t, toTS).Get(ctx, "/keyspaces/ks/Keyspace")
+	require.NoError(t, err)
+
+	require.NoError(t, mirror.Sync(ctx))
+	si, err := toTS.GetShard(ctx, "ks", "-80")
+	require.NoError(t, err)
+	utils.MustMatch(t, &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}, si.PrimaryAlias)
+	_, err = toTS.GetShard(ctx, "ks", "80-")
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "unexpected error: %v", err)
+	_, version, err := globalConn(t, toTS).Get(ctx, "/keyspaces/ks/Keyspace")
+	require.NoError(t, err)
+	assert.Equal(t, unchangedVersion, version)
+
+	// A new mirror doesn't write the files that are already in sync.
+	require.NoError(t, NewTopoMirror(fromTS, toTS, "memorytopo").Sync(ctx))
+	_, version, err = globalConn(t, toTS).Get(ctx, "/keyspaces/ks/Keyspace")
+	require.NoError(t, err)
+	assert.Equal(t, unchangedVersion, version)
+
+	// Once the standby is promoted, it is not written to anymore.
+	promotion, err := toTS.Promote(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 5, promotion.MirrorStatus.Files)
+	_, err = toTS.Promote(ctx)
+	assert.True(t, topo.IsErrType(err, topo.NodeExists), "unexpected error: %v", err)
+
+	require.NoError(t, fromTS.DeleteShard(ctx, "ks", "-80"))
+	assert.ErrorIs(t, mirror.Sync(ctx), ErrTopoPromoted)
+	assert.ErrorIs(t, mirror.Run(ctx, time.Millisecond), ErrTopoPromoted)
+	_, err = toTS.GetShard(ctx, "ks", "-80")
+	require.NoError(t, err)
+}
+
+func globalConn(t *testing.T, ts *topo.Server) topo.Conn {
+	conn, err := ts.ConnForCell(context.Background(), topo.GlobalCell)
+	require.NoError(t, err)
+	return conn
+}
@@ -382,3 +382,70 @@ func (ts *Server) GetShardNames(ctx context.Context, keyspace string) ([]string,
 	}
 	return DirEntriesToStringArray(children), err
 }
+
+// WatchKeyspaceData wraps the data we receive on the watch channel
+// The WatchKeyspace API guarantees exactly one of Value or Err will be set.
+type WatchKeyspaceData struct {
+	Value *topodatapb.Keyspace
+	Err   error
+}
+
+// WatchKeyspace will set a watch on the Keyspace object.
+// It has the same contract as conn.Watch, but it also unpacks the
+// contents into a Keyspace object
+func (ts *Server) WatchKeyspace(ctx context.Context, keyspace string) (*WatchKeyspaceData, <-chan *WatchKeyspaceData, error) {
+	if err := ValidateKeyspaceName(keyspace); err != nil {
+		return nil, nil, vterrors.Wrapf(err, "WatchKeyspace: %s", err)
+	}
+
+	keyspacePath := path.Join(KeyspacesPath, keyspace, KeyspaceFile)
+	ctx, cancel := context.WithCancel(ctx)
+
+	current, wdChannel, err := ts.globalCell.Watch(ctx, keyspacePath)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	value := &topodatapb.Keyspace{}
+	if err := value.UnmarshalVT(current.Contents); err != nil {
+		// Cancel the watch, drain channel.
+		cancel()
+		for range wdChannel {
+		}
+		return nil, nil, vterrors.Wrapf(err, "error unpacking initial Keyspace object")
+	}
+
+	changes := make(chan *WatchKeyspaceData, 10)
+	// The background routine reads any event from the watch channel,
+	// translates it, and sends it to the caller.
+	// If cancel() is called, the underlying Watch() code will
+	// send an ErrInterrupted and then close the channel. We'll
+	// just propagate that back to our caller.
+	go func() {
+		defer cancel()
+		defer close(changes)
+
+		for wd := range wdChannel {
+			if wd.Err != nil {
+				// Last error value, we're done.
+				// wdChannel will be closed right after
+				// this, no need to do anything.
+				changes <- &WatchKeyspaceData{Err: wd.Err}
+				return
+			}
+
+			value := &topodatapb.Keyspace{}
+			if err := value.UnmarshalVT(wd.Contents); err != nil {
+				cancel()
+				for range wdChannel {
+				}
+				changes <- &WatchKeyspaceData{Err: vterrors.Wrapf(err, "error unpacking Keyspace object")}
+				return
+			}
+
+			changes <- &WatchKeyspaceData{Value: value}
+		}
+	}()
+
+	return &WatchKeyspaceData{Value: value}, changes, nil
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"time"
+
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// This file contains the maintenance windows, which freeze the topology of a
+// keyspace, a shard or a cell for a TTL: VTOrc doesn't run any recovery there
+// until the window is stopped or expires. They are stored as JSON in the
+// global topo, one file per keyspace, shard or cell in maintenance.
+
+// MaintenanceWindow describes a keyspace, a shard or a cell in maintenance.
+// Keyspace and Shard are set for a shard, only Keyspace for a keyspace, and
+// only Cell for a cell.
+// It needs to be public as we JSON-serialize it.
+type MaintenanceWindow struct {
+	Keyspace string    `json:"keyspace,omitempty"`
+	Shard    string    `json:"shard,omitempty"`
+	Cell     string    `json:"cell,omitempty"`
+	Reason   string    `json:"reason,omitempty"`
+	Started  time.Time `json:"started"`
+	Expires  time.Time `json:"expires"`
+}
+
+// Target returns the keyspace, shard or cell in maintenance, for display.
+func (w *MaintenanceWindow) Target() string {
+	switch {
+	case w.Cell != "":
+		return "cell " + w.Cell
+	case w.Shard != "":
+		return fmt.Sprintf("shard %v/%v", w.Keyspace, w.Shard)
+	default:
+		return "keyspace " + w.Keyspace
+	}
+}
+
+// Covers returns whether the window applies to the given tablet location.
+// Any of keyspace, shard and cell can be empty if it is not known.
+func (w *MaintenanceWindow) Covers(keyspace, shard, cell string) bool {
+	switch {
+	case w.Cell != "":
+		return w.Cell == cell
+	case w.Shard != "":
+		return w.Keyspace == keyspace && w.Shard == shard
+	default:
+		return w.Keyspace == keyspace
+	}
+}
+
+// expired returns true if the window is over.
+func (w *MaintenanceWindow) expired(now time.Time) bool {
+	return !now.Before(w.Expires)
+}
+
+// StartMaintenance puts a shard, a keyspace if shard is empty, or a cell if
+// keyspace is empty, into maintenance until ttl elapses. If the target is
+// already in maintenance, its window is replaced, to extend it for instance.
+func (ts *Server) StartMaintenance(ctx context.Context, keyspace, shard, cell, reason string, ttl time.Duration) (*MaintenanceWindow, error) {
+	if err := checkMaintenanceTarget(keyspace, shard, cell); err != nil {
+		return nil, err
+	}
+	if ttl <= 0 {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "maintenance window TTL must be positive: %v", ttl)
+	}
+
+	now := time.Now()
+	window := &MaintenanceWindow{
+		Keyspace: keyspace,
+		Shard:    shard,
+		Cell:     cell,
+		Reason:   reason,
+		Started:  now,
+		Expires:  now.Add(ttl),
+	}
+	data, err := json.Marshal(window)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ts.globalCell.Update(ctx, maintenanceWindowFilePath(keyspace, shard, cell), data, nil); err != nil {
+		return nil, err
+	}
+	return window, nil
+}
+
+// StopMaintenance ends the maintenance of a shard, a keyspace if shard is
+// empty, or a cell if keyspace is empty. It returns a NoNode error if the
+// target is not in maintenance.
+func (ts *Server) StopMaintenance(ctx context.Context, keyspace, shard, cell string) error {
+	if err := checkMaintenanceTarget(keyspace, shard, cell); err != nil {
+		return err
+	}
+	return ts.globalCell.Delete(ctx, maintenanceWindowFilePath(keyspace, shard, cell), nil)
+}
+
+// GetMaintenanceWindows returns the maintenance windows that are not over,
+// sorted by target.
+func (ts *Server) GetMaintenanceWindows(ctx context.Context) ([]*MaintenanceWindow, error) {
+	entries, err := ts.globalCell.ListDir(ctx, MaintenanceWindowsPath, false /*full*/)
+	switch {
+	case IsErrType(err, NoNode):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	now := time.Now()
+	var windows []*MaintenanceWindow
+	for _, entry := range entries {
+		window := &MaintenanceWindow{}
+		err := getJSONFile(ctx, ts.globalCell, path.Join(MaintenanceWindowsPath, entry.Name), window)
+		switch {
+		case IsErrType(err, NoNode):
+			// The window was stopped in the meantime.
+			continue
+		case err != nil:
+			return nil, err
+		}
+		if !window.expired(now) {
+			windows = append(windows, window)
+		}
+	}
+	sort.Slice(windows, func(i, j int) bool {
+		return windows[i].Target() < windows[j].Target()
+	})
+	return windows, nil
+}
+
+func checkMaintenanceTarget(keyspace, shard, cell string) error {
+	switch {
+	case shard != "" && keyspace == "":
+		return vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "the maintenance window of a shard needs its keyspace")
+	case keyspace == "" && cell == "":
+		return vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "a maintenance window needs a keyspace, a shard or a cell")
+	case keyspace != "" && cell != "":
+		return vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "a maintenance window is either for a keyspace, a shard or a cell")
+	}
+	return nil
+}
+
+// maintenanceWindowFilePath returns the path of the maintenance window of a
+// shard, a keyspace or a cell.
+func maintenanceWindowFilePath(keyspace, shard, cell string) string {
+	var name string
+	switch {
+	case cell != "":
+		name = "cell:" + cell
+	case shard != "":
+		name = "shard:" + keyspace + "/" + shard
+	default:
+		name = "keyspace:" + keyspace
+	}
+	return path.Join(MaintenanceWindowsPath, url.PathEscape(name))
+}
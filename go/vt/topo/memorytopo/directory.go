@@ -27,7 +27,7 @@ import (
 
 // ListDir is part of the topo.Conn interface.
 func (c *Conn) ListDir(ctx context.Context, dirPath string, full bool) ([]topo.DirEntry, error) {
-	if err := c.dial(ctx); err != nil {
+	if err := c.dial(ctx, "ListDir", dirPath); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,237 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memorytopo
+
+import (
+	"context"
+	"math/rand"
+	"path"
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// Fault is a fault injected in the operations of the connections of a
+// Factory, to test how their callers cope with a faulty topo server.
+type Fault struct {
+	// Cell restricts the fault to the topo server of a cell. The fault
+	// affects all the cells if it is empty.
+	Cell string
+
+	// PathPrefix restricts the fault to the operations on the paths
+	// starting with it.
+	PathPrefix string
+
+	// Operations restricts the fault to the given operations: ListDir,
+	// Create, Update, Get, List, Delete, Lock, Watch and WatchRecursive.
+	// The fault affects all of them if it is empty.
+	Operations []string
+
+	// Latency is added to the affected operations, before they are
+	// executed.
+	Latency time.Duration
+
+	// Err is returned by a fraction of the affected operations, given by
+	// ErrorRate: 0 for none of them, 1 for all of them. The operations
+	// failing are not executed.
+	Err       error
+	ErrorRate float64
+}
+
+func (fault *Fault) matches(cell, operation, nodePath string) bool {
+	if fault.Cell != "" && fault.Cell != cell {
+		return false
+	}
+	if !strings.HasPrefix(nodePath, fault.PathPrefix) {
+		return false
+	}
+	if len(fault.Operations) == 0 {
+		return true
+	}
+	for _, op := range fault.Operations {
+		if op == operation {
+			return true
+		}
+	}
+	return false
+}
+
+// AddFault injects a fault in the operations of the connections, until it
+// is removed with the returned function, or with ClearFaults.
+func (f *Factory) AddFault(fault Fault) (remove func()) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := f.nextFaultID
+	f.nextFaultID++
+	f.faults[id] = &fault
+	return func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		delete(f.faults, id)
+	}
+}
+
+// ClearFaults removes all the faults and partitions.
+func (f *Factory) ClearFaults() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults = make(map[int]*Fault)
+	f.partitions = make(map[int]*partition)
+}
+
+// injectFaults adds the latency of the faults of an operation, and returns
+// the error of the first one failing it, if any.
+func (f *Factory) injectFaults(ctx context.Context, cell, operation, nodePath string) error {
+	var latency time.Duration
+	var err error
+	f.mu.Lock()
+	for _, fault := range f.faults {
+		if !fault.matches(cell, operation, nodePath) {
+			continue
+		}
+		latency += fault.Latency
+		if err == nil && fault.Err != nil && rand.Float64() < fault.ErrorRate {
+			err = fault.Err
+		}
+	}
+	f.mu.Unlock()
+
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return convertError(ctx.Err(), nodePath)
+		}
+	}
+	return err
+}
+
+// partition separates two groups of cells.
+type partition struct {
+	side1, side2 map[string]bool
+}
+
+func (p *partition) separates(cell1, cell2 string) bool {
+	return (p.side1[cell1] && p.side2[cell2]) || (p.side2[cell1] && p.side1[cell2])
+}
+
+// Partition simulates a network partition between two groups of cells,
+// which may include topo.GlobalCell: the processes of the cells of a group
+// can't reach the topo servers of the cells of the other group, until the
+// partition is healed with the returned function, or with ClearFaults.
+//
+// The processes of a cell use the servers returned by NewServerInCell.
+// Their operations on the topo servers they can't reach block until their
+// context is done, their new watches fail right away, and their current
+// watches fail with a topo.Timeout error.
+func (f *Factory) Partition(side1, side2 []string) (heal func()) {
+	p := &partition{
+		side1: make(map[string]bool),
+		side2: make(map[string]bool),
+	}
+	for _, cell := range side1 {
+		p.side1[cell] = true
+	}
+	for _, cell := range side2 {
+		p.side2[cell] = true
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := f.nextFaultID
+	f.nextFaultID++
+	f.partitions[id] = p
+	for cell, n := range f.cells {
+		f.disconnectWatches(n, "", func(w watch, nodePath string) error {
+			if !p.separates(w.clientCell, cell) {
+				return nil
+			}
+			return topo.NewError(topo.Timeout, nodePath)
+		})
+	}
+	return func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		delete(f.partitions, id)
+	}
+}
+
+// isPartitioned returns true if a partition cuts off the processes of
+// clientCell from the topo server of cell.
+func (f *Factory) isPartitioned(clientCell, cell string) bool {
+	if clientCell == "" {
+		return false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, p := range f.partitions {
+		if p.separates(clientCell, cell) {
+			return true
+		}
+	}
+	return false
+}
+
+// DisconnectWatches makes the watches of the files of a cell under
+// pathPrefix fail with the given error, like if the connection to the topo
+// server was lost. The watchers have to watch the files again to get their
+// next changes.
+func (f *Factory) DisconnectWatches(cell, pathPrefix string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n, ok := f.cells[cell]
+	if !ok {
+		return
+	}
+	f.disconnectWatches(n, "", func(w watch, nodePath string) error {
+		if !strings.HasPrefix(nodePath, pathPrefix) {
+			return nil
+		}
+		return err
+	})
+}
+
+// disconnectWatches stops the watches of a node and its children for
+// which disconnect returns an error, and sends them the error. f.mu must be
+// held.
+func (f *Factory) disconnectWatches(n *node, nodePath string, disconnect func(w watch, nodePath string) error) {
+	for index, w := range n.watches {
+		if w.contents == nil && w.recursive == nil {
+			// The watches of the leader elections are not affected.
+			continue
+		}
+		err := disconnect(w, nodePath)
+		if err == nil {
+			continue
+		}
+		delete(n.watches, index)
+		if w.contents != nil {
+			w.contents <- &topo.WatchData{Err: err}
+			close(w.contents)
+		} else {
+			// The recursive watches are closed when their context is
+			// done, as usual.
+			w.recursive <- &topo.WatchDataRecursive{Path: nodePath, WatchData: topo.WatchData{Err: err}}
+		}
+	}
+	for name, child := range n.children {
+		f.disconnectWatches(child, path.Join(nodePath, name), disconnect)
+	}
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memorytopo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestFaultErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts, f := NewServerAndFactory(ctx, "cell1")
+	defer ts.Close()
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks1", &topodatapb.Keyspace{}))
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks2", &topodatapb.Keyspace{}))
+
+	errInjected := errors.New("injected error")
+	remove := f.AddFault(Fault{
+		Cell:       topo.GlobalCell,
+		PathPrefix: "keyspaces/ks1/",
+		Operations: []string{"Get"},
+		Err:        errInjected,
+		ErrorRate:  1,
+	})
+	_, err := ts.GetKeyspace(ctx, "ks1")
+	assert.ErrorIs(t, err, errInjected)
+	_, err = ts.GetKeyspace(ctx, "ks2")
+	assert.NoError(t, err)
+	// Only the Get operations are affected.
+	_, err = ts.GetKeyspaces(ctx)
+	assert.NoError(t, err)
+
+	remove()
+	_, err = ts.GetKeyspace(ctx, "ks1")
+	assert.NoError(t, err)
+
+	// A fault with an error rate of 0 never fails.
+	f.AddFault(Fault{Err: errInjected})
+	_, err = ts.GetKeyspace(ctx, "ks1")
+	assert.NoError(t, err)
+
+	f.AddFault(Fault{Err: errInjected, ErrorRate: 1})
+	_, err = ts.GetKeyspace(ctx, "ks1")
+	assert.ErrorIs(t, err, errInjected)
+	f.ClearFaults()
+	_, err = ts.GetKeyspace(ctx, "ks1")
+	assert.NoError(t, err)
+}
+
+func TestFaultLatency(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts, f := NewServerAndFactory(ctx, "cell1")
+	defer ts.Close()
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks1", &topodatapb.Keyspace{}))
+
+	latency := 50 * time.Millisecond
+	f.AddFault(Fault{PathPrefix: "keyspaces/", Latency: latency})
+	start := time.Now()
+	_, err := ts.GetKeyspace(ctx, "ks1")
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), latency)
+
+	shortCtx, shortCancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer shortCancel()
+	_, err = ts.GetKeyspace(shortCtx, "ks1")
+	assert.True(t, topo.IsErrType(err, topo.Timeout), "expected Timeout, got %v", err)
+}
+
+func TestDisconnectWatches(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts, f := NewServerAndFactory(ctx, "cell1")
+	defer ts.Close()
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks1", &topodatapb.Keyspace{}))
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks2", &topodatapb.Keyspace{}))
+
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	require.NoError(t, err)
+	_, changes1, err := conn.Watch(ctx, "keyspaces/ks1/Keyspace")
+	require.NoError(t, err)
+	_, changes2, err := conn.Watch(ctx, "keyspaces/ks2/Keyspace")
+	require.NoError(t, err)
+
+	errLost := errors.New("connection lost")
+	f.DisconnectWatches(topo.GlobalCell, "keyspaces/ks1/", errLost)
+	wd := <-changes1
+	assert.ErrorIs(t, wd.Err, errLost)
+	_, ok := <-changes1
+	assert.False(t, ok, "the watch should be closed")
+
+	// The other watch still works.
+	_, err = conn.Update(ctx, "keyspaces/ks2/Keyspace", []byte{}, nil)
+	require.NoError(t, err)
+	wd = <-changes2
+	assert.NoError(t, wd.Err)
+}
+
+func TestPartition(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts, f := NewServerAndFactory(ctx, "cell1", "cell2")
+	defer ts.Close()
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks1", &topodatapb.Keyspace{}))
+	require.NoError(t, ts.UpdateSrvVSchema(ctx, "cell2", nil))
+
+	ts1, err := f.NewServerInCell("cell1")
+	require.NoError(t, err)
+	defer ts1.Close()
+	ts2, err := f.NewServerInCell("cell2")
+	require.NoError(t, err)
+	defer ts2.Close()
+
+	conn, err := ts1.ConnForCell(ctx, topo.GlobalCell)
+	require.NoError(t, err)
+	_, changes, err := conn.Watch(ctx, "keyspaces/ks1/Keyspace")
+	require.NoError(t, err)
+
+	// cell1 can't reach the global topo nor cell2 anymore.
+	heal := f.Partition([]string{"cell1"}, []string{topo.GlobalCell, "cell2"})
+	wd := <-changes
+	assert.True(t, topo.IsErrType(wd.Err, topo.Timeout), "expected Timeout, got %v", wd.Err)
+
+	shortCtx, shortCancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer shortCancel()
+	_, err = ts1.GetKeyspace(shortCtx, "ks1")
+	assert.Error(t, err)
+	_, _, err = conn.Watch(ctx, "keyspaces/ks1/Keyspace")
+	assert.True(t, topo.IsErrType(err, topo.Timeout), "expected Timeout, got %v", err)
+
+	// The other processes are not affected.
+	_, err = ts2.GetKeyspace(ctx, "ks1")
+	assert.NoError(t, err)
+	_, err = ts2.GetSrvVSchema(ctx, "cell2")
+	assert.NoError(t, err)
+	_, err = ts.GetKeyspace(ctx, "ks1")
+	assert.NoError(t, err)
+
+	heal()
+	_, err = ts1.GetKeyspace(ctx, "ks1")
+	assert.NoError(t, err)
+	_, err = ts1.GetSrvVSchema(ctx, "cell2")
+	assert.NoError(t, err)
+}
@@ -30,7 +30,7 @@ import (
 
 // Create is part of topo.Conn interface.
 func (c *Conn) Create(ctx context.Context, filePath string, contents []byte) (topo.Version, error) {
-	if err := c.dial(ctx); err != nil {
+	if err := c.dial(ctx, "Create", filePath); err != nil {
 		return nil, err
 	}
 
@@ -74,7 +74,7 @@ func (c *Conn) Create(ctx context.Context, filePath string, contents []byte) (to
 
 // Update is part of topo.Conn interface.
 func (c *Conn) Update(ctx context.Context, filePath string, contents []byte, version topo.Version) (topo.Version, error) {
-	if err := c.dial(ctx); err != nil {
+	if err := c.dial(ctx, "Update", filePath); err != nil {
 		return nil, err
 	}
 
@@ -152,7 +152,7 @@ func (c *Conn) Update(ctx context.Context, filePath string, contents []byte, ver
 
 // Get is part of topo.Conn interface.
 func (c *Conn) Get(ctx context.Context, filePath string) ([]byte, topo.Version, error) {
-	if err := c.dial(ctx); err != nil {
+	if err := c.dial(ctx, "Get", filePath); err != nil {
 		return nil, nil, err
 	}
 
@@ -177,7 +177,7 @@ func (c *Conn) Get(ctx context.Context, filePath string) ([]byte, topo.Version,
 
 // List is part of the topo.Conn interface.
 func (c *Conn) List(ctx context.Context, filePathPrefix string) ([]topo.KVInfo, error) {
-	if err := c.dial(ctx); err != nil {
+	if err := c.dial(ctx, "List", filePathPrefix); err != nil {
 		return nil, err
 	}
 
@@ -236,7 +236,7 @@ func gatherChildren(n *node, dirPath string) []topo.KVInfo {
 
 // Delete is part of topo.Conn interface.
 func (c *Conn) Delete(ctx context.Context, filePath string, version topo.Version) error {
-	if err := c.dial(ctx); err != nil {
+	if err := c.dial(ctx, "Delete", filePath); err != nil {
 		return err
 	}
 
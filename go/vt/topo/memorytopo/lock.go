@@ -53,7 +53,7 @@ func (c *Conn) Lock(ctx context.Context, dirPath, contents string) (topo.LockDes
 // Lock is part of the topo.Conn interface.
 func (c *Conn) lock(ctx context.Context, dirPath, contents string) (topo.LockDescriptor, error) {
 	for {
-		if err := c.dial(ctx); err != nil {
+		if err := c.dial(ctx, "Lock", dirPath); err != nil {
 			return nil, err
 		}
 
@@ -71,6 +71,11 @@ type Factory struct {
 	// err is used for testing purposes to force queries / watches
 	// to return the given error
 	err error
+	// faults and partitions are the faults injected with AddFault and
+	// Partition, by id.
+	faults      map[int]*Fault
+	partitions  map[int]*partition
+	nextFaultID int
 }
 
 // HasGlobalReadOnlyCell is part of the topo.Factory interface.
@@ -92,6 +97,23 @@ func (f *Factory) Create(cell, serverAddr, root string) (topo.Conn, error) {
 	}, nil
 }
 
+// clientFactory is a topo.Factory creating the connections of the
+// processes of a cell, see NewServerInCell.
+type clientFactory struct {
+	*Factory
+	clientCell string
+}
+
+// Create is part of the topo.Factory interface.
+func (cf clientFactory) Create(cell, serverAddr, root string) (topo.Conn, error) {
+	conn, err := cf.Factory.Create(cell, serverAddr, root)
+	if err != nil {
+		return nil, err
+	}
+	conn.(*Conn).clientCell = cf.clientCell
+	return conn, nil
+}
+
 // SetError forces the given error to be returned from all calls and propagates
 // the error to all active watches.
 func (f *Factory) SetError(err error) {
@@ -125,21 +147,42 @@ type Conn struct {
 	cell       string
 	serverAddr string
 	closed     bool
+	// clientCell is the cell of the process using the connection, if it
+	// was created by a server returned by NewServerInCell.
+	clientCell string
 }
 
 // dial returns immediately, unless the Conn points to the sentinel
-// UnreachableServerAddr, in which case it will block until the context expires.
-func (c *Conn) dial(ctx context.Context) error {
+// UnreachableServerAddr or is cut off by a partition, in which case it
+// will block until the context expires. It also injects the faults of the
+// operation, if any.
+func (c *Conn) dial(ctx context.Context, operation, nodePath string) error {
 	if c.closed {
 		return ErrConnectionClosed
 	}
-	if c.serverAddr == UnreachableServerAddr {
+	if c.serverAddr == UnreachableServerAddr || c.factory.isPartitioned(c.clientCell, c.cell) {
 		<-ctx.Done()
+		return ctx.Err()
+	}
+	if err := c.factory.injectFaults(ctx, c.cell, operation, nodePath); err != nil {
+		return err
 	}
 
 	return ctx.Err()
 }
 
+// startWatch checks a watch can be started. Unlike the other operations,
+// the watches fail right away if the Conn is cut off by a partition.
+func (c *Conn) startWatch(ctx context.Context, operation, nodePath string) error {
+	if c.closed {
+		return ErrConnectionClosed
+	}
+	if c.factory.isPartitioned(c.clientCell, c.cell) {
+		return topo.NewError(topo.Timeout, nodePath)
+	}
+	return c.factory.injectFaults(ctx, c.cell, operation, nodePath)
+}
+
 // Close is part of the topo.Conn interface.
 func (c *Conn) Close() {
 	c.closed = true
@@ -149,6 +192,9 @@ type watch struct {
 	contents  chan *topo.WatchData
 	recursive chan *topo.WatchDataRecursive
 	lock      chan string
+
+	// clientCell is the clientCell of the Conn of the watch.
+	clientCell string
 }
 
 // node contains a directory or a file entry.
@@ -238,6 +284,8 @@ func NewServerAndFactory(ctx context.Context, cells ...string) (*topo.Server, *F
 	f := &Factory{
 		cells:      make(map[string]*node),
 		generation: uint64(rand.Int63n(1 << 60)),
+		faults:     make(map[int]*Fault),
+		partitions: make(map[int]*partition),
 	}
 	f.cells[topo.GlobalCell] = f.newDirectory(topo.GlobalCell, nil)
 
@@ -260,6 +308,13 @@ func NewServer(ctx context.Context, cells ...string) *topo.Server {
 	return server
 }
 
+// NewServerInCell returns a new server on the same data, for the processes
+// of the given cell. Its connections are cut off by the partitions
+// isolating clientCell, see Partition.
+func (f *Factory) NewServerInCell(clientCell string) (*topo.Server, error) {
+	return topo.NewWithFactory(clientFactory{Factory: f, clientCell: clientCell}, "" /*serverAddress*/, "" /*root*/)
+}
+
 func (f *Factory) getNextVersion() uint64 {
 	f.generation++
 	return f.generation
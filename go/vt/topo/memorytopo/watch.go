@@ -25,8 +25,8 @@ import (
 
 // Watch is part of the topo.Conn interface.
 func (c *Conn) Watch(ctx context.Context, filePath string) (*topo.WatchData, <-chan *topo.WatchData, error) {
-	if c.closed {
-		return nil, nil, ErrConnectionClosed
+	if err := c.startWatch(ctx, "Watch", filePath); err != nil {
+		return nil, nil, err
 	}
 
 	c.factory.Lock()
@@ -50,7 +50,7 @@ func (c *Conn) Watch(ctx context.Context, filePath string) (*topo.WatchData, <-c
 	}
 
 	notifications := make(chan *topo.WatchData, 100)
-	watchIndex := n.addWatch(watch{contents: notifications})
+	watchIndex := n.addWatch(watch{contents: notifications, clientCell: c.clientCell})
 
 	go func() {
 		<-ctx.Done()
@@ -75,8 +75,8 @@ func (c *Conn) Watch(ctx context.Context, filePath string) (*topo.WatchData, <-c
 
 // WatchRecursive is part of the topo.Conn interface.
 func (c *Conn) WatchRecursive(ctx context.Context, dirpath string) ([]*topo.WatchDataRecursive, <-chan *topo.WatchDataRecursive, error) {
-	if c.closed {
-		return nil, nil, ErrConnectionClosed
+	if err := c.startWatch(ctx, "WatchRecursive", dirpath); err != nil {
+		return nil, nil, err
 	}
 
 	c.factory.Lock()
@@ -103,7 +103,7 @@ func (c *Conn) WatchRecursive(ctx context.Context, dirpath string) ([]*topo.Watc
 	})
 
 	notifications := make(chan *topo.WatchDataRecursive, 100)
-	watchIndex := n.addWatch(watch{recursive: notifications})
+	watchIndex := n.addWatch(watch{recursive: notifications, clientCell: c.clientCell})
 
 	go func() {
 		defer close(notifications)
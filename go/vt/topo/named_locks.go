@@ -0,0 +1,270 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/trace"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// This file contains the named locks, which applications use to coordinate
+// through the global topo. A named lock is held by an owner for a TTL, and
+// has to be renewed before it expires. Expiration relies on the clocks of
+// the processes using the locks to be reasonably in sync.
+
+// NamedLockPollInterval is how often a caller waiting for a named lock held
+// by another owner checks again.
+var NamedLockPollInterval = 500 * time.Millisecond
+
+// NamedLock describes the owner of a named lock.
+// It needs to be public as we JSON-serialize it.
+type NamedLock struct {
+	Name     string
+	Owner    string
+	Acquired time.Time
+	Expires  time.Time
+}
+
+// expired returns true if the lock was not renewed in time, which makes it
+// free to acquire.
+func (l *NamedLock) expired(now time.Time) bool {
+	return !now.Before(l.Expires)
+}
+
+// GetNamedLock returns the named lock, or a NoNode error if it is not held.
+func (ts *Server) GetNamedLock(ctx context.Context, name string) (*NamedLock, error) {
+	lock, _, err := ts.getNamedLock(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if lock.expired(time.Now()) {
+		return nil, NewError(NoNode, namedLockFilePath(name))
+	}
+	return lock, nil
+}
+
+// AcquireNamedLock acquires the named lock for owner, until it expires after
+// ttl. If owner already holds the lock, it is renewed. If another owner holds
+// it, AcquireNamedLock waits for up to wait for the lock to be released or to
+// expire, and then returns a NodeExists error. A negative wait waits until ctx
+// is done.
+func (ts *Server) AcquireNamedLock(ctx context.Context, name, owner string, ttl, wait time.Duration) (*NamedLock, error) {
+	span, ctx := trace.NewSpan(ctx, "TopoServer.AcquireNamedLock")
+	span.Annotate("name", name)
+	defer span.Finish()
+
+	if err := checkNamedLockArgs(name, owner); err != nil {
+		return nil, err
+	}
+	if err := checkNamedLockTTL(ttl); err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(wait)
+	for {
+		lock, err := ts.tryAcquireNamedLock(ctx, name, owner, ttl)
+		switch {
+		case err == nil:
+			return lock, nil
+		case IsErrType(err, BadVersion):
+			// Another owner raced us, we check the lock again.
+			continue
+		case !IsErrType(err, NodeExists):
+			return nil, err
+		}
+
+		remaining := time.Until(deadline)
+		switch {
+		case wait < 0:
+			remaining = NamedLockPollInterval
+		case remaining <= 0:
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, vterrors.Wrapf(ctx.Err(), "gave up waiting for named lock %v", name)
+		case <-time.After(min(remaining, NamedLockPollInterval)):
+		}
+	}
+}
+
+func (ts *Server) tryAcquireNamedLock(ctx context.Context, name, owner string, ttl time.Duration) (*NamedLock, error) {
+	now := time.Now()
+	lock := &NamedLock{
+		Name:     name,
+		Owner:    owner,
+		Acquired: now,
+		Expires:  now.Add(ttl),
+	}
+
+	current, version, err := ts.getNamedLock(ctx, name)
+	switch {
+	case IsErrType(err, NoNode):
+		data, err := json.Marshal(lock)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := ts.globalCell.Create(ctx, namedLockFilePath(name), data); err != nil {
+			return nil, err
+		}
+		return lock, nil
+	case err != nil:
+		return nil, err
+	}
+
+	switch {
+	case current.Owner == owner && !current.expired(now):
+		lock.Acquired = current.Acquired
+	case !current.expired(now):
+		return nil, Error{
+			code:    NodeExists,
+			message: fmt.Sprintf("named lock %v is held by %v until %v", name, current.Owner, current.Expires.Format(time.RFC3339)),
+		}
+	}
+	if err := ts.updateNamedLock(ctx, lock, version); err != nil {
+		return nil, err
+	}
+	return lock, nil
+}
+
+// RenewNamedLock extends the named lock held by owner, until it expires
+// after ttl. It returns a NoNode error if owner does not hold the lock,
+// including if it expired.
+func (ts *Server) RenewNamedLock(ctx context.Context, name, owner string, ttl time.Duration) (*NamedLock, error) {
+	if err := checkNamedLockArgs(name, owner); err != nil {
+		return nil, err
+	}
+	if err := checkNamedLockTTL(ttl); err != nil {
+		return nil, err
+	}
+
+	lock, version, err := ts.getHeldNamedLock(ctx, name, owner)
+	if err != nil {
+		return nil, err
+	}
+	if lock.expired(time.Now()) {
+		return nil, namedLockNotHeldError(name, owner)
+	}
+	lock.Expires = time.Now().Add(ttl)
+	if err := ts.updateNamedLock(ctx, lock, version); err != nil {
+		if IsErrType(err, BadVersion) {
+			return nil, namedLockNotHeldError(name, owner)
+		}
+		return nil, err
+	}
+	return lock, nil
+}
+
+// ReleaseNamedLock releases the named lock held by owner. It returns a NoNode
+// error if owner does not hold the lock.
+func (ts *Server) ReleaseNamedLock(ctx context.Context, name, owner string) error {
+	if err := checkNamedLockArgs(name, owner); err != nil {
+		return err
+	}
+
+	_, version, err := ts.getHeldNamedLock(ctx, name, owner)
+	if err != nil {
+		return err
+	}
+	err = ts.globalCell.Delete(ctx, namedLockFilePath(name), version)
+	if IsErrType(err, NoNode) || IsErrType(err, BadVersion) {
+		return namedLockNotHeldError(name, owner)
+	}
+	return err
+}
+
+// getHeldNamedLock returns the named lock, or a NoNode error if it is not
+// held by owner.
+func (ts *Server) getHeldNamedLock(ctx context.Context, name, owner string) (*NamedLock, Version, error) {
+	lock, version, err := ts.getNamedLock(ctx, name)
+	switch {
+	case IsErrType(err, NoNode):
+		return nil, nil, namedLockNotHeldError(name, owner)
+	case err != nil:
+		return nil, nil, err
+	case lock.Owner != owner:
+		return nil, nil, namedLockNotHeldError(name, owner)
+	}
+	return lock, version, nil
+}
+
+func (ts *Server) getNamedLock(ctx context.Context, name string) (*NamedLock, Version, error) {
+	filePath := namedLockFilePath(name)
+	data, version, err := ts.globalCell.Get(ctx, filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	lock := &NamedLock{}
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, nil, vterrors.Wrapf(err, "bad named lock data for %v", name)
+	}
+	return lock, version, nil
+}
+
+func (ts *Server) updateNamedLock(ctx context.Context, lock *NamedLock, version Version) error {
+	data, err := json.Marshal(lock)
+	if err != nil {
+		return err
+	}
+	_, err = ts.globalCell.Update(ctx, namedLockFilePath(lock.Name), data, version)
+	return err
+}
+
+func checkNamedLockArgs(name, owner string) error {
+	switch {
+	case name == "":
+		return vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "named lock name cannot be empty")
+	case owner == "":
+		return vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "named lock owner cannot be empty")
+	}
+	return nil
+}
+
+func checkNamedLockTTL(ttl time.Duration) error {
+	if ttl <= 0 {
+		return vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "named lock TTL must be positive: %v", ttl)
+	}
+	return nil
+}
+
+func namedLockNotHeldError(name, owner string) error {
+	return Error{
+		code:    NoNode,
+		message: fmt.Sprintf("named lock %v is not held by %v", name, owner),
+	}
+}
+
+// namedLockFilePath returns the path of a named lock. The name is escaped,
+// so it can contain any character. PathEscape leaves "." and ".." alone,
+// and those would resolve to the named locks directory or its parent, so
+// their dots are escaped too.
+func namedLockFilePath(name string) string {
+	escaped := url.PathEscape(name)
+	if escaped == "." || escaped == ".." {
+		escaped = strings.ReplaceAll(escaped, ".", "%2E")
+	}
+	return path.Join(NamedLocksPath, escaped)
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package natstopo
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/nats-io/nats.go/jetstream"
+
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// ListDir is part of the topo.Conn interface.
+func (s *Server) ListDir(ctx context.Context, dirPath string, full bool) ([]topo.DirEntry, error) {
+	nodePath := path.Join(s.root, dirPath)
+	dirKey := s.nodeKey(dirPath)
+
+	entries, err := s.entries(ctx, keysUnder(dirKey), jetstream.MetaOnly())
+	if err != nil {
+		return nil, convertError(err, nodePath)
+	}
+	if len(entries) == 0 {
+		// No key starts with this prefix, means the directory
+		// doesn't exist.
+		return nil, topo.NewError(topo.NoNode, nodePath)
+	}
+
+	prefixLen := len(dirKey)
+	if prefixLen > 0 {
+		// Skip the dot after the prefix too.
+		prefixLen++
+	}
+	types := make(map[string]topo.DirEntryType)
+	for _, entry := range entries {
+		// Keep only the first token of the key under the directory.
+		key := entry.Key()[prefixLen:]
+		t := topo.TypeFile
+		if i := strings.Index(key, "."); i >= 0 {
+			key = key[:i]
+			t = topo.TypeDirectory
+		}
+		name, err := unescapeToken(key)
+		if err != nil {
+			return nil, err
+		}
+		types[name] = t
+	}
+
+	result := make([]topo.DirEntry, 0, len(types))
+	for name, t := range types {
+		e := topo.DirEntry{
+			Name: name,
+		}
+		if full {
+			e.Type = t
+		}
+		result = append(result, e)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// entries returns the current entries of the files bucket whose keys match
+// the given pattern. Deleted keys are skipped.
+func (s *Server) entries(ctx context.Context, keys string, opts ...jetstream.WatchOpt) ([]jetstream.KeyValueEntry, error) {
+	// Watching the keys sends their current values, followed by nil.
+	watcher, err := s.kv.Watch(ctx, keys, append(opts, jetstream.IgnoreDeletes())...)
+	if err != nil {
+		return nil, err
+	}
+	defer stopWatcher(watcher)
+
+	var entries []jetstream.KeyValueEntry
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case entry, ok := <-watcher.Updates():
+			if !ok {
+				return nil, topo.NewError(topo.Interrupted, keys)
+			}
+			if entry == nil {
+				return entries, nil
+			}
+			entries = append(entries, entry)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package natstopo
+
+import (
+	"context"
+	"errors"
+	"path"
+
+	"github.com/nats-io/nats.go/jetstream"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// NewLeaderParticipation is part of the topo.Server interface
+func (s *Server) NewLeaderParticipation(name, id string) (topo.LeaderParticipation, error) {
+	return &natsLeaderParticipation{
+		s:    s,
+		name: name,
+		id:   id,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}, nil
+}
+
+// natsLeaderParticipation implements topo.LeaderParticipation.
+//
+// The leader is the process holding the lock of the election path, in the
+// locks bucket. The contents of the lock are the id of the leader.
+type natsLeaderParticipation struct {
+	// s is our parent NATS topo Server
+	s *Server
+
+	// name is the name of this LeaderParticipation
+	name string
+
+	// id is the process's current id.
+	id string
+
+	// stop is a channel closed when Stop is called.
+	stop chan struct{}
+
+	// done is a channel closed when we're done processing the Stop
+	done chan struct{}
+}
+
+// WaitForLeadership is part of the topo.LeaderParticipation interface.
+func (mp *natsLeaderParticipation) WaitForLeadership() (context.Context, error) {
+	// If Stop was already called, mp.done is closed, so we are interrupted.
+	select {
+	case <-mp.done:
+		return nil, topo.NewError(topo.Interrupted, "Leadership")
+	default:
+	}
+
+	electionPath := path.Join(electionsPath, mp.name)
+
+	// We use a cancelable context here. If stop is closed, or if we
+	// lose the lock, we just cancel that context.
+	lockCtx, lockCancel := context.WithCancel(context.Background())
+	ldc := make(chan *natsLockDescriptor, 1)
+	go func() {
+		var ld *natsLockDescriptor
+		select {
+		case <-mp.s.running:
+			return
+		case <-mp.stop:
+		case ld = <-ldc:
+			// We are the leader, until Stop is called or the lock
+			// is lost.
+			select {
+			case <-mp.s.running:
+				return
+			case <-mp.stop:
+			case <-ld.lost:
+				lockCancel()
+				<-mp.stop
+			}
+		}
+		if ld != nil {
+			if err := ld.Unlock(context.Background()); err != nil {
+				log.Errorf("failed to unlock electionPath %v: %v", electionPath, err)
+			}
+		}
+		lockCancel()
+		close(mp.done)
+	}()
+
+	// Try to get the primaryship, by getting a lock.
+	ld, err := mp.s.lock(lockCtx, electionPath, mp.id)
+	if err != nil {
+		// It can be that we were interrupted.
+		return nil, err
+	}
+	ldc <- ld
+
+	// We got the lock. Return the lockContext. If Stop() is called,
+	// it will cancel the lockCtx, and cancel the returned context.
+	return lockCtx, nil
+}
+
+// Stop is part of the topo.LeaderParticipation interface
+func (mp *natsLeaderParticipation) Stop() {
+	close(mp.stop)
+	<-mp.done
+}
+
+// GetCurrentLeaderID is part of the topo.LeaderParticipation interface
+func (mp *natsLeaderParticipation) GetCurrentLeaderID(ctx context.Context) (string, error) {
+	key := mp.s.nodeKey(path.Join(electionsPath, mp.name, locksPath))
+
+	entry, err := mp.s.locks.Get(ctx, key)
+	if errors.Is(err, jetstream.ErrKeyNotFound) {
+		// Nobody is the primary.
+		return "", nil
+	}
+	if err != nil {
+		return "", convertError(err, key)
+	}
+	return string(entry.Value()), nil
+}
+
+// WaitForNewLeader is part of the topo.LeaderParticipation interface
+func (mp *natsLeaderParticipation) WaitForNewLeader(ctx context.Context) (<-chan string, error) {
+	key := mp.s.nodeKey(path.Join(electionsPath, mp.name, locksPath))
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	// The watcher sends the current leader, if any, then the updates.
+	watcher, err := mp.s.locks.Watch(ctx, key)
+	if err != nil {
+		cancel()
+		return nil, convertError(err, key)
+	}
+
+	notifications := make(chan string, 8)
+	go func() {
+		defer cancel()
+		defer close(notifications)
+		defer stopWatcher(watcher)
+
+		var leader string
+		for {
+			select {
+			case <-mp.s.running:
+				return
+			case <-mp.done:
+				return
+			case <-ctx.Done():
+				return
+			case entry, ok := <-watcher.Updates():
+				if !ok {
+					return
+				}
+				// The leader refreshes its lock, we only notify
+				// when it changes.
+				if entry == nil || entry.Operation() != jetstream.KeyValuePut || string(entry.Value()) == leader {
+					continue
+				}
+				leader = string(entry.Value())
+				notifications <- leader
+			}
+		}
+	}()
+
+	return notifications, nil
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package natstopo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"vitess.io/vitess/go/vt/topo"
+)
+
+func convertError(err error, nodePath string) error {
+	if err == nil {
+		return nil
+	}
+
+	switch {
+	case errors.Is(err, jetstream.ErrKeyNotFound):
+		return topo.NewError(topo.NoNode, nodePath)
+	case errors.Is(err, jetstream.ErrKeyExists):
+		return topo.NewError(topo.NodeExists, nodePath)
+	case errors.Is(err, context.Canceled):
+		return topo.NewError(topo.Interrupted, nodePath)
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, nats.ErrTimeout):
+		return topo.NewError(topo.Timeout, nodePath)
+	default:
+		return err
+	}
+}
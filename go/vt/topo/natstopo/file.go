@@ -0,0 +1,147 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package natstopo
+
+import (
+	"context"
+	"errors"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/nats-io/nats.go/jetstream"
+
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// Create is part of the topo.Conn interface.
+func (s *Server) Create(ctx context.Context, filePath string, contents []byte) (topo.Version, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	revision, err := s.kv.Create(ctx, s.nodeKey(filePath), contents)
+	if err != nil {
+		return nil, convertError(err, nodePath)
+	}
+	return NatsVersion(revision), nil
+}
+
+// Update is part of the topo.Conn interface.
+func (s *Server) Update(ctx context.Context, filePath string, contents []byte, version topo.Version) (topo.Version, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	if version != nil {
+		// The update is only accepted if the current revision of the
+		// key is the expected one.
+		revision, err := s.kv.Update(ctx, s.nodeKey(filePath), contents, uint64(version.(NatsVersion)))
+		if errors.Is(err, jetstream.ErrKeyExists) {
+			return nil, topo.NewError(topo.BadVersion, nodePath)
+		}
+		if err != nil {
+			return nil, convertError(err, nodePath)
+		}
+		return NatsVersion(revision), nil
+	}
+
+	// No version specified. We can use a simple unconditional Put.
+	revision, err := s.kv.Put(ctx, s.nodeKey(filePath), contents)
+	if err != nil {
+		return nil, convertError(err, nodePath)
+	}
+	return NatsVersion(revision), nil
+}
+
+// Get is part of the topo.Conn interface.
+func (s *Server) Get(ctx context.Context, filePath string) ([]byte, topo.Version, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	entry, err := s.kv.Get(ctx, s.nodeKey(filePath))
+	if err != nil {
+		return nil, nil, convertError(err, nodePath)
+	}
+	return entry.Value(), NatsVersion(entry.Revision()), nil
+}
+
+// List is part of the topo.Conn interface.
+func (s *Server) List(ctx context.Context, filePathPrefix string) ([]topo.KVInfo, error) {
+	nodePathPrefix := path.Join(s.root, filePathPrefix)
+
+	// The prefix may end in the middle of a path component, so we watch
+	// the parent directory, and filter the paths.
+	entries, err := s.entries(ctx, keysUnder(s.nodeKey(path.Dir(filePathPrefix))))
+	if err != nil {
+		return []topo.KVInfo{}, convertError(err, nodePathPrefix)
+	}
+
+	var results []topo.KVInfo
+	for _, entry := range entries {
+		p, err := keyPath(entry.Key())
+		if err != nil {
+			return []topo.KVInfo{}, err
+		}
+		if !strings.HasPrefix(p, nodePathPrefix) {
+			continue
+		}
+		results = append(results, topo.KVInfo{
+			Key:     []byte(p),
+			Value:   entry.Value(),
+			Version: NatsVersion(entry.Revision()),
+		})
+	}
+	if len(results) == 0 {
+		return []topo.KVInfo{}, topo.NewError(topo.NoNode, nodePathPrefix)
+	}
+	sort.Slice(results, func(i, j int) bool { return string(results[i].Key) < string(results[j].Key) })
+	return results, nil
+}
+
+// Delete is part of the topo.Conn interface.
+func (s *Server) Delete(ctx context.Context, filePath string, version topo.Version) error {
+	nodePath := path.Join(s.root, filePath)
+	key := s.nodeKey(filePath)
+
+	for {
+		var revision uint64
+		if version != nil {
+			revision = uint64(version.(NatsVersion))
+		} else {
+			// Deleting a key that doesn't exist would succeed, so we
+			// get its current revision first.
+			entry, err := s.kv.Get(ctx, key)
+			if err != nil {
+				return convertError(err, nodePath)
+			}
+			revision = entry.Revision()
+		}
+
+		// The deletion is only accepted if the current revision of the
+		// key is the expected one. If it isn't, we get the key, to know
+		// if it failed because it didn't exist, or because the version
+		// was wrong.
+		err := s.kv.Delete(ctx, key, jetstream.LastRevision(revision))
+		if !errors.Is(err, jetstream.ErrKeyExists) {
+			return convertError(err, nodePath)
+		}
+		if version == nil {
+			// The file was updated since we got it, try again.
+			continue
+		}
+		if _, err := s.kv.Get(ctx, key); err != nil {
+			return convertError(err, nodePath)
+		}
+		return topo.NewError(topo.BadVersion, nodePath)
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package natstopo
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// nodeKey returns the key of the node at the given path, relative to the
+// root of the server. The tokens of the key, separated by dots, are the
+// components of the path, with the characters that are not allowed in the
+// tokens escaped as =XX, XX being their hexadecimal value. This way, the
+// files under a directory can be watched with a wildcard.
+func (s *Server) nodeKey(nodePath string) string {
+	var tokens []string
+	for _, component := range strings.Split(path.Join(s.root, nodePath), "/") {
+		if component != "" {
+			tokens = append(tokens, escapeToken(component))
+		}
+	}
+	return strings.Join(tokens, ".")
+}
+
+// keysUnder returns the pattern matching the keys under the given key.
+func keysUnder(key string) string {
+	if key == "" {
+		return ">"
+	}
+	return key + ".>"
+}
+
+// keyPath returns the absolute path of the node with the given key.
+func keyPath(key string) (string, error) {
+	tokens := strings.Split(key, ".")
+	for i, token := range tokens {
+		component, err := unescapeToken(token)
+		if err != nil {
+			return "", err
+		}
+		tokens[i] = component
+	}
+	return "/" + strings.Join(tokens, "/"), nil
+}
+
+func escapeToken(component string) string {
+	var b strings.Builder
+	for i := 0; i < len(component); i++ {
+		c := component[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "=%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func unescapeToken(token string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(token); i++ {
+		if token[i] != '=' {
+			b.WriteByte(token[i])
+			continue
+		}
+		if i+2 >= len(token) {
+			return "", fmt.Errorf("bad escape sequence in key token %q", token)
+		}
+		c, err := strconv.ParseUint(token[i+1:i+3], 16, 8)
+		if err != nil {
+			return "", fmt.Errorf("bad escape sequence in key token %q", token)
+		}
+		b.WriteByte(byte(c))
+		i += 2
+	}
+	return b.String(), nil
+}
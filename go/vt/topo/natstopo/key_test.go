@@ -0,0 +1,57 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package natstopo
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeKey(t *testing.T) {
+	tcs := []struct {
+		root     string
+		nodePath string
+		key      string
+	}{
+		{"/vitess/global", "/keyspaces/ks/Keyspace", "vitess.global.keyspaces.ks.Keyspace"},
+		{"/vitess/zone1", "tablets/zone1-0000000100/Tablet", "vitess.zone1.tablets.zone1-0000000100.Tablet"},
+		{"/vitess/global", "/keyspaces/ks/shards/-80/Shard", "vitess.global.keyspaces.ks.shards.-80.Shard"},
+		{"/", "/MyFile", "MyFile"},
+		{"/", "/", ""},
+		{"/vitess/global", "/", "vitess.global"},
+		{"/vitess", "/a.b/c d/e=f/*/>", "vitess.a=2Eb.c=20d.e=3Df.=2A.=3E"},
+	}
+	for _, tc := range tcs {
+		s := &Server{root: tc.root}
+		key := s.nodeKey(tc.nodePath)
+		assert.Equal(t, tc.key, key, "nodeKey(%q) with root %q", tc.nodePath, tc.root)
+		if key == "" {
+			continue
+		}
+		p, err := keyPath(key)
+		require.NoError(t, err)
+		assert.Equal(t, path.Join(tc.root, tc.nodePath), p)
+	}
+
+	_, err := keyPath("a.b=2")
+	assert.ErrorContains(t, err, "bad escape sequence")
+	_, err = keyPath("a.b=ZZ")
+	assert.ErrorContains(t, err, "bad escape sequence")
+}
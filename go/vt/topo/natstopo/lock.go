@@ -0,0 +1,229 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package natstopo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// lockPollInterval is how often a process waiting for a lock tries to take
+// it again. The watchers of a key are not notified when it expires, so a
+// lock held by a process that died is only noticed this way.
+var lockPollInterval = 1 * time.Second
+
+// natsLockDescriptor implements topo.LockDescriptor.
+type natsLockDescriptor struct {
+	s        *Server
+	key      string
+	contents []byte
+
+	// cancel stops the refresh of the lock, and done is closed when it
+	// is stopped.
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	// lost is closed when the lock is lost.
+	lost chan struct{}
+
+	// mu protects revision, the current revision of the key of the lock.
+	mu       sync.Mutex
+	revision uint64
+}
+
+// Lock is part of the topo.Conn interface.
+func (s *Server) Lock(ctx context.Context, dirPath, contents string) (topo.LockDescriptor, error) {
+	// We list the directory first to make sure it exists.
+	if _, err := s.ListDir(ctx, dirPath, false /*full*/); err != nil {
+		// We need to return the right error codes, like
+		// topo.ErrNoNode and topo.ErrInterrupted, and the
+		// easiest way to do this is to return convertError(err).
+		// It may lose some of the context, if this is an issue,
+		// maybe logging the error would work here.
+		return nil, convertError(err, dirPath)
+	}
+
+	return s.lock(ctx, dirPath, contents)
+}
+
+// TryLock is part of the topo.Conn interface.
+func (s *Server) TryLock(ctx context.Context, dirPath, contents string) (topo.LockDescriptor, error) {
+	// We list the directory first to make sure it exists.
+	if _, err := s.ListDir(ctx, dirPath, false /*full*/); err != nil {
+		return nil, convertError(err, dirPath)
+	}
+
+	// Creating the key of the lock fails right away if someone else
+	// already has the lock.
+	key := s.nodeKey(path.Join(dirPath, locksPath))
+	revision, err := s.locks.Create(ctx, key, []byte(contents))
+	if errors.Is(err, jetstream.ErrKeyExists) {
+		return nil, topo.NewError(topo.NodeExists, fmt.Sprintf("lock already exists at path %s", dirPath))
+	}
+	if err != nil {
+		return nil, convertError(err, dirPath)
+	}
+	return s.newLockDescriptor(key, []byte(contents), revision), nil
+}
+
+// lock is used by both Lock() and primary election.
+func (s *Server) lock(ctx context.Context, nodePath, contents string) (*natsLockDescriptor, error) {
+	key := s.nodeKey(path.Join(nodePath, locksPath))
+
+	for {
+		// Watch the key before trying to create it, so we don't miss
+		// its deletion.
+		watcher, err := s.locks.Watch(ctx, key, jetstream.UpdatesOnly())
+		if err != nil {
+			return nil, convertError(err, nodePath)
+		}
+		revision, err := s.locks.Create(ctx, key, []byte(contents))
+		if err == nil {
+			stopWatcher(watcher)
+			return s.newLockDescriptor(key, []byte(contents), revision), nil
+		}
+		if !errors.Is(err, jetstream.ErrKeyExists) {
+			stopWatcher(watcher)
+			return nil, convertError(err, nodePath)
+		}
+
+		// Someone else has the lock, wait until it is released or
+		// it expires.
+		err = waitForRelease(ctx, watcher)
+		stopWatcher(watcher)
+		if err != nil {
+			return nil, convertError(err, nodePath)
+		}
+	}
+}
+
+// waitForRelease waits until the watched lock key is deleted, or for
+// lockPollInterval.
+func waitForRelease(ctx context.Context, watcher jetstream.KeyWatcher) error {
+	timer := time.NewTimer(lockPollInterval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		case entry, ok := <-watcher.Updates():
+			if !ok || (entry != nil && entry.Operation() != jetstream.KeyValuePut) {
+				return nil
+			}
+		}
+	}
+}
+
+// newLockDescriptor returns the descriptor of a lock that was just taken,
+// and starts refreshing it until it is unlocked.
+func (s *Server) newLockDescriptor(key string, contents []byte, revision uint64) *natsLockDescriptor {
+	ctx, cancel := context.WithCancel(context.Background())
+	ld := &natsLockDescriptor{
+		s:        s,
+		key:      key,
+		contents: contents,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		lost:     make(chan struct{}),
+		revision: revision,
+	}
+	go ld.refresh(ctx)
+	return ld
+}
+
+// refresh updates the key of the lock before it expires, until ctx is
+// canceled or the lock is lost.
+func (ld *natsLockDescriptor) refresh(ctx context.Context) {
+	defer close(ld.done)
+
+	ticker := time.NewTicker(ld.s.lockTTL / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ld.s.running:
+			return
+		case <-ticker.C:
+		}
+
+		ld.mu.Lock()
+		updateCtx, cancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+		revision, err := ld.s.locks.Update(updateCtx, ld.key, ld.contents, ld.revision)
+		cancel()
+		if err == nil {
+			ld.revision = revision
+		}
+		ld.mu.Unlock()
+
+		switch {
+		case err == nil:
+		case errors.Is(err, jetstream.ErrKeyExists):
+			// The key was deleted or expired since we last
+			// refreshed it.
+			log.Errorf("Lock %v was lost", ld.key)
+			close(ld.lost)
+			return
+		case ctx.Err() == nil:
+			log.Warningf("Failed to refresh lock %v, will retry: %v", ld.key, err)
+		}
+	}
+}
+
+// Check is part of the topo.LockDescriptor interface.
+// We make sure the key of the lock still has the revision we last
+// wrote.
+func (ld *natsLockDescriptor) Check(ctx context.Context) error {
+	ld.mu.Lock()
+	defer ld.mu.Unlock()
+
+	entry, err := ld.s.locks.Get(ctx, ld.key)
+	if err != nil {
+		return convertError(err, ld.key)
+	}
+	if entry.Revision() != ld.revision {
+		return topo.NewError(topo.NoNode, ld.key)
+	}
+	return nil
+}
+
+// Unlock is part of the topo.LockDescriptor interface.
+func (ld *natsLockDescriptor) Unlock(ctx context.Context) error {
+	ld.cancel()
+	<-ld.done
+
+	ld.mu.Lock()
+	defer ld.mu.Unlock()
+
+	// The key is only deleted if we still have the lock.
+	err := ld.s.locks.Delete(ctx, ld.key, jetstream.LastRevision(ld.revision))
+	if errors.Is(err, jetstream.ErrKeyExists) {
+		return topo.NewError(topo.NoNode, ld.key)
+	}
+	return convertError(err, ld.key)
+}
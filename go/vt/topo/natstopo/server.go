@@ -0,0 +1,211 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package natstopo implements topo.Server with NATS JetStream key/value
+buckets as the backend.
+
+The files are stored in one bucket, shared by all the cells, and the
+path of a file, including the root of its cell, is mapped to a key whose
+tokens are the components of the path (see nodeKey). Directories are not
+stored, they exist as long as there are files under them, like with etcd.
+
+Locks and elections are stored in a second bucket, whose keys expire
+after the lock TTL if they are not refreshed, so the locks of a process
+that died are eventually released. The keys of this bucket are not
+listed by ListDir.
+
+The buckets are created if they don't exist, with a single revision per
+key. Note the maximum size of a file is the maximum payload of the NATS
+server, 1MB by default.
+
+We follow these conventions within this package:
+
+  - Call convertError(err) on any errors returned from the NATS client
+    library. Functions defined in this package can be assumed to have
+    already converted errors as necessary.
+*/
+package natstopo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/topo"
+)
+
+var (
+	bucket          = "vitess"
+	bucketReplicas  = 1
+	lockTTL         = 30 * time.Second
+	credentialsPath string
+	clientCertPath  string
+	clientKeyPath   string
+	serverCaPath    string
+)
+
+func init() {
+	servenv.RegisterFlagsForTopoBinaries(registerNatsTopoFlags)
+	topo.RegisterFactory("nats", Factory{})
+}
+
+func registerNatsTopoFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&bucket, "topo_nats_bucket", bucket, "Name of the NATS JetStream key/value bucket storing the topo files. Locks and elections are stored in the <bucket>_locks bucket.")
+	fs.IntVar(&bucketReplicas, "topo_nats_replicas", bucketReplicas, "Number of replicas of the NATS JetStream key/value buckets, when they are created.")
+	fs.DurationVar(&lockTTL, "topo_nats_lock_ttl", lockTTL, "TTL of the locks and leader elections, when the locks bucket is created. The locks are refreshed while they are held.")
+	fs.StringVar(&credentialsPath, "topo_nats_credentials", credentialsPath, "path to the NATS user credentials file to use to connect to the NATS topo server")
+	fs.StringVar(&clientCertPath, "topo_nats_tls_cert", clientCertPath, "path to the client cert to use to connect to the NATS topo server, requires topo_nats_tls_key, enables TLS")
+	fs.StringVar(&clientKeyPath, "topo_nats_tls_key", clientKeyPath, "path to the client key to use to connect to the NATS topo server, enables TLS")
+	fs.StringVar(&serverCaPath, "topo_nats_tls_ca", serverCaPath, "path to the ca to use to validate the server cert when connecting to the NATS topo server")
+}
+
+// Factory is the NATS topo.Factory implementation.
+type Factory struct{}
+
+// HasGlobalReadOnlyCell is part of the topo.Factory interface.
+func (f Factory) HasGlobalReadOnlyCell(serverAddr, root string) bool {
+	return false
+}
+
+// Create is part of the topo.Factory interface.
+func (f Factory) Create(cell, serverAddr, root string) (topo.Conn, error) {
+	return NewServer(serverAddr, root)
+}
+
+// Server is the implementation of topo.Server for NATS.
+type Server struct {
+	nc *nats.Conn
+
+	// kv is the bucket of the files.
+	kv jetstream.KeyValue
+
+	// locks is the bucket of the locks and elections.
+	locks jetstream.KeyValue
+
+	// lockTTL is how long the keys of the locks bucket live without
+	// being refreshed.
+	lockTTL time.Duration
+
+	// root is the root path for this client.
+	root string
+
+	running chan struct{}
+}
+
+// NewServer returns a new natstopo.Server. serverAddr is a comma-separated
+// list of NATS server URLs.
+func NewServer(serverAddr, root string) (*Server, error) {
+	opts := []nats.Option{
+		nats.Name("vitess"),
+		nats.MaxReconnects(-1),
+	}
+	if credentialsPath != "" {
+		opts = append(opts, nats.UserCredentials(credentialsPath))
+	}
+	if clientCertPath != "" && clientKeyPath != "" {
+		opts = append(opts, nats.ClientCert(clientCertPath, clientKeyPath))
+	}
+	if serverCaPath != "" {
+		opts = append(opts, nats.RootCAs(serverCaPath))
+	}
+
+	nc, err := nats.Connect(serverAddr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		nc:      nc,
+		root:    root,
+		running: make(chan struct{}),
+	}
+	if err := s.openBuckets(); err != nil {
+		nc.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// openBuckets opens the buckets, and creates them if they don't exist.
+func (s *Server) openBuckets() error {
+	ctx, cancel := context.WithTimeout(context.Background(), topo.RemoteOperationTimeout)
+	defer cancel()
+
+	js, err := jetstream.New(s.nc)
+	if err != nil {
+		return err
+	}
+	s.kv, err = openBucket(ctx, js, jetstream.KeyValueConfig{
+		Bucket:      bucket,
+		Description: "Vitess topo",
+		History:     1,
+		Storage:     jetstream.FileStorage,
+		Replicas:    bucketReplicas,
+	})
+	if err != nil {
+		return err
+	}
+	s.locks, err = openBucket(ctx, js, jetstream.KeyValueConfig{
+		Bucket:      bucket + "_locks",
+		Description: "Vitess topo locks and elections",
+		History:     1,
+		TTL:         lockTTL,
+		Storage:     jetstream.FileStorage,
+		Replicas:    bucketReplicas,
+	})
+	if err != nil {
+		return err
+	}
+
+	// The bucket may have been created with another TTL.
+	status, err := s.locks.Status(ctx)
+	if err != nil {
+		return convertError(err, s.locks.Bucket())
+	}
+	s.lockTTL = status.TTL()
+	if s.lockTTL == 0 {
+		log.Warningf("NATS bucket %v has no TTL, the locks of the processes that die will never be released", s.locks.Bucket())
+		s.lockTTL = lockTTL
+	}
+	return nil
+}
+
+func openBucket(ctx context.Context, js jetstream.JetStream, cfg jetstream.KeyValueConfig) (jetstream.KeyValue, error) {
+	kv, err := js.KeyValue(ctx, cfg.Bucket)
+	if errors.Is(err, jetstream.ErrBucketNotFound) {
+		log.Infof("Creating NATS bucket %v", cfg.Bucket)
+		kv, err = js.CreateKeyValue(ctx, cfg)
+	}
+	if err != nil {
+		return nil, convertError(err, cfg.Bucket)
+	}
+	return kv, nil
+}
+
+// Close implements topo.Server.Close.
+// It will nil out the connection, so any attempt to
+// re-use this server will panic.
+func (s *Server) Close() {
+	close(s.running)
+	s.nc.Close()
+	s.nc = nil
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package natstopo
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/testfiles"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/test"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// startNats starts a nats-server subprocess with JetStream enabled, and
+// waits for it to be ready.
+func startNats(t *testing.T) string {
+	// Create a temporary directory.
+	dataDir := t.TempDir()
+
+	port := testfiles.GoVtTopoNatstopoPort
+	serverAddr := fmt.Sprintf("nats://localhost:%v", port)
+
+	cmd := exec.Command("nats-server",
+		"-p", fmt.Sprintf("%v", port),
+		"-js",
+		"-sd", dataDir)
+	err := cmd.Start()
+	if err != nil {
+		t.Fatalf("failed to start nats-server: %v", err)
+	}
+	t.Cleanup(func() {
+		// log error
+		if err := cmd.Process.Kill(); err != nil {
+			log.Errorf("cmd.Process.Kill() failed : %v", err)
+		}
+		// log error
+		if err := cmd.Wait(); err != nil {
+			log.Errorf("cmd.wait() failed : %v", err)
+		}
+	})
+
+	// Wait until we can connect, or timeout.
+	start := time.Now()
+	for {
+		nc, err := nats.Connect(serverAddr)
+		if err == nil {
+			nc.Close()
+			break
+		}
+		if time.Since(start) > 10*time.Second {
+			t.Fatalf("Failed to start nats-server in time: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	return serverAddr
+}
+
+func TestNatsTopo(t *testing.T) {
+	// Start a single nats-server in the background.
+	serverAddr := startNats(t)
+
+	testIndex := 0
+	newServer := func() *topo.Server {
+		// Each test will use its own sub-directories.
+		testRoot := fmt.Sprintf("/test-%v", testIndex)
+		testIndex++
+
+		// Create the server on the new root.
+		ts, err := topo.OpenServer("nats", serverAddr, path.Join(testRoot, topo.GlobalCell))
+		if err != nil {
+			t.Fatalf("OpenServer() failed: %v", err)
+		}
+
+		// Create the CellInfo.
+		if err := ts.CreateCellInfo(context.Background(), test.LocalCellName, &topodatapb.CellInfo{
+			ServerAddress: serverAddr,
+			Root:          path.Join(testRoot, test.LocalCellName),
+		}); err != nil {
+			t.Fatalf("CreateCellInfo() failed: %v", err)
+		}
+
+		return ts
+	}
+
+	// Run the TopoServerTestSuite tests.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	test.TopoServerTestSuite(t, ctx, func() *topo.Server {
+		return newServer()
+	}, []string{})
+}
+
+// TestNatsTopoLockExpiry tests the locks are refreshed while they are held,
+// and expire when the process holding them stops refreshing them.
+func TestNatsTopoLockExpiry(t *testing.T) {
+	serverAddr := startNats(t)
+
+	defer func(oldBucket string, oldLockTTL, oldLockPollInterval time.Duration) {
+		bucket, lockTTL, lockPollInterval = oldBucket, oldLockTTL, oldLockPollInterval
+	}(bucket, lockTTL, lockPollInterval)
+	bucket = "lock_expiry"
+	lockTTL = 1 * time.Second
+	lockPollInterval = 100 * time.Millisecond
+
+	ctx := context.Background()
+	s, err := NewServer(serverAddr, "/test")
+	require.NoError(t, err)
+	defer s.Close()
+	_, err = s.Create(ctx, "/keyspaces/ks/Keyspace", []byte{})
+	require.NoError(t, err)
+
+	// The lock does not expire while it is held.
+	ld, err := s.Lock(ctx, "/keyspaces/ks", "held")
+	require.NoError(t, err)
+	time.Sleep(2 * lockTTL)
+	assert.NoError(t, ld.Check(ctx))
+	_, err = s.TryLock(ctx, "/keyspaces/ks", "again")
+	assert.True(t, topo.IsErrType(err, topo.NodeExists), "expected NodeExists, got %v", err)
+
+	// Until the process holding it stops refreshing it.
+	natsLD := ld.(*natsLockDescriptor)
+	natsLD.cancel()
+	<-natsLD.done
+	lockCtx, cancel := context.WithTimeout(ctx, 10*lockTTL)
+	defer cancel()
+	ld2, err := s.Lock(lockCtx, "/keyspaces/ks", "after expiry")
+	require.NoError(t, err)
+
+	assert.True(t, topo.IsErrType(ld.Check(ctx), topo.NoNode))
+	assert.True(t, topo.IsErrType(ld.Unlock(ctx), topo.NoNode))
+	assert.NoError(t, ld2.Unlock(ctx))
+}
@@ -0,0 +1,30 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package natstopo
+
+import (
+	"strconv"
+)
+
+// NatsVersion is the revision of a key in a NATS bucket.
+// It implements topo.Version.
+type NatsVersion uint64
+
+// String is part of the topo.Version interface.
+func (v NatsVersion) String() string {
+	return strconv.FormatUint(uint64(v), 10)
+}
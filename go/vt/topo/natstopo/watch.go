@@ -0,0 +1,239 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package natstopo
+
+import (
+	"context"
+	"path"
+
+	"github.com/nats-io/nats.go/jetstream"
+
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// Watch is part of the topo.Conn interface.
+func (s *Server) Watch(ctx context.Context, filePath string) (*topo.WatchData, <-chan *topo.WatchData, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	// Create a context, will be used to cancel the watch when we return.
+	watchCtx, watchCancel := context.WithCancel(ctx)
+
+	// The watcher sends the current value of the key, if it exists,
+	// followed by nil, then the updates.
+	watcher, err := s.kv.Watch(watchCtx, s.nodeKey(filePath))
+	if err != nil {
+		watchCancel()
+		return nil, nil, convertError(err, nodePath)
+	}
+	var initial jetstream.KeyValueEntry
+	for done := false; !done; {
+		select {
+		case <-watchCtx.Done():
+			stopWatcher(watcher)
+			watchCancel()
+			return nil, nil, convertError(watchCtx.Err(), nodePath)
+		case entry, ok := <-watcher.Updates():
+			if !ok {
+				watchCancel()
+				return nil, nil, topo.NewError(topo.Interrupted, nodePath)
+			}
+			if entry == nil {
+				done = true
+				break
+			}
+			initial = entry
+		}
+	}
+	if initial == nil || initial.Operation() != jetstream.KeyValuePut {
+		// Node doesn't exist.
+		stopWatcher(watcher)
+		watchCancel()
+		return nil, nil, topo.NewError(topo.NoNode, nodePath)
+	}
+	wd := &topo.WatchData{
+		Contents: initial.Value(),
+		Version:  NatsVersion(initial.Revision()),
+	}
+
+	// Create the notifications channel, send updates to it.
+	notifications := make(chan *topo.WatchData, 10)
+	go func() {
+		defer close(notifications)
+		defer watchCancel()
+		defer stopWatcher(watcher)
+
+		for {
+			select {
+			case <-s.running:
+				return
+			case <-watchCtx.Done():
+				// This includes context cancellation errors.
+				notifications <- &topo.WatchData{
+					Err: convertError(watchCtx.Err(), nodePath),
+				}
+				return
+			case entry, ok := <-watcher.Updates():
+				if !ok {
+					notifications <- &topo.WatchData{
+						Err: vterrors.Errorf(vtrpc.Code_UNAVAILABLE, "watch on %v stopped", nodePath),
+					}
+					return
+				}
+				if entry == nil {
+					continue
+				}
+
+				switch entry.Operation() {
+				case jetstream.KeyValuePut:
+					notifications <- &topo.WatchData{
+						Contents: entry.Value(),
+						Version:  NatsVersion(entry.Revision()),
+					}
+				default:
+					// Node is gone, send a final notice.
+					notifications <- &topo.WatchData{
+						Err: topo.NewError(topo.NoNode, nodePath),
+					}
+					return
+				}
+			}
+		}
+	}()
+
+	return wd, notifications, nil
+}
+
+// WatchRecursive is part of the topo.Conn interface.
+func (s *Server) WatchRecursive(ctx context.Context, dirpath string) ([]*topo.WatchDataRecursive, <-chan *topo.WatchDataRecursive, error) {
+	nodePath := path.Join(s.root, dirpath)
+
+	// Create a context, will be used to cancel the watch when we return.
+	watchCtx, watchCancel := context.WithCancel(ctx)
+
+	// The watcher sends the current values of the keys, followed by nil,
+	// then the updates.
+	watcher, err := s.kv.Watch(watchCtx, keysUnder(s.nodeKey(dirpath)))
+	if err != nil {
+		watchCancel()
+		return nil, nil, convertError(err, nodePath)
+	}
+	var initialwd []*topo.WatchDataRecursive
+	for done := false; !done; {
+		select {
+		case <-watchCtx.Done():
+			stopWatcher(watcher)
+			watchCancel()
+			return nil, nil, convertError(watchCtx.Err(), nodePath)
+		case entry, ok := <-watcher.Updates():
+			if !ok {
+				watchCancel()
+				return nil, nil, topo.NewError(topo.Interrupted, nodePath)
+			}
+			if entry == nil {
+				done = true
+				break
+			}
+			if entry.Operation() != jetstream.KeyValuePut {
+				// Deleted file.
+				continue
+			}
+			wd, err := watchDataRecursive(entry)
+			if err != nil {
+				stopWatcher(watcher)
+				watchCancel()
+				return nil, nil, err
+			}
+			initialwd = append(initialwd, wd)
+		}
+	}
+
+	// Create the notifications channel, send updates to it.
+	notifications := make(chan *topo.WatchDataRecursive, 10)
+	go func() {
+		defer close(notifications)
+		defer watchCancel()
+		defer stopWatcher(watcher)
+
+		for {
+			select {
+			case <-s.running:
+				return
+			case <-watchCtx.Done():
+				// This includes context cancellation errors.
+				notifications <- &topo.WatchDataRecursive{
+					WatchData: topo.WatchData{Err: convertError(watchCtx.Err(), nodePath)},
+				}
+				return
+			case entry, ok := <-watcher.Updates():
+				if !ok {
+					notifications <- &topo.WatchDataRecursive{
+						WatchData: topo.WatchData{Err: vterrors.Errorf(vtrpc.Code_UNAVAILABLE, "watch on %v stopped", nodePath)},
+					}
+					return
+				}
+				if entry == nil {
+					continue
+				}
+				wd, err := watchDataRecursive(entry)
+				if err != nil {
+					notifications <- &topo.WatchDataRecursive{
+						WatchData: topo.WatchData{Err: err},
+					}
+					return
+				}
+				notifications <- wd
+			}
+		}
+	}()
+
+	return initialwd, notifications, nil
+}
+
+// watchDataRecursive returns the notification of a watched entry.
+func watchDataRecursive(entry jetstream.KeyValueEntry) (*topo.WatchDataRecursive, error) {
+	p, err := keyPath(entry.Key())
+	if err != nil {
+		return nil, err
+	}
+	if entry.Operation() != jetstream.KeyValuePut {
+		return &topo.WatchDataRecursive{
+			Path: p,
+			WatchData: topo.WatchData{
+				Err: topo.NewError(topo.NoNode, p),
+			},
+		}, nil
+	}
+	return &topo.WatchDataRecursive{
+		Path: p,
+		WatchData: topo.WatchData{
+			Contents: entry.Value(),
+			Version:  NatsVersion(entry.Revision()),
+		},
+	}, nil
+}
+
+// stopWatcher stops a watcher, and drains its updates channel so the
+// subscription can finish delivering the pending entries.
+func stopWatcher(watcher jetstream.KeyWatcher) {
+	_ = watcher.Stop()
+	go func() {
+		for range watcher.Updates() {
+		}
+	}()
+}
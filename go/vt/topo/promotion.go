@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// This file contains the records used to fail over the global topo to a
+// standby topo cluster, which is kept up to date by a mirror (see
+// helpers.TopoMirror). They are stored as JSON at the root of the standby
+// global topo, and are never mirrored.
+
+// MirrorStatus is the status of the mirror keeping a standby global topo up
+// to date.
+type MirrorStatus struct {
+	// Source is the address and root of the mirrored global topo.
+	Source string `json:"source"`
+
+	// LastSync is when the standby was last in sync with the source.
+	LastSync time.Time `json:"last_sync"`
+
+	// Files is the number of files mirrored.
+	Files int `json:"files"`
+}
+
+// Promotion records that a standby global topo was promoted to replace the
+// primary global topo. Once it exists, the mirror stops writing to the
+// standby, and the processes configured with the standby switch to it.
+type Promotion struct {
+	// PromotedAt is when the standby was promoted.
+	PromotedAt time.Time `json:"promoted_at"`
+
+	// MirrorStatus is the status of the mirror when the standby was
+	// promoted, if any.
+	MirrorStatus *MirrorStatus `json:"mirror_status,omitempty"`
+}
+
+// GetMirrorStatus returns the status of the mirror writing to this global
+// topo, or a NoNode error if there is none.
+func (ts *Server) GetMirrorStatus(ctx context.Context) (*MirrorStatus, error) {
+	status := &MirrorStatus{}
+	if err := getJSONFile(ctx, ts.globalCell, MirrorStatusFile, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// SaveMirrorStatus saves the status of the mirror writing to this global
+// topo.
+func (ts *Server) SaveMirrorStatus(ctx context.Context, status *MirrorStatus) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	_, err = ts.globalCell.Update(ctx, MirrorStatusFile, data, nil)
+	return err
+}
+
+// GetPromotion returns the promotion of this global topo, or a NoNode error
+// if it was not promoted.
+func (ts *Server) GetPromotion(ctx context.Context) (*Promotion, error) {
+	return getPromotion(ctx, ts.globalCell)
+}
+
+// Promote marks this global topo as promoted, which makes the processes
+// using it as their standby global topo switch to it. It returns a
+// NodeExists error if it was already promoted.
+func (ts *Server) Promote(ctx context.Context) (*Promotion, error) {
+	promotion := &Promotion{
+		PromotedAt: time.Now().UTC(),
+	}
+	status, err := ts.GetMirrorStatus(ctx)
+	switch {
+	case err == nil:
+		promotion.MirrorStatus = status
+	case IsErrType(err, NoNode):
+		// The standby was not populated by a mirror.
+	default:
+		return nil, err
+	}
+
+	data, err := json.Marshal(promotion)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ts.globalCell.Create(ctx, PromotionFile, data); err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+func getPromotion(ctx context.Context, conn Conn) (*Promotion, error) {
+	promotion := &Promotion{}
+	if err := getJSONFile(ctx, conn, PromotionFile, promotion); err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+func getJSONFile(ctx context.Context, conn Conn, filePath string, v any) error {
+	data, _, err := conn.Get(ctx, filePath)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return vterrors.Wrapf(err, "bad %v data", filePath)
+	}
+	return nil
+}
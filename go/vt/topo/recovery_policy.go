@@ -0,0 +1,190 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil/promotionrule"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// This file contains the recovery policies, which configure the recoveries
+// VTOrc runs in a keyspace or in a shard. They are stored as JSON in the
+// global topo, under recovery_policies/<keyspace> for a keyspace and
+// recovery_policies/<keyspace>/<shard> for a shard, independently of the
+// keyspace and shard records, and VTOrc reloads them periodically.
+
+// RecoveryPolicy configures the recoveries of VTOrc in a keyspace or in a
+// shard. The fields that are not set use the policy of the keyspace, for a
+// shard, and then the flags of VTOrc.
+type RecoveryPolicy struct {
+	// AllowedRecoveries are the names of the recoveries VTOrc may run, like
+	// RecoverDeadPrimary or FixReplica. All of them are allowed if empty.
+	AllowedRecoveries []string `json:"allowed_recoveries,omitempty"`
+
+	// PromotionRules override the promotion rules of the durability policy
+	// of the keyspace when a new primary is chosen, by tablet alias, like
+	// zone1-0000000100, or by cell. The rules are prefer, neutral,
+	// prefer_not and must_not.
+	PromotionRules map[string]string `json:"promotion_rules,omitempty"`
+
+	// PromotionCells are the only cells whose tablets can be promoted, if
+	// set.
+	PromotionCells []string `json:"promotion_cells,omitempty"`
+
+	// PreferredCells are the cells whose tablets VTOrc prefers to promote,
+	// from the most preferred one, when it scores the candidates of an
+	// emergency reparent.
+	PreferredCells []string `json:"preferred_cells,omitempty"`
+
+	// CooldownSeconds is how long another recovery is blocked after a
+	// recovery of the shard. It overrides the recovery period block
+	// duration of VTOrc if set.
+	CooldownSeconds int `json:"cooldown_seconds,omitempty"`
+}
+
+// Validate checks the promotion rules and the cooldown of the policy.
+func (p *RecoveryPolicy) Validate() error {
+	for key, rule := range p.PromotionRules {
+		if _, err := promotionrule.Parse(rule); err != nil {
+			return vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "bad promotion rule for %v: %v", key, err)
+		}
+	}
+	if p.CooldownSeconds < 0 {
+		return vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "negative cooldown: %v", p.CooldownSeconds)
+	}
+	return nil
+}
+
+// IsRecoveryAllowed returns whether the policy allows VTOrc to run the given
+// recovery.
+func (p *RecoveryPolicy) IsRecoveryAllowed(recoveryName string) bool {
+	if p == nil || len(p.AllowedRecoveries) == 0 {
+		return true
+	}
+	for _, name := range p.AllowedRecoveries {
+		if name == recoveryName {
+			return true
+		}
+	}
+	return false
+}
+
+// MergeRecoveryPolicies returns the policy of a shard, which is the policy
+// of the shard with its unset fields taken from the policy of its keyspace.
+// Either of them can be nil, and nil is returned if both are.
+func MergeRecoveryPolicies(keyspacePolicy, shardPolicy *RecoveryPolicy) *RecoveryPolicy {
+	switch {
+	case keyspacePolicy == nil:
+		return shardPolicy
+	case shardPolicy == nil:
+		return keyspacePolicy
+	}
+
+	merged := *shardPolicy
+	if len(merged.AllowedRecoveries) == 0 {
+		merged.AllowedRecoveries = keyspacePolicy.AllowedRecoveries
+	}
+	if len(merged.PromotionRules) == 0 {
+		merged.PromotionRules = keyspacePolicy.PromotionRules
+	}
+	if len(merged.PromotionCells) == 0 {
+		merged.PromotionCells = keyspacePolicy.PromotionCells
+	}
+	if len(merged.PreferredCells) == 0 {
+		merged.PreferredCells = keyspacePolicy.PreferredCells
+	}
+	if merged.CooldownSeconds == 0 {
+		merged.CooldownSeconds = keyspacePolicy.CooldownSeconds
+	}
+	return &merged
+}
+
+// GetRecoveryPolicy returns the recovery policy of a shard, or of the
+// keyspace if shard is empty. It returns a NoNode error if there is none.
+// The policy of the keyspace is not merged into the policy of a shard.
+func (ts *Server) GetRecoveryPolicy(ctx context.Context, keyspace, shard string) (*RecoveryPolicy, error) {
+	policy := &RecoveryPolicy{}
+	if err := getJSONFile(ctx, ts.globalCell, recoveryPolicyFilePath(keyspace, shard), policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// GetRecoveryPolicies returns the recovery policies of a keyspace, by shard,
+// with the policy of the keyspace itself under the empty shard name.
+func (ts *Server) GetRecoveryPolicies(ctx context.Context, keyspace string) (map[string]*RecoveryPolicy, error) {
+	policies := make(map[string]*RecoveryPolicy)
+	entries, err := ts.globalCell.ListDir(ctx, path.Join(RecoveryPoliciesPath, keyspace), false /*full*/)
+	switch {
+	case IsErrType(err, NoNode):
+		return policies, nil
+	case err != nil:
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		shard := entry.Name
+		if shard == RecoveryPolicyFile {
+			shard = ""
+		}
+		policy, err := ts.GetRecoveryPolicy(ctx, keyspace, shard)
+		switch {
+		case IsErrType(err, NoNode):
+			// A directory left behind by a deleted policy.
+			continue
+		case err != nil:
+			return nil, err
+		}
+		policies[shard] = policy
+	}
+	return policies, nil
+}
+
+// SaveRecoveryPolicy saves the recovery policy of a shard, or of the
+// keyspace if shard is empty.
+func (ts *Server) SaveRecoveryPolicy(ctx context.Context, keyspace, shard string, policy *RecoveryPolicy) error {
+	if keyspace == "" {
+		return vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "a recovery policy needs a keyspace")
+	}
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(policy, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = ts.globalCell.Update(ctx, recoveryPolicyFilePath(keyspace, shard), data, nil)
+	return err
+}
+
+// DeleteRecoveryPolicy deletes the recovery policy of a shard, or of the
+// keyspace if shard is empty.
+func (ts *Server) DeleteRecoveryPolicy(ctx context.Context, keyspace, shard string) error {
+	return ts.globalCell.Delete(ctx, recoveryPolicyFilePath(keyspace, shard), nil)
+}
+
+func recoveryPolicyFilePath(keyspace, shard string) string {
+	if shard == "" {
+		return path.Join(RecoveryPoliciesPath, keyspace, RecoveryPolicyFile)
+	}
+	return path.Join(RecoveryPoliciesPath, keyspace, shard, RecoveryPolicyFile)
+}
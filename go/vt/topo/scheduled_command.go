@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"context"
+	"path"
+	"sort"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// ScheduledCommandInfo is a scheduled vtctl command, along with the version of
+// its topo record.
+type ScheduledCommandInfo struct {
+	version Version
+	*vtctldatapb.ScheduledCommand
+}
+
+func scheduledCommandPath(id string) string {
+	return path.Join(ScheduledCommandsPath, id)
+}
+
+// CreateScheduledCommand creates the topo record of a scheduled command.
+func (ts *Server) CreateScheduledCommand(ctx context.Context, cmd *vtctldatapb.ScheduledCommand) error {
+	data, err := cmd.MarshalVT()
+	if err != nil {
+		return err
+	}
+
+	_, err = ts.globalCell.Create(ctx, scheduledCommandPath(cmd.Id), data)
+	return err
+}
+
+// GetScheduledCommand returns the scheduled command with the given id.
+func (ts *Server) GetScheduledCommand(ctx context.Context, id string) (*ScheduledCommandInfo, error) {
+	data, version, err := ts.globalCell.Get(ctx, scheduledCommandPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := &vtctldatapb.ScheduledCommand{}
+	if err := cmd.UnmarshalVT(data); err != nil {
+		return nil, vterrors.Wrapf(err, "bad scheduled command data for %s", id)
+	}
+
+	return &ScheduledCommandInfo{
+		version:          version,
+		ScheduledCommand: cmd,
+	}, nil
+}
+
+// GetScheduledCommands returns all the scheduled commands, in the order they
+// are to run.
+func (ts *Server) GetScheduledCommands(ctx context.Context) ([]*ScheduledCommandInfo, error) {
+	entries, err := ts.globalCell.ListDir(ctx, ScheduledCommandsPath, false /* full */)
+	switch {
+	case IsErrType(err, NoNode):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	cmds := make([]*ScheduledCommandInfo, 0, len(entries))
+	for _, entry := range entries {
+		cmd, err := ts.GetScheduledCommand(ctx, entry.Name)
+		switch {
+		case IsErrType(err, NoNode):
+			// The command was deleted since we listed the directory.
+			continue
+		case err != nil:
+			return nil, err
+		}
+
+		cmds = append(cmds, cmd)
+	}
+
+	sort.SliceStable(cmds, func(i, j int) bool {
+		ti, tj := protoutil.TimeFromProto(cmds[i].RunAt), protoutil.TimeFromProto(cmds[j].RunAt)
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+
+		return cmds[i].Id < cmds[j].Id
+	})
+
+	return cmds, nil
+}
+
+// UpdateScheduledCommand updates the topo record of a scheduled command. It
+// fails with a BadVersion error if the record changed since it was read, which
+// lets vtctlds race to run a command without running it twice.
+func (ts *Server) UpdateScheduledCommand(ctx context.Context, cmd *ScheduledCommandInfo) error {
+	data, err := cmd.ScheduledCommand.MarshalVT()
+	if err != nil {
+		return err
+	}
+
+	version, err := ts.globalCell.Update(ctx, scheduledCommandPath(cmd.Id), data, cmd.version)
+	if err != nil {
+		return err
+	}
+
+	cmd.version = version
+	return nil
+}
+
+// DeleteScheduledCommand deletes the topo record of a scheduled command.
+func (ts *Server) DeleteScheduledCommand(ctx context.Context, id string) error {
+	return ts.globalCell.Delete(ctx, scheduledCommandPath(id), nil)
+}
@@ -79,17 +79,26 @@ const (
 	RoutingRulesFile      = "RoutingRules"
 	ExternalClustersFile  = "ExternalClusters"
 	ShardRoutingRulesFile = "ShardRoutingRules"
+	BackupSlotsFile       = "BackupSlots"
+	PromotionFile         = "TopoPromotion"
+	MirrorStatusFile      = "TopoMirrorStatus"
+	RecoveryPolicyFile    = "RecoveryPolicy"
 )
 
 // Path for all object types.
 const (
-	CellsPath             = "cells"
-	CellsAliasesPath      = "cells_aliases"
-	KeyspacesPath         = "keyspaces"
-	ShardsPath            = "shards"
-	TabletsPath           = "tablets"
-	MetadataPath          = "metadata"
-	ExternalClusterVitess = "vitess"
+	CellsPath              = "cells"
+	CellsAliasesPath       = "cells_aliases"
+	KeyspacesPath          = "keyspaces"
+	ShardsPath             = "shards"
+	TabletsPath            = "tablets"
+	MetadataPath           = "metadata"
+	ExternalClusterVitess  = "vitess"
+	ScheduledCommandsPath  = "scheduled_commands"
+	BackupSlotsPath        = "backup_slots"
+	NamedLocksPath         = "named_locks"
+	RecoveryPoliciesPath   = "recovery_policies"
+	MaintenanceWindowsPath = "maintenance_windows"
 )
 
 // Factory is a factory interface to create Conn objects.
@@ -206,15 +215,29 @@ func NewWithFactory(factory Factory, serverAddress, root string) (*Server, error
 	if err != nil {
 		return nil, err
 	}
-	conn = NewStatsConn(GlobalCell, conn)
+	conn, err = newFailoverConnIfEnabled(factory, conn)
+	if err != nil {
+		return nil, err
+	}
+	conn, err = newEncryptingConnIfEnabled(conn)
+	if err != nil {
+		return nil, err
+	}
+	conn = newCachingConnIfEnabled(GlobalCell, NewStatsConn(GlobalCell, conn))
 
+	// The read-only global cell is not used with a standby global topo,
+	// which has no read-only replicas of its own.
 	var connReadOnly Conn
-	if factory.HasGlobalReadOnlyCell(serverAddress, root) {
+	if factory.HasGlobalReadOnlyCell(serverAddress, root) && topoGlobalStandbyServerAddress == "" {
 		connReadOnly, err = factory.Create(GlobalReadOnlyCell, serverAddress, root)
 		if err != nil {
 			return nil, err
 		}
-		connReadOnly = NewStatsConn(GlobalReadOnlyCell, connReadOnly)
+		connReadOnly, err = newEncryptingConnIfEnabled(connReadOnly)
+		if err != nil {
+			return nil, err
+		}
+		connReadOnly = newCachingConnIfEnabled(GlobalReadOnlyCell, NewStatsConn(GlobalReadOnlyCell, connReadOnly))
 	} else {
 		connReadOnly = conn
 	}
@@ -293,9 +316,12 @@ func (ts *Server) ConnForCell(ctx context.Context, cell string) (Conn, error) {
 	// This ensures only one connection is established at any given time.
 	// Create the connection and cache it
 	conn, err := ts.factory.Create(cell, ci.ServerAddress, ci.Root)
+	if err == nil {
+		conn, err = newEncryptingConnIfEnabled(conn)
+	}
 	switch {
 	case err == nil:
-		conn = NewStatsConn(cell, conn)
+		conn = newCachingConnIfEnabled(cell, NewStatsConn(cell, conn))
 		ts.cellConns[cell] = cellConn{ci, conn}
 		return conn, nil
 	case IsErrType(err, NoNode):
@@ -379,14 +405,14 @@ func (ts *Server) OpenExternalVitessClusterServer(ctx context.Context, clusterNa
 
 // SetReadOnly is initially ONLY implemented by StatsConn and used in ReadOnlyServer
 func (ts *Server) SetReadOnly(readOnly bool) error {
-	globalCellConn, ok := ts.globalCell.(*StatsConn)
+	globalCellConn, ok := asStatsConn(ts.globalCell)
 	if !ok {
 		return fmt.Errorf("invalid global cell connection type, expected StatsConn but found: %T", ts.globalCell)
 	}
 	globalCellConn.SetReadOnly(readOnly)
 
 	for _, cc := range ts.cellConns {
-		localCellConn, ok := cc.conn.(*StatsConn)
+		localCellConn, ok := asStatsConn(cc.conn)
 		if !ok {
 			return fmt.Errorf("invalid local cell connection type, expected StatsConn but found: %T", cc.conn)
 		}
@@ -396,9 +422,19 @@ func (ts *Server) SetReadOnly(readOnly bool) error {
 	return nil
 }
 
+// asStatsConn returns the StatsConn of a connection, which may be wrapped
+// in a CachingConn.
+func asStatsConn(conn Conn) (*StatsConn, bool) {
+	if cc, ok := conn.(*CachingConn); ok {
+		conn = cc.Conn
+	}
+	sc, ok := conn.(*StatsConn)
+	return sc, ok
+}
+
 // IsReadOnly is initially ONLY implemented by StatsConn and used in ReadOnlyServer
 func (ts *Server) IsReadOnly() (bool, error) {
-	globalCellConn, ok := ts.globalCell.(*StatsConn)
+	globalCellConn, ok := asStatsConn(ts.globalCell)
 	if !ok {
 		return false, fmt.Errorf("invalid global cell connection type, expected StatsConn but found: %T", ts.globalCell)
 	}
@@ -407,7 +443,7 @@ func (ts *Server) IsReadOnly() (bool, error) {
 	}
 
 	for _, cc := range ts.cellConns {
-		localCellConn, ok := cc.conn.(*StatsConn)
+		localCellConn, ok := asStatsConn(cc.conn)
 		if !ok {
 			return false, fmt.Errorf("invalid local cell connection type, expected StatsConn but found: %T", cc.conn)
 		}
@@ -439,6 +439,53 @@ func (ts *Server) UpdateSrvKeyspaceThrottlerConfig(ctx context.Context, keyspace
 	return updatedCells, nil
 }
 
+// UpdateSrvKeyspaceOnlineDDLSchedulerConfig sets the Online DDL scheduler configuration
+// of the SrvKeyspace in the given cells, or in all cells if none is given.
+func (ts *Server) UpdateSrvKeyspaceOnlineDDLSchedulerConfig(ctx context.Context, keyspace string, cells []string, config *topodatapb.OnlineDDLSchedulerConfig) (updatedCells []string, err error) {
+	if err = CheckKeyspaceLocked(ctx, keyspace); err != nil {
+		return updatedCells, err
+	}
+
+	// The caller intends to update all cells in this case
+	if len(cells) == 0 {
+		cells, err = ts.GetCellInfoNames(ctx)
+		if err != nil {
+			return updatedCells, err
+		}
+	}
+
+	wg := sync.WaitGroup{}
+	mu := sync.Mutex{}
+	rec := concurrency.AllErrorRecorder{}
+	for _, cell := range cells {
+		wg.Add(1)
+		go func(cell string) {
+			defer wg.Done()
+			srvKeyspace, err := ts.GetSrvKeyspace(ctx, cell, keyspace)
+			switch {
+			case err == nil:
+				srvKeyspace.OnlineDdlSchedulerConfig = config
+				if err := ts.UpdateSrvKeyspace(ctx, cell, keyspace, srvKeyspace); err != nil {
+					rec.RecordError(err)
+					return
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				updatedCells = append(updatedCells, cell)
+			case IsErrType(err, NoNode):
+				// NOOP as not every cell will contain a serving tablet in the keyspace
+			default:
+				rec.RecordError(err)
+			}
+		}(cell)
+	}
+	wg.Wait()
+	if rec.HasErrors() {
+		return updatedCells, NewError(PartialResult, rec.Error().Error())
+	}
+	return updatedCells, nil
+}
+
 // UpdateDisableQueryService will make sure the disableQueryService is
 // set appropriately in tablet controls in srvKeyspace.
 func (ts *Server) UpdateDisableQueryService(ctx context.Context, keyspace string, shards []*ShardInfo, tabletType topodatapb.TabletType, cells []string, disableQueryService bool) (err error) {
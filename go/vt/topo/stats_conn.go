@@ -18,10 +18,15 @@ package topo
 
 import (
 	"context"
+	"sync"
 	"time"
 
+	"github.com/spf13/pflag"
+
 	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/trace"
 	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/servenv"
 	"vitess.io/vitess/go/vt/vterrors"
 )
 
@@ -37,10 +42,86 @@ var (
 		"TopologyConnErrors",
 		"TopologyConnErrors errors per operation",
 		[]string{"Operation", "Cell"})
+
+	topoStatsConnSlowOperations = stats.NewCountersWithMultiLabels(
+		"TopologyConnSlowOperations",
+		"TopologyConnSlowOperations operations slower than --topo_slow_operation_threshold",
+		[]string{"Operation", "Cell"})
+
+	// slowOperationThreshold is the duration above which the operations
+	// are recorded as slow. 0 disables it.
+	slowOperationThreshold = 1 * time.Second
+
+	// traceOperations creates a tracing span for every operation.
+	traceOperations bool
+
+	// slowOperations are the last slow operations, listed on /debug/topoz.
+	slowOperations = &slowOperationLog{max: maxSlowOperations}
 )
 
+// maxSlowOperations is the number of slow operations kept for /debug/topoz.
+const maxSlowOperations = 100
+
 const readOnlyErrorStrFormat = "cannot perform %s on %s as the topology server connection is read-only"
 
+func init() {
+	for _, cmd := range FlagBinaries {
+		servenv.OnParseFor(cmd, registerStatsConnFlags)
+	}
+}
+
+func registerStatsConnFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(&slowOperationThreshold, "topo_slow_operation_threshold", slowOperationThreshold, "Topo operations taking longer than this are counted in TopologyConnSlowOperations, and the last ones are listed on /debug/topoz. 0 disables it.")
+	fs.BoolVar(&traceOperations, "topo_trace_operations", traceOperations, "If set, a tracing span is created for every call to the topo server, annotated with the cell and the path.")
+}
+
+// SlowOperation is a topo operation that took longer than
+// --topo_slow_operation_threshold.
+type SlowOperation struct {
+	Time      time.Time
+	Operation string
+	Cell      string
+	Path      string
+	Duration  time.Duration
+	Error     string
+}
+
+// slowOperationLog is a ring buffer of the last slow operations.
+type slowOperationLog struct {
+	mu         sync.Mutex
+	max        int
+	operations []SlowOperation
+	next       int
+}
+
+func (l *slowOperationLog) add(op SlowOperation) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.operations) < l.max {
+		l.operations = append(l.operations, op)
+		return
+	}
+	l.operations[l.next] = op
+	l.next = (l.next + 1) % l.max
+}
+
+// get returns the slow operations, the most recent first.
+func (l *slowOperationLog) get() []SlowOperation {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	res := make([]SlowOperation, 0, len(l.operations))
+	for i := len(l.operations) - 1; i >= 0; i-- {
+		res = append(res, l.operations[(l.next+i)%len(l.operations)])
+	}
+	return res
+}
+
+// RecentSlowOperations returns the last topo operations that took longer
+// than --topo_slow_operation_threshold, the most recent first.
+func RecentSlowOperations() []SlowOperation {
+	return slowOperations.get()
+}
+
 // The StatsConn is a wrapper for a Conn that emits stats for every operation
 type StatsConn struct {
 	cell     string
@@ -57,90 +138,121 @@ func NewStatsConn(cell string, conn Conn) *StatsConn {
 	}
 }
 
+// statsOperation is an operation of a StatsConn in progress.
+type statsOperation struct {
+	statsKey  []string
+	path      string
+	startTime time.Time
+	span      trace.Span
+}
+
+// newOperation starts timing an operation on a node.
+func (st *StatsConn) newOperation(operation, nodePath string) *statsOperation {
+	return &statsOperation{
+		statsKey:  []string{operation, st.cell},
+		path:      nodePath,
+		startTime: time.Now(),
+	}
+}
+
+// startOperation starts timing an operation on a node. It returns the
+// context to use for the operation, with a tracing span if
+// --topo_trace_operations is set.
+func (st *StatsConn) startOperation(ctx context.Context, operation, nodePath string) (context.Context, *statsOperation) {
+	op := st.newOperation(operation, nodePath)
+	if traceOperations {
+		op.span, ctx = trace.NewSpan(ctx, "TopoConn."+operation)
+		op.span.Annotate("cell", st.cell)
+		op.span.Annotate("path", nodePath)
+	}
+	return ctx, op
+}
+
+// finish records the stats of an operation, with its error if any.
+func (op *statsOperation) finish(err error) {
+	topoStatsConnTimings.Record(op.statsKey, op.startTime)
+	if err != nil {
+		topoStatsConnErrors.Add(op.statsKey, int64(1))
+	}
+	if op.span != nil {
+		if err != nil {
+			op.span.Annotate("error", err.Error())
+		}
+		op.span.Finish()
+	}
+
+	duration := time.Since(op.startTime)
+	if slowOperationThreshold <= 0 || duration < slowOperationThreshold {
+		return
+	}
+	topoStatsConnSlowOperations.Add(op.statsKey, int64(1))
+	slow := SlowOperation{
+		Time:      op.startTime,
+		Operation: op.statsKey[0],
+		Cell:      op.statsKey[1],
+		Path:      op.path,
+		Duration:  duration,
+	}
+	if err != nil {
+		slow.Error = err.Error()
+	}
+	slowOperations.add(slow)
+}
+
 // ListDir is part of the Conn interface
 func (st *StatsConn) ListDir(ctx context.Context, dirPath string, full bool) ([]DirEntry, error) {
-	startTime := time.Now()
-	statsKey := []string{"ListDir", st.cell}
-	defer topoStatsConnTimings.Record(statsKey, startTime)
+	ctx, op := st.startOperation(ctx, "ListDir", dirPath)
 	res, err := st.conn.ListDir(ctx, dirPath, full)
-	if err != nil {
-		topoStatsConnErrors.Add(statsKey, int64(1))
-		return res, err
-	}
+	op.finish(err)
 	return res, err
 }
 
 // Create is part of the Conn interface
 func (st *StatsConn) Create(ctx context.Context, filePath string, contents []byte) (Version, error) {
-	statsKey := []string{"Create", st.cell}
 	if st.readOnly {
-		return nil, vterrors.Errorf(vtrpc.Code_READ_ONLY, readOnlyErrorStrFormat, statsKey[0], filePath)
+		return nil, vterrors.Errorf(vtrpc.Code_READ_ONLY, readOnlyErrorStrFormat, "Create", filePath)
 	}
-	startTime := time.Now()
-	defer topoStatsConnTimings.Record(statsKey, startTime)
+	ctx, op := st.startOperation(ctx, "Create", filePath)
 	res, err := st.conn.Create(ctx, filePath, contents)
-	if err != nil {
-		topoStatsConnErrors.Add(statsKey, int64(1))
-		return res, err
-	}
+	op.finish(err)
 	return res, err
 }
 
 // Update is part of the Conn interface
 func (st *StatsConn) Update(ctx context.Context, filePath string, contents []byte, version Version) (Version, error) {
-	statsKey := []string{"Update", st.cell}
 	if st.readOnly {
-		return nil, vterrors.Errorf(vtrpc.Code_READ_ONLY, readOnlyErrorStrFormat, statsKey[0], filePath)
+		return nil, vterrors.Errorf(vtrpc.Code_READ_ONLY, readOnlyErrorStrFormat, "Update", filePath)
 	}
-	startTime := time.Now()
-	defer topoStatsConnTimings.Record(statsKey, startTime)
+	ctx, op := st.startOperation(ctx, "Update", filePath)
 	res, err := st.conn.Update(ctx, filePath, contents, version)
-	if err != nil {
-		topoStatsConnErrors.Add(statsKey, int64(1))
-		return res, err
-	}
+	op.finish(err)
 	return res, err
 }
 
 // Get is part of the Conn interface
 func (st *StatsConn) Get(ctx context.Context, filePath string) ([]byte, Version, error) {
-	startTime := time.Now()
-	statsKey := []string{"Get", st.cell}
-	defer topoStatsConnTimings.Record(statsKey, startTime)
+	ctx, op := st.startOperation(ctx, "Get", filePath)
 	bytes, version, err := st.conn.Get(ctx, filePath)
-	if err != nil {
-		topoStatsConnErrors.Add(statsKey, int64(1))
-		return bytes, version, err
-	}
+	op.finish(err)
 	return bytes, version, err
 }
 
 // List is part of the Conn interface
 func (st *StatsConn) List(ctx context.Context, filePathPrefix string) ([]KVInfo, error) {
-	startTime := time.Now()
-	statsKey := []string{"List", st.cell}
-	defer topoStatsConnTimings.Record(statsKey, startTime)
+	ctx, op := st.startOperation(ctx, "List", filePathPrefix)
 	bytes, err := st.conn.List(ctx, filePathPrefix)
-	if err != nil {
-		topoStatsConnErrors.Add(statsKey, int64(1))
-		return bytes, err
-	}
+	op.finish(err)
 	return bytes, err
 }
 
 // Delete is part of the Conn interface
 func (st *StatsConn) Delete(ctx context.Context, filePath string, version Version) error {
-	statsKey := []string{"Delete", st.cell}
 	if st.readOnly {
-		return vterrors.Errorf(vtrpc.Code_READ_ONLY, readOnlyErrorStrFormat, statsKey[0], filePath)
+		return vterrors.Errorf(vtrpc.Code_READ_ONLY, readOnlyErrorStrFormat, "Delete", filePath)
 	}
-	startTime := time.Now()
-	defer topoStatsConnTimings.Record(statsKey, startTime)
+	ctx, op := st.startOperation(ctx, "Delete", filePath)
 	err := st.conn.Delete(ctx, filePath, version)
-	if err != nil {
-		topoStatsConnErrors.Add(statsKey, int64(1))
-		return err
-	}
+	op.finish(err)
 	return err
 }
 
@@ -156,12 +268,10 @@ func (st *StatsConn) TryLock(ctx context.Context, dirPath, contents string) (Loc
 
 // TryLock is part of the topo.Conn interface. Its implementation is same as Lock
 func (st *StatsConn) internalLock(ctx context.Context, dirPath, contents string, isBlocking bool) (LockDescriptor, error) {
-	statsKey := []string{"Lock", st.cell}
 	if st.readOnly {
-		return nil, vterrors.Errorf(vtrpc.Code_READ_ONLY, readOnlyErrorStrFormat, statsKey[0], dirPath)
+		return nil, vterrors.Errorf(vtrpc.Code_READ_ONLY, readOnlyErrorStrFormat, "Lock", dirPath)
 	}
-	startTime := time.Now()
-	defer topoStatsConnTimings.Record(statsKey, startTime)
+	ctx, op := st.startOperation(ctx, "Lock", dirPath)
 	var res LockDescriptor
 	var err error
 	if isBlocking {
@@ -169,47 +279,41 @@ func (st *StatsConn) internalLock(ctx context.Context, dirPath, contents string,
 	} else {
 		res, err = st.conn.TryLock(ctx, dirPath, contents)
 	}
-	if err != nil {
-		topoStatsConnErrors.Add(statsKey, int64(1))
-		return res, err
-	}
+	op.finish(err)
 	return res, err
 }
 
-// Watch is part of the Conn interface
+// Watch is part of the Conn interface. Only the initial read of the file is
+// timed, and traced.
 func (st *StatsConn) Watch(ctx context.Context, filePath string) (current *WatchData, changes <-chan *WatchData, err error) {
-	startTime := time.Now()
-	statsKey := []string{"Watch", st.cell}
-	defer topoStatsConnTimings.Record(statsKey, startTime)
-	return st.conn.Watch(ctx, filePath)
+	ctx, op := st.startOperation(ctx, "Watch", filePath)
+	current, changes, err = st.conn.Watch(ctx, filePath)
+	op.finish(err)
+	return current, changes, err
 }
 
+// WatchRecursive is part of the Conn interface. Only the initial read of
+// the files is timed, and traced.
 func (st *StatsConn) WatchRecursive(ctx context.Context, path string) ([]*WatchDataRecursive, <-chan *WatchDataRecursive, error) {
-	startTime := time.Now()
-	statsKey := []string{"WatchRecursive", st.cell}
-	defer topoStatsConnTimings.Record(statsKey, startTime)
-	return st.conn.WatchRecursive(ctx, path)
+	ctx, op := st.startOperation(ctx, "WatchRecursive", path)
+	current, changes, err := st.conn.WatchRecursive(ctx, path)
+	op.finish(err)
+	return current, changes, err
 }
 
 // NewLeaderParticipation is part of the Conn interface
 func (st *StatsConn) NewLeaderParticipation(name, id string) (LeaderParticipation, error) {
-	startTime := time.Now()
-	statsKey := []string{"NewLeaderParticipation", st.cell}
-	defer topoStatsConnTimings.Record(statsKey, startTime)
+	op := st.newOperation("NewLeaderParticipation", name)
 	res, err := st.conn.NewLeaderParticipation(name, id)
-	if err != nil {
-		topoStatsConnErrors.Add(statsKey, int64(1))
-		return res, err
-	}
+	op.finish(err)
 	return res, err
 }
 
 // Close is part of the Conn interface
 func (st *StatsConn) Close() {
-	startTime := time.Now()
-	statsKey := []string{"Close", st.cell}
-	defer topoStatsConnTimings.Record(statsKey, startTime)
+	op := st.newOperation("Close", "")
 	st.conn.Close()
+	op.finish(nil)
 }
 
 // SetReadOnly with true prevents any write operations from being made on the topo connection
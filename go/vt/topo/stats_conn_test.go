@@ -19,7 +19,12 @@ package topo
 import (
 	"context"
 	"fmt"
+	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/vterrors"
@@ -119,6 +124,9 @@ func (st *fakeConn) TryLock(ctx context.Context, dirPath, contents string) (lock
 
 // Watch is part of the Conn interface
 func (st *fakeConn) Watch(ctx context.Context, filePath string) (current *WatchData, changes <-chan *WatchData, err error) {
+	if filePath == "error" {
+		return current, changes, fmt.Errorf("Dummy error")
+	}
 	return current, changes, err
 }
 
@@ -363,3 +371,68 @@ func TestStatsConnTopoClose(t *testing.T) {
 		t.Errorf("stats were not properly recorded: got = %d, want = %d", got, want)
 	}
 }
+
+// TestStatsConnTopoWatchError emits error stats on Watch
+func TestStatsConnTopoWatchError(t *testing.T) {
+	conn := &fakeConn{}
+	statsConn := NewStatsConn("watch_error", conn)
+	ctx := context.Background()
+
+	_, _, err := statsConn.Watch(ctx, "error")
+	require.Error(t, err)
+	assert.EqualValues(t, 1, topoStatsConnErrors.Counts()["Watch.watch_error"])
+}
+
+// TestStatsConnSlowOperations records the slow operations
+func TestStatsConnSlowOperations(t *testing.T) {
+	oldThreshold := slowOperationThreshold
+	defer func() { slowOperationThreshold = oldThreshold }()
+	conn := &fakeConn{}
+	statsConn := NewStatsConn("slow", conn)
+	ctx := context.Background()
+
+	// With a high threshold, no operation is slow.
+	slowOperationThreshold = time.Hour
+	statsConn.Get(ctx, "/keyspaces/ks/Keyspace")
+	assert.EqualValues(t, 0, topoStatsConnSlowOperations.Counts()["Get.slow"])
+
+	slowOperationThreshold = time.Nanosecond
+	statsConn.Get(ctx, "/keyspaces/ks/Keyspace")
+	statsConn.ListDir(ctx, "error", true)
+	assert.EqualValues(t, 1, topoStatsConnSlowOperations.Counts()["Get.slow"])
+	assert.EqualValues(t, 1, topoStatsConnSlowOperations.Counts()["ListDir.slow"])
+
+	slow := RecentSlowOperations()
+	require.GreaterOrEqual(t, len(slow), 2)
+	assert.Equal(t, "ListDir", slow[0].Operation)
+	assert.Equal(t, "slow", slow[0].Cell)
+	assert.Equal(t, "error", slow[0].Path)
+	assert.Equal(t, "Dummy error", slow[0].Error)
+	assert.Equal(t, "Get", slow[1].Operation)
+	assert.Equal(t, "/keyspaces/ks/Keyspace", slow[1].Path)
+	assert.Empty(t, slow[1].Error)
+
+	// The page lists them.
+	w := httptest.NewRecorder()
+	topozHandler(w, httptest.NewRequest("GET", "/debug/topoz", nil))
+	assert.Contains(t, w.Body.String(), "<td>/keyspaces/ks/Keyspace</td>")
+	assert.Contains(t, w.Body.String(), "<td>Dummy error</td>")
+}
+
+func TestSlowOperationLog(t *testing.T) {
+	l := &slowOperationLog{max: 3}
+	assert.Empty(t, l.get())
+
+	for i := 0; i < 5; i++ {
+		l.add(SlowOperation{Path: fmt.Sprintf("path%d", i)})
+		var got []string
+		for _, op := range l.get() {
+			got = append(got, op.Path)
+		}
+		var want []string
+		for j := i; j >= 0 && j > i-3; j-- {
+			want = append(want, fmt.Sprintf("path%d", j))
+		}
+		assert.Equal(t, want, got)
+	}
+}
@@ -42,6 +42,10 @@ import (
 	"vitess.io/vitess/go/vt/topo/topoproto"
 )
 
+// ErrantGTIDQuarantineTag is the tag of the tablets VTOrc quarantined because
+// of their errant GTIDs. Its value is the errant GTID set.
+const ErrantGTIDQuarantineTag = "errant_gtid_quarantine"
+
 // IsTrivialTypeChange returns if this db type be trivially reassigned
 // without changes to the replication graph
 func IsTrivialTypeChange(oldTabletType, newTabletType topodatapb.TabletType) bool {
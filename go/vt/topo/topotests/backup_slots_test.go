@@ -0,0 +1,160 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topotests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+)
+
+// This file tests the backup slots part of the topo.Server API.
+
+func setBackupSlotTimings(t *testing.T, lease, pollInterval time.Duration) {
+	oldLease, oldPollInterval := topo.BackupSlotLease, topo.BackupSlotPollInterval
+	topo.BackupSlotLease, topo.BackupSlotPollInterval = lease, pollInterval
+	t.Cleanup(func() {
+		topo.BackupSlotLease, topo.BackupSlotPollInterval = oldLease, oldPollInterval
+	})
+}
+
+func TestAcquireBackupSlotMaxConcurrent(t *testing.T) {
+	setBackupSlotTimings(t, time.Minute, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+
+	release1, err := ts.AcquireBackupSlot(ctx, "cell1", "ks", "-80", 2, 0)
+	require.NoError(t, err)
+	release2, err := ts.AcquireBackupSlot(ctx, "cell1", "ks", "80-", 2, 0)
+	require.NoError(t, err)
+
+	slots, err := ts.GetBackupSlots(ctx, "cell1")
+	require.NoError(t, err)
+	require.Len(t, slots, 2)
+
+	// The cell is full, so a third backup waits.
+	timeoutCtx, timeoutCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer timeoutCancel()
+	_, err = ts.AcquireBackupSlot(timeoutCtx, "cell1", "other", "0", 2, 0)
+	assert.ErrorContains(t, err, "gave up waiting for a backup slot in cell cell1")
+
+	// Until one of them is done.
+	acquired := make(chan func())
+	go func() {
+		release3, err := ts.AcquireBackupSlot(ctx, "cell1", "other", "0", 2, 0)
+		assert.NoError(t, err)
+		acquired <- release3
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("backup slot acquired while the cell is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+	release1()
+	var release3 func()
+	select {
+	case release3 = <-acquired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the released backup slot")
+	}
+
+	slots, err = ts.GetBackupSlots(ctx, "cell1")
+	require.NoError(t, err)
+	var shards []string
+	for _, slot := range slots {
+		shards = append(shards, slot.Keyspace+"/"+slot.Shard)
+	}
+	assert.ElementsMatch(t, []string{"ks/80-", "other/0"}, shards)
+
+	release2()
+	release3()
+	slots, err = ts.GetBackupSlots(ctx, "cell1")
+	require.NoError(t, err)
+	assert.Empty(t, slots)
+}
+
+func TestAcquireBackupSlotStagger(t *testing.T) {
+	setBackupSlotTimings(t, time.Minute, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+
+	stagger := 200 * time.Millisecond
+	start := time.Now()
+	release1, err := ts.AcquireBackupSlot(ctx, "cell1", "ks", "-80", 0, stagger)
+	require.NoError(t, err)
+	defer release1()
+	release2, err := ts.AcquireBackupSlot(ctx, "cell1", "ks", "80-", 0, stagger)
+	require.NoError(t, err)
+	defer release2()
+
+	// The second backup started at least stagger after the first one.
+	slots, err := ts.GetBackupSlots(ctx, "cell1")
+	require.NoError(t, err)
+	require.Len(t, slots, 2)
+	var starts []time.Time
+	for _, slot := range slots {
+		starts = append(starts, slot.Started)
+	}
+	gap := starts[0].Sub(starts[1]).Abs()
+	assert.GreaterOrEqual(t, gap, stagger)
+	assert.GreaterOrEqual(t, time.Since(start), stagger)
+}
+
+func TestAcquireBackupSlotRenewal(t *testing.T) {
+	setBackupSlotTimings(t, 30*time.Millisecond, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+
+	release, err := ts.AcquireBackupSlot(ctx, "cell1", "ks", "0", 1, 0)
+	require.NoError(t, err)
+
+	// The slot is renewed while the backup runs, so it does not expire.
+	time.Sleep(100 * time.Millisecond)
+	slots, err := ts.GetBackupSlots(ctx, "cell1")
+	require.NoError(t, err)
+	assert.Len(t, slots, 1)
+
+	release()
+	slots, err = ts.GetBackupSlots(ctx, "cell1")
+	require.NoError(t, err)
+	assert.Empty(t, slots)
+}
+
+func TestAcquireBackupSlotNoCell(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+
+	_, err := ts.AcquireBackupSlot(ctx, "cell2", "ks", "0", 1, 0)
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "expected NoNode, got %v", err)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topotests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+)
+
+func TestCachingConn(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts, factory := memorytopo.NewServerAndFactory(ctx, "cell1")
+	defer ts.Close()
+
+	defer func(interval time.Duration) { topo.CachingConnRefreshInterval = interval }(topo.CachingConnRefreshInterval)
+	topo.CachingConnRefreshInterval = 10 * time.Millisecond
+
+	conn, err := factory.Create("cell1", "", "")
+	require.NoError(t, err)
+	maxStaleness := 500 * time.Millisecond
+	cc := topo.NewCachingConn("cell1", conn, maxStaleness)
+	defer cc.Close()
+
+	_, err = conn.Create(ctx, "/dir/file", []byte("a"))
+	require.NoError(t, err)
+
+	// The first read goes to the topo server.
+	contents, _, err := cc.Get(ctx, "/dir/file")
+	require.NoError(t, err)
+	assert.Equal(t, "a", string(contents))
+	_, _, err = cc.Get(ctx, "/dir/missing")
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "unexpected error: %v", err)
+
+	// The changes made by others are seen through the watch.
+	_, err = conn.Update(ctx, "/dir/file", []byte("b"), nil)
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		contents, _, err := cc.Get(ctx, "/dir/file")
+		return err == nil && string(contents) == "b"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Our own changes are seen right away.
+	_, err = cc.Update(ctx, "/dir/file", []byte("c"), nil)
+	require.NoError(t, err)
+	contents, _, err = cc.Get(ctx, "/dir/file")
+	require.NoError(t, err)
+	assert.Equal(t, "c", string(contents))
+
+	entries, err := cc.ListDir(ctx, "/dir", false)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	// While the topo server is unreachable, the cached data is served.
+	outage := errors.New("topo server unreachable")
+	factory.SetError(outage)
+	contents, _, err = cc.Get(ctx, "/dir/file")
+	require.NoError(t, err)
+	assert.Equal(t, "c", string(contents))
+	entries, err = cc.ListDir(ctx, "/dir", false)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	// But only for up to maxStaleness.
+	time.Sleep(maxStaleness + 100*time.Millisecond)
+	_, _, err = cc.Get(ctx, "/dir/file")
+	assert.ErrorIs(t, err, outage)
+	_, err = cc.ListDir(ctx, "/dir", false)
+	assert.ErrorIs(t, err, outage)
+
+	// The cache is refreshed once the topo server is back.
+	factory.SetError(nil)
+	_, err = conn.Update(ctx, "/dir/file", []byte("d"), nil)
+	require.NoError(t, err)
+	contents, _, err = cc.Get(ctx, "/dir/file")
+	require.NoError(t, err)
+	assert.Equal(t, "d", string(contents))
+
+	// Deleted files are removed from the cache.
+	require.NoError(t, conn.Delete(ctx, "/dir/file", nil))
+	assert.Eventually(t, func() bool {
+		_, _, err := cc.Get(ctx, "/dir/file")
+		return topo.IsErrType(err, topo.NoNode)
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestCachingConnContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts, factory := memorytopo.NewServerAndFactory(ctx, "cell1")
+	defer ts.Close()
+
+	conn, err := factory.Create("cell1", "", "")
+	require.NoError(t, err)
+	cc := topo.NewCachingConn("cell1", conn, time.Minute)
+	defer cc.Close()
+	_, err = conn.Create(ctx, "/file", []byte("a"))
+	require.NoError(t, err)
+
+	// The read waits for the topo server, which is locked.
+	factory.Lock()
+	getCtx, getCancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer getCancel()
+	_, _, err = cc.Get(getCtx, "/file")
+	factory.Unlock()
+	assert.True(t, topo.IsErrType(err, topo.Timeout), "unexpected error: %v", err)
+
+	contents, _, err := cc.Get(ctx, "/file")
+	require.NoError(t, err)
+	assert.Equal(t, "a", string(contents))
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topotests
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+)
+
+func encryptionKeys(t *testing.T, current string, ids ...string) topo.EncryptionKeyProvider {
+	var keys []string
+	for _, id := range ids {
+		key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat(id, 32)[:32]))
+		keys = append(keys, fmt.Sprintf("%q: %q", id, key))
+	}
+	provider, err := topo.NewStaticEncryptionKeyProvider([]byte(fmt.Sprintf(`{"current_key": %q, "keys": {%s}}`, current, strings.Join(keys, ", "))))
+	require.NoError(t, err)
+	return provider
+}
+
+func TestEncryptingConn(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts, factory := memorytopo.NewServerAndFactory(ctx, "cell1")
+	defer ts.Close()
+
+	conn, err := factory.Create("cell1", "", "")
+	require.NoError(t, err)
+	ec := topo.NewEncryptingConn(conn, encryptionKeys(t, "k1", "k1"), []string{"keyspaces/"})
+
+	// The files under the paths are encrypted.
+	_, err = ec.Create(ctx, "keyspaces/ks/Keyspace", []byte("secret"))
+	require.NoError(t, err)
+	raw, _, err := conn.Get(ctx, "keyspaces/ks/Keyspace")
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "secret")
+	contents, _, err := ec.Get(ctx, "keyspaces/ks/Keyspace")
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(contents))
+
+	// The other files are not.
+	_, err = ec.Create(ctx, "cells/cell1/CellInfo", []byte("public"))
+	require.NoError(t, err)
+	raw, _, err = conn.Get(ctx, "cells/cell1/CellInfo")
+	require.NoError(t, err)
+	assert.Equal(t, "public", string(raw))
+
+	// The files written before the encryption was enabled are still read.
+	_, err = conn.Create(ctx, "keyspaces/ks/VSchema", []byte("plain"))
+	require.NoError(t, err)
+	contents, _, err = ec.Get(ctx, "keyspaces/ks/VSchema")
+	require.NoError(t, err)
+	assert.Equal(t, "plain", string(contents))
+
+	kvs, err := ec.List(ctx, "keyspaces/ks/")
+	require.NoError(t, err)
+	got := make(map[string]string)
+	for _, kv := range kvs {
+		got[string(kv.Key)] = string(kv.Value)
+	}
+	assert.Equal(t, map[string]string{"keyspaces/ks/Keyspace": "secret", "keyspaces/ks/VSchema": "plain"}, got)
+
+	watchCtx, watchCancel := context.WithCancel(ctx)
+	defer watchCancel()
+	current, changes, err := ec.Watch(watchCtx, "keyspaces/ks/Keyspace")
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(current.Contents))
+	_, err = ec.Update(ctx, "keyspaces/ks/Keyspace", []byte("secret2"), nil)
+	require.NoError(t, err)
+	wd := <-changes
+	require.NoError(t, wd.Err)
+	assert.Equal(t, "secret2", string(wd.Contents))
+	watchCancel()
+	for range changes {
+	}
+}
+
+func TestEncryptingConnKeyRotation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts, factory := memorytopo.NewServerAndFactory(ctx, "cell1")
+	defer ts.Close()
+
+	conn, err := factory.Create("cell1", "", "")
+	require.NoError(t, err)
+	paths := []string{"keyspaces/"}
+	ec1 := topo.NewEncryptingConn(conn, encryptionKeys(t, "k1", "k1"), paths)
+	_, err = ec1.Create(ctx, "keyspaces/ks1/Keyspace", []byte("one"))
+	require.NoError(t, err)
+
+	// With k2 as the current key, the files encrypted with k1 are still
+	// read, and the new ones are encrypted with k2.
+	ec2 := topo.NewEncryptingConn(conn, encryptionKeys(t, "k2", "k1", "k2"), paths)
+	contents, _, err := ec2.Get(ctx, "keyspaces/ks1/Keyspace")
+	require.NoError(t, err)
+	assert.Equal(t, "one", string(contents))
+	_, err = ec2.Create(ctx, "keyspaces/ks2/Keyspace", []byte("two"))
+	require.NoError(t, err)
+
+	// Without k1, the files encrypted with it can't be read anymore.
+	ec3 := topo.NewEncryptingConn(conn, encryptionKeys(t, "k2", "k2"), paths)
+	contents, _, err = ec3.Get(ctx, "keyspaces/ks2/Keyspace")
+	require.NoError(t, err)
+	assert.Equal(t, "two", string(contents))
+	_, _, err = ec3.Get(ctx, "keyspaces/ks1/Keyspace")
+	assert.ErrorContains(t, err, "unknown topo encryption key k1")
+}
+
+func TestStaticEncryptionKeyProvider(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32)))
+	tcases := []struct {
+		data string
+		err  string
+	}{{
+		data: `{"current_key": "k1", "keys": {"k1": "` + key + `"}}`,
+	}, {
+		data: `{"current_key": "k2", "keys": {"k1": "` + key + `"}}`,
+		err:  `unknown current topo encryption key "k2"`,
+	}, {
+		data: `{"current_key": "k1", "keys": {"k1": "not base64"}}`,
+		err:  "cannot decode topo encryption key k1",
+	}, {
+		data: `{"current_key": "k1", "keys": {"k1": "` + base64.StdEncoding.EncodeToString([]byte("short")) + `"}}`,
+		err:  "bad topo encryption key k1",
+	}, {
+		data: `not json`,
+		err:  "cannot parse the topo encryption keys",
+	}}
+	for _, tcase := range tcases {
+		_, err := topo.NewStaticEncryptionKeyProvider([]byte(tcase.data))
+		if tcase.err == "" {
+			assert.NoError(t, err, tcase.data)
+		} else {
+			assert.ErrorContains(t, err, tcase.err, tcase.data)
+		}
+	}
+}
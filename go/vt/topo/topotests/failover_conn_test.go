@@ -0,0 +1,74 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topotests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+)
+
+func TestFailoverConn(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	primaryTS, primaryFactory := memorytopo.NewServerAndFactory(ctx)
+	defer primaryTS.Close()
+	standbyTS, standbyFactory := memorytopo.NewServerAndFactory(ctx)
+	defer standbyTS.Close()
+
+	primary, err := primaryFactory.Create(topo.GlobalCell, "", "")
+	require.NoError(t, err)
+	_, err = primary.Create(ctx, "/file", []byte("primary"))
+	require.NoError(t, err)
+	standby, err := standbyFactory.Create(topo.GlobalCell, "", "")
+	require.NoError(t, err)
+	_, err = standby.Create(ctx, "/file", []byte("standby"))
+	require.NoError(t, err)
+
+	conn := topo.NewFailoverConn(primary, standby, 10*time.Millisecond)
+	defer conn.Close()
+	contents, _, err := conn.Get(ctx, "/file")
+	require.NoError(t, err)
+	assert.Equal(t, "primary", string(contents))
+	assert.False(t, conn.Promoted())
+
+	// Once the standby is promoted, the conn switches to it.
+	_, err = standbyTS.Promote(ctx)
+	require.NoError(t, err)
+	assert.Eventually(t, conn.Promoted, 5*time.Second, 10*time.Millisecond)
+	contents, _, err = conn.Get(ctx, "/file")
+	require.NoError(t, err)
+	assert.Equal(t, "standby", string(contents))
+	_, _, err = primary.Get(ctx, "/file")
+	assert.ErrorIs(t, err, memorytopo.ErrConnectionClosed)
+
+	// A conn whose standby is already promoted uses it right away.
+	primary, err = primaryFactory.Create(topo.GlobalCell, "", "")
+	require.NoError(t, err)
+	conn = topo.NewFailoverConn(primary, standby, time.Hour)
+	defer conn.Close()
+	assert.True(t, conn.Promoted())
+	contents, _, err = conn.Get(ctx, "/file")
+	require.NoError(t, err)
+	assert.Equal(t, "standby", string(contents))
+}
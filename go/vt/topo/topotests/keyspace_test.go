@@ -23,6 +23,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
 	"vitess.io/vitess/go/vt/vterrors"
 
@@ -74,3 +76,51 @@ func TestGetKeyspace(t *testing.T) {
 		assert.Nil(t, ks)
 	})
 }
+
+func TestWatchKeyspace(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+
+	// No Keyspace -> ErrNoNode
+	_, _, err := ts.WatchKeyspace(ctx, "ks")
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "expected NoNode, got %v", err)
+
+	_, _, err = ts.WatchKeyspace(ctx, "no/slashes/allowed")
+	assert.Equal(t, vtrpc.Code_INVALID_ARGUMENT, vterrors.Code(err), "%+v", err)
+
+	err = ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{})
+	require.NoError(t, err)
+
+	watchCtx, watchCancel := context.WithCancel(ctx)
+	defer watchCancel()
+
+	current, changes, err := ts.WatchKeyspace(watchCtx, "ks")
+	require.NoError(t, err)
+	utils.MustMatch(t, &topodatapb.Keyspace{}, current.Value)
+
+	// Update the keyspace, and wait until we see it.
+	lctx, unlock, err := ts.LockKeyspace(ctx, "ks", "TestWatchKeyspace")
+	require.NoError(t, err)
+
+	ki, err := ts.GetKeyspace(lctx, "ks")
+	require.NoError(t, err)
+	ki.DurabilityPolicy = "semi_sync"
+	err = ts.UpdateKeyspace(lctx, ki)
+	unlock(&err)
+	require.NoError(t, err)
+
+	wd, ok := <-changes
+	require.True(t, ok, "watch channel unexpectedly closed")
+	require.NoError(t, wd.Err)
+	utils.MustMatch(t, &topodatapb.Keyspace{DurabilityPolicy: "semi_sync"}, wd.Value)
+
+	// Cancelling the watch interrupts it.
+	watchCancel()
+	for wd := range changes {
+		if wd.Err != nil {
+			assert.True(t, topo.IsErrType(wd.Err, topo.Interrupted), "expected Interrupted, got %v", wd.Err)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topotests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+)
+
+// This file tests the maintenance windows part of the topo.Server API.
+
+func TestMaintenanceWindows(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+
+	windows, err := ts.GetMaintenanceWindows(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, windows)
+
+	_, err = ts.StartMaintenance(ctx, "ks", "-80", "", "reboot", time.Hour)
+	require.NoError(t, err)
+	_, err = ts.StartMaintenance(ctx, "ks2", "", "", "", time.Hour)
+	require.NoError(t, err)
+	_, err = ts.StartMaintenance(ctx, "", "", "cell1", "network upgrade", time.Hour)
+	require.NoError(t, err)
+
+	windows, err = ts.GetMaintenanceWindows(ctx)
+	require.NoError(t, err)
+	require.Len(t, windows, 3)
+	assert.Equal(t, "cell cell1", windows[0].Target())
+	assert.Equal(t, "network upgrade", windows[0].Reason)
+	assert.Equal(t, "keyspace ks2", windows[1].Target())
+	assert.Equal(t, "shard ks/-80", windows[2].Target())
+	assert.True(t, windows[2].Expires.After(windows[2].Started))
+
+	assert.True(t, windows[0].Covers("ks", "80-", "cell1"))
+	assert.False(t, windows[0].Covers("ks", "80-", "cell2"))
+	assert.True(t, windows[1].Covers("ks2", "-80", "cell2"))
+	assert.False(t, windows[1].Covers("ks", "-80", "cell2"))
+	assert.True(t, windows[2].Covers("ks", "-80", "cell2"))
+	assert.False(t, windows[2].Covers("ks", "80-", "cell2"))
+
+	// Stopped and expired windows are not returned.
+	require.NoError(t, ts.StopMaintenance(ctx, "ks2", "", ""))
+	_, err = ts.StartMaintenance(ctx, "", "", "cell1", "", time.Nanosecond)
+	require.NoError(t, err)
+	time.Sleep(time.Millisecond)
+	windows, err = ts.GetMaintenanceWindows(ctx)
+	require.NoError(t, err)
+	require.Len(t, windows, 1)
+	assert.Equal(t, "shard ks/-80", windows[0].Target())
+
+	err = ts.StopMaintenance(ctx, "ks2", "", "")
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "unexpected error: %v", err)
+
+	// Bad targets are rejected.
+	_, err = ts.StartMaintenance(ctx, "", "", "", "", time.Hour)
+	assert.ErrorContains(t, err, "needs a keyspace, a shard or a cell")
+	_, err = ts.StartMaintenance(ctx, "ks", "", "cell1", "", time.Hour)
+	assert.ErrorContains(t, err, "either for a keyspace, a shard or a cell")
+	_, err = ts.StartMaintenance(ctx, "", "-80", "", "", time.Hour)
+	assert.ErrorContains(t, err, "needs its keyspace")
+	_, err = ts.StartMaintenance(ctx, "ks", "", "", "", 0)
+	assert.ErrorContains(t, err, "TTL must be positive")
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topotests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+)
+
+// This file tests the named locks part of the topo.Server API.
+
+func TestNamedLocks(t *testing.T) {
+	oldPollInterval := topo.NamedLockPollInterval
+	topo.NamedLockPollInterval = 10 * time.Millisecond
+	defer func() { topo.NamedLockPollInterval = oldPollInterval }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+
+	name := "app/lock 1"
+	_, err := ts.GetNamedLock(ctx, name)
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "expected NoNode, got %v", err)
+
+	lock, err := ts.AcquireNamedLock(ctx, name, "owner1", time.Minute, 0)
+	require.NoError(t, err)
+	assert.Equal(t, name, lock.Name)
+	assert.Equal(t, "owner1", lock.Owner)
+
+	got, err := ts.GetNamedLock(ctx, name)
+	require.NoError(t, err)
+	assert.Equal(t, "owner1", got.Owner)
+
+	// The owner can acquire it again, which renews it.
+	again, err := ts.AcquireNamedLock(ctx, name, "owner1", 2*time.Minute, 0)
+	require.NoError(t, err)
+	assert.True(t, lock.Acquired.Equal(again.Acquired))
+	assert.True(t, again.Expires.After(lock.Expires))
+
+	// Another owner cannot, even if it waits.
+	start := time.Now()
+	_, err = ts.AcquireNamedLock(ctx, name, "owner2", time.Minute, 50*time.Millisecond)
+	assert.True(t, topo.IsErrType(err, topo.NodeExists), "expected NodeExists, got %v", err)
+	assert.ErrorContains(t, err, "named lock app/lock 1 is held by owner1")
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	// Nor can it renew or release it.
+	_, err = ts.RenewNamedLock(ctx, name, "owner2", time.Minute)
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "expected NoNode, got %v", err)
+	err = ts.ReleaseNamedLock(ctx, name, "owner2")
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "expected NoNode, got %v", err)
+
+	// The waiting owner gets the lock once it is released.
+	acquired := make(chan error)
+	go func() {
+		_, err := ts.AcquireNamedLock(ctx, name, "owner2", time.Minute, 5*time.Second)
+		acquired <- err
+	}()
+	time.Sleep(30 * time.Millisecond)
+	require.NoError(t, ts.ReleaseNamedLock(ctx, name, "owner1"))
+	select {
+	case err := <-acquired:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the released named lock")
+	}
+	got, err = ts.GetNamedLock(ctx, name)
+	require.NoError(t, err)
+	assert.Equal(t, "owner2", got.Owner)
+
+	_, err = ts.RenewNamedLock(ctx, name, "owner2", time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, ts.ReleaseNamedLock(ctx, name, "owner2"))
+	err = ts.ReleaseNamedLock(ctx, name, "owner2")
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "expected NoNode, got %v", err)
+}
+
+func TestNamedLockExpiration(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+
+	_, err := ts.AcquireNamedLock(ctx, "lock", "owner1", 20*time.Millisecond, 0)
+	require.NoError(t, err)
+	time.Sleep(40 * time.Millisecond)
+
+	// The expired lock is free, and cannot be renewed.
+	_, err = ts.GetNamedLock(ctx, "lock")
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "expected NoNode, got %v", err)
+	_, err = ts.RenewNamedLock(ctx, "lock", "owner1", time.Minute)
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "expected NoNode, got %v", err)
+
+	// So another owner takes it over.
+	_, err = ts.AcquireNamedLock(ctx, "lock", "owner2", time.Minute, 0)
+	require.NoError(t, err)
+	got, err := ts.GetNamedLock(ctx, "lock")
+	require.NoError(t, err)
+	assert.Equal(t, "owner2", got.Owner)
+}
+
+func TestNamedLockArgs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+
+	_, err := ts.AcquireNamedLock(ctx, "", "owner", time.Minute, 0)
+	assert.ErrorContains(t, err, "named lock name cannot be empty")
+	_, err = ts.AcquireNamedLock(ctx, "lock", "", time.Minute, 0)
+	assert.ErrorContains(t, err, "named lock owner cannot be empty")
+	_, err = ts.AcquireNamedLock(ctx, "lock", "owner", 0, 0)
+	assert.ErrorContains(t, err, "named lock TTL must be positive")
+}
+
+func TestNamedLockDotNames(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+
+	// "." and ".." must not resolve to the named locks directory or the root
+	// of the global topo, and must not collide with their escaped forms.
+	names := []string{".", "..", "%2E", "%2E%2E", "..."}
+	for i, name := range names {
+		lock, err := ts.AcquireNamedLock(ctx, name, fmt.Sprintf("owner%d", i), time.Minute, 0)
+		require.NoError(t, err, name)
+		assert.Equal(t, name, lock.Name)
+	}
+	for i, name := range names {
+		got, err := ts.GetNamedLock(ctx, name)
+		require.NoError(t, err, name)
+		assert.Equal(t, fmt.Sprintf("owner%d", i), got.Owner)
+	}
+
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	require.NoError(t, err)
+	entries, err := conn.ListDir(ctx, topo.NamedLocksPath, false /* full */)
+	require.NoError(t, err)
+	assert.Len(t, entries, len(names))
+}
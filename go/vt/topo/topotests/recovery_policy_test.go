@@ -0,0 +1,101 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topotests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+)
+
+// This file tests the recovery policies part of the topo.Server API.
+
+func TestRecoveryPolicies(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+
+	policies, err := ts.GetRecoveryPolicies(ctx, "ks")
+	require.NoError(t, err)
+	assert.Empty(t, policies)
+	_, err = ts.GetRecoveryPolicy(ctx, "ks", "")
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "unexpected error: %v", err)
+
+	keyspacePolicy := &topo.RecoveryPolicy{
+		AllowedRecoveries: []string{"RecoverDeadPrimary", "FixReplica"},
+		PromotionCells:    []string{"cell1"},
+		PreferredCells:    []string{"cell1", "cell2"},
+		CooldownSeconds:   60,
+	}
+	shardPolicy := &topo.RecoveryPolicy{
+		PromotionRules:  map[string]string{"cell2": "must_not"},
+		CooldownSeconds: 300,
+	}
+	require.NoError(t, ts.SaveRecoveryPolicy(ctx, "ks", "", keyspacePolicy))
+	require.NoError(t, ts.SaveRecoveryPolicy(ctx, "ks", "-80", shardPolicy))
+
+	policy, err := ts.GetRecoveryPolicy(ctx, "ks", "-80")
+	require.NoError(t, err)
+	assert.Equal(t, shardPolicy, policy)
+
+	policies, err = ts.GetRecoveryPolicies(ctx, "ks")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*topo.RecoveryPolicy{
+		"":    keyspacePolicy,
+		"-80": shardPolicy,
+	}, policies)
+
+	// The shard policy overrides the fields it sets.
+	assert.Equal(t, &topo.RecoveryPolicy{
+		AllowedRecoveries: []string{"RecoverDeadPrimary", "FixReplica"},
+		PromotionRules:    map[string]string{"cell2": "must_not"},
+		PromotionCells:    []string{"cell1"},
+		PreferredCells:    []string{"cell1", "cell2"},
+		CooldownSeconds:   300,
+	}, topo.MergeRecoveryPolicies(policies[""], policies["-80"]))
+	assert.Equal(t, keyspacePolicy, topo.MergeRecoveryPolicies(keyspacePolicy, nil))
+	assert.Nil(t, topo.MergeRecoveryPolicies(nil, nil))
+
+	// Invalid policies are rejected.
+	err = ts.SaveRecoveryPolicy(ctx, "ks", "80-", &topo.RecoveryPolicy{PromotionRules: map[string]string{"cell1": "always"}})
+	assert.ErrorContains(t, err, "bad promotion rule for cell1")
+	err = ts.SaveRecoveryPolicy(ctx, "ks", "80-", &topo.RecoveryPolicy{CooldownSeconds: -1})
+	assert.ErrorContains(t, err, "negative cooldown")
+
+	require.NoError(t, ts.DeleteRecoveryPolicy(ctx, "ks", "-80"))
+	policies, err = ts.GetRecoveryPolicies(ctx, "ks")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*topo.RecoveryPolicy{"": keyspacePolicy}, policies)
+}
+
+func TestRecoveryPolicyIsRecoveryAllowed(t *testing.T) {
+	var policy *topo.RecoveryPolicy
+	assert.True(t, policy.IsRecoveryAllowed("RecoverDeadPrimary"))
+
+	policy = &topo.RecoveryPolicy{}
+	assert.True(t, policy.IsRecoveryAllowed("RecoverDeadPrimary"))
+
+	policy.AllowedRecoveries = []string{"FixReplica"}
+	assert.True(t, policy.IsRecoveryAllowed("FixReplica"))
+	assert.False(t, policy.IsRecoveryAllowed("RecoverDeadPrimary"))
+}
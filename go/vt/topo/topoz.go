@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"net/http"
+
+	"github.com/google/safehtml/template"
+
+	"vitess.io/vitess/go/acl"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logz"
+	"vitess.io/vitess/go/vt/servenv"
+)
+
+var (
+	topozHeader = []byte(`
+		<thead>
+			<tr>
+				<th>Start Time</th>
+				<th>Operation</th>
+				<th>Cell</th>
+				<th>Path</th>
+				<th>Duration</th>
+				<th>Error</th>
+			</tr>
+		</thead>
+	`)
+	topozTmpl = template.Must(template.New("topoz").Parse(`
+		<tr class="{{if .Error}}error{{else}}low{{end}}">
+			<td>{{.Time.Format "2006-01-02 15:04:05.000000"}}</td>
+			<td>{{.Operation}}</td>
+			<td>{{.Cell}}</td>
+			<td>{{.Path}}</td>
+			<td>{{.Duration}}</td>
+			<td>{{.Error}}</td>
+		</tr>
+	`))
+)
+
+func init() {
+	servenv.HTTPHandleFunc("/debug/topoz", topozHandler)
+}
+
+// topozHandler lists the last topo operations that took longer than
+// --topo_slow_operation_threshold.
+func topozHandler(w http.ResponseWriter, r *http.Request) {
+	if err := acl.CheckAccessHTTP(r, acl.DEBUGGING); err != nil {
+		acl.SendError(w, err)
+		return
+	}
+	logz.StartHTMLTable(w)
+	defer logz.EndHTMLTable(w)
+	w.Write(topozHeader)
+
+	for _, op := range RecentSlowOperations() {
+		if err := topozTmpl.Execute(w, op); err != nil {
+			log.Errorf("topoz: couldn't execute template: %v", err)
+		}
+	}
+}
@@ -0,0 +1,454 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topotools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"time"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// This file contains the topo doctor, which finds inconsistencies in the
+// topology, and optionally repairs them.
+
+// TopoIssueType is the type of an issue found by TopoDoctor.
+type TopoIssueType string
+
+const (
+	// OrphanTablet is a tablet whose keyspace or shard doesn't exist.
+	OrphanTablet TopoIssueType = "OrphanTablet"
+	// MissingCell is a shard referencing a cell that doesn't exist.
+	MissingCell TopoIssueType = "MissingCell"
+	// StaleSrvKeyspace is a SrvKeyspace of a keyspace that doesn't exist,
+	// or referencing shards that don't exist.
+	StaleSrvKeyspace TopoIssueType = "StaleSrvKeyspace"
+	// BrokenReplicationGraph is a ShardReplication entry of a tablet that
+	// doesn't exist or is in another shard, or a tablet missing from the
+	// ShardReplication of its shard.
+	BrokenReplicationGraph TopoIssueType = "BrokenReplicationGraph"
+	// DanglingLock is a keyspace or shard lock held for longer than
+	// TopoDoctorOptions.MaxLockAge.
+	DanglingLock TopoIssueType = "DanglingLock"
+	// UnreachableCell is a cell whose topo server cannot be read.
+	UnreachableCell TopoIssueType = "UnreachableCell"
+)
+
+// TopoIssue is an issue found by TopoDoctor.
+type TopoIssue struct {
+	Type        TopoIssueType
+	Description string
+
+	// Repaired is true if the issue was repaired.
+	Repaired bool
+
+	// repair fixes the issue, it is nil if the issue cannot be repaired
+	// automatically.
+	repair func(ctx context.Context) error
+}
+
+// String is part of the fmt.Stringer interface.
+func (issue *TopoIssue) String() string {
+	s := fmt.Sprintf("%v: %v", issue.Type, issue.Description)
+	if issue.Repaired {
+		s += " (repaired)"
+	}
+	return s
+}
+
+// TopoDoctorOptions are the options of TopoDoctor.
+type TopoDoctorOptions struct {
+	// Repair makes TopoDoctor repair the issues it can.
+	Repair bool
+
+	// MaxLockAge is the age after which a lock is considered dangling.
+	MaxLockAge time.Duration
+}
+
+// topoDoctor holds the state of a TopoDoctor run.
+type topoDoctor struct {
+	ts     *topo.Server
+	logger logutil.Logger
+	opts   TopoDoctorOptions
+
+	cells  map[string]bool
+	shards map[string]map[string]bool
+	issues []*TopoIssue
+}
+
+// TopoDoctor scans the topology for inconsistencies: orphan tablets, shards
+// referencing missing cells, stale SrvKeyspaces, broken replication graph
+// entries and dangling locks. If opts.Repair is set, the issues that can be
+// repaired are repaired, and marked as such.
+//
+// The dangling locks are only found for the topo implementations storing
+// the locks as files under a locks directory, like etcd and ZooKeeper.
+func TopoDoctor(ctx context.Context, ts *topo.Server, logger logutil.Logger, opts TopoDoctorOptions) ([]*TopoIssue, error) {
+	d := &topoDoctor{
+		ts:     ts,
+		logger: logger,
+		opts:   opts,
+		cells:  make(map[string]bool),
+		shards: make(map[string]map[string]bool),
+	}
+	if err := d.load(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := d.checkShards(ctx); err != nil {
+		return nil, err
+	}
+	if err := d.checkLocks(ctx); err != nil {
+		return nil, err
+	}
+	for _, cell := range sortedKeys(d.cells) {
+		if err := d.checkCell(ctx, cell); err != nil {
+			d.report(UnreachableCell, fmt.Sprintf("cannot check cell %v: %v", cell, err), nil)
+		}
+	}
+
+	if opts.Repair {
+		for _, issue := range d.issues {
+			if issue.repair == nil {
+				continue
+			}
+			if err := issue.repair(ctx); err != nil {
+				return d.issues, fmt.Errorf("cannot repair %v: %w", issue, err)
+			}
+			issue.Repaired = true
+			logger.Infof("Repaired %v", issue)
+		}
+	}
+	return d.issues, nil
+}
+
+func (d *topoDoctor) report(issueType TopoIssueType, description string, repair func(ctx context.Context) error) {
+	issue := &TopoIssue{
+		Type:        issueType,
+		Description: description,
+		repair:      repair,
+	}
+	d.logger.Warningf("%v", issue)
+	d.issues = append(d.issues, issue)
+}
+
+// load reads the cells, keyspaces and shards.
+func (d *topoDoctor) load(ctx context.Context) error {
+	cells, err := d.ts.GetCellInfoNames(ctx)
+	if err != nil {
+		return fmt.Errorf("GetCellInfoNames: %w", err)
+	}
+	for _, cell := range cells {
+		d.cells[cell] = true
+	}
+
+	keyspaces, err := d.ts.GetKeyspaces(ctx)
+	if err != nil {
+		return fmt.Errorf("GetKeyspaces: %w", err)
+	}
+	for _, keyspace := range keyspaces {
+		shards, err := d.ts.GetShardNames(ctx, keyspace)
+		if err != nil {
+			return fmt.Errorf("GetShardNames(%v): %w", keyspace, err)
+		}
+		d.shards[keyspace] = make(map[string]bool, len(shards))
+		for _, shard := range shards {
+			d.shards[keyspace][shard] = true
+		}
+	}
+	return nil
+}
+
+func (d *topoDoctor) shardExists(keyspace, shard string) bool {
+	return d.shards[keyspace][shard]
+}
+
+// checkShards finds the shards referencing missing cells.
+func (d *topoDoctor) checkShards(ctx context.Context) error {
+	for _, keyspace := range sortedKeys(d.shards) {
+		for _, shard := range sortedKeys(d.shards[keyspace]) {
+			si, err := d.ts.GetShard(ctx, keyspace, shard)
+			if err != nil {
+				return fmt.Errorf("GetShard(%v, %v): %w", keyspace, shard, err)
+			}
+			name := topoproto.KeyspaceShardString(keyspace, shard)
+
+			if si.PrimaryAlias != nil && !d.cells[si.PrimaryAlias.Cell] {
+				d.report(MissingCell, fmt.Sprintf("the primary %v of shard %v is in missing cell %v", topoproto.TabletAliasString(si.PrimaryAlias), name, si.PrimaryAlias.Cell), nil)
+			}
+
+			var missingCells []string
+			for _, tc := range si.TabletControls {
+				for _, cell := range tc.Cells {
+					if !d.cells[cell] {
+						missingCells = append(missingCells, cell)
+					}
+				}
+			}
+			if len(missingCells) > 0 {
+				keyspace, shard := keyspace, shard
+				d.report(MissingCell, fmt.Sprintf("the tablet controls of shard %v reference missing cells %v", name, missingCells), func(ctx context.Context) error {
+					return d.removeTabletControlsCells(ctx, keyspace, shard)
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// removeTabletControlsCells removes the missing cells from the tablet
+// controls of a shard.
+func (d *topoDoctor) removeTabletControlsCells(ctx context.Context, keyspace, shard string) (err error) {
+	ctx, unlock, lockErr := d.ts.LockShard(ctx, keyspace, shard, "TopoDoctor")
+	if lockErr != nil {
+		return lockErr
+	}
+	defer unlock(&err)
+
+	_, err = d.ts.UpdateShardFields(ctx, keyspace, shard, func(si *topo.ShardInfo) error {
+		var tabletControls []*topodatapb.Shard_TabletControl
+		for _, tc := range si.TabletControls {
+			var cells []string
+			for _, cell := range tc.Cells {
+				if d.cells[cell] {
+					cells = append(cells, cell)
+				}
+			}
+			if len(cells) == 0 && len(tc.Cells) > 0 {
+				// The tablet control only applied to missing cells.
+				continue
+			}
+			tc.Cells = cells
+			tabletControls = append(tabletControls, tc)
+		}
+		si.TabletControls = tabletControls
+		return nil
+	})
+	return err
+}
+
+// checkLocks finds the keyspace and shard locks held for longer than
+// MaxLockAge.
+func (d *topoDoctor) checkLocks(ctx context.Context) error {
+	if d.opts.MaxLockAge == 0 {
+		return nil
+	}
+	conn, err := d.ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		return err
+	}
+
+	for _, keyspace := range sortedKeys(d.shards) {
+		dirs := []string{path.Join(topo.KeyspacesPath, keyspace)}
+		for _, shard := range sortedKeys(d.shards[keyspace]) {
+			dirs = append(dirs, path.Join(topo.KeyspacesPath, keyspace, topo.ShardsPath, shard))
+		}
+
+		for _, dir := range dirs {
+			locksDir := path.Join(dir, "locks")
+			entries, err := conn.ListDir(ctx, locksDir, false /* full */)
+			switch {
+			case err == nil:
+			case topo.IsErrType(err, topo.NoNode):
+				continue
+			default:
+				return fmt.Errorf("ListDir(%v): %w", locksDir, err)
+			}
+
+			for _, entry := range entries {
+				lockPath := path.Join(locksDir, entry.Name)
+				data, _, err := conn.Get(ctx, lockPath)
+				switch {
+				case err == nil:
+				case topo.IsErrType(err, topo.NoNode):
+					// The lock was released in the meantime.
+					continue
+				default:
+					return fmt.Errorf("Get(%v): %w", lockPath, err)
+				}
+
+				lock := &topo.Lock{}
+				if err := json.Unmarshal(data, lock); err != nil {
+					d.logger.Warningf("Cannot parse lock %v, skipping it: %v", lockPath, err)
+					continue
+				}
+				lockTime, err := time.Parse(time.RFC3339, lock.Time)
+				if err != nil {
+					d.logger.Warningf("Cannot parse the time of lock %v, skipping it: %v", lockPath, err)
+					continue
+				}
+				if age := time.Since(lockTime); age > d.opts.MaxLockAge {
+					d.report(DanglingLock, fmt.Sprintf("lock %v was taken by %v@%v for %v %v ago", lockPath, lock.UserName, lock.HostName, lock.Action, age.Round(time.Second)), func(ctx context.Context) error {
+						return conn.Delete(ctx, lockPath, nil)
+					})
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkCell finds the orphan tablets, broken replication graph entries and
+// stale SrvKeyspaces of a cell.
+func (d *topoDoctor) checkCell(ctx context.Context, cell string) error {
+	tablets, err := d.ts.GetTabletsByCell(ctx, cell)
+	if err != nil {
+		return err
+	}
+
+	// tabletsByShard are the tablets of the cell that are in an existing
+	// shard, by keyspace/shard.
+	tabletsByShard := make(map[string][]*topo.TabletInfo)
+	for _, ti := range tablets {
+		alias := ti.Alias
+		name := topoproto.KeyspaceShardString(ti.Keyspace, ti.Shard)
+		if ti.Keyspace == "" || d.shardExists(ti.Keyspace, ti.Shard) {
+			tabletsByShard[name] = append(tabletsByShard[name], ti)
+			continue
+		}
+		keyspace, shard := ti.Keyspace, ti.Shard
+		d.report(OrphanTablet, fmt.Sprintf("tablet %v is in missing shard %v", topoproto.TabletAliasString(alias), name), func(ctx context.Context) error {
+			if err := d.ts.DeleteTablet(ctx, alias); err != nil && !topo.IsErrType(err, topo.NoNode) {
+				return err
+			}
+			err := topo.RemoveShardReplicationRecord(ctx, d.ts, cell, keyspace, shard, alias)
+			if err != nil && !topo.IsErrType(err, topo.NoNode) {
+				return err
+			}
+			return nil
+		})
+	}
+
+	for _, keyspace := range sortedKeys(d.shards) {
+		for _, shard := range sortedKeys(d.shards[keyspace]) {
+			if err := d.checkReplicationGraph(ctx, cell, keyspace, shard, tabletsByShard[topoproto.KeyspaceShardString(keyspace, shard)]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return d.checkSrvKeyspaces(ctx, cell)
+}
+
+// checkReplicationGraph compares the ShardReplication of a shard in a cell
+// with the tablets of the shard in the cell.
+func (d *topoDoctor) checkReplicationGraph(ctx context.Context, cell, keyspace, shard string, tablets []*topo.TabletInfo) error {
+	name := topoproto.KeyspaceShardString(keyspace, shard)
+	inGraph := make(map[string]bool)
+	sri, err := d.ts.GetShardReplication(ctx, cell, keyspace, shard)
+	switch {
+	case err == nil:
+		for _, node := range sri.Nodes {
+			alias := node.TabletAlias
+			aliasStr := topoproto.TabletAliasString(alias)
+			inGraph[aliasStr] = true
+
+			ti, err := d.ts.GetTablet(ctx, alias)
+			var problem string
+			switch {
+			case topo.IsErrType(err, topo.NoNode):
+				problem = "does not exist"
+			case err != nil:
+				return fmt.Errorf("GetTablet(%v): %w", aliasStr, err)
+			case ti.Keyspace != keyspace || ti.Shard != shard || ti.Alias.Cell != cell:
+				problem = fmt.Sprintf("is in shard %v of cell %v", topoproto.KeyspaceShardString(ti.Keyspace, ti.Shard), ti.Alias.Cell)
+			default:
+				continue
+			}
+			d.report(BrokenReplicationGraph, fmt.Sprintf("tablet %v is in the replication graph of shard %v in cell %v, but %v", aliasStr, name, cell, problem), func(ctx context.Context) error {
+				return topo.RemoveShardReplicationRecord(ctx, d.ts, cell, keyspace, shard, alias)
+			})
+		}
+	case topo.IsErrType(err, topo.NoNode):
+	default:
+		return fmt.Errorf("GetShardReplication(%v, %v): %w", cell, name, err)
+	}
+
+	for _, ti := range tablets {
+		alias := ti.Alias
+		if inGraph[topoproto.TabletAliasString(alias)] {
+			continue
+		}
+		d.report(BrokenReplicationGraph, fmt.Sprintf("tablet %v is missing from the replication graph of shard %v in cell %v", topoproto.TabletAliasString(alias), name, cell), func(ctx context.Context) error {
+			return topo.UpdateShardReplicationRecord(ctx, d.ts, keyspace, shard, alias)
+		})
+	}
+	return nil
+}
+
+// checkSrvKeyspaces finds the SrvKeyspaces of a cell whose keyspace doesn't
+// exist, or referencing shards that don't exist.
+func (d *topoDoctor) checkSrvKeyspaces(ctx context.Context, cell string) error {
+	keyspaces, err := d.ts.GetSrvKeyspaceNames(ctx, cell)
+	if err != nil {
+		return fmt.Errorf("GetSrvKeyspaceNames(%v): %w", cell, err)
+	}
+
+	for _, keyspace := range keyspaces {
+		keyspace := keyspace
+
+		// The directory of a keyspace may remain after its SrvKeyspace
+		// was deleted.
+		srvKeyspace, err := d.ts.GetSrvKeyspace(ctx, cell, keyspace)
+		switch {
+		case err == nil:
+		case topo.IsErrType(err, topo.NoNode):
+			continue
+		default:
+			return fmt.Errorf("GetSrvKeyspace(%v, %v): %w", cell, keyspace, err)
+		}
+
+		if _, ok := d.shards[keyspace]; !ok {
+			d.report(StaleSrvKeyspace, fmt.Sprintf("the SrvKeyspace of missing keyspace %v is still in cell %v", keyspace, cell), func(ctx context.Context) error {
+				return d.ts.DeleteSrvKeyspace(ctx, cell, keyspace)
+			})
+			continue
+		}
+
+		missingShards := make(map[string]bool)
+		for _, partition := range srvKeyspace.Partitions {
+			for _, ref := range partition.ShardReferences {
+				if !d.shardExists(keyspace, ref.Name) {
+					missingShards[ref.Name] = true
+				}
+			}
+		}
+		if len(missingShards) > 0 {
+			d.report(StaleSrvKeyspace, fmt.Sprintf("the SrvKeyspace of keyspace %v in cell %v references missing shards %v", keyspace, cell, sortedKeys(missingShards)), func(ctx context.Context) error {
+				return RebuildKeyspace(ctx, d.logger, d.ts, keyspace, []string{cell}, false)
+			})
+		}
+	}
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
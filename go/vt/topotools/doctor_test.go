@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topotools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestTopoDoctor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1", "zone2")
+	defer ts.Close()
+	logger := logutil.NewMemoryLogger()
+
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}))
+	require.NoError(t, ts.CreateShard(ctx, "ks", "0"))
+	for _, tablet := range []*topodatapb.Tablet{
+		{Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}, Keyspace: "ks", Shard: "0"},
+		{Alias: &topodatapb.TabletAlias{Cell: "zone2", Uid: 200}, Keyspace: "ks", Shard: "0"},
+		// An orphan tablet.
+		{Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 101}, Keyspace: "ks", Shard: "-80"},
+	} {
+		require.NoError(t, ts.CreateTablet(ctx, tablet))
+	}
+	require.NoError(t, RebuildKeyspace(ctx, logger, ts, "ks", nil, false))
+
+	issues, err := TopoDoctor(ctx, ts, logger, TopoDoctorOptions{MaxLockAge: time.Hour})
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "OrphanTablet: tablet zone1-0000000101 is in missing shard ks/-80", issues[0].String())
+
+	// A tablet missing from the replication graph, and an entry of a
+	// missing tablet.
+	require.NoError(t, topo.RemoveShardReplicationRecord(ctx, ts, "zone2", "ks", "0", &topodatapb.TabletAlias{Cell: "zone2", Uid: 200}))
+	require.NoError(t, topo.UpdateShardReplicationRecord(ctx, ts, "ks", "0", &topodatapb.TabletAlias{Cell: "zone1", Uid: 102}))
+	// Tablet controls referencing a missing cell.
+	_, err = ts.UpdateShardFields(ctx, "ks", "0", func(si *topo.ShardInfo) error {
+		si.TabletControls = []*topodatapb.Shard_TabletControl{{
+			TabletType:   topodatapb.TabletType_RDONLY,
+			Cells:        []string{"zone1", "zone3"},
+			DeniedTables: []string{"t1"},
+		}}
+		return nil
+	})
+	require.NoError(t, err)
+	// Stale SrvKeyspaces.
+	require.NoError(t, ts.UpdateSrvKeyspace(ctx, "zone1", "deleted", &topodatapb.SrvKeyspace{}))
+	require.NoError(t, ts.UpdateSrvKeyspace(ctx, "zone2", "ks", &topodatapb.SrvKeyspace{
+		Partitions: []*topodatapb.SrvKeyspace_KeyspacePartition{{
+			ServedType:      topodatapb.TabletType_PRIMARY,
+			ShardReferences: []*topodatapb.ShardReference{{Name: "-80"}},
+		}},
+	}))
+	// A dangling lock, stored like etcd and ZooKeeper do.
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	require.NoError(t, err)
+	lock, err := json.Marshal(&topo.Lock{
+		Action:   "Reshard",
+		HostName: "host",
+		UserName: "user",
+		Time:     time.Now().Add(-2 * time.Hour).Format(time.RFC3339),
+	})
+	require.NoError(t, err)
+	_, err = conn.Create(ctx, "keyspaces/ks/shards/0/locks/1", lock)
+	require.NoError(t, err)
+
+	issues, err = TopoDoctor(ctx, ts, logger, TopoDoctorOptions{MaxLockAge: time.Hour})
+	require.NoError(t, err)
+	var got []string
+	for _, issue := range issues {
+		assert.False(t, issue.Repaired)
+		got = append(got, issue.String())
+	}
+	// Lock times only have a precision of a second, so the age of the lock
+	// can be rounded either way.
+	require.Len(t, got, 7)
+	assert.Regexp(t, `^DanglingLock: lock keyspaces/ks/shards/0/locks/1 was taken by user@host for Reshard 2h0m[01]s ago$`, got[1])
+	got = append(got[:1], got[2:]...)
+	assert.Equal(t, []string{
+		"MissingCell: the tablet controls of shard ks/0 reference missing cells [zone3]",
+		"OrphanTablet: tablet zone1-0000000101 is in missing shard ks/-80",
+		"BrokenReplicationGraph: tablet zone1-0000000102 is in the replication graph of shard ks/0 in cell zone1, but does not exist",
+		"StaleSrvKeyspace: the SrvKeyspace of missing keyspace deleted is still in cell zone1",
+		"BrokenReplicationGraph: tablet zone2-0000000200 is missing from the replication graph of shard ks/0 in cell zone2",
+		"StaleSrvKeyspace: the SrvKeyspace of keyspace ks in cell zone2 references missing shards [-80]",
+	}, got)
+
+	issues, err = TopoDoctor(ctx, ts, logger, TopoDoctorOptions{Repair: true, MaxLockAge: time.Hour})
+	require.NoError(t, err)
+	require.Len(t, issues, 7)
+	for _, issue := range issues {
+		assert.True(t, issue.Repaired, "%v", issue)
+	}
+
+	issues, err = TopoDoctor(ctx, ts, logger, TopoDoctorOptions{MaxLockAge: time.Hour})
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+
+	si, err := ts.GetShard(ctx, "ks", "0")
+	require.NoError(t, err)
+	require.Len(t, si.TabletControls, 1)
+	assert.Equal(t, []string{"zone1"}, si.TabletControls[0].Cells)
+	_, err = ts.GetTablet(ctx, &topodatapb.TabletAlias{Cell: "zone1", Uid: 101})
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "unexpected error: %v", err)
+	_, err = ts.GetSrvKeyspace(ctx, "zone1", "ks")
+	require.NoError(t, err)
+}
@@ -97,6 +97,7 @@ func RebuildKeyspaceLocked(ctx context.Context, log logutil.Logger, ts *topo.Ser
 			ServedFrom: ki.ComputeCellServedFrom(cell),
 		}
 		srvKeyspaceMap[cell].ThrottlerConfig = ki.ThrottlerConfig
+		srvKeyspaceMap[cell].OnlineDdlSchedulerConfig = ki.OnlineDdlSchedulerConfig
 	}
 
 	servedTypes := []topodatapb.TabletType{topodatapb.TabletType_PRIMARY, topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY}
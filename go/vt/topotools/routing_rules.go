@@ -19,6 +19,7 @@ package topotools
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	"vitess.io/vitess/go/vt/log"
@@ -55,15 +56,32 @@ func GetRoutingRules(ctx context.Context, ts *topo.Server) (map[string][]string,
 
 // SaveRoutingRules converts a mapping of fromTable=>[]toTables into a
 // vschemapb.RoutingRules protobuf message and saves it in the topology.
+// The rules that keep the targets of a rule splitting its traffic by
+// percentages keep its percentages.
 func SaveRoutingRules(ctx context.Context, ts *topo.Server, rules map[string][]string) error {
 	log.Infof("Saving routing rules %v\n", rules)
 
+	current, err := ts.GetRoutingRules(ctx)
+	if err != nil {
+		return err
+	}
+	splits := make(map[string]*vschemapb.RoutingRule)
+	for _, rr := range current.Rules {
+		if len(rr.Percentages) > 0 {
+			splits[rr.FromTable] = rr
+		}
+	}
+
 	rrs := &vschemapb.RoutingRules{Rules: make([]*vschemapb.RoutingRule, 0, len(rules))}
 	for from, to := range rules {
-		rrs.Rules = append(rrs.Rules, &vschemapb.RoutingRule{
+		rr := &vschemapb.RoutingRule{
 			FromTable: from,
 			ToTables:  to,
-		})
+		}
+		if split, ok := splits[from]; ok && slices.Equal(split.ToTables, to) {
+			rr.Percentages = split.Percentages
+		}
+		rrs.Rules = append(rrs.Rules, rr)
 	}
 
 	return ts.SaveRoutingRules(ctx, rrs)
@@ -25,6 +25,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
 )
 
 func TestRoutingRulesRoundTrip(t *testing.T) {
@@ -47,6 +49,34 @@ func TestRoutingRulesRoundTrip(t *testing.T) {
 	assert.Equal(t, rules, roundtripRules)
 }
 
+func TestRoutingRulesKeepPercentages(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+
+	err := ts.SaveRoutingRules(ctx, &vschemapb.RoutingRules{Rules: []*vschemapb.RoutingRule{
+		{FromTable: "t1", ToTables: []string{"ks1.t1", "ks2.t1"}, Percentages: []uint32{90, 10}},
+		{FromTable: "t2", ToTables: []string{"ks1.t2", "ks2.t2"}, Percentages: []uint32{90, 10}},
+	}})
+	require.NoError(t, err)
+
+	rules, err := GetRoutingRules(ctx, ts)
+	require.NoError(t, err)
+	rules["t2"] = []string{"ks2.t2"}
+	rules["t3"] = []string{"ks2.t3"}
+	err = SaveRoutingRules(ctx, ts, rules)
+	require.NoError(t, err)
+
+	rrs, err := ts.GetRoutingRules(ctx)
+	require.NoError(t, err)
+	percentages := make(map[string][]uint32)
+	for _, rr := range rrs.Rules {
+		percentages[rr.FromTable] = rr.Percentages
+	}
+	assert.Equal(t, map[string][]uint32{"t1": {90, 10}, "t2": nil, "t3": nil}, percentages)
+}
+
 func TestRoutingRulesErrors(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
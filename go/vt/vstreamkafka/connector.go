@@ -0,0 +1,497 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package vstreamkafka publishes the row events of a vtgate VStream to Kafka.
+
+Each table is published to the topic <prefix>.<keyspace>.<table>, with one
+message per changed row. The messages are encoded with Avro or Protobuf, and
+their schemas are registered in a schema registry compatible with the
+Confluent Schema Registry, under the subject <topic>-value. The message
+payload is an envelope with the row before and after the change, the
+operation, the keyspace and shard of the row, and the timestamp of the
+change.
+
+The messages of a row always go to the same partition of the topic. By
+default the key of a message is the primary key of the row, and its
+partition is the murmur2 hash of the key, as with the default partitioner
+of the Kafka clients. The messages can also be partitioned by keyspace_id,
+when the rules of the filter select the keyspace_id() of their tables: the
+partitions then cover the keyspace_id ranges the way the shards do.
+
+The position of the stream is saved after the messages of each transaction
+are acknowledged by Kafka, so a restarted connector resumes from the last
+published transaction, and delivers the messages at least once.
+*/
+package vstreamkafka
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vtgate/vtgateconn"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+const (
+	// PartitionByPrimaryKey partitions the messages by the hash of the
+	// primary key of their rows.
+	PartitionByPrimaryKey = "primary_key"
+	// PartitionByKeyspaceID partitions the messages by the keyspace_id of
+	// their rows, which the rules of the filter must select.
+	PartitionByKeyspaceID = "keyspace_id"
+)
+
+// keyspaceIDColumn is the name of the column of the keyspace_id()
+// of the rules.
+const keyspaceIDColumn = "keyspace_id"
+
+// Config is the configuration of a Connector.
+type Config struct {
+	// Keyspace is the keyspace to stream. It can be a regular expression
+	// like "/.*", or empty to stream all the keyspaces.
+	Keyspace string
+	// TabletType is the type of the tablets to stream from.
+	TabletType topodatapb.TabletType
+	// Filter selects the tables, columns and rows to stream.
+	// All the tables are streamed if it is nil.
+	Filter *binlogdatapb.Filter
+	// Copy publishes the existing rows of the tables before their changes.
+	// Otherwise, only the changes from the current position are published.
+	Copy bool
+
+	// Brokers are the bootstrap brokers of the Kafka cluster.
+	Brokers []string
+	// TopicPrefix is the prefix of the topics of the tables.
+	TopicPrefix string
+	// Encoding is the encoding of the messages: avro or protobuf.
+	Encoding string
+	// SchemaRegistryURL is the URL of the schema registry.
+	SchemaRegistryURL string
+	// PartitionBy is primary_key or keyspace_id.
+	PartitionBy string
+	// Timeout is the timeout of the produce requests.
+	Timeout time.Duration
+	// Compression is the compression of the produced batches: none, gzip,
+	// snappy, lz4 or zstd.
+	Compression string
+
+	// TLS connects to the brokers, and to the schema registry when its URL
+	// is https, with TLS. Their certificates are verified with TLSCA, or
+	// with the system roots if it is empty.
+	TLS bool
+	// TLSCA is the file of the CA of the certificates of the brokers.
+	TLSCA string
+	// TLSCert and TLSKey are the files of the client certificate and key,
+	// if the brokers authenticate the clients with their certificates.
+	TLSCert string
+	TLSKey  string
+	// TLSServerName is the name the certificates of the brokers are
+	// verified against, instead of their host names.
+	TLSServerName string
+	// SASLMechanism is PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512, to
+	// authenticate to the brokers as SASLUser, or empty to not
+	// authenticate.
+	SASLMechanism string
+	SASLUser      string
+	SASLPassword  string
+	// SchemaRegistryUser and SchemaRegistryPassword are the credentials of
+	// the basic authentication to the schema registry, if it requires one.
+	SchemaRegistryUser     string
+	SchemaRegistryPassword string
+
+	// PositionFile is the file where the position of the stream is saved.
+	// If empty, the position is only kept in memory.
+	PositionFile string
+}
+
+// VStreamer streams the events of vtgate. A *vtgateconn.VTGateConn is a VStreamer.
+type VStreamer interface {
+	VStream(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid, filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags) (vtgateconn.VStreamReader, error)
+}
+
+// Connector publishes the row events of a VStream to Kafka.
+type Connector struct {
+	config    Config
+	vstreamer VStreamer
+	producer  producer
+	registry  *schemaRegistry
+	encoder   encoder
+
+	// tables are the tables of the stream, by their name in the events.
+	tables map[string]*table
+	// position is the position of the last published transaction.
+	position *binlogdatapb.VGtid
+}
+
+// table is the state of a streamed table.
+type table struct {
+	fields     []*querypb.Field
+	topic      string
+	schemaID   int32
+	partitions int32
+	// keyColumns are the columns of the key of the messages.
+	keyColumns []int
+}
+
+// NewConnector returns a Connector that streams with the vstreamer.
+func NewConnector(config Config, vstreamer VStreamer) (*Connector, error) {
+	if len(config.Brokers) == 0 {
+		return nil, errors.New("no kafka brokers")
+	}
+	producer, err := newKafkaProducer(config)
+	if err != nil {
+		return nil, err
+	}
+	c, err := newConnector(config, vstreamer, producer)
+	if err != nil {
+		producer.close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func newConnector(config Config, vstreamer VStreamer, producer producer) (*Connector, error) {
+	enc, err := newEncoder(config.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	switch config.PartitionBy {
+	case "":
+		config.PartitionBy = PartitionByPrimaryKey
+	case PartitionByPrimaryKey, PartitionByKeyspaceID:
+	default:
+		return nil, fmt.Errorf("unsupported partitioning %q, must be one of %s, %s", config.PartitionBy, PartitionByPrimaryKey, PartitionByKeyspaceID)
+	}
+	if config.SchemaRegistryURL == "" {
+		return nil, errors.New("no schema registry URL")
+	}
+	registry, err := newSchemaRegistry(config)
+	if err != nil {
+		return nil, err
+	}
+	if config.Filter == nil {
+		config.Filter = &binlogdatapb.Filter{Rules: []*binlogdatapb.Rule{{Match: "/.*"}}}
+	}
+	return &Connector{
+		config:    config,
+		vstreamer: vstreamer,
+		producer:  producer,
+		registry:  registry,
+		encoder:   enc,
+		tables:    make(map[string]*table),
+	}, nil
+}
+
+// Run streams the events and publishes them until the stream fails or
+// the context is done. It can be called again to resume the stream from
+// the last published transaction.
+func (c *Connector) Run(ctx context.Context) error {
+	position, err := c.startPosition()
+	if err != nil {
+		return err
+	}
+	log.Infof("Streaming from %v", position)
+	// The fields of the tables are sent again by the new stream.
+	c.tables = make(map[string]*table)
+	reader, err := c.vstreamer.VStream(ctx, c.config.TabletType, position, c.config.Filter, &vtgatepb.VStreamFlags{})
+	if err != nil {
+		return err
+	}
+	var pending []*message
+	for {
+		events, err := reader.Recv()
+		if err != nil {
+			return err
+		}
+		var position *binlogdatapb.VGtid
+		for _, event := range events {
+			switch event.Type {
+			case binlogdatapb.VEventType_FIELD:
+				if err := c.setFields(ctx, event.FieldEvent); err != nil {
+					return err
+				}
+			case binlogdatapb.VEventType_ROW:
+				msgs, err := c.rowMessages(event)
+				if err != nil {
+					return err
+				}
+				pending = append(pending, msgs...)
+			case binlogdatapb.VEventType_VGTID:
+				position = event.Vgtid
+			}
+		}
+		if position == nil {
+			continue
+		}
+		if len(pending) > 0 {
+			if err := c.producer.produce(ctx, pending); err != nil {
+				return err
+			}
+			pending = nil
+		}
+		if err := c.savePosition(position); err != nil {
+			return err
+		}
+	}
+}
+
+// Close closes the connections to Kafka.
+func (c *Connector) Close() {
+	c.producer.close()
+}
+
+// startPosition returns the position of the last published transaction,
+// or the position of the position file, or the start of the stream.
+func (c *Connector) startPosition() (*binlogdatapb.VGtid, error) {
+	if c.position != nil {
+		return c.position, nil
+	}
+	if c.config.PositionFile != "" {
+		data, err := os.ReadFile(c.config.PositionFile)
+		switch {
+		case err == nil:
+			position := &binlogdatapb.VGtid{}
+			if err := protojson.Unmarshal(data, position); err != nil {
+				return nil, fmt.Errorf("cannot read the position file %s: %v", c.config.PositionFile, err)
+			}
+			return position, nil
+		case !os.IsNotExist(err):
+			return nil, err
+		}
+	}
+	gtid := "current"
+	if c.config.Copy {
+		gtid = ""
+	}
+	return &binlogdatapb.VGtid{
+		ShardGtids: []*binlogdatapb.ShardGtid{{
+			Keyspace: c.config.Keyspace,
+			Gtid:     gtid,
+		}},
+	}, nil
+}
+
+func (c *Connector) savePosition(position *binlogdatapb.VGtid) error {
+	c.position = position
+	if c.config.PositionFile == "" {
+		return nil
+	}
+	data, err := protojson.Marshal(position)
+	if err != nil {
+		return err
+	}
+	// Rename a temporary file, so that the position file is always complete.
+	tmp, err := os.CreateTemp(filepath.Dir(c.config.PositionFile), filepath.Base(c.config.PositionFile)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.config.PositionFile)
+}
+
+// setFields registers the schema of the table for its new fields.
+func (c *Connector) setFields(ctx context.Context, fieldEvent *binlogdatapb.FieldEvent) error {
+	t := &table{
+		fields: fieldEvent.Fields,
+		topic:  c.topic(fieldEvent.TableName),
+	}
+	schema, err := c.encoder.schema(c.namespace(fieldEvent.TableName), fieldEvent.Fields)
+	if err != nil {
+		return err
+	}
+	if t.schemaID, err = c.registry.register(ctx, t.topic+"-value", c.encoder.schemaType(), schema); err != nil {
+		return err
+	}
+	if t.partitions, err = c.producer.partitions(ctx, t.topic); err != nil {
+		return err
+	}
+	if t.partitions == 0 {
+		return fmt.Errorf("topic %s has no partitions", t.topic)
+	}
+	switch c.config.PartitionBy {
+	case PartitionByKeyspaceID:
+		for i, field := range t.fields {
+			if field.Name == keyspaceIDColumn {
+				t.keyColumns = []int{i}
+			}
+		}
+		if t.keyColumns == nil {
+			return fmt.Errorf("partitioning by keyspace_id needs the rule of table %s to select keyspace_id()", fieldEvent.TableName)
+		}
+	default:
+		for i, field := range t.fields {
+			if field.Flags&uint32(querypb.MySqlFlag_PRI_KEY_FLAG) != 0 {
+				t.keyColumns = append(t.keyColumns, i)
+			}
+		}
+		if t.keyColumns == nil {
+			// Without a primary key, the messages of the same values go
+			// to the same partition.
+			for i := range t.fields {
+				t.keyColumns = append(t.keyColumns, i)
+			}
+		}
+	}
+	c.tables[fieldEvent.TableName] = t
+	return nil
+}
+
+// topic returns the topic of a table. The tables of the events that vtgate
+// sends are qualified by their keyspace.
+func (c *Connector) topic(tableName string) string {
+	if c.config.TopicPrefix == "" {
+		return tableName
+	}
+	return c.config.TopicPrefix + "." + tableName
+}
+
+// namespace returns the namespace of the schema of a table.
+func (c *Connector) namespace(tableName string) string {
+	parts := strings.Split(c.topic(tableName), ".")
+	for i, part := range parts {
+		parts[i] = fieldName(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// rowMessages returns the messages of the changed rows of a row event.
+func (c *Connector) rowMessages(event *binlogdatapb.VEvent) ([]*message, error) {
+	t, ok := c.tables[event.RowEvent.TableName]
+	if !ok {
+		return nil, fmt.Errorf("row event of table %s before its fields", event.RowEvent.TableName)
+	}
+	header := c.encoder.header(t.schemaID)
+	var msgs []*message
+	for _, rowChange := range event.RowEvent.RowChanges {
+		ch := &change{
+			keyspace: event.RowEvent.Keyspace,
+			shard:    event.RowEvent.Shard,
+			tsMs:     event.Timestamp * 1000,
+		}
+		if rowChange.Before != nil {
+			ch.before = sqltypes.MakeRowTrusted(t.fields, rowChange.Before)
+		}
+		if rowChange.After != nil {
+			ch.after = sqltypes.MakeRowTrusted(t.fields, rowChange.After)
+		}
+		if ch.keyspace == "" {
+			ch.keyspace = event.Keyspace
+			ch.shard = event.Shard
+		}
+		value, err := c.encoder.encode(t.fields, ch)
+		if err != nil {
+			return nil, err
+		}
+		row := ch.after
+		if row == nil {
+			row = ch.before
+		}
+		msg := &message{
+			topic: t.topic,
+			value: append(append([]byte(nil), header...), value...),
+		}
+		switch c.config.PartitionBy {
+		case PartitionByKeyspaceID:
+			msg.key = row[t.keyColumns[0]].Raw()
+			msg.partition = keyspaceIDPartition(msg.key, t.partitions)
+		default:
+			values := make([]string, len(t.keyColumns))
+			for i, col := range t.keyColumns {
+				values[i] = row[col].ToString()
+			}
+			if msg.key, err = json.Marshal(values); err != nil {
+				return nil, err
+			}
+			msg.partition = hashPartition(msg.key, t.partitions)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+// keyspaceIDPartition maps the keyspace_id range to the partitions, so that
+// each partition covers an equal range of keyspace_ids like the shards.
+func keyspaceIDPartition(keyspaceID []byte, partitions int32) int32 {
+	var prefix [8]byte
+	copy(prefix[:], keyspaceID)
+	hi, _ := bits.Mul64(binary.BigEndian.Uint64(prefix[:]), uint64(partitions))
+	return int32(hi)
+}
+
+// hashPartition returns the partition of a key like the default partitioner
+// of the Kafka clients.
+func hashPartition(key []byte, partitions int32) int32 {
+	return (murmur2(key) & 0x7fffffff) % partitions
+}
+
+// murmur2 is the murmur2 hash of the Kafka clients.
+func murmur2(data []byte) int32 {
+	const (
+		seed = uint32(0x9747b28c)
+		m    = uint32(0x5bd1e995)
+		r    = 24
+	)
+	length := len(data)
+	h := seed ^ uint32(length)
+	for i := 0; i+4 <= length; i += 4 {
+		k := binary.LittleEndian.Uint32(data[i:])
+		k *= m
+		k ^= k >> r
+		k *= m
+		h *= m
+		h ^= k
+	}
+	tail := data[length&^3:]
+	switch len(tail) {
+	case 3:
+		h ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(tail[0])
+		h *= m
+	}
+	h ^= h >> 13
+	h *= m
+	h ^= h >> 15
+	return int32(h)
+}
@@ -0,0 +1,273 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vstreamkafka
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/vtgate/vtgateconn"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+type fakeVStreamer struct {
+	vgtid  *binlogdatapb.VGtid
+	filter *binlogdatapb.Filter
+	events [][]*binlogdatapb.VEvent
+}
+
+func (fv *fakeVStreamer) VStream(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid, filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags) (vtgateconn.VStreamReader, error) {
+	fv.vgtid = vgtid
+	fv.filter = filter
+	return fv, nil
+}
+
+func (fv *fakeVStreamer) Recv() ([]*binlogdatapb.VEvent, error) {
+	if len(fv.events) == 0 {
+		return nil, io.EOF
+	}
+	events := fv.events[0]
+	fv.events = fv.events[1:]
+	return events, nil
+}
+
+type fakeProducer struct {
+	partitionCount map[string]int32
+	produced       []*message
+}
+
+func (fp *fakeProducer) partitions(ctx context.Context, topic string) (int32, error) {
+	return fp.partitionCount[topic], nil
+}
+
+func (fp *fakeProducer) produce(ctx context.Context, msgs []*message) error {
+	fp.produced = append(fp.produced, msgs...)
+	return nil
+}
+
+func (fp *fakeProducer) close() {}
+
+// newFakeSchemaRegistry returns a schema registry that registers all the
+// schemas with the id 42, and the schemas it registered by subject.
+func newFakeSchemaRegistry(t *testing.T) (*httptest.Server, map[string]string) {
+	schemas := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		switch {
+		case r.Method == http.MethodPost && len(path) == 3 && path[0] == "subjects" && path[2] == "versions":
+			var request struct {
+				Schema     string `json:"schema"`
+				SchemaType string `json:"schemaType"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+			schemas[path[1]] = request.Schema
+			w.Write([]byte(`{"id": 42}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/schemas/ids/42/versions":
+			var versions []map[string]any
+			for subject := range schemas {
+				versions = append(versions, map[string]any{"subject": subject, "version": 1})
+			}
+			assert.NoError(t, json.NewEncoder(w).Encode(versions))
+		case r.Method == http.MethodGet && len(path) == 4 && path[0] == "subjects" && path[3] == "1":
+			assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{"subject": path[1], "version": 1, "id": 42, "schema": schemas[path[1]]}))
+		default:
+			t.Errorf("unexpected schema registry request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, schemas
+}
+
+func TestConnector(t *testing.T) {
+	ctx := context.Background()
+	registry, schemas := newFakeSchemaRegistry(t)
+	positionFile := filepath.Join(t.TempDir(), "position")
+
+	fields := []*querypb.Field{
+		{Name: "id", Type: sqltypes.Int64, Flags: uint32(querypb.MySqlFlag_PRI_KEY_FLAG)},
+		{Name: "name", Type: sqltypes.VarChar},
+	}
+	row := func(id int64, name string) *querypb.Row {
+		return sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt64(id), sqltypes.NewVarChar(name)})
+	}
+	position := &binlogdatapb.VGtid{ShardGtids: []*binlogdatapb.ShardGtid{{Keyspace: "commerce", Shard: "0", Gtid: "MySQL56/a:1-5"}}}
+	vstreamer := &fakeVStreamer{
+		events: [][]*binlogdatapb.VEvent{{
+			{Type: binlogdatapb.VEventType_BEGIN},
+			{Type: binlogdatapb.VEventType_FIELD, FieldEvent: &binlogdatapb.FieldEvent{TableName: "commerce.customer", Fields: fields}},
+		}, {
+			{Type: binlogdatapb.VEventType_ROW, Timestamp: 2, RowEvent: &binlogdatapb.RowEvent{
+				TableName: "commerce.customer",
+				Keyspace:  "commerce",
+				Shard:     "0",
+				RowChanges: []*binlogdatapb.RowChange{
+					{After: row(1, "alice")},
+					{Before: row(2, "bob"), After: row(2, "robert")},
+				},
+			}},
+			{Type: binlogdatapb.VEventType_VGTID, Vgtid: position},
+			{Type: binlogdatapb.VEventType_COMMIT},
+		}},
+	}
+	producer := &fakeProducer{partitionCount: map[string]int32{"vitess.commerce.customer": 3}}
+	c, err := newConnector(Config{
+		Keyspace:          "commerce",
+		TopicPrefix:       "vitess",
+		Encoding:          EncodingAvro,
+		SchemaRegistryURL: registry.URL,
+		PositionFile:      positionFile,
+	}, vstreamer, producer)
+	require.NoError(t, err)
+	defer c.Close()
+
+	err = c.Run(ctx)
+	assert.Equal(t, io.EOF, err)
+	assert.True(t, proto.Equal(&binlogdatapb.VGtid{ShardGtids: []*binlogdatapb.ShardGtid{{Keyspace: "commerce", Gtid: "current"}}}, vstreamer.vgtid), vstreamer.vgtid)
+	assert.Equal(t, "/.*", vstreamer.filter.Rules[0].Match)
+	assert.Contains(t, schemas, "vitess.commerce.customer-value")
+
+	require.Len(t, producer.produced, 2)
+	for i, want := range []struct {
+		key   string
+		after []sqltypes.Value
+	}{
+		{key: `["1"]`, after: []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarChar("alice")}},
+		{key: `["2"]`, after: []sqltypes.Value{sqltypes.NewInt64(2), sqltypes.NewVarChar("robert")}},
+	} {
+		msg := producer.produced[i]
+		assert.Equal(t, "vitess.commerce.customer", msg.topic)
+		assert.Equal(t, want.key, string(msg.key))
+		assert.Equal(t, hashPartition(msg.key, 3), msg.partition)
+		assert.Equal(t, []byte{0, 0, 0, 0, 42}, msg.value[:5])
+	}
+	value, err := avroEncoder{}.encode(fields, &change{
+		keyspace: "commerce",
+		shard:    "0",
+		before:   []sqltypes.Value{sqltypes.NewInt64(2), sqltypes.NewVarChar("bob")},
+		after:    []sqltypes.Value{sqltypes.NewInt64(2), sqltypes.NewVarChar("robert")},
+		tsMs:     2000,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, value, producer.produced[1].value[5:])
+
+	// The position is saved, and a restarted connector resumes from it.
+	data, err := os.ReadFile(positionFile)
+	require.NoError(t, err)
+	saved := &binlogdatapb.VGtid{}
+	require.NoError(t, protojson.Unmarshal(data, saved))
+	assert.True(t, proto.Equal(position, saved), saved)
+
+	c, err = newConnector(Config{
+		Keyspace:          "commerce",
+		Encoding:          EncodingAvro,
+		SchemaRegistryURL: registry.URL,
+		PositionFile:      positionFile,
+	}, vstreamer, producer)
+	require.NoError(t, err)
+	assert.Equal(t, io.EOF, c.Run(ctx))
+	assert.True(t, proto.Equal(position, vstreamer.vgtid), vstreamer.vgtid)
+}
+
+func TestConnectorPartitionByKeyspaceID(t *testing.T) {
+	ctx := context.Background()
+	registry, _ := newFakeSchemaRegistry(t)
+	fields := []*querypb.Field{
+		{Name: "id", Type: sqltypes.Int64},
+		{Name: "keyspace_id", Type: sqltypes.VarBinary},
+	}
+	vstreamer := &fakeVStreamer{
+		events: [][]*binlogdatapb.VEvent{{
+			{Type: binlogdatapb.VEventType_FIELD, FieldEvent: &binlogdatapb.FieldEvent{TableName: "customer.customer", Fields: fields}},
+			{Type: binlogdatapb.VEventType_ROW, RowEvent: &binlogdatapb.RowEvent{
+				TableName: "customer.customer",
+				RowChanges: []*binlogdatapb.RowChange{
+					{Before: sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt64(1), sqltypes.MakeTrusted(sqltypes.VarBinary, []byte("\x16k@\xb4J\xbaK\xd6"))})},
+				},
+			}},
+			{Type: binlogdatapb.VEventType_VGTID, Vgtid: &binlogdatapb.VGtid{}},
+		}},
+	}
+	producer := &fakeProducer{partitionCount: map[string]int32{"customer.customer": 4, "customer.corder": 4}}
+	c, err := newConnector(Config{
+		Encoding:          EncodingProtobuf,
+		SchemaRegistryURL: registry.URL,
+		PartitionBy:       PartitionByKeyspaceID,
+	}, vstreamer, producer)
+	require.NoError(t, err)
+	assert.Equal(t, io.EOF, c.Run(ctx))
+	require.Len(t, producer.produced, 1)
+	assert.Equal(t, "customer.customer", producer.produced[0].topic)
+	assert.Equal(t, []byte("\x16k@\xb4J\xbaK\xd6"), producer.produced[0].key)
+	assert.EqualValues(t, 0, producer.produced[0].partition)
+
+	// The keyspace_id must be selected.
+	vstreamer.events = [][]*binlogdatapb.VEvent{{
+		{Type: binlogdatapb.VEventType_FIELD, FieldEvent: &binlogdatapb.FieldEvent{TableName: "customer.corder", Fields: fields[:1]}},
+	}}
+	assert.EqualError(t, c.Run(ctx), "partitioning by keyspace_id needs the rule of table customer.corder to select keyspace_id()")
+}
+
+func TestNewConnectorErrors(t *testing.T) {
+	_, err := newConnector(Config{Encoding: "json", SchemaRegistryURL: "http://registry"}, nil, nil)
+	assert.EqualError(t, err, `unsupported encoding "json", must be one of avro, protobuf`)
+	_, err = newConnector(Config{Encoding: EncodingAvro, SchemaRegistryURL: "http://registry", PartitionBy: "shard"}, nil, nil)
+	assert.EqualError(t, err, `unsupported partitioning "shard", must be one of primary_key, keyspace_id`)
+	_, err = newConnector(Config{Encoding: EncodingAvro}, nil, nil)
+	assert.EqualError(t, err, "no schema registry URL")
+	_, err = NewConnector(Config{Encoding: EncodingAvro}, nil)
+	assert.EqualError(t, err, "no kafka brokers")
+}
+
+func TestKeyspaceIDPartition(t *testing.T) {
+	assert.EqualValues(t, 0, keyspaceIDPartition([]byte{0x00}, 4))
+	assert.EqualValues(t, 1, keyspaceIDPartition([]byte{0x40}, 4))
+	assert.EqualValues(t, 2, keyspaceIDPartition([]byte{0xbf, 0xff}, 4))
+	assert.EqualValues(t, 3, keyspaceIDPartition([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, 4))
+	assert.EqualValues(t, 0, keyspaceIDPartition(nil, 1))
+}
+
+func TestMurmur2(t *testing.T) {
+	// The hashes of the Kafka clients.
+	for key, want := range map[string]int32{
+		"21":                         -973932308,
+		"foobar":                     -790332482,
+		"a-little-bit-long-string":   -985981536,
+		"a-little-bit-longer-string": -1486304829,
+		"lkjh234lh9fiuh90y23oiuhsafujhadof229phr9h19h89h8": -58897971,
+		"abc": 479470107,
+	} {
+		assert.Equal(t, want, murmur2([]byte(key)), key)
+	}
+}
@@ -0,0 +1,359 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vstreamkafka
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/twmb/franz-go/pkg/sr"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+const (
+	// EncodingAvro encodes the row changes with Avro.
+	EncodingAvro = "avro"
+	// EncodingProtobuf encodes the row changes with Protobuf.
+	EncodingProtobuf = "protobuf"
+)
+
+// change is a row change of a table, with the values of the row before
+// and after the change. before is nil for an insert, and after is nil
+// for a delete.
+type change struct {
+	keyspace string
+	shard    string
+	before   []sqltypes.Value
+	after    []sqltypes.Value
+	tsMs     int64
+}
+
+func (ch *change) op() string {
+	switch {
+	case ch.before == nil:
+		return "insert"
+	case ch.after == nil:
+		return "delete"
+	}
+	return "update"
+}
+
+// encoder encodes the row changes of a table. Both encodings use the same
+// envelope: the optional before and after rows, the operation, the keyspace
+// and shard of the row, and the timestamp of the change in milliseconds.
+type encoder interface {
+	// schemaType is the type of the schema for the schema registry.
+	schemaType() sr.SchemaType
+	// schema returns the schema of the changes of a table with these fields.
+	schema(namespace string, fields []*querypb.Field) (string, error)
+	// encode encodes a change, without the header of the schema registry.
+	encode(fields []*querypb.Field, ch *change) ([]byte, error)
+	// header returns the header of the messages with the schema id.
+	header(schemaID int32) []byte
+}
+
+func newEncoder(encoding string) (encoder, error) {
+	switch encoding {
+	case EncodingAvro:
+		return avroEncoder{}, nil
+	case EncodingProtobuf:
+		return protobufEncoder{}, nil
+	}
+	return nil, fmt.Errorf("unsupported encoding %q, must be one of %s, %s", encoding, EncodingAvro, EncodingProtobuf)
+}
+
+// columnType is the type of the encoded values of a column.
+type columnType int
+
+const (
+	columnLong columnType = iota
+	columnUnsignedLong
+	columnDouble
+	columnBytes
+	columnString
+)
+
+func typeOf(field *querypb.Field) columnType {
+	switch {
+	case field.Type == sqltypes.Uint64:
+		return columnUnsignedLong
+	case sqltypes.IsIntegral(field.Type):
+		return columnLong
+	case sqltypes.IsFloat(field.Type):
+		return columnDouble
+	case sqltypes.IsBinary(field.Type) || field.Type == sqltypes.Bit || field.Type == sqltypes.Geometry:
+		return columnBytes
+	}
+	// Decimals, texts, temporal types, enums, sets and JSON are sent as strings.
+	return columnString
+}
+
+// fieldName returns the name of a column as a name valid in Avro and Protobuf.
+func fieldName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
+
+// avroEncoder encodes the changes with the Avro binary encoding.
+type avroEncoder struct{}
+
+func (avroEncoder) schemaType() sr.SchemaType {
+	return sr.TypeAvro
+}
+
+func (avroEncoder) header(schemaID int32) []byte {
+	// The wire format of the schema registry: a zero magic byte and the
+	// big endian schema id.
+	return binary.BigEndian.AppendUint32([]byte{0}, uint32(schemaID))
+}
+
+type avroField struct {
+	Name    string `json:"name"`
+	Type    any    `json:"type"`
+	Default any    `json:"default"`
+}
+
+type avroRecord struct {
+	Type   string      `json:"type"`
+	Name   string      `json:"name"`
+	Fields []avroField `json:"fields"`
+}
+
+func (avroEncoder) schema(namespace string, fields []*querypb.Field) (string, error) {
+	row := avroRecord{Type: "record", Name: "Row"}
+	for _, field := range fields {
+		var typ string
+		switch typeOf(field) {
+		case columnLong:
+			typ = "long"
+		case columnDouble:
+			typ = "double"
+		case columnBytes:
+			typ = "bytes"
+		default:
+			// Avro has no unsigned type, so unsigned bigints are strings.
+			typ = "string"
+		}
+		row.Fields = append(row.Fields, avroField{Name: fieldName(field.Name), Type: []string{"null", typ}})
+	}
+	envelope := struct {
+		Type      string `json:"type"`
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+		Fields    []any  `json:"fields"`
+	}{
+		Type:      "record",
+		Name:      "Envelope",
+		Namespace: namespace,
+		Fields: []any{
+			avroField{Name: "before", Type: []any{"null", row}},
+			avroField{Name: "after", Type: []any{"null", "Row"}},
+			map[string]string{"name": "op", "type": "string"},
+			map[string]string{"name": "keyspace", "type": "string"},
+			map[string]string{"name": "shard", "type": "string"},
+			map[string]string{"name": "ts_ms", "type": "long"},
+		},
+	}
+	schema, err := json.Marshal(envelope)
+	if err != nil {
+		return "", err
+	}
+	return string(schema), nil
+}
+
+func (avroEncoder) encode(fields []*querypb.Field, ch *change) ([]byte, error) {
+	var buf []byte
+	for _, row := range [][]sqltypes.Value{ch.before, ch.after} {
+		if row == nil {
+			buf = avroAppendLong(buf, 0)
+			continue
+		}
+		buf = avroAppendLong(buf, 1)
+		for i, field := range fields {
+			if row[i].IsNull() {
+				buf = avroAppendLong(buf, 0)
+				continue
+			}
+			buf = avroAppendLong(buf, 1)
+			switch typeOf(field) {
+			case columnLong:
+				v, err := row[i].ToInt64()
+				if err != nil {
+					return nil, err
+				}
+				buf = avroAppendLong(buf, v)
+			case columnDouble:
+				v, err := row[i].ToFloat64()
+				if err != nil {
+					return nil, err
+				}
+				buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(v))
+			default:
+				buf = avroAppendBytes(buf, row[i].Raw())
+			}
+		}
+	}
+	buf = avroAppendBytes(buf, []byte(ch.op()))
+	buf = avroAppendBytes(buf, []byte(ch.keyspace))
+	buf = avroAppendBytes(buf, []byte(ch.shard))
+	buf = avroAppendLong(buf, ch.tsMs)
+	return buf, nil
+}
+
+// avroAppendLong appends a zigzag encoded long, which also encodes
+// the ints and the indexes of the unions.
+func avroAppendLong(buf []byte, v int64) []byte {
+	return binary.AppendVarint(buf, v)
+}
+
+// avroAppendBytes appends the length and the bytes, which also encodes
+// the strings.
+func avroAppendBytes(buf, b []byte) []byte {
+	return append(avroAppendLong(buf, int64(len(b))), b...)
+}
+
+// protobufEncoder encodes the changes with Protobuf. The schema declares
+// the row as a nested message of optional fields, so that the null values
+// are not sent.
+type protobufEncoder struct{}
+
+func (protobufEncoder) schemaType() sr.SchemaType {
+	return sr.TypeProtobuf
+}
+
+func (protobufEncoder) header(schemaID int32) []byte {
+	// The wire format of the schema registry: a zero magic byte, the big
+	// endian schema id, and the indexes of the message in the schema,
+	// which are a single zero for the first message.
+	return append(binary.BigEndian.AppendUint32([]byte{0}, uint32(schemaID)), 0)
+}
+
+func (protobufEncoder) schema(namespace string, fields []*querypb.Field) (string, error) {
+	var b strings.Builder
+	b.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&b, "package %s;\n\n", namespace)
+	b.WriteString("message Envelope {\n")
+	b.WriteString("  message Row {\n")
+	for i, field := range fields {
+		var typ string
+		switch typeOf(field) {
+		case columnLong:
+			typ = "int64"
+		case columnUnsignedLong:
+			typ = "uint64"
+		case columnDouble:
+			typ = "double"
+		case columnBytes:
+			typ = "bytes"
+		default:
+			typ = "string"
+		}
+		fmt.Fprintf(&b, "    optional %s %s = %d;\n", typ, fieldName(field.Name), i+1)
+	}
+	b.WriteString("  }\n")
+	b.WriteString("  Row before = 1;\n")
+	b.WriteString("  Row after = 2;\n")
+	b.WriteString("  string op = 3;\n")
+	b.WriteString("  string keyspace = 4;\n")
+	b.WriteString("  string shard = 5;\n")
+	b.WriteString("  int64 ts_ms = 6;\n")
+	b.WriteString("}\n")
+	return b.String(), nil
+}
+
+func (protobufEncoder) encode(fields []*querypb.Field, ch *change) ([]byte, error) {
+	var buf []byte
+	for num, row := range [][]sqltypes.Value{ch.before, ch.after} {
+		if row == nil {
+			continue
+		}
+		var rowBuf []byte
+		for i, field := range fields {
+			if row[i].IsNull() {
+				continue
+			}
+			fieldNum := protowire.Number(i + 1)
+			switch typeOf(field) {
+			case columnLong:
+				v, err := row[i].ToInt64()
+				if err != nil {
+					return nil, err
+				}
+				rowBuf = protowire.AppendTag(rowBuf, fieldNum, protowire.VarintType)
+				rowBuf = protowire.AppendVarint(rowBuf, uint64(v))
+			case columnUnsignedLong:
+				v, err := strconv.ParseUint(row[i].ToString(), 10, 64)
+				if err != nil {
+					return nil, err
+				}
+				rowBuf = protowire.AppendTag(rowBuf, fieldNum, protowire.VarintType)
+				rowBuf = protowire.AppendVarint(rowBuf, v)
+			case columnDouble:
+				v, err := row[i].ToFloat64()
+				if err != nil {
+					return nil, err
+				}
+				rowBuf = protowire.AppendTag(rowBuf, fieldNum, protowire.Fixed64Type)
+				rowBuf = protowire.AppendFixed64(rowBuf, math.Float64bits(v))
+			default:
+				rowBuf = protowire.AppendTag(rowBuf, fieldNum, protowire.BytesType)
+				rowBuf = protowire.AppendBytes(rowBuf, row[i].Raw())
+			}
+		}
+		buf = protowire.AppendTag(buf, protowire.Number(num+1), protowire.BytesType)
+		buf = protowire.AppendBytes(buf, rowBuf)
+	}
+	buf = protowire.AppendTag(buf, 3, protowire.BytesType)
+	buf = protowire.AppendString(buf, ch.op())
+	if ch.keyspace != "" {
+		buf = protowire.AppendTag(buf, 4, protowire.BytesType)
+		buf = protowire.AppendString(buf, ch.keyspace)
+	}
+	if ch.shard != "" {
+		buf = protowire.AppendTag(buf, 5, protowire.BytesType)
+		buf = protowire.AppendString(buf, ch.shard)
+	}
+	if ch.tsMs != 0 {
+		buf = protowire.AppendTag(buf, 6, protowire.VarintType)
+		buf = protowire.AppendVarint(buf, uint64(ch.tsMs))
+	}
+	return buf, nil
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vstreamkafka
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+var testFields = []*querypb.Field{
+	{Name: "id", Type: sqltypes.Int64},
+	{Name: "balance", Type: sqltypes.Float64},
+	{Name: "name", Type: sqltypes.VarChar},
+	{Name: "photo", Type: sqltypes.Blob},
+	{Name: "big", Type: sqltypes.Uint64},
+	{Name: "2nd-col", Type: sqltypes.Decimal},
+}
+
+func TestFieldName(t *testing.T) {
+	assert.Equal(t, "id", fieldName("id"))
+	assert.Equal(t, "_2nd_col", fieldName("2nd-col"))
+	assert.Equal(t, "_", fieldName(""))
+}
+
+func TestAvroSchema(t *testing.T) {
+	schema, err := avroEncoder{}.schema("vitess.commerce.customer", testFields)
+	require.NoError(t, err)
+
+	var envelope struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+		Fields    []struct {
+			Name string          `json:"name"`
+			Type json.RawMessage `json:"type"`
+		} `json:"fields"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(schema), &envelope))
+	assert.Equal(t, "Envelope", envelope.Name)
+	assert.Equal(t, "vitess.commerce.customer", envelope.Namespace)
+	var names []string
+	for _, field := range envelope.Fields {
+		names = append(names, field.Name)
+	}
+	assert.Equal(t, []string{"before", "after", "op", "keyspace", "shard", "ts_ms"}, names)
+	assert.JSONEq(t, `["null", "Row"]`, string(envelope.Fields[1].Type))
+	assert.JSONEq(t, `["null", {"type": "record", "name": "Row", "fields": [
+		{"name": "id", "type": ["null", "long"], "default": null},
+		{"name": "balance", "type": ["null", "double"], "default": null},
+		{"name": "name", "type": ["null", "string"], "default": null},
+		{"name": "photo", "type": ["null", "bytes"], "default": null},
+		{"name": "big", "type": ["null", "string"], "default": null},
+		{"name": "_2nd_col", "type": ["null", "string"], "default": null}
+	]}]`, string(envelope.Fields[0].Type))
+}
+
+func TestAvroEncode(t *testing.T) {
+	fields := testFields[:3]
+	ch := &change{
+		keyspace: "commerce",
+		shard:    "-80",
+		after:    []sqltypes.Value{sqltypes.NewInt64(-2), sqltypes.NewFloat64(1.5), sqltypes.NULL},
+		tsMs:     1000,
+	}
+	got, err := avroEncoder{}.encode(fields, ch)
+	require.NoError(t, err)
+
+	var want []byte
+	want = append(want, 0)    // before: null
+	want = append(want, 2)    // after: the row
+	want = append(want, 2, 3) // id: -2
+	want = append(want, 2)    // balance
+	want = binary.LittleEndian.AppendUint64(want, math.Float64bits(1.5))
+	want = append(want, 0) // name: null
+	want = append(want, 12, 'i', 'n', 's', 'e', 'r', 't')
+	want = append(want, 16, 'c', 'o', 'm', 'm', 'e', 'r', 'c', 'e')
+	want = append(want, 6, '-', '8', '0')
+	want = append(want, 0xd0, 0x0f) // ts_ms: 1000
+	assert.Equal(t, want, got)
+
+	assert.Equal(t, []byte{0, 0, 0, 1, 2}, avroEncoder{}.header(258))
+}
+
+func TestProtobufSchema(t *testing.T) {
+	schema, err := protobufEncoder{}.schema("vitess.commerce.customer", testFields)
+	require.NoError(t, err)
+	assert.Equal(t, `syntax = "proto3";
+
+package vitess.commerce.customer;
+
+message Envelope {
+  message Row {
+    optional int64 id = 1;
+    optional double balance = 2;
+    optional string name = 3;
+    optional bytes photo = 4;
+    optional uint64 big = 5;
+    optional string _2nd_col = 6;
+  }
+  Row before = 1;
+  Row after = 2;
+  string op = 3;
+  string keyspace = 4;
+  string shard = 5;
+  int64 ts_ms = 6;
+}
+`, schema)
+}
+
+func TestProtobufEncode(t *testing.T) {
+	ch := &change{
+		keyspace: "commerce",
+		shard:    "-80",
+		before:   []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NULL, sqltypes.NewVarChar("a"), sqltypes.NULL, sqltypes.NewUint64(math.MaxUint64), sqltypes.NULL},
+		tsMs:     1000,
+	}
+	got, err := protobufEncoder{}.encode(testFields, ch)
+	require.NoError(t, err)
+
+	values := make(map[protowire.Number][]byte)
+	for len(got) > 0 {
+		num, typ, n := protowire.ConsumeTag(got)
+		require.GreaterOrEqual(t, n, 0)
+		got = got[n:]
+		n = protowire.ConsumeFieldValue(num, typ, got)
+		require.GreaterOrEqual(t, n, 0)
+		values[num] = got[:n]
+		got = got[n:]
+	}
+	assert.NotContains(t, values, protowire.Number(2))
+	op, _ := protowire.ConsumeString(values[3])
+	assert.Equal(t, "delete", op)
+	keyspace, _ := protowire.ConsumeString(values[4])
+	assert.Equal(t, "commerce", keyspace)
+	tsMs, _ := protowire.ConsumeVarint(values[6])
+	assert.EqualValues(t, 1000, tsMs)
+
+	before, _ := protowire.ConsumeBytes(values[1])
+	var row []byte
+	row = protowire.AppendTag(row, 1, protowire.VarintType)
+	row = protowire.AppendVarint(row, 1)
+	row = protowire.AppendTag(row, 3, protowire.BytesType)
+	row = protowire.AppendString(row, "a")
+	row = protowire.AppendTag(row, 5, protowire.VarintType)
+	row = protowire.AppendVarint(row, math.MaxUint64)
+	assert.Equal(t, row, before)
+
+	assert.Equal(t, []byte{0, 0, 0, 1, 2, 0}, protobufEncoder{}.header(258))
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vstreamkafka
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"github.com/twmb/franz-go/pkg/sasl"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+
+	"vitess.io/vitess/go/vt/vttls"
+)
+
+const (
+	// SASLPlain authenticates to the brokers with SASL/PLAIN.
+	SASLPlain = "PLAIN"
+	// SASLScramSHA256 authenticates to the brokers with SASL/SCRAM-SHA-256.
+	SASLScramSHA256 = "SCRAM-SHA-256"
+	// SASLScramSHA512 authenticates to the brokers with SASL/SCRAM-SHA-512.
+	SASLScramSHA512 = "SCRAM-SHA-512"
+)
+
+const (
+	metadataRetries = 5
+	retryBackoff    = 500 * time.Millisecond
+)
+
+// message is a Kafka message.
+type message struct {
+	topic     string
+	partition int32
+	key       []byte
+	value     []byte
+}
+
+// producer publishes messages to Kafka.
+type producer interface {
+	// partitions returns the number of partitions of the topic.
+	partitions(ctx context.Context, topic string) (int32, error)
+	// produce publishes the messages, and returns once all the in-sync
+	// replicas of their partitions have them.
+	produce(ctx context.Context, msgs []*message) error
+	close()
+}
+
+var _ producer = (*kafkaProducer)(nil)
+
+// kafkaProducer publishes messages to the brokers of a Kafka cluster with
+// the franz-go client. The messages are sent to the partitions chosen by
+// the connector.
+type kafkaProducer struct {
+	client *kgo.Client
+
+	mu              sync.Mutex
+	partitionCounts map[string]int32
+}
+
+func newKafkaProducer(config Config) (*kafkaProducer, error) {
+	opts, err := clientOptions(config)
+	if err != nil {
+		return nil, err
+	}
+	client, err := kgo.NewClient(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &kafkaProducer{
+		client:          client,
+		partitionCounts: make(map[string]int32),
+	}, nil
+}
+
+// clientOptions returns the options of the franz-go client for the config.
+func clientOptions(config Config) ([]kgo.Opt, error) {
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(config.Brokers...),
+		kgo.ClientID("vstream2kafka"),
+		kgo.AllowAutoTopicCreation(),
+		kgo.RecordPartitioner(kgo.ManualPartitioner()),
+		kgo.RequiredAcks(kgo.AllISRAcks()),
+	}
+	if config.Timeout > 0 {
+		opts = append(opts, kgo.ProduceRequestTimeout(config.Timeout), kgo.RecordDeliveryTimeout(config.Timeout))
+	}
+	compression, err := compressionCodec(config.Compression)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, kgo.ProducerBatchCompression(compression))
+	if config.TLS {
+		tlsConfig, err := vttls.ClientConfig(vttls.VerifyIdentity, config.TLSCert, config.TLSKey, config.TLSCA, "", config.TLSServerName, tls.VersionTLS12)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TLS configuration: %v", err)
+		}
+		opts = append(opts, kgo.DialTLSConfig(tlsConfig))
+	}
+	if config.SASLMechanism != "" {
+		mechanism, err := saslMechanism(config.SASLMechanism, config.SASLUser, config.SASLPassword)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, kgo.SASL(mechanism))
+	}
+	return opts, nil
+}
+
+func compressionCodec(compression string) (kgo.CompressionCodec, error) {
+	switch strings.ToLower(compression) {
+	case "", "none":
+		return kgo.NoCompression(), nil
+	case "gzip":
+		return kgo.GzipCompression(), nil
+	case "snappy":
+		return kgo.SnappyCompression(), nil
+	case "lz4":
+		return kgo.Lz4Compression(), nil
+	case "zstd":
+		return kgo.ZstdCompression(), nil
+	}
+	return kgo.CompressionCodec{}, fmt.Errorf("unsupported compression %q, must be one of none, gzip, snappy, lz4, zstd", compression)
+}
+
+func saslMechanism(mechanism, user, password string) (sasl.Mechanism, error) {
+	switch strings.ToUpper(mechanism) {
+	case SASLPlain:
+		return plain.Auth{User: user, Pass: password}.AsMechanism(), nil
+	case SASLScramSHA256:
+		return scram.Auth{User: user, Pass: password}.AsSha256Mechanism(), nil
+	case SASLScramSHA512:
+		return scram.Auth{User: user, Pass: password}.AsSha512Mechanism(), nil
+	}
+	return nil, fmt.Errorf("unsupported SASL mechanism %q, must be one of %s, %s, %s", mechanism, SASLPlain, SASLScramSHA256, SASLScramSHA512)
+}
+
+func (kp *kafkaProducer) partitions(ctx context.Context, topic string) (int32, error) {
+	kp.mu.Lock()
+	defer kp.mu.Unlock()
+	if count, ok := kp.partitionCounts[topic]; ok {
+		return count, nil
+	}
+	var lastErr error
+	for attempt := 0; attempt < metadataRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			case <-time.After(retryBackoff):
+			}
+		}
+		var count int32
+		count, lastErr = kp.fetchPartitions(ctx, topic)
+		if lastErr == nil {
+			kp.partitionCounts[topic] = count
+			return count, nil
+		}
+		// The leaders of a topic that was just created are not
+		// available yet.
+		if !kerr.IsRetriable(lastErr) {
+			break
+		}
+	}
+	return 0, fmt.Errorf("cannot get the metadata of topic %s: %v", topic, lastErr)
+}
+
+func (kp *kafkaProducer) fetchPartitions(ctx context.Context, topic string) (int32, error) {
+	req := kmsg.NewPtrMetadataRequest()
+	reqTopic := kmsg.NewMetadataRequestTopic()
+	reqTopic.Topic = kmsg.StringPtr(topic)
+	req.Topics = append(req.Topics, reqTopic)
+	req.AllowAutoTopicCreation = true
+	resp, err := req.RequestWith(ctx, kp.client)
+	if err != nil {
+		return 0, err
+	}
+	for _, respTopic := range resp.Topics {
+		if respTopic.Topic == nil || *respTopic.Topic != topic {
+			continue
+		}
+		if err := kerr.ErrorForCode(respTopic.ErrorCode); err != nil {
+			return 0, err
+		}
+		return int32(len(respTopic.Partitions)), nil
+	}
+	return 0, kerr.UnknownTopicOrPartition
+}
+
+func (kp *kafkaProducer) produce(ctx context.Context, msgs []*message) error {
+	records := make([]*kgo.Record, len(msgs))
+	for i, msg := range msgs {
+		records[i] = &kgo.Record{
+			Topic:     msg.topic,
+			Partition: msg.partition,
+			Key:       msg.key,
+			Value:     msg.value,
+		}
+	}
+	if err := kp.client.ProduceSync(ctx, records...).FirstErr(); err != nil {
+		return fmt.Errorf("cannot produce %d messages to kafka: %v", len(msgs), err)
+	}
+	return nil
+}
+
+func (kp *kafkaProducer) close() {
+	kp.client.Close()
+}
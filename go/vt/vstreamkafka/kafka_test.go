@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vstreamkafka
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestClientOptions(t *testing.T) {
+	config := Config{Brokers: []string{"127.0.0.1:9092"}}
+	opts, err := clientOptions(config)
+	require.NoError(t, err)
+	client, err := kgo.NewClient(opts...)
+	require.NoError(t, err)
+	client.Close()
+
+	for _, compression := range []string{"none", "gzip", "snappy", "LZ4", "zstd"} {
+		config.Compression = compression
+		_, err := clientOptions(config)
+		assert.NoError(t, err, compression)
+	}
+	config.Compression = "brotli"
+	_, err = clientOptions(config)
+	assert.EqualError(t, err, `unsupported compression "brotli", must be one of none, gzip, snappy, lz4, zstd`)
+	config.Compression = ""
+
+	for _, mechanism := range []string{SASLPlain, "scram-sha-256", SASLScramSHA512} {
+		config.SASLMechanism = mechanism
+		config.SASLUser = "vstream2kafka"
+		config.SASLPassword = "secret"
+		opts, err := clientOptions(config)
+		require.NoError(t, err, mechanism)
+		client, err := kgo.NewClient(opts...)
+		require.NoError(t, err, mechanism)
+		client.Close()
+	}
+	config.SASLMechanism = "GSSAPI"
+	_, err = clientOptions(config)
+	assert.EqualError(t, err, `unsupported SASL mechanism "GSSAPI", must be one of PLAIN, SCRAM-SHA-256, SCRAM-SHA-512`)
+	config.SASLMechanism = ""
+
+	config.TLS = true
+	_, err = clientOptions(config)
+	assert.NoError(t, err)
+	config.TLSCA = filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(config.TLSCA, []byte("not a certificate"), 0o600))
+	_, err = clientOptions(config)
+	assert.ErrorContains(t, err, "invalid TLS configuration")
+}
+
+func TestKafkaProducerUnreachable(t *testing.T) {
+	// 127.0.0.1:1 refuses the connections, so the messages cannot be
+	// delivered before the timeout.
+	kp, err := newKafkaProducer(Config{Brokers: []string{"127.0.0.1:1"}, Timeout: time.Second})
+	require.NoError(t, err)
+	defer kp.close()
+	err = kp.produce(context.Background(), []*message{{topic: "vitess.commerce.customer", key: []byte("1"), value: []byte("row")}})
+	assert.ErrorContains(t, err, "cannot produce 1 messages to kafka")
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vstreamkafka
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+
+	"github.com/twmb/franz-go/pkg/sr"
+
+	"vitess.io/vitess/go/vt/vttls"
+)
+
+// schemaRegistry registers the schemas of the messages in a schema registry
+// with the API of the Confluent Schema Registry. Registering a schema that
+// is already registered returns its id, so the schemas are registered again
+// after a restart without creating new versions.
+type schemaRegistry struct {
+	client *sr.Client
+	// ids caches the ids of the registered schemas by subject and schema.
+	ids map[string]int32
+}
+
+func newSchemaRegistry(config Config) (*schemaRegistry, error) {
+	opts := []sr.ClientOpt{sr.URLs(config.SchemaRegistryURL), sr.UserAgent("vstream2kafka")}
+	if config.TLS {
+		// The registry is verified with the CA of the brokers, and
+		// authenticated with their client certificate, when its URL
+		// is https.
+		tlsConfig, err := vttls.ClientConfig(vttls.VerifyIdentity, config.TLSCert, config.TLSKey, config.TLSCA, "", "", tls.VersionTLS12)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TLS configuration: %v", err)
+		}
+		opts = append(opts, sr.DialTLSConfig(tlsConfig))
+	}
+	if config.SchemaRegistryUser != "" {
+		opts = append(opts, sr.BasicAuth(config.SchemaRegistryUser, config.SchemaRegistryPassword))
+	}
+	client, err := sr.NewClient(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &schemaRegistry{
+		client: client,
+		ids:    make(map[string]int32),
+	}, nil
+}
+
+// register registers the schema under the subject, and returns its id.
+func (r *schemaRegistry) register(ctx context.Context, subject string, schemaType sr.SchemaType, schema string) (int32, error) {
+	cacheKey := subject + "\x00" + schema
+	if id, ok := r.ids[cacheKey]; ok {
+		return id, nil
+	}
+	registered, err := r.client.CreateSchema(ctx, subject, sr.Schema{Schema: schema, Type: schemaType})
+	if err != nil {
+		return 0, fmt.Errorf("cannot register the schema of subject %s: %v", subject, err)
+	}
+	r.ids[cacheKey] = int32(registered.ID)
+	return int32(registered.ID), nil
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vstreamkafka
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/sr"
+)
+
+func TestSchemaRegistry(t *testing.T) {
+	registry, schemas := newFakeSchemaRegistry(t)
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "vstream2kafka" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		requests++
+		registry.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	r, err := newSchemaRegistry(Config{SchemaRegistryURL: server.URL})
+	require.NoError(t, err)
+	_, err = r.register(ctx, "vitess.commerce.customer-value", sr.TypeAvro, `"string"`)
+	assert.ErrorContains(t, err, "cannot register the schema of subject vitess.commerce.customer-value")
+
+	r, err = newSchemaRegistry(Config{SchemaRegistryURL: server.URL, SchemaRegistryUser: "vstream2kafka", SchemaRegistryPassword: "secret"})
+	require.NoError(t, err)
+	id, err := r.register(ctx, "vitess.commerce.customer-value", sr.TypeAvro, `"string"`)
+	require.NoError(t, err)
+	assert.EqualValues(t, 42, id)
+	assert.Equal(t, `"string"`, schemas["vitess.commerce.customer-value"])
+
+	// The registered schemas are cached.
+	sent := requests
+	id, err = r.register(ctx, "vitess.commerce.customer-value", sr.TypeAvro, `"string"`)
+	require.NoError(t, err)
+	assert.EqualValues(t, 42, id)
+	assert.Equal(t, sent, requests)
+}
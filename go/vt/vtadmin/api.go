@@ -119,10 +119,11 @@ func NewAPI(clusters []*cluster.Cluster, opts Options) *API {
 	if authz == nil {
 		authz, _ = rbac.NewAuthorizer(&rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "*",
@@ -155,6 +156,12 @@ func NewAPI(clusters []*cluster.Cluster, opts Options) *API {
 		w.Write([]byte("ok\n"))
 	})
 
+	if lh, ok := authn.(rbac.LoginHandler); ok {
+		for path, handler := range lh.LoginHandlers() {
+			serv.Router().Handle(path, handler)
+		}
+	}
+
 	router := serv.Router().PathPrefix("/api").Subrouter()
 	router.PathPrefix("/").Handler(api).Methods("DELETE", "OPTIONS", "GET", "POST", "PUT")
 
@@ -401,6 +408,7 @@ func (api *API) Handler() http.Handler {
 	router.HandleFunc("/vtctlds", httpAPI.Adapt(vtadminhttp.GetVtctlds)).Name("API.GetVtctlds")
 	router.HandleFunc("/vtexplain", httpAPI.Adapt(vtadminhttp.VTExplain)).Name("API.VTExplain")
 	router.HandleFunc("/workflow/{cluster_id}/{keyspace}/{name}", httpAPI.Adapt(vtadminhttp.GetWorkflow)).Name("API.GetWorkflow")
+	router.HandleFunc("/workflow/{cluster_id}/{keyspace}/{name}/status", httpAPI.Adapt(vtadminhttp.GetWorkflowStatus)).Name("API.GetWorkflowStatus")
 	router.HandleFunc("/workflows", httpAPI.Adapt(vtadminhttp.GetWorkflows)).Name("API.GetWorkflows")
 
 	experimentalRouter := router.PathPrefix("/experimental").Subrouter()
@@ -439,7 +447,7 @@ func (api *API) CreateKeyspace(ctx context.Context, req *vtadminpb.CreateKeyspac
 
 	span.Annotate("cluster_id", req.ClusterId)
 
-	if !api.authz.IsAuthorized(ctx, req.ClusterId, rbac.KeyspaceResource, rbac.CreateAction) {
+	if !api.authz.IsAuthorizedForKeyspace(ctx, req.ClusterId, req.Options.GetName(), "", rbac.KeyspaceResource, rbac.CreateAction) {
 		return nil, fmt.Errorf("%w: cannot create keyspace in %s", errors.ErrUnauthorized, req.ClusterId)
 	}
 
@@ -465,7 +473,7 @@ func (api *API) CreateShard(ctx context.Context, req *vtadminpb.CreateShardReque
 
 	span.Annotate("cluster_id", req.ClusterId)
 
-	if !api.authz.IsAuthorized(ctx, req.ClusterId, rbac.ShardResource, rbac.CreateAction) {
+	if !api.authz.IsAuthorizedForKeyspace(ctx, req.ClusterId, req.Options.GetKeyspace(), req.Options.GetShardName(), rbac.ShardResource, rbac.CreateAction) {
 		return nil, fmt.Errorf("%w: cannot create shard in %s", errors.ErrUnauthorized, req.ClusterId)
 	}
 
@@ -484,7 +492,7 @@ func (api *API) DeleteKeyspace(ctx context.Context, req *vtadminpb.DeleteKeyspac
 
 	span.Annotate("cluster_id", req.ClusterId)
 
-	if !api.authz.IsAuthorized(ctx, req.ClusterId, rbac.KeyspaceResource, rbac.DeleteAction) {
+	if !api.authz.IsAuthorizedForKeyspace(ctx, req.ClusterId, req.Options.GetKeyspace(), "", rbac.KeyspaceResource, rbac.DeleteAction) {
 		return nil, fmt.Errorf("%w: cannot delete keyspace in %s", errors.ErrUnauthorized, req.ClusterId)
 	}
 
@@ -503,10 +511,16 @@ func (api *API) DeleteShards(ctx context.Context, req *vtadminpb.DeleteShardsReq
 
 	span.Annotate("cluster_id", req.ClusterId)
 
-	if !api.authz.IsAuthorized(ctx, req.ClusterId, rbac.ShardResource, rbac.DeleteAction) {
+	if !api.authz.IsAuthorizedForAnyKeyspace(ctx, req.ClusterId, rbac.ShardResource, rbac.DeleteAction) {
 		return nil, fmt.Errorf("%w: cannot delete shards in %s", errors.ErrUnauthorized, req.ClusterId)
 	}
 
+	for _, shard := range req.Options.GetShards() {
+		if !api.authz.IsAuthorizedForKeyspace(ctx, req.ClusterId, shard.Keyspace, shard.Name, rbac.ShardResource, rbac.DeleteAction) {
+			return nil, fmt.Errorf("%w: cannot delete shard %s/%s in %s", errors.ErrUnauthorized, shard.Keyspace, shard.Name, req.ClusterId)
+		}
+	}
+
 	c, err := api.getClusterForRequest(req.ClusterId)
 	if err != nil {
 		return nil, err
@@ -548,7 +562,7 @@ func (api *API) EmergencyFailoverShard(ctx context.Context, req *vtadminpb.Emerg
 		return nil, err
 	}
 
-	if !api.authz.IsAuthorized(ctx, c.ID, rbac.ShardResource, rbac.EmergencyFailoverShardAction) {
+	if !api.authz.IsAuthorizedForKeyspace(ctx, c.ID, req.Options.GetKeyspace(), req.Options.GetShard(), rbac.ShardResource, rbac.EmergencyFailoverShardAction) {
 		return nil, nil
 	}
 
@@ -572,7 +586,7 @@ func (api *API) FindSchema(ctx context.Context, req *vtadminpb.FindSchemaRequest
 	)
 
 	for _, c := range clusters {
-		if !api.authz.IsAuthorized(ctx, c.ID, rbac.SchemaResource, rbac.GetAction) {
+		if !api.authz.IsAuthorizedForAnyKeyspace(ctx, c.ID, rbac.SchemaResource, rbac.GetAction) {
 			continue
 		}
 
@@ -590,6 +604,10 @@ func (api *API) FindSchema(ctx context.Context, req *vtadminpb.FindSchemaRequest
 			}
 
 			for _, schema := range schemas {
+				if !api.authz.IsAuthorizedForKeyspace(ctx, c.ID, schema.Keyspace, "", rbac.SchemaResource, rbac.GetAction) {
+					continue
+				}
+
 				for _, td := range schema.TableDefinitions {
 					if td.Name == req.Table {
 						m.Lock()
@@ -643,7 +661,7 @@ func (api *API) GetBackups(ctx context.Context, req *vtadminpb.GetBackupsRequest
 	}
 
 	for _, c := range clusters {
-		if !api.authz.IsAuthorized(ctx, c.ID, rbac.BackupResource, rbac.GetAction) {
+		if !api.authz.IsAuthorizedForAnyKeyspace(ctx, c.ID, rbac.BackupResource, rbac.GetAction) {
 			continue
 		}
 
@@ -661,7 +679,13 @@ func (api *API) GetBackups(ctx context.Context, req *vtadminpb.GetBackupsRequest
 			m.Lock()
 			defer m.Unlock()
 
-			backups = append(backups, bs...)
+			for _, b := range bs {
+				if !api.authz.IsAuthorizedForKeyspace(ctx, c.ID, b.Backup.GetKeyspace(), b.Backup.GetShard(), rbac.BackupResource, rbac.GetAction) {
+					continue
+				}
+
+				backups = append(backups, b)
+			}
 		}(c)
 	}
 
@@ -868,7 +892,7 @@ func (api *API) GetKeyspace(ctx context.Context, req *vtadminpb.GetKeyspaceReque
 		return nil, err
 	}
 
-	if !api.authz.IsAuthorized(ctx, c.ID, rbac.KeyspaceResource, rbac.GetAction) {
+	if !api.authz.IsAuthorizedForKeyspace(ctx, c.ID, req.Keyspace, "", rbac.KeyspaceResource, rbac.GetAction) {
 		return nil, nil
 	}
 
@@ -890,7 +914,7 @@ func (api *API) GetKeyspaces(ctx context.Context, req *vtadminpb.GetKeyspacesReq
 	)
 
 	for _, c := range clusters {
-		if !api.authz.IsAuthorized(ctx, c.ID, rbac.KeyspaceResource, rbac.GetAction) {
+		if !api.authz.IsAuthorizedForAnyKeyspace(ctx, c.ID, rbac.KeyspaceResource, rbac.GetAction) {
 			continue
 		}
 
@@ -906,7 +930,13 @@ func (api *API) GetKeyspaces(ctx context.Context, req *vtadminpb.GetKeyspacesReq
 			}
 
 			m.Lock()
-			keyspaces = append(keyspaces, kss...)
+			for _, ks := range kss {
+				if !api.authz.IsAuthorizedForKeyspace(ctx, c.ID, ks.Keyspace.GetName(), "", rbac.KeyspaceResource, rbac.GetAction) {
+					continue
+				}
+
+				keyspaces = append(keyspaces, ks)
+			}
 			m.Unlock()
 		}(c)
 	}
@@ -937,7 +967,7 @@ func (api *API) GetSchema(ctx context.Context, req *vtadminpb.GetSchemaRequest)
 		return nil, err
 	}
 
-	if !api.authz.IsAuthorized(ctx, c.ID, rbac.SchemaResource, rbac.GetAction) {
+	if !api.authz.IsAuthorizedForKeyspace(ctx, c.ID, req.Keyspace, "", rbac.SchemaResource, rbac.GetAction) {
 		return nil, nil
 	}
 
@@ -976,7 +1006,7 @@ func (api *API) GetSchemas(ctx context.Context, req *vtadminpb.GetSchemasRequest
 	)
 
 	for _, c := range clusters {
-		if !api.authz.IsAuthorized(ctx, c.ID, rbac.SchemaResource, rbac.GetAction) {
+		if !api.authz.IsAuthorizedForAnyKeyspace(ctx, c.ID, rbac.SchemaResource, rbac.GetAction) {
 			continue
 		}
 
@@ -995,7 +1025,13 @@ func (api *API) GetSchemas(ctx context.Context, req *vtadminpb.GetSchemasRequest
 			}
 
 			m.Lock()
-			schemas = append(schemas, ss...)
+			for _, schema := range ss {
+				if !api.authz.IsAuthorizedForKeyspace(ctx, c.ID, schema.Keyspace, "", rbac.SchemaResource, rbac.GetAction) {
+					continue
+				}
+
+				schemas = append(schemas, schema)
+			}
 			m.Unlock()
 		}(c)
 	}
@@ -1030,7 +1066,7 @@ func (api *API) GetShardReplicationPositions(ctx context.Context, req *vtadminpb
 	)
 
 	for _, c := range clusters {
-		if !api.authz.IsAuthorized(ctx, c.ID, rbac.ShardReplicationPositionResource, rbac.GetAction) {
+		if !api.authz.IsAuthorizedForAnyKeyspace(ctx, c.ID, rbac.ShardReplicationPositionResource, rbac.GetAction) {
 			continue
 		}
 
@@ -1047,7 +1083,14 @@ func (api *API) GetShardReplicationPositions(ctx context.Context, req *vtadminpb
 
 			m.Lock()
 			defer m.Unlock()
-			positions = append(positions, clusterPositions...)
+
+			for _, pos := range clusterPositions {
+				if !api.authz.IsAuthorizedForKeyspace(ctx, c.ID, pos.Keyspace, pos.Shard, rbac.ShardReplicationPositionResource, rbac.GetAction) {
+					continue
+				}
+
+				positions = append(positions, pos)
+			}
 		}(c)
 	}
 
@@ -1072,7 +1115,7 @@ func (api *API) GetSrvKeyspace(ctx context.Context, req *vtadminpb.GetSrvKeyspac
 		return nil, err
 	}
 
-	if !api.authz.IsAuthorized(ctx, c.ID, rbac.SrvKeyspaceResource, rbac.GetAction) {
+	if !api.authz.IsAuthorizedForKeyspace(ctx, c.ID, req.Keyspace, "", rbac.SrvKeyspaceResource, rbac.GetAction) {
 		return nil, nil
 	}
 
@@ -1097,7 +1140,7 @@ func (api *API) GetSrvKeyspaces(ctx context.Context, req *vtadminpb.GetSrvKeyspa
 	)
 
 	for _, c := range clusters {
-		if !api.authz.IsAuthorized(ctx, c.ID, rbac.SrvKeyspaceResource, rbac.GetAction) {
+		if !api.authz.IsAuthorizedForAnyKeyspace(ctx, c.ID, rbac.SrvKeyspaceResource, rbac.GetAction) {
 			continue
 		}
 
@@ -1118,6 +1161,10 @@ func (api *API) GetSrvKeyspaces(ctx context.Context, req *vtadminpb.GetSrvKeyspa
 
 			m.Lock()
 			for key, value := range sk {
+				if !api.authz.IsAuthorizedForKeyspace(ctx, c.ID, key, "", rbac.SrvKeyspaceResource, rbac.GetAction) {
+					continue
+				}
+
 				sks[key] = value
 			}
 			m.Unlock()
@@ -1227,7 +1274,7 @@ func (api *API) GetTablets(ctx context.Context, req *vtadminpb.GetTabletsRequest
 	)
 
 	for _, c := range clusters {
-		if !api.authz.IsAuthorized(ctx, c.ID, rbac.TabletResource, rbac.GetAction) {
+		if !api.authz.IsAuthorizedForAnyKeyspace(ctx, c.ID, rbac.TabletResource, rbac.GetAction) {
 			continue
 		}
 
@@ -1243,7 +1290,13 @@ func (api *API) GetTablets(ctx context.Context, req *vtadminpb.GetTabletsRequest
 			}
 
 			m.Lock()
-			tablets = append(tablets, ts...)
+			for _, t := range ts {
+				if !api.authz.IsAuthorizedForKeyspace(ctx, c.ID, t.Tablet.GetKeyspace(), t.Tablet.GetShard(), rbac.TabletResource, rbac.GetAction) {
+					continue
+				}
+
+				tablets = append(tablets, t)
+			}
 			m.Unlock()
 		}(c)
 	}
@@ -1290,7 +1343,7 @@ func (api *API) GetVSchema(ctx context.Context, req *vtadminpb.GetVSchemaRequest
 
 	cluster.AnnotateSpan(c, span)
 
-	if !api.authz.IsAuthorized(ctx, c.ID, rbac.VSchemaResource, rbac.GetAction) {
+	if !api.authz.IsAuthorizedForKeyspace(ctx, c.ID, req.Keyspace, "", rbac.VSchemaResource, rbac.GetAction) {
 		return nil, nil
 	}
 
@@ -1320,7 +1373,7 @@ func (api *API) GetVSchemas(ctx context.Context, req *vtadminpb.GetVSchemasReque
 	}
 
 	for _, c := range clusters {
-		if !api.authz.IsAuthorized(ctx, c.ID, rbac.VSchemaResource, rbac.GetAction) {
+		if !api.authz.IsAuthorizedForAnyKeyspace(ctx, c.ID, rbac.VSchemaResource, rbac.GetAction) {
 			continue
 		}
 
@@ -1354,6 +1407,10 @@ func (api *API) GetVSchemas(ctx context.Context, req *vtadminpb.GetVSchemasReque
 			)
 
 			for _, keyspace := range keyspaces.Keyspaces {
+				if !api.authz.IsAuthorizedForKeyspace(ctx, c.ID, keyspace.Name, "", rbac.VSchemaResource, rbac.GetAction) {
+					continue
+				}
+
 				clusterWG.Add(1)
 
 				go func(keyspace *vtctldatapb.Keyspace) {
@@ -1455,7 +1512,7 @@ func (api *API) GetWorkflow(ctx context.Context, req *vtadminpb.GetWorkflowReque
 	span.Annotate("workflow_name", req.Name)
 	span.Annotate("active_only", req.ActiveOnly)
 
-	if !api.authz.IsAuthorized(ctx, c.ID, rbac.WorkflowResource, rbac.GetAction) {
+	if !api.authz.IsAuthorizedForKeyspace(ctx, c.ID, req.Keyspace, "", rbac.WorkflowResource, rbac.GetAction) {
 		return nil, nil
 	}
 
@@ -1479,7 +1536,7 @@ func (api *API) GetWorkflows(ctx context.Context, req *vtadminpb.GetWorkflowsReq
 	)
 
 	for _, c := range clusters {
-		if !api.authz.IsAuthorized(ctx, c.ID, rbac.WorkflowResource, rbac.GetAction) {
+		if !api.authz.IsAuthorizedForAnyKeyspace(ctx, c.ID, rbac.WorkflowResource, rbac.GetAction) {
 			continue
 		}
 
@@ -1498,6 +1555,16 @@ func (api *API) GetWorkflows(ctx context.Context, req *vtadminpb.GetWorkflowsReq
 				return
 			}
 
+			authorized := workflows.Workflows[:0]
+			for _, workflow := range workflows.Workflows {
+				if !api.authz.IsAuthorizedForKeyspace(ctx, c.ID, workflow.Keyspace, "", rbac.WorkflowResource, rbac.GetAction) {
+					continue
+				}
+
+				authorized = append(authorized, workflow)
+			}
+			workflows.Workflows = authorized
+
 			m.Lock()
 			results[c.ID] = workflows
 			m.Unlock()
@@ -1515,6 +1582,30 @@ func (api *API) GetWorkflows(ctx context.Context, req *vtadminpb.GetWorkflowsReq
 	}, nil
 }
 
+// GetWorkflowStatus is part of the vtadminpb.VTAdminServer interface.
+func (api *API) GetWorkflowStatus(ctx context.Context, req *vtadminpb.GetWorkflowStatusRequest) (*vtctldatapb.WorkflowStatusResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "API.GetWorkflowStatus")
+	defer span.Finish()
+
+	c, err := api.getClusterForRequest(req.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster.AnnotateSpan(c, span)
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("workflow_name", req.Name)
+
+	if !api.authz.IsAuthorizedForKeyspace(ctx, c.ID, req.Keyspace, "", rbac.WorkflowResource, rbac.GetAction) {
+		return nil, nil
+	}
+
+	return c.Vtctld.WorkflowStatus(ctx, &vtctldatapb.WorkflowStatusRequest{
+		Keyspace: req.Keyspace,
+		Workflow: req.Name,
+	})
+}
+
 // PingTablet is part of the vtadminpb.VTAdminServer interface.
 func (api *API) PingTablet(ctx context.Context, req *vtadminpb.PingTabletRequest) (*vtadminpb.PingTabletResponse, error) {
 	span, ctx := trace.NewSpan(ctx, "API.PingTablet")
@@ -1551,7 +1642,7 @@ func (api *API) PlannedFailoverShard(ctx context.Context, req *vtadminpb.Planned
 		return nil, err
 	}
 
-	if !api.authz.IsAuthorized(ctx, c.ID, rbac.ShardResource, rbac.PlannedFailoverShardAction) {
+	if !api.authz.IsAuthorizedForKeyspace(ctx, c.ID, req.Options.GetKeyspace(), req.Options.GetShard(), rbac.ShardResource, rbac.PlannedFailoverShardAction) {
 		return nil, nil
 	}
 
@@ -1568,7 +1659,7 @@ func (api *API) RebuildKeyspaceGraph(ctx context.Context, req *vtadminpb.Rebuild
 		return nil, err
 	}
 
-	if !api.authz.IsAuthorized(ctx, c.ID, rbac.KeyspaceResource, rbac.PutAction) {
+	if !api.authz.IsAuthorizedForKeyspace(ctx, c.ID, req.Keyspace, "", rbac.KeyspaceResource, rbac.PutAction) {
 		return nil, nil
 	}
 
@@ -1676,7 +1767,7 @@ func (api *API) RemoveKeyspaceCell(ctx context.Context, req *vtadminpb.RemoveKey
 		return nil, err
 	}
 
-	if !api.authz.IsAuthorized(ctx, c.ID, rbac.KeyspaceResource, rbac.PutAction) {
+	if !api.authz.IsAuthorizedForKeyspace(ctx, c.ID, req.Keyspace, "", rbac.KeyspaceResource, rbac.PutAction) {
 		return nil, nil
 	}
 
@@ -1880,7 +1971,7 @@ func (api *API) ValidateKeyspace(ctx context.Context, req *vtadminpb.ValidateKey
 		return nil, err
 	}
 
-	if !api.authz.IsAuthorized(ctx, c.ID, rbac.KeyspaceResource, rbac.PutAction) {
+	if !api.authz.IsAuthorizedForKeyspace(ctx, c.ID, req.Keyspace, "", rbac.KeyspaceResource, rbac.PutAction) {
 		return nil, nil
 	}
 
@@ -1906,7 +1997,7 @@ func (api *API) ValidateSchemaKeyspace(ctx context.Context, req *vtadminpb.Valid
 		return nil, err
 	}
 
-	if !api.authz.IsAuthorized(ctx, c.ID, rbac.KeyspaceResource, rbac.PutAction) {
+	if !api.authz.IsAuthorizedForKeyspace(ctx, c.ID, req.Keyspace, "", rbac.KeyspaceResource, rbac.PutAction) {
 		return nil, nil
 	}
 
@@ -1931,7 +2022,7 @@ func (api *API) ValidateShard(ctx context.Context, req *vtadminpb.ValidateShardR
 		return nil, err
 	}
 
-	if !api.authz.IsAuthorized(ctx, c.ID, rbac.ShardResource, rbac.PutAction) {
+	if !api.authz.IsAuthorizedForKeyspace(ctx, c.ID, req.Keyspace, req.Shard, rbac.ShardResource, rbac.PutAction) {
 		return nil, nil
 	}
 
@@ -1958,7 +2049,7 @@ func (api *API) ValidateVersionKeyspace(ctx context.Context, req *vtadminpb.Vali
 		return nil, err
 	}
 
-	if !api.authz.IsAuthorized(ctx, c.ID, rbac.KeyspaceResource, rbac.PutAction) {
+	if !api.authz.IsAuthorizedForKeyspace(ctx, c.ID, req.Keyspace, "", rbac.KeyspaceResource, rbac.PutAction) {
 		return nil, nil
 	}
 
@@ -1983,7 +2074,7 @@ func (api *API) ValidateVersionShard(ctx context.Context, req *vtadminpb.Validat
 		return nil, err
 	}
 
-	if !api.authz.IsAuthorized(ctx, c.ID, rbac.ShardResource, rbac.PutAction) {
+	if !api.authz.IsAuthorizedForKeyspace(ctx, c.ID, req.Keyspace, req.Shard, rbac.ShardResource, rbac.PutAction) {
 		return nil, nil
 	}
 
@@ -2027,7 +2118,7 @@ func (api *API) VTExplain(ctx context.Context, req *vtadminpb.VTExplainRequest)
 	span.Annotate("keyspace", req.Keyspace)
 	cluster.AnnotateSpan(c, span)
 
-	if !api.authz.IsAuthorized(ctx, c.ID, rbac.VTExplainResource, rbac.GetAction) {
+	if !api.authz.IsAuthorizedForKeyspace(ctx, c.ID, req.Keyspace, "", rbac.VTExplainResource, rbac.GetAction) {
 		return nil, nil
 	}
 
@@ -2237,7 +2328,7 @@ func (api *API) getTabletForResourceAndAction(
 	)
 
 	for _, c := range clusters {
-		if !api.authz.IsAuthorized(ctx, c.ID, resource, action) {
+		if !api.authz.IsAuthorizedForAnyKeyspace(ctx, c.ID, resource, action) {
 			continue
 		}
 
@@ -2247,7 +2338,11 @@ func (api *API) getTabletForResourceAndAction(
 			defer wg.Done()
 
 			ts, err := c.FindTablets(ctx, func(t *vtadminpb.Tablet) bool {
-				return topoproto.TabletAliasEqual(t.Tablet.Alias, alias)
+				if !topoproto.TabletAliasEqual(t.Tablet.Alias, alias) {
+					return false
+				}
+
+				return api.authz.IsAuthorizedForKeyspace(ctx, c.ID, t.Tablet.Keyspace, t.Tablet.Shard, resource, action)
 			}, -1)
 			if err != nil {
 				rec.RecordError(fmt.Errorf("FindTablets(cluster = %s): %w", c.ID, err))
@@ -49,10 +49,11 @@ func TestCreateKeyspace(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Keyspace",
@@ -118,10 +119,11 @@ func TestCreateShard(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Shard",
@@ -189,10 +191,11 @@ func TestDeleteKeyspace(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Keyspace",
@@ -258,10 +261,11 @@ func TestDeleteShards(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Shard",
@@ -269,6 +273,20 @@ func TestDeleteShards(t *testing.T) {
 					Subjects: []string{"user:allowed"},
 					Clusters: []string{"*"},
 				},
+				{
+					Resource:  "Shard",
+					Actions:   []string{"delete"},
+					Subjects:  []string{"user:allowed-shard"},
+					Clusters:  []string{"*"},
+					Keyspaces: []string{"test/-"},
+				},
+				{
+					Resource:  "Shard",
+					Actions:   []string{"delete"},
+					Subjects:  []string{"user:allowed-othershard"},
+					Clusters:  []string{"*"},
+					Keyspaces: []string{"test/80-"},
+				},
 			},
 		},
 	}
@@ -329,6 +347,54 @@ func TestDeleteShards(t *testing.T) {
 		require.NoError(t, err)
 		assert.NotNil(t, resp, "actor %+v should be permitted to DeleteShards", actor)
 	})
+
+	t.Run("actor scoped to another shard", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed-othershard"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.DeleteShards(ctx, &vtadminpb.DeleteShardsRequest{
+			ClusterId: "test",
+			Options: &vtctldatapb.DeleteShardsRequest{
+				Shards: []*vtctldatapb.Shard{
+					{
+						Keyspace: "test",
+						Name:     "-",
+					},
+				},
+			},
+		})
+		assert.Error(t, err, "actor %+v should not be permitted to DeleteShards", actor)
+		assert.Nil(t, resp, "actor %+v should not be permitted to DeleteShards", actor)
+	})
+
+	t.Run("actor scoped to the shard", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed-shard"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.DeleteShards(ctx, &vtadminpb.DeleteShardsRequest{
+			ClusterId: "test",
+			Options: &vtctldatapb.DeleteShardsRequest{
+				Shards: []*vtctldatapb.Shard{
+					{
+						Keyspace: "test",
+						Name:     "-",
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to DeleteShards", actor)
+	})
 }
 
 func TestDeleteTablet(t *testing.T) {
@@ -337,10 +403,11 @@ func TestDeleteTablet(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Tablet",
@@ -408,10 +475,11 @@ func TestEmergencyFailoverShard(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Shard",
@@ -479,10 +547,11 @@ func TestFindSchema(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Schema",
@@ -578,10 +647,11 @@ func TestGetBackups(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Backup",
@@ -657,10 +727,11 @@ func TestGetCellInfos(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "CellInfo",
@@ -742,10 +813,11 @@ func TestGetCellsAliases(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "CellsAlias",
@@ -821,10 +893,11 @@ func TestGetClusters(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Cluster",
@@ -892,10 +965,11 @@ func TestGetGates(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "VTGate",
@@ -987,10 +1061,11 @@ func TestGetKeyspace(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Keyspace",
@@ -998,6 +1073,20 @@ func TestGetKeyspace(t *testing.T) {
 					Subjects: []string{"user:allowed"},
 					Clusters: []string{"*"},
 				},
+				{
+					Resource:  "Keyspace",
+					Actions:   []string{"get"},
+					Subjects:  []string{"user:allowed-ks"},
+					Clusters:  []string{"*"},
+					Keyspaces: []string{"test"},
+				},
+				{
+					Resource:  "Keyspace",
+					Actions:   []string{"get"},
+					Subjects:  []string{"user:allowed-otherks"},
+					Clusters:  []string{"*"},
+					Keyspaces: []string{"otherks"},
+				},
 			},
 		},
 	}
@@ -1044,6 +1133,40 @@ func TestGetKeyspace(t *testing.T) {
 		require.NoError(t, err)
 		assert.NotNil(t, resp, "actor %+v should be permitted to GetKeyspace", actor)
 	})
+
+	t.Run("actor scoped to another keyspace", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed-otherks"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.GetKeyspace(ctx, &vtadminpb.GetKeyspaceRequest{
+			ClusterId: "test",
+			Keyspace:  "test",
+		})
+		require.NoError(t, err)
+		assert.Nil(t, resp, "actor %+v should not be permitted to GetKeyspace", actor)
+	})
+
+	t.Run("actor scoped to the keyspace", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed-ks"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.GetKeyspace(ctx, &vtadminpb.GetKeyspaceRequest{
+			ClusterId: "test",
+			Keyspace:  "test",
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to GetKeyspace", actor)
+	})
 }
 
 func TestGetKeyspaces(t *testing.T) {
@@ -1052,10 +1175,11 @@ func TestGetKeyspaces(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Keyspace",
@@ -1069,6 +1193,13 @@ func TestGetKeyspaces(t *testing.T) {
 					Subjects: []string{"user:allowed-other"},
 					Clusters: []string{"other"},
 				},
+				{
+					Resource:  "Keyspace",
+					Actions:   []string{"get"},
+					Subjects:  []string{"user:allowed-otherks"},
+					Clusters:  []string{"*"},
+					Keyspaces: []string{"otherks"},
+				},
 			},
 		},
 	}
@@ -1137,6 +1268,27 @@ func TestGetKeyspaces(t *testing.T) {
 		}
 		assert.Equal(t, ksMap, map[string][]string{"test": {"test"}, "other": {"otherks"}}, "actor %+v should be permitted to GetKeyspaces", actor)
 	})
+
+	t.Run("keyspace access", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed-otherks"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, _ := api.GetKeyspaces(ctx, &vtadminpb.GetKeyspacesRequest{})
+		assert.NotEmpty(t, resp.Keyspaces, "actor %+v should be permitted to GetKeyspaces", actor)
+		ksMap := map[string][]string{}
+		for _, ks := range resp.Keyspaces {
+			if _, ok := ksMap[ks.Cluster.Id]; !ok {
+				ksMap[ks.Cluster.Id] = []string{}
+			}
+			ksMap[ks.Cluster.Id] = append(ksMap[ks.Cluster.Id], ks.Keyspace.Name)
+		}
+		assert.Equal(t, ksMap, map[string][]string{"other": {"otherks"}}, "actor %+v should be permitted to GetKeyspaces", actor)
+	})
 }
 
 func TestGetSchema(t *testing.T) {
@@ -1145,10 +1297,11 @@ func TestGetSchema(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Schema",
@@ -1219,10 +1372,11 @@ func TestGetSchemas(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Schema",
@@ -1326,10 +1480,11 @@ func TestGetShardReplicationPositions(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "ShardReplicationPosition",
@@ -1419,10 +1574,11 @@ func TestGetSrvVSchema(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "SrvVSchema",
@@ -1484,10 +1640,11 @@ func TestGetSrvVSchemas(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "SrvVSchema",
@@ -1577,10 +1734,11 @@ func TestGetTablet(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Tablet",
@@ -1646,10 +1804,11 @@ func TestGetTablets(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Tablet",
@@ -1663,6 +1822,13 @@ func TestGetTablets(t *testing.T) {
 					Subjects: []string{"user:allowed-other"},
 					Clusters: []string{"other"},
 				},
+				{
+					Resource:  "Tablet",
+					Actions:   []string{"get"},
+					Subjects:  []string{"user:allowed-otherks"},
+					Clusters:  []string{"*"},
+					Keyspaces: []string{"otherks"},
+				},
 			},
 		},
 	}
@@ -1731,6 +1897,27 @@ func TestGetTablets(t *testing.T) {
 		}
 		assert.Equal(t, clusterAliases, map[string][]string{"test": {"zone1-0000000100"}, "other": {"other1-0000000100"}}, "actor %+v should be permitted to GetTablets", actor)
 	})
+
+	t.Run("keyspace access", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed-otherks"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, _ := api.GetTablets(ctx, &vtadminpb.GetTabletsRequest{})
+		assert.NotEmpty(t, resp.Tablets, "actor %+v should be permitted to GetTablets", actor)
+		clusterAliases := map[string][]string{}
+		for _, tablet := range resp.Tablets {
+			if _, ok := clusterAliases[tablet.Cluster.Id]; !ok {
+				clusterAliases[tablet.Cluster.Id] = []string{}
+			}
+			clusterAliases[tablet.Cluster.Id] = append(clusterAliases[tablet.Cluster.Id], topoproto.TabletAliasString(tablet.Tablet.Alias))
+		}
+		assert.Equal(t, clusterAliases, map[string][]string{"other": {"other1-0000000100"}}, "actor %+v should be permitted to GetTablets", actor)
+	})
 }
 
 func TestGetVSchema(t *testing.T) {
@@ -1739,10 +1926,11 @@ func TestGetVSchema(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "VSchema",
@@ -1804,10 +1992,11 @@ func TestGetVSchemas(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "VSchema",
@@ -1897,10 +2086,11 @@ func TestGetVtctlds(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Vtctld",
@@ -1992,10 +2182,11 @@ func TestGetWorkflow(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Workflow",
@@ -2053,16 +2244,85 @@ func TestGetWorkflow(t *testing.T) {
 	})
 }
 
+func TestGetWorkflowStatus(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
+			}{
+				{
+					Resource: "Workflow",
+					Actions:  []string{"get"},
+					Subjects: []string{"user:allowed"},
+					Clusters: []string{"*"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "other"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.GetWorkflowStatus(ctx, &vtadminpb.GetWorkflowStatusRequest{
+			ClusterId: "test",
+			Keyspace:  "test",
+			Name:      "testworkflow",
+		})
+		require.NoError(t, err)
+		assert.Nil(t, resp, "actor %+v should not be permitted to GetWorkflowStatus", actor)
+	})
+
+	t.Run("authorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed"}
+		ctx := context.Background()
+		if actor != nil {
+			ctx = rbac.NewContext(ctx, actor)
+		}
+
+		resp, err := api.GetWorkflowStatus(ctx, &vtadminpb.GetWorkflowStatusRequest{
+			ClusterId: "test",
+			Keyspace:  "test",
+			Name:      "testworkflow",
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp, "actor %+v should be permitted to GetWorkflowStatus", actor)
+	})
+}
+
 func TestGetWorkflows(t *testing.T) {
 	t.Parallel()
 
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Workflow",
@@ -2138,10 +2398,11 @@ func TestPingTablet(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Tablet",
@@ -2207,10 +2468,11 @@ func TestPlannedFailoverShard(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Shard",
@@ -2278,10 +2540,11 @@ func TestRefreshState(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Tablet",
@@ -2347,10 +2610,11 @@ func TestRefreshTabletReplicationSource(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Tablet",
@@ -2416,10 +2680,11 @@ func TestReloadSchemas(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Schema",
@@ -2508,10 +2773,11 @@ func TestRunHealthCheck(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Tablet",
@@ -2577,10 +2843,11 @@ func TestSetReadOnly(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Tablet",
@@ -2646,10 +2913,11 @@ func TestSetReadWrite(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Tablet",
@@ -2715,10 +2983,11 @@ func TestStartReplication(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Tablet",
@@ -2784,10 +3053,11 @@ func TestStopReplication(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Tablet",
@@ -2853,10 +3123,11 @@ func TestTabletExternallyPromoted(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Shard",
@@ -2922,10 +3193,11 @@ func TestVTExplain(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "VTExplain",
@@ -2994,10 +3266,11 @@ func TestValidateKeyspace(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Keyspace",
@@ -3059,10 +3332,11 @@ func TestValidateSchemaKeyspace(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Keyspace",
@@ -3124,10 +3398,11 @@ func TestValidateVersionKeyspace(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct {
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{
 					Resource: "Keyspace",
@@ -3431,6 +3706,14 @@ func testClusters(t testing.TB) []*cluster.Cluster {
 						Response: &vtctldatapb.ValidateVersionKeyspaceResponse{},
 					},
 				},
+				WorkflowStatusResults: map[string]struct {
+					Response *vtctldatapb.WorkflowStatusResponse
+					Error    error
+				}{
+					"test": {
+						Response: &vtctldatapb.WorkflowStatusResponse{},
+					},
+				},
 			},
 			Tablets: []*vtadminpb.Tablet{
 				{
@@ -49,6 +49,14 @@ type Authenticator interface {
 	AuthenticateHTTP(r *http.Request) (*Actor, error)
 }
 
+// LoginHandler is an optional interface for Authenticators that serve their
+// own HTTP endpoints, for example to implement a login flow. vtadmin-api mounts
+// the handlers, keyed by path, on its root router, where they are not subject
+// to authentication.
+type LoginHandler interface {
+	LoginHandlers() map[string]http.Handler
+}
+
 // AuthenticationStreamInterceptor returns a grpc.StreamServerInterceptor that
 // uses the given Authenticator create an Actor from the stream's context, which
 // is then stored in the stream context for later use.
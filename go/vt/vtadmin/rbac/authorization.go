@@ -35,10 +35,11 @@ type Authorizer struct {
 //
 //	authz, err := rbac.NewAuthorizer(&rbac.Config{
 //		Rules: []*struct {
-//			Resource string
-//			Actions  []string
-//			Subjects []string
-//			Clusters []string
+//			Resource  string
+//			Actions   []string
+//			Subjects  []string
+//			Clusters  []string
+//			Keyspaces []string
 //		}{
 //			{
 //				Resource: "*",
@@ -60,12 +61,42 @@ func NewAuthorizer(cfg *Config) (*Authorizer, error) {
 
 // IsAuthorized returns whether an Actor (from the context) is permitted to take
 // the given action on the given resource in the given cluster.
+//
+// Rules scoped to particular keyspaces or shards are not considered; see
+// IsAuthorizedForKeyspace.
 func (authz *Authorizer) IsAuthorized(ctx context.Context, clusterID string, resource Resource, action Action) bool {
-	actor, _ := FromContext(ctx) // nil is ok here, since rule.Allows handles it
+	return authz.anyRule(ctx, resource, func(rule *Rule, actor *Actor) bool {
+		return rule.Allows(clusterID, action, actor)
+	})
+}
+
+// IsAuthorizedForKeyspace returns whether an Actor (from the context) is
+// permitted to take the given action on the given resource in the given
+// keyspace of the given cluster. If shard is non-empty, rules scoped to just
+// that shard of the keyspace are considered as well.
+func (authz *Authorizer) IsAuthorizedForKeyspace(ctx context.Context, clusterID string, keyspace string, shard string, resource Resource, action Action) bool {
+	return authz.anyRule(ctx, resource, func(rule *Rule, actor *Actor) bool {
+		return rule.AllowsKeyspace(clusterID, keyspace, shard, action, actor)
+	})
+}
+
+// IsAuthorizedForAnyKeyspace returns whether an Actor (from the context) is
+// permitted to take the given action on the given resource in at least one
+// keyspace (or shard) of the given cluster. Callers should use it to decide
+// whether to query a cluster at all, and then filter the results with
+// IsAuthorizedForKeyspace.
+func (authz *Authorizer) IsAuthorizedForAnyKeyspace(ctx context.Context, clusterID string, resource Resource, action Action) bool {
+	return authz.anyRule(ctx, resource, func(rule *Rule, actor *Actor) bool {
+		return rule.AllowsAnyKeyspace(clusterID, action, actor)
+	})
+}
+
+func (authz *Authorizer) anyRule(ctx context.Context, resource Resource, allows func(rule *Rule, actor *Actor) bool) bool {
+	actor, _ := FromContext(ctx) // nil is ok here, since the rules handle it
 	if p, ok := authz.policies["*"]; ok {
 		// We have policies for the wildcard resource to check first
 		for _, rule := range p {
-			if rule.Allows(clusterID, action, actor) {
+			if allows(rule, actor) {
 				return true
 			}
 		}
@@ -73,7 +104,7 @@ func (authz *Authorizer) IsAuthorized(ctx context.Context, clusterID string, res
 
 	if p, ok := authz.policies[string(resource)]; ok {
 		for _, rule := range p {
-			if rule.Allows(clusterID, action, actor) {
+			if allows(rule, actor) {
 				return true
 			}
 		}
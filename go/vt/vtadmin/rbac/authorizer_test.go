@@ -29,10 +29,11 @@ func TestIsAuthorized(t *testing.T) {
 
 	authz, err := NewAuthorizer(&Config{
 		Rules: []*struct {
-			Resource string
-			Actions  []string
-			Subjects []string
-			Clusters []string
+			Resource  string
+			Actions   []string
+			Subjects  []string
+			Clusters  []string
+			Keyspaces []string
 		}{
 			{
 				Resource: "*",
@@ -116,3 +117,243 @@ func TestIsAuthorized(t *testing.T) {
 		})
 	}
 }
+
+func TestIsAuthorizedForKeyspace(t *testing.T) {
+	t.Parallel()
+
+	authz, err := NewAuthorizer(&Config{
+		Rules: []*struct {
+			Resource  string
+			Actions   []string
+			Subjects  []string
+			Clusters  []string
+			Keyspaces []string
+		}{
+			{
+				Resource:  string(KeyspaceResource),
+				Actions:   []string{string(GetAction)},
+				Subjects:  []string{"role:team1"},
+				Clusters:  []string{"*"},
+				Keyspaces: []string{"ks1"},
+			},
+			{
+				Resource:  string(ShardResource),
+				Actions:   []string{"*"},
+				Subjects:  []string{"role:team2"},
+				Clusters:  []string{"c1"},
+				Keyspaces: []string{"ks2/-80"},
+			},
+			{
+				Resource:  string(TabletResource),
+				Actions:   []string{string(GetAction)},
+				Subjects:  []string{"user:admin"},
+				Clusters:  []string{"*"},
+				Keyspaces: []string{"*"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	team1 := &Actor{Name: "someuser", Roles: []string{"team1"}}
+	team2 := &Actor{Name: "otheruser", Roles: []string{"team2"}}
+	admin := &Actor{Name: "admin"}
+
+	tests := []struct {
+		name      string
+		actor     *Actor
+		clusterID string
+		keyspace  string
+		shard     string
+		resource  Resource
+		action    Action
+		// isAuthorized is the expected result of IsAuthorizedForKeyspace,
+		// isAuthorizedForCluster the one of IsAuthorized, and
+		// isAuthorizedForAny the one of IsAuthorizedForAnyKeyspace.
+		isAuthorized           bool
+		isAuthorizedForCluster bool
+		isAuthorizedForAny     bool
+	}{
+		{
+			name:               "keyspace rule",
+			actor:              team1,
+			clusterID:          "c2",
+			keyspace:           "ks1",
+			resource:           KeyspaceResource,
+			action:             GetAction,
+			isAuthorized:       true,
+			isAuthorizedForAny: true,
+		},
+		{
+			name:               "keyspace rule with shard",
+			actor:              team1,
+			clusterID:          "c2",
+			keyspace:           "ks1",
+			shard:              "-80",
+			resource:           KeyspaceResource,
+			action:             GetAction,
+			isAuthorized:       true,
+			isAuthorizedForAny: true,
+		},
+		{
+			name:               "keyspace rule for another keyspace",
+			actor:              team1,
+			clusterID:          "c2",
+			keyspace:           "ks2",
+			resource:           KeyspaceResource,
+			action:             GetAction,
+			isAuthorized:       false,
+			isAuthorizedForAny: true,
+		},
+		{
+			name:               "shard rule",
+			actor:              team2,
+			clusterID:          "c1",
+			keyspace:           "ks2",
+			shard:              "-80",
+			resource:           ShardResource,
+			action:             PlannedFailoverShardAction,
+			isAuthorized:       true,
+			isAuthorizedForAny: true,
+		},
+		{
+			name:               "shard rule for another shard",
+			actor:              team2,
+			clusterID:          "c1",
+			keyspace:           "ks2",
+			shard:              "80-",
+			resource:           ShardResource,
+			action:             PlannedFailoverShardAction,
+			isAuthorized:       false,
+			isAuthorizedForAny: true,
+		},
+		{
+			name:               "shard rule for the whole keyspace",
+			actor:              team2,
+			clusterID:          "c1",
+			keyspace:           "ks2",
+			resource:           ShardResource,
+			action:             PlannedFailoverShardAction,
+			isAuthorized:       false,
+			isAuthorizedForAny: true,
+		},
+		{
+			name:               "shard rule in another cluster",
+			actor:              team2,
+			clusterID:          "c2",
+			keyspace:           "ks2",
+			shard:              "-80",
+			resource:           ShardResource,
+			action:             PlannedFailoverShardAction,
+			isAuthorized:       false,
+			isAuthorizedForAny: false,
+		},
+		{
+			name:                   "wildcard keyspace",
+			actor:                  admin,
+			clusterID:              "c1",
+			keyspace:               "ks3",
+			resource:               TabletResource,
+			action:                 GetAction,
+			isAuthorized:           true,
+			isAuthorizedForCluster: true,
+			isAuthorizedForAny:     true,
+		},
+		{
+			name:               "nil actor",
+			actor:              nil,
+			clusterID:          "c1",
+			keyspace:           "ks1",
+			resource:           KeyspaceResource,
+			action:             GetAction,
+			isAuthorized:       false,
+			isAuthorizedForAny: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := NewContext(context.Background(), tt.actor)
+
+			got := authz.IsAuthorizedForKeyspace(ctx, tt.clusterID, tt.keyspace, tt.shard, tt.resource, tt.action)
+			assert.Equal(t, tt.isAuthorized, got, "IsAuthorizedForKeyspace")
+
+			got = authz.IsAuthorized(ctx, tt.clusterID, tt.resource, tt.action)
+			assert.Equal(t, tt.isAuthorizedForCluster, got, "IsAuthorized")
+
+			got = authz.IsAuthorizedForAnyKeyspace(ctx, tt.clusterID, tt.resource, tt.action)
+			assert.Equal(t, tt.isAuthorizedForAny, got, "IsAuthorizedForAnyKeyspace")
+		})
+	}
+}
+
+func TestReifyKeyspaces(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		keyspaces []string
+		shouldErr bool
+	}{
+		{
+			name:      "keyspaces and shards",
+			keyspaces: []string{"ks1", "ks2/-80"},
+		},
+		{
+			name:      "wildcard",
+			keyspaces: []string{"*"},
+		},
+		{
+			name:      "wildcard and keyspace",
+			keyspaces: []string{"*", "ks1"},
+			shouldErr: true,
+		},
+		{
+			name:      "empty shard",
+			keyspaces: []string{"ks1/"},
+			shouldErr: true,
+		},
+		{
+			name:      "empty keyspace",
+			keyspaces: []string{"/-80"},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &Config{
+				Rules: []*struct {
+					Resource  string
+					Actions   []string
+					Subjects  []string
+					Clusters  []string
+					Keyspaces []string
+				}{
+					{
+						Resource:  "*",
+						Actions:   []string{"*"},
+						Subjects:  []string{"*"},
+						Clusters:  []string{"*"},
+						Keyspaces: tt.keyspaces,
+					},
+				},
+			}
+
+			err := cfg.Reify()
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -32,11 +32,15 @@ import (
 // cfg.Reify. A config must be reified before first use.
 type Config struct {
 	Authenticator string
-	Rules         []*struct {
-		Resource string
-		Actions  []string
-		Subjects []string
-		Clusters []string
+	// OIDC configures the built-in OIDC authenticator, and is required when
+	// Authenticator is "oidc".
+	OIDC  *OIDCConfig
+	Rules []*struct {
+		Resource  string
+		Actions   []string
+		Subjects  []string
+		Clusters  []string
+		Keyspaces []string
 	}
 
 	reified bool
@@ -107,10 +111,28 @@ func (c *Config) Reify() error {
 			rec.RecordError(fmt.Errorf("rule %d: clusters list cannot include wildcard and other clusters, have %v", i, sets.List(clusters)))
 		}
 
+		keyspaces := sets.New[string](rule.Keyspaces...)
+		if keyspaces.Has("*") && keyspaces.Len() > 1 {
+			// error to have wildcard and something else
+			rec.RecordError(fmt.Errorf("rule %d: keyspaces list cannot include wildcard and other keyspaces, have %v", i, sets.List(keyspaces)))
+		}
+
+		for _, ks := range rule.Keyspaces {
+			if ks == "*" {
+				continue
+			}
+
+			keyspace, shard, isShard := strings.Cut(ks, "/")
+			if keyspace == "" || (isShard && (shard == "" || strings.Contains(shard, "/"))) {
+				rec.RecordError(fmt.Errorf("rule %d: keyspaces list entries must be of the form <keyspace> or <keyspace>/<shard>, have %q", i, ks))
+			}
+		}
+
 		resourceRules = append(resourceRules, &Rule{
-			actions:  actions,
-			subjects: subjects,
-			clusters: clusters,
+			actions:   actions,
+			subjects:  subjects,
+			clusters:  clusters,
+			keyspaces: keyspaces,
 		})
 		byResource[rule.Resource] = resourceRules
 	}
@@ -134,6 +156,13 @@ func (c *Config) Reify() error {
 			return err
 		}
 
+		c.authenticator = authn
+	case c.Authenticator == OIDCAuthenticatorName:
+		authn, err := NewOIDCAuthenticator(c.OIDC)
+		if err != nil {
+			return err
+		}
+
 		c.authenticator = authn
 	case c.Authenticator != "":
 		factory, ok := authenticators[c.Authenticator]
@@ -197,10 +226,11 @@ func DefaultConfig() *Config {
 
 	return &Config{
 		Rules: []*struct {
-			Resource string
-			Actions  []string
-			Subjects []string
-			Clusters []string
+			Resource  string
+			Actions   []string
+			Subjects  []string
+			Clusters  []string
+			Keyspaces []string
 		}{
 			{
 				Resource: "*",
@@ -0,0 +1,403 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc/metadata"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/oidc"
+)
+
+// OIDCAuthenticatorName is the name to set as the config's Authenticator to use
+// the built-in OpenID Connect authenticator. It requires the config's OIDC
+// section to be set.
+const OIDCAuthenticatorName = "oidc"
+
+// OIDC login endpoints, mounted on vtadmin-api's root router.
+const (
+	OIDCLoginPath    = "/oidc/login"
+	OIDCCallbackPath = "/oidc/callback"
+	OIDCLogoutPath   = "/oidc/logout"
+)
+
+var (
+	// ErrMissingIDToken is returned by the OIDC authenticator when a request
+	// carries neither a bearer token nor an id token cookie.
+	ErrMissingIDToken = errors.New("missing OIDC id token")
+	// ErrInvalidIDToken is returned by the OIDC authenticator when an id token
+	// fails validation.
+	ErrInvalidIDToken = oidc.ErrInvalidIDToken
+)
+
+// OIDCConfig configures the OIDC authenticator.
+type OIDCConfig struct {
+	// IssuerURL is the URL of the OpenID provider. Its discovery document must
+	// be served at IssuerURL + "/.well-known/openid-configuration".
+	IssuerURL string `mapstructure:"issuer_url"`
+	// ClientID is the OAuth2 client ID of vtadmin. ID tokens must list it in
+	// their audience.
+	ClientID string `mapstructure:"client_id"`
+	// ClientSecret is the OAuth2 client secret of vtadmin, used to exchange
+	// authorization codes for tokens.
+	ClientSecret string `mapstructure:"client_secret" json:"-"`
+	// RedirectURL is the URL of vtadmin-api's callback endpoint, as registered
+	// with the OpenID provider (e.g. "https://vtadmin-api.example.com/oidc/callback").
+	RedirectURL string `mapstructure:"redirect_url"`
+	// WebURL is the URL of vtadmin-web. Users are only ever redirected back
+	// to URLs under it after logging in or out.
+	WebURL string `mapstructure:"web_url"`
+	// Scopes are the scopes to request. Defaults to openid, email and profile.
+	Scopes []string `mapstructure:"scopes"`
+	// NameClaim is the claim used as the actor's name. Defaults to "email".
+	NameClaim string `mapstructure:"name_claim"`
+	// RolesClaim is the claim used as the actor's roles, either a string or a
+	// list of strings. Defaults to "groups".
+	RolesClaim string `mapstructure:"roles_claim"`
+	// CookieName is the name of the cookie holding the id token after a
+	// login. Defaults to "vtadmin_id_token".
+	CookieName string `mapstructure:"cookie_name"`
+}
+
+const (
+	oidcDefaultNameClaim  = "email"
+	oidcDefaultRolesClaim = "groups"
+	oidcDefaultCookieName = "vtadmin_id_token"
+
+	// oidcStateTTL bounds how long a login may take.
+	oidcStateTTL = 10 * time.Minute
+)
+
+// OIDCAuthenticator authenticates actors from OpenID Connect id tokens, passed
+// either as a bearer token (in the "authorization" header or gRPC metadata) or
+// in the cookie set by its login flow. Tokens are verified with an
+// oidc.Verifier.
+type OIDCAuthenticator struct {
+	cfg      OIDCConfig
+	verifier *oidc.Verifier
+}
+
+var _ Authenticator = (*OIDCAuthenticator)(nil)
+var _ LoginHandler = (*OIDCAuthenticator)(nil)
+
+// NewOIDCAuthenticator returns an OIDCAuthenticator for the given config,
+// filling in its defaults. It does not contact the provider.
+func NewOIDCAuthenticator(cfg *OIDCConfig) (*OIDCAuthenticator, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("the %s authenticator requires an oidc config", OIDCAuthenticatorName)
+	}
+
+	if cfg.IssuerURL == "" || cfg.ClientID == "" {
+		return nil, fmt.Errorf("oidc config requires issuer_url and client_id")
+	}
+
+	c := *cfg
+	c.IssuerURL = strings.TrimSuffix(c.IssuerURL, "/")
+
+	if len(c.Scopes) == 0 {
+		c.Scopes = []string{"openid", "email", "profile"}
+	}
+
+	if c.NameClaim == "" {
+		c.NameClaim = oidcDefaultNameClaim
+	}
+
+	if c.RolesClaim == "" {
+		c.RolesClaim = oidcDefaultRolesClaim
+	}
+
+	if c.CookieName == "" {
+		c.CookieName = oidcDefaultCookieName
+	}
+
+	verifier, err := oidc.NewVerifier(c.IssuerURL, c.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OIDCAuthenticator{
+		cfg:      c,
+		verifier: verifier,
+	}, nil
+}
+
+// Authenticate is part of the Authenticator interface. It reads the id token
+// from the "authorization" gRPC metadata.
+func (authn *OIDCAuthenticator) Authenticate(ctx context.Context) (*Actor, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, v := range md.Get("authorization") {
+		if token, ok := bearerToken(v); ok {
+			return authn.actorFromToken(ctx, token)
+		}
+	}
+
+	return nil, ErrMissingIDToken
+}
+
+// AuthenticateHTTP is part of the Authenticator interface. It reads the id
+// token from the Authorization header, falling back to the login cookie.
+func (authn *OIDCAuthenticator) AuthenticateHTTP(r *http.Request) (*Actor, error) {
+	if token, ok := bearerToken(r.Header.Get("Authorization")); ok {
+		return authn.actorFromToken(r.Context(), token)
+	}
+
+	if cookie, err := r.Cookie(authn.cfg.CookieName); err == nil && cookie.Value != "" {
+		return authn.actorFromToken(r.Context(), cookie.Value)
+	}
+
+	return nil, ErrMissingIDToken
+}
+
+// LoginHandlers is part of the LoginHandler interface.
+func (authn *OIDCAuthenticator) LoginHandlers() map[string]http.Handler {
+	return map[string]http.Handler{
+		OIDCLoginPath:    http.HandlerFunc(authn.handleLogin),
+		OIDCCallbackPath: http.HandlerFunc(authn.handleCallback),
+		OIDCLogoutPath:   http.HandlerFunc(authn.handleLogout),
+	}
+}
+
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func (authn *OIDCAuthenticator) actorFromToken(ctx context.Context, token string) (*Actor, error) {
+	claims, err := authn.verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	name, _ := claims[authn.cfg.NameClaim].(string)
+	if name == "" {
+		return nil, fmt.Errorf("%w: missing %s claim", ErrInvalidIDToken, authn.cfg.NameClaim)
+	}
+
+	actor := &Actor{Name: name}
+	switch roles := claims[authn.cfg.RolesClaim].(type) {
+	case string:
+		actor.Roles = []string{roles}
+	case []any:
+		for _, role := range roles {
+			if role, ok := role.(string); ok {
+				actor.Roles = append(actor.Roles, role)
+			}
+		}
+	}
+
+	return actor, nil
+}
+
+func (authn *OIDCAuthenticator) oauth2Config(ctx context.Context) (*oauth2.Config, error) {
+	discovery, err := authn.verifier.Discovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &oauth2.Config{
+		ClientID:     authn.cfg.ClientID,
+		ClientSecret: authn.cfg.ClientSecret,
+		RedirectURL:  authn.cfg.RedirectURL,
+		Scopes:       authn.cfg.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  discovery.AuthorizationEndpoint,
+			TokenURL: discovery.TokenEndpoint,
+		},
+	}, nil
+}
+
+func (authn *OIDCAuthenticator) stateCookieName() string {
+	return authn.cfg.CookieName + "_state"
+}
+
+func (authn *OIDCAuthenticator) secureCookies(r *http.Request) bool {
+	return r.TLS != nil || strings.HasPrefix(authn.cfg.RedirectURL, "https://")
+}
+
+// redirectTarget returns where to send the user after logging in or out:
+// the "redirect" query parameter if it points into vtadmin-web, or vtadmin-web
+// itself otherwise.
+func (authn *OIDCAuthenticator) redirectTarget(r *http.Request) string {
+	target := r.URL.Query().Get("redirect")
+	if target != "" && authn.cfg.WebURL != "" && isURLUnder(target, authn.cfg.WebURL) {
+		return target
+	}
+
+	if authn.cfg.WebURL != "" {
+		return authn.cfg.WebURL
+	}
+
+	return "/"
+}
+
+func isURLUnder(target string, base string) bool {
+	t, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+
+	b, err := url.Parse(base)
+	if err != nil {
+		return false
+	}
+
+	if t.Scheme != b.Scheme || t.Host != b.Host {
+		return false
+	}
+
+	// Compare whole path segments, so that a base of "/app" does not allow a
+	// sibling like "/app-evil", nor an escape like "/app/../evil".
+	basePath := strings.TrimSuffix(b.Path, "/")
+	if basePath == "" {
+		return true
+	}
+
+	targetPath := path.Clean("/" + t.Path)
+	return targetPath == basePath || strings.HasPrefix(targetPath, basePath+"/")
+}
+
+func (authn *OIDCAuthenticator) handleLogin(w http.ResponseWriter, r *http.Request) {
+	cfg, err := authn.oauth2Config(r.Context())
+	if err != nil {
+		log.Errorf("[rbac]: OIDC login: %s", err)
+		http.Error(w, "OIDC provider unavailable", http.StatusBadGateway)
+		return
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	state := hex.EncodeToString(buf)
+	http.SetCookie(w, &http.Cookie{
+		Name:     authn.stateCookieName(),
+		Value:    state + "." + base64.RawURLEncoding.EncodeToString([]byte(authn.redirectTarget(r))),
+		Path:     OIDCCallbackPath,
+		MaxAge:   int(oidcStateTTL / time.Second),
+		HttpOnly: true,
+		Secure:   authn.secureCookies(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, cfg.AuthCodeURL(state), http.StatusFound)
+}
+
+func (authn *OIDCAuthenticator) handleCallback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	if e := query.Get("error"); e != "" {
+		http.Error(w, fmt.Sprintf("OIDC login failed: %s: %s", e, query.Get("error_description")), http.StatusUnauthorized)
+		return
+	}
+
+	cookie, err := r.Cookie(authn.stateCookieName())
+	if err != nil {
+		http.Error(w, "OIDC login failed: missing state", http.StatusBadRequest)
+		return
+	}
+
+	state, encodedTarget, _ := strings.Cut(cookie.Value, ".")
+	if state == "" || subtle.ConstantTimeCompare([]byte(state), []byte(query.Get("state"))) != 1 {
+		http.Error(w, "OIDC login failed: state mismatch", http.StatusBadRequest)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:   authn.stateCookieName(),
+		Path:   OIDCCallbackPath,
+		MaxAge: -1,
+	})
+
+	cfg, err := authn.oauth2Config(ctx)
+	if err != nil {
+		log.Errorf("[rbac]: OIDC callback: %s", err)
+		http.Error(w, "OIDC provider unavailable", http.StatusBadGateway)
+		return
+	}
+
+	tok, err := cfg.Exchange(context.WithValue(ctx, oauth2.HTTPClient, authn.verifier.HTTPClient()), query.Get("code"))
+	if err != nil {
+		log.Errorf("[rbac]: OIDC callback: failed to exchange code: %s", err)
+		http.Error(w, "OIDC login failed: could not exchange code", http.StatusUnauthorized)
+		return
+	}
+
+	idToken, _ := tok.Extra("id_token").(string)
+	if idToken == "" {
+		http.Error(w, "OIDC login failed: no id_token in token response", http.StatusUnauthorized)
+		return
+	}
+
+	claims, err := authn.verifier.Verify(ctx, idToken)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("OIDC login failed: %s", err), http.StatusUnauthorized)
+		return
+	}
+
+	exp, _ := claims["exp"].(float64)
+	http.SetCookie(w, &http.Cookie{
+		Name:     authn.cfg.CookieName,
+		Value:    idToken,
+		Path:     "/",
+		Expires:  time.Unix(int64(exp), 0),
+		HttpOnly: true,
+		Secure:   authn.secureCookies(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	target := "/"
+	if data, err := base64.RawURLEncoding.DecodeString(encodedTarget); err == nil && len(data) > 0 {
+		target = string(data)
+	}
+
+	http.Redirect(w, r, target, http.StatusFound)
+}
+
+func (authn *OIDCAuthenticator) handleLogout(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     authn.cfg.CookieName,
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   authn.secureCookies(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, authn.redirectTarget(r), http.StatusFound)
+}
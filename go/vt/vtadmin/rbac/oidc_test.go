@@ -0,0 +1,311 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+
+	"vitess.io/vitess/go/vt/oidc/oidctest"
+)
+
+func newTestOIDCAuthenticator(t *testing.T, p *oidctest.Provider) *OIDCAuthenticator {
+	t.Helper()
+
+	authn, err := NewOIDCAuthenticator(&OIDCConfig{
+		IssuerURL:    p.URL,
+		ClientID:     "vtadmin",
+		ClientSecret: "secret",
+		RedirectURL:  "http://vtadmin-api.example.com/oidc/callback",
+		WebURL:       "http://vtadmin.example.com",
+	})
+	require.NoError(t, err)
+
+	return authn
+}
+
+func TestOIDCAuthenticateHTTP(t *testing.T) {
+	t.Parallel()
+
+	p := oidctest.NewProvider(t, "vtadmin")
+	authn := newTestOIDCAuthenticator(t, p)
+
+	tests := []struct {
+		name      string
+		token     func() string
+		expected  *Actor
+		shouldErr bool
+	}{
+		{
+			name: "RS256",
+			token: func() string {
+				return p.Sign(t, "RS256", "rsa", p.Claims(nil))
+			},
+			expected: &Actor{Name: "user@example.com", Roles: []string{"dev", "dba"}},
+		},
+		{
+			name: "ES256",
+			token: func() string {
+				return p.Sign(t, "ES256", "ec", p.Claims(map[string]any{"groups": "dev"}))
+			},
+			expected: &Actor{Name: "user@example.com", Roles: []string{"dev"}},
+		},
+		{
+			name: "audience list",
+			token: func() string {
+				return p.Sign(t, "RS256", "rsa", p.Claims(map[string]any{"aud": []string{"other", "vtadmin"}, "groups": nil}))
+			},
+			expected: &Actor{Name: "user@example.com"},
+		},
+		{
+			name: "expired",
+			token: func() string {
+				return p.Sign(t, "RS256", "rsa", p.Claims(map[string]any{"exp": time.Now().Add(-time.Hour).Unix()}))
+			},
+			shouldErr: true,
+		},
+		{
+			name: "missing name claim",
+			token: func() string {
+				return p.Sign(t, "RS256", "rsa", p.Claims(map[string]any{"email": nil}))
+			},
+			shouldErr: true,
+		},
+		{
+			name: "malformed",
+			token: func() string {
+				return "not-a-jwt"
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/api/clusters", nil)
+			r.Header.Set("Authorization", "Bearer "+tt.token())
+
+			actor, err := authn.AuthenticateHTTP(r)
+			if tt.shouldErr {
+				assert.ErrorIs(t, err, ErrInvalidIDToken)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, actor)
+		})
+	}
+}
+
+func TestOIDCAuthenticate(t *testing.T) {
+	t.Parallel()
+
+	p := oidctest.NewProvider(t, "vtadmin")
+	authn := newTestOIDCAuthenticator(t, p)
+	token := p.Sign(t, "RS256", "rsa", p.Claims(nil))
+
+	t.Run("grpc metadata", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+		actor, err := authn.Authenticate(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "user@example.com", actor.Name)
+	})
+
+	t.Run("grpc missing token", func(t *testing.T) {
+		_, err := authn.Authenticate(context.Background())
+		assert.ErrorIs(t, err, ErrMissingIDToken)
+	})
+
+	t.Run("http cookie", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/api/clusters", nil)
+		r.AddCookie(&http.Cookie{Name: oidcDefaultCookieName, Value: token})
+
+		actor, err := authn.AuthenticateHTTP(r)
+		require.NoError(t, err)
+		assert.Equal(t, "user@example.com", actor.Name)
+	})
+
+	t.Run("http missing token", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/api/clusters", nil)
+
+		_, err := authn.AuthenticateHTTP(r)
+		assert.ErrorIs(t, err, ErrMissingIDToken)
+	})
+}
+
+func TestOIDCLoginFlow(t *testing.T) {
+	t.Parallel()
+
+	p := oidctest.NewProvider(t, "vtadmin")
+	p.IDToken = p.Sign(t, "RS256", "rsa", p.Claims(nil))
+	authn := newTestOIDCAuthenticator(t, p)
+	handlers := authn.LoginHandlers()
+
+	login := func(t *testing.T, redirect string) (state string, stateCookie *http.Cookie) {
+		t.Helper()
+
+		w := httptest.NewRecorder()
+		handlers[OIDCLoginPath].ServeHTTP(w, httptest.NewRequest(http.MethodGet, OIDCLoginPath+"?redirect="+url.QueryEscape(redirect), nil))
+		require.Equal(t, http.StatusFound, w.Code)
+
+		loc, err := url.Parse(w.Header().Get("Location"))
+		require.NoError(t, err)
+		assert.Equal(t, p.URL+"/authorize", loc.Scheme+"://"+loc.Host+loc.Path)
+		assert.Equal(t, "vtadmin", loc.Query().Get("client_id"))
+		assert.Equal(t, "openid email profile", loc.Query().Get("scope"))
+
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+
+		return loc.Query().Get("state"), cookies[0]
+	}
+
+	callback := func(t *testing.T, query string, stateCookie *http.Cookie) *httptest.ResponseRecorder {
+		t.Helper()
+
+		r := httptest.NewRequest(http.MethodGet, OIDCCallbackPath+"?"+query, nil)
+		r.AddCookie(stateCookie)
+
+		w := httptest.NewRecorder()
+		handlers[OIDCCallbackPath].ServeHTTP(w, r)
+
+		return w
+	}
+
+	t.Run("login", func(t *testing.T) {
+		state, stateCookie := login(t, "http://vtadmin.example.com/keyspaces")
+
+		w := callback(t, "code=good-code&state="+state, stateCookie)
+		require.Equal(t, http.StatusFound, w.Code, w.Body.String())
+		assert.Equal(t, "http://vtadmin.example.com/keyspaces", w.Header().Get("Location"))
+
+		var idCookie *http.Cookie
+		for _, c := range w.Result().Cookies() {
+			if c.Name == oidcDefaultCookieName {
+				idCookie = c
+			}
+		}
+		require.NotNil(t, idCookie)
+		assert.Equal(t, p.IDToken, idCookie.Value)
+		assert.True(t, idCookie.HttpOnly)
+	})
+
+	t.Run("redirect outside vtadmin-web", func(t *testing.T) {
+		state, stateCookie := login(t, "https://evil.example.com/")
+
+		w := callback(t, "code=good-code&state="+state, stateCookie)
+		require.Equal(t, http.StatusFound, w.Code, w.Body.String())
+		assert.Equal(t, "http://vtadmin.example.com", w.Header().Get("Location"))
+	})
+
+	t.Run("state mismatch", func(t *testing.T) {
+		_, stateCookie := login(t, "")
+
+		w := callback(t, "code=good-code&state=forged", stateCookie)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("bad code", func(t *testing.T) {
+		state, stateCookie := login(t, "")
+
+		w := callback(t, "code=bad-code&state="+state, stateCookie)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("logout", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handlers[OIDCLogoutPath].ServeHTTP(w, httptest.NewRequest(http.MethodGet, OIDCLogoutPath, nil))
+		require.Equal(t, http.StatusFound, w.Code)
+
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.Equal(t, oidcDefaultCookieName, cookies[0].Name)
+		assert.Less(t, cookies[0].MaxAge, 0)
+	})
+}
+
+func TestIsURLUnder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		target string
+		base   string
+		want   bool
+	}{
+		{target: "http://vtadmin.example.com/keyspaces", base: "http://vtadmin.example.com", want: true},
+		{target: "http://vtadmin.example.com/app", base: "http://vtadmin.example.com/app", want: true},
+		{target: "http://vtadmin.example.com/app/keyspaces", base: "http://vtadmin.example.com/app/", want: true},
+		{target: "http://vtadmin.example.com/app-evil", base: "http://vtadmin.example.com/app", want: false},
+		{target: "http://vtadmin.example.com/app/../evil", base: "http://vtadmin.example.com/app", want: false},
+		{target: "https://vtadmin.example.com/app", base: "http://vtadmin.example.com/app", want: false},
+		{target: "http://evil.example.com/app", base: "http://vtadmin.example.com/app", want: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.target+" under "+tt.base, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, isURLUnder(tt.target, tt.base))
+		})
+	}
+}
+
+func TestLoadConfigOIDC(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "rbac.yaml")
+	err := os.WriteFile(path, []byte(`authenticator: oidc
+oidc:
+  issuer_url: https://accounts.example.com/
+  client_id: vtadmin
+  roles_claim: roles
+rules:
+  - resource: "*"
+    actions: ["get"]
+    subjects: ["role:dev"]
+    clusters: ["*"]
+    keyspaces: ["commerce"]
+`), 0o644)
+	require.NoError(t, err)
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+
+	authn, ok := cfg.GetAuthenticator().(*OIDCAuthenticator)
+	require.True(t, ok, "expected an *OIDCAuthenticator, got %T", cfg.GetAuthenticator())
+	assert.Equal(t, "https://accounts.example.com", authn.cfg.IssuerURL)
+	assert.Equal(t, "roles", authn.cfg.RolesClaim)
+	assert.Equal(t, oidcDefaultNameClaim, authn.cfg.NameClaim)
+
+	cfg = &Config{Authenticator: OIDCAuthenticatorName}
+	assert.Error(t, cfg.Reify(), "oidc authenticator without an oidc config should fail to reify")
+}
@@ -39,7 +39,9 @@ conforms to the expected Authenticator interface, and vtadmin will use that when
 setting up the interceptors/middlewares. Currently, authenticators may be
 registered at runtime via the rbac.RegisterAuthenticator method, or may be set
 as a Go plugin (built via `go build -buildmode=plugin`) by setting the
-authenticator name as a path ending in ".so" in the rbac config.
+authenticator name as a path ending in ".so" in the rbac config. An OpenID
+Connect authenticator is also built in, under the name "oidc" (see
+OIDCAuthenticator).
 
 2. Permissions are additive. There is no concept of a negative permission (or
 revocation). To "revoke" a permission from a user or role, structure your rules
@@ -54,6 +56,13 @@ check before calling into the cluster.
 5. Being unauthorized for an <action, resource> for a cluster does not fail the
 overall request. Instead, the action is simply not taken in that cluster, and is
 still taken in other clusters for which the actor is authorized.
+
+6. Rules may be further scoped to particular keyspaces ("<keyspace>") or shards
+("<keyspace>/<shard>") within their clusters. A scoped rule only grants access
+to its keyspaces and shards, which lets several teams share one vtadmin without
+seeing or operating on each other's keyspaces. API methods that return
+resources from many keyspaces filter out the resources the actor is not
+authorized for, in the same way they skip unauthorized clusters.
 */
 package rbac
 
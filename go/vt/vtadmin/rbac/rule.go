@@ -24,9 +24,10 @@ import (
 
 // Rule is a single rule governing access to a particular resource.
 type Rule struct {
-	clusters sets.Set[string]
-	actions  sets.Set[string]
-	subjects sets.Set[string]
+	clusters  sets.Set[string]
+	actions   sets.Set[string]
+	subjects  sets.Set[string]
+	keyspaces sets.Set[string]
 }
 
 // Allows returns true if the actor is allowed to take the specified action in
@@ -34,7 +35,48 @@ type Rule struct {
 //
 // A nil actor signifies the unauthenticated state, and is only allowed access
 // if the rule contains the wildcard ("*") subject.
+//
+// A rule scoped to particular keyspaces or shards never allows an action on an
+// entire cluster; use AllowsKeyspace to check those rules.
 func (r *Rule) Allows(clusterID string, action Action, actor *Actor) bool {
+	if r.isScoped() {
+		return false
+	}
+
+	return r.matches(clusterID, action, actor)
+}
+
+// AllowsKeyspace returns true if the actor is allowed to take the specified
+// action on the specified keyspace in the specified cluster. If shard is
+// non-empty, the action is allowed by rules scoped to either the whole keyspace
+// or to that single shard ("<keyspace>/<shard>"); otherwise, only rules scoped
+// to the whole keyspace allow it.
+//
+// Rules that are not scoped to any keyspace apply to every keyspace.
+func (r *Rule) AllowsKeyspace(clusterID string, keyspace string, shard string, action Action, actor *Actor) bool {
+	if !r.matches(clusterID, action, actor) {
+		return false
+	}
+
+	if !r.isScoped() || r.keyspaces.Has(keyspace) {
+		return true
+	}
+
+	return shard != "" && r.keyspaces.Has(fmt.Sprintf("%s/%s", keyspace, shard))
+}
+
+// AllowsAnyKeyspace returns true if the actor is allowed to take the specified
+// action on at least one keyspace (or shard) in the specified cluster,
+// regardless of how the rule is scoped.
+func (r *Rule) AllowsAnyKeyspace(clusterID string, action Action, actor *Actor) bool {
+	return r.matches(clusterID, action, actor)
+}
+
+func (r *Rule) isScoped() bool {
+	return r.keyspaces.Len() > 0 && !r.keyspaces.Has("*")
+}
+
+func (r *Rule) matches(clusterID string, action Action, actor *Actor) bool {
 	if r.clusters.HasAny("*", clusterID) {
 		if r.actions.HasAny("*", string(action)) {
 			if r.subjects.Has("*") {
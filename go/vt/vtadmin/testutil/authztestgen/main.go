@@ -60,10 +60,11 @@ type TestCase struct {
 }
 
 type AuthzRules struct {
-	Resource string   `json:"resource"`
-	Actions  []string `json:"actions"`
-	Subjects []string `json:"subjects"`
-	Clusters []string `json:"clusters"`
+	Resource  string   `json:"resource"`
+	Actions   []string `json:"actions"`
+	Subjects  []string `json:"subjects"`
+	Clusters  []string `json:"clusters"`
+	Keyspaces []string `json:"keyspaces,omitempty"`
 }
 
 type FakeVtctldClientResult struct {
@@ -68,10 +68,11 @@ func Test{{ .Method }}(t *testing.T) {
 	opts := vtadmin.Options{
 		RBAC: &rbac.Config{
 			Rules: []*struct{
-				Resource string
-				Actions  []string
-				Subjects []string
-				Clusters []string
+				Resource  string
+				Actions   []string
+				Subjects  []string
+				Clusters  []string
+				Keyspaces []string
 			}{
 				{{- range .Rules }}
 				{
@@ -79,6 +80,9 @@ func Test{{ .Method }}(t *testing.T) {
 					Actions:  []string{ {{ range .Actions }}"{{ . }}",{{ end }} },
 					Subjects: []string{ {{ range .Subjects }}"{{ . }}",{{ end }} },
 					Clusters: []string{ {{ range .Clusters }}"{{ . }}",{{ end }} },
+					{{- with .Keyspaces }}
+					Keyspaces: []string{ {{ range . }}"{{ . }}",{{ end }} },
+					{{- end }}
 				},
 				{{- end }}
 			},
@@ -139,6 +139,10 @@ type VtctldClient struct {
 		Response *vtctldatapb.ValidateVersionKeyspaceResponse
 		Error    error
 	}
+	WorkflowStatusResults map[string]struct {
+		Response *vtctldatapb.WorkflowStatusResponse
+		Error    error
+	}
 	WorkflowUpdateResults map[string]struct {
 		Response *vtctldatapb.WorkflowUpdateResponse
 		Error    error
@@ -676,6 +680,19 @@ func (fake *VtctldClient) ValidateVersionKeyspace(ctx context.Context, req *vtct
 	return nil, fmt.Errorf("%w: no result set for %s", assert.AnError, key)
 }
 
+// WorkflowStatus is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) WorkflowStatus(ctx context.Context, req *vtctldatapb.WorkflowStatusRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowStatusResponse, error) {
+	if fake.WorkflowStatusResults == nil {
+		return nil, fmt.Errorf("%w: WorkflowStatusResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	if result, ok := fake.WorkflowStatusResults[req.Keyspace]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for keyspace %s", assert.AnError, req.Keyspace)
+}
+
 // WorkflowUpdate is part of the vtctldclient.VtctldClient interface.
 func (fake *VtctldClient) WorkflowUpdate(ctx context.Context, req *vtctldatapb.WorkflowUpdateRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowUpdateResponse, error) {
 	if fake.WorkflowUpdateResults == nil {
@@ -766,6 +766,54 @@ func (itmc *internalTabletManagerClient) GetPermissions(ctx context.Context, tab
 	return t.tm.GetPermissions(ctx)
 }
 
+func (itmc *internalTabletManagerClient) GetPlanCache(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.GetPlanCacheRequest) (*tabletmanagerdatapb.GetPlanCacheResponse, error) {
+	t, ok := tabletMap[tablet.Alias.Uid]
+	if !ok {
+		return nil, fmt.Errorf("tmclient: cannot find tablet %v", tablet.Alias.Uid)
+	}
+	entries, err := t.tm.GetPlanCache(ctx, request.Tables, request.Fingerprints)
+	if err != nil {
+		return nil, err
+	}
+	return &tabletmanagerdatapb.GetPlanCacheResponse{Entries: entries}, nil
+}
+
+func (itmc *internalTabletManagerClient) GetTableStats(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.GetTableStatsRequest) (*tabletmanagerdatapb.GetTableStatsResponse, error) {
+	t, ok := tabletMap[tablet.Alias.Uid]
+	if !ok {
+		return nil, fmt.Errorf("tmclient: cannot find tablet %v", tablet.Alias.Uid)
+	}
+	tableStats, err := t.tm.GetTableStats(ctx, request.Tables)
+	if err != nil {
+		return nil, err
+	}
+	return &tabletmanagerdatapb.GetTableStatsResponse{TableStats: tableStats}, nil
+}
+
+func (itmc *internalTabletManagerClient) GetUnresolvedTransactions(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.GetUnresolvedTransactionsRequest) (*tabletmanagerdatapb.GetUnresolvedTransactionsResponse, error) {
+	t, ok := tabletMap[tablet.Alias.Uid]
+	if !ok {
+		return nil, fmt.Errorf("tmclient: cannot find tablet %v", tablet.Alias.Uid)
+	}
+	transactions, err := t.tm.GetUnresolvedTransactions(ctx, time.Duration(request.AbandonAge)*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	return &tabletmanagerdatapb.GetUnresolvedTransactionsResponse{Transactions: transactions}, nil
+}
+
+func (itmc *internalTabletManagerClient) ReadTransaction(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.ReadTransactionRequest) (*tabletmanagerdatapb.ReadTransactionResponse, error) {
+	t, ok := tabletMap[tablet.Alias.Uid]
+	if !ok {
+		return nil, fmt.Errorf("tmclient: cannot find tablet %v", tablet.Alias.Uid)
+	}
+	transaction, err := t.tm.ReadTransaction(ctx, request.Dtid)
+	if err != nil {
+		return nil, err
+	}
+	return &tabletmanagerdatapb.ReadTransactionResponse{Transaction: transaction}, nil
+}
+
 func (itmc *internalTabletManagerClient) SetReadOnly(ctx context.Context, tablet *topodatapb.Tablet) error {
 	return fmt.Errorf("not implemented in vtcombo")
 }
@@ -821,6 +869,29 @@ func (itmc *internalTabletManagerClient) ReloadSchema(ctx context.Context, table
 	return t.tm.ReloadSchema(ctx, waitPosition)
 }
 
+func (itmc *internalTabletManagerClient) InvalidatePlanCache(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.InvalidatePlanCacheRequest) (*tabletmanagerdatapb.InvalidatePlanCacheResponse, error) {
+	t, ok := tabletMap[tablet.Alias.Uid]
+	if !ok {
+		return nil, fmt.Errorf("tmclient: cannot find tablet %v", tablet.Alias.Uid)
+	}
+	invalidated, err := t.tm.InvalidatePlanCache(ctx, request.Tables, request.Fingerprints, request.All)
+	if err != nil {
+		return nil, err
+	}
+	return &tabletmanagerdatapb.InvalidatePlanCacheResponse{Invalidated: uint64(invalidated)}, nil
+}
+
+func (itmc *internalTabletManagerClient) ConcludeTransaction(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.ConcludeTransactionRequest) (*tabletmanagerdatapb.ConcludeTransactionResponse, error) {
+	t, ok := tabletMap[tablet.Alias.Uid]
+	if !ok {
+		return nil, fmt.Errorf("tmclient: cannot find tablet %v", tablet.Alias.Uid)
+	}
+	if err := t.tm.ConcludeTransaction(ctx, request.Dtid, request.Mm, request.Commit); err != nil {
+		return nil, err
+	}
+	return &tabletmanagerdatapb.ConcludeTransactionResponse{}, nil
+}
+
 func (itmc *internalTabletManagerClient) PreflightSchema(ctx context.Context, tablet *topodatapb.Tablet, changes []string) ([]*tabletmanagerdatapb.SchemaChangeResult, error) {
 	t, ok := tabletMap[tablet.Alias.Uid]
 	if !ok {
@@ -929,7 +1000,11 @@ func (itmc *internalTabletManagerClient) Backup(context.Context, *topodatapb.Tab
 	return nil, fmt.Errorf("not implemented in vtcombo")
 }
 
-func (itmc *internalTabletManagerClient) RestoreFromBackup(context.Context, *topodatapb.Tablet, *tabletmanagerdatapb.RestoreFromBackupRequest) (logutil.EventStream, error) {
+func (itmc *internalTabletManagerClient) RestoreFromBackup(context.Context, *topodatapb.Tablet, *tabletmanagerdatapb.RestoreFromBackupRequest) (tmclient.RestoreFromBackupStream, error) {
+	return nil, fmt.Errorf("not implemented in vtcombo")
+}
+
+func (itmc *internalTabletManagerClient) UpgradeMysql(context.Context, *topodatapb.Tablet, *tabletmanagerdatapb.UpgradeMysqlRequest) (logutil.EventStream, error) {
 	return nil, fmt.Errorf("not implemented in vtcombo")
 }
 
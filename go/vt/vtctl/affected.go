@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctl
+
+import (
+	"context"
+
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/vtctlclient"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+type affectedEntitiesKey struct{}
+
+// WithAffectedEntities returns a context in which the commands run by
+// RunCommand report the keyspaces, shards and tablets they act on to the
+// returned AffectedEntities.
+func WithAffectedEntities(ctx context.Context) (context.Context, *vtctlclient.AffectedEntities) {
+	affected := &vtctlclient.AffectedEntities{}
+	return context.WithValue(ctx, affectedEntitiesKey{}, affected), affected
+}
+
+// affectedEntities returns the AffectedEntities of ctx, if any.
+func affectedEntities(ctx context.Context) *vtctlclient.AffectedEntities {
+	affected, _ := ctx.Value(affectedEntitiesKey{}).(*vtctlclient.AffectedEntities)
+	return affected
+}
+
+// reportKeyspace reports that the command acts on a keyspace.
+func reportKeyspace(ctx context.Context, keyspace string) {
+	if affected := affectedEntities(ctx); affected != nil {
+		affected.AddKeyspace(keyspace)
+	}
+}
+
+// reportShard reports that the command acts on a shard.
+func reportShard(ctx context.Context, keyspace, shard string) {
+	if affected := affectedEntities(ctx); affected != nil {
+		affected.AddShard(keyspace, shard)
+	}
+}
+
+// reportTablet reports that the command acts on a tablet.
+func reportTablet(ctx context.Context, alias *topodatapb.TabletAlias) {
+	if affected := affectedEntities(ctx); affected != nil {
+		affected.AddTablet(topoproto.TabletAliasString(alias))
+	}
+}
+
+// parseKeyspaceShard parses a keyspace/shard, and reports that the command
+// acts on that shard.
+func parseKeyspaceShard(ctx context.Context, param string) (string, string, error) {
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(param)
+	if err != nil {
+		return "", "", err
+	}
+	reportShard(ctx, keyspace, shard)
+	return keyspace, shard, nil
+}
+
+// parseTabletAlias parses a tablet alias, and reports that the command acts
+// on that tablet.
+func parseTabletAlias(ctx context.Context, param string) (*topodatapb.TabletAlias, error) {
+	alias, err := topoproto.ParseTabletAlias(param)
+	if err != nil {
+		return nil, err
+	}
+	reportTablet(ctx, alias)
+	return alias, nil
+}
@@ -28,7 +28,6 @@ import (
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/mysqlctl"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
-	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/wrangler"
 
@@ -82,7 +81,7 @@ func commandBackup(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.F
 		return fmt.Errorf("the Backup command requires the <tablet alias> argument")
 	}
 
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := parseTabletAlias(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -124,7 +123,7 @@ func commandBackupShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *pf
 		return fmt.Errorf("action BackupShard requires <keyspace/shard>")
 	}
 
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -147,7 +146,7 @@ func commandListBackups(ctx context.Context, wr *wrangler.Wrangler, subFlags *pf
 		return fmt.Errorf("action ListBackups requires <keyspace/shard>")
 	}
 
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -176,7 +175,7 @@ func commandRemoveBackup(ctx context.Context, wr *wrangler.Wrangler, subFlags *p
 		return fmt.Errorf("action RemoveBackup requires <keyspace/shard> <backup name>")
 	}
 
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -201,7 +200,11 @@ type backupRestoreEventStreamLogger struct {
 func (b *backupRestoreEventStreamLogger) Context() context.Context { return b.ctx }
 
 func (b *backupRestoreEventStreamLogger) Send(resp *vtctldatapb.RestoreFromBackupResponse) error {
-	logutil.LogEvent(b.logger, resp.Event)
+	// The tablet also logs the progress of the restore, so that only
+	// the events need to be logged.
+	if resp.Event != nil {
+		logutil.LogEvent(b.logger, resp.Event)
+	}
 	return nil
 }
 
@@ -229,7 +232,7 @@ func commandRestoreFromBackup(ctx context.Context, wr *wrangler.Wrangler, subFla
 		}
 	}
 
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := parseTabletAlias(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -39,7 +39,7 @@ func init() {
 	addCommand(cellsGroupName, command{
 		name:   "AddCellInfo",
 		method: commandAddCellInfo,
-		params: "[--server_address <addr>] [--root <root>] <cell>",
+		params: "[--server_address <addr>] [--root <root>] [--if-not-exists] <cell>",
 		help:   "Registers a local topology service in a new cell by creating the CellInfo with the provided parameters. The address will be used to connect to the topology service, and we'll put Vitess data starting at the provided root.",
 	})
 
@@ -75,6 +75,7 @@ func init() {
 func commandAddCellInfo(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
 	serverAddress := subFlags.String("server_address", "", "The address the topology server is using for that cell.")
 	root := subFlags.String("root", "", "The root path the topology server is using for that cell.")
+	ifNotExists := subFlags.Bool("if-not-exists", false, "Succeeds without making any changes if the cell already exists.")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -83,14 +84,22 @@ func commandAddCellInfo(ctx context.Context, wr *wrangler.Wrangler, subFlags *pf
 	}
 	cell := subFlags.Arg(0)
 
-	_, err := wr.VtctldServer().AddCellInfo(ctx, &vtctldatapb.AddCellInfoRequest{
+	resp, err := wr.VtctldServer().AddCellInfo(ctx, &vtctldatapb.AddCellInfoRequest{
 		Name: cell,
 		CellInfo: &topodatapb.CellInfo{
 			ServerAddress: *serverAddress,
 			Root:          *root,
 		},
+		IfNotExists: *ifNotExists,
 	})
-	return err
+	if err != nil {
+		return err
+	}
+
+	if resp.AlreadyExists {
+		wr.Logger().Printf("Cell %v already exists, unchanged\n", cell)
+	}
+	return nil
 }
 
 func commandUpdateCellInfo(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
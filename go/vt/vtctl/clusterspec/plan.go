@@ -0,0 +1,371 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterspec
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/vt/vtctl/vtctldclient"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// Action is what a Change does to its resource.
+type Action string
+
+const (
+	// ActionCreate creates a resource that does not exist yet.
+	ActionCreate Action = "create"
+	// ActionUpdate updates an existing resource.
+	ActionUpdate Action = "update"
+)
+
+// Change is a single step of a Plan.
+type Change struct {
+	Action Action `json:"action"`
+	// Resource describes what the change applies to, such as
+	// "keyspace commerce" or "vschema of keyspace commerce".
+	Resource string `json:"resource"`
+	// Details lists what the change does to the resource, one item per line.
+	Details []string `json:"details,omitempty"`
+
+	apply func(ctx context.Context, client vtctldclient.VtctldClient) error
+}
+
+// Apply makes the change, through the given vtctld client.
+func (c *Change) Apply(ctx context.Context, client vtctldclient.VtctldClient) error {
+	if err := c.apply(ctx, client); err != nil {
+		return fmt.Errorf("cannot %s %s: %w", c.Action, c.Resource, err)
+	}
+
+	return nil
+}
+
+// String returns a one-line summary of the change, followed by its details.
+func (c *Change) String() string {
+	var sb strings.Builder
+
+	switch c.Action {
+	case ActionCreate:
+		sb.WriteString("+ ")
+	case ActionUpdate:
+		sb.WriteString("~ ")
+	}
+	fmt.Fprintf(&sb, "%s %s", c.Action, c.Resource)
+
+	for _, detail := range c.Details {
+		fmt.Fprintf(&sb, "\n    %s", detail)
+	}
+
+	return sb.String()
+}
+
+// Plan is the ordered list of changes that bring the topology in line with a
+// Spec.
+type Plan struct {
+	Changes []*Change `json:"changes"`
+}
+
+// Empty returns whether the topology already matches the spec.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// String formats the plan for humans.
+func (p *Plan) String() string {
+	if p.Empty() {
+		return "No changes. The topology matches the spec."
+	}
+
+	var (
+		sb      strings.Builder
+		creates int
+		updates int
+	)
+	for _, c := range p.Changes {
+		sb.WriteString(c.String())
+		sb.WriteString("\n")
+
+		switch c.Action {
+		case ActionCreate:
+			creates++
+		case ActionUpdate:
+			updates++
+		}
+	}
+
+	fmt.Fprintf(&sb, "\nPlan: %d to create, %d to update.", creates, updates)
+	return sb.String()
+}
+
+// Apply makes all the changes of the plan, in order, and stops at the first
+// one that fails. Changes that were already applied are not rolled back, but
+// computing a new plan picks up where the failed one stopped.
+func (p *Plan) Apply(ctx context.Context, client vtctldclient.VtctldClient) error {
+	for _, c := range p.Changes {
+		if err := c.Apply(ctx, client); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ComputePlan diffs the spec against the live topology, as seen by the given
+// vtctld client, and returns the changes needed to make the topology match the
+// spec. Keyspaces are created before their shards and vschemas, and routing
+// rules are updated last, since they may route to any of the keyspaces.
+func ComputePlan(ctx context.Context, client vtctldclient.VtctldClient, spec *Spec) (*Plan, error) {
+	resp, err := client.GetKeyspaces(ctx, &vtctldatapb.GetKeyspacesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("GetKeyspaces failed: %w", err)
+	}
+
+	keyspaces := make(map[string]*vtctldatapb.Keyspace, len(resp.Keyspaces))
+	for _, ks := range resp.Keyspaces {
+		keyspaces[ks.Name] = ks
+	}
+
+	plan := &Plan{}
+	for _, ks := range spec.Keyspaces {
+		changes, err := planKeyspace(ctx, client, ks, keyspaces[ks.Name])
+		if err != nil {
+			return nil, err
+		}
+
+		plan.Changes = append(plan.Changes, changes...)
+	}
+
+	if spec.routingRules != nil {
+		change, err := planRoutingRules(ctx, client, spec.routingRules)
+		if err != nil {
+			return nil, err
+		}
+
+		if change != nil {
+			plan.Changes = append(plan.Changes, change)
+		}
+	}
+
+	return plan, nil
+}
+
+func planKeyspace(ctx context.Context, client vtctldclient.VtctldClient, spec *KeyspaceSpec, current *vtctldatapb.Keyspace) ([]*Change, error) {
+	var changes []*Change
+
+	// Keyspaces get an empty vschema when they are created.
+	currentVSchema := &vschemapb.Keyspace{}
+	currentShards := map[string]bool{}
+
+	if current == nil {
+		details := []string{}
+		if spec.DurabilityPolicy != "" {
+			details = append(details, fmt.Sprintf("durability policy: %s", spec.DurabilityPolicy))
+		}
+		if spec.SidecarDBName != "" {
+			details = append(details, fmt.Sprintf("sidecar database: %s", spec.SidecarDBName))
+		}
+
+		changes = append(changes, &Change{
+			Action:   ActionCreate,
+			Resource: "keyspace " + spec.Name,
+			Details:  details,
+			apply: func(ctx context.Context, client vtctldclient.VtctldClient) error {
+				_, err := client.CreateKeyspace(ctx, &vtctldatapb.CreateKeyspaceRequest{
+					Name:             spec.Name,
+					DurabilityPolicy: spec.DurabilityPolicy,
+					SidecarDbName:    spec.SidecarDBName,
+				})
+				return err
+			},
+		})
+	} else {
+		if spec.SidecarDBName != "" && spec.SidecarDBName != current.Keyspace.SidecarDbName {
+			return nil, fmt.Errorf("cannot change the sidecar database of keyspace %s from %s to %s", spec.Name, current.Keyspace.SidecarDbName, spec.SidecarDBName)
+		}
+
+		if spec.DurabilityPolicy != "" && spec.DurabilityPolicy != current.Keyspace.DurabilityPolicy {
+			changes = append(changes, &Change{
+				Action:   ActionUpdate,
+				Resource: "durability policy of keyspace " + spec.Name,
+				Details:  []string{fmt.Sprintf("%q => %q", current.Keyspace.DurabilityPolicy, spec.DurabilityPolicy)},
+				apply: func(ctx context.Context, client vtctldclient.VtctldClient) error {
+					_, err := client.SetKeyspaceDurabilityPolicy(ctx, &vtctldatapb.SetKeyspaceDurabilityPolicyRequest{
+						Keyspace:         spec.Name,
+						DurabilityPolicy: spec.DurabilityPolicy,
+					})
+					return err
+				},
+			})
+		}
+
+		resp, err := client.FindAllShardsInKeyspace(ctx, &vtctldatapb.FindAllShardsInKeyspaceRequest{Keyspace: spec.Name})
+		if err != nil {
+			return nil, fmt.Errorf("FindAllShardsInKeyspace(%s) failed: %w", spec.Name, err)
+		}
+		for shard := range resp.Shards {
+			currentShards[shard] = true
+		}
+
+		if spec.vschema != nil {
+			vsResp, err := client.GetVSchema(ctx, &vtctldatapb.GetVSchemaRequest{Keyspace: spec.Name})
+			switch {
+			case err == nil:
+				currentVSchema = vsResp.VSchema
+			case strings.Contains(err.Error(), "node doesn't exist"):
+				// The keyspace was created without a vschema.
+			default:
+				return nil, fmt.Errorf("GetVSchema(%s) failed: %w", spec.Name, err)
+			}
+		}
+	}
+
+	for _, shard := range spec.Shards {
+		if currentShards[shard] {
+			continue
+		}
+
+		shard := shard
+		changes = append(changes, &Change{
+			Action:   ActionCreate,
+			Resource: fmt.Sprintf("shard %s/%s", spec.Name, shard),
+			apply: func(ctx context.Context, client vtctldclient.VtctldClient) error {
+				_, err := client.CreateShard(ctx, &vtctldatapb.CreateShardRequest{
+					Keyspace:  spec.Name,
+					ShardName: shard,
+				})
+				return err
+			},
+		})
+	}
+
+	if spec.vschema != nil && !proto.Equal(currentVSchema, spec.vschema) {
+		changes = append(changes, &Change{
+			Action:   ActionUpdate,
+			Resource: "vschema of keyspace " + spec.Name,
+			Details:  diffVSchema(currentVSchema, spec.vschema),
+			apply: func(ctx context.Context, client vtctldclient.VtctldClient) error {
+				_, err := client.ApplyVSchema(ctx, &vtctldatapb.ApplyVSchemaRequest{
+					Keyspace: spec.Name,
+					VSchema:  spec.vschema,
+				})
+				return err
+			},
+		})
+	}
+
+	return changes, nil
+}
+
+func planRoutingRules(ctx context.Context, client vtctldclient.VtctldClient, rules *vschemapb.RoutingRules) (*Change, error) {
+	resp, err := client.GetRoutingRules(ctx, &vtctldatapb.GetRoutingRulesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("GetRoutingRules failed: %w", err)
+	}
+
+	current := resp.RoutingRules
+	if current == nil {
+		current = &vschemapb.RoutingRules{}
+	}
+
+	if proto.Equal(current, rules) {
+		return nil, nil
+	}
+
+	return &Change{
+		Action:   ActionUpdate,
+		Resource: "routing rules",
+		Details:  diffRoutingRules(current, rules),
+		apply: func(ctx context.Context, client vtctldclient.VtctldClient) error {
+			_, err := client.ApplyRoutingRules(ctx, &vtctldatapb.ApplyRoutingRulesRequest{RoutingRules: rules})
+			return err
+		},
+	}, nil
+}
+
+// diffNamed returns the lines describing the differences between two sets of
+// named objects, sorted by name.
+func diffNamed[T proto.Message](kind string, current, desired map[string]T) []string {
+	names := make([]string, 0, len(current)+len(desired))
+	for name := range current {
+		names = append(names, name)
+	}
+	for name := range desired {
+		if _, ok := current[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var lines []string
+	for _, name := range names {
+		c, inCurrent := current[name]
+		d, inDesired := desired[name]
+
+		switch {
+		case !inCurrent:
+			lines = append(lines, fmt.Sprintf("+ %s %s", kind, name))
+		case !inDesired:
+			lines = append(lines, fmt.Sprintf("- %s %s", kind, name))
+		case !proto.Equal(c, d):
+			lines = append(lines, fmt.Sprintf("~ %s %s", kind, name))
+		}
+	}
+
+	return lines
+}
+
+func diffVSchema(current, desired *vschemapb.Keyspace) []string {
+	var lines []string
+
+	// Compare everything but the vindexes and tables, which are diffed one by
+	// one below.
+	currentSettings := proto.Clone(current).(*vschemapb.Keyspace)
+	currentSettings.Vindexes, currentSettings.Tables = nil, nil
+	desiredSettings := proto.Clone(desired).(*vschemapb.Keyspace)
+	desiredSettings.Vindexes, desiredSettings.Tables = nil, nil
+
+	if current.Sharded != desired.Sharded {
+		lines = append(lines, fmt.Sprintf("~ sharded: %t => %t", current.Sharded, desired.Sharded))
+		currentSettings.Sharded = desired.Sharded
+	}
+	if !proto.Equal(currentSettings, desiredSettings) {
+		lines = append(lines, "~ keyspace settings")
+	}
+
+	lines = append(lines, diffNamed("vindex", current.Vindexes, desired.Vindexes)...)
+	lines = append(lines, diffNamed("table", current.Tables, desired.Tables)...)
+	return lines
+}
+
+func diffRoutingRules(current, desired *vschemapb.RoutingRules) []string {
+	rules := func(rr *vschemapb.RoutingRules) map[string]*vschemapb.RoutingRule {
+		m := make(map[string]*vschemapb.RoutingRule, len(rr.Rules))
+		for _, rule := range rr.Rules {
+			m[rule.FromTable] = rule
+		}
+		return m
+	}
+
+	return diffNamed("rule", rules(current), rules(desired))
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterspec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver/testutil"
+	"vitess.io/vitess/go/vt/vtctl/localvtctldclient"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtctlservicepb "vitess.io/vitess/go/vt/proto/vtctlservice"
+)
+
+func TestPlan(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return grpcvtctldserver.NewVtctldServer(ts)
+	})
+	client := localvtctldclient.New(vtctld)
+
+	testutil.AddKeyspace(ctx, t, ts, &vtctldatapb.Keyspace{
+		Name:     "customer",
+		Keyspace: &topodatapb.Keyspace{DurabilityPolicy: "none"},
+	})
+	testutil.AddShards(ctx, t, ts, &vtctldatapb.Shard{Keyspace: "customer", Name: "-80"})
+	require.NoError(t, ts.EnsureVSchema(ctx, "customer"))
+
+	spec, err := Parse([]byte(`
+keyspaces:
+  - name: commerce
+    shards: ["0"]
+    vschema:
+      tables:
+        product: {}
+  - name: customer
+    durability_policy: semi_sync
+    shards: ["-80", "80-"]
+    vschema:
+      sharded: true
+      vindexes:
+        hash:
+          type: hash
+      tables:
+        customer:
+          column_vindexes:
+            - column: customer_id
+              name: hash
+  - name: unchanged
+routing_rules:
+  rules:
+    - from_table: product
+      to_tables: ["commerce.product"]
+`))
+	require.NoError(t, err)
+
+	testutil.AddKeyspace(ctx, t, ts, &vtctldatapb.Keyspace{
+		Name:     "unchanged",
+		Keyspace: &topodatapb.Keyspace{},
+	})
+
+	plan, err := ComputePlan(ctx, client, spec)
+	require.NoError(t, err)
+
+	expected := `+ create keyspace commerce
++ create shard commerce/0
+~ update vschema of keyspace commerce
+    + table product
+~ update durability policy of keyspace customer
+    "none" => "semi_sync"
++ create shard customer/80-
+~ update vschema of keyspace customer
+    ~ sharded: false => true
+    + vindex hash
+    + table customer
+~ update routing rules
+    + rule product
+
+Plan: 3 to create, 4 to update.`
+	assert.Equal(t, expected, plan.String())
+
+	require.NoError(t, plan.Apply(ctx, client))
+
+	ki, err := ts.GetKeyspace(ctx, "customer")
+	require.NoError(t, err)
+	assert.Equal(t, "semi_sync", ki.DurabilityPolicy)
+
+	shards, err := ts.GetShardNames(ctx, "customer")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"-80", "80-"}, shards)
+
+	// Once applied, the topology matches the spec.
+	plan, err = ComputePlan(ctx, client, spec)
+	require.NoError(t, err)
+	assert.True(t, plan.Empty(), "unexpected changes after apply:\n%s", plan)
+
+	// Changing the sidecar database of an existing keyspace is not supported.
+	spec, err = Parse([]byte(`keyspaces: [{name: customer, sidecar_db_name: other}]`))
+	require.NoError(t, err)
+	_, err = ComputePlan(ctx, client, spec)
+	assert.ErrorContains(t, err, "cannot change the sidecar database of keyspace customer")
+}
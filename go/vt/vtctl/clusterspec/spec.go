@@ -0,0 +1,131 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package clusterspec implements a declarative way of managing the topology of a
+cluster. A Spec describes the keyspaces, shards, vschemas and routing rules a
+cluster should have; ComputePlan diffs it against the live topology, through a
+vtctld, and returns the Plan of changes that would bring the topology in line
+with the spec. Applying the plan makes those changes.
+
+Plans only ever create and update: keyspaces and shards that are in the
+topology but not in the spec are left alone, as are the vschemas and routing
+rules that the spec does not mention.
+*/
+package clusterspec
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"vitess.io/vitess/go/json2"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/yaml2"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+// Spec is the desired state of the topology of a cluster.
+type Spec struct {
+	Keyspaces []*KeyspaceSpec `json:"keyspaces,omitempty"`
+	// RoutingRules are the routing rules of the cluster, in the same JSON
+	// format as ApplyRoutingRules. The routing rules are left as they are if
+	// this is not set.
+	RoutingRules json.RawMessage `json:"routing_rules,omitempty"`
+
+	routingRules *vschemapb.RoutingRules
+}
+
+// KeyspaceSpec is the desired state of a keyspace.
+type KeyspaceSpec struct {
+	Name string `json:"name"`
+	// DurabilityPolicy is the durability policy of the keyspace. The policy of
+	// an existing keyspace is left as it is if this is not set.
+	DurabilityPolicy string `json:"durability_policy,omitempty"`
+	// SidecarDBName is the name of the sidecar database of the keyspace. It
+	// can only be set when the keyspace gets created.
+	SidecarDBName string `json:"sidecar_db_name,omitempty"`
+	// Shards are the names of the shards of the keyspace, such as "0" or
+	// "-80".
+	Shards []string `json:"shards,omitempty"`
+	// VSchema is the vschema of the keyspace, in the same JSON format as
+	// ApplyVSchema. The vschema is left as it is if this is not set.
+	VSchema json.RawMessage `json:"vschema,omitempty"`
+
+	vschema *vschemapb.Keyspace
+}
+
+// Load reads and parses the spec in the given file.
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid spec %s: %w", path, err)
+	}
+
+	return spec, nil
+}
+
+// Parse parses and validates a spec, in either YAML or JSON.
+func Parse(data []byte) (*Spec, error) {
+	spec := &Spec{}
+	if err := yaml2.Unmarshal(data, spec); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(spec.Keyspaces))
+	for i, ks := range spec.Keyspaces {
+		if ks == nil || ks.Name == "" {
+			return nil, fmt.Errorf("keyspace %d has no name", i)
+		}
+		if names[ks.Name] {
+			return nil, fmt.Errorf("keyspace %s is listed more than once", ks.Name)
+		}
+		names[ks.Name] = true
+
+		shards := make(map[string]bool, len(ks.Shards))
+		for _, shard := range ks.Shards {
+			if _, _, err := topo.ValidateShardName(shard); err != nil {
+				return nil, fmt.Errorf("invalid shard %s/%s: %w", ks.Name, shard, err)
+			}
+			if shards[shard] {
+				return nil, fmt.Errorf("shard %s/%s is listed more than once", ks.Name, shard)
+			}
+			shards[shard] = true
+		}
+
+		if len(ks.VSchema) > 0 {
+			ks.vschema = &vschemapb.Keyspace{}
+			if err := json2.Unmarshal(ks.VSchema, ks.vschema); err != nil {
+				return nil, fmt.Errorf("invalid vschema for keyspace %s: %w", ks.Name, err)
+			}
+		}
+	}
+
+	if len(spec.RoutingRules) > 0 {
+		spec.routingRules = &vschemapb.RoutingRules{}
+		if err := json2.Unmarshal(spec.RoutingRules, spec.routingRules); err != nil {
+			return nil, fmt.Errorf("invalid routing rules: %w", err)
+		}
+	}
+
+	return spec, nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterspec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	spec, err := Parse([]byte(`
+keyspaces:
+  - name: commerce
+    durability_policy: semi_sync
+    shards: ["0"]
+    vschema:
+      tables:
+        product: {}
+routing_rules:
+  rules:
+    - from_table: product
+      to_tables: ["commerce.product"]
+`))
+	require.NoError(t, err)
+	require.Len(t, spec.Keyspaces, 1)
+	assert.Equal(t, "semi_sync", spec.Keyspaces[0].DurabilityPolicy)
+	assert.Equal(t, []string{"0"}, spec.Keyspaces[0].Shards)
+	assert.Contains(t, spec.Keyspaces[0].vschema.Tables, "product")
+	require.Len(t, spec.routingRules.Rules, 1)
+	assert.Equal(t, []string{"commerce.product"}, spec.routingRules.Rules[0].ToTables)
+
+	// JSON is valid YAML.
+	spec, err = Parse([]byte(`{"keyspaces": [{"name": "commerce"}]}`))
+	require.NoError(t, err)
+	assert.Nil(t, spec.Keyspaces[0].vschema)
+	assert.Nil(t, spec.routingRules)
+
+	tests := []struct {
+		name string
+		spec string
+		err  string
+	}{
+		{
+			name: "no name",
+			spec: "keyspaces: [{shards: ['0']}]",
+			err:  "keyspace 0 has no name",
+		},
+		{
+			name: "duplicate keyspace",
+			spec: "keyspaces: [{name: ks}, {name: ks}]",
+			err:  "keyspace ks is listed more than once",
+		},
+		{
+			name: "invalid shard",
+			spec: "keyspaces: [{name: ks, shards: ['80-40']}]",
+			err:  "invalid shard ks/80-40",
+		},
+		{
+			name: "duplicate shard",
+			spec: "keyspaces: [{name: ks, shards: ['0', '0']}]",
+			err:  "shard ks/0 is listed more than once",
+		},
+		{
+			name: "invalid vschema",
+			spec: "keyspaces: [{name: ks, vschema: {sharded: 'maybe'}}]",
+			err:  "invalid vschema for keyspace ks",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse([]byte(tt.spec))
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commandtracker
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// trackedServicePrefix selects the gRPC methods that get tracked: those of
+// both the Vtctl and the Vtctld services.
+const trackedServicePrefix = "/vtctlservice."
+
+// untrackedMethods are not tracked by the interceptors. The Vtctl methods run
+// legacy vtctl commands, which vtctl.RunCommand already tracks, one for each
+// command of a batch. Listing and canceling commands are not worth tracking.
+var untrackedMethods = map[string]bool{
+	"/vtctlservice.Vtctl/ExecuteVtctlCommand":      true,
+	"/vtctlservice.Vtctl/ExecuteVtctlCommandBatch": true,
+	"/vtctlservice.Vtctld/GetRunningCommands":      true,
+	"/vtctlservice.Vtctld/CancelCommand":           true,
+}
+
+func tracked(method string) bool {
+	return strings.HasPrefix(method, trackedServicePrefix) && !untrackedMethods[method]
+}
+
+// rpcArgs returns the arguments an RPC is listed with: the name of its
+// method, and its request as JSON, if any.
+func rpcArgs(method string, req any) []string {
+	args := []string{method[strings.LastIndex(method, "/")+1:]}
+
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return args
+	}
+
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	if err != nil || string(data) == "{}" {
+		return args
+	}
+
+	return append(args, string(data))
+}
+
+// UnaryServerInterceptor tracks the unary RPCs of the vtctl services, so they
+// can be listed and canceled.
+func (t *Tracker) UnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if !tracked(info.FullMethod) {
+		return handler(ctx, req)
+	}
+
+	ctx, done := t.Start(ctx, rpcArgs(info.FullMethod, req))
+	defer done()
+
+	return handler(ctx, req)
+}
+
+// StreamServerInterceptor tracks the streaming RPCs of the vtctl services, so
+// they can be listed and canceled. They are listed with the first message
+// received from the client as their request.
+func (t *Tracker) StreamServerInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if !tracked(info.FullMethod) {
+		return handler(srv, stream)
+	}
+
+	ctx, id, done := t.start(stream.Context(), rpcArgs(info.FullMethod, nil))
+	defer done()
+
+	return handler(srv, &trackedStream{
+		ServerStream: stream,
+		ctx:          ctx,
+		tracker:      t,
+		id:           id,
+		method:       info.FullMethod,
+	})
+}
+
+// trackedStream is a grpc.ServerStream whose context is the one of its
+// tracked command, and which records the first message it receives as the
+// request of the command.
+type trackedStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	tracker *Tracker
+	id      int64
+	method  string
+	recv    bool
+}
+
+func (s *trackedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *trackedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	if !s.recv {
+		s.recv = true
+		s.tracker.setArgs(s.id, rpcArgs(s.method, m))
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commandtracker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	tracker := New()
+	req := &vtctldatapb.GetKeyspaceRequest{Keyspace: "ks"}
+
+	_, err := tracker.UnaryServerInterceptor(context.Background(), req, &grpc.UnaryServerInfo{FullMethod: "/vtctlservice.Vtctld/GetKeyspace"}, func(ctx context.Context, req any) (any, error) {
+		commands := tracker.List()
+		require.Len(t, commands, 1)
+		assert.Equal(t, []string{"GetKeyspace", `{"keyspace":"ks"}`}, commands[0].Args)
+
+		require.NoError(t, tracker.Cancel(commands[0].Id))
+		return nil, ctx.Err()
+	})
+	assert.ErrorIs(t, err, context.Canceled, "canceling the RPC should cancel the context of its handler")
+	assert.Empty(t, tracker.List())
+
+	for _, method := range []string{"/vtctlservice.Vtctl/ExecuteVtctlCommand", "/vtctlservice.Vtctld/GetRunningCommands", "/queryservice.Query/Execute"} {
+		_, err := tracker.UnaryServerInterceptor(context.Background(), req, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req any) (any, error) {
+			assert.Empty(t, tracker.List(), "%s should not be tracked", method)
+			return nil, nil
+		})
+		assert.NoError(t, err)
+	}
+}
+
+// fakeServerStream receives a single request.
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	req proto.Message
+}
+
+func (s *fakeServerStream) Context() context.Context { return s.ctx }
+
+func (s *fakeServerStream) RecvMsg(m any) error {
+	proto.Merge(m.(proto.Message), s.req)
+	return nil
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	tracker := New()
+	stream := &fakeServerStream{
+		ctx: context.Background(),
+		req: &vtctldatapb.ValidateSchemaKeyspaceRequest{Keyspace: "ks"},
+	}
+
+	err := tracker.StreamServerInterceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: "/vtctlservice.Vtctld/ValidateSchemaKeyspaceStream"}, func(srv any, stream grpc.ServerStream) error {
+		commands := tracker.List()
+		require.Len(t, commands, 1)
+		assert.Equal(t, []string{"ValidateSchemaKeyspaceStream"}, commands[0].Args)
+
+		req := &vtctldatapb.ValidateSchemaKeyspaceRequest{}
+		require.NoError(t, stream.RecvMsg(req))
+		assert.Equal(t, []string{"ValidateSchemaKeyspaceStream", `{"keyspace":"ks"}`}, tracker.List()[0].Args)
+
+		require.NoError(t, tracker.Cancel(commands[0].Id))
+		return stream.Context().Err()
+	})
+	assert.ErrorIs(t, err, context.Canceled, "canceling the RPC should cancel the context of its stream")
+	assert.Empty(t, tracker.List())
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package commandtracker keeps track of the vtctl commands running in a process,
+so they can be listed and canceled by the GetRunningCommands and CancelCommand
+vtctld RPCs.
+
+Legacy vtctl commands are tracked by vtctl.RunCommand, whichever way they are
+run, and the RPCs of the vtctld gRPC services by the interceptors of the
+Tracker.
+*/
+package commandtracker
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// Tracker tracks running commands.
+type Tracker struct {
+	m        sync.Mutex
+	lastID   int64
+	commands map[int64]*trackedCommand
+}
+
+type trackedCommand struct {
+	command *vtctldatapb.RunningCommand
+	cancel  context.CancelFunc
+}
+
+// Default is the Tracker of the commands run with vtctl.RunCommand, and of
+// the RPCs of the vtctld.
+var Default = New()
+
+// New returns an empty Tracker.
+func New() *Tracker {
+	return &Tracker{
+		commands: map[int64]*trackedCommand{},
+	}
+}
+
+// Start registers a running command. The command must run with the returned
+// context, which gets canceled by Cancel, and call the returned function once
+// it is done.
+func (t *Tracker) Start(ctx context.Context, args []string) (context.Context, func()) {
+	ctx, _, done := t.start(ctx, args)
+	return ctx, done
+}
+
+// start is Start, but also returns the id of the command.
+func (t *Tracker) start(ctx context.Context, args []string) (context.Context, int64, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	t.lastID++
+	id := t.lastID
+	t.commands[id] = &trackedCommand{
+		command: &vtctldatapb.RunningCommand{
+			Id:        id,
+			Args:      args,
+			StartTime: protoutil.TimeToProto(time.Now()),
+		},
+		cancel: cancel,
+	}
+
+	return ctx, id, func() {
+		cancel()
+
+		t.m.Lock()
+		defer t.m.Unlock()
+		delete(t.commands, id)
+	}
+}
+
+// setArgs replaces the arguments of a running command.
+func (t *Tracker) setArgs(id int64, args []string) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if tc, ok := t.commands[id]; ok {
+		tc.command.Args = args
+	}
+}
+
+// List returns the running commands, oldest first.
+func (t *Tracker) List() []*vtctldatapb.RunningCommand {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	commands := make([]*vtctldatapb.RunningCommand, 0, len(t.commands))
+	for _, tc := range t.commands {
+		commands = append(commands, proto.Clone(tc.command).(*vtctldatapb.RunningCommand))
+	}
+
+	sort.Slice(commands, func(i, j int) bool {
+		return commands[i].Id < commands[j].Id
+	})
+
+	return commands
+}
+
+// Cancel cancels the context of the running command with the given id. It
+// returns a NOT_FOUND error if there is no such command.
+func (t *Tracker) Cancel(id int64) error {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	tc, ok := t.commands[id]
+	if !ok {
+		return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "no running command with id %d", id)
+	}
+
+	tc.cancel()
+	return nil
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commandtracker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+func TestTracker(t *testing.T) {
+	tracker := New()
+
+	ctx1, done1 := tracker.Start(context.Background(), []string{"CopySchemaShard", "ks/0", "ks/1"})
+	ctx2, done2 := tracker.Start(context.Background(), []string{"ListAllTablets"})
+
+	commands := tracker.List()
+	require.Len(t, commands, 2)
+	assert.Equal(t, []string{"CopySchemaShard", "ks/0", "ks/1"}, commands[0].Args)
+	assert.Equal(t, []string{"ListAllTablets"}, commands[1].Args)
+	assert.NotNil(t, commands[0].StartTime)
+
+	require.NoError(t, tracker.Cancel(commands[0].Id))
+	assert.ErrorIs(t, ctx1.Err(), context.Canceled)
+	assert.NoError(t, ctx2.Err())
+
+	// A canceled command is still listed until it returns.
+	assert.Len(t, tracker.List(), 2)
+	done1()
+	done2()
+	assert.Empty(t, tracker.List())
+
+	err := tracker.Cancel(commands[1].Id)
+	assert.Equal(t, vtrpcpb.Code_NOT_FOUND, vterrors.Code(err))
+}
@@ -24,8 +24,10 @@ import (
 	"time"
 
 	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/vtctl/vtctlclient"
 
 	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
 // FakeLoggerEventStreamingClient is the base for the fakes for vtctlclient.
@@ -151,3 +153,61 @@ func (f *FakeLoggerEventStreamingClient) StreamResult(addr string, args []string
 		err:   result.err,
 	}, nil
 }
+
+type batchStreamResultAdapter struct {
+	responses []*vtctldatapb.ExecuteVtctlCommandBatchResponse
+	index     int
+}
+
+func (s *batchStreamResultAdapter) Recv() (*vtctldatapb.ExecuteVtctlCommandBatchResponse, error) {
+	if s.index < len(s.responses) {
+		resp := s.responses[s.index]
+		s.index++
+		return resp, nil
+	}
+	return nil, io.EOF
+}
+
+// StreamBatchResult returns a stream which streams back the registered results
+// of "commands", in order, as batch responses. Like a sequential batch on a
+// real server, it stops after the first failing command unless
+// "continueOnError" is set.
+func (f *FakeLoggerEventStreamingClient) StreamBatchResult(addr string, commands [][]string, continueOnError bool) (vtctlclient.BatchEventStream, error) {
+	var responses []*vtctldatapb.ExecuteVtctlCommandBatchResponse
+	for i, args := range commands {
+		stream, err := f.StreamResult(addr, args)
+		if err != nil {
+			return nil, err
+		}
+
+		var cmdErr error
+		for {
+			e, err := stream.Recv()
+			if err != nil {
+				if err != io.EOF {
+					cmdErr = err
+				}
+				break
+			}
+			responses = append(responses, &vtctldatapb.ExecuteVtctlCommandBatchResponse{
+				CommandIndex: uint32(i),
+				Event:        e,
+			})
+		}
+
+		done := &vtctldatapb.ExecuteVtctlCommandBatchResponse{
+			CommandIndex: uint32(i),
+			Done:         true,
+		}
+		if cmdErr != nil {
+			done.Error = cmdErr.Error()
+		}
+		responses = append(responses, done)
+
+		if cmdErr != nil && !continueOnError {
+			break
+		}
+	}
+
+	return &batchStreamResultAdapter{responses: responses}, nil
+}
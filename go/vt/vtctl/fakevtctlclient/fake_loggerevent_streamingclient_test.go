@@ -18,6 +18,7 @@ package fakevtctlclient
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"reflect"
 	"strings"
@@ -183,3 +184,64 @@ func verifyListOfRegisteredCommands(t *testing.T, fake *FakeLoggerEventStreaming
 		t.Fatalf("fake.RegisteredCommands() = %v, want: %v", got, want)
 	}
 }
+
+func TestStreamBatchResult(t *testing.T) {
+	fake := NewFakeLoggerEventStreamingClient()
+	commands := [][]string{
+		{"ListShardTablets", "test_keyspace/0"},
+		{"ListShardTablets", "test_keyspace/1"},
+		{"ListShardTablets", "test_keyspace/2"},
+	}
+	register := func() {
+		if err := fake.RegisterResult(commands[0], "event1\nevent2", nil); err != nil {
+			t.Fatalf("Failed to register fake result for: %v err: %v", commands[0], err)
+		}
+		if err := fake.RegisterResult(commands[1], "event3", errors.New("something went wrong")); err != nil {
+			t.Fatalf("Failed to register fake result for: %v err: %v", commands[1], err)
+		}
+		if err := fake.RegisterResult(commands[2], "event4", nil); err != nil {
+			t.Fatalf("Failed to register fake result for: %v err: %v", commands[2], err)
+		}
+	}
+
+	// Without continueOnError, the batch stops at the second command and the
+	// result of the third one stays registered.
+	register()
+	verifyBatchResult(t, fake, commands, false, []string{"0:event1", "0:event2", "0:done", "1:event3", "1:error:something went wrong"})
+	verifyListOfRegisteredCommands(t, fake, []string{strings.Join(commands[2], " ")})
+
+	// With continueOnError, all the commands run.
+	fake = NewFakeLoggerEventStreamingClient()
+	register()
+	verifyBatchResult(t, fake, commands, true, []string{"0:event1", "0:event2", "0:done", "1:event3", "1:error:something went wrong", "2:event4", "2:done"})
+	verifyListOfRegisteredCommands(t, fake, []string{})
+}
+
+func verifyBatchResult(t *testing.T, fake *FakeLoggerEventStreamingClient, commands [][]string, continueOnError bool, want []string) {
+	stream, err := fake.StreamBatchResult("" /* addr */, commands, continueOnError)
+	if err != nil {
+		t.Fatalf("Failed to stream batch result: %v", err)
+	}
+
+	var got []string
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to receive batch response: %v", err)
+		}
+		switch {
+		case resp.Done && resp.Error != "":
+			got = append(got, fmt.Sprintf("%v:error:%v", resp.CommandIndex, resp.Error))
+		case resp.Done:
+			got = append(got, fmt.Sprintf("%v:done", resp.CommandIndex))
+		default:
+			got = append(got, fmt.Sprintf("%v:%v", resp.CommandIndex, resp.Event.Value))
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Wrong batch responses. got: %v want: %v", got, want)
+	}
+}
@@ -48,5 +48,11 @@ func (f *FakeVtctlClient) ExecuteVtctlCommand(ctx context.Context, args []string
 	return f.FakeLoggerEventStreamingClient.StreamResult("" /* addr */, args)
 }
 
+// ExecuteVtctlCommandBatch is part of the vtctlclient interface. The fake
+// always runs the commands sequentially.
+func (f *FakeVtctlClient) ExecuteVtctlCommandBatch(ctx context.Context, commands [][]string, opts vtctlclient.BatchOptions) (vtctlclient.BatchEventStream, error) {
+	return f.FakeLoggerEventStreamingClient.StreamBatchResult("" /* addr */, commands, opts.ContinueOnError)
+}
+
 // Close is part of the vtctlclient interface.
 func (f *FakeVtctlClient) Close() {}
@@ -83,6 +83,21 @@ func (client *gRPCVtctlClient) ExecuteVtctlCommand(ctx context.Context, args []s
 	return &eventStreamAdapter{stream}, nil
 }
 
+// ExecuteVtctlCommandBatch is part of the VtctlClient interface
+func (client *gRPCVtctlClient) ExecuteVtctlCommandBatch(ctx context.Context, commands [][]string, opts vtctlclient.BatchOptions) (vtctlclient.BatchEventStream, error) {
+	query := &vtctldatapb.ExecuteVtctlCommandBatchRequest{
+		Commands:        make([]*vtctldatapb.ExecuteVtctlCommandBatchRequest_Command, len(commands)),
+		ActionTimeout:   int64(opts.ActionTimeout.Nanoseconds()),
+		Parallel:        opts.Parallel,
+		ContinueOnError: opts.ContinueOnError,
+	}
+	for i, args := range commands {
+		query.Commands[i] = &vtctldatapb.ExecuteVtctlCommandBatchRequest_Command{Args: args}
+	}
+
+	return client.c.ExecuteVtctlCommandBatch(ctx, query)
+}
+
 // Close is part of the VtctlClient interface
 func (client *gRPCVtctlClient) Close() {
 	client.cc.Close()
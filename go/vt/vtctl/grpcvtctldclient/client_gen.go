@@ -29,6 +29,15 @@ import (
 	vtctlservicepb "vitess.io/vitess/go/vt/proto/vtctlservice"
 )
 
+// AcquireNamedLock is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) AcquireNamedLock(ctx context.Context, in *vtctldatapb.AcquireNamedLockRequest, opts ...grpc.CallOption) (*vtctldatapb.AcquireNamedLockResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.AcquireNamedLock(ctx, in, opts...)
+}
+
 // AddCellInfo is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) AddCellInfo(ctx context.Context, in *vtctldatapb.AddCellInfoRequest, opts ...grpc.CallOption) (*vtctldatapb.AddCellInfoResponse, error) {
 	if client.c == nil {
@@ -47,6 +56,15 @@ func (client *gRPCVtctldClient) AddCellsAlias(ctx context.Context, in *vtctldata
 	return client.c.AddCellsAlias(ctx, in, opts...)
 }
 
+// AddTabletTag is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) AddTabletTag(ctx context.Context, in *vtctldatapb.AddTabletTagRequest, opts ...grpc.CallOption) (*vtctldatapb.AddTabletTagResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.AddTabletTag(ctx, in, opts...)
+}
+
 // ApplyRoutingRules is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) ApplyRoutingRules(ctx context.Context, in *vtctldatapb.ApplyRoutingRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyRoutingRulesResponse, error) {
 	if client.c == nil {
@@ -101,6 +119,24 @@ func (client *gRPCVtctldClient) BackupShard(ctx context.Context, in *vtctldatapb
 	return client.c.BackupShard(ctx, in, opts...)
 }
 
+// CancelCommand is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) CancelCommand(ctx context.Context, in *vtctldatapb.CancelCommandRequest, opts ...grpc.CallOption) (*vtctldatapb.CancelCommandResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.CancelCommand(ctx, in, opts...)
+}
+
+// CancelScheduledCommand is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) CancelScheduledCommand(ctx context.Context, in *vtctldatapb.CancelScheduledCommandRequest, opts ...grpc.CallOption) (*vtctldatapb.CancelScheduledCommandResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.CancelScheduledCommand(ctx, in, opts...)
+}
+
 // CancelSchemaMigration is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) CancelSchemaMigration(ctx context.Context, in *vtctldatapb.CancelSchemaMigrationRequest, opts ...grpc.CallOption) (*vtctldatapb.CancelSchemaMigrationResponse, error) {
 	if client.c == nil {
@@ -119,6 +155,15 @@ func (client *gRPCVtctldClient) ChangeTabletType(ctx context.Context, in *vtctld
 	return client.c.ChangeTabletType(ctx, in, opts...)
 }
 
+// ChangeTabletTypeByFilter is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ChangeTabletTypeByFilter(ctx context.Context, in *vtctldatapb.ChangeTabletTypeByFilterRequest, opts ...grpc.CallOption) (*vtctldatapb.ChangeTabletTypeByFilterResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ChangeTabletTypeByFilter(ctx, in, opts...)
+}
+
 // CleanupSchemaMigration is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) CleanupSchemaMigration(ctx context.Context, in *vtctldatapb.CleanupSchemaMigrationRequest, opts ...grpc.CallOption) (*vtctldatapb.CleanupSchemaMigrationResponse, error) {
 	if client.c == nil {
@@ -128,6 +173,15 @@ func (client *gRPCVtctldClient) CleanupSchemaMigration(ctx context.Context, in *
 	return client.c.CleanupSchemaMigration(ctx, in, opts...)
 }
 
+// ConcludeTransaction is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ConcludeTransaction(ctx context.Context, in *vtctldatapb.ConcludeTransactionRequest, opts ...grpc.CallOption) (*vtctldatapb.ConcludeTransactionResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ConcludeTransaction(ctx, in, opts...)
+}
+
 // CreateKeyspace is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) CreateKeyspace(ctx context.Context, in *vtctldatapb.CreateKeyspaceRequest, opts ...grpc.CallOption) (*vtctldatapb.CreateKeyspaceResponse, error) {
 	if client.c == nil {
@@ -245,6 +299,15 @@ func (client *gRPCVtctldClient) FindAllShardsInKeyspace(ctx context.Context, in
 	return client.c.FindAllShardsInKeyspace(ctx, in, opts...)
 }
 
+// FixErrantGTID is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) FixErrantGTID(ctx context.Context, in *vtctldatapb.FixErrantGTIDRequest, opts ...grpc.CallOption) (*vtctldatapb.FixErrantGTIDResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.FixErrantGTID(ctx, in, opts...)
+}
+
 // GetBackups is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) GetBackups(ctx context.Context, in *vtctldatapb.GetBackupsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetBackupsResponse, error) {
 	if client.c == nil {
@@ -308,6 +371,15 @@ func (client *gRPCVtctldClient) GetKeyspaces(ctx context.Context, in *vtctldatap
 	return client.c.GetKeyspaces(ctx, in, opts...)
 }
 
+// GetMaintenanceWindows is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) GetMaintenanceWindows(ctx context.Context, in *vtctldatapb.GetMaintenanceWindowsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetMaintenanceWindowsResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.GetMaintenanceWindows(ctx, in, opts...)
+}
+
 // GetPermissions is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) GetPermissions(ctx context.Context, in *vtctldatapb.GetPermissionsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetPermissionsResponse, error) {
 	if client.c == nil {
@@ -326,6 +398,24 @@ func (client *gRPCVtctldClient) GetRoutingRules(ctx context.Context, in *vtctlda
 	return client.c.GetRoutingRules(ctx, in, opts...)
 }
 
+// GetRunningCommands is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) GetRunningCommands(ctx context.Context, in *vtctldatapb.GetRunningCommandsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetRunningCommandsResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.GetRunningCommands(ctx, in, opts...)
+}
+
+// GetScheduledCommands is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) GetScheduledCommands(ctx context.Context, in *vtctldatapb.GetScheduledCommandsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetScheduledCommandsResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.GetScheduledCommands(ctx, in, opts...)
+}
+
 // GetSchema is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) GetSchema(ctx context.Context, in *vtctldatapb.GetSchemaRequest, opts ...grpc.CallOption) (*vtctldatapb.GetSchemaResponse, error) {
 	if client.c == nil {
@@ -407,6 +497,15 @@ func (client *gRPCVtctldClient) GetTablet(ctx context.Context, in *vtctldatapb.G
 	return client.c.GetTablet(ctx, in, opts...)
 }
 
+// GetTabletPlanCache is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) GetTabletPlanCache(ctx context.Context, in *vtctldatapb.GetTabletPlanCacheRequest, opts ...grpc.CallOption) (*vtctldatapb.GetTabletPlanCacheResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.GetTabletPlanCache(ctx, in, opts...)
+}
+
 // GetTablets is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) GetTablets(ctx context.Context, in *vtctldatapb.GetTabletsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetTabletsResponse, error) {
 	if client.c == nil {
@@ -416,6 +515,15 @@ func (client *gRPCVtctldClient) GetTablets(ctx context.Context, in *vtctldatapb.
 	return client.c.GetTablets(ctx, in, opts...)
 }
 
+// GetTabletsStream is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) GetTabletsStream(ctx context.Context, in *vtctldatapb.GetTabletsRequest, opts ...grpc.CallOption) (vtctlservicepb.Vtctld_GetTabletsStreamClient, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.GetTabletsStream(ctx, in, opts...)
+}
+
 // GetTopologyPath is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) GetTopologyPath(ctx context.Context, in *vtctldatapb.GetTopologyPathRequest, opts ...grpc.CallOption) (*vtctldatapb.GetTopologyPathResponse, error) {
 	if client.c == nil {
@@ -425,6 +533,15 @@ func (client *gRPCVtctldClient) GetTopologyPath(ctx context.Context, in *vtctlda
 	return client.c.GetTopologyPath(ctx, in, opts...)
 }
 
+// GetUnresolvedTransactions is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) GetUnresolvedTransactions(ctx context.Context, in *vtctldatapb.GetUnresolvedTransactionsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetUnresolvedTransactionsResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.GetUnresolvedTransactions(ctx, in, opts...)
+}
+
 // GetVSchema is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) GetVSchema(ctx context.Context, in *vtctldatapb.GetVSchemaRequest, opts ...grpc.CallOption) (*vtctldatapb.GetVSchemaResponse, error) {
 	if client.c == nil {
@@ -461,6 +578,15 @@ func (client *gRPCVtctldClient) InitShardPrimary(ctx context.Context, in *vtctld
 	return client.c.InitShardPrimary(ctx, in, opts...)
 }
 
+// InvalidateTabletPlanCache is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) InvalidateTabletPlanCache(ctx context.Context, in *vtctldatapb.InvalidateTabletPlanCacheRequest, opts ...grpc.CallOption) (*vtctldatapb.InvalidateTabletPlanCacheResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.InvalidateTabletPlanCache(ctx, in, opts...)
+}
+
 // MoveTablesComplete is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) MoveTablesComplete(ctx context.Context, in *vtctldatapb.MoveTablesCompleteRequest, opts ...grpc.CallOption) (*vtctldatapb.MoveTablesCompleteResponse, error) {
 	if client.c == nil {
@@ -497,6 +623,15 @@ func (client *gRPCVtctldClient) PlannedReparentShard(ctx context.Context, in *vt
 	return client.c.PlannedReparentShard(ctx, in, opts...)
 }
 
+// PurgeBackups is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) PurgeBackups(ctx context.Context, in *vtctldatapb.PurgeBackupsRequest, opts ...grpc.CallOption) (*vtctldatapb.PurgeBackupsResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.PurgeBackups(ctx, in, opts...)
+}
+
 // RebuildKeyspaceGraph is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) RebuildKeyspaceGraph(ctx context.Context, in *vtctldatapb.RebuildKeyspaceGraphRequest, opts ...grpc.CallOption) (*vtctldatapb.RebuildKeyspaceGraphResponse, error) {
 	if client.c == nil {
@@ -524,6 +659,15 @@ func (client *gRPCVtctldClient) RefreshState(ctx context.Context, in *vtctldatap
 	return client.c.RefreshState(ctx, in, opts...)
 }
 
+// RefreshStateByFilter is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) RefreshStateByFilter(ctx context.Context, in *vtctldatapb.RefreshStateByFilterRequest, opts ...grpc.CallOption) (*vtctldatapb.RefreshStateByFilterResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.RefreshStateByFilter(ctx, in, opts...)
+}
+
 // RefreshStateByShard is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) RefreshStateByShard(ctx context.Context, in *vtctldatapb.RefreshStateByShardRequest, opts ...grpc.CallOption) (*vtctldatapb.RefreshStateByShardResponse, error) {
 	if client.c == nil {
@@ -533,6 +677,15 @@ func (client *gRPCVtctldClient) RefreshStateByShard(ctx context.Context, in *vtc
 	return client.c.RefreshStateByShard(ctx, in, opts...)
 }
 
+// ReleaseNamedLock is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ReleaseNamedLock(ctx context.Context, in *vtctldatapb.ReleaseNamedLockRequest, opts ...grpc.CallOption) (*vtctldatapb.ReleaseNamedLockResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ReleaseNamedLock(ctx, in, opts...)
+}
+
 // ReloadSchema is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) ReloadSchema(ctx context.Context, in *vtctldatapb.ReloadSchemaRequest, opts ...grpc.CallOption) (*vtctldatapb.ReloadSchemaResponse, error) {
 	if client.c == nil {
@@ -587,6 +740,33 @@ func (client *gRPCVtctldClient) RemoveShardCell(ctx context.Context, in *vtctlda
 	return client.c.RemoveShardCell(ctx, in, opts...)
 }
 
+// RemoveTabletTag is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) RemoveTabletTag(ctx context.Context, in *vtctldatapb.RemoveTabletTagRequest, opts ...grpc.CallOption) (*vtctldatapb.RemoveTabletTagResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.RemoveTabletTag(ctx, in, opts...)
+}
+
+// RenewNamedLock is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) RenewNamedLock(ctx context.Context, in *vtctldatapb.RenewNamedLockRequest, opts ...grpc.CallOption) (*vtctldatapb.RenewNamedLockResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.RenewNamedLock(ctx, in, opts...)
+}
+
+// ReparentPreflight is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ReparentPreflight(ctx context.Context, in *vtctldatapb.ReparentPreflightRequest, opts ...grpc.CallOption) (*vtctldatapb.ReparentPreflightResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ReparentPreflight(ctx, in, opts...)
+}
+
 // ReparentTablet is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) ReparentTablet(ctx context.Context, in *vtctldatapb.ReparentTabletRequest, opts ...grpc.CallOption) (*vtctldatapb.ReparentTabletResponse, error) {
 	if client.c == nil {
@@ -623,6 +803,15 @@ func (client *gRPCVtctldClient) RunHealthCheck(ctx context.Context, in *vtctldat
 	return client.c.RunHealthCheck(ctx, in, opts...)
 }
 
+// ScheduleCommand is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ScheduleCommand(ctx context.Context, in *vtctldatapb.ScheduleCommandRequest, opts ...grpc.CallOption) (*vtctldatapb.ScheduleCommandResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ScheduleCommand(ctx, in, opts...)
+}
+
 // SetKeyspaceDurabilityPolicy is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) SetKeyspaceDurabilityPolicy(ctx context.Context, in *vtctldatapb.SetKeyspaceDurabilityPolicyRequest, opts ...grpc.CallOption) (*vtctldatapb.SetKeyspaceDurabilityPolicyResponse, error) {
 	if client.c == nil {
@@ -722,6 +911,15 @@ func (client *gRPCVtctldClient) SourceShardDelete(ctx context.Context, in *vtctl
 	return client.c.SourceShardDelete(ctx, in, opts...)
 }
 
+// StartMaintenance is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) StartMaintenance(ctx context.Context, in *vtctldatapb.StartMaintenanceRequest, opts ...grpc.CallOption) (*vtctldatapb.StartMaintenanceResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.StartMaintenance(ctx, in, opts...)
+}
+
 // StartReplication is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) StartReplication(ctx context.Context, in *vtctldatapb.StartReplicationRequest, opts ...grpc.CallOption) (*vtctldatapb.StartReplicationResponse, error) {
 	if client.c == nil {
@@ -731,6 +929,15 @@ func (client *gRPCVtctldClient) StartReplication(ctx context.Context, in *vtctld
 	return client.c.StartReplication(ctx, in, opts...)
 }
 
+// StopMaintenance is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) StopMaintenance(ctx context.Context, in *vtctldatapb.StopMaintenanceRequest, opts ...grpc.CallOption) (*vtctldatapb.StopMaintenanceResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.StopMaintenance(ctx, in, opts...)
+}
+
 // StopReplication is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) StopReplication(ctx context.Context, in *vtctldatapb.StopReplicationRequest, opts ...grpc.CallOption) (*vtctldatapb.StopReplicationResponse, error) {
 	if client.c == nil {
@@ -767,6 +974,15 @@ func (client *gRPCVtctldClient) UpdateCellsAlias(ctx context.Context, in *vtctld
 	return client.c.UpdateCellsAlias(ctx, in, opts...)
 }
 
+// UpdateOnlineDDLSchedulerConfig is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) UpdateOnlineDDLSchedulerConfig(ctx context.Context, in *vtctldatapb.UpdateOnlineDDLSchedulerConfigRequest, opts ...grpc.CallOption) (*vtctldatapb.UpdateOnlineDDLSchedulerConfigResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.UpdateOnlineDDLSchedulerConfig(ctx, in, opts...)
+}
+
 // UpdateThrottlerConfig is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) UpdateThrottlerConfig(ctx context.Context, in *vtctldatapb.UpdateThrottlerConfigRequest, opts ...grpc.CallOption) (*vtctldatapb.UpdateThrottlerConfigResponse, error) {
 	if client.c == nil {
@@ -776,6 +992,15 @@ func (client *gRPCVtctldClient) UpdateThrottlerConfig(ctx context.Context, in *v
 	return client.c.UpdateThrottlerConfig(ctx, in, opts...)
 }
 
+// UpgradeMysql is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) UpgradeMysql(ctx context.Context, in *vtctldatapb.UpgradeMysqlRequest, opts ...grpc.CallOption) (vtctlservicepb.Vtctld_UpgradeMysqlClient, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.UpgradeMysql(ctx, in, opts...)
+}
+
 // Validate is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) Validate(ctx context.Context, in *vtctldatapb.ValidateRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidateResponse, error) {
 	if client.c == nil {
@@ -785,6 +1010,15 @@ func (client *gRPCVtctldClient) Validate(ctx context.Context, in *vtctldatapb.Va
 	return client.c.Validate(ctx, in, opts...)
 }
 
+// ValidateBackup is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ValidateBackup(ctx context.Context, in *vtctldatapb.ValidateBackupRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidateBackupResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ValidateBackup(ctx, in, opts...)
+}
+
 // ValidateKeyspace is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) ValidateKeyspace(ctx context.Context, in *vtctldatapb.ValidateKeyspaceRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidateKeyspaceResponse, error) {
 	if client.c == nil {
@@ -794,6 +1028,15 @@ func (client *gRPCVtctldClient) ValidateKeyspace(ctx context.Context, in *vtctld
 	return client.c.ValidateKeyspace(ctx, in, opts...)
 }
 
+// ValidatePermissionsKeyspace is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ValidatePermissionsKeyspace(ctx context.Context, in *vtctldatapb.ValidatePermissionsKeyspaceRequest, opts ...grpc.CallOption) (vtctlservicepb.Vtctld_ValidatePermissionsKeyspaceClient, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ValidatePermissionsKeyspace(ctx, in, opts...)
+}
+
 // ValidateSchemaKeyspace is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) ValidateSchemaKeyspace(ctx context.Context, in *vtctldatapb.ValidateSchemaKeyspaceRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidateSchemaKeyspaceResponse, error) {
 	if client.c == nil {
@@ -803,6 +1046,15 @@ func (client *gRPCVtctldClient) ValidateSchemaKeyspace(ctx context.Context, in *
 	return client.c.ValidateSchemaKeyspace(ctx, in, opts...)
 }
 
+// ValidateSchemaKeyspaceStream is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ValidateSchemaKeyspaceStream(ctx context.Context, in *vtctldatapb.ValidateSchemaKeyspaceRequest, opts ...grpc.CallOption) (vtctlservicepb.Vtctld_ValidateSchemaKeyspaceStreamClient, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ValidateSchemaKeyspaceStream(ctx, in, opts...)
+}
+
 // ValidateShard is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) ValidateShard(ctx context.Context, in *vtctldatapb.ValidateShardRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidateShardResponse, error) {
 	if client.c == nil {
@@ -839,6 +1091,33 @@ func (client *gRPCVtctldClient) ValidateVersionShard(ctx context.Context, in *vt
 	return client.c.ValidateVersionShard(ctx, in, opts...)
 }
 
+// WatchKeyspace is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) WatchKeyspace(ctx context.Context, in *vtctldatapb.WatchKeyspaceRequest, opts ...grpc.CallOption) (vtctlservicepb.Vtctld_WatchKeyspaceClient, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.WatchKeyspace(ctx, in, opts...)
+}
+
+// WatchShard is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) WatchShard(ctx context.Context, in *vtctldatapb.WatchShardRequest, opts ...grpc.CallOption) (vtctlservicepb.Vtctld_WatchShardClient, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.WatchShard(ctx, in, opts...)
+}
+
+// WatchSrvVSchema is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) WatchSrvVSchema(ctx context.Context, in *vtctldatapb.WatchSrvVSchemaRequest, opts ...grpc.CallOption) (vtctlservicepb.Vtctld_WatchSrvVSchemaClient, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.WatchSrvVSchema(ctx, in, opts...)
+}
+
 // WorkflowDelete is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) WorkflowDelete(ctx context.Context, in *vtctldatapb.WorkflowDeleteRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowDeleteResponse, error) {
 	if client.c == nil {
@@ -177,6 +177,9 @@ func rowToSchemaMigration(row sqltypes.RowNamedValues) (sm *vtctldatapb.SchemaMi
 		return nil, err
 	}
 
+	sm.RowsCopiedPerSecond = float32(row.AsFloat64("rows_copied_per_second", 0))
+	sm.VreplicationLagSeconds = row.AsInt64("vreplication_lag_seconds", 0)
+
 	return sm, nil
 }
 
@@ -18,6 +18,7 @@ package grpcvtctldserver
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -29,11 +30,13 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/sync/semaphore"
 	"google.golang.org/grpc"
 	"google.golang.org/protobuf/proto"
 
 	"vitess.io/vitess/go/event"
+	"vitess.io/vitess/go/mysql/replication"
 	"vitess.io/vitess/go/netutil"
 	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/sets"
@@ -42,6 +45,7 @@ import (
 	"vitess.io/vitess/go/trace"
 	"vitess.io/vitess/go/vt/callerid"
 	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/dtids"
 	hk "vitess.io/vitess/go/vt/hook"
 	"vitess.io/vitess/go/vt/key"
 	"vitess.io/vitess/go/vt/log"
@@ -57,9 +61,12 @@ import (
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/topotools"
 	"vitess.io/vitess/go/vt/topotools/events"
+	"vitess.io/vitess/go/vt/vtctl/commandtracker"
 	"vitess.io/vitess/go/vt/vtctl/reparentutil"
 	"vitess.io/vitess/go/vt/vtctl/schematools"
 	"vitess.io/vitess/go/vt/vtctl/workflow"
+	"vitess.io/vitess/go/vt/vtctld/audit"
+	"vitess.io/vitess/go/vt/vtctld/retention"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
@@ -91,9 +98,11 @@ type VtctldServer struct {
 	ws  *workflow.Server
 }
 
-// NewVtctldServer returns a new VtctldServer for the given topo server.
+// NewVtctldServer returns a new VtctldServer for the given topo server. Its
+// tablet manager RPCs that fan out to many tablets are retried according to
+// the --tablet-rpc-retry-* flags.
 func NewVtctldServer(ts *topo.Server) *VtctldServer {
-	tmc := tmclient.NewTabletManagerClient()
+	tmc := tmclient.NewRetryingClient(tmclient.NewTabletManagerClient(), tmclient.RetryPolicyFromFlags())
 
 	return &VtctldServer{
 		ts:  ts,
@@ -118,6 +127,47 @@ func panicHandler(err *error) {
 	}
 }
 
+// AcquireNamedLock is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) AcquireNamedLock(ctx context.Context, req *vtctldatapb.AcquireNamedLockRequest) (resp *vtctldatapb.AcquireNamedLockResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.AcquireNamedLock")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("name", req.Name)
+	span.Annotate("owner", req.Owner)
+
+	ttl, _, err := protoutil.DurationFromProto(req.Ttl)
+	if err != nil {
+		err = vterrors.Wrapf(err, "unable to parse Ttl into a valid duration")
+		return nil, err
+	}
+	waitTimeout, _, err := protoutil.DurationFromProto(req.WaitTimeout)
+	if err != nil {
+		err = vterrors.Wrapf(err, "unable to parse WaitTimeout into a valid duration")
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, waitTimeout+topo.RemoteOperationTimeout)
+	defer cancel()
+
+	lock, err := s.ts.AcquireNamedLock(ctx, req.Name, req.Owner, ttl, waitTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.AcquireNamedLockResponse{Lock: namedLockToProto(lock)}, nil
+}
+
+func namedLockToProto(lock *topo.NamedLock) *vtctldatapb.NamedLock {
+	return &vtctldatapb.NamedLock{
+		Name:       lock.Name,
+		Owner:      lock.Owner,
+		AcquiredAt: protoutil.TimeToProto(lock.Acquired),
+		ExpiresAt:  protoutil.TimeToProto(lock.Expires),
+	}
+}
+
 // AddCellInfo is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) AddCellInfo(ctx context.Context, req *vtctldatapb.AddCellInfoRequest) (resp *vtctldatapb.AddCellInfoResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.AddCellInfo")
@@ -133,11 +183,17 @@ func (s *VtctldServer) AddCellInfo(ctx context.Context, req *vtctldatapb.AddCell
 	span.Annotate("cell", req.Name)
 	span.Annotate("cell_root", req.CellInfo.Root)
 	span.Annotate("cell_address", req.CellInfo.ServerAddress)
+	span.Annotate("if_not_exists", req.IfNotExists)
 
 	ctx, cancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
 	defer cancel()
 
 	if err = s.ts.CreateCellInfo(ctx, req.Name, req.CellInfo); err != nil {
+		if req.IfNotExists && topo.IsErrType(err, topo.NodeExists) {
+			log.Infof("cell %v already exists; leaving it unchanged because IfNotExists = true", req.Name)
+			return &vtctldatapb.AddCellInfoResponse{AlreadyExists: true}, nil
+		}
+
 		return nil, err
 	}
 
@@ -164,6 +220,48 @@ func (s *VtctldServer) AddCellsAlias(ctx context.Context, req *vtctldatapb.AddCe
 	return &vtctldatapb.AddCellsAliasResponse{}, nil
 }
 
+// AddTabletTag is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) AddTabletTag(ctx context.Context, req *vtctldatapb.AddTabletTagRequest) (resp *vtctldatapb.AddTabletTagResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.AddTabletTag")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("tablet_alias", topoproto.TabletAliasString(req.TabletAlias))
+
+	if req.TabletAlias == nil {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "TabletAlias is required")
+		return nil, err
+	}
+	if len(req.Tags) == 0 {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "at least one tag is required")
+		return nil, err
+	}
+	for key := range req.Tags {
+		if key == "" {
+			err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "tag keys cannot be empty")
+			return nil, err
+		}
+	}
+
+	tablet, err := s.updateTabletTags(ctx, req.TabletAlias, func(tags map[string]string) bool {
+		changed := false
+		for key, value := range req.Tags {
+			if current, ok := tags[key]; !ok || current != value {
+				tags[key] = value
+				changed = true
+			}
+		}
+
+		return changed
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.AddTabletTagResponse{Tablet: tablet}, nil
+}
+
 // ApplyRoutingRules is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) ApplyRoutingRules(ctx context.Context, req *vtctldatapb.ApplyRoutingRulesRequest) (resp *vtctldatapb.ApplyRoutingRulesResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.ApplyRoutingRules")
@@ -229,7 +327,34 @@ func (s *VtctldServer) ApplySchema(ctx context.Context, req *vtctldatapb.ApplySc
 	span.Annotate("keyspace", req.Keyspace)
 	span.Annotate("ddl_strategy", req.DdlStrategy)
 
-	if len(req.Sql) == 0 {
+	sqls := req.Sql
+	var changelogIDs []string
+	if req.Changelog != "" {
+		span.Annotate("changelog", true)
+
+		if len(req.Sql) > 0 {
+			err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "cannot pass both Sql and Changelog")
+			return nil, err
+		}
+		if req.Plan {
+			err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Plan is not supported with a Changelog")
+			return nil, err
+		}
+
+		var entries []*schemamanager.ChangelogEntry
+		entries, err = schemamanager.ParseChangelog(req.Changelog)
+		if err != nil {
+			return nil, err
+		}
+		sqls = make([]string, 0, len(entries))
+		changelogIDs = make([]string, 0, len(entries))
+		for _, entry := range entries {
+			sqls = append(sqls, entry.SQL)
+			changelogIDs = append(changelogIDs, entry.ID)
+		}
+	}
+
+	if len(sqls) == 0 {
 		err = vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "Sql must be a non-empty array")
 		return nil, err
 	}
@@ -282,9 +407,43 @@ func (s *VtctldServer) ApplySchema(ctx context.Context, req *vtctldatapb.ApplySc
 		}
 	}
 
+	if len(changelogIDs) > 0 {
+		if err = executor.SetChangelogIDs(changelogIDs); err != nil {
+			err = vterrors.Wrapf(err, "invalid Changelog")
+			return resp, err
+		}
+	}
+
+	if req.Plan {
+		span.Annotate("plan", true)
+
+		var shardPlans []*schemamanager.ShardPlan
+		shardPlans, err = schemamanager.Plan(
+			ctx,
+			schemamanager.NewPlainController(req.Sql, req.Keyspace),
+			executor,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp = &vtctldatapb.ApplySchemaResponse{
+			ShardPlans: make([]*vtctldatapb.ApplySchemaShardPlan, 0, len(shardPlans)),
+		}
+		for _, shardPlan := range shardPlans {
+			resp.ShardPlans = append(resp.ShardPlans, &vtctldatapb.ApplySchemaShardPlan{
+				Shard:              shardPlan.Shard,
+				Statements:         shardPlan.Statements,
+				DestructiveChanges: shardPlan.DestructiveChanges,
+			})
+		}
+
+		return resp, nil
+	}
+
 	execResult, err := schemamanager.Run(
 		ctx,
-		schemamanager.NewPlainController(req.Sql, req.Keyspace),
+		schemamanager.NewPlainController(sqls, req.Keyspace),
 		executor,
 	)
 
@@ -513,6 +672,63 @@ func (s *VtctldServer) backupTablet(ctx context.Context, tablet *topodatapb.Tabl
 	}
 }
 
+// CancelCommand is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) CancelCommand(ctx context.Context, req *vtctldatapb.CancelCommandRequest) (resp *vtctldatapb.CancelCommandResponse, err error) {
+	span, _ := trace.NewSpan(ctx, "VtctldServer.CancelCommand")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("id", req.Id)
+
+	if err = commandtracker.Default.Cancel(req.Id); err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.CancelCommandResponse{}, nil
+}
+
+// CancelScheduledCommand is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) CancelScheduledCommand(ctx context.Context, req *vtctldatapb.CancelScheduledCommandRequest) (resp *vtctldatapb.CancelScheduledCommandResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.CancelScheduledCommand")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("id", req.Id)
+
+	if _, err = uuid.Parse(req.Id); err != nil {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid scheduled command id %q", req.Id)
+		return nil, err
+	}
+
+	cmd, err := s.ts.GetScheduledCommand(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if cmd.State != vtctldatapb.ScheduledCommand_PENDING {
+		err = vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "scheduled command %s is %s, only pending commands can be canceled", req.Id, cmd.State)
+		return nil, err
+	}
+
+	cmd.State = vtctldatapb.ScheduledCommand_CANCELED
+	cmd.FinishTime = protoutil.TimeToProto(time.Now())
+
+	err = s.ts.UpdateScheduledCommand(ctx, cmd)
+	switch {
+	case topo.IsErrType(err, topo.BadVersion):
+		err = vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "scheduled command %s changed while being canceled, it may have just started", req.Id)
+		return nil, err
+	case err != nil:
+		return nil, err
+	}
+
+	return &vtctldatapb.CancelScheduledCommandResponse{
+		ScheduledCommand: cmd.ScheduledCommand,
+	}, nil
+}
+
 // CancelSchemaMigration is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) CancelSchemaMigration(ctx context.Context, req *vtctldatapb.CancelSchemaMigrationRequest) (resp *vtctldatapb.CancelSchemaMigrationResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.CancelSchemaMigration")
@@ -642,6 +858,65 @@ func (s *VtctldServer) ChangeTabletType(ctx context.Context, req *vtctldatapb.Ch
 	}, nil
 }
 
+// ChangeTabletTypeByFilter is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ChangeTabletTypeByFilter(ctx context.Context, req *vtctldatapb.ChangeTabletTypeByFilterRequest) (resp *vtctldatapb.ChangeTabletTypeByFilterResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ChangeTabletTypeByFilter")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("cells", strings.Join(req.Cells, ","))
+	span.Annotate("from_type", topoproto.TabletTypeLString(req.FromType))
+	span.Annotate("to_type", topoproto.TabletTypeLString(req.ToType))
+	span.Annotate("dry_run", req.DryRun)
+	span.Annotate("concurrency", req.Concurrency)
+
+	if req.Keyspace == "" {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "ChangeTabletTypeByFilter requires a keyspace")
+		return nil, err
+	}
+
+	if req.FromType == topodatapb.TabletType_UNKNOWN {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "ChangeTabletTypeByFilter requires a tablet type to change from")
+		return nil, err
+	}
+
+	tablets, err := s.findTabletsByFilter(ctx, req.Keyspace, req.Shard, req.Cells, req.FromType)
+	if err != nil {
+		return nil, err
+	}
+
+	span.Annotate("num_tablets", len(tablets))
+
+	responses := make([]*vtctldatapb.ChangeTabletTypeResponse, len(tablets))
+	errs := forEachTablet(ctx, tablets, req.Concurrency, func(ctx context.Context, i int, tablet *topodatapb.Tablet) (err error) {
+		responses[i], err = s.ChangeTabletType(ctx, &vtctldatapb.ChangeTabletTypeRequest{
+			TabletAlias: tablet.Alias,
+			DbType:      req.ToType,
+			DryRun:      req.DryRun,
+		})
+		return err
+	})
+
+	resp = &vtctldatapb.ChangeTabletTypeByFilterResponse{
+		Results: make([]*vtctldatapb.ChangeTabletTypeByFilterResponse_Result, len(tablets)),
+	}
+	for i, tablet := range tablets {
+		resp.Results[i] = &vtctldatapb.ChangeTabletTypeByFilterResponse_Result{
+			TabletAlias: tablet.Alias,
+			Response:    responses[i],
+		}
+
+		if errs[i] != nil {
+			resp.Results[i].Error = errs[i].Error()
+		}
+	}
+
+	return resp, nil
+}
+
 // CleanupSchemaMigration is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) CleanupSchemaMigration(ctx context.Context, req *vtctldatapb.CleanupSchemaMigrationRequest) (resp *vtctldatapb.CleanupSchemaMigrationResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.CleanupSchemaMigration")
@@ -673,6 +948,66 @@ func (s *VtctldServer) CleanupSchemaMigration(ctx context.Context, req *vtctldat
 	return resp, nil
 }
 
+// ConcludeTransaction is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ConcludeTransaction(ctx context.Context, req *vtctldatapb.ConcludeTransactionRequest) (resp *vtctldatapb.ConcludeTransactionResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ConcludeTransaction")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("dtid", req.Dtid)
+
+	mmShard, err := dtids.ShardSession(req.Dtid)
+	if err != nil {
+		return nil, err
+	}
+	mm, err := s.shardPrimary(ctx, mmShard.Target.Keyspace, mmShard.Target.Shard)
+	if err != nil {
+		return nil, err
+	}
+	r, err := s.tmc.ReadTransaction(ctx, mm.Tablet, &tabletmanagerdatapb.ReadTransactionRequest{Dtid: req.Dtid})
+	if err != nil {
+		return nil, err
+	}
+	transaction := r.Transaction
+	if transaction == nil || transaction.Dtid == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "distributed transaction %s not found, it may be resolved already", req.Dtid)
+	}
+
+	var commit bool
+	switch transaction.State {
+	case querypb.TransactionState_COMMIT:
+		commit = true
+	case querypb.TransactionState_ROLLBACK:
+	default:
+		// The decision is not made yet: the watchdog of the metadata manager
+		// rolls back the transaction once it is abandoned.
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "distributed transaction %s is in the %v state, only COMMIT and ROLLBACK transactions can be concluded", req.Dtid, transaction.State)
+	}
+	span.Annotate("commit", commit)
+
+	for _, participant := range transaction.Participants {
+		primary, err := s.shardPrimary(ctx, participant.Keyspace, participant.Shard)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.tmc.ConcludeTransaction(ctx, primary.Tablet, &tabletmanagerdatapb.ConcludeTransactionRequest{
+			Dtid:   req.Dtid,
+			Commit: commit,
+		}); err != nil {
+			return nil, vterrors.Wrapf(err, "cannot resolve distributed transaction %s on %s/%s", req.Dtid, participant.Keyspace, participant.Shard)
+		}
+	}
+	if _, err := s.tmc.ConcludeTransaction(ctx, mm.Tablet, &tabletmanagerdatapb.ConcludeTransactionRequest{
+		Dtid: req.Dtid,
+		Mm:   true,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.ConcludeTransactionResponse{}, nil
+}
+
 // CreateKeyspace is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) CreateKeyspace(ctx context.Context, req *vtctldatapb.CreateKeyspaceRequest) (resp *vtctldatapb.CreateKeyspaceResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.CreateKeyspace")
@@ -683,6 +1018,7 @@ func (s *VtctldServer) CreateKeyspace(ctx context.Context, req *vtctldatapb.Crea
 	span.Annotate("keyspace", req.Name)
 	span.Annotate("keyspace_type", topoproto.KeyspaceTypeLString(req.Type))
 	span.Annotate("force", req.Force)
+	span.Annotate("if_not_exists", req.IfNotExists)
 	span.Annotate("allow_empty_vschema", req.AllowEmptyVSchema)
 	span.Annotate("durability_policy", req.DurabilityPolicy)
 
@@ -715,6 +1051,24 @@ func (s *VtctldServer) CreateKeyspace(ctx context.Context, req *vtctldatapb.Crea
 	}
 
 	err = s.ts.CreateKeyspace(ctx, req.Name, ki)
+	if req.IfNotExists && topo.IsErrType(err, topo.NodeExists) {
+		log.Infof("keyspace %v already exists; leaving it unchanged because IfNotExists = true", req.Name)
+
+		var ks *topo.KeyspaceInfo
+		ks, err = s.ts.GetKeyspace(ctx, req.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		return &vtctldatapb.CreateKeyspaceResponse{
+			Keyspace: &vtctldatapb.Keyspace{
+				Name:     req.Name,
+				Keyspace: ks.Keyspace,
+			},
+			AlreadyExists: true,
+		}, nil
+	}
+
 	if req.Force && topo.IsErrType(err, topo.NodeExists) {
 		log.Infof("keyspace %v already exists (ignoring error with Force=true)", req.Name)
 		err = nil
@@ -788,12 +1142,13 @@ func (s *VtctldServer) CreateShard(ctx context.Context, req *vtctldatapb.CreateS
 	span.Annotate("shard", req.ShardName)
 	span.Annotate("force", req.Force)
 	span.Annotate("include_parent", req.IncludeParent)
+	span.Annotate("if_not_exists", req.IfNotExists)
 
 	if req.IncludeParent {
 		log.Infof("Creating empty keyspace for %s", req.Keyspace)
 		if err2 := s.ts.CreateKeyspace(ctx, req.Keyspace, &topodatapb.Keyspace{}); err2 != nil {
-			if req.Force && topo.IsErrType(err2, topo.NodeExists) {
-				log.Infof("keyspace %v already exists; ignoring error because Force = true", req.Keyspace)
+			if (req.Force || req.IfNotExists) && topo.IsErrType(err2, topo.NodeExists) {
+				log.Infof("keyspace %v already exists; ignoring error because Force or IfNotExists = true", req.Keyspace)
 			} else {
 				err = err2
 				return nil, err
@@ -804,8 +1159,8 @@ func (s *VtctldServer) CreateShard(ctx context.Context, req *vtctldatapb.CreateS
 	shardExists := false
 
 	if err = s.ts.CreateShard(ctx, req.Keyspace, req.ShardName); err != nil {
-		if req.Force && topo.IsErrType(err, topo.NodeExists) {
-			log.Infof("shard %v/%v already exists; ignoring error because Force = true", req.Keyspace, req.ShardName)
+		if (req.Force || req.IfNotExists) && topo.IsErrType(err, topo.NodeExists) {
+			log.Infof("shard %v/%v already exists; ignoring error because Force or IfNotExists = true", req.Keyspace, req.ShardName)
 			shardExists = true
 			err = nil
 		} else {
@@ -889,16 +1244,27 @@ func (s *VtctldServer) DeleteKeyspace(ctx context.Context, req *vtctldatapb.Dele
 	span.Annotate("keyspace", req.Keyspace)
 	span.Annotate("recursive", req.Recursive)
 	span.Annotate("force", req.Force)
+	span.Annotate("dry_run", req.DryRun)
 
-	lctx, unlock, lerr := s.ts.LockKeyspace(ctx, req.Keyspace, "DeleteKeyspace")
-	switch {
-	case lerr == nil:
-		ctx = lctx
-	case !req.Force:
-		err = fmt.Errorf("failed to lock %s; if you really want to delete this keyspace, re-run with Force=true: %w", req.Keyspace, lerr)
-		return nil, err
-	default:
-		log.Warningf("%s: failed to lock keyspace %s for deletion, but force=true, proceeding anyway ...", lerr, req.Keyspace)
+	drl := newDryRunLog(req.DryRun)
+
+	var unlock func(*error)
+	if !drl.enabled() {
+		var (
+			lctx context.Context
+			lerr error
+		)
+
+		lctx, unlock, lerr = s.ts.LockKeyspace(ctx, req.Keyspace, "DeleteKeyspace")
+		switch {
+		case lerr == nil:
+			ctx = lctx
+		case !req.Force:
+			err = fmt.Errorf("failed to lock %s; if you really want to delete this keyspace, re-run with Force=true: %w", req.Keyspace, lerr)
+			return nil, err
+		default:
+			log.Warningf("%s: failed to lock keyspace %s for deletion, but force=true, proceeding anyway ...", lerr, req.Keyspace)
+		}
 	}
 
 	if unlock != nil {
@@ -935,7 +1301,7 @@ func (s *VtctldServer) DeleteKeyspace(ctx context.Context, req *vtctldatapb.Dele
 
 		for _, shard := range shards {
 			log.Infof("Recursively deleting shard %v/%v", req.Keyspace, shard)
-			err = deleteShard(ctx, s.ts, req.Keyspace, shard, recursive, evenIfServing, force)
+			err = deleteShard(ctx, s.ts, req.Keyspace, shard, recursive, evenIfServing, force, drl)
 			if err != nil {
 				err = fmt.Errorf("cannot delete shard %v/%v: %w", req.Keyspace, shard, err)
 				return nil, err
@@ -949,6 +1315,12 @@ func (s *VtctldServer) DeleteKeyspace(ctx context.Context, req *vtctldatapb.Dele
 	}
 
 	for _, cell := range cells {
+		if drl.enabled() {
+			drl.add("Delete KeyspaceReplication for keyspace %v in cell %v", req.Keyspace, cell)
+			drl.add("Delete SrvKeyspace for keyspace %v in cell %v", req.Keyspace, cell)
+			continue
+		}
+
 		if err := s.ts.DeleteKeyspaceReplication(ctx, cell, req.Keyspace); err != nil && !topo.IsErrType(err, topo.NoNode) {
 			log.Warningf("Cannot delete KeyspaceReplication in cell %v for %v: %v", cell, req.Keyspace, err)
 		}
@@ -958,6 +1330,13 @@ func (s *VtctldServer) DeleteKeyspace(ctx context.Context, req *vtctldatapb.Dele
 		}
 	}
 
+	if drl.enabled() {
+		drl.add("Delete keyspace %v", req.Keyspace)
+		return &vtctldatapb.DeleteKeyspaceResponse{
+			DryRunResults: drl.Results(),
+		}, nil
+	}
+
 	err = s.ts.DeleteKeyspace(ctx, req.Keyspace)
 	if err != nil {
 		return nil, err
@@ -977,15 +1356,19 @@ func (s *VtctldServer) DeleteShards(ctx context.Context, req *vtctldatapb.Delete
 	span.Annotate("even_if_serving", req.EvenIfServing)
 	span.Annotate("recursive", req.Recursive)
 	span.Annotate("force", req.Force)
+	span.Annotate("dry_run", req.DryRun)
 
+	drl := newDryRunLog(req.DryRun)
 	for _, shard := range req.Shards {
-		if err2 := deleteShard(ctx, s.ts, shard.Keyspace, shard.Name, req.Recursive, req.EvenIfServing, req.Force); err2 != nil {
+		if err2 := deleteShard(ctx, s.ts, shard.Keyspace, shard.Name, req.Recursive, req.EvenIfServing, req.Force, drl); err2 != nil {
 			err = err2
 			return nil, err
 		}
 	}
 
-	return &vtctldatapb.DeleteShardsResponse{}, nil
+	return &vtctldatapb.DeleteShardsResponse{
+		DryRunResults: drl.Results(),
+	}, nil
 }
 
 // DeleteSrvVSchema is part of the vtctlservicepb.VtctldServer interface.
@@ -1021,15 +1404,19 @@ func (s *VtctldServer) DeleteTablets(ctx context.Context, req *vtctldatapb.Delet
 
 	span.Annotate("num_tablets", len(req.TabletAliases))
 	span.Annotate("allow_primary", req.AllowPrimary)
+	span.Annotate("dry_run", req.DryRun)
 
+	drl := newDryRunLog(req.DryRun)
 	for _, alias := range req.TabletAliases {
-		if err2 := deleteTablet(ctx, s.ts, alias, req.AllowPrimary); err2 != nil {
+		if err2 := deleteTablet(ctx, s.ts, alias, req.AllowPrimary, drl); err2 != nil {
 			err = err2
 			return nil, err
 		}
 	}
 
-	return &vtctldatapb.DeleteTabletsResponse{}, nil
+	return &vtctldatapb.DeleteTabletsResponse{
+		DryRunResults: drl.Results(),
+	}, nil
 }
 
 // EmergencyReparentShard is part of the vtctldservicepb.VtctldServer interface.
@@ -1225,46 +1612,237 @@ func (s *VtctldServer) FindAllShardsInKeyspace(ctx context.Context, req *vtctlda
 	}, nil
 }
 
-// GetBackups is part of the vtctldservicepb.VtctldServer interface.
-func (s *VtctldServer) GetBackups(ctx context.Context, req *vtctldatapb.GetBackupsRequest) (resp *vtctldatapb.GetBackupsResponse, err error) {
-	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetBackups")
+// FixErrantGTID is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) FixErrantGTID(ctx context.Context, req *vtctldatapb.FixErrantGTIDRequest) (resp *vtctldatapb.FixErrantGTIDResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.FixErrantGTID")
 	defer span.Finish()
 
 	defer panicHandler(&err)
 
-	span.Annotate("keyspace", req.Keyspace)
-	span.Annotate("shard", req.Shard)
-	span.Annotate("limit", req.Limit)
-	span.Annotate("detailed", req.Detailed)
-	span.Annotate("detailed_limit", req.DetailedLimit)
+	if req.TabletAlias == nil {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "FixErrantGTID.TabletAlias is required")
+		return nil, err
+	}
 
-	bs, err := backupstorage.GetBackupStorage()
+	alias := topoproto.TabletAliasString(req.TabletAlias)
+	span.Annotate("tablet_alias", alias)
+	span.Annotate("mode", req.Mode.String())
+	span.Annotate("tablet_type", topoproto.TabletTypeLString(req.TabletType))
+	span.Annotate("dry_run", req.DryRun)
+
+	tablet, err := s.ts.GetTablet(ctx, req.TabletAlias)
 	if err != nil {
 		return nil, err
 	}
-	defer bs.Close()
-
-	bucket := filepath.Join(req.Keyspace, req.Shard)
-	span.Annotate("backup_path", bucket)
 
-	bhs, err := bs.ListBackups(ctx, bucket)
+	shard, err := s.ts.GetShard(ctx, tablet.Keyspace, tablet.Shard)
 	if err != nil {
 		return nil, err
 	}
 
-	totalBackups := len(bhs)
-	if req.Limit > 0 {
-		totalBackups = int(req.Limit)
+	if !shard.HasPrimary() {
+		err = vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no primary tablet for shard %v/%v", tablet.Keyspace, tablet.Shard)
+		return nil, err
 	}
 
-	totalDetailedBackups := len(bhs)
-	if req.DetailedLimit > 0 {
-		totalDetailedBackups = int(req.DetailedLimit)
+	if topoproto.TabletAliasEqual(shard.PrimaryAlias, req.TabletAlias) {
+		err = vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "tablet %v is the primary of shard %v/%v", alias, tablet.Keyspace, tablet.Shard)
+		return nil, err
 	}
 
-	backups := make([]*mysqlctlpb.BackupInfo, 0, totalBackups)
-	backupsToSkip := len(bhs) - totalBackups
-	backupsToSkipDetails := len(bhs) - totalDetailedBackups
+	shardPrimary, err := s.ts.GetTablet(ctx, shard.PrimaryAlias)
+	if err != nil {
+		err = fmt.Errorf("cannot lookup primary tablet %v for shard %v/%v: %w", topoproto.TabletAliasString(shard.PrimaryAlias), tablet.Keyspace, tablet.Shard, err)
+		return nil, err
+	}
+
+	replicaGTIDSet, err := s.executedGTIDSet(ctx, tablet.Tablet)
+	if err != nil {
+		return nil, err
+	}
+
+	primaryGTIDSet, err := s.executedGTIDSet(ctx, shardPrimary.Tablet)
+	if err != nil {
+		return nil, err
+	}
+
+	errantGTIDSet := replicaGTIDSet.Difference(primaryGTIDSet)
+	resp = &vtctldatapb.FixErrantGTIDResponse{
+		ErrantGtidSet: errantGTIDSet.String(),
+	}
+
+	if req.DryRun {
+		return resp, nil
+	}
+
+	durabilityName, err := s.ts.GetKeyspaceDurability(ctx, tablet.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+	durability, err := reparentutil.GetDurabilityPolicy(durabilityName)
+	if err != nil {
+		return nil, err
+	}
+
+	// The durability rules are checked for the type the replica is changed
+	// back to, and not for its quarantine type.
+	expectedTablet := proto.Clone(tablet.Tablet).(*topodatapb.Tablet)
+	if req.TabletType != topodatapb.TabletType_UNKNOWN {
+		expectedTablet.Type = req.TabletType
+	}
+	semiSync := reparentutil.IsReplicaSemiSync(durability, shardPrimary.Tablet, expectedTablet)
+
+	if len(errantGTIDSet) > 0 {
+		switch req.Mode {
+		case vtctldatapb.FixErrantGTIDRequest_RESET:
+			err = s.resetErrantGTIDs(ctx, tablet.Tablet, replicaGTIDSet.Difference(errantGTIDSet), semiSync)
+		case vtctldatapb.FixErrantGTIDRequest_RECONCILE:
+			err = s.reconcileErrantGTIDs(ctx, tablet.Tablet, errantGTIDSet)
+		default:
+			err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown FixErrantGTID mode %v", req.Mode)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if req.TabletType != topodatapb.TabletType_UNKNOWN && req.TabletType != tablet.Type {
+		if err = s.tmc.ChangeType(ctx, tablet.Tablet, req.TabletType, semiSync); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = s.ts.UpdateTabletFields(ctx, req.TabletAlias, func(t *topodatapb.Tablet) error {
+		if _, ok := t.Tags[topo.ErrantGTIDQuarantineTag]; !ok {
+			return topo.NewError(topo.NoUpdateNeeded, alias)
+		}
+		delete(t.Tags, topo.ErrantGTIDQuarantineTag)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// executedGTIDSet returns the GTID set executed by the given tablet.
+func (s *VtctldServer) executedGTIDSet(ctx context.Context, tablet *topodatapb.Tablet) (replication.Mysql56GTIDSet, error) {
+	position, err := s.tmc.PrimaryPosition(ctx, tablet)
+	if err != nil {
+		return nil, err
+	}
+
+	pos, err := replication.DecodePosition(position)
+	if err != nil {
+		return nil, err
+	}
+
+	gtidSet, ok := pos.GTIDSet.(replication.Mysql56GTIDSet)
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "tablet %v doesn't use MySQL 5.6 GTIDs: %v", topoproto.TabletAliasString(tablet.Alias), position)
+	}
+
+	return gtidSet, nil
+}
+
+// resetErrantGTIDs drops the errant GTIDs of a replica, by resetting its
+// binary logs and setting its GTID_PURGED to the GTID set it keeps.
+func (s *VtctldServer) resetErrantGTIDs(ctx context.Context, tablet *topodatapb.Tablet, keptGTIDSet replication.Mysql56GTIDSet, semiSync bool) error {
+	if err := s.tmc.StopReplication(ctx, tablet); err != nil {
+		return err
+	}
+
+	for _, query := range []string{
+		"RESET MASTER",
+		fmt.Sprintf("SET GLOBAL gtid_purged = '%s'", keptGTIDSet.String()),
+	} {
+		if _, err := s.tmc.ExecuteFetchAsDba(ctx, tablet, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+			Query:          []byte(query),
+			DisableBinlogs: true,
+		}); err != nil {
+			return fmt.Errorf("cannot reset the errant GTIDs of %v: %w", topoproto.TabletAliasString(tablet.Alias), err)
+		}
+	}
+
+	return s.tmc.StartReplication(ctx, tablet, semiSync)
+}
+
+// reconcileErrantGTIDs adds the errant GTIDs of a replica to the GTID_PURGED
+// of the other tablets of its shard, so that they are no longer errant.
+func (s *VtctldServer) reconcileErrantGTIDs(ctx context.Context, tablet *topodatapb.Tablet, errantGTIDSet replication.Mysql56GTIDSet) error {
+	tabletMap, err := s.ts.GetTabletMapForShard(ctx, tablet.Keyspace, tablet.Shard)
+	if err != nil {
+		return err
+	}
+
+	for _, ti := range tabletMap {
+		if topoproto.TabletAliasEqual(ti.Alias, tablet.Alias) {
+			continue
+		}
+
+		executed, err := s.executedGTIDSet(ctx, ti.Tablet)
+		if err != nil {
+			return err
+		}
+
+		// The GTIDs added to GTID_PURGED must not have been executed already.
+		missing := errantGTIDSet.Difference(executed)
+		if len(missing) == 0 {
+			continue
+		}
+
+		if _, err := s.tmc.ExecuteFetchAsDba(ctx, ti.Tablet, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+			Query:          []byte(fmt.Sprintf("SET GLOBAL gtid_purged = '+%s'", missing.String())),
+			DisableBinlogs: true,
+		}); err != nil {
+			return fmt.Errorf("cannot reconcile the errant GTIDs on %v: %w", topoproto.TabletAliasString(ti.Alias), err)
+		}
+	}
+
+	return nil
+}
+
+// GetBackups is part of the vtctldservicepb.VtctldServer interface.
+func (s *VtctldServer) GetBackups(ctx context.Context, req *vtctldatapb.GetBackupsRequest) (resp *vtctldatapb.GetBackupsResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetBackups")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("limit", req.Limit)
+	span.Annotate("detailed", req.Detailed)
+	span.Annotate("detailed_limit", req.DetailedLimit)
+
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return nil, err
+	}
+	defer bs.Close()
+
+	bucket := filepath.Join(req.Keyspace, req.Shard)
+	span.Annotate("backup_path", bucket)
+
+	bhs, err := bs.ListBackups(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	totalBackups := len(bhs)
+	if req.Limit > 0 {
+		totalBackups = int(req.Limit)
+	}
+
+	totalDetailedBackups := len(bhs)
+	if req.DetailedLimit > 0 {
+		totalDetailedBackups = int(req.DetailedLimit)
+	}
+
+	backups := make([]*mysqlctlpb.BackupInfo, 0, totalBackups)
+	backupsToSkip := len(bhs) - totalBackups
+	backupsToSkipDetails := len(bhs) - totalDetailedBackups
 
 	for i, bh := range bhs {
 		if i < backupsToSkip {
@@ -1419,6 +1997,27 @@ func (s *VtctldServer) GetKeyspaces(ctx context.Context, req *vtctldatapb.GetKey
 	return &vtctldatapb.GetKeyspacesResponse{Keyspaces: keyspaces}, nil
 }
 
+// GetMaintenanceWindows is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetMaintenanceWindows(ctx context.Context, req *vtctldatapb.GetMaintenanceWindowsRequest) (resp *vtctldatapb.GetMaintenanceWindowsResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetMaintenanceWindows")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	windows, err := s.ts.GetMaintenanceWindows(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &vtctldatapb.GetMaintenanceWindowsResponse{
+		Windows: make([]*vtctldatapb.MaintenanceWindow, len(windows)),
+	}
+	for i, window := range windows {
+		resp.Windows[i] = maintenanceWindowToProto(window)
+	}
+	return resp, nil
+}
+
 // GetPermissions is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) GetPermissions(ctx context.Context, req *vtctldatapb.GetPermissionsRequest) (resp *vtctldatapb.GetPermissionsResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetPermissions")
@@ -1460,6 +2059,40 @@ func (s *VtctldServer) GetRoutingRules(ctx context.Context, req *vtctldatapb.Get
 	}, nil
 }
 
+// GetRunningCommands is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetRunningCommands(ctx context.Context, req *vtctldatapb.GetRunningCommandsRequest) (resp *vtctldatapb.GetRunningCommandsResponse, err error) {
+	span, _ := trace.NewSpan(ctx, "VtctldServer.GetRunningCommands")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	return &vtctldatapb.GetRunningCommandsResponse{
+		Commands: commandtracker.Default.List(),
+	}, nil
+}
+
+// GetScheduledCommands is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetScheduledCommands(ctx context.Context, req *vtctldatapb.GetScheduledCommandsRequest) (resp *vtctldatapb.GetScheduledCommandsResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetScheduledCommands")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	cmds, err := s.ts.GetScheduledCommands(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &vtctldatapb.GetScheduledCommandsResponse{
+		ScheduledCommands: make([]*vtctldatapb.ScheduledCommand, 0, len(cmds)),
+	}
+	for _, cmd := range cmds {
+		resp.ScheduledCommands = append(resp.ScheduledCommands, cmd.ScheduledCommand)
+	}
+
+	return resp, nil
+}
+
 // GetShardRoutingRules is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) GetShardRoutingRules(ctx context.Context, req *vtctldatapb.GetShardRoutingRulesRequest) (*vtctldatapb.GetShardRoutingRulesResponse, error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetShardRoutingRules")
@@ -1822,6 +2455,70 @@ func (s *VtctldServer) UpdateThrottlerConfig(ctx context.Context, req *vtctldata
 	return &vtctldatapb.UpdateThrottlerConfigResponse{}, err
 }
 
+// UpdateOnlineDDLSchedulerConfig is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) UpdateOnlineDDLSchedulerConfig(ctx context.Context, req *vtctldatapb.UpdateOnlineDDLSchedulerConfigRequest) (resp *vtctldatapb.UpdateOnlineDDLSchedulerConfigResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.UpdateOnlineDDLSchedulerConfig")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+
+	if req.MaxConcurrentMigrationsSet && req.MaxConcurrentMigrations < 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "max concurrent migrations must not be negative: %d", req.MaxConcurrentMigrations)
+	}
+	for name := range req.PriorityClasses {
+		if name == "" {
+			return nil, vterrors.New(vtrpcpb.Code_INVALID_ARGUMENT, "priority class names cannot be empty")
+		}
+	}
+	if req.LaunchWindowSet && req.LaunchWindow != "" {
+		if _, err := schema.ParseLaunchWindow(req.LaunchWindow); err != nil {
+			return nil, vterrors.Wrapf(err, "invalid launch window")
+		}
+	}
+
+	ctx, unlock, lockErr := s.ts.LockKeyspace(ctx, req.Keyspace, "UpdateOnlineDDLSchedulerConfig")
+	if lockErr != nil {
+		return nil, lockErr
+	}
+	defer unlock(&err)
+
+	ki, err := s.ts.GetKeyspace(ctx, req.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	config := ki.OnlineDdlSchedulerConfig
+	if config == nil {
+		config = &topodatapb.OnlineDDLSchedulerConfig{}
+	}
+	if req.MaxConcurrentMigrationsSet {
+		config.MaxConcurrentMigrations = req.MaxConcurrentMigrations
+	}
+	if len(req.PriorityClasses) > 0 && config.PriorityClasses == nil {
+		config.PriorityClasses = make(map[string]int32, len(req.PriorityClasses))
+	}
+	for name, priority := range req.PriorityClasses {
+		config.PriorityClasses[name] = priority
+	}
+	for _, name := range req.RemovePriorityClasses {
+		delete(config.PriorityClasses, name)
+	}
+	if req.LaunchWindowSet {
+		config.LaunchWindow = req.LaunchWindow
+	}
+	ki.OnlineDdlSchedulerConfig = config
+
+	if err = s.ts.UpdateKeyspace(ctx, ki); err != nil {
+		return nil, err
+	}
+
+	_, err = s.ts.UpdateSrvKeyspaceOnlineDDLSchedulerConfig(ctx, req.Keyspace, nil, config)
+
+	return &vtctldatapb.UpdateOnlineDDLSchedulerConfigResponse{Config: config}, err
+}
+
 // GetSrvVSchema is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) GetSrvVSchema(ctx context.Context, req *vtctldatapb.GetSrvVSchemaRequest) (resp *vtctldatapb.GetSrvVSchemaResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetSrvVSchema")
@@ -1906,6 +2603,35 @@ func (s *VtctldServer) GetTablet(ctx context.Context, req *vtctldatapb.GetTablet
 	}, nil
 }
 
+// GetTabletPlanCache is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetTabletPlanCache(ctx context.Context, req *vtctldatapb.GetTabletPlanCacheRequest) (resp *vtctldatapb.GetTabletPlanCacheResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetTabletPlanCache")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("tablet_alias", topoproto.TabletAliasString(req.TabletAlias))
+	span.Annotate("tables", strings.Join(req.Tables, ","))
+
+	ti, err := s.ts.GetTablet(ctx, req.TabletAlias)
+	if err != nil {
+		err = vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "GetTablet(%v) failed: %v", req.TabletAlias, err)
+		return nil, err
+	}
+
+	r, err := s.tmc.GetPlanCache(ctx, ti.Tablet, &tabletmanagerdatapb.GetPlanCacheRequest{
+		Tables:       req.Tables,
+		Fingerprints: req.Fingerprints,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.GetTabletPlanCacheResponse{
+		Entries: r.Entries,
+	}, nil
+}
+
 // GetTablets is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) GetTablets(ctx context.Context, req *vtctldatapb.GetTabletsRequest) (resp *vtctldatapb.GetTabletsResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetTablets")
@@ -1913,12 +2639,77 @@ func (s *VtctldServer) GetTablets(ctx context.Context, req *vtctldatapb.GetTable
 
 	defer panicHandler(&err)
 
+	annotateGetTabletsRequest(span, req)
+
+	tablets, err := s.getTablets(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return paginateTablets(filterTabletsByTags(tablets, req.Tags), req.PageSize, req.PageToken)
+}
+
+// GetTabletsStream is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetTabletsStream(req *vtctldatapb.GetTabletsRequest, stream vtctlservicepb.Vtctld_GetTabletsStreamServer) (err error) {
+	span, ctx := trace.NewSpan(stream.Context(), "VtctldServer.GetTabletsStream")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	annotateGetTabletsRequest(span, req)
+
+	tablets, err := s.getTablets(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	pageSize := req.PageSize
+	if pageSize == 0 {
+		pageSize = defaultGetTabletsStreamPageSize
+	}
+
+	pageToken := req.PageToken
+	tablets = filterTabletsByTags(tablets, req.Tags)
+	for {
+		page, err := paginateTablets(tablets, pageSize, pageToken)
+		if err != nil {
+			return err
+		}
+
+		if err := stream.Send(page); err != nil {
+			return err
+		}
+
+		if page.NextPageToken == "" {
+			return nil
+		}
+
+		pageToken = page.NextPageToken
+	}
+}
+
+const defaultGetTabletsStreamPageSize = 100
+
+func annotateGetTabletsRequest(span trace.Span, req *vtctldatapb.GetTabletsRequest) {
 	span.Annotate("cells", strings.Join(req.Cells, ","))
 	if req.TabletType != topodatapb.TabletType_UNKNOWN {
 		span.Annotate("tablet_type", topodatapb.TabletType_name[int32(req.TabletType)])
 	}
 	span.Annotate("strict", req.Strict)
+	span.Annotate("page_size", req.PageSize)
 
+	switch {
+	case len(req.TabletAliases) > 0:
+		span.Annotate("tablet_aliases", strings.Join(topoproto.TabletAliasList(req.TabletAliases).ToStringSlice(), ","))
+	case req.Keyspace != "" && req.Shard != "":
+		span.Annotate("keyspace", req.Keyspace)
+		span.Annotate("shard", req.Shard)
+	}
+}
+
+// getTablets returns the tablets selected by the aliases, keyspace, shard,
+// cells and tablet type of a GetTablets request, in no particular order.
+func (s *VtctldServer) getTablets(ctx context.Context, req *vtctldatapb.GetTabletsRequest) ([]*topodatapb.Tablet, error) {
 	// It is possible that an old primary has not yet updated its type in the
 	// topo. In that case, report its type as UNKNOWN. It used to be PRIMARY but
 	// is no longer the serving primary.
@@ -1936,20 +2727,18 @@ func (s *VtctldServer) GetTablets(ctx context.Context, req *vtctldatapb.GetTable
 	ctx, cancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
 	defer cancel()
 
-	var tabletMap map[string]*topo.TabletInfo
+	var (
+		tabletMap map[string]*topo.TabletInfo
+		err       error
+	)
 
 	switch {
 	case len(req.TabletAliases) > 0:
-		span.Annotate("tablet_aliases", strings.Join(topoproto.TabletAliasList(req.TabletAliases).ToStringSlice(), ","))
-
 		tabletMap, err = s.ts.GetTabletMap(ctx, req.TabletAliases)
 		if err != nil {
 			err = fmt.Errorf("GetTabletMap(%v) failed: %w", req.TabletAliases, err)
 		}
 	case req.Keyspace != "" && req.Shard != "":
-		span.Annotate("keyspace", req.Keyspace)
-		span.Annotate("shard", req.Shard)
-
 		tabletMap, err = s.ts.GetTabletMapForShard(ctx, req.Keyspace, req.Shard)
 		if err != nil {
 			err = fmt.Errorf("GetTabletMapForShard(%s, %s) failed: %w", req.Keyspace, req.Shard, err)
@@ -1989,7 +2778,7 @@ func (s *VtctldServer) GetTablets(ctx context.Context, req *vtctldatapb.GetTable
 			tablets = append(tablets, ti.Tablet)
 		}
 
-		return &vtctldatapb.GetTabletsResponse{Tablets: tablets}, nil
+		return tablets, nil
 	}
 
 	cells := req.Cells
@@ -2026,61 +2815,170 @@ func (s *VtctldServer) GetTablets(ctx context.Context, req *vtctldatapb.GetTable
 				return
 			}
 
-			m.Lock()
-			defer m.Unlock()
-			allTablets = append(allTablets, tablets...)
-		}(cell)
+			m.Lock()
+			defer m.Unlock()
+			allTablets = append(allTablets, tablets...)
+		}(cell)
+	}
+
+	wg.Wait()
+
+	if rec.HasErrors() {
+		if req.Strict || len(rec.Errors) == len(cells) {
+			err = rec.Error()
+			return nil, err
+		}
+	}
+
+	// Collect true primary term start times, and optionally filter out any
+	// tablets by keyspace according to the request.
+	PrimaryTermStartTimes := map[string]time.Time{}
+	filteredTablets := make([]*topo.TabletInfo, 0, len(allTablets))
+
+	for _, tablet := range allTablets {
+		if req.Keyspace != "" && tablet.Keyspace != req.Keyspace {
+			continue
+		}
+		if req.TabletType != 0 && tablet.Type != req.TabletType {
+			continue
+		}
+
+		key := tablet.Keyspace + "." + tablet.Shard
+		if v, ok := PrimaryTermStartTimes[key]; ok {
+			if tablet.GetPrimaryTermStartTime().After(v) {
+				PrimaryTermStartTimes[key] = tablet.GetPrimaryTermStartTime()
+			}
+		} else {
+			PrimaryTermStartTimes[key] = tablet.GetPrimaryTermStartTime()
+		}
+
+		filteredTablets = append(filteredTablets, tablet)
+	}
+
+	adjustedTablets := make([]*topodatapb.Tablet, len(filteredTablets))
+
+	// collect the tablets with adjusted primary term start times. they've
+	// already been filtered by the above loop, so no keyspace filtering
+	// here.
+	for i, ti := range filteredTablets {
+		key := ti.Keyspace + "." + ti.Shard
+		adjustTypeForStalePrimary(ti, PrimaryTermStartTimes[key])
+
+		adjustedTablets[i] = ti.Tablet
+	}
+
+	return adjustedTablets, nil
+}
+
+// updateTabletTags updates the tags of a tablet record with a compare-and-swap,
+// and returns the tablet. The update function returns whether it changed the
+// tags; the record is not written if it did not.
+func (s *VtctldServer) updateTabletTags(ctx context.Context, alias *topodatapb.TabletAlias, update func(tags map[string]string) bool) (*topodatapb.Tablet, error) {
+	ctx, cancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+	defer cancel()
+
+	var unchanged *topodatapb.Tablet
+	tablet, err := s.ts.UpdateTabletFields(ctx, alias, func(tablet *topodatapb.Tablet) error {
+		if tablet.Tags == nil {
+			tablet.Tags = map[string]string{}
+		}
+
+		if !update(tablet.Tags) {
+			unchanged = tablet
+			return topo.NewError(topo.NoUpdateNeeded, topoproto.TabletAliasString(alias))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if tablet == nil {
+		return unchanged, nil
+	}
+
+	return tablet, nil
+}
+
+// filterTabletsByTags returns the tablets that have all the given tags.
+func filterTabletsByTags(tablets []*topodatapb.Tablet, tags map[string]string) []*topodatapb.Tablet {
+	if len(tags) == 0 {
+		return tablets
+	}
+
+	filtered := make([]*topodatapb.Tablet, 0, len(tablets))
+	for _, tablet := range tablets {
+		matches := true
+		for k, v := range tags {
+			if value, ok := tablet.Tags[k]; !ok || value != v {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			filtered = append(filtered, tablet)
+		}
 	}
 
-	wg.Wait()
+	return filtered
+}
 
-	if rec.HasErrors() {
-		if req.Strict || len(rec.Errors) == len(cells) {
-			err = rec.Error()
-			return nil, err
+// paginateTablets returns the page of tablets that starts after the tablet
+// encoded in pageToken, if any. Pages are ordered by tablet alias, so that
+// page tokens remain valid when tablets are added or removed between pages.
+// All tablets are returned, in their original order, if neither pageSize nor
+// pageToken are set.
+func paginateTablets(tablets []*topodatapb.Tablet, pageSize uint32, pageToken string) (*vtctldatapb.GetTabletsResponse, error) {
+	if pageSize == 0 && pageToken == "" {
+		return &vtctldatapb.GetTabletsResponse{Tablets: tablets}, nil
+	}
+
+	aliasLess := func(a, b *topodatapb.TabletAlias) bool {
+		if a.Cell != b.Cell {
+			return a.Cell < b.Cell
 		}
+		return a.Uid < b.Uid
 	}
 
-	// Collect true primary term start times, and optionally filter out any
-	// tablets by keyspace according to the request.
-	PrimaryTermStartTimes := map[string]time.Time{}
-	filteredTablets := make([]*topo.TabletInfo, 0, len(allTablets))
+	sort.Slice(tablets, func(i, j int) bool {
+		return aliasLess(tablets[i].Alias, tablets[j].Alias)
+	})
 
-	for _, tablet := range allTablets {
-		if req.Keyspace != "" && tablet.Keyspace != req.Keyspace {
-			continue
-		}
-		if req.TabletType != 0 && tablet.Type != req.TabletType {
-			continue
+	if pageToken != "" {
+		last, err := decodeTabletsPageToken(pageToken)
+		if err != nil {
+			return nil, err
 		}
 
-		key := tablet.Keyspace + "." + tablet.Shard
-		if v, ok := PrimaryTermStartTimes[key]; ok {
-			if tablet.GetPrimaryTermStartTime().After(v) {
-				PrimaryTermStartTimes[key] = tablet.GetPrimaryTermStartTime()
-			}
-		} else {
-			PrimaryTermStartTimes[key] = tablet.GetPrimaryTermStartTime()
-		}
+		start := sort.Search(len(tablets), func(i int) bool {
+			return aliasLess(last, tablets[i].Alias)
+		})
+		tablets = tablets[start:]
+	}
 
-		filteredTablets = append(filteredTablets, tablet)
+	resp := &vtctldatapb.GetTabletsResponse{Tablets: tablets}
+	if pageSize > 0 && len(tablets) > int(pageSize) {
+		resp.Tablets = tablets[:pageSize]
+		resp.NextPageToken = base64.RawURLEncoding.EncodeToString([]byte(topoproto.TabletAliasString(resp.Tablets[pageSize-1].Alias)))
 	}
 
-	adjustedTablets := make([]*topodatapb.Tablet, len(filteredTablets))
+	return resp, nil
+}
 
-	// collect the tablets with adjusted primary term start times. they've
-	// already been filtered by the above loop, so no keyspace filtering
-	// here.
-	for i, ti := range filteredTablets {
-		key := ti.Keyspace + "." + ti.Shard
-		adjustTypeForStalePrimary(ti, PrimaryTermStartTimes[key])
+func decodeTabletsPageToken(pageToken string) (*topodatapb.TabletAlias, error) {
+	data, err := base64.RawURLEncoding.DecodeString(pageToken)
+	if err != nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid page token %q", pageToken)
+	}
 
-		adjustedTablets[i] = ti.Tablet
+	alias, err := topoproto.ParseTabletAlias(string(data))
+	if err != nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid page token %q", pageToken)
 	}
 
-	return &vtctldatapb.GetTabletsResponse{
-		Tablets: adjustedTablets,
-	}, nil
+	return alias, nil
 }
 
 // GetTopologyPath is part of the vtctlservicepb.VtctldServer interface.
@@ -2116,6 +3014,42 @@ func (s *VtctldServer) GetTopologyPath(ctx context.Context, req *vtctldatapb.Get
 	}, nil
 }
 
+// GetUnresolvedTransactions is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetUnresolvedTransactions(ctx context.Context, req *vtctldatapb.GetUnresolvedTransactionsRequest) (resp *vtctldatapb.GetUnresolvedTransactionsResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetUnresolvedTransactions")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("abandon_age", req.AbandonAge)
+
+	shards, err := s.ts.GetShardNames(ctx, req.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &vtctldatapb.GetUnresolvedTransactionsResponse{}
+	for _, shard := range shards {
+		primary, err := s.shardPrimary(ctx, req.Keyspace, shard)
+		if err != nil {
+			return nil, err
+		}
+		r, err := s.tmc.GetUnresolvedTransactions(ctx, primary.Tablet, &tabletmanagerdatapb.GetUnresolvedTransactionsRequest{
+			AbandonAge: req.AbandonAge,
+		})
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "cannot read the unresolved transactions of %s/%s", req.Keyspace, shard)
+		}
+		resp.Transactions = append(resp.Transactions, r.Transactions...)
+	}
+	sort.Slice(resp.Transactions, func(i, j int) bool {
+		return resp.Transactions[i].Dtid < resp.Transactions[j].Dtid
+	})
+
+	return resp, nil
+}
+
 // GetVersion returns the version of a tablet from its debug vars
 func (s *VtctldServer) GetVersion(ctx context.Context, req *vtctldatapb.GetVersionRequest) (resp *vtctldatapb.GetVersionResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetVersion")
@@ -2448,6 +3382,42 @@ func (s *VtctldServer) InitShardPrimaryLocked(
 	return nil
 }
 
+// InvalidateTabletPlanCache is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) InvalidateTabletPlanCache(ctx context.Context, req *vtctldatapb.InvalidateTabletPlanCacheRequest) (resp *vtctldatapb.InvalidateTabletPlanCacheResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.InvalidateTabletPlanCache")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("tablet_alias", topoproto.TabletAliasString(req.TabletAlias))
+	span.Annotate("tables", strings.Join(req.Tables, ","))
+	span.Annotate("all", req.All)
+
+	if !req.All && len(req.Tables) == 0 && len(req.Fingerprints) == 0 {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "must specify tables, fingerprints or all")
+		return nil, err
+	}
+
+	ti, err := s.ts.GetTablet(ctx, req.TabletAlias)
+	if err != nil {
+		err = vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "GetTablet(%v) failed: %v", req.TabletAlias, err)
+		return nil, err
+	}
+
+	r, err := s.tmc.InvalidatePlanCache(ctx, ti.Tablet, &tabletmanagerdatapb.InvalidatePlanCacheRequest{
+		Tables:       req.Tables,
+		Fingerprints: req.Fingerprints,
+		All:          req.All,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.InvalidateTabletPlanCacheResponse{
+		Invalidated: r.Invalidated,
+	}, nil
+}
+
 // MoveTablesCreate is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) MoveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTablesCreateRequest) (resp *vtctldatapb.WorkflowStatusResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.MoveTablesCreate")
@@ -2572,6 +3542,56 @@ func (s *VtctldServer) PlannedReparentShard(ctx context.Context, req *vtctldatap
 	return resp, err
 }
 
+// PurgeBackups is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) PurgeBackups(ctx context.Context, req *vtctldatapb.PurgeBackupsRequest) (resp *vtctldatapb.PurgeBackupsResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.PurgeBackups")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("dry_run", req.DryRun)
+
+	minRetentionTime, _, err := protoutil.DurationFromProto(req.MinRetentionTime)
+	if err != nil {
+		err = vterrors.Wrapf(err, "unable to parse MinRetentionTime into a valid duration")
+		return nil, err
+	}
+
+	policy := mysqlctl.BackupRetentionPolicy{
+		KeepFullBackups:  int(req.KeepFullBackups),
+		MinRetentionTime: minRetentionTime,
+	}
+	if policy.IsZero() {
+		policy = retention.DefaultPolicy()
+	}
+
+	span.Annotate("keep_full_backups", policy.KeepFullBackups)
+	span.Annotate("min_retention_time", policy.MinRetentionTime.String())
+
+	if policy.IsZero() {
+		err = vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no backup retention policy: set KeepFullBackups or MinRetentionTime, or --backup-retention-keep-full-backups or --backup-retention-min-time on the vtctld")
+		return nil, err
+	}
+
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return nil, err
+	}
+	defer bs.Close()
+
+	purged, kept, err := mysqlctl.PurgeBackups(ctx, logutil.NewConsoleLogger(), bs, filepath.Join(req.Keyspace, req.Shard), policy, req.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.PurgeBackupsResponse{
+		PurgedBackups: purged,
+		KeptBackups:   kept,
+	}, nil
+}
+
 // RebuildKeyspaceGraph is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) RebuildKeyspaceGraph(ctx context.Context, req *vtctldatapb.RebuildKeyspaceGraphRequest) (resp *vtctldatapb.RebuildKeyspaceGraphResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.RebuildKeyspaceGraph")
@@ -2634,6 +3654,54 @@ func (s *VtctldServer) RefreshState(ctx context.Context, req *vtctldatapb.Refres
 	return &vtctldatapb.RefreshStateResponse{}, nil
 }
 
+// RefreshStateByFilter is part of the vtctldservicepb.VtctldServer interface.
+func (s *VtctldServer) RefreshStateByFilter(ctx context.Context, req *vtctldatapb.RefreshStateByFilterRequest) (resp *vtctldatapb.RefreshStateByFilterResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.RefreshStateByFilter")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("cells", strings.Join(req.Cells, ","))
+	span.Annotate("tablet_type", topoproto.TabletTypeLString(req.TabletType))
+	span.Annotate("concurrency", req.Concurrency)
+
+	if req.Keyspace == "" {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "RefreshStateByFilter requires a keyspace")
+		return nil, err
+	}
+
+	tablets, err := s.findTabletsByFilter(ctx, req.Keyspace, req.Shard, req.Cells, req.TabletType)
+	if err != nil {
+		return nil, err
+	}
+
+	span.Annotate("num_tablets", len(tablets))
+
+	errs := forEachTablet(ctx, tablets, req.Concurrency, func(ctx context.Context, i int, tablet *topodatapb.Tablet) error {
+		ctx, cancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+		defer cancel()
+
+		return s.tmc.RefreshState(ctx, tablet)
+	})
+
+	resp = &vtctldatapb.RefreshStateByFilterResponse{
+		Results: make([]*vtctldatapb.RefreshStateByFilterResponse_Result, len(tablets)),
+	}
+	for i, tablet := range tablets {
+		resp.Results[i] = &vtctldatapb.RefreshStateByFilterResponse_Result{
+			TabletAlias: tablet.Alias,
+		}
+
+		if errs[i] != nil {
+			resp.Results[i].Error = errs[i].Error()
+		}
+	}
+
+	return resp, nil
+}
+
 // RefreshStateByShard is part of the vtctldservicepb.VtctldServer interface.
 func (s *VtctldServer) RefreshStateByShard(ctx context.Context, req *vtctldatapb.RefreshStateByShardRequest) (resp *vtctldatapb.RefreshStateByShardResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.RefreshStateByShard")
@@ -2680,6 +3748,26 @@ func (s *VtctldServer) RefreshStateByShard(ctx context.Context, req *vtctldatapb
 	}, nil
 }
 
+// ReleaseNamedLock is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ReleaseNamedLock(ctx context.Context, req *vtctldatapb.ReleaseNamedLockRequest) (resp *vtctldatapb.ReleaseNamedLockResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ReleaseNamedLock")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("name", req.Name)
+	span.Annotate("owner", req.Owner)
+
+	ctx, cancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+	defer cancel()
+
+	if err = s.ts.ReleaseNamedLock(ctx, req.Name, req.Owner); err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.ReleaseNamedLockResponse{}, nil
+}
+
 // ReloadSchema is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) ReloadSchema(ctx context.Context, req *vtctldatapb.ReloadSchemaRequest) (resp *vtctldatapb.ReloadSchemaResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.ReloadSchema")
@@ -2825,16 +3913,19 @@ func (s *VtctldServer) RemoveKeyspaceCell(ctx context.Context, req *vtctldatapb.
 	span.Annotate("cell", req.Cell)
 	span.Annotate("force", req.Force)
 	span.Annotate("recursive", req.Recursive)
+	span.Annotate("dry_run", req.DryRun)
 
 	shards, err := s.ts.GetShardNames(ctx, req.Keyspace)
 	if err != nil {
 		return nil, err
 	}
 
+	drl := newDryRunLog(req.DryRun)
+
 	// Remove all the shards, serially. Stop immediately if any fail.
 	for _, shard := range shards {
 		log.Infof("Removing cell %v from shard %v/%v", req.Cell, req.Keyspace, shard)
-		if err2 := removeShardCell(ctx, s.ts, req.Cell, req.Keyspace, shard, req.Recursive, req.Force); err2 != nil {
+		if err2 := removeShardCell(ctx, s.ts, req.Cell, req.Keyspace, shard, req.Recursive, req.Force, drl); err2 != nil {
 			err = fmt.Errorf("cannot remove cell %v from shard %v/%v: %w", req.Cell, req.Keyspace, shard, err2)
 			return nil, err
 		}
@@ -2842,6 +3933,13 @@ func (s *VtctldServer) RemoveKeyspaceCell(ctx context.Context, req *vtctldatapb.
 
 	// Last, remove the SrvKeyspace object.
 	log.Infof("Removing cell %v keyspace %v SrvKeyspace object", req.Cell, req.Keyspace)
+	if drl.enabled() {
+		drl.add("Delete SrvKeyspace for keyspace %v in cell %v", req.Keyspace, req.Cell)
+		return &vtctldatapb.RemoveKeyspaceCellResponse{
+			DryRunResults: drl.Results(),
+		}, nil
+	}
+
 	if err = s.ts.DeleteSrvKeyspace(ctx, req.Cell, req.Keyspace); err != nil {
 		err = fmt.Errorf("cannot delete SrvKeyspace from cell %v for keyspace %v: %w", req.Cell, req.Keyspace, err)
 		return nil, err
@@ -2855,19 +3953,131 @@ func (s *VtctldServer) RemoveShardCell(ctx context.Context, req *vtctldatapb.Rem
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.RemoveShardCell")
 	defer span.Finish()
 
-	defer panicHandler(&err)
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.ShardName)
+	span.Annotate("cell", req.Cell)
+	span.Annotate("force", req.Force)
+	span.Annotate("recursive", req.Recursive)
+	span.Annotate("dry_run", req.DryRun)
+
+	drl := newDryRunLog(req.DryRun)
+	if err = removeShardCell(ctx, s.ts, req.Cell, req.Keyspace, req.ShardName, req.Recursive, req.Force, drl); err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.RemoveShardCellResponse{
+		DryRunResults: drl.Results(),
+	}, nil
+}
+
+// RemoveTabletTag is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) RemoveTabletTag(ctx context.Context, req *vtctldatapb.RemoveTabletTagRequest) (resp *vtctldatapb.RemoveTabletTagResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.RemoveTabletTag")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("tablet_alias", topoproto.TabletAliasString(req.TabletAlias))
+	span.Annotate("keys", strings.Join(req.Keys, ","))
+
+	if req.TabletAlias == nil {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "TabletAlias is required")
+		return nil, err
+	}
+	if len(req.Keys) == 0 {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "at least one tag key is required")
+		return nil, err
+	}
+
+	tablet, err := s.updateTabletTags(ctx, req.TabletAlias, func(tags map[string]string) bool {
+		changed := false
+		for _, key := range req.Keys {
+			if _, ok := tags[key]; ok {
+				delete(tags, key)
+				changed = true
+			}
+		}
+
+		return changed
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.RemoveTabletTagResponse{Tablet: tablet}, nil
+}
+
+// RenewNamedLock is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) RenewNamedLock(ctx context.Context, req *vtctldatapb.RenewNamedLockRequest) (resp *vtctldatapb.RenewNamedLockResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.RenewNamedLock")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("name", req.Name)
+	span.Annotate("owner", req.Owner)
+
+	ttl, _, err := protoutil.DurationFromProto(req.Ttl)
+	if err != nil {
+		err = vterrors.Wrapf(err, "unable to parse Ttl into a valid duration")
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+	defer cancel()
+
+	lock, err := s.ts.RenewNamedLock(ctx, req.Name, req.Owner, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.RenewNamedLockResponse{Lock: namedLockToProto(lock)}, nil
+}
+
+// ReparentPreflight is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ReparentPreflight(ctx context.Context, req *vtctldatapb.ReparentPreflightRequest) (resp *vtctldatapb.ReparentPreflightResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ReparentPreflight")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	waitReplicasTimeout, ok, err := protoutil.DurationFromProto(req.WaitReplicasTimeout)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		waitReplicasTimeout = time.Second * 30
+	}
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("wait_replicas_timeout_sec", waitReplicasTimeout.Seconds())
+
+	if req.AvoidPrimary != nil {
+		span.Annotate("avoid_primary_alias", topoproto.TabletAliasString(req.AvoidPrimary))
+	}
 
-	span.Annotate("keyspace", req.Keyspace)
-	span.Annotate("shard", req.ShardName)
-	span.Annotate("cell", req.Cell)
-	span.Annotate("force", req.Force)
-	span.Annotate("recursive", req.Recursive)
+	if req.NewPrimary != nil {
+		span.Annotate("new_primary_alias", topoproto.TabletAliasString(req.NewPrimary))
+	}
 
-	if err = removeShardCell(ctx, s.ts, req.Cell, req.Keyspace, req.ShardName, req.Recursive, req.Force); err != nil {
+	resp, err = reparentutil.NewPlannedReparenter(s.ts, s.tmc, nil).Preflight(ctx,
+		req.Keyspace,
+		req.Shard,
+		reparentutil.PlannedReparentOptions{
+			AvoidPrimaryAlias:   req.AvoidPrimary,
+			NewPrimaryAlias:     req.NewPrimary,
+			WaitReplicasTimeout: waitReplicasTimeout,
+		},
+	)
+	if err != nil {
 		return nil, err
 	}
 
-	return &vtctldatapb.RemoveShardCellResponse{}, nil
+	span.Annotate("ok", resp.Ok)
+
+	return resp, nil
 }
 
 // ReparentTablet is part of the vtctldservicepb.VtctldServer interface.
@@ -2966,8 +4176,9 @@ func (s *VtctldServer) RestoreFromBackup(req *vtctldatapb.RestoreFromBackupReque
 		RestoreToPos:       req.RestoreToPos,
 		RestoreToTimestamp: req.RestoreToTimestamp,
 		DryRun:             req.DryRun,
+		Concurrency:        int64(req.Concurrency),
 	}
-	logStream, err := s.tmc.RestoreFromBackup(ctx, ti.Tablet, r)
+	restoreStream, err := s.tmc.RestoreFromBackup(ctx, ti.Tablet, r)
 	if err != nil {
 		return err
 	}
@@ -2975,16 +4186,19 @@ func (s *VtctldServer) RestoreFromBackup(req *vtctldatapb.RestoreFromBackupReque
 	logger := logutil.NewConsoleLogger()
 
 	for {
-		var event *logutilpb.Event
-		event, err = logStream.Recv()
+		var tmResp *tabletmanagerdatapb.RestoreFromBackupResponse
+		tmResp, err = restoreStream.Recv()
 		switch err {
 		case nil:
-			logutil.LogEvent(logger, event)
+			if tmResp.Event != nil {
+				logutil.LogEvent(logger, tmResp.Event)
+			}
 			resp := &vtctldatapb.RestoreFromBackupResponse{
 				TabletAlias: req.TabletAlias,
 				Keyspace:    ti.Keyspace,
 				Shard:       ti.Shard,
-				Event:       event,
+				Event:       tmResp.Event,
+				Progress:    tmResp.Progress,
 			}
 			if err = stream.Send(resp); err != nil {
 				logger.Errorf("failed to send stream response %+v: %v", resp, err)
@@ -3071,6 +4285,71 @@ func (s *VtctldServer) RunHealthCheck(ctx context.Context, req *vtctldatapb.RunH
 	return &vtctldatapb.RunHealthCheckResponse{}, nil
 }
 
+// ScheduleCommand is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ScheduleCommand(ctx context.Context, req *vtctldatapb.ScheduleCommandRequest) (resp *vtctldatapb.ScheduleCommandResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ScheduleCommand")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("args", strings.Join(req.Args, " "))
+
+	if len(req.Args) == 0 {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "no command to schedule")
+		return nil, err
+	}
+
+	now := time.Now()
+
+	var runAt time.Time
+	switch {
+	case (req.RunAt == nil) == (req.RunAfter == nil):
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "exactly one of RunAt and RunAfter must be set")
+		return nil, err
+	case req.RunAt != nil:
+		runAt = protoutil.TimeFromProto(req.RunAt)
+		if runAt.Before(now) {
+			err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "RunAt %s is in the past", runAt.UTC().Format(time.RFC3339))
+			return nil, err
+		}
+	default:
+		runAfter, ok, derr := protoutil.DurationFromProto(req.RunAfter)
+		if derr != nil || !ok || runAfter < 0 {
+			err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid RunAfter %v", req.RunAfter)
+			return nil, err
+		}
+
+		runAt = now.Add(runAfter)
+	}
+
+	if timeout, _, derr := protoutil.DurationFromProto(req.ActionTimeout); derr != nil || timeout < 0 {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid ActionTimeout %v", req.ActionTimeout)
+		return nil, err
+	}
+
+	cmd := &vtctldatapb.ScheduledCommand{
+		Id:            uuid.NewString(),
+		Args:          req.Args,
+		RunAt:         protoutil.TimeToProto(runAt),
+		ActionTimeout: req.ActionTimeout,
+		State:         vtctldatapb.ScheduledCommand_PENDING,
+		CreateTime:    protoutil.TimeToProto(now),
+		CreatedBy:     audit.CallerFromContext(ctx).String(),
+	}
+
+	span.Annotate("id", cmd.Id)
+
+	if err = s.ts.CreateScheduledCommand(ctx, cmd); err != nil {
+		return nil, err
+	}
+
+	log.Infof("%s scheduled command %s to run %v at %s", cmd.CreatedBy, cmd.Id, cmd.Args, runAt.UTC().Format(time.RFC3339))
+
+	return &vtctldatapb.ScheduleCommandResponse{
+		ScheduledCommand: cmd,
+	}, nil
+}
+
 // SetKeyspaceDurabilityPolicy is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) SetKeyspaceDurabilityPolicy(ctx context.Context, req *vtctldatapb.SetKeyspaceDurabilityPolicyRequest) (resp *vtctldatapb.SetKeyspaceDurabilityPolicyResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.SetKeyspaceDurabilityPolicy")
@@ -3604,6 +4883,61 @@ func (s *VtctldServer) SourceShardDelete(ctx context.Context, req *vtctldatapb.S
 	return resp, err
 }
 
+// StartMaintenance is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) StartMaintenance(ctx context.Context, req *vtctldatapb.StartMaintenanceRequest) (resp *vtctldatapb.StartMaintenanceResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.StartMaintenance")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("cell", req.Cell)
+
+	ttl, ok, err := protoutil.DurationFromProto(req.Ttl)
+	if err != nil {
+		err = vterrors.Wrapf(err, "unable to parse Ttl into a valid duration")
+		return nil, err
+	}
+	if !ok {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Ttl is required")
+		return nil, err
+	}
+
+	// The maintenance of a keyspace, a shard or a cell that doesn't exist
+	// is most likely a typo, which would silently leave VTOrc running the
+	// recoveries.
+	switch {
+	case req.Shard != "":
+		_, err = s.ts.GetShard(ctx, req.Keyspace, req.Shard)
+	case req.Keyspace != "":
+		_, err = s.ts.GetKeyspace(ctx, req.Keyspace)
+	case req.Cell != "":
+		_, err = s.ts.GetCellInfo(ctx, req.Cell, false /* strongRead */)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	window, err := s.ts.StartMaintenance(ctx, req.Keyspace, req.Shard, req.Cell, req.Reason, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.StartMaintenanceResponse{Window: maintenanceWindowToProto(window)}, nil
+}
+
+func maintenanceWindowToProto(window *topo.MaintenanceWindow) *vtctldatapb.MaintenanceWindow {
+	return &vtctldatapb.MaintenanceWindow{
+		Keyspace:  window.Keyspace,
+		Shard:     window.Shard,
+		Cell:      window.Cell,
+		Reason:    window.Reason,
+		StartedAt: protoutil.TimeToProto(window.Started),
+		ExpiresAt: protoutil.TimeToProto(window.Expires),
+	}
+}
+
 // StartReplication is part of the vtctldservicepb.VtctldServer interface.
 func (s *VtctldServer) StartReplication(ctx context.Context, req *vtctldatapb.StartReplicationRequest) (resp *vtctldatapb.StartReplicationResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.StartReplication")
@@ -3669,6 +5003,24 @@ func (s *VtctldServer) StartReplication(ctx context.Context, req *vtctldatapb.St
 	return &vtctldatapb.StartReplicationResponse{}, nil
 }
 
+// StopMaintenance is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) StopMaintenance(ctx context.Context, req *vtctldatapb.StopMaintenanceRequest) (resp *vtctldatapb.StopMaintenanceResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.StopMaintenance")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("cell", req.Cell)
+
+	if err = s.ts.StopMaintenance(ctx, req.Keyspace, req.Shard, req.Cell); err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.StopMaintenanceResponse{}, nil
+}
+
 // StopReplication is part of the vtctldservicepb.VtctldServer interface.
 func (s *VtctldServer) StopReplication(ctx context.Context, req *vtctldatapb.StopReplicationRequest) (resp *vtctldatapb.StopReplicationResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.StopReplication")
@@ -3858,6 +5210,56 @@ func (s *VtctldServer) UpdateCellsAlias(ctx context.Context, req *vtctldatapb.Up
 	}, nil
 }
 
+// UpgradeMysql is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) UpgradeMysql(req *vtctldatapb.UpgradeMysqlRequest, stream vtctlservicepb.Vtctld_UpgradeMysqlServer) (err error) {
+	span, ctx := trace.NewSpan(stream.Context(), "VtctldServer.UpgradeMysql")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("tablet_alias", topoproto.TabletAliasString(req.TabletAlias))
+	span.Annotate("mysql_root", req.MysqlRoot)
+	span.Annotate("expected_version", req.ExpectedVersion)
+	span.Annotate("allow_primary", req.AllowPrimary)
+
+	ti, err := s.ts.GetTablet(ctx, req.TabletAlias)
+	if err != nil {
+		return err
+	}
+
+	span.Annotate("keyspace", ti.Keyspace)
+	span.Annotate("shard", ti.Shard)
+
+	r := &tabletmanagerdatapb.UpgradeMysqlRequest{
+		MysqlRoot:       req.MysqlRoot,
+		ExpectedVersion: req.ExpectedVersion,
+		AllowPrimary:    req.AllowPrimary,
+	}
+	logStream, err := s.tmc.UpgradeMysql(ctx, ti.Tablet, r)
+	if err != nil {
+		return err
+	}
+
+	logger := logutil.NewConsoleLogger()
+	for {
+		event, err := logStream.Recv()
+		switch err {
+		case nil:
+			logutil.LogEvent(logger, event)
+			resp := &vtctldatapb.UpgradeMysqlResponse{
+				Event: event,
+			}
+			if err := stream.Send(resp); err != nil {
+				logger.Errorf("failed to send stream response %+v: %v", resp, err)
+			}
+		case io.EOF:
+			return nil
+		default:
+			return err
+		}
+	}
+}
+
 // Validate is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) Validate(ctx context.Context, req *vtctldatapb.ValidateRequest) (resp *vtctldatapb.ValidateResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.Validate")
@@ -3992,6 +5394,76 @@ func (s *VtctldServer) Validate(ctx context.Context, req *vtctldatapb.ValidateRe
 	return resp, err
 }
 
+// ValidateBackup is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ValidateBackup(ctx context.Context, req *vtctldatapb.ValidateBackupRequest) (resp *vtctldatapb.ValidateBackupResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ValidateBackup")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("backup_name", req.BackupName)
+
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return nil, err
+	}
+	defer bs.Close()
+
+	bhs, err := bs.ListBackups(ctx, filepath.Join(req.Keyspace, req.Shard))
+	if err != nil {
+		return nil, err
+	}
+
+	logger := logutil.NewConsoleLogger()
+	var (
+		bh       backupstorage.BackupHandle
+		manifest *mysqlctl.BackupManifest
+	)
+	if req.BackupName == "" {
+		bh, manifest, err = mysqlctl.FindLatestSuccessfulBackup(ctx, logger, bhs, "")
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "no complete backup of %v/%v", req.Keyspace, req.Shard)
+		}
+	} else {
+		for _, h := range bhs {
+			if h.Name() == req.BackupName {
+				bh = h
+				break
+			}
+		}
+		if bh == nil {
+			return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "backup %v of %v/%v not found", req.BackupName, req.Keyspace, req.Shard)
+		}
+		if manifest, err = mysqlctl.GetBackupManifest(ctx, bh); err != nil {
+			return nil, err
+		}
+	}
+
+	filesChecked, results, err := mysqlctl.ValidateBackupFiles(ctx, logger, bh)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &vtctldatapb.ValidateBackupResponse{
+		BackupName:   bh.Name(),
+		Results:      results,
+		FilesChecked: int64(filesChecked),
+	}
+	if v := manifest.Verification; v != nil {
+		resp.Verification = mysqlctlproto.BackupVerificationToProto(v)
+		switch {
+		case v.Error != "":
+			resp.Results = append(resp.Results, fmt.Sprintf("restore drill at %v failed: %v", v.Time, v.Error))
+		case len(v.Mismatches) > 0:
+			resp.Results = append(resp.Results, fmt.Sprintf("restore drill at %v found tables which differ from replica %v: %v", v.Time, v.Replica, strings.Join(v.Mismatches, ", ")))
+		}
+	}
+
+	return resp, nil
+}
+
 // ValidateKeyspace is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) ValidateKeyspace(ctx context.Context, req *vtctldatapb.ValidateKeyspaceRequest) (resp *vtctldatapb.ValidateKeyspaceResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.ValidateKeyspace")
@@ -4045,6 +5517,112 @@ func (s *VtctldServer) ValidateKeyspace(ctx context.Context, req *vtctldatapb.Va
 	return resp, err
 }
 
+// ValidatePermissionsKeyspace is part of the vtctlservicepb.VtctldServer
+// interface.
+func (s *VtctldServer) ValidatePermissionsKeyspace(req *vtctldatapb.ValidatePermissionsKeyspaceRequest, stream vtctlservicepb.Vtctld_ValidatePermissionsKeyspaceServer) (err error) {
+	span, ctx := trace.NewSpan(stream.Context(), "VtctldServer.ValidatePermissionsKeyspace")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("concurrency", req.Concurrency)
+
+	keyspace := req.Keyspace
+	resp := &vtctldatapb.ValidatePermissionsKeyspaceResponse{
+		Results: []string{},
+	}
+
+	shards, err := s.ts.GetShardNames(ctx, keyspace)
+	if err != nil {
+		resp.Results = append(resp.Results, fmt.Sprintf("TopologyServer.GetShardNames(%v) failed: %v", keyspace, err))
+		return stream.Send(resp)
+	}
+	if len(shards) == 0 {
+		resp.Results = append(resp.Results, fmt.Sprintf("no shards in keyspace %v", keyspace))
+		return stream.Send(resp)
+	}
+
+	sort.Strings(shards)
+	resp.ResultsByShard = make(map[string]*vtctldatapb.ValidateShardResponse, len(shards))
+	for _, shard := range shards {
+		resp.ResultsByShard[shard] = &vtctldatapb.ValidateShardResponse{
+			Results: []string{},
+		}
+	}
+
+	recordResult := func(shard string, result string) {
+		resp.ResultsByShard[shard].Results = append(resp.ResultsByShard[shard].Results, result)
+		resp.Results = append(resp.Results, result)
+	}
+
+	getPermissions := func(ctx context.Context, alias *topodatapb.TabletAlias) (*tabletmanagerdatapb.Permissions, error) {
+		ti, err := s.ts.GetTablet(ctx, alias)
+		if err != nil {
+			return nil, fmt.Errorf("GetTablet(%v) failed: %v", topoproto.TabletAliasString(alias), err)
+		}
+
+		permissions, err := s.tmc.GetPermissions(ctx, ti.Tablet)
+		if err != nil {
+			return nil, fmt.Errorf("GetPermissions(%v) failed: %v", topoproto.TabletAliasString(alias), err)
+		}
+
+		return permissions, nil
+	}
+
+	validationShards := s.getValidationShards(ctx, keyspace, shards)
+
+	var reference *validationShard
+	for _, vs := range validationShards {
+		switch {
+		case vs.primary == nil && vs.err == "":
+			recordResult(vs.name, fmt.Sprintf("no primary in shard %v/%v", keyspace, vs.name))
+		case vs.err != "":
+			recordResult(vs.name, vs.err)
+		}
+
+		if reference == nil && vs.primary != nil {
+			reference = vs
+		}
+	}
+
+	if reference == nil {
+		return stream.Send(resp)
+	}
+
+	referencePermissions, err := getPermissions(ctx, reference.primary)
+	if err != nil {
+		recordResult(reference.name, err.Error())
+		return stream.Send(resp)
+	}
+
+	referenceAlias := topoproto.TabletAliasString(reference.primary)
+	err = validateTablets(ctx, validationShards, reference.primary, req.Concurrency,
+		func(ctx context.Context, alias *topodatapb.TabletAlias) []string {
+			permissions, err := getPermissions(ctx, alias)
+			if err != nil {
+				return []string{err.Error()}
+			}
+
+			rec := concurrency.AllErrorRecorder{}
+			tmutils.DiffPermissions(referenceAlias, referencePermissions, topoproto.TabletAliasString(alias), permissions, &rec)
+			return rec.ErrorStrings()
+		},
+		func(progress *vtctldatapb.ValidateProgress) error {
+			for _, result := range progress.Results {
+				recordResult(progress.Shard, result)
+			}
+
+			return stream.Send(&vtctldatapb.ValidatePermissionsKeyspaceResponse{Progress: progress})
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return stream.Send(resp)
+}
+
 // ValidateSchemaKeyspace is a part of the vtctlservicepb.VtctldServer interface.
 // It will diff the schema from all the tablets in the keyspace.
 func (s *VtctldServer) ValidateSchemaKeyspace(ctx context.Context, req *vtctldatapb.ValidateSchemaKeyspaceRequest) (resp *vtctldatapb.ValidateSchemaKeyspaceResponse, err error) {
@@ -4054,17 +5632,50 @@ func (s *VtctldServer) ValidateSchemaKeyspace(ctx context.Context, req *vtctldat
 	defer panicHandler(&err)
 
 	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("concurrency", req.Concurrency)
+
+	resp, err = s.validateSchemaKeyspace(ctx, req, nil)
+	return resp, err
+}
+
+// ValidateSchemaKeyspaceStream is a part of the vtctlservicepb.VtctldServer
+// interface.
+func (s *VtctldServer) ValidateSchemaKeyspaceStream(req *vtctldatapb.ValidateSchemaKeyspaceRequest, stream vtctlservicepb.Vtctld_ValidateSchemaKeyspaceStreamServer) (err error) {
+	span, ctx := trace.NewSpan(stream.Context(), "VtctldServer.ValidateSchemaKeyspaceStream")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("concurrency", req.Concurrency)
+
+	resp, err := s.validateSchemaKeyspace(ctx, req, func(progress *vtctldatapb.ValidateProgress) error {
+		return stream.Send(&vtctldatapb.ValidateSchemaKeyspaceStreamResponse{Progress: progress})
+	})
+	if err != nil {
+		return err
+	}
+
+	return stream.Send(&vtctldatapb.ValidateSchemaKeyspaceStreamResponse{
+		Results:        resp.Results,
+		ResultsByShard: resp.ResultsByShard,
+	})
+}
+
+// validateSchemaKeyspace diffs the schema of all the tablets in the keyspace
+// with the schema of the primary of its first shard. If report is not nil, it
+// is called with the outcome of each tablet as soon as it is validated.
+func (s *VtctldServer) validateSchemaKeyspace(ctx context.Context, req *vtctldatapb.ValidateSchemaKeyspaceRequest, report func(progress *vtctldatapb.ValidateProgress) error) (*vtctldatapb.ValidateSchemaKeyspaceResponse, error) {
 	keyspace := req.Keyspace
 
-	resp = &vtctldatapb.ValidateSchemaKeyspaceResponse{
+	resp := &vtctldatapb.ValidateSchemaKeyspaceResponse{
 		Results: []string{},
 	}
 
 	shards, err := s.ts.GetShardNames(ctx, keyspace)
 	if err != nil {
 		resp.Results = append(resp.Results, fmt.Sprintf("TopologyServer.GetShardNames(%v) failed: %v", req.Keyspace, err))
-		err = nil
-		return resp, err
+		return resp, nil
 	}
 
 	resp.ResultsByShard = make(map[string]*vtctldatapb.ValidateShardResponse, len(shards))
@@ -4077,14 +5688,13 @@ func (s *VtctldServer) ValidateSchemaKeyspace(ctx context.Context, req *vtctldat
 	}
 
 	if req.IncludeVschema {
-		results, err2 := s.ValidateVSchema(ctx, &vtctldatapb.ValidateVSchemaRequest{
+		results, err := s.ValidateVSchema(ctx, &vtctldatapb.ValidateVSchemaRequest{
 			Keyspace:      keyspace,
 			Shards:        shards,
 			ExcludeTables: req.ExcludeTables,
 			IncludeViews:  req.IncludeViews,
 		})
-		if err2 != nil {
-			err = err2
+		if err != nil {
 			return nil, err
 		}
 
@@ -4093,96 +5703,74 @@ func (s *VtctldServer) ValidateSchemaKeyspace(ctx context.Context, req *vtctldat
 			for shard, shardResults := range resp.ResultsByShard {
 				resp.ResultsByShard[shard].Results = append(resp.ResultsByShard[shard].Results, shardResults.Results...)
 			}
-			return resp, err
+			return resp, nil
 		}
 	}
 
 	sort.Strings(shards)
 
-	var (
-		referenceSchema *tabletmanagerdatapb.SchemaDefinition
-		referenceAlias  *topodatapb.TabletAlias
-		m               sync.Mutex
-		wg              sync.WaitGroup
-	)
-
-	r := &tabletmanagerdatapb.GetSchemaRequest{ExcludeTables: req.ExcludeTables, IncludeViews: req.IncludeViews}
-	for _, shard := range shards[0:] {
-		wg.Add(1)
-		go func(shard string) {
-			defer wg.Done()
-
-			si, err := s.ts.GetShard(ctx, keyspace, shard)
+	recordResult := func(shard string, result string) {
+		resp.ResultsByShard[shard].Results = append(resp.ResultsByShard[shard].Results, result)
+		resp.Results = append(resp.Results, result)
+	}
 
-			m.Lock()
-			defer m.Unlock()
+	validationShards := s.getValidationShards(ctx, keyspace, shards)
 
-			if err != nil {
-				errMessage := fmt.Sprintf("GetShard(%v, %v) failed: %v", keyspace, shard, err)
-				resp.ResultsByShard[shard].Results = append(resp.ResultsByShard[shard].Results, errMessage)
-				resp.Results = append(resp.Results, errMessage)
-				return
+	var reference *validationShard
+	for _, vs := range validationShards {
+		switch {
+		case vs.primary == nil && vs.err == "":
+			if !req.SkipNoPrimary {
+				recordResult(vs.name, fmt.Sprintf("no primary in shard %v/%v", keyspace, vs.name))
 			}
+		case vs.err != "":
+			recordResult(vs.name, vs.err)
+		}
 
-			if !si.HasPrimary() {
-				if !req.SkipNoPrimary {
-					errMessage := fmt.Sprintf("no primary in shard %v/%v", keyspace, shard)
-					resp.ResultsByShard[shard].Results = append(resp.ResultsByShard[shard].Results, errMessage)
-					resp.Results = append(resp.Results, errMessage)
-				}
-				return
-			}
+		if reference == nil && vs.primary != nil {
+			reference = vs
+		}
+	}
 
-			if referenceSchema == nil {
-				referenceAlias = si.PrimaryAlias
-				referenceSchema, err = schematools.GetSchema(ctx, s.ts, s.tmc, referenceAlias, r)
-				if err != nil {
-					return
-				}
-			}
+	if reference == nil {
+		return resp, nil
+	}
+
+	r := &tabletmanagerdatapb.GetSchemaRequest{ExcludeTables: req.ExcludeTables, IncludeViews: req.IncludeViews}
+	referenceSchema, err := schematools.GetSchema(ctx, s.ts, s.tmc, reference.primary, r)
+	if err != nil {
+		recordResult(reference.name, fmt.Sprintf("GetSchema(%v, nil, %v, %v) failed: %v", reference.primary, req.ExcludeTables, req.IncludeViews, err))
+		return resp, nil
+	}
 
-			aliases, err := s.ts.FindAllTabletAliasesInShard(ctx, keyspace, shard)
+	referenceAlias := topoproto.TabletAliasString(reference.primary)
+	err = validateTablets(ctx, validationShards, reference.primary, req.Concurrency,
+		func(ctx context.Context, alias *topodatapb.TabletAlias) []string {
+			replicaSchema, err := schematools.GetSchema(ctx, s.ts, s.tmc, alias, r)
 			if err != nil {
-				errMessage := fmt.Sprintf("FindAllTabletAliasesInShard(%v, %v) failed: %v", keyspace, shard, err)
-				resp.ResultsByShard[shard].Results = append(resp.ResultsByShard[shard].Results, errMessage)
-				resp.Results = append(resp.Results, errMessage)
-				return
+				return []string{fmt.Sprintf("GetSchema(%v, nil, %v, %v) failed: %v", alias, req.ExcludeTables, req.IncludeViews, err)}
 			}
 
-			aliasWg := sync.WaitGroup{}
-			aliasErrs := concurrency.AllErrorRecorder{}
-
-			for _, alias := range aliases {
-				if referenceAlias == alias {
-					continue
-				}
-				aliasWg.Add(1)
-				go func(alias *topodatapb.TabletAlias) {
-					defer aliasWg.Done()
-					replicaSchema, err := schematools.GetSchema(ctx, s.ts, s.tmc, alias, r)
-					if err != nil {
-						aliasErrs.RecordError(fmt.Errorf("GetSchema(%v, nil, %v, %v) failed: %v", alias, req.ExcludeTables, req.IncludeViews, err))
-						return
-					}
-
-					tmutils.DiffSchema(topoproto.TabletAliasString(referenceAlias), referenceSchema, topoproto.TabletAliasString(alias), replicaSchema, &aliasErrs)
-				}(alias)
+			rec := concurrency.AllErrorRecorder{}
+			tmutils.DiffSchema(referenceAlias, referenceSchema, topoproto.TabletAliasString(alias), replicaSchema, &rec)
+			return rec.ErrorStrings()
+		},
+		func(progress *vtctldatapb.ValidateProgress) error {
+			for _, result := range progress.Results {
+				recordResult(progress.Shard, result)
 			}
-			aliasWg.Wait()
 
-			if aliasErrs.HasErrors() {
-				for _, err := range aliasErrs.Errors {
-					errMessage := err.Error()
-					resp.ResultsByShard[shard].Results = append(resp.ResultsByShard[shard].Results, errMessage)
-					resp.Results = append(resp.Results, errMessage)
-				}
+			if report != nil {
+				return report(progress)
 			}
-		}(shard)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
 	}
 
-	wg.Wait()
-
-	return resp, err
+	return resp, nil
 }
 
 // ValidateShard is part of the vtctlservicepb.VtctldServer interface.
@@ -4613,6 +6201,111 @@ func (s *VtctldServer) ValidateVSchema(ctx context.Context, req *vtctldatapb.Val
 	return resp, err
 }
 
+// WatchKeyspace is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) WatchKeyspace(req *vtctldatapb.WatchKeyspaceRequest, stream vtctlservicepb.Vtctld_WatchKeyspaceServer) (err error) {
+	span, ctx := trace.NewSpan(stream.Context(), "VtctldServer.WatchKeyspace")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+
+	current, changes, err := s.ts.WatchKeyspace(ctx, req.Keyspace)
+	if err != nil {
+		return err
+	}
+
+	if err := stream.Send(&vtctldatapb.WatchKeyspaceResponse{Keyspace: current.Value}); err != nil {
+		return err
+	}
+
+	for change := range changes {
+		if change.Err != nil {
+			return watchError(change.Err)
+		}
+
+		if err := stream.Send(&vtctldatapb.WatchKeyspaceResponse{Keyspace: change.Value}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WatchShard is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) WatchShard(req *vtctldatapb.WatchShardRequest, stream vtctlservicepb.Vtctld_WatchShardServer) (err error) {
+	span, ctx := trace.NewSpan(stream.Context(), "VtctldServer.WatchShard")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+
+	current, changes, err := s.ts.WatchShard(ctx, req.Keyspace, req.Shard)
+	if err != nil {
+		return err
+	}
+
+	if err := stream.Send(&vtctldatapb.WatchShardResponse{Shard: current.Value}); err != nil {
+		return err
+	}
+
+	for change := range changes {
+		if change.Err != nil {
+			return watchError(change.Err)
+		}
+
+		if err := stream.Send(&vtctldatapb.WatchShardResponse{Shard: change.Value}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WatchSrvVSchema is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) WatchSrvVSchema(req *vtctldatapb.WatchSrvVSchemaRequest, stream vtctlservicepb.Vtctld_WatchSrvVSchemaServer) (err error) {
+	span, ctx := trace.NewSpan(stream.Context(), "VtctldServer.WatchSrvVSchema")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("cell", req.Cell)
+
+	current, changes, err := s.ts.WatchSrvVSchema(ctx, req.Cell)
+	if err != nil {
+		return err
+	}
+
+	if err := stream.Send(&vtctldatapb.WatchSrvVSchemaResponse{SrvVSchema: current.Value}); err != nil {
+		return err
+	}
+
+	for change := range changes {
+		if change.Err != nil {
+			return watchError(change.Err)
+		}
+
+		if err := stream.Send(&vtctldatapb.WatchSrvVSchemaResponse{SrvVSchema: change.Value}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// watchError returns the error a Watch* RPC ends with, given the error that
+// ended the underlying topo watch. An interrupted watch means the client went
+// away, so it is not reported as a failure.
+func watchError(err error) error {
+	if topo.IsErrType(err, topo.Interrupted) {
+		return nil
+	}
+
+	return err
+}
+
 // WorkflowDelete is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) WorkflowDelete(ctx context.Context, req *vtctldatapb.WorkflowDeleteRequest) (resp *vtctldatapb.WorkflowDeleteResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.WorkflowDelete")
@@ -4771,6 +6464,18 @@ func GetVersionFunc() func(string) (string, error) {
 }
 
 // helper method to asynchronously get and diff a version
+// shardPrimary returns the primary tablet of a shard.
+func (s *VtctldServer) shardPrimary(ctx context.Context, keyspace, shard string) (*topo.TabletInfo, error) {
+	si, err := s.ts.GetShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+	if si.PrimaryAlias == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "shard %s/%s has no primary", keyspace, shard)
+	}
+	return s.ts.GetTablet(ctx, si.PrimaryAlias)
+}
+
 func (s *VtctldServer) diffVersion(ctx context.Context, primaryVersion string, primaryAlias *topodatapb.TabletAlias, alias *topodatapb.TabletAlias, wg *sync.WaitGroup, er concurrency.ErrorRecorder) {
 	defer wg.Done()
 	log.Infof("Gathering version for %v", topoproto.TabletAliasString(alias))
@@ -4786,3 +6491,212 @@ func (s *VtctldServer) diffVersion(ctx context.Context, primaryVersion string, p
 		er.RecordError(fmt.Errorf("primary %v version %v is different than replica %v version %v", topoproto.TabletAliasString(primaryAlias), primaryVersion, topoproto.TabletAliasString(alias), replicaVersion))
 	}
 }
+
+// findTabletsByFilter returns the tablets of a keyspace, optionally limited to
+// a shard, to the given cells and to the given tablet type, ordered by alias.
+// Unlike GetTablets, it fails if any of the cells cannot be read, so that bulk
+// operations do not silently skip tablets.
+func (s *VtctldServer) findTabletsByFilter(ctx context.Context, keyspace string, shard string, cells []string, tabletType topodatapb.TabletType) ([]*topodatapb.Tablet, error) {
+	ctx, cancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+	defer cancel()
+
+	shards := []string{shard}
+	if shard == "" {
+		var err error
+		shards, err = s.ts.GetShardNames(ctx, keyspace)
+		if err != nil {
+			return nil, fmt.Errorf("GetShardNames(%s) failed: %w", keyspace, err)
+		}
+	}
+
+	var tablets []*topodatapb.Tablet
+	for _, shard := range shards {
+		tabletMap, err := s.ts.GetTabletMapForShardByCell(ctx, keyspace, shard, cells)
+		if err != nil {
+			return nil, fmt.Errorf("GetTabletMapForShardByCell(%s, %s) failed: %w", keyspace, shard, err)
+		}
+
+		for _, ti := range tabletMap {
+			if tabletType != topodatapb.TabletType_UNKNOWN && ti.Type != tabletType {
+				continue
+			}
+
+			tablets = append(tablets, ti.Tablet)
+		}
+	}
+
+	sort.Slice(tablets, func(i, j int) bool {
+		return topoproto.TabletAliasString(tablets[i].Alias) < topoproto.TabletAliasString(tablets[j].Alias)
+	})
+
+	return tablets, nil
+}
+
+// forEachTablet calls f on each tablet concurrently, running at most
+// concurrency calls at a time if concurrency is non-zero. It returns the error
+// of each call, indexed like tablets.
+func forEachTablet(ctx context.Context, tablets []*topodatapb.Tablet, concurrency uint32, f func(ctx context.Context, i int, tablet *topodatapb.Tablet) error) []error {
+	var (
+		wg   sync.WaitGroup
+		sema *semaphore.Weighted
+		errs = make([]error, len(tablets))
+	)
+
+	if concurrency > 0 {
+		sema = semaphore.NewWeighted(int64(concurrency))
+	}
+
+	for i, tablet := range tablets {
+		wg.Add(1)
+		go func(i int, tablet *topodatapb.Tablet) {
+			defer wg.Done()
+
+			if sema != nil {
+				if err := sema.Acquire(ctx, 1); err != nil {
+					errs[i] = err
+					return
+				}
+				defer sema.Release(1)
+			}
+
+			errs[i] = f(ctx, i, tablet)
+		}(i, tablet)
+	}
+
+	wg.Wait()
+	return errs
+}
+
+// validationShard is a shard whose tablets are validated by
+// ValidatePermissionsKeyspace or ValidateSchemaKeyspace.
+type validationShard struct {
+	name    string
+	primary *topodatapb.TabletAlias
+	aliases []*topodatapb.TabletAlias
+	// err describes why the shard or its tablets could not be read from the
+	// topo, if they could not.
+	err string
+}
+
+// getValidationShards reads the primary and the tablets of all the shards,
+// concurrently. A shard without a primary has a nil primary, and no tablets.
+func (s *VtctldServer) getValidationShards(ctx context.Context, keyspace string, shards []string) []*validationShard {
+	var (
+		wg               sync.WaitGroup
+		validationShards = make([]*validationShard, len(shards))
+	)
+
+	for i, shard := range shards {
+		vs := &validationShard{name: shard}
+		validationShards[i] = vs
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			si, err := s.ts.GetShard(ctx, keyspace, vs.name)
+			if err != nil {
+				vs.err = fmt.Sprintf("GetShard(%v, %v) failed: %v", keyspace, vs.name, err)
+				return
+			}
+
+			if !si.HasPrimary() {
+				return
+			}
+			vs.primary = si.PrimaryAlias
+
+			vs.aliases, err = s.ts.FindAllTabletAliasesInShard(ctx, keyspace, vs.name)
+			if err != nil {
+				vs.err = fmt.Sprintf("FindAllTabletAliasesInShard(%v, %v) failed: %v", keyspace, vs.name, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+	return validationShards
+}
+
+// validateTablets calls validate on the tablets of the shards, other than the
+// reference tablet, running at most concurrency calls at a time if concurrency
+// is non-zero. The outcome of each tablet is passed to report as soon as it is
+// known, along with how many tablets have been validated so far. report is
+// never called concurrently; if it returns an error, validateTablets cancels
+// the remaining validations and returns that error.
+func validateTablets(ctx context.Context, shards []*validationShard, reference *topodatapb.TabletAlias, concurrency uint32, validate func(ctx context.Context, alias *topodatapb.TabletAlias) []string, report func(progress *vtctldatapb.ValidateProgress) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type target struct {
+		shard string
+		alias *topodatapb.TabletAlias
+	}
+
+	var targets []target
+	for _, vs := range shards {
+		for _, alias := range vs.aliases {
+			if topoproto.TabletAliasEqual(alias, reference) {
+				continue
+			}
+
+			targets = append(targets, target{shard: vs.name, alias: alias})
+		}
+	}
+
+	var (
+		wg        sync.WaitGroup
+		m         sync.Mutex
+		sema      *semaphore.Weighted
+		completed uint32
+		reportErr error
+	)
+
+	if concurrency > 0 {
+		sema = semaphore.NewWeighted(int64(concurrency))
+	}
+
+	for _, t := range targets {
+		if sema != nil {
+			if err := sema.Acquire(ctx, 1); err != nil {
+				break
+			}
+		}
+
+		wg.Add(1)
+		go func(t target) {
+			defer wg.Done()
+			if sema != nil {
+				defer sema.Release(1)
+			}
+
+			results := validate(ctx, t.alias)
+
+			m.Lock()
+			defer m.Unlock()
+
+			if reportErr != nil {
+				return
+			}
+
+			completed++
+			reportErr = report(&vtctldatapb.ValidateProgress{
+				Shard:       t.shard,
+				TabletAlias: t.alias,
+				Results:     results,
+				Completed:   completed,
+				Total:       uint32(len(targets)),
+			})
+			if reportErr != nil {
+				cancel()
+			}
+		}(t)
+	}
+
+	wg.Wait()
+
+	if reportErr != nil {
+		return reportErr
+	}
+
+	// The context can only be done here if the caller's context is.
+	return ctx.Err()
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"hash/crc32"
 	"io"
 	"os"
 	"sort"
@@ -41,9 +42,11 @@ import (
 	hk "vitess.io/vitess/go/vt/hook"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/commandtracker"
 	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver/testutil"
 	"vitess.io/vitess/go/vt/vtctl/localvtctldclient"
 	"vitess.io/vitess/go/vt/vtctl/schematools"
@@ -94,16 +97,161 @@ func TestPanicHandler(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestNamedLocks(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+
+	resp, err := vtctld.AcquireNamedLock(ctx, &vtctldatapb.AcquireNamedLockRequest{
+		Name:  "lock",
+		Owner: "app1",
+		Ttl:   protoutil.DurationToProto(time.Minute),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "lock", resp.Lock.Name)
+	assert.Equal(t, "app1", resp.Lock.Owner)
+	acquiredAt := protoutil.TimeFromProto(resp.Lock.AcquiredAt)
+	assert.Equal(t, time.Minute, protoutil.TimeFromProto(resp.Lock.ExpiresAt).Sub(acquiredAt))
+
+	// Another owner cannot take the lock.
+	_, err = vtctld.AcquireNamedLock(ctx, &vtctldatapb.AcquireNamedLockRequest{
+		Name:        "lock",
+		Owner:       "app2",
+		Ttl:         protoutil.DurationToProto(time.Minute),
+		WaitTimeout: protoutil.DurationToProto(10 * time.Millisecond),
+	})
+	assert.ErrorContains(t, err, "named lock lock is held by app1")
+	_, err = vtctld.RenewNamedLock(ctx, &vtctldatapb.RenewNamedLockRequest{
+		Name:  "lock",
+		Owner: "app2",
+		Ttl:   protoutil.DurationToProto(time.Minute),
+	})
+	assert.ErrorContains(t, err, "named lock lock is not held by app2")
+	_, err = vtctld.ReleaseNamedLock(ctx, &vtctldatapb.ReleaseNamedLockRequest{
+		Name:  "lock",
+		Owner: "app2",
+	})
+	assert.ErrorContains(t, err, "named lock lock is not held by app2")
+
+	renewResp, err := vtctld.RenewNamedLock(ctx, &vtctldatapb.RenewNamedLockRequest{
+		Name:  "lock",
+		Owner: "app1",
+		Ttl:   protoutil.DurationToProto(time.Hour),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, acquiredAt, protoutil.TimeFromProto(renewResp.Lock.AcquiredAt))
+	assert.True(t, protoutil.TimeFromProto(renewResp.Lock.ExpiresAt).After(acquiredAt.Add(time.Minute)))
+
+	_, err = vtctld.ReleaseNamedLock(ctx, &vtctldatapb.ReleaseNamedLockRequest{
+		Name:  "lock",
+		Owner: "app1",
+	})
+	require.NoError(t, err)
+	_, err = ts.GetNamedLock(ctx, "lock")
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "expected NoNode, got %v", err)
+
+	// The lock is free for the other owner now.
+	resp, err = vtctld.AcquireNamedLock(ctx, &vtctldatapb.AcquireNamedLockRequest{
+		Name:  "lock",
+		Owner: "app2",
+		Ttl:   protoutil.DurationToProto(time.Minute),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "app2", resp.Lock.Owner)
+
+	_, err = vtctld.AcquireNamedLock(ctx, &vtctldatapb.AcquireNamedLockRequest{
+		Name:  "other",
+		Owner: "app1",
+	})
+	assert.ErrorContains(t, err, "named lock TTL must be positive")
+}
+
+func TestMaintenanceWindows(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+	require.NoError(t, ts.CreateKeyspace(ctx, "testkeyspace", &topodatapb.Keyspace{}))
+	require.NoError(t, ts.CreateShard(ctx, "testkeyspace", "-"))
+
+	resp, err := vtctld.StartMaintenance(ctx, &vtctldatapb.StartMaintenanceRequest{
+		Keyspace: "testkeyspace",
+		Shard:    "-",
+		Reason:   "upgrade",
+		Ttl:      protoutil.DurationToProto(time.Hour),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "testkeyspace", resp.Window.Keyspace)
+	assert.Equal(t, "-", resp.Window.Shard)
+	assert.Equal(t, "upgrade", resp.Window.Reason)
+	startedAt := protoutil.TimeFromProto(resp.Window.StartedAt)
+	assert.Equal(t, time.Hour, protoutil.TimeFromProto(resp.Window.ExpiresAt).Sub(startedAt))
+
+	_, err = vtctld.StartMaintenance(ctx, &vtctldatapb.StartMaintenanceRequest{
+		Cell: "zone1",
+		Ttl:  protoutil.DurationToProto(time.Hour),
+	})
+	require.NoError(t, err)
+
+	getResp, err := vtctld.GetMaintenanceWindows(ctx, &vtctldatapb.GetMaintenanceWindowsRequest{})
+	require.NoError(t, err)
+	require.Len(t, getResp.Windows, 2)
+	assert.Equal(t, "zone1", getResp.Windows[0].Cell)
+	utils.MustMatch(t, resp.Window, getResp.Windows[1])
+
+	_, err = vtctld.StopMaintenance(ctx, &vtctldatapb.StopMaintenanceRequest{
+		Keyspace: "testkeyspace",
+		Shard:    "-",
+	})
+	require.NoError(t, err)
+	getResp, err = vtctld.GetMaintenanceWindows(ctx, &vtctldatapb.GetMaintenanceWindowsRequest{})
+	require.NoError(t, err)
+	require.Len(t, getResp.Windows, 1)
+	assert.Equal(t, "zone1", getResp.Windows[0].Cell)
+
+	_, err = vtctld.StopMaintenance(ctx, &vtctldatapb.StopMaintenanceRequest{
+		Keyspace: "testkeyspace",
+		Shard:    "-",
+	})
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "expected NoNode, got %v", err)
+
+	// Unknown targets and missing TTLs are rejected.
+	_, err = vtctld.StartMaintenance(ctx, &vtctldatapb.StartMaintenanceRequest{
+		Keyspace: "otherkeyspace",
+		Ttl:      protoutil.DurationToProto(time.Hour),
+	})
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "expected NoNode, got %v", err)
+	_, err = vtctld.StartMaintenance(ctx, &vtctldatapb.StartMaintenanceRequest{
+		Cell: "zone2",
+		Ttl:  protoutil.DurationToProto(time.Hour),
+	})
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "expected NoNode, got %v", err)
+	_, err = vtctld.StartMaintenance(ctx, &vtctldatapb.StartMaintenanceRequest{
+		Keyspace: "testkeyspace",
+	})
+	assert.ErrorContains(t, err, "Ttl is required")
+}
+
 func TestAddCellInfo(t *testing.T) {
 	t.Parallel()
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	tests := []struct {
-		name      string
-		ts        *topo.Server
-		req       *vtctldatapb.AddCellInfoRequest
-		shouldErr bool
+		name          string
+		ts            *topo.Server
+		req           *vtctldatapb.AddCellInfoRequest
+		alreadyExists bool
+		shouldErr     bool
 	}{
 		{
 			ts: memorytopo.NewServer(ctx, "zone1"),
@@ -127,6 +275,19 @@ func TestAddCellInfo(t *testing.T) {
 			},
 			shouldErr: true,
 		},
+		{
+			name: "cell already exists/if not exists",
+			ts:   memorytopo.NewServer(ctx, "zone1"),
+			req: &vtctldatapb.AddCellInfoRequest{
+				Name: "zone1",
+				CellInfo: &topodatapb.CellInfo{
+					ServerAddress: ":1111",
+					Root:          "/zone1",
+				},
+				IfNotExists: true,
+			},
+			alreadyExists: true,
+		},
 		{
 			name: "no cell root",
 			ts:   memorytopo.NewServer(ctx, "zone1"),
@@ -145,15 +306,21 @@ func TestAddCellInfo(t *testing.T) {
 			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, tt.ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
 				return NewVtctldServer(ts)
 			})
-			_, err := vtctld.AddCellInfo(ctx, tt.req)
+			resp, err := vtctld.AddCellInfo(ctx, tt.req)
 			if tt.shouldErr {
 				assert.Error(t, err)
 				return
 			}
 
 			require.NoError(t, err)
+			assert.Equal(t, tt.alreadyExists, resp.AlreadyExists)
 			ci, err := tt.ts.GetCellInfo(ctx, tt.req.Name, true)
 			require.NoError(t, err, "failed to read new cell %s from topo", tt.req.Name)
+			if tt.alreadyExists {
+				// The existing cell must be left untouched.
+				assert.NotEqual(t, tt.req.CellInfo.ServerAddress, ci.ServerAddress)
+				return
+			}
 			utils.MustMatch(t, tt.req.CellInfo, ci)
 		})
 	}
@@ -232,6 +399,68 @@ func TestAddCellsAlias(t *testing.T) {
 	}
 }
 
+func TestAddRemoveTabletTag(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+
+	alias := &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}
+	testutil.AddTablet(ctx, t, ts, &topodatapb.Tablet{
+		Alias:    alias,
+		Keyspace: "testkeyspace",
+		Shard:    "-",
+		Type:     topodatapb.TabletType_REPLICA,
+		Tags:     map[string]string{"zone": "az1"},
+	}, nil)
+
+	resp, err := vtctld.AddTabletTag(ctx, &vtctldatapb.AddTabletTagRequest{
+		TabletAlias: alias,
+		Tags:        map[string]string{"zone": "az2", "hardware": "nvme"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"zone": "az2", "hardware": "nvme"}, resp.Tablet.Tags)
+
+	// Adding tags that are already set does not write the record.
+	ti, err := ts.GetTablet(ctx, alias)
+	require.NoError(t, err)
+	resp, err = vtctld.AddTabletTag(ctx, &vtctldatapb.AddTabletTagRequest{
+		TabletAlias: alias,
+		Tags:        map[string]string{"hardware": "nvme"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"zone": "az2", "hardware": "nvme"}, resp.Tablet.Tags)
+	unchanged, err := ts.GetTablet(ctx, alias)
+	require.NoError(t, err)
+	assert.Equal(t, ti.Version(), unchanged.Version())
+
+	removeResp, err := vtctld.RemoveTabletTag(ctx, &vtctldatapb.RemoveTabletTagRequest{
+		TabletAlias: alias,
+		Keys:        []string{"zone", "rack"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"hardware": "nvme"}, removeResp.Tablet.Tags)
+
+	ti, err = ts.GetTablet(ctx, alias)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"hardware": "nvme"}, ti.Tags)
+
+	_, err = vtctld.AddTabletTag(ctx, &vtctldatapb.AddTabletTagRequest{TabletAlias: alias})
+	assert.Error(t, err, "no tags")
+	_, err = vtctld.AddTabletTag(ctx, &vtctldatapb.AddTabletTagRequest{TabletAlias: alias, Tags: map[string]string{"": "x"}})
+	assert.Error(t, err, "empty tag key")
+	_, err = vtctld.RemoveTabletTag(ctx, &vtctldatapb.RemoveTabletTagRequest{TabletAlias: alias})
+	assert.Error(t, err, "no keys")
+	_, err = vtctld.RemoveTabletTag(ctx, &vtctldatapb.RemoveTabletTagRequest{
+		TabletAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+		Keys:        []string{"zone"},
+	})
+	assert.Error(t, err, "missing tablet")
+}
 func TestApplyRoutingRules(t *testing.T) {
 	t.Parallel()
 
@@ -1074,6 +1303,72 @@ func TestBackupShard(t *testing.T) {
 	}
 }
 
+func TestCancelCommand(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+
+	cmdCtx, done := commandtracker.Default.Start(ctx, []string{"CopySchemaShard", "ks/0", "ks/1"})
+	defer done()
+
+	var id int64
+	for _, cmd := range commandtracker.Default.List() {
+		if cmd.Args[0] == "CopySchemaShard" {
+			id = cmd.Id
+		}
+	}
+	require.NotZero(t, id, "command should be tracked")
+
+	_, err := vtctld.CancelCommand(ctx, &vtctldatapb.CancelCommandRequest{Id: id})
+	require.NoError(t, err)
+	assert.ErrorIs(t, cmdCtx.Err(), context.Canceled, "command context should be canceled")
+
+	_, err = vtctld.CancelCommand(ctx, &vtctldatapb.CancelCommandRequest{Id: -1})
+	assert.Error(t, err, "canceling an unknown command should fail")
+}
+
+func TestCancelScheduledCommand(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+
+	resp, err := vtctld.ScheduleCommand(ctx, &vtctldatapb.ScheduleCommandRequest{
+		Args:     []string{"ChangeTabletType", "zone1-0000000100", "drained"},
+		RunAfter: protoutil.DurationToProto(time.Hour),
+	})
+	require.NoError(t, err)
+	id := resp.ScheduledCommand.Id
+
+	cancelResp, err := vtctld.CancelScheduledCommand(ctx, &vtctldatapb.CancelScheduledCommandRequest{Id: id})
+	require.NoError(t, err)
+	assert.Equal(t, vtctldatapb.ScheduledCommand_CANCELED, cancelResp.ScheduledCommand.State)
+	assert.NotNil(t, cancelResp.ScheduledCommand.FinishTime)
+
+	getResp, err := vtctld.GetScheduledCommands(ctx, &vtctldatapb.GetScheduledCommandsRequest{})
+	require.NoError(t, err)
+	require.Len(t, getResp.ScheduledCommands, 1)
+	utils.MustMatch(t, cancelResp.ScheduledCommand, getResp.ScheduledCommands[0])
+
+	_, err = vtctld.CancelScheduledCommand(ctx, &vtctldatapb.CancelScheduledCommandRequest{Id: id})
+	assert.ErrorContains(t, err, "only pending commands can be canceled")
+
+	_, err = vtctld.CancelScheduledCommand(ctx, &vtctldatapb.CancelScheduledCommandRequest{Id: "../keyspaces/ks"})
+	assert.ErrorContains(t, err, "invalid scheduled command id")
+
+	_, err = vtctld.CancelScheduledCommand(ctx, &vtctldatapb.CancelScheduledCommandRequest{Id: "00000000-0000-0000-0000-000000000000"})
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "canceling an unknown command should fail with NoNode, got %v", err)
+}
+
 func TestCancelSchemaMigration(t *testing.T) {
 	t.Parallel()
 
@@ -1575,86 +1870,281 @@ func TestChangeTabletType(t *testing.T) {
 	})
 }
 
-func TestCleanupSchemaMigration(t *testing.T) {
+func TestChangeTabletTypeByFilter(t *testing.T) {
 	t.Parallel()
 
+	tablets := []*topodatapb.Tablet{
+		{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+			Keyspace: "ks",
+			Shard:    "-80",
+			Type:     topodatapb.TabletType_PRIMARY,
+		},
+		{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+			Keyspace: "ks",
+			Shard:    "-80",
+			Type:     topodatapb.TabletType_RDONLY,
+		},
+		{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 102},
+			Keyspace: "ks",
+			Shard:    "-80",
+			Type:     topodatapb.TabletType_REPLICA,
+		},
+		{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone2", Uid: 200},
+			Keyspace: "ks",
+			Shard:    "-80",
+			Type:     topodatapb.TabletType_RDONLY,
+		},
+		{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 300},
+			Keyspace: "ks",
+			Shard:    "80-",
+			Type:     topodatapb.TabletType_PRIMARY,
+		},
+		{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 301},
+			Keyspace: "ks",
+			Shard:    "80-",
+			Type:     topodatapb.TabletType_RDONLY,
+		},
+	}
+
 	tests := []struct {
-		name      string
-		tablets   []*topodatapb.Tablet
-		tmc       *testutil.TabletManagerClient
-		req       *vtctldatapb.CleanupSchemaMigrationRequest
-		expected  *vtctldatapb.CleanupSchemaMigrationResponse
+		name    string
+		req     *vtctldatapb.ChangeTabletTypeByFilterRequest
+		tmcErrs map[string]error
+		// expected maps the alias of each tablet in the results to its type
+		// after the change, or to UNKNOWN if the change failed.
+		expected  map[string]topodatapb.TabletType
 		shouldErr bool
 	}{
 		{
-			tablets: []*topodatapb.Tablet{
-				{
-					Keyspace: "ks",
-					Shard:    "-80",
-					Alias: &topodatapb.TabletAlias{
-						Cell: "zone1",
-						Uid:  100,
-					},
-					Type: topodatapb.TabletType_PRIMARY,
-				},
-				{
-					Keyspace: "ks",
-					Shard:    "80-",
-					Alias: &topodatapb.TabletAlias{
-						Cell: "zone1",
-						Uid:  200,
-					},
-					Type: topodatapb.TabletType_PRIMARY,
-				},
+			name: "shard and cell",
+			req: &vtctldatapb.ChangeTabletTypeByFilterRequest{
+				Keyspace: "ks",
+				Shard:    "-80",
+				Cells:    []string{"zone1"},
+				FromType: topodatapb.TabletType_RDONLY,
+				ToType:   topodatapb.TabletType_REPLICA,
 			},
-			tmc: &testutil.TabletManagerClient{
-				ExecuteQueryResults: map[string]struct {
-					Response *querypb.QueryResult
-					Error    error
-				}{
-					"zone1-0000000100": {
-						Response: &querypb.QueryResult{
-							RowsAffected: 1,
-						},
-					},
-					"zone1-0000000200": {
-						Response: &querypb.QueryResult{},
-					},
-				},
-				PrimaryPositionResults: map[string]struct {
-					Position string
-					Error    error
-				}{
-					"zone1-0000000100": {},
-					"zone1-0000000200": {},
-				},
-				ReloadSchemaResults: map[string]error{
-					"zone1-0000000100": nil,
-					"zone1-0000000200": nil,
-				},
+			expected: map[string]topodatapb.TabletType{
+				"zone1-0000000101": topodatapb.TabletType_REPLICA,
 			},
-			req: &vtctldatapb.CleanupSchemaMigrationRequest{
+		},
+		{
+			name: "whole keyspace",
+			req: &vtctldatapb.ChangeTabletTypeByFilterRequest{
+				Keyspace:    "ks",
+				FromType:    topodatapb.TabletType_RDONLY,
+				ToType:      topodatapb.TabletType_REPLICA,
+				Concurrency: 1,
+			},
+			expected: map[string]topodatapb.TabletType{
+				"zone1-0000000101": topodatapb.TabletType_REPLICA,
+				"zone1-0000000301": topodatapb.TabletType_REPLICA,
+				"zone2-0000000200": topodatapb.TabletType_REPLICA,
+			},
+		},
+		{
+			name: "partial failure",
+			req: &vtctldatapb.ChangeTabletTypeByFilterRequest{
 				Keyspace: "ks",
-				Uuid:     "abc",
+				FromType: topodatapb.TabletType_RDONLY,
+				ToType:   topodatapb.TabletType_REPLICA,
 			},
-			expected: &vtctldatapb.CleanupSchemaMigrationResponse{
-				RowsAffectedByShard: map[string]uint64{
-					"-80": 1,
-					"80-": 0,
-				},
+			tmcErrs: map[string]error{
+				"zone1-0000000301": assert.AnError,
+			},
+			expected: map[string]topodatapb.TabletType{
+				"zone1-0000000101": topodatapb.TabletType_REPLICA,
+				"zone1-0000000301": topodatapb.TabletType_UNKNOWN,
+				"zone2-0000000200": topodatapb.TabletType_REPLICA,
 			},
 		},
 		{
-			name: "no shard primary",
-			tablets: []*topodatapb.Tablet{
-				{
-					Keyspace: "ks",
-					Shard:    "-80",
-					Alias: &topodatapb.TabletAlias{
-						Cell: "zone1",
-						Uid:  100,
-					},
-					Type: topodatapb.TabletType_PRIMARY,
+			name: "disallowed transition",
+			req: &vtctldatapb.ChangeTabletTypeByFilterRequest{
+				Keyspace: "ks",
+				Shard:    "80-",
+				FromType: topodatapb.TabletType_PRIMARY,
+				ToType:   topodatapb.TabletType_REPLICA,
+			},
+			expected: map[string]topodatapb.TabletType{
+				"zone1-0000000300": topodatapb.TabletType_UNKNOWN,
+			},
+		},
+		{
+			name: "no matching tablets",
+			req: &vtctldatapb.ChangeTabletTypeByFilterRequest{
+				Keyspace: "ks",
+				FromType: topodatapb.TabletType_SPARE,
+				ToType:   topodatapb.TabletType_REPLICA,
+			},
+			expected: map[string]topodatapb.TabletType{},
+		},
+		{
+			name: "no keyspace",
+			req: &vtctldatapb.ChangeTabletTypeByFilterRequest{
+				FromType: topodatapb.TabletType_RDONLY,
+				ToType:   topodatapb.TabletType_REPLICA,
+			},
+			shouldErr: true,
+		},
+		{
+			name: "no from type",
+			req: &vtctldatapb.ChangeTabletTypeByFilterRequest{
+				Keyspace: "ks",
+				ToType:   topodatapb.TabletType_REPLICA,
+			},
+			shouldErr: true,
+		},
+		{
+			name: "unknown shard",
+			req: &vtctldatapb.ChangeTabletTypeByFilterRequest{
+				Keyspace: "ks",
+				Shard:    "80-c0",
+				FromType: topodatapb.TabletType_RDONLY,
+				ToType:   topodatapb.TabletType_REPLICA,
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ts := memorytopo.NewServer(ctx, "zone1", "zone2")
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, &testutil.TabletManagerClient{
+				TopoServer:             ts,
+				ChangeTabletTypeResult: tt.tmcErrs,
+			}, func(ts *topo.Server) vtctlservicepb.VtctldServer { return NewVtctldServer(ts) })
+
+			testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+				AlsoSetShardPrimary: true,
+			}, tablets...)
+
+			resp, err := vtctld.ChangeTabletTypeByFilter(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			results := make(map[string]topodatapb.TabletType, len(resp.Results))
+			aliases := make([]string, 0, len(resp.Results))
+			for _, result := range resp.Results {
+				alias := topoproto.TabletAliasString(result.TabletAlias)
+				aliases = append(aliases, alias)
+
+				if result.Error != "" {
+					assert.Nil(t, result.Response, "failed result for %s should not have a response", alias)
+					results[alias] = topodatapb.TabletType_UNKNOWN
+					continue
+				}
+
+				require.NotNil(t, result.Response, "successful result for %s should have a response", alias)
+				results[alias] = result.Response.AfterTablet.Type
+
+				tablet, err := ts.GetTablet(ctx, result.TabletAlias)
+				require.NoError(t, err)
+				assert.Equal(t, result.Response.AfterTablet.Type, tablet.Type, "ChangeTabletTypeByFilter did not cause topo update for %s", alias)
+			}
+
+			assert.Equal(t, tt.expected, results)
+			assert.True(t, sort.StringsAreSorted(aliases), "results should be ordered by alias, got %v", aliases)
+		})
+	}
+}
+
+func TestCleanupSchemaMigration(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		tablets   []*topodatapb.Tablet
+		tmc       *testutil.TabletManagerClient
+		req       *vtctldatapb.CleanupSchemaMigrationRequest
+		expected  *vtctldatapb.CleanupSchemaMigrationResponse
+		shouldErr bool
+	}{
+		{
+			tablets: []*topodatapb.Tablet{
+				{
+					Keyspace: "ks",
+					Shard:    "-80",
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  100,
+					},
+					Type: topodatapb.TabletType_PRIMARY,
+				},
+				{
+					Keyspace: "ks",
+					Shard:    "80-",
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  200,
+					},
+					Type: topodatapb.TabletType_PRIMARY,
+				},
+			},
+			tmc: &testutil.TabletManagerClient{
+				ExecuteQueryResults: map[string]struct {
+					Response *querypb.QueryResult
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Response: &querypb.QueryResult{
+							RowsAffected: 1,
+						},
+					},
+					"zone1-0000000200": {
+						Response: &querypb.QueryResult{},
+					},
+				},
+				PrimaryPositionResults: map[string]struct {
+					Position string
+					Error    error
+				}{
+					"zone1-0000000100": {},
+					"zone1-0000000200": {},
+				},
+				ReloadSchemaResults: map[string]error{
+					"zone1-0000000100": nil,
+					"zone1-0000000200": nil,
+				},
+			},
+			req: &vtctldatapb.CleanupSchemaMigrationRequest{
+				Keyspace: "ks",
+				Uuid:     "abc",
+			},
+			expected: &vtctldatapb.CleanupSchemaMigrationResponse{
+				RowsAffectedByShard: map[string]uint64{
+					"-80": 1,
+					"80-": 0,
+				},
+			},
+		},
+		{
+			name: "no shard primary",
+			tablets: []*topodatapb.Tablet{
+				{
+					Keyspace: "ks",
+					Shard:    "-80",
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  100,
+					},
+					Type: topodatapb.TabletType_PRIMARY,
 				},
 				{
 					Keyspace: "ks",
@@ -1777,6 +2267,179 @@ func TestCleanupSchemaMigration(t *testing.T) {
 	}
 }
 
+func TestConcludeTransaction(t *testing.T) {
+	t.Parallel()
+
+	tablets := []*topodatapb.Tablet{
+		{
+			Keyspace: "ks",
+			Shard:    "-80",
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  100,
+			},
+			Type: topodatapb.TabletType_PRIMARY,
+		},
+		{
+			Keyspace: "ks",
+			Shard:    "80-",
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  200,
+			},
+			Type: topodatapb.TabletType_PRIMARY,
+		},
+	}
+	transaction := func(state querypb.TransactionState) *querypb.TransactionMetadata {
+		return &querypb.TransactionMetadata{
+			Dtid:        "ks:-80:1234",
+			State:       state,
+			TimeCreated: 1,
+			Participants: []*querypb.Target{{
+				Keyspace:   "ks",
+				Shard:      "80-",
+				TabletType: topodatapb.TabletType_PRIMARY,
+			}},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		tmc       *testutil.TabletManagerClient
+		req       *vtctldatapb.ConcludeTransactionRequest
+		shouldErr string
+	}{
+		{
+			name: "commit",
+			tmc: &testutil.TabletManagerClient{
+				ReadTransactionResults: map[string]struct {
+					Response *tabletmanagerdatapb.ReadTransactionResponse
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Response: &tabletmanagerdatapb.ReadTransactionResponse{Transaction: transaction(querypb.TransactionState_COMMIT)},
+					},
+				},
+				ConcludeTransactionResults: map[string]error{
+					"zone1-0000000200/commit":   nil,
+					"zone1-0000000100/conclude": nil,
+				},
+			},
+			req: &vtctldatapb.ConcludeTransactionRequest{
+				Dtid: "ks:-80:1234",
+			},
+		},
+		{
+			name: "rollback",
+			tmc: &testutil.TabletManagerClient{
+				ReadTransactionResults: map[string]struct {
+					Response *tabletmanagerdatapb.ReadTransactionResponse
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Response: &tabletmanagerdatapb.ReadTransactionResponse{Transaction: transaction(querypb.TransactionState_ROLLBACK)},
+					},
+				},
+				ConcludeTransactionResults: map[string]error{
+					"zone1-0000000200/rollback": nil,
+					"zone1-0000000100/conclude": nil,
+				},
+			},
+			req: &vtctldatapb.ConcludeTransactionRequest{
+				Dtid: "ks:-80:1234",
+			},
+		},
+		{
+			name: "invalid dtid",
+			tmc:  &testutil.TabletManagerClient{},
+			req: &vtctldatapb.ConcludeTransactionRequest{
+				Dtid: "1234",
+			},
+			shouldErr: "invalid parts in dtid",
+		},
+		{
+			name: "resolved already",
+			tmc: &testutil.TabletManagerClient{
+				ReadTransactionResults: map[string]struct {
+					Response *tabletmanagerdatapb.ReadTransactionResponse
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Response: &tabletmanagerdatapb.ReadTransactionResponse{Transaction: &querypb.TransactionMetadata{}},
+					},
+				},
+			},
+			req: &vtctldatapb.ConcludeTransactionRequest{
+				Dtid: "ks:-80:1234",
+			},
+			shouldErr: "distributed transaction ks:-80:1234 not found",
+		},
+		{
+			name: "prepare state",
+			tmc: &testutil.TabletManagerClient{
+				ReadTransactionResults: map[string]struct {
+					Response *tabletmanagerdatapb.ReadTransactionResponse
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Response: &tabletmanagerdatapb.ReadTransactionResponse{Transaction: transaction(querypb.TransactionState_PREPARE)},
+					},
+				},
+			},
+			req: &vtctldatapb.ConcludeTransactionRequest{
+				Dtid: "ks:-80:1234",
+			},
+			shouldErr: "is in the PREPARE state",
+		},
+		{
+			name: "participant failure",
+			tmc: &testutil.TabletManagerClient{
+				ReadTransactionResults: map[string]struct {
+					Response *tabletmanagerdatapb.ReadTransactionResponse
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Response: &tabletmanagerdatapb.ReadTransactionResponse{Transaction: transaction(querypb.TransactionState_COMMIT)},
+					},
+				},
+				ConcludeTransactionResults: map[string]error{
+					"zone1-0000000200/commit":   assert.AnError,
+					"zone1-0000000100/conclude": nil,
+				},
+			},
+			req: &vtctldatapb.ConcludeTransactionRequest{
+				Dtid: "ks:-80:1234",
+			},
+			shouldErr: "cannot resolve distributed transaction ks:-80:1234 on ks/80-",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ts := memorytopo.NewServer(ctx, "zone1")
+
+			testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+				AlsoSetShardPrimary: true,
+			}, tablets...)
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, test.tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(ts)
+			})
+
+			resp, err := vtctld.ConcludeTransaction(ctx, test.req)
+			if test.shouldErr != "" {
+				assert.ErrorContains(t, err, test.shouldErr)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, &vtctldatapb.ConcludeTransactionResponse{}, resp)
+		})
+	}
+}
+
 func TestCreateKeyspace(t *testing.T) {
 	t.Parallel()
 
@@ -1961,18 +2624,47 @@ func TestCreateKeyspace(t *testing.T) {
 			shouldErr: false,
 		},
 		{
-			name: "allow empty vschema",
-			topo: nil,
+			name: "keyspace exists/if not exists",
+			topo: map[string]*topodatapb.Keyspace{
+				"testkeyspace": {
+					KeyspaceType:     topodatapb.KeyspaceType_NORMAL,
+					DurabilityPolicy: "semi_sync",
+				},
+			},
 			req: &vtctldatapb.CreateKeyspaceRequest{
-				Name:              "testkeyspace",
-				Type:              topodatapb.KeyspaceType_NORMAL,
-				AllowEmptyVSchema: true,
+				Name:             "testkeyspace",
+				Type:             topodatapb.KeyspaceType_NORMAL,
+				DurabilityPolicy: "none",
+				IfNotExists:      true,
 			},
 			expected: &vtctldatapb.CreateKeyspaceResponse{
 				Keyspace: &vtctldatapb.Keyspace{
 					Name: "testkeyspace",
 					Keyspace: &topodatapb.Keyspace{
-						KeyspaceType: topodatapb.KeyspaceType_NORMAL,
+						KeyspaceType:     topodatapb.KeyspaceType_NORMAL,
+						DurabilityPolicy: "semi_sync",
+					},
+				},
+				AlreadyExists: true,
+			},
+			// The existing keyspace is left unchanged, so no vschema is
+			// created for it.
+			vschemaShouldExist: false,
+			shouldErr:          false,
+		},
+		{
+			name: "allow empty vschema",
+			topo: nil,
+			req: &vtctldatapb.CreateKeyspaceRequest{
+				Name:              "testkeyspace",
+				Type:              topodatapb.KeyspaceType_NORMAL,
+				AllowEmptyVSchema: true,
+			},
+			expected: &vtctldatapb.CreateKeyspaceResponse{
+				Keyspace: &vtctldatapb.Keyspace{
+					Name: "testkeyspace",
+					Keyspace: &topodatapb.Keyspace{
+						KeyspaceType: topodatapb.KeyspaceType_NORMAL,
 					},
 				},
 			},
@@ -2041,6 +2733,7 @@ func TestCreateKeyspace(t *testing.T) {
 
 			assert.NoError(t, err)
 			testutil.AssertKeyspacesEqual(t, tt.expected.Keyspace, resp.Keyspace, "%+v\n%+v\n", tt.expected.Keyspace, resp.Keyspace)
+			assert.Equal(t, tt.expected.AlreadyExists, resp.AlreadyExists)
 
 			// Fetch the newly-created keyspace out of the topo and assert on it
 			ks, err := ts.GetKeyspace(ctx, tt.req.Name)
@@ -2264,6 +2957,44 @@ func TestCreateShard(t *testing.T) {
 			},
 			shouldErr: false,
 		},
+		{
+			name: "include parent/keyspace and shard exist/if not exists",
+			keyspaces: []*vtctldatapb.Keyspace{
+				{
+					Name:     "testkeyspace",
+					Keyspace: &topodatapb.Keyspace{},
+				},
+			},
+			shards: []*vtctldatapb.Shard{
+				{
+					Keyspace: "testkeyspace",
+					Name:     "-",
+				},
+			},
+			topoErr: nil,
+			req: &vtctldatapb.CreateShardRequest{
+				Keyspace:      "testkeyspace",
+				ShardName:     "-",
+				IncludeParent: true,
+				IfNotExists:   true,
+			},
+			expected: &vtctldatapb.CreateShardResponse{
+				Keyspace: &vtctldatapb.Keyspace{
+					Name:     "testkeyspace",
+					Keyspace: &topodatapb.Keyspace{},
+				},
+				Shard: &vtctldatapb.Shard{
+					Keyspace: "testkeyspace",
+					Name:     "-",
+					Shard: &topodatapb.Shard{
+						KeyRange:         &topodatapb.KeyRange{},
+						IsPrimaryServing: true,
+					},
+				},
+				ShardAlreadyExists: true,
+			},
+			shouldErr: false,
+		},
 		{
 			name: "topo is down",
 			keyspaces: []*vtctldatapb.Keyspace{
@@ -2543,6 +3274,56 @@ func TestDeleteKeyspace(t *testing.T) {
 			expectedRemainingShards:    map[string][]string{},
 			shouldErr:                  false,
 		},
+		{
+			name: "keyspace has shards/Recursive=true/DryRun=true",
+			keyspaces: []*vtctldatapb.Keyspace{
+				{
+					Name:     "testkeyspace",
+					Keyspace: &topodatapb.Keyspace{},
+				},
+			},
+			shards: []*vtctldatapb.Shard{
+				{
+					Keyspace: "testkeyspace",
+					Name:     "-80",
+				},
+				{
+					Keyspace: "testkeyspace",
+					Name:     "80-",
+				},
+			},
+			srvKeyspaces: nil,
+			topoErr:      nil,
+			req: &vtctldatapb.DeleteKeyspaceRequest{
+				Keyspace:  "testkeyspace",
+				Recursive: true,
+				DryRun:    true,
+			},
+			expected: &vtctldatapb.DeleteKeyspaceResponse{
+				DryRunResults: []string{
+					"Delete ShardReplication for shard testkeyspace/-80 in cell zone1",
+					"Delete ShardReplication for shard testkeyspace/-80 in cell zone2",
+					"Delete ShardReplication for shard testkeyspace/-80 in cell zone3",
+					"Delete shard testkeyspace/-80",
+					"Delete ShardReplication for shard testkeyspace/80- in cell zone1",
+					"Delete ShardReplication for shard testkeyspace/80- in cell zone2",
+					"Delete ShardReplication for shard testkeyspace/80- in cell zone3",
+					"Delete shard testkeyspace/80-",
+					"Delete KeyspaceReplication for keyspace testkeyspace in cell zone1",
+					"Delete SrvKeyspace for keyspace testkeyspace in cell zone1",
+					"Delete KeyspaceReplication for keyspace testkeyspace in cell zone2",
+					"Delete SrvKeyspace for keyspace testkeyspace in cell zone2",
+					"Delete KeyspaceReplication for keyspace testkeyspace in cell zone3",
+					"Delete SrvKeyspace for keyspace testkeyspace in cell zone3",
+					"Delete keyspace testkeyspace",
+				},
+			},
+			expectedRemainingKeyspaces: []string{"testkeyspace"},
+			expectedRemainingShards: map[string][]string{
+				"testkeyspace": {"-80", "80-"},
+			},
+			shouldErr: false,
+		},
 		// Not sure how to force this case because we always pass
 		// (Recursive=true, EvenIfServing=true) so anything short of "topo
 		// server is down" won't fail, and "topo server is down" will cause us
@@ -3359,6 +4140,49 @@ func TestDeleteTablets(t *testing.T) {
 			expectedRemainingTablets: []*topodatapb.Tablet{},
 			shouldErr:                false,
 		},
+		{
+			name: "single replica/dry run",
+			tablets: []*topodatapb.Tablet{
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  100,
+					},
+					Type:     topodatapb.TabletType_REPLICA,
+					Keyspace: "testkeyspace",
+					Shard:    "-",
+				},
+			},
+			lockedShards: nil,
+			topoError:    nil,
+			req: &vtctldatapb.DeleteTabletsRequest{
+				TabletAliases: []*topodatapb.TabletAlias{
+					{
+						Cell: "zone1",
+						Uid:  100,
+					},
+				},
+				DryRun: true,
+			},
+			expected: &vtctldatapb.DeleteTabletsResponse{
+				DryRunResults: []string{
+					"Delete tablet zone1-0000000100",
+					"Remove tablet zone1-0000000100 from ShardReplication for shard testkeyspace/- in cell zone1",
+				},
+			},
+			expectedRemainingTablets: []*topodatapb.Tablet{
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  100,
+					},
+					Type:     topodatapb.TabletType_REPLICA,
+					Keyspace: "testkeyspace",
+					Shard:    "-",
+				},
+			},
+			shouldErr: false,
+		},
 		{
 			name: "single primary/no AllowPrimary",
 			tablets: []*topodatapb.Tablet{
@@ -4503,131 +5327,337 @@ func TestFindAllShardsInKeyspace(t *testing.T) {
 	assert.Error(t, err)
 }
 
-func TestGetBackups(t *testing.T) {
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-	ts := memorytopo.NewServer(ctx)
-	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
-		return NewVtctldServer(ts)
-	})
+func TestFixErrantGTID(t *testing.T) {
+	t.Parallel()
 
-	testutil.BackupStorage.Backups = map[string][]string{
-		"testkeyspace/-": {"backup1", "backup2"},
-	}
+	const (
+		primaryPosition = "MySQL56/8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1-12"
+		replicaPosition = "MySQL56/8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1-10,8bc65cca-3fe4-11ed-bd54-6ba5ce0c5eb6:1-2"
+		errantGTIDSet   = "8bc65cca-3fe4-11ed-bd54-6ba5ce0c5eb6:1-2"
+	)
 
-	expected := &vtctldatapb.GetBackupsResponse{
-		Backups: []*mysqlctlpb.BackupInfo{
-			{
-				Directory: "testkeyspace/-",
-				Name:      "backup1",
-				Keyspace:  "testkeyspace",
-				Shard:     "-",
-			},
-			{
-				Directory: "testkeyspace/-",
-				Name:      "backup2",
-				Keyspace:  "testkeyspace",
-				Shard:     "-",
+	tablets := []*topodatapb.Tablet{
+		{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  100,
 			},
-		},
-	}
-
-	resp, err := vtctld.GetBackups(ctx, &vtctldatapb.GetBackupsRequest{
-		Keyspace: "testkeyspace",
-		Shard:    "-",
-	})
-	assert.NoError(t, err)
-	utils.MustMatch(t, expected, resp)
-
-	t.Run("no backupstorage", func(t *testing.T) {
-		backupstorage.BackupStorageImplementation = "doesnotexist"
-		defer func() { backupstorage.BackupStorageImplementation = testutil.BackupStorageImplementation }()
-
-		_, err := vtctld.GetBackups(ctx, &vtctldatapb.GetBackupsRequest{
-			Keyspace: "testkeyspace",
-			Shard:    "-",
-		})
-		assert.Error(t, err)
-	})
-
-	t.Run("listbackups error", func(t *testing.T) {
-		testutil.BackupStorage.ListBackupsError = assert.AnError
-		defer func() { testutil.BackupStorage.ListBackupsError = nil }()
-
-		_, err := vtctld.GetBackups(ctx, &vtctldatapb.GetBackupsRequest{
 			Keyspace: "testkeyspace",
 			Shard:    "-",
-		})
-		assert.Error(t, err)
-	})
-
-	t.Run("parsing times and aliases", func(t *testing.T) {
-		testutil.BackupStorage.Backups["ks2/-80"] = []string{
-			"2021-06-11.123456.zone1-101",
-		}
-
-		resp, err := vtctld.GetBackups(ctx, &vtctldatapb.GetBackupsRequest{
-			Keyspace: "ks2",
-			Shard:    "-80",
-		})
-		require.NoError(t, err)
-		expected := &vtctldatapb.GetBackupsResponse{
-			Backups: []*mysqlctlpb.BackupInfo{
-				{
-					Directory: "ks2/-80",
-					Name:      "2021-06-11.123456.zone1-101",
-					Keyspace:  "ks2",
-					Shard:     "-80",
-					Time:      protoutil.TimeToProto(time.Date(2021, time.June, 11, 12, 34, 56, 0, time.UTC)),
-					TabletAlias: &topodatapb.TabletAlias{
-						Cell: "zone1",
-						Uid:  101,
-					},
-				},
+			Type:     topodatapb.TabletType_DRAINED,
+			Tags: map[string]string{
+				topo.ErrantGTIDQuarantineTag: errantGTIDSet,
+			},
+		}, {
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  101,
 			},
-		}
-		utils.MustMatch(t, expected, resp)
-	})
-
-	t.Run("limiting", func(t *testing.T) {
-		unlimited, err := vtctld.GetBackups(ctx, &vtctldatapb.GetBackupsRequest{
 			Keyspace: "testkeyspace",
 			Shard:    "-",
-		})
-		require.NoError(t, err)
-
-		limited, err := vtctld.GetBackups(ctx, &vtctldatapb.GetBackupsRequest{
+			Type:     topodatapb.TabletType_PRIMARY,
+		}, {
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  102,
+			},
 			Keyspace: "testkeyspace",
 			Shard:    "-",
-			Limit:    1,
-		})
-		require.NoError(t, err)
-
-		assert.Equal(t, len(limited.Backups), 1, "expected limited backups to have length 1")
-		assert.Less(t, len(limited.Backups), len(unlimited.Backups), "expected limited backups to be less than unlimited")
-		utils.MustMatch(t, limited.Backups[0], unlimited.Backups[len(unlimited.Backups)-1], "expected limiting to keep N most recent")
-	})
-}
-
-func TestGetKeyspace(t *testing.T) {
-	t.Parallel()
-
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-	ts := memorytopo.NewServer(ctx, "cell1")
-	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
-		return NewVtctldServer(ts)
-	})
-
-	expected := &vtctldatapb.GetKeyspaceResponse{
-		Keyspace: &vtctldatapb.Keyspace{
-			Name:     "testkeyspace",
-			Keyspace: &topodatapb.Keyspace{},
+			Type:     topodatapb.TabletType_REPLICA,
 		},
 	}
-	testutil.AddKeyspace(ctx, t, ts, expected.Keyspace)
-
-	ks, err := vtctld.GetKeyspace(ctx, &vtctldatapb.GetKeyspaceRequest{Keyspace: expected.Keyspace.Name})
+	positions := map[string]struct {
+		Position string
+		Error    error
+	}{
+		"zone1-0000000100": {Position: replicaPosition},
+		"zone1-0000000101": {Position: primaryPosition},
+		"zone1-0000000102": {Position: primaryPosition},
+	}
+	fetches := map[string]struct {
+		Response *querypb.QueryResult
+		Error    error
+	}{
+		"zone1-0000000100": {Response: &querypb.QueryResult{}},
+		"zone1-0000000101": {Response: &querypb.QueryResult{}},
+		"zone1-0000000102": {Response: &querypb.QueryResult{}},
+	}
+
+	tests := []struct {
+		name         string
+		tmc          testutil.TabletManagerClient
+		req          *vtctldatapb.FixErrantGTIDRequest
+		expected     *vtctldatapb.FixErrantGTIDResponse
+		expectedType topodatapb.TabletType
+		expectedTag  string
+		shouldErr    bool
+	}{
+		{
+			name: "dry run",
+			tmc: testutil.TabletManagerClient{
+				PrimaryPositionResults: positions,
+			},
+			req: &vtctldatapb.FixErrantGTIDRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+				TabletType: topodatapb.TabletType_REPLICA,
+				DryRun:     true,
+			},
+			expected: &vtctldatapb.FixErrantGTIDResponse{
+				ErrantGtidSet: errantGTIDSet,
+			},
+			expectedType: topodatapb.TabletType_DRAINED,
+			expectedTag:  errantGTIDSet,
+		},
+		{
+			name: "reconcile",
+			tmc: testutil.TabletManagerClient{
+				PrimaryPositionResults:   positions,
+				ExecuteFetchAsDbaResults: fetches,
+			},
+			req: &vtctldatapb.FixErrantGTIDRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+				Mode:       vtctldatapb.FixErrantGTIDRequest_RECONCILE,
+				TabletType: topodatapb.TabletType_REPLICA,
+			},
+			expected: &vtctldatapb.FixErrantGTIDResponse{
+				ErrantGtidSet: errantGTIDSet,
+			},
+			expectedType: topodatapb.TabletType_REPLICA,
+		},
+		{
+			name: "reset",
+			tmc: testutil.TabletManagerClient{
+				PrimaryPositionResults:   positions,
+				ExecuteFetchAsDbaResults: fetches,
+				StopReplicationResults: map[string]error{
+					"zone1-0000000100": nil,
+				},
+				StartReplicationResults: map[string]error{
+					"zone1-0000000100": nil,
+				},
+			},
+			req: &vtctldatapb.FixErrantGTIDRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+				Mode: vtctldatapb.FixErrantGTIDRequest_RESET,
+			},
+			expected: &vtctldatapb.FixErrantGTIDResponse{
+				ErrantGtidSet: errantGTIDSet,
+			},
+			expectedType: topodatapb.TabletType_DRAINED,
+		},
+		{
+			name: "reset fails",
+			tmc: testutil.TabletManagerClient{
+				PrimaryPositionResults: positions,
+				ExecuteFetchAsDbaResults: map[string]struct {
+					Response *querypb.QueryResult
+					Error    error
+				}{
+					"zone1-0000000100": {Error: assert.AnError},
+				},
+				StopReplicationResults: map[string]error{
+					"zone1-0000000100": nil,
+				},
+			},
+			req: &vtctldatapb.FixErrantGTIDRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+				Mode: vtctldatapb.FixErrantGTIDRequest_RESET,
+			},
+			shouldErr: true,
+		},
+		{
+			name: "primary tablet",
+			tmc: testutil.TabletManagerClient{
+				PrimaryPositionResults: positions,
+			},
+			req: &vtctldatapb.FixErrantGTIDRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  101,
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			name:      "bad request",
+			req:       &vtctldatapb.FixErrantGTIDRequest{},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ts := memorytopo.NewServer(ctx, "zone1")
+			defer ts.Close()
+
+			testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+				AlsoSetShardPrimary: true,
+			}, tablets...)
+			tt.tmc.TopoServer = ts
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, &tt.tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(ts)
+			})
+
+			resp, err := vtctld.FixErrantGTID(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
+
+			tablet, err := ts.GetTablet(ctx, tt.req.TabletAlias)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedType, tablet.Type)
+			assert.Equal(t, tt.expectedTag, tablet.Tags[topo.ErrantGTIDQuarantineTag])
+		})
+	}
+}
+
+func TestGetBackups(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx)
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+
+	testutil.BackupStorage.Backups = map[string][]string{
+		"testkeyspace/-": {"backup1", "backup2"},
+	}
+
+	expected := &vtctldatapb.GetBackupsResponse{
+		Backups: []*mysqlctlpb.BackupInfo{
+			{
+				Directory: "testkeyspace/-",
+				Name:      "backup1",
+				Keyspace:  "testkeyspace",
+				Shard:     "-",
+			},
+			{
+				Directory: "testkeyspace/-",
+				Name:      "backup2",
+				Keyspace:  "testkeyspace",
+				Shard:     "-",
+			},
+		},
+	}
+
+	resp, err := vtctld.GetBackups(ctx, &vtctldatapb.GetBackupsRequest{
+		Keyspace: "testkeyspace",
+		Shard:    "-",
+	})
+	assert.NoError(t, err)
+	utils.MustMatch(t, expected, resp)
+
+	t.Run("no backupstorage", func(t *testing.T) {
+		backupstorage.BackupStorageImplementation = "doesnotexist"
+		defer func() { backupstorage.BackupStorageImplementation = testutil.BackupStorageImplementation }()
+
+		_, err := vtctld.GetBackups(ctx, &vtctldatapb.GetBackupsRequest{
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("listbackups error", func(t *testing.T) {
+		testutil.BackupStorage.ListBackupsError = assert.AnError
+		defer func() { testutil.BackupStorage.ListBackupsError = nil }()
+
+		_, err := vtctld.GetBackups(ctx, &vtctldatapb.GetBackupsRequest{
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("parsing times and aliases", func(t *testing.T) {
+		testutil.BackupStorage.Backups["ks2/-80"] = []string{
+			"2021-06-11.123456.zone1-101",
+		}
+
+		resp, err := vtctld.GetBackups(ctx, &vtctldatapb.GetBackupsRequest{
+			Keyspace: "ks2",
+			Shard:    "-80",
+		})
+		require.NoError(t, err)
+		expected := &vtctldatapb.GetBackupsResponse{
+			Backups: []*mysqlctlpb.BackupInfo{
+				{
+					Directory: "ks2/-80",
+					Name:      "2021-06-11.123456.zone1-101",
+					Keyspace:  "ks2",
+					Shard:     "-80",
+					Time:      protoutil.TimeToProto(time.Date(2021, time.June, 11, 12, 34, 56, 0, time.UTC)),
+					TabletAlias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  101,
+					},
+				},
+			},
+		}
+		utils.MustMatch(t, expected, resp)
+	})
+
+	t.Run("limiting", func(t *testing.T) {
+		unlimited, err := vtctld.GetBackups(ctx, &vtctldatapb.GetBackupsRequest{
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+		})
+		require.NoError(t, err)
+
+		limited, err := vtctld.GetBackups(ctx, &vtctldatapb.GetBackupsRequest{
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+			Limit:    1,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, len(limited.Backups), 1, "expected limited backups to have length 1")
+		assert.Less(t, len(limited.Backups), len(unlimited.Backups), "expected limited backups to be less than unlimited")
+		utils.MustMatch(t, limited.Backups[0], unlimited.Backups[len(unlimited.Backups)-1], "expected limiting to keep N most recent")
+	})
+}
+
+func TestGetKeyspace(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+
+	expected := &vtctldatapb.GetKeyspaceResponse{
+		Keyspace: &vtctldatapb.Keyspace{
+			Name:     "testkeyspace",
+			Keyspace: &topodatapb.Keyspace{},
+		},
+	}
+	testutil.AddKeyspace(ctx, t, ts, expected.Keyspace)
+
+	ks, err := vtctld.GetKeyspace(ctx, &vtctldatapb.GetKeyspaceRequest{Keyspace: expected.Keyspace.Name})
 	assert.NoError(t, err)
 	utils.MustMatch(t, expected, ks)
 
@@ -5019,27 +6049,60 @@ func TestGetPermissions(t *testing.T) {
 	}
 }
 
-func TestGetRoutingRules(t *testing.T) {
+func TestGetRunningCommands(t *testing.T) {
 	t.Parallel()
 
-	tests := []struct {
-		name      string
-		topoDown  bool
-		rrIn      *vschemapb.RoutingRules
-		expected  *vschemapb.RoutingRules
-		shouldErr bool
-	}{
-		{
-			name: "success",
-			rrIn: &vschemapb.RoutingRules{
-				Rules: []*vschemapb.RoutingRule{
-					{
-						FromTable: "t1",
-						ToTables:  []string{"t2", "t3"},
-					},
-				},
-			},
-			expected: &vschemapb.RoutingRules{
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+
+	_, done := commandtracker.Default.Start(ctx, []string{"ValidateSchemaKeyspace", "testkeyspace"})
+
+	hasCommand := func(commands []*vtctldatapb.RunningCommand) bool {
+		for _, cmd := range commands {
+			if len(cmd.Args) == 2 && cmd.Args[0] == "ValidateSchemaKeyspace" && cmd.Args[1] == "testkeyspace" {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	resp, err := vtctld.GetRunningCommands(ctx, &vtctldatapb.GetRunningCommandsRequest{})
+	require.NoError(t, err)
+	assert.True(t, hasCommand(resp.Commands), "running command should be listed in %v", resp.Commands)
+
+	done()
+
+	resp, err = vtctld.GetRunningCommands(ctx, &vtctldatapb.GetRunningCommandsRequest{})
+	require.NoError(t, err)
+	assert.False(t, hasCommand(resp.Commands), "finished command should not be listed in %v", resp.Commands)
+}
+
+func TestGetRoutingRules(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		topoDown  bool
+		rrIn      *vschemapb.RoutingRules
+		expected  *vschemapb.RoutingRules
+		shouldErr bool
+	}{
+		{
+			name: "success",
+			rrIn: &vschemapb.RoutingRules{
+				Rules: []*vschemapb.RoutingRule{
+					{
+						FromTable: "t1",
+						ToTables:  []string{"t2", "t3"},
+					},
+				},
+			},
+			expected: &vschemapb.RoutingRules{
 				Rules: []*vschemapb.RoutingRule{
 					{
 						FromTable: "t1",
@@ -6187,6 +7250,133 @@ func TestGetTablet(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestGetTabletPlanCache(t *testing.T) {
+	t.Parallel()
+
+	entries := []*tabletmanagerdatapb.PlanCacheEntry{
+		{
+			Query:       "select * from t1 where id = 1",
+			Fingerprint: "select * from t1 where id = :id",
+			PlanType:    "Select",
+			Tables:      []string{"t1"},
+			QueryCount:  10,
+		},
+	}
+	tests := []struct {
+		name      string
+		tablets   []*topodatapb.Tablet
+		tmc       testutil.TabletManagerClient
+		req       *vtctldatapb.GetTabletPlanCacheRequest
+		expected  *vtctldatapb.GetTabletPlanCacheResponse
+		shouldErr bool
+	}{
+		{
+			name: "ok",
+			tablets: []*topodatapb.Tablet{
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  100,
+					},
+				},
+			},
+			tmc: testutil.TabletManagerClient{
+				GetPlanCacheResults: map[string]struct {
+					Response *tabletmanagerdatapb.GetPlanCacheResponse
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Response: &tabletmanagerdatapb.GetPlanCacheResponse{Entries: entries},
+					},
+				},
+			},
+			req: &vtctldatapb.GetTabletPlanCacheRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+				Tables: []string{"t1"},
+			},
+			expected: &vtctldatapb.GetTabletPlanCacheResponse{
+				Entries: entries,
+			},
+		},
+		{
+			name: "no tablet",
+			tmc: testutil.TabletManagerClient{
+				GetPlanCacheResults: map[string]struct {
+					Response *tabletmanagerdatapb.GetPlanCacheResponse
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Response: &tabletmanagerdatapb.GetPlanCacheResponse{Entries: entries},
+					},
+				},
+			},
+			req: &vtctldatapb.GetTabletPlanCacheRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			name: "tmc call failed",
+			tablets: []*topodatapb.Tablet{
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  100,
+					},
+				},
+			},
+			tmc: testutil.TabletManagerClient{
+				GetPlanCacheResults: map[string]struct {
+					Response *tabletmanagerdatapb.GetPlanCacheResponse
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Error: assert.AnError,
+					},
+				},
+			},
+			req: &vtctldatapb.GetTabletPlanCacheRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddTablets(ctx, t, ts, nil, tt.tablets...)
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, &tt.tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(ts)
+			})
+			resp, err := vtctld.GetTabletPlanCache(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
+		})
+	}
+}
+
 func TestGetTablets(t *testing.T) {
 	t.Parallel()
 
@@ -6649,6 +7839,138 @@ func TestGetTablets(t *testing.T) {
 	}
 }
 
+func TestGetTabletsPagination(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1", "zone2")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+
+	var tablets []*topodatapb.Tablet
+	for _, cell := range []string{"zone2", "zone1"} {
+		for uid := uint32(5); uid > 0; uid-- {
+			tags := map[string]string{"hardware": "ssd"}
+			if uid%2 == 0 {
+				tags["hardware"] = "nvme"
+			}
+
+			tablets = append(tablets, &topodatapb.Tablet{
+				Alias:    &topodatapb.TabletAlias{Cell: cell, Uid: uid},
+				Keyspace: "testkeyspace",
+				Shard:    "-",
+				Type:     topodatapb.TabletType_REPLICA,
+				Tags:     tags,
+			})
+		}
+	}
+	testutil.AddTablets(ctx, t, ts, nil, tablets...)
+
+	aliases := func(tablets []*topodatapb.Tablet) []string {
+		s := make([]string, len(tablets))
+		for i, tablet := range tablets {
+			s[i] = topoproto.TabletAliasString(tablet.Alias)
+		}
+		return s
+	}
+
+	t.Run("pages", func(t *testing.T) {
+		var (
+			got   []string
+			token string
+			pages int
+		)
+		for {
+			resp, err := vtctld.GetTablets(ctx, &vtctldatapb.GetTabletsRequest{
+				Tags:      map[string]string{"hardware": "ssd"},
+				PageSize:  2,
+				PageToken: token,
+			})
+			require.NoError(t, err)
+			assert.LessOrEqual(t, len(resp.Tablets), 2)
+
+			got = append(got, aliases(resp.Tablets)...)
+			pages++
+
+			if resp.NextPageToken == "" {
+				break
+			}
+			token = resp.NextPageToken
+		}
+
+		assert.Equal(t, []string{
+			"zone1-0000000001",
+			"zone1-0000000003",
+			"zone1-0000000005",
+			"zone2-0000000001",
+			"zone2-0000000003",
+			"zone2-0000000005",
+		}, got)
+		assert.Equal(t, 3, pages)
+	})
+
+	t.Run("tablet added between pages", func(t *testing.T) {
+		resp, err := vtctld.GetTablets(ctx, &vtctldatapb.GetTabletsRequest{
+			Cells:    []string{"zone1"},
+			PageSize: 3,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"zone1-0000000001", "zone1-0000000002", "zone1-0000000003"}, aliases(resp.Tablets))
+
+		// A tablet sorting before the page token does not shift the next page.
+		testutil.AddTablet(ctx, t, ts, &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 0},
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+			Type:     topodatapb.TabletType_REPLICA,
+		}, nil)
+		defer func() {
+			require.NoError(t, ts.DeleteTablet(ctx, &topodatapb.TabletAlias{Cell: "zone1", Uid: 0}))
+		}()
+
+		resp, err = vtctld.GetTablets(ctx, &vtctldatapb.GetTabletsRequest{
+			Cells:     []string{"zone1"},
+			PageSize:  3,
+			PageToken: resp.NextPageToken,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"zone1-0000000004", "zone1-0000000005"}, aliases(resp.Tablets))
+		assert.Empty(t, resp.NextPageToken)
+	})
+
+	t.Run("invalid page token", func(t *testing.T) {
+		_, err := vtctld.GetTablets(ctx, &vtctldatapb.GetTabletsRequest{PageToken: "not a token"})
+		assert.ErrorContains(t, err, "invalid page token")
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		client := localvtctldclient.New(vtctld)
+		stream, err := client.GetTabletsStream(ctx, &vtctldatapb.GetTabletsRequest{
+			Tags:     map[string]string{"hardware": "nvme"},
+			PageSize: 3,
+		})
+		require.NoError(t, err)
+
+		var got [][]string
+		for {
+			resp, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+
+			got = append(got, aliases(resp.Tablets))
+		}
+
+		assert.Equal(t, [][]string{
+			{"zone1-0000000002", "zone1-0000000004", "zone2-0000000002"},
+			{"zone2-0000000004"},
+		}, got)
+	})
+}
+
 func TestGetTopologyPath(t *testing.T) {
 	t.Parallel()
 
@@ -6738,6 +8060,147 @@ func TestGetTopologyPath(t *testing.T) {
 	}
 }
 
+func TestGetUnresolvedTransactions(t *testing.T) {
+	t.Parallel()
+
+	tablets := []*topodatapb.Tablet{
+		{
+			Keyspace: "ks",
+			Shard:    "-80",
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  100,
+			},
+			Type: topodatapb.TabletType_PRIMARY,
+		},
+		{
+			Keyspace: "ks",
+			Shard:    "80-",
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  200,
+			},
+			Type: topodatapb.TabletType_PRIMARY,
+		},
+	}
+	tx1 := &querypb.TransactionMetadata{
+		Dtid:  "ks:-80:1",
+		State: querypb.TransactionState_COMMIT,
+		Participants: []*querypb.Target{{
+			Keyspace:   "ks",
+			Shard:      "80-",
+			TabletType: topodatapb.TabletType_PRIMARY,
+		}},
+	}
+	tx2 := &querypb.TransactionMetadata{
+		Dtid:  "ks:80-:2",
+		State: querypb.TransactionState_PREPARE,
+		Participants: []*querypb.Target{{
+			Keyspace:   "ks",
+			Shard:      "-80",
+			TabletType: topodatapb.TabletType_PRIMARY,
+		}},
+	}
+	tx3 := &querypb.TransactionMetadata{
+		Dtid:  "ks:-80:3",
+		State: querypb.TransactionState_ROLLBACK,
+		Participants: []*querypb.Target{{
+			Keyspace:   "ks",
+			Shard:      "80-",
+			TabletType: topodatapb.TabletType_PRIMARY,
+		}},
+	}
+
+	tests := []struct {
+		name      string
+		tmc       *testutil.TabletManagerClient
+		req       *vtctldatapb.GetUnresolvedTransactionsRequest
+		expected  *vtctldatapb.GetUnresolvedTransactionsResponse
+		shouldErr bool
+	}{
+		{
+			name: "ok",
+			tmc: &testutil.TabletManagerClient{
+				GetUnresolvedTransactionsResults: map[string]struct {
+					Response *tabletmanagerdatapb.GetUnresolvedTransactionsResponse
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Response: &tabletmanagerdatapb.GetUnresolvedTransactionsResponse{
+							Transactions: []*querypb.TransactionMetadata{tx1, tx3},
+						},
+					},
+					"zone1-0000000200": {
+						Response: &tabletmanagerdatapb.GetUnresolvedTransactionsResponse{
+							Transactions: []*querypb.TransactionMetadata{tx2},
+						},
+					},
+				},
+			},
+			req: &vtctldatapb.GetUnresolvedTransactionsRequest{
+				Keyspace:   "ks",
+				AbandonAge: 60,
+			},
+			expected: &vtctldatapb.GetUnresolvedTransactionsResponse{
+				Transactions: []*querypb.TransactionMetadata{tx1, tx3, tx2},
+			},
+		},
+		{
+			name: "tablet failure",
+			tmc: &testutil.TabletManagerClient{
+				GetUnresolvedTransactionsResults: map[string]struct {
+					Response *tabletmanagerdatapb.GetUnresolvedTransactionsResponse
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Response: &tabletmanagerdatapb.GetUnresolvedTransactionsResponse{},
+					},
+					"zone1-0000000200": {
+						Error: assert.AnError,
+					},
+				},
+			},
+			req: &vtctldatapb.GetUnresolvedTransactionsRequest{
+				Keyspace: "ks",
+			},
+			shouldErr: true,
+		},
+		{
+			name: "keyspace not found",
+			tmc:  &testutil.TabletManagerClient{},
+			req: &vtctldatapb.GetUnresolvedTransactionsRequest{
+				Keyspace: "missing",
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ts := memorytopo.NewServer(ctx, "zone1")
+
+			testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+				AlsoSetShardPrimary: true,
+			}, tablets...)
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, test.tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(ts)
+			})
+
+			resp, err := vtctld.GetUnresolvedTransactions(ctx, test.req)
+			if test.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, test.expected, resp)
+		})
+	}
+}
+
 func TestGetVSchema(t *testing.T) {
 	t.Parallel()
 
@@ -6777,14 +8240,162 @@ func TestGetVSchema(t *testing.T) {
 		utils.MustMatch(t, expected, resp)
 	})
 
-	t.Run("not found", func(t *testing.T) {
-		t.Parallel()
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := vtctld.GetVSchema(ctx, &vtctldatapb.GetVSchemaRequest{
+			Keyspace: "doesnotexist",
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestInvalidateTabletPlanCache(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		tablets   []*topodatapb.Tablet
+		tmc       testutil.TabletManagerClient
+		req       *vtctldatapb.InvalidateTabletPlanCacheRequest
+		expected  *vtctldatapb.InvalidateTabletPlanCacheResponse
+		shouldErr bool
+	}{
+		{
+			name: "ok",
+			tablets: []*topodatapb.Tablet{
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  100,
+					},
+				},
+			},
+			tmc: testutil.TabletManagerClient{
+				InvalidatePlanCacheResults: map[string]struct {
+					Response *tabletmanagerdatapb.InvalidatePlanCacheResponse
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Response: &tabletmanagerdatapb.InvalidatePlanCacheResponse{Invalidated: 4},
+					},
+				},
+			},
+			req: &vtctldatapb.InvalidateTabletPlanCacheRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+				Tables: []string{"t1"},
+			},
+			expected: &vtctldatapb.InvalidateTabletPlanCacheResponse{
+				Invalidated: 4,
+			},
+		},
+		{
+			name: "nothing to invalidate",
+			tablets: []*topodatapb.Tablet{
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  100,
+					},
+				},
+			},
+			tmc: testutil.TabletManagerClient{
+				InvalidatePlanCacheResults: map[string]struct {
+					Response *tabletmanagerdatapb.InvalidatePlanCacheResponse
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Response: &tabletmanagerdatapb.InvalidatePlanCacheResponse{Invalidated: 4},
+					},
+				},
+			},
+			req: &vtctldatapb.InvalidateTabletPlanCacheRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			name: "no tablet",
+			tmc: testutil.TabletManagerClient{
+				InvalidatePlanCacheResults: map[string]struct {
+					Response *tabletmanagerdatapb.InvalidatePlanCacheResponse
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Response: &tabletmanagerdatapb.InvalidatePlanCacheResponse{Invalidated: 4},
+					},
+				},
+			},
+			req: &vtctldatapb.InvalidateTabletPlanCacheRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+				All: true,
+			},
+			shouldErr: true,
+		},
+		{
+			name: "tmc call failed",
+			tablets: []*topodatapb.Tablet{
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  100,
+					},
+				},
+			},
+			tmc: testutil.TabletManagerClient{
+				InvalidatePlanCacheResults: map[string]struct {
+					Response *tabletmanagerdatapb.InvalidatePlanCacheResponse
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Error: assert.AnError,
+					},
+				},
+			},
+			req: &vtctldatapb.InvalidateTabletPlanCacheRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+				All: true,
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddTablets(ctx, t, ts, nil, tt.tablets...)
 
-		_, err := vtctld.GetVSchema(ctx, &vtctldatapb.GetVSchemaRequest{
-			Keyspace: "doesnotexist",
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, &tt.tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(ts)
+			})
+			resp, err := vtctld.InvalidateTabletPlanCache(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
 		})
-		assert.Error(t, err)
-	})
+	}
 }
 
 func TestPingTablet(t *testing.T) {
@@ -7073,6 +8684,64 @@ func TestPlannedReparentShard(t *testing.T) {
 	}
 }
 
+func TestPurgeBackups(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx)
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+
+	testutil.BackupStorage.Backups = map[string][]string{
+		"testkeyspace/-": {"2021-06-11.123456.zone1-101", "2021-06-12.123456.zone1-101", "2021-06-13.123456.zone1-101", "2021-06-14.123456.zone1-101", "2021-06-15.123456.zone1-101"},
+	}
+	testutil.BackupStorage.Files = map[string]string{
+		"testkeyspace/-/2021-06-11.123456.zone1-101/MANIFEST": `{"BackupMethod": "builtin"}`,
+		"testkeyspace/-/2021-06-12.123456.zone1-101/MANIFEST": `{"BackupMethod": "builtin", "Incremental": true}`,
+		"testkeyspace/-/2021-06-13.123456.zone1-101/MANIFEST": `{"BackupMethod": "builtin"}`,
+		"testkeyspace/-/2021-06-14.123456.zone1-101/MANIFEST": `{"BackupMethod": "builtin", "Incremental": true}`,
+		// the last backup is incomplete
+	}
+	defer func() { testutil.BackupStorage.Files = map[string]string{} }()
+
+	want := &vtctldatapb.PurgeBackupsResponse{
+		PurgedBackups: []string{"2021-06-11.123456.zone1-101", "2021-06-12.123456.zone1-101"},
+		KeptBackups:   []string{"2021-06-13.123456.zone1-101", "2021-06-14.123456.zone1-101", "2021-06-15.123456.zone1-101"},
+	}
+
+	t.Run("no retention policy", func(t *testing.T) {
+		_, err := vtctld.PurgeBackups(ctx, &vtctldatapb.PurgeBackupsRequest{
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+		})
+		assert.ErrorContains(t, err, "no backup retention policy")
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		resp, err := vtctld.PurgeBackups(ctx, &vtctldatapb.PurgeBackupsRequest{
+			Keyspace:        "testkeyspace",
+			Shard:           "-",
+			KeepFullBackups: 1,
+			DryRun:          true,
+		})
+		require.NoError(t, err)
+		utils.MustMatch(t, want, resp)
+		assert.Len(t, testutil.BackupStorage.Backups["testkeyspace/-"], 5)
+	})
+
+	t.Run("purge", func(t *testing.T) {
+		resp, err := vtctld.PurgeBackups(ctx, &vtctldatapb.PurgeBackupsRequest{
+			Keyspace:         "testkeyspace",
+			Shard:            "-",
+			KeepFullBackups:  1,
+			MinRetentionTime: protoutil.DurationToProto(24 * time.Hour),
+		})
+		require.NoError(t, err)
+		utils.MustMatch(t, want, resp)
+		assert.Equal(t, want.KeptBackups, testutil.BackupStorage.Backups["testkeyspace/-"])
+	})
+}
+
 func TestRebuildKeyspaceGraph(t *testing.T) {
 	t.Parallel()
 
@@ -7306,6 +8975,145 @@ func TestRefreshState(t *testing.T) {
 	}
 }
 
+func TestRefreshStateByFilter(t *testing.T) {
+	t.Parallel()
+
+	tablets := []*topodatapb.Tablet{
+		{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+			Keyspace: "ks",
+			Shard:    "-80",
+			Type:     topodatapb.TabletType_PRIMARY,
+		},
+		{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+			Keyspace: "ks",
+			Shard:    "-80",
+			Type:     topodatapb.TabletType_REPLICA,
+		},
+		{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone2", Uid: 200},
+			Keyspace: "ks",
+			Shard:    "-80",
+			Type:     topodatapb.TabletType_REPLICA,
+		},
+		{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 300},
+			Keyspace: "ks",
+			Shard:    "80-",
+			Type:     topodatapb.TabletType_PRIMARY,
+		},
+	}
+
+	tests := []struct {
+		name                string
+		req                 *vtctldatapb.RefreshStateByFilterRequest
+		refreshStateResults map[string]error
+		expected            *vtctldatapb.RefreshStateByFilterResponse
+		shouldErr           bool
+	}{
+		{
+			name: "whole keyspace",
+			req: &vtctldatapb.RefreshStateByFilterRequest{
+				Keyspace:    "ks",
+				Concurrency: 2,
+			},
+			refreshStateResults: map[string]error{
+				"zone1-0000000100": nil,
+				"zone1-0000000101": nil,
+				"zone2-0000000200": nil,
+				"zone1-0000000300": nil,
+			},
+			expected: &vtctldatapb.RefreshStateByFilterResponse{
+				Results: []*vtctldatapb.RefreshStateByFilterResponse_Result{
+					{TabletAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}},
+					{TabletAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 101}},
+					{TabletAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 300}},
+					{TabletAlias: &topodatapb.TabletAlias{Cell: "zone2", Uid: 200}},
+				},
+			},
+		},
+		{
+			name: "tablet type and cell",
+			req: &vtctldatapb.RefreshStateByFilterRequest{
+				Keyspace:   "ks",
+				Cells:      []string{"zone2"},
+				TabletType: topodatapb.TabletType_REPLICA,
+			},
+			refreshStateResults: map[string]error{
+				"zone2-0000000200": nil,
+			},
+			expected: &vtctldatapb.RefreshStateByFilterResponse{
+				Results: []*vtctldatapb.RefreshStateByFilterResponse_Result{
+					{TabletAlias: &topodatapb.TabletAlias{Cell: "zone2", Uid: 200}},
+				},
+			},
+		},
+		{
+			name: "partial failure",
+			req: &vtctldatapb.RefreshStateByFilterRequest{
+				Keyspace: "ks",
+				Shard:    "-80",
+			},
+			refreshStateResults: map[string]error{
+				"zone1-0000000100": nil,
+				"zone1-0000000101": fmt.Errorf("%w: RefreshState failed", assert.AnError),
+				"zone2-0000000200": nil,
+			},
+			expected: &vtctldatapb.RefreshStateByFilterResponse{
+				Results: []*vtctldatapb.RefreshStateByFilterResponse_Result{
+					{TabletAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}},
+					{
+						TabletAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+						Error:       fmt.Errorf("%w: RefreshState failed", assert.AnError).Error(),
+					},
+					{TabletAlias: &topodatapb.TabletAlias{Cell: "zone2", Uid: 200}},
+				},
+			},
+		},
+		{
+			name:      "no keyspace",
+			req:       &vtctldatapb.RefreshStateByFilterRequest{},
+			shouldErr: true,
+		},
+		{
+			name: "unknown keyspace",
+			req: &vtctldatapb.RefreshStateByFilterRequest{
+				Keyspace: "unknown",
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ts := memorytopo.NewServer(ctx, "zone1", "zone2")
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, &testutil.TabletManagerClient{
+				RefreshStateResults: tt.refreshStateResults,
+			}, func(ts *topo.Server) vtctlservicepb.VtctldServer { return NewVtctldServer(ts) })
+
+			testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+				AlsoSetShardPrimary: true,
+			}, tablets...)
+
+			resp, err := vtctld.RefreshStateByFilter(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
+		})
+	}
+}
+
 func TestRefreshStateByShard(t *testing.T) {
 	t.Parallel()
 
@@ -8162,6 +9970,44 @@ func TestRemoveShardCell(t *testing.T) {
 			expected:  &vtctldatapb.RemoveShardCellResponse{},
 			shouldErr: false,
 		},
+		{
+			name: "success/dry run",
+			shards: []*vtctldatapb.Shard{
+				{
+					Keyspace: "testkeyspace",
+					Name:     "-",
+				},
+			},
+			replicationGraphs: []*topo.ShardReplicationInfo{
+				topo.NewShardReplicationInfo(&topodatapb.ShardReplication{
+					Nodes: []*topodatapb.ShardReplication_Node{
+						{
+							TabletAlias: &topodatapb.TabletAlias{
+								Cell: "zone2",
+								Uid:  200,
+							},
+						},
+					},
+				}, "zone2", "testkeyspace", "-"),
+			},
+			req: &vtctldatapb.RemoveShardCellRequest{
+				Keyspace:  "testkeyspace",
+				ShardName: "-",
+				Cell:      "zone2",
+				Recursive: true,
+				DryRun:    true,
+			},
+			expected: &vtctldatapb.RemoveShardCellResponse{
+				DryRunResults: []string{
+					"Delete tablet zone2-0000000200",
+					"Delete ShardReplication for shard testkeyspace/- in cell zone2",
+					"Remove shard testkeyspace/- from rdonly SrvKeyspace partitions in cell zone2",
+					"Remove shard testkeyspace/- from replica SrvKeyspace partitions in cell zone2",
+					"Remove shard testkeyspace/- from primary SrvKeyspace partitions in cell zone2",
+				},
+			},
+			shouldErr: false,
+		},
 		{
 			name: "success/no tablets",
 			shards: []*vtctldatapb.Shard{
@@ -8404,6 +10250,83 @@ func TestRemoveShardCell(t *testing.T) {
 	}
 }
 
+func TestReparentPreflight(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	testutil.AddKeyspace(ctx, t, ts, &vtctldatapb.Keyspace{
+		Name:     "testkeyspace",
+		Keyspace: &topodatapb.Keyspace{DurabilityPolicy: "none"},
+	})
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{AlsoSetShardPrimary: true},
+		&topodatapb.Tablet{
+			Alias:                &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+			Keyspace:             "testkeyspace",
+			Shard:                "-",
+			Type:                 topodatapb.TabletType_PRIMARY,
+			PrimaryTermStartTime: &vttime.Time{Seconds: 100},
+		},
+		&topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+			Type:     topodatapb.TabletType_REPLICA,
+		},
+	)
+
+	tmc := &testutil.TabletManagerClient{
+		FullStatusResults: map[string]struct {
+			Status *replicationdatapb.FullStatus
+			Error  error
+		}{
+			"zone1-0000000100": {
+				Status: &replicationdatapb.FullStatus{
+					PrimaryStatus: &replicationdatapb.PrimaryStatus{Position: "MySQL56/3e11fa47-71ca-11e1-9e33-c80aa9429562:1-10"},
+				},
+			},
+			"zone1-0000000101": {
+				Status: &replicationdatapb.FullStatus{
+					ReplicationStatus: &replicationdatapb.Status{
+						Position: "MySQL56/3e11fa47-71ca-11e1-9e33-c80aa9429562:1-10",
+						IoState:  int32(replication.ReplicationStateRunning),
+						SqlState: int32(replication.ReplicationStateRunning),
+					},
+				},
+			},
+		},
+	}
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+
+	resp, err := vtctld.ReparentPreflight(ctx, &vtctldatapb.ReparentPreflightRequest{
+		Keyspace: "testkeyspace",
+		Shard:    "-",
+	})
+	require.NoError(t, err)
+	assert.True(t, resp.Ok, "%+v", resp.Checks)
+	utils.MustMatch(t, &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}, resp.CurrentPrimary)
+	utils.MustMatch(t, &topodatapb.TabletAlias{Cell: "zone1", Uid: 101}, resp.NewPrimary)
+
+	// The primary-elect cannot be the tablet to avoid.
+	resp, err = vtctld.ReparentPreflight(ctx, &vtctldatapb.ReparentPreflightRequest{
+		Keyspace:     "testkeyspace",
+		Shard:        "-",
+		NewPrimary:   &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+		AvoidPrimary: &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+	})
+	require.NoError(t, err)
+	assert.False(t, resp.Ok)
+
+	_, err = vtctld.ReparentPreflight(ctx, &vtctldatapb.ReparentPreflightRequest{
+		Keyspace: "testkeyspace",
+		Shard:    "80-",
+	})
+	assert.Error(t, err, "shard does not exist")
+}
+
 func TestReparentTablet(t *testing.T) {
 	t.Parallel()
 
@@ -9400,20 +11323,130 @@ func TestRunHealthCheck(t *testing.T) {
 
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
-
+
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddTablets(ctx, t, ts, nil, tt.tablets...)
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, &tt.tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(ts)
+			})
+			_, err := vtctld.RunHealthCheck(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestScheduleCommand(t *testing.T) {
+	t.Parallel()
+
+	args := []string{"PlannedReparentShard", "--new_primary", "zone1-0000000101", "testkeyspace/-"}
+	tests := []struct {
+		name             string
+		req              *vtctldatapb.ScheduleCommandRequest
+		expectedRunAfter time.Duration
+		shouldErr        bool
+	}{
+		{
+			name: "run after",
+			req: &vtctldatapb.ScheduleCommandRequest{
+				Args:          args,
+				RunAfter:      protoutil.DurationToProto(time.Hour),
+				ActionTimeout: protoutil.DurationToProto(time.Minute),
+			},
+			expectedRunAfter: time.Hour,
+		},
+		{
+			name: "run at",
+			req: &vtctldatapb.ScheduleCommandRequest{
+				Args:  args,
+				RunAt: protoutil.TimeToProto(time.Now().Add(2 * time.Hour)),
+			},
+			expectedRunAfter: 2 * time.Hour,
+		},
+		{
+			name: "no command",
+			req: &vtctldatapb.ScheduleCommandRequest{
+				RunAfter: protoutil.DurationToProto(time.Hour),
+			},
+			shouldErr: true,
+		},
+		{
+			name: "no time",
+			req: &vtctldatapb.ScheduleCommandRequest{
+				Args: args,
+			},
+			shouldErr: true,
+		},
+		{
+			name: "both times",
+			req: &vtctldatapb.ScheduleCommandRequest{
+				Args:     args,
+				RunAt:    protoutil.TimeToProto(time.Now().Add(time.Hour)),
+				RunAfter: protoutil.DurationToProto(time.Hour),
+			},
+			shouldErr: true,
+		},
+		{
+			name: "run at in the past",
+			req: &vtctldatapb.ScheduleCommandRequest{
+				Args:  args,
+				RunAt: protoutil.TimeToProto(time.Now().Add(-time.Hour)),
+			},
+			shouldErr: true,
+		},
+		{
+			name: "negative run after",
+			req: &vtctldatapb.ScheduleCommandRequest{
+				Args:     args,
+				RunAfter: protoutil.DurationToProto(-time.Hour),
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
 			ts := memorytopo.NewServer(ctx, "zone1")
-			testutil.AddTablets(ctx, t, ts, nil, tt.tablets...)
-
-			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, &tt.tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
 				return NewVtctldServer(ts)
 			})
-			_, err := vtctld.RunHealthCheck(ctx, tt.req)
+
+			start := time.Now()
+			resp, err := vtctld.ScheduleCommand(ctx, tt.req)
 			if tt.shouldErr {
 				assert.Error(t, err)
+
+				cmds, err := ts.GetScheduledCommands(ctx)
+				require.NoError(t, err)
+				assert.Empty(t, cmds, "no command should be scheduled")
 				return
 			}
 
 			require.NoError(t, err)
+
+			cmd := resp.ScheduledCommand
+			assert.NotEmpty(t, cmd.Id)
+			assert.Equal(t, tt.req.Args, cmd.Args)
+			assert.Equal(t, vtctldatapb.ScheduledCommand_PENDING, cmd.State)
+			assert.Equal(t, "unauthenticated caller", cmd.CreatedBy)
+			utils.MustMatch(t, tt.req.ActionTimeout, cmd.ActionTimeout)
+
+			runAt := protoutil.TimeFromProto(cmd.RunAt)
+			assert.WithinDuration(t, start.Add(tt.expectedRunAfter), runAt, time.Minute)
+
+			stored, err := ts.GetScheduledCommand(ctx, cmd.Id)
+			require.NoError(t, err)
+			utils.MustMatch(t, cmd, stored.ScheduledCommand)
 		})
 	}
 }
@@ -11743,6 +13776,222 @@ func TestUpdateCellsAlias(t *testing.T) {
 	}
 }
 
+func TestUpdateOnlineDDLSchedulerConfig(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "zone1", "zone2")
+	defer ts.Close()
+
+	testutil.AddKeyspace(ctx, t, ts, &vtctldatapb.Keyspace{
+		Name: "ks1",
+		Keyspace: &topodatapb.Keyspace{
+			OnlineDdlSchedulerConfig: &topodatapb.OnlineDDLSchedulerConfig{
+				MaxConcurrentMigrations: 2,
+				PriorityClasses:         map[string]int32{"low": -1, "old": 5},
+			},
+		},
+	})
+	// Only zone1 serves the keyspace.
+	require.NoError(t, ts.UpdateSrvKeyspace(ctx, "zone1", "ks1", &topodatapb.SrvKeyspace{}))
+
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+
+	resp, err := vtctld.UpdateOnlineDDLSchedulerConfig(ctx, &vtctldatapb.UpdateOnlineDDLSchedulerConfigRequest{
+		Keyspace:              "ks1",
+		PriorityClasses:       map[string]int32{"urgent": 10},
+		RemovePriorityClasses: []string{"old"},
+		LaunchWindow:          "* 1-5 * * *",
+		LaunchWindowSet:       true,
+	})
+	require.NoError(t, err)
+	expected := &topodatapb.OnlineDDLSchedulerConfig{
+		MaxConcurrentMigrations: 2,
+		PriorityClasses:         map[string]int32{"low": -1, "urgent": 10},
+		LaunchWindow:            "* 1-5 * * *",
+	}
+	utils.MustMatch(t, expected, resp.Config)
+
+	ki, err := ts.GetKeyspace(ctx, "ks1")
+	require.NoError(t, err)
+	utils.MustMatch(t, expected, ki.OnlineDdlSchedulerConfig)
+	srvKeyspace, err := ts.GetSrvKeyspace(ctx, "zone1", "ks1")
+	require.NoError(t, err)
+	utils.MustMatch(t, expected, srvKeyspace.OnlineDdlSchedulerConfig)
+
+	// An empty launch window and no limit on the concurrent migrations remove them.
+	resp, err = vtctld.UpdateOnlineDDLSchedulerConfig(ctx, &vtctldatapb.UpdateOnlineDDLSchedulerConfigRequest{
+		Keyspace:                   "ks1",
+		MaxConcurrentMigrationsSet: true,
+		LaunchWindowSet:            true,
+	})
+	require.NoError(t, err)
+	utils.MustMatch(t, &topodatapb.OnlineDDLSchedulerConfig{
+		PriorityClasses: map[string]int32{"low": -1, "urgent": 10},
+	}, resp.Config)
+
+	_, err = vtctld.UpdateOnlineDDLSchedulerConfig(ctx, &vtctldatapb.UpdateOnlineDDLSchedulerConfigRequest{
+		Keyspace:                   "ks1",
+		MaxConcurrentMigrations:    -1,
+		MaxConcurrentMigrationsSet: true,
+	})
+	assert.ErrorContains(t, err, "max concurrent migrations must not be negative")
+	_, err = vtctld.UpdateOnlineDDLSchedulerConfig(ctx, &vtctldatapb.UpdateOnlineDDLSchedulerConfigRequest{
+		Keyspace:        "ks1",
+		LaunchWindow:    "* 25 * * *",
+		LaunchWindowSet: true,
+	})
+	assert.ErrorContains(t, err, "invalid launch window")
+	_, err = vtctld.UpdateOnlineDDLSchedulerConfig(ctx, &vtctldatapb.UpdateOnlineDDLSchedulerConfigRequest{
+		Keyspace: "ks2",
+	})
+	assert.ErrorContains(t, err, "node doesn't exist")
+}
+
+func TestUpgradeMysql(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tablets := []*topodatapb.Tablet{
+		{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  100,
+			},
+			Keyspace: "ks",
+			Shard:    "-",
+			Type:     topodatapb.TabletType_REPLICA,
+		},
+		{
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  200,
+			},
+			Keyspace: "ks",
+			Shard:    "-",
+			Type:     topodatapb.TabletType_PRIMARY,
+		},
+	}
+	tmc := &testutil.TabletManagerClient{
+		UpgradeMysqlResults: map[string]struct {
+			Events []*logutilpb.Event
+			Error  error
+		}{
+			"zone1-0000000100": {
+				Events: []*logutilpb.Event{{}, {}, {}},
+			},
+			"zone1-0000000200": {
+				Events: []*logutilpb.Event{{}, {}},
+				Error:  assert.AnError,
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		req       *vtctldatapb.UpgradeMysqlRequest
+		shouldErr bool
+		assertion func(t *testing.T, responses []*vtctldatapb.UpgradeMysqlResponse, err error)
+	}{
+		{
+			name: "ok",
+			req: &vtctldatapb.UpgradeMysqlRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+				ExpectedVersion: "8.0.36",
+			},
+			assertion: func(t *testing.T, responses []*vtctldatapb.UpgradeMysqlResponse, err error) {
+				assert.ErrorIs(t, err, io.EOF, "expected Recv loop to end with io.EOF")
+				assert.Equal(t, 3, len(responses), "expected 3 messages from upgrademysqlclient stream")
+			},
+		},
+		{
+			name: "primary",
+			req: &vtctldatapb.UpgradeMysqlRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  200,
+				},
+			},
+			assertion: func(t *testing.T, responses []*vtctldatapb.UpgradeMysqlResponse, err error) {
+				assert.NotErrorIs(t, err, io.EOF, "expected upgrademysqlclient stream to close with non-EOF")
+				assert.Zero(t, len(responses), "expected no upgrademysqlclient messages")
+			},
+		},
+		{
+			name: "allow primary with failed upgrade",
+			req: &vtctldatapb.UpgradeMysqlRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  200,
+				},
+				AllowPrimary: true,
+			},
+			assertion: func(t *testing.T, responses []*vtctldatapb.UpgradeMysqlResponse, err error) {
+				assert.ErrorContains(t, err, assert.AnError.Error())
+				assert.Equal(t, 2, len(responses), "expected 2 messages from upgrademysqlclient stream")
+			},
+		},
+		{
+			name: "no such tablet",
+			req: &vtctldatapb.UpgradeMysqlRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone404",
+					Uid:  404,
+				},
+			},
+			assertion: func(t *testing.T, responses []*vtctldatapb.UpgradeMysqlResponse, err error) {
+				assert.NotErrorIs(t, err, io.EOF, "expected upgrademysqlclient stream to close with non-EOF")
+				assert.Zero(t, len(responses), "expected no upgrademysqlclient messages")
+			},
+		},
+	}
+
+	ts := memorytopo.NewServer(ctx, "zone1")
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+		AlsoSetShardPrimary: true,
+	}, tablets...)
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+	client := localvtctldclient.New(vtctld)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := client.UpgradeMysql(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			responses, err := func() (responses []*vtctldatapb.UpgradeMysqlResponse, err error) {
+				for {
+					resp, err := stream.Recv()
+					if err != nil {
+						return responses, err
+					}
+
+					responses = append(responses, resp)
+				}
+			}()
+
+			if tt.assertion != nil {
+				func() {
+					t.Helper()
+					tt.assertion(t, responses, err)
+				}()
+			}
+		})
+	}
+}
+
 func TestValidate(t *testing.T) {
 	t.Parallel()
 
@@ -11818,47 +14067,272 @@ func TestValidate(t *testing.T) {
 				Cell: "zone2",
 				Uid:  201,
 			},
-			Hostname: "ks2-80-00-replica1",
+			Hostname: "ks2-80-00-replica1",
+		},
+		{
+			Keyspace: "ks2",
+			Shard:    "80-",
+			Type:     topodatapb.TabletType_RDONLY,
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  103,
+			},
+			Hostname: "ks2-80-00-rdonly1",
+		},
+	}
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+		AlsoSetShardPrimary:  true,
+		ForceSetShardPrimary: true,
+		SkipShardCreation:    false,
+	}, tablets...)
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+
+	resp, err := vtctld.Validate(ctx, &vtctldatapb.ValidateRequest{
+		PingTablets: false,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &vtctldatapb.ValidateResponse{
+		ResultsByKeyspace: map[string]*vtctldatapb.ValidateKeyspaceResponse{
+			"ks1": {
+				ResultsByShard: map[string]*vtctldatapb.ValidateShardResponse{
+					"-": {},
+				},
+			},
+			"ks2": {
+				ResultsByShard: map[string]*vtctldatapb.ValidateShardResponse{
+					"-80": {},
+					"80-": {},
+				},
+			},
+		},
+	}, resp)
+}
+
+func TestValidateBackup(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx)
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+
+	testutil.BackupStorage.Backups = map[string][]string{
+		"testkeyspace/-": {"2021-06-11.123456.zone1-101", "2021-06-12.123456.zone1-101", "2021-06-13.123456.zone1-101"},
+	}
+	testutil.BackupStorage.Files = map[string]string{
+		"testkeyspace/-/2021-06-11.123456.zone1-101/MANIFEST": `{"BackupMethod": "builtin", "FileEntries": [{"Base": "Data", "Name": "ibdata1", "Hash": "` +
+			fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte("ibdata"))) + `"}]}`,
+		"testkeyspace/-/2021-06-11.123456.zone1-101/0": "ibdata",
+		"testkeyspace/-/2021-06-12.123456.zone1-101/MANIFEST": `{"BackupMethod": "builtin", "FileEntries": [{"Base": "Data", "Name": "ibdata1", "Hash": "00000000"}],
+			"Verification": {"Time": "2021-06-12T13:00:00Z", "Replica": "zone1-0000000102", "Position": "MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-615", "TablesChecked": 3, "Mismatches": ["testkeyspace.t2"]}}`,
+		"testkeyspace/-/2021-06-12.123456.zone1-101/0": "ibdata",
+		// the last backup is incomplete
+	}
+	defer func() { testutil.BackupStorage.Files = map[string]string{} }()
+
+	t.Run("named backup", func(t *testing.T) {
+		resp, err := vtctld.ValidateBackup(ctx, &vtctldatapb.ValidateBackupRequest{
+			Keyspace:   "testkeyspace",
+			Shard:      "-",
+			BackupName: "2021-06-11.123456.zone1-101",
+		})
+		require.NoError(t, err)
+		utils.MustMatch(t, &vtctldatapb.ValidateBackupResponse{
+			BackupName:   "2021-06-11.123456.zone1-101",
+			FilesChecked: 1,
+		}, resp)
+	})
+
+	t.Run("latest complete backup", func(t *testing.T) {
+		resp, err := vtctld.ValidateBackup(ctx, &vtctldatapb.ValidateBackupRequest{
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "2021-06-12.123456.zone1-101", resp.BackupName)
+		assert.EqualValues(t, 1, resp.FilesChecked)
+		require.Len(t, resp.Results, 2)
+		assert.Contains(t, resp.Results[0], "hash mismatch")
+		assert.Equal(t, "restore drill at 2021-06-12T13:00:00Z found tables which differ from replica zone1-0000000102: testkeyspace.t2", resp.Results[1])
+		utils.MustMatch(t, &vtctldatapb.BackupVerification{
+			Time: protoutil.TimeToProto(time.Date(2021, time.June, 12, 13, 0, 0, 0, time.UTC)),
+			Replica: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  102,
+			},
+			Position:      "MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-615",
+			TablesChecked: 3,
+			Mismatches:    []string{"testkeyspace.t2"},
+		}, resp.Verification)
+	})
+
+	t.Run("backup not found", func(t *testing.T) {
+		_, err := vtctld.ValidateBackup(ctx, &vtctldatapb.ValidateBackupRequest{
+			Keyspace:   "testkeyspace",
+			Shard:      "-",
+			BackupName: "doesnotexist",
+		})
+		assert.ErrorContains(t, err, "backup doesnotexist of testkeyspace/- not found")
+	})
+
+	t.Run("no complete backup", func(t *testing.T) {
+		_, err := vtctld.ValidateBackup(ctx, &vtctldatapb.ValidateBackupRequest{
+			Keyspace: "otherkeyspace",
+			Shard:    "-",
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestValidatePermissionsKeyspace(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1", "zone2")
+	tablets := []*topodatapb.Tablet{
+		{
+			Keyspace: "ks",
+			Shard:    "-80",
+			Type:     topodatapb.TabletType_PRIMARY,
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  100,
+			},
+		},
+		{
+			Keyspace: "ks",
+			Shard:    "-80",
+			Type:     topodatapb.TabletType_REPLICA,
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone2",
+				Uid:  200,
+			},
+		},
+		{
+			Keyspace: "ks",
+			Shard:    "80-",
+			Type:     topodatapb.TabletType_PRIMARY,
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  101,
+			},
 		},
 		{
-			Keyspace: "ks2",
+			Keyspace: "ks",
 			Shard:    "80-",
-			Type:     topodatapb.TabletType_RDONLY,
+			Type:     topodatapb.TabletType_REPLICA,
 			Alias: &topodatapb.TabletAlias{
-				Cell: "zone1",
-				Uid:  103,
+				Cell: "zone2",
+				Uid:  201,
 			},
-			Hostname: "ks2-80-00-rdonly1",
 		},
 	}
 	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
-		AlsoSetShardPrimary:  true,
-		ForceSetShardPrimary: true,
-		SkipShardCreation:    false,
+		AlsoSetShardPrimary: true,
 	}, tablets...)
-	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
-		return NewVtctldServer(ts)
-	})
 
-	resp, err := vtctld.Validate(ctx, &vtctldatapb.ValidateRequest{
-		PingTablets: false,
-	})
-	require.NoError(t, err)
-	assert.Equal(t, &vtctldatapb.ValidateResponse{
-		ResultsByKeyspace: map[string]*vtctldatapb.ValidateKeyspaceResponse{
-			"ks1": {
-				ResultsByShard: map[string]*vtctldatapb.ValidateShardResponse{
-					"-": {},
-				},
+	permissions := &tabletmanagerdatapb.Permissions{
+		UserPermissions: []*tabletmanagerdatapb.UserPermission{{
+			Host:       "%",
+			User:       "vt_app",
+			Privileges: map[string]string{"select": "Y"},
+		}},
+	}
+	otherPermissions := &tabletmanagerdatapb.Permissions{}
+
+	tests := []struct {
+		name        string
+		permissions map[string]*tabletmanagerdatapb.Permissions
+		// inconsistent lists the tablets expected to differ from the reference.
+		inconsistent []string
+	}{
+		{
+			name: "consistent",
+			permissions: map[string]*tabletmanagerdatapb.Permissions{
+				"zone1-0000000100": permissions,
+				"zone2-0000000200": permissions,
+				"zone1-0000000101": permissions,
+				"zone2-0000000201": permissions,
 			},
-			"ks2": {
-				ResultsByShard: map[string]*vtctldatapb.ValidateShardResponse{
-					"-80": {},
-					"80-": {},
-				},
+		},
+		{
+			name: "inconsistent",
+			permissions: map[string]*tabletmanagerdatapb.Permissions{
+				"zone1-0000000100": permissions,
+				"zone2-0000000200": permissions,
+				"zone1-0000000101": permissions,
+				"zone2-0000000201": otherPermissions,
 			},
+			inconsistent: []string{"zone2-0000000201"},
 		},
-	}, resp)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmc := &testutil.TabletManagerClient{
+				GetPermissionsResults: map[string]struct {
+					Permissions *tabletmanagerdatapb.Permissions
+					Error       error
+				}{},
+			}
+			for alias, p := range tt.permissions {
+				tmc.GetPermissionsResults[alias] = struct {
+					Permissions *tabletmanagerdatapb.Permissions
+					Error       error
+				}{Permissions: p}
+			}
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(ts)
+			})
+			client := localvtctldclient.New(vtctld)
+
+			stream, err := client.ValidatePermissionsKeyspace(ctx, &vtctldatapb.ValidatePermissionsKeyspaceRequest{
+				Keyspace:    "ks",
+				Concurrency: 1,
+			})
+			require.NoError(t, err)
+
+			var (
+				progress     []*vtctldatapb.ValidateProgress
+				final        *vtctldatapb.ValidatePermissionsKeyspaceResponse
+				inconsistent []string
+			)
+			for {
+				resp, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err)
+
+				if resp.Progress == nil {
+					final = resp
+					continue
+				}
+
+				progress = append(progress, resp.Progress)
+				if len(resp.Progress.Results) > 0 {
+					inconsistent = append(inconsistent, topoproto.TabletAliasString(resp.Progress.TabletAlias))
+				}
+			}
+
+			// The primary of the first shard is the reference, so it is not
+			// part of the progress events.
+			require.Len(t, progress, 3)
+			for i, p := range progress {
+				assert.Equal(t, uint32(i+1), p.Completed)
+				assert.Equal(t, uint32(3), p.Total)
+			}
+
+			require.NotNil(t, final)
+			assert.Equal(t, tt.inconsistent, inconsistent)
+			assert.Equal(t, len(tt.inconsistent) > 0, len(final.Results) > 0)
+			assert.Empty(t, final.ResultsByShard["-80"].Results)
+		})
+	}
 }
 
 func TestValidateSchemaKeyspace(t *testing.T) {
@@ -12089,6 +14563,138 @@ func TestValidateSchemaKeyspace(t *testing.T) {
 	}
 }
 
+func TestValidateSchemaKeyspaceStream(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1", "zone2")
+	tablets := []*topodatapb.Tablet{
+		{
+			Keyspace: "ks",
+			Shard:    "-80",
+			Type:     topodatapb.TabletType_PRIMARY,
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  100,
+			},
+		},
+		{
+			Keyspace: "ks",
+			Shard:    "-80",
+			Type:     topodatapb.TabletType_REPLICA,
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone2",
+				Uid:  200,
+			},
+		},
+		{
+			Keyspace: "ks",
+			Shard:    "80-",
+			Type:     topodatapb.TabletType_PRIMARY,
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  101,
+			},
+		},
+		{
+			Keyspace: "ks",
+			Shard:    "80-",
+			Type:     topodatapb.TabletType_REPLICA,
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone2",
+				Uid:  201,
+			},
+		},
+	}
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+		AlsoSetShardPrimary: true,
+	}, tablets...)
+
+	schema := &tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{{
+			Name:    "t1",
+			Columns: []string{"c1"},
+		}},
+	}
+	otherSchema := &tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+			{
+				Name:    "t1",
+				Columns: []string{"c1"},
+			},
+			{
+				Name:    "t2",
+				Columns: []string{"c1"},
+				Type:    tmutils.TableBaseTable,
+			},
+		},
+	}
+
+	tmc := &testutil.TabletManagerClient{
+		GetSchemaResults: map[string]struct {
+			Schema *tabletmanagerdatapb.SchemaDefinition
+			Error  error
+		}{
+			"zone1-0000000100": {Schema: schema},
+			"zone2-0000000200": {Schema: schema},
+			"zone1-0000000101": {Schema: schema},
+			"zone2-0000000201": {Schema: otherSchema},
+		},
+	}
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+	client := localvtctldclient.New(vtctld)
+
+	req := &vtctldatapb.ValidateSchemaKeyspaceRequest{
+		Keyspace:    "ks",
+		Concurrency: 2,
+	}
+	expected, err := vtctld.ValidateSchemaKeyspace(ctx, req)
+	require.NoError(t, err)
+
+	stream, err := client.ValidateSchemaKeyspaceStream(ctx, req)
+	require.NoError(t, err)
+
+	var (
+		progress []*vtctldatapb.ValidateProgress
+		final    *vtctldatapb.ValidateSchemaKeyspaceStreamResponse
+	)
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		if resp.Progress == nil {
+			final = resp
+			continue
+		}
+
+		progress = append(progress, resp.Progress)
+	}
+
+	require.Len(t, progress, 3)
+	for _, p := range progress {
+		assert.Equal(t, uint32(3), p.Total)
+		if topoproto.TabletAliasString(p.TabletAlias) == "zone2-0000000201" {
+			assert.NotEmpty(t, p.Results)
+		} else {
+			assert.Empty(t, p.Results)
+		}
+	}
+
+	// The final message holds the same results as the unary RPC.
+	require.NotNil(t, final)
+	assert.NotEmpty(t, final.Results)
+	utils.MustMatch(t, expected, &vtctldatapb.ValidateSchemaKeyspaceResponse{
+		Results:        final.Results,
+		ResultsByShard: final.ResultsByShard,
+	})
+}
+
 func TestValidateVersionKeyspace(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -12860,6 +15466,156 @@ func TestValidateShard(t *testing.T) {
 		})
 	}
 }
+func TestWatchKeyspace(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+	client := localvtctldclient.New(vtctld)
+
+	t.Run("no keyspace", func(t *testing.T) {
+		stream, err := client.WatchKeyspace(ctx, &vtctldatapb.WatchKeyspaceRequest{Keyspace: "unknown"})
+		require.NoError(t, err)
+
+		_, err = stream.Recv()
+		assert.Error(t, err)
+	})
+
+	testutil.AddKeyspace(ctx, t, ts, &vtctldatapb.Keyspace{
+		Name:     "testkeyspace",
+		Keyspace: &topodatapb.Keyspace{},
+	})
+
+	stream, err := client.WatchKeyspace(ctx, &vtctldatapb.WatchKeyspaceRequest{Keyspace: "testkeyspace"})
+	require.NoError(t, err)
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	utils.MustMatch(t, &topodatapb.Keyspace{}, resp.Keyspace)
+
+	lctx, unlock, err := ts.LockKeyspace(ctx, "testkeyspace", "TestWatchKeyspace")
+	require.NoError(t, err)
+	ki, err := ts.GetKeyspace(lctx, "testkeyspace")
+	require.NoError(t, err)
+	ki.DurabilityPolicy = "semi_sync"
+	err = ts.UpdateKeyspace(lctx, ki)
+	unlock(&err)
+	require.NoError(t, err)
+
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	utils.MustMatch(t, &topodatapb.Keyspace{DurabilityPolicy: "semi_sync"}, resp.Keyspace)
+
+	// Deleting the keyspace ends the stream.
+	require.NoError(t, ts.DeleteKeyspace(ctx, "testkeyspace"))
+	_, err = stream.Recv()
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, io.EOF)
+}
+
+func TestWatchShard(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+	client := localvtctldclient.New(vtctld)
+
+	t.Run("no shard", func(t *testing.T) {
+		stream, err := client.WatchShard(ctx, &vtctldatapb.WatchShardRequest{Keyspace: "testkeyspace", Shard: "-"})
+		require.NoError(t, err)
+
+		_, err = stream.Recv()
+		assert.Error(t, err)
+	})
+
+	testutil.AddShards(ctx, t, ts, &vtctldatapb.Shard{
+		Keyspace: "testkeyspace",
+		Name:     "-",
+	})
+
+	stream, err := client.WatchShard(ctx, &vtctldatapb.WatchShardRequest{Keyspace: "testkeyspace", Shard: "-"})
+	require.NoError(t, err)
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	assert.True(t, resp.Shard.IsPrimaryServing)
+
+	_, err = ts.UpdateShardFields(ctx, "testkeyspace", "-", func(si *topo.ShardInfo) error {
+		si.IsPrimaryServing = false
+		return nil
+	})
+	require.NoError(t, err)
+
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	assert.False(t, resp.Shard.IsPrimaryServing)
+
+	// Deleting the shard ends the stream.
+	require.NoError(t, ts.DeleteShard(ctx, "testkeyspace", "-"))
+	_, err = stream.Recv()
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, io.EOF)
+}
+
+func TestWatchSrvVSchema(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(ts)
+	})
+	client := localvtctldclient.New(vtctld)
+
+	t.Run("no srv vschema", func(t *testing.T) {
+		stream, err := client.WatchSrvVSchema(ctx, &vtctldatapb.WatchSrvVSchemaRequest{Cell: "zone1"})
+		require.NoError(t, err)
+
+		_, err = stream.Recv()
+		assert.Error(t, err)
+	})
+
+	initial := &vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"testkeyspace": {},
+		},
+	}
+	require.NoError(t, ts.UpdateSrvVSchema(ctx, "zone1", initial))
+
+	stream, err := client.WatchSrvVSchema(ctx, &vtctldatapb.WatchSrvVSchemaRequest{Cell: "zone1"})
+	require.NoError(t, err)
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	utils.MustMatch(t, initial, resp.SrvVSchema)
+
+	updated := &vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"testkeyspace": {Sharded: true},
+		},
+	}
+	require.NoError(t, ts.UpdateSrvVSchema(ctx, "zone1", updated))
+
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	utils.MustMatch(t, updated, resp.SrvVSchema)
+
+	// Deleting the SrvVSchema ends the stream.
+	require.NoError(t, ts.DeleteSrvVSchema(ctx, "zone1"))
+	_, err = stream.Recv()
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, io.EOF)
+}
+
 func TestMain(m *testing.M) {
 	_flag.ParseFlagsForTest()
 	os.Exit(m.Run())
@@ -19,6 +19,7 @@ package testutil
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"sync"
@@ -186,6 +187,9 @@ type TabletManagerClient struct {
 	}
 	// keyed by tablet alias.
 	ChangeTabletTypeResult map[string]error
+	// keyed by `<tablet_alias>/<operation>`, where the operation is one of
+	// conclude, commit or rollback.
+	ConcludeTransactionResults map[string]error
 	// keyed by tablet alias.
 	DemotePrimaryDelays map[string]time.Duration
 	// keyed by tablet alias.
@@ -223,6 +227,11 @@ type TabletManagerClient struct {
 	}
 	// FullStatus result
 	FullStatusResult *replicationdatapb.FullStatus
+	// keyed by tablet alias. Takes precedence over FullStatusResult.
+	FullStatusResults map[string]struct {
+		Status *replicationdatapb.FullStatus
+		Error  error
+	}
 	// keyed by tablet alias.
 	GetPermissionsDelays map[string]time.Duration
 	// keyed by tablet alias.
@@ -231,6 +240,21 @@ type TabletManagerClient struct {
 		Error       error
 	}
 	// keyed by tablet alias.
+	GetPlanCacheResults map[string]struct {
+		Response *tabletmanagerdatapb.GetPlanCacheResponse
+		Error    error
+	}
+	// keyed by tablet alias.
+	GetTableStatsResults map[string]struct {
+		Response *tabletmanagerdatapb.GetTableStatsResponse
+		Error    error
+	}
+	// keyed by tablet alias.
+	GetUnresolvedTransactionsResults map[string]struct {
+		Response *tabletmanagerdatapb.GetUnresolvedTransactionsResponse
+		Error    error
+	}
+	// keyed by tablet alias.
 	GetReplicasResults map[string]struct {
 		Replicas []string
 		Error    error
@@ -253,6 +277,11 @@ type TabletManagerClient struct {
 		Error  error
 	}
 	// keyed by tablet alias.
+	InvalidatePlanCacheResults map[string]struct {
+		Response *tabletmanagerdatapb.InvalidatePlanCacheResponse
+		Error    error
+	}
+	// keyed by tablet alias.
 	PrimaryPositionDelays map[string]time.Duration
 	// keyed by tablet alias.
 	PrimaryPositionResults map[string]struct {
@@ -278,6 +307,11 @@ type TabletManagerClient struct {
 		Error  error
 	}
 	// keyed by tablet alias.
+	ReadTransactionResults map[string]struct {
+		Response *tabletmanagerdatapb.ReadTransactionResponse
+		Error    error
+	}
+	// keyed by tablet alias.
 	RefreshStateResults map[string]error
 	// keyed by `<tablet_alias>/<wait_pos>`.
 	ReloadSchemaDelays map[string]time.Duration
@@ -340,6 +374,12 @@ type TabletManagerClient struct {
 	UndoDemotePrimaryDelays map[string]time.Duration
 	// keyed by tablet alias
 	UndoDemotePrimaryResults map[string]error
+	// keyed by tablet alias. The events are streamed, then the error is
+	// returned.
+	UpgradeMysqlResults map[string]struct {
+		Events []*logutilpb.Event
+		Error  error
+	}
 	// tablet alias => duration
 	VReplicationExecDelays map[string]time.Duration
 	// tablet alias => query string => result
@@ -473,6 +513,30 @@ func (fake *TabletManagerClient) ChangeType(ctx context.Context, tablet *topodat
 	return err
 }
 
+// ConcludeTransaction is part of the tmclient.TabletManagerClient interface.
+func (fake *TabletManagerClient) ConcludeTransaction(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.ConcludeTransactionRequest) (*tabletmanagerdatapb.ConcludeTransactionResponse, error) {
+	if fake.ConcludeTransactionResults == nil {
+		return nil, assert.AnError
+	}
+
+	operation := "rollback"
+	switch {
+	case request.Mm:
+		operation = "conclude"
+	case request.Commit:
+		operation = "commit"
+	}
+	key := path.Join(topoproto.TabletAliasString(tablet.Alias), operation)
+	if err, ok := fake.ConcludeTransactionResults[key]; ok {
+		if err != nil {
+			return nil, err
+		}
+		return &tabletmanagerdatapb.ConcludeTransactionResponse{}, nil
+	}
+
+	return nil, fmt.Errorf("%w: no ConcludeTransaction result set for %s", assert.AnError, key)
+}
+
 // DemotePrimary is part of the tmclient.TabletManagerClient interface.
 func (fake *TabletManagerClient) DemotePrimary(ctx context.Context, tablet *topodatapb.Tablet) (*replicationdatapb.PrimaryStatus, error) {
 	if fake.DemotePrimaryResults == nil {
@@ -601,6 +665,14 @@ func (fake *TabletManagerClient) ExecuteQuery(ctx context.Context, tablet *topod
 
 // FullStatus is part of the tmclient.TabletManagerClient interface.
 func (fake *TabletManagerClient) FullStatus(ctx context.Context, tablet *topodatapb.Tablet) (*replicationdatapb.FullStatus, error) {
+	if fake.FullStatusResults != nil {
+		if result, ok := fake.FullStatusResults[topoproto.TabletAliasString(tablet.Alias)]; ok {
+			return result.Status, result.Error
+		}
+
+		return nil, fmt.Errorf("%w: no FullStatus result set for tablet %s", assert.AnError, topoproto.TabletAliasString(tablet.Alias))
+	}
+
 	if fake.FullStatusResult != nil {
 		return fake.FullStatusResult, nil
 	}
@@ -642,6 +714,48 @@ func (fake *TabletManagerClient) GetPermissions(ctx context.Context, tablet *top
 	return nil, fmt.Errorf("%w: no permissions for %s", assert.AnError, key)
 }
 
+// GetPlanCache is part of the tmclient.TabletManagerClient interface.
+func (fake *TabletManagerClient) GetPlanCache(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.GetPlanCacheRequest) (*tabletmanagerdatapb.GetPlanCacheResponse, error) {
+	if fake.GetPlanCacheResults == nil {
+		return nil, assert.AnError
+	}
+
+	key := topoproto.TabletAliasString(tablet.Alias)
+	if result, ok := fake.GetPlanCacheResults[key]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no plan cache for %s", assert.AnError, key)
+}
+
+// GetTableStats is part of the tmclient.TabletManagerClient interface.
+func (fake *TabletManagerClient) GetTableStats(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.GetTableStatsRequest) (*tabletmanagerdatapb.GetTableStatsResponse, error) {
+	if fake.GetTableStatsResults == nil {
+		return nil, assert.AnError
+	}
+
+	key := topoproto.TabletAliasString(tablet.Alias)
+	if result, ok := fake.GetTableStatsResults[key]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no table stats for %s", assert.AnError, key)
+}
+
+// GetUnresolvedTransactions is part of the tmclient.TabletManagerClient interface.
+func (fake *TabletManagerClient) GetUnresolvedTransactions(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.GetUnresolvedTransactionsRequest) (*tabletmanagerdatapb.GetUnresolvedTransactionsResponse, error) {
+	if fake.GetUnresolvedTransactionsResults == nil {
+		return nil, assert.AnError
+	}
+
+	key := topoproto.TabletAliasString(tablet.Alias)
+	if result, ok := fake.GetUnresolvedTransactionsResults[key]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no unresolved transactions for %s", assert.AnError, key)
+}
+
 // GetReplicas is part of the tmclient.TabletManagerClient interface.
 func (fake *TabletManagerClient) GetReplicas(ctx context.Context, tablet *topodatapb.Tablet) ([]string, error) {
 	if fake.GetReplicasResults == nil {
@@ -722,6 +836,20 @@ func (fake *TabletManagerClient) InitPrimary(ctx context.Context, tablet *topoda
 	return "", assert.AnError
 }
 
+// InvalidatePlanCache is part of the tmclient.TabletManagerClient interface.
+func (fake *TabletManagerClient) InvalidatePlanCache(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.InvalidatePlanCacheRequest) (*tabletmanagerdatapb.InvalidatePlanCacheResponse, error) {
+	if fake.InvalidatePlanCacheResults == nil {
+		return nil, assert.AnError
+	}
+
+	key := topoproto.TabletAliasString(tablet.Alias)
+	if result, ok := fake.InvalidatePlanCacheResults[key]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, assert.AnError
+}
+
 // PrimaryPosition is part of the tmclient.TabletManagerClient interface.
 func (fake *TabletManagerClient) PrimaryPosition(ctx context.Context, tablet *topodatapb.Tablet) (string, error) {
 	if fake.PrimaryPositionResults == nil {
@@ -844,6 +972,20 @@ func (fake *TabletManagerClient) PromoteReplica(ctx context.Context, tablet *top
 	return "", assert.AnError
 }
 
+// ReadTransaction is part of the tmclient.TabletManagerClient interface.
+func (fake *TabletManagerClient) ReadTransaction(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.ReadTransactionRequest) (*tabletmanagerdatapb.ReadTransactionResponse, error) {
+	if fake.ReadTransactionResults == nil {
+		return nil, assert.AnError
+	}
+
+	key := topoproto.TabletAliasString(tablet.Alias)
+	if result, ok := fake.ReadTransactionResults[key]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no transaction for %s", assert.AnError, key)
+}
+
 // RefreshState is part of the tmclient.TabletManagerClient interface.
 func (fake *TabletManagerClient) RefreshState(ctx context.Context, tablet *topodatapb.Tablet) error {
 	if fake.RefreshStateResults == nil {
@@ -965,8 +1107,22 @@ func (stream *backupRestoreStreamAdapter) Send(msg *logutilpb.Event) error {
 	}
 }
 
+// restoreFromBackupStreamAdapter sends the events of a backupRestoreStreamAdapter
+// as RestoreFromBackup responses.
+type restoreFromBackupStreamAdapter struct {
+	*backupRestoreStreamAdapter
+}
+
+func (stream *restoreFromBackupStreamAdapter) Recv() (*tabletmanagerdatapb.RestoreFromBackupResponse, error) {
+	event, err := stream.backupRestoreStreamAdapter.Recv()
+	if err != nil {
+		return nil, err
+	}
+	return &tabletmanagerdatapb.RestoreFromBackupResponse{Event: event}, nil
+}
+
 // RestoreFromBackup is part of the tmclient.TabletManagerClient interface.
-func (fake *TabletManagerClient) RestoreFromBackup(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.RestoreFromBackupRequest) (logutil.EventStream, error) {
+func (fake *TabletManagerClient) RestoreFromBackup(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.RestoreFromBackupRequest) (tmclient.RestoreFromBackupStream, error) {
 	key := topoproto.TabletAliasString(tablet.Alias)
 	testdata, ok := fake.RestoreFromBackupResults[key]
 	if !ok {
@@ -1027,7 +1183,7 @@ func (fake *TabletManagerClient) RestoreFromBackup(ctx context.Context, tablet *
 		<-errCtx.Done()
 	}()
 
-	return stream, nil
+	return &restoreFromBackupStreamAdapter{stream}, nil
 }
 
 // RunHealthCheck is part of the tmclient.TabletManagerClient interface.
@@ -1348,6 +1504,43 @@ func (fake *TabletManagerClient) UndoDemotePrimary(ctx context.Context, tablet *
 	return assert.AnError
 }
 
+// UpgradeMysql is part of the tmclient.TabletManagerClient interface.
+func (fake *TabletManagerClient) UpgradeMysql(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.UpgradeMysqlRequest) (logutil.EventStream, error) {
+	if tablet.Type == topodatapb.TabletType_PRIMARY && !req.AllowPrimary {
+		return nil, fmt.Errorf("cannot upgrade primary with allowPrimary=false")
+	}
+
+	key := topoproto.TabletAliasString(tablet.Alias)
+	testdata, ok := fake.UpgradeMysqlResults[key]
+	if !ok {
+		return nil, fmt.Errorf("no UpgradeMysql fake result set for %s", key)
+	}
+
+	return &upgradeMysqlStream{
+		events: testdata.Events,
+		err:    testdata.Error,
+	}, nil
+}
+
+// upgradeMysqlStream returns all of its events before its error, or io.EOF
+// if there is none.
+type upgradeMysqlStream struct {
+	events []*logutilpb.Event
+	err    error
+}
+
+func (stream *upgradeMysqlStream) Recv() (*logutilpb.Event, error) {
+	if len(stream.events) > 0 {
+		event := stream.events[0]
+		stream.events = stream.events[1:]
+		return event, nil
+	}
+	if stream.err != nil {
+		return nil, stream.err
+	}
+	return nil, io.EOF
+}
+
 // VReplicationExec is part of the tmclient.TabletManagerCLient interface.
 func (fake *TabletManagerClient) VReplicationExec(ctx context.Context, tablet *topodatapb.Tablet, query string) (*querypb.QueryResult, error) {
 	if fake.VReplicationExecResults == nil {
@@ -19,6 +19,7 @@ package grpcvtctldserver
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"vitess.io/vitess/go/trace"
@@ -32,7 +33,40 @@ import (
 	"vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
-func deleteShard(ctx context.Context, ts *topo.Server, keyspace string, shard string, recursive bool, evenIfServing bool, force bool) (err error) {
+// dryRunLog collects the topo mutations a destructive operation would have
+// performed. A nil *dryRunLog means mutations are applied as usual.
+type dryRunLog struct {
+	results []string
+}
+
+// newDryRunLog returns a dryRunLog if dryRun is set, and nil otherwise.
+func newDryRunLog(dryRun bool) *dryRunLog {
+	if !dryRun {
+		return nil
+	}
+
+	return &dryRunLog{}
+}
+
+// enabled returns true if mutations should be recorded instead of applied.
+func (drl *dryRunLog) enabled() bool {
+	return drl != nil
+}
+
+func (drl *dryRunLog) add(format string, args ...any) {
+	drl.results = append(drl.results, fmt.Sprintf(format, args...))
+}
+
+// Results returns the recorded mutations, or nil if not in dry-run mode.
+func (drl *dryRunLog) Results() []string {
+	if drl == nil {
+		return nil
+	}
+
+	return drl.results
+}
+
+func deleteShard(ctx context.Context, ts *topo.Server, keyspace string, shard string, recursive bool, evenIfServing bool, force bool, drl *dryRunLog) (err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.deleteShard")
 	defer span.Finish()
 
@@ -41,17 +75,26 @@ func deleteShard(ctx context.Context, ts *topo.Server, keyspace string, shard st
 	span.Annotate("recursive", recursive)
 	span.Annotate("even_if_serving", evenIfServing)
 	span.Annotate("force", force)
-
-	lctx, unlock, lerr := ts.LockShard(ctx, keyspace, shard, "DeleteShard")
-	switch {
-	case lerr == nil:
-		// We locked the shard, all good
-		ctx = lctx
-	case !force:
-		return fmt.Errorf("failed to lock %s/%s; if you really want to delete this shard, re-run with Force=true: %w", keyspace, shard, lerr)
-	default:
-		// Failed to lock, but force=true. Warn and continue
-		log.Warningf("%s: failed to lock shard %s/%s for deletion, but force=true, proceeding anyway ...", lerr, keyspace, shard)
+	span.Annotate("dry_run", drl.enabled())
+
+	var unlock func(*error)
+	if !drl.enabled() {
+		var (
+			lctx context.Context
+			lerr error
+		)
+
+		lctx, unlock, lerr = ts.LockShard(ctx, keyspace, shard, "DeleteShard")
+		switch {
+		case lerr == nil:
+			// We locked the shard, all good
+			ctx = lctx
+		case !force:
+			return fmt.Errorf("failed to lock %s/%s; if you really want to delete this shard, re-run with Force=true: %w", keyspace, shard, lerr)
+		default:
+			// Failed to lock, but force=true. Warn and continue
+			log.Warningf("%s: failed to lock shard %s/%s for deletion, but force=true, proceeding anyway ...", lerr, keyspace, shard)
+		}
 	}
 
 	if unlock != nil {
@@ -77,6 +120,11 @@ func deleteShard(ctx context.Context, ts *topo.Server, keyspace string, shard st
 		if topo.IsErrType(err, topo.NoNode) {
 			log.Infof("Shard %v/%v doesn't seem to exist; cleaning up any potential leftover topo data", keyspace, shard)
 
+			if drl.enabled() {
+				drl.add("Delete any leftover topo data for shard %v/%v", keyspace, shard)
+				return nil
+			}
+
 			_ = ts.DeleteShard(ctx, keyspace, shard)
 			return nil
 		}
@@ -101,7 +149,7 @@ func deleteShard(ctx context.Context, ts *topo.Server, keyspace string, shard st
 	}
 
 	for _, cell := range cells {
-		err = deleteShardCell(ctx, ts, keyspace, shard, cell, recursive)
+		err = deleteShardCell(ctx, ts, keyspace, shard, cell, recursive, drl)
 		if err != nil {
 			return err
 		}
@@ -110,11 +158,21 @@ func deleteShard(ctx context.Context, ts *topo.Server, keyspace string, shard st
 	// Try to remove the replication and serving graphs from each cell,
 	// regardless of whether they exist.
 	for _, cell := range cells {
+		if drl.enabled() {
+			drl.add("Delete ShardReplication for shard %v/%v in cell %v", keyspace, shard, cell)
+			continue
+		}
+
 		if err := ts.DeleteShardReplication(ctx, cell, keyspace, shard); err != nil && !topo.IsErrType(err, topo.NoNode) {
 			log.Warningf("Cannot delete ShardReplication in cell %v for %v/%v: %w", cell, keyspace, shard, err)
 		}
 	}
 
+	if drl.enabled() {
+		drl.add("Delete shard %v/%v", keyspace, shard)
+		return nil
+	}
+
 	err = ts.DeleteShard(ctx, keyspace, shard)
 	return err
 }
@@ -122,7 +180,7 @@ func deleteShard(ctx context.Context, ts *topo.Server, keyspace string, shard st
 // deleteShardCell is the per-cell helper function for deleteShard, and is
 // distinct from the RemoveShardCell rpc. Despite having similar names, they are
 // **not** the same!
-func deleteShardCell(ctx context.Context, ts *topo.Server, keyspace string, shard string, cell string, recursive bool) error {
+func deleteShardCell(ctx context.Context, ts *topo.Server, keyspace string, shard string, cell string, recursive bool, drl *dryRunLog) error {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.deleteShardCell")
 	defer span.Finish()
 
@@ -182,6 +240,20 @@ func deleteShardCell(ctx context.Context, ts *topo.Server, keyspace string, shar
 		}
 
 		log.Infof("Deleting all %d tablets in shard %v/%v cell %v", len(tabletMap), keyspace, shard, cell)
+		if drl.enabled() {
+			aliases := make([]string, 0, len(tabletMap))
+			for alias := range tabletMap {
+				aliases = append(aliases, alias)
+			}
+
+			sort.Strings(aliases)
+			for _, alias := range aliases {
+				drl.add("Delete tablet %v", alias)
+			}
+
+			return nil
+		}
+
 		for alias, tablet := range tabletMap {
 			// We don't care about updating the ShardReplication object, because
 			// later we're going to delete the entire object.
@@ -205,12 +277,13 @@ func deleteShardCell(ctx context.Context, ts *topo.Server, keyspace string, shar
 	return nil
 }
 
-func deleteTablet(ctx context.Context, ts *topo.Server, alias *topodatapb.TabletAlias, allowPrimary bool) (err error) {
+func deleteTablet(ctx context.Context, ts *topo.Server, alias *topodatapb.TabletAlias, allowPrimary bool, drl *dryRunLog) (err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.deleteTablet")
 	defer span.Finish()
 
 	span.Annotate("tablet_alias", topoproto.TabletAliasString(alias))
 	span.Annotate("allow_primary", allowPrimary)
+	span.Annotate("dry_run", drl.enabled())
 
 	tablet, err := ts.GetTablet(ctx, alias)
 	if err != nil {
@@ -228,6 +301,16 @@ func deleteTablet(ctx context.Context, ts *topo.Server, alias *topodatapb.Tablet
 		return vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "cannot delete tablet %v as it is a primary, pass AllowPrimary = true", topoproto.TabletAliasString(alias))
 	}
 
+	if drl.enabled() {
+		if isPrimary {
+			drl.add("Clear primary %v from shard %v/%v", topoproto.TabletAliasString(alias), tablet.Keyspace, tablet.Shard)
+		}
+
+		drl.add("Delete tablet %v", topoproto.TabletAliasString(alias))
+		drl.add("Remove tablet %v from ShardReplication for shard %v/%v in cell %v", topoproto.TabletAliasString(alias), tablet.Keyspace, tablet.Shard, alias.Cell)
+		return nil
+	}
+
 	// Update the Shard object if the primary was scrapped. We do this before
 	// calling DeleteTablet so that the operation can be retried in case of
 	// failure.
@@ -265,7 +348,7 @@ func deleteTablet(ctx context.Context, ts *topo.Server, alias *topodatapb.Tablet
 	return err
 }
 
-func removeShardCell(ctx context.Context, ts *topo.Server, cell string, keyspace string, shardName string, recursive bool, force bool) error {
+func removeShardCell(ctx context.Context, ts *topo.Server, cell string, keyspace string, shardName string, recursive bool, force bool, drl *dryRunLog) error {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.removeShardCell")
 	defer span.Finish()
 
@@ -274,6 +357,7 @@ func removeShardCell(ctx context.Context, ts *topo.Server, cell string, keyspace
 	span.Annotate("cell", cell)
 	span.Annotate("recursive", recursive)
 	span.Annotate("force", force)
+	span.Annotate("dry_run", drl.enabled())
 
 	shard, err := ts.GetShard(ctx, keyspace, shardName)
 	if err != nil {
@@ -304,6 +388,11 @@ func removeShardCell(ctx context.Context, ts *topo.Server, cell string, keyspace
 				// graph, because we're about to delete the entire replication
 				// graph.
 				log.Infof("Deleting tablet %v", topoproto.TabletAliasString(node.TabletAlias))
+				if drl.enabled() {
+					drl.add("Delete tablet %v", topoproto.TabletAliasString(node.TabletAlias))
+					continue
+				}
+
 				if err := ts.DeleteTablet(ctx, node.TabletAlias); err != nil && !topo.IsErrType(err, topo.NoNode) {
 					return fmt.Errorf("cannot delete tablet %v: %w", topoproto.TabletAliasString(node.TabletAlias), err)
 				}
@@ -313,6 +402,11 @@ func removeShardCell(ctx context.Context, ts *topo.Server, cell string, keyspace
 		}
 
 		// Remove the empty replication graph.
+		if drl.enabled() {
+			drl.add("Delete ShardReplication for shard %v/%v in cell %v", keyspace, shardName, cell)
+			break
+		}
+
 		if err := ts.DeleteShardReplication(ctx, cell, keyspace, shardName); err != nil && !topo.IsErrType(err, topo.NoNode) {
 			return fmt.Errorf("error deleting ShardReplication object in cell %v: %w", cell, err)
 		}
@@ -335,6 +429,14 @@ func removeShardCell(ctx context.Context, ts *topo.Server, cell string, keyspace
 
 	log.Infof("Removing cell %v from SrvKeyspace %v/%v", cell, keyspace, shardName)
 
+	if drl.enabled() {
+		for _, tabletType := range []topodatapb.TabletType{topodatapb.TabletType_RDONLY, topodatapb.TabletType_REPLICA, topodatapb.TabletType_PRIMARY} {
+			drl.add("Remove shard %v/%v from %v SrvKeyspace partitions in cell %v", keyspace, shardName, topoproto.TabletTypeLString(tabletType), cell)
+		}
+
+		return nil
+	}
+
 	ctx, unlock, lockErr := ts.LockKeyspace(ctx, keyspace, "Locking keyspace to remove shard from SrvKeyspace")
 	if lockErr != nil {
 		return lockErr
@@ -21,8 +21,12 @@ of the remote execution of vtctl commands.
 package grpcvtctlserver
 
 import (
+	"context"
 	"sync"
+	"time"
 
+	"github.com/spf13/pflag"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 
 	"vitess.io/vitess/go/vt/logutil"
@@ -37,6 +41,20 @@ import (
 	vtctlservicepb "vitess.io/vitess/go/vt/proto/vtctlservice"
 )
 
+// batchConcurrency is the maximum number of commands of a parallel
+// ExecuteVtctlCommandBatch request that run at the same time.
+var batchConcurrency = 8
+
+func init() {
+	for _, cmd := range []string{"vtcombo", "vtctld"} {
+		servenv.OnParseFor(cmd, registerFlags)
+	}
+}
+
+func registerFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&batchConcurrency, "vtctl-batch-concurrency", batchConcurrency, "Maximum number of commands of a parallel ExecuteVtctlCommandBatch request that run at the same time. Set to 0 for no limit.")
+}
+
 // VtctlServer is our RPC server
 type VtctlServer struct {
 	vtctlservicepb.UnimplementedVtctlServer
@@ -70,12 +88,123 @@ func (s *VtctlServer) ExecuteVtctlCommand(args *vtctldatapb.ExecuteVtctlCommandR
 	logger := logutil.NewTeeLogger(logstream, logutil.NewConsoleLogger())
 
 	// create the wrangler
-	tmc := tmclient.NewTabletManagerClient()
+	tmc := tmclient.NewRetryingClient(tmclient.NewTabletManagerClient(), tmclient.RetryPolicyFromFlags())
 	defer tmc.Close()
 	wr := wrangler.New(logger, s.ts, tmc)
 
-	// execute the command
-	return vtctl.RunCommand(stream.Context(), wr, args.Args)
+	// execute the command, canceling it if the client goes away or if the
+	// action timeout expires
+	ctx := stream.Context()
+	if args.ActionTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(args.ActionTimeout))
+		defer cancel()
+	}
+
+	// Report the entities the command acted on once it completes, even if
+	// it failed part way through.
+	ctx, affected := vtctl.WithAffectedEntities(ctx)
+	defer func() {
+		if !affected.Empty() {
+			mu.Lock()
+			stream.Send(&vtctldatapb.ExecuteVtctlCommandResponse{
+				Event: affected.Event(),
+			})
+			mu.Unlock()
+		}
+	}()
+
+	return vtctl.RunCommand(ctx, wr, args.Args)
+}
+
+// ExecuteVtctlCommandBatch is part of the vtctldatapb.VtctlServer interface.
+// Each command runs with its own logger, and the outcome of each command is
+// reported in its Done response, so the RPC itself only fails if the batch
+// could not be run at all.
+func (s *VtctlServer) ExecuteVtctlCommandBatch(req *vtctldatapb.ExecuteVtctlCommandBatchRequest, stream vtctlservicepb.Vtctl_ExecuteVtctlCommandBatchServer) (err error) {
+	defer servenv.HandlePanic("vtctl", &err)
+
+	// Commands may run in parallel, and stream.Send() is not thread safe
+	// in gRPC, so use a mutex to protect it.
+	mu := sync.Mutex{}
+	send := func(resp *vtctldatapb.ExecuteVtctlCommandBatchResponse) {
+		// If the client disconnects, we will just fail to send the
+		// responses, but won't interrupt the commands.
+		mu.Lock()
+		stream.Send(resp)
+		mu.Unlock()
+	}
+
+	tmc := tmclient.NewRetryingClient(tmclient.NewTabletManagerClient(), tmclient.RetryPolicyFromFlags())
+	defer tmc.Close()
+
+	run := func(index int, args []string) (err error) {
+		defer func() {
+			resp := &vtctldatapb.ExecuteVtctlCommandBatchResponse{
+				CommandIndex: uint32(index),
+				Done:         true,
+			}
+			if err != nil {
+				resp.Error = err.Error()
+			}
+			send(resp)
+		}()
+		defer servenv.HandlePanic("vtctl", &err)
+
+		logstream := logutil.NewCallbackLogger(func(e *logutilpb.Event) {
+			send(&vtctldatapb.ExecuteVtctlCommandBatchResponse{
+				CommandIndex: uint32(index),
+				Event:        e,
+			})
+		})
+		logger := logutil.NewTeeLogger(logstream, logutil.NewConsoleLogger())
+		wr := wrangler.New(logger, s.ts, tmc)
+
+		ctx := stream.Context()
+		if req.ActionTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, time.Duration(req.ActionTimeout))
+			defer cancel()
+		}
+
+		ctx, affected := vtctl.WithAffectedEntities(ctx)
+		defer func() {
+			if !affected.Empty() {
+				send(&vtctldatapb.ExecuteVtctlCommandBatchResponse{
+					CommandIndex: uint32(index),
+					Event:        affected.Event(),
+				})
+			}
+		}()
+
+		return vtctl.RunCommand(ctx, wr, args)
+	}
+
+	if req.Parallel {
+		// The outcome of each command is in its Done response, so the
+		// goroutines never return an error.
+		eg := errgroup.Group{}
+		if batchConcurrency > 0 {
+			eg.SetLimit(batchConcurrency)
+		}
+		for i, cmd := range req.Commands {
+			i, args := i, cmd.Args
+			eg.Go(func() error {
+				run(i, args)
+				return nil
+			})
+		}
+		eg.Wait()
+		return nil
+	}
+
+	for i, cmd := range req.Commands {
+		if err := run(i, cmd.Args); err != nil && !req.ContinueOnError {
+			break
+		}
+	}
+
+	return nil
 }
 
 // StartServer registers the VtctlServer for RPCs
@@ -29,6 +29,11 @@ import (
 	vtctlservicepb "vitess.io/vitess/go/vt/proto/vtctlservice"
 )
 
+// AcquireNamedLock is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) AcquireNamedLock(ctx context.Context, in *vtctldatapb.AcquireNamedLockRequest, opts ...grpc.CallOption) (*vtctldatapb.AcquireNamedLockResponse, error) {
+	return client.s.AcquireNamedLock(ctx, in)
+}
+
 // AddCellInfo is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) AddCellInfo(ctx context.Context, in *vtctldatapb.AddCellInfoRequest, opts ...grpc.CallOption) (*vtctldatapb.AddCellInfoResponse, error) {
 	return client.s.AddCellInfo(ctx, in)
@@ -39,6 +44,11 @@ func (client *localVtctldClient) AddCellsAlias(ctx context.Context, in *vtctldat
 	return client.s.AddCellsAlias(ctx, in)
 }
 
+// AddTabletTag is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) AddTabletTag(ctx context.Context, in *vtctldatapb.AddTabletTagRequest, opts ...grpc.CallOption) (*vtctldatapb.AddTabletTagResponse, error) {
+	return client.s.AddTabletTag(ctx, in)
+}
+
 // ApplyRoutingRules is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) ApplyRoutingRules(ctx context.Context, in *vtctldatapb.ApplyRoutingRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyRoutingRulesResponse, error) {
 	return client.s.ApplyRoutingRules(ctx, in)
@@ -161,6 +171,16 @@ func (client *localVtctldClient) BackupShard(ctx context.Context, in *vtctldatap
 	return stream, nil
 }
 
+// CancelCommand is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) CancelCommand(ctx context.Context, in *vtctldatapb.CancelCommandRequest, opts ...grpc.CallOption) (*vtctldatapb.CancelCommandResponse, error) {
+	return client.s.CancelCommand(ctx, in)
+}
+
+// CancelScheduledCommand is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) CancelScheduledCommand(ctx context.Context, in *vtctldatapb.CancelScheduledCommandRequest, opts ...grpc.CallOption) (*vtctldatapb.CancelScheduledCommandResponse, error) {
+	return client.s.CancelScheduledCommand(ctx, in)
+}
+
 // CancelSchemaMigration is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) CancelSchemaMigration(ctx context.Context, in *vtctldatapb.CancelSchemaMigrationRequest, opts ...grpc.CallOption) (*vtctldatapb.CancelSchemaMigrationResponse, error) {
 	return client.s.CancelSchemaMigration(ctx, in)
@@ -171,11 +191,21 @@ func (client *localVtctldClient) ChangeTabletType(ctx context.Context, in *vtctl
 	return client.s.ChangeTabletType(ctx, in)
 }
 
+// ChangeTabletTypeByFilter is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ChangeTabletTypeByFilter(ctx context.Context, in *vtctldatapb.ChangeTabletTypeByFilterRequest, opts ...grpc.CallOption) (*vtctldatapb.ChangeTabletTypeByFilterResponse, error) {
+	return client.s.ChangeTabletTypeByFilter(ctx, in)
+}
+
 // CleanupSchemaMigration is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) CleanupSchemaMigration(ctx context.Context, in *vtctldatapb.CleanupSchemaMigrationRequest, opts ...grpc.CallOption) (*vtctldatapb.CleanupSchemaMigrationResponse, error) {
 	return client.s.CleanupSchemaMigration(ctx, in)
 }
 
+// ConcludeTransaction is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ConcludeTransaction(ctx context.Context, in *vtctldatapb.ConcludeTransactionRequest, opts ...grpc.CallOption) (*vtctldatapb.ConcludeTransactionResponse, error) {
+	return client.s.ConcludeTransaction(ctx, in)
+}
+
 // CreateKeyspace is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) CreateKeyspace(ctx context.Context, in *vtctldatapb.CreateKeyspaceRequest, opts ...grpc.CallOption) (*vtctldatapb.CreateKeyspaceResponse, error) {
 	return client.s.CreateKeyspace(ctx, in)
@@ -241,6 +271,11 @@ func (client *localVtctldClient) FindAllShardsInKeyspace(ctx context.Context, in
 	return client.s.FindAllShardsInKeyspace(ctx, in)
 }
 
+// FixErrantGTID is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) FixErrantGTID(ctx context.Context, in *vtctldatapb.FixErrantGTIDRequest, opts ...grpc.CallOption) (*vtctldatapb.FixErrantGTIDResponse, error) {
+	return client.s.FixErrantGTID(ctx, in)
+}
+
 // GetBackups is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) GetBackups(ctx context.Context, in *vtctldatapb.GetBackupsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetBackupsResponse, error) {
 	return client.s.GetBackups(ctx, in)
@@ -276,6 +311,11 @@ func (client *localVtctldClient) GetKeyspaces(ctx context.Context, in *vtctldata
 	return client.s.GetKeyspaces(ctx, in)
 }
 
+// GetMaintenanceWindows is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) GetMaintenanceWindows(ctx context.Context, in *vtctldatapb.GetMaintenanceWindowsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetMaintenanceWindowsResponse, error) {
+	return client.s.GetMaintenanceWindows(ctx, in)
+}
+
 // GetPermissions is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) GetPermissions(ctx context.Context, in *vtctldatapb.GetPermissionsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetPermissionsResponse, error) {
 	return client.s.GetPermissions(ctx, in)
@@ -286,6 +326,16 @@ func (client *localVtctldClient) GetRoutingRules(ctx context.Context, in *vtctld
 	return client.s.GetRoutingRules(ctx, in)
 }
 
+// GetRunningCommands is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) GetRunningCommands(ctx context.Context, in *vtctldatapb.GetRunningCommandsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetRunningCommandsResponse, error) {
+	return client.s.GetRunningCommands(ctx, in)
+}
+
+// GetScheduledCommands is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) GetScheduledCommands(ctx context.Context, in *vtctldatapb.GetScheduledCommandsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetScheduledCommandsResponse, error) {
+	return client.s.GetScheduledCommands(ctx, in)
+}
+
 // GetSchema is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) GetSchema(ctx context.Context, in *vtctldatapb.GetSchemaRequest, opts ...grpc.CallOption) (*vtctldatapb.GetSchemaResponse, error) {
 	return client.s.GetSchema(ctx, in)
@@ -331,16 +381,77 @@ func (client *localVtctldClient) GetTablet(ctx context.Context, in *vtctldatapb.
 	return client.s.GetTablet(ctx, in)
 }
 
+// GetTabletPlanCache is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) GetTabletPlanCache(ctx context.Context, in *vtctldatapb.GetTabletPlanCacheRequest, opts ...grpc.CallOption) (*vtctldatapb.GetTabletPlanCacheResponse, error) {
+	return client.s.GetTabletPlanCache(ctx, in)
+}
+
 // GetTablets is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) GetTablets(ctx context.Context, in *vtctldatapb.GetTabletsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetTabletsResponse, error) {
 	return client.s.GetTablets(ctx, in)
 }
 
+type getTabletsStreamStreamAdapter struct {
+	*grpcshim.BidiStream
+	ch chan *vtctldatapb.GetTabletsResponse
+}
+
+func (stream *getTabletsStreamStreamAdapter) Recv() (*vtctldatapb.GetTabletsResponse, error) {
+	select {
+	case <-stream.Context().Done():
+		return nil, stream.Context().Err()
+	case <-stream.Closed():
+		// Stream has been closed for future sends. If there are messages that
+		// have already been sent, receive them until there are no more. After
+		// all sent messages have been received, Recv will return the CloseErr.
+		select {
+		case msg := <-stream.ch:
+			return msg, nil
+		default:
+			return nil, stream.CloseErr()
+		}
+	case err := <-stream.ErrCh:
+		return nil, err
+	case msg := <-stream.ch:
+		return msg, nil
+	}
+}
+
+func (stream *getTabletsStreamStreamAdapter) Send(msg *vtctldatapb.GetTabletsResponse) error {
+	select {
+	case <-stream.Context().Done():
+		return stream.Context().Err()
+	case <-stream.Closed():
+		return grpcshim.ErrStreamClosed
+	case stream.ch <- msg:
+		return nil
+	}
+}
+
+// GetTabletsStream is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) GetTabletsStream(ctx context.Context, in *vtctldatapb.GetTabletsRequest, opts ...grpc.CallOption) (vtctlservicepb.Vtctld_GetTabletsStreamClient, error) {
+	stream := &getTabletsStreamStreamAdapter{
+		BidiStream: grpcshim.NewBidiStream(ctx),
+		ch:         make(chan *vtctldatapb.GetTabletsResponse, 1),
+	}
+	go func() {
+		err := client.s.GetTabletsStream(in, stream)
+		stream.CloseWithError(err)
+	}()
+
+	return stream, nil
+}
+
 // GetTopologyPath is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) GetTopologyPath(ctx context.Context, in *vtctldatapb.GetTopologyPathRequest, opts ...grpc.CallOption) (*vtctldatapb.GetTopologyPathResponse, error) {
 	return client.s.GetTopologyPath(ctx, in)
 }
 
+// GetUnresolvedTransactions is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) GetUnresolvedTransactions(ctx context.Context, in *vtctldatapb.GetUnresolvedTransactionsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetUnresolvedTransactionsResponse, error) {
+	return client.s.GetUnresolvedTransactions(ctx, in)
+}
+
 // GetVSchema is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) GetVSchema(ctx context.Context, in *vtctldatapb.GetVSchemaRequest, opts ...grpc.CallOption) (*vtctldatapb.GetVSchemaResponse, error) {
 	return client.s.GetVSchema(ctx, in)
@@ -361,6 +472,11 @@ func (client *localVtctldClient) InitShardPrimary(ctx context.Context, in *vtctl
 	return client.s.InitShardPrimary(ctx, in)
 }
 
+// InvalidateTabletPlanCache is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) InvalidateTabletPlanCache(ctx context.Context, in *vtctldatapb.InvalidateTabletPlanCacheRequest, opts ...grpc.CallOption) (*vtctldatapb.InvalidateTabletPlanCacheResponse, error) {
+	return client.s.InvalidateTabletPlanCache(ctx, in)
+}
+
 // MoveTablesComplete is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) MoveTablesComplete(ctx context.Context, in *vtctldatapb.MoveTablesCompleteRequest, opts ...grpc.CallOption) (*vtctldatapb.MoveTablesCompleteResponse, error) {
 	return client.s.MoveTablesComplete(ctx, in)
@@ -381,6 +497,11 @@ func (client *localVtctldClient) PlannedReparentShard(ctx context.Context, in *v
 	return client.s.PlannedReparentShard(ctx, in)
 }
 
+// PurgeBackups is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) PurgeBackups(ctx context.Context, in *vtctldatapb.PurgeBackupsRequest, opts ...grpc.CallOption) (*vtctldatapb.PurgeBackupsResponse, error) {
+	return client.s.PurgeBackups(ctx, in)
+}
+
 // RebuildKeyspaceGraph is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) RebuildKeyspaceGraph(ctx context.Context, in *vtctldatapb.RebuildKeyspaceGraphRequest, opts ...grpc.CallOption) (*vtctldatapb.RebuildKeyspaceGraphResponse, error) {
 	return client.s.RebuildKeyspaceGraph(ctx, in)
@@ -396,11 +517,21 @@ func (client *localVtctldClient) RefreshState(ctx context.Context, in *vtctldata
 	return client.s.RefreshState(ctx, in)
 }
 
+// RefreshStateByFilter is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) RefreshStateByFilter(ctx context.Context, in *vtctldatapb.RefreshStateByFilterRequest, opts ...grpc.CallOption) (*vtctldatapb.RefreshStateByFilterResponse, error) {
+	return client.s.RefreshStateByFilter(ctx, in)
+}
+
 // RefreshStateByShard is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) RefreshStateByShard(ctx context.Context, in *vtctldatapb.RefreshStateByShardRequest, opts ...grpc.CallOption) (*vtctldatapb.RefreshStateByShardResponse, error) {
 	return client.s.RefreshStateByShard(ctx, in)
 }
 
+// ReleaseNamedLock is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ReleaseNamedLock(ctx context.Context, in *vtctldatapb.ReleaseNamedLockRequest, opts ...grpc.CallOption) (*vtctldatapb.ReleaseNamedLockResponse, error) {
+	return client.s.ReleaseNamedLock(ctx, in)
+}
+
 // ReloadSchema is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) ReloadSchema(ctx context.Context, in *vtctldatapb.ReloadSchemaRequest, opts ...grpc.CallOption) (*vtctldatapb.ReloadSchemaResponse, error) {
 	return client.s.ReloadSchema(ctx, in)
@@ -431,6 +562,21 @@ func (client *localVtctldClient) RemoveShardCell(ctx context.Context, in *vtctld
 	return client.s.RemoveShardCell(ctx, in)
 }
 
+// RemoveTabletTag is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) RemoveTabletTag(ctx context.Context, in *vtctldatapb.RemoveTabletTagRequest, opts ...grpc.CallOption) (*vtctldatapb.RemoveTabletTagResponse, error) {
+	return client.s.RemoveTabletTag(ctx, in)
+}
+
+// RenewNamedLock is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) RenewNamedLock(ctx context.Context, in *vtctldatapb.RenewNamedLockRequest, opts ...grpc.CallOption) (*vtctldatapb.RenewNamedLockResponse, error) {
+	return client.s.RenewNamedLock(ctx, in)
+}
+
+// ReparentPreflight is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ReparentPreflight(ctx context.Context, in *vtctldatapb.ReparentPreflightRequest, opts ...grpc.CallOption) (*vtctldatapb.ReparentPreflightResponse, error) {
+	return client.s.ReparentPreflight(ctx, in)
+}
+
 // ReparentTablet is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) ReparentTablet(ctx context.Context, in *vtctldatapb.ReparentTabletRequest, opts ...grpc.CallOption) (*vtctldatapb.ReparentTabletResponse, error) {
 	return client.s.ReparentTablet(ctx, in)
@@ -497,6 +643,11 @@ func (client *localVtctldClient) RunHealthCheck(ctx context.Context, in *vtctlda
 	return client.s.RunHealthCheck(ctx, in)
 }
 
+// ScheduleCommand is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ScheduleCommand(ctx context.Context, in *vtctldatapb.ScheduleCommandRequest, opts ...grpc.CallOption) (*vtctldatapb.ScheduleCommandResponse, error) {
+	return client.s.ScheduleCommand(ctx, in)
+}
+
 // SetKeyspaceDurabilityPolicy is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) SetKeyspaceDurabilityPolicy(ctx context.Context, in *vtctldatapb.SetKeyspaceDurabilityPolicyRequest, opts ...grpc.CallOption) (*vtctldatapb.SetKeyspaceDurabilityPolicyResponse, error) {
 	return client.s.SetKeyspaceDurabilityPolicy(ctx, in)
@@ -552,11 +703,21 @@ func (client *localVtctldClient) SourceShardDelete(ctx context.Context, in *vtct
 	return client.s.SourceShardDelete(ctx, in)
 }
 
+// StartMaintenance is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) StartMaintenance(ctx context.Context, in *vtctldatapb.StartMaintenanceRequest, opts ...grpc.CallOption) (*vtctldatapb.StartMaintenanceResponse, error) {
+	return client.s.StartMaintenance(ctx, in)
+}
+
 // StartReplication is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) StartReplication(ctx context.Context, in *vtctldatapb.StartReplicationRequest, opts ...grpc.CallOption) (*vtctldatapb.StartReplicationResponse, error) {
 	return client.s.StartReplication(ctx, in)
 }
 
+// StopMaintenance is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) StopMaintenance(ctx context.Context, in *vtctldatapb.StopMaintenanceRequest, opts ...grpc.CallOption) (*vtctldatapb.StopMaintenanceResponse, error) {
+	return client.s.StopMaintenance(ctx, in)
+}
+
 // StopReplication is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) StopReplication(ctx context.Context, in *vtctldatapb.StopReplicationRequest, opts ...grpc.CallOption) (*vtctldatapb.StopReplicationResponse, error) {
 	return client.s.StopReplication(ctx, in)
@@ -577,26 +738,189 @@ func (client *localVtctldClient) UpdateCellsAlias(ctx context.Context, in *vtctl
 	return client.s.UpdateCellsAlias(ctx, in)
 }
 
+// UpdateOnlineDDLSchedulerConfig is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) UpdateOnlineDDLSchedulerConfig(ctx context.Context, in *vtctldatapb.UpdateOnlineDDLSchedulerConfigRequest, opts ...grpc.CallOption) (*vtctldatapb.UpdateOnlineDDLSchedulerConfigResponse, error) {
+	return client.s.UpdateOnlineDDLSchedulerConfig(ctx, in)
+}
+
 // UpdateThrottlerConfig is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) UpdateThrottlerConfig(ctx context.Context, in *vtctldatapb.UpdateThrottlerConfigRequest, opts ...grpc.CallOption) (*vtctldatapb.UpdateThrottlerConfigResponse, error) {
 	return client.s.UpdateThrottlerConfig(ctx, in)
 }
 
+type upgradeMysqlStreamAdapter struct {
+	*grpcshim.BidiStream
+	ch chan *vtctldatapb.UpgradeMysqlResponse
+}
+
+func (stream *upgradeMysqlStreamAdapter) Recv() (*vtctldatapb.UpgradeMysqlResponse, error) {
+	select {
+	case <-stream.Context().Done():
+		return nil, stream.Context().Err()
+	case <-stream.Closed():
+		// Stream has been closed for future sends. If there are messages that
+		// have already been sent, receive them until there are no more. After
+		// all sent messages have been received, Recv will return the CloseErr.
+		select {
+		case msg := <-stream.ch:
+			return msg, nil
+		default:
+			return nil, stream.CloseErr()
+		}
+	case err := <-stream.ErrCh:
+		return nil, err
+	case msg := <-stream.ch:
+		return msg, nil
+	}
+}
+
+func (stream *upgradeMysqlStreamAdapter) Send(msg *vtctldatapb.UpgradeMysqlResponse) error {
+	select {
+	case <-stream.Context().Done():
+		return stream.Context().Err()
+	case <-stream.Closed():
+		return grpcshim.ErrStreamClosed
+	case stream.ch <- msg:
+		return nil
+	}
+}
+
+// UpgradeMysql is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) UpgradeMysql(ctx context.Context, in *vtctldatapb.UpgradeMysqlRequest, opts ...grpc.CallOption) (vtctlservicepb.Vtctld_UpgradeMysqlClient, error) {
+	stream := &upgradeMysqlStreamAdapter{
+		BidiStream: grpcshim.NewBidiStream(ctx),
+		ch:         make(chan *vtctldatapb.UpgradeMysqlResponse, 1),
+	}
+	go func() {
+		err := client.s.UpgradeMysql(in, stream)
+		stream.CloseWithError(err)
+	}()
+
+	return stream, nil
+}
+
 // Validate is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) Validate(ctx context.Context, in *vtctldatapb.ValidateRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidateResponse, error) {
 	return client.s.Validate(ctx, in)
 }
 
+// ValidateBackup is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ValidateBackup(ctx context.Context, in *vtctldatapb.ValidateBackupRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidateBackupResponse, error) {
+	return client.s.ValidateBackup(ctx, in)
+}
+
 // ValidateKeyspace is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) ValidateKeyspace(ctx context.Context, in *vtctldatapb.ValidateKeyspaceRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidateKeyspaceResponse, error) {
 	return client.s.ValidateKeyspace(ctx, in)
 }
 
+type validatePermissionsKeyspaceStreamAdapter struct {
+	*grpcshim.BidiStream
+	ch chan *vtctldatapb.ValidatePermissionsKeyspaceResponse
+}
+
+func (stream *validatePermissionsKeyspaceStreamAdapter) Recv() (*vtctldatapb.ValidatePermissionsKeyspaceResponse, error) {
+	select {
+	case <-stream.Context().Done():
+		return nil, stream.Context().Err()
+	case <-stream.Closed():
+		// Stream has been closed for future sends. If there are messages that
+		// have already been sent, receive them until there are no more. After
+		// all sent messages have been received, Recv will return the CloseErr.
+		select {
+		case msg := <-stream.ch:
+			return msg, nil
+		default:
+			return nil, stream.CloseErr()
+		}
+	case err := <-stream.ErrCh:
+		return nil, err
+	case msg := <-stream.ch:
+		return msg, nil
+	}
+}
+
+func (stream *validatePermissionsKeyspaceStreamAdapter) Send(msg *vtctldatapb.ValidatePermissionsKeyspaceResponse) error {
+	select {
+	case <-stream.Context().Done():
+		return stream.Context().Err()
+	case <-stream.Closed():
+		return grpcshim.ErrStreamClosed
+	case stream.ch <- msg:
+		return nil
+	}
+}
+
+// ValidatePermissionsKeyspace is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ValidatePermissionsKeyspace(ctx context.Context, in *vtctldatapb.ValidatePermissionsKeyspaceRequest, opts ...grpc.CallOption) (vtctlservicepb.Vtctld_ValidatePermissionsKeyspaceClient, error) {
+	stream := &validatePermissionsKeyspaceStreamAdapter{
+		BidiStream: grpcshim.NewBidiStream(ctx),
+		ch:         make(chan *vtctldatapb.ValidatePermissionsKeyspaceResponse, 1),
+	}
+	go func() {
+		err := client.s.ValidatePermissionsKeyspace(in, stream)
+		stream.CloseWithError(err)
+	}()
+
+	return stream, nil
+}
+
 // ValidateSchemaKeyspace is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) ValidateSchemaKeyspace(ctx context.Context, in *vtctldatapb.ValidateSchemaKeyspaceRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidateSchemaKeyspaceResponse, error) {
 	return client.s.ValidateSchemaKeyspace(ctx, in)
 }
 
+type validateSchemaKeyspaceStreamStreamAdapter struct {
+	*grpcshim.BidiStream
+	ch chan *vtctldatapb.ValidateSchemaKeyspaceStreamResponse
+}
+
+func (stream *validateSchemaKeyspaceStreamStreamAdapter) Recv() (*vtctldatapb.ValidateSchemaKeyspaceStreamResponse, error) {
+	select {
+	case <-stream.Context().Done():
+		return nil, stream.Context().Err()
+	case <-stream.Closed():
+		// Stream has been closed for future sends. If there are messages that
+		// have already been sent, receive them until there are no more. After
+		// all sent messages have been received, Recv will return the CloseErr.
+		select {
+		case msg := <-stream.ch:
+			return msg, nil
+		default:
+			return nil, stream.CloseErr()
+		}
+	case err := <-stream.ErrCh:
+		return nil, err
+	case msg := <-stream.ch:
+		return msg, nil
+	}
+}
+
+func (stream *validateSchemaKeyspaceStreamStreamAdapter) Send(msg *vtctldatapb.ValidateSchemaKeyspaceStreamResponse) error {
+	select {
+	case <-stream.Context().Done():
+		return stream.Context().Err()
+	case <-stream.Closed():
+		return grpcshim.ErrStreamClosed
+	case stream.ch <- msg:
+		return nil
+	}
+}
+
+// ValidateSchemaKeyspaceStream is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ValidateSchemaKeyspaceStream(ctx context.Context, in *vtctldatapb.ValidateSchemaKeyspaceRequest, opts ...grpc.CallOption) (vtctlservicepb.Vtctld_ValidateSchemaKeyspaceStreamClient, error) {
+	stream := &validateSchemaKeyspaceStreamStreamAdapter{
+		BidiStream: grpcshim.NewBidiStream(ctx),
+		ch:         make(chan *vtctldatapb.ValidateSchemaKeyspaceStreamResponse, 1),
+	}
+	go func() {
+		err := client.s.ValidateSchemaKeyspaceStream(in, stream)
+		stream.CloseWithError(err)
+	}()
+
+	return stream, nil
+}
+
 // ValidateShard is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) ValidateShard(ctx context.Context, in *vtctldatapb.ValidateShardRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidateShardResponse, error) {
 	return client.s.ValidateShard(ctx, in)
@@ -617,6 +941,159 @@ func (client *localVtctldClient) ValidateVersionShard(ctx context.Context, in *v
 	return client.s.ValidateVersionShard(ctx, in)
 }
 
+type watchKeyspaceStreamAdapter struct {
+	*grpcshim.BidiStream
+	ch chan *vtctldatapb.WatchKeyspaceResponse
+}
+
+func (stream *watchKeyspaceStreamAdapter) Recv() (*vtctldatapb.WatchKeyspaceResponse, error) {
+	select {
+	case <-stream.Context().Done():
+		return nil, stream.Context().Err()
+	case <-stream.Closed():
+		// Stream has been closed for future sends. If there are messages that
+		// have already been sent, receive them until there are no more. After
+		// all sent messages have been received, Recv will return the CloseErr.
+		select {
+		case msg := <-stream.ch:
+			return msg, nil
+		default:
+			return nil, stream.CloseErr()
+		}
+	case err := <-stream.ErrCh:
+		return nil, err
+	case msg := <-stream.ch:
+		return msg, nil
+	}
+}
+
+func (stream *watchKeyspaceStreamAdapter) Send(msg *vtctldatapb.WatchKeyspaceResponse) error {
+	select {
+	case <-stream.Context().Done():
+		return stream.Context().Err()
+	case <-stream.Closed():
+		return grpcshim.ErrStreamClosed
+	case stream.ch <- msg:
+		return nil
+	}
+}
+
+// WatchKeyspace is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) WatchKeyspace(ctx context.Context, in *vtctldatapb.WatchKeyspaceRequest, opts ...grpc.CallOption) (vtctlservicepb.Vtctld_WatchKeyspaceClient, error) {
+	stream := &watchKeyspaceStreamAdapter{
+		BidiStream: grpcshim.NewBidiStream(ctx),
+		ch:         make(chan *vtctldatapb.WatchKeyspaceResponse, 1),
+	}
+	go func() {
+		err := client.s.WatchKeyspace(in, stream)
+		stream.CloseWithError(err)
+	}()
+
+	return stream, nil
+}
+
+type watchShardStreamAdapter struct {
+	*grpcshim.BidiStream
+	ch chan *vtctldatapb.WatchShardResponse
+}
+
+func (stream *watchShardStreamAdapter) Recv() (*vtctldatapb.WatchShardResponse, error) {
+	select {
+	case <-stream.Context().Done():
+		return nil, stream.Context().Err()
+	case <-stream.Closed():
+		// Stream has been closed for future sends. If there are messages that
+		// have already been sent, receive them until there are no more. After
+		// all sent messages have been received, Recv will return the CloseErr.
+		select {
+		case msg := <-stream.ch:
+			return msg, nil
+		default:
+			return nil, stream.CloseErr()
+		}
+	case err := <-stream.ErrCh:
+		return nil, err
+	case msg := <-stream.ch:
+		return msg, nil
+	}
+}
+
+func (stream *watchShardStreamAdapter) Send(msg *vtctldatapb.WatchShardResponse) error {
+	select {
+	case <-stream.Context().Done():
+		return stream.Context().Err()
+	case <-stream.Closed():
+		return grpcshim.ErrStreamClosed
+	case stream.ch <- msg:
+		return nil
+	}
+}
+
+// WatchShard is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) WatchShard(ctx context.Context, in *vtctldatapb.WatchShardRequest, opts ...grpc.CallOption) (vtctlservicepb.Vtctld_WatchShardClient, error) {
+	stream := &watchShardStreamAdapter{
+		BidiStream: grpcshim.NewBidiStream(ctx),
+		ch:         make(chan *vtctldatapb.WatchShardResponse, 1),
+	}
+	go func() {
+		err := client.s.WatchShard(in, stream)
+		stream.CloseWithError(err)
+	}()
+
+	return stream, nil
+}
+
+type watchSrvVSchemaStreamAdapter struct {
+	*grpcshim.BidiStream
+	ch chan *vtctldatapb.WatchSrvVSchemaResponse
+}
+
+func (stream *watchSrvVSchemaStreamAdapter) Recv() (*vtctldatapb.WatchSrvVSchemaResponse, error) {
+	select {
+	case <-stream.Context().Done():
+		return nil, stream.Context().Err()
+	case <-stream.Closed():
+		// Stream has been closed for future sends. If there are messages that
+		// have already been sent, receive them until there are no more. After
+		// all sent messages have been received, Recv will return the CloseErr.
+		select {
+		case msg := <-stream.ch:
+			return msg, nil
+		default:
+			return nil, stream.CloseErr()
+		}
+	case err := <-stream.ErrCh:
+		return nil, err
+	case msg := <-stream.ch:
+		return msg, nil
+	}
+}
+
+func (stream *watchSrvVSchemaStreamAdapter) Send(msg *vtctldatapb.WatchSrvVSchemaResponse) error {
+	select {
+	case <-stream.Context().Done():
+		return stream.Context().Err()
+	case <-stream.Closed():
+		return grpcshim.ErrStreamClosed
+	case stream.ch <- msg:
+		return nil
+	}
+}
+
+// WatchSrvVSchema is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) WatchSrvVSchema(ctx context.Context, in *vtctldatapb.WatchSrvVSchemaRequest, opts ...grpc.CallOption) (vtctlservicepb.Vtctld_WatchSrvVSchemaClient, error) {
+	stream := &watchSrvVSchemaStreamAdapter{
+		BidiStream: grpcshim.NewBidiStream(ctx),
+		ch:         make(chan *vtctldatapb.WatchSrvVSchemaResponse, 1),
+	}
+	go func() {
+		err := client.s.WatchSrvVSchema(in, stream)
+		stream.CloseWithError(err)
+	}()
+
+	return stream, nil
+}
+
 // WorkflowDelete is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) WorkflowDelete(ctx context.Context, in *vtctldatapb.WorkflowDeleteRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowDeleteResponse, error) {
 	return client.s.WorkflowDelete(ctx, in)
@@ -77,7 +77,7 @@ func commandReparentTablet(ctx context.Context, wr *wrangler.Wrangler, subFlags
 	if subFlags.NArg() != 1 {
 		return fmt.Errorf("action ReparentTablet requires <tablet alias>")
 	}
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := parseTabletAlias(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -97,11 +97,11 @@ func commandInitShardPrimary(ctx context.Context, wr *wrangler.Wrangler, subFlag
 	if subFlags.NArg() != 2 {
 		return fmt.Errorf("action InitShardPrimary requires <keyspace/shard> <tablet alias>")
 	}
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(1))
+	tabletAlias, err := parseTabletAlias(ctx, subFlags.Arg(1))
 	if err != nil {
 		return err
 	}
@@ -132,19 +132,19 @@ func commandPlannedReparentShard(ctx context.Context, wr *wrangler.Wrangler, sub
 		return fmt.Errorf("action PlannedReparentShard requires --keyspace_shard=<keyspace/shard> [--new_primary=<tablet alias>] [--avoid_tablet=<tablet alias>]")
 	}
 
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(*keyspaceShard)
+	keyspace, shard, err := parseKeyspaceShard(ctx, *keyspaceShard)
 	if err != nil {
 		return err
 	}
 	var newPrimaryAlias, avoidTabletAlias *topodatapb.TabletAlias
 	if *newPrimary != "" {
-		newPrimaryAlias, err = topoproto.ParseTabletAlias(*newPrimary)
+		newPrimaryAlias, err = parseTabletAlias(ctx, *newPrimary)
 		if err != nil {
 			return err
 		}
 	}
 	if *avoidTablet != "" {
-		avoidTabletAlias, err = topoproto.ParseTabletAlias(*avoidTablet)
+		avoidTabletAlias, err = parseTabletAlias(ctx, *avoidTablet)
 		if err != nil {
 			return err
 		}
@@ -178,13 +178,13 @@ func commandEmergencyReparentShard(ctx context.Context, wr *wrangler.Wrangler, s
 		return fmt.Errorf("action EmergencyReparentShard requires --keyspace_shard=<keyspace/shard>")
 	}
 
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(*keyspaceShard)
+	keyspace, shard, err := parseKeyspaceShard(ctx, *keyspaceShard)
 	if err != nil {
 		return err
 	}
 	var tabletAlias *topodatapb.TabletAlias
 	if *newPrimary != "" {
-		tabletAlias, err = topoproto.ParseTabletAlias(*newPrimary)
+		tabletAlias, err = parseTabletAlias(ctx, *newPrimary)
 		if err != nil {
 			return err
 		}
@@ -201,7 +201,7 @@ func commandTabletExternallyReparented(ctx context.Context, wr *wrangler.Wrangle
 		return fmt.Errorf("action TabletExternallyReparented requires <tablet alias>")
 	}
 
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := parseTabletAlias(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,239 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reparentutil
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil/promotionrule"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// CandidateScoreWeights are the weights of the criteria the candidates of an
+// emergency reparent are scored on. A criterion with a zero weight is ignored.
+type CandidateScoreWeights struct {
+	// ReplicationLag weighs how few transactions the candidate is missing
+	// from the most advanced tablet, which it has to catch up on before it is
+	// promoted.
+	ReplicationLag float64
+	// CellLocality weighs whether the candidate is in the cell of the
+	// previous primary.
+	CellLocality float64
+	// DurabilityAcks weighs whether the reachable tablets can send the
+	// candidate more semi-sync acks than its durability policy requires, so
+	// that it keeps accepting writes if one of them fails.
+	DurabilityAcks float64
+	// CellPreference weighs the rank of the cell of the candidate in the
+	// preferred cells.
+	CellPreference float64
+	// PromotionRule weighs the promotion rule of the candidate.
+	PromotionRule float64
+}
+
+// DefaultCandidateScoreWeights are the weights used for the criteria whose
+// weight isn't configured.
+var DefaultCandidateScoreWeights = CandidateScoreWeights{
+	ReplicationLag: 4,
+	PromotionRule:  3,
+	CellLocality:   2,
+	DurabilityAcks: 2,
+	CellPreference: 1,
+}
+
+// ParseCandidateScoreWeights parses weights given by criterion name, like
+// replication_lag=4. The criteria are replication_lag, cell_locality,
+// durability_acks, cell_preference and promotion_rule, and the ones that are
+// not given keep their default weight.
+func ParseCandidateScoreWeights(weights map[string]string) (CandidateScoreWeights, error) {
+	parsed := DefaultCandidateScoreWeights
+	for name, value := range weights {
+		weight, err := strconv.ParseFloat(value, 64)
+		if err != nil || weight < 0 {
+			return parsed, fmt.Errorf("invalid weight %q for %v: must be a non-negative number", value, name)
+		}
+		switch name {
+		case "replication_lag":
+			parsed.ReplicationLag = weight
+		case "cell_locality":
+			parsed.CellLocality = weight
+		case "durability_acks":
+			parsed.DurabilityAcks = weight
+		case "cell_preference":
+			parsed.CellPreference = weight
+		case "promotion_rule":
+			parsed.PromotionRule = weight
+		default:
+			return parsed, fmt.Errorf("unknown candidate score criterion %v", name)
+		}
+	}
+	return parsed, nil
+}
+
+// CandidateScoring makes an emergency reparent choose the tablet to promote
+// by scoring each of the valid candidates on several criteria, instead of only
+// ranking them by promotion rule. The candidates that can't be promoted, like
+// the ones with the must_not promotion rule, are still never chosen.
+type CandidateScoring struct {
+	Weights CandidateScoreWeights
+	// PreferredCells are the cells whose tablets are preferred for promotion,
+	// from the most preferred one.
+	PreferredCells []string
+}
+
+// CandidateScore is the score of a candidate of an emergency reparent, with
+// the score of each criterion, between 0 and 1, before it is weighed.
+type CandidateScore struct {
+	Tablet         *topodatapb.Tablet
+	ReplicationLag float64
+	CellLocality   float64
+	DurabilityAcks float64
+	CellPreference float64
+	PromotionRule  float64
+	// Total is the weighed sum of the scores of the criteria.
+	Total float64
+}
+
+// String is part of the fmt.Stringer interface.
+func (score *CandidateScore) String() string {
+	return fmt.Sprintf("%v: %.3f (replication_lag=%.2f cell_locality=%.2f durability_acks=%.2f cell_preference=%.2f promotion_rule=%.2f)",
+		topoproto.TabletAliasString(score.Tablet.Alias), score.Total,
+		score.ReplicationLag, score.CellLocality, score.DurabilityAcks, score.CellPreference, score.PromotionRule)
+}
+
+// scoreCandidates scores the candidates, given the most advanced tablet, the
+// replication positions of the tablets by alias, the previous primary, which
+// can be nil, and the tablets that were reachable. The scores are returned in
+// the order of the candidates.
+func scoreCandidates(
+	scoring *CandidateScoring,
+	durability Durabler,
+	mostAdvanced *topodatapb.Tablet,
+	candidates []*topodatapb.Tablet,
+	positions map[string]replication.Position,
+	prevPrimary *topodatapb.Tablet,
+	reachableTablets []*topodatapb.Tablet,
+) []*CandidateScore {
+	mostAdvancedPosition := positions[topoproto.TabletAliasString(mostAdvanced.Alias)]
+
+	scores := make([]*CandidateScore, 0, len(candidates))
+	for _, candidate := range candidates {
+		score := &CandidateScore{
+			Tablet:         candidate,
+			ReplicationLag: replicationLagScore(mostAdvancedPosition, positions[topoproto.TabletAliasString(candidate.Alias)]),
+			DurabilityAcks: durabilityAcksScore(durability, candidate, reachableTablets),
+			CellPreference: cellPreferenceScore(candidate, scoring.PreferredCells),
+			PromotionRule:  promotionRuleScore(PromotionRule(durability, candidate)),
+		}
+		if prevPrimary != nil && candidate.Alias.Cell == prevPrimary.Alias.Cell {
+			score.CellLocality = 1
+		}
+
+		weights := scoring.Weights
+		score.Total = weights.ReplicationLag*score.ReplicationLag +
+			weights.CellLocality*score.CellLocality +
+			weights.DurabilityAcks*score.DurabilityAcks +
+			weights.CellPreference*score.CellPreference +
+			weights.PromotionRule*score.PromotionRule
+		scores = append(scores, score)
+	}
+	return scores
+}
+
+// bestScoredCandidate returns the candidate with the highest score. The ties
+// are broken in favor of the intermediate source, which doesn't have to catch
+// up, and then of the first candidate.
+func bestScoredCandidate(intermediateSource *topodatapb.Tablet, scores []*CandidateScore) *topodatapb.Tablet {
+	sorted := make([]*CandidateScore, len(scores))
+	copy(sorted, scores)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Total != sorted[j].Total {
+			return sorted[i].Total > sorted[j].Total
+		}
+		return topoproto.TabletAliasEqual(sorted[i].Tablet.Alias, intermediateSource.Alias) &&
+			!topoproto.TabletAliasEqual(sorted[j].Tablet.Alias, intermediateSource.Alias)
+	})
+	if len(sorted) == 0 {
+		return nil
+	}
+	return sorted[0].Tablet
+}
+
+// replicationLagScore is 1 for a candidate that is as advanced as the most
+// advanced tablet, and decreases with each transaction it is missing.
+func replicationLagScore(mostAdvanced, candidate replication.Position) float64 {
+	mostAdvancedSet, ok := mostAdvanced.GTIDSet.(replication.Mysql56GTIDSet)
+	if !ok {
+		return 1
+	}
+	candidateSet, ok := candidate.GTIDSet.(replication.Mysql56GTIDSet)
+	if !ok {
+		return 0
+	}
+	missing := mostAdvancedSet.Difference(candidateSet).Count()
+	return 1 / (1 + float64(missing))
+}
+
+// durabilityAcksScore is the ratio of the semi-sync acks the reachable tablets
+// can send the candidate, up to one more than its durability policy requires.
+func durabilityAcksScore(durability Durabler, candidate *topodatapb.Tablet, reachableTablets []*topodatapb.Tablet) float64 {
+	required := SemiSyncAckers(durability, candidate)
+	if required == 0 {
+		return 1
+	}
+	ackers := 0
+	for _, tablet := range reachableTablets {
+		if topoproto.TabletAliasEqual(tablet.Alias, candidate.Alias) {
+			continue
+		}
+		if IsReplicaSemiSync(durability, candidate, tablet) {
+			ackers++
+		}
+	}
+	if ackers > required+1 {
+		ackers = required + 1
+	}
+	return float64(ackers) / float64(required+1)
+}
+
+// cellPreferenceScore is 1 for a candidate in the most preferred cell, and
+// decreases with the rank of its cell, down to 0 out of the preferred cells.
+func cellPreferenceScore(candidate *topodatapb.Tablet, preferredCells []string) float64 {
+	for i, cell := range preferredCells {
+		if cell == candidate.Alias.Cell {
+			return float64(len(preferredCells)-i) / float64(len(preferredCells))
+		}
+	}
+	return 0
+}
+
+// promotionRuleScore maps the promotion rules of the valid candidates to a
+// score.
+func promotionRuleScore(rule promotionrule.CandidatePromotionRule) float64 {
+	switch rule {
+	case promotionrule.Must, promotionrule.Prefer:
+		return 1
+	case promotionrule.Neutral:
+		return 0.5
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reparentutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/replication"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func mustDecodePosition(t *testing.T, s string) replication.Position {
+	t.Helper()
+	position, err := replication.DecodePosition(s)
+	require.NoError(t, err)
+	return position
+}
+
+func TestParseCandidateScoreWeights(t *testing.T) {
+	weights, err := ParseCandidateScoreWeights(nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultCandidateScoreWeights, weights)
+
+	weights, err = ParseCandidateScoreWeights(map[string]string{
+		"replication_lag": "10",
+		"cell_preference": "0.5",
+	})
+	require.NoError(t, err)
+	expected := DefaultCandidateScoreWeights
+	expected.ReplicationLag = 10
+	expected.CellPreference = 0.5
+	assert.Equal(t, expected, weights)
+
+	_, err = ParseCandidateScoreWeights(map[string]string{"cell_locality": "-1"})
+	assert.ErrorContains(t, err, "must be a non-negative number")
+	_, err = ParseCandidateScoreWeights(map[string]string{"cell_locality": "high"})
+	assert.ErrorContains(t, err, "must be a non-negative number")
+	_, err = ParseCandidateScoreWeights(map[string]string{"uptime": "1"})
+	assert.ErrorContains(t, err, "unknown candidate score criterion uptime")
+}
+
+func TestScoreCandidates(t *testing.T) {
+	durability, err := GetDurabilityPolicy("semi_sync")
+	require.NoError(t, err)
+
+	prevPrimary := &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+		Type:  topodatapb.TabletType_PRIMARY,
+	}
+	mostAdvanced := &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{Cell: "zone2", Uid: 101},
+		Type:  topodatapb.TabletType_REPLICA,
+	}
+	lagging := &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 102},
+		Type:  topodatapb.TabletType_REPLICA,
+	}
+	rdonly := &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 103},
+		Type:  topodatapb.TabletType_RDONLY,
+	}
+	positions := map[string]replication.Position{
+		"zone2-0000000101": mustDecodePosition(t, "MySQL56/3e11fa47-71ca-11e1-9e33-c80aa9429562:1-10"),
+		"zone1-0000000102": mustDecodePosition(t, "MySQL56/3e11fa47-71ca-11e1-9e33-c80aa9429562:1-9"),
+	}
+	scoring := &CandidateScoring{
+		Weights:        DefaultCandidateScoreWeights,
+		PreferredCells: []string{"zone3", "zone1"},
+	}
+
+	scores := scoreCandidates(scoring, durability, mostAdvanced, []*topodatapb.Tablet{mostAdvanced, lagging},
+		positions, prevPrimary, []*topodatapb.Tablet{mostAdvanced, lagging, rdonly})
+	require.Len(t, scores, 2)
+
+	// The most advanced tablet can get acks from the lagging replica only.
+	assert.Equal(t, &CandidateScore{
+		Tablet:         mostAdvanced,
+		ReplicationLag: 1,
+		DurabilityAcks: 0.5,
+		PromotionRule:  0.5,
+		Total:          4 + 1 + 1.5,
+	}, scores[0])
+	assert.Equal(t, &CandidateScore{
+		Tablet:         lagging,
+		ReplicationLag: 0.5,
+		CellLocality:   1,
+		DurabilityAcks: 0.5,
+		CellPreference: 0.5,
+		PromotionRule:  0.5,
+		Total:          2 + 2 + 1 + 0.5 + 1.5,
+	}, scores[1])
+	assert.Equal(t, "zone1-0000000102: 7.000 (replication_lag=0.50 cell_locality=1.00 durability_acks=0.50 cell_preference=0.50 promotion_rule=0.50)", scores[1].String())
+	assert.Equal(t, lagging, bestScoredCandidate(mostAdvanced, scores))
+
+	// Without the locality and the cell preference, the most advanced tablet
+	// wins.
+	scoring.Weights.CellLocality = 0
+	scoring.Weights.CellPreference = 0
+	scores = scoreCandidates(scoring, durability, mostAdvanced, []*topodatapb.Tablet{lagging, mostAdvanced},
+		positions, prevPrimary, []*topodatapb.Tablet{mostAdvanced, lagging, rdonly})
+	assert.Equal(t, mostAdvanced, bestScoredCandidate(mostAdvanced, scores))
+}
+
+func TestBestScoredCandidate(t *testing.T) {
+	first := &topodatapb.Tablet{Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}}
+	second := &topodatapb.Tablet{Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 101}}
+	third := &topodatapb.Tablet{Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 102}}
+
+	scores := []*CandidateScore{
+		{Tablet: first, Total: 5},
+		{Tablet: second, Total: 5},
+		{Tablet: third, Total: 4},
+	}
+	assert.Equal(t, first, bestScoredCandidate(third, scores))
+	assert.Equal(t, second, bestScoredCandidate(second, scores))
+	assert.Nil(t, bestScoredCandidate(first, nil))
+}
+
+func TestReplicationLagScore(t *testing.T) {
+	mostAdvanced := mustDecodePosition(t, "MySQL56/3e11fa47-71ca-11e1-9e33-c80aa9429562:1-10")
+
+	assert.Equal(t, 1.0, replicationLagScore(mostAdvanced, mostAdvanced))
+	assert.Equal(t, 0.5, replicationLagScore(mostAdvanced, mustDecodePosition(t, "MySQL56/3e11fa47-71ca-11e1-9e33-c80aa9429562:1-9")))
+	assert.Equal(t, 0.0, replicationLagScore(mostAdvanced, replication.Position{}))
+	assert.Equal(t, 1.0, replicationLagScore(replication.Position{}, mostAdvanced))
+}
@@ -85,6 +85,51 @@ func RegisterDurability(name string, newDurablerFunc NewDurabler) {
 	durabilityPolicies[name] = newDurablerFunc
 }
 
+// durabilityWithPromotionRules is a Durabler whose promotion rules are
+// overridden by tablet alias or by cell.
+type durabilityWithPromotionRules struct {
+	Durabler
+	rules map[string]promotionrule.CandidatePromotionRule
+}
+
+// withPromotionRules returns the durability policy with its promotion rules
+// overridden by the given rules, keyed by tablet alias or by cell. A rule for
+// a tablet takes precedence over a rule for its cell.
+func withPromotionRules(durability Durabler, rules map[string]promotionrule.CandidatePromotionRule) Durabler {
+	if len(rules) == 0 {
+		return durability
+	}
+	return &durabilityWithPromotionRules{
+		Durabler: durability,
+		rules:    rules,
+	}
+}
+
+func (d *durabilityWithPromotionRules) promotionRule(tablet *topodatapb.Tablet) promotionrule.CandidatePromotionRule {
+	if rule, ok := d.rules[topoproto.TabletAliasString(tablet.Alias)]; ok {
+		return rule
+	}
+	if rule, ok := d.rules[tablet.Alias.Cell]; ok {
+		return rule
+	}
+	return d.Durabler.promotionRule(tablet)
+}
+
+// isInPromotionCells returns whether the tablet can be promoted when the
+// promotions are restricted to the given cells. All the cells are allowed if
+// the list is empty.
+func isInPromotionCells(tablet *topodatapb.Tablet, cells []string) bool {
+	if len(cells) == 0 {
+		return true
+	}
+	for _, cell := range cells {
+		if tablet.Alias.Cell == cell {
+			return true
+		}
+	}
+	return false
+}
+
 //=======================================================================
 
 // GetDurabilityPolicy is used to get a new durability policy from the registered policies
@@ -62,11 +62,23 @@ type EmergencyReparentOptions struct {
 	WaitAllTablets            bool
 	WaitReplicasTimeout       time.Duration
 	PreventCrossCellPromotion bool
+	// PromotionCells, if set, are the only cells whose tablets can be promoted.
+	PromotionCells []string
+	// PromotionRules override the promotion rules of the durability policy,
+	// by tablet alias or by cell.
+	PromotionRules map[string]promotionrule.CandidatePromotionRule
+	// CandidateScoring, if set, makes ERS choose the tablet to promote by
+	// scoring the valid candidates. The scores are logged.
+	CandidateScoring *CandidateScoring
 
 	// Private options managed internally. We use value passing to avoid leaking
 	// these details back out.
 	lockAction string
 	durability Durabler
+	// The state the candidates are scored with, if CandidateScoring is set.
+	prevPrimary      *topodatapb.Tablet
+	positions        map[string]replication.Position
+	reachableTablets []*topodatapb.Tablet
 }
 
 // counters for Emergency Reparent Shard
@@ -175,6 +187,7 @@ func (erp *EmergencyReparenter) reparentShardLocked(ctx context.Context, ev *eve
 	if err != nil {
 		return err
 	}
+	opts.durability = withPromotionRules(opts.durability, opts.PromotionRules)
 
 	// get the previous primary according to the topology server,
 	// we use this information to choose the best candidate in the same cell
@@ -240,12 +253,17 @@ func (erp *EmergencyReparenter) reparentShardLocked(ctx context.Context, ev *eve
 	// 1. Only keep the tablets which can make progress after being promoted (have sufficient reachable semi-sync ackers)
 	// 2. Remove the tablets with the Must_not promote rule
 	// 3. Remove cross-cell tablets if PreventCrossCellPromotion is specified
+	// 4. Remove the tablets outside of the PromotionCells, if they are specified
 	// Our final primary candidate MUST belong to this list of valid candidates
 	validCandidateTablets, err = erp.filterValidCandidates(validCandidateTablets, stoppedReplicationSnapshot.reachableTablets, prevPrimary, opts)
 	if err != nil {
 		return err
 	}
 
+	opts.prevPrimary = prevPrimary
+	opts.positions = validCandidates
+	opts.reachableTablets = stoppedReplicationSnapshot.reachableTablets
+
 	// Check whether the intermediate source candidate selected is ideal or if it can be improved later.
 	// If the intermediateSource is ideal, then we can be certain that it is part of the valid candidates list.
 	isIdeal, err = erp.isIntermediateSourceIdeal(intermediateSource, validCandidateTablets, tabletMap, opts)
@@ -671,6 +689,16 @@ func (erp *EmergencyReparenter) identifyPrimaryCandidate(
 		return nil, vterrors.Errorf(vtrpc.Code_ABORTED, "requested candidate %v is not in valid candidates list", requestedPrimaryAlias)
 	}
 
+	// With candidate scoring, the candidate with the best score is chosen, the
+	// intermediate source winning the ties.
+	if opts.CandidateScoring != nil {
+		scores := scoreCandidates(opts.CandidateScoring, opts.durability, intermediateSource, validCandidates, opts.positions, opts.prevPrimary, opts.reachableTablets)
+		for _, score := range scores {
+			erp.logger.Infof("candidate score - %v", score)
+		}
+		return bestScoredCandidate(intermediateSource, scores), nil
+	}
+
 	// We have already selected an intermediate source which was selected based on the replication position
 	// (ties broken by promotion rules), but that tablet might not even be a valid candidate i.e. it could
 	// be in a different cell when we have PreventCrossCellPromotion specified, or it could have a promotion rule of
@@ -744,6 +772,14 @@ func (erp *EmergencyReparenter) filterValidCandidates(validTablets []*topodatapb
 			}
 			continue
 		}
+		// Remove any tablet which isn't in the cells allowed for promotion, if they are restricted
+		if !isInPromotionCells(tablet, opts.PromotionCells) {
+			erp.logger.Infof("Removing %s from list of valid candidates for promotion because it isn't in the cells allowed for promotion", tabletAliasStr)
+			if opts.NewPrimaryAlias != nil && topoproto.TabletAliasEqual(opts.NewPrimaryAlias, tablet.Alias) {
+				return nil, vterrors.Errorf(vtrpc.Code_ABORTED, "proposed primary %s isn't in the cells allowed for promotion", topoproto.TabletAliasString(opts.NewPrimaryAlias))
+			}
+			continue
+		}
 		// Remove any tablet which cannot make forward progress using the list of tablets we have reached
 		if !canEstablishForTablet(opts.durability, tablet, tabletsReachable) {
 			erp.logger.Infof("Removing %s from list of valid candidates for promotion because it will not be able to make forward progress on promotion with the tablets currently reachable", tabletAliasStr)
@@ -35,6 +35,7 @@ import (
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/topotools/events"
 	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver/testutil"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil/promotionrule"
 	"vitess.io/vitess/go/vt/vtctl/reparentutil/reparenttestutil"
 
 	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
@@ -4219,6 +4220,97 @@ func TestEmergencyReparenter_identifyPrimaryCandidate(t *testing.T) {
 					Uid:  102,
 				},
 			},
+		}, {
+			name: "candidate scoring prefers the preferred cell over a less lagging candidate",
+			emergencyReparentOps: EmergencyReparentOptions{
+				CandidateScoring: &CandidateScoring{
+					Weights:        DefaultCandidateScoreWeights,
+					PreferredCells: []string{"zone1"},
+				},
+				prevPrimary: &topodatapb.Tablet{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  101,
+					},
+				},
+				positions: map[string]replication.Position{
+					"zone2-0000000100": mustDecodePosition(t, "MySQL56/3e11fa47-71ca-11e1-9e33-c80aa9429562:1-10"),
+					"zone1-0000000102": mustDecodePosition(t, "MySQL56/3e11fa47-71ca-11e1-9e33-c80aa9429562:1-9"),
+				},
+			},
+			intermediateSource: &topodatapb.Tablet{
+				Alias: &topodatapb.TabletAlias{
+					Cell: "zone2",
+					Uid:  100,
+				},
+				Type: topodatapb.TabletType_REPLICA,
+			},
+			validCandidates: []*topodatapb.Tablet{
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone2",
+						Uid:  100,
+					},
+					Type: topodatapb.TabletType_REPLICA,
+				}, {
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  102,
+					},
+					Type: topodatapb.TabletType_REPLICA,
+				},
+			},
+			result: &topodatapb.Tablet{
+				Alias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  102,
+				},
+			},
+		}, {
+			name: "candidate scoring keeps the intermediate source on ties",
+			emergencyReparentOps: EmergencyReparentOptions{
+				CandidateScoring: &CandidateScoring{
+					Weights: DefaultCandidateScoreWeights,
+				},
+				prevPrimary: &topodatapb.Tablet{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  101,
+					},
+				},
+				positions: map[string]replication.Position{
+					"zone2-0000000100": mustDecodePosition(t, "MySQL56/3e11fa47-71ca-11e1-9e33-c80aa9429562:1-10"),
+					"zone1-0000000102": mustDecodePosition(t, "MySQL56/3e11fa47-71ca-11e1-9e33-c80aa9429562:1-9"),
+				},
+			},
+			intermediateSource: &topodatapb.Tablet{
+				Alias: &topodatapb.TabletAlias{
+					Cell: "zone2",
+					Uid:  100,
+				},
+				Type: topodatapb.TabletType_REPLICA,
+			},
+			validCandidates: []*topodatapb.Tablet{
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  102,
+					},
+					Type: topodatapb.TabletType_REPLICA,
+				}, {
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone2",
+						Uid:  100,
+					},
+					Type: topodatapb.TabletType_REPLICA,
+				},
+			},
+			result: &topodatapb.Tablet{
+				Alias: &topodatapb.TabletAlias{
+					Cell: "zone2",
+					Uid:  100,
+				},
+			},
 		},
 	}
 
@@ -4438,13 +4530,44 @@ func TestEmergencyReparenter_filterValidCandidates(t *testing.T) {
 				NewPrimaryAlias: primaryTablet.Alias,
 			},
 			errShouldContain: "proposed primary zone-1-0000000001 will not be able to make forward progress on being promoted",
+		}, {
+			name:             "filter promotion cells",
+			durability:       "none",
+			validTablets:     allTablets,
+			tabletsReachable: allTablets,
+			opts: EmergencyReparentOptions{
+				PromotionCells: []string{"zone-2"},
+			},
+			filteredTablets: []*topodatapb.Tablet{replicaCrossCellTablet},
+		}, {
+			name:             "filter promotion rules",
+			durability:       "none",
+			validTablets:     allTablets,
+			tabletsReachable: allTablets,
+			opts: EmergencyReparentOptions{
+				PromotionRules: map[string]promotionrule.CandidatePromotionRule{
+					"zone-2":            promotionrule.MustNot,
+					"zone-1-0000000003": promotionrule.Neutral,
+				},
+			},
+			filteredTablets: []*topodatapb.Tablet{primaryTablet, replicaTablet, rdonlyTablet},
+		}, {
+			name:             "error - requested primary not in promotion cells",
+			durability:       "none",
+			validTablets:     allTablets,
+			tabletsReachable: allTablets,
+			opts: EmergencyReparentOptions{
+				PromotionCells:  []string{"zone-1"},
+				NewPrimaryAlias: replicaCrossCellTablet.Alias,
+			},
+			errShouldContain: "proposed primary zone-2-0000000002 isn't in the cells allowed for promotion",
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			durability, err := GetDurabilityPolicy(tt.durability)
 			require.NoError(t, err)
-			tt.opts.durability = durability
+			tt.opts.durability = withPromotionRules(durability, tt.opts.PromotionRules)
 			logger := logutil.NewMemoryLogger()
 			erp := NewEmergencyReparenter(nil, nil, logger)
 			tabletList, err := erp.filterValidCandidates(tt.validTablets, tt.tabletsReachable, tt.prevPrimary, tt.opts)
@@ -33,6 +33,7 @@ import (
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/topotools/events"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil/promotionrule"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 
@@ -55,6 +56,12 @@ type PlannedReparentOptions struct {
 	NewPrimaryAlias     *topodatapb.TabletAlias
 	AvoidPrimaryAlias   *topodatapb.TabletAlias
 	WaitReplicasTimeout time.Duration
+	// PromotionCells, if set, are the only cells whose tablets can be chosen
+	// as the new primary, when NewPrimaryAlias is not specified.
+	PromotionCells []string
+	// PromotionRules override the promotion rules of the durability policy,
+	// by tablet alias or by cell.
+	PromotionRules map[string]promotionrule.CandidatePromotionRule
 
 	// Private options managed internally. We use value-passing semantics to
 	// set these options inside a PlannedReparent without leaking these details
@@ -166,7 +173,16 @@ func (pr *PlannedReparenter) preflightChecks(
 
 		event.DispatchUpdate(ev, "searching for primary candidate")
 
-		opts.NewPrimaryAlias, err = ChooseNewPrimary(ctx, pr.tmc, &ev.ShardInfo, tabletMap, opts.AvoidPrimaryAlias, opts.WaitReplicasTimeout, opts.durability, pr.logger)
+		candidates := tabletMap
+		if len(opts.PromotionCells) > 0 {
+			candidates = make(map[string]*topo.TabletInfo, len(tabletMap))
+			for alias, info := range tabletMap {
+				if isInPromotionCells(info.Tablet, opts.PromotionCells) {
+					candidates[alias] = info
+				}
+			}
+		}
+		opts.NewPrimaryAlias, err = ChooseNewPrimary(ctx, pr.tmc, &ev.ShardInfo, candidates, opts.AvoidPrimaryAlias, opts.WaitReplicasTimeout, opts.durability, pr.logger)
 		if err != nil {
 			return true, err
 		}
@@ -510,6 +526,7 @@ func (pr *PlannedReparenter) reparentShardLocked(
 	if err != nil {
 		return err
 	}
+	opts.durability = withPromotionRules(opts.durability, opts.PromotionRules)
 
 	ev.ShardInfo = *shardInfo
 
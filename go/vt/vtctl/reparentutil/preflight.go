@@ -0,0 +1,436 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reparentutil
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil/promotionrule"
+
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// Names of the checks run by Preflight.
+const (
+	PreflightCheckPrimary        = "primary"
+	PreflightCheckReachable      = "reachable"
+	PreflightCheckPrimaryElect   = "primary_elect"
+	PreflightCheckDurability     = "durability"
+	PreflightCheckReplication    = "replication"
+	PreflightCheckReplicationLag = "replication_lag"
+	PreflightCheckSemiSync       = "semi_sync"
+	PreflightCheckErrantGTIDs    = "errant_gtids"
+)
+
+// preflightReport accumulates the checks of a Preflight.
+type preflightReport struct {
+	resp *vtctldatapb.ReparentPreflightResponse
+}
+
+func (r *preflightReport) add(name string, alias *topodatapb.TabletAlias, result vtctldatapb.ReparentPreflightCheck_Result, format string, args ...any) {
+	if result == vtctldatapb.ReparentPreflightCheck_FAIL {
+		r.resp.Ok = false
+	}
+
+	r.resp.Checks = append(r.resp.Checks, &vtctldatapb.ReparentPreflightCheck{
+		Name:        name,
+		TabletAlias: alias,
+		Result:      result,
+		Message:     fmt.Sprintf(format, args...),
+	})
+}
+
+// Preflight runs the checks a PlannedReparentShard with the same options
+// depends on against the given shard, without locking or changing anything,
+// and returns a go/no-go report. It returns an error only when the shard
+// itself cannot be read; problems with its tablets are reported as failed
+// checks.
+//
+// The checks cover the reachability of all tablets, the selection and the
+// durability policy compliance of the primary-elect, the replication health
+// and lag of the replicas, their semi-sync configuration, and errant GTIDs.
+func (pr *PlannedReparenter) Preflight(ctx context.Context, keyspace string, shard string, opts PlannedReparentOptions) (*vtctldatapb.ReparentPreflightResponse, error) {
+	shardInfo, err := pr.ts.GetShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+
+	keyspaceDurability, err := pr.ts.GetKeyspaceDurability(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	durability, err := GetDurabilityPolicy(keyspaceDurability)
+	if err != nil {
+		return nil, err
+	}
+
+	tabletMap, err := pr.ts.GetTabletMapForShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &preflightReport{
+		resp: &vtctldatapb.ReparentPreflightResponse{
+			Keyspace: keyspace,
+			Shard:    shard,
+			Ok:       true,
+		},
+	}
+
+	aliases := make([]string, 0, len(tabletMap))
+	for alias := range tabletMap {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	// Checks that run against the primary.
+	currentPrimary := FindCurrentPrimary(tabletMap, pr.logger)
+	if currentPrimary == nil {
+		report.add(PreflightCheckPrimary, nil, vtctldatapb.ReparentPreflightCheck_WARN, "shard has no current primary, PlannedReparentShard would initialize it")
+	} else {
+		report.resp.CurrentPrimary = currentPrimary.Alias
+		report.add(PreflightCheckPrimary, currentPrimary.Alias, vtctldatapb.ReparentPreflightCheck_PASS, "current primary is %v", topoproto.TabletAliasString(currentPrimary.Alias))
+	}
+
+	statuses := pr.getPreflightStatuses(ctx, tabletMap, currentPrimary, opts)
+
+	var reachable []*topodatapb.Tablet
+	for _, alias := range aliases {
+		tablet := tabletMap[alias].Tablet
+		if err := statuses[alias].err; err != nil {
+			report.add(PreflightCheckReachable, tablet.Alias, vtctldatapb.ReparentPreflightCheck_FAIL, "cannot get the status of the tablet: %v", err)
+			continue
+		}
+
+		reachable = append(reachable, tablet)
+		report.add(PreflightCheckReachable, tablet.Alias, vtctldatapb.ReparentPreflightCheck_PASS, "tablet is reachable")
+	}
+
+	// Select the primary-elect the same way PlannedReparentShard does.
+	if opts.NewPrimaryAlias == nil && opts.AvoidPrimaryAlias == nil {
+		opts.AvoidPrimaryAlias = shardInfo.PrimaryAlias
+	}
+
+	newPrimary := pr.preflightPrimaryElect(report, shardInfo, tabletMap, statuses, durability, opts)
+	if newPrimary != nil {
+		report.resp.NewPrimary = newPrimary.Alias
+
+		switch {
+		case PromotionRule(durability, newPrimary) == promotionrule.MustNot:
+			report.add(PreflightCheckDurability, newPrimary.Alias, vtctldatapb.ReparentPreflightCheck_FAIL, "durability policy %s does not allow promoting %v", keyspaceDurability, topoproto.TabletAliasString(newPrimary.Alias))
+		case !canEstablishForTablet(durability, newPrimary, reachable):
+			report.add(PreflightCheckDurability, newPrimary.Alias, vtctldatapb.ReparentPreflightCheck_FAIL, "durability policy %s requires %d semi-sync acks for %v, but only %d reachable tablets can send them",
+				keyspaceDurability, SemiSyncAckers(durability, newPrimary), topoproto.TabletAliasString(newPrimary.Alias), len(SemiSyncAckersForPrimary(durability, newPrimary, reachable)))
+		default:
+			report.add(PreflightCheckDurability, newPrimary.Alias, vtctldatapb.ReparentPreflightCheck_PASS, "durability policy %s allows promoting %v, with %d semi-sync acks required",
+				keyspaceDurability, topoproto.TabletAliasString(newPrimary.Alias), SemiSyncAckers(durability, newPrimary))
+		}
+	}
+
+	// Checks that run against each replica. Failures are only fatal for the
+	// primary-elect; the other replicas get repointed after the reparent.
+	var primaryGTIDs replication.Mysql56GTIDSet
+	if currentPrimary != nil {
+		primaryGTIDs = preflightPrimaryGTIDs(statuses[topoproto.TabletAliasString(currentPrimary.Alias)].status)
+	}
+
+	for _, alias := range aliases {
+		tablet := tabletMap[alias].Tablet
+		status := statuses[alias].status
+		if status == nil {
+			continue
+		}
+
+		failResult := vtctldatapb.ReparentPreflightCheck_WARN
+		if newPrimary != nil && topoproto.TabletAliasEqual(tablet.Alias, newPrimary.Alias) {
+			failResult = vtctldatapb.ReparentPreflightCheck_FAIL
+		}
+
+		if currentPrimary != nil {
+			preflightSemiSync(report, tablet, currentPrimary.Tablet, status, durability)
+		}
+
+		if currentPrimary == nil || topoproto.TabletAliasEqual(tablet.Alias, currentPrimary.Alias) {
+			continue
+		}
+
+		if !topo.IsReplicaType(tablet.Type) {
+			continue
+		}
+
+		preflightReplication(report, tablet, status.ReplicationStatus, failResult, opts)
+
+		if primaryGTIDs != nil {
+			preflightErrantGTIDs(report, tablet, status.ReplicationStatus, primaryGTIDs, failResult)
+		}
+	}
+
+	return report.resp, nil
+}
+
+type preflightStatus struct {
+	status *replicationdatapb.FullStatus
+	err    error
+}
+
+// getPreflightStatuses fetches the full status of every tablet of the shard.
+// The primary is only read once all the replicas have been, so that its GTID
+// set is a superset of theirs unless they have errant GTIDs.
+func (pr *PlannedReparenter) getPreflightStatuses(ctx context.Context, tabletMap map[string]*topo.TabletInfo, currentPrimary *topo.TabletInfo, opts PlannedReparentOptions) map[string]*preflightStatus {
+	ctx, cancel := context.WithTimeout(ctx, opts.WaitReplicasTimeout)
+	defer cancel()
+
+	var (
+		m        sync.Mutex
+		wg       sync.WaitGroup
+		statuses = make(map[string]*preflightStatus, len(tabletMap))
+	)
+
+	getStatus := func(alias string, tablet *topodatapb.Tablet) {
+		status, err := pr.tmc.FullStatus(ctx, tablet)
+
+		m.Lock()
+		defer m.Unlock()
+		statuses[alias] = &preflightStatus{status: status, err: err}
+	}
+
+	for alias, info := range tabletMap {
+		if currentPrimary != nil && alias == topoproto.TabletAliasString(currentPrimary.Alias) {
+			continue
+		}
+
+		wg.Add(1)
+		go func(alias string, tablet *topodatapb.Tablet) {
+			defer wg.Done()
+			getStatus(alias, tablet)
+		}(alias, info.Tablet)
+	}
+	wg.Wait()
+
+	if currentPrimary != nil {
+		getStatus(topoproto.TabletAliasString(currentPrimary.Alias), currentPrimary.Tablet)
+	}
+
+	return statuses
+}
+
+// preflightPrimaryElect reports on the tablet PlannedReparentShard would
+// promote, and returns it. It returns nil if there is none.
+func (pr *PlannedReparenter) preflightPrimaryElect(
+	report *preflightReport,
+	shardInfo *topo.ShardInfo,
+	tabletMap map[string]*topo.TabletInfo,
+	statuses map[string]*preflightStatus,
+	durability Durabler,
+	opts PlannedReparentOptions,
+) *topodatapb.Tablet {
+	if opts.NewPrimaryAlias != nil {
+		alias := topoproto.TabletAliasString(opts.NewPrimaryAlias)
+		if topoproto.TabletAliasEqual(opts.NewPrimaryAlias, opts.AvoidPrimaryAlias) {
+			report.add(PreflightCheckPrimaryElect, opts.NewPrimaryAlias, vtctldatapb.ReparentPreflightCheck_FAIL, "primary-elect tablet %v is the same as the tablet to avoid", alias)
+			return nil
+		}
+
+		info, ok := tabletMap[alias]
+		if !ok {
+			report.add(PreflightCheckPrimaryElect, opts.NewPrimaryAlias, vtctldatapb.ReparentPreflightCheck_FAIL, "primary-elect tablet %v is not in the shard", alias)
+			return nil
+		}
+
+		report.add(PreflightCheckPrimaryElect, info.Alias, vtctldatapb.ReparentPreflightCheck_PASS, "primary-elect is %v", alias)
+		return info.Tablet
+	}
+
+	if shardInfo.PrimaryAlias != nil && !topoproto.TabletAliasEqual(opts.AvoidPrimaryAlias, shardInfo.PrimaryAlias) {
+		report.add(PreflightCheckPrimaryElect, nil, vtctldatapb.ReparentPreflightCheck_WARN, "current primary is different than tablet to avoid, PlannedReparentShard would be a no-op")
+		return nil
+	}
+
+	// Same selection as ChooseNewPrimary, from the statuses we already have.
+	var primaryCell string
+	if shardInfo.PrimaryAlias != nil {
+		primaryCell = shardInfo.PrimaryAlias.Cell
+	}
+
+	var (
+		candidates []*topodatapb.Tablet
+		positions  []replication.Position
+	)
+	for alias, info := range tabletMap {
+		switch {
+		case primaryCell != "" && info.Alias.Cell != primaryCell:
+			continue
+		case opts.AvoidPrimaryAlias != nil && topoproto.TabletAliasEqual(info.Alias, opts.AvoidPrimaryAlias):
+			continue
+		case info.Type != topodatapb.TabletType_REPLICA:
+			continue
+		case statuses[alias].status == nil || statuses[alias].status.ReplicationStatus == nil:
+			continue
+		}
+
+		status := statuses[alias].status.ReplicationStatus
+		positionString := status.Position
+		if status.RelayLogPosition != "" {
+			positionString = status.RelayLogPosition
+		}
+		pos, err := replication.DecodePosition(positionString)
+		if err != nil {
+			continue
+		}
+
+		candidates = append(candidates, info.Tablet)
+		positions = append(positions, pos)
+	}
+
+	if len(candidates) == 0 {
+		report.add(PreflightCheckPrimaryElect, nil, vtctldatapb.ReparentPreflightCheck_FAIL, "cannot find a reachable tablet to reparent to in the same cell as the current primary")
+		return nil
+	}
+
+	if err := sortTabletsForReparent(candidates, positions, durability); err != nil {
+		report.add(PreflightCheckPrimaryElect, nil, vtctldatapb.ReparentPreflightCheck_FAIL, "cannot select a primary-elect: %v", err)
+		return nil
+	}
+
+	report.add(PreflightCheckPrimaryElect, candidates[0].Alias, vtctldatapb.ReparentPreflightCheck_PASS, "primary-elect would be %v", topoproto.TabletAliasString(candidates[0].Alias))
+	return candidates[0]
+}
+
+// preflightSemiSync reports whether the semi-sync configuration of the tablet
+// matches what the durability policy expects with the current primary.
+func preflightSemiSync(report *preflightReport, tablet, primary *topodatapb.Tablet, status *replicationdatapb.FullStatus, durability Durabler) {
+	if topoproto.TabletAliasEqual(tablet.Alias, primary.Alias) {
+		expected := SemiSyncAckers(durability, primary) > 0
+		if status.SemiSyncPrimaryEnabled != expected {
+			report.add(PreflightCheckSemiSync, tablet.Alias, vtctldatapb.ReparentPreflightCheck_WARN, "semi-sync primary is %s, but the durability policy expects it to be %s", enabledString(status.SemiSyncPrimaryEnabled), enabledString(expected))
+			return
+		}
+
+		report.add(PreflightCheckSemiSync, tablet.Alias, vtctldatapb.ReparentPreflightCheck_PASS, "semi-sync primary is %s", enabledString(expected))
+		return
+	}
+
+	expected := IsReplicaSemiSync(durability, primary, tablet)
+	if status.SemiSyncReplicaEnabled != expected {
+		report.add(PreflightCheckSemiSync, tablet.Alias, vtctldatapb.ReparentPreflightCheck_WARN, "semi-sync replica is %s, but the durability policy expects it to be %s", enabledString(status.SemiSyncReplicaEnabled), enabledString(expected))
+		return
+	}
+
+	report.add(PreflightCheckSemiSync, tablet.Alias, vtctldatapb.ReparentPreflightCheck_PASS, "semi-sync replica is %s", enabledString(expected))
+}
+
+func enabledString(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+// preflightReplication reports on the replication threads and lag of a
+// replica.
+func preflightReplication(report *preflightReport, tablet *topodatapb.Tablet, status *replicationdatapb.Status, failResult vtctldatapb.ReparentPreflightCheck_Result, opts PlannedReparentOptions) {
+	if status == nil {
+		report.add(PreflightCheckReplication, tablet.Alias, failResult, "replication is not configured")
+		return
+	}
+
+	ioState := replication.ReplicationState(status.IoState)
+	sqlState := replication.ReplicationState(status.SqlState)
+	if ioState != replication.ReplicationStateRunning || sqlState != replication.ReplicationStateRunning {
+		report.add(PreflightCheckReplication, tablet.Alias, failResult, "replication is not running (IO thread: %s, SQL thread: %s)", replicationStateString(ioState), replicationStateString(sqlState))
+		return
+	}
+
+	report.add(PreflightCheckReplication, tablet.Alias, vtctldatapb.ReparentPreflightCheck_PASS, "replication is running")
+
+	switch lag := float64(status.ReplicationLagSeconds); {
+	case status.ReplicationLagUnknown:
+		report.add(PreflightCheckReplicationLag, tablet.Alias, failResult, "replication lag is unknown")
+	case lag > opts.WaitReplicasTimeout.Seconds():
+		report.add(PreflightCheckReplicationLag, tablet.Alias, failResult, "replication lag of %ds is above the wait replicas timeout of %v", status.ReplicationLagSeconds, opts.WaitReplicasTimeout)
+	default:
+		report.add(PreflightCheckReplicationLag, tablet.Alias, vtctldatapb.ReparentPreflightCheck_PASS, "replication lag is %ds", status.ReplicationLagSeconds)
+	}
+}
+
+func replicationStateString(state replication.ReplicationState) string {
+	switch state {
+	case replication.ReplicationStateStopped:
+		return "stopped"
+	case replication.ReplicationStateConnecting:
+		return "connecting"
+	case replication.ReplicationStateRunning:
+		return "running"
+	default:
+		return "unknown"
+	}
+}
+
+// preflightPrimaryGTIDs returns the executed GTID set of the primary, or nil
+// if it cannot be read or is not a MySQL GTID set.
+func preflightPrimaryGTIDs(status *replicationdatapb.FullStatus) replication.Mysql56GTIDSet {
+	if status == nil || status.PrimaryStatus == nil {
+		return nil
+	}
+
+	pos, err := replication.DecodePosition(status.PrimaryStatus.Position)
+	if err != nil {
+		return nil
+	}
+
+	gtids, ok := pos.GTIDSet.(replication.Mysql56GTIDSet)
+	if !ok {
+		return nil
+	}
+
+	return gtids
+}
+
+// preflightErrantGTIDs reports the GTIDs a replica has executed that the
+// primary has not.
+func preflightErrantGTIDs(report *preflightReport, tablet *topodatapb.Tablet, status *replicationdatapb.Status, primaryGTIDs replication.Mysql56GTIDSet, failResult vtctldatapb.ReparentPreflightCheck_Result) {
+	if status == nil {
+		return
+	}
+
+	pos, err := replication.DecodePosition(status.Position)
+	if err != nil {
+		report.add(PreflightCheckErrantGTIDs, tablet.Alias, vtctldatapb.ReparentPreflightCheck_WARN, "cannot decode the replication position %v: %v", status.Position, err)
+		return
+	}
+
+	gtids, ok := pos.GTIDSet.(replication.Mysql56GTIDSet)
+	if !ok {
+		return
+	}
+
+	if errant := gtids.Difference(primaryGTIDs); len(errant) > 0 {
+		report.add(PreflightCheckErrantGTIDs, tablet.Alias, failResult, "tablet has errant GTIDs: %v", errant)
+		return
+	}
+
+	report.add(PreflightCheckErrantGTIDs, tablet.Alias, vtctldatapb.ReparentPreflightCheck_PASS, "tablet has no errant GTIDs")
+}
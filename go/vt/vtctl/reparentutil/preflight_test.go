@@ -0,0 +1,242 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reparentutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver/testutil"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	"vitess.io/vitess/go/vt/proto/vttime"
+)
+
+type preflightTestTMClient struct {
+	tmclient.TabletManagerClient
+	fullStatuses map[string]*replicationdatapb.FullStatus
+}
+
+func (fake *preflightTestTMClient) FullStatus(ctx context.Context, tablet *topodatapb.Tablet) (*replicationdatapb.FullStatus, error) {
+	if status, ok := fake.fullStatuses[topoproto.TabletAliasString(tablet.Alias)]; ok {
+		return status, nil
+	}
+
+	return nil, assert.AnError
+}
+
+func TestPreflight(t *testing.T) {
+	t.Parallel()
+
+	const primaryUUID = "3e11fa47-71ca-11e1-9e33-c80aa9429562"
+	replicaStatus := func(position string, lag uint32, semiSync bool) *replicationdatapb.FullStatus {
+		return &replicationdatapb.FullStatus{
+			ReplicationStatus: &replicationdatapb.Status{
+				Position:              position,
+				IoState:               int32(replication.ReplicationStateRunning),
+				SqlState:              int32(replication.ReplicationStateRunning),
+				ReplicationLagSeconds: lag,
+			},
+			SemiSyncReplicaEnabled: semiSync,
+		}
+	}
+	healthy := func() map[string]*replicationdatapb.FullStatus {
+		return map[string]*replicationdatapb.FullStatus{
+			"zone1-0000000100": {
+				PrimaryStatus:          &replicationdatapb.PrimaryStatus{Position: "MySQL56/" + primaryUUID + ":1-10"},
+				SemiSyncPrimaryEnabled: true,
+			},
+			"zone1-0000000101": replicaStatus("MySQL56/"+primaryUUID+":1-9", 1, true),
+			"zone1-0000000102": replicaStatus("MySQL56/"+primaryUUID+":1-8", 2, true),
+		}
+	}
+
+	tests := []struct {
+		name       string
+		statuses   func() map[string]*replicationdatapb.FullStatus
+		newPrimary *topodatapb.TabletAlias
+		ok         bool
+		expected   *topodatapb.TabletAlias
+		// checks are the expected results of some checks, keyed by check name
+		// and tablet alias.
+		checks map[string]vtctldatapb.ReparentPreflightCheck_Result
+	}{
+		{
+			name:     "healthy shard",
+			statuses: healthy,
+			ok:       true,
+			expected: &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+			checks: map[string]vtctldatapb.ReparentPreflightCheck_Result{
+				"primary_elect zone1-0000000101":   vtctldatapb.ReparentPreflightCheck_PASS,
+				"durability zone1-0000000101":      vtctldatapb.ReparentPreflightCheck_PASS,
+				"replication_lag zone1-0000000102": vtctldatapb.ReparentPreflightCheck_PASS,
+				"errant_gtids zone1-0000000102":    vtctldatapb.ReparentPreflightCheck_PASS,
+				"semi_sync zone1-0000000100":       vtctldatapb.ReparentPreflightCheck_PASS,
+			},
+		},
+		{
+			name: "problems on a replica that is not the primary-elect",
+			statuses: func() map[string]*replicationdatapb.FullStatus {
+				statuses := healthy()
+				statuses["zone1-0000000102"] = replicaStatus("MySQL56/"+primaryUUID+":1-8", 60, false)
+				return statuses
+			},
+			ok:       true,
+			expected: &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+			checks: map[string]vtctldatapb.ReparentPreflightCheck_Result{
+				"replication_lag zone1-0000000102": vtctldatapb.ReparentPreflightCheck_WARN,
+				"semi_sync zone1-0000000102":       vtctldatapb.ReparentPreflightCheck_WARN,
+			},
+		},
+		{
+			name: "errant GTIDs on the primary-elect",
+			statuses: func() map[string]*replicationdatapb.FullStatus {
+				statuses := healthy()
+				statuses["zone1-0000000102"] = replicaStatus("MySQL56/"+primaryUUID+":1-8,8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1", 2, true)
+				return statuses
+			},
+			newPrimary: &topodatapb.TabletAlias{Cell: "zone1", Uid: 102},
+			ok:         false,
+			expected:   &topodatapb.TabletAlias{Cell: "zone1", Uid: 102},
+			checks: map[string]vtctldatapb.ReparentPreflightCheck_Result{
+				"errant_gtids zone1-0000000102": vtctldatapb.ReparentPreflightCheck_FAIL,
+			},
+		},
+		{
+			name: "errant GTIDs on a replica that is not the primary-elect",
+			statuses: func() map[string]*replicationdatapb.FullStatus {
+				statuses := healthy()
+				statuses["zone1-0000000102"] = replicaStatus("MySQL56/"+primaryUUID+":1-8,8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1", 2, true)
+				return statuses
+			},
+			newPrimary: &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+			ok:         true,
+			expected:   &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+			checks: map[string]vtctldatapb.ReparentPreflightCheck_Result{
+				"errant_gtids zone1-0000000101": vtctldatapb.ReparentPreflightCheck_PASS,
+				"errant_gtids zone1-0000000102": vtctldatapb.ReparentPreflightCheck_WARN,
+			},
+		},
+		{
+			name: "lagging primary-elect",
+			statuses: func() map[string]*replicationdatapb.FullStatus {
+				statuses := healthy()
+				statuses["zone1-0000000101"] = replicaStatus("MySQL56/"+primaryUUID+":1-9", 60, true)
+				return statuses
+			},
+			ok:       false,
+			expected: &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+			checks: map[string]vtctldatapb.ReparentPreflightCheck_Result{
+				"replication_lag zone1-0000000101": vtctldatapb.ReparentPreflightCheck_FAIL,
+			},
+		},
+		{
+			name: "unreachable replica",
+			statuses: func() map[string]*replicationdatapb.FullStatus {
+				statuses := healthy()
+				delete(statuses, "zone1-0000000101")
+				return statuses
+			},
+			ok:       false,
+			expected: &topodatapb.TabletAlias{Cell: "zone1", Uid: 102},
+			checks: map[string]vtctldatapb.ReparentPreflightCheck_Result{
+				"reachable zone1-0000000101":     vtctldatapb.ReparentPreflightCheck_FAIL,
+				"primary_elect zone1-0000000102": vtctldatapb.ReparentPreflightCheck_PASS,
+			},
+		},
+		{
+			name:       "primary-elect not in the shard",
+			statuses:   healthy,
+			newPrimary: &topodatapb.TabletAlias{Cell: "zone1", Uid: 200},
+			ok:         false,
+			checks: map[string]vtctldatapb.ReparentPreflightCheck_Result{
+				"primary_elect zone1-0000000200": vtctldatapb.ReparentPreflightCheck_FAIL,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ts := memorytopo.NewServer(ctx, "zone1")
+			defer ts.Close()
+
+			testutil.AddKeyspace(ctx, t, ts, &vtctldatapb.Keyspace{
+				Name:     "testkeyspace",
+				Keyspace: &topodatapb.Keyspace{DurabilityPolicy: "semi_sync"},
+			})
+			testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{AlsoSetShardPrimary: true},
+				&topodatapb.Tablet{
+					Alias:                &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+					Keyspace:             "testkeyspace",
+					Shard:                "-",
+					Type:                 topodatapb.TabletType_PRIMARY,
+					PrimaryTermStartTime: &vttime.Time{Seconds: 100},
+				},
+				&topodatapb.Tablet{
+					Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+					Keyspace: "testkeyspace",
+					Shard:    "-",
+					Type:     topodatapb.TabletType_REPLICA,
+				},
+				&topodatapb.Tablet{
+					Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 102},
+					Keyspace: "testkeyspace",
+					Shard:    "-",
+					Type:     topodatapb.TabletType_REPLICA,
+				},
+			)
+
+			tmc := &preflightTestTMClient{fullStatuses: tt.statuses()}
+			pr := NewPlannedReparenter(ts, tmc, logutil.NewMemoryLogger())
+			resp, err := pr.Preflight(ctx, "testkeyspace", "-", PlannedReparentOptions{
+				NewPrimaryAlias:     tt.newPrimary,
+				WaitReplicasTimeout: 30 * time.Second,
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.ok, resp.Ok, "%+v", resp.Checks)
+			assert.True(t, topoproto.TabletAliasEqual(tt.expected, resp.NewPrimary), "expected primary-elect %v, got %v", tt.expected, resp.NewPrimary)
+			assert.Equal(t, "zone1-0000000100", topoproto.TabletAliasString(resp.CurrentPrimary))
+
+			results := make(map[string]vtctldatapb.ReparentPreflightCheck_Result, len(resp.Checks))
+			for _, check := range resp.Checks {
+				results[check.Name+" "+topoproto.TabletAliasString(check.TabletAlias)] = check.Result
+			}
+			for key, expected := range tt.checks {
+				if assert.Contains(t, results, key) {
+					assert.Equal(t, expected, results[key], key)
+				}
+			}
+		})
+	}
+}
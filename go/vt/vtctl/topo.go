@@ -21,11 +21,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/pflag"
+	"google.golang.org/protobuf/encoding/protojson"
 
 	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/helpers"
+	"vitess.io/vitess/go/vt/topotools"
 	"vitess.io/vitess/go/vt/wrangler"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
 // This file contains the topo command group for vtctl.
@@ -48,6 +54,34 @@ func init() {
 		params: "[--cell <cell>] [--to_topo] <src> <dst>",
 		help:   "Copies a file from topo to local file structure, or the other way around",
 	})
+
+	addCommand(topoGroupName, command{
+		name:   "TopoBackup",
+		method: commandTopoBackup,
+		params: "[--archive_file <path>]",
+		help:   "Exports the cell infos, keyspaces, shards, vschemas and routing rules of the topology into a versioned JSON archive, which TopoRestore can apply onto another topology. The archive is printed, or written to --archive_file on the host running the command.",
+	})
+
+	addCommand(topoGroupName, command{
+		name:   "TopoRestore",
+		method: commandTopoRestore,
+		params: "{--archive=<archive> || --archive_file=<path>} [--skip_rebuild]",
+		help:   "Applies an archive written by TopoBackup onto the topology, typically a fresh one. Records that already exist with the same contents are skipped, while records that already exist with different contents fail the restore. The serving graph is rebuilt afterwards, unless --skip_rebuild is set.",
+	})
+
+	addCommand(topoGroupName, command{
+		name:   "TopoDoctor",
+		method: commandTopoDoctor,
+		params: "[--repair] [--max_lock_age <duration>]",
+		help:   "Scans the topology for orphan tablets, shards referencing missing cells, stale SrvKeyspaces, broken replication graph entries and dangling keyspace and shard locks, and reports them. With --repair, the issues that can be repaired are repaired. Fails if some issues remain.",
+	})
+
+	addCommand(topoGroupName, command{
+		name:   "PromoteTopo",
+		method: commandPromoteTopo,
+		params: "[--max_mirror_lag <duration>] [--force]",
+		help:   "Promotes the standby global topology, kept up to date by topo2topo --mirror, to replace the primary global topology. It must be run against the standby. The mirror stops writing to it, and the vtgates and vttablets configured with it as --topo_global_standby_server_address switch to it.",
+	})
 }
 
 func commandTopoCat(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
@@ -126,6 +160,84 @@ func copyFileToTopo(ctx context.Context, ts *topo.Server, cell, from, to string)
 	return err
 }
 
+func commandTopoBackup(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	archiveFile := subFlags.String("archive_file", "", "If specified, write the archive to this file instead of printing it.")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 0 {
+		return fmt.Errorf("TopoBackup does not take any positional arguments")
+	}
+
+	archive, err := helpers.BackupTopo(ctx, wr.TopoServer())
+	if err != nil {
+		return fmt.Errorf("TopoBackup: %w", err)
+	}
+
+	if *archiveFile == "" {
+		return printJSON(wr.Logger(), archive)
+	}
+
+	data, err := MarshalJSON(archive)
+	if err != nil {
+		return fmt.Errorf("cannot marshal archive: %w", err)
+	}
+
+	if err := os.WriteFile(*archiveFile, data, 0600); err != nil {
+		return err
+	}
+
+	wr.Logger().Printf("Wrote topo archive of %d keyspaces and %d shards to %s\n", len(archive.Keyspaces), len(archive.Shards), *archiveFile)
+	return nil
+}
+
+func commandTopoRestore(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	archiveJSON := subFlags.String("archive", "", "The archive written by TopoBackup, as JSON.")
+	archiveFile := subFlags.String("archive_file", "", "The file holding the archive written by TopoBackup.")
+	skipRebuild := subFlags.Bool("skip_rebuild", false, "If set, do not rebuild the SrvKeyspace and SrvVSchema objects after the restore.")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 0 {
+		return fmt.Errorf("TopoRestore does not take any positional arguments")
+	}
+
+	if (*archiveJSON != "") == (*archiveFile != "") {
+		return fmt.Errorf("exactly one of the --archive or --archive_file flags must be specified when calling the TopoRestore command")
+	}
+
+	data := []byte(*archiveJSON)
+	if *archiveFile != "" {
+		var err error
+		if data, err = os.ReadFile(*archiveFile); err != nil {
+			return err
+		}
+	}
+
+	archive := &vtctldatapb.TopoArchive{}
+	if err := protojson.Unmarshal(data, archive); err != nil {
+		return fmt.Errorf("cannot parse topo archive: %w", err)
+	}
+
+	ts := wr.TopoServer()
+	if err := helpers.RestoreTopo(ctx, ts, archive, wr.Logger()); err != nil {
+		return fmt.Errorf("TopoRestore: %w", err)
+	}
+
+	if *skipRebuild {
+		wr.Logger().Warningf("Skipping rebuild of the serving graph, SrvKeyspace and SrvVSchema objects must be rebuilt before the restored keyspaces can serve")
+		return nil
+	}
+
+	for _, ks := range archive.Keyspaces {
+		if err := topotools.RebuildKeyspace(ctx, wr.Logger(), ts, ks.Name, nil /* cells */, false /* allowPartial */); err != nil {
+			return fmt.Errorf("RebuildKeyspace(%v): %w", ks.Name, err)
+		}
+	}
+
+	return ts.RebuildSrvVSchema(ctx, nil /* cells */)
+}
+
 // TopologyDecoder interface for exporting out a leaf node in a readable form
 type TopologyDecoder interface {
 	decode(context.Context, []string, topo.Conn, *wrangler.Wrangler, bool) error
@@ -243,3 +355,73 @@ func (d JSONTopologyDecoder) decode(ctx context.Context, topoPaths []string, con
 	}
 	return nil
 }
+
+func commandPromoteTopo(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	maxMirrorLag := subFlags.Duration("max_mirror_lag", time.Minute, "Refuse to promote the topology if it was last synced by the mirror longer ago than this.")
+	force := subFlags.Bool("force", false, "Promote the topology even if it was not synced by a mirror recently, or ever.")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 0 {
+		return fmt.Errorf("PromoteTopo does not take any positional arguments")
+	}
+
+	status, err := wr.TopoServer().GetMirrorStatus(ctx)
+	switch {
+	case err == nil:
+		lag := time.Since(status.LastSync)
+		wr.Logger().Printf("The topology was last synced from %v %v ago\n", status.Source, lag.Round(time.Second))
+		if lag > *maxMirrorLag && !*force {
+			return fmt.Errorf("PromoteTopo: the topology was last synced %v ago, more than --max_mirror_lag, use --force to promote it anyway", lag.Round(time.Second))
+		}
+	case topo.IsErrType(err, topo.NoNode):
+		if !*force {
+			return fmt.Errorf("PromoteTopo: the topology was never synced by a mirror, use --force to promote it anyway")
+		}
+	default:
+		return fmt.Errorf("PromoteTopo: %w", err)
+	}
+
+	promotion, err := wr.TopoServer().Promote(ctx)
+	if err != nil {
+		return fmt.Errorf("PromoteTopo: %w", err)
+	}
+	wr.Logger().Printf("Promoted the topology at %v\n", promotion.PromotedAt)
+	return nil
+}
+
+func commandTopoDoctor(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	repair := subFlags.Bool("repair", false, "Repair the issues that can be repaired.")
+	maxLockAge := subFlags.Duration("max_lock_age", time.Hour, "Report the keyspace and shard locks held for longer than this. Zero disables the check of the locks.")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 0 {
+		return fmt.Errorf("TopoDoctor does not take any positional arguments")
+	}
+
+	issues, err := topotools.TopoDoctor(ctx, wr.TopoServer(), wr.Logger(), topotools.TopoDoctorOptions{
+		Repair:     *repair,
+		MaxLockAge: *maxLockAge,
+	})
+	for _, issue := range issues {
+		wr.Logger().Printf("%v\n", issue)
+	}
+	if err != nil {
+		return fmt.Errorf("TopoDoctor: %w", err)
+	}
+
+	remaining := 0
+	for _, issue := range issues {
+		if !issue.Repaired {
+			remaining++
+		}
+	}
+	if remaining > 0 {
+		return fmt.Errorf("TopoDoctor found %d issues that were not repaired", remaining)
+	}
+	if len(issues) == 0 {
+		wr.Logger().Printf("No issues found\n")
+	}
+	return nil
+}
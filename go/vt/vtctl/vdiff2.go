@@ -67,6 +67,9 @@ func commandVDiff2(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.F
 	verbose := subFlags.Bool("verbose", false, "Show verbose vdiff output in summaries")
 	wait := subFlags.Bool("wait", false, "When creating or resuming a vdiff, wait for it to finish before exiting")
 	waitUpdateInterval := subFlags.Duration("wait-update-interval", time.Duration(1*time.Minute), "When waiting on a vdiff to finish, check and display the current status this often")
+	maxDiffDuration := subFlags.Duration("max-diff-duration", 0, "How long a table is diffed before its diff is restarted from its last checkpoint, which releases the database snapshots held on the source and target tablets (0 means no limit)")
+	parallelism := subFlags.Int64("parallelism", 1, "The number of tables diffed at the same time on each target shard")
+	updateOptions := subFlags.Bool("update-options", false, "When resuming a vdiff, only update its --max-diff-duration and --parallelism, right away if it is running, and keep its other options")
 	updateTableStats := subFlags.Bool("update-table-stats", false, "Update the table statistics, using ANALYZE TABLE, on each table involved in the VDiff during initialization. This will ensure that progress estimates are as accurate as possible -- but it does involve locks and can potentially impact query processing on the target keyspace.")
 
 	if err := subFlags.Parse(args); err != nil {
@@ -95,7 +98,7 @@ func commandVDiff2(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.F
 	if action == "" {
 		return fmt.Errorf("invalid action '%s'; %s", subFlags.Arg(1), usage)
 	}
-	keyspace, workflowName, err := splitKeyspaceWorkflow(subFlags.Arg(0))
+	keyspace, workflowName, err := splitKeyspaceWorkflow(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -103,6 +106,23 @@ func commandVDiff2(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.F
 	if *maxRows <= 0 {
 		return fmt.Errorf("invalid --limit value (%d), maximum number of rows to compare needs to be greater than 0", *maxRows)
 	}
+	if *parallelism <= 0 {
+		return fmt.Errorf("invalid --parallelism value (%d), the number of tables diffed at the same time needs to be greater than 0", *parallelism)
+	}
+	if *maxDiffDuration < 0 {
+		return fmt.Errorf("invalid --max-diff-duration value (%s), it cannot be negative", *maxDiffDuration)
+	}
+	if *updateOptions {
+		if action != vdiff.ResumeAction {
+			return fmt.Errorf("--update-options can only be used with the %s action", vdiff.ResumeAction)
+		}
+		if !subFlags.Changed("max-diff-duration") && !subFlags.Changed("parallelism") {
+			return fmt.Errorf("--update-options needs a --max-diff-duration or a --parallelism to update")
+		}
+		if subFlags.Changed("max-diff-duration") && *maxDiffDuration == 0 {
+			return fmt.Errorf("--update-options cannot remove the limit of the --max-diff-duration, please provide a duration")
+		}
+	}
 
 	options := &tabletmanagerdatapb.VDiffOptions{
 		PickerOptions: &tabletmanagerdatapb.VDiffPickerOptions{
@@ -119,6 +139,8 @@ func commandVDiff2(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.F
 			TimeoutSeconds:        int64(timeout.Seconds()),
 			MaxExtraRowsToCompare: *maxExtraRowsToCompare,
 			UpdateTableStats:      *updateTableStats,
+			MaxDiffSeconds:        int64(maxDiffDuration.Seconds()),
+			Parallelism:           *parallelism,
 		},
 		ReportOptions: &tabletmanagerdatapb.VDiffReportOptions{
 			OnlyPks:    *onlyPks,
@@ -165,7 +187,20 @@ func commandVDiff2(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.F
 		return fmt.Errorf("invalid action '%s'; %s", action, usage)
 	}
 
-	output, err := wr.VDiff2(ctx, keyspace, workflowName, action, actionArg, vdiffUUID.String(), options)
+	var output *wrangler.VDiffOutput
+	if *updateOptions {
+		// Only the options that were provided are updated.
+		update := &tabletmanagerdatapb.VDiffOptions{CoreOptions: &tabletmanagerdatapb.VDiffCoreOptions{}}
+		if subFlags.Changed("max-diff-duration") {
+			update.CoreOptions.MaxDiffSeconds = options.CoreOptions.MaxDiffSeconds
+		}
+		if subFlags.Changed("parallelism") {
+			update.CoreOptions.Parallelism = options.CoreOptions.Parallelism
+		}
+		output, err = wr.UpdateVDiff2Options(ctx, keyspace, workflowName, vdiffUUID.String(), update)
+	} else {
+		output, err = wr.VDiff2(ctx, keyspace, workflowName, action, actionArg, vdiffUUID.String(), options)
+	}
 	if err != nil {
 		log.Errorf("vdiff2 returning with error: %v", err)
 		return err
@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/require"
 	"gotest.tools/assert"
 
@@ -385,6 +387,36 @@ func TestVDiff2Sharded(t *testing.T) {
 	}
 }
 
+func TestVDiff2UpdateOptionsFlags(t *testing.T) {
+	vdiffUUID := "0c4b0f3c-8c2a-11ee-b9d1-0242ac120002"
+	testcases := []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"--update-options", "--parallelism=2", "ks.wf", "create", vdiffUUID},
+		err:  "--update-options can only be used with the resume action",
+	}, {
+		args: []string{"--update-options", "ks.wf", "resume", vdiffUUID},
+		err:  "--update-options needs a --max-diff-duration or a --parallelism to update",
+	}, {
+		args: []string{"--update-options", "--max-diff-duration=0", "ks.wf", "resume", vdiffUUID},
+		err:  "--update-options cannot remove the limit of the --max-diff-duration, please provide a duration",
+	}, {
+		args: []string{"--parallelism=0", "ks.wf", "resume", vdiffUUID},
+		err:  "invalid --parallelism value (0), the number of tables diffed at the same time needs to be greater than 0",
+	}, {
+		args: []string{"--max-diff-duration=-1h", "ks.wf", "create"},
+		err:  "invalid --max-diff-duration value (-1h0m0s), it cannot be negative",
+	}}
+	for _, tc := range testcases {
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			subFlags := pflag.NewFlagSet("VDiff", pflag.ContinueOnError)
+			err := commandVDiff2(context.Background(), nil, subFlags, tc.args)
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
 func TestGetStructNames(t *testing.T) {
 	type s struct {
 		A string
@@ -80,6 +80,7 @@ COMMAND ARGUMENT DEFINITIONS
 */
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -124,6 +125,7 @@ import (
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/topotools"
+	"vitess.io/vitess/go/vt/vtctl/commandtracker"
 	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver"
 	"vitess.io/vitess/go/vt/vtctl/workflow"
 	"vitess.io/vitess/go/vt/vterrors"
@@ -135,7 +137,7 @@ import (
 // ErrUnknownCommand is returned for an unknown command.
 var ErrUnknownCommand = errors.New("unknown command")
 
-const errWorkflowUpdateWithoutChanges = "no updates were provided; use --cells, --tablet-types, or --on-ddl to specify new values"
+const errWorkflowUpdateWithoutChanges = "no updates were provided; use --cells, --tablet-types, --on-ddl, or --max-replication-lag to specify new values"
 
 type command struct {
 	name   string
@@ -192,7 +194,7 @@ var commands = []commandGroup{
 			{
 				name:   "DeleteTablet",
 				method: commandDeleteTablet,
-				params: "[--allow_primary] <tablet alias> ...",
+				params: "[--allow_primary] [--dry_run] <tablet alias> ...",
 				help:   "Deletes tablet(s) from the topology.",
 			},
 			{
@@ -291,7 +293,7 @@ var commands = []commandGroup{
 			{
 				name:   "CreateShard",
 				method: commandCreateShard,
-				params: "[--force] [--parent] <keyspace/shard>",
+				params: "[--force] [--parent] [--if-not-exists] <keyspace/shard>",
 				help:   "Creates the specified shard.",
 			},
 			{
@@ -380,13 +382,13 @@ var commands = []commandGroup{
 			{
 				name:   "RemoveShardCell",
 				method: commandRemoveShardCell,
-				params: "[--force] [--recursive] <keyspace/shard> <cell>",
+				params: "[--force] [--recursive] [--dry_run] <keyspace/shard> <cell>",
 				help:   "Removes the cell from the shard's Cells list.",
 			},
 			{
 				name:   "DeleteShard",
 				method: commandDeleteShard,
-				params: "[--recursive] [--even_if_serving] <keyspace/shard> ...",
+				params: "[--recursive] [--even_if_serving] [--dry_run] <keyspace/shard> ...",
 				help:   "Deletes the specified shard(s). In recursive mode, it also deletes all tablets belonging to the shard. Otherwise, there must be no tablets left in the shard.",
 			},
 		},
@@ -396,19 +398,19 @@ var commands = []commandGroup{
 			{
 				name:   "CreateKeyspace",
 				method: commandCreateKeyspace,
-				params: "[--served_from=tablettype1:ks1,tablettype2:ks2,...] [--force] [--keyspace_type=type] [--base_keyspace=base_keyspace] [--snapshot_time=time] [--durability-policy=policy_name] [--sidecar-db-name=db_name] <keyspace name>",
+				params: "[--served_from=tablettype1:ks1,tablettype2:ks2,...] [--force] [--if-not-exists] [--keyspace_type=type] [--base_keyspace=base_keyspace] [--snapshot_time=time] [--durability-policy=policy_name] [--sidecar-db-name=db_name] <keyspace name>",
 				help:   "Creates the specified keyspace. keyspace_type can be NORMAL or SNAPSHOT. For a SNAPSHOT keyspace you must specify the name of a base_keyspace, and a snapshot_time in UTC, in RFC3339 time format, e.g. 2006-01-02T15:04:05+00:00",
 			},
 			{
 				name:   "DeleteKeyspace",
 				method: commandDeleteKeyspace,
-				params: "[--recursive] <keyspace>",
+				params: "[--recursive] [--dry_run] <keyspace>",
 				help:   "Deletes the specified keyspace. In recursive mode, it also recursively deletes all shards in the keyspace. Otherwise, there must be no shards left in the keyspace.",
 			},
 			{
 				name:   "RemoveKeyspaceCell",
 				method: commandRemoveKeyspaceCell,
-				params: "[--force] [--recursive] <keyspace> <cell>",
+				params: "[--force] [--recursive] [--dry_run] <keyspace> <cell>",
 				help:   "Removes the cell from the Cells list for all shards in the keyspace, and the SrvKeyspace for that keyspace in that cell.",
 			},
 			{
@@ -435,18 +437,42 @@ var commands = []commandGroup{
 				params: "[--ping-tablets] <keyspace name>",
 				help:   "Validates that all nodes reachable from the specified keyspace are consistent.",
 			},
+			{
+				name:   "GetRecoveryPolicy",
+				method: commandGetRecoveryPolicy,
+				params: "<keyspace|keyspace/shard>",
+				help:   "Outputs the VTOrc recovery policy of the keyspace or of the shard. The policy of a shard is output as stored, without the fields it inherits from the policy of its keyspace.",
+			},
+			{
+				name:   "SetRecoveryPolicy",
+				method: commandSetRecoveryPolicy,
+				params: "{--policy=<policy> || --policy_file=<policy_file> || --clear} <keyspace|keyspace/shard>",
+				help:   "Sets the VTOrc recovery policy of the keyspace or of the shard, as JSON with the fields allowed_recoveries, promotion_rules, promotion_cells, preferred_cells and cooldown_seconds. The fields a shard policy doesn't set are inherited from the keyspace policy. VTOrc picks up the change without a restart.",
+			},
 			{
 				name:   "Reshard",
 				method: commandReshard,
 				params: "[--source_shards=<source_shards>] [--target_shards=<target_shards>] [--cells=<cells>] [--tablet_types=<source_tablet_types>] [--on-ddl=<ddl-action>] [--defer-secondary-keys] [--skip_schema_copy] <action> 'action must be one of the following: Create, Complete, Cancel, SwitchTraffic, ReverseTrafffic, Show, or Progress' <keyspace.workflow>",
 				help:   "Start a Resharding process.",
 			},
+			{
+				name:   "ReshardPlan",
+				method: commandReshardPlan,
+				params: "[--source-shards=<source_shards>] [--target-shard-count=<count>] [--sample-size=<rows>] [--balance=data|qps] <keyspace>",
+				help:   "Recommend the target shards of a Reshard: sample the keyspace ids of the rows of the source shards, and split them into target shards of even data or queries per second. The recommended shards are printed with the Reshard command creating them.",
+			},
 			{
 				name:   "MoveTables",
 				method: commandMoveTables,
-				params: "[--source=<sourceKs>] [--tables=<tableSpecs>] [--cells=<cells>] [--tablet_types=<source_tablet_types>] [--all] [--exclude=<tables>] [--auto_start] [--stop_after_copy] [--defer-secondary-keys] [--on-ddl=<ddl-action>] [--source_shards=<source_shards>] [--source_time_zone=<mysql_time_zone>] [--initialize-target-sequences] <action> 'action must be one of the following: Create, Complete, Cancel, SwitchTraffic, ReverseTrafffic, Show, or Progress' <targetKs.workflow>",
+				params: "[--source=<sourceKs>] [--tables=<tableSpecs>] [--cells=<cells>] [--tablet_types=<source_tablet_types>] [--all] [--exclude=<tables>] [--auto_start] [--stop_after_copy] [--defer-secondary-keys] [--on-ddl=<ddl-action>] [--source_shards=<source_shards>] [--source_time_zone=<mysql_time_zone>] [--initialize-target-sequences] [--auto-sequences] <action> 'action must be one of the following: Create, Complete, Cancel, SwitchTraffic, ReverseTrafffic, Show, or Progress' <targetKs.workflow>",
 				help:   `Move table(s) to another keyspace, table_specs is a list of tables or the tables section of the vschema for the target keyspace. Example: '{"t1":{"column_vindexes": [{"column": "id1", "name": "hash"}]}, "t2":{"column_vindexes": [{"column": "id2", "name": "hash"}]}}'.  In the case of an unsharded target keyspace the vschema for each table may be empty. Example: '{"t1":{}, "t2":{}}'.`,
 			},
+			{
+				name:   "DelayedReplica",
+				method: commandDelayedReplica,
+				params: "[--source=<sourceKs>] [--tables=<tables>] [--delay=<duration>] [--cells=<cells>] [--tablet_types=<source_tablet_types>] [--position=<position>] [--timestamp=<RFC3339 timestamp>] <action> 'action must be one of the following: Create, FastForward' <targetKs.workflow>",
+				help:   "Create a copy of tables of the source keyspace in the target keyspace, to which the changes of the source keyspace are applied with a delay, or fast-forward it without delay up to a position or timestamp. The target keyspace keeps the tables as they were before a mistaken write for the duration of the delay.",
+			},
 			{
 				name:   "Migrate",
 				method: commandMigrate,
@@ -462,8 +488,14 @@ var commands = []commandGroup{
 			{
 				name:   "ExternalizeVindex",
 				method: commandExternalizeVindex,
-				params: "<keyspace>.<vindex>",
-				help:   `Externalize a backfilled vindex.`,
+				params: "[--verify-sample-size=<rows>] <keyspace>.<vindex>",
+				help:   `Externalize a backfilled vindex, after verifying a sample of its rows with VerifyLookupVindex.`,
+			},
+			{
+				name:   "VerifyLookupVindex",
+				method: commandVerifyLookupVindex,
+				params: "[--sample-size=<rows>] <keyspace>.<vindex>",
+				help:   `Verify a backfilled lookup vindex: sample rows of its table on each source shard, and check that the lookup table has their rows. The mismatches are reported.`,
 			},
 			{
 				name:   "Materialize",
@@ -474,7 +506,7 @@ var commands = []commandGroup{
 			{
 				name:   "VDiff",
 				method: commandVDiff,
-				params: "[--source_cell=<cell>] [--target_cell=<cell>] [--tablet_types=in_order:RDONLY,REPLICA,PRIMARY] [--limit=<max rows to diff>] [--tables=<table list>] [--format=json] [--auto-retry] [--verbose] [--max_extra_rows_to_compare=1000] [--filtered_replication_wait_time=30s] [--debug_query] [--only_pks] [--wait] [--wait-update-interval=1m] <keyspace.workflow> [<action>] [<UUID>]",
+				params: "[--source_cell=<cell>] [--target_cell=<cell>] [--tablet_types=in_order:RDONLY,REPLICA,PRIMARY] [--limit=<max rows to diff>] [--tables=<table list>] [--format=json] [--auto-retry] [--verbose] [--max_extra_rows_to_compare=1000] [--filtered_replication_wait_time=30s] [--debug_query] [--only_pks] [--wait] [--wait-update-interval=1m] [--max-diff-duration=0] [--parallelism=1] [--update-options] <keyspace.workflow> [<action>] [<UUID>]",
 				help:   "Perform a diff of all tables in the workflow",
 			},
 			{
@@ -486,7 +518,7 @@ var commands = []commandGroup{
 			{
 				name:   "Mount",
 				method: commandMount,
-				params: "[--topo_type=etcd2|consul|zookeeper] [--topo_server=topo_url] [--topo_root=root_topo_node> [--unmount] [--list] [--show]  [<cluster_name>]",
+				params: "[--topo_type=etcd2|consul|zookeeper] [--topo_server=topo_url] [--topo_root=root_topo_node> [--vtgate_address=vtgate_host:grpc_port] [--unmount] [--list] [--show]  [<cluster_name>]",
 				help:   "Add/Remove/Display/List external cluster(s) to this vitess cluster",
 			},
 		},
@@ -585,8 +617,8 @@ var commands = []commandGroup{
 			{
 				name:   "ValidateSchemaKeyspace",
 				method: commandValidateSchemaKeyspace,
-				params: "[--exclude_tables=''] [--include-views] [--skip-no-primary] [--include-vschema] <keyspace name>",
-				help:   "Validates that the schema on the primary tablet for shard 0 matches the schema on all of the other tablets in the keyspace.",
+				params: "[--exclude_tables=''] [--include-views] [--skip-no-primary] [--include-vschema] [--concurrency=0] <keyspace name>",
+				help:   "Validates that the schema on the primary tablet for shard 0 matches the schema on all of the other tablets in the keyspace. The outcome of each tablet is logged as soon as it is validated.",
 			},
 			{
 				name:   "ApplySchema",
@@ -654,8 +686,8 @@ var commands = []commandGroup{
 			{
 				name:   "ValidatePermissionsKeyspace",
 				method: commandValidatePermissionsKeyspace,
-				params: "<keyspace name>",
-				help:   "Validates that the permissions on primary of shard 0 match those of all of the other tablets in the keyspace.",
+				params: "[--concurrency=0] <keyspace name>",
+				help:   "Validates that the permissions on primary of shard 0 match those of all of the other tablets in the keyspace. The outcome of each tablet is logged as soon as it is validated.",
 			},
 			{
 				name:   "GetVSchema",
@@ -738,7 +770,7 @@ var commands = []commandGroup{
 			{
 				name:   "Workflow",
 				method: commandWorkflow,
-				params: "[--dry-run] [--cells] [--tablet-types] <keyspace>[.<workflow>] start/stop/update/delete/show/listall/tags [<tags>]",
+				params: "[--dry-run] [--cells] [--tablet-types] [--on-ddl] [--max-replication-lag] <keyspace>[.<workflow>] start/stop/update/delete/show/listall/tags [<tags>]",
 				help:   "Start/Stop/Update/Delete/Show/ListAll/Tags Workflow on all target tablets in workflow. Example: Workflow merchant.morders Start",
 			},
 		},
@@ -846,6 +878,9 @@ func keyspaceParamsToKeyspaces(ctx context.Context, wr *wrangler.Wrangler, param
 			if err != nil {
 				return nil, fmt.Errorf("failed to resolve keyspace wildcard %v: %v", param, err)
 			}
+			for _, keyspace := range keyspaces {
+				reportKeyspace(ctx, keyspace)
+			}
 			result = append(result, keyspaces...)
 		}
 	}
@@ -863,7 +898,7 @@ func shardParamsToKeyspaceShards(ctx context.Context, wr *wrangler.Wrangler, par
 		if param[0] == '/' {
 			// this is a topology-specific path
 			for _, path := range params {
-				keyspace, shard, err := topoproto.ParseKeyspaceShard(path)
+				keyspace, shard, err := parseKeyspaceShard(ctx, path)
 				if err != nil {
 					return nil, err
 				}
@@ -876,6 +911,9 @@ func shardParamsToKeyspaceShards(ctx context.Context, wr *wrangler.Wrangler, par
 			if err != nil {
 				return nil, fmt.Errorf("failed to resolve keyspace/shard wildcard %v: %v", param, err)
 			}
+			for _, ks := range keyspaceShards {
+				reportShard(ctx, ks.Keyspace, ks.Shard)
+			}
 			result = append(result, keyspaceShards...)
 		}
 	}
@@ -884,11 +922,11 @@ func shardParamsToKeyspaceShards(ctx context.Context, wr *wrangler.Wrangler, par
 
 // tabletParamsToTabletAliases takes multiple params and converts them
 // to tablet aliases.
-func tabletParamsToTabletAliases(params []string) ([]*topodatapb.TabletAlias, error) {
+func tabletParamsToTabletAliases(ctx context.Context, params []string) ([]*topodatapb.TabletAlias, error) {
 	result := make([]*topodatapb.TabletAlias, len(params))
 	var err error
 	for i, param := range params {
-		result[i], err = topoproto.ParseTabletAlias(param)
+		result[i], err = parseTabletAlias(ctx, param)
 		if err != nil {
 			return nil, err
 		}
@@ -931,7 +969,7 @@ func commandInitTablet(ctx context.Context, wr *wrangler.Wrangler, subFlags *pfl
 	if subFlags.NArg() != 2 {
 		return fmt.Errorf("the <tablet alias> and <tablet type> arguments are both required for the InitTablet command")
 	}
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := parseTabletAlias(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -973,7 +1011,7 @@ func commandGetTablet(ctx context.Context, wr *wrangler.Wrangler, subFlags *pfla
 		return fmt.Errorf("the <tablet alias> argument is required for the GetTablet command")
 	}
 
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := parseTabletAlias(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -999,7 +1037,7 @@ func commandUpdateTabletAddrs(ctx context.Context, wr *wrangler.Wrangler, subFla
 		return fmt.Errorf("the <tablet alias> argument is required for the UpdateTabletAddrs command")
 	}
 
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := parseTabletAlias(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1032,6 +1070,7 @@ func commandUpdateTabletAddrs(ctx context.Context, wr *wrangler.Wrangler, subFla
 
 func commandDeleteTablet(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
 	allowPrimary := subFlags.Bool("allow_primary", false, "Allows for the primary tablet of a shard to be deleted. Use with caution.")
+	dryRun := subFlags.Bool("dry_run", false, "Prints the topo mutations that would be performed, without actually deleting anything.")
 
 	if err := subFlags.Parse(args); err != nil {
 		return err
@@ -1040,10 +1079,22 @@ func commandDeleteTablet(ctx context.Context, wr *wrangler.Wrangler, subFlags *p
 		return fmt.Errorf("the <tablet alias> argument must be used to specify at least one tablet when calling the DeleteTablet command")
 	}
 
-	tabletAliases, err := tabletParamsToTabletAliases(subFlags.Args())
+	tabletAliases, err := tabletParamsToTabletAliases(ctx, subFlags.Args())
 	if err != nil {
 		return err
 	}
+	if *dryRun {
+		resp, err := wr.VtctldServer().DeleteTablets(ctx, &vtctldatapb.DeleteTabletsRequest{
+			TabletAliases: tabletAliases,
+			AllowPrimary:  *allowPrimary,
+			DryRun:        true,
+		})
+		if err != nil {
+			return err
+		}
+		printDryRunResults(wr.Logger(), "DeleteTablet", resp.DryRunResults)
+		return nil
+	}
 	for _, tabletAlias := range tabletAliases {
 		if err := wr.DeleteTablet(ctx, tabletAlias, *allowPrimary); err != nil {
 			return err
@@ -1060,7 +1111,7 @@ func commandSetReadOnly(ctx context.Context, wr *wrangler.Wrangler, subFlags *pf
 		return fmt.Errorf("the <tablet alias> argument is required for the SetReadOnly command")
 	}
 
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := parseTabletAlias(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1079,7 +1130,7 @@ func commandSetReadWrite(ctx context.Context, wr *wrangler.Wrangler, subFlags *p
 		return fmt.Errorf("the <tablet alias> argument is required for the SetReadWrite command")
 	}
 
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := parseTabletAlias(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1098,7 +1149,7 @@ func commandStartReplication(ctx context.Context, wr *wrangler.Wrangler, subFlag
 		return fmt.Errorf("action StartReplication requires <tablet alias>")
 	}
 
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := parseTabletAlias(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1117,7 +1168,7 @@ func commandStopReplication(ctx context.Context, wr *wrangler.Wrangler, subFlags
 		return fmt.Errorf("action StopReplication requires <tablet alias>")
 	}
 
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := parseTabletAlias(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1138,7 +1189,7 @@ func commandChangeTabletType(ctx context.Context, wr *wrangler.Wrangler, subFlag
 		return fmt.Errorf("the <tablet alias> and <db type> arguments are required for the ChangeTabletType command")
 	}
 
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := parseTabletAlias(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1173,7 +1224,7 @@ func commandPing(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.Fla
 	if subFlags.NArg() != 1 {
 		return fmt.Errorf("the <tablet alias> argument is required for the Ping command")
 	}
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := parseTabletAlias(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1190,7 +1241,7 @@ func commandRefreshState(ctx context.Context, wr *wrangler.Wrangler, subFlags *p
 	if subFlags.NArg() != 1 {
 		return fmt.Errorf("the <tablet alias> argument is required for the RefreshState command")
 	}
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := parseTabletAlias(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1210,7 +1261,7 @@ func commandRefreshStateByShard(ctx context.Context, wr *wrangler.Wrangler, subF
 		return fmt.Errorf("the <keyspace/shard> argument is required for the RefreshStateByShard command")
 	}
 
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1235,7 +1286,7 @@ func commandRunHealthCheck(ctx context.Context, wr *wrangler.Wrangler, subFlags
 	if subFlags.NArg() != 1 {
 		return fmt.Errorf("the <tablet alias> argument is required for the RunHealthCheck command")
 	}
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := parseTabletAlias(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1253,7 +1304,7 @@ func commandSleep(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.Fl
 	if subFlags.NArg() != 2 {
 		return fmt.Errorf("the <tablet alias> and <duration> arguments are required for the Sleep command")
 	}
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := parseTabletAlias(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1281,7 +1332,7 @@ func commandExecuteFetchAsApp(ctx context.Context, wr *wrangler.Wrangler, subFla
 		return fmt.Errorf("the <tablet alias> and <sql command> arguments are required for the ExecuteFetchAsApp command")
 	}
 
-	alias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	alias, err := parseTabletAlias(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1311,7 +1362,7 @@ func commandExecuteFetchAsDba(ctx context.Context, wr *wrangler.Wrangler, subFla
 		return fmt.Errorf("the <tablet alias> and <sql command> arguments are required for the ExecuteFetchAsDba command")
 	}
 
-	alias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	alias, err := parseTabletAlias(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1340,7 +1391,7 @@ func commandVReplicationExec(ctx context.Context, wr *wrangler.Wrangler, subFlag
 		return fmt.Errorf("the <tablet alias> and <sql command> arguments are required for the VReplicationExec command")
 	}
 
-	alias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	alias, err := parseTabletAlias(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1367,7 +1418,7 @@ func commandExecuteHook(ctx context.Context, wr *wrangler.Wrangler, subFlags *pf
 		return fmt.Errorf("the <tablet alias> and <hook name> arguments are required for the ExecuteHook command")
 	}
 
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := parseTabletAlias(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1394,6 +1445,7 @@ func commandExecuteHook(ctx context.Context, wr *wrangler.Wrangler, subFlags *pf
 func commandCreateShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
 	force := subFlags.Bool("force", false, "Proceeds with the command even if the shard already exists")
 	parent := subFlags.Bool("parent", false, "Creates the parent keyspace if it doesn't already exist")
+	ifNotExists := subFlags.Bool("if-not-exists", false, "Succeeds without making any changes if the shard already exists")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -1401,7 +1453,7 @@ func commandCreateShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *pf
 		return fmt.Errorf("the <keyspace/shard> argument is required for the CreateShard command")
 	}
 
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1412,7 +1464,11 @@ func commandCreateShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *pf
 	}
 
 	err = wr.TopoServer().CreateShard(ctx, keyspace, shard)
-	if *force && topo.IsErrType(err, topo.NodeExists) {
+	switch {
+	case *ifNotExists && topo.IsErrType(err, topo.NodeExists):
+		wr.Logger().Printf("Shard %v/%v already exists, unchanged\n", keyspace, shard)
+		err = nil
+	case *force && topo.IsErrType(err, topo.NodeExists):
 		wr.Logger().Infof("shard %v/%v already exists (ignoring error with --force)", keyspace, shard)
 		err = nil
 	}
@@ -1427,7 +1483,7 @@ func commandGetShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag
 		return fmt.Errorf("the <keyspace/shard> argument is required for the GetShard command")
 	}
 
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1448,7 +1504,7 @@ func commandValidateShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *
 		return fmt.Errorf("the <keyspace/shard> argument is required for the ValidateShard command")
 	}
 
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1462,7 +1518,7 @@ func commandShardReplicationPositions(ctx context.Context, wr *wrangler.Wrangler
 	if subFlags.NArg() != 1 {
 		return fmt.Errorf("the <keyspace/shard> argument is required for the ShardReplicationPositions command")
 	}
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1498,7 +1554,7 @@ func commandListShardTablets(ctx context.Context, wr *wrangler.Wrangler, subFlag
 	if subFlags.NArg() != 1 {
 		return fmt.Errorf("the <keyspace/shard> argument is required for the ListShardTablets command")
 	}
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1526,7 +1582,7 @@ func commandSetShardIsPrimaryServing(ctx context.Context, wr *wrangler.Wrangler,
 	if subFlags.NArg() != 2 {
 		return fmt.Errorf("the <keyspace/shard> <is_serving> arguments are both required for the SetShardIsPrimaryServing command")
 	}
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1554,7 +1610,7 @@ func commandUpdateSrvKeyspacePartition(ctx context.Context, wr *wrangler.Wrangle
 	if subFlags.NArg() != 2 {
 		return fmt.Errorf("the <keyspace/shard> and <tablet type> arguments are both required for the UpdateSrvKeyspacePartition command")
 	}
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1587,7 +1643,7 @@ func commandSetShardTabletControl(ctx context.Context, wr *wrangler.Wrangler, su
 	if subFlags.NArg() != 2 {
 		return fmt.Errorf("the <keyspace/shard> and <tablet type> arguments are both required for the SetShardTabletControl command")
 	}
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1624,7 +1680,7 @@ func commandSourceShardDelete(ctx context.Context, wr *wrangler.Wrangler, subFla
 	if subFlags.NArg() < 2 {
 		return fmt.Errorf("the <keyspace/shard> and <uid> arguments are both required for the SourceShardDelete command")
 	}
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1644,7 +1700,7 @@ func commandSourceShardAdd(ctx context.Context, wr *wrangler.Wrangler, subFlags
 	if subFlags.NArg() != 3 {
 		return fmt.Errorf("the <keyspace/shard>, <uid>, and <source keyspace/shard> arguments are all required for the SourceShardAdd command")
 	}
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1652,7 +1708,7 @@ func commandSourceShardAdd(ctx context.Context, wr *wrangler.Wrangler, subFlags
 	if err != nil {
 		return err
 	}
-	skeyspace, sshard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(2))
+	skeyspace, sshard, err := parseKeyspaceShard(ctx, subFlags.Arg(2))
 	if err != nil {
 		return err
 	}
@@ -1677,11 +1733,11 @@ func commandShardReplicationAdd(ctx context.Context, wr *wrangler.Wrangler, subF
 		return fmt.Errorf("the <keyspace/shard> and <tablet alias> arguments are required for the ShardReplicationAdd command")
 	}
 
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(1))
+	tabletAlias, err := parseTabletAlias(ctx, subFlags.Arg(1))
 	if err != nil {
 		return err
 	}
@@ -1702,11 +1758,11 @@ func commandShardReplicationRemove(ctx context.Context, wr *wrangler.Wrangler, s
 		return fmt.Errorf("the <keyspace/shard> and <tablet alias> arguments are required for the ShardReplicationRemove command")
 	}
 
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(1))
+	tabletAlias, err := parseTabletAlias(ctx, subFlags.Arg(1))
 	if err != nil {
 		return err
 	}
@@ -1728,7 +1784,7 @@ func commandShardReplicationFix(ctx context.Context, wr *wrangler.Wrangler, subF
 	}
 
 	cell := subFlags.Arg(0)
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(1))
+	keyspace, shard, err := parseKeyspaceShard(ctx, subFlags.Arg(1))
 	if err != nil {
 		return err
 	}
@@ -1748,7 +1804,7 @@ func commandWaitForFilteredReplication(ctx context.Context, wr *wrangler.Wrangle
 	if subFlags.NArg() != 1 {
 		return fmt.Errorf("the <keyspace/shard> argument is required for the WaitForFilteredReplication command")
 	}
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -1758,6 +1814,7 @@ func commandWaitForFilteredReplication(ctx context.Context, wr *wrangler.Wrangle
 func commandRemoveShardCell(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
 	force := subFlags.Bool("force", false, "Proceeds even if the cell's topology server cannot be reached. The assumption is that you turned down the entire cell, and just need to update the global topo data.")
 	recursive := subFlags.Bool("recursive", false, "Also delete all tablets in that cell belonging to the specified shard.")
+	dryRun := subFlags.Bool("dry_run", false, "Prints the topo mutations that would be performed, without actually removing anything.")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -1765,26 +1822,34 @@ func commandRemoveShardCell(ctx context.Context, wr *wrangler.Wrangler, subFlags
 		return fmt.Errorf("the <keyspace/shard> and <cell> arguments are required for the RemoveShardCell command")
 	}
 
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
 
 	cell := subFlags.Arg(1)
 
-	_, err = wr.VtctldServer().RemoveShardCell(ctx, &vtctldatapb.RemoveShardCellRequest{
+	resp, err := wr.VtctldServer().RemoveShardCell(ctx, &vtctldatapb.RemoveShardCellRequest{
 		Keyspace:  keyspace,
 		ShardName: shard,
 		Cell:      cell,
 		Force:     *force,
 		Recursive: *recursive,
+		DryRun:    *dryRun,
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		printDryRunResults(wr.Logger(), "RemoveShardCell", resp.DryRunResults)
+	}
+	return nil
 }
 
 func commandDeleteShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
 	recursive := subFlags.Bool("recursive", false, "Also delete all tablets belonging to the shard.")
 	evenIfServing := subFlags.Bool("even_if_serving", false, "Remove the shard even if it is serving. Use with caution.")
+	dryRun := subFlags.Bool("dry_run", false, "Prints the topo mutations that would be performed, without actually deleting anything.")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -1796,6 +1861,23 @@ func commandDeleteShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *pf
 	if err != nil {
 		return err
 	}
+	if *dryRun {
+		shards := make([]*vtctldatapb.Shard, 0, len(keyspaceShards))
+		for _, ks := range keyspaceShards {
+			shards = append(shards, &vtctldatapb.Shard{Keyspace: ks.Keyspace, Name: ks.Shard})
+		}
+		resp, err := wr.VtctldServer().DeleteShards(ctx, &vtctldatapb.DeleteShardsRequest{
+			Shards:        shards,
+			Recursive:     *recursive,
+			EvenIfServing: *evenIfServing,
+			DryRun:        true,
+		})
+		if err != nil {
+			return err
+		}
+		printDryRunResults(wr.Logger(), "DeleteShard", resp.DryRunResults)
+		return nil
+	}
 	for _, ks := range keyspaceShards {
 		err := wr.DeleteShard(ctx, ks.Keyspace, ks.Shard, *recursive, *evenIfServing)
 		switch {
@@ -1812,6 +1894,7 @@ func commandDeleteShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *pf
 
 func commandCreateKeyspace(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
 	force := subFlags.Bool("force", false, "Proceeds even if the keyspace already exists")
+	ifNotExists := subFlags.Bool("if-not-exists", false, "Succeeds without making any changes if the keyspace already exists")
 	allowEmptyVSchema := subFlags.Bool("allow_empty_vschema", false, "If set this will allow a new keyspace to have no vschema")
 
 	var servedFrom flagutil.StringMapValue
@@ -1829,6 +1912,7 @@ func commandCreateKeyspace(ctx context.Context, wr *wrangler.Wrangler, subFlags
 	}
 
 	keyspace := subFlags.Arg(0)
+	reportKeyspace(ctx, keyspace)
 	ktype := topodatapb.KeyspaceType_NORMAL
 	if *keyspaceType != "" {
 		kt, err := topoproto.ParseKeyspaceType(*keyspaceType)
@@ -1883,6 +1967,10 @@ func commandCreateKeyspace(ctx context.Context, wr *wrangler.Wrangler, subFlags
 		}
 	}
 	err := wr.TopoServer().CreateKeyspace(ctx, keyspace, ki)
+	if *ifNotExists && topo.IsErrType(err, topo.NodeExists) {
+		wr.Logger().Printf("Keyspace %v already exists, unchanged\n", keyspace)
+		return nil
+	}
 	if *force && topo.IsErrType(err, topo.NodeExists) {
 		wr.Logger().Infof("keyspace %v already exists (ignoring error with --force)", keyspace)
 		err = nil
@@ -1927,6 +2015,7 @@ func commandCreateKeyspace(ctx context.Context, wr *wrangler.Wrangler, subFlags
 
 func commandDeleteKeyspace(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
 	recursive := subFlags.Bool("recursive", false, "Also recursively delete all shards in the keyspace.")
+	dryRun := subFlags.Bool("dry_run", false, "Prints the topo mutations that would be performed, without actually deleting anything.")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -1934,16 +2023,24 @@ func commandDeleteKeyspace(ctx context.Context, wr *wrangler.Wrangler, subFlags
 		return fmt.Errorf("must specify the <keyspace> argument for DeleteKeyspace")
 	}
 
-	_, err := wr.VtctldServer().DeleteKeyspace(ctx, &vtctldatapb.DeleteKeyspaceRequest{
+	resp, err := wr.VtctldServer().DeleteKeyspace(ctx, &vtctldatapb.DeleteKeyspaceRequest{
 		Keyspace:  subFlags.Arg(0),
 		Recursive: *recursive,
+		DryRun:    *dryRun,
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		printDryRunResults(wr.Logger(), "DeleteKeyspace", resp.DryRunResults)
+	}
+	return nil
 }
 
 func commandRemoveKeyspaceCell(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
 	force := subFlags.Bool("force", false, "Proceeds even if the cell's topology server cannot be reached. The assumption is that you turned down the entire cell, and just need to update the global topo data.")
 	recursive := subFlags.Bool("recursive", false, "Also delete all tablets in that cell belonging to the specified keyspace.")
+	dryRun := subFlags.Bool("dry_run", false, "Prints the topo mutations that would be performed, without actually removing anything.")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -1952,15 +2049,23 @@ func commandRemoveKeyspaceCell(ctx context.Context, wr *wrangler.Wrangler, subFl
 	}
 
 	keyspace := subFlags.Arg(0)
+	reportKeyspace(ctx, keyspace)
 	cell := subFlags.Arg(1)
 
-	_, err := wr.VtctldServer().RemoveKeyspaceCell(ctx, &vtctldatapb.RemoveKeyspaceCellRequest{
+	resp, err := wr.VtctldServer().RemoveKeyspaceCell(ctx, &vtctldatapb.RemoveKeyspaceCellRequest{
 		Keyspace:  keyspace,
 		Cell:      cell,
 		Force:     *force,
 		Recursive: *recursive,
+		DryRun:    *dryRun,
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		printDryRunResults(wr.Logger(), "RemoveKeyspaceCell", resp.DryRunResults)
+	}
+	return nil
 }
 
 func commandGetKeyspace(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
@@ -1972,6 +2077,7 @@ func commandGetKeyspace(ctx context.Context, wr *wrangler.Wrangler, subFlags *pf
 	}
 
 	keyspace := subFlags.Arg(0)
+	reportKeyspace(ctx, keyspace)
 
 	keyspaceInfo, err := wr.VtctldServer().GetKeyspace(ctx, &vtctldatapb.GetKeyspaceRequest{
 		Keyspace: keyspace,
@@ -2040,17 +2146,155 @@ func commandValidateKeyspace(ctx context.Context, wr *wrangler.Wrangler, subFlag
 	}
 
 	keyspace := subFlags.Arg(0)
+	reportKeyspace(ctx, keyspace)
 	return wr.ValidateKeyspace(ctx, keyspace, *pingTablets)
 }
 
+func commandGetRecoveryPolicy(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <keyspace|keyspace/shard> argument is required for the GetRecoveryPolicy command")
+	}
+	keyspace, shard, err := parseRecoveryPolicyTarget(ctx, wr, subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	policy, err := wr.TopoServer().GetRecoveryPolicy(ctx, keyspace, shard)
+	if err != nil {
+		return err
+	}
+	return printJSON(wr.Logger(), policy)
+}
+
+func commandSetRecoveryPolicy(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	policyJSON := subFlags.String("policy", "", "Specify the policy as a string")
+	policyFile := subFlags.String("policy_file", "", "Specify the policy in a file")
+	clearPolicy := subFlags.Bool("clear", false, "Delete the policy")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <keyspace|keyspace/shard> argument is required for the SetRecoveryPolicy command")
+	}
+	keyspace, shard, err := parseRecoveryPolicyTarget(ctx, wr, subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if *clearPolicy {
+		if *policyJSON != "" || *policyFile != "" {
+			return fmt.Errorf("--clear cannot be used with --policy or --policy_file")
+		}
+		return wr.TopoServer().DeleteRecoveryPolicy(ctx, keyspace, shard)
+	}
+
+	var policyBytes []byte
+	switch {
+	case *policyJSON != "" && *policyFile != "":
+		return fmt.Errorf("only one of --policy and --policy_file can be specified")
+	case *policyFile != "":
+		policyBytes, err = os.ReadFile(*policyFile)
+		if err != nil {
+			return err
+		}
+	case *policyJSON != "":
+		policyBytes = []byte(*policyJSON)
+	default:
+		return fmt.Errorf("one of --policy, --policy_file or --clear is required")
+	}
+
+	policy := &topo.RecoveryPolicy{}
+	decoder := json.NewDecoder(bytes.NewReader(policyBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(policy); err != nil {
+		return fmt.Errorf("cannot parse the recovery policy: %v", err)
+	}
+	return wr.TopoServer().SaveRecoveryPolicy(ctx, keyspace, shard, policy)
+}
+
+// parseRecoveryPolicyTarget parses the keyspace or the keyspace/shard of a
+// recovery policy, and checks that it exists.
+func parseRecoveryPolicyTarget(ctx context.Context, wr *wrangler.Wrangler, arg string) (keyspace, shard string, err error) {
+	if !strings.Contains(arg, "/") {
+		_, err = wr.TopoServer().GetKeyspace(ctx, arg)
+		return arg, "", err
+	}
+	keyspace, shard, err = parseKeyspaceShard(ctx, arg)
+	if err != nil {
+		return "", "", err
+	}
+	_, err = wr.TopoServer().GetShard(ctx, keyspace, shard)
+	return keyspace, shard, err
+}
+
 func commandReshard(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
 	return commandVReplicationWorkflow(ctx, wr, subFlags, args, wrangler.ReshardWorkflow)
 }
 
+func commandReshardPlan(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	sourceShards := subFlags.StringSlice("source-shards", nil, "Source shards to reshard, all the serving shards of the keyspace by default.")
+	targetShardCount := subFlags.Int("target-shard-count", 0, "Number of target shards, twice the number of source shards by default.")
+	sampleSize := subFlags.Int("sample-size", wrangler.DefaultReshardPlanSampleSize, "Number of rows sampled from each table of each source shard.")
+	balance := subFlags.String("balance", wrangler.ReshardPlanBalanceData, "What the target shards are balanced by: data, the size of the tables, or qps, the queries per second of the source shards, assuming they are spread evenly over their data.")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("one argument is required: keyspace")
+	}
+	plan, err := wr.PlanReshard(ctx, subFlags.Arg(0), *sourceShards, *targetShardCount, *sampleSize, *balance)
+	if err != nil {
+		return err
+	}
+	wr.Logger().Printf("%s\n", plan)
+	return nil
+}
+
 func commandMoveTables(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
 	return commandVReplicationWorkflow(ctx, wr, subFlags, args, wrangler.MoveTablesWorkflow)
 }
 
+func commandDelayedReplica(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	sourceKeyspace := subFlags.String("source", "", "Source keyspace.")
+	tables := subFlags.StringSlice("tables", nil, "Tables to replicate, all the tables of the source keyspace by default.")
+	delay := subFlags.Duration("delay", time.Hour, "Delay with which the changes of the source keyspace are applied, at least one second.")
+	cells := subFlags.String("cells", "", "Cell(s) or CellAlias(es) (comma-separated) to replicate from.")
+	tabletTypes := subFlags.String("tablet_types", "in_order:REPLICA,PRIMARY", "Source tablet types to replicate from (e.g. PRIMARY, REPLICA, RDONLY). Defaults to in_order:REPLICA,PRIMARY.")
+	position := subFlags.String("position", "", "FastForward: position of the source up to which the changes are applied without delay.")
+	timestamp := subFlags.String("timestamp", "", "FastForward: time, in RFC3339 format, up to which the changes of the source are applied without delay.")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 2 {
+		return fmt.Errorf("two arguments are required: action and keyspace.workflow")
+	}
+	action := subFlags.Arg(0)
+	keyspace, workflow, err := splitKeyspaceWorkflow(ctx, subFlags.Arg(1))
+	if err != nil {
+		return err
+	}
+	switch action {
+	case "Create":
+		if *sourceKeyspace == "" {
+			return fmt.Errorf("source keyspace is not specified")
+		}
+		return wr.CreateDelayedReplica(ctx, workflow, *sourceKeyspace, keyspace, *tables, *delay, *cells, *tabletTypes)
+	case "FastForward":
+		var ts time.Time
+		if *timestamp != "" {
+			if ts, err = time.Parse(time.RFC3339, *timestamp); err != nil {
+				return err
+			}
+		}
+		return wr.FastForwardDelayedReplica(ctx, keyspace, workflow, *position, ts)
+	default:
+		return fmt.Errorf("found unsupported action %s", action)
+	}
+}
+
 // VReplicationWorkflowAction defines subcommands passed to vtctl for movetables or reshard
 type VReplicationWorkflowAction string
 
@@ -2090,7 +2334,7 @@ func commandVReplicationWorkflow(ctx context.Context, wr *wrangler.Wrangler, sub
 
 	cells := subFlags.String("cells", "", "Cell(s) or CellAlias(es) (comma-separated) to replicate from.")
 	tabletTypesStr := subFlags.String("tablet_types", "in_order:REPLICA,PRIMARY", "Source tablet types to replicate from (e.g. PRIMARY, REPLICA, RDONLY). Defaults to --vreplication_tablet_type parameter value for the tablet, which has the default value of in_order:REPLICA,PRIMARY. Note: SwitchTraffic overrides this default and uses in_order:RDONLY,REPLICA,PRIMARY to switch all traffic by default.")
-	dryRun := subFlags.Bool("dry_run", false, "Does a dry run of SwitchTraffic and only reports the actions to be taken. --dry_run is only supported for SwitchTraffic, ReverseTraffic and Complete.")
+	dryRun := subFlags.Bool("dry_run", false, "Does a dry run of SwitchTraffic and only reports the actions to be taken. --dry_run is only supported for SwitchTraffic, ReverseTraffic, Complete and Cancel.")
 	timeout := subFlags.Duration("timeout", defaultWaitTime, "Specifies the maximum time to wait, in seconds, for vreplication to catch up on primary migrations. The migration will be cancelled on a timeout. --timeout is only supported for SwitchTraffic and ReverseTraffic.")
 	reverseReplication := subFlags.Bool("reverse_replication", true, "Also reverse the replication (default true). --reverse_replication is only supported for SwitchTraffic.")
 	keepData := subFlags.Bool("keep_data", false, "Do not drop tables or shards (if true, only vreplication artifacts are cleaned up).  --keep_data is only supported for Complete and Cancel.")
@@ -2109,6 +2353,7 @@ func commandVReplicationWorkflow(ctx context.Context, wr *wrangler.Wrangler, sub
 	excludes := subFlags.String("exclude", "", "MoveTables only. Tables to exclude (comma-separated) if --all is specified")
 	sourceKeyspace := subFlags.String("source", "", "MoveTables only. Source keyspace")
 	initializeTargetSequences := subFlags.Bool("initialize-target-sequences", false, "MoveTables only. When moving tables from an unsharded keyspace to a sharded keyspace, initialize any sequences that are being used on the target when switching writes.")
+	autoSequences := subFlags.Bool("auto-sequences", false, "MoveTables only. When moving tables with auto_increment columns from an unsharded keyspace to a sharded keyspace, create backing sequence tables in the source keyspace, initialize them past the current max values and use them in the target vschema. --auto-sequences is only supported for Create.")
 
 	// if sourceTimeZone is specified, the target needs to have time zones loaded
 	// note we make an opinionated decision to not allow specifying a different target time zone than UTC.
@@ -2142,7 +2387,7 @@ func commandVReplicationWorkflow(ctx context.Context, wr *wrangler.Wrangler, sub
 
 	action := subFlags.Arg(0)
 	ksWorkflow := subFlags.Arg(1)
-	target, workflowName, err := splitKeyspaceWorkflow(ksWorkflow)
+	target, workflowName, err := splitKeyspaceWorkflow(ctx, ksWorkflow)
 	if err != nil {
 		return err
 	}
@@ -2260,6 +2505,7 @@ func commandVReplicationWorkflow(ctx context.Context, wr *wrangler.Wrangler, sub
 			vrwp.ExternalCluster = externalClusterName
 			vrwp.SourceTimeZone = *sourceTimeZone
 			vrwp.DropForeignKeys = *dropForeignKeys
+			vrwp.AutoSequences = *autoSequences
 			if *sourceShards != "" {
 				vrwp.SourceShards = strings.Split(*sourceShards, ",")
 			}
@@ -2350,9 +2596,9 @@ func commandVReplicationWorkflow(ctx context.Context, wr *wrangler.Wrangler, sub
 
 	if *dryRun {
 		switch action {
-		case vReplicationWorkflowActionSwitchTraffic, vReplicationWorkflowActionReverseTraffic, vReplicationWorkflowActionComplete:
+		case vReplicationWorkflowActionSwitchTraffic, vReplicationWorkflowActionReverseTraffic, vReplicationWorkflowActionComplete, vReplicationWorkflowActionCancel:
 		default:
-			return fmt.Errorf("--dry_run is only supported for SwitchTraffic, ReverseTraffic and Complete, not for %s", originalAction)
+			return fmt.Errorf("--dry_run is only supported for SwitchTraffic, ReverseTraffic, Complete and Cancel, not for %s", originalAction)
 		}
 	}
 
@@ -2440,7 +2686,7 @@ func commandVReplicationWorkflow(ctx context.Context, wr *wrangler.Wrangler, sub
 	case vReplicationWorkflowActionComplete:
 		dryRunResults, err = wf.Complete()
 	case vReplicationWorkflowActionCancel:
-		err = wf.Cancel()
+		dryRunResults, err = wf.Cancel()
 	case vReplicationWorkflowActionGetState:
 		wr.Logger().Printf(wf.CachedState() + "\n")
 		return nil
@@ -2474,6 +2720,7 @@ func commandCreateLookupVindex(ctx context.Context, wr *wrangler.Wrangler, subFl
 		return fmt.Errorf("two arguments are required: keyspace and json_spec")
 	}
 	keyspace := subFlags.Arg(0)
+	reportKeyspace(ctx, keyspace)
 	specs := &vschemapb.Keyspace{}
 	if err := json2.Unmarshal([]byte(subFlags.Arg(1)), specs); err != nil {
 		return err
@@ -2482,13 +2729,33 @@ func commandCreateLookupVindex(ctx context.Context, wr *wrangler.Wrangler, subFl
 }
 
 func commandExternalizeVindex(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	verifySampleSize := subFlags.Int("verify-sample-size", wrangler.DefaultLookupVindexVerifySampleSize, "Number of rows sampled from each source shard to verify the lookup vindex before externalizing it, 0 to skip the verification.")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
 	if subFlags.NArg() != 1 {
 		return fmt.Errorf("one argument is required: keyspace.vindex")
 	}
-	return wr.ExternalizeVindex(ctx, subFlags.Arg(0))
+	return wr.ExternalizeVindex(ctx, subFlags.Arg(0), *verifySampleSize)
+}
+
+func commandVerifyLookupVindex(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	sampleSize := subFlags.Int("sample-size", wrangler.DefaultLookupVindexVerifySampleSize, "Number of rows sampled from each source shard.")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("one argument is required: keyspace.vindex")
+	}
+	verification, err := wr.VerifyLookupVindex(ctx, subFlags.Arg(0), *sampleSize)
+	if err != nil {
+		return err
+	}
+	wr.Logger().Printf("%s\n", verification)
+	if verification.MismatchCount > 0 {
+		return fmt.Errorf("verification of vindex %s failed", subFlags.Arg(0))
+	}
+	return nil
 }
 
 func commandMaterialize(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
@@ -2550,7 +2817,7 @@ func commandVDiff(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.Fl
 	if subFlags.NArg() != 1 {
 		return fmt.Errorf("<keyspace.workflow> is required")
 	}
-	keyspace, workflow, err := splitKeyspaceWorkflow(subFlags.Arg(0))
+	keyspace, workflow, err := splitKeyspaceWorkflow(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -2576,11 +2843,12 @@ func commandVDiff(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.Fl
 	return err
 }
 
-func splitKeyspaceWorkflow(in string) (keyspace, workflow string, err error) {
+func splitKeyspaceWorkflow(ctx context.Context, in string) (keyspace, workflow string, err error) {
 	splits := strings.Split(in, ".")
 	if len(splits) != 2 {
 		return "", "", fmt.Errorf("invalid format for <keyspace.workflow>: %s", in)
 	}
+	reportKeyspace(ctx, splits[0])
 	return splits[0], splits[1], nil
 }
 
@@ -2593,6 +2861,7 @@ func commandFindAllShardsInKeyspace(ctx context.Context, wr *wrangler.Wrangler,
 	}
 
 	keyspace := subFlags.Arg(0)
+	reportKeyspace(ctx, keyspace)
 	result, err := wr.VtctldServer().FindAllShardsInKeyspace(ctx, &vtctldatapb.FindAllShardsInKeyspaceRequest{
 		Keyspace: keyspace,
 	})
@@ -2671,7 +2940,7 @@ func commandListTablets(ctx context.Context, wr *wrangler.Wrangler, subFlags *pf
 	aliases := make([]*topodatapb.TabletAlias, len(paths))
 	var err error
 	for i, path := range paths {
-		aliases[i], err = topoproto.ParseTabletAlias(path)
+		aliases[i], err = parseTabletAlias(ctx, path)
 		if err != nil {
 			return err
 		}
@@ -2707,7 +2976,7 @@ func commandGetSchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *pfla
 	if subFlags.NArg() != 1 {
 		return fmt.Errorf("the <tablet alias> argument is required for the GetSchema command")
 	}
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := parseTabletAlias(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -2750,7 +3019,7 @@ func commandReloadSchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *p
 	if subFlags.NArg() != 1 {
 		return fmt.Errorf("the <tablet alias> argument is required for the ReloadSchema command")
 	}
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := parseTabletAlias(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -2770,7 +3039,7 @@ func commandReloadSchemaShard(ctx context.Context, wr *wrangler.Wrangler, subFla
 	if subFlags.NArg() != 1 {
 		return fmt.Errorf("the <keyspace/shard> argument is required for the ReloadSchemaShard command")
 	}
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -2824,7 +3093,7 @@ func commandValidateSchemaShard(ctx context.Context, wr *wrangler.Wrangler, subF
 		return fmt.Errorf("the <keyspace/shard> argument is required for the ValidateSchemaShard command")
 	}
 
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -2840,6 +3109,7 @@ func commandValidateSchemaKeyspace(ctx context.Context, wr *wrangler.Wrangler, s
 	includeViews := subFlags.Bool("include-views", false, "Includes views in the validation")
 	skipNoPrimary := subFlags.Bool("skip-no-primary", true, "Skip shards that don't have primary when performing validation")
 	includeVSchema := subFlags.Bool("include-vschema", false, "Validate schemas against the vschema")
+	concurrency := subFlags.Uint32("concurrency", 0, "Maximum number of tablets to validate at once. Zero means no limit.")
 
 	if err := subFlags.Parse(args); err != nil {
 		return err
@@ -2849,16 +3119,18 @@ func commandValidateSchemaKeyspace(ctx context.Context, wr *wrangler.Wrangler, s
 	}
 
 	keyspace := subFlags.Arg(0)
+	reportKeyspace(ctx, keyspace)
 	var excludeTableArray []string
 	if *excludeTables != "" {
 		excludeTableArray = strings.Split(*excludeTables, ",")
 	}
-	resp, err := wr.VtctldServer().ValidateSchemaKeyspace(ctx, &vtctldatapb.ValidateSchemaKeyspaceRequest{
+	resp, err := wr.ValidateSchemaKeyspaceWithProgress(ctx, &vtctldatapb.ValidateSchemaKeyspaceRequest{
 		Keyspace:       keyspace,
 		ExcludeTables:  excludeTableArray,
 		IncludeViews:   *includeViews,
 		SkipNoPrimary:  *skipNoPrimary,
 		IncludeVschema: *includeVSchema,
+		Concurrency:    *concurrency,
 	})
 
 	if err != nil {
@@ -2901,6 +3173,7 @@ func commandApplySchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *pf
 	}
 
 	keyspace := subFlags.Arg(0)
+	reportKeyspace(ctx, keyspace)
 	change, err := getFileParam(*sql, *sqlFile, "sql")
 	if err != nil {
 		return err
@@ -2989,6 +3262,7 @@ func commandOnlineDDL(ctx context.Context, wr *wrangler.Wrangler, subFlags *pfla
 		return fmt.Errorf("the <keyspace> argument is required for the OnlineDDL command")
 	}
 	keyspace := subFlags.Args()[0]
+	reportKeyspace(ctx, keyspace)
 	if subFlags.NArg() < 2 {
 		return fmt.Errorf("the <command> argument is required for the OnlineDDL command")
 	}
@@ -3133,16 +3407,16 @@ func commandCopySchemaShard(ctx context.Context, wr *wrangler.Wrangler, subFlags
 	if *excludeTables != "" {
 		excludeTableArray = strings.Split(*excludeTables, ",")
 	}
-	destKeyspace, destShard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(1))
+	destKeyspace, destShard, err := parseKeyspaceShard(ctx, subFlags.Arg(1))
 	if err != nil {
 		return err
 	}
 
-	sourceKeyspace, sourceShard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	sourceKeyspace, sourceShard, err := parseKeyspaceShard(ctx, subFlags.Arg(0))
 	if err == nil {
 		return wr.CopySchemaShardFromShard(ctx, tableArray, excludeTableArray, *includeViews, sourceKeyspace, sourceShard, destKeyspace, destShard, *waitReplicasTimeout, *skipVerify)
 	}
-	sourceTabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	sourceTabletAlias, err := parseTabletAlias(ctx, subFlags.Arg(0))
 	if err == nil {
 		return wr.CopySchemaShard(ctx, sourceTabletAlias, tableArray, excludeTableArray, *includeViews, destKeyspace, destShard, *waitReplicasTimeout, *skipVerify)
 	}
@@ -3157,7 +3431,7 @@ func commandValidateVersionShard(ctx context.Context, wr *wrangler.Wrangler, sub
 		return fmt.Errorf("the <keyspace/shard> argument is required for the ValidateVersionShard command")
 	}
 
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -3173,6 +3447,7 @@ func commandValidateVersionKeyspace(ctx context.Context, wr *wrangler.Wrangler,
 	}
 
 	keyspace := subFlags.Arg(0)
+	reportKeyspace(ctx, keyspace)
 	res, err := wr.VtctldServer().ValidateVersionKeyspace(ctx, &vtctldatapb.ValidateVersionKeyspaceRequest{Keyspace: keyspace})
 
 	if err != nil {
@@ -3197,7 +3472,7 @@ func commandGetPermissions(ctx context.Context, wr *wrangler.Wrangler, subFlags
 	if subFlags.NArg() != 1 {
 		return fmt.Errorf("the <tablet alias> argument is required for the GetPermissions command")
 	}
-	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	tabletAlias, err := parseTabletAlias(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -3223,7 +3498,7 @@ func commandValidatePermissionsShard(ctx context.Context, wr *wrangler.Wrangler,
 		return fmt.Errorf("the <keyspace/shard> argument is required for the ValidatePermissionsShard command")
 	}
 
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	keyspace, shard, err := parseKeyspaceShard(ctx, subFlags.Arg(0))
 	if err != nil {
 		return err
 	}
@@ -3231,6 +3506,7 @@ func commandValidatePermissionsShard(ctx context.Context, wr *wrangler.Wrangler,
 }
 
 func commandValidatePermissionsKeyspace(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
+	concurrency := subFlags.Uint32("concurrency", 0, "Maximum number of tablets to validate at once. Zero means no limit.")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -3238,8 +3514,18 @@ func commandValidatePermissionsKeyspace(ctx context.Context, wr *wrangler.Wrangl
 		return fmt.Errorf("the <keyspace name> argument is required for the ValidatePermissionsKeyspace command")
 	}
 
-	keyspace := subFlags.Arg(0)
-	return wr.ValidatePermissionsKeyspace(ctx, keyspace)
+	resp, err := wr.ValidatePermissionsKeyspaceWithProgress(ctx, &vtctldatapb.ValidatePermissionsKeyspaceRequest{
+		Keyspace:    subFlags.Arg(0),
+		Concurrency: *concurrency,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(resp.Results) > 0 {
+		return fmt.Errorf("permissions diffs: %v", strings.Join(resp.Results, ", "))
+	}
+	return nil
 }
 
 func commandGetVSchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
@@ -3250,6 +3536,7 @@ func commandGetVSchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *pfl
 		return fmt.Errorf("the <keyspace> argument is required for the GetVSchema command")
 	}
 	keyspace := subFlags.Arg(0)
+	reportKeyspace(ctx, keyspace)
 	schema, err := wr.TopoServer().GetVSchema(ctx, keyspace)
 	if err != nil {
 		return err
@@ -3316,6 +3603,7 @@ func commandApplyVSchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *p
 		return fmt.Errorf("the <keyspace> argument is required for the ApplyVSchema command")
 	}
 	keyspace := subFlags.Arg(0)
+	reportKeyspace(ctx, keyspace)
 
 	var vs *vschemapb.Keyspace
 	var err error
@@ -3553,6 +3841,7 @@ func commandGetSrvKeyspace(ctx context.Context, wr *wrangler.Wrangler, subFlags
 
 	cell := subFlags.Arg(0)
 	keyspace := subFlags.Arg(1)
+	reportKeyspace(ctx, keyspace)
 
 	resp, err := wr.VtctldServer().GetSrvKeyspaces(ctx, &vtctldatapb.GetSrvKeyspacesRequest{
 		Keyspace: keyspace,
@@ -3609,6 +3898,7 @@ func commandUpdateThrottlerConfig(ctx context.Context, wr *wrangler.Wrangler, su
 	}
 
 	keyspace := subFlags.Arg(0)
+	reportKeyspace(ctx, keyspace)
 
 	req := &vtctldatapb.UpdateThrottlerConfigRequest{
 		Keyspace:          keyspace,
@@ -3675,7 +3965,7 @@ func commandGetShardReplication(ctx context.Context, wr *wrangler.Wrangler, subF
 		return fmt.Errorf("the <cell> and <keyspace/shard> arguments are required for the GetShardReplication command")
 	}
 
-	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(1))
+	keyspace, shard, err := parseKeyspaceShard(ctx, subFlags.Arg(1))
 	if err != nil {
 		return err
 	}
@@ -3705,11 +3995,12 @@ func commandHelp(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.Fla
 }
 
 func commandWorkflow(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.FlagSet, args []string) error {
-	usage := "usage: Workflow [--dry-run] [--cells] [--tablet-types] <keyspace>[.<workflow>] start/stop/update/delete/show/listall/tags [<tags>]"
+	usage := "usage: Workflow [--dry-run] [--cells] [--tablet-types] [--on-ddl] [--max-replication-lag] <keyspace>[.<workflow>] start/stop/update/delete/show/listall/tags [<tags>]"
 	dryRun := subFlags.Bool("dry-run", false, "Does a dry run of the Workflow action and reports the query and list of tablets on which the operation will be applied")
 	cells := subFlags.StringSlice("cells", []string{}, "New Cell(s) or CellAlias(es) (comma-separated) to replicate from. (Update only)")
 	tabletTypesStrs := subFlags.StringSlice("tablet-types", []string{}, "New source tablet types to replicate from (e.g. PRIMARY, REPLICA, RDONLY). (Update only)")
 	onDDL := subFlags.String("on-ddl", "", "New instruction on what to do when DDL is encountered in the VReplication stream. Possible values are IGNORE, STOP, EXEC, and EXEC_IGNORE. (Update only)")
+	maxReplicationLag := subFlags.Duration("max-replication-lag", 0, "New replication lag of the replicas of the target shards at which the workflow is throttled, instead of the threshold of the tablet throttler. 0 uses the threshold of the tablet throttler. (Update only)")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -3721,13 +4012,15 @@ func commandWorkflow(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag
 	var workflow string
 	var err error
 	if action != "listall" {
-		keyspace, workflow, err = splitKeyspaceWorkflow(subFlags.Arg(0))
+		keyspace, workflow, err = splitKeyspaceWorkflow(ctx, subFlags.Arg(0))
 		if err != nil {
 			return err
 		}
 		if workflow == "" {
 			return fmt.Errorf("workflow has to be defined for action %s", action)
 		}
+	} else {
+		reportKeyspace(ctx, keyspace)
 	}
 	_, err = wr.TopoServer().GetKeyspace(ctx, keyspace)
 	if err != nil {
@@ -3787,6 +4080,14 @@ func commandWorkflow(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag
 				}
 				onddl = ival
 			}
+			maxlag := int64(textutil.SimulatedNullInt) // To signify no value has been provided
+			if subFlags.Lookup("max-replication-lag").Changed {
+				changes = true
+				if *maxReplicationLag < 0 || *maxReplicationLag%time.Second != 0 {
+					return fmt.Errorf("invalid max-replication-lag: %v, it must be a non-negative whole number of seconds", *maxReplicationLag)
+				}
+				maxlag = int64(maxReplicationLag.Seconds())
+			}
 			if !changes {
 				return fmt.Errorf(errWorkflowUpdateWithoutChanges)
 			}
@@ -3800,6 +4101,7 @@ func commandWorkflow(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag
 				TabletTypes:               tabletTypes,
 				TabletSelectionPreference: tsp,
 				OnDdl:                     binlogdatapb.OnDDLAction(onddl),
+				MaxReplicationLag:         maxlag,
 			}
 		}
 		results, err = wr.WorkflowAction(ctx, workflow, keyspace, action, *dryRun, rpcReq) // Only update currently uses the new RPC path
@@ -3832,6 +4134,7 @@ func commandMount(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.Fl
 	topoType := subFlags.String("topo_type", "", "Type of cluster's topology server")
 	topoServer := subFlags.String("topo_server", "", "Server url of cluster's topology server")
 	topoRoot := subFlags.String("topo_root", "", "Root node of cluster's topology")
+	vtgateAddress := subFlags.String("vtgate_address", "", "Address of a vtgate of the cluster. If set, the workflows whose source is the cluster stream the binlog events through this vtgate")
 
 	if err := subFlags.Parse(args); err != nil {
 		return err
@@ -3869,7 +4172,7 @@ func commandMount(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.Fl
 			wr.Logger().Printf("%s\n", string(data))
 			return nil
 		default:
-			return wr.MountExternalVitessCluster(ctx, clusterName, *topoType, *topoServer, *topoRoot)
+			return wr.MountExternalVitessCluster(ctx, clusterName, *topoType, *topoServer, *topoRoot, *vtgateAddress)
 		}
 	case "mysql":
 		return fmt.Errorf("mysql cluster type not yet supported")
@@ -3897,6 +4200,13 @@ func commandPanic(ctx context.Context, wr *wrangler.Wrangler, subFlags *pflag.Fl
 	panic(fmt.Errorf("this command panics on purpose"))
 }
 
+// printDryRunResults prints the mutations a destructive command would have
+// performed.
+func printDryRunResults(logger logutil.Logger, command string, results []string) {
+	logger.Printf("Dry Run results for %s run at %s\n\n", command, time.Now().Format(time.RFC822))
+	logger.Printf("%s\n", strings.Join(results, "\n"))
+}
+
 // printJSON will print the JSON version of the structure to the logger.
 func printJSON(logger logutil.Logger, val any) error {
 	data, err := MarshalJSON(val)
@@ -3983,6 +4293,11 @@ func RunCommand(ctx context.Context, wr *wrangler.Wrangler, args []string) error
 		return fmt.Errorf("no command was specified")
 	}
 
+	// Track the command so it can be listed and canceled with the
+	// GetRunningCommands and CancelCommand vtctld RPCs.
+	ctx, done := commandtracker.Default.Start(ctx, args)
+	defer done()
+
 	action := args[0]
 	actionLowerCase := strings.ToLower(action)
 	for _, group := range commands {
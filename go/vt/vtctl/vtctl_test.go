@@ -469,3 +469,43 @@ func TestGenerateOnlineDDLQuery(t *testing.T) {
 		})
 	}
 }
+
+// TestRecoveryPolicy tests the SetRecoveryPolicy and GetRecoveryPolicy
+// commands.
+func TestRecoveryPolicy(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env := newTestVTCtlEnv(ctx)
+	defer env.close()
+	_ = env.addTablet(100, "ks", "0", &topodatapb.KeyRange{}, topodatapb.TabletType_PRIMARY)
+
+	run := func(command func(context.Context, *wrangler.Wrangler, *pflag.FlagSet, []string) error, args ...string) error {
+		return command(ctx, env.wr, pflag.NewFlagSet("test", pflag.ContinueOnError), args)
+	}
+
+	err := run(commandSetRecoveryPolicy, "--policy", `{"allowed_recoveries": ["FixReplica"], "cooldown_seconds": 60}`, "ks")
+	require.NoError(t, err)
+	err = run(commandSetRecoveryPolicy, "--policy", `{"promotion_cells": ["zone1"]}`, "ks/0")
+	require.NoError(t, err)
+
+	err = run(commandGetRecoveryPolicy, "ks/0")
+	require.NoError(t, err)
+	require.Equal(t, "{\n  \"promotion_cells\": [\n    \"zone1\"\n  ]\n}\n\n", env.cmdlog.String())
+	env.cmdlog.Clear()
+
+	err = run(commandSetRecoveryPolicy, "--policy", `{"cooldown": 60}`, "ks")
+	require.ErrorContains(t, err, `unknown field "cooldown"`)
+	err = run(commandSetRecoveryPolicy, "--policy", `{"promotion_rules": {"zone1": "always"}}`, "ks")
+	require.ErrorContains(t, err, "bad promotion rule for zone1")
+	err = run(commandSetRecoveryPolicy, "--policy", "{}", "ks/80-")
+	require.ErrorContains(t, err, "node doesn't exist")
+
+	err = run(commandSetRecoveryPolicy, "--clear", "ks/0")
+	require.NoError(t, err)
+	err = run(commandGetRecoveryPolicy, "ks/0")
+	require.ErrorContains(t, err, "node doesn't exist")
+	policy, err := env.topoServ.GetRecoveryPolicy(ctx, "ks", "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"FixReplica"}, policy.AllowedRecoveries)
+	require.Equal(t, 60, policy.CooldownSeconds)
+}
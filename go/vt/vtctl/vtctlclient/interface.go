@@ -27,6 +27,8 @@ import (
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/servenv"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
 // vtctlClientProtocol specifics which RPC client implementation should be used.
@@ -45,11 +47,34 @@ func init() {
 	}
 }
 
+// BatchOptions controls how ExecuteVtctlCommandBatch runs its commands.
+type BatchOptions struct {
+	// ActionTimeout applies to each command individually.
+	ActionTimeout time.Duration
+	// Parallel runs all the commands concurrently.
+	Parallel bool
+	// ContinueOnError keeps running a sequential batch after a command fails.
+	ContinueOnError bool
+}
+
+// BatchEventStream is the stream returned by ExecuteVtctlCommandBatch. Each
+// response is tagged with the index of the command it belongs to, and the last
+// response for each command that ran has Done set.
+type BatchEventStream interface {
+	// Recv returns the next response of the batch.
+	// If there are no more, it will return io.EOF.
+	Recv() (*vtctldatapb.ExecuteVtctlCommandBatchResponse, error)
+}
+
 // VtctlClient defines the interface used to send remote vtctl commands
 type VtctlClient interface {
 	// ExecuteVtctlCommand will execute the command remotely
 	ExecuteVtctlCommand(ctx context.Context, args []string, actionTimeout time.Duration) (logutil.EventStream, error)
 
+	// ExecuteVtctlCommandBatch will execute several commands remotely, within
+	// a single stream.
+	ExecuteVtctlCommandBatch(ctx context.Context, commands [][]string, opts BatchOptions) (BatchEventStream, error)
+
 	// Close will terminate the connection. This object won't be
 	// used after this.
 	Close()
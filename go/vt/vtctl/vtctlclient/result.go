@@ -0,0 +1,210 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctlclient
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/vt/logutil"
+
+	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
+)
+
+const (
+	// CommandStatusOK is the CommandResult status of a command that succeeded.
+	CommandStatusOK = "ok"
+	// CommandStatusError is the CommandResult status of a command that failed.
+	CommandStatusError = "error"
+)
+
+// CommandResult is a machine-readable summary of a single vtctl command
+// execution, built from the event stream returned by the vtctld.
+type CommandResult struct {
+	// Command is the command name and its arguments.
+	Command []string `json:"command"`
+	// Status is either CommandStatusOK or CommandStatusError.
+	Status string `json:"status"`
+	// Error is the error returned by the command, if any.
+	Error string `json:"error,omitempty"`
+	// Output is the console output of the command.
+	Output string `json:"output,omitempty"`
+	// Result is the console output of the command, if it was a valid JSON
+	// document, which is the case for most commands that return entities.
+	Result json.RawMessage `json:"result,omitempty"`
+	// Info, Warnings and Errors are the log messages the command emitted at
+	// the corresponding level.
+	Info     []string `json:"info,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+	Errors   []string `json:"errors,omitempty"`
+	// Affected are the entities the command acted on, as reported by the
+	// command.
+	Affected *AffectedEntities `json:"affected,omitempty"`
+	// Duration is how long the command took to run, as seen by the client.
+	Duration string `json:"duration"`
+}
+
+// AffectedEntities are the keyspaces, shards and tablets a command acts on.
+// Shards are given as keyspace/shard, and tablets by their alias.
+type AffectedEntities struct {
+	Keyspaces []string `json:"keyspaces,omitempty"`
+	Shards    []string `json:"shards,omitempty"`
+	Tablets   []string `json:"tablets,omitempty"`
+}
+
+// AddKeyspace adds a keyspace, unless it is empty or already there.
+func (a *AffectedEntities) AddKeyspace(keyspace string) {
+	if keyspace != "" && !slices.Contains(a.Keyspaces, keyspace) {
+		a.Keyspaces = append(a.Keyspaces, keyspace)
+	}
+}
+
+// AddShard adds a shard, along with its keyspace.
+func (a *AffectedEntities) AddShard(keyspace, shard string) {
+	a.AddKeyspace(keyspace)
+	if name := keyspace + "/" + shard; !slices.Contains(a.Shards, name) {
+		a.Shards = append(a.Shards, name)
+	}
+}
+
+// AddTablet adds a tablet, given by its alias.
+func (a *AffectedEntities) AddTablet(alias string) {
+	if !slices.Contains(a.Tablets, alias) {
+		a.Tablets = append(a.Tablets, alias)
+	}
+}
+
+// Empty returns whether there are no entities at all.
+func (a *AffectedEntities) Empty() bool {
+	return len(a.Keyspaces) == 0 && len(a.Shards) == 0 && len(a.Tablets) == 0
+}
+
+// Add adds all the entities of other.
+func (a *AffectedEntities) Add(other *AffectedEntities) {
+	for _, keyspace := range other.Keyspaces {
+		a.AddKeyspace(keyspace)
+	}
+	for _, shard := range other.Shards {
+		if !slices.Contains(a.Shards, shard) {
+			a.Shards = append(a.Shards, shard)
+		}
+	}
+	for _, alias := range other.Tablets {
+		a.AddTablet(alias)
+	}
+}
+
+// Event returns the AFFECTED_ENTITIES event that reports the entities to
+// the client of a command.
+func (a *AffectedEntities) Event() *logutilpb.Event {
+	value, _ := json.Marshal(a)
+	return &logutilpb.Event{
+		Time:  logutil.TimeToProto(time.Now()),
+		Level: logutilpb.Level_AFFECTED_ENTITIES,
+		Value: string(value),
+	}
+}
+
+// ResultCollector accumulates the events of a command's stream into a
+// CommandResult. Its Recv method is meant to be passed to RunCommandAndWait.
+type ResultCollector struct {
+	args     []string
+	affected *AffectedEntities
+	start    time.Time
+
+	m        sync.Mutex
+	output   strings.Builder
+	info     []string
+	warnings []string
+	errors   []string
+}
+
+// NewResultCollector returns a ResultCollector for the given command.
+func NewResultCollector(args []string) *ResultCollector {
+	return &ResultCollector{
+		args:     args,
+		affected: &AffectedEntities{},
+		start:    time.Now(),
+	}
+}
+
+// Report adds entities the command acts on, for commands that report them
+// on the client side. Commands run by a vtctld report theirs with
+// AFFECTED_ENTITIES events instead, which Recv adds.
+func (rc *ResultCollector) Report(affected *AffectedEntities) {
+	rc.m.Lock()
+	defer rc.m.Unlock()
+
+	rc.affected.Add(affected)
+}
+
+// Recv records a single event of the command's stream.
+func (rc *ResultCollector) Recv(e *logutilpb.Event) {
+	rc.m.Lock()
+	defer rc.m.Unlock()
+
+	switch e.Level {
+	case logutilpb.Level_CONSOLE:
+		rc.output.WriteString(e.Value)
+	case logutilpb.Level_INFO:
+		rc.info = append(rc.info, strings.TrimSuffix(e.Value, "\n"))
+	case logutilpb.Level_WARNING:
+		rc.warnings = append(rc.warnings, strings.TrimSuffix(e.Value, "\n"))
+	case logutilpb.Level_ERROR:
+		rc.errors = append(rc.errors, strings.TrimSuffix(e.Value, "\n"))
+	case logutilpb.Level_AFFECTED_ENTITIES:
+		affected := &AffectedEntities{}
+		if err := json.Unmarshal([]byte(e.Value), affected); err == nil {
+			rc.affected.Add(affected)
+		}
+	}
+}
+
+// Finish returns the CommandResult for the command, given the error returned
+// by RunCommandAndWait.
+func (rc *ResultCollector) Finish(err error) *CommandResult {
+	rc.m.Lock()
+	defer rc.m.Unlock()
+
+	result := &CommandResult{
+		Command:  rc.args,
+		Status:   CommandStatusOK,
+		Output:   rc.output.String(),
+		Info:     rc.info,
+		Warnings: rc.warnings,
+		Errors:   rc.errors,
+		Duration: time.Since(rc.start).String(),
+	}
+
+	if !rc.affected.Empty() {
+		result.Affected = rc.affected
+	}
+
+	if err != nil {
+		result.Status = CommandStatusError
+		result.Error = strings.TrimPrefix(err.Error(), "remote error: ")
+	}
+
+	if output := strings.TrimSpace(result.Output); output != "" && json.Valid([]byte(output)) {
+		result.Result = json.RawMessage(output)
+	}
+
+	return result
+}
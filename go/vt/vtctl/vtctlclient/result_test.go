@@ -0,0 +1,135 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctlclient
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
+)
+
+func TestResultCollector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		events   []*logutilpb.Event
+		err      error
+		expected *CommandResult
+	}{
+		{
+			name: "json output",
+			events: []*logutilpb.Event{
+				{Level: logutilpb.Level_INFO, Value: "reading tablet\n"},
+				{Level: logutilpb.Level_CONSOLE, Value: `{"keyspace": `},
+				{Level: logutilpb.Level_CONSOLE, Value: "\"ks\"}\n"},
+				{Level: logutilpb.Level_AFFECTED_ENTITIES, Value: `{"tablets":["cell1-0000000001"]}`},
+			},
+			expected: &CommandResult{
+				Status:   CommandStatusOK,
+				Output:   "{\"keyspace\": \"ks\"}\n",
+				Result:   json.RawMessage(`{"keyspace": "ks"}`),
+				Info:     []string{"reading tablet"},
+				Affected: &AffectedEntities{Tablets: []string{"cell1-0000000001"}},
+			},
+		},
+		{
+			name: "text output with warnings",
+			events: []*logutilpb.Event{
+				{Level: logutilpb.Level_WARNING, Value: "shard is not serving"},
+				{Level: logutilpb.Level_CONSOLE, Value: "cell1-0000000001 ks - primary\n"},
+				{Level: logutilpb.Level_AFFECTED_ENTITIES, Value: `{"keyspaces":["ks"],"shards":["ks/0"]}`},
+				{Level: logutilpb.Level_AFFECTED_ENTITIES, Value: `{"keyspaces":["ks"],"tablets":["cell1-0000000001"]}`},
+			},
+			expected: &CommandResult{
+				Status:   CommandStatusOK,
+				Output:   "cell1-0000000001 ks - primary\n",
+				Warnings: []string{"shard is not serving"},
+				Affected: &AffectedEntities{
+					Keyspaces: []string{"ks"},
+					Shards:    []string{"ks/0"},
+					Tablets:   []string{"cell1-0000000001"},
+				},
+			},
+		},
+		{
+			name: "error",
+			events: []*logutilpb.Event{
+				{Level: logutilpb.Level_ERROR, Value: "cannot reach tablet\n"},
+			},
+			err: errors.New("remote error: node doesn't exist"),
+			expected: &CommandResult{
+				Status: CommandStatusError,
+				Error:  "node doesn't exist",
+				Errors: []string{"cannot reach tablet"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			args := []string{"GetTablet", "cell1-1"}
+			rc := NewResultCollector(args)
+			for _, e := range tt.events {
+				rc.Recv(e)
+			}
+
+			result := rc.Finish(tt.err)
+			require.NotEmpty(t, result.Duration)
+
+			tt.expected.Command = args
+			tt.expected.Duration = result.Duration
+			assert.Equal(t, tt.expected, result)
+
+			_, err := json.Marshal(result)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAffectedEntitiesEvent(t *testing.T) {
+	t.Parallel()
+
+	affected := &AffectedEntities{}
+	assert.True(t, affected.Empty())
+
+	affected.AddShard("ks", "-80")
+	affected.AddTablet("zone1-0000000100")
+	affected.AddShard("ks", "-80")
+	assert.False(t, affected.Empty())
+
+	e := affected.Event()
+	assert.Equal(t, logutilpb.Level_AFFECTED_ENTITIES, e.Level)
+	assert.JSONEq(t, `{"keyspaces":["ks"],"shards":["ks/-80"],"tablets":["zone1-0000000100"]}`, e.Value)
+
+	rc := NewResultCollector([]string{"PlannedReparentShard"})
+	rc.Report(&AffectedEntities{Keyspaces: []string{"other"}})
+	rc.Recv(e)
+	assert.Equal(t, &AffectedEntities{
+		Keyspaces: []string{"other", "ks"},
+		Shards:    []string{"ks/-80"},
+		Tablets:   []string{"zone1-0000000100"},
+	}, rc.Finish(nil).Affected)
+}
@@ -40,6 +40,7 @@ import (
 	// import the gRPC client implementation for tablet manager
 	_ "vitess.io/vitess/go/vt/vttablet/grpctmclient"
 
+	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
@@ -96,6 +97,28 @@ func TestSuite(t *testing.T, ts *topo.Server, client vtctlclient.VtctlClient) {
 		t.Errorf("Didn't get end of log stream: %v %v", got, err)
 	}
 
+	// run a command that reports the tablet it acts on once it completes
+	stream, err = client.ExecuteVtctlCommand(ctx, []string{"GetTablet", "cell1-1"}, 30*time.Second)
+	if err != nil {
+		t.Fatalf("Remote error: %v", err)
+	}
+
+	var affected *logutilpb.Event
+	for {
+		got, err = stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Remote error: %v", err)
+		}
+		affected = got
+	}
+	expected = `{"tablets":["cell1-0000000001"]}`
+	if affected.GetLevel() != logutilpb.Level_AFFECTED_ENTITIES || affected.GetValue() != expected {
+		t.Errorf("Got unexpected last event '%v' expected affected entities '%v'", affected, expected)
+	}
+
 	// run a command that's gonna fail
 	stream, err = client.ExecuteVtctlCommand(ctx, []string{"ListAllTablets", "cell2"}, 30*time.Second)
 	if err != nil {
@@ -121,8 +144,72 @@ func TestSuite(t *testing.T, ts *topo.Server, client vtctlclient.VtctlClient) {
 		t.Fatalf("Unexpected remote error, got: '%v' was expecting to find '%v' and '%v'", err, expected1, expected2)
 	}
 
+	// run a sequential batch that stops at its first failing command
+	testBatch(ctx, t, client, vtctlclient.BatchOptions{ActionTimeout: 30 * time.Second}, map[int]string{0: "", 1: "node doesn't exist"})
+
+	// run the same batch, but keep going after the failure
+	testBatch(ctx, t, client, vtctlclient.BatchOptions{ActionTimeout: 30 * time.Second, ContinueOnError: true}, map[int]string{0: "", 1: "node doesn't exist", 2: "uncaught vtctl panic", 3: ""})
+
 	// and clean up the tablet
 	if err := ts.DeleteTablet(ctx, tablet.Alias); err != nil {
 		t.Errorf("DeleteTablet: %v", err)
 	}
 }
+
+// batchCommands is the batch used by testBatch. Its commands respectively
+// succeed, fail, panic and succeed.
+var batchCommands = [][]string{
+	{"ListAllTablets", "cell1"},
+	{"ListAllTablets", "cell2"},
+	{"Panic"},
+	{"ListAllTablets", "cell1"},
+}
+
+// testBatch runs batchCommands and checks that exactly the commands in
+// "expected" ran, and that they failed with the expected errors (an empty
+// string meaning success).
+func testBatch(ctx context.Context, t *testing.T, client vtctlclient.VtctlClient, opts vtctlclient.BatchOptions, expected map[int]string) {
+	stream, err := client.ExecuteVtctlCommandBatch(ctx, batchCommands, opts)
+	if err != nil {
+		t.Fatalf("Remote error: %v", err)
+	}
+
+	expectedLine := "cell1-0000000001 test_keyspace <null> primary localhost:3333 localhost:3334 [tag: \"value\"] 1970-01-01T01:01:01Z\n"
+	done := map[int]string{}
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Remote error: %v", err)
+		}
+
+		index := int(resp.CommandIndex)
+		if _, ok := done[index]; ok {
+			t.Fatalf("Got response for command %v after it was done: %v", index, resp)
+		}
+		if resp.Done {
+			done[index] = resp.Error
+			continue
+		}
+		if expected[index] == "" && logutil.EventString(resp.Event) != expectedLine {
+			t.Errorf("Got unexpected log line '%v' for command %v expected '%v'", resp.Event.String(), index, expectedLine)
+		}
+	}
+
+	if len(done) != len(expected) {
+		t.Fatalf("Got %v finished commands, expected %v: %v", len(done), len(expected), done)
+	}
+	for index, expectedErr := range expected {
+		gotErr, ok := done[index]
+		switch {
+		case !ok:
+			t.Errorf("Command %v did not run", index)
+		case expectedErr == "" && gotErr != "":
+			t.Errorf("Command %v failed unexpectedly: %v", index, gotErr)
+		case !strings.Contains(gotErr, expectedErr):
+			t.Errorf("Unexpected error for command %v, got: '%v' was expecting to find '%v'", index, gotErr, expectedErr)
+		}
+	}
+}
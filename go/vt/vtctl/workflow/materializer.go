@@ -151,6 +151,7 @@ func (mz *materializer) generateInserts(ctx context.Context, targetShard *topo.S
 			SourceTimeZone:  mz.ms.SourceTimeZone,
 			TargetTimeZone:  mz.ms.TargetTimeZone,
 			OnDdl:           binlogdatapb.OnDDLAction(binlogdatapb.OnDDLAction_value[mz.ms.OnDdl]),
+			DelaySeconds:    mz.ms.DelaySeconds,
 		}
 		for _, ts := range mz.ms.TableSettings {
 			rule := &binlogdatapb.Rule{
@@ -230,6 +231,8 @@ func (mz *materializer) generateInserts(ctx context.Context, targetShard *topo.S
 			workflowType = binlogdatapb.VReplicationWorkflowType_MoveTables
 		case vtctldatapb.MaterializationIntent_CREATELOOKUPINDEX:
 			workflowType = binlogdatapb.VReplicationWorkflowType_CreateLookupIndex
+		case vtctldatapb.MaterializationIntent_DELAYEDREPLICA:
+			workflowType = binlogdatapb.VReplicationWorkflowType_DelayedReplica
 		}
 		ig.AddRow(mz.ms.Workflow, bls, "", mz.ms.Cell, mz.ms.TabletTypes,
 			workflowType,
@@ -257,6 +260,7 @@ func (mz *materializer) generateBinlogSources(ctx context.Context, targetShard *
 			SourceTimeZone:  mz.ms.SourceTimeZone,
 			TargetTimeZone:  mz.ms.TargetTimeZone,
 			OnDdl:           binlogdatapb.OnDDLAction(binlogdatapb.OnDDLAction_value[mz.ms.OnDdl]),
+			DelaySeconds:    mz.ms.DelaySeconds,
 		}
 		for _, ts := range mz.ms.TableSettings {
 			rule := &binlogdatapb.Rule{
@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"sort"
 	"strings"
@@ -48,6 +49,7 @@ import (
 	"vitess.io/vitess/go/vt/vtctl/workflow/vexec"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vttablet/tabletmanager/vreplication"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
@@ -375,7 +377,8 @@ func (s *Server) GetWorkflows(ctx context.Context, req *vtctldatapb.GetWorkflows
 			message,
 			tags,
 			workflow_type,
-			workflow_sub_type
+			workflow_sub_type,
+			rows_copied
 		FROM
 			_vt.vreplication
 		%s`,
@@ -451,6 +454,7 @@ func (s *Server) GetWorkflows(ctx context.Context, req *vtctldatapb.GetWorkflows
 		}
 		workflowType, _ := row["workflow_type"].ToInt32()
 		workflowSubType, _ := row["workflow_sub_type"].ToInt32()
+		rowsCopied := row.AsInt64("rows_copied", 0)
 		stream := &vtctldatapb.Workflow_Stream{
 			Id:           id,
 			Shard:        tablet.Shard,
@@ -466,8 +470,9 @@ func (s *Server) GetWorkflows(ctx context.Context, req *vtctldatapb.GetWorkflows
 			TimeUpdated: &vttimepb.Time{
 				Seconds: timeUpdatedSeconds,
 			},
-			Message: message,
-			Tags:    tagArray,
+			Message:    message,
+			Tags:       tagArray,
+			RowsCopied: rowsCopied,
 		}
 
 		stream.CopyStates, err = s.getWorkflowCopyStates(ctx, tablet, id)
@@ -1326,6 +1331,12 @@ func (s *Server) WorkflowStatus(ctx context.Context, req *vtctldatapb.WorkflowSt
 			resp.TableCopyState[table].BytesCopied = progress.TargetTableSize
 			resp.TableCopyState[table].BytesTotal = progress.SourceTableSize
 			resp.TableCopyState[table].BytesPercentage = tableSizePct
+			// The row counts of the table statistics are estimates, so
+			// the target may seem to have more rows than the source.
+			if progress.SourceRowCount > progress.TargetRowCount {
+				resp.TableCopyState[table].RowsRemaining = progress.SourceRowCount - progress.TargetRowCount
+			}
+			resp.RowsRemaining += resp.TableCopyState[table].RowsRemaining
 		}
 	}
 
@@ -1341,6 +1352,7 @@ func (s *Server) WorkflowStatus(ctx context.Context, req *vtctldatapb.WorkflowSt
 		streamKeys = append(streamKeys, streamKey)
 	}
 	sort.Strings(streamKeys)
+	checkTime := time.Now()
 	resp.ShardStreams = make(map[string]*vtctldatapb.WorkflowStatusResponse_ShardStreams, len(streamKeys))
 	for _, streamKey := range streamKeys {
 		streams := workflow.ShardStreams[streamKey].GetStreams()
@@ -1379,13 +1391,46 @@ func (s *Server) WorkflowStatus(ctx context.Context, req *vtctldatapb.WorkflowSt
 			ts.Position = st.Position
 			ts.Status = st.State
 			ts.Info = strings.Join(info, "; ")
+			ts.RowsCopied = st.RowsCopied
+			ts.RowsPerSecond = streamCopyRate(st, checkTime)
+			resp.RowsPerSecond += ts.RowsPerSecond
 			resp.ShardStreams[ksShard].Streams[i] = ts
 		}
 	}
+	resp.Eta = copyEta(resp.RowsRemaining, resp.RowsPerSecond)
 
 	return resp, nil
 }
 
+// streamCopyRate returns the average number of rows per second that a stream
+// in its copy phase copied since the copy phase started, which is the time of
+// its first copy phase log. It returns 0 if the stream is not copying rows.
+func streamCopyRate(st *vtctldatapb.Workflow_Stream, now time.Time) float64 {
+	if st.State != binlogdatapb.VReplicationWorkflowState_Copying.String() || st.RowsCopied <= 0 {
+		return 0
+	}
+	for _, streamLog := range st.Logs {
+		if streamLog.Type != vreplication.LogCopyStart {
+			continue
+		}
+		elapsed := now.Sub(protoutil.TimeFromProto(streamLog.CreatedAt))
+		if elapsed < time.Second {
+			return 0
+		}
+		return float64(st.RowsCopied) / elapsed.Seconds()
+	}
+	return 0
+}
+
+// copyEta returns the estimated time to copy the remaining rows at the given
+// rate, or nil if no rows are being copied.
+func copyEta(rowsRemaining int64, rowsPerSecond float64) *vttimepb.Duration {
+	if rowsRemaining <= 0 || rowsPerSecond <= 0 {
+		return nil
+	}
+	return &vttimepb.Duration{Seconds: int64(math.Ceil(float64(rowsRemaining) / rowsPerSecond))}
+}
+
 // GetCopyProgress returns the progress of all tables being copied in the
 // workflow.
 func (s *Server) GetCopyProgress(ctx context.Context, ts *trafficSwitcher, state *State) (*CopyProgress, error) {
@@ -1529,6 +1574,7 @@ func (s *Server) WorkflowUpdate(ctx context.Context, req *vtctldatapb.WorkflowUp
 	span.Annotate("cells", req.TabletRequest.Cells)
 	span.Annotate("tablet_types", req.TabletRequest.TabletTypes)
 	span.Annotate("on_ddl", req.TabletRequest.OnDdl)
+	span.Annotate("max_replication_lag", req.TabletRequest.MaxReplicationLag)
 
 	vx := vexec.NewVExec(req.Keyspace, req.TabletRequest.Workflow, s.ts, s.tmc)
 	callback := func(ctx context.Context, tablet *topo.TabletInfo) (*querypb.QueryResult, error) {
@@ -2283,6 +2329,9 @@ func (s *Server) WorkflowSwitchTraffic(ctx context.Context, req *vtctldatapb.Wor
 			return nil, err
 		}
 	}
+	if err := ts.selectShards(req.Shards); err != nil {
+		return nil, err
+	}
 	reason, err := s.canSwitch(ctx, ts, startState, direction, int64(maxReplicationLagAllowed.Seconds()))
 	if err != nil {
 		return nil, err
@@ -2642,6 +2691,10 @@ func (s *Server) canSwitch(ctx context.Context, ts *trafficSwitcher, state *Stat
 	}
 	for _, stream := range wf.ShardStreams {
 		for _, st := range stream.GetStreams() {
+			if _, ok := ts.targets[st.Shard]; !ok {
+				// The traffic of this shard of a partial workflow is not switched.
+				continue
+			}
 			if st.Message == Frozen {
 				return cannotSwitchFrozen, nil
 			}
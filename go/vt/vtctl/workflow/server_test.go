@@ -20,19 +20,24 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/encoding/prototext"
 
+	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/test/utils"
 	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vttablet/tabletmanager/vreplication"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vttimepb "vitess.io/vitess/go/vt/proto/vttime"
 )
 
 type fakeTMC struct {
@@ -163,3 +168,55 @@ func TestCheckReshardingJournalExistsOnTablet(t *testing.T) {
 		})
 	}
 }
+
+func TestStreamCopyRate(t *testing.T) {
+	now := time.Now()
+	logs := []*vtctldatapb.Workflow_Stream_Log{
+		{Type: "State Changed", CreatedAt: protoutil.TimeToProto(now.Add(-300 * time.Second))},
+		{Type: vreplication.LogCopyStart, CreatedAt: protoutil.TimeToProto(now.Add(-200 * time.Second))},
+		{Type: vreplication.LogCopyStart, CreatedAt: protoutil.TimeToProto(now.Add(-100 * time.Second))},
+	}
+	copying := binlogdatapb.VReplicationWorkflowState_Copying.String()
+
+	tests := []struct {
+		name   string
+		stream *vtctldatapb.Workflow_Stream
+		rate   float64
+	}{
+		{
+			name:   "copying since the first copy phase log",
+			stream: &vtctldatapb.Workflow_Stream{State: copying, RowsCopied: 1000, Logs: logs},
+			rate:   5,
+		},
+		{
+			name:   "done copying",
+			stream: &vtctldatapb.Workflow_Stream{State: binlogdatapb.VReplicationWorkflowState_Running.String(), RowsCopied: 1000, Logs: logs},
+		},
+		{
+			name:   "no rows copied yet",
+			stream: &vtctldatapb.Workflow_Stream{State: copying, Logs: logs},
+		},
+		{
+			name:   "no copy phase log",
+			stream: &vtctldatapb.Workflow_Stream{State: copying, RowsCopied: 1000, Logs: logs[:1]},
+		},
+		{
+			name: "copy phase just started",
+			stream: &vtctldatapb.Workflow_Stream{State: copying, RowsCopied: 1000, Logs: []*vtctldatapb.Workflow_Stream_Log{
+				{Type: vreplication.LogCopyStart, CreatedAt: protoutil.TimeToProto(now)},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.rate, streamCopyRate(tt.stream, now))
+		})
+	}
+}
+
+func TestCopyEta(t *testing.T) {
+	assert.Nil(t, copyEta(0, 10))
+	assert.Nil(t, copyEta(1000, 0))
+	assert.Equal(t, &vttimepb.Duration{Seconds: 100}, copyEta(1000, 10))
+	assert.Equal(t, &vttimepb.Duration{Seconds: 334}, copyEta(1000, 3))
+}
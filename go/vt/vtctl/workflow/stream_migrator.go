@@ -697,11 +697,12 @@ func (sm *StreamMigrator) templatizeKeyRange(ctx context.Context, rule *binlogda
 		}
 
 		var krExpr sqlparser.SelectExpr
-		switch len(funcExpr.Exprs) {
-		case 1:
+		switch {
+		case len(funcExpr.Exprs) == 1:
 			krExpr = funcExpr.Exprs[0]
-		case 3:
-			krExpr = funcExpr.Exprs[2]
+		case len(funcExpr.Exprs) >= 3:
+			// The columns of the vindex are followed by the vindex and the keyrange.
+			krExpr = funcExpr.Exprs[len(funcExpr.Exprs)-1]
 		default:
 			return fmt.Errorf("unexpected in_keyrange parameters: %v", sqlparser.String(funcExpr))
 		}
@@ -727,13 +728,23 @@ func (sm *StreamMigrator) templatizeKeyRange(ctx context.Context, rule *binlogda
 
 	// There was no in_keyrange expression. Create a new one.
 	vtable := sm.ts.SourceKeyspaceSchema().Tables[rule.Match]
+	cv := vtable.ColumnVindexes[0]
+	krExprs := make(sqlparser.SelectExprs, 0, len(cv.Columns)+2)
+	for _, col := range cv.Columns {
+		krExprs = append(krExprs, &sqlparser.AliasedExpr{Expr: &sqlparser.ColName{Name: col}})
+	}
+	vindex := cv.Type
+	if len(cv.Columns) > 1 {
+		// A multi-column vindex cannot be created from its type alone, as it needs its params.
+		vindex = fmt.Sprintf("%s.%s", sm.ts.SourceKeyspaceName(), cv.Name)
+	}
+	krExprs = append(krExprs,
+		&sqlparser.AliasedExpr{Expr: sqlparser.NewStrLiteral(vindex)},
+		&sqlparser.AliasedExpr{Expr: sqlparser.NewStrLiteral("{{.}}")},
+	)
 	inkr := &sqlparser.FuncExpr{
-		Name: sqlparser.NewIdentifierCI("in_keyrange"),
-		Exprs: sqlparser.SelectExprs{
-			&sqlparser.AliasedExpr{Expr: &sqlparser.ColName{Name: vtable.ColumnVindexes[0].Columns[0]}},
-			&sqlparser.AliasedExpr{Expr: sqlparser.NewStrLiteral(vtable.ColumnVindexes[0].Type)},
-			&sqlparser.AliasedExpr{Expr: sqlparser.NewStrLiteral("{{.}}")},
-		},
+		Name:  sqlparser.NewIdentifierCI("in_keyrange"),
+		Exprs: krExprs,
 	}
 	sel.AddWhere(inkr)
 	rule.Filter = sqlparser.String(statement)
@@ -200,6 +200,32 @@ func TestTemplatize(t *testing.T) {
 			},
 		}},
 		out: `[{"ID":0,"Workflow":"","BinlogSource":{"filter":{"rules":[{"match":"t1","filter":"select * from t1 where in_keyrange(col, vdx, '{{.}}')"}]}}}]`,
+	}, {
+		// Select expression with the keyrange of a multi-column vindex
+		in: []*VReplicationStream{{
+			BinlogSource: &binlogdatapb.BinlogSource{
+				Filter: &binlogdatapb.Filter{
+					Rules: []*binlogdatapb.Rule{{
+						Match:  "t4",
+						Filter: "select * from t4 where in_keyrange(c1, c2, 'ks.tmulticol', '-80')",
+					}},
+				},
+			},
+		}},
+		out: `[{"ID":0,"Workflow":"","BinlogSource":{"filter":{"rules":[{"match":"t4","filter":"select * from t4 where in_keyrange(c1, c2, 'ks.tmulticol', '{{.}}')"}]}}}]`,
+	}, {
+		// Select expression with no keyrange value on a multi-column vindex
+		in: []*VReplicationStream{{
+			BinlogSource: &binlogdatapb.BinlogSource{
+				Filter: &binlogdatapb.Filter{
+					Rules: []*binlogdatapb.Rule{{
+						Match:  "t4",
+						Filter: "select * from t4",
+					}},
+				},
+			},
+		}},
+		out: `[{"ID":0,"Workflow":"","BinlogSource":{"filter":{"rules":[{"match":"t4","filter":"select * from t4 where in_keyrange(c1, c2, 'ks.tmulticol', '{{.}}')"}]}}}]`,
 	}, {
 		// syntax error
 		in: []*VReplicationStream{{
@@ -285,6 +311,12 @@ func TestTemplatize(t *testing.T) {
 			"thash": {
 				Type: "hash",
 			},
+			"tmulticol": {
+				Type: "multicol",
+				Params: map[string]string{
+					"column_count": "2",
+				},
+			},
 		},
 		Tables: map[string]*vschemapb.Table{
 			"t1": {
@@ -299,6 +331,12 @@ func TestTemplatize(t *testing.T) {
 					Name:    "thash",
 				}},
 			},
+			"t4": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{
+					Columns: []string{"c1", "c2"},
+					Name:    "tmulticol",
+				}},
+			},
 			"ref": {
 				Type: vindexes.TypeReference,
 			},
@@ -110,6 +110,14 @@ func (dr *switcherDryRun) changeRouting(ctx context.Context) error {
 	if dr.ts.MigrationType() == binlogdatapb.MigrationType_TABLES {
 		tables := strings.Join(dr.ts.Tables(), ",")
 		dr.drLog.Logf("Routing rules for tables [%s] will be updated", tables)
+		if dr.ts.isPartialMigration {
+			var shards []string
+			for _, si := range dr.ts.SourceShards() {
+				shards = append(shards, si.ShardName())
+			}
+			sort.Strings(shards)
+			dr.drLog.Logf("Shard routing rules for shards [%s] will be updated", strings.Join(shards, ","))
+		}
 		return nil
 	}
 	deleteLogs = nil
@@ -300,6 +300,9 @@ type MigrationTarget struct {
 	primary  *topo.TabletInfo
 	Sources  map[int32]*binlogdatapb.BinlogSource
 	Position string
+
+	// frozen is set if the writes of the target were switched.
+	frozen bool
 }
 
 // GetShard returns the *topo.ShardInfo for the migration target.
@@ -340,13 +343,19 @@ func (ts *trafficSwitcher) getSourceAndTargetShardsNames() ([]string, []string)
 	return sourceShards, targetShards
 }
 
-// isPartialMoveTables returns true if whe workflow is MoveTables, has the same
-// number of shards, is not covering the entire shard range, and has one-to-one
-// shards in source and target.
+// isPartialMoveTables returns true if whe workflow is MoveTables, and was
+// either created for a list of source shards, or has the same number of shards,
+// is not covering the entire shard range, and has one-to-one shards in source
+// and target.
 func (ts *trafficSwitcher) isPartialMoveTables(sourceShards, targetShards []string) (bool, error) {
 	if ts.MigrationType() != binlogdatapb.MigrationType_TABLES {
 		return false, nil
 	}
+	// A MoveTables created for a list of source shards is partial even if
+	// they cover the entire shard range, so that they can be switched one by one.
+	if ts.workflowSubType == binlogdatapb.VReplicationWorkflowSubType_Partial {
+		return true, nil
+	}
 
 	skr, tkr, err := getSourceAndTargetKeyRanges(sourceShards, targetShards)
 	if err != nil {
@@ -360,6 +369,50 @@ func (ts *trafficSwitcher) isPartialMoveTables(sourceShards, targetShards []stri
 	return key.KeyRangeEqual(skr, tkr), nil
 }
 
+// selectShards limits the traffic switch of a partial MoveTables workflow to
+// the given shards. Without shards, it selects the shards whose traffic was
+// not switched yet, if the traffic of some of them was, so that the traffic
+// of the shards of a partial workflow can be switched independently.
+func (ts *trafficSwitcher) selectShards(shards []string) error {
+	if !ts.isPartialMigration {
+		if len(shards) > 0 {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the traffic of the shards of workflow %s cannot be switched independently as it is not a partial MoveTables workflow", ts.workflow)
+		}
+		return nil
+	}
+	targets := make(map[string]*MigrationTarget)
+	if len(shards) == 0 {
+		for shard, target := range ts.targets {
+			if !target.frozen {
+				targets[shard] = target
+			}
+		}
+		if len(targets) == 0 || len(targets) == len(ts.targets) {
+			return nil
+		}
+	}
+	for _, shard := range shards {
+		target, ok := ts.targets[shard]
+		if !ok {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "shard %s is not part of workflow %s in keyspace %s", shard, ts.workflow, ts.targetKeyspace)
+		}
+		targets[shard] = target
+	}
+	sources := make(map[string]*MigrationSource)
+	ts.frozen = false
+	for _, target := range targets {
+		for _, bls := range target.Sources {
+			sources[bls.Shard] = ts.sources[bls.Shard]
+		}
+		ts.frozen = ts.frozen || target.frozen
+	}
+	ts.targets = targets
+	ts.sources = sources
+	ts.id = HashStreams(ts.targetKeyspace, targets)
+	log.Infof("Switching the traffic of workflow %s for shards %v, with migration ID %d", ts.workflow, maps2.Keys(targets), ts.id)
+	return nil
+}
+
 // addParticipatingTablesToKeyspace updates the vschema with the new tables that
 // were created as part of the Migrate flow. It is called when the Migrate flow
 // is Completed.
@@ -853,7 +906,12 @@ func (ts *trafficSwitcher) createReverseVReplication(ctx context.Context) error
 						// For non-reference tables we return an error if there's no primary
 						// vindex as it's not clear what to do.
 						if len(vtable.ColumnVindexes) > 0 && len(vtable.ColumnVindexes[0].Columns) > 0 {
-							inKeyrange = fmt.Sprintf(" where in_keyrange(%s, '%s.%s', '%s')", sqlparser.String(vtable.ColumnVindexes[0].Columns[0]),
+							// A multi-column primary vindex maps all of its columns to the keyspace id.
+							cols := make([]string, 0, len(vtable.ColumnVindexes[0].Columns))
+							for _, col := range vtable.ColumnVindexes[0].Columns {
+								cols = append(cols, sqlparser.String(col))
+							}
+							inKeyrange = fmt.Sprintf(" where in_keyrange(%s, '%s.%s', '%s')", strings.Join(cols, ", "),
 								ts.SourceKeyspaceName(), vtable.ColumnVindexes[0].Name, key.KeyRangeString(source.GetShard().KeyRange))
 						} else {
 							return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "no primary vindex found for the %s table in the %s keyspace",
@@ -17,11 +17,16 @@ limitations under the License.
 package workflow
 
 import (
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"vitess.io/vitess/go/maps2"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 )
 
 type testTrafficSwitcher struct {
@@ -33,6 +38,10 @@ func (tts *testTrafficSwitcher) SourceKeyspaceSchema() *vindexes.KeyspaceSchema
 	return tts.sourceKeyspaceSchema
 }
 
+func (tts *testTrafficSwitcher) SourceKeyspaceName() string {
+	return tts.sourceKeyspaceSchema.Keyspace.Name
+}
+
 func TestReverseWorkflowName(t *testing.T) {
 	tests := []struct {
 		in  string
@@ -56,3 +65,90 @@ func TestReverseWorkflowName(t *testing.T) {
 		assert.Equal(t, test.out, got)
 	}
 }
+
+func TestIsPartialMoveTables(t *testing.T) {
+	ts := &trafficSwitcher{migrationType: binlogdatapb.MigrationType_TABLES}
+	partial, err := ts.isPartialMoveTables([]string{"-80", "80-"}, []string{"-80", "80-"})
+	require.NoError(t, err)
+	assert.False(t, partial)
+	partial, err = ts.isPartialMoveTables([]string{"-80"}, []string{"-80"})
+	require.NoError(t, err)
+	assert.True(t, partial)
+
+	// A workflow created for a list of shards is partial even if they cover
+	// the entire shard range.
+	ts.workflowSubType = binlogdatapb.VReplicationWorkflowSubType_Partial
+	partial, err = ts.isPartialMoveTables([]string{"-80", "80-"}, []string{"-80", "80-"})
+	require.NoError(t, err)
+	assert.True(t, partial)
+
+	ts.migrationType = binlogdatapb.MigrationType_SHARDS
+	partial, err = ts.isPartialMoveTables([]string{"-80"}, []string{"-80"})
+	require.NoError(t, err)
+	assert.False(t, partial)
+}
+
+func TestSelectShards(t *testing.T) {
+	newTrafficSwitcher := func(frozen ...string) *trafficSwitcher {
+		ts := &trafficSwitcher{
+			workflow:           "wf",
+			targetKeyspace:     "customer",
+			isPartialMigration: true,
+			sources:            make(map[string]*MigrationSource),
+			targets:            make(map[string]*MigrationTarget),
+		}
+		for i, shard := range []string{"-40", "40-80", "80-"} {
+			ts.sources[shard] = &MigrationSource{}
+			ts.targets[shard] = &MigrationTarget{
+				Sources: map[int32]*binlogdatapb.BinlogSource{int32(i + 1): {Keyspace: "commerce", Shard: shard}},
+			}
+		}
+		for _, shard := range frozen {
+			ts.targets[shard].frozen = true
+		}
+		ts.id = HashStreams(ts.targetKeyspace, ts.targets)
+		return ts
+	}
+	sortedKeys := func(m map[string]*MigrationTarget) []string {
+		keys := maps2.Keys(m)
+		sort.Strings(keys)
+		return keys
+	}
+
+	ts := newTrafficSwitcher()
+	id := ts.id
+	require.NoError(t, ts.selectShards([]string{"40-80", "-40"}))
+	assert.Equal(t, []string{"-40", "40-80"}, sortedKeys(ts.targets))
+	assert.Len(t, ts.sources, 2)
+	assert.Contains(t, ts.sources, "-40")
+	assert.Contains(t, ts.sources, "40-80")
+	assert.False(t, ts.frozen)
+	assert.NotEqual(t, id, ts.id)
+	assert.Equal(t, HashStreams("customer", ts.targets), ts.id)
+
+	// Without shards, all the shards are selected if none were switched, and
+	// the shards that were not switched otherwise.
+	ts = newTrafficSwitcher()
+	require.NoError(t, ts.selectShards(nil))
+	assert.Len(t, ts.targets, 3)
+	assert.Equal(t, id, ts.id)
+	ts = newTrafficSwitcher("-40")
+	require.NoError(t, ts.selectShards(nil))
+	assert.Equal(t, []string{"40-80", "80-"}, sortedKeys(ts.targets))
+	assert.False(t, ts.frozen)
+	ts = newTrafficSwitcher("-40", "40-80", "80-")
+	require.NoError(t, ts.selectShards(nil))
+	assert.Len(t, ts.targets, 3)
+
+	// Selecting a switched shard lets switchWrites skip it.
+	ts = newTrafficSwitcher("-40")
+	require.NoError(t, ts.selectShards([]string{"-40"}))
+	assert.True(t, ts.frozen)
+
+	ts = newTrafficSwitcher()
+	assert.EqualError(t, ts.selectShards([]string{"c0-"}), "shard c0- is not part of workflow wf in keyspace customer")
+	ts.isPartialMigration = false
+	assert.EqualError(t, ts.selectShards([]string{"-40"}), "the traffic of the shards of workflow wf cannot be switched independently as it is not a partial MoveTables workflow")
+	require.NoError(t, ts.selectShards(nil))
+	assert.Len(t, ts.targets, 3)
+}
@@ -385,6 +385,7 @@ func BuildTargets(ctx context.Context, ts *topo.Server, tmc tmclient.TabletManag
 		for _, stream := range wf.Streams {
 			if stream.Message == Frozen {
 				frozen = true
+				target.frozen = true
 			}
 			target.Sources[stream.Id] = stream.Bls
 		}
@@ -739,6 +740,7 @@ func LegacyBuildTargets(ctx context.Context, ts *topo.Server, tmc tmclient.Table
 
 			if row["message"].ToString() == Frozen {
 				frozen = true
+				target.frozen = true
 			}
 
 			target.Sources[id] = &bls
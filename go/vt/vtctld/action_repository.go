@@ -125,7 +125,7 @@ func (ar *ActionRepository) ApplyKeyspaceAction(ctx context.Context, actionName,
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, actionTimeout)
-	wr := wrangler.New(logutil.NewConsoleLogger(), ar.ts, tmclient.NewTabletManagerClient())
+	wr := wrangler.New(logutil.NewConsoleLogger(), ar.ts, tmclient.NewRetryingClient(tmclient.NewTabletManagerClient(), tmclient.RetryPolicyFromFlags()))
 	output, err := action(ctx, wr, keyspace)
 	cancel()
 	if err != nil {
@@ -152,7 +152,7 @@ func (ar *ActionRepository) ApplyShardAction(ctx context.Context, actionName, ke
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, actionTimeout)
-	wr := wrangler.New(logutil.NewConsoleLogger(), ar.ts, tmclient.NewTabletManagerClient())
+	wr := wrangler.New(logutil.NewConsoleLogger(), ar.ts, tmclient.NewRetryingClient(tmclient.NewTabletManagerClient(), tmclient.RetryPolicyFromFlags()))
 	output, err := action(ctx, wr, keyspace, shard)
 	cancel()
 	if err != nil {
@@ -186,7 +186,7 @@ func (ar *ActionRepository) ApplyTabletAction(ctx context.Context, actionName st
 
 	// run the action
 	ctx, cancel := context.WithTimeout(ctx, actionTimeout)
-	wr := wrangler.New(logutil.NewConsoleLogger(), ar.ts, tmclient.NewTabletManagerClient())
+	wr := wrangler.New(logutil.NewConsoleLogger(), ar.ts, tmclient.NewRetryingClient(tmclient.NewTabletManagerClient(), tmclient.RetryPolicyFromFlags()))
 	output, err := action.method(ctx, wr, tabletAlias)
 	cancel()
 	if err != nil {
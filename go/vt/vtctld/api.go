@@ -40,6 +40,9 @@ import (
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vtctl"
+	"vitess.io/vitess/go/vt/vtctld/approval"
+	"vitess.io/vitess/go/vt/vtctld/audit"
+	"vitess.io/vitess/go/vt/vtctld/rbac"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 	"vitess.io/vitess/go/vt/wrangler"
 
@@ -183,7 +186,7 @@ func unmarshalRequest(r *http.Request, v any) error {
 
 func initAPI(ctx context.Context, ts *topo.Server, actions *ActionRepository) {
 	tabletHealthCache := newTabletHealthCache(ts)
-	tmClient := tmclient.NewTabletManagerClient()
+	tmClient := tmclient.NewRetryingClient(tmclient.NewTabletManagerClient(), tmclient.RetryPolicyFromFlags())
 
 	// Cells
 	handleCollection("cells", func(r *http.Request) (any, error) {
@@ -222,6 +225,9 @@ func initAPI(ctx context.Context, ts *topo.Server, actions *ActionRepository) {
 			if action == "" {
 				return nil, errors.New("a POST request must specify action")
 			}
+			if err := rbac.Authorize(r.Context(), audit.CallerFromHTTPRequest(r), action); err != nil {
+				return nil, err
+			}
 			return actions.ApplyKeyspaceAction(ctx, action, keyspace), nil
 		default:
 			return nil, fmt.Errorf("unsupported HTTP method: %v", r.Method)
@@ -312,6 +318,9 @@ func initAPI(ctx context.Context, ts *topo.Server, actions *ActionRepository) {
 			if action == "" {
 				return nil, errors.New("must specify action")
 			}
+			if err := rbac.Authorize(r.Context(), audit.CallerFromHTTPRequest(r), action); err != nil {
+				return nil, err
+			}
 			return actions.ApplyShardAction(ctx, action, keyspace, shard), nil
 		}
 
@@ -445,6 +454,9 @@ func initAPI(ctx context.Context, ts *topo.Server, actions *ActionRepository) {
 			if action == "" {
 				return nil, errors.New("must specify action")
 			}
+			if err := rbac.Authorize(r.Context(), audit.CallerFromHTTPRequest(r), action); err != nil {
+				return nil, err
+			}
 			return actions.ApplyTabletAction(ctx, action, tabletAlias, r), nil
 		}
 
@@ -490,8 +502,20 @@ func initAPI(ctx context.Context, ts *topo.Server, actions *ActionRepository) {
 
 		logstream := logutil.NewMemoryLogger()
 
-		wr := wrangler.New(logstream, ts, tmClient)
-		err := vtctl.RunCommand(r.Context(), wr, args)
+		start := time.Now()
+		caller := audit.CallerFromHTTPRequest(r)
+		var err error
+		if len(args) > 0 {
+			err = rbac.Authorize(r.Context(), caller, args[0])
+			if err == nil {
+				err = approval.Check(r.Context(), caller, r.Header.Get(approval.TokenMetadataKey), args[0], args[1:])
+			}
+		}
+		if err == nil {
+			wr := wrangler.New(logstream, ts, tmClient)
+			err = vtctl.RunCommand(r.Context(), wr, args)
+		}
+		audit.Record(r.Context(), caller, "/api/vtctl", args, start, err)
 		if err != nil {
 			resp.Error = err.Error()
 		}
@@ -522,6 +546,9 @@ func initAPI(ctx context.Context, ts *topo.Server, actions *ActionRepository) {
 		if req.ReplicaTimeoutSeconds <= 0 {
 			req.ReplicaTimeoutSeconds = 10
 		}
+		if err := rbac.Authorize(r.Context(), audit.CallerFromHTTPRequest(r), "ApplySchema"); err != nil {
+			return err
+		}
 
 		logger := logutil.NewCallbackLogger(func(ev *logutilpb.Event) {
 			w.Write([]byte(logutil.EventString(ev)))
@@ -22,6 +22,8 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -29,6 +31,7 @@ import (
 
 	"vitess.io/vitess/go/vt/servenv/testutils"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtctld/rbac"
 	"vitess.io/vitess/go/vt/wrangler"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
@@ -243,7 +246,8 @@ func TestAPI(t *testing.T) {
 				"snapshot_time":null,
 				"durability_policy":"semi_sync",
 				"throttler_config": null,
-				"sidecar_db_name":"_vt_sidecar_ks1"
+				"sidecar_db_name":"_vt_sidecar_ks1",
+				"online_ddl_scheduler_config":null
 			}`, http.StatusOK},
 		{"GET", "keyspaces/nonexistent", "", "404 page not found", http.StatusNotFound},
 		{"POST", "keyspaces/ks1?action=TestKeyspaceAction", "", `{
@@ -324,11 +328,11 @@ func TestAPI(t *testing.T) {
 		// vtctl RunCommand
 		{"POST", "vtctl/", `["GetKeyspace","ks1"]`, `{
 		   "Error": "",
-		   "Output": "{\n  \"served_froms\": [],\n  \"keyspace_type\": 0,\n  \"base_keyspace\": \"\",\n  \"snapshot_time\": null,\n  \"durability_policy\": \"semi_sync\",\n  \"throttler_config\": null,\n  \"sidecar_db_name\": \"_vt_sidecar_ks1\"\n}\n\n"
+		   "Output": "{\n  \"served_froms\": [],\n  \"keyspace_type\": 0,\n  \"base_keyspace\": \"\",\n  \"snapshot_time\": null,\n  \"durability_policy\": \"semi_sync\",\n  \"throttler_config\": null,\n  \"sidecar_db_name\": \"_vt_sidecar_ks1\",\n  \"online_ddl_scheduler_config\": null\n}\n\n"
 		}`, http.StatusOK},
 		{"POST", "vtctl/", `["GetKeyspace","ks3"]`, `{
 		   "Error": "",
-		   "Output": "{\n  \"served_froms\": [],\n  \"keyspace_type\": 1,\n  \"base_keyspace\": \"ks1\",\n  \"snapshot_time\": {\n    \"seconds\": \"1136214245\",\n    \"nanoseconds\": 0\n  },\n  \"durability_policy\": \"none\",\n  \"throttler_config\": null,\n  \"sidecar_db_name\": \"_vt\"\n}\n\n"
+		   "Output": "{\n  \"served_froms\": [],\n  \"keyspace_type\": 1,\n  \"base_keyspace\": \"ks1\",\n  \"snapshot_time\": {\n    \"seconds\": \"1136214245\",\n    \"nanoseconds\": 0\n  },\n  \"durability_policy\": \"none\",\n  \"throttler_config\": null,\n  \"sidecar_db_name\": \"_vt\",\n  \"online_ddl_scheduler_config\": null\n}\n\n"
 		}`, http.StatusOK},
 		{"POST", "vtctl/", `["GetVSchema","ks3"]`, `{
 		   "Error": "",
@@ -377,4 +381,37 @@ func TestAPI(t *testing.T) {
 		})
 
 	}
+
+	// With an rbac policy that only grants the read-only commands, the other
+	// commands and actions are rejected.
+	policyPath := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(policyPath, []byte("rules:\n  - groups: [read-only]\n    subjects: [\"*\"]\n"), 0o644))
+	authz, err := rbac.NewFileAuthorizer(policyPath, 0)
+	require.NoError(t, err)
+	defer rbac.SetAuthorizer(authz)()
+
+	rbacTable := []struct {
+		path, body, want string
+		statusCode       int
+	}{
+		{"vtctl/", `["GetKeyspace", "ks1"]`, `"Error": ""`, http.StatusOK},
+		{"vtctl/", `["DeleteKeyspace", "--recursive", "ks1"]`, `"Error": "unauthenticated caller is not allowed to run DeleteKeyspace (group admin)"`, http.StatusOK},
+		{"schema/apply", `{"Keyspace": "ks1", "SQL": "drop table t1"}`, "unauthenticated caller is not allowed to run ApplySchema (group admin)", http.StatusInternalServerError},
+		{"keyspaces/ks1?action=TestKeyspaceAction", "", "unauthenticated caller is not allowed to run TestKeyspaceAction (group admin)", http.StatusInternalServerError},
+		{"shards/ks1/-80?action=TestShardAction", "", "unauthenticated caller is not allowed to run TestShardAction (group admin)", http.StatusInternalServerError},
+		{"tablets/cell1-100?action=TestTabletAction", "", "unauthenticated caller is not allowed to run TestTabletAction (group admin)", http.StatusInternalServerError},
+		{"keyspaces/ks1?action=ValidateKeyspace", "", `"Name": "ValidateKeyspace"`, http.StatusOK},
+	}
+	for _, in := range rbacTable {
+		t.Run("rbac POST "+in.path, func(t *testing.T) {
+			resp, err := http.Post(server.URL+apiPrefix+in.path, "application/json", strings.NewReader(in.body))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.Equal(t, in.statusCode, resp.StatusCode)
+			require.Contains(t, string(body), in.want)
+		})
+	}
 }
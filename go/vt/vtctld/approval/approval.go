@@ -0,0 +1,263 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package approval requires an external approval before the high-risk vtctl
+commands and vtctld RPCs, such as EmergencyReparentShard or a recursive
+DeleteKeyspace, run against a vtctld.
+
+The approval decision is delegated to a pluggable Approver, selected with
+--approval-hook. Two Approvers ship with this package: "webhook" asks a
+change-control service over HTTP, and "token" requires a token issued by a
+second person (see NewToken). Commands that are not high-risk never go through
+the Approver.
+*/
+package approval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vtctld/audit"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// Request describes a high-risk command waiting for approval.
+type Request struct {
+	Caller audit.Caller `json:"caller"`
+	// Command is the name of the vtctl command or vtctld RPC.
+	Command string `json:"command"`
+	// Target is what the command acts on: a keyspace, or a keyspace/shard.
+	Target string `json:"target"`
+	// Request holds the arguments of the call, as JSON.
+	Request json.RawMessage `json:"request,omitempty"`
+	// Token is the approval token the caller sent along with the call, if any.
+	Token string `json:"token,omitempty"`
+}
+
+// Approver decides whether a high-risk command may run.
+type Approver interface {
+	// Approve returns nil if the command is approved, and an error otherwise.
+	// It may block until a decision is made, or ctx expires.
+	Approve(ctx context.Context, req *Request) error
+}
+
+// ApproverFactory creates an Approver, given the value of
+// --approval-hook-config.
+type ApproverFactory func(config string) (Approver, error)
+
+var approverFactories = map[string]ApproverFactory{}
+
+// RegisterApprover registers an ApproverFactory under the given name, to be
+// selected with --approval-hook.
+func RegisterApprover(name string, factory ApproverFactory) {
+	if _, ok := approverFactories[name]; ok {
+		log.Fatalf("approval hook %s already registered", name)
+	}
+
+	approverFactories[name] = factory
+}
+
+// highRiskCheck returns the target of a command, given either its gRPC request
+// or its legacy vtctl arguments (without the command name), and whether that
+// invocation is high-risk.
+type highRiskCheck func(req any) (target string, highRisk bool)
+
+// highRiskCommands holds the commands that may require approval. Names are
+// lowercase, because the legacy vtctl command names are case-insensitive.
+var highRiskCommands = map[string]highRiskCheck{
+	"emergencyreparentshard": func(req any) (string, bool) {
+		switch req := req.(type) {
+		case *vtctldatapb.EmergencyReparentShardRequest:
+			return req.Keyspace + "/" + req.Shard, true
+		case []string:
+			// The shard is given either with --keyspace_shard or as the first
+			// positional argument, and it is the only argument with a slash.
+			for _, arg := range req {
+				if _, value, ok := strings.Cut(arg, "="); ok && strings.HasPrefix(arg, "-") {
+					arg = value
+				}
+				if strings.Contains(arg, "/") {
+					return arg, true
+				}
+			}
+			return "", true
+		}
+		return "", false
+	},
+	"deletekeyspace": func(req any) (string, bool) {
+		switch req := req.(type) {
+		case *vtctldatapb.DeleteKeyspaceRequest:
+			return req.Keyspace, req.Recursive && !req.DryRun
+		case []string:
+			// Parse the arguments like the legacy command does, so that a flag
+			// set more than once, or set with any of the spellings ParseBool
+			// accepts, has the same effect here as on the command.
+			fs := pflag.NewFlagSet("DeleteKeyspace", pflag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			recursive := fs.Bool("recursive", false, "")
+			dryRun := fs.Bool("dry_run", false, "")
+			if len(req) > 0 && req[0] == "--" {
+				req = req[1:]
+			}
+			if err := fs.Parse(req); err != nil || fs.NArg() != 1 {
+				// The command fails on such arguments, but err on the safe
+				// side if it does not.
+				return "", true
+			}
+			return fs.Arg(0), *recursive && !*dryRun
+		}
+		return "", false
+	},
+}
+
+// MayRequireApproval returns whether some invocations of the command are
+// high-risk.
+func MayRequireApproval(command string) bool {
+	_, ok := highRiskCommands[strings.ToLower(command)]
+	return ok
+}
+
+// HighRisk returns whether a command requires approval, and its target. req is
+// either the gRPC request of a vtctld RPC, or the arguments of a legacy vtctl
+// command, without the command name.
+func HighRisk(command string, req any) (target string, highRisk bool) {
+	check, ok := highRiskCommands[strings.ToLower(command)]
+	if !ok {
+		return "", false
+	}
+
+	return check(req)
+}
+
+var (
+	hookName   string
+	hookConfig string
+
+	defaultApprover Approver
+)
+
+func init() {
+	for _, cmd := range []string{"vtcombo", "vtctld"} {
+		servenv.OnParseFor(cmd, registerFlags)
+	}
+
+	RegisterApprover("token", NewTokenApprover)
+	RegisterApprover("webhook", NewWebhookApprover)
+}
+
+func registerFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&hookName, "approval-hook", hookName, fmt.Sprintf("Name of the hook approving high-risk vtctl commands and vtctld RPCs, such as EmergencyReparentShard and recursive DeleteKeyspace. Leave empty to run them without approval. Valid values are: %s.", strings.Join(approverNames(), ", ")))
+	fs.StringVar(&hookConfig, "approval-hook-config", hookConfig, "Configuration of the approval hook: a URL for the webhook hook, and the path to the approvers file for the token hook.")
+}
+
+func approverNames() []string {
+	names := make([]string, 0, len(approverFactories))
+	for name := range approverFactories {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// Init creates the Approver selected by --approval-hook, and installs the gRPC
+// interceptors that enforce it. It is a no-op if no hook is selected. It must
+// be called before servenv.Run.
+func Init() error {
+	if hookName == "" {
+		return nil
+	}
+
+	factory, ok := approverFactories[hookName]
+	if !ok {
+		return fmt.Errorf("unknown approval hook %q, valid values are: %s", hookName, strings.Join(approverNames(), ", "))
+	}
+
+	approver, err := factory(hookConfig)
+	if err != nil {
+		return fmt.Errorf("cannot create %s approval hook: %w", hookName, err)
+	}
+
+	defaultApprover = approver
+
+	i := &interceptor{approver: approver}
+	servenv.AddGRPCServerInterceptors(i.stream, i.unary)
+
+	log.Infof("approval of high-risk commands enabled with the %s hook", hookName)
+	return nil
+}
+
+// Check gets approval for a call that does not go through gRPC, such as a
+// vtctld HTTP API call, from the Approver selected by --approval-hook. It
+// approves every call if no hook is selected, and every call that is not
+// high-risk.
+func Check(ctx context.Context, caller audit.Caller, token string, command string, req any) error {
+	if defaultApprover == nil {
+		return nil
+	}
+
+	return check(ctx, defaultApprover, caller, token, command, req)
+}
+
+func check(ctx context.Context, approver Approver, caller audit.Caller, token string, command string, req any) error {
+	target, highRisk := HighRisk(command, req)
+	if !highRisk {
+		return nil
+	}
+
+	if err := approver.Approve(ctx, &Request{
+		Caller:  caller,
+		Command: command,
+		Target:  target,
+		Request: marshalRequest(req),
+		Token:   token,
+	}); err != nil {
+		return err
+	}
+
+	log.Infof("%s on %s has been approved", command, target)
+	return nil
+}
+
+func marshalRequest(req any) json.RawMessage {
+	var (
+		data []byte
+		err  error
+	)
+	switch req := req.(type) {
+	case proto.Message:
+		data, err = protojson.MarshalOptions{UseProtoNames: true}.Marshal(req)
+	default:
+		data, err = json.Marshal(req)
+	}
+	if err != nil {
+		return nil
+	}
+
+	return data
+}
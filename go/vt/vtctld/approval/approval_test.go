@@ -0,0 +1,235 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approval
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"vitess.io/vitess/go/vt/vtctld/audit"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+func TestHighRisk(t *testing.T) {
+	tests := []struct {
+		name           string
+		command        string
+		req            any
+		expectedTarget string
+		expectedRisk   bool
+	}{
+		{
+			name:           "EmergencyReparentShard rpc",
+			command:        "EmergencyReparentShard",
+			req:            &vtctldatapb.EmergencyReparentShardRequest{Keyspace: "ks", Shard: "-80"},
+			expectedTarget: "ks/-80",
+			expectedRisk:   true,
+		},
+		{
+			name:           "legacy EmergencyReparentShard with positional shard",
+			command:        "EmergencyReparentShard",
+			req:            []string{"--wait_replicas_timeout", "30s", "ks/-80", "zone1-101"},
+			expectedTarget: "ks/-80",
+			expectedRisk:   true,
+		},
+		{
+			name:           "legacy EmergencyReparentShard with flag",
+			command:        "emergencyreparentshard",
+			req:            []string{"--new_primary=zone1-101", "--keyspace_shard=ks/80-"},
+			expectedTarget: "ks/80-",
+			expectedRisk:   true,
+		},
+		{
+			name:           "recursive DeleteKeyspace rpc",
+			command:        "DeleteKeyspace",
+			req:            &vtctldatapb.DeleteKeyspaceRequest{Keyspace: "ks", Recursive: true},
+			expectedTarget: "ks",
+			expectedRisk:   true,
+		},
+		{
+			name:           "recursive DeleteKeyspace rpc dry run",
+			command:        "DeleteKeyspace",
+			req:            &vtctldatapb.DeleteKeyspaceRequest{Keyspace: "ks", Recursive: true, DryRun: true},
+			expectedTarget: "ks",
+		},
+		{
+			name:           "non-recursive DeleteKeyspace rpc",
+			command:        "DeleteKeyspace",
+			req:            &vtctldatapb.DeleteKeyspaceRequest{Keyspace: "ks"},
+			expectedTarget: "ks",
+		},
+		{
+			name:           "legacy recursive DeleteKeyspace",
+			command:        "DeleteKeyspace",
+			req:            []string{"--recursive", "ks"},
+			expectedTarget: "ks",
+			expectedRisk:   true,
+		},
+		{
+			name:           "legacy recursive DeleteKeyspace with other spellings of true",
+			command:        "DeleteKeyspace",
+			req:            []string{"--recursive=1", "ks"},
+			expectedTarget: "ks",
+			expectedRisk:   true,
+		},
+		{
+			name:           "legacy recursive DeleteKeyspace with uppercase true",
+			command:        "DeleteKeyspace",
+			req:            []string{"ks", "--recursive=TRUE"},
+			expectedTarget: "ks",
+			expectedRisk:   true,
+		},
+		{
+			name:           "legacy recursive DeleteKeyspace with dry run overridden",
+			command:        "DeleteKeyspace",
+			req:            []string{"--recursive", "--dry_run", "--dry_run=false", "ks"},
+			expectedTarget: "ks",
+			expectedRisk:   true,
+		},
+		{
+			name:           "legacy recursive DeleteKeyspace dry run",
+			command:        "DeleteKeyspace",
+			req:            []string{"--recursive=T", "--dry_run=false", "--dry_run", "ks"},
+			expectedTarget: "ks",
+		},
+		{
+			name:           "legacy non-recursive DeleteKeyspace",
+			command:        "DeleteKeyspace",
+			req:            []string{"--recursive", "--recursive=false", "ks"},
+			expectedTarget: "ks",
+		},
+		{
+			name:         "legacy DeleteKeyspace with unknown arguments",
+			command:      "DeleteKeyspace",
+			req:          []string{"--recursive=maybe", "ks"},
+			expectedRisk: true,
+		},
+		{
+			name:    "other command",
+			command: "PlannedReparentShard",
+			req:     &vtctldatapb.PlannedReparentShardRequest{Keyspace: "ks", Shard: "-"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, highRisk := HighRisk(tt.command, tt.req)
+			assert.Equal(t, tt.expectedTarget, target)
+			assert.Equal(t, tt.expectedRisk, highRisk)
+		})
+	}
+}
+
+// fakeApprover approves the commands whose token is "approved", and records
+// the requests it gets.
+type fakeApprover struct {
+	requests []*Request
+}
+
+func (a *fakeApprover) Approve(ctx context.Context, req *Request) error {
+	a.requests = append(a.requests, req)
+	if req.Token != "approved" {
+		return vterrors.Errorf(vtrpcpb.Code_PERMISSION_DENIED, "%s on %s was not approved", req.Command, req.Target)
+	}
+
+	return nil
+}
+
+func TestInterceptor(t *testing.T) {
+	approver := &fakeApprover{}
+	i := &interceptor{approver: approver}
+
+	handler := func(ctx context.Context, req any) (any, error) { return "ok", nil }
+	info := &grpc.UnaryServerInfo{FullMethod: "/vtctlservice.Vtctld/EmergencyReparentShard"}
+	req := &vtctldatapb.EmergencyReparentShardRequest{Keyspace: "ks", Shard: "-"}
+
+	_, err := i.unary(context.Background(), req, info, handler)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(TokenMetadataKey, "approved"))
+	resp, err := i.unary(ctx, req, info, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+
+	require.Len(t, approver.requests, 2)
+	assert.Equal(t, "EmergencyReparentShard", approver.requests[1].Command)
+	assert.Equal(t, "ks/-", approver.requests[1].Target)
+	assert.JSONEq(t, `{"keyspace":"ks","shard":"-"}`, string(approver.requests[1].Request))
+
+	// Commands that are not high-risk do not go through the approver.
+	_, err = i.unary(context.Background(), &vtctldatapb.DeleteKeyspaceRequest{Keyspace: "ks"}, &grpc.UnaryServerInfo{FullMethod: "/vtctlservice.Vtctld/DeleteKeyspace"}, handler)
+	assert.NoError(t, err)
+	assert.Len(t, approver.requests, 2)
+
+	// Legacy commands are checked based on their arguments.
+	streamHandler := func(srv any, stream grpc.ServerStream) error {
+		return stream.RecvMsg(&vtctldatapb.ExecuteVtctlCommandRequest{})
+	}
+	streamInfo := &grpc.StreamServerInfo{FullMethod: "/vtctlservice.Vtctl/ExecuteVtctlCommand"}
+
+	err = i.stream(nil, &fakeServerStream{ctx: context.Background(), args: []string{"DeleteKeyspace", "ks"}}, streamInfo, streamHandler)
+	assert.NoError(t, err)
+
+	err = i.stream(nil, &fakeServerStream{ctx: context.Background(), args: []string{"DeleteKeyspace", "--recursive", "ks"}}, streamInfo, streamHandler)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	require.Len(t, approver.requests, 3)
+	assert.Equal(t, "ks", approver.requests[2].Target)
+	assert.JSONEq(t, `["--recursive","ks"]`, string(approver.requests[2].Request))
+}
+
+func TestCheck(t *testing.T) {
+	defer func() { defaultApprover = nil }()
+
+	ctx := context.Background()
+	req := &vtctldatapb.DeleteKeyspaceRequest{Keyspace: "ks", Recursive: true}
+
+	// Without a hook, every command runs.
+	assert.NoError(t, Check(ctx, audit.Caller{}, "", "DeleteKeyspace", req))
+
+	defaultApprover = &fakeApprover{}
+	err := Check(ctx, audit.Caller{}, "", "DeleteKeyspace", req)
+	assert.Equal(t, vtrpcpb.Code_PERMISSION_DENIED, vterrors.Code(err))
+	assert.NoError(t, Check(ctx, audit.Caller{}, "approved", "DeleteKeyspace", req))
+
+	assert.NoError(t, Check(ctx, audit.Caller{}, "", "GetKeyspaces", &vtctldatapb.GetKeyspacesRequest{}))
+}
+
+// fakeServerStream is a grpc.ServerStream that receives a single
+// ExecuteVtctlCommandRequest.
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	args []string
+}
+
+func (s *fakeServerStream) Context() context.Context { return s.ctx }
+
+func (s *fakeServerStream) RecvMsg(m any) error {
+	m.(*vtctldatapb.ExecuteVtctlCommandRequest).Args = s.args
+	return nil
+}
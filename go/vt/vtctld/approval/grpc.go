@@ -0,0 +1,140 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approval
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"vitess.io/vitess/go/vt/vtctld/audit"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// TokenMetadataKey is the gRPC metadata key, and the HTTP header, under which
+// clients send an approval token.
+const TokenMetadataKey = "vtctld-approval-token"
+
+// servicePrefix selects the gRPC methods that may require approval: those of
+// both the Vtctl and the Vtctld services.
+const servicePrefix = "/vtctlservice."
+
+// TokenFromContext returns the approval token sent by the client of a gRPC call.
+func TokenFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	if values := md.Get(TokenMetadataKey); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// interceptor enforces an Approver on gRPC calls.
+type interceptor struct {
+	approver Approver
+}
+
+func (i *interceptor) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if !strings.HasPrefix(info.FullMethod, servicePrefix) {
+		return handler(ctx, req)
+	}
+
+	if err := i.approve(ctx, info.FullMethod, req); err != nil {
+		return nil, vterrors.ToGRPC(err)
+	}
+
+	return handler(ctx, req)
+}
+
+func (i *interceptor) stream(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if !strings.HasPrefix(info.FullMethod, servicePrefix) {
+		return handler(srv, stream)
+	}
+
+	// The command to run is only known once the request is received, so the
+	// approval happens when the handler reads it.
+	return handler(srv, &approvingStream{
+		ServerStream: stream,
+		interceptor:  i,
+		method:       info.FullMethod,
+	})
+}
+
+// approve gets approval for the RPC or, for the Vtctl service and
+// ScheduleCommand, for the commands in the request.
+func (i *interceptor) approve(ctx context.Context, method string, req any) error {
+	caller := audit.CallerFromContext(ctx)
+	token := TokenFromContext(ctx)
+
+	switch req := req.(type) {
+	case *vtctldatapb.ExecuteVtctlCommandRequest:
+		return i.approveArgs(ctx, caller, token, req.Args)
+	case *vtctldatapb.ExecuteVtctlCommandBatchRequest:
+		for _, cmd := range req.Commands {
+			if err := i.approveArgs(ctx, caller, token, cmd.Args); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *vtctldatapb.ScheduleCommandRequest:
+		// The approval is checked when the command is scheduled, as nobody is
+		// around to provide a token when it runs.
+		return i.approveArgs(ctx, caller, token, req.Args)
+	default:
+		return check(ctx, i.approver, caller, token, method[strings.LastIndex(method, "/")+1:], req)
+	}
+}
+
+func (i *interceptor) approveArgs(ctx context.Context, caller audit.Caller, token string, args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+
+	return check(ctx, i.approver, caller, token, args[0], args[1:])
+}
+
+// approvingStream is a grpc.ServerStream that gets approval for the first
+// message it receives.
+type approvingStream struct {
+	grpc.ServerStream
+	interceptor *interceptor
+	method      string
+	approved    bool
+}
+
+func (s *approvingStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	if !s.approved {
+		if err := s.interceptor.approve(s.Context(), s.method, m); err != nil {
+			return vterrors.ToGRPC(err)
+		}
+		s.approved = true
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approval
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// TokenApprover approves a command if the caller sent a valid token issued by
+// one of the approvers, other than the caller themselves, for that command and
+// target. This implements a two-person rule: whoever runs a high-risk command
+// needs someone else to sign off on it.
+//
+// Tokens are created with NewToken, using the secret of the approver, and
+// expire.
+type TokenApprover struct {
+	secrets map[string]string
+}
+
+// tokenApproversFile is the format of the approvers file of the TokenApprover.
+type tokenApproversFile struct {
+	Approvers []*struct {
+		Name   string
+		Secret string
+	}
+}
+
+// NewTokenApprover returns a TokenApprover for the approvers listed in the file
+// at path. Any file format supported by viper is supported: yaml, json or
+// toml. The file lists the name and secret of each approver:
+//
+//	approvers:
+//	  - name: alice
+//	    secret: <a long random string>
+//
+// The name of an approver is compared to the username and principal of the
+// caller to prevent self-approval.
+func NewTokenApprover(path string) (Approver, error) {
+	if path == "" {
+		return nil, errors.New("the token approval hook requires --approval-hook-config to be set to the path of the approvers file")
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	var file tokenApproversFile
+	if err := v.UnmarshalExact(&file); err != nil {
+		return nil, err
+	}
+
+	secrets := make(map[string]string, len(file.Approvers))
+	for i, approver := range file.Approvers {
+		switch {
+		case approver.Name == "" || strings.Contains(approver.Name, ":"):
+			return nil, fmt.Errorf("approver %d: name must be set, and cannot contain a colon", i)
+		case approver.Secret == "":
+			return nil, fmt.Errorf("approver %s: secret must be set", approver.Name)
+		}
+		if _, ok := secrets[approver.Name]; ok {
+			return nil, fmt.Errorf("approver %s is listed more than once", approver.Name)
+		}
+
+		secrets[approver.Name] = approver.Secret
+	}
+
+	return &TokenApprover{secrets: secrets}, nil
+}
+
+// NewToken returns a token approving the command on the given target until
+// expiry, signed with the approver's secret. The token has the form
+// <approver>:<expiry as a unix timestamp>:<signature>.
+func NewToken(approver string, secret string, command string, target string, expiry time.Time) string {
+	expires := strconv.FormatInt(expiry.Unix(), 10)
+	return approver + ":" + expires + ":" + signToken(secret, approver, expires, command, target)
+}
+
+func signToken(secret string, approver string, expires string, command string, target string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.Join([]string{approver, expires, strings.ToLower(command), target}, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Approve is part of the Approver interface.
+func (a *TokenApprover) Approve(ctx context.Context, req *Request) error {
+	deny := func(format string, args ...any) error {
+		return vterrors.Errorf(vtrpcpb.Code_PERMISSION_DENIED, "%s on %s requires an approval token: %s", req.Command, req.Target, fmt.Sprintf(format, args...))
+	}
+
+	if req.Token == "" {
+		return deny("no token was sent, ask an approver for one")
+	}
+
+	parts := strings.Split(req.Token, ":")
+	if len(parts) != 3 {
+		return deny("the token is malformed")
+	}
+	approver, expires, signature := parts[0], parts[1], parts[2]
+
+	secret, ok := a.secrets[approver]
+	if !ok {
+		return deny("%s is not an approver", approver)
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(signToken(secret, approver, expires, req.Command, req.Target))) {
+		return deny("the token was not issued by %s for this command and target", approver)
+	}
+
+	expiry, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return deny("the token is malformed")
+	}
+	if time.Now().Unix() > expiry {
+		return deny("the token expired at %s", time.Unix(expiry, 0).UTC().Format(time.RFC3339))
+	}
+
+	if approver == req.Caller.Username || approver == req.Caller.Principal {
+		return deny("%s cannot approve their own command", approver)
+	}
+
+	return nil
+}
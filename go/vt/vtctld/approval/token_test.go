@@ -0,0 +1,152 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approval
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/vtctld/audit"
+)
+
+func TestTokenApprover(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "approvers.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+approvers:
+  - name: alice
+    secret: alice-secret
+  - name: bob
+    secret: bob-secret
+`), 0600))
+
+	approver, err := NewTokenApprover(path)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	expiry := time.Now().Add(time.Hour)
+	request := func(caller string, token string) *Request {
+		return &Request{
+			Caller:  audit.Caller{Username: caller},
+			Command: "EmergencyReparentShard",
+			Target:  "ks/-80",
+			Token:   token,
+		}
+	}
+
+	tests := []struct {
+		name          string
+		req           *Request
+		expectedError string
+	}{
+		{
+			name: "approved by someone else",
+			req:  request("carol", NewToken("bob", "bob-secret", "emergencyreparentshard", "ks/-80", expiry)),
+		},
+		{
+			name:          "no token",
+			req:           request("carol", ""),
+			expectedError: "no token was sent",
+		},
+		{
+			name:          "malformed token",
+			req:           request("carol", "bob"),
+			expectedError: "the token is malformed",
+		},
+		{
+			name:          "unknown approver",
+			req:           request("carol", NewToken("mallory", "bob-secret", "EmergencyReparentShard", "ks/-80", expiry)),
+			expectedError: "mallory is not an approver",
+		},
+		{
+			name:          "wrong secret",
+			req:           request("carol", NewToken("bob", "alice-secret", "EmergencyReparentShard", "ks/-80", expiry)),
+			expectedError: "the token was not issued by bob for this command and target",
+		},
+		{
+			name:          "other target",
+			req:           request("carol", NewToken("bob", "bob-secret", "EmergencyReparentShard", "ks/80-", expiry)),
+			expectedError: "the token was not issued by bob for this command and target",
+		},
+		{
+			name:          "expired",
+			req:           request("carol", NewToken("bob", "bob-secret", "EmergencyReparentShard", "ks/-80", time.Now().Add(-time.Minute))),
+			expectedError: "the token expired",
+		},
+		{
+			name:          "self approval",
+			req:           request("bob", NewToken("bob", "bob-secret", "EmergencyReparentShard", "ks/-80", expiry)),
+			expectedError: "bob cannot approve their own command",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := approver.Approve(ctx, tt.req)
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorContains(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestNewTokenApproverErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name     string
+		contents string
+	}{
+		{
+			name:     "missing secret",
+			contents: "approvers: [{name: alice}]",
+		},
+		{
+			name:     "missing name",
+			contents: "approvers: [{secret: s}]",
+		},
+		{
+			name:     "duplicate approver",
+			contents: "approvers: [{name: alice, secret: s1}, {name: alice, secret: s2}]",
+		},
+		{
+			name:     "unknown field",
+			contents: "approvers: [{name: alice, secret: s, team: dba}]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, "approvers.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.contents), 0600))
+
+			_, err := NewTokenApprover(path)
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := NewTokenApprover("")
+	assert.Error(t, err)
+}
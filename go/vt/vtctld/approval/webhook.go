@@ -0,0 +1,92 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approval
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// webhookTimeout bounds how long the webhook may take to decide on a request,
+// which may include waiting for a human to approve it.
+const webhookTimeout = 5 * time.Minute
+
+// maxReasonLength bounds how much of a denial's response body gets returned to
+// the caller.
+const maxReasonLength = 1024
+
+// WebhookApprover POSTs each Request, as JSON, to a URL. A 2xx response status
+// approves the command; any other status denies it, and the response body is
+// returned to the caller as the reason.
+type WebhookApprover struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookApprover returns a WebhookApprover that posts to the given URL.
+func NewWebhookApprover(url string) (Approver, error) {
+	if url == "" {
+		return nil, errors.New("the webhook approval hook requires --approval-hook-config to be set to a URL")
+	}
+
+	return &WebhookApprover{
+		url:    url,
+		client: &http.Client{Timeout: webhookTimeout},
+	}, nil
+}
+
+// Approve is part of the Approver interface.
+func (a *WebhookApprover) Approve(ctx context.Context, req *Request) error {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(httpReq)
+	if err != nil {
+		return vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "cannot get approval for %s on %s: %v", req.Command, req.Target, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxReasonLength))
+	reason := strings.TrimSpace(string(body))
+	if reason == "" {
+		reason = resp.Status
+	}
+
+	return vterrors.Errorf(vtrpcpb.Code_PERMISSION_DENIED, "%s on %s was not approved: %s", req.Command, req.Target, reason)
+}
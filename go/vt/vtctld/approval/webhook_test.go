@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approval
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/vtctld/audit"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+func TestWebhookApprover(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		req := &Request{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		switch req.Target {
+		case "ks/-":
+			w.WriteHeader(http.StatusOK)
+		case "ks/-80":
+			http.Error(w, "change CHG-1234 is not approved yet", http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	approver, err := NewWebhookApprover(server.URL)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	caller := audit.Caller{Username: "alice"}
+
+	assert.NoError(t, approver.Approve(ctx, &Request{Caller: caller, Command: "EmergencyReparentShard", Target: "ks/-"}))
+
+	err = approver.Approve(ctx, &Request{Caller: caller, Command: "EmergencyReparentShard", Target: "ks/-80"})
+	assert.Equal(t, vtrpcpb.Code_PERMISSION_DENIED, vterrors.Code(err))
+	assert.ErrorContains(t, err, "EmergencyReparentShard on ks/-80 was not approved: change CHG-1234 is not approved yet")
+
+	err = approver.Approve(ctx, &Request{Caller: caller, Command: "DeleteKeyspace", Target: "ks"})
+	assert.ErrorContains(t, err, "DeleteKeyspace on ks was not approved: 403 Forbidden")
+
+	_, err = NewWebhookApprover("")
+	assert.Error(t, err)
+}
+
+func TestWebhookApproverUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	approver, err := NewWebhookApprover(server.URL)
+	require.NoError(t, err)
+
+	err = approver.Approve(context.Background(), &Request{Command: "DeleteKeyspace", Target: "ks"})
+	assert.Equal(t, vtrpcpb.Code_UNAVAILABLE, vterrors.Code(err))
+}
@@ -0,0 +1,382 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package audit records the commands and RPCs run against a vtctld to a
+pluggable sink, so operators have a trail of who ran what against the cluster.
+
+Each Entry holds the hash of the previous entry, and its own hash covers
+both its contents and that previous hash. Hashes are HMAC-SHA256 keyed with
+the secret of --audit-log-key-file, which the sink never sees, so whoever can
+write to the sink cannot forge entries. Modifying, removing or reordering
+entries in a sink therefore breaks the chain, which Verify detects.
+
+The Head of the chain, that is the sequence and hash of its latest entry, is
+signed with the same key and kept in --audit-log-head-file, apart from the
+sink. VerifyHead checks that a log ends at that head, which detects removed
+trailing entries. A restarted vtctld continues the chain from its head.
+
+Sinks are registered by name with RegisterSink, and selected with the
+--audit-log-sink and --audit-log-target flags.
+*/
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/topo"
+)
+
+const (
+	// StatusOK is the Entry status of a command or RPC that succeeded.
+	StatusOK = "ok"
+	// StatusError is the Entry status of a command or RPC that failed.
+	StatusError = "error"
+)
+
+// Caller identifies who ran an audited command or RPC, as far as the vtctld
+// can tell.
+type Caller struct {
+	// Username is the username authenticated by the gRPC static auth plugin.
+	Username string `json:"username,omitempty"`
+	// Principal is the principal of the bearer token the client sent, once
+	// verified by the TokenVerifier set with SetTokenVerifier.
+	Principal string `json:"principal,omitempty"`
+	// CertSubject is the subject of the client's TLS certificate, if any.
+	CertSubject string `json:"cert_subject,omitempty"`
+	// Peer is the network address of the client.
+	Peer string `json:"peer,omitempty"`
+	// Claims are the claims of the verified bearer token, if any. They are
+	// used for authorization, but not recorded in the audit log.
+	Claims map[string][]string `json:"-"`
+}
+
+// String describes the caller by the strongest identity it has.
+func (c Caller) String() string {
+	switch {
+	case c.Username != "":
+		return "user " + c.Username
+	case c.Principal != "":
+		return "principal " + c.Principal
+	case c.CertSubject != "":
+		return "certificate " + c.CertSubject
+	default:
+		return "unauthenticated caller"
+	}
+}
+
+// Entry is a single record of the audit log.
+type Entry struct {
+	// Sequence is the position of the entry in its chain, starting at 1.
+	Sequence uint64 `json:"sequence"`
+	// Time is when the command or RPC started.
+	Time   time.Time `json:"time"`
+	Caller Caller    `json:"caller"`
+	// Method is the full gRPC method name, or the HTTP API path, that was
+	// called.
+	Method string `json:"method"`
+	// Request holds the arguments of the call, as JSON.
+	Request json.RawMessage `json:"request,omitempty"`
+	// Status is either StatusOK or StatusError.
+	Status string `json:"status"`
+	// Error is the error returned by the call, if any.
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+	// PrevHash is the Hash of the previous entry of the chain.
+	PrevHash string `json:"prev_hash"`
+	// Hash is the hex-encoded HMAC-SHA256 of the entry, keyed with the audit
+	// log key and computed with an empty Hash field.
+	Hash string `json:"hash"`
+}
+
+// computeHash returns the Hash the entry should have, given the audit log key.
+func (e *Entry) computeHash(key []byte) (string, error) {
+	unhashed := *e
+	unhashed.Hash = ""
+
+	data, err := json.Marshal(&unhashed)
+	if err != nil {
+		return "", err
+	}
+
+	return sign(key, data), nil
+}
+
+// sign returns the hex-encoded HMAC-SHA256 of data.
+func sign(key []byte, data []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks that the entries form an untampered chain, in order, given
+// the audit log key. It does not require the chain to start at its first
+// entry, so it can be used on the tail of a log. Use VerifyHead to also
+// detect removed trailing entries.
+func Verify(key []byte, entries []*Entry) error {
+	for i, e := range entries {
+		hash, err := e.computeHash(key)
+		if err != nil {
+			return fmt.Errorf("cannot hash entry %d: %w", e.Sequence, err)
+		}
+		if !hmac.Equal([]byte(hash), []byte(e.Hash)) {
+			return fmt.Errorf("entry %d has been modified or was not signed with this key", e.Sequence)
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		prev := entries[i-1]
+		if e.Sequence != prev.Sequence+1 || e.PrevHash != prev.Hash {
+			return fmt.Errorf("chain is broken between entries %d and %d", prev.Sequence, e.Sequence)
+		}
+	}
+
+	return nil
+}
+
+// Sink is where audit entries get written to.
+type Sink interface {
+	// Write records a single entry. Entries are written in order, one at a
+	// time.
+	Write(ctx context.Context, e *Entry) error
+	// Close flushes and releases the resources of the sink.
+	Close() error
+}
+
+// SinkFactory creates a Sink, given the value of --audit-log-target.
+type SinkFactory func(ts *topo.Server, target string) (Sink, error)
+
+var sinkFactories = map[string]SinkFactory{}
+
+// RegisterSink registers a SinkFactory under the given name, to be selected
+// with --audit-log-sink.
+func RegisterSink(name string, factory SinkFactory) {
+	if _, ok := sinkFactories[name]; ok {
+		log.Fatalf("audit sink %s already registered", name)
+	}
+
+	sinkFactories[name] = factory
+}
+
+var writeErrors = stats.NewCounter("AuditLogWriteErrors", "Number of audit log entries that could not be written to the sink")
+
+// Logger chains audit entries together and writes them to a Sink.
+type Logger struct {
+	sink     Sink
+	key      []byte
+	headFile string
+
+	m        sync.Mutex
+	sequence uint64
+	lastHash string
+}
+
+// NewLogger returns a Logger that writes to the given sink, keying the chain
+// with key, and keeping its signed Head in headFile. If headFile already
+// holds a head, the chain continues from it.
+func NewLogger(sink Sink, key []byte, headFile string) (*Logger, error) {
+	if len(key) == 0 {
+		return nil, errors.New("the audit log key cannot be empty")
+	}
+	if headFile == "" {
+		return nil, errors.New("the audit log requires a head file")
+	}
+
+	l := &Logger{
+		sink:     sink,
+		key:      key,
+		headFile: headFile,
+	}
+
+	head, err := ReadHead(headFile)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return l, nil
+	case err != nil:
+		return nil, err
+	}
+
+	if err := head.verify(key); err != nil {
+		return nil, fmt.Errorf("cannot continue the audit log chain from %s: %w", headFile, err)
+	}
+
+	l.sequence = head.Sequence
+	l.lastHash = head.Hash
+	return l, nil
+}
+
+// Log adds the outcome of a call to the audit log. Errors writing to the sink
+// are logged and counted, but not returned, so that a failing sink does not
+// fail the calls it audits.
+func (l *Logger) Log(ctx context.Context, caller Caller, method string, request json.RawMessage, start time.Time, callErr error) {
+	e := &Entry{
+		Time:     start.UTC(),
+		Caller:   caller,
+		Method:   method,
+		Request:  request,
+		Status:   StatusOK,
+		Duration: time.Since(start).String(),
+	}
+	if callErr != nil {
+		e.Status = StatusError
+		e.Error = callErr.Error()
+	}
+
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	e.Sequence = l.sequence + 1
+	e.PrevHash = l.lastHash
+
+	hash, err := e.computeHash(l.key)
+	if err != nil {
+		writeErrors.Add(1)
+		log.Errorf("cannot hash audit entry for %s: %v", method, err)
+		return
+	}
+	e.Hash = hash
+
+	if err := l.sink.Write(ctx, e); err != nil {
+		writeErrors.Add(1)
+		log.Errorf("cannot write audit entry %d for %s: %v", e.Sequence, method, err)
+		return
+	}
+
+	// Only advance the chain once the entry made it to the sink, so that a
+	// transient sink error does not look like a removed entry.
+	l.sequence = e.Sequence
+	l.lastHash = e.Hash
+
+	if err := writeHead(l.headFile, newHead(l.key, e)); err != nil {
+		writeErrors.Add(1)
+		log.Errorf("cannot write the audit log head for entry %d: %v", e.Sequence, err)
+	}
+}
+
+// Close closes the underlying sink.
+func (l *Logger) Close() error {
+	return l.sink.Close()
+}
+
+var (
+	sinkName string
+	target   string
+	keyFile  string
+	headFile string
+
+	defaultLogger *Logger
+)
+
+func init() {
+	for _, cmd := range []string{"vtcombo", "vtctld"} {
+		servenv.OnParseFor(cmd, registerFlags)
+	}
+}
+
+func registerFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&sinkName, "audit-log-sink", sinkName, fmt.Sprintf("Name of the sink to write an audit log of every vtctl command and vtctld RPC to. Leave empty to disable the audit log. Valid values are: %s.", strings.Join(sinkNames(), ", ")))
+	fs.StringVar(&target, "audit-log-target", target, "Where the audit log sink writes to: a file path for the file sink, a tag for the syslog sink, a path in the global topo for the topo sink, and a URL for the webhook sink.")
+	fs.StringVar(&keyFile, "audit-log-key-file", keyFile, "File holding the secret key the audit log entries are signed with. Required with --audit-log-sink. The key must not be readable from the audit log sink.")
+	fs.StringVar(&headFile, "audit-log-head-file", headFile, "File the vtctld keeps the signed head of the audit log chain in, to detect removed trailing entries and to continue the chain across restarts. Required with --audit-log-sink. It must be kept apart from the audit log sink.")
+}
+
+func sinkNames() []string {
+	names := make([]string, 0, len(sinkFactories))
+	for name := range sinkFactories {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// Init creates the audit logger selected by --audit-log-sink, and installs the
+// gRPC interceptors that feed it. It is a no-op if the audit log is disabled.
+// It must be called before servenv.Run.
+func Init(ts *topo.Server) error {
+	if sinkName == "" {
+		return nil
+	}
+
+	factory, ok := sinkFactories[sinkName]
+	if !ok {
+		return fmt.Errorf("unknown audit log sink %q, valid values are: %s", sinkName, strings.Join(sinkNames(), ", "))
+	}
+
+	if keyFile == "" || headFile == "" {
+		return errors.New("the audit log requires --audit-log-key-file and --audit-log-head-file")
+	}
+	key, err := os.ReadFile(keyFile)
+	if err != nil {
+		return fmt.Errorf("cannot read the audit log key: %w", err)
+	}
+
+	sink, err := factory(ts, target)
+	if err != nil {
+		return fmt.Errorf("cannot create %s audit log sink: %w", sinkName, err)
+	}
+
+	defaultLogger, err = NewLogger(sink, bytes.TrimSpace(key), headFile)
+	if err != nil {
+		sink.Close()
+		return err
+	}
+	servenv.AddGRPCServerInterceptors(defaultLogger.StreamServerInterceptor, defaultLogger.UnaryServerInterceptor)
+	servenv.OnClose(func() {
+		if err := defaultLogger.Close(); err != nil {
+			log.Errorf("cannot close audit log sink: %v", err)
+		}
+	})
+
+	log.Infof("audit log enabled with the %s sink", sinkName)
+	return nil
+}
+
+// Record adds the outcome of a call that does not go through gRPC, such as a
+// vtctld HTTP API call, to the audit log. It is a no-op if the audit log is
+// disabled.
+func Record(ctx context.Context, caller Caller, method string, request any, start time.Time, callErr error) {
+	if defaultLogger == nil {
+		return
+	}
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		data = nil
+		log.Warningf("cannot marshal audit request for %s: %v", method, err)
+	}
+
+	defaultLogger.Log(ctx, caller, method, data, start, callErr)
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySink records entries in memory, and fails writes while err is set.
+type memorySink struct {
+	m       sync.Mutex
+	entries []*Entry
+	err     error
+}
+
+func (s *memorySink) Write(ctx context.Context, e *Entry) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+
+	s.entries = append(s.entries, e)
+	return nil
+}
+
+func (s *memorySink) Close() error { return nil }
+
+var testKey = []byte("audit-log-key")
+
+// newTestLogger returns a Logger keyed with testKey, with its head in a
+// temporary directory.
+func newTestLogger(t *testing.T, sink Sink) *Logger {
+	logger, err := NewLogger(sink, testKey, filepath.Join(t.TempDir(), "head"))
+	require.NoError(t, err)
+	return logger
+}
+
+func TestLoggerChain(t *testing.T) {
+	ctx := context.Background()
+	sink := &memorySink{}
+	logger := newTestLogger(t, sink)
+
+	logger.Log(ctx, Caller{Username: "alice"}, "/vtctlservice.Vtctld/GetKeyspace", json.RawMessage(`{"keyspace":"ks"}`), time.Now(), nil)
+
+	// A failed write must not leave a gap in the chain.
+	sink.err = errors.New("sink is down")
+	logger.Log(ctx, Caller{Username: "alice"}, "/vtctlservice.Vtctld/GetKeyspace", nil, time.Now(), nil)
+	sink.err = nil
+
+	logger.Log(ctx, Caller{Username: "bob"}, "/vtctlservice.Vtctld/DeleteKeyspace", json.RawMessage(`{"keyspace":"ks"}`), time.Now(), errors.New("keyspace is not empty"))
+
+	require.Len(t, sink.entries, 2)
+	assert.Equal(t, uint64(1), sink.entries[0].Sequence)
+	assert.Empty(t, sink.entries[0].PrevHash)
+	assert.Equal(t, StatusOK, sink.entries[0].Status)
+	assert.Equal(t, uint64(2), sink.entries[1].Sequence)
+	assert.Equal(t, sink.entries[0].Hash, sink.entries[1].PrevHash)
+	assert.Equal(t, StatusError, sink.entries[1].Status)
+	assert.Equal(t, "keyspace is not empty", sink.entries[1].Error)
+
+	assert.NoError(t, Verify(testKey, sink.entries))
+}
+
+func TestVerify(t *testing.T) {
+	newChain := func() []*Entry {
+		sink := &memorySink{}
+		logger := newTestLogger(t, sink)
+		for i := 0; i < 3; i++ {
+			logger.Log(context.Background(), Caller{Peer: "127.0.0.1:1234"}, "/vtctlservice.Vtctl/ExecuteVtctlCommand", json.RawMessage(`{"args":["ListAllTablets"]}`), time.Now(), nil)
+		}
+
+		return sink.entries
+	}
+
+	tests := []struct {
+		name    string
+		tamper  func(entries []*Entry) []*Entry
+		wantErr string
+	}{
+		{
+			name:   "untampered",
+			tamper: func(entries []*Entry) []*Entry { return entries },
+		},
+		{
+			name:   "tail of the chain",
+			tamper: func(entries []*Entry) []*Entry { return entries[1:] },
+		},
+		{
+			name: "modified entry",
+			tamper: func(entries []*Entry) []*Entry {
+				entries[1].Caller.Username = "someone-else"
+				return entries
+			},
+			wantErr: "entry 2 has been modified",
+		},
+		{
+			name: "entry rehashed without the key",
+			tamper: func(entries []*Entry) []*Entry {
+				entries[1].Caller.Username = "someone-else"
+				entries[1].Hash, _ = entries[1].computeHash([]byte("another-key"))
+				entries[2].PrevHash = entries[1].Hash
+				return entries
+			},
+			wantErr: "entry 2 has been modified",
+		},
+		{
+			name: "removed entry",
+			tamper: func(entries []*Entry) []*Entry {
+				return []*Entry{entries[0], entries[2]}
+			},
+			wantErr: "chain is broken between entries 1 and 3",
+		},
+		{
+			name: "reordered entries",
+			tamper: func(entries []*Entry) []*Entry {
+				return []*Entry{entries[0], entries[2], entries[1]}
+			},
+			wantErr: "chain is broken between entries 1 and 3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(testKey, tt.tamper(newChain()))
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestVerifyHead(t *testing.T) {
+	ctx := context.Background()
+	sink := &memorySink{}
+	headFile := filepath.Join(t.TempDir(), "head")
+	logger, err := NewLogger(sink, testKey, headFile)
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		logger.Log(ctx, Caller{Username: "alice"}, "/vtctlservice.Vtctld/GetKeyspace", nil, time.Now(), nil)
+	}
+
+	head, err := ReadHead(headFile)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), head.Sequence)
+
+	assert.NoError(t, VerifyHead(testKey, sink.entries, head))
+	assert.NoError(t, VerifyHead(testKey, sink.entries[1:], head), "the tail of the log should end at the head")
+	assert.ErrorContains(t, VerifyHead(testKey, sink.entries[:2], head), "the log has been truncated: it ends at entry 2, but its head is at entry 3")
+	assert.ErrorContains(t, VerifyHead(testKey, nil, head), "the log is empty")
+	assert.ErrorContains(t, VerifyHead([]byte("another-key"), sink.entries, head), "the head has been modified")
+
+	forged := *head
+	forged.Sequence, forged.Hash = 2, sink.entries[1].Hash
+	assert.ErrorContains(t, VerifyHead(testKey, sink.entries[:2], &forged), "the head has been modified")
+}
+
+func TestLoggerContinuesChain(t *testing.T) {
+	ctx := context.Background()
+	sink := &memorySink{}
+	headFile := filepath.Join(t.TempDir(), "head")
+
+	logger, err := NewLogger(sink, testKey, headFile)
+	require.NoError(t, err)
+	logger.Log(ctx, Caller{Username: "alice"}, "/vtctlservice.Vtctld/GetKeyspace", nil, time.Now(), nil)
+
+	// A restarted vtctld continues the chain from its head.
+	logger, err = NewLogger(sink, testKey, headFile)
+	require.NoError(t, err)
+	logger.Log(ctx, Caller{Username: "alice"}, "/vtctlservice.Vtctld/GetKeyspace", nil, time.Now(), nil)
+
+	require.Len(t, sink.entries, 2)
+	assert.Equal(t, uint64(2), sink.entries[1].Sequence)
+	assert.Equal(t, sink.entries[0].Hash, sink.entries[1].PrevHash)
+
+	head, err := ReadHead(headFile)
+	require.NoError(t, err)
+	assert.NoError(t, VerifyHead(testKey, sink.entries, head))
+
+	// But not from a head it did not sign.
+	_, err = NewLogger(sink, []byte("another-key"), headFile)
+	assert.ErrorContains(t, err, "cannot continue the audit log chain")
+
+	require.NoError(t, os.WriteFile(headFile, []byte("{}"), 0o600))
+	_, err = NewLogger(sink, testKey, headFile)
+	assert.ErrorContains(t, err, "the head has been modified")
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+
+	"vitess.io/vitess/go/vt/topo"
+)
+
+func init() {
+	RegisterSink("file", func(ts *topo.Server, target string) (Sink, error) {
+		return NewFileSink(target)
+	})
+}
+
+// FileSink appends entries to a file, one JSON document per line.
+type FileSink struct {
+	m sync.Mutex
+	f *os.File
+}
+
+// NewFileSink returns a FileSink that appends to the file at the given path,
+// creating it if needed.
+func NewFileSink(path string) (*FileSink, error) {
+	if path == "" {
+		return nil, errors.New("the file audit log sink requires --audit-log-target to be set to a file path")
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileSink{f: f}, nil
+}
+
+// Write is part of the Sink interface.
+func (s *FileSink) Write(ctx context.Context, e *Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	_, err = s.f.Write(append(data, '\n'))
+	return err
+}
+
+// Close is part of the Sink interface.
+func (s *FileSink) Close() error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.f.Close()
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	sink, err := NewFileSink(path)
+	require.NoError(t, err)
+
+	logger := newTestLogger(t, sink)
+	for _, keyspace := range []string{"ks1", "ks2"} {
+		logger.Log(context.Background(), Caller{Username: "alice"}, "/vtctlservice.Vtctld/CreateKeyspace", json.RawMessage(`{"name": "`+keyspace+`"}`), time.Now(), nil)
+	}
+	require.NoError(t, logger.Close())
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var entries []*Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		e := &Entry{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), e))
+		entries = append(entries, e)
+	}
+	require.NoError(t, scanner.Err())
+
+	require.Len(t, entries, 2)
+	assert.JSONEq(t, `{"name":"ks2"}`, string(entries[1].Request))
+	assert.NoError(t, Verify(testKey, entries), "entries read back from the file should form a valid chain")
+
+	_, err = NewFileSink("")
+	assert.Error(t, err)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/vt/servenv"
+)
+
+// auditedServicePrefix selects the gRPC methods that get audited: those of
+// both the Vtctl and the Vtctld services.
+const auditedServicePrefix = "/vtctlservice."
+
+// CallerFromContext returns the Caller of a gRPC call. Its principal and
+// claims come from the bearer token in the "authorization" metadata.
+func CallerFromContext(ctx context.Context) Caller {
+	caller := Caller{
+		Username: servenv.StaticAuthUsernameFromContext(ctx),
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, v := range md.Get("authorization") {
+		if token, ok := bearerToken(v); ok {
+			caller.Principal, caller.Claims = verifyToken(ctx, token)
+			break
+		}
+	}
+
+	if p, ok := peer.FromContext(ctx); ok {
+		if p.Addr != nil {
+			caller.Peer = p.Addr.String()
+		}
+
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.PeerCertificates) > 0 {
+			caller.CertSubject = tlsInfo.State.PeerCertificates[0].Subject.String()
+		}
+	}
+
+	return caller
+}
+
+func marshalRequest(req any) json.RawMessage {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return nil
+	}
+
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	if err != nil {
+		return nil
+	}
+
+	return data
+}
+
+// UnaryServerInterceptor audits the unary RPCs of the vtctl services.
+func (l *Logger) UnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if !strings.HasPrefix(info.FullMethod, auditedServicePrefix) {
+		return handler(ctx, req)
+	}
+
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	l.Log(ctx, CallerFromContext(ctx), info.FullMethod, marshalRequest(req), start, err)
+
+	return resp, err
+}
+
+// StreamServerInterceptor audits the streaming RPCs of the vtctl services, such
+// as ExecuteVtctlCommand. The request recorded is the first message received
+// from the client.
+func (l *Logger) StreamServerInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if !strings.HasPrefix(info.FullMethod, auditedServicePrefix) {
+		return handler(srv, stream)
+	}
+
+	start := time.Now()
+	recorder := &requestRecorder{ServerStream: stream}
+	err := handler(srv, recorder)
+	l.Log(stream.Context(), CallerFromContext(stream.Context()), info.FullMethod, recorder.request, start, err)
+
+	return err
+}
+
+// requestRecorder is a grpc.ServerStream that keeps a copy of the first
+// message it receives.
+type requestRecorder struct {
+	grpc.ServerStream
+	request json.RawMessage
+}
+
+func (r *requestRecorder) RecvMsg(m any) error {
+	if err := r.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	if r.request == nil {
+		r.request = marshalRequest(m)
+	}
+
+	return nil
+}
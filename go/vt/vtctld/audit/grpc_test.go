@@ -0,0 +1,95 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	sink := &memorySink{}
+	logger := newTestLogger(t, sink)
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 4567}})
+	req := &vtctldatapb.DeleteKeyspaceRequest{Keyspace: "ks", Recursive: true}
+	wantErr := errors.New("keyspace is locked")
+
+	_, err := logger.UnaryServerInterceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: "/vtctlservice.Vtctld/DeleteKeyspace"}, func(ctx context.Context, req any) (any, error) {
+		return nil, wantErr
+	})
+	assert.Equal(t, wantErr, err)
+
+	// Methods of other services are not audited.
+	_, err = logger.UnaryServerInterceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, func(ctx context.Context, req any) (any, error) {
+		return nil, nil
+	})
+	assert.NoError(t, err)
+
+	require.Len(t, sink.entries, 1)
+	e := sink.entries[0]
+	assert.Equal(t, "/vtctlservice.Vtctld/DeleteKeyspace", e.Method)
+	assert.Equal(t, "10.0.0.1:4567", e.Caller.Peer)
+	assert.JSONEq(t, `{"keyspace":"ks","recursive":true}`, string(e.Request))
+	assert.Equal(t, StatusError, e.Status)
+	assert.Equal(t, "keyspace is locked", e.Error)
+}
+
+// fakeServerStream is a grpc.ServerStream that receives a single request.
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	req *vtctldatapb.ExecuteVtctlCommandRequest
+}
+
+func (s *fakeServerStream) Context() context.Context { return s.ctx }
+
+func (s *fakeServerStream) RecvMsg(m any) error {
+	m.(*vtctldatapb.ExecuteVtctlCommandRequest).Args = s.req.Args
+	return nil
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	sink := &memorySink{}
+	logger := newTestLogger(t, sink)
+
+	stream := &fakeServerStream{
+		ctx: context.Background(),
+		req: &vtctldatapb.ExecuteVtctlCommandRequest{Args: []string{"DeleteTablet", "zone1-100"}},
+	}
+
+	err := logger.StreamServerInterceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: "/vtctlservice.Vtctl/ExecuteVtctlCommand"}, func(srv any, stream grpc.ServerStream) error {
+		req := &vtctldatapb.ExecuteVtctlCommandRequest{}
+		return stream.RecvMsg(req)
+	})
+	require.NoError(t, err)
+
+	require.Len(t, sink.entries, 1)
+	e := sink.entries[0]
+	assert.Equal(t, "/vtctlservice.Vtctl/ExecuteVtctlCommand", e.Method)
+	assert.JSONEq(t, `{"args":["DeleteTablet","zone1-100"]}`, string(e.Request))
+	assert.Equal(t, StatusOK, e.Status)
+}
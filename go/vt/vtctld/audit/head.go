@@ -0,0 +1,126 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Head is the latest entry of an audit log chain, signed with the audit log
+// key. It is kept apart from the sink, so that removing the last entries of
+// the log, which leaves a valid chain, can still be detected.
+type Head struct {
+	// Sequence and Hash are those of the latest entry of the chain.
+	Sequence uint64 `json:"sequence"`
+	Hash     string `json:"hash"`
+	// Signature is the hex-encoded HMAC-SHA256 of the head, computed with
+	// an empty Signature field.
+	Signature string `json:"signature"`
+}
+
+// newHead returns the signed head of a chain that ends at e.
+func newHead(key []byte, e *Entry) *Head {
+	h := &Head{
+		Sequence: e.Sequence,
+		Hash:     e.Hash,
+	}
+	h.Signature = h.computeSignature(key)
+	return h
+}
+
+func (h *Head) computeSignature(key []byte) string {
+	// Sequence and Hash cannot hold the separator, so the signed data is
+	// unambiguous.
+	return sign(key, []byte(fmt.Sprintf("%d:%s", h.Sequence, h.Hash)))
+}
+
+// verify checks that the head was signed with the given key.
+func (h *Head) verify(key []byte) error {
+	if !hmac.Equal([]byte(h.computeSignature(key)), []byte(h.Signature)) {
+		return errors.New("the head has been modified or was not signed with this key")
+	}
+
+	return nil
+}
+
+// ReadHead reads the head kept in the given file.
+func ReadHead(path string) (*Head, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &Head{}
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, fmt.Errorf("cannot parse the audit log head in %s: %w", path, err)
+	}
+
+	return h, nil
+}
+
+// writeHead replaces the head kept in the given file, atomically so that a
+// crash never leaves a partial head behind.
+func writeHead(path string, h *Head) error {
+	data, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// VerifyHead checks, like Verify, that the entries form an untampered chain,
+// and also that the chain ends at the given head, so that removed trailing
+// entries are detected.
+func VerifyHead(key []byte, entries []*Entry, head *Head) error {
+	if err := head.verify(key); err != nil {
+		return err
+	}
+	if err := Verify(key, entries); err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		return fmt.Errorf("the log is empty, but its head is at entry %d", head.Sequence)
+	}
+
+	last := entries[len(entries)-1]
+	if last.Sequence != head.Sequence || last.Hash != head.Hash {
+		return fmt.Errorf("the log has been truncated: it ends at entry %d, but its head is at entry %d", last.Sequence, head.Sequence)
+	}
+
+	return nil
+}
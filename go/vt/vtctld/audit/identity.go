@@ -0,0 +1,92 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"vitess.io/vitess/go/vt/log"
+)
+
+// TokenVerifier authenticates a caller from a bearer token. It returns the
+// caller's principal and the claims of the token, or an error if the token is
+// not valid.
+type TokenVerifier func(ctx context.Context, token string) (principal string, claims map[string][]string, err error)
+
+var (
+	tokenVerifierMu sync.RWMutex
+	tokenVerifier   TokenVerifier
+)
+
+// SetTokenVerifier sets the TokenVerifier used to authenticate the bearer
+// tokens sent by callers. Without one, bearer tokens are ignored.
+func SetTokenVerifier(verifier TokenVerifier) {
+	tokenVerifierMu.Lock()
+	defer tokenVerifierMu.Unlock()
+
+	tokenVerifier = verifier
+}
+
+// verifyToken returns the principal and claims of a bearer token, or nothing
+// if there is no TokenVerifier or the token is not valid.
+func verifyToken(ctx context.Context, token string) (string, map[string][]string) {
+	tokenVerifierMu.RLock()
+	verifier := tokenVerifier
+	tokenVerifierMu.RUnlock()
+
+	if verifier == nil {
+		return "", nil
+	}
+
+	principal, claims, err := verifier(ctx, token)
+	if err != nil {
+		log.Warningf("ignoring invalid bearer token: %v", err)
+		return "", nil
+	}
+
+	return principal, claims
+}
+
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// CallerFromHTTPRequest returns the Caller of a vtctld HTTP API call. Its
+// principal and claims come from the bearer token in the Authorization
+// header.
+func CallerFromHTTPRequest(r *http.Request) Caller {
+	caller := Caller{Peer: r.RemoteAddr}
+
+	if token, ok := bearerToken(r.Header.Get("Authorization")); ok {
+		caller.Principal, caller.Claims = verifyToken(r.Context(), token)
+	}
+
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		caller.CertSubject = r.TLS.PeerCertificates[0].Subject.String()
+	}
+
+	return caller
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestCallerBearerToken(t *testing.T) {
+	SetTokenVerifier(func(ctx context.Context, token string) (string, map[string][]string, error) {
+		if token != "good-token" {
+			return "", nil, errors.New("bad signature")
+		}
+
+		return "alice@example.com", map[string][]string{"groups": {"sre"}}, nil
+	})
+	defer SetTokenVerifier(nil)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer good-token"))
+	caller := CallerFromContext(ctx)
+	assert.Equal(t, "alice@example.com", caller.Principal)
+	assert.Equal(t, map[string][]string{"groups": {"sre"}}, caller.Claims)
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer forged-token"))
+	caller = CallerFromContext(ctx)
+	assert.Empty(t, caller.Principal)
+	assert.Empty(t, caller.Claims)
+
+	r := httptest.NewRequest("POST", "/api/vtctl/", nil)
+	r.Header.Set("Authorization", "bearer good-token")
+	caller = CallerFromHTTPRequest(r)
+	assert.Equal(t, "alice@example.com", caller.Principal)
+	assert.Equal(t, "192.0.2.1:1234", caller.Peer)
+
+	r.Header.Set("Authorization", "Basic YWxpY2U6c2VjcmV0")
+	caller = CallerFromHTTPRequest(r)
+	assert.Empty(t, caller.Principal)
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"log/syslog"
+
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// defaultSyslogTag is the syslog tag used when --audit-log-target is empty.
+const defaultSyslogTag = "vtctld-audit"
+
+func init() {
+	RegisterSink("syslog", func(ts *topo.Server, target string) (Sink, error) {
+		if target == "" {
+			target = defaultSyslogTag
+		}
+
+		return NewSyslogSink(target)
+	})
+}
+
+// SyslogSink sends entries, as JSON, to the local syslog daemon with the
+// LOG_AUTH facility.
+type SyslogSink struct {
+	w *syslog.Writer
+}
+
+// NewSyslogSink returns a SyslogSink that tags its messages with the given tag.
+func NewSyslogSink(tag string) (*SyslogSink, error) {
+	w, err := syslog.New(syslog.LOG_AUTH|syslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SyslogSink{w: w}, nil
+}
+
+// Write is part of the Sink interface.
+func (s *SyslogSink) Write(ctx context.Context, e *Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	if e.Status == StatusError {
+		return s.w.Warning(string(data))
+	}
+
+	return s.w.Info(string(data))
+}
+
+// Close is part of the Sink interface.
+func (s *SyslogSink) Close() error {
+	return s.w.Close()
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// defaultTopoRoot is the directory of the global topo the topo sink writes to
+// when --audit-log-target is empty.
+const defaultTopoRoot = "audit"
+
+func init() {
+	RegisterSink("topo", func(ts *topo.Server, target string) (Sink, error) {
+		if target == "" {
+			target = defaultTopoRoot
+		}
+
+		return NewTopoSink(context.Background(), ts, target)
+	})
+}
+
+// TopoSink writes each entry to its own file in the global topo. Entries of a
+// given vtctld process live in their own directory, named after the time the
+// sink was created, and are named after their sequence number so they list in
+// order.
+type TopoSink struct {
+	conn topo.Conn
+	dir  string
+}
+
+// NewTopoSink returns a TopoSink that writes under the given directory of the
+// global topo.
+func NewTopoSink(ctx context.Context, ts *topo.Server, root string) (*TopoSink, error) {
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TopoSink{
+		conn: conn,
+		dir:  path.Join(root, time.Now().UTC().Format("20060102T150405.000000000Z")),
+	}, nil
+}
+
+// Write is part of the Sink interface.
+func (s *TopoSink) Write(ctx context.Context, e *Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	// The call being audited may have been canceled, but its entry must
+	// still be recorded.
+	ctx, cancel := context.WithTimeout(context.Background(), topo.RemoteOperationTimeout)
+	defer cancel()
+
+	_, err = s.conn.Create(ctx, path.Join(s.dir, fmt.Sprintf("%020d", e.Sequence)), data)
+	return err
+}
+
+// Close is part of the Sink interface. The connection is owned by the topo
+// server, so there is nothing to do.
+func (s *TopoSink) Close() error {
+	return nil
+}
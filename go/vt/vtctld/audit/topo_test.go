@@ -0,0 +1,67 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+)
+
+func TestTopoSink(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+
+	sink, err := NewTopoSink(ctx, ts, defaultTopoRoot)
+	require.NoError(t, err)
+
+	logger := newTestLogger(t, sink)
+	for i := 0; i < 3; i++ {
+		logger.Log(ctx, Caller{Username: "alice"}, "/vtctlservice.Vtctld/GetKeyspaces", nil, time.Now(), nil)
+	}
+
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	require.NoError(t, err)
+
+	dirEntries, err := conn.ListDir(ctx, sink.dir, false /* full */)
+	require.NoError(t, err)
+	require.Len(t, dirEntries, 3)
+
+	var entries []*Entry
+	for _, de := range dirEntries {
+		data, _, err := conn.Get(ctx, path.Join(sink.dir, de.Name))
+		require.NoError(t, err)
+
+		e := &Entry{}
+		require.NoError(t, json.Unmarshal(data, e))
+		entries = append(entries, e)
+	}
+
+	assert.Equal(t, uint64(1), entries[0].Sequence)
+	assert.NoError(t, Verify(testKey, entries))
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// webhookTimeout bounds how long a single entry may take to be posted.
+const webhookTimeout = 10 * time.Second
+
+func init() {
+	RegisterSink("webhook", func(ts *topo.Server, target string) (Sink, error) {
+		return NewWebhookSink(target)
+	})
+}
+
+// WebhookSink POSTs each entry, as JSON, to a URL. Any response status other
+// than 2xx is an error.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSink returns a WebhookSink that posts to the given URL.
+func NewWebhookSink(url string) (*WebhookSink, error) {
+	if url == "" {
+		return nil, errors.New("the webhook audit log sink requires --audit-log-target to be set to a URL")
+	}
+
+	return &WebhookSink{
+		url:    url,
+		client: &http.Client{Timeout: webhookTimeout},
+	}, nil
+}
+
+// Write is part of the Sink interface.
+func (s *WebhookSink) Write(ctx context.Context, e *Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	// The call being audited may have been canceled, but its entry must
+	// still be recorded, so this does not use ctx.
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned %s", s.url, resp.Status)
+	}
+
+	return nil
+}
+
+// Close is part of the Sink interface.
+func (s *WebhookSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
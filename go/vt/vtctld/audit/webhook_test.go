@@ -0,0 +1,76 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSink(t *testing.T) {
+	var (
+		m        sync.Mutex
+		received []*Entry
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		e := &Entry{}
+		if err := json.NewDecoder(r.Body).Decode(e); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		m.Lock()
+		defer m.Unlock()
+		received = append(received, e)
+	}))
+	defer server.Close()
+
+	sink, err := NewWebhookSink(server.URL)
+	require.NoError(t, err)
+
+	logger := newTestLogger(t, sink)
+	logger.Log(context.Background(), Caller{Username: "alice"}, "/vtctlservice.Vtctld/DeleteShards", nil, time.Now(), nil)
+	require.NoError(t, logger.Close())
+
+	require.Len(t, received, 1)
+	assert.Equal(t, "/vtctlservice.Vtctld/DeleteShards", received[0].Method)
+	assert.NoError(t, Verify(testKey, received))
+}
+
+func TestWebhookSinkError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sink, err := NewWebhookSink(server.URL)
+	require.NoError(t, err)
+
+	err = sink.Write(context.Background(), &Entry{Sequence: 1})
+	assert.ErrorContains(t, err, "503")
+}
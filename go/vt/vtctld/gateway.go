@@ -0,0 +1,318 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctld
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"vitess.io/vitess/go/sets"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver"
+	"vitess.io/vitess/go/vt/vtctld/audit"
+	"vitess.io/vitess/go/vt/vtctld/rbac"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtctlservicepb "vitess.io/vitess/go/vt/proto/vtctlservice"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// This file implements an HTTP/JSON gateway to the read-only unary RPCs of the
+// Vtctld service, for tools that cannot speak gRPC. Callers are identified
+// like on the rest of the vtctld HTTP API, by their TLS client certificate and
+// their bearer token (see audit.CallerFromHTTPRequest).
+
+const gatewayPath = "vtctld/"
+
+var enableHTTPGateway bool
+
+func init() {
+	for _, cmd := range []string{"vtcombo", "vtctld"} {
+		servenv.OnParseFor(cmd, registerHTTPGatewayFlags)
+	}
+}
+
+func registerHTTPGatewayFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&enableHTTPGateway, "vtctld-http-gateway", enableHTTPGateway, "Serve the read-only RPCs of the vtctld gRPC API as HTTP/JSON, under /api/vtctld/<RPC name>.")
+}
+
+// gatewayMethods are the RPCs served by the gateway. They are listed one by
+// one, rather than picked by name, so that new RPCs are not exposed over HTTP
+// by accident. It leaves out read-only RPCs that return command arguments,
+// queries or grants, such as GetRunningCommands or GetPermissions.
+var gatewayMethods = sets.New[string](
+	"FindAllShardsInKeyspace",
+	"GetBackups",
+	"GetCellInfo",
+	"GetCellInfoNames",
+	"GetCellsAliases",
+	"GetFullStatus",
+	"GetKeyspace",
+	"GetKeyspaces",
+	"GetMaintenanceWindows",
+	"GetRoutingRules",
+	"GetSchema",
+	"GetSchemaMigrations",
+	"GetShard",
+	"GetShardRoutingRules",
+	"GetSrvKeyspaceNames",
+	"GetSrvKeyspaces",
+	"GetSrvVSchema",
+	"GetSrvVSchemas",
+	"GetTablet",
+	"GetTablets",
+	"GetTopologyPath",
+	"GetVersion",
+	"GetVSchema",
+	"GetWorkflows",
+	"ReparentPreflight",
+	"Validate",
+	"ValidateKeyspace",
+	"ValidateSchemaKeyspace",
+	"ValidateShard",
+	"ValidateVersionKeyspace",
+	"ValidateVersionShard",
+	"ValidateVSchema",
+)
+
+// httpGateway serves the read-only unary RPCs of a VtctldServer over HTTP. The
+// request message is read from the JSON body of the HTTP request, or from its
+// query parameters, and the response message is written as JSON.
+type httpGateway struct {
+	server  vtctlservicepb.VtctldServer
+	methods map[string]grpc.MethodDesc
+}
+
+func newHTTPGateway(server vtctlservicepb.VtctldServer) *httpGateway {
+	methods := map[string]grpc.MethodDesc{}
+	for _, md := range vtctlservicepb.Vtctld_ServiceDesc.Methods {
+		if gatewayMethods.Has(md.MethodName) {
+			methods[md.MethodName] = md
+		}
+	}
+
+	return &httpGateway{
+		server:  server,
+		methods: methods,
+	}
+}
+
+// initHTTPGateway serves the HTTP gateway, if --vtctld-http-gateway is set.
+func initHTTPGateway(ts *topo.Server) {
+	if !enableHTTPGateway {
+		return
+	}
+
+	servenv.HTTPHandle(apiPrefix+gatewayPath, newHTTPGateway(grpcvtctldserver.NewVtctldServer(ts)))
+}
+
+// gatewayError is the body of a failed gateway call, modeled after the
+// google.rpc.Status message returned by grpc-gateway.
+type gatewayError struct {
+	Code    vtrpcpb.Code `json:"code"`
+	Message string       `json:"message"`
+}
+
+func (gw *httpGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "405 Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	start := time.Now()
+	name := strings.TrimPrefix(r.URL.Path, apiPrefix+gatewayPath)
+	caller := audit.CallerFromHTTPRequest(r)
+
+	var body []byte
+	resp, err := func() (any, error) {
+		md, ok := gw.methods[name]
+		if !ok {
+			return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "unknown or non-read-only RPC %q", name)
+		}
+
+		if err := rbac.Authorize(r.Context(), caller, name); err != nil {
+			return nil, err
+		}
+
+		var err error
+		body, err = io.ReadAll(r.Body)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "cannot read request body")
+		}
+
+		dec := func(req any) error {
+			return decodeGatewayRequest(r, body, req.(proto.Message))
+		}
+		return md.Handler(gw.server, r.Context(), dec, nil)
+	}()
+
+	var request any
+	if len(body) > 0 && json.Valid(body) {
+		request = json.RawMessage(body)
+	} else if len(r.URL.RawQuery) > 0 {
+		request = r.URL.Query()
+	}
+	audit.Record(r.Context(), caller, r.URL.Path, request, start, err)
+
+	if err != nil {
+		writeGatewayError(w, r, err)
+		return
+	}
+
+	data, err := protojson.MarshalOptions{
+		Multiline:       true,
+		Indent:          "  ",
+		UseProtoNames:   true,
+		UseEnumNumbers:  true,
+		EmitUnpopulated: true,
+	}.Marshal(resp.(proto.Message))
+	if err != nil {
+		writeGatewayError(w, r, vterrors.Wrapf(err, "cannot marshal response"))
+		return
+	}
+
+	w.Header().Set("Content-Type", jsonContentType)
+	w.Write(data)
+}
+
+// decodeGatewayRequest fills req from the JSON body of the HTTP request if
+// there is one, and from its query parameters otherwise.
+func decodeGatewayRequest(r *http.Request, body []byte, req proto.Message) error {
+	if len(body) == 0 {
+		data, err := queryToJSON(r.URL.Query(), req.ProtoReflect().Descriptor())
+		if err != nil {
+			return err
+		}
+		body = data
+	}
+
+	if err := protojson.Unmarshal(body, req); err != nil {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "cannot parse request: %v", err)
+	}
+
+	return nil
+}
+
+// queryToJSON converts query parameters to the JSON form of the message
+// described by md. Each parameter sets the top-level field of the same name;
+// repeated fields can be given several times.
+func queryToJSON(query map[string][]string, md protoreflect.MessageDescriptor) ([]byte, error) {
+	obj := make(map[string]any, len(query))
+	for key, values := range query {
+		fd := md.Fields().ByName(protoreflect.Name(key))
+		if fd == nil {
+			fd = md.Fields().ByJSONName(key)
+		}
+		if fd == nil {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown field %q in %s", key, md.FullName())
+		}
+		if fd.IsMap() || fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "field %q of %s cannot be set from a query parameter, send a JSON body instead", key, md.FullName())
+		}
+
+		converted := make([]any, 0, len(values))
+		for _, value := range values {
+			v, err := queryValue(fd, value)
+			if err != nil {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid value %q for field %q: %v", value, key, err)
+			}
+			converted = append(converted, v)
+		}
+
+		switch {
+		case fd.IsList():
+			obj[key] = converted
+		case len(converted) == 1:
+			obj[key] = converted[0]
+		default:
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "field %q of %s is set %d times", key, md.FullName(), len(converted))
+		}
+	}
+
+	return json.Marshal(obj)
+}
+
+// queryValue returns the JSON value of a scalar field given as a query
+// parameter. protojson accepts numbers and enums as strings, but not booleans.
+func queryValue(fd protoreflect.FieldDescriptor, value string) (any, error) {
+	if fd.Kind() == protoreflect.BoolKind {
+		return strconv.ParseBool(value)
+	}
+
+	return value, nil
+}
+
+func writeGatewayError(w http.ResponseWriter, r *http.Request, err error) {
+	code := vterrors.Code(err)
+	if topo.IsErrType(err, topo.NoNode) {
+		code = vtrpcpb.Code_NOT_FOUND
+	}
+
+	status := gatewayHTTPStatus(code)
+	if status == http.StatusInternalServerError {
+		log.Errorf("HTTP error on %v: %v", r.URL.Path, err)
+	}
+
+	data, _ := json.Marshal(&gatewayError{Code: code, Message: err.Error()})
+	w.Header().Set("Content-Type", jsonContentType)
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+// gatewayHTTPStatus maps error codes to HTTP statuses the same way grpc-gateway
+// does.
+func gatewayHTTPStatus(code vtrpcpb.Code) int {
+	switch code {
+	case vtrpcpb.Code_OK:
+		return http.StatusOK
+	case vtrpcpb.Code_CANCELED:
+		return 499
+	case vtrpcpb.Code_INVALID_ARGUMENT, vtrpcpb.Code_FAILED_PRECONDITION, vtrpcpb.Code_OUT_OF_RANGE:
+		return http.StatusBadRequest
+	case vtrpcpb.Code_DEADLINE_EXCEEDED:
+		return http.StatusGatewayTimeout
+	case vtrpcpb.Code_NOT_FOUND:
+		return http.StatusNotFound
+	case vtrpcpb.Code_ALREADY_EXISTS, vtrpcpb.Code_ABORTED:
+		return http.StatusConflict
+	case vtrpcpb.Code_PERMISSION_DENIED:
+		return http.StatusForbidden
+	case vtrpcpb.Code_UNAUTHENTICATED:
+		return http.StatusUnauthorized
+	case vtrpcpb.Code_RESOURCE_EXHAUSTED:
+		return http.StatusTooManyRequests
+	case vtrpcpb.Code_UNIMPLEMENTED:
+		return http.StatusNotImplemented
+	case vtrpcpb.Code_UNAVAILABLE:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
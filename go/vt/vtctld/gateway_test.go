@@ -0,0 +1,222 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctld
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sets"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver"
+	"vitess.io/vitess/go/vt/vtctld/audit"
+	"vitess.io/vitess/go/vt/vtctld/rbac"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctlservicepb "vitess.io/vitess/go/vt/proto/vtctlservice"
+)
+
+func TestHTTPGateway(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks1", &topodatapb.Keyspace{DurabilityPolicy: "semi_sync"}))
+	require.NoError(t, ts.CreateShard(ctx, "ks1", "-"))
+
+	server := httptest.NewServer(newHTTPGateway(grpcvtctldserver.NewTestVtctldServer(ts, nil)))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+		expectedBody   []string
+	}{
+		{
+			name:           "get without parameters",
+			method:         http.MethodGet,
+			path:           "/api/vtctld/GetKeyspaces",
+			expectedStatus: http.StatusOK,
+			expectedBody:   []string{`"name": "ks1"`, `"durability_policy": "semi_sync"`},
+		},
+		{
+			name:           "get with query parameters",
+			method:         http.MethodGet,
+			path:           "/api/vtctld/GetShard?keyspace=ks1&shard_name=-",
+			expectedStatus: http.StatusOK,
+			expectedBody:   []string{`"keyspace": "ks1"`, `"name": "-"`},
+		},
+		{
+			name:           "post with a json body",
+			method:         http.MethodPost,
+			path:           "/api/vtctld/GetKeyspace",
+			body:           `{"keyspace": "ks1"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   []string{`"name": "ks1"`},
+		},
+		{
+			name:           "boolean query parameter",
+			method:         http.MethodGet,
+			path:           "/api/vtctld/GetTablets?keyspace=ks1&strict=true",
+			expectedStatus: http.StatusOK,
+			expectedBody:   []string{`"tablets": []`},
+		},
+		{
+			name:           "missing record",
+			method:         http.MethodGet,
+			path:           "/api/vtctld/GetKeyspace?keyspace=ks2",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   []string{`"code":5`},
+		},
+		{
+			name:           "unknown field",
+			method:         http.MethodGet,
+			path:           "/api/vtctld/GetKeyspace?name=ks1",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   []string{`unknown field \"name\"`},
+		},
+		{
+			name:           "invalid body",
+			method:         http.MethodPost,
+			path:           "/api/vtctld/GetKeyspace",
+			body:           `{"keyspace": 1}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   []string{"cannot parse request"},
+		},
+		{
+			name:           "rpc that is not read-only",
+			method:         http.MethodPost,
+			path:           "/api/vtctld/DeleteKeyspace",
+			body:           `{"keyspace": "ks1"}`,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   []string{`unknown or non-read-only RPC \"DeleteKeyspace\"`},
+		},
+		{
+			name:           "read-only rpc returning command arguments",
+			method:         http.MethodGet,
+			path:           "/api/vtctld/GetRunningCommands",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "streaming rpc",
+			method:         http.MethodGet,
+			path:           "/api/vtctld/WatchKeyspace?keyspace=ks1",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "unsupported http method",
+			method:         http.MethodDelete,
+			path:           "/api/vtctld/GetKeyspace",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, server.URL+tt.path, strings.NewReader(tt.body))
+			require.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode, string(body))
+			for _, s := range tt.expectedBody {
+				assert.Contains(t, string(body), s)
+			}
+		})
+	}
+
+	// The keyspace must not have been deleted.
+	_, err := ts.GetKeyspace(ctx, "ks1")
+	assert.NoError(t, err)
+}
+
+func TestHTTPGatewayMethods(t *testing.T) {
+	unary := sets.New[string]()
+	for _, md := range vtctlservicepb.Vtctld_ServiceDesc.Methods {
+		unary.Insert(md.MethodName)
+	}
+
+	for _, name := range sets.List(gatewayMethods) {
+		assert.True(t, unary.Has(name), "%s is not a unary RPC of the Vtctld service", name)
+		assert.Equal(t, rbac.ReadOnly, rbac.GroupForCommand(name), "%s is not read-only", name)
+	}
+}
+
+func TestHTTPGatewayCaller(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks1", &topodatapb.Keyspace{}))
+
+	server := httptest.NewServer(newHTTPGateway(grpcvtctldserver.NewTestVtctldServer(ts, nil)))
+	defer server.Close()
+
+	audit.SetTokenVerifier(func(ctx context.Context, token string) (string, map[string][]string, error) {
+		if token != "alice-token" {
+			return "", nil, errors.New("invalid token")
+		}
+		return "alice@example.com", nil, nil
+	})
+	defer audit.SetTokenVerifier(nil)
+
+	policyPath := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(policyPath, []byte("rules:\n  - groups: [read-only]\n    subjects: [\"principal:alice@example.com\"]\n"), 0o644))
+	authz, err := rbac.NewFileAuthorizer(policyPath, 0)
+	require.NoError(t, err)
+	defer rbac.SetAuthorizer(authz)()
+
+	get := func(token string) int {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/api/vtctld/GetKeyspaces", nil)
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusOK, get("alice-token"))
+	assert.Equal(t, http.StatusForbidden, get("forged-token"))
+	assert.Equal(t, http.StatusForbidden, get(""))
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"strings"
+)
+
+// Group is a set of vtctl commands and vtctld RPCs that policies grant access
+// to as a whole.
+type Group string
+
+const (
+	// ReadOnly is the group of commands that only read from the topo or the
+	// tablets: the Get*, Find*, List*, Validate*, Ping* and Watch* ones, except
+	// ValidateBackup.
+	ReadOnly Group = "read-only"
+	// TabletOps is the group of commands that act on individual tablets
+	// without changing the shard's primary, such as ChangeTabletType or
+	// ReloadSchema, and the backup and restore commands.
+	TabletOps Group = "tablet-ops"
+	// EmergencyOps is the group of commands that change the primary of a
+	// shard.
+	EmergencyOps Group = "emergency-ops"
+	// Admin is the group of every other command.
+	Admin Group = "admin"
+)
+
+// readOnlyPrefixes are the prefixes of the names of ReadOnly commands.
+var readOnlyPrefixes = []string{"get", "find", "list", "validate", "ping", "show", "watch"}
+
+// groupsByCommand holds the commands that are not in the Admin group, other than
+// the ones matching readOnlyPrefixes. Names are lowercase, because the legacy
+// vtctl command names are case-insensitive.
+var groupsByCommand = map[string]Group{
+	"reparentpreflight": ReadOnly,
+
+	"addtablettag":              TabletOps,
+	"backup":                    TabletOps,
+	"backupshard":               TabletOps,
+	"changetablettype":          TabletOps,
+	"changetablettypebyfilter":  TabletOps,
+	"concludetransaction":       TabletOps,
+	"executehook":               TabletOps,
+	"invalidatetabletplancache": TabletOps,
+	"refreshstate":              TabletOps,
+	"refreshstatebyfilter":      TabletOps,
+	"refreshstatebyshard":       TabletOps,
+	"reloadschema":              TabletOps,
+	"reloadschemakeyspace":      TabletOps,
+	"reloadschemashard":         TabletOps,
+	"removetablettag":           TabletOps,
+	"restorefrombackup":         TabletOps,
+	"runhealthcheck":            TabletOps,
+	"setreadonly":               TabletOps,
+	"setreadwrite":              TabletOps,
+	"setwritable":               TabletOps,
+	"sleeptablet":               TabletOps,
+	"startreplication":          TabletOps,
+	"stopreplication":           TabletOps,
+	"upgrademysql":              TabletOps,
+	"validatebackup":            TabletOps,
+
+	"emergencyreparentshard":     EmergencyOps,
+	"fixerrantgtid":              EmergencyOps,
+	"initshardprimary":           EmergencyOps,
+	"plannedreparentshard":       EmergencyOps,
+	"reparenttablet":             EmergencyOps,
+	"tabletexternallyreparented": EmergencyOps,
+}
+
+// GroupForCommand returns the group of a vtctl command or vtctld RPC, given its
+// name.
+func GroupForCommand(name string) Group {
+	name = strings.ToLower(name)
+	if group, ok := groupsByCommand[name]; ok {
+		return group
+	}
+
+	for _, prefix := range readOnlyPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return ReadOnly
+		}
+	}
+
+	return Admin
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupForCommand(t *testing.T) {
+	tests := []struct {
+		command  string
+		expected Group
+	}{
+		{command: "GetKeyspace", expected: ReadOnly},
+		{command: "ListAllTablets", expected: ReadOnly},
+		{command: "ValidateSchemaKeyspace", expected: ReadOnly},
+		{command: "FindAllShardsInKeyspace", expected: ReadOnly},
+		{command: "WatchSrvVSchema", expected: ReadOnly},
+		{command: "ChangeTabletTypeByFilter", expected: TabletOps},
+		{command: "ChangeTabletType", expected: TabletOps},
+		{command: "changetablettype", expected: TabletOps},
+		{command: "ReloadSchemaShard", expected: TabletOps},
+		{command: "ValidateBackup", expected: TabletOps},
+		{command: "PlannedReparentShard", expected: EmergencyOps},
+		{command: "EmergencyReparentShard", expected: EmergencyOps},
+		{command: "DeleteKeyspace", expected: Admin},
+		{command: "ApplySchema", expected: Admin},
+		{command: "", expected: Admin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			assert.Equal(t, tt.expected, GroupForCommand(tt.command))
+		})
+	}
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"vitess.io/vitess/go/sets"
+	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/vtctld/audit"
+)
+
+// Policy is a set of rules granting callers access to command groups.
+//
+// Each rule lists subjects and the groups they are allowed to run. A subject
+// is one of:
+//   - "*", which matches any caller, including unauthenticated ones.
+//   - "user:<name>", which matches the username authenticated by the gRPC
+//     static auth plugin.
+//   - "cert:<DN>", which matches a caller whose TLS client certificate's
+//     subject is the distinguished name, such as "cert:CN=admin,O=Acme".
+//     Attribute types are case-insensitive, and spaces around the separators
+//     are ignored.
+//   - "principal:<name>", which matches the principal of the OIDC id token
+//     the caller sent as a bearer token (see --rbac-oidc-issuer-url).
+//   - "claim:<claim>=<value>", which matches a caller whose OIDC id token has
+//     the value for the claim, or among the values of the claim if it is a
+//     list, such as "claim:groups=dba".
+//
+// Groups are the Group names, or "*" for all of them. A caller is allowed to
+// run a command if any rule matches both the caller and the command's group.
+type Policy struct {
+	Rules []*struct {
+		Groups   []string
+		Subjects []string
+	}
+
+	rules []*rule
+}
+
+type rule struct {
+	groups   sets.Set[string]
+	subjects sets.Set[string]
+	// certs holds the normalized distinguished names of the cert subjects.
+	certs sets.Set[string]
+	// claims holds the claim subjects, by claim name.
+	claims map[string]sets.Set[string]
+}
+
+var validGroups = sets.New[string]("*", string(ReadOnly), string(TabletOps), string(EmergencyOps), string(Admin))
+
+// LoadPolicy reads and validates the policy in the file at path. Any file
+// format supported by viper is supported: yaml, json or toml.
+func LoadPolicy(path string) (*Policy, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	var policy Policy
+	if err := v.UnmarshalExact(&policy); err != nil {
+		return nil, err
+	}
+
+	if err := policy.reify(); err != nil {
+		return nil, err
+	}
+
+	return &policy, nil
+}
+
+// reify validates the rules loaded from the file, and builds the sets used to
+// evaluate them.
+func (p *Policy) reify() error {
+	rec := concurrency.AllErrorRecorder{}
+
+	for i, r := range p.Rules {
+		groups := sets.New[string](r.Groups...)
+		if invalid := groups.Difference(validGroups); invalid.Len() > 0 {
+			rec.RecordError(fmt.Errorf("rule %d: unknown groups %v", i, sets.List(invalid)))
+		}
+
+		pr := &rule{
+			groups:   groups,
+			subjects: sets.New[string](r.Subjects...),
+			certs:    sets.New[string](),
+			claims:   map[string]sets.Set[string]{},
+		}
+		for _, subject := range r.Subjects {
+			if subject == "*" {
+				continue
+			}
+
+			kind, value, ok := strings.Cut(subject, ":")
+			switch {
+			case !ok:
+				rec.RecordError(fmt.Errorf("rule %d: subject %q must be \"*\" or of the form <kind>:<value>", i, subject))
+			case kind == "user" || kind == "principal":
+			case kind == "cert":
+				dn, err := normalizeDN(value)
+				if err != nil {
+					rec.RecordError(fmt.Errorf("rule %d: subject %q: %w", i, subject, err))
+					continue
+				}
+				pr.certs.Insert(dn)
+			case kind == "claim":
+				name, claimValue, ok := strings.Cut(value, "=")
+				if !ok || name == "" {
+					rec.RecordError(fmt.Errorf("rule %d: subject %q must be of the form claim:<claim>=<value>", i, subject))
+					continue
+				}
+				if pr.claims[name] == nil {
+					pr.claims[name] = sets.New[string]()
+				}
+				pr.claims[name].Insert(claimValue)
+			default:
+				rec.RecordError(fmt.Errorf("rule %d: subject %q has unknown kind %q", i, subject, kind))
+			}
+		}
+
+		p.rules = append(p.rules, pr)
+	}
+
+	return rec.Error()
+}
+
+// Allows returns true if the caller is allowed to run commands of the group.
+func (p *Policy) Allows(caller audit.Caller, group Group) bool {
+	for _, r := range p.rules {
+		if r.groups.HasAny("*", string(group)) && r.matches(caller) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *rule) matches(caller audit.Caller) bool {
+	if r.subjects.Has("*") {
+		return true
+	}
+
+	if caller.Username != "" && r.subjects.Has("user:"+caller.Username) {
+		return true
+	}
+
+	if caller.Principal != "" && r.subjects.Has("principal:"+caller.Principal) {
+		return true
+	}
+
+	if caller.CertSubject != "" && r.certs.Len() > 0 {
+		if dn, err := normalizeDN(caller.CertSubject); err == nil && r.certs.Has(dn) {
+			return true
+		}
+	}
+
+	for name, values := range caller.Claims {
+		if r.claims[name] != nil && r.claims[name].HasAny(values...) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalizeDN parses a distinguished name in the string form of RFC 4514, as
+// returned by pkix.Name.String, and returns it with its attribute types in
+// upper case and without spaces around the separators, so that equivalent
+// names compare equal.
+func normalizeDN(dn string) (string, error) {
+	var (
+		b       strings.Builder
+		attr    strings.Builder
+		escaped bool
+	)
+
+	flush := func(sep byte) error {
+		typ, value, ok := strings.Cut(attr.String(), "=")
+		typ = strings.TrimSpace(typ)
+		if !ok || typ == "" {
+			return fmt.Errorf("invalid distinguished name %q", dn)
+		}
+
+		b.WriteString(strings.ToUpper(typ))
+		b.WriteByte('=')
+		b.WriteString(trimUnescapedSpace(value))
+		if sep != 0 {
+			b.WriteByte(sep)
+		}
+
+		attr.Reset()
+		return nil
+	}
+
+	for i := 0; i < len(dn); i++ {
+		c := dn[i]
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == ',' || c == '+':
+			if err := flush(c); err != nil {
+				return "", err
+			}
+			continue
+		}
+
+		attr.WriteByte(c)
+	}
+
+	if escaped {
+		return "", fmt.Errorf("invalid distinguished name %q", dn)
+	}
+
+	if err := flush(0); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// trimUnescapedSpace trims the leading spaces of a value, and its trailing
+// spaces unless they are escaped.
+func trimUnescapedSpace(value string) string {
+	value = strings.TrimLeft(value, " ")
+	for strings.HasSuffix(value, " ") && !strings.HasSuffix(value, "\\ ") {
+		value = value[:len(value)-1]
+	}
+
+	return value
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/vtctld/audit"
+)
+
+const testPolicy = `
+rules:
+  - groups: ["read-only"]
+    subjects: ["*"]
+  - groups: ["tablet-ops", "emergency-ops"]
+    subjects: ["user:oncall", "cert:CN=alice, OU=sre, O=example", "claim:groups=sre"]
+  - groups: ["*"]
+    subjects: ["principal:admin@example.com"]
+`
+
+func writePolicy(t *testing.T, path string, policy string) {
+	require.NoError(t, os.WriteFile(path, []byte(policy), 0o600))
+}
+
+func TestPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	writePolicy(t, path, testPolicy)
+
+	policy, err := LoadPolicy(path)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		caller   audit.Caller
+		group    Group
+		expected bool
+	}{
+		{
+			name:     "anyone can read",
+			caller:   audit.Caller{},
+			group:    ReadOnly,
+			expected: true,
+		},
+		{
+			name:     "unauthenticated caller cannot run tablet ops",
+			caller:   audit.Caller{},
+			group:    TabletOps,
+			expected: false,
+		},
+		{
+			name:     "user match",
+			caller:   audit.Caller{Username: "oncall"},
+			group:    EmergencyOps,
+			expected: true,
+		},
+		{
+			name:     "cert match",
+			caller:   audit.Caller{CertSubject: "CN=alice,OU=sre,O=example"},
+			group:    TabletOps,
+			expected: true,
+		},
+		{
+			name:     "cert match with different attribute type case",
+			caller:   audit.Caller{CertSubject: "cn=alice,ou=sre,o=example"},
+			group:    TabletOps,
+			expected: true,
+		},
+		{
+			name:     "cert with a longer common name",
+			caller:   audit.Caller{CertSubject: "CN=alice-evil,OU=sre,O=example"},
+			group:    TabletOps,
+			expected: false,
+		},
+		{
+			name:     "cert with an escaped comma in the common name",
+			caller:   audit.Caller{CertSubject: `CN=alice\,OU=sre,OU=sre,O=example`},
+			group:    TabletOps,
+			expected: false,
+		},
+		{
+			name:     "cert with more attributes",
+			caller:   audit.Caller{CertSubject: "CN=alice,OU=sre,O=example,C=US"},
+			group:    TabletOps,
+			expected: false,
+		},
+		{
+			name:     "claim match",
+			caller:   audit.Caller{Principal: "bob@example.com", Claims: map[string][]string{"groups": {"dev", "sre"}}},
+			group:    EmergencyOps,
+			expected: true,
+		},
+		{
+			name:     "claim with another value",
+			caller:   audit.Caller{Principal: "bob@example.com", Claims: map[string][]string{"groups": {"dev"}, "sub": {"sre"}}},
+			group:    EmergencyOps,
+			expected: false,
+		},
+		{
+			name:     "oncall cannot run admin commands",
+			caller:   audit.Caller{Username: "oncall"},
+			group:    Admin,
+			expected: false,
+		},
+		{
+			name:     "wildcard group",
+			caller:   audit.Caller{Principal: "admin@example.com"},
+			group:    Admin,
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, policy.Allows(tt.caller, tt.group))
+		})
+	}
+}
+
+func TestLoadPolicyErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		wantErr string
+	}{
+		{
+			name: "unknown group",
+			policy: `
+rules:
+  - groups: ["superuser"]
+    subjects: ["*"]
+`,
+			wantErr: "unknown groups [superuser]",
+		},
+		{
+			name: "unknown subject kind",
+			policy: `
+rules:
+  - groups: ["admin"]
+    subjects: ["role:admin"]
+`,
+			wantErr: `unknown kind "role"`,
+		},
+		{
+			name: "malformed subject",
+			policy: `
+rules:
+  - groups: ["admin"]
+    subjects: ["alice"]
+`,
+			wantErr: "must be",
+		},
+		{
+			name: "malformed claim subject",
+			policy: `
+rules:
+  - groups: ["admin"]
+    subjects: ["claim:groups"]
+`,
+			wantErr: "must be of the form claim:<claim>=<value>",
+		},
+		{
+			name: "malformed cert subject",
+			policy: `
+rules:
+  - groups: ["admin"]
+    subjects: ["cert:alice"]
+`,
+			wantErr: "invalid distinguished name",
+		},
+		{
+			name: "unknown field",
+			policy: `
+rules:
+  - groups: ["admin"]
+    users: ["user:alice"]
+`,
+			wantErr: "users",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "policy.yaml")
+			writePolicy(t, path, tt.policy)
+
+			_, err := LoadPolicy(path)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
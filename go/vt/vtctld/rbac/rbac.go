@@ -0,0 +1,367 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package rbac authorizes the vtctl commands and vtctld RPCs run against a
+vtctld, based on the identity of the caller and the group of the command (see
+Group).
+
+The authorization decision is delegated to a pluggable Authorizer, selected
+with --rbac-policy-engine. The "file" engine ships with this package: it reads
+a Policy from the file at --rbac-policy-config, and reloads it periodically.
+*/
+package rbac
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/oidc"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vtctld/audit"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// Authorizer decides whether a caller may run a command.
+type Authorizer interface {
+	// Authorize returns nil if the caller is allowed to run the named command,
+	// and an error otherwise.
+	Authorize(ctx context.Context, caller audit.Caller, command string) error
+}
+
+// AuthorizerFactory creates an Authorizer, given the value of
+// --rbac-policy-config.
+type AuthorizerFactory func(config string) (Authorizer, error)
+
+var authorizerFactories = map[string]AuthorizerFactory{}
+
+// RegisterAuthorizer registers an AuthorizerFactory under the given name, to be
+// selected with --rbac-policy-engine.
+func RegisterAuthorizer(name string, factory AuthorizerFactory) {
+	if _, ok := authorizerFactories[name]; ok {
+		log.Fatalf("rbac policy engine %s already registered", name)
+	}
+
+	authorizerFactories[name] = factory
+}
+
+var (
+	engineName     string
+	engineConfig   string
+	reloadInterval = 30 * time.Second
+
+	oidcIssuerURL      string
+	oidcClientID       string
+	oidcPrincipalClaim = "email"
+
+	defaultAuthorizer Authorizer
+)
+
+func init() {
+	for _, cmd := range []string{"vtcombo", "vtctld"} {
+		servenv.OnParseFor(cmd, registerFlags)
+	}
+
+	RegisterAuthorizer("file", func(config string) (Authorizer, error) {
+		return NewFileAuthorizer(config, reloadInterval)
+	})
+}
+
+func registerFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&engineName, "rbac-policy-engine", engineName, fmt.Sprintf("Name of the policy engine authorizing vtctl commands and vtctld RPCs. Leave empty to allow any caller to run any command. Valid values are: %s.", strings.Join(authorizerNames(), ", ")))
+	fs.StringVar(&engineConfig, "rbac-policy-config", engineConfig, "Configuration of the rbac policy engine. For the file engine, the path to the policy file.")
+	fs.DurationVar(&reloadInterval, "rbac-policy-reload-interval", reloadInterval, "How often the file rbac policy engine reloads its policy file. Set to 0 to disable reloading.")
+	fs.StringVar(&oidcIssuerURL, "rbac-oidc-issuer-url", oidcIssuerURL, "URL of the OpenID provider whose id tokens, sent by callers as bearer tokens, authenticate the principal: and claim: subjects of rbac policies. Leave empty to ignore bearer tokens.")
+	fs.StringVar(&oidcClientID, "rbac-oidc-client-id", oidcClientID, "OAuth2 client ID that the id tokens accepted with --rbac-oidc-issuer-url must list in their audience.")
+	fs.StringVar(&oidcPrincipalClaim, "rbac-oidc-principal-claim", oidcPrincipalClaim, "Claim of the id tokens accepted with --rbac-oidc-issuer-url used as the caller's principal.")
+}
+
+func authorizerNames() []string {
+	names := make([]string, 0, len(authorizerFactories))
+	for name := range authorizerFactories {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// Init creates the Authorizer selected by --rbac-policy-engine, and installs the
+// gRPC interceptors that enforce it. It is a no-op if no engine is selected. It
+// must be called before servenv.Run.
+func Init() error {
+	if engineName == "" {
+		return nil
+	}
+
+	if oidcIssuerURL != "" {
+		verifier, err := newOIDCTokenVerifier(oidcIssuerURL, oidcClientID, oidcPrincipalClaim)
+		if err != nil {
+			return err
+		}
+
+		audit.SetTokenVerifier(verifier)
+	}
+
+	factory, ok := authorizerFactories[engineName]
+	if !ok {
+		return fmt.Errorf("unknown rbac policy engine %q, valid values are: %s", engineName, strings.Join(authorizerNames(), ", "))
+	}
+
+	authz, err := factory(engineConfig)
+	if err != nil {
+		return fmt.Errorf("cannot create %s rbac policy engine: %w", engineName, err)
+	}
+
+	defaultAuthorizer = authz
+
+	i := &interceptor{authz: authz}
+	servenv.AddGRPCServerInterceptors(i.stream, i.unary)
+
+	log.Infof("rbac enabled with the %s policy engine", engineName)
+	return nil
+}
+
+// Authorize checks a call that does not go through gRPC, such as a vtctld HTTP
+// API call, against the Authorizer selected by --rbac-policy-engine. It allows
+// every call if no engine is selected.
+func Authorize(ctx context.Context, caller audit.Caller, command string) error {
+	if defaultAuthorizer == nil {
+		return nil
+	}
+
+	return defaultAuthorizer.Authorize(ctx, caller, command)
+}
+
+// SetAuthorizer replaces the Authorizer used by Authorize, and returns a
+// function that restores the previous one. It is meant for tests.
+func SetAuthorizer(authz Authorizer) (restore func()) {
+	previous := defaultAuthorizer
+	defaultAuthorizer = authz
+
+	return func() { defaultAuthorizer = previous }
+}
+
+// newOIDCTokenVerifier returns an audit.TokenVerifier accepting the id tokens
+// of the OpenID provider at issuerURL, issued for clientID. The principal of a
+// token is the value of its principalClaim, and its claims are the ones with a
+// string or a list of strings as value.
+func newOIDCTokenVerifier(issuerURL string, clientID string, principalClaim string) (audit.TokenVerifier, error) {
+	verifier, err := oidc.NewVerifier(issuerURL, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create the rbac oidc token verifier: %w", err)
+	}
+
+	return func(ctx context.Context, token string) (string, map[string][]string, error) {
+		raw, err := verifier.Verify(ctx, token)
+		if err != nil {
+			return "", nil, err
+		}
+
+		claims := make(map[string][]string, len(raw))
+		for name, value := range raw {
+			switch value := value.(type) {
+			case string:
+				claims[name] = []string{value}
+			case []any:
+				for _, v := range value {
+					if v, ok := v.(string); ok {
+						claims[name] = append(claims[name], v)
+					}
+				}
+			}
+		}
+
+		principal, _ := raw[principalClaim].(string)
+		if principal == "" {
+			return "", nil, fmt.Errorf("id token has no %s claim", principalClaim)
+		}
+
+		return principal, claims, nil
+	}, nil
+}
+
+// FileAuthorizer is an Authorizer that enforces a Policy loaded from a file.
+type FileAuthorizer struct {
+	path string
+
+	m      sync.RWMutex
+	policy *Policy
+}
+
+// NewFileAuthorizer returns a FileAuthorizer for the policy file at path. If
+// reloadInterval is positive, the file is reloaded periodically; if a reload
+// fails, the previous policy remains in effect.
+func NewFileAuthorizer(path string, reloadInterval time.Duration) (*FileAuthorizer, error) {
+	if path == "" {
+		return nil, fmt.Errorf("the file rbac policy engine requires --rbac-policy-config to be set to a file path")
+	}
+
+	fa := &FileAuthorizer{path: path}
+	if err := fa.Reload(); err != nil {
+		return nil, err
+	}
+
+	if reloadInterval > 0 {
+		go func() {
+			ticker := time.NewTicker(reloadInterval)
+			defer ticker.Stop()
+
+			for range ticker.C {
+				if err := fa.Reload(); err != nil {
+					log.Errorf("cannot reload rbac policy from %s, keeping the previous one: %v", path, err)
+				}
+			}
+		}()
+	}
+
+	return fa, nil
+}
+
+// Reload reads the policy file again.
+func (fa *FileAuthorizer) Reload() error {
+	policy, err := LoadPolicy(fa.path)
+	if err != nil {
+		return err
+	}
+
+	fa.m.Lock()
+	defer fa.m.Unlock()
+
+	fa.policy = policy
+	return nil
+}
+
+// Authorize is part of the Authorizer interface.
+func (fa *FileAuthorizer) Authorize(ctx context.Context, caller audit.Caller, command string) error {
+	fa.m.RLock()
+	policy := fa.policy
+	fa.m.RUnlock()
+
+	group := GroupForCommand(command)
+	if !policy.Allows(caller, group) {
+		return vterrors.Errorf(vtrpcpb.Code_PERMISSION_DENIED, "%s is not allowed to run %s (group %s)", caller, command, group)
+	}
+
+	return nil
+}
+
+// servicePrefix selects the gRPC methods that get authorized: those of both
+// the Vtctl and the Vtctld services.
+const servicePrefix = "/vtctlservice."
+
+// interceptor enforces an Authorizer on gRPC calls.
+type interceptor struct {
+	authz Authorizer
+}
+
+func (i *interceptor) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if !strings.HasPrefix(info.FullMethod, servicePrefix) {
+		return handler(ctx, req)
+	}
+
+	if err := i.authorize(ctx, info.FullMethod, req); err != nil {
+		return nil, vterrors.ToGRPC(err)
+	}
+
+	return handler(ctx, req)
+}
+
+func (i *interceptor) stream(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if !strings.HasPrefix(info.FullMethod, servicePrefix) {
+		return handler(srv, stream)
+	}
+
+	// The command to run is only known once the request is received, so the
+	// authorization happens when the handler reads it.
+	return handler(srv, &authorizingStream{
+		ServerStream: stream,
+		interceptor:  i,
+		method:       info.FullMethod,
+	})
+}
+
+// authorize checks the caller may run the RPC or, for the Vtctl service and
+// ScheduleCommand, the commands in the request.
+func (i *interceptor) authorize(ctx context.Context, method string, req any) error {
+	caller := audit.CallerFromContext(ctx)
+
+	var commands []string
+	switch req := req.(type) {
+	case *vtctldatapb.ExecuteVtctlCommandRequest:
+		commands = append(commands, firstArg(req.Args))
+	case *vtctldatapb.ExecuteVtctlCommandBatchRequest:
+		for _, cmd := range req.Commands {
+			commands = append(commands, firstArg(cmd.Args))
+		}
+	case *vtctldatapb.ScheduleCommandRequest:
+		// Scheduling a command requires the permission to run it.
+		commands = append(commands, firstArg(req.Args))
+	default:
+		commands = append(commands, method[strings.LastIndex(method, "/")+1:])
+	}
+
+	for _, command := range commands {
+		if err := i.authz.Authorize(ctx, caller, command); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func firstArg(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	return args[0]
+}
+
+// authorizingStream is a grpc.ServerStream that authorizes the first message it
+// receives.
+type authorizingStream struct {
+	grpc.ServerStream
+	interceptor *interceptor
+	method      string
+	authorized  bool
+}
+
+func (s *authorizingStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	if !s.authorized {
+		if err := s.interceptor.authorize(s.Context(), s.method, m); err != nil {
+			return vterrors.ToGRPC(err)
+		}
+		s.authorized = true
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"vitess.io/vitess/go/vt/vtctld/audit"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+func TestFileAuthorizerReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	writePolicy(t, path, testPolicy)
+
+	authz, err := NewFileAuthorizer(path, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	oncall := audit.Caller{Username: "oncall"}
+	require.NoError(t, authz.Authorize(ctx, oncall, "PlannedReparentShard"))
+
+	// Revoke emergency ops from oncall.
+	writePolicy(t, path, `
+rules:
+  - groups: ["read-only", "tablet-ops"]
+    subjects: ["user:oncall"]
+`)
+	assert.Eventually(t, func() bool {
+		return authz.Authorize(ctx, oncall, "PlannedReparentShard") != nil
+	}, 5*time.Second, 10*time.Millisecond, "policy should be reloaded")
+
+	// An invalid policy keeps the previous one in effect.
+	writePolicy(t, path, "rules: [")
+	assert.Error(t, authz.Reload())
+	assert.NoError(t, authz.Authorize(ctx, oncall, "ChangeTabletType"))
+
+	_, err = NewFileAuthorizer("", 0)
+	assert.Error(t, err)
+}
+
+func TestInterceptor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	writePolicy(t, path, testPolicy)
+
+	authz, err := NewFileAuthorizer(path, 0)
+	require.NoError(t, err)
+	i := &interceptor{authz: authz}
+
+	handler := func(ctx context.Context, req any) (any, error) { return "ok", nil }
+
+	resp, err := i.unary(context.Background(), &vtctldatapb.GetKeyspacesRequest{}, &grpc.UnaryServerInfo{FullMethod: "/vtctlservice.Vtctld/GetKeyspaces"}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+
+	_, err = i.unary(context.Background(), &vtctldatapb.DeleteKeyspaceRequest{}, &grpc.UnaryServerInfo{FullMethod: "/vtctlservice.Vtctld/DeleteKeyspace"}, handler)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Scheduled commands are authorized based on the command they schedule.
+	scheduleInfo := &grpc.UnaryServerInfo{FullMethod: "/vtctlservice.Vtctld/ScheduleCommand"}
+
+	_, err = i.unary(context.Background(), &vtctldatapb.ScheduleCommandRequest{Args: []string{"ListAllTablets", "zone1"}}, scheduleInfo, handler)
+	assert.NoError(t, err)
+
+	_, err = i.unary(context.Background(), &vtctldatapb.ScheduleCommandRequest{Args: []string{"DeleteTablet", "zone1-100"}}, scheduleInfo, handler)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Other services are not affected.
+	_, err = i.unary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, handler)
+	assert.NoError(t, err)
+
+	// Legacy commands are authorized based on the command name.
+	streamHandler := func(srv any, stream grpc.ServerStream) error {
+		return stream.RecvMsg(&vtctldatapb.ExecuteVtctlCommandRequest{})
+	}
+	info := &grpc.StreamServerInfo{FullMethod: "/vtctlservice.Vtctl/ExecuteVtctlCommand"}
+
+	err = i.stream(nil, &fakeServerStream{ctx: context.Background(), args: []string{"ListAllTablets", "zone1"}}, info, streamHandler)
+	assert.NoError(t, err)
+
+	err = i.stream(nil, &fakeServerStream{ctx: context.Background(), args: []string{"DeleteTablet", "zone1-100"}}, info, streamHandler)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+// fakeServerStream is a grpc.ServerStream that receives a single
+// ExecuteVtctlCommandRequest.
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	args []string
+}
+
+func (s *fakeServerStream) Context() context.Context { return s.ctx }
+
+func (s *fakeServerStream) RecvMsg(m any) error {
+	m.(*vtctldatapb.ExecuteVtctlCommandRequest).Args = s.args
+	return nil
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package retention enforces the backup retention policy configured on the
+vtctld with the --backup-retention-* flags.
+
+The policy is the default of the PurgeBackups vtctld RPC. When
+--backup-retention-purge-interval is set, the vtctld also purges the backups
+of every shard periodically. Every vtctld doing so removes the same backups,
+so it is enough to set it on one of them.
+*/
+package retention
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/topo"
+)
+
+var (
+	keepFullBackups  int
+	minRetentionTime time.Duration
+	purgeInterval    time.Duration
+
+	backupsPurged = stats.NewCountersWithSingleLabel("BackupsPurged", "Number of backups purged by the backup retention policy of this vtctld, by keyspace", "Keyspace")
+)
+
+func init() {
+	for _, cmd := range []string{"vtcombo", "vtctld"} {
+		servenv.OnParseFor(cmd, registerFlags)
+	}
+}
+
+func registerFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&keepFullBackups, "backup-retention-keep-full-backups", keepFullBackups, "Number of most recent complete full backups of each shard kept by the backup retention policy, along with the incremental backups taken since. The latest complete full backup is always kept.")
+	fs.DurationVar(&minRetentionTime, "backup-retention-min-time", minRetentionTime, "How long backups are kept by the backup retention policy, whatever their number.")
+	fs.DurationVar(&purgeInterval, "backup-retention-purge-interval", purgeInterval, "How often the vtctld purges the backups of every shard according to the backup retention policy. Set to 0 to only purge backups with the PurgeBackups command.")
+}
+
+// DefaultPolicy returns the backup retention policy configured with the
+// --backup-retention-keep-full-backups and --backup-retention-min-time flags.
+func DefaultPolicy() mysqlctl.BackupRetentionPolicy {
+	return mysqlctl.BackupRetentionPolicy{
+		KeepFullBackups:  keepFullBackups,
+		MinRetentionTime: minRetentionTime,
+	}
+}
+
+// Init starts purging backups periodically once the vtctld serves, unless
+// --backup-retention-purge-interval is 0. It must be called before
+// servenv.Run.
+func Init(ts *topo.Server) error {
+	if purgeInterval <= 0 {
+		return nil
+	}
+
+	policy := DefaultPolicy()
+	if policy.IsZero() {
+		return fmt.Errorf("--backup-retention-purge-interval requires --backup-retention-keep-full-backups or --backup-retention-min-time")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	servenv.OnRun(func() {
+		go Loop(ctx, ts, policy, purgeInterval)
+	})
+	servenv.OnTerm(cancel)
+	return nil
+}
+
+// Loop purges the backups of every shard every interval, until the context
+// is done.
+func Loop(ctx context.Context, ts *topo.Server, policy mysqlctl.BackupRetentionPolicy, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := purge(ctx, ts, policy); err != nil {
+			log.Warningf("cannot purge backups: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func purge(ctx context.Context, ts *topo.Server, policy mysqlctl.BackupRetentionPolicy) error {
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return err
+	}
+	defer bs.Close()
+
+	return PurgeAll(ctx, ts, bs, policy)
+}
+
+// PurgeAll purges the backups of every shard according to the policy. The
+// errors of a shard are logged, and do not prevent purging the backups of the
+// other shards.
+func PurgeAll(ctx context.Context, ts *topo.Server, bs backupstorage.BackupStorage, policy mysqlctl.BackupRetentionPolicy) error {
+	keyspaces, err := ts.GetKeyspaces(ctx)
+	if err != nil {
+		return err
+	}
+
+	logger := logutil.NewConsoleLogger()
+	for _, keyspace := range keyspaces {
+		shards, err := ts.GetShardNames(ctx, keyspace)
+		if err != nil {
+			log.Warningf("cannot purge the backups of keyspace %v: %v", keyspace, err)
+			continue
+		}
+
+		for _, shard := range shards {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			purged, _, err := mysqlctl.PurgeBackups(ctx, logger, bs, fmt.Sprintf("%v/%v", keyspace, shard), policy, false)
+			backupsPurged.Add(keyspace, int64(len(purged)))
+			if err != nil {
+				log.Warningf("cannot purge the backups of shard %v/%v: %v", keyspace, shard, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retention
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// addBackup writes a complete full backup of the shard, taken the given
+// number of days ago.
+func addBackup(t *testing.T, bs backupstorage.BackupStorage, dir string, days int) {
+	t.Helper()
+
+	ctx := context.Background()
+	name := fmt.Sprintf("%v.zone1-0000000100", time.Now().Add(-time.Duration(days)*24*time.Hour).UTC().Format(mysqlctl.BackupTimestampFormat))
+	bh, err := bs.StartBackup(ctx, dir, name)
+	require.NoError(t, err)
+
+	manifest := []byte(`{"BackupMethod": "builtin"}`)
+	wc, err := bh.AddFile(ctx, "MANIFEST", int64(len(manifest)))
+	require.NoError(t, err)
+	_, err = wc.Write(manifest)
+	require.NoError(t, err)
+	require.NoError(t, wc.Close())
+	require.NoError(t, bh.EndBackup(ctx))
+}
+
+func TestPurgeAll(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "zone1")
+	for keyspace, shards := range map[string][]string{"ks1": {"-80", "80-"}, "ks2": {"0"}} {
+		require.NoError(t, ts.CreateKeyspace(ctx, keyspace, &topodatapb.Keyspace{}))
+		for _, shard := range shards {
+			require.NoError(t, ts.CreateShard(ctx, keyspace, shard))
+		}
+	}
+
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+	bs := backupstorage.BackupStorageMap["file"]
+	for _, days := range []int{3, 2, 1} {
+		addBackup(t, bs, "ks1/-80", days)
+	}
+	addBackup(t, bs, "ks1/80-", 1)
+	for _, days := range []int{2, 1} {
+		addBackup(t, bs, "ks2/0", days)
+	}
+
+	ks1Purged := backupsPurged.Counts()["ks1"]
+	ks2Purged := backupsPurged.Counts()["ks2"]
+	err := PurgeAll(ctx, ts, bs, mysqlctl.BackupRetentionPolicy{KeepFullBackups: 1})
+	require.NoError(t, err)
+
+	for dir, want := range map[string]int{"ks1/-80": 1, "ks1/80-": 1, "ks2/0": 1} {
+		bhs, err := bs.ListBackups(ctx, dir)
+		require.NoError(t, err)
+		assert.Len(t, bhs, want, dir)
+	}
+	assert.EqualValues(t, 2, backupsPurged.Counts()["ks1"]-ks1Purged)
+	assert.EqualValues(t, 1, backupsPurged.Counts()["ks2"]-ks2Purged)
+}
+
+func TestInit(t *testing.T) {
+	defer func(interval time.Duration) { purgeInterval = interval }(purgeInterval)
+
+	purgeInterval = 0
+	assert.NoError(t, Init(nil))
+
+	purgeInterval = time.Hour
+	assert.ErrorContains(t, Init(nil), "requires --backup-retention-keep-full-backups or --backup-retention-min-time")
+}
@@ -0,0 +1,277 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package scheduler runs the vtctl commands scheduled with the ScheduleCommand
+vtctld RPC, once they are due.
+
+Scheduled commands are stored in the global topo, so every vtctld polls them.
+The first vtctld to mark a due command as running, which is a compare-and-swap
+update of its topo record, is the one that runs it. The outcome and output of
+the command are then written back to its record, which is deleted once
+--scheduled-commands-retention has passed.
+*/
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/topo"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+const (
+	// DefaultActionTimeout bounds the run time of the scheduled commands that
+	// do not set an ActionTimeout.
+	DefaultActionTimeout = time.Hour
+
+	// maxOutputSize is the size of the output kept in the record of a
+	// command. Longer outputs keep their end, which is where errors usually
+	// are.
+	maxOutputSize = 64 * 1024
+)
+
+var (
+	pollInterval = 10 * time.Second
+	retention    = 7 * 24 * time.Hour
+
+	commandsRun = stats.NewCountersWithSingleLabel("ScheduledCommandsRun", "Number of scheduled commands run by this vtctld, by final state", "State")
+)
+
+func init() {
+	for _, cmd := range []string{"vtcombo", "vtctld"} {
+		servenv.OnParseFor(cmd, registerFlags)
+	}
+}
+
+func registerFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(&pollInterval, "scheduled-commands-poll-interval", pollInterval, "How often the vtctld looks for scheduled commands that are due to run. Set to 0 to never run scheduled commands on this vtctld.")
+	fs.DurationVar(&retention, "scheduled-commands-retention", retention, "How long finished scheduled commands are kept in the topo, to be listed by GetScheduledCommands.")
+}
+
+// RunFunc runs a vtctl command, and logs its output to the given logger.
+type RunFunc func(ctx context.Context, logger logutil.Logger, args []string) error
+
+// Scheduler runs the scheduled commands that are due.
+type Scheduler struct {
+	ts  *topo.Server
+	run RunFunc
+	// name identifies this vtctld in the RunBy field of the commands.
+	name      string
+	retention time.Duration
+	now       func() time.Time
+
+	m       sync.Mutex
+	running map[string]bool
+	wg      sync.WaitGroup
+}
+
+// New returns a Scheduler that runs the commands with the given function.
+func New(ts *topo.Server, name string, run RunFunc) *Scheduler {
+	return &Scheduler{
+		ts:        ts,
+		run:       run,
+		name:      name,
+		retention: retention,
+		now:       time.Now,
+		running:   map[string]bool{},
+	}
+}
+
+// Init starts a Scheduler once the vtctld serves, unless
+// --scheduled-commands-poll-interval is 0. It must be called before
+// servenv.Run.
+func Init(ts *topo.Server, run RunFunc) {
+	if pollInterval <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	servenv.OnRun(func() {
+		s := New(ts, servenv.ListeningURL.Host, run)
+		go s.Loop(ctx, pollInterval)
+	})
+	servenv.OnTerm(cancel)
+}
+
+// Loop polls the scheduled commands every interval, until the context is
+// done.
+func (s *Scheduler) Loop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Poll(ctx); err != nil {
+			log.Warningf("cannot poll scheduled commands: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll starts the commands that are due, and deletes the ones that finished
+// more than the retention period ago. It does not wait for the commands it
+// started, see Wait.
+func (s *Scheduler) Poll(ctx context.Context) error {
+	cmds, err := s.ts.GetScheduledCommands(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	for _, cmd := range cmds {
+		switch cmd.State {
+		case vtctldatapb.ScheduledCommand_PENDING:
+			if protoutil.TimeFromProto(cmd.RunAt).After(now) {
+				continue
+			}
+
+			s.start(ctx, cmd)
+		case vtctldatapb.ScheduledCommand_RUNNING:
+			// A command this vtctld is marked as running, but which it does
+			// not run, was interrupted by a restart.
+			if cmd.RunBy == s.name && !s.isRunning(cmd.Id) {
+				s.finish(cmd, "", errors.New("the vtctld running the command restarted before it finished"))
+			}
+		default:
+			if now.Sub(protoutil.TimeFromProto(cmd.FinishTime)) < s.retention {
+				continue
+			}
+
+			if err := s.ts.DeleteScheduledCommand(ctx, cmd.Id); err != nil && !topo.IsErrType(err, topo.NoNode) {
+				log.Warningf("cannot delete finished scheduled command %s: %v", cmd.Id, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Wait waits for the commands started by Poll to finish.
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Scheduler) isRunning(id string) bool {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.running[id]
+}
+
+func (s *Scheduler) setRunning(id string, running bool) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if running {
+		s.running[id] = true
+	} else {
+		delete(s.running, id)
+	}
+}
+
+// start claims and runs a command, unless another vtctld got to it first.
+func (s *Scheduler) start(ctx context.Context, cmd *topo.ScheduledCommandInfo) {
+	cmd.State = vtctldatapb.ScheduledCommand_RUNNING
+	cmd.StartTime = protoutil.TimeToProto(s.now())
+	cmd.RunBy = s.name
+
+	s.setRunning(cmd.Id, true)
+	if err := s.ts.UpdateScheduledCommand(ctx, cmd); err != nil {
+		s.setRunning(cmd.Id, false)
+
+		// A BadVersion error means that another vtctld started the command,
+		// or that it got canceled.
+		if !topo.IsErrType(err, topo.BadVersion) {
+			log.Warningf("cannot start scheduled command %s: %v", cmd.Id, err)
+		}
+		return
+	}
+
+	log.Infof("running scheduled command %s: %v", cmd.Id, cmd.Args)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer s.setRunning(cmd.Id, false)
+
+		output, err := s.execute(ctx, cmd)
+		s.finish(cmd, output, err)
+	}()
+}
+
+func (s *Scheduler) execute(ctx context.Context, cmd *topo.ScheduledCommandInfo) (output string, err error) {
+	defer servenv.HandlePanic("vtctl", &err)
+
+	timeout := DefaultActionTimeout
+	if d, ok, _ := protoutil.DurationFromProto(cmd.ActionTimeout); ok && d > 0 {
+		timeout = d
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	logger := logutil.NewMemoryLogger()
+	err = s.run(ctx, logutil.NewTeeLogger(logger, logutil.NewConsoleLogger()), cmd.Args)
+
+	return logger.String(), err
+}
+
+// finish records the outcome of a command.
+func (s *Scheduler) finish(cmd *topo.ScheduledCommandInfo, output string, err error) {
+	cmd.FinishTime = protoutil.TimeToProto(s.now())
+	cmd.Output = truncateOutput(output)
+	cmd.State = vtctldatapb.ScheduledCommand_SUCCEEDED
+	if err != nil {
+		cmd.State = vtctldatapb.ScheduledCommand_FAILED
+		cmd.Error = err.Error()
+	}
+
+	commandsRun.Add(cmd.State.String(), 1)
+	log.Infof("scheduled command %s finished: %s", cmd.Id, cmd.State)
+
+	// The command may have run past the end of the context it was started in,
+	// but its outcome must still be recorded.
+	ctx, cancel := context.WithTimeout(context.Background(), topo.RemoteOperationTimeout)
+	defer cancel()
+
+	if err := s.ts.UpdateScheduledCommand(ctx, cmd); err != nil {
+		log.Errorf("cannot record the outcome of scheduled command %s (%s): %v", cmd.Id, cmd.State, err)
+	}
+}
+
+func truncateOutput(output string) string {
+	if len(output) <= maxOutputSize {
+		return output
+	}
+
+	return "... (truncated)\n" + output[len(output)-maxOutputSize:]
+}
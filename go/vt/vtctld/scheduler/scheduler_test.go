@@ -0,0 +1,201 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// recordingRunner records the commands it runs, and fails the ones named
+// "Fail".
+type recordingRunner struct {
+	m    sync.Mutex
+	runs [][]string
+}
+
+func (r *recordingRunner) run(ctx context.Context, logger logutil.Logger, args []string) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.runs = append(r.runs, args)
+	logger.Printf("running %s\n", strings.Join(args, " "))
+
+	if args[0] == "Fail" {
+		return errors.New("command failed")
+	}
+
+	return nil
+}
+
+func scheduleCommand(t *testing.T, ts *topo.Server, id string, runAt time.Time, args ...string) {
+	t.Helper()
+
+	err := ts.CreateScheduledCommand(context.Background(), &vtctldatapb.ScheduledCommand{
+		Id:    id,
+		Args:  args,
+		RunAt: protoutil.TimeToProto(runAt),
+		State: vtctldatapb.ScheduledCommand_PENDING,
+	})
+	require.NoError(t, err)
+}
+
+func getState(t *testing.T, ts *topo.Server, id string) *vtctldatapb.ScheduledCommand {
+	t.Helper()
+
+	cmd, err := ts.GetScheduledCommand(context.Background(), id)
+	require.NoError(t, err)
+
+	return cmd.ScheduledCommand
+}
+
+func TestPoll(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+
+	now := time.Now()
+	scheduleCommand(t, ts, "due", now.Add(-time.Second), "ChangeTabletType", "zone1-0000000100", "drained")
+	scheduleCommand(t, ts, "failing", now.Add(-time.Second), "Fail")
+	scheduleCommand(t, ts, "later", now.Add(time.Hour), "RefreshState", "zone1-0000000100")
+
+	runner := &recordingRunner{}
+	s := New(ts, "vtctld1:15000", runner.run)
+	s.now = func() time.Time { return now }
+
+	require.NoError(t, s.Poll(ctx))
+	s.Wait()
+
+	assert.ElementsMatch(t, [][]string{{"ChangeTabletType", "zone1-0000000100", "drained"}, {"Fail"}}, runner.runs)
+
+	due := getState(t, ts, "due")
+	assert.Equal(t, vtctldatapb.ScheduledCommand_SUCCEEDED, due.State)
+	assert.Equal(t, "vtctld1:15000", due.RunBy)
+	assert.Contains(t, due.Output, "running ChangeTabletType zone1-0000000100 drained")
+	assert.Empty(t, due.Error)
+	assert.NotNil(t, due.StartTime)
+	assert.NotNil(t, due.FinishTime)
+
+	failing := getState(t, ts, "failing")
+	assert.Equal(t, vtctldatapb.ScheduledCommand_FAILED, failing.State)
+	assert.Equal(t, "command failed", failing.Error)
+
+	assert.Equal(t, vtctldatapb.ScheduledCommand_PENDING, getState(t, ts, "later").State)
+
+	// Finished commands are not run again, and get deleted once the retention
+	// period is over.
+	now = now.Add(s.retention + time.Hour)
+	require.NoError(t, s.Poll(ctx))
+	s.Wait()
+
+	assert.Len(t, runner.runs, 3)
+	assert.Equal(t, vtctldatapb.ScheduledCommand_SUCCEEDED, getState(t, ts, "later").State)
+
+	_, err := ts.GetScheduledCommand(ctx, "due")
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "finished command should be deleted, got %v", err)
+	_, err = ts.GetScheduledCommand(ctx, "failing")
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "finished command should be deleted, got %v", err)
+}
+
+func TestPollRunsOnce(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+
+	scheduleCommand(t, ts, "due", time.Now().Add(-time.Second), "RefreshState", "zone1-0000000100")
+
+	runner := &recordingRunner{}
+	schedulers := []*Scheduler{
+		New(ts, "vtctld1:15000", runner.run),
+		New(ts, "vtctld2:15000", runner.run),
+		New(ts, "vtctld3:15000", runner.run),
+	}
+
+	var wg sync.WaitGroup
+	for _, s := range schedulers {
+		wg.Add(1)
+		go func(s *Scheduler) {
+			defer wg.Done()
+			assert.NoError(t, s.Poll(ctx))
+		}(s)
+	}
+	wg.Wait()
+
+	for _, s := range schedulers {
+		s.Wait()
+	}
+
+	assert.Len(t, runner.runs, 1, "the command should only run once")
+	assert.Equal(t, vtctldatapb.ScheduledCommand_SUCCEEDED, getState(t, ts, "due").State)
+}
+
+func TestPollInterruptedCommand(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+
+	for _, id := range []string{"mine", "theirs"} {
+		scheduleCommand(t, ts, id, time.Now().Add(-time.Minute), "RefreshState", "zone1-0000000100")
+
+		cmd, err := ts.GetScheduledCommand(ctx, id)
+		require.NoError(t, err)
+
+		cmd.State = vtctldatapb.ScheduledCommand_RUNNING
+		cmd.RunBy = "vtctld1:15000"
+		if id == "theirs" {
+			cmd.RunBy = "vtctld2:15000"
+		}
+		require.NoError(t, ts.UpdateScheduledCommand(ctx, cmd))
+	}
+
+	runner := &recordingRunner{}
+	s := New(ts, "vtctld1:15000", runner.run)
+	require.NoError(t, s.Poll(ctx))
+	s.Wait()
+
+	assert.Empty(t, runner.runs)
+
+	mine := getState(t, ts, "mine")
+	assert.Equal(t, vtctldatapb.ScheduledCommand_FAILED, mine.State)
+	assert.Contains(t, mine.Error, "restarted")
+
+	assert.Equal(t, vtctldatapb.ScheduledCommand_RUNNING, getState(t, ts, "theirs").State)
+}
+
+func TestTruncateOutput(t *testing.T) {
+	assert.Equal(t, "short", truncateOutput("short"))
+
+	long := strings.Repeat("a", maxOutputSize) + "the end"
+	truncated := truncateOutput(long)
+	assert.True(t, strings.HasPrefix(truncated, "... (truncated)\n"))
+	assert.True(t, strings.HasSuffix(truncated, "the end"))
+	assert.Len(t, truncated, len("... (truncated)\n")+maxOutputSize)
+}
@@ -26,7 +26,17 @@ import (
 	"vitess.io/vitess/go/vt/servenv"
 
 	"vitess.io/vitess/go/acl"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vtctl"
+	"vitess.io/vitess/go/vt/vtctl/commandtracker"
+	"vitess.io/vitess/go/vt/vtctld/approval"
+	"vitess.io/vitess/go/vt/vtctld/audit"
+	"vitess.io/vitess/go/vt/vtctld/rbac"
+	"vitess.io/vitess/go/vt/vtctld/retention"
+	"vitess.io/vitess/go/vt/vtctld/scheduler"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
 	"vitess.io/vitess/go/vt/wrangler"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
@@ -52,6 +62,25 @@ func registerVtctldFlags(fs *pflag.FlagSet) {
 
 // InitVtctld initializes all the vtctld functionality.
 func InitVtctld(ts *topo.Server) error {
+	if err := audit.Init(ts); err != nil {
+		log.Errorf("Failed to initialize the audit log: %v", err)
+		return err
+	}
+
+	if err := rbac.Init(); err != nil {
+		log.Errorf("Failed to initialize rbac: %v", err)
+		return err
+	}
+
+	if err := approval.Init(); err != nil {
+		log.Errorf("Failed to initialize the approval hook: %v", err)
+		return err
+	}
+
+	// Track the RPCs, so that they can be listed and canceled like the
+	// legacy vtctl commands.
+	servenv.AddGRPCServerInterceptors(commandtracker.Default.StreamServerInterceptor, commandtracker.Default.UnaryServerInterceptor)
+
 	actionRepo := NewActionRepository(ts)
 
 	// keyspace actions
@@ -128,9 +157,24 @@ func InitVtctld(ts *topo.Server) error {
 			return "", err
 		})
 
+	// Run the commands scheduled with ScheduleCommand once they are due
+	schedulerTMC := tmclient.NewRetryingClient(tmclient.NewTabletManagerClient(), tmclient.RetryPolicyFromFlags())
+	scheduler.Init(ts, func(ctx context.Context, logger logutil.Logger, args []string) error {
+		return vtctl.RunCommand(ctx, wrangler.New(logger, ts, schedulerTMC), args)
+	})
+
+	// Purge the backups of every shard according to the retention policy
+	if err := retention.Init(ts); err != nil {
+		log.Errorf("Failed to initialize the backup retention policy: %v", err)
+		return err
+	}
+
 	// Serve the REST API
 	initAPI(context.Background(), ts, actionRepo)
 
+	// Serve the read-only vtctld RPCs as HTTP/JSON at /api/vtctld
+	initHTTPGateway(ts)
+
 	// Serve the topology endpoint in the REST API at /topodata
 	initExplorer(ts)
 
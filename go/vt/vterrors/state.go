@@ -73,6 +73,7 @@ const (
 
 	// resource exhausted
 	NetPacketTooLarge
+	UserLimitReached
 
 	// cancelled
 	QueryInterrupted
@@ -87,6 +88,7 @@ const (
 
 	// server not available
 	ServerNotAvailable
+	ServerShutdown
 
 	// unknown timezone
 	UnknownTimeZone
@@ -440,6 +440,7 @@ func newTabletEnvironment(ddls []sqlparser.DDLStatement, opts *Options) (*tablet
 	})
 
 	indexRows := make([][]sqltypes.Value, 0, 4)
+	var uniqueKeyRows [][]sqltypes.Value
 	for _, ddl := range ddls {
 		table := sqlparser.String(ddl.GetTable().Name)
 		backtickedTable := sqlescape.EscapeID(sqlescape.UnescapeID(table))
@@ -463,6 +464,16 @@ func newTabletEnvironment(ddls []sqlparser.DDLStatement, opts *Options) (*tablet
 			continue
 		}
 		for _, idx := range ddl.GetTableSpec().Indexes {
+			if idx.Info.Unique && !idx.Info.Primary && len(idx.Columns) > 0 {
+				// MySQL names the unnamed keys after their first column.
+				name := idx.Info.Name.String()
+				if name == "" {
+					name = idx.Columns[0].Column.String()
+				}
+				for _, col := range idx.Columns {
+					uniqueKeyRows = append(uniqueKeyRows, mysql.ShowUniqueKeysRow(table, name, col.Column.String()))
+				}
+			}
 			if !idx.Info.Primary {
 				continue
 			}
@@ -507,6 +518,13 @@ func newTabletEnvironment(ddls []sqlparser.DDLStatement, opts *Options) (*tablet
 		Fields: mysql.ShowPrimaryFields,
 		Rows:   indexRows,
 	})
+	tEnv.addResult(mysql.BaseShowUniqueKeys, &sqltypes.Result{
+		Fields: mysql.ShowUniqueKeysFields,
+		Rows:   uniqueKeyRows,
+	})
+	tEnv.addResult(mysql.BaseShowRoutines, &sqltypes.Result{
+		Fields: mysql.ShowRoutinesFields,
+	})
 
 	return tEnv, nil
 }
@@ -0,0 +1,139 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/acl"
+	"vitess.io/vitess/go/vt/log"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// errDraining is returned by IsHealthy once the vtgate is draining
+var errDraining = errors.New("vtgate is draining")
+
+// drainState records when the vtgate started draining. A draining vtgate accepts no
+// new connections, and only serves the sessions that are in a transaction, so that the
+// clients move to other vtgates once their transactions are finished.
+type drainState struct {
+	mu      sync.Mutex
+	started time.Time
+}
+
+// start returns false if the vtgate was already draining
+func (d *drainState) start() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.started.IsZero() {
+		return false
+	}
+	d.started = time.Now()
+	return true
+}
+
+func (d *drainState) startTime() time.Time {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.started
+}
+
+// DrainStatus is the progress of the drain of a vtgate, as returned by /debug/drain.
+type DrainStatus struct {
+	Draining bool      `json:"draining"`
+	Started  time.Time `json:"started,omitempty"`
+	// OpenConnections is the number of MySQL connections that are still open.
+	OpenConnections int `json:"open_connections"`
+	// BusyConnections is the number of MySQL connections executing a query or in a transaction.
+	BusyConnections int `json:"busy_connections"`
+}
+
+// Drain starts draining the vtgate: its MySQL listeners stop accepting connections,
+// and the connections and gRPC sessions that are not in a transaction get an
+// ER_SERVER_SHUTDOWN error on their next query. It is called when the vtgate
+// receives a SIGTERM, and can be called beforehand through /debug/drain.
+func (vtg *VTGate) Drain() {
+	if !vtg.drain.start() {
+		return
+	}
+	log.Infof("Draining vtgate: new connections are refused, and clients are disconnected once their transactions are finished")
+	if vtg.mysqlServer != nil {
+		vtg.mysqlServer.shutdownListeners()
+	}
+}
+
+// IsDraining returns true once the vtgate started draining.
+func (vtg *VTGate) IsDraining() bool {
+	return !vtg.drain.startTime().IsZero()
+}
+
+// checkDraining returns an error if the vtgate is draining and the session is not
+// in a transaction, to let the client retry on another vtgate.
+func (vtg *VTGate) checkDraining(session *vtgatepb.Session) error {
+	if session.GetInTransaction() || !vtg.IsDraining() {
+		return nil
+	}
+	return vterrors.NewErrorf(vtrpcpb.Code_UNAVAILABLE, vterrors.ServerShutdown, "Server shutdown in progress")
+}
+
+// DrainStatus returns the progress of the drain of the vtgate.
+func (vtg *VTGate) DrainStatus() DrainStatus {
+	status := DrainStatus{Started: vtg.drain.startTime()}
+	status.Draining = !status.Started.IsZero()
+	if vtg.mysqlServer != nil && vtg.mysqlServer.vtgateHandle != nil {
+		status.OpenConnections = vtg.mysqlServer.vtgateHandle.numConnections()
+		status.BusyConnections = int(vtg.mysqlServer.vtgateHandle.busyConnections.Load())
+	}
+	return status
+}
+
+func (vtg *VTGate) registerDebugDrainHandler() {
+	servenv.HTTPHandleFunc("/debug/drain", func(w http.ResponseWriter, r *http.Request) {
+		vtg.debugDrainHandler(w, r)
+	})
+}
+
+// debugDrainHandler returns the progress of the drain of the vtgate, after starting
+// it if the request is a POST.
+func (vtg *VTGate) debugDrainHandler(w http.ResponseWriter, r *http.Request) {
+	role := acl.MONITORING
+	if r.Method == http.MethodPost {
+		role = acl.ADMIN
+	}
+	if err := acl.CheckAccessHTTP(r, role); err != nil {
+		acl.SendError(w, err)
+		return
+	}
+	if r.Method == http.MethodPost {
+		vtg.Drain()
+	}
+
+	data, err := json.MarshalIndent(vtg.DrainStatus(), "", "  ")
+	if err != nil {
+		httpErrorf(w, r, "cannot marshal data: %v", err)
+		return
+	}
+	w.Header().Set("Content-Type", jsonContentType)
+	w.Write(data)
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+func TestVTGateDrain(t *testing.T) {
+	vtg, _, ctx := createVtgateEnv(t)
+
+	session := &vtgatepb.Session{
+		Autocommit:   true,
+		TargetString: KsTestUnsharded + "@primary",
+	}
+	_, _, err := vtg.Execute(ctx, nil, session, "select id from t1", nil)
+	require.NoError(t, err)
+	require.NoError(t, vtg.IsHealthy())
+	assert.False(t, vtg.DrainStatus().Draining)
+
+	vtg.Drain()
+	require.True(t, vtg.IsDraining())
+	require.Equal(t, errDraining, vtg.IsHealthy())
+	started := vtg.DrainStatus().Started
+	require.False(t, started.IsZero())
+
+	// draining twice keeps the start of the drain
+	vtg.Drain()
+	assert.Equal(t, started, vtg.DrainStatus().Started)
+
+	_, _, err = vtg.Execute(ctx, nil, session, "select id from t1", nil)
+	require.Error(t, err)
+	assert.Equal(t, vtrpcpb.Code_UNAVAILABLE, vterrors.Code(err))
+	sqlErr := sqlerror.NewSQLErrorFromError(err).(*sqlerror.SQLError)
+	assert.Equal(t, sqlerror.ERServerShutdown, sqlErr.Number())
+
+	// the sessions in a transaction are served until the transaction is finished
+	session.InTransaction = true
+	_, _, err = vtg.Execute(ctx, nil, session, "select id from t1", nil)
+	require.NoError(t, err)
+}
+
+func TestVTGateDebugDrainHandler(t *testing.T) {
+	vtg, _, _ := createVtgateEnv(t)
+
+	get := func(method string) DrainStatus {
+		resp := httptest.NewRecorder()
+		vtg.debugDrainHandler(resp, httptest.NewRequest(method, "/debug/drain", nil))
+		require.Equal(t, http.StatusOK, resp.Code)
+		require.Equal(t, jsonContentType, resp.Header().Get("Content-Type"))
+		var status DrainStatus
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &status))
+		return status
+	}
+
+	assert.False(t, get(http.MethodGet).Draining)
+	assert.False(t, vtg.IsDraining())
+
+	status := get(http.MethodPost)
+	assert.True(t, status.Draining)
+	assert.False(t, status.Started.IsZero())
+	assert.True(t, vtg.IsDraining())
+	assert.True(t, get(http.MethodGet).Draining)
+}
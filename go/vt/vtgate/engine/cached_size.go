@@ -123,7 +123,7 @@ func (cached *DDL) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(64)
+		size += int64(80)
 	}
 	// field Keyspace *vitess.io/vitess/go/vt/vtgate/vindexes.Keyspace
 	size += cached.Keyspace.CachedSize(true)
@@ -133,6 +133,8 @@ func (cached *DDL) CachedSize(alloc bool) int64 {
 	if cc, ok := cached.DDL.(cachedObject); ok {
 		size += cc.CachedSize(true)
 	}
+	// field DDLStrategy string
+	size += hack.RuntimeAllocSize(int64(len(cached.DDLStrategy)))
 	// field NormalDDL *vitess.io/vitess/go/vt/vtgate/engine.Send
 	size += cached.NormalDDL.CachedSize(true)
 	// field OnlineDDL *vitess.io/vitess/go/vt/vtgate/engine.OnlineDDL
@@ -416,7 +418,7 @@ func (cached *Insert) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(240)
+		size += int64(264)
 	}
 	// field Keyspace *vitess.io/vitess/go/vt/vtgate/vindexes.Keyspace
 	size += cached.Keyspace.CachedSize(true)
@@ -476,6 +478,43 @@ func (cached *Insert) CachedSize(alloc bool) int64 {
 	if cc, ok := cached.Input.(cachedObject); ok {
 		size += cc.CachedSize(true)
 	}
+	// field Pinned []byte
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Pinned)))
+	}
+	return size
+}
+
+func (cached *JSONTable) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(88)
+	}
+	// field Input vitess.io/vitess/go/vt/vtgate/engine.Primitive
+	if cc, ok := cached.Input.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field Doc vitess.io/vitess/go/vt/vtgate/evalengine.Expr
+	if cc, ok := cached.Doc.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field Columns []*vitess.io/vitess/go/vt/vtgate/evalengine.JSONTableColumn
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Columns)) * int64(8))
+		for _, elem := range cached.Columns {
+			size += elem.CachedSize(true)
+		}
+	}
+	// field Fields []*vitess.io/vitess/go/vt/proto/query.Field
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Fields)) * int64(8))
+		for _, elem := range cached.Fields {
+			size += elem.CachedSize(true)
+		}
+	}
 	return size
 }
 
@@ -569,7 +608,7 @@ func (cached *LockFunc) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(24)
+		size += int64(40)
 	}
 	// field Typ *vitess.io/vitess/go/vt/sqlparser.LockingFunc
 	size += cached.Typ.CachedSize(true)
@@ -577,6 +616,10 @@ func (cached *LockFunc) CachedSize(alloc bool) int64 {
 	if cc, ok := cached.Name.(cachedObject); ok {
 		size += cc.CachedSize(true)
 	}
+	// field Timeout vitess.io/vitess/go/vt/vtgate/evalengine.Expr
+	if cc, ok := cached.Timeout.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
 	return size
 }
 func (cached *MStream) CachedSize(alloc bool) int64 {
@@ -778,6 +821,38 @@ func (cached *PulloutSubquery) CachedSize(alloc bool) int64 {
 	}
 	return size
 }
+func (cached *RecursiveCTE) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(48)
+	}
+	// field Seed vitess.io/vitess/go/vt/vtgate/engine.Primitive
+	if cc, ok := cached.Seed.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field Recursive vitess.io/vitess/go/vt/vtgate/engine.Primitive
+	if cc, ok := cached.Recursive.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field Vars map[string]int
+	if cached.Vars != nil {
+		size += int64(48)
+		hmap := reflect.ValueOf(cached.Vars)
+		numBuckets := int(math.Pow(2, float64((*(*uint8)(unsafe.Pointer(hmap.Pointer() + uintptr(9)))))))
+		numOldBuckets := (*(*uint16)(unsafe.Pointer(hmap.Pointer() + uintptr(10))))
+		size += hack.RuntimeAllocSize(int64(numOldBuckets * 208))
+		if len(cached.Vars) > 0 || numBuckets > 1 {
+			size += hack.RuntimeAllocSize(int64(numBuckets * 208))
+		}
+		for k := range cached.Vars {
+			size += hack.RuntimeAllocSize(int64(len(k)))
+		}
+	}
+	return size
+}
 func (cached *RenameFields) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -1390,6 +1465,57 @@ func (cached *VitessMetadata) CachedSize(alloc bool) int64 {
 	size += hack.RuntimeAllocSize(int64(len(cached.Value)))
 	return size
 }
+func (cached *Window) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(112)
+	}
+	// field Input vitess.io/vitess/go/vt/vtgate/engine.Primitive
+	if cc, ok := cached.Input.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field PartitionBy []vitess.io/vitess/go/vt/vtgate/engine.CheckCol
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.PartitionBy)) * int64(22))
+		for _, elem := range cached.PartitionBy {
+			size += elem.CachedSize(false)
+		}
+	}
+	// field OrderBy []vitess.io/vitess/go/vt/vtgate/engine.CheckCol
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.OrderBy)) * int64(22))
+		for _, elem := range cached.OrderBy {
+			size += elem.CachedSize(false)
+		}
+	}
+	// field Funcs []*vitess.io/vitess/go/vt/vtgate/engine.WindowFunc
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Funcs)) * int64(8))
+		for _, elem := range cached.Funcs {
+			size += elem.CachedSize(true)
+		}
+	}
+	// field Cols []int
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Cols)) * int64(8))
+	}
+	return size
+}
+func (cached *WindowFunc) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(56)
+	}
+	// field Alias string
+	size += hack.RuntimeAllocSize(int64(len(cached.Alias)))
+	return size
+}
 
 //go:nocheckptr
 func (cached *shardRoute) CachedSize(alloc bool) int64 {
@@ -35,6 +35,9 @@ type DDL struct {
 	Keyspace *vindexes.Keyspace
 	SQL      string
 	DDL      sqlparser.DDLStatement
+	// DDLStrategy is the strategy of the DDL_STRATEGY comment directive of the statement,
+	// which overrides the strategy of the session when set.
+	DDLStrategy string
 
 	NormalDDL *Send
 	OnlineDDL *OnlineDDL
@@ -56,6 +59,9 @@ func (ddl *DDL) description() PrimitiveDescription {
 	if ddl.CreateTempTable {
 		other["TempTable"] = true
 	}
+	if ddl.DDLStrategy != "" {
+		other["DDLStrategy"] = ddl.DDLStrategy
+	}
 	return PrimitiveDescription{
 		OperatorType: "DDL",
 		Keyspace:     ddl.Keyspace,
@@ -91,11 +97,20 @@ func (ddl *DDL) isOnlineSchemaDDL() bool {
 func (ddl *DDL) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*query.BindVariable, wantfields bool) (result *sqltypes.Result, err error) {
 	if ddl.CreateTempTable {
 		vcursor.Session().HasCreatedTempTable()
-		vcursor.Session().NeedsReservedConn()
-		return vcursor.ExecutePrimitive(ctx, ddl.NormalDDL, bindVars, wantfields)
+		vcursor.Session().PinReservedConn()
+		result, err = vcursor.ExecutePrimitive(ctx, ddl.NormalDDL, bindVars, wantfields)
+		if err != nil || !ddl.Keyspace.Sharded {
+			return result, err
+		}
+		ddl.trackTempTables(vcursor.Session())
+		return result, nil
 	}
 
-	ddlStrategySetting, err := schema.ParseDDLStrategy(vcursor.Session().GetDDLStrategy())
+	ddlStrategy := vcursor.Session().GetDDLStrategy()
+	if ddl.DDLStrategy != "" {
+		ddlStrategy = ddl.DDLStrategy
+	}
+	ddlStrategySetting, err := schema.ParseDDLStrategy(ddlStrategy)
 	if err != nil {
 		return nil, err
 	}
@@ -115,6 +130,19 @@ func (ddl *DDL) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[st
 	}
 }
 
+// trackTempTables records the temporary tables created in the sharded keyspace,
+// so that their queries are routed to the shard they live on.
+func (ddl *DDL) trackTempTables(session SessionActions) {
+	switch stmt := ddl.DDL.(type) {
+	case *sqlparser.CreateTable:
+		session.AddTempTable(ddl.Keyspace.Name, stmt.Table.Name.String())
+	case *sqlparser.DropTable:
+		for _, tbl := range stmt.FromTables {
+			session.RemoveTempTable(ddl.Keyspace.Name, tbl.Name.String())
+		}
+	}
+}
+
 // TryStreamExecute implements the Primitive interface
 func (ddl *DDL) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*query.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
 	results, err := ddl.TryExecute(ctx, vcursor, bindVars, wantfields)
@@ -43,6 +43,9 @@ import (
 
 var testMaxMemoryRows = 100
 var testIgnoreMaxMemoryRows = false
+var testSpillDir = ""
+var testMaxSpillBytes int64
+var testMaxReadRetries = 0
 
 var _ VCursor = (*noopVCursor)(nil)
 var _ SessionActions = (*noopVCursor)(nil)
@@ -199,6 +202,14 @@ func (t *noopVCursor) HasCreatedTempTable() {
 	panic("implement me")
 }
 
+func (t *noopVCursor) AddTempTable(keyspace, table string) {
+	panic("implement me")
+}
+
+func (t *noopVCursor) RemoveTempTable(keyspace, table string) {
+	panic("implement me")
+}
+
 func (t *noopVCursor) LookupRowLockShardSession() vtgatepb.CommitOrder {
 	panic("implement me")
 }
@@ -215,6 +226,14 @@ func (t *noopVCursor) FindRoutedTable(sqlparser.TableName) (*vindexes.Table, err
 	panic("implement me")
 }
 
+func (t *noopVCursor) UseTopoNamedLocks(keyspace *vindexes.Keyspace) bool {
+	return false
+}
+
+func (t *noopVCursor) ExecuteTopoLock(ctx context.Context, lockFuncType sqlparser.LockingFuncType, name string, timeout time.Duration) (sqltypes.Value, error) {
+	panic("implement me")
+}
+
 func (t *noopVCursor) ExecuteLock(ctx context.Context, rs *srvtopo.ResolvedShard, query *querypb.BoundQuery, lockFuncType sqlparser.LockingFuncType) (*sqltypes.Result, error) {
 	panic("implement me")
 }
@@ -222,6 +241,9 @@ func (t *noopVCursor) ExecuteLock(ctx context.Context, rs *srvtopo.ResolvedShard
 func (t *noopVCursor) NeedsReservedConn() {
 }
 
+func (t *noopVCursor) PinReservedConn() {
+}
+
 func (t *noopVCursor) SetUDV(key string, value any) error {
 	panic("implement me")
 }
@@ -261,6 +283,24 @@ func (t *noopVCursor) GetQueryTimeout(queryTimeoutFromComments int) int {
 	return queryTimeoutFromComments
 }
 
+func (t *noopVCursor) GetScatterOptions(scatterOptionsFromComments ScatterOptions) ScatterOptions {
+	return scatterOptionsFromComments
+}
+
+func (t *noopVCursor) SetScatterConcurrency(int64) {
+}
+
+func (t *noopVCursor) SetShardTimeout(int64) {
+}
+
+func (t *noopVCursor) SetSkipReadRetry(context.Context, bool) error {
+	panic("implement me")
+}
+
+func (t *noopVCursor) SetAllowPartialScatter(context.Context, bool) error {
+	panic("implement me")
+}
+
 func (t *noopVCursor) SetSkipQueryPlanCache(context.Context, bool) error {
 	panic("implement me")
 }
@@ -305,6 +345,18 @@ func (t *noopVCursor) ExceedsMaxMemoryRows(numRows int) bool {
 	return !testIgnoreMaxMemoryRows && numRows > testMaxMemoryRows
 }
 
+func (t *noopVCursor) SpillDir() string {
+	return testSpillDir
+}
+
+func (t *noopVCursor) MaxSpillBytes() int64 {
+	return testMaxSpillBytes
+}
+
+func (t *noopVCursor) MaxReadRetries() int {
+	return testMaxReadRetries
+}
+
 func (t *noopVCursor) GetKeyspace() string {
 	return ""
 }
@@ -371,6 +423,10 @@ type loggingVCursor struct {
 	// multi-shard queries
 	multiShardErrs []error
 
+	// Optional number of times ExecuteMultiShard fails with resultErr on each shard, by
+	// name, alongside the result of the other shards.
+	shardFailures map[string]int
+
 	log []string
 	mu  sync.Mutex
 
@@ -452,6 +508,11 @@ func (f *loggingVCursor) NeedsReservedConn() {
 	f.inReservedConn = true
 }
 
+func (f *loggingVCursor) PinReservedConn() {
+	f.log = append(f.log, "Pins Reserved Conn")
+	f.inReservedConn = true
+}
+
 func (f *loggingVCursor) InReservedConn() bool {
 	return f.inReservedConn
 }
@@ -499,10 +560,21 @@ func (f *loggingVCursor) Execute(ctx context.Context, method string, query strin
 
 func (f *loggingVCursor) ExecuteMultiShard(ctx context.Context, primitive Primitive, rss []*srvtopo.ResolvedShard, queries []*querypb.BoundQuery, rollbackOnError, canAutocommit bool) (*sqltypes.Result, []error) {
 	f.log = append(f.log, fmt.Sprintf("ExecuteMultiShard %v%v %v", printResolvedShardQueries(rss, queries), rollbackOnError, canAutocommit))
+	var shardErrs []error
+	for _, rs := range rss {
+		if f.shardFailures[rs.Target.Shard] > 0 {
+			f.shardFailures[rs.Target.Shard]--
+			RecordShardFailure(ctx, rs.Target)
+			shardErrs = append(shardErrs, f.resultErr)
+		}
+	}
 	res, err := f.nextResult()
 	if err != nil {
 		return nil, []error{err}
 	}
+	if shardErrs != nil {
+		return res, shardErrs
+	}
 
 	return res, f.multiShardErrs
 }
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/sqltypes"
@@ -65,6 +66,10 @@ type HashJoin struct {
 
 // TryExecute implements the Primitive interface
 func (hj *HashJoin) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	if vcursor.SpillDir() != "" {
+		return executeWithSpill(ctx, vcursor, bindVars, wantfields, hj.TryStreamExecute)
+	}
+
 	lresult, err := vcursor.ExecutePrimitive(ctx, hj.Left, bindVars, wantfields)
 	if err != nil {
 		return nil, err
@@ -86,28 +91,11 @@ func (hj *HashJoin) TryExecute(ctx context.Context, vcursor VCursor, bindVars ma
 	}
 
 	for _, currentRHSRow := range rresult.Rows {
-		joinVal := currentRHSRow[hj.RHSKey]
-		if joinVal.IsNull() {
-			continue
-		}
-		hashcode, err := evalengine.NullsafeHashcode(joinVal, hj.Collation, hj.ComparisonType)
+		matches, err := hj.probe(probeTable, currentRHSRow)
 		if err != nil {
 			return nil, err
 		}
-		lftRows := probeTable[hashcode]
-		for _, currentLHSRow := range lftRows {
-			lhsVal := currentLHSRow[hj.LHSKey]
-			// hash codes can give false positives, so we need to check with a real comparison as well
-			cmp, err := evalengine.NullsafeCompare(joinVal, lhsVal, hj.Collation)
-			if err != nil {
-				return nil, err
-			}
-
-			if cmp == 0 {
-				// we have a match!
-				result.Rows = append(result.Rows, joinRows(currentLHSRow, currentRHSRow, hj.Cols))
-			}
-		}
+		result.Rows = append(result.Rows, matches...)
 	}
 
 	return result, nil
@@ -129,12 +117,53 @@ func (hj *HashJoin) buildProbeTable(lresult *sqltypes.Result) (map[evalengine.Ha
 	return probeTable, nil
 }
 
+// probe returns the joined rows of the RHS row with the LHS rows of the probe
+// table it matches.
+func (hj *HashJoin) probe(probeTable map[evalengine.HashCode][]sqltypes.Row, currentRHSRow sqltypes.Row) ([]sqltypes.Row, error) {
+	joinVal := currentRHSRow[hj.RHSKey]
+	if joinVal.IsNull() {
+		return nil, nil
+	}
+	hashcode, err := evalengine.NullsafeHashcode(joinVal, hj.Collation, hj.ComparisonType)
+	if err != nil {
+		return nil, err
+	}
+	var rows []sqltypes.Row
+	lftRows := probeTable[hashcode]
+	for _, currentLHSRow := range lftRows {
+		lhsVal := currentLHSRow[hj.LHSKey]
+		// hash codes can give false positives, so we need to check with a real comparison as well
+		cmp, err := evalengine.NullsafeCompare(joinVal, lhsVal, hj.Collation)
+		if err != nil {
+			return nil, err
+		}
+
+		if cmp == 0 {
+			// we have a match!
+			rows = append(rows, joinRows(currentLHSRow, currentRHSRow, hj.Cols))
+		}
+	}
+	return rows, nil
+}
+
 // TryStreamExecute implements the Primitive interface
 func (hj *HashJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
 	// build the probe table from the LHS result
 	probeTable := map[evalengine.HashCode][]sqltypes.Row{}
+	probeRows := 0
 	var lfields []*querypb.Field
+	// spill receives the rows of both sides once the probe table exceeds the
+	// memory limit, if spilling to disk is enabled.
+	var spill *hashJoinSpill
+	defer func() {
+		if spill != nil {
+			spill.close()
+		}
+	}()
+	var mu sync.Mutex
 	err := vcursor.StreamExecutePrimitive(ctx, hj.Left, bindVars, wantfields, func(result *sqltypes.Result) error {
+		mu.Lock()
+		defer mu.Unlock()
 		if len(lfields) == 0 && len(result.Fields) != 0 {
 			lfields = result.Fields
 		}
@@ -147,7 +176,25 @@ func (hj *HashJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindV
 			if err != nil {
 				return err
 			}
+			if spill != nil {
+				if err := spill.addLeft(hashcode, current); err != nil {
+					return err
+				}
+				continue
+			}
 			probeTable[hashcode] = append(probeTable[hashcode], current)
+			probeRows++
+		}
+		if spill == nil && vcursor.SpillDir() != "" && vcursor.ExceedsMaxMemoryRows(probeRows) {
+			spill = newHashJoinSpill(vcursor.SpillDir(), vcursor.MaxSpillBytes())
+			for hashcode, rows := range probeTable {
+				for _, row := range rows {
+					if err := spill.addLeft(hashcode, row); err != nil {
+						return err
+					}
+				}
+			}
+			probeTable = nil
 		}
 		return nil
 	})
@@ -155,7 +202,9 @@ func (hj *HashJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindV
 		return err
 	}
 
-	return vcursor.StreamExecutePrimitive(ctx, hj.Right, bindVars, wantfields, func(result *sqltypes.Result) error {
+	err = vcursor.StreamExecutePrimitive(ctx, hj.Right, bindVars, wantfields, func(result *sqltypes.Result) error {
+		mu.Lock()
+		defer mu.Unlock()
 		// compare the results coming from the RHS with the probe-table
 		res := &sqltypes.Result{}
 		if len(result.Fields) != 0 {
@@ -164,34 +213,36 @@ func (hj *HashJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindV
 			}
 		}
 		for _, currentRHSRow := range result.Rows {
-			joinVal := currentRHSRow[hj.RHSKey]
-			if joinVal.IsNull() {
-				continue
-			}
-			hashcode, err := evalengine.NullsafeHashcode(joinVal, hj.Collation, hj.ComparisonType)
-			if err != nil {
-				return err
-			}
-			lftRows := probeTable[hashcode]
-			for _, currentLHSRow := range lftRows {
-				lhsVal := currentLHSRow[hj.LHSKey]
-				// hash codes can give false positives, so we need to check with a real comparison as well
-				cmp, err := evalengine.NullsafeCompare(joinVal, lhsVal, hj.Collation)
+			if spill != nil {
+				// the rows are joined once all of them are on disk
+				joinVal := currentRHSRow[hj.RHSKey]
+				if joinVal.IsNull() {
+					continue
+				}
+				hashcode, err := evalengine.NullsafeHashcode(joinVal, hj.Collation, hj.ComparisonType)
 				if err != nil {
 					return err
 				}
-
-				if cmp == 0 {
-					// we have a match!
-					res.Rows = append(res.Rows, joinRows(currentLHSRow, currentRHSRow, hj.Cols))
+				if err := spill.addRight(hashcode, currentRHSRow); err != nil {
+					return err
 				}
+				continue
 			}
+			matches, err := hj.probe(probeTable, currentRHSRow)
+			if err != nil {
+				return err
+			}
+			res.Rows = append(res.Rows, matches...)
 		}
 		if len(res.Rows) != 0 || len(res.Fields) != 0 {
 			return callback(res)
 		}
 		return nil
 	})
+	if err != nil || spill == nil {
+		return err
+	}
+	return spill.join(ctx, hj, callback)
 }
 
 // RouteType implements the Primitive interface
@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+// hashJoinSpillPartitions is the number of partitions the rows of a hash join
+// are split into once they are spilled to disk.
+const hashJoinSpillPartitions = 16
+
+// hashJoinSpill is a grace hash join for the hash joins whose LHS does not fit
+// in memory: the rows of both sides are split into partitions on disk by the
+// hash code of their join value, so that the rows that may match end up in the
+// same partition, and the partitions are then joined one at a time.
+type hashJoinSpill struct {
+	dir      string
+	maxBytes int64
+
+	size        int64
+	left, right [hashJoinSpillPartitions]*spillFile
+}
+
+func newHashJoinSpill(dir string, maxBytes int64) *hashJoinSpill {
+	return &hashJoinSpill{
+		dir:      dir,
+		maxBytes: maxBytes,
+	}
+}
+
+func (s *hashJoinSpill) add(files *[hashJoinSpillPartitions]*spillFile, hashcode evalengine.HashCode, row sqltypes.Row) error {
+	partition := hashcode % hashJoinSpillPartitions
+	if files[partition] == nil {
+		f, err := newSpillFile(s.dir)
+		if err != nil {
+			return err
+		}
+		files[partition] = f
+	}
+	return files[partition].write(row, &s.size, s.maxBytes)
+}
+
+// addLeft adds a row of the LHS, given the hash code of its join value.
+func (s *hashJoinSpill) addLeft(hashcode evalengine.HashCode, row sqltypes.Row) error {
+	return s.add(&s.left, hashcode, row)
+}
+
+// addRight adds a row of the RHS, given the hash code of its join value.
+func (s *hashJoinSpill) addRight(hashcode evalengine.HashCode, row sqltypes.Row) error {
+	return s.add(&s.right, hashcode, row)
+}
+
+// join joins the partitions one at a time: the probe table of the LHS rows of
+// a partition is built in memory, and probed with its RHS rows as they are
+// read. The joined rows are sent to the callback in batches.
+func (s *hashJoinSpill) join(ctx context.Context, hj *HashJoin, callback func(*sqltypes.Result) error) error {
+	batch := make([]sqltypes.Row, 0, sortSpillBatchRows)
+	for partition := range s.left {
+		if s.left[partition] == nil || s.right[partition] == nil {
+			continue
+		}
+
+		r, err := s.left[partition].reader()
+		if err != nil {
+			return err
+		}
+		probeTable := map[evalengine.HashCode][]sqltypes.Row{}
+		for {
+			row, err := readSpilledRow(r)
+			if err != nil {
+				return err
+			}
+			if row == nil {
+				break
+			}
+			hashcode, err := evalengine.NullsafeHashcode(row[hj.LHSKey], hj.Collation, hj.ComparisonType)
+			if err != nil {
+				return err
+			}
+			probeTable[hashcode] = append(probeTable[hashcode], row)
+		}
+
+		r, err = s.right[partition].reader()
+		if err != nil {
+			return err
+		}
+		for {
+			row, err := readSpilledRow(r)
+			if err != nil {
+				return err
+			}
+			if row == nil {
+				break
+			}
+			matches, err := hj.probe(probeTable, row)
+			if err != nil {
+				return err
+			}
+			batch = append(batch, matches...)
+			if len(batch) >= sortSpillBatchRows {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				if err := callback(&sqltypes.Result{Rows: batch}); err != nil {
+					return err
+				}
+				batch = make([]sqltypes.Row, 0, sortSpillBatchRows)
+			}
+		}
+	}
+	if len(batch) == 0 {
+		return nil
+	}
+	return callback(&sqltypes.Result{Rows: batch})
+}
+
+// close removes the partitions from the disk.
+func (s *hashJoinSpill) close() {
+	for _, files := range []*[hashJoinSpillPartitions]*spillFile{&s.left, &s.right} {
+		for i, f := range files {
+			if f != nil {
+				f.remove()
+				files[i] = nil
+			}
+		}
+	}
+}
@@ -18,6 +18,8 @@ package engine
 
 import (
 	"context"
+	"os"
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -145,3 +147,84 @@ func TestHashJoinExecuteDifferentType(t *testing.T) {
 		"5|c| 5.0toto|g",
 	))
 }
+
+func TestHashJoinStreamSpill(t *testing.T) {
+	saveMax := testMaxMemoryRows
+	saveDir := testSpillDir
+	testMaxMemoryRows = 2
+	testSpillDir = t.TempDir()
+	defer func() {
+		testMaxMemoryRows = saveMax
+		testSpillDir = saveDir
+	}()
+
+	leftPrim := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(
+				sqltypes.MakeTestFields(
+					"col1|col2|col3",
+					"int64|varchar|varchar",
+				),
+				"1|a|aa",
+				"2|b|bb",
+				"3|c|cc",
+				"null|d|dd",
+				"3|e|ee",
+			),
+		},
+	}
+	rightPrim := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(
+				sqltypes.MakeTestFields(
+					"col4|col5|col6",
+					"int64|varchar|varchar",
+				),
+				"1|d|dd",
+				"3|e|ee",
+				"4|f|ff",
+				"3|g|gg",
+				"null|h|hh",
+			),
+		},
+	}
+
+	jn := &HashJoin{
+		Opcode: InnerJoin,
+		Left:   leftPrim,
+		Right:  rightPrim,
+		Cols:   []int{-1, -2, 1, 2},
+		LHSKey: 0,
+		RHSKey: 0,
+	}
+	var fields []*querypb.Field
+	var rows []sqltypes.Row
+	err := jn.TryStreamExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, true, func(qr *sqltypes.Result) error {
+		if len(qr.Fields) != 0 {
+			fields = qr.Fields
+		}
+		rows = append(rows, qr.Rows...)
+		return nil
+	})
+	require.NoError(t, err)
+	// the partitions are joined in the order of their hash codes
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i][1].ToString()+rows[i][3].ToString() < rows[j][1].ToString()+rows[j][3].ToString()
+	})
+	expectResult(t, "jn.StreamExecute", &sqltypes.Result{Fields: fields, Rows: rows}, sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"col1|col2|col4|col5",
+			"int64|varchar|int64|varchar",
+		),
+		"1|a|1|d",
+		"3|c|3|e",
+		"3|c|3|g",
+		"3|e|3|e",
+		"3|e|3|g",
+	))
+
+	// The partitions are removed from the disk.
+	entries, err := os.ReadDir(testSpillDir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
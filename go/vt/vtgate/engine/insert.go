@@ -18,6 +18,7 @@ package engine
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"strconv"
@@ -101,6 +102,10 @@ type (
 		// This will avoid locking by the select table.
 		ForceNonStreaming bool
 
+		// Pinned is the keyspace id of the table if it is pinned in a sharded keyspace.
+		// The InsertUnsharded plans send their query to the shard of this keyspace id.
+		Pinned []byte
+
 		// Insert needs tx handling
 		txNeeded
 	}
@@ -942,6 +947,9 @@ func (ins *Insert) description() PrimitiveDescription {
 		"InsertIgnore":         ins.Ignore,
 		"InputAsNonStreaming":  ins.ForceNonStreaming,
 	}
+	if ins.Pinned != nil {
+		other["Pinned"] = hex.EncodeToString(ins.Pinned)
+	}
 
 	if len(ins.VindexValues) > 0 {
 		valuesOffsets := map[string]string{}
@@ -1010,7 +1018,11 @@ func (ins *Insert) executeUnshardedTableQuery(ctx context.Context, vcursor VCurs
 		return 0, nil, err
 	}
 
-	rss, _, err := vcursor.ResolveDestinations(ctx, ins.Keyspace.Name, nil, []key.Destination{key.DestinationAllShards{}})
+	var dest key.Destination = key.DestinationAllShards{}
+	if ins.Pinned != nil {
+		dest = key.DestinationKeyspaceID(ins.Pinned)
+	}
+	rss, _, err := vcursor.ResolveDestinations(ctx, ins.Keyspace.Name, nil, []key.Destination{dest})
 	if err != nil {
 		return 0, nil, err
 	}
@@ -51,6 +51,10 @@ type Join struct {
 
 // TryExecute performs a non-streaming exec.
 func (jn *Join) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	if vcursor.SpillDir() != "" {
+		return executeWithSpill(ctx, vcursor, bindVars, wantfields, jn.TryStreamExecute)
+	}
+
 	joinVars := make(map[string]*querypb.BindVariable)
 	lresult, err := vcursor.ExecutePrimitive(ctx, jn.Left, bindVars, wantfields)
 	if err != nil {
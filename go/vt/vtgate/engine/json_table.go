@@ -0,0 +1,158 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"vitess.io/vitess/go/mysql/json"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+var _ Primitive = (*JSONTable)(nil)
+
+// JSONTable evaluates a JSON_TABLE that cannot be sent to a single shard. For every row
+// of its Input, the document is evaluated and the row is repeated once per row of the
+// JSON_TABLE, followed by the columns of the JSON_TABLE.
+type JSONTable struct {
+	Input Primitive
+
+	// Doc is the JSON document, evaluated on the rows of the Input.
+	Doc evalengine.Expr
+	// Path is the path of the rows of the JSON_TABLE in the document.
+	Path    *json.Path
+	Columns []*evalengine.JSONTableColumn
+
+	// Fields are the fields of the columns of the JSON_TABLE.
+	Fields []*querypb.Field
+}
+
+// RouteType implements the Primitive interface
+func (jt *JSONTable) RouteType() string {
+	return jt.Input.RouteType()
+}
+
+// GetKeyspaceName implements the Primitive interface
+func (jt *JSONTable) GetKeyspaceName() string {
+	return jt.Input.GetKeyspaceName()
+}
+
+// GetTableName implements the Primitive interface
+func (jt *JSONTable) GetTableName() string {
+	return jt.Input.GetTableName()
+}
+
+// TryExecute implements the Primitive interface
+func (jt *JSONTable) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	qr, err := vcursor.ExecutePrimitive(ctx, jt.Input, bindVars, wantfields)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := jt.rows(evalengine.NewExpressionEnv(ctx, bindVars, vcursor), vcursor, qr.Rows)
+	if err != nil {
+		return nil, err
+	}
+	if vcursor.ExceedsMaxMemoryRows(len(rows)) {
+		return nil, fmt.Errorf("in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
+	}
+	result := &sqltypes.Result{Rows: rows}
+	if wantfields {
+		result.Fields = jt.fields(qr.Fields)
+	}
+	return result, nil
+}
+
+// TryStreamExecute implements the Primitive interface
+func (jt *JSONTable) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
+	return vcursor.StreamExecutePrimitive(ctx, jt.Input, bindVars, wantfields, func(qr *sqltypes.Result) error {
+		rows, err := jt.rows(env, vcursor, qr.Rows)
+		if err != nil {
+			return err
+		}
+		result := &sqltypes.Result{Rows: rows}
+		if qr.Fields != nil {
+			result.Fields = jt.fields(qr.Fields)
+		}
+		return callback(result)
+	})
+}
+
+func (jt *JSONTable) rows(env *evalengine.ExpressionEnv, vcursor VCursor, input []sqltypes.Row) ([]sqltypes.Row, error) {
+	var rows []sqltypes.Row
+	for _, row := range input {
+		env.Row = row
+		doc, err := env.Evaluate(jt.Doc)
+		if err != nil {
+			return nil, err
+		}
+		jtRows, err := evalengine.JSONTable(doc.Value(vcursor.ConnCollation()), jt.Path, jt.Columns)
+		if err != nil {
+			return nil, err
+		}
+		for _, jtRow := range jtRows {
+			out := make(sqltypes.Row, 0, len(row)+len(jtRow))
+			out = append(out, row...)
+			out = append(out, jtRow...)
+			rows = append(rows, out)
+		}
+	}
+	return rows, nil
+}
+
+func (jt *JSONTable) fields(input []*querypb.Field) []*querypb.Field {
+	fields := make([]*querypb.Field, 0, len(input)+len(jt.Fields))
+	fields = append(fields, input...)
+	return append(fields, jt.Fields...)
+}
+
+// GetFields implements the Primitive interface
+func (jt *JSONTable) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	qr, err := jt.Input.GetFields(ctx, vcursor, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	return &sqltypes.Result{Fields: jt.fields(qr.Fields)}, nil
+}
+
+// NeedsTransaction implements the Primitive interface
+func (jt *JSONTable) NeedsTransaction() bool {
+	return jt.Input.NeedsTransaction()
+}
+
+// Inputs implements the Primitive interface
+func (jt *JSONTable) Inputs() []Primitive {
+	return []Primitive{jt.Input}
+}
+
+func (jt *JSONTable) description() PrimitiveDescription {
+	var columns []string
+	for _, field := range jt.Fields {
+		columns = append(columns, field.Name)
+	}
+	return PrimitiveDescription{
+		OperatorType: "JSONTable",
+		Other: map[string]any{
+			"Expression": evalengine.FormatExpr(jt.Doc),
+			"Path":       jt.Path.String(),
+			"Columns":    columns,
+		},
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/json"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+func TestJSONTable(t *testing.T) {
+	path := func(p string) *json.Path {
+		var parser json.PathParser
+		jp, err := parser.ParseBytes([]byte(p))
+		require.NoError(t, err)
+		return jp
+	}
+	jt := &JSONTable{
+		Input: &fakePrimitive{results: []*sqltypes.Result{
+			r("id|doc", "int64|varchar", `1|[{"tag": "a"}, {"tag": "b"}]`, `2|[]`, `3|null`),
+		}},
+		Doc:  evalengine.NewColumn(1, sqltypes.VarChar, collations.CollationUtf8mb4ID),
+		Path: path("$[*]"),
+		Columns: []*evalengine.JSONTableColumn{
+			{Name: "tag", Type: sqltypes.VarChar, Path: path("$.tag")},
+		},
+		Fields: []*querypb.Field{{Name: "tag", Type: sqltypes.VarChar}},
+	}
+
+	qr, err := jt.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+	require.NoError(t, err)
+	utils.MustMatch(t, r("id|doc|tag", "int64|varchar|varchar", `1|[{"tag": "a"}, {"tag": "b"}]|a`, `1|[{"tag": "a"}, {"tag": "b"}]|b`), qr)
+
+	jt.Input.(*fakePrimitive).rewind()
+	qr, err = wrapStreamExecute(jt, &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+	require.NoError(t, err)
+	utils.MustMatch(t, r("id|doc|tag", "int64|varchar|varchar", `1|[{"tag": "a"}, {"tag": "b"}]|a`, `1|[{"tag": "a"}, {"tag": "b"}]|b`), qr)
+}
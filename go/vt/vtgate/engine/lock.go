@@ -19,6 +19,7 @@ package engine
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
 
@@ -54,8 +55,9 @@ type Lock struct {
 }
 
 type LockFunc struct {
-	Typ  *sqlparser.LockingFunc
-	Name evalengine.Expr
+	Typ     *sqlparser.LockingFunc
+	Name    evalengine.Expr
+	Timeout evalengine.Expr
 }
 
 // RouteType is part of the Primitive interface
@@ -79,6 +81,10 @@ func (l *Lock) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[str
 }
 
 func (l *Lock) execLock(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	if vcursor.UseTopoNamedLocks(l.Keyspace) {
+		return l.execTopoLock(ctx, vcursor, bindVars)
+	}
+
 	rss, _, err := vcursor.ResolveDestinations(ctx, l.Keyspace.Name, nil, []key.Destination{l.TargetDestination})
 	if err != nil {
 		return nil, err
@@ -137,6 +143,53 @@ func (l *Lock) execLock(ctx context.Context, vcursor VCursor, bindVars map[strin
 	}, nil
 }
 
+// execTopoLock executes the lock functions with the named locks of the topo.
+// The locks held by the session are tracked by the vtgate, so the advisory
+// locks of the session are not used.
+func (l *Lock) execTopoLock(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
+	var fields []*querypb.Field
+	var rrow sqltypes.Row
+	for _, lf := range l.LockFunctions {
+		var lName string
+		if lf.Name != nil {
+			er, err := env.Evaluate(lf.Name)
+			if err != nil {
+				return nil, err
+			}
+			lName = er.Value(vcursor.ConnCollation()).ToString()
+		}
+		var timeout time.Duration
+		if lf.Timeout != nil {
+			er, err := env.Evaluate(lf.Timeout)
+			if err != nil {
+				return nil, err
+			}
+			seconds, err := er.Value(vcursor.ConnCollation()).ToFloat64()
+			if err != nil {
+				return nil, err
+			}
+			// Like MySQL, a negative timeout waits forever.
+			timeout = time.Duration(seconds * float64(time.Second))
+		}
+
+		res, err := vcursor.ExecuteTopoLock(ctx, lf.Typ.Type, lName, timeout)
+		if err != nil {
+			return nil, err
+		}
+		typ := sqltypes.Int64
+		if lf.Typ.Type == sqlparser.IsUsedLock {
+			typ = sqltypes.VarChar
+		}
+		fields = append(fields, &querypb.Field{Name: sqlparser.String(lf.Typ), Type: typ})
+		rrow = append(rrow, res)
+	}
+	return &sqltypes.Result{
+		Fields: fields,
+		Rows:   []sqltypes.Row{rrow},
+	}, nil
+}
+
 func (lf *LockFunc) execLock(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, rs *srvtopo.ResolvedShard) (*sqltypes.Result, error) {
 	boundQuery := &querypb.BoundQuery{
 		Sql:           fmt.Sprintf("select %s from dual", sqlparser.String(lf.Typ)),
@@ -68,6 +68,10 @@ func (ms *MemorySort) SetTruncateColumnCount(count int) {
 
 // TryExecute satisfies the Primitive interface.
 func (ms *MemorySort) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	if vcursor.SpillDir() != "" {
+		return executeWithSpill(ctx, vcursor, bindVars, wantfields, ms.TryStreamExecute)
+	}
+
 	count, err := ms.fetchCount(ctx, vcursor, bindVars)
 	if err != nil {
 		return nil, err
@@ -109,6 +113,13 @@ func (ms *MemorySort) TryStreamExecute(ctx context.Context, vcursor VCursor, bin
 		comparers: extractSlices(ms.OrderBy),
 		reverse:   true,
 	}
+	// spill receives the rows that exceed the memory limit, if spilling to disk is enabled.
+	var spill *sortSpill
+	defer func() {
+		if spill != nil {
+			spill.close()
+		}
+	}()
 	err = vcursor.StreamExecutePrimitive(ctx, ms.Input, bindVars, wantfields, func(qr *sqltypes.Result) error {
 		if len(qr.Fields) != 0 {
 			if err := cb(&sqltypes.Result{Fields: qr.Fields}); err != nil {
@@ -124,7 +135,22 @@ func (ms *MemorySort) TryStreamExecute(ctx context.Context, vcursor VCursor, bin
 			}
 		}
 		if vcursor.ExceedsMaxMemoryRows(len(sh.rows)) {
-			return fmt.Errorf("in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
+			if spill == nil {
+				if vcursor.SpillDir() == "" {
+					return fmt.Errorf("in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
+				}
+				spill = newSortSpill(vcursor.SpillDir(), vcursor.MaxSpillBytes(), sh.comparers)
+			}
+			// The rows of the heap are written to disk as a sorted run, and
+			// the heap starts over: each run keeps its own first count rows.
+			if err := sh.sortRows(); err != nil {
+				return err
+			}
+			if err := spill.addRun(sh.rows); err != nil {
+				return err
+			}
+			sh.rows = nil
+			sh.reverse = true
 		}
 		return nil
 	})
@@ -134,12 +160,12 @@ func (ms *MemorySort) TryStreamExecute(ctx context.Context, vcursor VCursor, bin
 	if sh.err != nil {
 		return sh.err
 	}
-	// Set ordering to normal for the final ordering.
-	sh.reverse = false
-	sort.Sort(sh)
-	if sh.err != nil {
+	if err := sh.sortRows(); err != nil {
 		// Unreachable.
-		return sh.err
+		return err
+	}
+	if spill != nil {
+		return spill.merge(ctx, sh.rows, count, cb)
 	}
 	return cb(&sqltypes.Result{Rows: sh.rows})
 }
@@ -226,6 +252,13 @@ type sortHeap struct {
 	err       error
 }
 
+// sortRows sorts the rows in the normal order.
+func (sh *sortHeap) sortRows() error {
+	sh.reverse = false
+	sort.Sort(sh)
+	return sh.err
+}
+
 // Len satisfies sort.Interface and heap.Interface.
 func (sh *sortHeap) Len() int {
 	return len(sh.rows)
@@ -18,6 +18,7 @@ package engine
 
 import (
 	"context"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -621,6 +622,125 @@ func TestMemorySortMaxMemoryRows(t *testing.T) {
 	}
 }
 
+func TestMemorySortSpill(t *testing.T) {
+	saveMax := testMaxMemoryRows
+	saveDir := testSpillDir
+	saveMaxBytes := testMaxSpillBytes
+	testMaxMemoryRows = 2
+	testSpillDir = t.TempDir()
+	defer func() {
+		testMaxMemoryRows = saveMax
+		testSpillDir = saveDir
+		testMaxSpillBytes = saveMaxBytes
+	}()
+
+	fields := sqltypes.MakeTestFields(
+		"c1|c2",
+		"varbinary|decimal",
+	)
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(fields, "a|1", "g|2", "a|1"),
+			sqltypes.MakeTestResult(fields, "c|4", "c|3", "n|null"),
+			sqltypes.MakeTestResult(fields, "d|0", "|5"),
+		},
+		allResultsInOneCall: true,
+	}
+
+	ms := &MemorySort{
+		OrderBy: []OrderByParams{{
+			WeightStringCol: -1,
+			Col:             1,
+		}},
+		Input: fp,
+	}
+
+	var rows []sqltypes.Row
+	err := ms.TryStreamExecute(context.Background(), &noopVCursor{}, nil, false, func(qr *sqltypes.Result) error {
+		rows = append(rows, qr.Rows...)
+		return nil
+	})
+	require.NoError(t, err)
+	wantResult := sqltypes.MakeTestResult(fields, "n|null", "d|0", "a|1", "a|1", "g|2", "c|3", "c|4", "|5")
+	utils.MustMatch(t, wantResult.Rows, rows)
+
+	// The spilled runs are removed from the disk.
+	entries, err := os.ReadDir(testSpillDir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	fp.rewind()
+	ms.UpperLimit = evalengine.NewBindVar("__upper_limit", sqltypes.Int64, collations.CollationBinaryID)
+	bv := map[string]*querypb.BindVariable{"__upper_limit": sqltypes.Int64BindVariable(4)}
+	rows = nil
+	err = ms.TryStreamExecute(context.Background(), &noopVCursor{}, bv, false, func(qr *sqltypes.Result) error {
+		rows = append(rows, qr.Rows...)
+		return nil
+	})
+	require.NoError(t, err)
+	utils.MustMatch(t, wantResult.Rows[:4], rows)
+
+	fp.rewind()
+	ms.UpperLimit = nil
+	testMaxSpillBytes = 10
+	err = ms.TryStreamExecute(context.Background(), &noopVCursor{}, nil, false, func(qr *sqltypes.Result) error {
+		return nil
+	})
+	require.EqualError(t, err, "spilled row size exceeded allowed limit of 10 bytes")
+	entries, err = os.ReadDir(testSpillDir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestMemorySortExecuteSpill(t *testing.T) {
+	saveMax := testMaxMemoryRows
+	saveDir := testSpillDir
+	testMaxMemoryRows = 4
+	testSpillDir = t.TempDir()
+	defer func() {
+		testMaxMemoryRows = saveMax
+		testSpillDir = saveDir
+	}()
+
+	fields := sqltypes.MakeTestFields(
+		"c1|c2",
+		"varbinary|decimal",
+	)
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(fields, "a|1", "g|2", "a|1"),
+			sqltypes.MakeTestResult(fields, "c|4", "c|3", "n|null"),
+			sqltypes.MakeTestResult(fields, "d|0", "|5"),
+		},
+		allResultsInOneCall: true,
+	}
+
+	ms := &MemorySort{
+		OrderBy: []OrderByParams{{
+			WeightStringCol: -1,
+			Col:             1,
+		}},
+		UpperLimit: evalengine.NewBindVar("__upper_limit", sqltypes.Int64, collations.CollationBinaryID),
+		Input:      fp,
+	}
+
+	bv := map[string]*querypb.BindVariable{"__upper_limit": sqltypes.Int64BindVariable(4)}
+	result, err := ms.TryExecute(context.Background(), &noopVCursor{}, bv, true)
+	require.NoError(t, err)
+	wantResult := sqltypes.MakeTestResult(fields, "n|null", "d|0", "a|1", "a|1")
+	utils.MustMatch(t, wantResult, result)
+
+	entries, err := os.ReadDir(testSpillDir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	// The sorted rows are still returned in memory, and bounded by the limit.
+	fp.rewind()
+	ms.UpperLimit = nil
+	_, err = ms.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.EqualError(t, err, "in-memory row count exceeded allowed limit of 4")
+}
+
 func TestMemorySortExecuteNoVarChar(t *testing.T) {
 	fields := sqltypes.MakeTestFields(
 		"c1|c2",
@@ -179,3 +179,80 @@ func (code AggregateOpcode) IsDistinct() bool {
 		return false
 	}
 }
+
+// WindowOpcode is the opcode of a window function computed by the Window primitive.
+type WindowOpcode int
+
+// These constants list the possible window function opcodes.
+const (
+	WindowUnassigned = WindowOpcode(iota)
+	WindowRowNumber
+	WindowRank
+	WindowDenseRank
+	WindowPercentRank
+	WindowCumeDist
+	WindowNtile
+	WindowLag
+	WindowLead
+	WindowFirstValue
+	WindowLastValue
+	WindowNthValue
+	_NumOfWindowOpCodes // This line must be last of the opcodes!
+)
+
+var WindowName = map[WindowOpcode]string{
+	WindowRowNumber:   "row_number",
+	WindowRank:        "rank",
+	WindowDenseRank:   "dense_rank",
+	WindowPercentRank: "percent_rank",
+	WindowCumeDist:    "cume_dist",
+	WindowNtile:       "ntile",
+	WindowLag:         "lag",
+	WindowLead:        "lead",
+	WindowFirstValue:  "first_value",
+	WindowLastValue:   "last_value",
+	WindowNthValue:    "nth_value",
+}
+
+func (code WindowOpcode) String() string {
+	name := WindowName[code]
+	if name == "" {
+		name = "ERROR"
+	}
+	return name
+}
+
+// MarshalJSON serializes the WindowOpcode as a JSON string.
+// It's used for testing and diagnostics.
+func (code WindowOpcode) MarshalJSON() ([]byte, error) {
+	return ([]byte)(fmt.Sprintf("\"%s\"", code.String())), nil
+}
+
+// Type returns the sql type of the result of the window function, given the type of its argument
+func (code WindowOpcode) Type(typ *querypb.Type) querypb.Type {
+	switch code {
+	case WindowUnassigned:
+		return sqltypes.Null
+	case WindowRowNumber, WindowRank, WindowDenseRank, WindowNtile:
+		return sqltypes.Uint64
+	case WindowPercentRank, WindowCumeDist:
+		return sqltypes.Float64
+	case WindowLag, WindowLead, WindowFirstValue, WindowLastValue, WindowNthValue:
+		if typ == nil {
+			return sqltypes.Null
+		}
+		return *typ
+	default:
+		panic(code.String()) // we have a unit test checking we never reach here
+	}
+}
+
+// NeedsArgument returns true for the window functions that return a value of their argument
+func (code WindowOpcode) NeedsArgument() bool {
+	switch code {
+	case WindowLag, WindowLead, WindowFirstValue, WindowLastValue, WindowNthValue:
+		return true
+	default:
+		return false
+	}
+}
@@ -24,3 +24,10 @@ func TestCheckAllAggrOpCodes(t *testing.T) {
 		i.Type(nil)
 	}
 }
+
+func TestCheckAllWindowOpCodes(t *testing.T) {
+	// This test is just checking that we never reach the panic when using Type() on valid opcodes
+	for i := WindowOpcode(0); i < _NumOfWindowOpCodes; i++ {
+		i.Type(nil)
+	}
+}
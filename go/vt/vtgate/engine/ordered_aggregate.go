@@ -108,6 +108,10 @@ func (oa *OrderedAggregate) SetTruncateColumnCount(count int) {
 
 // TryExecute is a Primitive function.
 func (oa *OrderedAggregate) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool) (*sqltypes.Result, error) {
+	if vcursor.SpillDir() != "" {
+		return executeWithSpill(ctx, vcursor, bindVars, true, oa.TryStreamExecute)
+	}
+
 	qr, err := oa.execute(ctx, vcursor, bindVars)
 	if err != nil {
 		return nil, err
@@ -77,6 +77,50 @@ func TestOrderedAggregateExecute(t *testing.T) {
 	utils.MustMatch(t, wantResult, result)
 }
 
+func TestOrderedAggregateExecuteSpill(t *testing.T) {
+	saveMax := testMaxMemoryRows
+	saveDir := testSpillDir
+	testMaxMemoryRows = 3
+	testSpillDir = t.TempDir()
+	defer func() {
+		testMaxMemoryRows = saveMax
+		testSpillDir = saveDir
+	}()
+
+	fields := sqltypes.MakeTestFields(
+		"col|count(*)",
+		"varbinary|decimal",
+	)
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			fields,
+			"a|1",
+			"a|1",
+			"b|2",
+			"c|3",
+			"c|4",
+		)},
+	}
+
+	oa := &OrderedAggregate{
+		Aggregates:  []*AggregateParams{NewAggregateParam(AggregateSum, 1, "")},
+		GroupByKeys: []*GroupByParams{{KeyCol: 0}},
+		Input:       fp,
+	}
+
+	// The input is streamed, so only the aggregated rows are held in memory.
+	result, err := oa.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+
+	wantResult := sqltypes.MakeTestResult(
+		fields,
+		"a|2",
+		"b|2",
+		"c|7",
+	)
+	utils.MustMatch(t, wantResult, result)
+}
+
 func TestOrderedAggregateExecuteTruncate(t *testing.T) {
 	fp := &fakePrimitive{
 		results: []*sqltypes.Result{sqltypes.MakeTestResult(
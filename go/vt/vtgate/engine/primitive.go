@@ -55,6 +55,17 @@ type (
 		// if the max memory rows override directive is set to true
 		ExceedsMaxMemoryRows(numRows int) bool
 
+		// SpillDir returns the directory the sorts and hash joins spill the rows exceeding
+		// the maxMemoryRows value to. Spilling is disabled if it is empty.
+		SpillDir() string
+		// MaxSpillBytes returns the maximum number of bytes a query
+		// can spill to disk, 0 meaning no limit.
+		MaxSpillBytes() int64
+
+		// MaxReadRetries returns the maximum number of times a read-only query
+		// that fails on a replica is retried on another tablet, 0 meaning no retries.
+		MaxReadRetries() int
+
 		Execute(ctx context.Context, method string, query string, bindVars map[string]*querypb.BindVariable, rollbackOnError bool, co vtgatepb.CommitOrder) (*sqltypes.Result, error)
 		AutocommitApproval() bool
 
@@ -91,6 +102,14 @@ type (
 
 		ExecuteLock(ctx context.Context, rs *srvtopo.ResolvedShard, query *querypb.BoundQuery, lockFuncType sqlparser.LockingFuncType) (*sqltypes.Result, error)
 
+		// UseTopoNamedLocks returns true if the lock functions on the keyspace
+		// use the named locks of the topo instead of the locks of MySQL.
+		UseTopoNamedLocks(keyspace *vindexes.Keyspace) bool
+
+		// ExecuteTopoLock executes a lock function with the named locks of
+		// the topo, and returns its result.
+		ExecuteTopoLock(ctx context.Context, lockFuncType sqlparser.LockingFuncType, name string, timeout time.Duration) (sqltypes.Value, error)
+
 		InTransactionAndIsDML() bool
 
 		LookupRowLockShardSession() vtgatepb.CommitOrder
@@ -136,6 +155,10 @@ type (
 		// NeedsReservedConn marks this session as needing a dedicated connection to underlying database
 		NeedsReservedConn()
 
+		// PinReservedConn marks this session as needing a dedicated connection to underlying database
+		// that holds state which cannot be replayed on another connection, like temporary tables.
+		PinReservedConn()
+
 		// InReservedConn provides whether this session is using reserved connection
 		InReservedConn() bool
 
@@ -174,6 +197,10 @@ type (
 
 		// HasCreatedTempTable will mark the session as having created temp tables
 		HasCreatedTempTable()
+		// AddTempTable records a temporary table created in a sharded keyspace
+		AddTempTable(keyspace, table string)
+		// RemoveTempTable forgets a temporary table dropped from a sharded keyspace
+		RemoveTempTable(keyspace, table string)
 		GetWarnings() []*querypb.QueryWarning
 
 		// AnyAdvisoryLockTaken returns true of any advisory lock is taken
@@ -200,6 +227,21 @@ type (
 		// SetQueryTimeout sets the query timeout
 		SetQueryTimeout(queryTimeout int64)
 
+		// GetScatterOptions gets the scatter options and takes in the scatter options from comments
+		GetScatterOptions(scatterOptionsFromComments ScatterOptions) ScatterOptions
+
+		// SetScatterConcurrency sets the maximum number of shards a query is sent to at the same time
+		SetScatterConcurrency(concurrency int64)
+
+		// SetShardTimeout sets the timeout of the query sent to each shard
+		SetShardTimeout(shardTimeout int64)
+
+		// SetAllowPartialScatter sets whether scatter queries return the results of the shards that succeeded
+		SetAllowPartialScatter(context.Context, bool) error
+
+		// SetSkipReadRetry sets whether the read-only queries that fail on a replica are not retried on another tablet
+		SetSkipReadRetry(context.Context, bool) error
+
 		// InTransaction returns true if the session has already opened transaction or
 		// will start a transaction on the query execution.
 		InTransaction() bool
@@ -0,0 +1,151 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"sync"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+var readRetries = stats.NewCounter("ReadRetries", "Count of read-only queries retried on another tablet after failing on a replica")
+
+// readRetry keeps the tablets a read-only query failed on, so that its retries
+// are routed to other tablets, and the shards it failed on, so that only these
+// are retried.
+type readRetry struct {
+	mu      sync.Mutex
+	tablets map[string]bool
+	// shards are the shards the last attempt of the query failed on, by
+	// keyspace/shard.
+	shards map[string]bool
+}
+
+// WithReadRetry returns a copy of the context that records the tablets the query fails on,
+// so that the gateway routes its retries to other tablets.
+func WithReadRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readRetryKey, &readRetry{
+		tablets: make(map[string]bool),
+		shards:  make(map[string]bool),
+	})
+}
+
+// AvoidTabletOnRetry records that the query carried by the context failed on the tablet,
+// if the query can be retried.
+func AvoidTabletOnRetry(ctx context.Context, tabletAlias string) {
+	rr, ok := ctx.Value(readRetryKey).(*readRetry)
+	if !ok {
+		return
+	}
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	rr.tablets[tabletAlias] = true
+}
+
+// ShouldAvoidTablet returns true if the query carried by the context already failed on the tablet.
+func ShouldAvoidTablet(ctx context.Context, tabletAlias string) bool {
+	rr, ok := ctx.Value(readRetryKey).(*readRetry)
+	if !ok {
+		return false
+	}
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	return rr.tablets[tabletAlias]
+}
+
+// RecordShardFailure records that the query carried by the context failed on the shard
+// of the target, if the query can be retried.
+func RecordShardFailure(ctx context.Context, target *querypb.Target) {
+	rr, ok := ctx.Value(readRetryKey).(*readRetry)
+	if !ok || target == nil {
+		return
+	}
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	rr.shards[topoproto.KeyspaceShardString(target.Keyspace, target.Shard)] = true
+}
+
+// failedShards returns the indexes of the shards of rss the last attempt of the
+// query carried by the context failed on, and forgets them. It returns false if
+// they can't be told apart, that is if there are fewer of them than errors.
+func failedShards(ctx context.Context, rss []*srvtopo.ResolvedShard, errs []error) ([]int, bool) {
+	rr, ok := ctx.Value(readRetryKey).(*readRetry)
+	if !ok {
+		return nil, false
+	}
+	rr.mu.Lock()
+	shards := rr.shards
+	rr.shards = make(map[string]bool)
+	rr.mu.Unlock()
+
+	var failed []int
+	for i, rs := range rss {
+		if rs.Target != nil && shards[topoproto.KeyspaceShardString(rs.Target.Keyspace, rs.Target.Shard)] {
+			failed = append(failed, i)
+		}
+	}
+	if len(failed) == 0 || len(failed) < len(filterOutNilErrors(errs)) {
+		return nil, false
+	}
+	return failed, true
+}
+
+// maxReadRetries returns the number of times the route can be retried on the shards
+// when it fails. Only the queries on replicas that are neither in a transaction nor
+// in a reserved connection can be retried, as re-running them has no side effect.
+func (route *Route) maxReadRetries(vcursor VCursor, rss []*srvtopo.ResolvedShard) int {
+	if route.Opcode == Next || len(rss) == 0 {
+		return 0
+	}
+	if vcursor.Session().InTransaction() || vcursor.Session().InReservedConn() {
+		return 0
+	}
+	for _, rs := range rss {
+		if rs.Target == nil || rs.Target.TabletType == topodatapb.TabletType_PRIMARY {
+			return 0
+		}
+	}
+	return vcursor.MaxReadRetries()
+}
+
+// canRetryRead returns true if all the errors returned by the shards are
+// caused by the tablets rather than by the query, and can be retried.
+func canRetryRead(ctx context.Context, errs []error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	errs = filterOutNilErrors(errs)
+	if len(errs) == 0 {
+		return false
+	}
+	for _, err := range errs {
+		switch vterrors.Code(err) {
+		case vtrpcpb.Code_UNAVAILABLE, vtrpcpb.Code_CLUSTER_EVENT, vtrpcpb.Code_ABORTED:
+		default:
+			return false
+		}
+	}
+	return true
+}
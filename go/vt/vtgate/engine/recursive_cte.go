@@ -0,0 +1,221 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// recursiveCTEMaxDepth is the number of iterations after which a recursive
+// common table expression is aborted, the default cte_max_recursion_depth of MySQL.
+const recursiveCTEMaxDepth = 1000
+
+var _ Primitive = (*RecursiveCTE)(nil)
+
+// RecursiveCTE evaluates a recursive common table expression that cannot be
+// sent as a whole to a single shard. The Seed is executed once, and the Recursive
+// part is then executed for each row produced by the previous iteration, until
+// an iteration produces no new rows.
+type RecursiveCTE struct {
+	// Seed is the non-recursive part of the common table expression.
+	Seed Primitive
+	// Recursive is the recursive part of the common table expression. It is nil
+	// if the common table expression does not reference itself.
+	Recursive Primitive `json:",omitempty"`
+
+	// Vars defines the bind variables of the Recursive part, and the
+	// columns of the rows of the previous iteration they are set from.
+	Vars map[string]int `json:",omitempty"`
+
+	// Distinct discards the rows that were already produced, as a UNION
+	// between the seed and the recursive part does.
+	Distinct bool `json:",omitempty"`
+}
+
+// RouteType implements the Primitive interface
+func (r *RecursiveCTE) RouteType() string {
+	return "RecursiveCTE"
+}
+
+// GetKeyspaceName implements the Primitive interface
+func (r *RecursiveCTE) GetKeyspaceName() string {
+	if r.Recursive == nil || r.Seed.GetKeyspaceName() == r.Recursive.GetKeyspaceName() {
+		return r.Seed.GetKeyspaceName()
+	}
+	return r.Seed.GetKeyspaceName() + "_" + r.Recursive.GetKeyspaceName()
+}
+
+// GetTableName implements the Primitive interface
+func (r *RecursiveCTE) GetTableName() string {
+	if r.Recursive == nil {
+		return r.Seed.GetTableName()
+	}
+	return r.Seed.GetTableName() + "_" + r.Recursive.GetTableName()
+}
+
+// TryExecute implements the Primitive interface
+func (r *RecursiveCTE) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	result := &sqltypes.Result{}
+	err := r.iterate(ctx, vcursor, bindVars, wantfields, func(qr *sqltypes.Result) error {
+		if qr.Fields != nil {
+			result.Fields = qr.Fields
+		}
+		result.Rows = append(result.Rows, qr.Rows...)
+		if vcursor.ExceedsMaxMemoryRows(len(result.Rows)) {
+			return fmt.Errorf("in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// TryStreamExecute implements the Primitive interface
+func (r *RecursiveCTE) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	return r.iterate(ctx, vcursor, bindVars, wantfields, callback)
+}
+
+// iterate executes the seed and then the recursive part, once per row of the
+// previous iteration, and sends the rows of each iteration to the callback.
+func (r *RecursiveCTE) iterate(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	seed, err := vcursor.ExecutePrimitive(ctx, r.Seed, bindVars, wantfields || r.Distinct)
+	if err != nil {
+		return err
+	}
+
+	var seen *probeTable
+	if r.Distinct {
+		seen = newProbeTable(checkColsFor(seed.Fields))
+	}
+	rows, err := unseenRows(seen, seed.Rows)
+	if err != nil {
+		return err
+	}
+	first := &sqltypes.Result{Rows: rows}
+	if wantfields {
+		first.Fields = seed.Fields
+	}
+	if err := callback(first); err != nil {
+		return err
+	}
+	if r.Recursive == nil {
+		return nil
+	}
+
+	for depth := 1; len(rows) > 0; depth++ {
+		if depth > recursiveCTEMaxDepth {
+			return vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, "recursive query aborted after %d iterations", depth)
+		}
+		var next []sqltypes.Row
+		joinVars := make(map[string]*querypb.BindVariable, len(r.Vars))
+		for _, row := range rows {
+			for k, col := range r.Vars {
+				joinVars[k] = sqltypes.ValueBindVariable(row[col])
+			}
+			qr, err := vcursor.ExecutePrimitive(ctx, r.Recursive, combineVars(bindVars, joinVars), false)
+			if err != nil {
+				return err
+			}
+			next = append(next, qr.Rows...)
+			if vcursor.ExceedsMaxMemoryRows(len(next)) {
+				return fmt.Errorf("in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
+			}
+		}
+		rows, err = unseenRows(seen, next)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		if err := callback(&sqltypes.Result{Rows: rows}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unseenRows returns the rows that were not seen yet, or all of them if seen is nil.
+func unseenRows(seen *probeTable, rows []sqltypes.Row) ([]sqltypes.Row, error) {
+	if seen == nil {
+		return rows, nil
+	}
+	var out []sqltypes.Row
+	for _, row := range rows {
+		exists, err := seen.exists(row)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
+func checkColsFor(fields []*querypb.Field) []CheckCol {
+	cols := make([]CheckCol, len(fields))
+	for i, field := range fields {
+		coll := collations.ID(collations.CollationBinaryID)
+		if sqltypes.IsText(field.Type) {
+			coll = collations.ID(field.Charset)
+		}
+		cols[i] = CheckCol{Col: i, Type: field.Type, Collation: coll}
+	}
+	return cols
+}
+
+// GetFields implements the Primitive interface
+func (r *RecursiveCTE) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return r.Seed.GetFields(ctx, vcursor, bindVars)
+}
+
+// NeedsTransaction implements the Primitive interface
+func (r *RecursiveCTE) NeedsTransaction() bool {
+	return r.Seed.NeedsTransaction() || (r.Recursive != nil && r.Recursive.NeedsTransaction())
+}
+
+// Inputs implements the Primitive interface
+func (r *RecursiveCTE) Inputs() []Primitive {
+	if r.Recursive == nil {
+		return []Primitive{r.Seed}
+	}
+	return []Primitive{r.Seed, r.Recursive}
+}
+
+func (r *RecursiveCTE) description() PrimitiveDescription {
+	other := map[string]any{}
+	if len(r.Vars) > 0 {
+		other["Vars"] = orderedStringIntMap(r.Vars)
+	}
+	if r.Distinct {
+		other["Distinct"] = true
+	}
+	return PrimitiveDescription{
+		OperatorType: "RecursiveCTE",
+		Other:        other,
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+func TestRecursiveCTE(t *testing.T) {
+	// a tree where 1 is the parent of 2 and 3, and 2 is the parent of 4
+	newCTE := func() (*RecursiveCTE, *fakePrimitive) {
+		recursive := &fakePrimitive{results: []*sqltypes.Result{
+			r("id|parent", "int64|int64", "2|1", "3|1"),
+			r("id|parent", "int64|int64", "4|2"),
+			r("id|parent", "int64|int64"),
+			r("id|parent", "int64|int64"),
+		}}
+		return &RecursiveCTE{
+			Seed:      &fakePrimitive{results: []*sqltypes.Result{r("id|parent", "int64|int64", "1|null")}},
+			Recursive: recursive,
+			Vars:      map[string]int{"cte_id": 0},
+		}, recursive
+	}
+	expected := r("id|parent", "int64|int64", "1|null", "2|1", "3|1", "4|2")
+	expectedLog := []string{
+		`Execute cte_id: type:INT64 value:"1" false`,
+		`Execute cte_id: type:INT64 value:"2" false`,
+		`Execute cte_id: type:INT64 value:"3" false`,
+		`Execute cte_id: type:INT64 value:"4" false`,
+	}
+
+	cte, recursive := newCTE()
+	qr, err := cte.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+	require.NoError(t, err)
+	utils.MustMatch(t, expected, qr)
+	utils.MustMatch(t, expectedLog, recursive.log)
+
+	cte, _ = newCTE()
+	qr, err = wrapStreamExecute(cte, &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+	require.NoError(t, err)
+	utils.MustMatch(t, expected, qr)
+}
+
+func TestRecursiveCTEDistinct(t *testing.T) {
+	// the graph has a cycle, 1 -> 2 -> 1, which is only broken by discarding the rows already seen
+	cte := &RecursiveCTE{
+		Seed: &fakePrimitive{results: []*sqltypes.Result{r("id", "int64", "1")}},
+		Recursive: &fakePrimitive{results: []*sqltypes.Result{
+			r("id", "int64", "2"),
+			r("id", "int64", "1"),
+		}},
+		Vars:     map[string]int{"cte_id": 0},
+		Distinct: true,
+	}
+
+	qr, err := cte.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+	require.NoError(t, err)
+	utils.MustMatch(t, r("id", "int64", "1", "2"), qr)
+}
+
+func TestRecursiveCTEMaxDepth(t *testing.T) {
+	var results []*sqltypes.Result
+	for i := 0; i <= recursiveCTEMaxDepth; i++ {
+		results = append(results, r("id", "int64", "1"))
+	}
+	cte := &RecursiveCTE{
+		Seed:      &fakePrimitive{results: []*sqltypes.Result{r("id", "int64", "1")}},
+		Recursive: &fakePrimitive{results: results},
+		Vars:      map[string]int{"cte_id": 0},
+	}
+
+	// the rows are streamed, so the recursion is aborted before the memory limit of the vtgate is reached
+	_, err := wrapStreamExecute(cte, &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+	require.EqualError(t, err, "recursive query aborted after 1001 iterations")
+}
+
+func TestRecursiveCTEWithoutRecursion(t *testing.T) {
+	cte := &RecursiveCTE{
+		Seed: &fakePrimitive{results: []*sqltypes.Result{r("id", "int64", "1", "2")}},
+	}
+
+	qr, err := cte.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+	require.NoError(t, err)
+	utils.MustMatch(t, r("id", "int64", "1", "2"), qr)
+}
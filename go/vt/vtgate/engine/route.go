@@ -22,6 +22,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"vitess.io/vitess/go/mysql/collations"
@@ -73,6 +74,12 @@ type Route struct {
 	// ScatterErrorsAsWarnings is true if results should be returned even if some shards have an error
 	ScatterErrorsAsWarnings bool
 
+	// ScatterConcurrency is the optional maximum number of shards the query is sent to at the same time
+	ScatterConcurrency int
+
+	// ShardTimeout contains the optional timeout (in milliseconds) to apply to the query sent to each shard
+	ShardTimeout int
+
 	// RoutingParameters parameters required for query routing.
 	*RoutingParameters
 
@@ -185,8 +192,45 @@ type cxtKey int
 
 const (
 	IgnoreReserveTxn cxtKey = iota
+	scatterOptionsKey
+	readRetryKey
 )
 
+// ScatterOptions controls how a query is sent to several shards.
+type ScatterOptions struct {
+	// Concurrency is the maximum number of shards the query is sent to at the same time, 0 means no limit.
+	Concurrency int
+	// ShardTimeout is the timeout (in milliseconds) of the query on each shard, 0 means no timeout.
+	ShardTimeout int
+	// AllowPartial returns the results of the shards that succeeded when some shards fail.
+	AllowPartial bool
+}
+
+// addScatterOptions resolves the scatter options of the route against the ones of the session, and adds them
+// to the context it receives, so that they are applied when the query is sent to the shards.
+func (route *Route) addScatterOptions(ctx context.Context, vcursor VCursor) (context.Context, ScatterOptions) {
+	opts := vcursor.Session().GetScatterOptions(ScatterOptions{
+		Concurrency:  route.ScatterConcurrency,
+		ShardTimeout: route.ShardTimeout,
+		AllowPartial: route.ScatterErrorsAsWarnings,
+	})
+	if opts.Concurrency == 0 && opts.ShardTimeout == 0 {
+		return ctx, opts
+	}
+	return WithScatterOptions(ctx, opts), opts
+}
+
+// WithScatterOptions returns a copy of the context that carries the given scatter options.
+func WithScatterOptions(ctx context.Context, opts ScatterOptions) context.Context {
+	return context.WithValue(ctx, scatterOptionsKey, opts)
+}
+
+// GetScatterOptions returns the scatter options carried by the context.
+func GetScatterOptions(ctx context.Context) ScatterOptions {
+	opts, _ := ctx.Value(scatterOptionsKey).(ScatterOptions)
+	return opts
+}
+
 func (route *Route) executeInternal(
 	ctx context.Context,
 	vcursor VCursor,
@@ -237,12 +281,13 @@ func (route *Route) executeShards(
 		}
 	}
 
+	ctx, opts := route.addScatterOptions(ctx, vcursor)
 	queries := getQueries(route.Query, bvs)
-	result, errs := vcursor.ExecuteMultiShard(ctx, route, rss, queries, false /* rollbackOnError */, false /* canAutocommit */)
+	result, errs := route.executeMultiShard(ctx, vcursor, rss, queries)
 
 	if errs != nil {
 		errs = filterOutNilErrors(errs)
-		if !route.ScatterErrorsAsWarnings || len(errs) == len(rss) {
+		if !opts.AllowPartial || len(errs) == len(rss) {
 			return nil, vterrors.Aggregate(errs)
 		}
 
@@ -261,6 +306,54 @@ func (route *Route) executeShards(
 	return route.sort(result)
 }
 
+// executeMultiShard executes the queries on the shards. A read-only query that fails on
+// replicas because of the tablets is retried on the shards it failed on, routed to other
+// tablets, and the results of these shards are merged with the ones of the other shards.
+func (route *Route) executeMultiShard(
+	ctx context.Context,
+	vcursor VCursor,
+	rss []*srvtopo.ResolvedShard,
+	queries []*querypb.BoundQuery,
+) (*sqltypes.Result, []error) {
+	retries := route.maxReadRetries(vcursor, rss)
+	if retries > 0 {
+		ctx = WithReadRetry(ctx)
+	}
+
+	// done holds the results of the shards that are not retried anymore.
+	var done *sqltypes.Result
+	result, errs := vcursor.ExecuteMultiShard(ctx, route, rss, queries, false /* rollbackOnError */, false /* canAutocommit */)
+	for i := 0; i < retries && canRetryRead(ctx, errs); i++ {
+		readRetries.Add(1)
+		// If the shards the query failed on can't be told apart, it is retried
+		// on all the shards of the last attempt.
+		if failed, ok := failedShards(ctx, rss, errs); ok {
+			if done == nil {
+				done = &sqltypes.Result{}
+			}
+			if result != nil {
+				done.AppendResult(result)
+			}
+			failedRss := make([]*srvtopo.ResolvedShard, 0, len(failed))
+			failedQueries := make([]*querypb.BoundQuery, 0, len(failed))
+			for _, j := range failed {
+				failedRss = append(failedRss, rss[j])
+				failedQueries = append(failedQueries, queries[j])
+			}
+			rss, queries = failedRss, failedQueries
+		}
+		result, errs = vcursor.ExecuteMultiShard(ctx, route, rss, queries, false /* rollbackOnError */, false /* canAutocommit */)
+	}
+
+	if done == nil {
+		return result, errs
+	}
+	if result != nil {
+		done.AppendResult(result)
+	}
+	return done, errs
+}
+
 func filterOutNilErrors(errs []error) []error {
 	var errors []error
 	for _, err := range errs {
@@ -328,12 +421,43 @@ func (route *Route) streamExecuteShards(
 		}
 	}
 
+	ctx, opts := route.addScatterOptions(ctx, vcursor)
+	retries := route.maxReadRetries(vcursor, rss)
+	if retries == 0 {
+		return route.streamExecuteShardsOnce(ctx, vcursor, bindVars, wantfields, callback, rss, bvs, opts)
+	}
+
+	// The query is retried only as long as none of its results were sent.
+	ctx = WithReadRetry(ctx)
+	var sent atomic.Bool
+	for i := 0; ; i++ {
+		err := route.streamExecuteShardsOnce(ctx, vcursor, bindVars, wantfields, func(qr *sqltypes.Result) error {
+			sent.Store(true)
+			return callback(qr)
+		}, rss, bvs, opts)
+		if err == nil || i == retries || sent.Load() || !canRetryRead(ctx, []error{err}) {
+			return err
+		}
+		readRetries.Add(1)
+	}
+}
+
+func (route *Route) streamExecuteShardsOnce(
+	ctx context.Context,
+	vcursor VCursor,
+	bindVars map[string]*querypb.BindVariable,
+	wantfields bool,
+	callback func(*sqltypes.Result) error,
+	rss []*srvtopo.ResolvedShard,
+	bvs []map[string]*querypb.BindVariable,
+	opts ScatterOptions,
+) error {
 	if len(route.OrderBy) == 0 {
 		errs := vcursor.StreamExecuteMulti(ctx, route, route.Query, rss, bvs, false /* rollbackOnError */, false /* autocommit */, func(qr *sqltypes.Result) error {
 			return callback(qr.Truncate(route.TruncateColumnCount))
 		})
 		if len(errs) > 0 {
-			if !route.ScatterErrorsAsWarnings || len(errs) == len(rss) {
+			if !opts.AllowPartial || len(errs) == len(rss) {
 				return vterrors.Aggregate(errs)
 			}
 			partialSuccessScatterQueries.Add(1)
@@ -346,7 +470,7 @@ func (route *Route) streamExecuteShards(
 	}
 
 	// There is an order by. We have to merge-sort.
-	return route.mergeSort(ctx, vcursor, bindVars, wantfields, callback, rss, bvs)
+	return route.mergeSort(ctx, vcursor, bindVars, wantfields, callback, rss, bvs, opts.AllowPartial)
 }
 
 func (route *Route) mergeSort(
@@ -357,6 +481,7 @@ func (route *Route) mergeSort(
 	callback func(*sqltypes.Result) error,
 	rss []*srvtopo.ResolvedShard,
 	bvs []map[string]*querypb.BindVariable,
+	allowPartial bool,
 ) error {
 	prims := make([]StreamExecutor, 0, len(rss))
 	for i, rs := range rss {
@@ -370,7 +495,7 @@ func (route *Route) mergeSort(
 	ms := MergeSort{
 		Primitives:              prims,
 		OrderBy:                 route.OrderBy,
-		ScatterErrorsAsWarnings: route.ScatterErrorsAsWarnings,
+		ScatterErrorsAsWarnings: allowPartial,
 	}
 	return vcursor.StreamExecutePrimitive(ctx, &ms, bindVars, wantfields, func(qr *sqltypes.Result) error {
 		return callback(qr.Truncate(route.TruncateColumnCount))
@@ -492,6 +617,12 @@ func (route *Route) description() PrimitiveDescription {
 	if route.QueryTimeout > 0 {
 		other["QueryTimeout"] = route.QueryTimeout
 	}
+	if route.ScatterConcurrency > 0 {
+		other["ScatterConcurrency"] = route.ScatterConcurrency
+	}
+	if route.ShardTimeout > 0 {
+		other["ShardTimeout"] = route.ShardTimeout
+	}
 	return PrimitiveDescription{
 		OperatorType:      "Route",
 		Variant:           route.Opcode.String(),
@@ -30,6 +30,7 @@ import (
 	"vitess.io/vitess/go/mysql/sqlerror"
 	"vitess.io/vitess/go/sqltypes"
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/servenv"
 	"vitess.io/vitess/go/vt/sqlparser"
@@ -1449,6 +1450,124 @@ func TestExecFail(t *testing.T) {
 	})
 }
 
+func TestRouteReadRetry(t *testing.T) {
+	saveMaxReadRetries := testMaxReadRetries
+	testMaxReadRetries = 1
+	defer func() {
+		testMaxReadRetries = saveMaxReadRetries
+	}()
+
+	sel := NewRoute(
+		Scatter,
+		&vindexes.Keyspace{
+			Name:    "ks",
+			Sharded: true,
+		},
+		"dummy_select",
+		"dummy_select_field",
+	)
+
+	// The query fails on the first attempt, and succeeds on the retry.
+	vc := &loggingVCursor{
+		shards:                   []string{"-20", "20-"},
+		results:                  []*sqltypes.Result{nil, defaultSelectResult},
+		resultErr:                vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "tablet is shutting down"),
+		resolvedTargetTabletType: topodatapb.TabletType_REPLICA,
+	}
+	result, err := sel.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.-20: dummy_select {} ks.20-: dummy_select {} false false`,
+		`ExecuteMultiShard ks.-20: dummy_select {} ks.20-: dummy_select {} false false`,
+	})
+	expectResult(t, "sel.Execute", result, defaultSelectResult)
+
+	vc.Rewind()
+	result, err = wrapStreamExecute(sel, vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`StreamExecuteMulti dummy_select ks.-20: {} ks.20-: {} `,
+		`StreamExecuteMulti dummy_select ks.-20: {} ks.20-: {} `,
+	})
+	expectResult(t, "sel.StreamExecute", result, defaultSelectResult)
+
+	// Only the shards the query failed on are retried, and their results are
+	// merged with the ones of the other shards.
+	vc.Rewind()
+	vc.results = []*sqltypes.Result{
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "1"),
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "2"),
+	}
+	vc.shardFailures = map[string]int{"20-": 1}
+	result, err = sel.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.-20: dummy_select {} ks.20-: dummy_select {} false false`,
+		`ExecuteMultiShard ks.20-: dummy_select {} false false`,
+	})
+	expectResult(t, "sel.Execute", result, sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "1", "2"))
+
+	// Along with the errors of the last retry, if it fails again.
+	vc.Rewind()
+	vc.shardFailures = map[string]int{"20-": 2}
+	result, err = sel.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.EqualError(t, err, `tablet is shutting down`)
+	assert.Nil(t, result)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.-20: dummy_select {} ks.20-: dummy_select {} false false`,
+		`ExecuteMultiShard ks.20-: dummy_select {} false false`,
+	})
+	vc.shardFailures = nil
+
+	// The retries are capped.
+	vc.Rewind()
+	vc.results = []*sqltypes.Result{nil, nil, defaultSelectResult}
+	_, err = sel.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.EqualError(t, err, `tablet is shutting down`)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.-20: dummy_select {} ks.20-: dummy_select {} false false`,
+		`ExecuteMultiShard ks.-20: dummy_select {} ks.20-: dummy_select {} false false`,
+	})
+
+	// The errors caused by the query are not retried.
+	vc.Rewind()
+	vc.results = []*sqltypes.Result{nil, defaultSelectResult}
+	vc.resultErr = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "syntax error")
+	_, err = sel.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.EqualError(t, err, `syntax error`)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.-20: dummy_select {} ks.20-: dummy_select {} false false`,
+	})
+
+	// The queries on the primary are not retried.
+	vc.Rewind()
+	vc.resultErr = vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "tablet is shutting down")
+	vc.resolvedTargetTabletType = topodatapb.TabletType_PRIMARY
+	_, err = sel.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.EqualError(t, err, `tablet is shutting down`)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.-20: dummy_select {} ks.20-: dummy_select {} false false`,
+	})
+
+	// The queries in a reserved connection are not retried.
+	vc.Rewind()
+	vc.resolvedTargetTabletType = topodatapb.TabletType_REPLICA
+	vc.inReservedConn = true
+	_, err = wrapStreamExecute(sel, vc, map[string]*querypb.BindVariable{}, false)
+	require.EqualError(t, err, `tablet is shutting down`)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`StreamExecuteMulti dummy_select ks.-20: {} ks.20-: {} `,
+	})
+}
+
 func TestSelectEqualUniqueMultiColumnVindex(t *testing.T) {
 	vindex, _ := vindexes.CreateVindex("region_experimental", "", map[string]string{"region_bytes": "1"})
 	sel := NewRoute(
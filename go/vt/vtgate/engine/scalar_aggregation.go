@@ -77,6 +77,10 @@ func (sa *ScalarAggregate) NeedsTransaction() bool {
 
 // TryExecute implements the Primitive interface
 func (sa *ScalarAggregate) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	if vcursor.SpillDir() != "" {
+		return executeWithSpill(ctx, vcursor, bindVars, wantfields, sa.TryStreamExecute)
+	}
+
 	result, err := vcursor.ExecutePrimitive(ctx, sa.Input, bindVars, wantfields)
 	if err != nil {
 		return nil, err
@@ -293,7 +293,8 @@ func (svs *SysVarReservedConn) Execute(ctx context.Context, vcursor VCursor, env
 		if err != nil {
 			return err
 		}
-		vcursor.Session().NeedsReservedConn()
+		// The setting is only applied to the connections of the destination, it cannot be replayed.
+		vcursor.Session().PinReservedConn()
 		return svs.execSetStatement(ctx, vcursor, rss, env)
 	}
 	needReservedConn, err := svs.checkAndUpdateSysVar(ctx, vcursor, env)
@@ -508,6 +509,28 @@ func (svss *SysVarSetAware) Execute(ctx context.Context, vcursor VCursor, env *e
 			return err
 		}
 		vcursor.Session().SetQueryTimeout(queryTimeout)
+	case sysvars.ScatterConcurrency.Name:
+		concurrency, err := svss.evalAsInt64(env, vcursor)
+		if err != nil {
+			return err
+		}
+		if concurrency < 0 {
+			return vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.WrongValueForVar, "invalid scatter_concurrency: %d", concurrency)
+		}
+		vcursor.Session().SetScatterConcurrency(concurrency)
+	case sysvars.ShardTimeout.Name:
+		shardTimeout, err := svss.evalAsInt64(env, vcursor)
+		if err != nil {
+			return err
+		}
+		if shardTimeout < 0 {
+			return vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.WrongValueForVar, "invalid shard_timeout: %d", shardTimeout)
+		}
+		vcursor.Session().SetShardTimeout(shardTimeout)
+	case sysvars.AllowPartialScatter.Name:
+		err = svss.setBoolSysVar(ctx, env, vcursor.Session().SetAllowPartialScatter)
+	case sysvars.SkipReadRetry.Name:
+		err = svss.setBoolSysVar(ctx, env, vcursor.Session().SetSkipReadRetry)
 	case sysvars.SessionEnableSystemSettings.Name:
 		err = svss.setBoolSysVar(ctx, env, vcursor.Session().SetSessionEnableSystemSettings)
 	case sysvars.Charset.Name, sysvars.Names.Name:
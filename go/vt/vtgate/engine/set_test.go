@@ -243,7 +243,7 @@ func TestSetTable(t *testing.T) {
 		},
 		expectedQueryLog: []string{
 			`ResolveDestinations ks [] Destinations:DestinationAnyShard()`,
-			`Needs Reserved Conn`,
+			`Pins Reserved Conn`,
 			`ExecuteMultiShard ks.-20: set @@x = dummy_expr {} false false`,
 		},
 	}, {
@@ -610,7 +610,7 @@ func TestSysVarSetErr(t *testing.T) {
 
 	expectedQueryLog := []string{
 		`ResolveDestinations ks [] Destinations:DestinationAnyShard()`,
-		"Needs Reserved Conn",
+		"Pins Reserved Conn",
 		`ExecuteMultiShard ks.-20: set @@x = dummy_expr {} false false`,
 	}
 
@@ -0,0 +1,270 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"bufio"
+	"container/heap"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// sortSpillBatchRows is the number of rows of each result sent
+// while the spilled rows are merged.
+const sortSpillBatchRows = 1000
+
+// sortSpill is an external merge sort for the rows of a MemorySort that do not
+// fit in memory: the rows are written to disk in sorted runs, which are merged
+// back in order once all the input has been read.
+type sortSpill struct {
+	dir       string
+	maxBytes  int64
+	comparers []*comparer
+
+	size int64
+	runs []string
+}
+
+func newSortSpill(dir string, maxBytes int64, comparers []*comparer) *sortSpill {
+	return &sortSpill{
+		dir:       dir,
+		maxBytes:  maxBytes,
+		comparers: comparers,
+	}
+}
+
+// addRun writes the rows, which must be sorted, to a new run on disk.
+func (s *sortSpill) addRun(rows []sqltypes.Row) error {
+	f, err := os.CreateTemp(s.dir, "vtgate-sort-")
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot spill the rows of the sort")
+	}
+	s.runs = append(s.runs, f.Name())
+
+	w := bufio.NewWriter(f)
+	var buf []byte
+	for _, row := range rows {
+		buf = appendSpilledRow(buf[:0], row)
+		s.size += int64(len(buf))
+		if s.maxBytes > 0 && s.size > s.maxBytes {
+			_ = f.Close()
+			return vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, "spilled row size exceeded allowed limit of %d bytes", s.maxBytes)
+		}
+		if _, err := w.Write(buf); err != nil {
+			_ = f.Close()
+			return vterrors.Wrapf(err, "cannot spill the rows of the sort")
+		}
+	}
+	if err := w.Flush(); err != nil {
+		_ = f.Close()
+		return vterrors.Wrapf(err, "cannot spill the rows of the sort")
+	}
+	return f.Close()
+}
+
+// merge merges the runs on disk with the sorted rows left in memory, and
+// streams the first count rows of the merge to the callback.
+func (s *sortSpill) merge(ctx context.Context, rows []sqltypes.Row, count int, callback func(*sqltypes.Result) error) error {
+	h := &spillHeap{comparers: s.comparers}
+	for _, name := range s.runs {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := h.add(&spillRun{r: bufio.NewReader(f)}); err != nil {
+			return err
+		}
+	}
+	if err := h.add(&spillRun{rows: rows}); err != nil {
+		return err
+	}
+	heap.Init(h)
+
+	batch := make([]sqltypes.Row, 0, sortSpillBatchRows)
+	for sent := 0; h.Len() > 0 && sent < count; sent++ {
+		if h.err != nil {
+			return h.err
+		}
+		cur := h.runs[0]
+		batch = append(batch, cur.row)
+		if len(batch) == sortSpillBatchRows {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := callback(&sqltypes.Result{Rows: batch}); err != nil {
+				return err
+			}
+			batch = make([]sqltypes.Row, 0, sortSpillBatchRows)
+		}
+		row, err := cur.next()
+		if err != nil {
+			return err
+		}
+		if row == nil {
+			heap.Pop(h)
+			continue
+		}
+		cur.row = row
+		heap.Fix(h, 0)
+	}
+	if h.err != nil {
+		return h.err
+	}
+	if len(batch) == 0 {
+		return nil
+	}
+	return callback(&sqltypes.Result{Rows: batch})
+}
+
+// close removes the runs from the disk.
+func (s *sortSpill) close() {
+	for _, name := range s.runs {
+		_ = os.Remove(name)
+	}
+	s.runs = nil
+}
+
+// appendSpilledRow appends the encoding of the row to buf: the number of values,
+// then the type, the length and the bytes of every value.
+func appendSpilledRow(buf []byte, row sqltypes.Row) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(row)))
+	for _, value := range row {
+		raw := value.Raw()
+		buf = binary.AppendUvarint(buf, uint64(value.Type()))
+		buf = binary.AppendUvarint(buf, uint64(len(raw)))
+		buf = append(buf, raw...)
+	}
+	return buf
+}
+
+// readSpilledRow reads a row written by appendSpilledRow. It returns a nil row
+// at the end of the run.
+func readSpilledRow(r *bufio.Reader) (sqltypes.Row, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	row := make(sqltypes.Row, n)
+	for i := range row {
+		typ, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		size, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		var raw []byte
+		if typ != uint64(querypb.Type_NULL_TYPE) {
+			raw = make([]byte, size)
+			if _, err := io.ReadFull(r, raw); err != nil {
+				return nil, err
+			}
+		}
+		row[i] = sqltypes.MakeTrusted(querypb.Type(typ), raw)
+	}
+	return row, nil
+}
+
+// spillRun is a sorted run of rows, read from the disk or from memory.
+type spillRun struct {
+	r    *bufio.Reader
+	rows []sqltypes.Row
+	row  sqltypes.Row
+}
+
+func (run *spillRun) next() (sqltypes.Row, error) {
+	if run.r != nil {
+		return readSpilledRow(run.r)
+	}
+	if len(run.rows) == 0 {
+		return nil, nil
+	}
+	row := run.rows[0]
+	run.rows = run.rows[1:]
+	return row, nil
+}
+
+// spillHeap orders the runs by their current row.
+type spillHeap struct {
+	runs      []*spillRun
+	comparers []*comparer
+	err       error
+}
+
+func (h *spillHeap) add(run *spillRun) error {
+	row, err := run.next()
+	if err != nil || row == nil {
+		return err
+	}
+	run.row = row
+	h.runs = append(h.runs, run)
+	return nil
+}
+
+// Len satisfies heap.Interface.
+func (h *spillHeap) Len() int {
+	return len(h.runs)
+}
+
+// Less satisfies heap.Interface.
+func (h *spillHeap) Less(i, j int) bool {
+	for _, c := range h.comparers {
+		if h.err != nil {
+			return true
+		}
+		cmp, err := c.compare(h.runs[i].row, h.runs[j].row)
+		if err != nil {
+			h.err = err
+			return true
+		}
+		if cmp != 0 {
+			return cmp < 0
+		}
+	}
+	return true
+}
+
+// Swap satisfies heap.Interface.
+func (h *spillHeap) Swap(i, j int) {
+	h.runs[i], h.runs[j] = h.runs[j], h.runs[i]
+}
+
+// Push satisfies heap.Interface.
+func (h *spillHeap) Push(x any) {
+	h.runs = append(h.runs, x.(*spillRun))
+}
+
+// Pop satisfies heap.Interface.
+func (h *spillHeap) Pop() any {
+	n := len(h.runs)
+	x := h.runs[n-1]
+	h.runs = h.runs[:n-1]
+	return x
+}
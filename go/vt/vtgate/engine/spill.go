@@ -0,0 +1,100 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// executeWithSpill executes a primitive that spills the rows exceeding the
+// maxMemoryRows value to disk through its streaming execution, so that only its
+// result is held in memory rather than its whole input. The result must still
+// fit within the maxMemoryRows value.
+func executeWithSpill(
+	ctx context.Context,
+	vcursor VCursor,
+	bindVars map[string]*querypb.BindVariable,
+	wantfields bool,
+	streamExecute func(context.Context, VCursor, map[string]*querypb.BindVariable, bool, func(*sqltypes.Result) error) error,
+) (*sqltypes.Result, error) {
+	result := &sqltypes.Result{}
+	err := streamExecute(ctx, vcursor, bindVars, wantfields, func(qr *sqltypes.Result) error {
+		result.AppendResult(qr)
+		if vcursor.ExceedsMaxMemoryRows(len(result.Rows)) {
+			return fmt.Errorf("in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// spillFile is a temporary file rows are written to, and read back from once
+// all of them were written.
+type spillFile struct {
+	f *os.File
+	w *bufio.Writer
+}
+
+func newSpillFile(dir string) (*spillFile, error) {
+	f, err := os.CreateTemp(dir, "vtgate-spill-")
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot spill rows to disk")
+	}
+	return &spillFile{f: f, w: bufio.NewWriter(f)}, nil
+}
+
+// write writes the encoded row. size counts the bytes spilled so far, which
+// may not exceed maxBytes if it is set.
+func (sf *spillFile) write(row sqltypes.Row, size *int64, maxBytes int64) error {
+	buf := appendSpilledRow(nil, row)
+	*size += int64(len(buf))
+	if maxBytes > 0 && *size > maxBytes {
+		return vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, "spilled row size exceeded allowed limit of %d bytes", maxBytes)
+	}
+	if _, err := sf.w.Write(buf); err != nil {
+		return vterrors.Wrapf(err, "cannot spill rows to disk")
+	}
+	return nil
+}
+
+// reader flushes the rows written so far, and returns a reader of all of them.
+func (sf *spillFile) reader() (*bufio.Reader, error) {
+	if err := sf.w.Flush(); err != nil {
+		return nil, vterrors.Wrapf(err, "cannot spill rows to disk")
+	}
+	if _, err := sf.f.Seek(0, 0); err != nil {
+		return nil, err
+	}
+	return bufio.NewReader(sf.f), nil
+}
+
+// remove closes and removes the file.
+func (sf *spillFile) remove() {
+	_ = sf.f.Close()
+	_ = os.Remove(sf.f.Name())
+}
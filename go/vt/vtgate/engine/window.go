@@ -0,0 +1,409 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/slice"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/vterrors"
+	. "vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+var _ Primitive = (*Window)(nil)
+
+type (
+	// Window is a primitive that computes window functions over the rows of its input.
+	// All the functions share the same window, and the input must be sorted by the
+	// PartitionBy columns followed by the OrderBy columns, so every partition arrives
+	// as a run of consecutive rows. Only the default window frame is supported.
+	Window struct {
+		Input Primitive
+
+		// PartitionBy are the columns that split the input into partitions
+		PartitionBy []CheckCol
+		// OrderBy are the columns the rows of a partition are sorted by.
+		// Rows that are equal on all of them are peers.
+		OrderBy []CheckCol
+
+		Funcs []*WindowFunc
+
+		// Cols maps every output column to a column of the input,
+		// or is -1 for the columns computed by one of the Funcs
+		Cols []int
+	}
+
+	// WindowFunc is a single window function computed by the Window primitive
+	WindowFunc struct {
+		Opcode WindowOpcode
+		// Col is the output column the result is written to
+		Col int
+		// ArgCol is the input column holding the argument of the function, or -1
+		ArgCol int
+		// DefaultCol is the input column holding the default value of LAG and LEAD, or -1
+		DefaultCol int
+		// N is the number of buckets of NTILE, the offset of LAG and LEAD and the row of NTH_VALUE
+		N     int64
+		Alias string
+	}
+
+	// windowPartition buffers the rows of the current partition until it is complete
+	windowPartition struct {
+		w           *Window
+		partitionBy []CheckCol
+		orderBy     []CheckCol
+		rows        []sqltypes.Row
+	}
+)
+
+// TryExecute implements the Primitive interface
+func (w *Window) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	input, err := vcursor.ExecutePrimitive(ctx, w.Input, bindVars, wantfields)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &sqltypes.Result{}
+	if input.Fields != nil {
+		result.Fields = w.convertFields(input.Fields)
+	}
+
+	p := w.newPartition()
+	for _, row := range input.Rows {
+		out, err := p.add(row)
+		if err != nil {
+			return nil, err
+		}
+		result.Rows = append(result.Rows, out...)
+	}
+	out, err := p.flush()
+	if err != nil {
+		return nil, err
+	}
+	result.Rows = append(result.Rows, out...)
+	return result, nil
+}
+
+// TryStreamExecute implements the Primitive interface
+func (w *Window) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	var mu sync.Mutex
+	p := w.newPartition()
+
+	err := vcursor.StreamExecutePrimitive(ctx, w.Input, bindVars, wantfields, func(input *sqltypes.Result) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		result := &sqltypes.Result{}
+		if input.Fields != nil {
+			result.Fields = w.convertFields(input.Fields)
+		}
+		for _, row := range input.Rows {
+			out, err := p.add(row)
+			if err != nil {
+				return err
+			}
+			result.Rows = append(result.Rows, out...)
+		}
+		if result.Fields == nil && len(result.Rows) == 0 {
+			return nil
+		}
+		return callback(result)
+	})
+	if err != nil {
+		return err
+	}
+
+	out, err := p.flush()
+	if err != nil || len(out) == 0 {
+		return err
+	}
+	return callback(&sqltypes.Result{Rows: out})
+}
+
+// GetFields implements the Primitive interface
+func (w *Window) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	qr, err := w.Input.GetFields(ctx, vcursor, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	return &sqltypes.Result{Fields: w.convertFields(qr.Fields)}, nil
+}
+
+func (w *Window) convertFields(fields []*querypb.Field) []*querypb.Field {
+	out := make([]*querypb.Field, len(w.Cols))
+	for i, col := range w.Cols {
+		if col >= 0 {
+			out[i] = fields[col]
+		}
+	}
+	for _, f := range w.Funcs {
+		field := &querypb.Field{Type: f.Opcode.Type(nil)}
+		if f.ArgCol >= 0 {
+			field = proto.Clone(fields[f.ArgCol]).(*querypb.Field)
+			field.Type = f.Opcode.Type(&field.Type)
+		}
+		field.Name = f.Alias
+		out[f.Col] = field
+	}
+	return out
+}
+
+func (w *Window) newPartition() *windowPartition {
+	return &windowPartition{
+		w:           w,
+		partitionBy: slices.Clone(w.PartitionBy),
+		orderBy:     slices.Clone(w.OrderBy),
+	}
+}
+
+// add adds a row to the partition. If the row starts a new partition,
+// the rows of the previous partition are computed and returned.
+func (p *windowPartition) add(row sqltypes.Row) ([]sqltypes.Row, error) {
+	if len(p.rows) == 0 {
+		p.rows = append(p.rows, row)
+		return nil, nil
+	}
+	same, err := equalOn(p.partitionBy, p.rows[0], row)
+	if err != nil {
+		return nil, err
+	}
+	if same {
+		p.rows = append(p.rows, row)
+		return nil, nil
+	}
+	out, err := p.flush()
+	if err != nil {
+		return nil, err
+	}
+	p.rows = append(p.rows, row)
+	return out, nil
+}
+
+// flush computes the window functions of all the rows of the current partition and empties it
+func (p *windowPartition) flush() ([]sqltypes.Row, error) {
+	rows := p.rows
+	p.rows = nil
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	// peerEnd[i] is the index after the last peer of the row i, and
+	// peerStart[i] the index of its first peer
+	peerStart := make([]int, len(rows))
+	peerEnd := make([]int, len(rows))
+	denseRank := make([]int, len(rows))
+	start, rank := 0, 1
+	for i := 1; i <= len(rows); i++ {
+		if i < len(rows) {
+			peers, err := equalOn(p.orderBy, rows[i-1], rows[i])
+			if err != nil {
+				return nil, err
+			}
+			if peers {
+				continue
+			}
+		}
+		for j := start; j < i; j++ {
+			peerStart[j], peerEnd[j], denseRank[j] = start, i, rank
+		}
+		start = i
+		rank++
+	}
+
+	size := len(rows)
+	out := make([]sqltypes.Row, 0, size)
+	for i, row := range rows {
+		outRow := make(sqltypes.Row, len(p.w.Cols))
+		for idx, col := range p.w.Cols {
+			if col >= 0 {
+				outRow[idx] = row[col]
+			}
+		}
+		for _, f := range p.w.Funcs {
+			val, err := f.compute(rows, i, peerStart[i], peerEnd[i], denseRank[i])
+			if err != nil {
+				return nil, err
+			}
+			outRow[f.Col] = val
+		}
+		out = append(out, outRow)
+	}
+	return out, nil
+}
+
+// compute returns the value of the window function for the row at idx in a partition
+func (f *WindowFunc) compute(rows []sqltypes.Row, idx, peerStart, peerEnd, denseRank int) (sqltypes.Value, error) {
+	size := len(rows)
+	switch f.Opcode {
+	case WindowRowNumber:
+		return sqltypes.NewUint64(uint64(idx + 1)), nil
+	case WindowRank:
+		return sqltypes.NewUint64(uint64(peerStart + 1)), nil
+	case WindowDenseRank:
+		return sqltypes.NewUint64(uint64(denseRank)), nil
+	case WindowPercentRank:
+		if size == 1 {
+			return sqltypes.NewFloat64(0), nil
+		}
+		return sqltypes.NewFloat64(float64(peerStart) / float64(size-1)), nil
+	case WindowCumeDist:
+		return sqltypes.NewFloat64(float64(peerEnd) / float64(size)), nil
+	case WindowNtile:
+		if f.N <= 0 {
+			return sqltypes.NULL, vterrors.VT13001(fmt.Sprintf("invalid number of buckets for ntile: %d", f.N))
+		}
+		buckets := int(f.N)
+		bucketSize, extra := size/buckets, size%buckets
+		// the first `extra` buckets get one row more than the others
+		if idx < extra*(bucketSize+1) {
+			return sqltypes.NewUint64(uint64(idx/(bucketSize+1) + 1)), nil
+		}
+		return sqltypes.NewUint64(uint64((idx-extra*(bucketSize+1))/bucketSize + extra + 1)), nil
+	case WindowLag, WindowLead:
+		target := idx - int(f.N)
+		if f.Opcode == WindowLead {
+			target = idx + int(f.N)
+		}
+		if target >= 0 && target < size {
+			return rows[target][f.ArgCol], nil
+		}
+		if f.DefaultCol >= 0 {
+			return rows[idx][f.DefaultCol], nil
+		}
+		return sqltypes.NULL, nil
+	case WindowFirstValue:
+		// the default frame always starts at the first row of the partition
+		return rows[0][f.ArgCol], nil
+	case WindowLastValue:
+		// and ends at the last peer of the current row
+		return rows[peerEnd-1][f.ArgCol], nil
+	case WindowNthValue:
+		if f.N <= 0 {
+			return sqltypes.NULL, vterrors.VT13001(fmt.Sprintf("invalid row for nth_value: %d", f.N))
+		}
+		if int(f.N) > peerEnd {
+			return sqltypes.NULL, nil
+		}
+		return rows[f.N-1][f.ArgCol], nil
+	default:
+		return sqltypes.NULL, vterrors.VT13001(fmt.Sprintf("unexpected window function: %s", f.Opcode.String()))
+	}
+}
+
+// equalOn returns true if the two rows have the same values on all the given columns.
+// It falls back to comparing the weight strings of the columns when needed.
+func equalOn(cols []CheckCol, a, b sqltypes.Row) (bool, error) {
+	for i, col := range cols {
+		cmp, err := evalengine.NullsafeCompare(a[col.Col], b[col.Col], col.Collation)
+		if err != nil {
+			_, isComparisonErr := err.(evalengine.UnsupportedComparisonError)
+			if !isComparisonErr || col.WsCol == nil {
+				return false, err
+			}
+			col = col.SwitchToWeightString()
+			cols[i] = col
+			cmp, err = evalengine.NullsafeCompare(a[col.Col], b[col.Col], col.Collation)
+			if err != nil {
+				return false, err
+			}
+		}
+		if cmp != 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// RouteType implements the Primitive interface
+func (w *Window) RouteType() string {
+	return w.Input.RouteType()
+}
+
+// GetKeyspaceName implements the Primitive interface
+func (w *Window) GetKeyspaceName() string {
+	return w.Input.GetKeyspaceName()
+}
+
+// GetTableName implements the Primitive interface
+func (w *Window) GetTableName() string {
+	return w.Input.GetTableName()
+}
+
+// NeedsTransaction implements the Primitive interface
+func (w *Window) NeedsTransaction() bool {
+	return w.Input.NeedsTransaction()
+}
+
+// Inputs implements the Primitive interface
+func (w *Window) Inputs() []Primitive {
+	return []Primitive{w.Input}
+}
+
+func (w *Window) description() PrimitiveDescription {
+	other := map[string]any{}
+
+	funcs := slice.Map(w.Funcs, func(from *WindowFunc) string {
+		return from.String()
+	})
+	other["Functions"] = strings.Join(funcs, ", ")
+
+	if len(w.PartitionBy) > 0 {
+		other["PartitionBy"] = strings.Join(slice.Map(w.PartitionBy, CheckCol.String), ", ")
+	}
+	if len(w.OrderBy) > 0 {
+		other["OrderBy"] = strings.Join(slice.Map(w.OrderBy, CheckCol.String), ", ")
+	}
+	other["Columns"] = strings.Join(slice.Map(w.Cols, func(from int) string {
+		if from < 0 {
+			return "_"
+		}
+		return fmt.Sprintf("%d", from)
+	}), ", ")
+
+	return PrimitiveDescription{
+		OperatorType: "Window",
+		Other:        other,
+	}
+}
+
+func (f *WindowFunc) String() string {
+	var args []string
+	if f.ArgCol >= 0 {
+		args = append(args, fmt.Sprintf("%d", f.ArgCol))
+	}
+	switch f.Opcode {
+	case WindowNtile, WindowLag, WindowLead, WindowNthValue:
+		args = append(args, fmt.Sprintf("%d", f.N))
+	}
+	if f.DefaultCol >= 0 {
+		args = append(args, fmt.Sprintf("%d", f.DefaultCol))
+	}
+	res := fmt.Sprintf("%s(%s)", f.Opcode.String(), strings.Join(args, ", "))
+	if f.Alias != "" {
+		res += " AS " + f.Alias
+	}
+	return res
+}
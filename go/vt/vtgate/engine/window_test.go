@@ -0,0 +1,189 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
+	. "vitess.io/vitess/go/vt/vtgate/engine/opcode"
+)
+
+func TestWindow(t *testing.T) {
+	// the input is sorted by the partition (a) and then the order (b) of the window
+	input := r("a|b|c|d", "int64|int64|varchar|varchar",
+		"1|1|x|-",
+		"1|1|y|-",
+		"1|2|z|-",
+		"1|3|null|-",
+		"2|1|u|=",
+		"2|2|v|=",
+	)
+	intCol := func(col int) CheckCol {
+		return CheckCol{Col: col, Type: sqltypes.Int64, Collation: collations.CollationBinaryID}
+	}
+
+	tcases := []struct {
+		name     string
+		funcs    []*WindowFunc
+		orderBy  []CheckCol
+		expected *sqltypes.Result
+	}{{
+		name: "ranking functions",
+		funcs: []*WindowFunc{
+			{Opcode: WindowRowNumber, Col: 1, ArgCol: -1, DefaultCol: -1, Alias: "rn"},
+			{Opcode: WindowRank, Col: 2, ArgCol: -1, DefaultCol: -1, Alias: "r"},
+			{Opcode: WindowDenseRank, Col: 3, ArgCol: -1, DefaultCol: -1, Alias: "dr"},
+		},
+		orderBy: []CheckCol{intCol(1)},
+		expected: r("a|rn|r|dr", "int64|uint64|uint64|uint64",
+			"1|1|1|1",
+			"1|2|1|1",
+			"1|3|3|2",
+			"1|4|4|3",
+			"2|1|1|1",
+			"2|2|2|2",
+		),
+	}, {
+		name: "distribution functions",
+		funcs: []*WindowFunc{
+			{Opcode: WindowPercentRank, Col: 1, ArgCol: -1, DefaultCol: -1, Alias: "pr"},
+			{Opcode: WindowCumeDist, Col: 2, ArgCol: -1, DefaultCol: -1, Alias: "cd"},
+			{Opcode: WindowNtile, Col: 3, ArgCol: -1, DefaultCol: -1, N: 3, Alias: "nt"},
+		},
+		orderBy: []CheckCol{intCol(1)},
+		expected: r("a|pr|cd|nt", "int64|float64|float64|uint64",
+			"1|0|0.5|1",
+			"1|0|0.5|1",
+			"1|0.6666666666666666|0.75|2",
+			"1|1|1|3",
+			"2|0|0.5|1",
+			"2|1|1|2",
+		),
+	}, {
+		name: "value functions",
+		funcs: []*WindowFunc{
+			{Opcode: WindowLag, Col: 1, ArgCol: 2, DefaultCol: -1, N: 1, Alias: "lag"},
+			{Opcode: WindowLead, Col: 2, ArgCol: 2, DefaultCol: 3, N: 2, Alias: "lead"},
+			{Opcode: WindowFirstValue, Col: 3, ArgCol: 2, DefaultCol: -1, Alias: "fv"},
+			{Opcode: WindowLastValue, Col: 4, ArgCol: 2, DefaultCol: -1, Alias: "lv"},
+			{Opcode: WindowNthValue, Col: 5, ArgCol: 2, DefaultCol: -1, N: 3, Alias: "nv"},
+		},
+		orderBy: []CheckCol{intCol(1)},
+		expected: r("a|lag|lead|fv|lv|nv", "int64|varchar|varchar|varchar|varchar|varchar",
+			"1|null|z|x|y|null",
+			"1|x|null|x|y|null",
+			"1|y|-|x|z|z",
+			"1|z|-|x|null|z",
+			"2|null|=|u|u|null",
+			"2|u|=|u|v|null",
+		),
+	}, {
+		name: "without ordering all rows of a partition are peers",
+		funcs: []*WindowFunc{
+			{Opcode: WindowRank, Col: 1, ArgCol: -1, DefaultCol: -1, Alias: "r"},
+			{Opcode: WindowLastValue, Col: 2, ArgCol: 2, DefaultCol: -1, Alias: "lv"},
+		},
+		expected: r("a|r|lv", "int64|uint64|varchar",
+			"1|1|null",
+			"1|1|null",
+			"1|1|null",
+			"1|1|null",
+			"2|1|v",
+			"2|1|v",
+		),
+	}}
+
+	for _, tc := range tcases {
+		cols := make([]int, len(tc.funcs)+1)
+		for i := range cols {
+			cols[i] = -1
+		}
+		cols[0] = 0
+		newWindow := func() *Window {
+			return &Window{
+				Input:       &fakePrimitive{results: []*sqltypes.Result{input}},
+				PartitionBy: []CheckCol{intCol(0)},
+				OrderBy:     tc.orderBy,
+				Funcs:       tc.funcs,
+				Cols:        cols,
+			}
+		}
+
+		t.Run(tc.name+"-Execute", func(t *testing.T) {
+			qr, err := newWindow().TryExecute(context.Background(), &noopVCursor{}, nil, true)
+			require.NoError(t, err)
+			utils.MustMatch(t, tc.expected, qr)
+		})
+		t.Run(tc.name+"-StreamExecute", func(t *testing.T) {
+			qr, err := wrapStreamExecute(newWindow(), &noopVCursor{}, nil, true)
+			require.NoError(t, err)
+			utils.MustMatch(t, tc.expected, qr)
+		})
+	}
+}
+
+func TestWindowStreamAcrossResults(t *testing.T) {
+	// a partition can be split across several results of the stream
+	fp := &fakePrimitive{allResultsInOneCall: true, results: sqltypes.MakeTestStreamingResults(
+		sqltypes.MakeTestFields("a|b", "int64|int64"),
+		"1|1",
+		"1|2",
+		"---",
+		"1|3",
+		"2|1",
+		"---",
+		"2|2",
+	)}
+	w := &Window{
+		Input:       fp,
+		PartitionBy: []CheckCol{{Col: 0, Type: sqltypes.Int64, Collation: collations.CollationBinaryID}},
+		Funcs:       []*WindowFunc{{Opcode: WindowRowNumber, Col: 2, ArgCol: -1, DefaultCol: -1, Alias: "rn"}},
+		Cols:        []int{0, 1, -1},
+	}
+
+	qr, err := wrapStreamExecute(w, &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+	utils.MustMatch(t, r("a|b|rn", "int64|int64|uint64",
+		"1|1|1",
+		"1|2|2",
+		"1|3|3",
+		"2|1|1",
+		"2|2|2",
+	), qr)
+}
+
+func TestWindowGetFields(t *testing.T) {
+	fields := sqltypes.MakeTestFields("a|b", "int64|varchar")
+	w := &Window{
+		Input: &fakePrimitive{results: []*sqltypes.Result{{Fields: fields}}},
+		Funcs: []*WindowFunc{
+			{Opcode: WindowRank, Col: 0, ArgCol: -1, DefaultCol: -1, Alias: "rank() over ()"},
+			{Opcode: WindowLag, Col: 2, ArgCol: 1, DefaultCol: -1, N: 1, Alias: "lag(b) over ()"},
+		},
+		Cols: []int{-1, 0, -1},
+	}
+
+	qr, err := w.GetFields(context.Background(), &noopVCursor{}, nil)
+	require.NoError(t, err)
+	utils.MustMatch(t, sqltypes.MakeTestFields("rank() over ()|a|lag(b) over ()", "uint64|int64|varchar"), qr.Fields)
+}
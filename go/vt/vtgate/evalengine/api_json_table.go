@@ -0,0 +1,233 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package evalengine
+
+import (
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/fastparse"
+	"vitess.io/vitess/go/mysql/json"
+	"vitess.io/vitess/go/sqltypes"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+type (
+	// JSONTableResponse is the ON EMPTY or ON ERROR clause of a column of a JSON_TABLE:
+	// the column is either NULL, set to Default, or the evaluation fails.
+	JSONTableResponse struct {
+		Error   bool
+		Default *json.Value
+	}
+
+	// JSONTableColumn is a column of a JSON_TABLE. A column with a Nested list is a
+	// NESTED PATH clause, whose columns are produced for every value matched by its Path.
+	JSONTableColumn struct {
+		Name      string
+		Type      sqltypes.Type
+		Collation collations.ID
+
+		Ordinality bool
+		Exists     bool
+		Path       *json.Path
+
+		OnEmpty JSONTableResponse
+		OnError JSONTableResponse
+
+		Nested []*JSONTableColumn
+	}
+)
+
+// JSONTableWidth returns the number of columns produced by the JSON_TABLE columns,
+// including the columns of their NESTED PATH clauses.
+func JSONTableWidth(columns []*JSONTableColumn) int {
+	width := 0
+	for _, col := range columns {
+		if col.Nested != nil {
+			width += JSONTableWidth(col.Nested)
+			continue
+		}
+		width++
+	}
+	return width
+}
+
+// JSONTable returns the rows of JSON_TABLE(doc, path COLUMNS(columns)), following the
+// semantics of MySQL: a row is produced for every value of the document matched by the path,
+// and the sibling NESTED PATH clauses of a row produce their rows one after the other,
+// with the columns of the other clauses set to NULL. A NULL document produces no rows.
+func JSONTable(doc sqltypes.Value, path *json.Path, columns []*JSONTableColumn) ([]sqltypes.Row, error) {
+	if doc.IsNull() {
+		return nil, nil
+	}
+	e, err := valueToEval(doc, defaultCoercionCollation(collations.CollationUtf8mb4ID))
+	if err != nil {
+		return nil, err
+	}
+	j, err := intoJSON("JSON_TABLE", e)
+	if err != nil {
+		return nil, err
+	}
+	return jsonTableMatches(j, path, columns, JSONTableWidth(columns))
+}
+
+func jsonTableMatches(doc *json.Value, path *json.Path, columns []*JSONTableColumn, width int) ([]sqltypes.Row, error) {
+	var rows []sqltypes.Row
+	var err error
+	ordinal := 0
+	path.Match(doc, true, func(value *json.Value) {
+		if err != nil {
+			return
+		}
+		ordinal++
+		var matched []sqltypes.Row
+		matched, err = jsonTableRows(value, ordinal, columns, width)
+		rows = append(rows, matched...)
+	})
+	return rows, err
+}
+
+func jsonTableRows(value *json.Value, ordinal int, columns []*JSONTableColumn, width int) ([]sqltypes.Row, error) {
+	row := make(sqltypes.Row, width)
+	type nested struct {
+		offset  int
+		columns *JSONTableColumn
+	}
+	var nestedPaths []nested
+
+	offset := 0
+	for _, col := range columns {
+		if col.Nested != nil {
+			nestedPaths = append(nestedPaths, nested{offset: offset, columns: col})
+			nestedWidth := JSONTableWidth(col.Nested)
+			for i := 0; i < nestedWidth; i++ {
+				row[offset+i] = sqltypes.NULL
+			}
+			offset += nestedWidth
+			continue
+		}
+		v, err := jsonTableColumn(value, ordinal, col)
+		if err != nil {
+			return nil, err
+		}
+		row[offset] = v
+		offset++
+	}
+
+	var rows []sqltypes.Row
+	for _, n := range nestedPaths {
+		nestedRows, err := jsonTableMatches(value, n.columns.Path, n.columns.Nested, JSONTableWidth(n.columns.Nested))
+		if err != nil {
+			return nil, err
+		}
+		for _, nestedRow := range nestedRows {
+			r := make(sqltypes.Row, width)
+			copy(r, row)
+			copy(r[n.offset:], nestedRow)
+			rows = append(rows, r)
+		}
+	}
+	if len(rows) == 0 {
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func jsonTableColumn(value *json.Value, ordinal int, col *JSONTableColumn) (sqltypes.Value, error) {
+	if col.Ordinality {
+		return sqltypes.NewUint32(uint32(ordinal)), nil
+	}
+
+	var matches []*json.Value
+	col.Path.Match(value, true, func(match *json.Value) {
+		matches = append(matches, match)
+	})
+	if col.Exists {
+		if len(matches) > 0 {
+			return evalToSQLValueWithType(newEvalInt64(1), col.Type), nil
+		}
+		return evalToSQLValueWithType(newEvalInt64(0), col.Type), nil
+	}
+
+	switch {
+	case len(matches) == 0:
+		return jsonTableResponse(col, col.OnEmpty, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Missing value for JSON_TABLE column '%s'", col.Name))
+	case len(matches) > 1:
+		return jsonTableResponse(col, col.OnError, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Subquery returns more than 1 row"))
+	}
+	v, err := jsonTableScalar(matches[0], col)
+	if err != nil {
+		return jsonTableResponse(col, col.OnError, err)
+	}
+	return v, nil
+}
+
+// jsonTableResponse returns the value of a column without a valid value, as set by its
+// ON EMPTY or ON ERROR clause.
+func jsonTableResponse(col *JSONTableColumn, response JSONTableResponse, err error) (sqltypes.Value, error) {
+	switch {
+	case response.Error:
+		return sqltypes.NULL, err
+	case response.Default != nil:
+		return jsonTableScalar(response.Default, col)
+	default:
+		return sqltypes.NULL, nil
+	}
+}
+
+// jsonTableScalar converts a JSON value to the type of the column
+func jsonTableScalar(value *json.Value, col *JSONTableColumn) (sqltypes.Value, error) {
+	if col.Type == sqltypes.TypeJSON {
+		return sqltypes.MakeTrusted(sqltypes.TypeJSON, value.MarshalTo(nil)), nil
+	}
+
+	var e eval
+	switch value.Type() {
+	case json.TypeNull:
+		return sqltypes.NULL, nil
+	case json.TypeObject, json.TypeArray:
+		return sqltypes.NULL, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Can't store an array or an object in the scalar column '%s' of JSON_TABLE", col.Name)
+	case json.TypeBoolean:
+		b, _ := value.Bool()
+		if sqltypes.IsNumber(col.Type) {
+			e = newEvalBool(b)
+			break
+		}
+		e = newEvalText(value.MarshalTo(nil), collationJSON)
+	case json.TypeString:
+		e = newEvalText(value.ToUnencodedBytes(), collationJSON)
+	default:
+		e = newEvalText(value.MarshalTo(nil), collationJSON)
+	}
+
+	if b, ok := e.(*evalBytes); ok && sqltypes.IsNumber(col.Type) {
+		if _, err := fastparse.ParseFloat64(b.string()); err != nil {
+			return sqltypes.NULL, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Invalid JSON value for CAST to %s in column '%s' of JSON_TABLE", col.Type.String(), col.Name)
+		}
+	}
+	collation := col.Collation
+	if collation == collations.Unknown {
+		collation = collations.CollationUtf8mb4ID
+	}
+	e, err := evalCoerce(e, col.Type, collation)
+	if err != nil {
+		return sqltypes.NULL, err
+	}
+	if e == nil {
+		return sqltypes.NULL, nil
+	}
+	return evalToSQLValueWithType(e, col.Type), nil
+}
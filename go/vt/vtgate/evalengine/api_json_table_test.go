@@ -0,0 +1,95 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package evalengine
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/json"
+	"vitess.io/vitess/go/sqltypes"
+)
+
+func TestJSONTable(t *testing.T) {
+	path := func(p string) *json.Path {
+		var parser json.PathParser
+		jp, err := parser.ParseBytes([]byte(p))
+		require.NoError(t, err)
+		return jp
+	}
+	doc := func(d string) *json.Value {
+		var parser json.Parser
+		j, err := parser.ParseBytes([]byte(d))
+		require.NoError(t, err)
+		return j
+	}
+
+	columns := []*JSONTableColumn{
+		{Name: "rowid", Ordinality: true},
+		{Name: "id", Type: sqltypes.Int64, Path: path("$.id"), OnEmpty: JSONTableResponse{Default: doc("-1")}},
+		{Name: "name", Type: sqltypes.VarChar, Path: path("$.name")},
+		{Name: "has_tags", Type: sqltypes.Int8, Exists: true, Path: path("$.tags")},
+		{Path: path("$.tags[*]"), Nested: []*JSONTableColumn{
+			{Name: "tag", Type: sqltypes.VarChar, Path: path("$")},
+		}},
+		{Path: path("$.scores[*]"), Nested: []*JSONTableColumn{
+			{Name: "score", Type: sqltypes.Int64, Path: path("$")},
+		}},
+	}
+	assert.Equal(t, 6, JSONTableWidth(columns))
+
+	rows, err := JSONTable(
+		sqltypes.NewVarChar(`[{"id": 1, "name": "a", "tags": ["x", "y"], "scores": [10]}, {"name": "b"}, {"id": 3, "name": {"first": "c"}}]`),
+		path("$[*]"),
+		columns,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, `[[UINT32(1) INT64(1) VARCHAR("a") INT8(1) VARCHAR("x") NULL] `+
+		`[UINT32(1) INT64(1) VARCHAR("a") INT8(1) VARCHAR("y") NULL] `+
+		`[UINT32(1) INT64(1) VARCHAR("a") INT8(1) NULL INT64(10)] `+
+		`[UINT32(2) INT64(-1) VARCHAR("b") INT8(0) NULL NULL] `+
+		`[UINT32(3) INT64(3) NULL INT8(0) NULL NULL]]`, fmt.Sprintf("%v", rows))
+
+	rows, err = JSONTable(sqltypes.NULL, path("$[*]"), columns)
+	require.NoError(t, err)
+	assert.Empty(t, rows)
+
+	errorOnError := []*JSONTableColumn{
+		{Name: "c1", Type: sqltypes.Int32, Path: path("$.c1"), OnError: JSONTableResponse{Error: true}},
+	}
+	rows, err = JSONTable(sqltypes.NewVarChar(`[{"c1": null}, {"c1": 2}]`), path("$[*]"), errorOnError)
+	require.NoError(t, err)
+	assert.Equal(t, `[[NULL] [INT32(2)]]`, fmt.Sprintf("%v", rows))
+
+	_, err = JSONTable(sqltypes.NewVarChar(`[{"c1": [1]}]`), path("$[*]"), errorOnError)
+	assert.EqualError(t, err, "Can't store an array or an object in the scalar column 'c1' of JSON_TABLE")
+
+	_, err = JSONTable(sqltypes.NewVarChar(`[{"c1": "abc"}]`), path("$[*]"), errorOnError)
+	assert.Error(t, err)
+
+	errorOnEmpty := []*JSONTableColumn{
+		{Name: "c1", Type: sqltypes.Int32, Path: path("$.c1"), OnEmpty: JSONTableResponse{Error: true}},
+	}
+	_, err = JSONTable(sqltypes.NewVarChar(`[{}]`), path("$[*]"), errorOnEmpty)
+	assert.EqualError(t, err, "Missing value for JSON_TABLE column 'c1'")
+
+	_, err = JSONTable(sqltypes.NewVarChar(`[{`), path("$[*]"), errorOnEmpty)
+	assert.Error(t, err)
+}
@@ -269,6 +269,41 @@ func (cached *IsExpr) CachedSize(alloc bool) int64 {
 	size += cached.UnaryExpr.CachedSize(false)
 	return size
 }
+func (cached *JSONTableColumn) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(88)
+	}
+	// field Name string
+	size += hack.RuntimeAllocSize(int64(len(cached.Name)))
+	// field OnEmpty vitess.io/vitess/go/vt/vtgate/evalengine.JSONTableResponse
+	size += cached.OnEmpty.CachedSize(false)
+	// field OnError vitess.io/vitess/go/vt/vtgate/evalengine.JSONTableResponse
+	size += cached.OnError.CachedSize(false)
+	// field Nested []*vitess.io/vitess/go/vt/vtgate/evalengine.JSONTableColumn
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Nested)) * int64(8))
+		for _, elem := range cached.Nested {
+			size += elem.CachedSize(true)
+		}
+	}
+	return size
+}
+func (cached *JSONTableResponse) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(16)
+	}
+	// field Default *vitess.io/vitess/go/mysql/json.Value
+	size += cached.Default.CachedSize(true)
+	return size
+}
 func (cached *LikeExpr) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -26,6 +26,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -108,6 +109,21 @@ type Executor struct {
 	plans        cache.Cache
 	vschemaStats *VSchemaStats
 
+	// prepared caches the result of preparing statements, so that the
+	// connections preparing the same statement share a single plan and fields.
+	prepared cache.Cache
+
+	// resultCache caches the results of the read-only queries on the tables
+	// whose VSchema enables it.
+	resultCache *resultCache
+
+	// quotas throttles the queries exceeding the query quotas, if any.
+	quotas *queryQuotas
+
+	// namedLocks serves the lock functions on the unsharded keyspaces with
+	// the named locks of the topo, if enabled.
+	namedLocks *topoNamedLocks
+
 	normalize       bool
 	warnShardedOnly bool
 
@@ -148,6 +164,8 @@ func NewExecutor(
 		scatterConn:     resolver.scatterConn,
 		txConn:          resolver.scatterConn.txConn,
 		plans:           plans,
+		prepared:        cache.NewDefaultCacheImpl(&cache.Config{MaxEntries: preparedStatementCacheSize}),
+		resultCache:     newResultCache(resultCacheMemory),
 		normalize:       normalize,
 		warnShardedOnly: warnOnShardedOnly,
 		streamSize:      streamSize,
@@ -157,6 +175,14 @@ func NewExecutor(
 		queryLogger:     queryLogger,
 	}
 
+	if enableTopoNamedLocks {
+		ts, err := serv.GetTopoServer()
+		if err != nil {
+			log.Exitf("Cannot use the named locks of the topo: %v", err)
+		}
+		e.namedLocks = newTopoNamedLocks(ts, topoNamedLockTTL)
+	}
+
 	vschemaacl.Init()
 	// we subscribe to update from the VSchemaManager
 	e.vm = &VSchemaManager{
@@ -186,6 +212,33 @@ func NewExecutor(
 		stats.NewCounterFunc("QueryPlanCacheMisses", "Query plan cache misses", func() int64 {
 			return e.plans.Misses()
 		})
+		stats.NewGaugeFunc("PreparedStatementCacheLength", "Prepared statement cache length", func() int64 {
+			return int64(e.prepared.Len())
+		})
+		stats.NewCounterFunc("PreparedStatementCacheEvictions", "Prepared statement cache evictions", func() int64 {
+			return e.prepared.Evictions()
+		})
+		stats.NewCounterFunc("PreparedStatementCacheHits", "Prepared statement cache hits", func() int64 {
+			return e.prepared.Hits()
+		})
+		stats.NewCounterFunc("PreparedStatementCacheMisses", "Prepared statement cache misses", func() int64 {
+			return e.prepared.Misses()
+		})
+		stats.NewGaugeFunc("ResultCacheLength", "Result cache length", func() int64 {
+			return int64(e.resultCache.entries.Len())
+		})
+		stats.NewGaugeFunc("ResultCacheSize", "Result cache size", func() int64 {
+			return e.resultCache.entries.UsedCapacity()
+		})
+		stats.NewCounterFunc("ResultCacheEvictions", "Result cache evictions", func() int64 {
+			return e.resultCache.entries.Evictions()
+		})
+		stats.NewCounterFunc("ResultCacheHits", "Result cache hits", func() int64 {
+			return e.resultCache.entries.Hits()
+		})
+		stats.NewCounterFunc("ResultCacheMisses", "Result cache misses", func() int64 {
+			return e.resultCache.entries.Misses()
+		})
 		servenv.HTTPHandle(pathQueryPlans, e)
 		servenv.HTTPHandle(pathScatterStats, e)
 		servenv.HTTPHandle(pathVSchema, e)
@@ -218,7 +271,7 @@ func (e *Executor) Execute(ctx context.Context, mysqlCtx vtgateservice.MySQLConn
 	}
 
 	logStats.SaveEndTime()
-	e.queryLogger.Send(logStats)
+	e.sendQueryLog(logStats)
 	err = vterrors.TruncateError(err, truncateErrorLen)
 	return result, err
 }
@@ -244,6 +297,13 @@ func (s *streaminResultReceiver) storeResultStats(typ sqlparser.StatementType, q
 	return s.callback(qr)
 }
 
+// rowStats returns the rows affected and returned so far.
+func (s *streaminResultReceiver) rowStats() (uint64, uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rowsAffected, uint64(s.rowsReturned)
+}
+
 // StreamExecute executes a streaming query.
 func (e *Executor) StreamExecute(
 	ctx context.Context,
@@ -330,7 +390,9 @@ func (e *Executor) StreamExecute(
 		// 5: Log and add statistics
 		logStats.TablesUsed = plan.TablesUsed
 		logStats.TabletType = vc.TabletType().String()
+		logStats.PlanType = plan.Instructions.RouteType()
 		logStats.ExecuteTime = time.Since(execStart)
+		logStats.RowsAffected, logStats.RowsReturned = srr.rowStats()
 		logStats.ActiveKeyspace = vc.keyspace
 
 		e.updateQueryCounts(plan.Instructions.RouteType(), plan.Instructions.GetKeyspaceName(), plan.Instructions.GetTableName(), int64(logStats.ShardQueries))
@@ -339,6 +401,8 @@ func (e *Executor) StreamExecute(
 	}
 
 	err = e.newExecute(ctx, mysqlCtx, safeSession, sql, bindVars, logStats, resultHandler, srr.storeResultStats)
+	e.updateReadAfterWriteToken(ctx, safeSession)
+	e.releaseMultiplexedConns(ctx, safeSession)
 
 	logStats.Error = err
 	saveSessionStats(safeSession, srr.stmtType, srr.rowsAffected, srr.insertID, srr.rowsReturned, err)
@@ -352,7 +416,7 @@ func (e *Executor) StreamExecute(
 	}
 
 	logStats.SaveEndTime()
-	e.queryLogger.Send(logStats)
+	e.sendQueryLog(logStats)
 	return vterrors.TruncateError(err, truncateErrorLen)
 
 }
@@ -398,10 +462,23 @@ func (e *Executor) execute(ctx context.Context, mysqlCtx vtgateservice.MySQLConn
 		qr = result
 		return nil
 	})
+	e.updateReadAfterWriteToken(ctx, safeSession)
+	e.releaseMultiplexedConns(ctx, safeSession)
 
 	return stmtType, qr, err
 }
 
+// releaseMultiplexedConns releases the reserved connections of the session that only hold
+// its system variables, if --multiplex-reserved-connections is set.
+func (e *Executor) releaseMultiplexedConns(ctx context.Context, safeSession *SafeSession) {
+	if !multiplexReservedConns {
+		return
+	}
+	if err := e.txConn.ReleaseMultiplexed(ctx, safeSession); err != nil {
+		log.Warningf("Failed to release the multiplexed reserved connections of session %s: %v", safeSession.GetSessionUUID(), err)
+	}
+}
+
 // addNeededBindVars adds bind vars that are needed by the plan
 func (e *Executor) addNeededBindVars(vcursor *vcursorImpl, bindVarNeeds *sqlparser.BindVarNeeds, bindVars map[string]*querypb.BindVariable, session *SafeSession) error {
 	for _, funcName := range bindVarNeeds.NeedFunctionResult {
@@ -424,6 +501,14 @@ func (e *Executor) addNeededBindVars(vcursor *vcursorImpl, bindVarNeeds *sqlpars
 			bindVars[key] = sqltypes.BoolBindVariable(session.Autocommit)
 		case sysvars.QueryTimeout.Name:
 			bindVars[key] = sqltypes.Int64BindVariable(session.GetQueryTimeout())
+		case sysvars.ScatterConcurrency.Name:
+			bindVars[key] = sqltypes.Int64BindVariable(session.GetScatterConcurrency())
+		case sysvars.ShardTimeout.Name:
+			bindVars[key] = sqltypes.Int64BindVariable(session.GetShardTimeout())
+		case sysvars.AllowPartialScatter.Name:
+			bindVars[key] = sqltypes.BoolBindVariable(session.GetAllowPartialScatter())
+		case sysvars.SkipReadRetry.Name:
+			bindVars[key] = sqltypes.BoolBindVariable(session.GetSkipReadRetry())
 		case sysvars.ClientFoundRows.Name:
 			var v bool
 			ifOptionsExist(session, func(options *querypb.ExecuteOptions) {
@@ -666,6 +751,9 @@ func (e *Executor) handleKill(ctx context.Context, mysqlCtx vtgateservice.MySQLC
 // CloseSession releases the current connection, which rollbacks open transactions and closes reserved connections.
 // It is called then the MySQL servers closes the connection to its client.
 func (e *Executor) CloseSession(ctx context.Context, safeSession *SafeSession) error {
+	if e.namedLocks != nil {
+		e.namedLocks.closeSession(ctx, safeSession.GetSessionUUID())
+	}
 	return e.txConn.ReleaseAll(ctx, safeSession)
 }
 
@@ -978,6 +1066,8 @@ func (e *Executor) SaveVSchema(vschema *vindexes.VSchema, stats *VSchemaStats) {
 	}
 	e.vschemaStats = stats
 	e.plans.Clear()
+	e.prepared.Clear()
+	e.resultCache.setVSchema(e.vschema)
 
 	if vschemaCounters != nil {
 		vschemaCounters.Add("Reload", 1)
@@ -1244,10 +1334,16 @@ func buildVarCharRow(values ...string) []sqltypes.Value {
 	return row
 }
 
+// sendQueryLog sends the log of the query to the query logs, if the query is sampled.
+func (e *Executor) sendQueryLog(logStats *logstats.LogStats) {
+	if streamlog.ShouldSampleQuery() {
+		e.queryLogger.Send(logStats)
+	}
+}
+
 // isValidPayloadSize validates whether a query payload is above the
 // configured MaxPayloadSize threshold. The WarnPayloadSizeExceeded will increment
 // if the payload size exceeds the warnPayloadSize.
-
 func isValidPayloadSize(query string) bool {
 	payloadSize := len(query)
 	if maxPayloadSize > 0 && payloadSize > maxPayloadSize {
@@ -1270,7 +1366,7 @@ func (e *Executor) Prepare(ctx context.Context, method string, safeSession *Safe
 	// it was a no-op record (i.e. didn't issue any queries)
 	if !(logStats.StmtType == "ROLLBACK" && logStats.ShardQueries == 0) {
 		logStats.SaveEndTime()
-		e.queryLogger.Send(logStats)
+		e.sendQueryLog(logStats)
 	}
 	return fld, vterrors.TruncateError(err, truncateErrorLen)
 }
@@ -1311,6 +1407,41 @@ func (e *Executor) prepare(ctx context.Context, safeSession *SafeSession, sql st
 	return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "[BUG] unrecognized prepare statement: %s", sql)
 }
 
+// preparedStatement is the result of preparing a statement, shared by all the
+// connections that prepare it.
+type preparedStatement struct {
+	plan   *engine.Plan
+	fields []*querypb.Field
+	// expires is when the fields are to be fetched again, if set.
+	expires time.Time
+}
+
+// expired returns whether the fields of the statement may be stale.
+func (p *preparedStatement) expired(now time.Time) bool {
+	return !p.expires.IsZero() && now.After(p.expires)
+}
+
+// preparedKey returns the key of a statement in the prepared statement cache:
+// the plan key of its normalized form, along with the types of its bind
+// variables.
+func (e *Executor) preparedKey(ctx context.Context, vcursor *vcursorImpl, stmt sqlparser.Statement, bindVars map[string]*querypb.BindVariable) string {
+	names := make([]string, 0, len(bindVars))
+	for name := range bindVars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var key strings.Builder
+	key.WriteString(e.hashPlan(ctx, vcursor, sqlparser.String(stmt)))
+	for _, name := range names {
+		key.WriteString("+")
+		key.WriteString(name)
+		key.WriteString(":")
+		key.WriteString(bindVars[name].GetType().String())
+	}
+	return key.String()
+}
+
 func (e *Executor) handlePrepare(ctx context.Context, safeSession *SafeSession, sql string, bindVars map[string]*querypb.BindVariable, logStats *logstats.LogStats) ([]*querypb.Field, error) {
 	query, comments := sqlparser.SplitMarginComments(sql)
 	vcursor, _ := newVCursorImpl(safeSession, comments, e, logStats, e.vm, e.VSchema(), e.resolver.resolver, e.serv, e.warnShardedOnly, e.pv)
@@ -1320,6 +1451,20 @@ func (e *Executor) handlePrepare(ctx context.Context, safeSession *SafeSession,
 		return nil, err
 	}
 
+	// The key has to be computed before planning, which rewrites stmt.
+	cachable := sqlparser.CachePlan(stmt) && safeSession.cachePlan()
+	var key string
+	if cachable {
+		key = e.preparedKey(ctx, vcursor, stmt, bindVars)
+		if cached, ok := e.prepared.Get(key); ok && !cached.(*preparedStatement).expired(time.Now()) {
+			prepared := cached.(*preparedStatement)
+			logStats.CachedPlan = true
+			logStats.PlanTime = time.Since(logStats.StartTime)
+			prepared.plan.AddStats(1, time.Since(logStats.StartTime), 0, 0, 0, 0)
+			return prepared.fields, nil
+		}
+	}
+
 	plan, err := e.getPlan(ctx, vcursor, sql, stmt, comments, bindVars, reservedVars /* parameterize */, false, logStats)
 	execStart := time.Now()
 	logStats.PlanTime = execStart.Sub(logStats.StartTime)
@@ -1347,6 +1492,14 @@ func (e *Executor) handlePrepare(ctx context.Context, safeSession *SafeSession,
 
 	plan.AddStats(1, time.Since(logStats.StartTime), logStats.ShardQueries, qr.RowsAffected, uint64(len(qr.Rows)), errCount)
 
+	if cachable {
+		prepared := &preparedStatement{plan: plan, fields: qr.Fields}
+		if preparedStatementCacheTTL > 0 {
+			prepared.expires = time.Now().Add(preparedStatementCacheTTL)
+		}
+		e.prepared.Set(key, prepared)
+	}
+
 	return qr.Fields, err
 }
 
@@ -1484,6 +1637,19 @@ func (e *Executor) ReleaseLock(ctx context.Context, session *SafeSession) error
 	return e.txConn.ReleaseLock(ctx, session)
 }
 
+// topoNamedLocksEnabled implements the IExecutor interface
+func (e *Executor) topoNamedLocksEnabled() bool {
+	return e.namedLocks != nil
+}
+
+// ExecuteTopoLock implements the IExecutor interface
+func (e *Executor) ExecuteTopoLock(ctx context.Context, session *SafeSession, lockFuncType sqlparser.LockingFuncType, name string, timeout time.Duration) (sqltypes.Value, error) {
+	if e.namedLocks == nil {
+		return sqltypes.NULL, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the named locks of the topo are not enabled")
+	}
+	return e.namedLocks.execute(ctx, session.GetSessionUUID(), lockFuncType, name, timeout)
+}
+
 // planPrepareStmt implements the IExecutor interface
 func (e *Executor) planPrepareStmt(ctx context.Context, vcursor *vcursorImpl, query string) (*engine.Plan, sqlparser.Statement, error) {
 	stmt, reservedVars, err := parseAndValidateQuery(query)
@@ -1511,6 +1677,9 @@ func (e *Executor) planPrepareStmt(ctx context.Context, vcursor *vcursorImpl, qu
 }
 
 func (e *Executor) Close() {
+	if e.namedLocks != nil {
+		e.namedLocks.close()
+	}
 	e.scatterConn.Close()
 	topo, err := e.serv.GetTopoServer()
 	if err != nil {
@@ -1518,4 +1687,6 @@ func (e *Executor) Close() {
 	}
 	topo.Close()
 	e.plans.Close()
+	e.prepared.Close()
+	e.resultCache.close()
 }
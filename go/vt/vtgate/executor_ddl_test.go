@@ -53,6 +53,17 @@ func TestDDLFlags(t *testing.T) {
 			sql:             "revert vitess_migration 'abc'",
 			wantErr:         true,
 			err:             "online DDL is disabled",
+		}, {
+			enableDirectDDL: true,
+			enableOnlineDDL: false,
+			sql:             "alter /*vt+ DDL_STRATEGY=vitess */ table t add column c int",
+			wantErr:         true,
+			err:             "online DDL is disabled",
+		}, {
+			enableDirectDDL: false,
+			enableOnlineDDL: true,
+			sql:             "alter /*vt+ DDL_STRATEGY=vitess */ table t add column c int",
+			wantErr:         false,
 		},
 	}
 	for _, testcase := range testcases {
@@ -40,11 +40,13 @@ import (
 	"vitess.io/vitess/go/cache"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/callerid"
 	"vitess.io/vitess/go/vt/discovery"
 	"vitess.io/vitess/go/vt/vterrors"
-	_ "vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
 	"vitess.io/vitess/go/vt/vttablet/sandboxconn"
 
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
@@ -1194,6 +1196,41 @@ func TestSelectBindvars(t *testing.T) {
 	testQueryLog(t, executor, logChan, "TestExecute", "SELECT", "select id from `user` where `name` = :name", 2)
 }
 
+func TestSelectRecursiveCTE(t *testing.T) {
+	executor, sbc1, sbc2, _, ctx := createExecutorEnv(t)
+	session := &vtgatepb.Session{
+		TargetString: "@primary",
+	}
+
+	// all the parts of the query go to the same shard, it is sent as a whole
+	sql := "with recursive cte as (select id, col from user where id = 1 union all select u.id, u.col from user u join cte on u.col = cte.id where u.id = 1) select * from cte"
+	_, err := executorExec(ctx, executor, session, sql, nil)
+	require.NoError(t, err)
+	wantQueries := []*querypb.BoundQuery{{
+		Sql:           "with recursive cte as (select id, col from `user` where id = 1 union all select u.id, u.col from `user` as u join cte on u.col = cte.id where u.id = 1) select * from cte",
+		BindVariables: map[string]*querypb.BindVariable{},
+	}}
+	utils.MustMatch(t, wantQueries, sbc1.Queries)
+	require.Nil(t, sbc2.Queries)
+	sbc1.Queries = nil
+
+	// the recursive part is executed once per row of the previous iteration, on the shard of the row it reads
+	sbc1.SetResults([]*sqltypes.Result{sqltypes.MakeTestResult(sqltypes.MakeTestFields("id|col", "int64|int64"), "1|3")})
+	sbc2.SetResults([]*sqltypes.Result{sqltypes.MakeTestResult(sqltypes.MakeTestFields("id|col", "int64|int64"), "3|null")})
+	sql = "with recursive cte as (select id, col from user where id = 1 union all select u.id, u.col from user u join cte on u.id = cte.col) select id from cte"
+	result, err := executorExec(ctx, executor, session, sql, nil)
+	require.NoError(t, err)
+	require.Equal(t, `[[INT64(1)] [INT64(3)]]`, fmt.Sprintf("%v", result.Rows))
+	utils.MustMatch(t, []*querypb.BoundQuery{{
+		Sql:           "select id, col from `user` where id = 1",
+		BindVariables: map[string]*querypb.BindVariable{},
+	}}, sbc1.Queries)
+	utils.MustMatch(t, []*querypb.BoundQuery{{
+		Sql:           "select u.id, u.col from `user` as u where u.id = :cte_col",
+		BindVariables: map[string]*querypb.BindVariable{"cte_col": sqltypes.Int64BindVariable(3)},
+	}}, sbc2.Queries)
+}
+
 func TestSelectEqual(t *testing.T) {
 	executor, sbc1, sbc2, sbclookup, ctx := createExecutorEnv(t)
 
@@ -1654,6 +1691,16 @@ func TestSelectScatterPartial(t *testing.T) {
 	}
 	testQueryLog(t, executor, logChan, "TestExecute", "SELECT", "select /*vt+ SCATTER_ERRORS_AS_WARNINGS=1 */ id from `user`", 8)
 
+	// Fail 1 of N with allow_partial_scatter set in the session succeeds with 7 rows
+	partialSession := &vtgatepb.Session{
+		TargetString:        "@primary",
+		AllowPartialScatter: true,
+	}
+	results, err = executorExec(ctx, executor, partialSession, "select id from user", nil)
+	require.NoError(t, err)
+	assert.Len(t, results.Rows, 7)
+	testQueryLog(t, executor, logChan, "TestExecute", "SELECT", "select id from `user`", 8)
+
 	// When all shards fail, the execution should also fail
 	conns[0].MustFailCodes[vtrpcpb.Code_RESOURCE_EXHAUSTED] = 1000
 	conns[1].MustFailCodes[vtrpcpb.Code_RESOURCE_EXHAUSTED] = 1000
@@ -2981,6 +3028,176 @@ func TestSelectBindvarswithPrepare(t *testing.T) {
 	}
 }
 
+func TestSelectPrepareCache(t *testing.T) {
+	executor, sbc1, _, _, ctx := createExecutorEnv(t)
+
+	session := &vtgatepb.Session{
+		TargetString: "@primary",
+	}
+	bindVars := map[string]*querypb.BindVariable{
+		"id": sqltypes.Int64BindVariable(1),
+	}
+	_, err := executorPrepare(ctx, executor, session, "select id from `user` where id = :id", bindVars)
+	require.NoError(t, err)
+	require.Len(t, sbc1.Queries, 1)
+
+	// The same statement, written differently, reuses the cached fields.
+	_, err = executorPrepare(ctx, executor, session, "SELECT id FROM user WHERE id = :id", bindVars)
+	require.NoError(t, err)
+	assert.Len(t, sbc1.Queries, 1)
+	assert.EqualValues(t, 1, executor.prepared.Hits())
+	assert.Equal(t, 1, executor.prepared.Len())
+
+	// Bind variables of another type make for a different statement.
+	_, err = executorPrepare(ctx, executor, session, "select id from `user` where id = :id", map[string]*querypb.BindVariable{
+		"id": sqltypes.StringBindVariable("1"),
+	})
+	require.NoError(t, err)
+	assert.Len(t, sbc1.Queries, 2)
+	assert.Equal(t, 2, executor.prepared.Len())
+
+	// So does skipping the plan cache.
+	_, err = executorPrepare(ctx, executor, session, "select /*vt+ SKIP_QUERY_PLAN_CACHE=1 */ id from `user` where id = :id", bindVars)
+	require.NoError(t, err)
+	assert.Len(t, sbc1.Queries, 3)
+	assert.Equal(t, 2, executor.prepared.Len())
+
+	// A DDL may change the fields of the statements, so it clears the cache.
+	_, err = executorExec(ctx, executor, session, "alter table `user` add column b int", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, executor.prepared.Len())
+	sbc1.Queries = nil
+
+	_, err = executorPrepare(ctx, executor, session, "select id from `user` where id = :id", bindVars)
+	require.NoError(t, err)
+	assert.Len(t, sbc1.Queries, 1)
+	assert.Equal(t, 1, executor.prepared.Len())
+}
+
+func TestSelectPrepareCacheTTL(t *testing.T) {
+	executor, sbc1, _, _, ctx := createExecutorEnv(t)
+
+	ttl := preparedStatementCacheTTL
+	defer func() {
+		preparedStatementCacheTTL = ttl
+	}()
+	preparedStatementCacheTTL = time.Nanosecond
+
+	session := &vtgatepb.Session{
+		TargetString: "@primary",
+	}
+	bindVars := map[string]*querypb.BindVariable{
+		"id": sqltypes.Int64BindVariable(1),
+	}
+	_, err := executorPrepare(ctx, executor, session, "select id from `user` where id = :id", bindVars)
+	require.NoError(t, err)
+	require.Len(t, sbc1.Queries, 1)
+
+	// The fields of an expired statement are fetched again, as a DDL that did
+	// not go through the executor may have changed them.
+	time.Sleep(time.Millisecond)
+	_, err = executorPrepare(ctx, executor, session, "select id from `user` where id = :id", bindVars)
+	require.NoError(t, err)
+	assert.Len(t, sbc1.Queries, 2)
+	assert.Equal(t, 1, executor.prepared.Len())
+}
+
+func TestSelectResultCache(t *testing.T) {
+	executor, _, _, sbclookup, ctx := createExecutorEnv(t)
+
+	executor.VSchema().Keyspaces[KsTestUnsharded].Tables["main1"].ResultCache = &vindexes.ResultCache{}
+	executor.resultCache.setVSchema(executor.VSchema())
+	events := make(chan []*binlogdatapb.VEvent)
+	executor.resultCache.start(func(ctx context.Context, keyspace string, filter *binlogdatapb.Filter, send func([]*binlogdatapb.VEvent) error) error {
+		assert.Equal(t, KsTestUnsharded, keyspace)
+		assert.Equal(t, "main1", filter.Rules[0].Match)
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case evs := <-events:
+				if err := send(evs); err != nil {
+					return err
+				}
+			}
+		}
+	})
+
+	// Nothing is cached until the changes to the table are streamed.
+	session := &vtgatepb.Session{TargetString: KsTestUnsharded, Autocommit: true}
+	_, err := executorExec(ctx, executor, session, "select id from main1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, executor.resultCache.entries.Len())
+
+	events <- []*binlogdatapb.VEvent{{Type: binlogdatapb.VEventType_HEARTBEAT}}
+	require.Eventually(t, func() bool {
+		_, ok := executor.resultCache.snapshot([]string{KsTestUnsharded + ".main1"})
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	sbclookup.Queries = nil
+	for i := 0; i < 2; i++ {
+		_, err = executorExec(ctx, executor, session, "select id from main1", nil)
+		require.NoError(t, err)
+	}
+	assert.Len(t, sbclookup.Queries, 1)
+	assert.EqualValues(t, 1, executor.resultCache.entries.Hits())
+
+	// A change to the table invalidates its results.
+	events <- []*binlogdatapb.VEvent{{
+		Type:     binlogdatapb.VEventType_ROW,
+		RowEvent: &binlogdatapb.RowEvent{TableName: "main1"},
+	}}
+	events <- []*binlogdatapb.VEvent{{Type: binlogdatapb.VEventType_HEARTBEAT}}
+	_, err = executorExec(ctx, executor, session, "select id from main1", nil)
+	require.NoError(t, err)
+	assert.Len(t, sbclookup.Queries, 2)
+
+	// So does a DML through vtgate.
+	_, err = executorExec(ctx, executor, session, "delete from main1 where id = 1", nil)
+	require.NoError(t, err)
+	_, err = executorExec(ctx, executor, session, "select id from main1", nil)
+	require.NoError(t, err)
+	assert.Len(t, sbclookup.Queries, 4)
+
+	// The results of the tables without a result cache aren't cached.
+	_, err = executorExec(ctx, executor, session, "select id from music_user_map", nil)
+	require.NoError(t, err)
+	_, err = executorExec(ctx, executor, session, "select id from music_user_map", nil)
+	require.NoError(t, err)
+	assert.Len(t, sbclookup.Queries, 6)
+
+	// Neither are the results read in a transaction.
+	session = &vtgatepb.Session{TargetString: KsTestUnsharded, Autocommit: true, InTransaction: true}
+	_, err = executorExec(ctx, executor, session, "select id from main1", nil)
+	require.NoError(t, err)
+	assert.Len(t, sbclookup.Queries, 7)
+
+	// Nor the results read from a replica, which may lag behind the changes.
+	hits := executor.resultCache.entries.Hits()
+	session = &vtgatepb.Session{TargetString: KsTestUnsharded + "@replica", Autocommit: true}
+	for i := 0; i < 2; i++ {
+		_, err = executorExec(ctx, executor, session, "select id from main1", nil)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, executor.resultCache.entries.Len())
+	assert.Equal(t, hits, executor.resultCache.entries.Hits())
+
+	// Each caller has its own cached results.
+	session = &vtgatepb.Session{TargetString: KsTestUnsharded, Autocommit: true}
+	_, err = executorExec(ctx, executor, session, "select id from main1", nil)
+	require.NoError(t, err)
+	assert.Len(t, sbclookup.Queries, 7)
+
+	otherCtx := callerid.NewContext(ctx, callerid.NewEffectiveCallerID("other", "", ""), callerid.NewImmediateCallerID("other"))
+	for i := 0; i < 2; i++ {
+		_, err = executorExec(otherCtx, executor, session, "select id from main1", nil)
+		require.NoError(t, err)
+	}
+	assert.Len(t, sbclookup.Queries, 8)
+	assert.Equal(t, 2, executor.resultCache.entries.Len())
+}
+
 func TestSelectDatabasePrepare(t *testing.T) {
 	executor, _, _, _, ctx := createExecutorEnv(t)
 	executor.normalize = true
@@ -3137,6 +3354,38 @@ func TestLockReserve(t *testing.T) {
 
 }
 
+func TestSelectTopoNamedLock(t *testing.T) {
+	enableTopoNamedLocks = true
+	defer func() { enableTopoNamedLocks = false }()
+	// The lock functions are routed to the first keyspace, which has to be
+	// unsharded.
+	executor, sbc1, _, _, ctx := createCustomExecutor(t, `{"sharded": false}`)
+
+	session := NewAutocommitSession(&vtgatepb.Session{SessionUUID: "uuid1"})
+	result, err := exec(executor, session, "select get_lock('lock name', 1) from dual")
+	require.NoError(t, err)
+	assert.Equal(t, `[[INT64(1)]]`, fmt.Sprintf("%v", result.Rows))
+	// The lock is in the topo, and no connection is reserved.
+	assert.Nil(t, session.LockSession)
+	assert.Empty(t, sbc1.Queries)
+	ts, err := executor.serv.GetTopoServer()
+	require.NoError(t, err)
+	lock, err := ts.GetNamedLock(ctx, "lock name")
+	require.NoError(t, err)
+	assert.Equal(t, namedLockOwner("uuid1"), lock.Owner)
+
+	other := NewAutocommitSession(&vtgatepb.Session{SessionUUID: "uuid2"})
+	result, err = exec(executor, other, "select get_lock('lock name', 0), is_free_lock('lock name'), is_used_lock('lock name') from dual")
+	require.NoError(t, err)
+	assert.Equal(t, `[[INT64(0) INT64(0) VARCHAR("vtgate session uuid1")]]`, fmt.Sprintf("%v", result.Rows))
+
+	// The locks of a closed session are released.
+	require.NoError(t, executor.CloseSession(ctx, session))
+	result, err = exec(executor, other, "select is_free_lock('lock name') from dual")
+	require.NoError(t, err)
+	assert.Equal(t, `[[INT64(1)]]`, fmt.Sprintf("%v", result.Rows))
+}
+
 func TestSelectFromInformationSchema(t *testing.T) {
 	executor, sbc1, _, _, _ := createExecutorEnv(t)
 	session := NewSafeSession(nil)
@@ -265,6 +265,15 @@ func TestExecutorSet(t *testing.T) {
 	}, {
 		in:  "set @@query_timeout = 50, query_timeout = 75",
 		out: &vtgatepb.Session{Autocommit: true, QueryTimeout: 75},
+	}, {
+		in:  "set scatter_concurrency = 4, shard_timeout = 500, allow_partial_scatter = 1",
+		out: &vtgatepb.Session{Autocommit: true, ScatterConcurrency: 4, ShardTimeout: 500, AllowPartialScatter: true},
+	}, {
+		in:  "set @@scatter_concurrency = -1",
+		err: "invalid scatter_concurrency: -1",
+	}, {
+		in:  "set skip_read_retry = 1",
+		out: &vtgatepb.Session{Autocommit: true, SkipReadRetry: true},
 	}}
 	for i, tcase := range testcases {
 		t.Run(fmt.Sprintf("%d-%s", i, tcase.in), func(t *testing.T) {
@@ -55,6 +55,7 @@ import (
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 	"vitess.io/vitess/go/vt/vtgate/vschemaacl"
 	"vitess.io/vitess/go/vt/vtgate/vtgateservice"
+	"vitess.io/vitess/go/vt/vttablet/sandboxconn"
 )
 
 func TestExecutorResultsExceeded(t *testing.T) {
@@ -213,6 +214,44 @@ func TestDirectTargetRewrites(t *testing.T) {
 	}})
 }
 
+func TestExecutorRoutingRulesSplit(t *testing.T) {
+	executor, sbc1, _, sbclookup, ctx := createExecutorEnv(t)
+
+	srvVSchema := executor.vm.GetCurrentSrvVschema()
+	srvVSchema.RoutingRules = &vschemapb.RoutingRules{Rules: []*vschemapb.RoutingRule{{
+		FromTable:   "main1",
+		ToTables:    []string{KsTestUnsharded + ".main1", KsTestSharded + ".user"},
+		Percentages: []uint32{50, 50},
+	}}}
+	executor.vm.VSchemaUpdate(srvVSchema, nil)
+
+	// find a session routed to each table
+	sessions := make([]*vtgatepb.Session, 2)
+	for i := 0; sessions[0] == nil || sessions[1] == nil; i++ {
+		uuid := fmt.Sprintf("session-%d", i)
+		target := vindexes.RoutingBucket(uuid) / 50
+		if sessions[target] == nil {
+			sessions[target] = &vtgatepb.Session{TargetString: "@primary", SessionUUID: uuid}
+		}
+	}
+
+	// the plans of the sessions routed to different tables are cached apart
+	for i := 0; i < 2; i++ {
+		sbc1.Queries = nil
+		sbclookup.Queries = nil
+		_, err := executorExec(ctx, executor, sessions[0], "select id from main1 where id = 1", nil)
+		require.NoError(t, err)
+		assert.Len(t, sbclookup.Queries, 1)
+		assert.Empty(t, sbc1.Queries)
+
+		sbclookup.Queries = nil
+		_, err = executorExec(ctx, executor, sessions[1], "select id from main1 where id = 1", nil)
+		require.NoError(t, err)
+		assert.Empty(t, sbclookup.Queries)
+		assert.Len(t, sbc1.Queries, 1)
+	}
+}
+
 func TestExecutorTransactionsAutoCommit(t *testing.T) {
 	executor, _, _, sbclookup, ctx := createExecutorEnv(t)
 
@@ -1155,6 +1194,62 @@ func TestExecutorComment(t *testing.T) {
 	}
 }
 
+func TestExecutorReadAfterWrite(t *testing.T) {
+	executor, _, _, sbclookup, ctx := createExecutorEnv(t)
+	replica := executor.scatterConn.gateway.hc.GetHealthyTabletStats(&querypb.Target{
+		Keyspace:   KsTestUnsharded,
+		Shard:      "0",
+		TabletType: topodatapb.TabletType_REPLICA,
+	})[0].Conn.(*sandboxconn.SandboxConn)
+	gtidResult := func(gtidSet string) *sqltypes.Result {
+		return sqltypes.MakeTestResult(sqltypes.MakeTestFields("@@global.gtid_executed", "varchar"), gtidSet)
+	}
+
+	// writes are not tracked by default
+	session := NewSafeSession(&vtgatepb.Session{TargetString: "@primary", Autocommit: true})
+	_, err := executor.Execute(ctx, nil, "TestExecute", session, "update main1 set id = 1", nil)
+	require.NoError(t, err)
+	assert.Nil(t, session.ReadAfterWrite)
+	assert.Len(t, sbclookup.Queries, 1)
+	sbclookup.Queries = nil
+
+	// an autocommit write fetches the GTID set executed by the primary
+	_, err = executor.Execute(ctx, nil, "TestExecute", session, "set session_track_gtids = own_gtid", nil)
+	require.NoError(t, err)
+	sbclookup.SetResults([]*sqltypes.Result{{RowsAffected: 1}, gtidResult("3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5\n")})
+	_, err = executor.Execute(ctx, nil, "TestExecute", session, "update main1 set id = 1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "TestUnsharded/0@3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5", session.ReadAfterWrite.ReadAfterWriteGtid)
+	require.Len(t, sbclookup.Queries, 2)
+	assert.Equal(t, readAfterWriteGTIDQuery, sbclookup.Queries[1].Sql)
+
+	// a transaction is tracked once it is committed
+	_, err = executor.Execute(ctx, nil, "TestExecute", session, "begin", nil)
+	require.NoError(t, err)
+	_, err = executor.Execute(ctx, nil, "TestExecute", session, "update main1 set id = 2", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "TestUnsharded/0@3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5", session.ReadAfterWrite.ReadAfterWriteGtid)
+	sbclookup.SetResults([]*sqltypes.Result{gtidResult("3e11fa47-71ca-11e1-9e33-c80aa9429562:1-7")})
+	_, err = executor.Execute(ctx, nil, "TestExecute", session, "commit", nil)
+	require.NoError(t, err)
+	token := "TestUnsharded/0@3e11fa47-71ca-11e1-9e33-c80aa9429562:1-7"
+	assert.Equal(t, token, session.ReadAfterWrite.ReadAfterWriteGtid)
+
+	result, err := executor.Execute(ctx, nil, "TestExecute", session, "select @@read_after_write_gtid", nil)
+	require.NoError(t, err)
+	assert.Equal(t, token, result.Rows[0][0].ToString())
+
+	// the token can be carried to another session, whose reads on a replica wait for it
+	other := NewSafeSession(&vtgatepb.Session{TargetString: "@replica", Autocommit: true})
+	_, err = executor.Execute(ctx, nil, "TestExecute", other, "set read_after_write_gtid = '"+token+"', read_after_write_timeout = 0.5", nil)
+	require.NoError(t, err)
+	_, err = executor.Execute(ctx, nil, "TestExecute", other, "select id from main1", nil)
+	require.NoError(t, err)
+	require.Len(t, replica.Options, 1)
+	assert.Equal(t, "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-7", replica.Options[0].WaitForGtidSet)
+	assert.Equal(t, 0.5, replica.Options[0].WaitForGtidSetTimeout)
+}
+
 func TestExecutorOther(t *testing.T) {
 	executor, sbc1, sbc2, sbclookup, ctx := createExecutorEnv(t)
 
@@ -2589,13 +2684,12 @@ func TestExecutorCallProc(t *testing.T) {
 func TestExecutorTempTable(t *testing.T) {
 	executor, _, _, sbcUnsharded, ctx := createExecutorEnv(t)
 
-	executor.warnShardedOnly = true
 	creatQuery := "create temporary table temp_t(id bigint primary key)"
 	session := NewSafeSession(&vtgatepb.Session{TargetString: KsTestUnsharded})
 	_, err := executor.Execute(ctx, nil, "TestExecutorTempTable", session, creatQuery, nil)
 	require.NoError(t, err)
 	assert.EqualValues(t, 1, sbcUnsharded.ExecCount.Load())
-	assert.NotEmpty(t, session.Warnings)
+	assert.Empty(t, session.TempTables)
 
 	before := executor.plans.Len()
 
@@ -2605,6 +2699,93 @@ func TestExecutorTempTable(t *testing.T) {
 	assert.Equal(t, before, executor.plans.Len())
 }
 
+func TestExecutorTempTableSharded(t *testing.T) {
+	executor, sbc1, sbc2, _, ctx := createExecutorEnv(t)
+
+	session := NewSafeSession(&vtgatepb.Session{TargetString: KsTestSharded})
+	_, err := executor.Execute(ctx, nil, "TestExecutorTempTableSharded", session, "create temporary table temp_t(id bigint primary key)", nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, sbc1.ExecCount.Load())
+	assert.EqualValues(t, 0, sbc2.ExecCount.Load())
+	assert.True(t, session.InReservedConn())
+	assert.Equal(t, []string{"TestExecutor.temp_t"}, session.TempTables)
+
+	// The queries on the temporary table are routed to the shard it lives on.
+	for _, query := range []string{
+		"insert into temp_t(id) values (1), (2)",
+		"select id from temp_t where id = 1",
+		"update temp_t set id = 3 where id = 2",
+		"delete from temp_t",
+	} {
+		sbc1.Queries = nil
+		_, err = executor.Execute(ctx, nil, "TestExecutorTempTableSharded", session, query, nil)
+		require.NoError(t, err, query)
+		require.Len(t, sbc1.Queries, 1, query)
+		assert.Contains(t, sbc1.Queries[0].Sql, "temp_t", query)
+		assert.EqualValues(t, 0, sbc2.ExecCount.Load(), query)
+	}
+
+	// A join with a sharded table is planned as a join of two routes.
+	sbc1.Queries = nil
+	_, err = executor.Execute(ctx, nil, "TestExecutorTempTableSharded", session, "select temp_t.id, u.name from temp_t join user as u on temp_t.id = u.id", nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, sbc1.Queries)
+	assert.Equal(t, "select temp_t.id from temp_t", sbc1.Queries[0].Sql)
+
+	_, err = executor.Execute(ctx, nil, "TestExecutorTempTableSharded", session, "drop temporary table temp_t", nil)
+	require.NoError(t, err)
+	assert.Empty(t, session.TempTables)
+
+	_, err = executor.Execute(ctx, nil, "TestExecutorTempTableSharded", session, "select id from temp_t", nil)
+	require.ErrorContains(t, err, "table temp_t not found")
+
+	// The temporary tables are forgotten with the reserved connections of the session.
+	_, err = executor.Execute(ctx, nil, "TestExecutorTempTableSharded", session, "create temporary table temp_t(id bigint primary key)", nil)
+	require.NoError(t, err)
+	require.NoError(t, executor.CloseSession(ctx, session))
+	assert.Empty(t, session.TempTables)
+}
+
+func TestExecutorMultiplexReservedConns(t *testing.T) {
+	executor, sbc1, _, sbcUnsharded, ctx := createExecutorEnv(t)
+	multiplexReservedConns = true
+	defer func() {
+		multiplexReservedConns = false
+	}()
+
+	// The reserved connections of a session that only set system variables are released after each query.
+	session := NewSafeSession(&vtgatepb.Session{TargetString: KsTestUnsharded, Autocommit: true, InReservedConn: true, SystemVariables: map[string]string{"time_zone": "'+08:00'"}})
+	for i := 1; i <= 2; i++ {
+		_, err := executor.Execute(ctx, nil, "TestExecutorMultiplexReservedConns", session, "select id from music_user_map where id = 1", nil)
+		require.NoError(t, err)
+		assert.Empty(t, session.ShardSessions)
+		assert.True(t, session.InReservedConn())
+		assert.EqualValues(t, i, sbcUnsharded.ReserveCount.Load())
+		assert.EqualValues(t, i, sbcUnsharded.ReleaseCount.Load())
+	}
+
+	// The reserved connections of a transaction are released at its end.
+	_, err := executor.Execute(ctx, nil, "TestExecutorMultiplexReservedConns", session, "begin", nil)
+	require.NoError(t, err)
+	_, err = executor.Execute(ctx, nil, "TestExecutorMultiplexReservedConns", session, "select id from music_user_map where id = 1", nil)
+	require.NoError(t, err)
+	assert.Len(t, session.ShardSessions, 1)
+	_, err = executor.Execute(ctx, nil, "TestExecutorMultiplexReservedConns", session, "commit", nil)
+	require.NoError(t, err)
+	assert.Empty(t, session.ShardSessions)
+	assert.EqualValues(t, 3, sbcUnsharded.ReleaseCount.Load())
+
+	// The reserved connections holding temporary tables are kept.
+	session = NewSafeSession(&vtgatepb.Session{TargetString: KsTestSharded, Autocommit: true})
+	_, err = executor.Execute(ctx, nil, "TestExecutorMultiplexReservedConns", session, "create temporary table temp_t(id bigint primary key)", nil)
+	require.NoError(t, err)
+	assert.True(t, session.PinnedReservedConn)
+	_, err = executor.Execute(ctx, nil, "TestExecutorMultiplexReservedConns", session, "select id from temp_t", nil)
+	require.NoError(t, err)
+	assert.Len(t, session.ShardSessions, 1)
+	assert.EqualValues(t, 0, sbc1.ReleaseCount.Load())
+}
+
 func TestExecutorShowVitessMigrations(t *testing.T) {
 	executor, sbc1, sbc2, _, ctx := createExecutorEnv(t)
 
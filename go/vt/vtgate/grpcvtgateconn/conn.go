@@ -49,10 +49,12 @@ func init() {
 	vtgateconn.RegisterDialer("grpc", dial)
 
 	for _, cmd := range []string{
+		"vstream2kafka",
 		"vtbench",
 		"vtclient",
 		"vtcombo",
 		"vtctl",
+		"vttablet",
 		"vttestserver",
 	} {
 		servenv.OnParseFor(cmd, registerFlags)
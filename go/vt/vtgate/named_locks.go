@@ -0,0 +1,250 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/log"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// topoNamedLocks serves the lock functions of the sessions, GET_LOCK() and
+// the like, with the named locks of the global topo instead of the locks of
+// MySQL, so they are shared by all the vtgates. The locks are owned by the
+// sessions, and renewed in the background until they are released or their
+// session is closed. If the vtgate dies, they expire after their TTL.
+type topoNamedLocks struct {
+	ts  *topo.Server
+	ttl time.Duration
+
+	mu sync.Mutex
+	// held counts the acquisitions of the locks held by each session, by
+	// session UUID and lock name.
+	held map[string]map[string]int
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newTopoNamedLocks(ts *topo.Server, ttl time.Duration) *topoNamedLocks {
+	ctx, cancel := context.WithCancel(context.Background())
+	nl := &topoNamedLocks{
+		ts:     ts,
+		ttl:    ttl,
+		held:   make(map[string]map[string]int),
+		cancel: cancel,
+	}
+	nl.wg.Add(1)
+	go nl.renew(ctx)
+	return nl
+}
+
+// namedLockOwner returns the owner of the named locks of a session.
+func namedLockOwner(sessionUUID string) string {
+	return "vtgate session " + sessionUUID
+}
+
+// execute executes a lock function for a session, and returns its result
+// like MySQL would.
+func (nl *topoNamedLocks) execute(ctx context.Context, sessionUUID string, lockFuncType sqlparser.LockingFuncType, name string, timeout time.Duration) (sqltypes.Value, error) {
+	if sessionUUID == "" {
+		return sqltypes.NULL, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "lock functions require a session UUID when they use the named locks of the topo")
+	}
+
+	switch lockFuncType {
+	case sqlparser.GetLock:
+		return nl.getLock(ctx, sessionUUID, name, timeout)
+	case sqlparser.ReleaseLock:
+		return nl.releaseLock(ctx, sessionUUID, name)
+	case sqlparser.ReleaseAllLocks:
+		return nl.releaseAllLocks(ctx, sessionUUID)
+	case sqlparser.IsFreeLock, sqlparser.IsUsedLock:
+		lock, err := nl.ts.GetNamedLock(ctx, name)
+		switch {
+		case topo.IsErrType(err, topo.NoNode):
+			if lockFuncType == sqlparser.IsFreeLock {
+				return sqltypes.NewInt64(1), nil
+			}
+			return sqltypes.NULL, nil
+		case err != nil:
+			return sqltypes.NULL, err
+		}
+		if lockFuncType == sqlparser.IsFreeLock {
+			return sqltypes.NewInt64(0), nil
+		}
+		// MySQL returns the id of the connection holding the lock, we
+		// return the owner of the lock instead.
+		return sqltypes.NewVarChar(lock.Owner), nil
+	}
+	return sqltypes.NULL, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "[BUG] unexpected lock function: %v", lockFuncType)
+}
+
+func (nl *topoNamedLocks) getLock(ctx context.Context, sessionUUID, name string, timeout time.Duration) (sqltypes.Value, error) {
+	nl.mu.Lock()
+	if nl.held[sessionUUID][name] > 0 {
+		nl.held[sessionUUID][name]++
+		nl.mu.Unlock()
+		return sqltypes.NewInt64(1), nil
+	}
+	nl.mu.Unlock()
+
+	_, err := nl.ts.AcquireNamedLock(ctx, name, namedLockOwner(sessionUUID), nl.ttl, timeout)
+	switch {
+	case topo.IsErrType(err, topo.NodeExists):
+		// Another session holds the lock.
+		return sqltypes.NewInt64(0), nil
+	case err != nil:
+		return sqltypes.NULL, err
+	}
+
+	nl.mu.Lock()
+	defer nl.mu.Unlock()
+	if nl.held[sessionUUID] == nil {
+		nl.held[sessionUUID] = make(map[string]int)
+	}
+	nl.held[sessionUUID][name]++
+	return sqltypes.NewInt64(1), nil
+}
+
+func (nl *topoNamedLocks) releaseLock(ctx context.Context, sessionUUID, name string) (sqltypes.Value, error) {
+	nl.mu.Lock()
+	count := nl.held[sessionUUID][name]
+	if count > 1 {
+		nl.held[sessionUUID][name]--
+		nl.mu.Unlock()
+		return sqltypes.NewInt64(1), nil
+	}
+	if count == 1 {
+		nl.forget(sessionUUID, name)
+	}
+	nl.mu.Unlock()
+
+	if count == 1 {
+		err := nl.ts.ReleaseNamedLock(ctx, name, namedLockOwner(sessionUUID))
+		if err != nil && !topo.IsErrType(err, topo.NoNode) {
+			return sqltypes.NULL, err
+		}
+		return sqltypes.NewInt64(1), nil
+	}
+
+	// The session does not hold the lock: MySQL returns 0 if another one
+	// does, and NULL if the lock does not exist.
+	_, err := nl.ts.GetNamedLock(ctx, name)
+	switch {
+	case topo.IsErrType(err, topo.NoNode):
+		return sqltypes.NULL, nil
+	case err != nil:
+		return sqltypes.NULL, err
+	}
+	return sqltypes.NewInt64(0), nil
+}
+
+// releaseAllLocks releases the locks held by a session, and returns the
+// number of acquisitions released.
+func (nl *topoNamedLocks) releaseAllLocks(ctx context.Context, sessionUUID string) (sqltypes.Value, error) {
+	nl.mu.Lock()
+	held := nl.held[sessionUUID]
+	delete(nl.held, sessionUUID)
+	nl.mu.Unlock()
+
+	var released int64
+	var err error
+	for name, count := range held {
+		released += int64(count)
+		if rerr := nl.ts.ReleaseNamedLock(ctx, name, namedLockOwner(sessionUUID)); rerr != nil && !topo.IsErrType(rerr, topo.NoNode) {
+			log.Warningf("Failed to release named lock %v of session %v, it will expire in %v: %v", name, sessionUUID, nl.ttl, rerr)
+			err = rerr
+		}
+	}
+	if err != nil {
+		return sqltypes.NULL, err
+	}
+	return sqltypes.NewInt64(released), nil
+}
+
+// closeSession releases the locks held by a session that is closed.
+func (nl *topoNamedLocks) closeSession(ctx context.Context, sessionUUID string) {
+	if sessionUUID == "" {
+		return
+	}
+	_, _ = nl.releaseAllLocks(ctx, sessionUUID)
+}
+
+// forget removes a lock from the locks held by a session. nl.mu must be
+// held.
+func (nl *topoNamedLocks) forget(sessionUUID, name string) {
+	delete(nl.held[sessionUUID], name)
+	if len(nl.held[sessionUUID]) == 0 {
+		delete(nl.held, sessionUUID)
+	}
+}
+
+// renew renews the held locks before they expire, until ctx is done.
+func (nl *topoNamedLocks) renew(ctx context.Context) {
+	defer nl.wg.Done()
+
+	ticker := time.NewTicker(nl.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		type heldLock struct{ sessionUUID, name string }
+		var locks []heldLock
+		nl.mu.Lock()
+		for sessionUUID, names := range nl.held {
+			for name := range names {
+				locks = append(locks, heldLock{sessionUUID, name})
+			}
+		}
+		nl.mu.Unlock()
+
+		for _, lock := range locks {
+			_, err := nl.ts.RenewNamedLock(ctx, lock.name, namedLockOwner(lock.sessionUUID), nl.ttl)
+			switch {
+			case err == nil:
+			case topo.IsErrType(err, topo.NoNode):
+				// The lock expired, and may have been taken by another
+				// session since.
+				log.Warningf("Session %v lost named lock %v: %v", lock.sessionUUID, lock.name, err)
+				nl.mu.Lock()
+				if nl.held[lock.sessionUUID][lock.name] > 0 {
+					nl.forget(lock.sessionUUID, lock.name)
+				}
+				nl.mu.Unlock()
+			case ctx.Err() == nil:
+				log.Warningf("Failed to renew named lock %v of session %v: %v", lock.name, lock.sessionUUID, err)
+			}
+		}
+	}
+}
+
+// close stops renewing the held locks, which expire after their TTL.
+func (nl *topoNamedLocks) close() {
+	nl.cancel()
+	nl.wg.Wait()
+}
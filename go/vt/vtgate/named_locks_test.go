@@ -0,0 +1,107 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+)
+
+func TestTopoNamedLocks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "aa")
+	defer ts.Close()
+	nl := newTopoNamedLocks(ts, time.Minute)
+	defer nl.close()
+
+	exec := func(sessionUUID string, lockFuncType sqlparser.LockingFuncType, name string) sqltypes.Value {
+		t.Helper()
+		v, err := nl.execute(ctx, sessionUUID, lockFuncType, name, 0)
+		require.NoError(t, err)
+		return v
+	}
+
+	assert.Equal(t, sqltypes.NewInt64(1), exec("s1", sqlparser.IsFreeLock, "l1"))
+	assert.Equal(t, sqltypes.NULL, exec("s1", sqlparser.IsUsedLock, "l1"))
+	assert.Equal(t, sqltypes.NULL, exec("s1", sqlparser.ReleaseLock, "l1"))
+
+	// get_lock is re-entrant, and the lock is held by the session until it
+	// is released as many times.
+	assert.Equal(t, sqltypes.NewInt64(1), exec("s1", sqlparser.GetLock, "l1"))
+	assert.Equal(t, sqltypes.NewInt64(1), exec("s1", sqlparser.GetLock, "l1"))
+	assert.Equal(t, sqltypes.NewInt64(0), exec("s1", sqlparser.IsFreeLock, "l1"))
+	assert.Equal(t, sqltypes.NewVarChar(namedLockOwner("s1")), exec("s2", sqlparser.IsUsedLock, "l1"))
+
+	assert.Equal(t, sqltypes.NewInt64(0), exec("s2", sqlparser.GetLock, "l1"))
+	assert.Equal(t, sqltypes.NewInt64(0), exec("s2", sqlparser.ReleaseLock, "l1"))
+
+	assert.Equal(t, sqltypes.NewInt64(1), exec("s1", sqlparser.ReleaseLock, "l1"))
+	assert.Equal(t, sqltypes.NewInt64(0), exec("s1", sqlparser.IsFreeLock, "l1"))
+	assert.Equal(t, sqltypes.NewInt64(1), exec("s1", sqlparser.ReleaseLock, "l1"))
+	assert.Equal(t, sqltypes.NewInt64(1), exec("s1", sqlparser.IsFreeLock, "l1"))
+
+	assert.Equal(t, sqltypes.NewInt64(1), exec("s2", sqlparser.GetLock, "l1"))
+	assert.Equal(t, sqltypes.NewInt64(1), exec("s2", sqlparser.GetLock, "l2"))
+	assert.Equal(t, sqltypes.NewInt64(1), exec("s2", sqlparser.GetLock, "l2"))
+	assert.Equal(t, sqltypes.NewInt64(3), exec("s2", sqlparser.ReleaseAllLocks, ""))
+	assert.Equal(t, sqltypes.NewInt64(1), exec("s1", sqlparser.IsFreeLock, "l1"))
+	assert.Equal(t, sqltypes.NewInt64(1), exec("s1", sqlparser.IsFreeLock, "l2"))
+
+	// The locks of a closed session are released.
+	assert.Equal(t, sqltypes.NewInt64(1), exec("s3", sqlparser.GetLock, "l3"))
+	nl.closeSession(ctx, "s3")
+	assert.Equal(t, sqltypes.NewInt64(1), exec("s1", sqlparser.IsFreeLock, "l3"))
+
+	_, err := nl.execute(ctx, "", sqlparser.GetLock, "l1", 0)
+	assert.ErrorContains(t, err, "require a session UUID")
+}
+
+func TestTopoNamedLocksRenewal(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "aa")
+	defer ts.Close()
+	ttl := 300 * time.Millisecond
+	nl := newTopoNamedLocks(ts, ttl)
+	defer nl.close()
+
+	v, err := nl.execute(ctx, "s1", sqlparser.GetLock, "l1", 0)
+	require.NoError(t, err)
+	assert.Equal(t, sqltypes.NewInt64(1), v)
+
+	// The lock is still held well after its TTL, as it is renewed.
+	time.Sleep(3 * ttl)
+	lock, err := ts.GetNamedLock(ctx, "l1")
+	require.NoError(t, err)
+	assert.Equal(t, namedLockOwner("s1"), lock.Owner)
+
+	// Once the locks are not renewed anymore, they expire.
+	nl.close()
+	time.Sleep(2 * ttl)
+	_, err = ts.GetNamedLock(ctx, "l1")
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "expected NoNode, got %v", err)
+}
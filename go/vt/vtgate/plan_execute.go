@@ -145,6 +145,11 @@ func (e *Executor) newExecute(
 		}
 
 		// 5: Execute the plan and retry if needed
+		releaseQuota, err := e.quotas.acquire(ctx, plan.TablesUsed)
+		if err != nil {
+			logStats.Error = err
+			return err
+		}
 		if plan.Instructions.NeedsTransaction() {
 			err = e.insideTransaction(ctx, safeSession, logStats,
 				func() error {
@@ -153,6 +158,13 @@ func (e *Executor) newExecute(
 		} else {
 			err = execPlan(ctx, plan, vcursor, bindVars, execStart)
 		}
+		releaseQuota(logStats.RowsReturned)
+
+		if plan.Type == sqlparser.StmtDDL {
+			// The DDL may have changed the fields of prepared statements, even
+			// if it failed on some of the shards.
+			e.prepared.Clear()
+		}
 
 		if err == nil || safeSession.InTransaction() {
 			return err
@@ -277,6 +289,19 @@ func (e *Executor) executePlan(
 	execStart time.Time,
 ) (*sqltypes.Result, error) {
 
+	// Serve the query from the result cache if possible.
+	resultKey, resultTTL, resultCachable := e.resultCacheEntry(ctx, safeSession, plan, vcursor, bindVars)
+	var versions []uint64
+	if resultCachable {
+		if qr, ok := e.resultCache.get(resultKey); ok {
+			e.setLogStats(logStats, plan, vcursor, execStart, nil, qr)
+			return qr, nil
+		}
+		// The versions have to be taken before executing, so that the changes
+		// made while the query runs prevent its result from being cached.
+		versions, resultCachable = e.resultCache.snapshot(plan.TablesUsed)
+	}
+
 	// 4: Execute!
 	qr, err := vcursor.ExecutePrimitive(ctx, plan.Instructions, bindVars, true)
 
@@ -287,6 +312,17 @@ func (e *Executor) executePlan(
 	if err != nil {
 		return nil, e.rollbackExecIfNeeded(ctx, safeSession, bindVars, logStats, err)
 	}
+
+	switch plan.Type {
+	case sqlparser.StmtSelect:
+		if resultCachable {
+			e.resultCache.set(resultKey, plan.TablesUsed, versions, resultTTL, qr)
+		}
+	case sqlparser.StmtInsert, sqlparser.StmtReplace, sqlparser.StmtUpdate, sqlparser.StmtDelete:
+		// Don't wait for the VStream to invalidate the results of the tables
+		// changed through this vtgate.
+		e.resultCache.invalidateTables(plan.TablesUsed)
+	}
 	return qr, nil
 }
 
@@ -342,6 +378,7 @@ func (e *Executor) setLogStats(logStats *logstats.LogStats, plan *engine.Plan, v
 	logStats.ActiveKeyspace = vcursor.keyspace
 	logStats.TablesUsed = plan.TablesUsed
 	logStats.TabletType = vcursor.TabletType().String()
+	logStats.PlanType = plan.Instructions.RouteType()
 	errCount := e.logExecutionEnd(logStats, execStart, plan, err, qr)
 	plan.AddStats(1, time.Since(logStats.StartTime), logStats.ShardQueries, logStats.RowsAffected, logStats.RowsReturned, errCount)
 }
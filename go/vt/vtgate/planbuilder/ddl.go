@@ -6,6 +6,7 @@ import (
 
 	"vitess.io/vitess/go/vt/key"
 	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	"vitess.io/vitess/go/vt/schema"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/engine"
@@ -50,11 +51,17 @@ func buildGeneralDDLPlan(ctx context.Context, sql string, ddlStatement sqlparser
 	if err != nil {
 		return nil, err
 	}
+	ddlStrategy, err := ddlStrategyDirective(ddlStatement)
+	if err != nil {
+		return nil, err
+	}
 
 	if ddlStatement.IsTemporary() {
-		err := vschema.ErrorIfShardedF(normalDDLPlan.Keyspace, "temporary table", "Temporary table not supported in sharded database %s", normalDDLPlan.Keyspace.Name)
-		if err != nil {
-			return nil, err
+		if normalDDLPlan.Keyspace.Sharded {
+			// The temporary tables of a sharded keyspace only live on the shard they are pinned to.
+			if _, allShards := normalDDLPlan.TargetDestination.(key.DestinationAllShards); allShards {
+				normalDDLPlan.TargetDestination = key.DestinationKeyspaceID(vindexes.TemporaryTablePinned)
+			}
 		}
 		onlineDDLPlan = nil // emptying this so it does not accidentally gets used somewhere
 	}
@@ -66,6 +73,8 @@ func buildGeneralDDLPlan(ctx context.Context, sql string, ddlStatement sqlparser
 		NormalDDL: normalDDLPlan,
 		OnlineDDL: onlineDDLPlan,
 
+		DDLStrategy: ddlStrategy,
+
 		DirectDDLEnabled: enableDirectDDL,
 		OnlineDDLEnabled: enableOnlineDDL,
 
@@ -79,6 +88,28 @@ func buildGeneralDDLPlan(ctx context.Context, sql string, ddlStatement sqlparser
 	return newPlanResult(eddl, tc.getTables()...), nil
 }
 
+// ddlStrategyDirective returns the strategy of the DDL_STRATEGY comment directive of the statement, if any.
+// As the directive explicitly asks for an online DDL, the temporary tables, which cannot be changed by
+// online DDLs, are rejected instead of silently being changed directly.
+func ddlStrategyDirective(ddlStatement sqlparser.DDLStatement) (string, error) {
+	commented, ok := ddlStatement.(sqlparser.Commented)
+	if !ok {
+		return "", nil
+	}
+	ddlStrategy, ok := commented.GetParsedComments().Directives().GetString(sqlparser.DirectiveDDLStrategy, "")
+	if !ok || ddlStrategy == "" {
+		return "", nil
+	}
+	setting, err := schema.ParseDDLStrategy(ddlStrategy)
+	if err != nil {
+		return "", err
+	}
+	if !setting.Strategy.IsDirect() && ddlStatement.IsTemporary() {
+		return "", vterrors.VT12001(fmt.Sprintf("online DDL strategy %s on temporary tables", setting.Strategy))
+	}
+	return ddlStrategy, nil
+}
+
 func buildByPassDDLPlan(sql string, vschema plancontext.VSchema) (*planResult, error) {
 	keyspace, err := vschema.DefaultKeyspace()
 	if err != nil {
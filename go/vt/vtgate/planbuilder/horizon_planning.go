@@ -50,6 +50,18 @@ func (hp *horizonPlanning) planHorizon(ctx *plancontext.PlanningContext, plan lo
 		return plan, nil
 	}
 
+	if operators.ContainsWindowFunction(hp.sel.SelectExprs) || operators.ContainsWindowFunction(hp.sel.OrderBy) {
+		// this planner can only send window functions to MySQL, which is only correct
+		// when all the rows of every partition live on the same shard
+		local, err := operators.ShardLocalWindows(ctx, hp.sel)
+		if err != nil {
+			return nil, err
+		}
+		if !isRoute || !local {
+			return nil, vterrors.VT12001("window functions in this cross-shard query")
+		}
+	}
+
 	// If the current plan is a simpleProjection, we want to rewrite derived expression.
 	// In transformDerivedPlan (operator_transformers.go), derived tables that are not
 	// a simple route are put behind a simpleProjection. In this simple projection,
@@ -0,0 +1,340 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package planbuilder
+
+import (
+	"slices"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/json"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+)
+
+// jsonTablesOf returns the JSON_TABLE expressions of the FROM clause of the SELECT statement,
+// without the ones of its subqueries
+func jsonTablesOf(sel *sqlparser.Select) []*sqlparser.JSONTableExpr {
+	var jts []*sqlparser.JSONTableExpr
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node := node.(type) {
+		case *sqlparser.JSONTableExpr:
+			jts = append(jts, node)
+			return false, nil
+		case *sqlparser.DerivedTable, *sqlparser.Subquery:
+			return false, nil
+		}
+		return true, nil
+	}, sqlparser.TableExprs(sel.From))
+	return jts
+}
+
+// buildJSONTablePlan plans a SELECT statement reading from JSON_TABLE expressions.
+// The statement is sent as a whole to a keyspace when its other tables are all in the
+// same unsharded keyspace or in the same shard. Otherwise, the rows of the JSON_TABLE are
+// produced by the vtgate, from the documents read from the other tables.
+func buildJSONTablePlan(sel *sqlparser.Select, reservedVars *sqlparser.ReservedVars, vschema plancontext.VSchema, version querypb.ExecuteOptions_PlannerVersion) (*planResult, error) {
+	jts := jsonTablesOf(sel)
+	tables := make(map[string]*cteTable, len(jts))
+	for _, jt := range jts {
+		alias := jt.Alias.String()
+		if tables[alias] != nil {
+			return nil, vterrors.VT03013(alias)
+		}
+		tables[alias] = &cteTable{name: alias, columns: jsonTableColumnNames(jt.Columns)}
+	}
+	lookup := func(expr sqlparser.TableExpr) (string, *cteTable) {
+		jt, isJSONTable := expr.(*sqlparser.JSONTableExpr)
+		if !isJSONTable {
+			return "", nil
+		}
+		return jt.Alias.String(), tables[jt.Alias.String()]
+	}
+
+	block, err := newCTEBlock(sel, lookup, reservedVars)
+	if err != nil {
+		return nil, err
+	}
+	plan, tablesUsed, err := newBuildSelectPlan(block.probe, reservedVars, vschema, version)
+	if err != nil {
+		return nil, err
+	}
+	block.plan = plan.Primitive()
+
+	if readsTables(block.probe) {
+		routed := []engine.Primitive{block.plan}
+		if rp := singleShardRouting(routed, map[*sqlparser.Select]*cteBlock{sel: block}); rp != nil {
+			plan, err := sendSingleShardStatement(sel, rp, routed, vschema)
+			if err != nil {
+				return nil, err
+			}
+			return newPlanResult(plan, tablesUsed...), nil
+		}
+	}
+
+	if len(jts) != 1 {
+		return nil, vterrors.VT12001("several JSON_TABLE expressions in a cross-shard query")
+	}
+	evaluated, err := evaluateJSONTable(sel, jts[0], tables[jts[0].Alias.String()], block, reservedVars, vschema, version)
+	if err != nil {
+		return nil, err
+	}
+	return newPlanResult(evaluated, tablesUsed...), nil
+}
+
+// jsonTableColumnNames returns the names of the columns of a JSON_TABLE, including
+// the ones of its NESTED PATH clauses
+func jsonTableColumnNames(defs []*sqlparser.JtColumnDefinition) []string {
+	var names []string
+	for _, def := range defs {
+		switch {
+		case def.JtOrdinal != nil:
+			names = append(names, def.JtOrdinal.Name.String())
+		case def.JtPath != nil:
+			names = append(names, def.JtPath.Name.String())
+		case def.JtNestedPath != nil:
+			names = append(names, jsonTableColumnNames(def.JtNestedPath.Columns)...)
+		}
+	}
+	return names
+}
+
+// evaluateJSONTable plans the evaluation of the JSON_TABLE by the vtgate. The other tables of
+// the query are read with the predicates that don't use the columns of the JSON_TABLE, and the
+// vtgate then produces the rows of the JSON_TABLE for each of their rows, which it filters,
+// sorts and projects.
+func evaluateJSONTable(sel *sqlparser.Select, jt *sqlparser.JSONTableExpr, table *cteTable, block *cteBlock, reservedVars *sqlparser.ReservedVars, vschema plancontext.VSchema, version querypb.ExecuteOptions_PlannerVersion) (engine.Primitive, error) {
+	unsupported := func(what string) error {
+		return vterrors.VT12001(what + " in a cross-shard query using JSON_TABLE")
+	}
+	if !block.exact {
+		return nil, unsupported("an outer join")
+	}
+	aliases := map[string]*cteTable{table.name: table}
+	sel = sqlparser.CloneRefOfSelect(sel)
+	sel.SelectExprs = expandStars(sel.SelectExprs, aliases)
+	if err := checkVTGateSelect(sel, unsupported); err != nil {
+		return nil, err
+	}
+
+	readsOtherTables := readsTables(block.probe)
+	var inputPredicates, predicates []sqlparser.Expr
+	if block.probe.Where != nil {
+		for _, predicate := range sqlparser.SplitAndExpression(nil, block.probe.Where.Expr) {
+			if readsOtherTables && !usesVars(predicate, block.vars) {
+				inputPredicates = append(inputPredicates, predicate)
+				continue
+			}
+			predicates = append(predicates, restoreJSONTableColumns(predicate, table, block.vars))
+		}
+	}
+
+	var inputColumns []*sqlparser.ColName
+	collect := func(node sqlparser.SQLNode) {
+		_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+			col, isCol := node.(*sqlparser.ColName)
+			if !isCol {
+				return true, nil
+			}
+			if _, offset := cteColumn(col, aliases); offset < 0 && !slices.ContainsFunc(inputColumns, func(c *sqlparser.ColName) bool {
+				return sqlparser.Equals.RefOfColName(c, col)
+			}) {
+				inputColumns = append(inputColumns, col)
+			}
+			return true, nil
+		}, node)
+	}
+	collect(jt.Expr)
+	collect(sel.SelectExprs)
+	collect(sel.OrderBy)
+	for _, predicate := range predicates {
+		collect(predicate)
+	}
+
+	// the columns of the JSON_TABLE follow the ones of the other tables, or the
+	// literal selected when the query uses none of them
+	width := 0
+	if readsOtherTables {
+		width = max(len(inputColumns), 1)
+	}
+	cfg := &evalengine.Config{
+		ResolveColumn: func(col *sqlparser.ColName) (int, error) {
+			if _, offset := cteColumn(col, aliases); offset >= 0 {
+				return width + offset, nil
+			}
+			offset := slices.IndexFunc(inputColumns, func(c *sqlparser.ColName) bool {
+				return sqlparser.Equals.RefOfColName(c, col)
+			})
+			if offset < 0 || !readsOtherTables {
+				return 0, vterrors.VT03019(sqlparser.String(col))
+			}
+			return offset, nil
+		},
+	}
+
+	var input engine.Primitive = &engine.SingleRow{}
+	if readsOtherTables {
+		inputSel := &sqlparser.Select{From: block.probe.From}
+		for _, col := range inputColumns {
+			inputSel.SelectExprs = append(inputSel.SelectExprs, &sqlparser.AliasedExpr{Expr: col})
+		}
+		if len(inputColumns) == 0 {
+			inputSel.SelectExprs = sqlparser.SelectExprs{&sqlparser.AliasedExpr{Expr: sqlparser.NewIntLiteral("1")}}
+		}
+		if len(inputPredicates) > 0 {
+			inputSel.Where = sqlparser.NewWhere(sqlparser.WhereClause, sqlparser.AndExpressions(inputPredicates...))
+		}
+		plan, _, err := newBuildSelectPlan(inputSel, reservedVars, vschema, version)
+		if err != nil {
+			return nil, err
+		}
+		input = plan.Primitive()
+	}
+
+	jtPrimitive := &engine.JSONTable{Input: input}
+	var err error
+	if jtPrimitive.Doc, err = evalengine.Translate(jt.Expr, cfg); err != nil {
+		return nil, err
+	}
+	if jtPrimitive.Path, err = jsonTablePath(jt.Filter); err != nil {
+		return nil, err
+	}
+	if jtPrimitive.Columns, jtPrimitive.Fields, err = jsonTableColumns(jt.Columns); err != nil {
+		return nil, err
+	}
+
+	columns := table.columns
+	if width > 0 {
+		columns = nil
+		for _, expr := range sel.SelectExprs {
+			if _, isStar := expr.(*sqlparser.StarExpr); isStar {
+				return nil, unsupported("'*' with other tables")
+			}
+		}
+	}
+	return planVTGateSelect(sel, sqlparser.AndExpressions(predicates...), cfg, columns, jtPrimitive, unsupported)
+}
+
+// usesVars returns true if the expression uses one of the arguments
+func usesVars(expr sqlparser.Expr, vars map[string]int) bool {
+	found := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if arg, isArg := node.(*sqlparser.Argument); isArg {
+			_, found = vars[arg.Name]
+		}
+		return !found, nil
+	}, expr)
+	return found
+}
+
+// restoreJSONTableColumns replaces the arguments of the expression by the columns of the
+// JSON_TABLE they replaced
+func restoreJSONTableColumns(expr sqlparser.Expr, table *cteTable, vars map[string]int) sqlparser.Expr {
+	return sqlparser.SafeRewrite(sqlparser.CloneExpr(expr), nil, func(cursor *sqlparser.Cursor) bool {
+		arg, isArg := cursor.Node().(*sqlparser.Argument)
+		if !isArg {
+			return true
+		}
+		if offset, found := vars[arg.Name]; found {
+			cursor.Replace(sqlparser.NewColNameWithQualifier(table.columns[offset], sqlparser.TableName{Name: sqlparser.NewIdentifierCS(table.name)}))
+		}
+		return true
+	}).(sqlparser.Expr)
+}
+
+func jsonTablePath(expr sqlparser.Expr) (*json.Path, error) {
+	lit, isLiteral := expr.(*sqlparser.Literal)
+	if !isLiteral || lit.Type != sqlparser.StrVal {
+		return nil, vterrors.VT12001("a JSON_TABLE path that is not a string literal in a cross-shard query")
+	}
+	var parser json.PathParser
+	return parser.ParseBytes([]byte(lit.Val))
+}
+
+func jsonTableResponse(response *sqlparser.JtOnResponse) (evalengine.JSONTableResponse, error) {
+	if response == nil {
+		return evalengine.JSONTableResponse{}, nil
+	}
+	switch response.ResponseType {
+	case sqlparser.ErrorJSONType:
+		return evalengine.JSONTableResponse{Error: true}, nil
+	case sqlparser.DefaultJSONType:
+		lit, isLiteral := response.Expr.(*sqlparser.Literal)
+		if !isLiteral || lit.Type != sqlparser.StrVal {
+			return evalengine.JSONTableResponse{}, vterrors.VT12001("a JSON_TABLE default value that is not a string literal in a cross-shard query")
+		}
+		var parser json.Parser
+		doc, err := parser.ParseBytes([]byte(lit.Val))
+		if err != nil {
+			return evalengine.JSONTableResponse{}, err
+		}
+		return evalengine.JSONTableResponse{Default: doc}, nil
+	}
+	return evalengine.JSONTableResponse{}, nil
+}
+
+// jsonTableColumns returns the columns of a JSON_TABLE evaluated by the vtgate, and their fields
+func jsonTableColumns(defs []*sqlparser.JtColumnDefinition) ([]*evalengine.JSONTableColumn, []*querypb.Field, error) {
+	var columns []*evalengine.JSONTableColumn
+	var fields []*querypb.Field
+	for _, def := range defs {
+		switch {
+		case def.JtOrdinal != nil:
+			name := def.JtOrdinal.Name.String()
+			columns = append(columns, &evalengine.JSONTableColumn{Name: name, Type: sqltypes.Uint32, Ordinality: true})
+			fields = append(fields, &querypb.Field{Name: name, Type: sqltypes.Uint32, Charset: collations.CollationBinaryID})
+		case def.JtPath != nil:
+			col := &evalengine.JSONTableColumn{
+				Name:      def.JtPath.Name.String(),
+				Type:      def.JtPath.Type.SQLType(),
+				Collation: collations.CollationBinaryID,
+				Exists:    def.JtPath.JtColExists,
+			}
+			if sqltypes.IsText(col.Type) {
+				col.Collation = collations.CollationUtf8mb4ID
+			}
+			var err error
+			if col.Path, err = jsonTablePath(def.JtPath.Path); err != nil {
+				return nil, nil, err
+			}
+			if col.OnEmpty, err = jsonTableResponse(def.JtPath.EmptyOnResponse); err != nil {
+				return nil, nil, err
+			}
+			if col.OnError, err = jsonTableResponse(def.JtPath.ErrorOnResponse); err != nil {
+				return nil, nil, err
+			}
+			columns = append(columns, col)
+			fields = append(fields, &querypb.Field{Name: col.Name, Type: col.Type, Charset: uint32(col.Collation)})
+		case def.JtNestedPath != nil:
+			path, err := jsonTablePath(def.JtNestedPath.Path)
+			if err != nil {
+				return nil, nil, err
+			}
+			nested, nestedFields, err := jsonTableColumns(def.JtNestedPath.Columns)
+			if err != nil {
+				return nil, nil, err
+			}
+			columns = append(columns, &evalengine.JSONTableColumn{Path: path, Nested: nested})
+			fields = append(fields, nestedFields...)
+		}
+	}
+	return columns, fields, nil
+}
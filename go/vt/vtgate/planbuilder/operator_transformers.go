@@ -65,6 +65,8 @@ func transformToLogicalPlan(ctx *plancontext.PlanningContext, op ops.Operator) (
 		return transformAggregator(ctx, op)
 	case *operators.Distinct:
 		return transformDistinct(ctx, op)
+	case *operators.Window:
+		return transformWindow(ctx, op)
 	case *operators.FkCascade:
 		return transformFkCascade(ctx, op)
 	}
@@ -158,6 +160,34 @@ func transformDistinct(ctx *plancontext.PlanningContext, op *operators.Distinct)
 	return newDistinct(src, op.Columns, op.Truncate), nil
 }
 
+func transformWindow(ctx *plancontext.PlanningContext, op *operators.Window) (logicalPlan, error) {
+	src, err := transformToLogicalPlan(ctx, op.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	funcs := slice.Map(op.Funcs, func(from *operators.WindowFunc) *engine.WindowFunc {
+		return &engine.WindowFunc{
+			Opcode:     from.OpCode,
+			Col:        from.ColOffset,
+			ArgCol:     from.ArgOffset,
+			DefaultCol: from.DefaultOffset,
+			N:          from.N,
+			Alias:      op.Columns[from.ColOffset].ColumnName(),
+		}
+	})
+
+	return &window{
+		logicalPlanCommon: newBuilderCommon(src),
+		eWindow: &engine.Window{
+			PartitionBy: op.PartitionCols,
+			OrderBy:     op.OrderCols,
+			Funcs:       funcs,
+			Cols:        op.Offsets,
+		},
+	}, nil
+}
+
 func transformOrdering(ctx *plancontext.PlanningContext, op *operators.Ordering) (logicalPlan, error) {
 	plan, err := transformToLogicalPlan(ctx, op.Source)
 	if err != nil {
@@ -442,6 +472,7 @@ func transformInsertPlan(ctx *plancontext.PlanningContext, op *operators.Route,
 	eins := &engine.Insert{
 		Opcode:            mapToInsertOpCode(op.Routing.OpCode(), ins.Input != nil),
 		Keyspace:          op.Routing.Keyspace(),
+		Pinned:            ins.VTable.Pinned,
 		TableName:         ins.VTable.Name.String(),
 		Ignore:            ins.Ignore,
 		ForceNonStreaming: ins.ForceNonStreaming,
@@ -450,6 +481,10 @@ func transformInsertPlan(ctx *plancontext.PlanningContext, op *operators.Route,
 		VindexValues:      ins.VindexValues,
 		VindexValueOffset: ins.VindexValueOffset,
 	}
+	if eins.Pinned != nil {
+		// The rows of a pinned table are all inserted on the shard of its keyspace id.
+		eins.Opcode = engine.InsertUnsharded
+	}
 	i = &insert{eInsert: eins}
 
 	// we would need to generate the query on the fly. The only exception here is
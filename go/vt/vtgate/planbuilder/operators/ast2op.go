@@ -116,6 +116,8 @@ func createOpFromStmt(ctx *plancontext.PlanningContext, stmt sqlparser.Statement
 	if err != nil {
 		return nil, err
 	}
+	// the hints of the outer query also apply to the statement
+	newCtx.PlanHints = append(newCtx.PlanHints, ctx.PlanHints...)
 	ctx = newCtx
 
 	return PlanQuery(ctx, stmt)
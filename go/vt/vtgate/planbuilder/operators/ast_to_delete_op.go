@@ -79,7 +79,8 @@ func createDeleteOperator(ctx *plancontext.PlanningContext, deleteStmt *sqlparse
 		Routing: routing,
 	}
 
-	if !vindexTable.Keyspace.Sharded {
+	if !vindexTable.Keyspace.Sharded || vindexTable.Pinned != nil {
+		// The rows of a pinned table all live on the shard of its keyspace id.
 		return route, nil
 	}
 
@@ -103,7 +103,7 @@ func (h *Horizon) AddPredicate(ctx *plancontext.PlanningContext, expr sqlparser.
 	}
 
 	newExpr := semantics.RewriteDerivedTableExpression(expr, tableInfo)
-	if sqlparser.ContainsAggregation(newExpr) {
+	if sqlparser.ContainsAggregation(newExpr) || ContainsWindowFunction(newExpr) {
 		return &Filter{Source: h, Predicates: []sqlparser.Expr{expr}}, nil
 	}
 	h.Source, err = h.Source.AddPredicate(ctx, newExpr)
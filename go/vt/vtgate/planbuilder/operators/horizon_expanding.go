@@ -124,8 +124,23 @@ func createProjectionFromSelect(ctx *plancontext.PlanningContext, horizon *Horiz
 		return nil, err
 	}
 
+	needsWindows, err := windowsNeedVTGate(ctx, horizon)
+	if err != nil {
+		return nil, err
+	}
+	if needsWindows && qp.NeedsDistinct() {
+		return nil, vterrors.VT12001("DISTINCT together with window functions in a cross-shard query")
+	}
+
 	if !qp.NeedsAggregation() {
-		projX, err := createProjectionWithoutAggr(qp, horizon.src())
+		src := horizon.src()
+		if needsWindows {
+			src, err = createWindows(ctx, horizon, src)
+			if err != nil {
+				return nil, err
+			}
+		}
+		projX, err := createProjectionWithoutAggr(qp, src)
 		if err != nil {
 			return nil, err
 		}
@@ -136,6 +151,10 @@ func createProjectionFromSelect(ctx *plancontext.PlanningContext, horizon *Horiz
 		return out, nil
 	}
 
+	if needsWindows {
+		return nil, vterrors.VT12001("window functions together with aggregation in a cross-shard query")
+	}
+
 	aggregations, complexAggr, err := qp.AggregationExpressions(ctx, true)
 	if err != nil {
 		return nil, err
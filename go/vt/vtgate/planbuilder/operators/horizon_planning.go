@@ -169,9 +169,15 @@ func pushOrExpandHorizon(ctx *plancontext.PlanningContext, in *Horizon) (ops.Ope
 	needsOrdering := len(qp.OrderExprs) > 0
 	hasHaving := isSel && sel.Having != nil
 
+	needsWindows, err := windowsNeedVTGate(ctx, in)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	canPushDown := isRoute &&
 		!hasHaving &&
 		!needsOrdering &&
+		!needsWindows &&
 		!qp.NeedsAggregation() &&
 		!in.selectStatement().IsDistinct() &&
 		in.selectStatement().GetLimit() == nil
@@ -423,6 +429,9 @@ func setUpperLimit(in *Limit) (ops.Operator, *rewrite.ApplyResult, error) {
 		case *Join, *ApplyJoin:
 			// we can't push limits down on either side
 			return rewrite.SkipChildren
+		case *Window:
+			// the window functions need all the rows of their partitions
+			return rewrite.SkipChildren
 		case *Route:
 			newSrc := &Limit{
 				Source: op.Source,
@@ -556,6 +565,10 @@ func canPushLeft(ctx *plancontext.PlanningContext, aj *ApplyJoin, order []ops.Or
 func tryPushingDownFilter(ctx *plancontext.PlanningContext, in *Filter) (ops.Operator, *rewrite.ApplyResult, error) {
 	switch src := in.Source.(type) {
 	case *Projection:
+		if _, isWindow := src.Source.(*Window); isWindow {
+			// the predicates can't be pushed under the window functions, so we keep them on top
+			return in, rewrite.SameTree, nil
+		}
 		return pushFilterUnderProjection(ctx, in, src)
 	case *Route:
 		return rewrite.Swap(in, src, "push filter into Route")
@@ -116,6 +116,11 @@ func addColumnsToInput(ctx *plancontext.PlanningContext, root ops.Operator) (ops
 			// not much we can do here
 			return in, rewrite.SameTree, nil
 		}
+		if dt, isProj := proj.(*Projection); isProj && dt.isDerived() {
+			// the filter uses the columns of the derived table, so they are
+			// resolved against its expressions when we plan offsets
+			return in, rewrite.SameTree, nil
+		}
 		addedColumns := false
 		found := func(expr sqlparser.Expr, i int) {}
 		notFound := func(e sqlparser.Expr) error {
@@ -157,9 +157,7 @@ type fetchExpr struct {
 func (p *Projection) AddColumns(ctx *plancontext.PlanningContext, reuse bool, addToGroupBy []bool, exprs []*sqlparser.AliasedExpr) ([]int, error) {
 	offsets := make([]int, len(exprs))
 	var fetch []fetchExpr
-	startOffset := len(p.Columns)
 	for i, ae := range exprs {
-		colIdx := i + startOffset
 		expr := ae.Expr
 
 		if p.TableID != nil {
@@ -201,7 +199,7 @@ func (p *Projection) AddColumns(ctx *plancontext.PlanningContext, reuse bool, ad
 			})
 		}
 
-		fetch[fIdx].colIdx = append(fetch[fIdx].colIdx, colIdx)
+		fetch[fIdx].colIdx = append(fetch[fIdx].colIdx, offsets[i])
 		fetch[fIdx].groupBy = fetch[fIdx].groupBy || addToGroupBy[i]
 	}
 
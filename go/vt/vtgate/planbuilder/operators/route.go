@@ -419,7 +419,7 @@ func createRouteFromVSchemaTable(
 	}
 
 	// We create the appropiate Routing struct here, depending on the type of table we are dealing with.
-	routing := createRoutingForVTable(vschemaTable, solves)
+	routing := createRoutingForVTable(ctx, vschemaTable, solves)
 	for _, predicate := range queryTable.Predicates {
 		var err error
 		routing, err = UpdateRoutingLogic(ctx, predicate, routing)
@@ -453,7 +453,7 @@ func createRouteFromVSchemaTable(
 	return plan, nil
 }
 
-func createRoutingForVTable(vschemaTable *vindexes.Table, id semantics.TableSet) Routing {
+func createRoutingForVTable(ctx *plancontext.PlanningContext, vschemaTable *vindexes.Table, id semantics.TableSet) Routing {
 	switch {
 	case vschemaTable.Type == vindexes.TypeSequence:
 		return &SequenceRouting{keyspace: vschemaTable.Keyspace}
@@ -462,7 +462,7 @@ func createRoutingForVTable(vschemaTable *vindexes.Table, id semantics.TableSet)
 	case vschemaTable.Type == vindexes.TypeReference || !vschemaTable.Keyspace.Sharded:
 		return &AnyShardRouting{keyspace: vschemaTable.Keyspace}
 	default:
-		return newShardedRouting(vschemaTable, id)
+		return newShardedRouting(ctx, vschemaTable, id)
 	}
 }
 
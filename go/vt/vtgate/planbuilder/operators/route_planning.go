@@ -149,6 +149,9 @@ func buildVindexTableForDML(
 	if err != nil {
 		return nil, nil, err
 	}
+	if dest == nil && vindexTable.Pinned != nil {
+		return vindexTable, newShardedRouting(ctx, vindexTable, table.ID), nil
+	}
 	if dest == nil {
 		routing := &ShardedRouting{
 			keyspace:    vindexTable.Keyspace,
@@ -193,7 +196,7 @@ func getUpdateVindexInformation(
 	tableID semantics.TableSet,
 	predicates []sqlparser.Expr,
 ) ([]*VindexPlusPredicates, map[string]*engine.VindexValues, string, error) {
-	if !vindexTable.Keyspace.Sharded {
+	if !vindexTable.Keyspace.Sharded || vindexTable.Pinned != nil {
 		return nil, nil, "", nil
 	}
 
@@ -404,12 +407,14 @@ func requiresSwitchingSides(ctx *plancontext.PlanningContext, op ops.Operator) b
 }
 
 func mergeOrJoin(ctx *plancontext.PlanningContext, lhs, rhs ops.Operator, joinPredicates []sqlparser.Expr, inner bool) (ops.Operator, *rewrite.ApplyResult, error) {
-	newPlan, err := mergeJoinInputs(ctx, lhs, rhs, joinPredicates, newJoinMerge(ctx, joinPredicates, inner))
-	if err != nil {
-		return nil, nil, err
-	}
-	if newPlan != nil {
-		return newPlan, rewrite.NewTree("merge routes into single operator", newPlan), nil
+	if !ctx.PlanHints.RewriteDisabled(vindexes.RewriteMergeJoins) {
+		newPlan, err := mergeJoinInputs(ctx, lhs, rhs, joinPredicates, newJoinMerge(ctx, joinPredicates, inner))
+		if err != nil {
+			return nil, nil, err
+		}
+		if newPlan != nil {
+			return newPlan, rewrite.NewTree("merge routes into single operator", newPlan), nil
+		}
 	}
 
 	if len(joinPredicates) > 0 && requiresSwitchingSides(ctx, rhs) {
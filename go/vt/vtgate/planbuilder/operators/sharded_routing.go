@@ -53,7 +53,7 @@ type ShardedRouting struct {
 
 var _ Routing = (*ShardedRouting)(nil)
 
-func newShardedRouting(vtable *vindexes.Table, id semantics.TableSet) Routing {
+func newShardedRouting(ctx *plancontext.PlanningContext, vtable *vindexes.Table, id semantics.TableSet) Routing {
 	routing := &ShardedRouting{
 		RouteOpCode: engine.Scatter,
 		keyspace:    vtable.Keyspace,
@@ -78,11 +78,20 @@ func newShardedRouting(vtable *vindexes.Table, id semantics.TableSet) Routing {
 		}
 
 	}
+	hint := ctx.PlanHints.ForTable(vtable)
+	if hint != nil && hint.ForceScatter {
+		// the plan hint forces a scatter, so no vindex can be used to route the query
+		return routing
+	}
 	for _, columnVindex := range vtable.ColumnVindexes {
 		// ignore any backfilling vindexes from vindex selection.
 		if columnVindex.IsBackfilling() {
 			continue
 		}
+		// the plan hint only allows its vindex to be used to route the query
+		if hint != nil && columnVindex.Name != hint.Vindex {
+			continue
+		}
 		routing.VindexPreds = append(routing.VindexPreds, &VindexPlusPredicates{ColVindex: columnVindex, TableID: id})
 	}
 	return routing
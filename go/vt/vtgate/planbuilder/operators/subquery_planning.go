@@ -109,6 +109,14 @@ func isMergeable(ctx *plancontext.PlanningContext, query sqlparser.SelectStateme
 		return false
 	}
 
+	if len(windowFunctions(sel)) > 0 {
+		// the window functions can only be computed inside the shards if no partition spans more than one shard
+		local, err := ShardLocalWindows(ctx, sel)
+		if err != nil || !local {
+			return false
+		}
+	}
+
 	if len(sel.GroupBy) > 0 {
 		// iff we are grouping, we need to check that we can perform the grouping inside a single shard, and we check that
 		// by checking that one of the grouping expressions used is a unique single column vindex.
@@ -0,0 +1,547 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"vitess.io/vitess/go/slice"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/operators/ops"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+)
+
+type (
+	// Window computes window functions on the vtgate. It is used when the rows of a
+	// partition of the window can come from more than one shard, so MySQL can't compute them.
+	// All the functions of a Window share the same window, and the Source must be sorted
+	// by the partitioning and then the ordering of that window.
+	Window struct {
+		Source ops.Operator
+
+		PartitionBy []sqlparser.Expr
+		OrderBy     []ops.OrderBy
+		Funcs       []*WindowFunc
+
+		// Columns are the columns produced by this operator. Offsets maps each of them to a
+		// column of the Source, or is -1 for the columns computed by one of the Funcs
+		Columns []*sqlparser.AliasedExpr
+		Offsets []int
+
+		// These are only filled in during offset planning
+		PartitionCols []engine.CheckCol
+		OrderCols     []engine.CheckCol
+	}
+
+	// WindowFunc is a single window function computed by the Window operator
+	WindowFunc struct {
+		Func   sqlparser.Expr
+		OpCode opcode.WindowOpcode
+
+		Arg     sqlparser.Expr
+		Default sqlparser.Expr
+		N       int64
+
+		// ColOffset is the column of the Window that holds the result of the function
+		ColOffset int
+
+		// These offsets point to columns of the source, and are filled in during offset planning
+		ArgOffset     int
+		DefaultOffset int
+	}
+)
+
+func (w *Window) Clone(inputs []ops.Operator) ops.Operator {
+	return &Window{
+		Source:        inputs[0],
+		PartitionBy:   slices.Clone(w.PartitionBy),
+		OrderBy:       slices.Clone(w.OrderBy),
+		Funcs:         slice.Map(w.Funcs, func(from *WindowFunc) *WindowFunc { clone := *from; return &clone }),
+		Columns:       slices.Clone(w.Columns),
+		Offsets:       slices.Clone(w.Offsets),
+		PartitionCols: slices.Clone(w.PartitionCols),
+		OrderCols:     slices.Clone(w.OrderCols),
+	}
+}
+
+func (w *Window) Inputs() []ops.Operator {
+	return []ops.Operator{w.Source}
+}
+
+func (w *Window) SetInputs(operators []ops.Operator) {
+	w.Source = operators[0]
+}
+
+func (w *Window) AddPredicate(_ *plancontext.PlanningContext, expr sqlparser.Expr) (ops.Operator, error) {
+	// filtering the input would change the rows the window functions are computed over,
+	// so the predicate has to stay on top of this operator
+	return newFilter(w, expr), nil
+}
+
+func (w *Window) AddColumns(ctx *plancontext.PlanningContext, reuse bool, addToGroupBy []bool, exprs []*sqlparser.AliasedExpr) ([]int, error) {
+	offsets := make([]int, len(exprs))
+	for i, ae := range exprs {
+		if reuse {
+			offset, err := w.FindCol(ctx, ae.Expr, false)
+			if err != nil {
+				return nil, err
+			}
+			if offset >= 0 {
+				offsets[i] = offset
+				continue
+			}
+		}
+
+		// all columns that are not computed here are passed through from the source
+		srcOffsets, err := w.Source.AddColumns(ctx, reuse, addToGroupBy[i:i+1], []*sqlparser.AliasedExpr{ae})
+		if err != nil {
+			return nil, err
+		}
+		offsets[i] = len(w.Columns)
+		w.Columns = append(w.Columns, ae)
+		w.Offsets = append(w.Offsets, srcOffsets[0])
+	}
+	return offsets, nil
+}
+
+func (w *Window) FindCol(ctx *plancontext.PlanningContext, expr sqlparser.Expr, _ bool) (int, error) {
+	if offset, found := canReuseColumn(ctx, w.Columns, expr, extractExpr); found {
+		return offset, nil
+	}
+	return -1, nil
+}
+
+func (w *Window) GetColumns(*plancontext.PlanningContext) ([]*sqlparser.AliasedExpr, error) {
+	return w.Columns, nil
+}
+
+func (w *Window) GetSelectExprs(ctx *plancontext.PlanningContext) (sqlparser.SelectExprs, error) {
+	return transformColumnsToSelectExprs(ctx, w)
+}
+
+func (w *Window) GetOrdering() ([]ops.OrderBy, error) {
+	return w.Source.GetOrdering()
+}
+
+func (w *Window) planOffsets(ctx *plancontext.PlanningContext) error {
+	for _, expr := range w.PartitionBy {
+		col, err := w.checkCol(ctx, expr)
+		if err != nil {
+			return err
+		}
+		w.PartitionCols = append(w.PartitionCols, col)
+	}
+	for _, order := range w.OrderBy {
+		col, err := w.checkCol(ctx, order.SimplifiedExpr)
+		if err != nil {
+			return err
+		}
+		w.OrderCols = append(w.OrderCols, col)
+	}
+
+	for _, f := range w.Funcs {
+		var err error
+		if f.ArgOffset, err = w.sourceOffset(ctx, f.Arg); err != nil {
+			return err
+		}
+		if f.DefaultOffset, err = w.sourceOffset(ctx, f.Default); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkCol returns the column of the source used to compare rows on the given expression
+func (w *Window) checkCol(ctx *plancontext.PlanningContext, expr sqlparser.Expr) (engine.CheckCol, error) {
+	offset, err := w.sourceOffset(ctx, expr)
+	if err != nil {
+		return engine.CheckCol{}, err
+	}
+	typ, coll, _ := ctx.SemTable.TypeForExpr(expr)
+	col := engine.CheckCol{
+		Col:       offset,
+		Type:      typ,
+		Collation: coll,
+	}
+	if ctx.SemTable.NeedsWeightString(expr) {
+		wsOffset, err := w.sourceOffset(ctx, weightStringFor(expr))
+		if err != nil {
+			return engine.CheckCol{}, err
+		}
+		col.WsCol = &wsOffset
+	}
+	return col, nil
+}
+
+// sourceOffset returns the offset of the expression in the source, or -1 if there is no expression
+func (w *Window) sourceOffset(ctx *plancontext.PlanningContext, expr sqlparser.Expr) (int, error) {
+	if expr == nil {
+		return -1, nil
+	}
+	offsets, err := w.Source.AddColumns(ctx, true, []bool{false}, []*sqlparser.AliasedExpr{aeWrap(expr)})
+	if err != nil {
+		return 0, err
+	}
+	return offsets[0], nil
+}
+
+func (w *Window) ShortDescription() string {
+	funcs := slice.Map(w.Funcs, func(from *WindowFunc) string {
+		return sqlparser.String(from.Func)
+	})
+	return strings.Join(funcs, ", ")
+}
+
+// windowFunctions returns the window functions used in the SELECT expressions and the ORDER BY of the query
+func windowFunctions(sel *sqlparser.Select) []sqlparser.Expr {
+	var funcs []sqlparser.Expr
+	visit := func(node sqlparser.SQLNode) (bool, error) {
+		if overClauseOf(node) == nil {
+			return true, nil
+		}
+		funcs = append(funcs, node.(sqlparser.Expr))
+		return false, nil
+	}
+	_ = sqlparser.Walk(visit, sel.SelectExprs)
+	_ = sqlparser.Walk(visit, sel.OrderBy)
+	return funcs
+}
+
+// ContainsWindowFunction returns true if the node uses window functions
+func ContainsWindowFunction(e sqlparser.SQLNode) bool {
+	hasWindow := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (kontinue bool, err error) {
+		if overClauseOf(node) != nil {
+			hasWindow = true
+			return false, io.EOF
+		}
+		return true, nil
+	}, e)
+	return hasWindow
+}
+
+// overClauseOf returns the OVER clause of a window function, or nil if the node is not a window function
+func overClauseOf(node sqlparser.SQLNode) *sqlparser.OverClause {
+	switch node := node.(type) {
+	case *sqlparser.ArgumentLessWindowExpr:
+		return node.OverClause
+	case *sqlparser.FirstOrLastValueExpr:
+		return node.OverClause
+	case *sqlparser.NtileExpr:
+		return node.OverClause
+	case *sqlparser.NTHValueExpr:
+		return node.OverClause
+	case *sqlparser.LagLeadExpr:
+		return node.OverClause
+	default:
+		return nil
+	}
+}
+
+// ShardLocalWindows returns true if every window of the query is partitioned by a column that is
+// either the primary vindex or a unique vindex of its table. All the rows of a partition of such a
+// window live on the same shard, so MySQL can compute the functions even when the query hits many shards.
+func ShardLocalWindows(ctx *plancontext.PlanningContext, sel *sqlparser.Select) (bool, error) {
+	for _, f := range windowFunctions(sel) {
+		spec, err := resolveWindow(sel, overClauseOf(f))
+		if err != nil {
+			return false, err
+		}
+		if !partitionedByVindex(ctx, spec) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func partitionedByVindex(ctx *plancontext.PlanningContext, spec *sqlparser.WindowSpecification) bool {
+	for _, expr := range spec.PartitionClause {
+		col, ok := expr.(*sqlparser.ColName)
+		if !ok {
+			continue
+		}
+		ti, err := ctx.SemTable.TableInfoForExpr(col)
+		if err != nil {
+			continue
+		}
+		vtbl := ti.GetVindexTable()
+		if vtbl == nil {
+			continue
+		}
+		for idx, cv := range vtbl.ColumnVindexes {
+			if len(cv.Columns) == 1 && cv.Columns[0].Equal(col.Name) && (idx == 0 || cv.IsUnique()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// windowsNeedVTGate returns true if the query uses window functions that have to be computed on the vtgate
+func windowsNeedVTGate(ctx *plancontext.PlanningContext, horizon *Horizon) (bool, error) {
+	sel, isSel := horizon.selectStatement().(*sqlparser.Select)
+	if !isSel || len(windowFunctions(sel)) == 0 {
+		return false, nil
+	}
+	rb, isRoute := horizon.src().(*Route)
+	if !isRoute {
+		return true, nil
+	}
+	if rb.IsSingleShard() {
+		return false, nil
+	}
+	local, err := ShardLocalWindows(ctx, sel)
+	return !local, err
+}
+
+// resolveWindow returns the window specification used by an OVER clause, following the references to named windows
+func resolveWindow(sel *sqlparser.Select, over *sqlparser.OverClause) (*sqlparser.WindowSpecification, error) {
+	if !over.WindowName.IsEmpty() {
+		return lookupWindow(sel, over.WindowName, 0)
+	}
+	return inheritWindow(sel, over.WindowSpec, 0)
+}
+
+func lookupWindow(sel *sqlparser.Select, name sqlparser.IdentifierCI, depth int) (*sqlparser.WindowSpecification, error) {
+	for _, named := range sel.Windows {
+		for _, def := range named.Windows {
+			if def.Name.Equal(name) {
+				return inheritWindow(sel, def.WindowSpec, depth+1)
+			}
+		}
+	}
+	return nil, vterrors.VT03012(fmt.Sprintf("window name '%s' is not defined", name.String()))
+}
+
+// inheritWindow fills in the parts of a window specification that it takes from the window it is based on
+func inheritWindow(sel *sqlparser.Select, spec *sqlparser.WindowSpecification, depth int) (*sqlparser.WindowSpecification, error) {
+	if spec.Name.IsEmpty() {
+		return spec, nil
+	}
+	if depth > len(sel.Windows) {
+		return nil, vterrors.VT03012(fmt.Sprintf("window '%s' has a circular reference", spec.Name.String()))
+	}
+	base, err := lookupWindow(sel, spec.Name, depth)
+	if err != nil {
+		return nil, err
+	}
+	result := &sqlparser.WindowSpecification{
+		PartitionClause: base.PartitionClause,
+		OrderClause:     base.OrderClause,
+		FrameClause:     base.FrameClause,
+	}
+	if len(spec.OrderClause) > 0 {
+		result.OrderClause = spec.OrderClause
+	}
+	if spec.FrameClause != nil {
+		result.FrameClause = spec.FrameClause
+	}
+	return result, nil
+}
+
+// createWindows builds the Window operators that compute the window functions of the query on the vtgate.
+// Functions that share a window are computed by the same operator, and every Window gets an Ordering
+// below it that sorts its input by the partitioning and ordering of the window.
+func createWindows(ctx *plancontext.PlanningContext, horizon *Horizon, src ops.Operator) (ops.Operator, error) {
+	sel := horizon.selectStatement().(*sqlparser.Select)
+	if sel.Having != nil {
+		return nil, vterrors.VT12001("HAVING together with window functions in a cross-shard query")
+	}
+
+	aliases := map[sqlparser.Expr]*sqlparser.AliasedExpr{}
+	for _, se := range sel.SelectExprs {
+		if ae, ok := se.(*sqlparser.AliasedExpr); ok {
+			aliases[ae.Expr] = ae
+		}
+	}
+
+	var windows []*Window
+outer:
+	for _, expr := range windowFunctions(sel) {
+		spec, err := resolveWindow(sel, overClauseOf(expr))
+		if err != nil {
+			return nil, err
+		}
+		if spec.FrameClause != nil {
+			return nil, vterrors.VT12001(fmt.Sprintf("window frames in a cross-shard query: %s", sqlparser.String(expr)))
+		}
+
+		var window *Window
+		for _, w := range windows {
+			if _, found := canReuseColumn(ctx, w.Columns, expr, extractExpr); found {
+				// the same function is used more than once in the query
+				continue outer
+			}
+			if w.sameWindow(ctx, spec) {
+				window = w
+				break
+			}
+		}
+		if window == nil {
+			window = newWindow(spec)
+			windows = append(windows, window)
+		}
+
+		f, err := newWindowFunc(expr)
+		if err != nil {
+			return nil, err
+		}
+		ae, found := aliases[expr]
+		if !found {
+			ae = aeWrap(expr)
+		}
+		f.ColOffset = len(window.Columns)
+		window.Funcs = append(window.Funcs, f)
+		window.Columns = append(window.Columns, ae)
+		window.Offsets = append(window.Offsets, -1)
+	}
+
+	// every window reads the output of the next one, and passes on its columns,
+	// so the functions of all the windows are available above the first Window
+	for i := len(windows) - 1; i >= 0; i-- {
+		w := windows[i]
+		if i < len(windows)-1 {
+			below := windows[i+1]
+			for offset, col := range below.Columns {
+				w.Columns = append(w.Columns, col)
+				w.Offsets = append(w.Offsets, offset)
+			}
+		}
+		order := slice.Map(w.PartitionBy, func(from sqlparser.Expr) ops.OrderBy {
+			return ops.OrderBy{
+				Inner:          &sqlparser.Order{Expr: from, Direction: sqlparser.AscOrder},
+				SimplifiedExpr: from,
+			}
+		})
+		order = append(order, w.OrderBy...)
+		w.Source = src
+		if len(order) > 0 {
+			w.Source = &Ordering{
+				Source: src,
+				Order:  order,
+			}
+		}
+		src = w
+	}
+	return src, nil
+}
+
+func newWindow(spec *sqlparser.WindowSpecification) *Window {
+	return &Window{
+		PartitionBy: spec.PartitionClause,
+		OrderBy: slice.Map(spec.OrderClause, func(from *sqlparser.Order) ops.OrderBy {
+			return ops.OrderBy{
+				Inner:          from,
+				SimplifiedExpr: from.Expr,
+			}
+		}),
+	}
+}
+
+// sameWindow returns true if the rows of the window specification are partitioned and ordered like the rows of this Window
+func (w *Window) sameWindow(ctx *plancontext.PlanningContext, spec *sqlparser.WindowSpecification) bool {
+	if len(w.PartitionBy) != len(spec.PartitionClause) || len(w.OrderBy) != len(spec.OrderClause) {
+		return false
+	}
+	for i, expr := range spec.PartitionClause {
+		if !ctx.SemTable.EqualsExprWithDeps(w.PartitionBy[i], expr) {
+			return false
+		}
+	}
+	for i, order := range spec.OrderClause {
+		if order.Direction != w.OrderBy[i].Inner.Direction ||
+			!ctx.SemTable.EqualsExprWithDeps(w.OrderBy[i].SimplifiedExpr, order.Expr) {
+			return false
+		}
+	}
+	return true
+}
+
+func newWindowFunc(expr sqlparser.Expr) (*WindowFunc, error) {
+	f := &WindowFunc{
+		Func:          expr,
+		N:             1,
+		ArgOffset:     -1,
+		DefaultOffset: -1,
+	}
+
+	var n sqlparser.Expr
+	var nullTreatment *sqlparser.NullTreatmentClause
+	switch expr := expr.(type) {
+	case *sqlparser.ArgumentLessWindowExpr:
+		switch expr.Type {
+		case sqlparser.CumeDistExprType:
+			f.OpCode = opcode.WindowCumeDist
+		case sqlparser.DenseRankExprType:
+			f.OpCode = opcode.WindowDenseRank
+		case sqlparser.PercentRankExprType:
+			f.OpCode = opcode.WindowPercentRank
+		case sqlparser.RankExprType:
+			f.OpCode = opcode.WindowRank
+		case sqlparser.RowNumberExprType:
+			f.OpCode = opcode.WindowRowNumber
+		}
+	case *sqlparser.NtileExpr:
+		f.OpCode = opcode.WindowNtile
+		n = expr.N
+	case *sqlparser.LagLeadExpr:
+		f.OpCode = opcode.WindowLag
+		if expr.Type == sqlparser.LeadExprType {
+			f.OpCode = opcode.WindowLead
+		}
+		f.Arg, f.Default = expr.Expr, expr.Default
+		n, nullTreatment = expr.N, expr.NullTreatmentClause
+	case *sqlparser.FirstOrLastValueExpr:
+		f.OpCode = opcode.WindowFirstValue
+		if expr.Type == sqlparser.LastValueExprType {
+			f.OpCode = opcode.WindowLastValue
+		}
+		f.Arg, nullTreatment = expr.Expr, expr.NullTreatmentClause
+	case *sqlparser.NTHValueExpr:
+		if expr.FromFirstLastClause != nil && expr.FromFirstLastClause.Type == sqlparser.FromLastType {
+			return nil, vterrors.VT12001("FROM LAST in window functions")
+		}
+		f.OpCode = opcode.WindowNthValue
+		f.Arg, n, nullTreatment = expr.Expr, expr.N, expr.NullTreatmentClause
+	}
+	if f.OpCode == opcode.WindowUnassigned {
+		return nil, vterrors.VT13001(fmt.Sprintf("unexpected window function: %s", sqlparser.String(expr)))
+	}
+	if nullTreatment != nil && nullTreatment.Type == sqlparser.IgnoreNullsType {
+		return nil, vterrors.VT12001("IGNORE NULLS in window functions")
+	}
+
+	if n != nil {
+		lit, ok := n.(*sqlparser.Literal)
+		if !ok || lit.Type != sqlparser.IntVal {
+			return nil, vterrors.VT12001(fmt.Sprintf("non-literal argument of a window function in a cross-shard query: %s", sqlparser.String(expr)))
+		}
+		val, err := strconv.ParseInt(lit.Val, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		f.N = val
+	}
+	return f, nil
+}
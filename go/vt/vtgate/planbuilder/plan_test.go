@@ -37,6 +37,7 @@ import (
 	"vitess.io/vitess/go/test/utils"
 	"vitess.io/vitess/go/vt/key"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
 	"vitess.io/vitess/go/vt/servenv"
 	"vitess.io/vitess/go/vt/sidecardb"
 	"vitess.io/vitess/go/vt/sqlparser"
@@ -96,6 +97,9 @@ func TestPlan(t *testing.T) {
 	testFile(t, "reference_cases.json", testOutputTempDir, vschemaWrapper, false)
 	testFile(t, "vexplain_cases.json", testOutputTempDir, vschemaWrapper, false)
 	testFile(t, "misc_cases.json", testOutputTempDir, vschemaWrapper, false)
+	testFile(t, "window_cases.json", testOutputTempDir, vschemaWrapper, false)
+	testFile(t, "cte_cases.json", testOutputTempDir, vschemaWrapper, false)
+	testFile(t, "json_table_cases.json", testOutputTempDir, vschemaWrapper, false)
 }
 
 // TestForeignKeyPlanning tests the planning of foreign keys in a managed mode by Vitess.
@@ -111,6 +115,35 @@ func TestForeignKeyPlanning(t *testing.T) {
 	testFile(t, "foreignkey_cases.json", testOutputTempDir, vschemaWrapper, false)
 }
 
+// TestPlanHints tests the planning of the queries with the plan hints of the VSchema.
+func TestPlanHints(t *testing.T) {
+	formal, err := vindexes.LoadFormal(locateFile("vschemas/schema.json"))
+	require.NoError(t, err)
+	formal.Keyspaces["user"].PlanHints = []*vschemapb.PlanHint{{
+		Name:         "scatter_music",
+		Table:        "music",
+		ForceScatter: true,
+	}, {
+		Name:   "user_by_id",
+		Table:  "user",
+		Query:  "select id from user where name = 'x'",
+		Vindex: "user_index",
+	}, {
+		Name:            "user_extra_no_merge",
+		Query:           "select user.id, user_extra.col from user join user_extra on user.id = user_extra.user_id where user.id = 1",
+		DisableRewrites: []string{vindexes.RewriteMergeJoins},
+	}}
+	vschema := vindexes.BuildVSchema(formal)
+	require.NoError(t, vschema.Keyspaces["user"].Error)
+	vschemaWrapper := &vschemawrapper.VSchemaWrapper{
+		V: vschema,
+	}
+
+	testOutputTempDir := makeTestOutput(t)
+
+	testFile(t, "plan_hints_cases.json", testOutputTempDir, vschemaWrapper, false)
+}
+
 func setFks(t *testing.T, vschema *vindexes.VSchema) {
 	if vschema.Keyspaces["sharded_fk_allow"] != nil {
 		// FK from multicol_tbl2 referencing multicol_tbl1 that is shard scoped.
@@ -20,6 +20,7 @@ import (
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vtgate/semantics"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
 )
 
 type PlanningContext struct {
@@ -43,6 +44,9 @@ type PlanningContext struct {
 	// DelegateAggregation tells us when we are allowed to split an aggregation across vtgate and mysql
 	// We aggregate within a shard, and then at the vtgate level we aggregate the incoming shard aggregates
 	DelegateAggregation bool
+
+	// PlanHints are the plan hints of the VSchema applying to the query
+	PlanHints vindexes.PlanHints
 }
 
 func CreatePlanningContext(stmt sqlparser.Statement, reservedVars *sqlparser.ReservedVars, vschema VSchema, version querypb.ExecuteOptions_PlannerVersion) (*PlanningContext, error) {
@@ -51,10 +55,20 @@ func CreatePlanningContext(stmt sqlparser.Statement, reservedVars *sqlparser.Res
 		ksName = ks.Name
 	}
 
+	// the hints are matched on the fingerprint of the statement before the semantic analysis rewrites it
+	planHints := vschema.GetVSchema().PlanHints(stmt)
+
 	semTable, err := semantics.Analyze(stmt, ksName, vschema)
 	if err != nil {
 		return nil, err
 	}
+	if len(planHints) > 0 {
+		tables := make([]*vindexes.Table, 0, len(semTable.Tables))
+		for _, ti := range semTable.Tables {
+			tables = append(tables, ti.GetVindexTable())
+		}
+		planHints = planHints.ForTables(tables)
+	}
 
 	// record any warning as planner warning.
 	vschema.PlannerWarning(semTable.Warning)
@@ -67,6 +81,7 @@ func CreatePlanningContext(stmt sqlparser.Statement, reservedVars *sqlparser.Res
 		SkipPredicates:    map[sqlparser.Expr]any{},
 		PlannerVersion:    version,
 		ReservedArguments: map[sqlparser.Expr]string{},
+		PlanHints:         planHints,
 	}, nil
 }
 
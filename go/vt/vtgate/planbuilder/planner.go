@@ -49,16 +49,20 @@ func pushCommentDirectivesOnPlan(plan logicalPlan, stmt sqlparser.Statement) log
 	cmt, ok := stmt.(sqlparser.Commented)
 	if ok {
 		directives = cmt.GetParsedComments().Directives()
-		scatterAsWarns := directives.IsSet(sqlparser.DirectiveScatterErrorsAsWarnings)
+		scatterAsWarns := directives.IsSet(sqlparser.DirectiveScatterErrorsAsWarnings) || directives.IsSet(sqlparser.DirectiveAllowPartialScatter)
 		timeout := queryTimeout(directives)
+		scatterConcurrency := intDirective(directives, sqlparser.DirectiveScatterConcurrency)
+		shardTimeout := intDirective(directives, sqlparser.DirectiveShardTimeout)
 		multiShardAutoCommit := directives.IsSet(sqlparser.DirectiveMultiShardAutocommit)
 
-		if scatterAsWarns || timeout > 0 || multiShardAutoCommit {
+		if scatterAsWarns || timeout > 0 || scatterConcurrency > 0 || shardTimeout > 0 || multiShardAutoCommit {
 			_, _ = visit(plan, func(logicalPlan logicalPlan) (bool, logicalPlan, error) {
 				switch plan := logicalPlan.(type) {
 				case *route:
 					plan.eroute.ScatterErrorsAsWarnings = scatterAsWarns
 					plan.eroute.QueryTimeout = timeout
+					plan.eroute.ScatterConcurrency = scatterConcurrency
+					plan.eroute.ShardTimeout = shardTimeout
 				case *primitiveWrapper:
 					setDirective(plan.prim, multiShardAutoCommit, timeout)
 				case *insert:
@@ -88,7 +92,12 @@ func setDirective(prim engine.Primitive, msac bool, timeout int) {
 
 // queryTimeout returns DirectiveQueryTimeout value if set, otherwise returns 0.
 func queryTimeout(d *sqlparser.CommentDirectives) int {
-	val, _ := d.GetString(sqlparser.DirectiveQueryTimeout, "0")
+	return intDirective(d, sqlparser.DirectiveQueryTimeout)
+}
+
+// intDirective returns the integer value of the given directive if set, otherwise returns 0.
+func intDirective(d *sqlparser.CommentDirectives, name string) int {
+	val, _ := d.GetString(name, "0")
 	if intVal, err := strconv.Atoi(val); err == nil {
 		return intVal
 	}
@@ -0,0 +1,613 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package planbuilder
+
+import (
+	"slices"
+	"strings"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/operators"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+type (
+	// cteTable is a common table expression of a WITH RECURSIVE clause
+	cteTable struct {
+		name    string
+		columns []string
+		body    sqlparser.SelectStatement
+	}
+
+	// cteBlock is one of the SELECT statements of a query with common table expressions,
+	// without its references to them, so that it can be planned on its own: the common
+	// table expressions are removed from the FROM clause, and their columns are replaced
+	// by arguments.
+	cteBlock struct {
+		probe *sqlparser.Select
+		// vars are the arguments replacing the columns of the common table expressions,
+		// and the offsets of these columns
+		vars map[string]int
+		// exact is false when the probe is not equivalent to the original block for
+		// a given row of the common table expressions, because of an outer join
+		exact bool
+
+		plan engine.Primitive
+	}
+)
+
+// buildRecursiveCTEPlan plans a SELECT statement with a WITH RECURSIVE clause.
+// The statement is sent as a whole to a keyspace when all its SELECT statements go to
+// the same unsharded keyspace or to the same shard. Otherwise, the common table expression
+// is evaluated by the vtgate, which executes its recursive part once per row produced by
+// the previous iteration.
+func buildRecursiveCTEPlan(sel *sqlparser.Select, reservedVars *sqlparser.ReservedVars, vschema plancontext.VSchema, version querypb.ExecuteOptions_PlannerVersion) (*planResult, error) {
+	ctes := map[string]*cteTable{}
+	var selects []*sqlparser.Select
+	for _, cte := range sel.With.CTEs() {
+		table, err := newCTETable(cte)
+		if err != nil {
+			return nil, err
+		}
+		ctes[table.name] = table
+		selects = append(selects, sqlparser.GetAllSelects(table.body)...)
+	}
+	selects = append(selects, sel)
+
+	blocks := make(map[*sqlparser.Select]*cteBlock, len(selects))
+	var tablesUsed []string
+	var routed []engine.Primitive
+	for _, s := range selects {
+		block, err := newCTEBlock(s, cteLookup(ctes), reservedVars)
+		if err != nil {
+			return nil, err
+		}
+		plan, tables, err := newBuildSelectPlan(block.probe, reservedVars, vschema, version)
+		if err != nil {
+			return nil, err
+		}
+		block.plan = plan.Primitive()
+		blocks[s] = block
+		if !readsTables(block.probe) {
+			continue
+		}
+		routed = append(routed, block.plan)
+		for _, table := range tables {
+			if !slices.Contains(tablesUsed, table) {
+				tablesUsed = append(tablesUsed, table)
+			}
+		}
+	}
+
+	if len(routed) == 0 {
+		// the statement only reads from dual
+		for _, block := range blocks {
+			routed = append(routed, block.plan)
+		}
+	}
+	if rp := singleShardRouting(routed, blocks); rp != nil {
+		plan, err := sendSingleShardStatement(sel, rp, routed, vschema)
+		if err != nil {
+			return nil, err
+		}
+		return newPlanResult(plan, tablesUsed...), nil
+	}
+
+	plan, err := evaluateRecursiveCTE(sel, ctes, blocks, reservedVars, vschema, version)
+	if err != nil {
+		return nil, err
+	}
+	return newPlanResult(plan, tablesUsed...), nil
+}
+
+func newCTETable(cte *sqlparser.CommonTableExpr) (*cteTable, error) {
+	table := &cteTable{
+		name: cte.ID.String(),
+		body: cte.Subquery.Select,
+	}
+	if len(cte.Columns) > 0 {
+		for _, col := range cte.Columns {
+			table.columns = append(table.columns, col.String())
+		}
+		return table, nil
+	}
+	for _, expr := range sqlparser.GetFirstSelect(table.body).SelectExprs {
+		ae, ok := expr.(*sqlparser.AliasedExpr)
+		if !ok {
+			return nil, vterrors.VT12001("'*' in a recursive common table expression")
+		}
+		table.columns = append(table.columns, ae.ColumnName())
+	}
+	return table, nil
+}
+
+// cteLookup returns the alias and the common table expression read by a table expression,
+// or nil if it does not read one of the common table expressions
+func cteLookup(ctes map[string]*cteTable) func(sqlparser.TableExpr) (string, *cteTable) {
+	return func(expr sqlparser.TableExpr) (string, *cteTable) {
+		ate, isAliased := expr.(*sqlparser.AliasedTableExpr)
+		if !isAliased {
+			return "", nil
+		}
+		tbl, isTable := ate.Expr.(sqlparser.TableName)
+		if !isTable || !tbl.Qualifier.IsEmpty() || ctes[tbl.Name.String()] == nil {
+			return "", nil
+		}
+		if !ate.As.IsEmpty() {
+			return ate.As.String(), ctes[tbl.Name.String()]
+		}
+		return tbl.Name.String(), ctes[tbl.Name.String()]
+	}
+}
+
+// newCTEBlock builds the block of a SELECT statement, removing the table expressions for
+// which lookup returns a table.
+func newCTEBlock(sel *sqlparser.Select, lookup func(sqlparser.TableExpr) (string, *cteTable), reservedVars *sqlparser.ReservedVars) (*cteBlock, error) {
+	probe := sqlparser.CloneRefOfSelect(sel)
+	probe.With = nil
+
+	block := &cteBlock{
+		probe: probe,
+		vars:  map[string]int{},
+		exact: true,
+	}
+	aliases := map[string]*cteTable{}
+	var predicates []sqlparser.Expr
+	var removeCTEs func(sqlparser.TableExpr) sqlparser.TableExpr
+	removeCTEs = func(expr sqlparser.TableExpr) sqlparser.TableExpr {
+		if alias, table := lookup(expr); table != nil {
+			aliases[alias] = table
+			return nil
+		}
+		switch expr := expr.(type) {
+		case *sqlparser.JoinTableExpr:
+			left, right := removeCTEs(expr.LeftExpr), removeCTEs(expr.RightExpr)
+			if left != nil && right != nil {
+				expr.LeftExpr, expr.RightExpr = left, right
+				return expr
+			}
+			if expr.Condition != nil {
+				innerJoin := expr.Join == sqlparser.NormalJoinType || expr.Join == sqlparser.StraightJoinType
+				if innerJoin && expr.Condition.On != nil {
+					predicates = append(predicates, expr.Condition.On)
+				} else if expr.Condition.On != nil || len(expr.Condition.Using) > 0 {
+					block.exact = false
+				}
+			}
+			if left != nil {
+				return left
+			}
+			return right
+		case *sqlparser.ParenTableExpr:
+			var exprs sqlparser.TableExprs
+			for _, e := range expr.Exprs {
+				if e := removeCTEs(e); e != nil {
+					exprs = append(exprs, e)
+				}
+			}
+			if len(exprs) == 0 {
+				return nil
+			}
+			expr.Exprs = exprs
+			return expr
+		}
+		return expr
+	}
+
+	var from sqlparser.TableExprs
+	for _, expr := range probe.From {
+		if expr := removeCTEs(expr); expr != nil {
+			from = append(from, expr)
+		}
+	}
+	if len(from) == 0 {
+		from = sqlparser.TableExprs{&sqlparser.AliasedTableExpr{Expr: sqlparser.TableName{Name: sqlparser.NewIdentifierCS("dual")}}}
+	}
+	probe.From = from
+	for _, predicate := range predicates {
+		probe.AddWhere(predicate)
+	}
+	if len(aliases) == 0 {
+		return block, nil
+	}
+	probe.SelectExprs = expandStars(probe.SelectExprs, aliases)
+
+	args := map[string]string{}
+	block.probe = sqlparser.SafeRewrite(probe, nil, func(cursor *sqlparser.Cursor) bool {
+		col, isCol := cursor.Node().(*sqlparser.ColName)
+		if !isCol {
+			return true
+		}
+		alias, offset := cteColumn(col, aliases)
+		if offset < 0 {
+			return true
+		}
+		key := alias + "." + aliases[alias].columns[offset]
+		arg, found := args[key]
+		if !found {
+			arg = reservedVars.ReserveColName(sqlparser.NewColNameWithQualifier(aliases[alias].columns[offset], sqlparser.TableName{Name: sqlparser.NewIdentifierCS(alias)}))
+			args[key] = arg
+			block.vars[arg] = offset
+		}
+		cursor.Replace(sqlparser.NewArgument(arg))
+		return true
+	}).(*sqlparser.Select)
+	return block, nil
+}
+
+// expandStars replaces the '*' of the tables in aliases by their columns
+func expandStars(exprs sqlparser.SelectExprs, aliases map[string]*cteTable) sqlparser.SelectExprs {
+	var expanded sqlparser.SelectExprs
+	for _, expr := range exprs {
+		star, isStar := expr.(*sqlparser.StarExpr)
+		if !isStar || star.TableName.IsEmpty() || !star.TableName.Qualifier.IsEmpty() || aliases[star.TableName.Name.String()] == nil {
+			expanded = append(expanded, expr)
+			continue
+		}
+		for _, col := range aliases[star.TableName.Name.String()].columns {
+			expanded = append(expanded, &sqlparser.AliasedExpr{Expr: sqlparser.NewColNameWithQualifier(col, star.TableName)})
+		}
+	}
+	return expanded
+}
+
+// cteColumn returns the alias of the common table expression the column belongs to,
+// and the offset of the column in it, or -1 if it is not a column of a common table expression
+func cteColumn(col *sqlparser.ColName, aliases map[string]*cteTable) (string, int) {
+	if !col.Qualifier.IsEmpty() {
+		table := aliases[col.Qualifier.Name.String()]
+		if table == nil || !col.Qualifier.Qualifier.IsEmpty() {
+			return "", -1
+		}
+		return col.Qualifier.Name.String(), columnOffset(table.columns, col.Name)
+	}
+	for alias, table := range aliases {
+		if offset := columnOffset(table.columns, col.Name); offset >= 0 {
+			return alias, offset
+		}
+	}
+	return "", -1
+}
+
+func columnOffset(columns []string, name sqlparser.IdentifierCI) int {
+	return slices.IndexFunc(columns, func(col string) bool {
+		return name.EqualString(col)
+	})
+}
+
+// readsTables returns true if the SELECT statement reads other tables than dual
+func readsTables(sel *sqlparser.Select) bool {
+	reads := false
+	for _, expr := range sel.From {
+		_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+			ate, ok := node.(*sqlparser.AliasedTableExpr)
+			if !ok {
+				return true, nil
+			}
+			tbl, isTable := ate.Expr.(sqlparser.TableName)
+			if !isTable || tbl.Name.String() != "dual" {
+				reads = true
+			}
+			return !reads, nil
+		}, expr)
+	}
+	return reads
+}
+
+// singleShardRouting returns the routing parameters shared by all the plans, if they all
+// go to the same unsharded keyspace or to the same shard, and nil otherwise.
+func singleShardRouting(plans []engine.Primitive, blocks map[*sqlparser.Select]*cteBlock) *engine.RoutingParameters {
+	isCTEArgument := func(expr evalengine.Expr) bool {
+		bv, ok := expr.(*evalengine.BindVariable)
+		if !ok {
+			return false
+		}
+		for _, block := range blocks {
+			if _, found := block.vars[bv.Key]; found {
+				return true
+			}
+		}
+		return false
+	}
+
+	var target *engine.RoutingParameters
+	for _, plan := range plans {
+		var rp *engine.RoutingParameters
+		switch plan := plan.(type) {
+		case *engine.Route:
+			rp = plan.RoutingParameters
+		case *engine.VindexLookup:
+			vindex, _ := plan.Vindex.(vindexes.Vindex)
+			rp = &engine.RoutingParameters{Opcode: plan.Opcode, Keyspace: plan.Keyspace, Vindex: vindex, Values: plan.Values}
+		default:
+			return nil
+		}
+		switch rp.Opcode {
+		case engine.Unsharded, engine.Reference:
+		case engine.EqualUnique:
+			if len(rp.Values) != 1 || isCTEArgument(rp.Values[0]) {
+				return nil
+			}
+		default:
+			return nil
+		}
+
+		switch {
+		case target == nil:
+			target = rp
+		case target.Keyspace.Name != rp.Keyspace.Name:
+			return nil
+		case target.Opcode == engine.Reference:
+			target = rp
+		case rp.Opcode == engine.EqualUnique && target.Opcode == engine.EqualUnique:
+			if rp.Vindex != target.Vindex || evalengine.FormatExpr(rp.Values[0]) != evalengine.FormatExpr(target.Values[0]) {
+				return nil
+			}
+		}
+	}
+	return target
+}
+
+// sendSingleShardStatement builds a route sending the whole statement to the shard of the routing parameters
+func sendSingleShardStatement(sel *sqlparser.Select, rp *engine.RoutingParameters, plans []engine.Primitive, vschema plancontext.VSchema) (engine.Primitive, error) {
+	sqlparser.SafeRewrite(sel, nil, func(cursor *sqlparser.Cursor) bool {
+		switch node := cursor.Node().(type) {
+		case sqlparser.SelectExpr:
+			removeKeyspaceFromSelectExpr(node)
+		case sqlparser.TableName:
+			cursor.Replace(sqlparser.TableName{
+				Name: node.Name,
+			})
+		}
+		return true
+	})
+
+	var tableNames []string
+	for _, plan := range plans {
+		for _, name := range strings.Split(plan.GetTableName(), ", ") {
+			if name != "" && !slices.Contains(tableNames, name) {
+				tableNames = append(tableNames, name)
+			}
+		}
+	}
+	rb := &route{
+		eroute: &engine.Route{
+			RoutingParameters: &engine.RoutingParameters{
+				Opcode:   rp.Opcode,
+				Keyspace: rp.Keyspace,
+				Vindex:   rp.Vindex,
+				Values:   rp.Values,
+			},
+			TableName: strings.Join(tableNames, ", "),
+		},
+		Select: sel,
+	}
+	if err := rb.Wireup(&plancontext.PlanningContext{VSchema: vschema}); err != nil {
+		return nil, err
+	}
+	return rb.Primitive(), nil
+}
+
+// evaluateRecursiveCTE plans the evaluation of the common table expression by the vtgate.
+// The recursive part is planned with the columns of the common table expression replaced
+// by arguments, which are set from each row of the previous iteration. The query using the
+// common table expression can only read from it, and is evaluated by the vtgate too.
+func evaluateRecursiveCTE(sel *sqlparser.Select, ctes map[string]*cteTable, blocks map[*sqlparser.Select]*cteBlock, reservedVars *sqlparser.ReservedVars, vschema plancontext.VSchema, version querypb.ExecuteOptions_PlannerVersion) (engine.Primitive, error) {
+	if len(ctes) != 1 {
+		return nil, vterrors.VT12001("several common table expressions in a cross-shard recursive query")
+	}
+	var table *cteTable
+	for _, t := range ctes {
+		table = t
+	}
+
+	seed, recursive := table.body, sqlparser.SelectStatement(nil)
+	distinct := false
+	if union, isUnion := table.body.(*sqlparser.Union); isUnion && referencesCTE(union.Right, table) {
+		seed, recursive, distinct = union.Left, union.Right, union.Distinct
+	}
+	if referencesCTE(seed, table) {
+		return nil, vterrors.VT12001("a recursive common table expression that does not end with its recursive SELECT in a cross-shard query")
+	}
+
+	seedPlan, _, err := newBuildSelectPlan(sqlparser.CloneSelectStatement(seed), reservedVars, vschema, version)
+	if err != nil {
+		return nil, err
+	}
+	cte := &engine.RecursiveCTE{
+		Seed:     seedPlan.Primitive(),
+		Distinct: distinct,
+	}
+	if recursive != nil {
+		rsel, isSelect := recursive.(*sqlparser.Select)
+		if !isSelect || !blocks[rsel].exact {
+			return nil, vterrors.VT12001("this recursive SELECT in a cross-shard recursive query")
+		}
+		cte.Recursive = blocks[rsel].plan
+		cte.Vars = blocks[rsel].vars
+	}
+
+	return planCTEQuery(sel, table, cte)
+}
+
+// referencesCTE returns true if the statement reads from the common table expression
+func referencesCTE(stmt sqlparser.SelectStatement, table *cteTable) bool {
+	found := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if tbl, ok := node.(sqlparser.TableName); ok && tbl.Qualifier.IsEmpty() && tbl.Name.String() == table.name {
+			found = true
+		}
+		return !found, nil
+	}, stmt)
+	return found
+}
+
+// planCTEQuery plans the query using the common table expression. It can only select,
+// filter, sort and limit the rows of the common table expression.
+func planCTEQuery(sel *sqlparser.Select, table *cteTable, input engine.Primitive) (engine.Primitive, error) {
+	unsupported := func(what string) error {
+		return vterrors.VT12001(what + " in the query using a cross-shard recursive common table expression")
+	}
+	if len(sel.From) != 1 {
+		return nil, unsupported("reading other tables")
+	}
+	ate, isAliased := sel.From[0].(*sqlparser.AliasedTableExpr)
+	if !isAliased {
+		return nil, unsupported("reading other tables")
+	}
+	tbl, isTable := ate.Expr.(sqlparser.TableName)
+	if !isTable || !tbl.Qualifier.IsEmpty() || tbl.Name.String() != table.name {
+		return nil, unsupported("reading other tables")
+	}
+	if err := checkVTGateSelect(sel, unsupported); err != nil {
+		return nil, err
+	}
+
+	alias := table.name
+	if !ate.As.IsEmpty() {
+		alias = ate.As.String()
+	}
+	cfg := &evalengine.Config{
+		ResolveColumn: func(col *sqlparser.ColName) (int, error) {
+			if !col.Qualifier.IsEmpty() && (!col.Qualifier.Qualifier.IsEmpty() || col.Qualifier.Name.String() != alias) {
+				return 0, vterrors.VT03019(sqlparser.String(col))
+			}
+			offset := columnOffset(table.columns, col.Name)
+			if offset < 0 {
+				return 0, vterrors.VT03019(sqlparser.String(col))
+			}
+			return offset, nil
+		},
+	}
+
+	var where sqlparser.Expr
+	if sel.Where != nil {
+		where = sel.Where.Expr
+	}
+	return planVTGateSelect(sel, where, cfg, table.columns, input, unsupported)
+}
+
+// checkVTGateSelect returns an error if the SELECT statement cannot be evaluated by
+// planVTGateSelect.
+func checkVTGateSelect(sel *sqlparser.Select, unsupported func(string) error) error {
+	switch {
+	case sel.Distinct:
+		return unsupported("DISTINCT")
+	case sel.GroupBy != nil || sel.Having != nil || sel.SelectExprs.AllAggregation() || sqlparser.ContainsAggregation(sel.SelectExprs):
+		return unsupported("aggregation")
+	case operators.ContainsWindowFunction(sel.SelectExprs):
+		return unsupported("window functions")
+	case sel.Lock != sqlparser.NoLock || sel.Into != nil:
+		return unsupported("locking or INTO")
+	}
+	hasSubquery := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if _, ok := node.(*sqlparser.Subquery); ok {
+			hasSubquery = true
+		}
+		return !hasSubquery, nil
+	}, sel.SelectExprs, sel.Where, sel.OrderBy)
+	if hasSubquery {
+		return unsupported("subqueries")
+	}
+	return nil
+}
+
+// planVTGateSelect plans the evaluation by the vtgate of the SELECT statement on the rows
+// of the input: they are filtered by the predicate, sorted and limited, and the columns
+// are projected. '*' selects the columns given.
+func planVTGateSelect(sel *sqlparser.Select, predicate sqlparser.Expr, cfg *evalengine.Config, columns []string, input engine.Primitive, unsupported func(string) error) (engine.Primitive, error) {
+	plan := input
+	if predicate != nil {
+		epredicate, err := evalengine.Translate(predicate, cfg)
+		if err != nil {
+			return nil, err
+		}
+		plan = &engine.Filter{
+			Predicate:    epredicate,
+			ASTPredicate: predicate,
+			Input:        plan,
+		}
+	}
+
+	if len(sel.OrderBy) > 0 {
+		ms := &engine.MemorySort{Input: plan}
+		for _, order := range sel.OrderBy {
+			col, isCol := order.Expr.(*sqlparser.ColName)
+			if !isCol {
+				return nil, unsupported("ordering by an expression")
+			}
+			offset, err := cfg.ResolveColumn(col)
+			if err != nil {
+				return nil, err
+			}
+			ms.OrderBy = append(ms.OrderBy, engine.OrderByParams{
+				Col:             offset,
+				WeightStringCol: -1,
+				Desc:            order.Direction == sqlparser.DescOrder,
+			})
+		}
+		plan = ms
+	}
+
+	if sel.Limit != nil {
+		limit := &engine.Limit{Input: plan}
+		var err error
+		if limit.Count, err = evalengine.Translate(sel.Limit.Rowcount, nil); err != nil {
+			return nil, err
+		}
+		if sel.Limit.Offset != nil {
+			if limit.Offset, err = evalengine.Translate(sel.Limit.Offset, nil); err != nil {
+				return nil, err
+			}
+		}
+		plan = limit
+	}
+
+	if len(sel.SelectExprs) == 1 {
+		if _, isStar := sel.SelectExprs[0].(*sqlparser.StarExpr); isStar {
+			return plan, nil
+		}
+	}
+	proj := &engine.Projection{Input: plan}
+	for _, expr := range sel.SelectExprs {
+		switch expr := expr.(type) {
+		case *sqlparser.StarExpr:
+			for offset, col := range columns {
+				proj.Cols = append(proj.Cols, col)
+				proj.Exprs = append(proj.Exprs, evalengine.NewColumn(offset, sqltypes.Unknown, collations.Unknown))
+			}
+		case *sqlparser.AliasedExpr:
+			eexpr, err := evalengine.Translate(expr.Expr, cfg)
+			if err != nil {
+				return nil, err
+			}
+			proj.Cols = append(proj.Cols, expr.ColumnName())
+			proj.Exprs = append(proj.Exprs, eexpr)
+		default:
+			return nil, unsupported(sqlparser.String(expr))
+		}
+	}
+	return proj, nil
+}
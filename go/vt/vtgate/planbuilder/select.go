@@ -41,8 +41,14 @@ func gen4SelectStmtPlanner(
 	switch node := stmt.(type) {
 	case *sqlparser.Select:
 		if node.With != nil {
+			if node.With.Recursive {
+				return buildRecursiveCTEPlan(node, reservedVars, vschema, plannerVersion)
+			}
 			return nil, vterrors.VT12001("WITH expression in SELECT statement")
 		}
+		if len(jsonTablesOf(node)) > 0 {
+			return buildJSONTablePlan(node, reservedVars, vschema, plannerVersion)
+		}
 	case *sqlparser.Union:
 		if node.With != nil {
 			return nil, vterrors.VT12001("WITH expression in UNION statement")
@@ -452,6 +458,13 @@ func handleDualSelects(sel *sqlparser.Select, vschema plancontext.VSchema) (engi
 				}
 				elem.Name = n
 			}
+			if lFunc.Timeout != nil {
+				t, err := evalengine.Translate(lFunc.Timeout, nil)
+				if err != nil {
+					return nil, err
+				}
+				elem.Timeout = t
+			}
 			lockFunctions = append(lockFunctions, elem)
 			continue
 		}
@@ -535,7 +535,14 @@ func buildCreatePlan(show *sqlparser.ShowCreate, vschema plancontext.VSchema) (e
 
 	dest, ks, _, err := vschema.TargetDestination(dbName)
 	if err != nil {
-		return nil, err
+		if dbName != "" {
+			return nil, err
+		}
+		// No keyspace is selected, so we look for the keyspace of the object in the tracked schema.
+		ks = findTrackedKeyspace(show, vschema.GetVSchema())
+		if ks == nil {
+			return nil, err
+		}
 	}
 	if dest == nil {
 		dest = key.DestinationAnyShard{}
@@ -551,6 +558,35 @@ func buildCreatePlan(show *sqlparser.ShowCreate, vschema plancontext.VSchema) (e
 
 }
 
+// findTrackedKeyspace returns the keyspace of the view, stored procedure or function,
+// as known by the schema tracker. It returns nil if none or more than one keyspace has it.
+func findTrackedKeyspace(show *sqlparser.ShowCreate, vschema *vindexes.VSchema) *vindexes.Keyspace {
+	if vschema == nil {
+		return nil
+	}
+	name := show.Op.Name.String()
+	var found *vindexes.Keyspace
+	for _, ks := range vschema.Keyspaces {
+		var ok bool
+		switch show.Command {
+		case sqlparser.CreateV:
+			_, ok = ks.Views[name]
+		case sqlparser.CreateProc:
+			_, ok = ks.Procedures[name]
+		case sqlparser.CreateF:
+			_, ok = ks.Functions[name]
+		}
+		if !ok {
+			continue
+		}
+		if found != nil {
+			return nil
+		}
+		found = ks.Keyspace
+	}
+	return found
+}
+
 func buildShowVGtidPlan(show *sqlparser.ShowBasic, vschema plancontext.VSchema) (engine.Primitive, error) {
 	send, err := buildShowGtidPlan(show, vschema)
 	if err != nil {
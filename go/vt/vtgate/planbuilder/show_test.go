@@ -29,6 +29,7 @@ import (
 
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/engine"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 )
 
@@ -64,6 +65,70 @@ func TestBuildDBPlan(t *testing.T) {
 	}
 }
 
+func TestBuildShowCreateTrackedPlan(t *testing.T) {
+	mainKs := &vindexes.Keyspace{Name: "main"}
+	userKs := &vindexes.Keyspace{Name: "user", Sharded: true}
+	vschema := &vschemawrapper.VSchemaWrapper{
+		V: &vindexes.VSchema{
+			Keyspaces: map[string]*vindexes.KeyspaceSchema{
+				"main": {
+					Keyspace:   mainKs,
+					Views:      map[string]sqlparser.SelectStatement{"v1": &sqlparser.Select{}},
+					Procedures: map[string]string{"p1": "create procedure p1() select 1", "p2": "create procedure p2() select 2"},
+				},
+				"user": {
+					Keyspace:   userKs,
+					Procedures: map[string]string{"p2": "create procedure p2() select 2"},
+					Functions:  map[string]string{"f1": "create function f1() returns int return 1"},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		query    string
+		keyspace string
+		err      string
+	}{{
+		query:    "show create view v1",
+		keyspace: "main",
+	}, {
+		query:    "show create procedure p1",
+		keyspace: "main",
+	}, {
+		query:    "show create function f1",
+		keyspace: "user",
+	}, {
+		// p2 is in both keyspaces.
+		query: "show create procedure p2",
+		err:   "VT03007",
+	}, {
+		query: "show create view unknown",
+		err:   "VT03007",
+	}, {
+		query: "show create trigger tr1",
+		err:   "VT03007",
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			parserOut, err := sqlparser.Parse(tc.query)
+			require.NoError(t, err)
+
+			show := parserOut.(*sqlparser.Show)
+			primitive, err := buildShowCreatePlan(show.Internal.(*sqlparser.ShowCreate), vschema)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			send := primitive.(*engine.Send)
+			require.Equal(t, tc.keyspace, send.Keyspace.Name)
+			require.Equal(t, tc.query, send.Query)
+		})
+	}
+}
+
 func TestGenerateCharsetRows(t *testing.T) {
 	rows0 := [][]sqltypes.Value{
 		append(buildVarCharRow(
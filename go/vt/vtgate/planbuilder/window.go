@@ -0,0 +1,50 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package planbuilder
+
+import (
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+)
+
+var _ logicalPlan = (*window)(nil)
+
+// window is the logicalPlan for engine.Window.
+type window struct {
+	logicalPlanCommon
+	eWindow *engine.Window
+}
+
+// Primitive implements the logicalPlan interface
+func (w *window) Primitive() engine.Primitive {
+	w.eWindow.Input = w.input.Primitive()
+	return w.eWindow
+}
+
+// Rewrite implements the logicalPlan interface
+func (w *window) Rewrite(inputs ...logicalPlan) error {
+	if len(inputs) != 1 {
+		return vterrors.VT13001("window: wrong number of inputs")
+	}
+	w.input = inputs[0]
+	return nil
+}
+
+// Inputs implements the logicalPlan interface
+func (w *window) Inputs() []logicalPlan {
+	return []logicalPlan{w.input}
+}
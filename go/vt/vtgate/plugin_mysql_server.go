@@ -51,6 +51,7 @@ import (
 
 var (
 	mysqlServerPort                   = -1
+	mysqlxServerPort                  = -1
 	mysqlServerBindAddress            string
 	mysqlServerSocketPath             string
 	mysqlTCPVersion                   = "tcp"
@@ -78,6 +79,7 @@ var (
 
 func registerPluginFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&mysqlServerPort, "mysql_server_port", mysqlServerPort, "If set, also listen for MySQL binary protocol connections on this port.")
+	fs.IntVar(&mysqlxServerPort, "mysqlx_server_port", mysqlxServerPort, "If set, also listen for MySQL X Protocol connections on this port, for the document store clients. It uses the bind address, the auth server and the TLS configuration of the MySQL protocol.")
 	fs.StringVar(&mysqlServerBindAddress, "mysql_server_bind_address", mysqlServerBindAddress, "Binds on this address when listening to MySQL binary protocol. Useful to restrict listening to 'localhost' only for instance.")
 	fs.StringVar(&mysqlServerSocketPath, "mysql_server_socket_path", mysqlServerSocketPath, "This option specifies the Unix socket file to use when listening for local connections. By default it will be empty and it won't listen to a unix socket")
 	fs.StringVar(&mysqlTCPVersion, "mysql_tcp_version", mysqlTCPVersion, "Select tcp, tcp4, or tcp6 to control the socket type.")
@@ -438,8 +440,10 @@ func (vh *vtgateHandler) session(c *mysql.Conn) *vtgatepb.Session {
 }
 
 type mysqlServer struct {
+	mu           sync.Mutex
 	tcpListener  *mysql.Listener
 	unixListener *mysql.Listener
+	xListener    *mysql.Listener
 	sigChan      chan os.Signal
 	vtgateHandle *vtgateHandler
 }
@@ -451,8 +455,10 @@ func initTLSConfig(ctx context.Context, srv *mysqlServer, mysqlSslCert, mysqlSsl
 		log.Exitf("grpcutils.TLSServerConfig failed: %v", err)
 		return err
 	}
-	srv.tcpListener.TLSConfig.Store(serverConfig)
-	srv.tcpListener.RequireSecureTransport = mysqlServerRequireSecureTransport
+	for _, l := range srv.tlsListeners() {
+		l.TLSConfig.Store(serverConfig)
+		l.RequireSecureTransport = mysqlServerRequireSecureTransport
+	}
 	srv.sigChan = make(chan os.Signal, 1)
 	signal.Notify(srv.sigChan, syscall.SIGHUP)
 	go func() {
@@ -466,7 +472,9 @@ func initTLSConfig(ctx context.Context, srv *mysqlServer, mysqlSslCert, mysqlSsl
 					log.Errorf("grpcutils.TLSServerConfig failed: %v", err)
 				} else {
 					log.Info("grpcutils.TLSServerConfig updated")
-					srv.tcpListener.TLSConfig.Store(serverConfig)
+					for _, l := range srv.tlsListeners() {
+						l.TLSConfig.Store(serverConfig)
+					}
 				}
 			}
 		}
@@ -474,11 +482,22 @@ func initTLSConfig(ctx context.Context, srv *mysqlServer, mysqlSslCert, mysqlSsl
 	return nil
 }
 
+// tlsListeners returns the TCP listeners, which share the TLS configuration.
+func (srv *mysqlServer) tlsListeners() []*mysql.Listener {
+	var listeners []*mysql.Listener
+	for _, l := range []*mysql.Listener{srv.tcpListener, srv.xListener} {
+		if l != nil {
+			listeners = append(listeners, l)
+		}
+	}
+	return listeners
+}
+
 // initiMySQLProtocol starts the mysql protocol.
 // It should be called only once in a process.
 func initMySQLProtocol(vtgate *VTGate) *mysqlServer {
 	// Flag is not set, just return.
-	if mysqlServerPort < 0 && mysqlServerSocketPath == "" {
+	if mysqlServerPort < 0 && mysqlServerSocketPath == "" && mysqlxServerPort < 0 {
 		return nil
 	}
 
@@ -526,22 +545,47 @@ func initMySQLProtocol(vtgate *VTGate) *mysqlServer {
 			log.Exitf("mysql.NewListener failed: %v", err)
 		}
 		srv.tcpListener.ServerVersion = servenv.MySQLServerVersion()
-		if mysqlSslCert != "" && mysqlSslKey != "" {
-			tlsVersion, err := vttls.TLSVersionToNumber(mysqlTLSMinVersion)
-			if err != nil {
-				log.Exitf("mysql.NewListener failed: %v", err)
-			}
-
-			_ = initTLSConfig(context.Background(), srv, mysqlSslCert, mysqlSslKey, mysqlSslCa, mysqlSslCrl, mysqlSslServerCA, mysqlServerRequireSecureTransport, tlsVersion)
-		}
 		srv.tcpListener.AllowClearTextWithoutTLS.Store(mysqlAllowClearTextWithoutTLS)
 		// Check for the connection threshold
 		if mysqlSlowConnectWarnThreshold != 0 {
 			log.Infof("setting mysql slow connection threshold to %v", mysqlSlowConnectWarnThreshold)
 			srv.tcpListener.SlowConnectWarnThreshold.Store(mysqlSlowConnectWarnThreshold.Nanoseconds())
 		}
-		// Start listening for tcp
-		go srv.tcpListener.Accept()
+	}
+
+	if mysqlxServerPort >= 0 {
+		srv.xListener, err = mysql.NewListenerWithConfig(mysql.ListenerConfig{
+			Protocol:            mysqlTCPVersion,
+			Address:             net.JoinHostPort(mysqlServerBindAddress, fmt.Sprintf("%v", mysqlxServerPort)),
+			AuthServer:          authServer,
+			Handler:             srv.vtgateHandle,
+			ConnReadTimeout:     mysqlConnReadTimeout,
+			ConnWriteTimeout:    mysqlConnWriteTimeout,
+			ConnKeepAlivePeriod: mysqlKeepAlivePeriod,
+			XProtocol:           true,
+		})
+		if err != nil {
+			log.Exitf("mysql.NewListener failed: %v", err)
+		}
+		srv.xListener.ServerVersion = servenv.MySQLServerVersion()
+		srv.xListener.AllowClearTextWithoutTLS.Store(mysqlAllowClearTextWithoutTLS)
+		if mysqlSlowConnectWarnThreshold != 0 {
+			srv.xListener.SlowConnectWarnThreshold.Store(mysqlSlowConnectWarnThreshold.Nanoseconds())
+		}
+	}
+
+	if len(srv.tlsListeners()) > 0 && mysqlSslCert != "" && mysqlSslKey != "" {
+		tlsVersion, err := vttls.TLSVersionToNumber(mysqlTLSMinVersion)
+		if err != nil {
+			log.Exitf("mysql.NewListener failed: %v", err)
+		}
+
+		_ = initTLSConfig(context.Background(), srv, mysqlSslCert, mysqlSslKey, mysqlSslCa, mysqlSslCrl, mysqlSslServerCA, mysqlServerRequireSecureTransport, tlsVersion)
+	}
+
+	// Start listening for tcp
+	for _, l := range srv.tlsListeners() {
+		go l.Accept()
 	}
 
 	if mysqlServerSocketPath != "" {
@@ -612,18 +656,31 @@ func newMysqlUnixSocket(address string, authServer mysql.AuthServer, handler mys
 	}
 }
 
-func (srv *mysqlServer) shutdownMysqlProtocolAndDrain() {
+// shutdownListeners stops accepting connections. The open connections that are not
+// in a transaction get an ER_SERVER_SHUTDOWN error on their next command.
+func (srv *mysqlServer) shutdownListeners() {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
 	if srv.tcpListener != nil {
-		srv.tcpListener.Close()
+		srv.tcpListener.Shutdown()
 		srv.tcpListener = nil
 	}
 	if srv.unixListener != nil {
-		srv.unixListener.Close()
+		srv.unixListener.Shutdown()
 		srv.unixListener = nil
 	}
+	if srv.xListener != nil {
+		srv.xListener.Shutdown()
+		srv.xListener = nil
+	}
 	if srv.sigChan != nil {
 		signal.Stop(srv.sigChan)
 	}
+}
+
+func (srv *mysqlServer) shutdownMysqlProtocolAndDrain() {
+	srv.vtgateHandle.vtg.Drain()
+	srv.shutdownListeners()
 
 	if busy := srv.vtgateHandle.busyConnections.Load(); busy > 0 {
 		log.Infof("Waiting for all client connections to be idle (%d active)...", busy)
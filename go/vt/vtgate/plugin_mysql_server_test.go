@@ -39,6 +39,7 @@ import (
 	"vitess.io/vitess/go/trace"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	"vitess.io/vitess/go/vt/tlstest"
+	"vitess.io/vitess/go/vt/vttablet/sandboxconn"
 )
 
 type testHandler struct {
@@ -116,6 +117,55 @@ func TestConnectionUnixSocket(t *testing.T) {
 	c.Close()
 }
 
+func TestMultiStatementsThroughVtgateHandler(t *testing.T) {
+	vtg, sbc, _ := createVtgateEnv(t)
+	vh := newVtgateHandler(vtg)
+
+	unixSocket, err := os.CreateTemp("", "mysql_vitess_test.sock")
+	require.NoError(t, err)
+	os.Remove(unixSocket.Name())
+
+	l, err := newMysqlUnixSocket(unixSocket.Name(), newTestAuthServerStatic(), vh)
+	require.NoError(t, err)
+	defer l.Close()
+	go l.Accept()
+
+	params := &mysql.ConnParams{
+		UnixSocket: unixSocket.Name(),
+		Uname:      "user1",
+		Pass:       "password1",
+		DbName:     KsTestUnsharded,
+	}
+	c, err := mysql.Connect(context.Background(), params)
+	require.NoError(t, err)
+	defer c.Close()
+
+	// The statements are executed in order in the session, with a result set for each of them.
+	qr, more, err := c.ExecuteFetchMulti("begin; select id from t1; update t1 set id = 2 where id = 1; commit; -- done", 100, true)
+	require.NoError(t, err)
+	assert.True(t, more)
+	assert.Empty(t, qr.Fields)
+
+	qr, more, _, err = c.ReadQueryResult(100, true)
+	require.NoError(t, err)
+	assert.True(t, more)
+	assert.Equal(t, sandboxconn.SingleRowResult.Rows, qr.Rows)
+
+	qr, more, _, err = c.ReadQueryResult(100, true)
+	require.NoError(t, err)
+	assert.True(t, more)
+	assert.EqualValues(t, 1, qr.RowsAffected)
+
+	_, more, _, err = c.ReadQueryResult(100, true)
+	require.NoError(t, err)
+	assert.False(t, more)
+
+	require.Len(t, sbc.Queries, 2)
+	assert.Equal(t, "select id from t1", sbc.Queries[0].Sql)
+	assert.Equal(t, "update t1 set id = :id /* INT64 */ where id = :id1 /* INT64 */", sbc.Queries[1].Sql)
+	assert.EqualValues(t, 1, sbc.CommitCount.Load())
+}
+
 func TestConnectionStaleUnixSocket(t *testing.T) {
 	th := &testHandler{}
 
@@ -286,7 +336,7 @@ func testInitTLSConfig(t *testing.T, serverCA bool) {
 		serverCACert = path.Join(root, "ca-cert.pem")
 	}
 
-	srv := &mysqlServer{tcpListener: &mysql.Listener{}}
+	srv := &mysqlServer{tcpListener: &mysql.Listener{}, xListener: &mysql.Listener{}}
 	if err := initTLSConfig(ctx, srv, path.Join(root, "server-cert.pem"), path.Join(root, "server-key.pem"), path.Join(root, "ca-cert.pem"), path.Join(root, "ca-crl.pem"), serverCACert, true, tls.VersionTLS12); err != nil {
 		t.Fatalf("init tls config failure due to: +%v", err)
 	}
@@ -295,6 +345,9 @@ func testInitTLSConfig(t *testing.T, serverCA bool) {
 	if serverConfig == nil {
 		t.Fatalf("init tls config shouldn't create nil server config")
 	}
+	if srv.xListener.TLSConfig.Load() != serverConfig {
+		t.Fatalf("init tls config should share the server config with the X Protocol listener")
+	}
 
 	srv.sigChan <- syscall.SIGHUP
 	time.Sleep(100 * time.Millisecond) // wait for signal handler
@@ -302,6 +355,9 @@ func testInitTLSConfig(t *testing.T, serverCA bool) {
 	if srv.tcpListener.TLSConfig.Load() == serverConfig {
 		t.Fatalf("init tls config should have been recreated after SIGHUP")
 	}
+	if srv.xListener.TLSConfig.Load() != srv.tcpListener.TLSConfig.Load() {
+		t.Fatalf("init tls config should have been recreated for the X Protocol listener after SIGHUP")
+	}
 }
 
 // TestKillMethods test the mysql plugin for kill method calls.
@@ -0,0 +1,217 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/ratelimiter"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+const (
+	quotaLimitQPS         = "qps"
+	quotaLimitConcurrency = "concurrency"
+	quotaLimitRows        = "rows"
+)
+
+var queryQuotaThrottled = stats.NewCountersWithMultiLabels("QueryQuotaThrottled", "Count of queries throttled by a query quota", []string{"Quota", "Limit"})
+
+// QueryQuota is a budget shared by the queries of a MySQL user, of a keyspace
+// or of a table. The fields that are set must all match the query for the quota to apply.
+// The limits that are 0 are not enforced.
+type QueryQuota struct {
+	User     string `json:"user,omitempty"`
+	Keyspace string `json:"keyspace,omitempty"`
+	// Table requires the Keyspace to be set.
+	Table string `json:"table,omitempty"`
+
+	// MaxQPS is the maximum number of queries per second.
+	MaxQPS int `json:"max_qps,omitempty"`
+	// MaxConcurrency is the maximum number of queries running at the same time.
+	MaxConcurrency int `json:"max_concurrency,omitempty"`
+	// MaxRowsPerSecond is the maximum number of rows returned per second. As the
+	// rows are only known once the query ran, the queries are throttled once the
+	// rows of the current second exceeded the limit.
+	MaxRowsPerSecond int64 `json:"max_rows_per_second,omitempty"`
+}
+
+// String returns the scope of the quota, as used in the errors and the stats.
+func (q *QueryQuota) String() string {
+	var scope []string
+	if q.User != "" {
+		scope = append(scope, "user "+q.User)
+	}
+	if q.Table != "" {
+		scope = append(scope, "table "+q.Keyspace+"."+q.Table)
+	} else if q.Keyspace != "" {
+		scope = append(scope, "keyspace "+q.Keyspace)
+	}
+	return strings.Join(scope, " on ")
+}
+
+// matches returns true if the quota applies to the query of the user on the tables,
+// given as keyspace.table.
+func (q *QueryQuota) matches(user string, tables []string) bool {
+	if q.User != "" && q.User != user {
+		return false
+	}
+	if q.Keyspace == "" {
+		return true
+	}
+	for _, table := range tables {
+		ks, tbl, _ := strings.Cut(table, ".")
+		if ks == q.Keyspace && (q.Table == "" || tbl == q.Table) {
+			return true
+		}
+	}
+	return false
+}
+
+// quotaLimiter enforces the limits of a QueryQuota.
+type quotaLimiter struct {
+	quota QueryQuota
+	name  string
+	qps   *ratelimiter.RateLimiter
+
+	mu          sync.Mutex
+	concurrency int
+	rowsSecond  time.Time
+	rows        int64
+}
+
+func newQuotaLimiter(quota QueryQuota) *quotaLimiter {
+	ql := &quotaLimiter{
+		quota: quota,
+		name:  quota.String(),
+	}
+	if quota.MaxQPS > 0 {
+		ql.qps = ratelimiter.NewRateLimiter(quota.MaxQPS, time.Second)
+	}
+	return ql
+}
+
+// acquire reserves a query in the quota, or returns the limit it exceeds.
+func (ql *quotaLimiter) acquire(now time.Time) (string, int64) {
+	ql.mu.Lock()
+	defer ql.mu.Unlock()
+
+	if ql.quota.MaxConcurrency > 0 && ql.concurrency >= ql.quota.MaxConcurrency {
+		return quotaLimitConcurrency, int64(ql.quota.MaxConcurrency)
+	}
+	if ql.quota.MaxRowsPerSecond > 0 && now.Sub(ql.rowsSecond) < time.Second && ql.rows >= ql.quota.MaxRowsPerSecond {
+		return quotaLimitRows, ql.quota.MaxRowsPerSecond
+	}
+	if ql.qps != nil && !ql.qps.Allow() {
+		return quotaLimitQPS, int64(ql.quota.MaxQPS)
+	}
+	ql.concurrency++
+	return "", 0
+}
+
+// release releases a query reserved by acquire, and charges the rows it returned.
+func (ql *quotaLimiter) release(now time.Time, rows uint64) {
+	ql.mu.Lock()
+	defer ql.mu.Unlock()
+
+	ql.concurrency--
+	if ql.quota.MaxRowsPerSecond > 0 {
+		if now.Sub(ql.rowsSecond) >= time.Second {
+			ql.rowsSecond = now
+			ql.rows = 0
+		}
+		ql.rows += int64(rows)
+	}
+}
+
+// queryQuotas enforces the query quotas of the --query-quota-config file.
+type queryQuotas struct {
+	limiters []*quotaLimiter
+}
+
+// loadQueryQuotas loads the quotas of the JSON file, which contains a list of QueryQuota.
+// It returns nil if path is empty.
+func loadQueryQuotas(path string) (*queryQuotas, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var quotas []QueryQuota
+	if err := json.Unmarshal(data, &quotas); err != nil {
+		return nil, fmt.Errorf("cannot parse the query quotas of %s: %v", path, err)
+	}
+	return newQueryQuotas(quotas)
+}
+
+func newQueryQuotas(quotas []QueryQuota) (*queryQuotas, error) {
+	qq := &queryQuotas{}
+	for _, quota := range quotas {
+		if quota.User == "" && quota.Keyspace == "" {
+			return nil, fmt.Errorf("query quota must have a user or a keyspace")
+		}
+		if quota.Table != "" && quota.Keyspace == "" {
+			return nil, fmt.Errorf("query quota on table %s must have a keyspace", quota.Table)
+		}
+		if quota.MaxQPS < 0 || quota.MaxConcurrency < 0 || quota.MaxRowsPerSecond < 0 {
+			return nil, fmt.Errorf("query quota of %s has a negative limit", quota.String())
+		}
+		qq.limiters = append(qq.limiters, newQuotaLimiter(quota))
+	}
+	return qq, nil
+}
+
+// acquire reserves the query of the user of the context on the tables, given as keyspace.table,
+// in all the quotas that apply to it. It returns a function releasing the query with the number
+// of rows it returned, or a VT08001 error if a quota is exceeded.
+func (qq *queryQuotas) acquire(ctx context.Context, tables []string) (func(rows uint64), error) {
+	if qq == nil || len(qq.limiters) == 0 {
+		return func(uint64) {}, nil
+	}
+	user := callerid.GetUsername(callerid.ImmediateCallerIDFromContext(ctx))
+	now := time.Now()
+
+	var acquired []*quotaLimiter
+	release := func(rows uint64) {
+		now := time.Now()
+		for _, ql := range acquired {
+			ql.release(now, rows)
+		}
+	}
+	for _, ql := range qq.limiters {
+		if !ql.quota.matches(user, tables) {
+			continue
+		}
+		if limit, value := ql.acquire(now); limit != "" {
+			release(0)
+			queryQuotaThrottled.Add([]string{ql.name, limit}, 1)
+			return nil, vterrors.VT08001(ql.name, limit, value)
+		}
+		acquired = append(acquired, ql)
+	}
+	return release, nil
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+func TestLoadQueryQuotas(t *testing.T) {
+	qq, err := loadQueryQuotas("")
+	require.NoError(t, err)
+	assert.Nil(t, qq)
+
+	dir := t.TempDir()
+	file := path.Join(dir, "quotas.json")
+	require.NoError(t, os.WriteFile(file, []byte(`[{"user": "app", "max_qps": 10}, {"keyspace": "ks", "table": "t", "max_concurrency": 2}]`), 0600))
+	qq, err = loadQueryQuotas(file)
+	require.NoError(t, err)
+	require.Len(t, qq.limiters, 2)
+	assert.Equal(t, "user app", qq.limiters[0].name)
+	assert.Equal(t, "table ks.t", qq.limiters[1].name)
+
+	require.NoError(t, os.WriteFile(file, []byte(`{`), 0600))
+	_, err = loadQueryQuotas(file)
+	assert.ErrorContains(t, err, "cannot parse the query quotas")
+
+	_, err = newQueryQuotas([]QueryQuota{{MaxQPS: 1}})
+	assert.EqualError(t, err, "query quota must have a user or a keyspace")
+	_, err = newQueryQuotas([]QueryQuota{{User: "app", Table: "t"}})
+	assert.EqualError(t, err, "query quota on table t must have a keyspace")
+	_, err = newQueryQuotas([]QueryQuota{{User: "app", Keyspace: "ks", MaxQPS: -1}})
+	assert.EqualError(t, err, "query quota of user app on keyspace ks has a negative limit")
+}
+
+func TestQueryQuotaMatches(t *testing.T) {
+	tcases := []struct {
+		quota  QueryQuota
+		user   string
+		tables []string
+		want   bool
+	}{{
+		quota: QueryQuota{User: "app"},
+		user:  "app",
+		want:  true,
+	}, {
+		quota: QueryQuota{User: "app"},
+		user:  "other",
+		want:  false,
+	}, {
+		quota:  QueryQuota{Keyspace: "ks"},
+		tables: []string{"other.t", "ks.t"},
+		want:   true,
+	}, {
+		quota:  QueryQuota{Keyspace: "ks"},
+		tables: []string{"other.t"},
+		want:   false,
+	}, {
+		quota:  QueryQuota{Keyspace: "ks", Table: "t"},
+		tables: []string{"ks.t2"},
+		want:   false,
+	}, {
+		quota:  QueryQuota{User: "app", Keyspace: "ks", Table: "t"},
+		user:   "app",
+		tables: []string{"ks.t"},
+		want:   true,
+	}, {
+		quota:  QueryQuota{User: "app", Keyspace: "ks", Table: "t"},
+		user:   "other",
+		tables: []string{"ks.t"},
+		want:   false,
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.quota.String(), func(t *testing.T) {
+			assert.Equal(t, tcase.want, tcase.quota.matches(tcase.user, tcase.tables))
+		})
+	}
+}
+
+func TestQueryQuotaLimits(t *testing.T) {
+	ctx := callerid.NewContext(context.Background(), nil, callerid.NewImmediateCallerID("app"))
+	tables := []string{"ks.t"}
+
+	t.Run("concurrency", func(t *testing.T) {
+		qq, err := newQueryQuotas([]QueryQuota{{User: "app", MaxConcurrency: 1}})
+		require.NoError(t, err)
+
+		release, err := qq.acquire(ctx, tables)
+		require.NoError(t, err)
+		_, err = qq.acquire(ctx, tables)
+		require.EqualError(t, err, "VT08001: query quota exceeded: user app has exceeded its concurrency quota of 1")
+		assert.Equal(t, vtrpcpb.Code_RESOURCE_EXHAUSTED, vterrors.Code(err))
+		assert.Equal(t, sqlerror.ERUserLimitReached, sqlerror.NewSQLErrorFromError(err).(*sqlerror.SQLError).Number())
+
+		// The queries of the other users are not throttled.
+		_, err = qq.acquire(context.Background(), tables)
+		require.NoError(t, err)
+
+		release(0)
+		_, err = qq.acquire(ctx, tables)
+		require.NoError(t, err)
+	})
+
+	t.Run("qps", func(t *testing.T) {
+		qq, err := newQueryQuotas([]QueryQuota{{Keyspace: "ks", MaxQPS: 2}})
+		require.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			release, err := qq.acquire(ctx, tables)
+			require.NoError(t, err)
+			release(0)
+		}
+		_, err = qq.acquire(ctx, tables)
+		require.EqualError(t, err, "VT08001: query quota exceeded: keyspace ks has exceeded its qps quota of 2")
+	})
+
+	t.Run("rows", func(t *testing.T) {
+		qq, err := newQueryQuotas([]QueryQuota{{Keyspace: "ks", Table: "t", MaxRowsPerSecond: 10}})
+		require.NoError(t, err)
+
+		release, err := qq.acquire(ctx, tables)
+		require.NoError(t, err)
+		release(5)
+		release, err = qq.acquire(ctx, tables)
+		require.NoError(t, err)
+		release(5)
+		_, err = qq.acquire(ctx, tables)
+		require.EqualError(t, err, "VT08001: query quota exceeded: table ks.t has exceeded its rows quota of 10")
+	})
+
+	t.Run("release on throttle", func(t *testing.T) {
+		qq, err := newQueryQuotas([]QueryQuota{{User: "app", MaxConcurrency: 1}, {Keyspace: "ks", MaxConcurrency: 1}})
+		require.NoError(t, err)
+
+		release, err := qq.acquire(context.Background(), tables)
+		require.NoError(t, err)
+		_, err = qq.acquire(ctx, tables)
+		require.ErrorContains(t, err, "keyspace ks has exceeded its concurrency quota of 1")
+		release(0)
+
+		// The user quota was released when the keyspace quota throttled the query.
+		_, err = qq.acquire(ctx, tables)
+		require.NoError(t, err)
+	})
+}
+
+func TestExecutorQueryQuota(t *testing.T) {
+	executor, _, _, sbclookup, ctx := createExecutorEnv(t)
+	executor.quotas, _ = newQueryQuotas([]QueryQuota{{Keyspace: KsTestUnsharded, Table: "main1", MaxRowsPerSecond: 2}})
+
+	sbclookup.SetResults([]*sqltypes.Result{sqltypes.MakeTestResult(sqltypes.MakeTestFields("col", "int64"), "1", "2")})
+	session := NewSafeSession(&vtgatepb.Session{TargetString: "@primary"})
+	_, err := executor.Execute(ctx, nil, "TestExecutorQueryQuota", session, "select * from main1", nil)
+	require.NoError(t, err)
+
+	before := queryQuotaThrottled.Counts()["table TestUnsharded_main1.rows"]
+	_, err = executor.Execute(ctx, nil, "TestExecutorQueryQuota", session, "select * from main1", nil)
+	require.EqualError(t, err, "VT08001: query quota exceeded: table TestUnsharded.main1 has exceeded its rows quota of 2")
+	assert.Equal(t, before+1, queryQuotaThrottled.Counts()["table TestUnsharded_main1.rows"])
+
+	// The quota does not apply to the queries on other tables.
+	_, err = executor.Execute(ctx, nil, "TestExecutorQueryQuota", session, "select 1 from dual", nil)
+	require.NoError(t, err)
+}
@@ -57,5 +57,9 @@ func initQueryLogger(plans cache.Cache) (*streamlog.StreamLogger[*logstats.LogSt
 		}
 	}
 
+	if _, err := startQueryLogSinks(queryLogger, queryLogSinks); err != nil {
+		return nil, err
+	}
+
 	return queryLogger, nil
 }
@@ -0,0 +1,112 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/streamlog"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/vtgate/logstats"
+)
+
+// fileQueryLogSink is the name of the built-in sink writing the query logs to a file,
+// in the --querylog-format format.
+const fileQueryLogSink = "file"
+
+var (
+	queryLogSinkFactoriesMu sync.Mutex
+	queryLogSinkFactories   = make(map[string]QueryLogSinkFactory)
+
+	queryLogSinkErrors = stats.NewCountersWithSingleLabel("QueryLogSinkErrors", "Count of query logs that could not be sent to a sink", "Sink")
+	logQueryLogSink    = logutil.NewThrottledLogger("QueryLogSink", 5*time.Second)
+)
+
+// QueryLogSink receives the logs of the queries executed by VTGate, such as
+// a gRPC stream, a Kafka topic or an OTLP collector.
+type QueryLogSink interface {
+	// Send sends the log of a query. It is called from a single goroutine,
+	// and only with the logs that are not filtered out by the querylog flags.
+	Send(stats *logstats.LogStats) error
+}
+
+// QueryLogSinkFactory creates a sink from the target of its --querylog-sink flag.
+type QueryLogSinkFactory func(target string) (QueryLogSink, error)
+
+// RegisterQueryLogSink registers a query log sink, which can then be enabled
+// with --querylog-sink=<name>:<target>. Sinks are usually registered by plugins.
+func RegisterQueryLogSink(name string, factory QueryLogSinkFactory) {
+	queryLogSinkFactoriesMu.Lock()
+	defer queryLogSinkFactoriesMu.Unlock()
+
+	if _, ok := queryLogSinkFactories[name]; ok || name == fileQueryLogSink {
+		panic(fmt.Sprintf("query log sink %s is already registered", name))
+	}
+	queryLogSinkFactories[name] = factory
+}
+
+// startQueryLogSinks subscribes the sinks of the --querylog-sink flags to the query logger.
+//
+// Returns the channels used for the subscriptions, which can be used to close them.
+func startQueryLogSinks(queryLogger *streamlog.StreamLogger[*logstats.LogStats], sinks []string) ([]chan *logstats.LogStats, error) {
+	var chans []chan *logstats.LogStats
+	for _, sink := range sinks {
+		name, target, ok := strings.Cut(sink, ":")
+		if !ok || target == "" {
+			return chans, fmt.Errorf("invalid query log sink %q, expected <name>:<target>", sink)
+		}
+		if name == fileQueryLogSink {
+			ch, err := queryLogger.LogToFile(target, streamlog.GetFormatter(queryLogger))
+			if err != nil {
+				return chans, err
+			}
+			chans = append(chans, ch)
+			continue
+		}
+
+		queryLogSinkFactoriesMu.Lock()
+		factory, ok := queryLogSinkFactories[name]
+		queryLogSinkFactoriesMu.Unlock()
+		if !ok {
+			return chans, fmt.Errorf("unknown query log sink %q", name)
+		}
+		s, err := factory(target)
+		if err != nil {
+			return chans, fmt.Errorf("cannot create query log sink %q: %v", sink, err)
+		}
+		ch := queryLogger.Subscribe("QueryLogSink:" + name)
+		chans = append(chans, ch)
+		go runQueryLogSink(name, s, ch)
+	}
+	return chans, nil
+}
+
+func runQueryLogSink(name string, sink QueryLogSink, ch chan *logstats.LogStats) {
+	for stats := range ch {
+		if !stats.ShouldEmit() {
+			continue
+		}
+		if err := sink.Send(stats); err != nil {
+			queryLogSinkErrors.Add(name, 1)
+			logQueryLogSink.Warningf("cannot send the query log to sink %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/streamlog"
+	"vitess.io/vitess/go/vt/vtgate/logstats"
+)
+
+type fakeQueryLogSink struct {
+	target string
+	logs   chan *logstats.LogStats
+}
+
+func (sink *fakeQueryLogSink) Send(stats *logstats.LogStats) error {
+	sink.logs <- stats
+	return nil
+}
+
+func TestQueryLogSinks(t *testing.T) {
+	var sink *fakeQueryLogSink
+	RegisterQueryLogSink("fake", func(target string) (QueryLogSink, error) {
+		if target == "bad" {
+			return nil, errors.New("bad target")
+		}
+		sink = &fakeQueryLogSink{target: target, logs: make(chan *logstats.LogStats, 10)}
+		return sink, nil
+	})
+	defer delete(queryLogSinkFactories, "fake")
+	assert.Panics(t, func() {
+		RegisterQueryLogSink("fake", nil)
+	})
+
+	queryLogger := streamlog.New[*logstats.LogStats]("VTGate", 10)
+	_, err := startQueryLogSinks(queryLogger, []string{"fake"})
+	require.EqualError(t, err, `invalid query log sink "fake", expected <name>:<target>`)
+	_, err = startQueryLogSinks(queryLogger, []string{"unknown:target"})
+	require.EqualError(t, err, `unknown query log sink "unknown"`)
+	_, err = startQueryLogSinks(queryLogger, []string{"fake:bad"})
+	require.EqualError(t, err, `cannot create query log sink "fake:bad": bad target`)
+
+	chans, err := startQueryLogSinks(queryLogger, []string{"fake:topic"})
+	require.NoError(t, err)
+	require.Len(t, chans, 1)
+	defer func() {
+		queryLogger.Unsubscribe(chans[0])
+		close(chans[0])
+	}()
+	assert.Equal(t, "topic", sink.target)
+
+	defer streamlog.SetQueryLogFilterTag("")
+	streamlog.SetQueryLogFilterTag("LOG_THIS_QUERY")
+	queryLogger.Send(logstats.NewLogStats(context.Background(), "Execute", "select 1", "", nil))
+	queryLogger.Send(logstats.NewLogStats(context.Background(), "Execute", "select 2 /* LOG_THIS_QUERY */", "", nil))
+
+	select {
+	case stats := <-sink.logs:
+		assert.Equal(t, "select 2 /* LOG_THIS_QUERY */", stats.SQL)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the query log was not sent to the sink")
+	}
+}
+
+func TestQueryLogSampleRate(t *testing.T) {
+	defer streamlog.SetQueryLogSampleRate(1)
+
+	queryLogger := streamlog.New[*logstats.LogStats]("VTGate", 10)
+	e := &Executor{queryLogger: queryLogger}
+	logChan := queryLogger.Subscribe("Test")
+	defer queryLogger.Unsubscribe(logChan)
+
+	streamlog.SetQueryLogSampleRate(0)
+	e.sendQueryLog(logstats.NewLogStats(context.Background(), "Execute", "select 1", "", nil))
+	assert.Nil(t, getQueryLog(logChan))
+
+	streamlog.SetQueryLogSampleRate(1)
+	e.sendQueryLog(logstats.NewLogStats(context.Background(), "Execute", "select 1", "", nil))
+	assert.NotNil(t, getQueryLog(logChan))
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package querylogsink
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"vitess.io/vitess/go/vt/grpcclient"
+	"vitess.io/vitess/go/vt/vtgate"
+	"vitess.io/vitess/go/vt/vtgate/logstats"
+
+	querylogservicepb "vitess.io/vitess/go/vt/proto/querylogservice"
+)
+
+// grpcSink streams the query logs to a QueryLogCollector gRPC service.
+type grpcSink struct {
+	client querylogservicepb.QueryLogCollectorClient
+	stream querylogservicepb.QueryLogCollector_SendQueryLogsClient
+}
+
+func newGRPCSink(target string) (vtgate.QueryLogSink, error) {
+	creds := insecure.NewCredentials()
+	config, err := tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	if config != nil {
+		creds = credentials.NewTLS(config)
+	}
+
+	// The connection fails fast so that the sink doesn't block while the
+	// collector is down, the query logs are dropped instead.
+	cc, err := grpcclient.Dial(target, grpcclient.FailFast(true), grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+	return &grpcSink{client: querylogservicepb.NewQueryLogCollectorClient(cc)}, nil
+}
+
+// Send sends the query log on the stream, which is opened again if the
+// previous one failed.
+func (sink *grpcSink) Send(stats *logstats.LogStats) error {
+	if sink.stream == nil {
+		stream, err := sink.client.SendQueryLogs(context.Background())
+		if err != nil {
+			return err
+		}
+		sink.stream = stream
+	}
+
+	err := sink.stream.Send(queryLog(stats))
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, io.EOF) {
+		// The stream was closed by the collector, the reason is only
+		// returned by the receive.
+		if _, recvErr := sink.stream.CloseAndRecv(); recvErr != nil {
+			err = recvErr
+		}
+	}
+	sink.stream = nil
+	return err
+}
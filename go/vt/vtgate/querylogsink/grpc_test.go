@@ -0,0 +1,119 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package querylogsink
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	querylogdatapb "vitess.io/vitess/go/vt/proto/querylogdata"
+	querylogservicepb "vitess.io/vitess/go/vt/proto/querylogservice"
+)
+
+// fakeCollector records the query logs it receives, and closes each stream
+// with an error after failAfter logs if it is set.
+type fakeCollector struct {
+	querylogservicepb.UnimplementedQueryLogCollectorServer
+
+	logs      chan *querylogdatapb.QueryLog
+	failAfter int
+}
+
+func (collector *fakeCollector) SendQueryLogs(stream querylogservicepb.QueryLogCollector_SendQueryLogsServer) error {
+	for n := 0; ; n++ {
+		if collector.failAfter > 0 && n == collector.failAfter {
+			return status.Error(codes.Unavailable, "collector is shutting down")
+		}
+		log, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&querylogdatapb.SendQueryLogsResponse{})
+		}
+		if err != nil {
+			return err
+		}
+		collector.logs <- log
+	}
+}
+
+func startFakeCollector(t *testing.T, collector *fakeCollector) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	querylogservicepb.RegisterQueryLogCollectorServer(server, collector)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+func TestGRPCSink(t *testing.T) {
+	collector := &fakeCollector{logs: make(chan *querylogdatapb.QueryLog, 10)}
+	sink, err := newGRPCSink(startFakeCollector(t, collector))
+	require.NoError(t, err)
+
+	stats := testLogStats()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, sink.Send(stats))
+	}
+	for i := 0; i < 3; i++ {
+		log := <-collector.logs
+		assert.Equal(t, stats.SQL, log.Sql)
+		assert.Equal(t, "user", log.Username)
+	}
+}
+
+func TestGRPCSinkReopensStream(t *testing.T) {
+	collector := &fakeCollector{logs: make(chan *querylogdatapb.QueryLog, 10), failAfter: 1}
+	sink, err := newGRPCSink(startFakeCollector(t, collector))
+	require.NoError(t, err)
+
+	stats := testLogStats()
+	require.NoError(t, sink.Send(stats))
+	<-collector.logs
+
+	// The collector closes the stream, which fails a later send with its
+	// error. The failed stream is then replaced by a new one.
+	var sendErr error
+	for start := time.Now(); sendErr == nil && time.Since(start) < 10*time.Second; {
+		time.Sleep(10 * time.Millisecond)
+		sendErr = sink.Send(stats)
+	}
+	require.Error(t, sendErr)
+	assert.Equal(t, codes.Unavailable, status.Code(sendErr), sendErr)
+
+	require.NoError(t, sink.Send(stats))
+	log := <-collector.logs
+	assert.Equal(t, stats.SQL, log.Sql)
+}
+
+func TestGRPCSinkUnavailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	sink, err := newGRPCSink(addr)
+	require.NoError(t, err)
+	assert.Error(t, sink.Send(testLogStats()))
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package querylogsink
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"vitess.io/vitess/go/json2"
+	"vitess.io/vitess/go/vt/vtgate"
+	"vitess.io/vitess/go/vt/vtgate/logstats"
+)
+
+// kafkaTimeout is the time to produce a query log, after which it is dropped.
+const kafkaTimeout = 5 * time.Second
+
+// kafkaSink produces the query logs as JSON to a Kafka topic.
+type kafkaSink struct {
+	client *kgo.Client
+}
+
+func newKafkaSink(target string) (vtgate.QueryLogSink, error) {
+	opts, err := kafkaOptions(target)
+	if err != nil {
+		return nil, err
+	}
+	client, err := kgo.NewClient(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &kafkaSink{client: client}, nil
+}
+
+// kafkaOptions returns the options of the Kafka client of the
+// <broker>[,<broker>...]/<topic> target.
+func kafkaOptions(target string) ([]kgo.Opt, error) {
+	brokers, topic, ok := strings.Cut(target, "/")
+	if !ok || brokers == "" || topic == "" {
+		return nil, fmt.Errorf("invalid Kafka target %q, expected <broker>[,<broker>...]/<topic>", target)
+	}
+
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(strings.Split(brokers, ",")...),
+		kgo.ClientID("vtgate"),
+		kgo.DefaultProduceTopic(topic),
+		kgo.RecordDeliveryTimeout(kafkaTimeout),
+	}
+	config, err := tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	if config != nil {
+		opts = append(opts, kgo.DialTLSConfig(config))
+	}
+	return opts, nil
+}
+
+// Send produces the query log, and waits for the brokers to acknowledge it.
+func (sink *kafkaSink) Send(stats *logstats.LogStats) error {
+	value, err := json2.MarshalPB(queryLog(stats))
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), kafkaTimeout)
+	defer cancel()
+	return sink.client.ProduceSync(ctx, &kgo.Record{Value: value}).FirstErr()
+}
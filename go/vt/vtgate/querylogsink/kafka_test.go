@@ -0,0 +1,53 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package querylogsink
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKafkaOptions(t *testing.T) {
+	for _, target := range []string{"", "broker:9092", "broker:9092/", "/topic"} {
+		_, err := kafkaOptions(target)
+		assert.EqualError(t, err, `invalid Kafka target "`+target+`", expected <broker>[,<broker>...]/<topic>`)
+	}
+
+	opts, err := kafkaOptions("broker1:9092,broker2:9092/queries")
+	require.NoError(t, err)
+	assert.Len(t, opts, 4)
+
+	tlsCA = "/nonexistent/ca.pem"
+	defer func() { tlsCA = "" }()
+	_, err = kafkaOptions("broker1:9092/queries")
+	assert.Error(t, err)
+}
+
+func TestKafkaSinkUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	sink, err := newKafkaSink(addr + "/queries")
+	require.NoError(t, err)
+	defer sink.(*kafkaSink).client.Close()
+	assert.Error(t, sink.Send(testLogStats()))
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package querylogsink
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/vtgate"
+	"vitess.io/vitess/go/vt/vtgate/logstats"
+
+	querylogdatapb "vitess.io/vitess/go/vt/proto/querylogdata"
+)
+
+// otlpTimeout is the time to export a query log, after which it is dropped.
+const otlpTimeout = 5 * time.Second
+
+// otlpSink exports the query logs to the OTLP/HTTP logs endpoint of an
+// OpenTelemetry collector.
+type otlpSink struct {
+	url      string
+	client   *http.Client
+	resource *resourcepb.Resource
+}
+
+func newOTLPSink(target string) (vtgate.QueryLogSink, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid OTLP target %q, expected an http or https URL", target)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig, err = tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := []*commonpb.KeyValue{stringAttribute("service.name", "vtgate")}
+	if hostname, err := os.Hostname(); err == nil {
+		attributes = append(attributes, stringAttribute("host.name", hostname))
+	}
+	return &otlpSink{
+		url:      strings.TrimSuffix(target, "/") + "/v1/logs",
+		client:   &http.Client{Transport: transport, Timeout: otlpTimeout},
+		resource: &resourcepb.Resource{Attributes: attributes},
+	}, nil
+}
+
+// Send exports the query log. LogsData has the same encoding as the
+// ExportLogsServiceRequest of the collector.
+func (sink *otlpSink) Send(stats *logstats.LogStats) error {
+	body, err := proto.Marshal(&logspb.LogsData{
+		ResourceLogs: []*logspb.ResourceLogs{{
+			Resource: sink.resource,
+			ScopeLogs: []*logspb.ScopeLogs{{
+				Scope:      &commonpb.InstrumentationScope{Name: "vitess.io/vitess/go/vt/vtgate"},
+				LogRecords: []*logspb.LogRecord{logRecord(queryLog(stats))},
+			}},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := sink.client.Post(sink.url, "application/x-protobuf", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("OTLP collector returned %s", resp.Status)
+	}
+	return nil
+}
+
+// logRecord returns the OTLP log record of a query log. The attributes use
+// the OpenTelemetry semantic conventions of the databases when they exist.
+func logRecord(log *querylogdatapb.QueryLog) *logspb.LogRecord {
+	severity, severityText := logspb.SeverityNumber_SEVERITY_NUMBER_INFO, "INFO"
+	if log.Error != "" {
+		severity, severityText = logspb.SeverityNumber_SEVERITY_NUMBER_ERROR, "ERROR"
+	}
+
+	attributes := []*commonpb.KeyValue{
+		stringAttribute("db.system", "vitess"),
+		stringAttribute("db.statement", log.Sql),
+		stringAttribute("db.operation", log.StmtType),
+		stringAttribute("db.user", log.Username),
+		stringAttribute("client.address", log.RemoteAddr),
+		stringAttribute("vitess.method", log.Method),
+		stringAttribute("vitess.immediate_caller", log.ImmediateCaller),
+		stringAttribute("vitess.effective_caller", log.EffectiveCaller),
+		stringAttribute("vitess.tablet_type", log.TabletType),
+		intAttribute("vitess.shard_queries", log.ShardQueries),
+		intAttribute("vitess.rows_affected", log.RowsAffected),
+		intAttribute("vitess.rows_returned", log.RowsReturned),
+		intAttribute("vitess.duration_ns", uint64(protoutil.TimeFromProto(log.EndTime).Sub(protoutil.TimeFromProto(log.StartTime)))),
+	}
+	if log.Error != "" {
+		attributes = append(attributes, stringAttribute("vitess.error", log.Error))
+	}
+	if len(log.TablesUsed) > 0 {
+		tables := make([]*commonpb.AnyValue, 0, len(log.TablesUsed))
+		for _, table := range log.TablesUsed {
+			tables = append(tables, &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: table}})
+		}
+		attributes = append(attributes, &commonpb.KeyValue{
+			Key:   "vitess.tables_used",
+			Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{Values: tables}}},
+		})
+	}
+
+	return &logspb.LogRecord{
+		TimeUnixNano:         uint64(protoutil.TimeFromProto(log.StartTime).UnixNano()),
+		ObservedTimeUnixNano: uint64(protoutil.TimeFromProto(log.EndTime).UnixNano()),
+		SeverityNumber:       severity,
+		SeverityText:         severityText,
+		Body:                 &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: log.Sql}},
+		Attributes:           attributes,
+	}
+}
+
+func stringAttribute(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}}
+}
+
+func intAttribute(key string, value uint64) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(value)}}}
+}
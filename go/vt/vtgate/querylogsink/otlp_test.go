@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package querylogsink
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestOTLPSink(t *testing.T) {
+	requests := make(chan *logspb.LogsData, 1)
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/logs", r.URL.Path)
+		assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		logs := &logspb.LogsData{}
+		require.NoError(t, proto.Unmarshal(body, logs))
+		requests <- logs
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	_, err := newOTLPSink("localhost:4318")
+	assert.EqualError(t, err, `invalid OTLP target "localhost:4318", expected an http or https URL`)
+
+	sink, err := newOTLPSink(server.URL + "/")
+	require.NoError(t, err)
+	require.NoError(t, sink.Send(testLogStats()))
+
+	logs := <-requests
+	require.Len(t, logs.ResourceLogs, 1)
+	assert.Equal(t, "service.name", logs.ResourceLogs[0].Resource.Attributes[0].Key)
+	assert.Equal(t, "vtgate", logs.ResourceLogs[0].Resource.Attributes[0].Value.GetStringValue())
+	require.Len(t, logs.ResourceLogs[0].ScopeLogs, 1)
+	require.Len(t, logs.ResourceLogs[0].ScopeLogs[0].LogRecords, 1)
+
+	record := logs.ResourceLogs[0].ScopeLogs[0].LogRecords[0]
+	assert.EqualValues(t, 1700000000000000500, record.TimeUnixNano)
+	assert.Equal(t, logspb.SeverityNumber_SEVERITY_NUMBER_ERROR, record.SeverityNumber)
+	assert.Equal(t, "ERROR", record.SeverityText)
+	assert.Equal(t, "select * from t where id = :id", record.Body.GetStringValue())
+
+	attributes := make(map[string]*commonpb.AnyValue)
+	for _, attribute := range record.Attributes {
+		attributes[attribute.Key] = attribute.Value
+	}
+	assert.Equal(t, "user", attributes["db.user"].GetStringValue())
+	assert.Equal(t, "SELECT", attributes["db.operation"].GetStringValue())
+	assert.Equal(t, "query failed", attributes["vitess.error"].GetStringValue())
+	assert.EqualValues(t, 10, attributes["vitess.rows_returned"].GetIntValue())
+	assert.EqualValues(t, 2000000, attributes["vitess.duration_ns"].GetIntValue())
+	assert.Equal(t, "ks.t", attributes["vitess.tables_used"].GetArrayValue().Values[0].GetStringValue())
+
+	status = http.StatusServiceUnavailable
+	assert.EqualError(t, sink.Send(testLogStats()), "OTLP collector returned 503 Service Unavailable")
+	<-requests
+}
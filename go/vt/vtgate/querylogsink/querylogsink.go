@@ -0,0 +1,90 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package querylogsink registers the gRPC stream, Kafka and OTLP query log
+// sinks of VTGate, enabled with --querylog-sink=<name>:<target>:
+//
+//   - grpc:<address> streams the query logs to a QueryLogCollector gRPC service.
+//   - kafka:<broker>[,<broker>...]/<topic> produces them as JSON to a Kafka topic.
+//   - otlp:<url> exports them as OTLP logs to the /v1/logs HTTP endpoint of an
+//     OpenTelemetry collector, e.g. otlp:http://localhost:4318.
+package querylogsink
+
+import (
+	"crypto/tls"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vtgate"
+	"vitess.io/vitess/go/vt/vtgate/logstats"
+	"vitess.io/vitess/go/vt/vttls"
+
+	querylogdatapb "vitess.io/vitess/go/vt/proto/querylogdata"
+)
+
+var (
+	tlsCA         string
+	tlsCert       string
+	tlsKey        string
+	tlsServerName string
+)
+
+func registerFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&tlsCA, "querylog-sink-tls-ca", tlsCA, "CA to verify the query log sinks with. The sinks use TLS when it is set.")
+	fs.StringVar(&tlsCert, "querylog-sink-tls-cert", tlsCert, "Client certificate to authenticate to the query log sinks with.")
+	fs.StringVar(&tlsKey, "querylog-sink-tls-key", tlsKey, "Key of the client certificate to authenticate to the query log sinks with.")
+	fs.StringVar(&tlsServerName, "querylog-sink-tls-server-name", tlsServerName, "Server name to verify the certificates of the query log sinks with, if it differs from their host name.")
+}
+
+func init() {
+	servenv.OnParseFor("vtgate", registerFlags)
+
+	vtgate.RegisterQueryLogSink("grpc", newGRPCSink)
+	vtgate.RegisterQueryLogSink("kafka", newKafkaSink)
+	vtgate.RegisterQueryLogSink("otlp", newOTLPSink)
+}
+
+// tlsConfig returns the TLS config of the sinks, or nil if they don't use TLS.
+func tlsConfig() (*tls.Config, error) {
+	if tlsCA == "" && (tlsCert == "" || tlsKey == "") {
+		return nil, nil
+	}
+	return vttls.ClientConfig(vttls.VerifyIdentity, tlsCert, tlsKey, tlsCA, "", tlsServerName, tls.VersionTLS12)
+}
+
+// queryLog converts the log of a query to the message sent to the sinks.
+func queryLog(stats *logstats.LogStats) *querylogdatapb.QueryLog {
+	remoteAddr, username := stats.RemoteAddrUsername()
+	return &querylogdatapb.QueryLog{
+		Method:          stats.Method,
+		RemoteAddr:      remoteAddr,
+		Username:        username,
+		ImmediateCaller: stats.ImmediateCaller(),
+		EffectiveCaller: stats.EffectiveCaller(),
+		StartTime:       protoutil.TimeToProto(stats.StartTime),
+		EndTime:         protoutil.TimeToProto(stats.EndTime),
+		Sql:             stats.SQL,
+		StmtType:        stats.StmtType,
+		TabletType:      stats.TabletType,
+		ShardQueries:    stats.ShardQueries,
+		RowsAffected:    stats.RowsAffected,
+		RowsReturned:    stats.RowsReturned,
+		Error:           stats.ErrorStr(),
+		TablesUsed:      stats.TablesUsed,
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package querylogsink
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/callinfo"
+	"vitess.io/vitess/go/vt/callinfo/fakecallinfo"
+	"vitess.io/vitess/go/vt/vtgate/logstats"
+
+	querylogdatapb "vitess.io/vitess/go/vt/proto/querylogdata"
+	vttimepb "vitess.io/vitess/go/vt/proto/vttime"
+)
+
+func testLogStats() *logstats.LogStats {
+	ctx := callinfo.NewContext(context.Background(), &fakecallinfo.FakeCallInfo{Remote: "10.0.0.1", User: "user"})
+	ctx = callerid.NewContext(ctx, callerid.NewEffectiveCallerID("effective", "", ""), callerid.NewImmediateCallerID("immediate"))
+	stats := logstats.NewLogStats(ctx, "Execute", "select * from t where id = :id", "uuid", nil)
+	stats.StartTime = time.Unix(1700000000, 500)
+	stats.EndTime = stats.StartTime.Add(2 * time.Millisecond)
+	stats.StmtType = "SELECT"
+	stats.TabletType = "PRIMARY"
+	stats.ShardQueries = 2
+	stats.RowsReturned = 10
+	stats.TablesUsed = []string{"ks.t"}
+	stats.Error = errors.New("query failed")
+	return stats
+}
+
+func TestQueryLog(t *testing.T) {
+	want := &querylogdatapb.QueryLog{
+		Method:          "Execute",
+		RemoteAddr:      "10.0.0.1",
+		Username:        "user",
+		ImmediateCaller: "immediate",
+		EffectiveCaller: "effective",
+		StartTime:       &vttimepb.Time{Seconds: 1700000000, Nanoseconds: 500},
+		EndTime:         &vttimepb.Time{Seconds: 1700000000, Nanoseconds: 2000500},
+		Sql:             "select * from t where id = :id",
+		StmtType:        "SELECT",
+		TabletType:      "PRIMARY",
+		ShardQueries:    2,
+		RowsReturned:    10,
+		Error:           "query failed",
+		TablesUsed:      []string{"ks.t"},
+	}
+	got := queryLog(testLogStats())
+	assert.True(t, proto.Equal(want, got), "got %v, want %v", got, want)
+}
+
+func TestTLSConfig(t *testing.T) {
+	config, err := tlsConfig()
+	require.NoError(t, err)
+	assert.Nil(t, config)
+
+	tlsCA = "/nonexistent/ca.pem"
+	defer func() { tlsCA = "" }()
+	_, err = tlsConfig()
+	assert.Error(t, err)
+}
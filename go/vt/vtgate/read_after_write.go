@@ -0,0 +1,165 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// readAfterWriteGTIDQuery fetches the GTID set executed by a primary after a write.
+const readAfterWriteGTIDQuery = "select @@global.gtid_executed"
+
+// readAfterWriteToken is the parsed read_after_write_gtid of a session. It maps
+// a keyspace/shard to the GTID set a tablet of the shard must have executed
+// before it answers the reads of the session.
+//
+// The token is a list of keyspace/shard@gtid_set entries separated by ';', for example
+// "commerce/0@3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5". An entry without a
+// keyspace/shard applies to every shard.
+type readAfterWriteToken map[string]string
+
+func parseReadAfterWriteToken(token string) readAfterWriteToken {
+	t := readAfterWriteToken{}
+	for _, entry := range strings.Split(token, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		shard, gtidSet, ok := strings.Cut(entry, "@")
+		if !ok {
+			shard, gtidSet = "", entry
+		}
+		t[shard] = gtidSet
+	}
+	return t
+}
+
+// String returns the token as a string, with its entries sorted by keyspace/shard.
+func (t readAfterWriteToken) String() string {
+	shards := make([]string, 0, len(t))
+	for shard := range t {
+		shards = append(shards, shard)
+	}
+	sort.Strings(shards)
+
+	entries := make([]string, 0, len(t))
+	for _, shard := range shards {
+		if shard == "" {
+			entries = append(entries, t[shard])
+			continue
+		}
+		entries = append(entries, shard+"@"+t[shard])
+	}
+	return strings.Join(entries, ";")
+}
+
+// gtidSet returns the GTID set a tablet of the target has to wait for.
+func (t readAfterWriteToken) gtidSet(target *querypb.Target) string {
+	if gtidSet, ok := t[topoproto.KeyspaceShardString(target.Keyspace, target.Shard)]; ok {
+		return gtidSet
+	}
+	return t[""]
+}
+
+// merge adds the GTID set executed by the primary of the target to the token.
+func (t readAfterWriteToken) merge(target *querypb.Target, gtidSet string) {
+	// gtid_executed separates the sets of the different servers by a newline.
+	gtidSet = strings.Join(strings.Fields(gtidSet), "")
+	shard := topoproto.KeyspaceShardString(target.Keyspace, target.Shard)
+	if prev, ok := t[shard]; ok {
+		gtidSet = unionGTIDSets(prev, gtidSet)
+	}
+	t[shard] = gtidSet
+}
+
+// unionGTIDSets returns the union of two MySQL 5.6 GTID sets, or the second
+// one if the first can't be parsed.
+func unionGTIDSets(a, b string) string {
+	setA, err := replication.ParseMysql56GTIDSet(a)
+	if err != nil {
+		return b
+	}
+	setB, err := replication.ParseMysql56GTIDSet(b)
+	if err != nil {
+		return b
+	}
+	return setA.Union(setB).String()
+}
+
+// readAfterWriteOptions returns the options of a query on the target, with the
+// GTID set the tablet must wait for if the read_after_write_gtid of the session
+// has one for it. Queries on a primary never wait, as the token of a session
+// only holds the GTID sets executed by the primaries.
+func readAfterWriteOptions(session *SafeSession, target *querypb.Target, opts *querypb.ExecuteOptions) *querypb.ExecuteOptions {
+	if session == nil || target == nil || target.TabletType == topodatapb.TabletType_PRIMARY {
+		return opts
+	}
+	raw := session.GetReadAfterWrite()
+	if raw.GetReadAfterWriteGtid() == "" {
+		return opts
+	}
+	gtidSet := parseReadAfterWriteToken(raw.ReadAfterWriteGtid).gtidSet(target)
+	if gtidSet == "" {
+		return opts
+	}
+
+	if opts == nil {
+		opts = &querypb.ExecuteOptions{}
+	} else {
+		opts = proto.Clone(opts).(*querypb.ExecuteOptions)
+	}
+	opts.WaitForGtidSet = gtidSet
+	opts.WaitForGtidSetTimeout = raw.ReadAfterWriteTimeout
+	return opts
+}
+
+// updateReadAfterWriteToken merges the GTID sets executed by the primaries
+// the session wrote to into the read_after_write_gtid of the session. The
+// writes are only tracked if the session sets session_track_gtids.
+func (e *Executor) updateReadAfterWriteToken(ctx context.Context, safeSession *SafeSession) {
+	targets := safeSession.takeWrittenTargets()
+	if len(targets) == 0 {
+		return
+	}
+
+	token := parseReadAfterWriteToken(safeSession.GetReadAfterWrite().GetReadAfterWriteGtid())
+	for _, target := range targets {
+		qr, err := e.scatterConn.gateway.Execute(ctx, target, readAfterWriteGTIDQuery, nil, 0, 0, nil)
+		if err == nil && len(qr.Rows) != 1 {
+			err = fmt.Errorf("unexpected result for %s: %v", readAfterWriteGTIDQuery, qr.Rows)
+		}
+		if err != nil {
+			// The write succeeded, so its result is returned with a warning
+			// rather than an error.
+			safeSession.RecordWarning(&querypb.QueryWarning{Message: fmt.Sprintf("failed to track the GTID set of %s: %v", topoproto.KeyspaceShardString(target.Keyspace, target.Shard), err)})
+			continue
+		}
+		token.merge(target, qr.Rows[0][0].ToString())
+	}
+	safeSession.SetReadAfterWriteGTID(token.String())
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"vitess.io/vitess/go/test/utils"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+func TestReadAfterWriteToken(t *testing.T) {
+	const (
+		uuid1 = "3e11fa47-71ca-11e1-9e33-c80aa9429562"
+		uuid2 = "4e11fa47-71ca-11e1-9e33-c80aa9429562"
+	)
+	replica := func(keyspace, shard string) *querypb.Target {
+		return &querypb.Target{Keyspace: keyspace, Shard: shard, TabletType: topodatapb.TabletType_REPLICA}
+	}
+
+	token := parseReadAfterWriteToken("ks/-80@" + uuid1 + ":1-5; " + uuid2 + ":1-3")
+	assert.Equal(t, uuid1+":1-5", token.gtidSet(replica("ks", "-80")))
+	// the entry without a keyspace/shard applies to the other shards
+	assert.Equal(t, uuid2+":1-3", token.gtidSet(replica("ks", "80-")))
+	assert.Equal(t, uuid2+":1-3;ks/-80@"+uuid1+":1-5", token.String())
+
+	token.merge(replica("ks", "-80"), uuid1+":6-7,\n"+uuid2+":1-2")
+	token.merge(replica("ks", "80-"), uuid2+":1-4")
+	assert.Equal(t, uuid2+":1-3;ks/-80@"+uuid1+":1-7,"+uuid2+":1-2;ks/80-@"+uuid2+":1-4", token.String())
+
+	assert.Empty(t, parseReadAfterWriteToken("").String())
+}
+
+func TestReadAfterWriteOptions(t *testing.T) {
+	gtidSet := "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5"
+	options := &querypb.ExecuteOptions{Workload: querypb.ExecuteOptions_OLAP}
+	session := NewSafeSession(&vtgatepb.Session{
+		Options: options,
+		ReadAfterWrite: &vtgatepb.ReadAfterWrite{
+			ReadAfterWriteGtid:    "ks/-80@" + gtidSet,
+			ReadAfterWriteTimeout: 2,
+		},
+	})
+
+	got := readAfterWriteOptions(session, &querypb.Target{Keyspace: "ks", Shard: "-80", TabletType: topodatapb.TabletType_REPLICA}, options)
+	utils.MustMatch(t, &querypb.ExecuteOptions{Workload: querypb.ExecuteOptions_OLAP, WaitForGtidSet: gtidSet, WaitForGtidSetTimeout: 2}, got)
+	// the options of the session are left untouched
+	assert.Empty(t, options.WaitForGtidSet)
+
+	// primaries and shards without a GTID set don't wait
+	assert.Same(t, options, readAfterWriteOptions(session, &querypb.Target{Keyspace: "ks", Shard: "-80", TabletType: topodatapb.TabletType_PRIMARY}, options))
+	assert.Same(t, options, readAfterWriteOptions(session, &querypb.Target{Keyspace: "ks", Shard: "80-", TabletType: topodatapb.TabletType_REPLICA}, options))
+}
@@ -0,0 +1,391 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/cache"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/log"
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+var (
+	resultCacheInvalidations = stats.NewCountersWithSingleLabel("ResultCacheInvalidations", "Result cache invalidations, by keyspace", "Keyspace")
+
+	// resultCacheRetryDelay is the time to wait before restarting a failed
+	// VStream of the changes to the cached tables.
+	resultCacheRetryDelay = 5 * time.Second
+)
+
+// resultCacheVStreamer streams the changes of keyspace that match filter
+// to send, until ctx is done or an error occurs.
+type resultCacheVStreamer func(ctx context.Context, keyspace string, filter *binlogdatapb.Filter, send func([]*binlogdatapb.VEvent) error) error
+
+// resultCache caches the results of the read-only queries on the tables whose
+// VSchema sets a result_cache. The results are invalidated by the changes to
+// their tables, as reported by a VStream of each keyspace with cached tables.
+// A keyspace's results are only cached while its VStream is running.
+type resultCache struct {
+	entries cache.Cache
+
+	mu sync.Mutex
+	// versions counts the changes to each table, by qualified name. A cached
+	// result is stale as soon as the version of one of its tables changes.
+	versions map[string]uint64
+	// tables lists the cached tables of each keyspace.
+	tables map[string][]string
+	// watchers holds the running VStreams, by keyspace.
+	watchers map[string]*resultCacheWatcher
+	vstream  resultCacheVStreamer
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+// resultCacheWatcher is the VStream of the changes to the cached tables of
+// a keyspace.
+type resultCacheWatcher struct {
+	tables []string
+	cancel context.CancelFunc
+	// streaming is set once the first events of the stream are received.
+	streaming bool
+}
+
+// cachedResult is a result in the result cache.
+type cachedResult struct {
+	result *sqltypes.Result
+	// tables are the qualified tables of the query, and versions their
+	// versions when it ran.
+	tables   []string
+	versions []uint64
+	// expires is the time after which the result is stale. It is zero if the
+	// result never expires.
+	expires time.Time
+}
+
+func newResultCache(maxMemoryUsage int64) *resultCache {
+	// The cache is sized by the memory used by the cached results.
+	entries := cache.NewDefaultCacheImpl(nil)
+	if maxMemoryUsage > 0 {
+		entries = cache.NewLRUCache(maxMemoryUsage, func(val any) int64 {
+			return val.(*cachedResult).result.CachedSize(true)
+		})
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &resultCache{
+		entries:  entries,
+		versions: make(map[string]uint64),
+		tables:   make(map[string][]string),
+		watchers: make(map[string]*resultCacheWatcher),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// start starts streaming the changes to the cached tables with vstream.
+func (rc *resultCache) start(vstream resultCacheVStreamer) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.vstream = vstream
+	rc.updateWatchersLocked()
+}
+
+// close stops all the VStreams and drops the cached results.
+func (rc *resultCache) close() {
+	rc.cancel()
+	rc.entries.Close()
+}
+
+// enabled returns whether the result cache can hold any result.
+func (rc *resultCache) enabled() bool {
+	return rc.entries.MaxCapacity() > 0
+}
+
+// setVSchema drops the cached results, and watches the tables of vschema
+// that have a result cache.
+func (rc *resultCache) setVSchema(vschema *vindexes.VSchema) {
+	tables := make(map[string][]string)
+	if vschema != nil {
+		for ksName, ks := range vschema.Keyspaces {
+			for name, table := range ks.Tables {
+				if table.ResultCache != nil {
+					tables[ksName] = append(tables[ksName], name)
+				}
+			}
+			sort.Strings(tables[ksName])
+		}
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.entries.Clear()
+	rc.tables = tables
+	rc.updateWatchersLocked()
+}
+
+// updateWatchersLocked starts and stops the VStreams, so that there is one
+// per keyspace with cached tables.
+func (rc *resultCache) updateWatchersLocked() {
+	if rc.vstream == nil || !rc.enabled() {
+		return
+	}
+	for keyspace, w := range rc.watchers {
+		if tables, ok := rc.tables[keyspace]; !ok || !slices.Equal(tables, w.tables) {
+			w.cancel()
+			delete(rc.watchers, keyspace)
+		}
+	}
+	for keyspace, tables := range rc.tables {
+		if len(tables) == 0 || rc.watchers[keyspace] != nil {
+			continue
+		}
+		ctx, cancel := context.WithCancel(rc.ctx)
+		w := &resultCacheWatcher{tables: tables, cancel: cancel}
+		rc.watchers[keyspace] = w
+		go rc.watch(ctx, keyspace, w)
+	}
+}
+
+// watch streams the changes to the tables of w, invalidating the cached
+// results that depend on them, until ctx is done.
+func (rc *resultCache) watch(ctx context.Context, keyspace string, w *resultCacheWatcher) {
+	filter := &binlogdatapb.Filter{}
+	for _, table := range w.tables {
+		filter.Rules = append(filter.Rules, &binlogdatapb.Rule{Match: table})
+	}
+	for {
+		err := rc.vstream(ctx, keyspace, filter, func(events []*binlogdatapb.VEvent) error {
+			rc.mu.Lock()
+			defer rc.mu.Unlock()
+			w.streaming = true
+			for _, event := range events {
+				switch event.Type {
+				case binlogdatapb.VEventType_ROW:
+					rc.invalidateLocked(keyspace, event.RowEvent.TableName)
+				case binlogdatapb.VEventType_FIELD:
+					rc.invalidateLocked(keyspace, event.FieldEvent.TableName)
+				case binlogdatapb.VEventType_DDL:
+					rc.invalidateLocked(keyspace, w.tables...)
+				}
+			}
+			return nil
+		})
+
+		// Changes may be missed until the stream is restarted, so the
+		// cached results of the keyspace can't be trusted anymore.
+		rc.mu.Lock()
+		w.streaming = false
+		rc.invalidateLocked(keyspace, w.tables...)
+		rc.mu.Unlock()
+
+		if ctx.Err() != nil {
+			return
+		}
+		log.Warningf("Result cache: VStream of keyspace %s stopped, restarting in %v: %v", keyspace, resultCacheRetryDelay, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(resultCacheRetryDelay):
+		}
+	}
+}
+
+// invalidate marks the cached results of the given tables of keyspace as stale.
+func (rc *resultCache) invalidate(keyspace string, tables ...string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.invalidateLocked(keyspace, tables...)
+}
+
+// invalidateTables marks the cached results of the given qualified tables as
+// stale.
+func (rc *resultCache) invalidateTables(tables []string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for _, table := range tables {
+		keyspace, name, _ := strings.Cut(table, ".")
+		rc.invalidateLocked(keyspace, name)
+	}
+}
+
+func (rc *resultCache) invalidateLocked(keyspace string, tables ...string) {
+	for _, table := range tables {
+		rc.versions[keyspace+"."+table]++
+	}
+	resultCacheInvalidations.Add(keyspace, int64(len(tables)))
+}
+
+// versionsLocked returns the versions of the given qualified tables, and
+// whether all of them are cached tables of a keyspace whose changes are
+// being streamed.
+func (rc *resultCache) versionsLocked(tables []string) ([]uint64, bool) {
+	versions := make([]uint64, len(tables))
+	for i, table := range tables {
+		keyspace, _, _ := strings.Cut(table, ".")
+		w := rc.watchers[keyspace]
+		if w == nil || !w.streaming {
+			return nil, false
+		}
+		versions[i] = rc.versions[table]
+	}
+	return versions, true
+}
+
+// resultCacheEntry returns the key and TTL of the result of plan in the
+// result cache, or false if the result can't be cached.
+func (e *Executor) resultCacheEntry(ctx context.Context, safeSession *SafeSession, plan *engine.Plan, vcursor *vcursorImpl, bindVars map[string]*querypb.BindVariable) (string, time.Duration, bool) {
+	if !e.resultCache.enabled() || plan.Type != sqlparser.StmtSelect || len(plan.TablesUsed) == 0 {
+		return "", 0, false
+	}
+	if plan.BindVarNeeds != nil && plan.BindVarNeeds.HasRewrites() {
+		return "", 0, false
+	}
+	if safeSession.InTransaction() || safeSession.InReservedConn() || !safeSession.cachePlan() {
+		return "", 0, false
+	}
+	// The replicas may lag behind the changes streamed to invalidate the
+	// cached results, so only the results read from the primaries are cached.
+	if vcursor.TabletType() != topodatapb.TabletType_PRIMARY {
+		return "", 0, false
+	}
+	// The system variables of the session may change the results.
+	hasSystemVariables := false
+	safeSession.GetSystemVariables(func(string, string) {
+		hasSystemVariables = true
+	})
+	if hasSystemVariables {
+		return "", 0, false
+	}
+
+	var ttl time.Duration
+	vschema := e.VSchema()
+	for _, name := range plan.TablesUsed {
+		keyspace, tableName, _ := strings.Cut(name, ".")
+		ks := vschema.Keyspaces[keyspace]
+		if ks == nil {
+			return "", 0, false
+		}
+		table := ks.Tables[tableName]
+		if table == nil || table.ResultCache == nil {
+			return "", 0, false
+		}
+		if table.ResultCache.TTL > 0 && (ttl == 0 || table.ResultCache.TTL < ttl) {
+			ttl = table.ResultCache.TTL
+		}
+	}
+
+	names := make([]string, 0, len(bindVars))
+	for name := range bindVars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	hash := sha256.New()
+	hash.Write([]byte(e.hashPlan(ctx, vcursor, plan.Original)))
+	// What a query returns may depend on who runs it, through the table ACLs
+	// of the tablets for instance, so the callers don't share their results.
+	writeString := func(s string) {
+		fmt.Fprintf(hash, "%d:%s", len(s), s)
+	}
+	immediate := callerid.ImmediateCallerIDFromContext(ctx)
+	hash.Write([]byte("+immediate:"))
+	writeString(immediate.GetUsername())
+	for _, group := range immediate.GetGroups() {
+		writeString(group)
+	}
+	effective := callerid.EffectiveCallerIDFromContext(ctx)
+	hash.Write([]byte("+effective:"))
+	writeString(effective.GetPrincipal())
+	writeString(effective.GetComponent())
+	writeString(effective.GetSubcomponent())
+	for _, group := range effective.GetGroups() {
+		writeString(group)
+	}
+	for _, name := range names {
+		bv := bindVars[name]
+		fmt.Fprintf(hash, "+%s:%s:%d:", name, bv.Type, len(bv.Value))
+		hash.Write(bv.Value)
+		for _, v := range bv.Values {
+			fmt.Fprintf(hash, ",%s:%d:", v.Type, len(v.Value))
+			hash.Write(v.Value)
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), ttl, true
+}
+
+// get returns a copy of the cached result of key, if it is still valid.
+func (rc *resultCache) get(key string) (*sqltypes.Result, bool) {
+	val, ok := rc.entries.Get(key)
+	if !ok {
+		return nil, false
+	}
+	entry := val.(*cachedResult)
+
+	rc.mu.Lock()
+	versions, ok := rc.versionsLocked(entry.tables)
+	rc.mu.Unlock()
+	if !ok || !slices.Equal(versions, entry.versions) || (!entry.expires.IsZero() && time.Now().After(entry.expires)) {
+		rc.entries.Delete(key)
+		return nil, false
+	}
+	return entry.result.Copy(), true
+}
+
+// snapshot returns the current versions of tables, to be passed to set once
+// their query ran, or false if the result of the query can't be cached.
+func (rc *resultCache) snapshot(tables []string) ([]uint64, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.versionsLocked(tables)
+}
+
+// set caches result as the result of key, unless one of tables changed since
+// the versions were taken.
+func (rc *resultCache) set(key string, tables []string, versions []uint64, ttl time.Duration, result *sqltypes.Result) {
+	rc.mu.Lock()
+	current, ok := rc.versionsLocked(tables)
+	rc.mu.Unlock()
+	if !ok || !slices.Equal(current, versions) {
+		return
+	}
+
+	entry := &cachedResult{
+		result:   result.Copy(),
+		tables:   tables,
+		versions: versions,
+	}
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+	rc.entries.Set(key, entry)
+}
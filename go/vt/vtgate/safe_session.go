@@ -18,6 +18,7 @@ package vtgate
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -66,6 +67,10 @@ type (
 
 		logging *executeLogger
 
+		// writtenTargets are the primaries written to since the read after write
+		// token of the session was last updated, if the session tracks its GTIDs.
+		writtenTargets []*querypb.Target
+
 		*vtgatepb.Session
 	}
 
@@ -189,6 +194,9 @@ func (session *SafeSession) ResetAll() {
 	session.PostSessions = nil
 	session.LockSession = nil
 	session.AdvisoryLock = nil
+	// The temporary tables are dropped with the reserved connections they were created on.
+	session.TempTables = nil
+	session.PinnedReservedConn = false
 }
 
 func (session *SafeSession) resetCommonLocked() {
@@ -600,6 +608,46 @@ func (session *SafeSession) SetReservedConn(reservedConn bool) {
 	session.Session.InReservedConn = reservedConn
 }
 
+// PinReservedConn marks the session as needing reserved connections that hold state
+// which cannot be replayed on another connection, so that they are never multiplexed.
+func (session *SafeSession) PinReservedConn() {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.Session.InReservedConn = true
+	session.Session.PinnedReservedConn = true
+}
+
+// MultiplexedShardSessions returns the shard sessions of the reserved connections that can be
+// released after a query, because they only hold the system variables of the session: the session
+// is not in a transaction and did not pin its reserved connections.
+func (session *SafeSession) MultiplexedShardSessions() []*vtgatepb.Session_ShardSession {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if !session.Session.InReservedConn || session.Session.PinnedReservedConn || session.Session.InTransaction {
+		return nil
+	}
+	var shardSessions []*vtgatepb.Session_ShardSession
+	for _, ss := range session.ShardSessions {
+		if ss.ReservedId != 0 && ss.TransactionId == 0 {
+			shardSessions = append(shardSessions, ss)
+		}
+	}
+	return shardSessions
+}
+
+// RemoveReleasedShardSessions removes the shard sessions whose reserved connection was released.
+// The session stays in reserved connection mode, so that its next queries reserve new connections.
+func (session *SafeSession) RemoveReleasedShardSessions() {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.ShardSessions = slices.DeleteFunc(session.ShardSessions, func(ss *vtgatepb.Session_ShardSession) bool {
+		return ss.ReservedId == 0 && ss.TransactionId == 0
+	})
+	if len(session.ShardSessions) == 0 {
+		session.ShardSessions = nil
+	}
+}
+
 // SetPreQueries returns the prequeries that need to be run when reserving a connection
 func (session *SafeSession) SetPreQueries() []string {
 	// extract keys
@@ -781,6 +829,48 @@ func (session *SafeSession) SetSessionTrackGtids(enable bool) {
 	session.ReadAfterWrite.SessionTrackGtids = enable
 }
 
+// RecordWrite records a write on the target, if the session tracks its GTIDs.
+func (session *SafeSession) RecordWrite(target *querypb.Target) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.recordWriteLocked(target)
+}
+
+// RecordTransactionWrites records the targets of the transaction that is about
+// to be committed as written, if the session tracks its GTIDs.
+func (session *SafeSession) RecordTransactionWrites() {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	for _, shardSessions := range [][]*vtgatepb.Session_ShardSession{session.PreSessions, session.ShardSessions, session.PostSessions} {
+		for _, shardSession := range shardSessions {
+			if shardSession.TransactionId != 0 {
+				session.recordWriteLocked(shardSession.Target)
+			}
+		}
+	}
+}
+
+func (session *SafeSession) recordWriteLocked(target *querypb.Target) {
+	if !session.GetReadAfterWrite().GetSessionTrackGtids() || target.GetTabletType() != topodatapb.TabletType_PRIMARY {
+		return
+	}
+	for _, written := range session.writtenTargets {
+		if written.Keyspace == target.Keyspace && written.Shard == target.Shard {
+			return
+		}
+	}
+	session.writtenTargets = append(session.writtenTargets, target)
+}
+
+// takeWrittenTargets returns the targets written to since the last call.
+func (session *SafeSession) takeWrittenTargets() []*querypb.Target {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	targets := session.writtenTargets
+	session.writtenTargets = nil
+	return targets
+}
+
 func removeShard(tabletAlias *topodatapb.TabletAlias, sessions []*vtgatepb.Session_ShardSession) ([]*vtgatepb.Session_ShardSession, error) {
 	idx := -1
 	for i, session := range sessions {
@@ -869,6 +959,47 @@ func (session *SafeSession) RemoveInternalSavepoint() {
 	}
 }
 
+// AddTempTable records a temporary table created by the session in a sharded keyspace.
+func (session *SafeSession) AddTempTable(keyspace, table string) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	name := tempTableName(keyspace, table)
+	if slices.Contains(session.TempTables, name) {
+		return
+	}
+	session.TempTables = append(session.TempTables, name)
+}
+
+// RemoveTempTable forgets a temporary table dropped by the session from a sharded keyspace.
+func (session *SafeSession) RemoveTempTable(keyspace, table string) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	name := tempTableName(keyspace, table)
+	session.TempTables = slices.DeleteFunc(session.TempTables, func(tempTable string) bool {
+		return tempTable == name
+	})
+	if len(session.TempTables) == 0 {
+		session.TempTables = nil
+	}
+}
+
+// HasTempTable returns true if the session has created the temporary table in the sharded keyspace.
+func (session *SafeSession) HasTempTable(keyspace, table string) bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	if len(session.TempTables) == 0 {
+		return false
+	}
+	return slices.Contains(session.TempTables, tempTableName(keyspace, table))
+}
+
+func tempTableName(keyspace, table string) string {
+	return sqlparser.String(sqlparser.NewTableNameWithQualifier(table, keyspace))
+}
+
 // HasAdvisoryLock returns if any advisory lock is taken
 func (session *SafeSession) HasAdvisoryLock() bool {
 	session.mu.Lock()
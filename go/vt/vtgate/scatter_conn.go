@@ -68,7 +68,7 @@ type shardActionFunc func(rs *srvtopo.ResolvedShard, i int) error
 // multiGoTransaction is capable of executing multiple
 // shardActionTransactionFunc actions in parallel and consolidating
 // the results and errors for the caller.
-type shardActionTransactionFunc func(rs *srvtopo.ResolvedShard, i int, shardActionInfo *shardActionInfo) (*shardActionInfo, error)
+type shardActionTransactionFunc func(ctx context.Context, rs *srvtopo.ResolvedShard, i int, shardActionInfo *shardActionInfo) (*shardActionInfo, error)
 
 // NewScatterConn creates a new ScatterConn.
 func NewScatterConn(statsName string, txConn *TxConn, gw *TabletGateway) *ScatterConn {
@@ -150,6 +150,17 @@ func (stc *ScatterConn) ExecuteMultiShard(
 		return nil, []error{vterrors.Errorf(vtrpcpb.Code_INTERNAL, "[BUG] got mismatched number of queries and shards")}
 	}
 
+	// The results of the queries routed to several shards are not paged.
+	var continuationToken string
+	unpaged := false
+	if session != nil && session.Session != nil {
+		continuationToken = session.Session.GetOptions().GetContinuationToken()
+		unpaged = session.Session.GetOptions().GetResultPaging() && len(rss) > 1
+	}
+	if continuationToken != "" && len(rss) > 1 {
+		return nil, []error{vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "continuation tokens are only supported by the queries routed to a single shard")}
+	}
+
 	// mu protects qr
 	var mu sync.Mutex
 	qr = new(sqltypes.Result)
@@ -164,7 +175,7 @@ func (stc *ScatterConn) ExecuteMultiShard(
 		rss,
 		session,
 		autocommit,
-		func(rs *srvtopo.ResolvedShard, i int, info *shardActionInfo) (*shardActionInfo, error) {
+		func(ctx context.Context, rs *srvtopo.ResolvedShard, i int, info *shardActionInfo) (*shardActionInfo, error) {
 			var (
 				innerqr *sqltypes.Result
 				err     error
@@ -176,7 +187,11 @@ func (stc *ScatterConn) ExecuteMultiShard(
 			reservedID := info.reservedID
 
 			if session != nil && session.Session != nil {
-				opts = session.Session.Options
+				opts = readAfterWriteOptions(session, rs.Target, session.Session.Options)
+			}
+			if unpaged {
+				opts = proto.Clone(opts).(*querypb.ExecuteOptions)
+				opts.ResultPaging = false
 			}
 
 			if autocommit {
@@ -255,6 +270,9 @@ func (stc *ScatterConn) ExecuteMultiShard(
 			if err != nil {
 				return newInfo, err
 			}
+			if autocommit {
+				session.RecordWrite(rs.Target)
+			}
 			mu.Lock()
 			defer mu.Unlock()
 
@@ -363,7 +381,7 @@ func (stc *ScatterConn) StreamExecuteMulti(
 		rss,
 		session,
 		autocommit,
-		func(rs *srvtopo.ResolvedShard, i int, info *shardActionInfo) (*shardActionInfo, error) {
+		func(ctx context.Context, rs *srvtopo.ResolvedShard, i int, info *shardActionInfo) (*shardActionInfo, error) {
 			var (
 				err   error
 				opts  *querypb.ExecuteOptions
@@ -374,7 +392,7 @@ func (stc *ScatterConn) StreamExecuteMulti(
 			reservedID := info.reservedID
 
 			if session != nil && session.Session != nil {
-				opts = session.Session.Options
+				opts = readAfterWriteOptions(session, rs.Target, session.Session.Options)
 			}
 
 			if autocommit {
@@ -453,6 +471,9 @@ func (stc *ScatterConn) StreamExecuteMulti(
 			if err != nil {
 				return newInfo, err
 			}
+			if autocommit {
+				session.RecordWrite(rs.Target)
+			}
 
 			return newInfo, nil
 		},
@@ -610,6 +631,9 @@ func (stc *ScatterConn) multiGo(
 // contains a transaction id for the shard, it reuses it.
 // The action function must match the shardActionTransactionFunc signature.
 //
+// The number of shards the action runs on at the same time, and the timeout of
+// the action on each shard, are limited by the scatter options of the context.
+//
 // It returns an error recorder in which each shard error is recorded positionally,
 // i.e. if rss[2] had an error, then the error recorder will store that error
 // in the second position.
@@ -628,6 +652,7 @@ func (stc *ScatterConn) multiGoTransaction(
 	if numShards == 0 {
 		return allErrors
 	}
+	opts := engine.GetScatterOptions(ctx)
 	oneShard := func(rs *srvtopo.ResolvedShard, i int) {
 		var err error
 		startTime, statsKey := stc.startAction(name, rs.Target)
@@ -637,7 +662,13 @@ func (stc *ScatterConn) multiGoTransaction(
 		if err != nil {
 			return
 		}
-		updated, err := action(rs, i, shardActionInfo)
+		shardCtx := ctx
+		if opts.ShardTimeout > 0 {
+			var cancel context.CancelFunc
+			shardCtx, cancel = context.WithTimeout(ctx, time.Duration(opts.ShardTimeout)*time.Millisecond)
+			defer cancel()
+		}
+		updated, err := action(shardCtx, rs, i, shardActionInfo)
 		if updated == nil {
 			return
 		}
@@ -660,11 +691,22 @@ func (stc *ScatterConn) multiGoTransaction(
 			oneShard(rs, i)
 		}
 	} else {
+		// sem bounds the number of shards the action runs on at the same time.
+		var sem chan struct{}
+		if opts.Concurrency > 0 && opts.Concurrency < numShards {
+			sem = make(chan struct{}, opts.Concurrency)
+		}
 		var wg sync.WaitGroup
 		for i, rs := range rss {
+			if sem != nil {
+				sem <- struct{}{}
+			}
 			wg.Add(1)
 			go func(rs *srvtopo.ResolvedShard, i int) {
 				defer wg.Done()
+				if sem != nil {
+					defer func() { <-sem }()
+				}
 				oneShard(rs, i)
 			}(rs, i)
 		}
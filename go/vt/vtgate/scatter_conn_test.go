@@ -17,7 +17,11 @@ limitations under the License.
 package vtgate
 
 import (
+	"context"
+	"fmt"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"vitess.io/vitess/go/mysql/sqlerror"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
@@ -37,6 +41,8 @@ import (
 	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
 	"vitess.io/vitess/go/vt/srvtopo"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vttablet/sandboxconn"
 )
 
 // This file uses the sandbox_test framework.
@@ -105,6 +111,45 @@ func TestExecuteFailOnAutocommit(t *testing.T) {
 	utils.MustMatch(t, []*querypb.BoundQuery{queries[1]}, sbc1.Queries, "")
 }
 
+func TestExecuteMultiShardResultPaging(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+
+	keyspace := "TestExecuteMultiShardResultPaging"
+	createSandbox(keyspace)
+	hc := discovery.NewFakeHealthCheck(nil)
+	sc := newTestScatterConn(ctx, hc, newSandboxForCells(ctx, []string{"aa"}), "aa")
+	sbc0 := hc.AddTestTablet("aa", "0", 1, keyspace, "0", topodatapb.TabletType_PRIMARY, true, 1, nil)
+	sbc1 := hc.AddTestTablet("aa", "1", 1, keyspace, "1", topodatapb.TabletType_PRIMARY, true, 1, nil)
+	var rss []*srvtopo.ResolvedShard
+	var queries []*querypb.BoundQuery
+	for i, sbc := range []*sandboxconn.SandboxConn{sbc0, sbc1} {
+		rss = append(rss, &srvtopo.ResolvedShard{
+			Target:  &querypb.Target{Keyspace: keyspace, Shard: fmt.Sprint(i), TabletType: topodatapb.TabletType_PRIMARY},
+			Gateway: sbc,
+		})
+		queries = append(queries, &querypb.BoundQuery{Sql: "select id from t order by id"})
+	}
+
+	// The continuation token of a single shard is returned as is.
+	sbc0.SetResults([]*sqltypes.Result{{Rows: [][]sqltypes.Value{{sqltypes.NewInt64(1)}}, ContinuationToken: "token"}})
+	session := NewSafeSession(&vtgatepb.Session{Options: &querypb.ExecuteOptions{ResultPaging: true}})
+	qr, errs := sc.ExecuteMultiShard(ctx, nil, rss[:1], queries[:1], session, true, false)
+	require.NoError(t, vterrors.Aggregate(errs))
+	assert.Equal(t, "token", qr.ContinuationToken)
+	assert.True(t, sbc0.Options[0].ResultPaging)
+
+	// The results of several shards are not paged.
+	_, errs = sc.ExecuteMultiShard(ctx, nil, rss, queries, session, true, false)
+	require.NoError(t, vterrors.Aggregate(errs))
+	assert.False(t, sbc0.Options[1].ResultPaging)
+	assert.False(t, sbc1.Options[0].ResultPaging)
+	assert.True(t, session.Options.ResultPaging)
+
+	session = NewSafeSession(&vtgatepb.Session{Options: &querypb.ExecuteOptions{ContinuationToken: "token"}})
+	_, errs = sc.ExecuteMultiShard(ctx, nil, rss, queries, session, true, false)
+	assert.ErrorContains(t, vterrors.Aggregate(errs), "continuation tokens are only supported by the queries routed to a single shard")
+}
+
 func TestReservedOnMultiReplica(t *testing.T) {
 	ctx := utils.LeakCheckContext(t)
 
@@ -410,6 +455,51 @@ func TestReservedConnFail(t *testing.T) {
 	assert.NotEqual(t, oldAlias, session.Session.ShardSessions[0].TabletAlias, "tablet alias should have changed as this is a different tablet")
 }
 
+func TestMultiGoTransactionScatterOptions(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+
+	createSandbox("TestMultiGoTransactionScatterOptions")
+	hc := discovery.NewFakeHealthCheck(nil)
+	sc := newTestScatterConn(ctx, hc, newSandboxForCells(ctx, []string{"aa"}), "aa")
+	var rss []*srvtopo.ResolvedShard
+	for i := 0; i < 4; i++ {
+		rss = append(rss, &srvtopo.ResolvedShard{
+			Target: &querypb.Target{Keyspace: "TestMultiGoTransactionScatterOptions", Shard: fmt.Sprint(i), TabletType: topodatapb.TabletType_PRIMARY},
+		})
+	}
+
+	t.Run("concurrency", func(t *testing.T) {
+		var running, maxRunning atomic.Int32
+		opts := engine.ScatterOptions{Concurrency: 2}
+		allErrors := sc.multiGoTransaction(engine.WithScatterOptions(ctx, opts), "Execute", rss, NewSafeSession(nil), false,
+			func(ctx context.Context, rs *srvtopo.ResolvedShard, i int, info *shardActionInfo) (*shardActionInfo, error) {
+				n := running.Add(1)
+				defer running.Add(-1)
+				for m := maxRunning.Load(); n > m && !maxRunning.CompareAndSwap(m, n); m = maxRunning.Load() {
+				}
+				time.Sleep(10 * time.Millisecond)
+				return info, nil
+			})
+		require.NoError(t, allErrors.Error())
+		assert.LessOrEqual(t, maxRunning.Load(), int32(2), "no more than two shards should be queried at the same time")
+	})
+
+	t.Run("shard timeout", func(t *testing.T) {
+		opts := engine.ScatterOptions{ShardTimeout: 10}
+		allErrors := sc.multiGoTransaction(engine.WithScatterOptions(ctx, opts), "Execute", rss, NewSafeSession(nil), false,
+			func(ctx context.Context, rs *srvtopo.ResolvedShard, i int, info *shardActionInfo) (*shardActionInfo, error) {
+				if rs.Target.Shard == "0" {
+					<-ctx.Done()
+					return info, ctx.Err()
+				}
+				return info, nil
+			})
+		errs := allErrors.GetErrors()
+		require.Len(t, errs, 1)
+		assert.ErrorIs(t, errs[0], context.DeadlineExceeded)
+	})
+}
+
 func TestIsConnClosed(t *testing.T) {
 	var testCases = []struct {
 		name      string
@@ -32,20 +32,23 @@ import (
 )
 
 type (
-	keyspaceStr  = string
-	tableNameStr = string
-	viewNameStr  = string
+	keyspaceStr    = string
+	tableNameStr   = string
+	viewNameStr    = string
+	routineNameStr = string
 
 	// Tracker contains the required fields to perform schema tracking.
 	Tracker struct {
 		ch     chan *discovery.TabletHealth
 		cancel context.CancelFunc
 
-		mu     sync.Mutex
-		tables *tableMap
-		views  *viewMap
-		ctx    context.Context
-		signal func() // a function that we'll call whenever we have new schema data
+		mu         sync.Mutex
+		tables     *tableMap
+		views      *viewMap
+		procedures *routineMap
+		functions  *routineMap
+		ctx        context.Context
+		signal     func() // a function that we'll call whenever we have new schema data
 
 		// map of keyspace currently tracked
 		tracked      map[keyspaceStr]*updateController
@@ -62,6 +65,8 @@ func NewTracker(ch chan *discovery.TabletHealth, enableViews bool) *Tracker {
 		ctx:          context.Background(),
 		ch:           ch,
 		tables:       &tableMap{m: make(map[keyspaceStr]map[tableNameStr]*vindexes.TableInfo)},
+		procedures:   &routineMap{m: make(map[keyspaceStr]map[routineNameStr]string)},
+		functions:    &routineMap{m: make(map[keyspaceStr]map[routineNameStr]string)},
 		tracked:      map[keyspaceStr]*updateController{},
 		consumeDelay: defaultConsumeDelay,
 	}
@@ -82,6 +87,9 @@ func (t *Tracker) LoadKeyspace(conn queryservice.QueryService, target *querypb.T
 	if err != nil {
 		return err
 	}
+	// The tablets of older versions do not track the stored procedures and functions,
+	// so failing to load them does not fail the load of the keyspace.
+	t.loadRoutines(conn, target)
 
 	t.tracked[target.Keyspace].setLoaded(true)
 	return nil
@@ -142,6 +150,35 @@ func (t *Tracker) loadViews(conn queryservice.QueryService, target *querypb.Targ
 	return nil
 }
 
+func (t *Tracker) loadRoutines(conn queryservice.QueryService, target *querypb.Target) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	// We must clear out any previous routine definition before loading it here as this is called
+	// whenever a shard's primary tablet starts and sends the initial signal.
+	t.procedures.clear(target.Keyspace)
+	t.functions.clear(target.Keyspace)
+
+	for _, rt := range []struct {
+		tableType querypb.SchemaTableType
+		routines  *routineMap
+	}{
+		{querypb.SchemaTableType_PROCEDURES, t.procedures},
+		{querypb.SchemaTableType_FUNCTIONS, t.functions},
+	} {
+		var numRoutines int
+		err := conn.GetSchema(t.ctx, target, rt.tableType, nil, func(schemaRes *querypb.GetSchemaResponse) error {
+			rt.routines.update(target.Keyspace, schemaRes.TableDefinition)
+			numRoutines += len(schemaRes.TableDefinition)
+			return nil
+		})
+		if err != nil {
+			log.Warningf("Unable to load the %s of keyspace %s: %v", strings.ToLower(rt.tableType.String()), target.Keyspace, err)
+			continue
+		}
+		log.Infof("finished loading %s for keyspace %s. Found %d", strings.ToLower(rt.tableType.String()), target.Keyspace, numRoutines)
+	}
+}
+
 // Start starts the schema tracking.
 func (t *Tracker) Start() {
 	log.Info("Starting schema tracking")
@@ -229,6 +266,22 @@ func (t *Tracker) Tables(ks string) map[string]*vindexes.TableInfo {
 	return maps.Clone(m)
 }
 
+// Procedures returns all known stored procedures in the keyspace with their create statement.
+func (t *Tracker) Procedures(ks string) map[string]string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return maps.Clone(t.procedures.m[ks])
+}
+
+// Functions returns all known stored functions in the keyspace with their create statement.
+func (t *Tracker) Functions(ks string) map[string]string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return maps.Clone(t.functions.m[ks])
+}
+
 // Views returns all known views in the keyspace with their definition.
 func (t *Tracker) Views(ks string) map[string]sqlparser.SelectStatement {
 	t.mu.Lock()
@@ -247,11 +300,17 @@ func (t *Tracker) updateSchema(th *discovery.TabletHealth) bool {
 	if th.Stats.TableSchemaChanged != nil {
 		success = t.updatedTableSchema(th)
 	}
-	if !success || th.Stats.ViewSchemaChanged == nil {
-		return success
+	if success && th.Stats.ViewSchemaChanged != nil {
+		// there is view definition change in the tablet
+		success = t.updatedViewSchema(th)
 	}
-	// there is view definition change in the tablet
-	return t.updatedViewSchema(th)
+	if success && th.Stats.ProcedureSchemaChanged != nil {
+		success = t.updatedRoutineSchema(th, querypb.SchemaTableType_PROCEDURES, t.procedures, th.Stats.ProcedureSchemaChanged)
+	}
+	if success && th.Stats.FunctionSchemaChanged != nil {
+		success = t.updatedRoutineSchema(th, querypb.SchemaTableType_FUNCTIONS, t.functions, th.Stats.FunctionSchemaChanged)
+	}
+	return success
 }
 
 func (t *Tracker) updatedTableSchema(th *discovery.TabletHealth) bool {
@@ -377,6 +436,27 @@ func (t *Tracker) updateViews(keyspace string, res map[string]string) {
 	}
 }
 
+func (t *Tracker) updatedRoutineSchema(th *discovery.TabletHealth, tableType querypb.SchemaTableType, routines *routineMap, routinesUpdated []string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// first we empty all prior definitions. deleted routines will not show up in the result,
+	// so this is the only chance to delete
+	for _, routine := range routinesUpdated {
+		routines.delete(th.Target.Keyspace, routine)
+	}
+	err := th.Conn.GetSchema(t.ctx, th.Target, tableType, routinesUpdated, func(schemaRes *querypb.GetSchemaResponse) error {
+		routines.update(th.Target.Keyspace, schemaRes.TableDefinition)
+		return nil
+	})
+	if err != nil {
+		t.tracked[th.Target.Keyspace].setLoaded(false)
+		log.Warningf("error fetching new %s definition for %v: %v", strings.ToLower(tableType.String()), routinesUpdated, err)
+		return false
+	}
+	return true
+}
+
 // RegisterSignalReceiver allows a function to register to be called when new schema is available
 func (t *Tracker) RegisterSignalReceiver(f func()) {
 	t.mu.Lock()
@@ -481,6 +561,34 @@ func (t *Tracker) clearKeyspaceViews(ks string) {
 	}
 }
 
+// routineMap contains the create statements of the stored procedures or functions.
+type routineMap struct {
+	m map[keyspaceStr]map[routineNameStr]string
+}
+
+func (rm *routineMap) update(ks string, res map[string]string) {
+	m := rm.m[ks]
+	if m == nil {
+		m = make(map[routineNameStr]string)
+		rm.m[ks] = m
+	}
+	for name, def := range res {
+		m[name] = def
+	}
+}
+
+func (rm *routineMap) delete(ks, name string) {
+	m := rm.m[ks]
+	if m == nil {
+		return
+	}
+	delete(m, name)
+}
+
+func (rm *routineMap) clear(ks string) {
+	delete(rm.m, ks)
+}
+
 // GetViews returns the view statement for the given keyspace and view name.
 func (t *Tracker) GetViews(ks string, tbl string) sqlparser.SelectStatement {
 	t.mu.Lock()
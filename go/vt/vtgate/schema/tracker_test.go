@@ -130,7 +130,8 @@ func TestTrackingUnHealthyTablet(t *testing.T) {
 	}
 
 	require.False(t, waitTimeout(&wg, 5*time.Second), "schema was updated but received no signal")
-	require.EqualValues(t, 3, sbc.GetSchemaCount.Load())
+	// Each load fetches the tables, procedures and functions.
+	require.EqualValues(t, 7, sbc.GetSchemaCount.Load())
 }
 
 // TestTrackerGetKeyspaceUpdateController tests table update controller initialization.
@@ -172,6 +173,10 @@ func TestTableTracking(t *testing.T) {
 		"prior": "create table prior(id int primary key)",
 	}, {
 		// initial load of view - kept empty
+	}, {
+		// initial load of procedures - kept empty
+	}, {
+		// initial load of functions - kept empty
 	}, {
 		"t1": "create table t1(id bigint primary key, name varchar(50))",
 		"t2": "create table t2(id varchar(50) primary key)",
@@ -223,6 +228,10 @@ func TestViewsTracking(t *testing.T) {
 		// initial load of table - kept empty
 	}, {
 		"prior": "create view prior as select 1 from tbl",
+	}, {
+		// initial load of procedures - kept empty
+	}, {
+		// initial load of functions - kept empty
 	}, {
 		"t1": "create view t1 as select 1 from tbl1",
 		"t2": "create view t2 as select 1 from tbl2",
@@ -264,6 +273,41 @@ func TestViewsTracking(t *testing.T) {
 	testTracker(t, schemaDefResult, testcases)
 }
 
+// TestRoutinesTracking tests that the tracker is able to track stored procedures and functions.
+func TestRoutinesTracking(t *testing.T) {
+	schemaDefResult := []map[string]string{{
+		// initial load of table - kept empty
+	}, {
+		// initial load of view - kept empty
+	}, {
+		"prior": "create procedure prior() select 1",
+	}, {
+		"f1": "create function f1() returns int return 1",
+	}, {
+		"p1": "create procedure p1() select 1",
+	}, {
+		"f1": "create function f1() returns int return 2",
+	}}
+
+	testcases := []testCases{{
+		testName: "initial routine load",
+		expProc:  map[string]string{"prior": "create procedure prior() select 1"},
+		expFunc:  map[string]string{"f1": "create function f1() returns int return 1"},
+	}, {
+		testName: "delete prior and new p1",
+		updProc:  []string{"prior", "p1"},
+		expProc:  map[string]string{"p1": "create procedure p1() select 1"},
+		expFunc:  map[string]string{"f1": "create function f1() returns int return 1"},
+	}, {
+		testName: "updated f1",
+		updFunc:  []string{"f1"},
+		expProc:  map[string]string{"p1": "create procedure p1() select 1"},
+		expFunc:  map[string]string{"f1": "create function f1() returns int return 2"},
+	}}
+
+	testTracker(t, schemaDefResult, testcases)
+}
+
 // TestTableInfoRetrieval tests that the tracker is able to retrieve required information from ddl statement.
 func TestTableInfoRetrieval(t *testing.T) {
 	schemaDefResult := []map[string]string{{
@@ -276,6 +320,10 @@ func TestTableInfoRetrieval(t *testing.T) {
 			"ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci",
 	}, {
 		// initial load of view - kept empty
+	}, {
+		// initial load of procedures - kept empty
+	}, {
+		// initial load of functions - kept empty
 	}, {
 		"my_child_tbl": "CREATE TABLE `my_child_tbl` (" +
 			"`id` bigint NOT NULL AUTO_INCREMENT," +
@@ -331,6 +379,12 @@ type testCases struct {
 
 	updView []string
 	expView map[string]string
+
+	updProc []string
+	expProc map[string]string
+
+	updFunc []string
+	expFunc map[string]string
 }
 
 func testTracker(t *testing.T, schemaDefResult []map[string]string, tcases []testCases) {
@@ -359,11 +413,17 @@ func testTracker(t *testing.T, schemaDefResult []map[string]string, tcases []tes
 				Tablet:  tablet,
 				Target:  target,
 				Serving: true,
-				Stats:   &querypb.RealtimeStats{TableSchemaChanged: tcase.updTbl, ViewSchemaChanged: tcase.updView},
+				Stats: &querypb.RealtimeStats{
+					TableSchemaChanged:     tcase.updTbl,
+					ViewSchemaChanged:      tcase.updView,
+					ProcedureSchemaChanged: tcase.updProc,
+					FunctionSchemaChanged:  tcase.updFunc,
+				},
 			}
 
 			require.False(t, waitTimeout(&wg, time.Second), "schema was updated but received no signal")
-			require.EqualValues(t, count+2, sbc.GetSchemaCount.Load())
+			// The initial load fetches the tables, views, procedures and functions.
+			require.EqualValues(t, count+4, sbc.GetSchemaCount.Load())
 
 			_, keyspacePresent := tracker.tracked[target.Keyspace]
 			require.Equal(t, true, keyspacePresent)
@@ -381,6 +441,13 @@ func testTracker(t *testing.T, schemaDefResult []map[string]string, tcases []tes
 			for k, v := range tcase.expView {
 				utils.MustMatch(t, v, sqlparser.String(tracker.GetViews(keyspace, k)), "mismatch for view: ", k)
 			}
+
+			if tcase.expProc != nil {
+				utils.MustMatch(t, tcase.expProc, tracker.Procedures(keyspace), "mismatch procedures")
+			}
+			if tcase.expFunc != nil {
+				utils.MustMatch(t, tcase.expFunc, tracker.Functions(keyspace), "mismatch functions")
+			}
 		})
 	}
 }
@@ -17,11 +17,13 @@ limitations under the License.
 package schema
 
 import (
+	"slices"
 	"sync"
 	"time"
 
 	"vitess.io/vitess/go/mysql/sqlerror"
 
+	querypb "vitess.io/vitess/go/vt/proto/query"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 
 	"vitess.io/vitess/go/vt/discovery"
@@ -100,30 +102,11 @@ func (u *updateController) getItemFromQueueLocked() *discovery.TabletHealth {
 		// We are trying to minimize the vttablet calls here by merging all the table/view changes received into a single changed item
 		// with all the table and view names.
 		for i := 1; i < itemsCount; i++ {
-			for _, table := range u.queue.items[i].Stats.TableSchemaChanged {
-				found := false
-				for _, itemTable := range item.Stats.TableSchemaChanged {
-					if itemTable == table {
-						found = true
-						break
-					}
-				}
-				if !found {
-					item.Stats.TableSchemaChanged = append(item.Stats.TableSchemaChanged, table)
-				}
-			}
-			for _, view := range u.queue.items[i].Stats.ViewSchemaChanged {
-				found := false
-				for _, itemView := range item.Stats.ViewSchemaChanged {
-					if itemView == view {
-						found = true
-						break
-					}
-				}
-				if !found {
-					item.Stats.ViewSchemaChanged = append(item.Stats.ViewSchemaChanged, view)
-				}
-			}
+			stats := u.queue.items[i].Stats
+			item.Stats.TableSchemaChanged = mergeNames(item.Stats.TableSchemaChanged, stats.TableSchemaChanged)
+			item.Stats.ViewSchemaChanged = mergeNames(item.Stats.ViewSchemaChanged, stats.ViewSchemaChanged)
+			item.Stats.ProcedureSchemaChanged = mergeNames(item.Stats.ProcedureSchemaChanged, stats.ProcedureSchemaChanged)
+			item.Stats.FunctionSchemaChanged = mergeNames(item.Stats.FunctionSchemaChanged, stats.FunctionSchemaChanged)
 		}
 	}
 	// emptying queue's items as all items from 0 to i (length of the queue) are merged
@@ -131,6 +114,22 @@ func (u *updateController) getItemFromQueueLocked() *discovery.TabletHealth {
 	return item
 }
 
+// mergeNames appends to names the names that it does not contain yet.
+func mergeNames(names, more []string) []string {
+	for _, name := range more {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// hasSchemaChanges returns true if the tablet health signals schema changes.
+func hasSchemaChanges(stats *querypb.RealtimeStats) bool {
+	return len(stats.TableSchemaChanged) > 0 || len(stats.ViewSchemaChanged) > 0 ||
+		len(stats.ProcedureSchemaChanged) > 0 || len(stats.FunctionSchemaChanged) > 0
+}
+
 func (u *updateController) add(th *discovery.TabletHealth) {
 	// For non-primary tablet health, there is no schema tracking.
 	if th.Target.TabletType != topodatapb.TabletType_PRIMARY {
@@ -148,11 +147,11 @@ func (u *updateController) add(th *discovery.TabletHealth) {
 	}
 
 	// If the keyspace schema is loaded and there is no schema change detected. Then there is nothing to process.
-	if len(th.Stats.TableSchemaChanged) == 0 && len(th.Stats.ViewSchemaChanged) == 0 && u.loaded {
+	if !hasSchemaChanges(th.Stats) && u.loaded {
 		return
 	}
 
-	if (len(th.Stats.TableSchemaChanged) > 0 || len(th.Stats.ViewSchemaChanged) > 0) && u.ignore {
+	if hasSchemaChanges(th.Stats) && u.ignore {
 		// we got an update for this keyspace - we need to stop ignoring it, and reload everything
 		u.ignore = false
 		u.loaded = false
@@ -28,6 +28,7 @@ import (
 
 	"github.com/spf13/pflag"
 
+	"vitess.io/vitess/go/flagutil"
 	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/vt/discovery"
 	"vitess.io/vitess/go/vt/log"
@@ -37,6 +38,7 @@ import (
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/buffer"
+	"vitess.io/vitess/go/vt/vtgate/engine"
 	"vitess.io/vitess/go/vt/vttablet/queryservice"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
@@ -53,6 +55,11 @@ var (
 	initialTabletTimeout = 30 * time.Second
 	// retryCount is the number of times a query will be retried on error
 	retryCount = 2
+
+	// tabletPreferTags and tabletAvoidTags are the tablet tags used to order
+	// the tablets a query can be sent to.
+	tabletPreferTags flagutil.StringMapValue
+	tabletAvoidTags  flagutil.StringMapValue
 )
 
 func init() {
@@ -62,6 +69,8 @@ func init() {
 		fs.MarkDeprecated("buffer_implementation", "The 'healthcheck' buffer implementation has been removed in v18 and this option will be removed in v19")
 		fs.DurationVar(&initialTabletTimeout, "gateway_initial_tablet_timeout", 30*time.Second, "At startup, the tabletGateway will wait up to this duration to get at least one tablet per keyspace/shard/tablet type")
 		fs.IntVar(&retryCount, "retry-count", 2, "retry count")
+		fs.Var(&tabletPreferTags, "tablet-prefer-tags", "Comma-separated list of tablet tags as key:value pairs. Tablets that have all of these tags are preferred when routing queries, over tablets in the local cell.")
+		fs.Var(&tabletAvoidTags, "tablet-avoid-tags", "Comma-separated list of tablet tags as key:value pairs. Tablets that have any of these tags are only used when no other tablet is available.")
 	})
 }
 
@@ -307,11 +316,19 @@ func (gw *TabletGateway) withRetry(ctx context.Context, target *querypb.Target,
 		}
 
 		gw.shuffleTablets(gw.localCell, tablets)
+		orderTabletsByTags(tablets, tabletPreferTags, tabletAvoidTags)
 
 		var th *discovery.TabletHealth
-		// skip tablets we tried before
+		// skip tablets we tried before, and prefer the ones a retried read did not fail on
 		for _, t := range tablets {
-			if _, ok := invalidTablets[topoproto.TabletAliasString(t.Tablet.Alias)]; !ok {
+			alias := topoproto.TabletAliasString(t.Tablet.Alias)
+			if _, ok := invalidTablets[alias]; ok {
+				continue
+			}
+			if th == nil {
+				th = t
+			}
+			if !engine.ShouldAvoidTablet(ctx, alias) {
 				th = t
 				break
 			}
@@ -338,12 +355,18 @@ func (gw *TabletGateway) withRetry(ctx context.Context, target *querypb.Target,
 		var canRetry bool
 		canRetry, err = inner(ctx, target, th.Conn)
 		gw.updateStats(target, startTime, err)
+		if err != nil {
+			engine.AvoidTabletOnRetry(ctx, topoproto.TabletAliasString(tabletLastUsed.Alias))
+		}
 		if canRetry {
 			invalidTablets[topoproto.TabletAliasString(tabletLastUsed.Alias)] = true
 			continue
 		}
 		break
 	}
+	if err != nil {
+		engine.RecordShardFailure(ctx, target)
+	}
 	return NewShardError(err, target)
 }
 
@@ -414,6 +437,30 @@ func (gw *TabletGateway) shuffleTablets(cell string, tablets []*discovery.Tablet
 	}
 }
 
+// orderTabletsByTags moves the tablets that have all the preferred tags to the
+// front, and the tablets that have any of the avoided tags to the back. The
+// order is otherwise kept, so that the tablets stay shuffled.
+func orderTabletsByTags(tablets []*discovery.TabletHealth, prefer, avoid map[string]string) {
+	if len(prefer) == 0 && len(avoid) == 0 {
+		return
+	}
+
+	rank := func(th *discovery.TabletHealth) int {
+		for key, value := range avoid {
+			if v, ok := th.Tablet.Tags[key]; ok && v == value {
+				return 2
+			}
+		}
+		if len(prefer) > 0 && discovery.TabletHasTags(th.Tablet, prefer) {
+			return 0
+		}
+		return 1
+	}
+	sort.SliceStable(tablets, func(i, j int) bool {
+		return rank(tablets[i]) < rank(tablets[j])
+	})
+}
+
 func (gw *TabletGateway) nextTablet(cell string, tablets []*discovery.TabletHealth, offset, length int, sameCell bool) int {
 	for ; offset < length; offset++ {
 		if (tablets[offset].Tablet.Alias.Cell == cell) == sameCell {
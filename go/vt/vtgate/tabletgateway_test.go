@@ -34,6 +34,7 @@ import (
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
 )
 
 func TestTabletGatewayExecute(t *testing.T) {
@@ -146,6 +147,39 @@ func TestTabletGatewayShuffleTablets(t *testing.T) {
 	}
 }
 
+func TestOrderTabletsByTags(t *testing.T) {
+	newTablet := func(uid uint32, tags map[string]string) *discovery.TabletHealth {
+		tablet := topo.NewTablet(uid, "cell1", fmt.Sprintf("host%d", uid))
+		tablet.Tags = tags
+		return &discovery.TabletHealth{Tablet: tablet}
+	}
+	uids := func(tablets []*discovery.TabletHealth) []uint32 {
+		var res []uint32
+		for _, th := range tablets {
+			res = append(res, th.Tablet.Alias.Uid)
+		}
+		return res
+	}
+
+	tablets := []*discovery.TabletHealth{
+		newTablet(1, nil),
+		newTablet(2, map[string]string{"hardware": "hdd"}),
+		newTablet(3, map[string]string{"zone": "az1", "hardware": "nvme"}),
+		newTablet(4, map[string]string{"zone": "az1"}),
+		newTablet(5, map[string]string{"zone": "az1", "hardware": "nvme", "maintenance": "true"}),
+		newTablet(6, map[string]string{"zone": "az1", "hardware": "nvme"}),
+	}
+
+	orderTabletsByTags(tablets, nil, nil)
+	assert.Equal(t, []uint32{1, 2, 3, 4, 5, 6}, uids(tablets))
+
+	orderTabletsByTags(tablets, map[string]string{"zone": "az1", "hardware": "nvme"}, nil)
+	assert.Equal(t, []uint32{3, 5, 6, 1, 2, 4}, uids(tablets))
+
+	orderTabletsByTags(tablets, map[string]string{"zone": "az1", "hardware": "nvme"}, map[string]string{"maintenance": "true", "hardware": "hdd"})
+	assert.Equal(t, []uint32{3, 6, 1, 4, 5, 2}, uids(tablets))
+}
+
 func TestTabletGatewayReplicaTransactionError(t *testing.T) {
 	ctx := utils.LeakCheckContext(t)
 
@@ -171,6 +205,40 @@ func TestTabletGatewayReplicaTransactionError(t *testing.T) {
 	verifyContainsError(t, err, "query service can only be used for non-transactional queries on replicas", vtrpcpb.Code_INTERNAL)
 }
 
+func TestTabletGatewayReadRetryAvoidsTablet(t *testing.T) {
+	ctx := engine.WithReadRetry(utils.LeakCheckContext(t))
+
+	keyspace := "ks"
+	shard := "0"
+	tabletType := topodatapb.TabletType_REPLICA
+	host := "1.1.1.1"
+	target := &querypb.Target{
+		Keyspace:   keyspace,
+		Shard:      shard,
+		TabletType: tabletType,
+	}
+	hc := discovery.NewFakeHealthCheck(nil)
+	tg := NewTabletGateway(ctx, hc, &fakeTopoServer{}, "cell")
+	defer tg.Close(ctx)
+
+	// ABORTED is not retried by the gateway, the second query goes to the tablet the first one did not fail on.
+	sc1 := hc.AddTestTablet("cell", host, 1001, keyspace, shard, tabletType, true, 10, nil)
+	sc2 := hc.AddTestTablet("cell", host, 1002, keyspace, shard, tabletType, true, 10, nil)
+	sc1.MustFailCodes[vtrpcpb.Code_ABORTED] = 1
+	sc2.MustFailCodes[vtrpcpb.Code_ABORTED] = 1
+
+	_, err := tg.Execute(ctx, target, "query", nil, 0, 0, nil)
+	verifyContainsError(t, err, "target: ks.0.replica", vtrpcpb.Code_ABORTED)
+	_, err = tg.Execute(ctx, target, "query", nil, 0, 0, nil)
+	verifyContainsError(t, err, "target: ks.0.replica", vtrpcpb.Code_ABORTED)
+	assert.EqualValues(t, 1, sc1.ExecCount.Load())
+	assert.EqualValues(t, 1, sc2.ExecCount.Load())
+
+	// Once all the tablets failed, they are used again.
+	_, err = tg.Execute(ctx, target, "query", nil, 0, 0, nil)
+	require.NoError(t, err)
+}
+
 func testTabletGatewayGeneric(t *testing.T, f func(tg *TabletGateway, target *querypb.Target) error) {
 	t.Helper()
 	ctx := utils.LeakCheckContext(t)
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"sync"
 
+	"vitess.io/vitess/go/stats"
 	"vitess.io/vitess/go/vt/concurrency"
 	"vitess.io/vitess/go/vt/dtids"
 	"vitess.io/vitess/go/vt/log"
@@ -34,6 +35,8 @@ import (
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
+var reservedConnsMultiplexed = stats.NewCounter("ReservedConnectionsMultiplexed", "Reserved connections released after a query because they only held the system variables of their session")
+
 // TxConn is used for executing transactional requests.
 type TxConn struct {
 	tabletGateway *TabletGateway
@@ -83,6 +86,7 @@ func (txc *TxConn) Commit(ctx context.Context, session *SafeSession) error {
 	if !session.InTransaction() {
 		return nil
 	}
+	session.RecordTransactionWrites()
 
 	twopc := false
 	switch session.TransactionMode {
@@ -267,6 +271,33 @@ func (txc *TxConn) Release(ctx context.Context, session *SafeSession) error {
 	})
 }
 
+// ReleaseMultiplexed releases the reserved connections of the session that only hold
+// its system variables. The next queries of the session reserve connections again with
+// the system variables as pre-queries, so that the session does not keep dedicated
+// connections between its queries. The tablets with the settings pool do not reserve
+// connections for the system variables, so there is nothing to release on them.
+func (txc *TxConn) ReleaseMultiplexed(ctx context.Context, session *SafeSession) error {
+	shardSessions := session.MultiplexedShardSessions()
+	if len(shardSessions) == 0 {
+		return nil
+	}
+	defer session.RemoveReleasedShardSessions()
+
+	return txc.runSessions(ctx, shardSessions, session.logging, func(ctx context.Context, s *vtgatepb.Session_ShardSession, logging *executeLogger) error {
+		qs, err := txc.queryService(s.TabletAlias)
+		if err != nil {
+			return err
+		}
+		err = qs.Release(ctx, s.Target, 0, s.ReservedId)
+		if err != nil {
+			return err
+		}
+		s.ReservedId = 0
+		reservedConnsMultiplexed.Add(1)
+		return nil
+	})
+}
+
 // ReleaseLock releases the reserved connection used for locking.
 func (txc *TxConn) ReleaseLock(ctx context.Context, session *SafeSession) error {
 	if !session.InLockSession() {
@@ -1373,3 +1373,42 @@ func newTestTxConnEnv(t *testing.T, ctx context.Context, name string) (sc *Scatt
 	require.NoError(t, err)
 	return sc, sbc0, sbc1, rss0, rss1, rss01
 }
+
+func TestTxConnReleaseMultiplexed(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+
+	sc, sbc0, sbc1, _, _, rss01 := newTestTxConnEnv(t, ctx, "TestTxConn")
+
+	// The reserved connections only holding the system variables are released. The sandbox
+	// connections are really reserved, like those of the tablets without the settings pool.
+	session := NewSafeSession(&vtgatepb.Session{InReservedConn: true, SystemVariables: map[string]string{"sql_mode": "''"}})
+	sc.ExecuteMultiShard(ctx, nil, rss01, twoQueries, session, false, false)
+	require.Len(t, session.ShardSessions, 2)
+	require.NoError(t, sc.txConn.ReleaseMultiplexed(ctx, session))
+	wantSession := vtgatepb.Session{InReservedConn: true, SystemVariables: map[string]string{"sql_mode": "''"}}
+	utils.MustMatch(t, &wantSession, session.Session, "Session")
+	assert.EqualValues(t, 1, sbc0.ReleaseCount.Load(), "sbc0.ReleaseCount")
+	assert.EqualValues(t, 1, sbc1.ReleaseCount.Load(), "sbc1.ReleaseCount")
+
+	// The next query reserves new connections.
+	sc.ExecuteMultiShard(ctx, nil, rss01, twoQueries, session, false, false)
+	require.Len(t, session.ShardSessions, 2)
+	assert.EqualValues(t, 2, sbc0.ReserveCount.Load(), "sbc0.ReserveCount")
+
+	// The reserved connections of a transaction are kept.
+	session.Session.InTransaction = true
+	require.NoError(t, sc.txConn.ReleaseMultiplexed(ctx, session))
+	require.Len(t, session.ShardSessions, 2)
+
+	// The pinned reserved connections are kept.
+	session.Session.InTransaction = false
+	session.PinReservedConn()
+	require.NoError(t, sc.txConn.ReleaseMultiplexed(ctx, session))
+	require.Len(t, session.ShardSessions, 2)
+	assert.EqualValues(t, 1, sbc0.ReleaseCount.Load(), "sbc0.ReleaseCount")
+	assert.EqualValues(t, 1, sbc1.ReleaseCount.Load(), "sbc1.ReleaseCount")
+
+	// Releasing all the connections unpins the session.
+	require.NoError(t, sc.txConn.ReleaseAll(ctx, session))
+	assert.False(t, session.PinnedReservedConn)
+}
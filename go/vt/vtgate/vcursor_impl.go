@@ -72,6 +72,8 @@ type iExecute interface {
 	ExecuteMessageStream(ctx context.Context, rss []*srvtopo.ResolvedShard, name string, callback func(*sqltypes.Result) error) error
 	ExecuteVStream(ctx context.Context, rss []*srvtopo.ResolvedShard, filter *binlogdatapb.Filter, gtid string, callback func(evs []*binlogdatapb.VEvent) error) error
 	ReleaseLock(ctx context.Context, session *SafeSession) error
+	ExecuteTopoLock(ctx context.Context, session *SafeSession, lockFuncType sqlparser.LockingFuncType, name string, timeout time.Duration) (sqltypes.Value, error)
+	topoNamedLocksEnabled() bool
 
 	showVitessReplicationStatus(ctx context.Context, filter *sqlparser.ShowFilter) (*sqltypes.Result, error)
 	showShards(ctx context.Context, filter *sqlparser.ShowFilter, destTabletType topodatapb.TabletType) (*sqltypes.Result, error)
@@ -205,6 +207,24 @@ func (vc *vcursorImpl) ExceedsMaxMemoryRows(numRows int) bool {
 	return !vc.ignoreMaxMemoryRows && numRows > maxMemoryRows
 }
 
+// SpillDir returns the spillDir flag value.
+func (vc *vcursorImpl) SpillDir() string {
+	return spillDir
+}
+
+// MaxSpillBytes returns the maxSpillBytes flag value.
+func (vc *vcursorImpl) MaxSpillBytes() int64 {
+	return maxSpillBytes
+}
+
+// MaxReadRetries returns the maxReadRetries flag value, or 0 if the session disabled the retries.
+func (vc *vcursorImpl) MaxReadRetries() int {
+	if vc.safeSession.GetSkipReadRetry() {
+		return 0
+	}
+	return maxReadRetries
+}
+
 // SetIgnoreMaxMemoryRows sets the ignoreMaxMemoryRows value.
 func (vc *vcursorImpl) SetIgnoreMaxMemoryRows(ignoreMaxMemoryRows bool) {
 	vc.ignoreMaxMemoryRows = ignoreMaxMemoryRows
@@ -230,6 +250,9 @@ func (vc *vcursorImpl) FindTable(name sqlparser.TableName) (*vindexes.Table, str
 	if destKeyspace == "" {
 		destKeyspace = vc.keyspace
 	}
+	if table := vc.findTempTable(destKeyspace, name.Name.String()); table != nil {
+		return table, destKeyspace, destTabletType, dest, nil
+	}
 	table, err := vc.vschema.FindTable(destKeyspace, name.Name.String())
 	if err != nil {
 		return nil, "", destTabletType, nil, err
@@ -256,8 +279,11 @@ func (vc *vcursorImpl) FindRoutedTable(name sqlparser.TableName) (*vindexes.Tabl
 	if destKeyspace == "" {
 		destKeyspace = vc.keyspace
 	}
+	if table := vc.findTempTable(destKeyspace, name.Name.String()); table != nil {
+		return table, nil
+	}
 
-	table, err := vc.vschema.FindRoutedTable(destKeyspace, name.Name.String(), destTabletType)
+	table, err := vc.vschema.FindRoutedTable(destKeyspace, name.Name.String(), destTabletType, vc.routingBucket())
 	if err != nil {
 		return nil, err
 	}
@@ -280,13 +306,35 @@ func (vc *vcursorImpl) FindTableOrVindex(name sqlparser.TableName) (*vindexes.Ta
 	if destKeyspace == "" {
 		destKeyspace = vc.getActualKeyspace()
 	}
-	table, vindex, err := vc.vschema.FindTableOrVindex(destKeyspace, name.Name.String(), vc.tabletType)
+	if table := vc.findTempTable(destKeyspace, name.Name.String()); table != nil {
+		return table, nil, destKeyspace, destTabletType, dest, nil
+	}
+	table, vindex, err := vc.vschema.FindTableOrVindex(destKeyspace, name.Name.String(), vc.tabletType, vc.routingBucket())
 	if err != nil {
 		return nil, nil, "", destTabletType, nil, err
 	}
 	return table, vindex, destKeyspace, destTabletType, dest, nil
 }
 
+// findTempTable returns the temporary table that the session created in the sharded keyspace, if any.
+// Like in MySQL, a temporary table hides the table of the same name.
+func (vc *vcursorImpl) findTempTable(keyspace, name string) *vindexes.Table {
+	if !vc.safeSession.HasTempTable(keyspace, name) {
+		return nil
+	}
+	ks, ok := vc.vschema.Keyspaces[keyspace]
+	if !ok || !ks.Keyspace.Sharded {
+		return nil
+	}
+	return vindexes.NewTemporaryTable(ks.Keyspace, name)
+}
+
+// routingBucket returns the bucket of the session for the routing rules that split their traffic.
+// The session UUID is hashed, so that all the queries and transactions of a session use the same tables.
+func (vc *vcursorImpl) routingBucket() uint32 {
+	return vindexes.RoutingBucket(vc.safeSession.GetSessionUUID())
+}
+
 func (vc *vcursorImpl) getDualTable() (*vindexes.Table, vindexes.Vindex, string, topodatapb.TabletType, key.Destination, error) {
 	ksName := vc.getActualKeyspace()
 	var ks *vindexes.Keyspace
@@ -568,6 +616,16 @@ func (vc *vcursorImpl) StreamExecuteMulti(ctx context.Context, primitive engine.
 	return errs
 }
 
+// UseTopoNamedLocks is part of the engine.VCursor interface.
+func (vc *vcursorImpl) UseTopoNamedLocks(keyspace *vindexes.Keyspace) bool {
+	return vc.executor.topoNamedLocksEnabled() && !keyspace.Sharded
+}
+
+// ExecuteTopoLock is part of the engine.VCursor interface.
+func (vc *vcursorImpl) ExecuteTopoLock(ctx context.Context, lockFuncType sqlparser.LockingFuncType, name string, timeout time.Duration) (sqltypes.Value, error) {
+	return vc.executor.ExecuteTopoLock(ctx, vc.safeSession, lockFuncType, name, timeout)
+}
+
 // ExecuteLock is for executing advisory lock statements.
 func (vc *vcursorImpl) ExecuteLock(ctx context.Context, rs *srvtopo.ResolvedShard, query *querypb.BoundQuery, lockFuncType sqlparser.LockingFuncType) (*sqltypes.Result, error) {
 	query.Sql = vc.marginComments.Leading + query.Sql + vc.marginComments.Trailing
@@ -739,6 +797,11 @@ func (vc *vcursorImpl) NeedsReservedConn() {
 	vc.safeSession.SetReservedConn(true)
 }
 
+// PinReservedConn implements the SessionActions interface
+func (vc *vcursorImpl) PinReservedConn() {
+	vc.safeSession.PinReservedConn()
+}
+
 func (vc *vcursorImpl) InReservedConn() bool {
 	return vc.safeSession.InReservedConn()
 }
@@ -830,6 +893,42 @@ func (vc *vcursorImpl) GetQueryTimeout(queryTimeoutFromComments int) int {
 	return queryTimeout
 }
 
+// GetScatterOptions implements the SessionActions interface
+// The comment directives take precedence over the session settings.
+func (vc *vcursorImpl) GetScatterOptions(scatterOptionsFromComments engine.ScatterOptions) engine.ScatterOptions {
+	opts := scatterOptionsFromComments
+	if opts.Concurrency == 0 {
+		opts.Concurrency = int(vc.safeSession.GetScatterConcurrency())
+	}
+	if opts.ShardTimeout == 0 {
+		opts.ShardTimeout = int(vc.safeSession.GetShardTimeout())
+	}
+	opts.AllowPartial = opts.AllowPartial || vc.safeSession.GetAllowPartialScatter()
+	return opts
+}
+
+// SetScatterConcurrency implements the SessionActions interface
+func (vc *vcursorImpl) SetScatterConcurrency(concurrency int64) {
+	vc.safeSession.ScatterConcurrency = concurrency
+}
+
+// SetShardTimeout implements the SessionActions interface
+func (vc *vcursorImpl) SetShardTimeout(shardTimeout int64) {
+	vc.safeSession.ShardTimeout = shardTimeout
+}
+
+// SetAllowPartialScatter implements the SessionActions interface
+func (vc *vcursorImpl) SetAllowPartialScatter(_ context.Context, allowPartialScatter bool) error {
+	vc.safeSession.AllowPartialScatter = allowPartialScatter
+	return nil
+}
+
+// SetSkipReadRetry implements the SessionActions interface
+func (vc *vcursorImpl) SetSkipReadRetry(_ context.Context, skipReadRetry bool) error {
+	vc.safeSession.SkipReadRetry = skipReadRetry
+	return nil
+}
+
 // SetClientFoundRows implements the SessionActions interface
 func (vc *vcursorImpl) SetClientFoundRows(_ context.Context, clientFoundRows bool) error {
 	vc.safeSession.GetOrCreateOptions().ClientFoundRows = clientFoundRows
@@ -950,6 +1049,16 @@ func (vc *vcursorImpl) HasCreatedTempTable() {
 	vc.safeSession.GetOrCreateOptions().HasCreatedTempTables = true
 }
 
+// AddTempTable implements the SessionActions interface
+func (vc *vcursorImpl) AddTempTable(keyspace, table string) {
+	vc.safeSession.AddTempTable(keyspace, table)
+}
+
+// RemoveTempTable implements the SessionActions interface
+func (vc *vcursorImpl) RemoveTempTable(keyspace, table string) {
+	vc.safeSession.RemoveTempTable(keyspace, table)
+}
+
 // GetWarnings implements the SessionActions interface
 func (vc *vcursorImpl) GetWarnings() []*querypb.QueryWarning {
 	return vc.safeSession.GetWarnings()
@@ -1074,6 +1183,10 @@ func (vc *vcursorImpl) keyForPlan(ctx context.Context, query string, buf io.Stri
 			_, _ = buf.WriteString(vc.destination.String())
 		}
 	}
+	if split := vc.vschema.RoutingSplitKey(vc.routingBucket()); split != "" {
+		_, _ = buf.WriteString("+RoutingSplit:")
+		_, _ = buf.WriteString(split)
+	}
 	_, _ = buf.WriteString("+Query:")
 	_, _ = buf.WriteString(query)
 }
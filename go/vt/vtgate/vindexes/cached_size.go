@@ -416,6 +416,22 @@ func (cached *NumericStaticMap) CachedSize(alloc bool) int64 {
 	}
 	return size
 }
+func (cached *Plugin) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(32)
+	}
+	// field name string
+	size += hack.RuntimeAllocSize(int64(len(cached.name)))
+	// field vindex vitess.io/vitess/go/vt/vtgate/vindexes.SingleColumn
+	if cc, ok := cached.vindex.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	return size
+}
 func (cached *RegionExperimental) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -645,3 +661,22 @@ func (cached *prefixCFC) CachedSize(alloc bool) int64 {
 	size += cached.cfcCommon.CachedSize(true)
 	return size
 }
+func (cached *wasmVindex) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(48)
+	}
+	// field name string
+	size += hack.RuntimeAllocSize(int64(len(cached.name)))
+	// field unknownParams []string
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.unknownParams)) * int64(16))
+		for _, elem := range cached.unknownParams {
+			size += hack.RuntimeAllocSize(int64(len(elem)))
+		}
+	}
+	return size
+}
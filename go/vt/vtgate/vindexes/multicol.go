@@ -30,7 +30,8 @@ import (
 )
 
 var (
-	_ MultiColumn = (*MultiCol)(nil)
+	_ MultiColumn   = (*MultiCol)(nil)
+	_ ColumnCounter = (*MultiCol)(nil)
 )
 
 type MultiCol struct {
@@ -121,6 +122,11 @@ func (m *MultiCol) PartialVindex() bool {
 	return true
 }
 
+// ColumnCount implements the ColumnCounter interface.
+func (m *MultiCol) ColumnCount() int {
+	return m.noOfCols
+}
+
 func (m *MultiCol) mapKsid(colValues []sqltypes.Value) (bool, []byte, error) {
 	if m.noOfCols < len(colValues) {
 		// wrong number of column values were passed
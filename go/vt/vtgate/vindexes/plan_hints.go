@@ -0,0 +1,193 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// RewriteMergeJoins is the rewrite merging the routes of the two sides of a join
+// into a single route. When disabled by a plan hint, the joins are done by VTGate.
+const RewriteMergeJoins = "merge_joins"
+
+var planHintRewrites = map[string]bool{
+	RewriteMergeJoins: true,
+}
+
+// PlanHint is a planning hint of the VSchema, applying to the queries on a table,
+// to the queries with a given fingerprint, or to both.
+type PlanHint struct {
+	Name string `json:"name"`
+	// Table is the table of the keyspace the hint applies to.
+	Table string `json:"table,omitempty"`
+	// Query is the query the hint applies to, matched on its fingerprint.
+	Query string `json:"query,omitempty"`
+	// ForceScatter routes the queries on the Table to all the shards.
+	ForceScatter bool `json:"force_scatter,omitempty"`
+	// Vindex is the only vindex of the Table used to route the queries.
+	Vindex string `json:"vindex,omitempty"`
+	// DisableRewrites are the rewrites the planner must not apply.
+	DisableRewrites []string `json:"disable_rewrites,omitempty"`
+
+	keyspace    string
+	fingerprint string
+}
+
+// appliesToTable returns true if the hint applies to the queries on the table.
+func (hint *PlanHint) appliesToTable(table *Table) bool {
+	return table != nil && table.Keyspace != nil &&
+		table.Keyspace.Name == hint.keyspace && table.Name.String() == hint.Table
+}
+
+// PlanHints are the plan hints applying to a query.
+type PlanHints []*PlanHint
+
+// ForTables returns the hints applying to a query on the tables: the hints of
+// its fingerprint, and the hints of the tables.
+func (hints PlanHints) ForTables(tables []*Table) PlanHints {
+	var res PlanHints
+	for _, hint := range hints {
+		if hint.Query != "" {
+			res = append(res, hint)
+			continue
+		}
+		for _, table := range tables {
+			if hint.appliesToTable(table) {
+				res = append(res, hint)
+				break
+			}
+		}
+	}
+	return res
+}
+
+// ForTable returns the hint forcing the routing of the queries on the table,
+// or nil if there is none.
+func (hints PlanHints) ForTable(table *Table) *PlanHint {
+	for _, hint := range hints {
+		if (hint.ForceScatter || hint.Vindex != "") && hint.appliesToTable(table) {
+			return hint
+		}
+	}
+	return nil
+}
+
+// RewriteDisabled returns true if one of the hints disables the rewrite.
+func (hints PlanHints) RewriteDisabled(rewrite string) bool {
+	for _, hint := range hints {
+		for _, r := range hint.DisableRewrites {
+			if r == rewrite {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PlanHints returns the hints that may apply to the statement: the hints of
+// the tables, and the hints of the fingerprint of the statement. The hints of the
+// tables must then be filtered with ForTables once the tables are resolved.
+func (vschema *VSchema) PlanHints(stmt sqlparser.Statement) PlanHints {
+	if vschema == nil {
+		return nil
+	}
+	var hints PlanHints
+	fingerprint := ""
+	for _, ks := range vschema.Keyspaces {
+		for _, hint := range ks.PlanHints {
+			if hint.Query == "" {
+				hints = append(hints, hint)
+				continue
+			}
+			if fingerprint == "" {
+				fingerprint = sqlparser.Fingerprint(stmt)
+			}
+			if hint.fingerprint == fingerprint {
+				hints = append(hints, hint)
+			}
+		}
+	}
+	return hints
+}
+
+// buildPlanHints validates the plan hints of the keyspace against its tables.
+func buildPlanHints(ks *vschemapb.Keyspace, ksvschema *KeyspaceSchema) error {
+	names := make(map[string]bool, len(ks.PlanHints))
+	for _, ph := range ks.PlanHints {
+		if ph.Name == "" {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "plan hint must have a name")
+		}
+		if names[ph.Name] {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "duplicate plan hint %s", ph.Name)
+		}
+		names[ph.Name] = true
+
+		hint := &PlanHint{
+			Name:            ph.Name,
+			Table:           ph.Table,
+			Query:           ph.Query,
+			ForceScatter:    ph.ForceScatter,
+			Vindex:          ph.Vindex,
+			DisableRewrites: ph.DisableRewrites,
+			keyspace:        ksvschema.Keyspace.Name,
+		}
+		if hint.Table == "" && hint.Query == "" {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "plan hint %s must have a table or a query", ph.Name)
+		}
+		if hint.ForceScatter && hint.Vindex != "" {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "plan hint %s cannot both force a scatter and a vindex", ph.Name)
+		}
+		if hint.Table != "" {
+			table := ksvschema.Tables[hint.Table]
+			if table == nil {
+				return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "table %s not found for plan hint %s", hint.Table, ph.Name)
+			}
+			if hint.Vindex != "" && !hasColumnVindex(table, hint.Vindex) {
+				return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "vindex %s not found for table %s of plan hint %s", hint.Vindex, hint.Table, ph.Name)
+			}
+		} else if hint.ForceScatter || hint.Vindex != "" {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "plan hint %s must have a table to force a scatter or a vindex", ph.Name)
+		}
+		for _, rewrite := range hint.DisableRewrites {
+			if !planHintRewrites[rewrite] {
+				return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown rewrite %s in plan hint %s", rewrite, ph.Name)
+			}
+		}
+		if hint.Query != "" {
+			stmt, err := sqlparser.Parse(hint.Query)
+			if err != nil {
+				return vterrors.Wrapf(err, "cannot parse the query of plan hint %s", ph.Name)
+			}
+			hint.fingerprint = sqlparser.Fingerprint(stmt)
+		}
+		ksvschema.PlanHints = append(ksvschema.PlanHints, hint)
+	}
+	return nil
+}
+
+func hasColumnVindex(table *Table, name string) bool {
+	for _, cv := range table.ColumnVindexes {
+		if cv.Name == name {
+			return true
+		}
+	}
+	return false
+}
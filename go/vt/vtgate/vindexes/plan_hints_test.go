@@ -0,0 +1,159 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+func planHintsVSchema(hints ...*vschemapb.PlanHint) *vschemapb.SrvVSchema {
+	return &vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"ks": {
+				Sharded: true,
+				Vindexes: map[string]*vschemapb.Vindex{
+					"hash":   {Type: "hash"},
+					"xxhash": {Type: "xxhash"},
+				},
+				Tables: map[string]*vschemapb.Table{
+					"t1": {
+						ColumnVindexes: []*vschemapb.ColumnVindex{
+							{Column: "id", Name: "hash"},
+							{Column: "c1", Name: "xxhash"},
+						},
+					},
+					"t2": {
+						ColumnVindexes: []*vschemapb.ColumnVindex{
+							{Column: "id", Name: "hash"},
+						},
+					},
+				},
+				PlanHints: hints,
+			},
+		},
+	}
+}
+
+func TestBuildPlanHints(t *testing.T) {
+	tcases := []struct {
+		name string
+		hint *vschemapb.PlanHint
+		err  string
+	}{{
+		name: "table",
+		hint: &vschemapb.PlanHint{Name: "h", Table: "t1", ForceScatter: true},
+	}, {
+		name: "query",
+		hint: &vschemapb.PlanHint{Name: "h", Query: "select * from t1 join t2", DisableRewrites: []string{RewriteMergeJoins}},
+	}, {
+		name: "no name",
+		hint: &vschemapb.PlanHint{Table: "t1"},
+		err:  "plan hint must have a name",
+	}, {
+		name: "no table or query",
+		hint: &vschemapb.PlanHint{Name: "h", ForceScatter: true},
+		err:  "plan hint h must have a table or a query",
+	}, {
+		name: "no table",
+		hint: &vschemapb.PlanHint{Name: "h", Query: "select 1", Vindex: "hash"},
+		err:  "plan hint h must have a table to force a scatter or a vindex",
+	}, {
+		name: "unknown table",
+		hint: &vschemapb.PlanHint{Name: "h", Table: "t3"},
+		err:  "table t3 not found for plan hint h",
+	}, {
+		name: "unknown vindex",
+		hint: &vschemapb.PlanHint{Name: "h", Table: "t2", Vindex: "xxhash"},
+		err:  "vindex xxhash not found for table t2 of plan hint h",
+	}, {
+		name: "scatter and vindex",
+		hint: &vschemapb.PlanHint{Name: "h", Table: "t1", Vindex: "hash", ForceScatter: true},
+		err:  "plan hint h cannot both force a scatter and a vindex",
+	}, {
+		name: "unknown rewrite",
+		hint: &vschemapb.PlanHint{Name: "h", Table: "t1", DisableRewrites: []string{"unknown"}},
+		err:  "unknown rewrite unknown in plan hint h",
+	}, {
+		name: "bad query",
+		hint: &vschemapb.PlanHint{Name: "h", Query: "selec 1"},
+		err:  "cannot parse the query of plan hint h: syntax error at position 6 near 'selec'",
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			vschema := BuildVSchema(planHintsVSchema(tcase.hint))
+			err := vschema.Keyspaces["ks"].Error
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, vschema.Keyspaces["ks"].PlanHints, 1)
+		})
+	}
+
+	vschema := BuildVSchema(planHintsVSchema(&vschemapb.PlanHint{Name: "h", Table: "t1"}, &vschemapb.PlanHint{Name: "h", Table: "t2"}))
+	require.EqualError(t, vschema.Keyspaces["ks"].Error, "duplicate plan hint h")
+}
+
+func TestVSchemaPlanHints(t *testing.T) {
+	vschema := BuildVSchema(planHintsVSchema(
+		&vschemapb.PlanHint{Name: "scatter_t1", Table: "t1", ForceScatter: true},
+		&vschemapb.PlanHint{Name: "t2_by_hash", Table: "t2", Query: "select * from t2 where c1 = 1", Vindex: "hash"},
+		&vschemapb.PlanHint{Name: "no_merge", Query: "select * from t1 join t2 on t1.id = t2.id where t1.id = 2", DisableRewrites: []string{RewriteMergeJoins}},
+	))
+	require.NoError(t, vschema.Keyspaces["ks"].Error)
+	t1, err := vschema.FindTable("ks", "t1")
+	require.NoError(t, err)
+	t2, err := vschema.FindTable("ks", "t2")
+	require.NoError(t, err)
+
+	hintNames := func(hints PlanHints) []string {
+		var names []string
+		for _, hint := range hints {
+			names = append(names, hint.Name)
+		}
+		return names
+	}
+
+	// The query hints are matched on the fingerprint of the query.
+	stmt, err := sqlparser.Parse("select * from t2 where c1 = 42")
+	require.NoError(t, err)
+	hints := vschema.PlanHints(stmt)
+	assert.ElementsMatch(t, []string{"scatter_t1", "t2_by_hash"}, hintNames(hints))
+	hints = hints.ForTables([]*Table{t2})
+	assert.Equal(t, []string{"t2_by_hash"}, hintNames(hints))
+	assert.Nil(t, hints.ForTable(t1))
+	assert.Equal(t, "hash", hints.ForTable(t2).Vindex)
+	assert.False(t, hints.RewriteDisabled(RewriteMergeJoins))
+
+	stmt, err = sqlparser.Parse("select * from t1 join t2 on t1.id = t2.id where t1.id = 5")
+	require.NoError(t, err)
+	hints = vschema.PlanHints(stmt).ForTables([]*Table{t1, t2})
+	assert.ElementsMatch(t, []string{"scatter_t1", "no_merge"}, hintNames(hints))
+	assert.True(t, hints.ForTable(t1).ForceScatter)
+	assert.Nil(t, hints.ForTable(t2))
+	assert.True(t, hints.RewriteDisabled(RewriteMergeJoins))
+
+	assert.Nil(t, (*VSchema)(nil).PlanHints(stmt))
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+const (
+	pluginParamPath    = "plugin"
+	pluginParamVersion = "plugin_version"
+)
+
+var (
+	_ SingleColumn    = (*Plugin)(nil)
+	_ ParamValidating = (*Plugin)(nil)
+
+	// vindexPlugins caches the plugins by path, as a Go plugin can only be opened once,
+	// and a WASM module is only compiled once.
+	vindexPlugins  = map[string]*vindexPlugin{}
+	vindexPluginsM sync.Mutex
+)
+
+// vindexPlugin is a vindex implementation loaded from a Go plugin, see loadGoPlugin,
+// or from a WASM module, see loadWASMPlugin.
+type vindexPlugin struct {
+	version   string
+	newVindex func(string, map[string]string) (Vindex, error)
+}
+
+// Plugin defines a vindex whose implementation is loaded from the Go plugin or the
+// WASM module, with the .wasm extension, referenced by its plugin param, pinned to
+// the version of its plugin_version param.
+//
+// The other params are passed to the vindex of a Go plugin, which must be SingleColumn.
+// Go plugins run in the process of vtgate, which requires a vtgate built with cgo: only
+// their panics are returned as errors instead of crashing the process. WASM modules
+// run in a sandbox instead, without access to the host, and with bounded memory and
+// execution time.
+type Plugin struct {
+	name   string
+	vindex SingleColumn
+}
+
+// newPlugin creates a new Plugin.
+func newPlugin(name string, m map[string]string) (Vindex, error) {
+	path := m[pluginParamPath]
+	if path == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "plugin vindex %s missing %s param", name, pluginParamPath)
+	}
+	version := m[pluginParamVersion]
+	if version == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "plugin vindex %s missing %s param", name, pluginParamVersion)
+	}
+	p, err := loadVindexPlugin(path)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "plugin vindex %s cannot load %s", name, path)
+	}
+	if p.version != version {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "plugin vindex %s: %s has version %s, expected %s", name, path, p.version, version)
+	}
+
+	params := make(map[string]string, len(m))
+	for k, v := range m {
+		if k != pluginParamPath && k != pluginParamVersion {
+			params[k] = v
+		}
+	}
+	var vindex Vindex
+	err = protect(name, func() (err error) {
+		vindex, err = p.newVindex(name, params)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	single, ok := vindex.(SingleColumn)
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "plugin vindex %s: %T is not a single column vindex", name, vindex)
+	}
+	return &Plugin{
+		name:   name,
+		vindex: single,
+	}, nil
+}
+
+func loadVindexPlugin(path string) (*vindexPlugin, error) {
+	vindexPluginsM.Lock()
+	defer vindexPluginsM.Unlock()
+
+	if p, ok := vindexPlugins[path]; ok {
+		return p, nil
+	}
+
+	var p *vindexPlugin
+	var err error
+	if strings.EqualFold(filepath.Ext(path), ".wasm") {
+		p, err = loadWASMPlugin(path)
+	} else {
+		p, err = loadGoPlugin(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	vindexPlugins[path] = p
+	return p, nil
+}
+
+// protect runs f, and returns the panic of f as an error.
+func protect(name string, f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = vterrors.Errorf(vtrpcpb.Code_INTERNAL, "plugin vindex %s panicked: %v", name, r)
+		}
+	}()
+	return f()
+}
+
+// String returns the name of the vindex.
+func (vind *Plugin) String() string {
+	return vind.name
+}
+
+// Cost returns the cost of the vindex of the plugin.
+func (vind *Plugin) Cost() int {
+	return vind.vindex.Cost()
+}
+
+// IsUnique returns true if the vindex of the plugin is unique.
+func (vind *Plugin) IsUnique() bool {
+	return vind.vindex.IsUnique()
+}
+
+// NeedsVCursor satisfies the Vindex interface.
+func (vind *Plugin) NeedsVCursor() bool {
+	return vind.vindex.NeedsVCursor()
+}
+
+// Map can map ids to key.Destination objects.
+func (vind *Plugin) Map(ctx context.Context, vcursor VCursor, ids []sqltypes.Value) (out []key.Destination, err error) {
+	err = protect(vind.name, func() (err error) {
+		out, err = vind.vindex.Map(ctx, vcursor, ids)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(out) != len(ids) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "plugin vindex %s mapped %d ids to %d destinations", vind.name, len(ids), len(out))
+	}
+	return out, nil
+}
+
+// Verify returns true if ids maps to ksids.
+func (vind *Plugin) Verify(ctx context.Context, vcursor VCursor, ids []sqltypes.Value, ksids [][]byte) (out []bool, err error) {
+	err = protect(vind.name, func() (err error) {
+		out, err = vind.vindex.Verify(ctx, vcursor, ids, ksids)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(out) != len(ids) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "plugin vindex %s verified %d ids with %d results", vind.name, len(ids), len(out))
+	}
+	return out, nil
+}
+
+// UnknownParams implements the ParamValidating interface.
+func (vind *Plugin) UnknownParams() []string {
+	if pv, ok := vind.vindex.(ParamValidating); ok {
+		return pv.UnknownParams()
+	}
+	return nil
+}
+
+func init() {
+	Register("plugin", newPlugin)
+}
@@ -0,0 +1,60 @@
+//go:build cgo
+
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"fmt"
+	"plugin"
+)
+
+// loadGoPlugin loads the vindex implementation of a Go plugin, which must export:
+//
+//	var VindexVersion string
+//	func NewVindex(name string, params map[string]string) (vindexes.Vindex, error)
+//
+// Go plugins can only be opened by binaries built with cgo.
+func loadGoPlugin(path string) (*vindexPlugin, error) {
+	pl, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sym, err := pl.Lookup("VindexVersion")
+	if err != nil {
+		return nil, err
+	}
+	version, ok := sym.(*string)
+	if !ok {
+		return nil, fmt.Errorf("symbol VindexVersion must be of type `string`; have %T", sym)
+	}
+
+	sym, err = pl.Lookup("NewVindex")
+	if err != nil {
+		return nil, err
+	}
+	newVindex, ok := sym.(func(string, map[string]string) (Vindex, error))
+	if !ok {
+		return nil, fmt.Errorf("symbol NewVindex must be of type `func(string, map[string]string) (vindexes.Vindex, error)`; have %T", sym)
+	}
+
+	return &vindexPlugin{
+		version:   *version,
+		newVindex: newVindex,
+	}, nil
+}
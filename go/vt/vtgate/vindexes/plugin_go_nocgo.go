@@ -0,0 +1,30 @@
+//go:build !cgo
+
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// loadGoPlugin fails, as Go plugins can only be opened by binaries built with cgo,
+// which the release binaries are not.
+func loadGoPlugin(path string) (*vindexPlugin, error) {
+	return nil, vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "Go plugins require a vtgate built with CGO_ENABLED=1, use a WASM module instead")
+}
@@ -0,0 +1,33 @@
+//go:build !cgo
+
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadGoPluginWithoutCgo(t *testing.T) {
+	_, err := CreateVindex("plugin", "plugin", map[string]string{
+		"plugin":         "/plugins/missing.so",
+		"plugin_version": "v1",
+	})
+	assert.EqualError(t, err, "plugin vindex plugin cannot load /plugins/missing.so: Go plugins require a vtgate built with CGO_ENABLED=1, use a WASM module instead")
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+)
+
+// panickingVindex is a vindex that panics on the ids set to "panic".
+type panickingVindex struct {
+	*XXHash
+}
+
+func (vind *panickingVindex) Map(ctx context.Context, vcursor VCursor, ids []sqltypes.Value) ([]key.Destination, error) {
+	for _, id := range ids {
+		if id.ToString() == "panic" {
+			panic("bad id")
+		}
+	}
+	return vind.XXHash.Map(ctx, vcursor, ids)
+}
+
+func init() {
+	vindexPlugins["/plugins/xxhash.so"] = &vindexPlugin{
+		version:   "v1",
+		newVindex: newXXHash,
+	}
+	vindexPlugins["/plugins/panicking.so"] = &vindexPlugin{
+		version: "v1",
+		newVindex: func(name string, m map[string]string) (Vindex, error) {
+			if m["panic"] != "" {
+				panic("bad params")
+			}
+			vindex, err := newXXHash(name, m)
+			if err != nil {
+				return nil, err
+			}
+			return &panickingVindex{XXHash: vindex.(*XXHash)}, nil
+		},
+	}
+	vindexPlugins["/plugins/multicol.so"] = &vindexPlugin{
+		version: "v1",
+		newVindex: func(name string, m map[string]string) (Vindex, error) {
+			return newMultiCol(name, map[string]string{"column_count": "2"})
+		},
+	}
+}
+
+func pluginCreateVindexTestCase(
+	testName string,
+	vindexParams map[string]string,
+	expectErr error,
+	expectUnknownParams []string,
+) createVindexTestCase {
+	return createVindexTestCase{
+		testName: testName,
+
+		vindexType:   "plugin",
+		vindexName:   "plugin",
+		vindexParams: vindexParams,
+
+		expectCost:          1,
+		expectErr:           expectErr,
+		expectIsUnique:      true,
+		expectNeedsVCursor:  false,
+		expectString:        "plugin",
+		expectUnknownParams: expectUnknownParams,
+	}
+}
+
+func TestPluginCreateVindex(t *testing.T) {
+	cases := []createVindexTestCase{
+		pluginCreateVindexTestCase(
+			"no params",
+			nil,
+			errors.New("plugin vindex plugin missing plugin param"),
+			nil,
+		),
+		pluginCreateVindexTestCase(
+			"no version",
+			map[string]string{
+				"plugin": "/plugins/xxhash.so",
+			},
+			errors.New("plugin vindex plugin missing plugin_version param"),
+			nil,
+		),
+		pluginCreateVindexTestCase(
+			"wasm module",
+			map[string]string{
+				"plugin":         "testdata/identity.wasm",
+				"plugin_version": "v1",
+			},
+			nil,
+			nil,
+		),
+		pluginCreateVindexTestCase(
+			"wasm module params",
+			map[string]string{
+				"plugin":         "testdata/identity.wasm",
+				"plugin_version": "v1",
+				"hello":          "world",
+			},
+			nil,
+			[]string{"hello"},
+		),
+		pluginCreateVindexTestCase(
+			"wasm module version mismatch",
+			map[string]string{
+				"plugin":         "testdata/identity.wasm",
+				"plugin_version": "v2",
+			},
+			errors.New("plugin vindex plugin: testdata/identity.wasm has version v1, expected v2"),
+			nil,
+		),
+		pluginCreateVindexTestCase(
+			"missing wasm module",
+			map[string]string{
+				"plugin":         "testdata/missing.wasm",
+				"plugin_version": "v1",
+			},
+			errors.New("plugin vindex plugin cannot load testdata/missing.wasm: open testdata/missing.wasm: no such file or directory"),
+			nil,
+		),
+		pluginCreateVindexTestCase(
+			"version mismatch",
+			map[string]string{
+				"plugin":         "/plugins/xxhash.so",
+				"plugin_version": "v2",
+			},
+			errors.New("plugin vindex plugin: /plugins/xxhash.so has version v1, expected v2"),
+			nil,
+		),
+		pluginCreateVindexTestCase(
+			"panic",
+			map[string]string{
+				"plugin":         "/plugins/panicking.so",
+				"plugin_version": "v1",
+				"panic":          "1",
+			},
+			errors.New("plugin vindex plugin panicked: bad params"),
+			nil,
+		),
+		pluginCreateVindexTestCase(
+			"multi column",
+			map[string]string{
+				"plugin":         "/plugins/multicol.so",
+				"plugin_version": "v1",
+			},
+			errors.New("plugin vindex plugin: *vindexes.MultiCol is not a single column vindex"),
+			nil,
+		),
+		pluginCreateVindexTestCase(
+			"plugin params",
+			map[string]string{
+				"plugin":         "/plugins/xxhash.so",
+				"plugin_version": "v1",
+			},
+			nil,
+			nil,
+		),
+		pluginCreateVindexTestCase(
+			"unknown params",
+			map[string]string{
+				"plugin":         "/plugins/xxhash.so",
+				"plugin_version": "v1",
+				"hello":          "world",
+			},
+			nil,
+			[]string{"hello"},
+		),
+	}
+
+	testCreateVindexes(t, cases)
+}
+
+func TestPluginMap(t *testing.T) {
+	vindex, err := CreateVindex("plugin", "plugin", map[string]string{
+		"plugin":         "/plugins/panicking.so",
+		"plugin_version": "v1",
+	})
+	require.NoError(t, err)
+	plugin := vindex.(SingleColumn)
+
+	got, err := plugin.Map(context.Background(), nil, []sqltypes.Value{sqltypes.NewInt64(1)})
+	require.NoError(t, err)
+	want, err := xxHash.Map(context.Background(), nil, []sqltypes.Value{sqltypes.NewInt64(1)})
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	ok, err := plugin.Verify(context.Background(), nil, []sqltypes.Value{sqltypes.NewInt64(1)}, [][]byte{[]byte(want[0].(key.DestinationKeyspaceID))})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true}, ok)
+
+	// The panics of the vindex are returned as errors.
+	_, err = plugin.Map(context.Background(), nil, []sqltypes.Value{sqltypes.NewVarChar("panic")})
+	require.EqualError(t, err, "plugin vindex plugin panicked: bad id")
+}
+
+func TestPluginWASMMap(t *testing.T) {
+	vindex, err := CreateVindex("plugin", "plugin", map[string]string{
+		"plugin":         "testdata/identity.wasm",
+		"plugin_version": "v1",
+	})
+	require.NoError(t, err)
+	plugin := vindex.(SingleColumn)
+
+	ids := []sqltypes.Value{sqltypes.NewVarChar("abc"), sqltypes.NewInt64(1234)}
+	got, err := plugin.Map(context.Background(), nil, ids)
+	require.NoError(t, err)
+	assert.Equal(t, []key.Destination{
+		key.DestinationKeyspaceID("abc"),
+		key.DestinationKeyspaceID("1234"),
+	}, got)
+
+	ok, err := plugin.Verify(context.Background(), nil, ids, [][]byte{[]byte("abc"), []byte("123")})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, false}, ok)
+
+	// The traps of the module are returned as errors, and the module is
+	// instantiated again for the next ids.
+	_, err = plugin.Map(context.Background(), nil, []sqltypes.Value{sqltypes.NewVarChar("")})
+	require.ErrorContains(t, err, "plugin vindex plugin failed: ")
+	got, err = plugin.Map(context.Background(), nil, ids[:1])
+	require.NoError(t, err)
+	assert.Equal(t, []key.Destination{key.DestinationKeyspaceID("abc")}, got)
+
+	// The module is stopped when it runs for too long.
+	start := time.Now()
+	_, err = plugin.Map(context.Background(), nil, []sqltypes.Value{sqltypes.NewVarChar("x")})
+	require.ErrorContains(t, err, "plugin vindex plugin failed: ")
+	assert.Less(t, time.Since(start), 10*wasmCallTimeout)
+	got, err = plugin.Map(context.Background(), nil, ids[:1])
+	require.NoError(t, err)
+	assert.Equal(t, []key.Destination{key.DestinationKeyspaceID("abc")}, got)
+}
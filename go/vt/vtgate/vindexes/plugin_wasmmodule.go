@@ -0,0 +1,288 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+const (
+	// wasmMemoryLimitPages limits the memory of a WASM module to 16MiB.
+	wasmMemoryLimitPages = 256
+	// wasmCallTimeout limits the time a WASM module takes to map the ids of a query.
+	wasmCallTimeout = time.Second
+)
+
+var (
+	_ SingleColumn    = (*wasmVindex)(nil)
+	_ ParamValidating = (*wasmVindex)(nil)
+)
+
+// wasmModule is a WASM module implementing a vindex. It doesn't import anything,
+// so it has no access to the host, and must export:
+//
+//	memory
+//	vindex_version() i64: the location of its version in its memory.
+//	vindex_alloc(len i32) i32: the offset of len bytes of its memory where the id to map is written.
+//	vindex_map(ptr i32, len i32) i64: the location of the keyspace id of the id, or -1 to map
+//	    it to no keyspace id.
+//
+// The locations are the offset of the bytes in the memory, shifted left by 32 bits, ORed with
+// their length.
+type wasmModule struct {
+	path     string
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+
+	// mu protects module, as a module can only run one function at a time.
+	mu sync.Mutex
+	// module is the instance of the module, or nil if it has to be instantiated
+	// again because a function of the previous one failed.
+	module api.Module
+}
+
+// loadWASMPlugin compiles the WASM module of a vindex, and reads its version.
+func loadWASMPlugin(path string) (*vindexPlugin, error) {
+	binary, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	runtime := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(wasmMemoryLimitPages).
+		WithCloseOnContextDone(true))
+	compiled, err := runtime.CompileModule(ctx, binary)
+	if err != nil {
+		runtime.Close(ctx)
+		return nil, err
+	}
+	if imports := compiled.ImportedFunctions(); len(imports) > 0 {
+		runtime.Close(ctx)
+		module, name, _ := imports[0].Import()
+		return nil, fmt.Errorf("WASM module imports %s.%s, but vindex modules cannot import anything", module, name)
+	}
+
+	m := &wasmModule{
+		path:     path,
+		runtime:  runtime,
+		compiled: compiled,
+	}
+	var version []byte
+	err = m.call(ctx, func(module api.Module) (err error) {
+		version, err = m.callBytes(ctx, module, "vindex_version")
+		return err
+	})
+	if err != nil {
+		runtime.Close(ctx)
+		return nil, err
+	}
+
+	return &vindexPlugin{
+		version: string(version),
+		newVindex: func(name string, params map[string]string) (Vindex, error) {
+			// The params are not passed to WASM modules.
+			var unknownParams []string
+			for param := range params {
+				unknownParams = append(unknownParams, param)
+			}
+			sort.Strings(unknownParams)
+			return &wasmVindex{
+				name:          name,
+				module:        m,
+				unknownParams: unknownParams,
+			}, nil
+		},
+	}, nil
+}
+
+// call runs f with the instance of the module, which is instantiated again
+// after a failure, as it may be left in an inconsistent state.
+func (m *wasmModule) call(ctx context.Context, f func(module api.Module) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.module == nil {
+		module, err := m.runtime.InstantiateModule(ctx, m.compiled, wazero.NewModuleConfig().WithName(""))
+		if err != nil {
+			return err
+		}
+		m.module = module
+	}
+	if err := f(m.module); err != nil {
+		m.module.Close(context.Background())
+		m.module = nil
+		return err
+	}
+	return nil
+}
+
+// callBytes calls a function of the module returning a location, and returns
+// a copy of the bytes at that location, or nil if the location is negative.
+func (m *wasmModule) callBytes(ctx context.Context, module api.Module, name string, params ...uint64) ([]byte, error) {
+	function := module.ExportedFunction(name)
+	if function == nil {
+		return nil, fmt.Errorf("WASM module %s does not export %s", m.path, name)
+	}
+	results, err := function.Call(ctx, params...)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) != 1 {
+		return nil, fmt.Errorf("WASM module %s: %s returned %d values, expected 1", m.path, name, len(results))
+	}
+	if int64(results[0]) < 0 {
+		return nil, nil
+	}
+	ptr, length := uint32(results[0]>>32), uint32(results[0])
+	b, ok := memory(module).Read(ptr, length)
+	if !ok {
+		return nil, fmt.Errorf("WASM module %s: %s returned %d bytes at %d, out of its memory", m.path, name, length, ptr)
+	}
+	return bytes.Clone(b), nil
+}
+
+// mapID maps an id to its keyspace id with the instance of the module.
+func (m *wasmModule) mapID(ctx context.Context, module api.Module, id []byte) ([]byte, error) {
+	alloc := module.ExportedFunction("vindex_alloc")
+	if alloc == nil {
+		return nil, fmt.Errorf("WASM module %s does not export vindex_alloc", m.path)
+	}
+	results, err := alloc.Call(ctx, uint64(len(id)))
+	if err != nil {
+		return nil, err
+	}
+	if len(results) != 1 {
+		return nil, fmt.Errorf("WASM module %s: vindex_alloc returned %d values, expected 1", m.path, len(results))
+	}
+	ptr := uint32(results[0])
+	if !memory(module).Write(ptr, id) {
+		return nil, fmt.Errorf("WASM module %s: vindex_alloc returned %d, out of its memory", m.path, ptr)
+	}
+	return m.callBytes(ctx, module, "vindex_map", uint64(ptr), uint64(len(id)))
+}
+
+// memory returns the memory exported by a module, or an empty memory.
+func memory(module api.Module) api.Memory {
+	if mem := module.ExportedMemory("memory"); mem != nil {
+		return mem
+	}
+	return module.Memory()
+}
+
+// wasmVindex is the vindex of a WASM module. It is functional and unique, and
+// maps the bytes of the ids.
+type wasmVindex struct {
+	name          string
+	module        *wasmModule
+	unknownParams []string
+}
+
+// String returns the name of the vindex.
+func (vind *wasmVindex) String() string {
+	return vind.name
+}
+
+// Cost returns the cost of this vindex as 1.
+func (vind *wasmVindex) Cost() int {
+	return 1
+}
+
+// IsUnique returns true since the Vindex is unique.
+func (vind *wasmVindex) IsUnique() bool {
+	return true
+}
+
+// NeedsVCursor satisfies the Vindex interface.
+func (vind *wasmVindex) NeedsVCursor() bool {
+	return false
+}
+
+// Map can map ids to key.Destination objects.
+func (vind *wasmVindex) Map(ctx context.Context, vcursor VCursor, ids []sqltypes.Value) ([]key.Destination, error) {
+	ksids, err := vind.mapIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]key.Destination, 0, len(ids))
+	for _, ksid := range ksids {
+		if ksid == nil {
+			out = append(out, key.DestinationNone{})
+			continue
+		}
+		out = append(out, key.DestinationKeyspaceID(ksid))
+	}
+	return out, nil
+}
+
+// Verify returns true if ids maps to ksids.
+func (vind *wasmVindex) Verify(ctx context.Context, vcursor VCursor, ids []sqltypes.Value, ksids [][]byte) ([]bool, error) {
+	mapped, err := vind.mapIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]bool, 0, len(ids))
+	for i, ksid := range mapped {
+		out = append(out, ksid != nil && bytes.Equal(ksid, ksids[i]))
+	}
+	return out, nil
+}
+
+// mapIDs maps the ids to their keyspace ids, nil for no keyspace id, within wasmCallTimeout.
+func (vind *wasmVindex) mapIDs(ctx context.Context, ids []sqltypes.Value) ([][]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, wasmCallTimeout)
+	defer cancel()
+
+	ksids := make([][]byte, 0, len(ids))
+	err := vind.module.call(ctx, func(module api.Module) error {
+		for _, id := range ids {
+			idBytes, err := id.ToBytes()
+			if err != nil {
+				return err
+			}
+			ksid, err := vind.module.mapID(ctx, module, idBytes)
+			if err != nil {
+				return err
+			}
+			ksids = append(ksids, ksid)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "plugin vindex %s failed: %v", vind.name, err)
+	}
+	return ksids, nil
+}
+
+// UnknownParams implements the ParamValidating interface.
+func (vind *wasmVindex) UnknownParams() []string {
+	return vind.unknownParams
+}
@@ -35,6 +35,7 @@ const (
 var (
 	_ MultiColumn     = (*RegionExperimental)(nil)
 	_ ParamValidating = (*RegionExperimental)(nil)
+	_ ColumnCounter   = (*RegionExperimental)(nil)
 
 	regionExperimentalParams = []string{
 		regionExperimentalParamRegionBytes,
@@ -157,6 +158,11 @@ func (ge *RegionExperimental) PartialVindex() bool {
 	return true
 }
 
+// ColumnCount implements the ColumnCounter interface.
+func (ge *RegionExperimental) ColumnCount() int {
+	return 2
+}
+
 // UnknownParams implements the ParamValidating interface.
 func (ge *RegionExperimental) UnknownParams() []string {
 	return ge.unknownParams
@@ -36,7 +36,8 @@ const (
 )
 
 var (
-	_ MultiColumn = (*RegionJSON)(nil)
+	_ MultiColumn   = (*RegionJSON)(nil)
+	_ ColumnCounter = (*RegionJSON)(nil)
 
 	regionJSONParams = []string{
 		regionJSONParamRegionBytes,
@@ -169,6 +170,11 @@ func (rv *RegionJSON) PartialVindex() bool {
 	return false
 }
 
+// ColumnCount implements the ColumnCounter interface.
+func (rv *RegionJSON) ColumnCount() int {
+	return 2
+}
+
 // UnknownParams implements the ParamValidating interface.
 func (rv *RegionJSON) UnknownParams() []string {
 	return rv.unknownParams
@@ -0,0 +1,22 @@
+;; identity.wasm is the WASM module of the plugin vindex tests, version v1.
+;; It maps the ids to themselves, traps on the empty ids and loops forever
+;; on the ids of one byte.
+(module
+  (memory (export "memory") 1)
+  (data (i32.const 0) "v1")
+
+  (func (export "vindex_version") (result i64)
+    ;; "v1" at offset 0.
+    i64.const 2)
+
+  (func (export "vindex_alloc") (param $len i32) (result i32)
+    i32.const 1024)
+
+  (func (export "vindex_map") (param $ptr i32) (param $len i32) (result i64)
+    (if (i32.eqz (local.get $len))
+      (then unreachable))
+    (if (i32.eq (local.get $len) (i32.const 1))
+      (then (loop $forever (br $forever))))
+    (i64.or
+      (i64.shl (i64.extend_i32_u (local.get $ptr)) (i64.const 32))
+      (i64.extend_i32_u (local.get $len)))))
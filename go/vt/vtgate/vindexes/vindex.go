@@ -114,6 +114,13 @@ type (
 		PartialVindex() bool
 	}
 
+	// ColumnCounter is implemented by the multi-column vindexes that map a fixed number of columns.
+	// The tables must declare that many columns for them, which is checked when the vschema is built.
+	ColumnCounter interface {
+		MultiColumn
+		ColumnCount() int
+	}
+
 	// Hashing defined the interface for the vindexes that export the Hash function to be used by multi-column vindex.
 	Hashing interface {
 		Hash(id sqltypes.Value) ([]byte, error)
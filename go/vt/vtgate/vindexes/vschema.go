@@ -20,6 +20,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"os"
 	"sort"
 	"strings"
@@ -70,6 +71,9 @@ type VSchema struct {
 	uniqueVindexes    map[string]Vindex
 	Keyspaces         map[string]*KeyspaceSchema `json:"keyspaces"`
 	ShardRoutingRules map[string]string          `json:"shard_routing_rules"`
+	// splitRoutingRules contains the sorted names of the routing rules that split their
+	// traffic across several tables.
+	splitRoutingRules []string
 	// created is the time when the VSchema object was created. Used to detect if a cached
 	// copy of the vschema is stale.
 	created time.Time
@@ -78,7 +82,37 @@ type VSchema struct {
 // RoutingRule represents one routing rule.
 type RoutingRule struct {
 	Tables []*Table
-	Error  error
+	// Percentages split the traffic across the Tables, when there is more than one.
+	Percentages []uint32
+	Error       error
+}
+
+// RoutingBuckets is the number of buckets the sessions are hashed into by RoutingBucket.
+// A routing rule with percentages routes the sessions of each bucket to one of its tables.
+const RoutingBuckets = 100
+
+// RoutingBucket returns the bucket of a session, between 0 and RoutingBuckets-1. The
+// sessions without a key, such as the gRPC sessions that have no session UUID, all get
+// the bucket 0, and are routed to the first table of the rules.
+func RoutingBucket(key string) uint32 {
+	if key == "" {
+		return 0
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return h.Sum32() % RoutingBuckets
+}
+
+// target returns the index of the table that the sessions of the bucket are routed to.
+func (rr *RoutingRule) target(bucket uint32) int {
+	var sum uint32
+	for i, pct := range rr.Percentages {
+		sum += pct
+		if bucket < sum {
+			return i
+		}
+	}
+	return 0
 }
 
 // MarshalJSON returns a JSON representation of Column.
@@ -87,7 +121,11 @@ func (rr *RoutingRule) MarshalJSON() ([]byte, error) {
 		return json.Marshal(rr.Error.Error())
 	}
 	tables := make([]string, 0, len(rr.Tables))
-	for _, t := range rr.Tables {
+	for i, t := range rr.Tables {
+		if len(rr.Percentages) > 0 {
+			tables = append(tables, fmt.Sprintf("%s:%d%%", t.String(), rr.Percentages[i]))
+			continue
+		}
 		tables = append(tables, t.String())
 	}
 
@@ -115,6 +153,9 @@ type Table struct {
 	// Source is a keyspace-qualified table name that points to the source of a
 	// reference table. Only applicable for tables with Type set to "reference".
 	Source *Source `json:"source,omitempty"`
+	// ResultCache is set if vtgate caches the results of the read-only
+	// queries on this table.
+	ResultCache *ResultCache `json:"result_cache,omitempty"`
 
 	ChildForeignKeys  []ChildFKInfo  `json:"child_foreign_keys,omitempty"`
 	ParentForeignKeys []ParentFKInfo `json:"parent_foreign_keys,omitempty"`
@@ -125,6 +166,23 @@ func (t *Table) GetTableName() sqlparser.TableName {
 	return sqlparser.NewTableNameWithQualifier(t.Name.String(), t.Keyspace.Name)
 }
 
+// TemporaryTablePinned is the keyspace id that the temporary tables of the sharded
+// keyspaces are pinned to: they are created on the first shard of the keyspace.
+var TemporaryTablePinned = []byte{0}
+
+// NewTemporaryTable returns the Table of a temporary table of the keyspace.
+// In a sharded keyspace, the table is pinned to TemporaryTablePinned.
+func NewTemporaryTable(keyspace *Keyspace, name string) *Table {
+	t := &Table{
+		Name:     sqlparser.NewIdentifierCS(name),
+		Keyspace: keyspace,
+	}
+	if keyspace.Sharded {
+		t.Pinned = TemporaryTablePinned
+	}
+	return t
+}
+
 // Keyspace contains the keyspcae info for each Table.
 type Keyspace struct {
 	Name    string
@@ -198,7 +256,12 @@ type KeyspaceSchema struct {
 	Tables         map[string]*Table
 	Vindexes       map[string]Vindex
 	Views          map[string]sqlparser.SelectStatement
-	Error          error
+	// Procedures and Functions contain the create statements of the stored
+	// procedures and functions known by the schema tracker.
+	Procedures map[string]string
+	Functions  map[string]string
+	PlanHints  PlanHints
+	Error      error
 }
 
 type ksJSON struct {
@@ -207,6 +270,9 @@ type ksJSON struct {
 	Tables         map[string]*Table `json:"tables,omitempty"`
 	Vindexes       map[string]Vindex `json:"vindexes,omitempty"`
 	Views          map[string]string `json:"views,omitempty"`
+	Procedures     map[string]string `json:"procedures,omitempty"`
+	Functions      map[string]string `json:"functions,omitempty"`
+	PlanHints      PlanHints         `json:"planHints,omitempty"`
 	Error          string            `json:"error,omitempty"`
 }
 
@@ -240,6 +306,9 @@ func (ks *KeyspaceSchema) MarshalJSON() ([]byte, error) {
 		Tables:         ks.Tables,
 		ForeignKeyMode: ks.ForeignKeyMode.String(),
 		Vindexes:       ks.Vindexes,
+		Procedures:     ks.Procedures,
+		Functions:      ks.Functions,
+		PlanHints:      ks.PlanHints,
 	}
 	if ks.Error != nil {
 		ksJ.Error = ks.Error.Error()
@@ -264,6 +333,12 @@ type Source struct {
 	sqlparser.TableName
 }
 
+// ResultCache contains the result caching settings of a table.
+type ResultCache struct {
+	// TTL is how long a result is cached for, at most. Zero means no limit.
+	TTL time.Duration `json:"ttl,omitempty"`
+}
+
 func (source *Source) String() string {
 	buf := sqlparser.NewTrackedBuffer(nil)
 	source.Format(buf)
@@ -332,6 +407,9 @@ func buildKeyspaces(source *vschemapb.SrvVSchema, vschema *VSchema) {
 		}
 		vschema.Keyspaces[ksname] = ksvschema
 		ksvschema.Error = buildTables(ks, vschema, ksvschema)
+		if ksvschema.Error == nil {
+			ksvschema.Error = buildPlanHints(ks, ksvschema)
+		}
 	}
 }
 
@@ -587,6 +665,16 @@ func buildTables(ks *vschemapb.Keyspace, vschema *VSchema, ksvschema *KeyspaceSc
 			}
 			t.Pinned = decoded
 		}
+		if table.ResultCache != nil {
+			if table.ResultCache.TtlSeconds < 0 {
+				return vterrors.Errorf(
+					vtrpcpb.Code_INVALID_ARGUMENT,
+					"negative result cache ttl for table: %s",
+					tname,
+				)
+			}
+			t.ResultCache = &ResultCache{TTL: time.Duration(table.ResultCache.TtlSeconds) * time.Second}
+		}
 
 		// If keyspace is sharded, then any table that's not a reference or pinned must have vindexes.
 		if keyspace.Sharded && t.Type != TypeReference && table.Pinned == "" && len(table.ColumnVindexes) == 0 {
@@ -708,6 +796,17 @@ func buildTables(ks *vschemapb.Keyspace, vschema *VSchema, ksvschema *KeyspaceSc
 					tname,
 				)
 			}
+			if counter, ok := vindex.(ColumnCounter); ok && len(columns) != counter.ColumnCount() {
+				// The rows would not map to a keyspace id.
+				return vterrors.Errorf(
+					vtrpcpb.Code_INVALID_ARGUMENT,
+					"multi-column vindex %s maps %d columns, but %d columns are declared for table %s",
+					ind.Name,
+					counter.ColumnCount(),
+					len(columns),
+					tname,
+				)
+			}
 			if !mcv.PartialVindex() {
 				// Partial column selection not allowed.
 				// Do not create subset column vindex.
@@ -760,7 +859,7 @@ func resolveAutoIncrement(source *vschemapb.SrvVSchema, vschema *VSchema) {
 			var seq *Table
 			if err == nil {
 				// Ensure that sequence tables also obey routing rules.
-				seq, err = vschema.FindRoutedTable(seqks, seqtab, topodatapb.TabletType_PRIMARY)
+				seq, err = vschema.FindRoutedTable(seqks, seqtab, topodatapb.TabletType_PRIMARY, 0)
 				if seq == nil && err == nil {
 					err = vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "table %s not found", seqtab)
 				}
@@ -840,7 +939,7 @@ func buildRoutingRule(source *vschemapb.SrvVSchema, vschema *VSchema) {
 outer:
 	for _, rule := range source.RoutingRules.Rules {
 		rr := &RoutingRule{}
-		if len(rule.ToTables) > 1 {
+		if len(rule.ToTables) > 1 && len(rule.Percentages) == 0 {
 			vschema.RoutingRules[rule.FromTable] = &RoutingRule{
 				Error: vterrors.Errorf(
 					vtrpcpb.Code_INVALID_ARGUMENT,
@@ -851,6 +950,13 @@ outer:
 			}
 			continue
 		}
+		if len(rule.Percentages) > 0 {
+			if err := checkRoutingPercentages(rule); err != nil {
+				vschema.RoutingRules[rule.FromTable] = &RoutingRule{Error: err}
+				continue
+			}
+			rr.Percentages = rule.Percentages
+		}
 		for _, toTable := range rule.ToTables {
 			if _, ok := vschema.RoutingRules[rule.FromTable]; ok {
 				vschema.RoutingRules[rule.FromTable] = &RoutingRule{
@@ -904,6 +1010,46 @@ outer:
 		}
 		vschema.RoutingRules[rule.FromTable] = rr
 	}
+	for name, rr := range vschema.RoutingRules {
+		if rr.Error == nil && len(rr.Percentages) > 0 {
+			vschema.splitRoutingRules = append(vschema.splitRoutingRules, name)
+		}
+	}
+	sort.Strings(vschema.splitRoutingRules)
+}
+
+func checkRoutingPercentages(rule *vschemapb.RoutingRule) error {
+	if len(rule.Percentages) != len(rule.ToTables) {
+		return vterrors.Errorf(
+			vtrpcpb.Code_INVALID_ARGUMENT,
+			"table %v has %d percentages for %d targets",
+			rule.FromTable,
+			len(rule.Percentages),
+			len(rule.ToTables),
+		)
+	}
+	var sum uint32
+	for _, pct := range rule.Percentages {
+		// Reject large values up front, so that the sum cannot wrap around.
+		if pct > RoutingBuckets {
+			return vterrors.Errorf(
+				vtrpcpb.Code_INVALID_ARGUMENT,
+				"table %v has a percentage of %d, which is above 100",
+				rule.FromTable,
+				pct,
+			)
+		}
+		sum += pct
+	}
+	if sum != RoutingBuckets {
+		return vterrors.Errorf(
+			vtrpcpb.Code_INVALID_ARGUMENT,
+			"the percentages of table %v add up to %d instead of 100",
+			rule.FromTable,
+			sum,
+		)
+	}
+	return nil
 }
 
 func buildShardRoutingRule(source *vschemapb.SrvVSchema, vschema *VSchema) {
@@ -1026,8 +1172,9 @@ func (vschema *VSchema) FirstKeyspace() *Keyspace {
 	return ks.Keyspace
 }
 
-// FindRoutedTable finds a table checking the routing rules.
-func (vschema *VSchema) FindRoutedTable(keyspace, tablename string, tabletType topodatapb.TabletType) (*Table, error) {
+// FindRoutedTable finds a table checking the routing rules. The routing rules that
+// split their traffic route the tables with the routing bucket of the session.
+func (vschema *VSchema) FindRoutedTable(keyspace, tablename string, tabletType topodatapb.TabletType, bucket uint32) (*Table, error) {
 	qualified := tablename
 	if keyspace != "" {
 		qualified = keyspace + "." + tablename
@@ -1048,7 +1195,7 @@ func (vschema *VSchema) FindRoutedTable(keyspace, tablename string, tabletType t
 					tablename,
 				)
 			}
-			return rr.Tables[0], nil
+			return rr.Tables[rr.target(bucket)], nil
 		}
 	}
 	return vschema.findTable(
@@ -1059,8 +1206,8 @@ func (vschema *VSchema) FindRoutedTable(keyspace, tablename string, tabletType t
 }
 
 // FindTableOrVindex finds a table or a Vindex by name using Find and FindVindex.
-func (vschema *VSchema) FindTableOrVindex(keyspace, name string, tabletType topodatapb.TabletType) (*Table, Vindex, error) {
-	tables, err := vschema.FindRoutedTable(keyspace, name, tabletType)
+func (vschema *VSchema) FindTableOrVindex(keyspace, name string, tabletType topodatapb.TabletType, bucket uint32) (*Table, Vindex, error) {
+	tables, err := vschema.FindRoutedTable(keyspace, name, tabletType, bucket)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1077,6 +1224,23 @@ func (vschema *VSchema) FindTableOrVindex(keyspace, name string, tabletType topo
 	return nil, nil, NotFoundError{TableName: name}
 }
 
+// RoutingSplitKey returns the targets of the routing rules that split their traffic
+// for the sessions of the bucket, to cache the plans of the sessions that are routed to
+// different tables apart. It is empty if no routing rule splits its traffic.
+func (vschema *VSchema) RoutingSplitKey(bucket uint32) string {
+	if len(vschema.splitRoutingRules) == 0 {
+		return ""
+	}
+	var key strings.Builder
+	for i, name := range vschema.splitRoutingRules {
+		if i > 0 {
+			key.WriteByte(',')
+		}
+		fmt.Fprintf(&key, "%d", vschema.RoutingRules[name].target(bucket))
+	}
+	return key.String()
+}
+
 func (vschema *VSchema) FindView(keyspace, name string) sqlparser.SelectStatement {
 	if keyspace == "" {
 		switch {
@@ -24,6 +24,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -512,6 +513,37 @@ func TestVSchemaPinned(t *testing.T) {
 	assert.Equal(t, "\x80", string(t1.Pinned))
 }
 
+func TestVSchemaResultCache(t *testing.T) {
+	good := vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"unsharded": {
+				Tables: map[string]*vschemapb.Table{
+					"t1": {
+						ResultCache: &vschemapb.ResultCache{TtlSeconds: 30}},
+					"t2": {}}}}}
+
+	got := BuildVSchema(&good)
+	require.NoError(t, got.Keyspaces["unsharded"].Error)
+
+	t1, err := got.FindTable("unsharded", "t1")
+	require.NoError(t, err)
+	assert.Equal(t, &ResultCache{TTL: 30 * time.Second}, t1.ResultCache)
+
+	t2, err := got.FindTable("unsharded", "t2")
+	require.NoError(t, err)
+	assert.Nil(t, t2.ResultCache)
+
+	bad := vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"unsharded": {
+				Tables: map[string]*vschemapb.Table{
+					"t1": {
+						ResultCache: &vschemapb.ResultCache{TtlSeconds: -1}}}}}}
+
+	got = BuildVSchema(&bad)
+	require.EqualError(t, got.Keyspaces["unsharded"].Error, "negative result cache ttl for table: t1")
+}
+
 func TestShardedVSchemaOwned(t *testing.T) {
 	good := vschemapb.SrvVSchema{
 		Keyspaces: map[string]*vschemapb.Keyspace{
@@ -2435,19 +2467,19 @@ func TestFindTableOrVindex(t *testing.T) {
 	ta := vschema.Keyspaces["ksa"].Tables["ta"]
 	t1 := vschema.Keyspaces["ksb"].Tables["t1"]
 
-	_, _, err := vschema.FindTableOrVindex("", "t1", topodatapb.TabletType_PRIMARY)
+	_, _, err := vschema.FindTableOrVindex("", "t1", topodatapb.TabletType_PRIMARY, 0)
 	wantErr := "ambiguous table reference: t1"
 	if err == nil || err.Error() != wantErr {
 		t.Errorf("FindTableOrVindex(\"\"): %v, want %s", err, wantErr)
 	}
 
-	_, _, err = vschema.FindTableOrVindex("", "none", topodatapb.TabletType_PRIMARY)
+	_, _, err = vschema.FindTableOrVindex("", "none", topodatapb.TabletType_PRIMARY, 0)
 	wantErr = "table none not found"
 	if err == nil || err.Error() != wantErr {
 		t.Errorf("FindTableOrVindex(\"\"): %v, want %s", err, wantErr)
 	}
 
-	got, _, err := vschema.FindTableOrVindex("", "ta", topodatapb.TabletType_PRIMARY)
+	got, _, err := vschema.FindTableOrVindex("", "ta", topodatapb.TabletType_PRIMARY, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -2455,7 +2487,7 @@ func TestFindTableOrVindex(t *testing.T) {
 		t.Errorf("FindTableOrVindex(\"t1a\"): %+v, want %+v", got, ta)
 	}
 
-	_, vindex, err := vschema.FindTableOrVindex("", "stfu1", topodatapb.TabletType_PRIMARY)
+	_, vindex, err := vschema.FindTableOrVindex("", "stfu1", topodatapb.TabletType_PRIMARY, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -2466,7 +2498,7 @@ func TestFindTableOrVindex(t *testing.T) {
 		t.Errorf("FindTableOrVindex(\"stfu1\"): %+v, want %+v", vindex, wantVindex)
 	}
 
-	_, vindex, err = vschema.FindTableOrVindex("ksc", "ta", topodatapb.TabletType_PRIMARY)
+	_, vindex, err = vschema.FindTableOrVindex("ksc", "ta", topodatapb.TabletType_PRIMARY, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -2477,13 +2509,13 @@ func TestFindTableOrVindex(t *testing.T) {
 		t.Errorf("FindTableOrVindex(\"stfu1\"): %+v, want %+v", vindex, wantVindex)
 	}
 
-	_, _, err = vschema.FindTableOrVindex("", "dup", topodatapb.TabletType_PRIMARY)
+	_, _, err = vschema.FindTableOrVindex("", "dup", topodatapb.TabletType_PRIMARY, 0)
 	wantErr = "ambiguous vindex reference: dup"
 	if err == nil || err.Error() != wantErr {
 		t.Errorf("FindTableOrVindex(\"\"): %v, want %s", err, wantErr)
 	}
 
-	got, _, err = vschema.FindTableOrVindex("", "unqualified", topodatapb.TabletType_PRIMARY)
+	got, _, err = vschema.FindTableOrVindex("", "unqualified", topodatapb.TabletType_PRIMARY, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -2491,7 +2523,7 @@ func TestFindTableOrVindex(t *testing.T) {
 		t.Errorf("FindTableOrVindex(unqualified): %+v, want %+v", got, want)
 	}
 
-	got, _, err = vschema.FindTableOrVindex("", "unqualified", topodatapb.TabletType_REPLICA)
+	got, _, err = vschema.FindTableOrVindex("", "unqualified", topodatapb.TabletType_REPLICA, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -2499,7 +2531,7 @@ func TestFindTableOrVindex(t *testing.T) {
 		t.Errorf("FindTableOrVindex(unqualified): %+v, want %+v", got, want)
 	}
 
-	got, _, err = vschema.FindTableOrVindex("newks", "qualified", topodatapb.TabletType_PRIMARY)
+	got, _, err = vschema.FindTableOrVindex("newks", "qualified", topodatapb.TabletType_PRIMARY, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -2507,7 +2539,7 @@ func TestFindTableOrVindex(t *testing.T) {
 		t.Errorf("FindTableOrVindex(unqualified): %+v, want %+v", got, want)
 	}
 
-	got, _, err = vschema.FindTableOrVindex("newks", "qualified", topodatapb.TabletType_REPLICA)
+	got, _, err = vschema.FindTableOrVindex("newks", "qualified", topodatapb.TabletType_REPLICA, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -2515,13 +2547,83 @@ func TestFindTableOrVindex(t *testing.T) {
 		t.Errorf("FindTableOrVindex(unqualified): %+v, want %+v", got, want)
 	}
 
-	_, _, err = vschema.FindTableOrVindex("", "notarget", topodatapb.TabletType_PRIMARY)
+	_, _, err = vschema.FindTableOrVindex("", "notarget", topodatapb.TabletType_PRIMARY, 0)
 	wantErr = "table notarget has been disabled"
 	if err == nil || err.Error() != wantErr {
 		t.Errorf("FindTableOrVindex(\"\"): %v, want %s", err, wantErr)
 	}
 }
 
+func TestFindRoutedTableSplit(t *testing.T) {
+	input := vschemapb.SrvVSchema{
+		RoutingRules: &vschemapb.RoutingRules{
+			Rules: []*vschemapb.RoutingRule{{
+				FromTable:   "t1",
+				ToTables:    []string{"ksa.t1", "ksb.t1"},
+				Percentages: []uint32{90, 10},
+			}, {
+				FromTable:   "t2",
+				ToTables:    []string{"ksa.t2", "ksb.t2"},
+				Percentages: []uint32{90},
+			}, {
+				FromTable:   "t3",
+				ToTables:    []string{"ksa.t3", "ksb.t3"},
+				Percentages: []uint32{90, 20},
+			}, {
+				FromTable:   "t4",
+				ToTables:    []string{"ksa.t4", "ksb.t4"},
+				Percentages: []uint32{4294967295, 101},
+			}},
+		},
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"ksa": {Tables: map[string]*vschemapb.Table{"t1": {}, "t2": {}, "t3": {}, "t4": {}}},
+			"ksb": {Tables: map[string]*vschemapb.Table{"t1": {}, "t2": {}, "t3": {}, "t4": {}}},
+		},
+	}
+	vschema := BuildVSchema(&input)
+	ksa := vschema.Keyspaces["ksa"].Tables["t1"]
+	ksb := vschema.Keyspaces["ksb"].Tables["t1"]
+
+	got, err := vschema.FindRoutedTable("", "t1", topodatapb.TabletType_PRIMARY, 0)
+	require.NoError(t, err)
+	assert.Equal(t, ksa, got)
+	got, err = vschema.FindRoutedTable("", "t1", topodatapb.TabletType_PRIMARY, 89)
+	require.NoError(t, err)
+	assert.Equal(t, ksa, got)
+	got, err = vschema.FindRoutedTable("", "t1", topodatapb.TabletType_PRIMARY, 90)
+	require.NoError(t, err)
+	assert.Equal(t, ksb, got)
+	assert.Equal(t, "0", vschema.RoutingSplitKey(12))
+	assert.Equal(t, "1", vschema.RoutingSplitKey(95))
+
+	_, err = vschema.FindRoutedTable("", "t2", topodatapb.TabletType_PRIMARY, 0)
+	assert.EqualError(t, err, "table t2 has 1 percentages for 2 targets")
+	_, err = vschema.FindRoutedTable("", "t3", topodatapb.TabletType_PRIMARY, 0)
+	assert.EqualError(t, err, "the percentages of table t3 add up to 110 instead of 100")
+	_, err = vschema.FindRoutedTable("", "t4", topodatapb.TabletType_PRIMARY, 0)
+	assert.EqualError(t, err, "table t4 has a percentage of 4294967295, which is above 100")
+
+	// the buckets are deterministic, and spread the sessions across the targets
+	assert.Equal(t, RoutingBucket("3e1a7f0c-2b55-11ee-be56-0242ac120002"), RoutingBucket("3e1a7f0c-2b55-11ee-be56-0242ac120002"))
+	assert.EqualValues(t, 0, RoutingBucket(""))
+	counts := make([]int, 2)
+	for i := 0; i < 1000; i++ {
+		got, err := vschema.FindRoutedTable("", "t1", topodatapb.TabletType_PRIMARY, RoutingBucket(fmt.Sprintf("session-%d", i)))
+		require.NoError(t, err)
+		if got == ksa {
+			counts[0]++
+		} else {
+			counts[1]++
+		}
+	}
+	assert.InDelta(t, 900, counts[0], 50)
+	assert.InDelta(t, 100, counts[1], 50)
+
+	data, err := json.Marshal(vschema.RoutingRules["t1"])
+	require.NoError(t, err)
+	assert.Equal(t, `["ksa.t1:90%","ksb.t1:10%"]`, string(data))
+}
+
 func TestBuildKeyspaceSchema(t *testing.T) {
 	good := &vschemapb.Keyspace{
 		Tables: map[string]*vschemapb.Table{
@@ -2899,6 +3001,39 @@ func TestMultiColVindexPartialNotAllowed(t *testing.T) {
 	require.EqualValues(t, 1, table.ColumnVindexes[0].Cost())
 }
 
+func TestMultiColVindexColumnCount(t *testing.T) {
+	input := vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"ksa": {
+				Sharded: true,
+				Vindexes: map[string]*vschemapb.Vindex{
+					"multicol_vdx": {
+						Type:   "multicol",
+						Params: map[string]string{"column_count": "2"},
+					},
+				},
+				Tables: map[string]*vschemapb.Table{
+					"multiColTbl": {
+						ColumnVindexes: []*vschemapb.ColumnVindex{
+							{
+								Columns: []string{"tenant_id", "region"},
+								Name:    "multicol_vdx",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	vschema := BuildVSchema(&input)
+	require.NoError(t, vschema.Keyspaces["ksa"].Error)
+
+	// The rows of a table declaring another number of columns would not map to a keyspace id.
+	input.Keyspaces["ksa"].Tables["multiColTbl"].ColumnVindexes[0].Columns = []string{"tenant_id", "region", "id"}
+	vschema = BuildVSchema(&input)
+	require.EqualError(t, vschema.Keyspaces["ksa"].Error, "multi-column vindex multicol_vdx maps 2 columns, but 3 columns are declared for table multiColTbl")
+}
+
 func TestSourceTableHasReferencedBy(t *testing.T) {
 	input := vschemapb.SrvVSchema{
 		Keyspaces: map[string]*vschemapb.Keyspace{
@@ -3131,7 +3266,7 @@ func TestFindTableWithSequences(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			_, _, err := vschema.FindTableOrVindex(tc.keyspace, tc.table, topodatapb.TabletType_PRIMARY)
+			_, _, err := vschema.FindTableOrVindex(tc.keyspace, tc.table, topodatapb.TabletType_PRIMARY, 0)
 			if tc.mustError {
 				require.Error(t, err)
 				require.Contains(t, err.Error(), tc.errorContains)
@@ -51,6 +51,8 @@ type VSchemaManager struct {
 type SchemaInfo interface {
 	Tables(ks string) map[string]*vindexes.TableInfo
 	Views(ks string) map[string]sqlparser.SelectStatement
+	Procedures(ks string) map[string]string
+	Functions(ks string) map[string]string
 }
 
 // GetCurrentSrvVschema returns a copy of the latest SrvVschema from the
@@ -209,12 +211,12 @@ func (vm *VSchemaManager) updateFromSchema(vschema *vindexes.VSchema) {
 		// in the tables.
 		for tblName, tblInfo := range m {
 			for _, fkDef := range tblInfo.ForeignKeys {
-				parentTbl, err := vschema.FindRoutedTable(ksName, fkDef.ReferenceDefinition.ReferencedTable.Name.String(), topodatapb.TabletType_PRIMARY)
+				parentTbl, err := vschema.FindRoutedTable(ksName, fkDef.ReferenceDefinition.ReferencedTable.Name.String(), topodatapb.TabletType_PRIMARY, 0)
 				if err != nil {
 					log.Errorf("error finding parent table %s: %v", fkDef.ReferenceDefinition.ReferencedTable.Name.String(), err)
 					continue
 				}
-				childTbl, err := vschema.FindRoutedTable(ksName, tblName, topodatapb.TabletType_PRIMARY)
+				childTbl, err := vschema.FindRoutedTable(ksName, tblName, topodatapb.TabletType_PRIMARY, 0)
 				if err != nil {
 					log.Errorf("error finding child table %s: %v", tblName, err)
 					continue
@@ -231,6 +233,8 @@ func (vm *VSchemaManager) updateFromSchema(vschema *vindexes.VSchema) {
 				ks.Views[name] = sqlparser.CloneSelectStatement(def)
 			}
 		}
+		ks.Procedures = vm.schema.Procedures(ksName)
+		ks.Functions = vm.schema.Functions(ksName)
 	}
 }
 
@@ -420,4 +420,12 @@ func (f *fakeSchema) Views(string) map[string]sqlparser.SelectStatement {
 	return nil
 }
 
+func (f *fakeSchema) Procedures(string) map[string]string {
+	return nil
+}
+
+func (f *fakeSchema) Functions(string) map[string]string {
+	return nil
+}
+
 var _ SchemaInfo = (*fakeSchema)(nil)
@@ -35,10 +35,12 @@ import (
 
 	"google.golang.org/protobuf/proto"
 
+	"vitess.io/vitess/go/vt/key"
 	"vitess.io/vitess/go/vt/log"
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/srvtopo"
 	"vitess.io/vitess/go/vt/vterrors"
 )
@@ -193,6 +195,10 @@ func (vsm *vstreamManager) resolveParams(ctx context.Context, tabletType topodat
 		}
 	}
 
+	if err := validateFilter(filter); err != nil {
+		return nil, nil, nil, err
+	}
+
 	if flags == nil {
 		flags = &vtgatepb.VStreamFlags{}
 	}
@@ -268,6 +274,52 @@ func (vsm *vstreamManager) resolveParams(ctx context.Context, tabletType topodat
 	return newvgtid, filter, flags, nil
 }
 
+// validateFilter checks the rules of the filter before they are sent to
+// every shard of the stream, so that a bad rule fails the request once
+// instead of failing the stream of each shard. The rules of a table can
+// project its columns and filter its rows with a select statement, which
+// the vstreamers evaluate; the rules matching tables with a regular
+// expression can only filter the rows with a keyrange.
+func validateFilter(filter *binlogdatapb.Filter) error {
+	for _, rule := range filter.Rules {
+		if strings.HasPrefix(rule.Match, "/") {
+			if _, err := regexp.Compile(strings.Trim(rule.Match, "/")); err != nil {
+				return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid table regular expression %s: %v", rule.Match, err)
+			}
+			if rule.Filter == "" {
+				continue
+			}
+			keyranges, err := key.ParseShardingSpec(rule.Filter)
+			if err != nil || len(keyranges) != 1 {
+				return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the filter of the tables matching %s must be a keyrange: %s", rule.Match, rule.Filter)
+			}
+			continue
+		}
+		if rule.Match == "" {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "filter rule must match a table: %v", rule)
+		}
+		if rule.Filter == "" {
+			continue
+		}
+		stmt, err := sqlparser.Parse(rule.Filter)
+		if err != nil {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid filter for table %s: %v", rule.Match, err)
+		}
+		sel, ok := stmt.(*sqlparser.Select)
+		if !ok || len(sel.From) != 1 {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the filter of table %s must select from the table: %s", rule.Match, rule.Filter)
+		}
+		node, ok := sel.From[0].(*sqlparser.AliasedTableExpr)
+		if !ok {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the filter of table %s must select from the table: %s", rule.Match, rule.Filter)
+		}
+		if fromTable := sqlparser.GetTableName(node.Expr); fromTable.String() != rule.Match {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the filter of table %s must select from the table: %s", rule.Match, rule.Filter)
+		}
+	}
+	return nil
+}
+
 func (vsm *vstreamManager) RecordStreamDelay() {
 	vstreamSkewDelayCount.Add(1)
 }
@@ -1136,6 +1136,78 @@ func TestResolveVStreamParams(t *testing.T) {
 
 }
 
+func TestResolveVStreamParamsFilter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	name := "TestVStream"
+	_ = createSandbox(name)
+	hc := discovery.NewFakeHealthCheck(nil)
+	vsm := newTestVStreamManager(hc, newSandboxForCells(ctx, []string{"aa"}), "aa")
+	vgtid := &binlogdatapb.VGtid{
+		ShardGtids: []*binlogdatapb.ShardGtid{{
+			Keyspace: name,
+			Shard:    "-20",
+			Gtid:     "current",
+		}},
+	}
+	testcases := []struct {
+		name  string
+		rules []*binlogdatapb.Rule
+		err   string
+	}{{
+		name:  "projection and predicates",
+		rules: []*binlogdatapb.Rule{{Match: "t1", Filter: "select id, val from t1 where id in (1, 2) and val is not null"}},
+	}, {
+		name:  "keyrange",
+		rules: []*binlogdatapb.Rule{{Match: "/.*", Filter: "-80"}},
+	}, {
+		name:  "table without filter",
+		rules: []*binlogdatapb.Rule{{Match: "t1"}},
+	}, {
+		name:  "bad regexp",
+		rules: []*binlogdatapb.Rule{{Match: "/(/"}},
+		err:   "invalid table regular expression /(/",
+	}, {
+		name:  "select on regexp",
+		rules: []*binlogdatapb.Rule{{Match: "/.*", Filter: "select * from t1"}},
+		err:   "the filter of the tables matching /.* must be a keyrange: select * from t1",
+	}, {
+		name:  "no table",
+		rules: []*binlogdatapb.Rule{{Filter: "select * from t1"}},
+		err:   "filter rule must match a table",
+	}, {
+		name:  "syntax error",
+		rules: []*binlogdatapb.Rule{{Match: "t1", Filter: "select * frm t1"}},
+		err:   "invalid filter for table t1",
+	}, {
+		name:  "other table",
+		rules: []*binlogdatapb.Rule{{Match: "t1", Filter: "select * from t2"}},
+		err:   "the filter of table t1 must select from the table: select * from t2",
+	}, {
+		name:  "join",
+		rules: []*binlogdatapb.Rule{{Match: "t1", Filter: "select * from t1, t2"}},
+		err:   "the filter of table t1 must select from the table: select * from t1, t2",
+	}, {
+		name:  "not a select",
+		rules: []*binlogdatapb.Rule{{Match: "t1", Filter: "delete from t1"}},
+		err:   "the filter of table t1 must select from the table: delete from t1",
+	}}
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			filter := &binlogdatapb.Filter{Rules: tcase.rules}
+			_, got, _, err := vsm.resolveParams(ctx, topodatapb.TabletType_REPLICA, vgtid, filter, nil)
+			if tcase.err != "" {
+				require.ErrorContains(t, err, tcase.err)
+				assert.Equal(t, vtrpcpb.Code_INVALID_ARGUMENT, vterrors.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, filter, got)
+		})
+	}
+}
+
 func TestVStreamIdleHeartbeat(t *testing.T) {
 	ctx := utils.LeakCheckContext(t)
 
@@ -69,11 +69,37 @@ var (
 	queryPlanCacheMemory = cache.DefaultConfig.MaxMemoryUsage
 	queryPlanCacheLFU    bool
 
+	// preparedStatementCacheSize is the number of prepared statements whose
+	// plan and fields are cached.
+	preparedStatementCacheSize int64 = 5000
+	// preparedStatementCacheTTL is how long the fields of a prepared
+	// statement are reused for, which bounds how stale they can get after a
+	// DDL that did not go through this vtgate.
+	preparedStatementCacheTTL = time.Minute
+
+	// resultCacheMemory is the memory used by the results cached for the
+	// tables whose VSchema enables it.
+	resultCacheMemory int64 = 16 * 1024 * 1024
+
 	maxMemoryRows   = 300000
 	warnMemoryRows  = 30000
 	maxPayloadSize  int
 	warnPayloadSize int
 
+	// spillDir is the directory where the sorts, aggregations and hash joins spill
+	// the rows exceeding maxMemoryRows. Spilling is disabled when it is empty.
+	spillDir string
+	// maxSpillBytes is the maximum number of bytes each query can spill.
+	maxSpillBytes int64 = 1024 * 1024 * 1024
+
+	// multiplexReservedConns releases after each query the reserved connections
+	// that only hold the system variables of their session.
+	multiplexReservedConns bool
+
+	// maxReadRetries is the number of times the read-only queries that fail
+	// on a replica are retried on another tablet.
+	maxReadRetries = 1
+
 	noScatter          bool
 	enableShardRouting bool
 
@@ -90,6 +116,12 @@ var (
 	lockHeartbeatTime = 5 * time.Second
 	warnShardedOnly   bool
 
+	// enableTopoNamedLocks serves the lock functions on the unsharded
+	// keyspaces with the named locks of the topo, held for topoNamedLockTTL
+	// without being renewed.
+	enableTopoNamedLocks bool
+	topoNamedLockTTL     = 30 * time.Second
+
 	// ddl related flags
 	foreignKeyMode     = "allow"
 	dbDDLPlugin        = "fail"
@@ -109,6 +141,10 @@ var (
 	queryLogToFile string
 	// queryLogBufferSize controls how many query logs will be buffered before dropping them if logging is not fast enough
 	queryLogBufferSize = 10
+	// queryQuotaConfig is the JSON file of the query quotas
+	queryQuotaConfig string
+	// queryLogSinks are the sinks, as <name>:<target>, the query logs are sent to
+	queryLogSinks []string
 
 	messageStreamGracePeriod = 30 * time.Second
 
@@ -125,7 +161,15 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.Int64Var(&queryPlanCacheSize, "gate_query_cache_size", queryPlanCacheSize, "gate server query cache size, maximum number of queries to be cached. vtgate analyzes every incoming query and generate a query plan, these plans are being cached in a cache. This config controls the expected amount of unique entries in the cache.")
 	fs.Int64Var(&queryPlanCacheMemory, "gate_query_cache_memory", queryPlanCacheMemory, "gate server query cache size in bytes, maximum amount of memory to be cached. vtgate analyzes every incoming query and generate a query plan, these plans are being cached in a lru cache. This config controls the capacity of the lru cache.")
 	fs.BoolVar(&queryPlanCacheLFU, "gate_query_cache_lfu", cache.DefaultConfig.LFU, "gate server cache algorithm. when set to true, a new cache algorithm based on a TinyLFU admission policy will be used to improve cache behavior and prevent pollution from sparse queries")
+	fs.Int64Var(&preparedStatementCacheSize, "gate-prepared-statement-cache-size", preparedStatementCacheSize, "Maximum number of prepared statements whose plan and result fields are cached, and shared by all the connections preparing the same statement. Set to 0 to disable the cache.")
+	fs.DurationVar(&preparedStatementCacheTTL, "gate-prepared-statement-cache-ttl", preparedStatementCacheTTL, "How long the plan and result fields of a prepared statement are cached for, which bounds how stale they can get after a DDL that did not go through this vtgate. Set to 0 for no expiry.")
+	fs.Int64Var(&resultCacheMemory, "gate-result-cache-memory", resultCacheMemory, "Maximum amount of memory, in bytes, used by the cached results of the read-only queries on the tables whose VSchema sets a result_cache. Set to 0 to disable the cache.")
 	fs.IntVar(&maxMemoryRows, "max_memory_rows", maxMemoryRows, "Maximum number of rows that will be held in memory for intermediate results as well as the final result.")
+	fs.StringVar(&spillDir, "spill_dir", spillDir, "Directory where the sorts, aggregations and hash joins spill the rows exceeding --max_memory_rows instead of failing. Spilling to disk is disabled when empty.")
+	fs.Int64Var(&maxSpillBytes, "max_spill_bytes", maxSpillBytes, "Maximum number of bytes each query can spill to the --spill_dir directory. Set to 0 for no limit.")
+	fs.StringVar(&queryQuotaConfig, "query-quota-config", queryQuotaConfig, "JSON file of the query quotas: a list of QPS, concurrency and rows per second limits for the queries of a MySQL user, of a keyspace or of a table. The queries exceeding a quota fail with a VT08001 error.")
+	fs.BoolVar(&multiplexReservedConns, "multiplex-reserved-connections", multiplexReservedConns, "Release after each query the reserved connections of the sessions that only set system variables, outside of transactions, so that the sessions do not hold connections of the tablets between their queries, which reserve new connections with the system variables of their session. The reserved connections holding temporary tables or settings applied to a single shard are kept. Only the tablets with --queryserver-enable-settings-pool=false reserve connections for system variables.")
+	fs.IntVar(&maxReadRetries, "max_read_retries", maxReadRetries, "Maximum number of times a read-only, non-transactional query that fails on a replica or rdonly tablet with a retryable error is retried on another tablet. Set to 0 to disable the retries.")
 	fs.IntVar(&warnMemoryRows, "warn_memory_rows", warnMemoryRows, "Warning threshold for in-memory results. A row count higher than this amount will cause the VtGateWarnings.ResultsExceeded counter to be incremented.")
 	fs.StringVar(&defaultDDLStrategy, "ddl_strategy", defaultDDLStrategy, "Set default strategy for DDL statements. Override with @@ddl_strategy session variable")
 	fs.StringVar(&dbDDLPlugin, "dbddl_plugin", dbDDLPlugin, "controls how to handle CREATE/DROP DATABASE. use it if you are using your own database provisioning service")
@@ -138,6 +182,8 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&sysVarSetEnabled, "enable_system_settings", sysVarSetEnabled, "This will enable the system settings to be changed per session at the database connection level")
 	fs.BoolVar(&setVarEnabled, "enable_set_var", setVarEnabled, "This will enable the use of MySQL's SET_VAR query hint for certain system variables instead of using reserved connections")
 	fs.DurationVar(&lockHeartbeatTime, "lock_heartbeat_time", lockHeartbeatTime, "If there is lock function used. This will keep the lock connection active by using this heartbeat")
+	fs.BoolVar(&enableTopoNamedLocks, "enable-topo-named-locks", enableTopoNamedLocks, "Serve the lock functions, such as GET_LOCK() and RELEASE_LOCK(), on the unsharded keyspaces with named locks stored in the global topo instead of the locks of MySQL. The locks are shared by all the vtgates, and released when the sessions holding them are closed.")
+	fs.DurationVar(&topoNamedLockTTL, "topo-named-lock-ttl", topoNamedLockTTL, "How long the named locks of the topo held by the sessions of a vtgate that stopped renewing them, because it died for instance, are kept before they expire. Requires --enable-topo-named-locks.")
 	fs.BoolVar(&warnShardedOnly, "warn_sharded_only", warnShardedOnly, "If any features that are only available in unsharded mode are used, query execution warnings will be added to the session")
 	fs.StringVar(&foreignKeyMode, "foreign_key_mode", foreignKeyMode, "This is to provide how to handle foreign key constraint in create/alter table. Valid values are: allow, disallow")
 	fs.BoolVar(&enableOnlineDDL, "enable_online_ddl", enableOnlineDDL, "Allow users to submit, review and control Online DDL")
@@ -146,6 +192,7 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&queryTimeout, "query-timeout", queryTimeout, "Sets the default query timeout (in ms). Can be overridden by session variable (query_timeout) or comment directive (QUERY_TIMEOUT_MS)")
 	fs.StringVar(&queryLogToFile, "log_queries_to_file", queryLogToFile, "Enable query logging to the specified file")
 	fs.IntVar(&queryLogBufferSize, "querylog-buffer-size", queryLogBufferSize, "Maximum number of buffered query logs before throttling log output")
+	fs.StringSliceVar(&queryLogSinks, "querylog-sink", queryLogSinks, "Sink the query logs are sent to, as <name>:<target>, e.g. file:/var/log/vtgate/queries.log. Can be repeated. Sinks other than file are registered by plugins, such as grpc:<address>, kafka:<broker>[,<broker>...]/<topic> and otlp:<url> in vtgate.")
 	fs.DurationVar(&messageStreamGracePeriod, "message_stream_grace_period", messageStreamGracePeriod, "the amount of time to give for a vttablet to resume if it ends a message stream, usually because of a reparent.")
 	fs.BoolVar(&enableViews, "enable-views", enableViews, "Enable views support in vtgate.")
 	fs.BoolVar(&allowKillStmt, "allow-kill-statement", allowKillStmt, "Allows the execution of kill statement")
@@ -230,6 +277,9 @@ type VTGate struct {
 	logExecute       *logutil.ThrottledLogger
 	logPrepare       *logutil.ThrottledLogger
 	logStreamExecute *logutil.ThrottledLogger
+
+	mysqlServer *mysqlServer
+	drain       drainState
 }
 
 // RegisterVTGate defines the type of registration mechanism.
@@ -331,11 +381,25 @@ func Init(
 		queryLogger,
 	)
 
+	executor.quotas, err = loadQueryQuotas(queryQuotaConfig)
+	if err != nil {
+		log.Fatalf("error loading the query quotas: %v", err)
+	}
+
 	// connect the schema tracker with the vschema manager
 	if enableSchemaChangeSignal {
 		st.RegisterSignalReceiver(executor.vm.Rebuild)
 	}
 
+	// stream the changes to the cached tables to invalidate their results
+	executor.resultCache.start(func(ctx context.Context, keyspace string, filter *binlogdatapb.Filter, send func([]*binlogdatapb.VEvent) error) error {
+		vgtid := &binlogdatapb.VGtid{ShardGtids: []*binlogdatapb.ShardGtid{{
+			Keyspace: keyspace,
+			Gtid:     "current",
+		}}}
+		return vsm.VStream(ctx, topodatapb.TabletType_PRIMARY, vgtid, filter, &vtgatepb.VStreamFlags{HeartbeatInterval: 1}, send)
+	})
+
 	// TODO: call serv.WatchSrvVSchema here
 
 	vtgateInst := newVTGate(executor, resolver, vsm, tc, gw)
@@ -356,6 +420,7 @@ func Init(
 			st.Start()
 		}
 		srv := initMySQLProtocol(vtgateInst)
+		vtgateInst.mysqlServer = srv
 		servenv.OnTermSync(srv.shutdownMysqlProtocolAndDrain)
 		servenv.OnClose(srv.rollbackAtShutdown)
 	})
@@ -366,6 +431,7 @@ func Init(
 	})
 	vtgateInst.registerDebugHealthHandler()
 	vtgateInst.registerDebugEnvHandler()
+	vtgateInst.registerDebugDrainHandler()
 
 	initAPI(gw.hc)
 	return vtgateInst
@@ -433,6 +499,9 @@ func (vtg *VTGate) registerDebugHealthHandler() {
 // IsHealthy returns nil if server is healthy.
 // Otherwise, it returns an error indicating the reason.
 func (vtg *VTGate) IsHealthy() error {
+	if vtg.IsDraining() {
+		return errDraining
+	}
 	return nil
 }
 
@@ -450,7 +519,7 @@ func (vtg *VTGate) Execute(ctx context.Context, mysqlCtx vtgateservice.MySQLConn
 
 	if bvErr := sqltypes.ValidateBindVariables(bindVariables); bvErr != nil {
 		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%v", bvErr)
-	} else {
+	} else if err = vtg.checkDraining(session); err == nil {
 		safeSession := NewSafeSession(session)
 		qr, err = vtg.executor.Execute(ctx, mysqlCtx, "Execute", safeSession, sql, bindVariables)
 		safeSession.RemoveInternalSavepoint()
@@ -511,7 +580,7 @@ func (vtg *VTGate) StreamExecute(ctx context.Context, mysqlCtx vtgateservice.MyS
 	var err error
 	if bvErr := sqltypes.ValidateBindVariables(bindVariables); bvErr != nil {
 		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%v", bvErr)
-	} else {
+	} else if err = vtg.checkDraining(session); err == nil {
 		err = vtg.executor.StreamExecute(
 			ctx,
 			mysqlCtx,
@@ -561,6 +630,9 @@ func (vtg *VTGate) Prepare(ctx context.Context, session *vtgatepb.Session, sql s
 		goto handleError
 	}
 
+	if err = vtg.checkDraining(session); err != nil {
+		goto handleError
+	}
 	fld, err = vtg.executor.Prepare(ctx, "Prepare", NewSafeSession(session), sql, bindVariables)
 	if err == nil {
 		return session, fld, nil
@@ -25,6 +25,9 @@ import (
 	"github.com/spf13/pflag"
 
 	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
 const (
@@ -62,6 +65,11 @@ var (
 	topoInformationRefreshDuration = 15 * time.Second
 	recoveryPollDuration           = 1 * time.Second
 	ersEnabled                     = true
+	errantGTIDQuarantine           = false
+	errantGTIDQuarantineTabletType = topodatapb.TabletType_DRAINED
+	candidateScoring               = false
+	candidateScoreWeights          = map[string]string{}
+	preferredCells                 []string
 )
 
 // RegisterFlags registers the flags required by VTOrc
@@ -82,6 +90,11 @@ func RegisterFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&topoInformationRefreshDuration, "topo-information-refresh-duration", topoInformationRefreshDuration, "Timer duration on which VTOrc refreshes the keyspace and vttablet records from the topology server")
 	fs.DurationVar(&recoveryPollDuration, "recovery-poll-duration", recoveryPollDuration, "Timer duration on which VTOrc polls its database to run a recovery")
 	fs.BoolVar(&ersEnabled, "allow-emergency-reparent", ersEnabled, "Whether VTOrc should be allowed to run emergency reparent operation when it detects a dead primary")
+	fs.BoolVar(&errantGTIDQuarantine, "errant-gtid-quarantine", errantGTIDQuarantine, "Whether VTOrc should quarantine the replicas with errant GTIDs, by changing their type to --errant-gtid-quarantine-tablet-type and tagging them, until they are fixed with FixErrantGTID")
+	fs.Var((*topoproto.TabletTypeFlag)(&errantGTIDQuarantineTabletType), "errant-gtid-quarantine-tablet-type", "Tablet type VTOrc changes the replicas with errant GTIDs to, with --errant-gtid-quarantine")
+	fs.BoolVar(&candidateScoring, "promotion-candidate-scoring", candidateScoring, "Whether VTOrc should choose the primary it promotes in an emergency reparent by scoring the candidates on replication lag, cell locality, durability acks, preferred cells and promotion rule. The scores are written to the recovery audit")
+	fs.StringToStringVar(&candidateScoreWeights, "promotion-candidate-score-weights", candidateScoreWeights, "Weights of the criteria of --promotion-candidate-scoring, like replication_lag=4,cell_locality=2. The criteria are replication_lag, cell_locality, durability_acks, cell_preference and promotion_rule")
+	fs.StringSliceVar(&preferredCells, "promotion-preferred-cells", preferredCells, "Cells whose tablets are preferred by --promotion-candidate-scoring, from the most preferred one. The preferred_cells of a recovery policy override them")
 }
 
 // Configuration makes for vtorc configuration input, which can be provided by user via JSON formatted file.
@@ -143,6 +156,56 @@ func SetERSEnabled(val bool) {
 	ersEnabled = val
 }
 
+// ErrantGTIDQuarantineEnabled reports whether VTOrc quarantines the replicas
+// with errant GTIDs or not.
+func ErrantGTIDQuarantineEnabled() bool {
+	return errantGTIDQuarantine
+}
+
+// SetErrantGTIDQuarantineEnabled sets the value for the errantGTIDQuarantine variable. This should only be used from tests.
+func SetErrantGTIDQuarantineEnabled(val bool) {
+	errantGTIDQuarantine = val
+}
+
+// ErrantGTIDQuarantineTabletType returns the tablet type of the replicas
+// quarantined because of errant GTIDs.
+func ErrantGTIDQuarantineTabletType() topodatapb.TabletType {
+	return errantGTIDQuarantineTabletType
+}
+
+// CandidateScoringEnabled reports whether VTOrc scores the candidates of an
+// emergency reparent or not.
+func CandidateScoringEnabled() bool {
+	return candidateScoring
+}
+
+// SetCandidateScoringEnabled sets the value for the candidateScoring variable. This should only be used from tests.
+func SetCandidateScoringEnabled(val bool) {
+	candidateScoring = val
+}
+
+// CandidateScoreWeights returns the weights of the criteria the candidates
+// are scored on, by criterion name.
+func CandidateScoreWeights() map[string]string {
+	return candidateScoreWeights
+}
+
+// SetCandidateScoreWeights sets the value for the candidateScoreWeights variable. This should only be used from tests.
+func SetCandidateScoreWeights(val map[string]string) {
+	candidateScoreWeights = val
+}
+
+// PreferredCells returns the cells whose tablets are preferred when the
+// candidates are scored.
+func PreferredCells() []string {
+	return preferredCells
+}
+
+// SetPreferredCells sets the value for the preferredCells variable. This should only be used from tests.
+func SetPreferredCells(val []string) {
+	preferredCells = val
+}
+
 // LogConfigValues is used to log the config values.
 func LogConfigValues() {
 	b, _ := json.MarshalIndent(Config, "", "\t")
@@ -0,0 +1,57 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package grpcvtorcclient contains a gRPC client of the VTOrc service.
+package grpcvtorcclient
+
+import (
+	"google.golang.org/grpc"
+
+	"vitess.io/vitess/go/vt/grpcclient"
+	"vitess.io/vitess/go/vt/vtctl/grpcclientcommon"
+
+	vtorcservicepb "vitess.io/vitess/go/vt/proto/vtorcservice"
+)
+
+// Client is a gRPC client of the VTOrc service.
+type Client struct {
+	vtorcservicepb.VTOrcClient
+	cc *grpc.ClientConn
+}
+
+// New returns a Client of the VTOrc at the given address, dialed with the
+// same security options as the vtctld clients.
+func New(addr string) (*Client, error) {
+	opt, err := grpcclientcommon.SecureDialOption()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpcclient.Dial(addr, grpcclient.FailFast(false), opt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		VTOrcClient: vtorcservicepb.NewVTOrcClient(conn),
+		cc:          conn,
+	}, nil
+}
+
+// Close closes the connection to VTOrc.
+func (c *Client) Close() error {
+	return c.cc.Close()
+}
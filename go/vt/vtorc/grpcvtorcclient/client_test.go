@@ -0,0 +1,54 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcvtorcclient
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	_ "modernc.org/sqlite"
+
+	"vitess.io/vitess/go/vt/vtorc/grpcvtorcserver"
+
+	vtorcdatapb "vitess.io/vitess/go/vt/proto/vtorcdata"
+)
+
+func TestClient(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	grpcvtorcserver.StartServer(server)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	client, err := New(listener.Addr().String())
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	recoveries, err := client.GetRecentRecoveries(ctx, &vtorcdatapb.GetRecentRecoveriesRequest{Keyspace: "ks"})
+	require.NoError(t, err)
+	assert.Empty(t, recoveries.Recoveries)
+
+	_, err = client.GetShardReplicationAnalysis(ctx, &vtorcdatapb.GetShardReplicationAnalysisRequest{Shard: "-80"})
+	assert.ErrorContains(t, err, "filtering by shard without keyspace isn't supported")
+}
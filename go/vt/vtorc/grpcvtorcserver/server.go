@@ -0,0 +1,165 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcvtorcserver
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+	"vitess.io/vitess/go/vt/vtorc/logic"
+
+	vtorcdatapb "vitess.io/vitess/go/vt/proto/vtorcdata"
+	vtorcservicepb "vitess.io/vitess/go/vt/proto/vtorcservice"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// VTOrcServer implements the VTOrc gRPC service.
+type VTOrcServer struct {
+	vtorcservicepb.UnimplementedVTOrcServer
+}
+
+var _ vtorcservicepb.VTOrcServer = (*VTOrcServer)(nil)
+
+// NewVTOrcServer returns a new VTOrcServer.
+func NewVTOrcServer() *VTOrcServer {
+	return &VTOrcServer{}
+}
+
+// StreamRecoveryEvents is part of the vtorcservicepb.VTOrcServer interface.
+func (s *VTOrcServer) StreamRecoveryEvents(req *vtorcdatapb.StreamRecoveryEventsRequest, stream vtorcservicepb.VTOrc_StreamRecoveryEventsServer) error {
+	events := logic.SubscribeRecoveryEvents("StreamRecoveryEvents")
+	defer logic.UnsubscribeRecoveryEvents(events)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event := <-events:
+			if req.Keyspace != "" && event.Keyspace != req.Keyspace {
+				continue
+			}
+			if req.Shard != "" && event.Shard != req.Shard {
+				continue
+			}
+			if err := stream.Send(&vtorcdatapb.StreamRecoveryEventsResponse{Event: event}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// GetShardReplicationAnalysis is part of the vtorcservicepb.VTOrcServer interface.
+func (s *VTOrcServer) GetShardReplicationAnalysis(ctx context.Context, req *vtorcdatapb.GetShardReplicationAnalysisRequest) (*vtorcdatapb.GetShardReplicationAnalysisResponse, error) {
+	if req.Shard != "" && req.Keyspace == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "filtering by shard without keyspace isn't supported")
+	}
+	analyses, err := inst.GetReplicationAnalysis(req.Keyspace, req.Shard, &inst.ReplicationAnalysisHints{})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &vtorcdatapb.GetShardReplicationAnalysisResponse{
+		Analyses: make([]*vtorcdatapb.ReplicationAnalysis, 0, len(analyses)),
+	}
+	for _, analysis := range analyses {
+		resp.Analyses = append(resp.Analyses, replicationAnalysisToProto(analysis))
+	}
+	return resp, nil
+}
+
+// GetRecentRecoveries is part of the vtorcservicepb.VTOrcServer interface.
+func (s *VTOrcServer) GetRecentRecoveries(ctx context.Context, req *vtorcdatapb.GetRecentRecoveriesRequest) (*vtorcdatapb.GetRecentRecoveriesResponse, error) {
+	if req.Shard != "" && req.Keyspace == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "filtering by shard without keyspace isn't supported")
+	}
+	if req.Page < 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "negative page: %v", req.Page)
+	}
+	recoveries, err := logic.ReadRecentRecoveries(req.Keyspace, req.Shard, req.UnacknowledgedOnly, int(req.Page))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &vtorcdatapb.GetRecentRecoveriesResponse{
+		Recoveries: make([]*vtorcdatapb.Recovery, 0, len(recoveries)),
+	}
+	for _, recovery := range recoveries {
+		resp.Recoveries = append(resp.Recoveries, recoveryToProto(recovery))
+	}
+	return resp, nil
+}
+
+func replicationAnalysisToProto(analysis *inst.ReplicationAnalysis) *vtorcdatapb.ReplicationAnalysis {
+	structureAnalysis := make([]string, 0, len(analysis.StructureAnalysis))
+	for _, code := range analysis.StructureAnalysis {
+		structureAnalysis = append(structureAnalysis, string(code))
+	}
+	return &vtorcdatapb.ReplicationAnalysis{
+		TabletAlias:                   analysis.AnalyzedInstanceAlias,
+		Keyspace:                      analysis.ClusterDetails.Keyspace,
+		Shard:                         analysis.ClusterDetails.Shard,
+		TabletType:                    analysis.TabletType,
+		PrimaryAlias:                  analysis.AnalyzedInstancePrimaryAlias,
+		Analysis:                      string(analysis.Analysis),
+		Description:                   analysis.Description,
+		StructureAnalysis:             structureAnalysis,
+		IsPrimary:                     analysis.IsPrimary,
+		LastCheckValid:                analysis.LastCheckValid,
+		ReplicationStopped:            analysis.ReplicationStopped,
+		IsReadOnly:                    analysis.IsReadOnly,
+		CountReplicas:                 uint32(analysis.CountReplicas),
+		CountValidReplicas:            uint32(analysis.CountValidReplicas),
+		CountValidReplicatingReplicas: uint32(analysis.CountValidReplicatingReplicas),
+		IsActionableRecovery:          analysis.IsActionableRecovery,
+		ErrantGtidSet:                 analysis.GTIDErrant,
+	}
+}
+
+func recoveryToProto(recovery *logic.TopologyRecovery) *vtorcdatapb.Recovery {
+	var allErrors []string
+	for _, err := range recovery.AllErrors {
+		if err != "" {
+			allErrors = append(allErrors, err)
+		}
+	}
+	return &vtorcdatapb.Recovery{
+		Id:                  recovery.ID,
+		Uid:                 recovery.UID,
+		TabletAlias:         recovery.AnalysisEntry.AnalyzedInstanceAlias,
+		Keyspace:            recovery.AnalysisEntry.ClusterDetails.Keyspace,
+		Shard:               recovery.AnalysisEntry.ClusterDetails.Shard,
+		Analysis:            string(recovery.AnalysisEntry.Analysis),
+		SuccessorAlias:      recovery.SuccessorAlias,
+		IsActive:            recovery.IsActive,
+		IsSuccessful:        recovery.IsSuccessful,
+		Errors:              allErrors,
+		StartTime:           recovery.RecoveryStartTimestamp,
+		EndTime:             recovery.RecoveryEndTimestamp,
+		Acknowledged:        recovery.Acknowledged,
+		AcknowledgedAt:      recovery.AcknowledgedAt,
+		AcknowledgedBy:      recovery.AcknowledgedBy,
+		AcknowledgedComment: recovery.AcknowledgedComment,
+	}
+}
+
+// StartServer registers a VTOrcServer on the given gRPC server.
+func StartServer(s *grpc.Server) {
+	vtorcservicepb.RegisterVTOrcServer(s, NewVTOrcServer())
+}
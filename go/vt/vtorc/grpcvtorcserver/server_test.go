@@ -0,0 +1,117 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcvtorcserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"vitess.io/vitess/go/vt/vtorc/inst"
+	"vitess.io/vitess/go/vt/vtorc/logic"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtorcdatapb "vitess.io/vitess/go/vt/proto/vtorcdata"
+)
+
+func TestGetShardReplicationAnalysis(t *testing.T) {
+	s := NewVTOrcServer()
+	_, err := s.GetShardReplicationAnalysis(context.Background(), &vtorcdatapb.GetShardReplicationAnalysisRequest{Shard: "-80"})
+	assert.ErrorContains(t, err, "filtering by shard without keyspace isn't supported")
+
+	resp, err := s.GetShardReplicationAnalysis(context.Background(), &vtorcdatapb.GetShardReplicationAnalysisRequest{Keyspace: "ks", Shard: "-80"})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Analyses)
+}
+
+func TestGetRecentRecoveries(t *testing.T) {
+	s := NewVTOrcServer()
+	_, err := s.GetRecentRecoveries(context.Background(), &vtorcdatapb.GetRecentRecoveriesRequest{Shard: "-80"})
+	assert.ErrorContains(t, err, "filtering by shard without keyspace isn't supported")
+	_, err = s.GetRecentRecoveries(context.Background(), &vtorcdatapb.GetRecentRecoveriesRequest{Page: -1})
+	assert.ErrorContains(t, err, "negative page: -1")
+
+	resp, err := s.GetRecentRecoveries(context.Background(), &vtorcdatapb.GetRecentRecoveriesRequest{Keyspace: "ks"})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Recoveries)
+}
+
+func TestReplicationAnalysisToProto(t *testing.T) {
+	analysis := &inst.ReplicationAnalysis{
+		AnalyzedInstanceAlias:        "zone1-0000000100",
+		AnalyzedInstancePrimaryAlias: "zone1-0000000101",
+		TabletType:                   topodatapb.TabletType_REPLICA,
+		ClusterDetails: inst.ClusterInfo{
+			Keyspace: "ks",
+			Shard:    "-80",
+		},
+		Analysis:             inst.ErrantGTIDDetected,
+		Description:          "Tablet has errant GTIDs",
+		StructureAnalysis:    []inst.StructureAnalysisCode{inst.NotEnoughValidSemiSyncReplicasStructureWarning},
+		LastCheckValid:       true,
+		CountReplicas:        2,
+		IsActionableRecovery: true,
+		GTIDErrant:           "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1",
+	}
+	assert.Equal(t, &vtorcdatapb.ReplicationAnalysis{
+		TabletAlias:          "zone1-0000000100",
+		Keyspace:             "ks",
+		Shard:                "-80",
+		TabletType:           topodatapb.TabletType_REPLICA,
+		PrimaryAlias:         "zone1-0000000101",
+		Analysis:             "ErrantGTIDDetected",
+		Description:          "Tablet has errant GTIDs",
+		StructureAnalysis:    []string{"NotEnoughValidSemiSyncReplicasStructureWarning"},
+		LastCheckValid:       true,
+		CountReplicas:        2,
+		IsActionableRecovery: true,
+		ErrantGtidSet:        "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1",
+	}, replicationAnalysisToProto(analysis))
+}
+
+func TestRecoveryToProto(t *testing.T) {
+	recovery := logic.NewTopologyRecovery(inst.ReplicationAnalysis{
+		AnalyzedInstanceAlias: "zone1-0000000100",
+		ClusterDetails: inst.ClusterInfo{
+			Keyspace: "ks",
+			Shard:    "0",
+		},
+		Analysis: inst.DeadPrimary,
+	})
+	recovery.ID = 3
+	recovery.SuccessorAlias = "zone1-0000000101"
+	recovery.IsSuccessful = true
+	recovery.AllErrors = []string{""}
+	recovery.RecoveryStartTimestamp = "2023-08-01 10:00:00"
+	recovery.RecoveryEndTimestamp = "2023-08-01 10:00:05"
+
+	assert.Equal(t, &vtorcdatapb.Recovery{
+		Id:             3,
+		Uid:            recovery.UID,
+		TabletAlias:    "zone1-0000000100",
+		Keyspace:       "ks",
+		Shard:          "0",
+		Analysis:       "DeadPrimary",
+		SuccessorAlias: "zone1-0000000101",
+		IsSuccessful:   true,
+		StartTime:      "2023-08-01 10:00:00",
+		EndTime:        "2023-08-01 10:00:05",
+	}, recoveryToProto(recovery))
+}
@@ -58,6 +58,7 @@ const (
 	PrimaryWithoutReplicas                 AnalysisCode = "PrimaryWithoutReplicas"
 	BinlogServerFailingToConnectToPrimary  AnalysisCode = "BinlogServerFailingToConnectToPrimary"
 	GraceFulPrimaryTakeover                AnalysisCode = "GracefulPrimaryTakeover"
+	ErrantGTIDDetected                     AnalysisCode = "ErrantGTIDDetected"
 )
 
 const (
@@ -143,6 +144,7 @@ type ReplicationAnalysis struct {
 	ProcessingNodeToken                       string
 	StartActivePeriod                         string
 	GTIDMode                                  string
+	GTIDErrant                                string
 	MinReplicaGTIDMode                        string
 	MaxReplicaGTIDMode                        string
 	MaxReplicaGTIDErrant                      string
@@ -72,7 +72,7 @@ func RefreshAllKeyspacesAndShards() {
 		if idx != 0 && keyspace == keyspaces[idx-1] {
 			continue
 		}
-		wg.Add(2)
+		wg.Add(3)
 		go func(keyspace string) {
 			defer wg.Done()
 			_ = refreshKeyspaceHelper(refreshCtx, keyspace)
@@ -81,17 +81,27 @@ func RefreshAllKeyspacesAndShards() {
 			defer wg.Done()
 			_ = refreshAllShards(refreshCtx, keyspace)
 		}(keyspace)
+		go func(keyspace string) {
+			defer wg.Done()
+			_ = refreshRecoveryPolicies(refreshCtx, keyspace)
+		}(keyspace)
 	}
 	wg.Wait()
 }
 
-// RefreshKeyspaceAndShard refreshes the keyspace record and shard record for the given keyspace and shard.
+// RefreshKeyspaceAndShard refreshes the keyspace record, the shard record and the recovery policies for the given keyspace and shard.
 func RefreshKeyspaceAndShard(keyspaceName string, shardName string) error {
 	err := refreshKeyspace(keyspaceName)
 	if err != nil {
 		return err
 	}
-	return refreshShard(keyspaceName, shardName)
+	err = refreshShard(keyspaceName, shardName)
+	if err != nil {
+		return err
+	}
+	refreshCtx, refreshCancel := context.WithTimeout(context.Background(), topo.RemoteOperationTimeout)
+	defer refreshCancel()
+	return refreshRecoveryPolicies(refreshCtx, keyspaceName)
 }
 
 // refreshKeyspace refreshes the keyspace's information for the given keyspace from the topo
@@ -0,0 +1,116 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// This file holds the maintenance windows of the keyspaces, shards and cells,
+// which are read from the topo with the keyspace and shard records. VTOrc
+// doesn't run any recovery in a keyspace, a shard or a cell in maintenance.
+
+// MaintenanceWindowsTemplate is the HTML to use to display the keyspaces,
+// shards and cells in maintenance.
+const MaintenanceWindowsTemplate = `
+<style>
+  table {
+    border-collapse: collapse;
+  }
+  td, th {
+    border: 1px solid #999;
+    padding: 0.2rem;
+  }
+</style>
+<table>
+  <tr>
+    <th colspan="4">Maintenance Windows (no recoveries)</th>
+  </tr>
+  <tr>
+    <th>Target</th>
+    <th>Reason</th>
+    <th>Started</th>
+    <th>Expires</th>
+  </tr>
+  {{range $i, $window := .}}
+  <tr>
+    <td>{{$window.Target}}</td>
+    <td>{{$window.Reason}}</td>
+    <td>{{$window.Started}}</td>
+    <td>{{$window.Expires}}</td>
+  </tr>
+  {{end}}
+</table>
+`
+
+var (
+	maintenanceWindowsMu sync.Mutex
+	// maintenanceWindows are the maintenance windows last read from the
+	// topo. Some may have expired since.
+	maintenanceWindows []*topo.MaintenanceWindow
+)
+
+// refreshMaintenanceWindows reloads the maintenance windows from the topo.
+func refreshMaintenanceWindows(ctx context.Context) error {
+	windows, err := ts.GetMaintenanceWindows(ctx)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	maintenanceWindowsMu.Lock()
+	defer maintenanceWindowsMu.Unlock()
+	maintenanceWindows = windows
+	return nil
+}
+
+// RefreshMaintenanceWindows reloads the maintenance windows from the topo.
+func RefreshMaintenanceWindows() {
+	ctx, cancel := context.WithTimeout(context.Background(), topo.RemoteOperationTimeout)
+	defer cancel()
+	_ = refreshMaintenanceWindows(ctx)
+}
+
+// GetMaintenanceWindows returns the maintenance windows that are not over.
+func GetMaintenanceWindows() []*topo.MaintenanceWindow {
+	maintenanceWindowsMu.Lock()
+	defer maintenanceWindowsMu.Unlock()
+	now := time.Now()
+	var windows []*topo.MaintenanceWindow
+	for _, window := range maintenanceWindows {
+		if now.Before(window.Expires) {
+			windows = append(windows, window)
+		}
+	}
+	return windows
+}
+
+// getMaintenanceWindow returns the maintenance window covering a tablet of
+// the given keyspace, shard and cell, or nil if none does.
+func getMaintenanceWindow(keyspace, shard, cell string) *topo.MaintenanceWindow {
+	for _, window := range GetMaintenanceWindows() {
+		if window.Covers(keyspace, shard, cell) {
+			return window
+		}
+	}
+	return nil
+}
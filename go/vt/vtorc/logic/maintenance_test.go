@@ -0,0 +1,117 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtorc/db"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+)
+
+func TestRecoverySkippedInMaintenance(t *testing.T) {
+	oldTs := ts
+	defer func() {
+		ts = oldTs
+		maintenanceWindowsMu.Lock()
+		defer maintenanceWindowsMu.Unlock()
+		maintenanceWindows = nil
+	}()
+
+	db.ClearVTOrcDatabase()
+	defer func() {
+		db.ClearVTOrcDatabase()
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// The shard doesn't exist in the topo, so the recovery fails to lock it
+	// if it gets that far.
+	ts = memorytopo.NewServer(ctx, "zone1")
+
+	tablet := &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{
+			Cell: "zone1",
+			Uid:  100,
+		},
+		Hostname:      "localhost",
+		MysqlHostname: "localhost",
+		MysqlPort:     1200,
+		Keyspace:      "ks",
+		Shard:         "0",
+		Type:          topodatapb.TabletType_REPLICA,
+	}
+	err := inst.SaveTablet(tablet)
+	require.NoError(t, err)
+	newAnalysisEntry := func() *inst.ReplicationAnalysis {
+		return &inst.ReplicationAnalysis{
+			AnalyzedInstanceAlias: "zone1-0000000100",
+			AnalyzedKeyspace:      "ks",
+			AnalyzedShard:         "0",
+			ClusterDetails: inst.ClusterInfo{
+				Keyspace: "ks",
+				Shard:    "0",
+			},
+			Analysis: inst.ReplicationStopped,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		keyspace string
+		shard    string
+		cell     string
+	}{
+		{name: "keyspace", keyspace: "ks"},
+		{name: "shard", keyspace: "ks", shard: "0"},
+		{name: "cell", cell: "zone1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			skipped := recoveriesSkippedInMaintenanceCounter.Counts()[FixReplicaRecoveryName]
+			_, err := ts.StartMaintenance(ctx, tt.keyspace, tt.shard, tt.cell, "test", time.Hour)
+			require.NoError(t, err)
+			RefreshMaintenanceWindows()
+			require.Len(t, GetMaintenanceWindows(), 1)
+
+			err = executeCheckAndRecoverFunction(newAnalysisEntry())
+			require.NoError(t, err)
+			require.Equal(t, skipped+1, recoveriesSkippedInMaintenanceCounter.Counts()[FixReplicaRecoveryName])
+
+			err = ts.StopMaintenance(ctx, tt.keyspace, tt.shard, tt.cell)
+			require.NoError(t, err)
+			RefreshMaintenanceWindows()
+			require.Empty(t, GetMaintenanceWindows())
+		})
+	}
+
+	// The maintenance of another shard or cell doesn't stop the recovery.
+	_, err = ts.StartMaintenance(ctx, "ks", "-80", "", "", time.Hour)
+	require.NoError(t, err)
+	_, err = ts.StartMaintenance(ctx, "", "", "zone2", "", time.Hour)
+	require.NoError(t, err)
+	RefreshMaintenanceWindows()
+	require.Len(t, GetMaintenanceWindows(), 2)
+	err = executeCheckAndRecoverFunction(newAnalysisEntry())
+	require.ErrorContains(t, err, "node doesn't exist")
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/streamlog"
+	"vitess.io/vitess/go/vt/external/golib/sqlutils"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vtorc/db"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtorcdatapb "vitess.io/vitess/go/vt/proto/vtorcdata"
+)
+
+// This file holds the stream of the recovery events: the detections, the
+// decisions not to recover, and the steps of the recoveries VTOrc runs. The
+// events are streamed by the StreamRecoveryEvents RPC, and posted to the
+// --recovery-events-webhook-url if it is set.
+
+// recoveryEventsBufferSize is the number of events buffered for each
+// subscriber, and for the webhook, before the new events are dropped.
+const recoveryEventsBufferSize = 1000
+
+var (
+	recoveryEventsWebhookURL     string
+	recoveryEventsWebhookTimeout = 10 * time.Second
+
+	recoveryEvents            = streamlog.New[*vtorcdatapb.RecoveryEvent]("RecoveryEvents", recoveryEventsBufferSize)
+	recoveryEventsSubscribers atomic.Int32
+
+	recoveryEventsWebhookOnce  sync.Once
+	recoveryEventsWebhookQueue chan *vtorcdatapb.RecoveryEvent
+
+	recoveryEventsWebhookDroppedCounter = stats.NewCounter("RecoveryEventsWebhookDropped", "Number of recovery events not posted to the webhook because its queue was full")
+	recoveryEventsWebhookErrorsCounter  = stats.NewCounter("RecoveryEventsWebhookErrors", "Number of recovery events that could not be posted to the webhook")
+)
+
+// SubscribeRecoveryEvents returns a channel on which the recovery events are
+// sent as they happen. The events are dropped while the channel is full.
+func SubscribeRecoveryEvents(name string) chan *vtorcdatapb.RecoveryEvent {
+	recoveryEventsSubscribers.Add(1)
+	return recoveryEvents.Subscribe(name)
+}
+
+// UnsubscribeRecoveryEvents stops sending the recovery events to a channel
+// returned by SubscribeRecoveryEvents.
+func UnsubscribeRecoveryEvents(ch chan *vtorcdatapb.RecoveryEvent) {
+	recoveryEvents.Unsubscribe(ch)
+	recoveryEventsSubscribers.Add(-1)
+}
+
+// recoveryEventsWanted returns true if anyone consumes the recovery events,
+// so that the events and their topology snapshots are only built when needed.
+func recoveryEventsWanted() bool {
+	return recoveryEventsSubscribers.Load() > 0 || recoveryEventsWebhookURL != ""
+}
+
+// newRecoveryEvent returns an event of the given type about the analysis.
+func newRecoveryEvent(eventType vtorcdatapb.RecoveryEvent_Type, analysisEntry *inst.ReplicationAnalysis, recoveryName string, message string) *vtorcdatapb.RecoveryEvent {
+	return &vtorcdatapb.RecoveryEvent{
+		Type:         eventType,
+		Time:         protoutil.TimeToProto(time.Now()),
+		Keyspace:     analysisEntry.AnalyzedKeyspace,
+		Shard:        analysisEntry.AnalyzedShard,
+		TabletAlias:  analysisEntry.AnalyzedInstanceAlias,
+		Analysis:     string(analysisEntry.Analysis),
+		RecoveryName: recoveryName,
+		Message:      message,
+	}
+}
+
+// publishRecoveryEvent sends an event to the subscribers and to the webhook.
+// If withTopology is set, the state of the tablets of the shard, as VTOrc
+// last saw it, is added to the event.
+func publishRecoveryEvent(event *vtorcdatapb.RecoveryEvent, withTopology bool) {
+	if !recoveryEventsWanted() {
+		return
+	}
+	if withTopology {
+		snapshot, err := readTopologySnapshot(event.Keyspace, event.Shard)
+		if err != nil {
+			log.Errorf("publishRecoveryEvent: error reading the topology of %v/%v: %v", event.Keyspace, event.Shard, err)
+		}
+		event.Topology = snapshot
+	}
+
+	recoveryEvents.Send(event)
+	if recoveryEventsWebhookURL == "" {
+		return
+	}
+	recoveryEventsWebhookOnce.Do(func() {
+		recoveryEventsWebhookQueue = make(chan *vtorcdatapb.RecoveryEvent, recoveryEventsBufferSize)
+		go postRecoveryEvents(recoveryEventsWebhookQueue)
+	})
+	select {
+	case recoveryEventsWebhookQueue <- event:
+	default:
+		recoveryEventsWebhookDroppedCounter.Add(1)
+	}
+}
+
+// publishSkippedRecovery sends the SKIPPED event of an analysis that VTOrc
+// decided not to recover.
+func publishSkippedRecovery(analysisEntry *inst.ReplicationAnalysis, recoveryName string, reason string) {
+	if !recoveryEventsWanted() {
+		return
+	}
+	publishRecoveryEvent(newRecoveryEvent(vtorcdatapb.RecoveryEvent_SKIPPED, analysisEntry, recoveryName, reason), false)
+}
+
+// publishRecoveryFinished sends the RECOVERY_FINISHED event of a recovery,
+// with the topology of the shard after the recovery.
+func publishRecoveryFinished(analysisEntry *inst.ReplicationAnalysis, recoveryName string, topologyRecovery *TopologyRecovery, err error) {
+	if !recoveryEventsWanted() {
+		return
+	}
+	message := "recovery succeeded"
+	if err != nil {
+		message = err.Error()
+	}
+	event := newRecoveryEvent(vtorcdatapb.RecoveryEvent_RECOVERY_FINISHED, analysisEntry, recoveryName, message)
+	if topologyRecovery != nil {
+		event.RecoveryId = topologyRecovery.ID
+	}
+	event.Success = err == nil
+	publishRecoveryEvent(event, true)
+}
+
+// postRecoveryEvents posts the events of the queue, as JSON, to the webhook.
+func postRecoveryEvents(queue chan *vtorcdatapb.RecoveryEvent) {
+	client := &http.Client{Timeout: recoveryEventsWebhookTimeout}
+	for event := range queue {
+		if err := postRecoveryEvent(client, recoveryEventsWebhookURL, event); err != nil {
+			recoveryEventsWebhookErrorsCounter.Add(1)
+			log.Errorf("postRecoveryEvents: %v", err)
+		}
+	}
+}
+
+// postRecoveryEvent posts a single event to the webhook. Any response status
+// other than 2xx is an error.
+func postRecoveryEvent(client *http.Client, url string, event *vtorcdatapb.RecoveryEvent) error {
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned %s", url, resp.Status)
+	}
+	return nil
+}
+
+// readTopologySnapshot reads the state of the tablets of a shard, as VTOrc
+// last saw it.
+func readTopologySnapshot(keyspace, shard string) (*vtorcdatapb.TopologySnapshot, error) {
+	primaryAlias, _, err := inst.ReadShardPrimaryInformation(keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &vtorcdatapb.TopologySnapshot{
+		PrimaryAlias: primaryAlias,
+	}
+
+	query := `
+		select
+			vitess_tablet.alias,
+			vitess_tablet.tablet_type,
+			ifnull(database_instance.last_checked <= database_instance.last_seen, 0) as is_last_check_valid,
+			ifnull(database_instance.read_only, 0) as read_only,
+			ifnull(source_tablet.alias, '') as source_alias,
+			ifnull(database_instance.replica_io_running, 0) as replica_io_running,
+			ifnull(database_instance.replica_sql_running, 0) as replica_sql_running,
+			ifnull(database_instance.executed_gtid_set, '') as executed_gtid_set
+		from
+			vitess_tablet
+			left join database_instance on (
+				database_instance.alias = vitess_tablet.alias
+			)
+			left join vitess_tablet source_tablet on (
+				source_tablet.hostname = database_instance.source_host
+				and source_tablet.port = database_instance.source_port
+			)
+		where
+			vitess_tablet.keyspace = ?
+			and vitess_tablet.shard = ?
+		order by
+			vitess_tablet.alias
+		`
+	err = db.QueryVTOrc(query, sqlutils.Args(keyspace, shard), func(row sqlutils.RowMap) error {
+		snapshot.Tablets = append(snapshot.Tablets, &vtorcdatapb.TabletState{
+			Alias:             row.GetString("alias"),
+			TabletType:        topodatapb.TabletType(row.GetInt32("tablet_type")),
+			Reachable:         row.GetBool("is_last_check_valid"),
+			ReadOnly:          row.GetBool("read_only"),
+			ReplicationSource: row.GetString("source_alias"),
+			IoThreadRunning:   row.GetBool("replica_io_running"),
+			SqlThreadRunning:  row.GetBool("replica_sql_running"),
+			ExecutedGtidSet:   row.GetString("executed_gtid_set"),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
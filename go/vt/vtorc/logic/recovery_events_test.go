@@ -0,0 +1,195 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vtorc/db"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtorcdatapb "vitess.io/vitess/go/vt/proto/vtorcdata"
+)
+
+// saveTestShard saves a shard of three tablets: zone1-101 is the primary,
+// zone1-100 replicates from it, and MySQL was never reached on zone1-102.
+func saveTestShard(t *testing.T) {
+	shard := topo.NewShardInfo("ks", "0", &topodatapb.Shard{
+		PrimaryAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+	}, nil)
+	require.NoError(t, inst.SaveShard(shard))
+
+	for uid, port := range map[uint32]int32{100: 6711, 101: 6714, 102: 6747} {
+		tabletType := topodatapb.TabletType_REPLICA
+		if uid == 101 {
+			tabletType = topodatapb.TabletType_PRIMARY
+		}
+		err := inst.SaveTablet(&topodatapb.Tablet{
+			Alias:         &topodatapb.TabletAlias{Cell: "zone1", Uid: uid},
+			MysqlHostname: "localhost",
+			MysqlPort:     port,
+			Keyspace:      "ks",
+			Shard:         "0",
+			Type:          tabletType,
+		})
+		require.NoError(t, err)
+	}
+
+	// The rows of the two reachable tablets are taken from a dump of a running VTOrc.
+	for _, query := range []string{
+		`INSERT INTO database_instance VALUES('zone1-0000000100','localhost',6711,'2022-12-28 07:26:04','2022-12-28 07:26:04',1094500338,'8.0.31','ROW',1,1,'vt-0000000100-bin.000001',15963,'localhost',6714,1,1,'vt-0000000101-bin.000001',15583,'vt-0000000101-bin.000001',15583,0,0,1,'','',1,0,'vt-0000000100-relay-bin.000002',15815,0,1,0,'zone1','',0,0,0,1,'729a4cc4-8680-11ed-a104-47706090afbd:1-54','729a5138-8680-11ed-acf8-d6b0ef9f4eaa','2022-12-28 07:26:04','',1,0,0,'Homebrew','8.0','FULL',10103920,0,1,'ON',1,'729a4cc4-8680-11ed-a104-47706090afbd','','729a4cc4-8680-11ed-a104-47706090afbd,729a5138-8680-11ed-acf8-d6b0ef9f4eaa',1,1,'',1000000000000000000,1,0,1,0);`,
+		`INSERT INTO database_instance VALUES('zone1-0000000101','localhost',6714,'2022-12-28 07:26:04','2022-12-28 07:26:04',390954723,'8.0.31','ROW',1,1,'vt-0000000101-bin.000001',15583,'',0,0,0,'',0,'',0,NULL,NULL,0,'','',0,0,'',0,0,0,0,'zone1','',0,0,0,1,'729a4cc4-8680-11ed-a104-47706090afbd:1-55','729a4cc4-8680-11ed-a104-47706090afbd','2022-12-28 07:26:04','',0,0,0,'Homebrew','8.0','FULL',11366095,1,1,'ON',1,'','','729a4cc4-8680-11ed-a104-47706090afbd',-1,-1,'',1000000000000000000,1,1,0,2);`,
+	} {
+		_, err := db.ExecVTOrc(query)
+		require.NoError(t, err)
+	}
+}
+
+func TestReadTopologySnapshot(t *testing.T) {
+	db.ClearVTOrcDatabase()
+	defer db.ClearVTOrcDatabase()
+	saveTestShard(t)
+
+	snapshot, err := readTopologySnapshot("ks", "0")
+	require.NoError(t, err)
+	utils.MustMatch(t, &vtorcdatapb.TopologySnapshot{
+		PrimaryAlias: "zone1-0000000101",
+		Tablets: []*vtorcdatapb.TabletState{{
+			Alias:             "zone1-0000000100",
+			TabletType:        topodatapb.TabletType_REPLICA,
+			Reachable:         true,
+			ReadOnly:          true,
+			ReplicationSource: "zone1-0000000101",
+			IoThreadRunning:   true,
+			SqlThreadRunning:  true,
+			ExecutedGtidSet:   "729a4cc4-8680-11ed-a104-47706090afbd:1-54",
+		}, {
+			Alias:           "zone1-0000000101",
+			TabletType:      topodatapb.TabletType_PRIMARY,
+			Reachable:       true,
+			ExecutedGtidSet: "729a4cc4-8680-11ed-a104-47706090afbd:1-55",
+		}, {
+			Alias:      "zone1-0000000102",
+			TabletType: topodatapb.TabletType_REPLICA,
+		}},
+	}, snapshot)
+
+	_, err = readTopologySnapshot("ks", "-80")
+	require.ErrorIs(t, err, inst.ErrShardNotFound)
+}
+
+func TestRecoveryEventsOfSkippedRecovery(t *testing.T) {
+	db.ClearVTOrcDatabase()
+	defer db.ClearVTOrcDatabase()
+	saveTestShard(t)
+
+	require.NoError(t, DisableRecovery())
+	defer func() {
+		_ = EnableRecovery()
+	}()
+
+	events := SubscribeRecoveryEvents("test")
+	defer UnsubscribeRecoveryEvents(events)
+
+	err := executeCheckAndRecoverFunction(&inst.ReplicationAnalysis{
+		AnalyzedInstanceAlias: "zone1-0000000100",
+		AnalyzedKeyspace:      "ks",
+		AnalyzedShard:         "0",
+		ClusterDetails: inst.ClusterInfo{
+			Keyspace: "ks",
+			Shard:    "0",
+		},
+		Analysis: inst.ReplicationStopped,
+	})
+	require.NoError(t, err)
+
+	detection := <-events
+	assert.Equal(t, vtorcdatapb.RecoveryEvent_DETECTION, detection.Type)
+	assert.Equal(t, "zone1-0000000100", detection.TabletAlias)
+	assert.Equal(t, string(inst.ReplicationStopped), detection.Analysis)
+	assert.Equal(t, FixReplicaRecoveryName, detection.RecoveryName)
+	assert.NotNil(t, detection.Time)
+	require.NotNil(t, detection.Topology)
+	assert.Equal(t, "zone1-0000000101", detection.Topology.PrimaryAlias)
+	assert.Len(t, detection.Topology.Tablets, 3)
+
+	skipped := <-events
+	assert.Equal(t, vtorcdatapb.RecoveryEvent_SKIPPED, skipped.Type)
+	assert.Equal(t, "recoveries disabled globally", skipped.Message)
+	assert.Nil(t, skipped.Topology)
+
+	select {
+	case event := <-events:
+		assert.Fail(t, "unexpected event", "%v", event)
+	default:
+	}
+}
+
+func TestRecoveryEventsWebhook(t *testing.T) {
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		bodies <- body
+	}))
+	defer server.Close()
+
+	oldURL := recoveryEventsWebhookURL
+	recoveryEventsWebhookURL = server.URL
+	defer func() {
+		recoveryEventsWebhookURL = oldURL
+	}()
+
+	event := newRecoveryEvent(vtorcdatapb.RecoveryEvent_RECOVERY_STEP, &inst.ReplicationAnalysis{
+		AnalyzedInstanceAlias: "zone1-0000000101",
+		AnalyzedKeyspace:      "ks",
+		AnalyzedShard:         "0",
+		Analysis:              inst.DeadPrimary,
+	}, RecoverDeadPrimaryRecoveryName, "promoting zone1-0000000100")
+	event.RecoveryId = 7
+	publishRecoveryEvent(event, false)
+
+	select {
+	case body := <-bodies:
+		got := &vtorcdatapb.RecoveryEvent{}
+		require.NoError(t, protojson.Unmarshal(body, got))
+		utils.MustMatch(t, event, got)
+		assert.Contains(t, string(body), `"recovery_name":"RecoverDeadPrimary"`)
+	case <-time.After(10 * time.Second):
+		require.Fail(t, "the event was not posted to the webhook")
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	err := postRecoveryEvent(failing.Client(), failing.URL, event)
+	require.ErrorContains(t, err, "500 Internal Server Error")
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"context"
+	"sync"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil/promotionrule"
+	"vitess.io/vitess/go/vt/vtorc/config"
+)
+
+// This file holds the recovery policies of the keyspaces and shards, which
+// are read from the topo with the keyspace and shard records, so the changes
+// to the policies are picked up without restarting VTOrc.
+
+var (
+	recoveryPoliciesMu sync.Mutex
+	// recoveryPolicies are the recovery policies of the keyspaces, by
+	// keyspace and then by shard, the policy of the keyspace itself being
+	// under the empty shard name.
+	recoveryPolicies = make(map[string]map[string]*topo.RecoveryPolicy)
+)
+
+// refreshRecoveryPolicies reloads the recovery policies of the given keyspace.
+func refreshRecoveryPolicies(ctx context.Context, keyspaceName string) error {
+	policies, err := ts.GetRecoveryPolicies(ctx, keyspaceName)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	for shard, policy := range policies {
+		for _, name := range policy.AllowedRecoveries {
+			if !isRecoveryName(name) {
+				log.Warningf("Unknown recovery %v in the recovery policy of %v/%v", name, keyspaceName, shard)
+			}
+		}
+	}
+
+	recoveryPoliciesMu.Lock()
+	defer recoveryPoliciesMu.Unlock()
+	if len(policies) == 0 {
+		delete(recoveryPolicies, keyspaceName)
+		return nil
+	}
+	recoveryPolicies[keyspaceName] = policies
+	return nil
+}
+
+// getRecoveryPolicy returns the recovery policy of the given shard, merged
+// with the policy of its keyspace. It returns nil if neither has a policy.
+func getRecoveryPolicy(keyspaceName, shardName string) *topo.RecoveryPolicy {
+	recoveryPoliciesMu.Lock()
+	defer recoveryPoliciesMu.Unlock()
+	policies := recoveryPolicies[keyspaceName]
+	return topo.MergeRecoveryPolicies(policies[""], policies[shardName])
+}
+
+// getRecoveryCooldownSeconds returns how long the recoveries of the given
+// shard stay in their active period, blocking other recoveries.
+func getRecoveryCooldownSeconds(keyspaceName, shardName string) int {
+	if policy := getRecoveryPolicy(keyspaceName, shardName); policy != nil && policy.CooldownSeconds > 0 {
+		return policy.CooldownSeconds
+	}
+	return config.Config.RecoveryPeriodBlockSeconds
+}
+
+// getPromotionRules returns the promotion rules of a recovery policy, in the
+// form the reparent options take them.
+func getPromotionRules(policy *topo.RecoveryPolicy) map[string]promotionrule.CandidatePromotionRule {
+	if policy == nil || len(policy.PromotionRules) == 0 {
+		return nil
+	}
+	rules := make(map[string]promotionrule.CandidatePromotionRule, len(policy.PromotionRules))
+	for key, rule := range policy.PromotionRules {
+		rules[key] = promotionrule.CandidatePromotionRule(rule)
+	}
+	return rules
+}
+
+// getPromotionCells returns the cells allowed for promotion by a recovery
+// policy, or nil if they are not restricted.
+func getPromotionCells(policy *topo.RecoveryPolicy) []string {
+	if policy == nil {
+		return nil
+	}
+	return policy.PromotionCells
+}
+
+// getCandidateScoring returns how the candidates of an emergency reparent
+// are scored under a recovery policy, or nil if they are not scored.
+func getCandidateScoring(policy *topo.RecoveryPolicy) *reparentutil.CandidateScoring {
+	if !config.CandidateScoringEnabled() {
+		return nil
+	}
+	weights, err := reparentutil.ParseCandidateScoreWeights(config.CandidateScoreWeights())
+	if err != nil {
+		log.Errorf("Using the default candidate score weights: %v", err)
+		weights = reparentutil.DefaultCandidateScoreWeights
+	}
+	scoring := &reparentutil.CandidateScoring{
+		Weights:        weights,
+		PreferredCells: config.PreferredCells(),
+	}
+	if policy != nil && len(policy.PreferredCells) > 0 {
+		scoring.PreferredCells = policy.PreferredCells
+	}
+	return scoring
+}
+
+// isRecoveryName returns whether name is the name of one of the recoveries.
+func isRecoveryName(name string) bool {
+	switch name {
+	case CheckAndRecoverGenericProblemRecoveryName, RecoverDeadPrimaryRecoveryName, RecoverPrimaryTabletDeletedRecoveryName,
+		RecoverPrimaryHasPrimaryRecoveryName, CheckAndRecoverLockedSemiSyncPrimaryRecoveryName, ElectNewPrimaryRecoveryName,
+		FixPrimaryRecoveryName, FixReplicaRecoveryName, QuarantineErrantGTIDRecoveryName:
+		return true
+	default:
+		return false
+	}
+}
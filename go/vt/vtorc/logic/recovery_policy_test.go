@@ -0,0 +1,236 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/external/golib/sqlutils"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil/promotionrule"
+	"vitess.io/vitess/go/vt/vtorc/config"
+	"vitess.io/vitess/go/vt/vtorc/db"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+)
+
+// setRecoveryPolicies replaces the recovery policies loaded from the topo
+// for the duration of the test.
+func setRecoveryPolicies(t *testing.T, policies map[string]map[string]*topo.RecoveryPolicy) {
+	recoveryPoliciesMu.Lock()
+	defer recoveryPoliciesMu.Unlock()
+	oldPolicies := recoveryPolicies
+	recoveryPolicies = policies
+	t.Cleanup(func() {
+		recoveryPoliciesMu.Lock()
+		defer recoveryPoliciesMu.Unlock()
+		recoveryPolicies = oldPolicies
+	})
+}
+
+func TestRefreshRecoveryPolicies(t *testing.T) {
+	oldTs := ts
+	oldClustersToWatch := clustersToWatch
+	defer func() {
+		ts = oldTs
+		clustersToWatch = oldClustersToWatch
+	}()
+	setRecoveryPolicies(t, make(map[string]map[string]*topo.RecoveryPolicy))
+
+	db.ClearVTOrcDatabase()
+	defer func() {
+		db.ClearVTOrcDatabase()
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts = memorytopo.NewServer(ctx, "zone1")
+	clustersToWatch = nil
+	err := ts.CreateKeyspace(ctx, "ks", keyspaceDurabilityNone)
+	require.NoError(t, err)
+	for _, shardName := range []string{"-80", "80-"} {
+		err = ts.CreateShard(ctx, "ks", shardName)
+		require.NoError(t, err)
+	}
+
+	// Without policies, the flags of VTOrc apply.
+	RefreshAllKeyspacesAndShards()
+	require.Nil(t, getRecoveryPolicy("ks", "-80"))
+	require.Equal(t, config.Config.RecoveryPeriodBlockSeconds, getRecoveryCooldownSeconds("ks", "-80"))
+
+	err = ts.SaveRecoveryPolicy(ctx, "ks", "", &topo.RecoveryPolicy{
+		AllowedRecoveries: []string{FixReplicaRecoveryName},
+		PromotionCells:    []string{"zone1"},
+	})
+	require.NoError(t, err)
+	err = ts.SaveRecoveryPolicy(ctx, "ks", "-80", &topo.RecoveryPolicy{
+		PromotionRules:  map[string]string{"zone2": "must_not"},
+		CooldownSeconds: 5,
+	})
+	require.NoError(t, err)
+
+	// The new policies are picked up by the next refresh.
+	RefreshAllKeyspacesAndShards()
+	policy := getRecoveryPolicy("ks", "-80")
+	require.Equal(t, &topo.RecoveryPolicy{
+		AllowedRecoveries: []string{FixReplicaRecoveryName},
+		PromotionRules:    map[string]string{"zone2": "must_not"},
+		PromotionCells:    []string{"zone1"},
+		CooldownSeconds:   5,
+	}, policy)
+	require.Equal(t, map[string]promotionrule.CandidatePromotionRule{"zone2": promotionrule.MustNot}, getPromotionRules(policy))
+	require.Equal(t, []string{"zone1"}, getPromotionCells(policy))
+	require.Equal(t, 5, getRecoveryCooldownSeconds("ks", "-80"))
+	require.Equal(t, config.Config.RecoveryPeriodBlockSeconds, getRecoveryCooldownSeconds("ks", "80-"))
+	require.False(t, getRecoveryPolicy("ks", "80-").IsRecoveryAllowed(RecoverDeadPrimaryRecoveryName))
+
+	// And so are the deleted ones.
+	err = ts.DeleteRecoveryPolicy(ctx, "ks", "")
+	require.NoError(t, err)
+	err = ts.DeleteRecoveryPolicy(ctx, "ks", "-80")
+	require.NoError(t, err)
+	err = RefreshKeyspaceAndShard("ks", "-80")
+	require.NoError(t, err)
+	require.Nil(t, getRecoveryPolicy("ks", "-80"))
+}
+
+func TestGetCandidateScoring(t *testing.T) {
+	defer config.SetCandidateScoringEnabled(config.CandidateScoringEnabled())
+	defer config.SetCandidateScoreWeights(config.CandidateScoreWeights())
+	defer config.SetPreferredCells(config.PreferredCells())
+
+	config.SetCandidateScoringEnabled(false)
+	require.Nil(t, getCandidateScoring(nil))
+
+	config.SetCandidateScoringEnabled(true)
+	config.SetCandidateScoreWeights(map[string]string{"cell_locality": "5"})
+	config.SetPreferredCells([]string{"zone1"})
+	weights := reparentutil.DefaultCandidateScoreWeights
+	weights.CellLocality = 5
+	require.Equal(t, &reparentutil.CandidateScoring{
+		Weights:        weights,
+		PreferredCells: []string{"zone1"},
+	}, getCandidateScoring(nil))
+
+	// The preferred cells of the policy override the flag, and the default
+	// weights are used if the flag is invalid.
+	config.SetCandidateScoreWeights(map[string]string{"cell_locality": "-5"})
+	require.Equal(t, &reparentutil.CandidateScoring{
+		Weights:        reparentutil.DefaultCandidateScoreWeights,
+		PreferredCells: []string{"zone2", "zone1"},
+	}, getCandidateScoring(&topo.RecoveryPolicy{PreferredCells: []string{"zone2", "zone1"}}))
+}
+
+func TestClearActiveRecoveriesWithCooldown(t *testing.T) {
+	orcDb, err := db.OpenVTOrc()
+	require.NoError(t, err)
+	defer func() {
+		_, err = orcDb.Exec("delete from topology_recovery")
+		require.NoError(t, err)
+	}()
+
+	// The recoveries of -80 have a cooldown shorter than the recovery period block.
+	setRecoveryPolicies(t, map[string]map[string]*topo.RecoveryPolicy{
+		"ks": {"-80": {CooldownSeconds: 10}},
+	})
+	for i, shardName := range []string{"-80", "80-"} {
+		_, err = writeTopologyRecovery(NewTopologyRecovery(inst.ReplicationAnalysis{
+			AnalyzedInstanceAlias: []string{"zone1-0000000100", "zone1-0000000200"}[i],
+			ClusterDetails: inst.ClusterInfo{
+				Keyspace: "ks",
+				Shard:    shardName,
+			},
+			Analysis: inst.DeadPrimary,
+		}))
+		require.NoError(t, err)
+	}
+	_, err = db.ExecVTOrc("update topology_recovery set start_active_period = NOW() - INTERVAL 20 SECOND")
+	require.NoError(t, err)
+
+	err = ClearActiveRecoveries()
+	require.NoError(t, err)
+
+	activeRecoveries := make(map[string]bool)
+	err = db.QueryVTOrc("select shard, in_active_period from topology_recovery", nil, func(rowMap sqlutils.RowMap) error {
+		activeRecoveries[rowMap.GetString("shard")] = rowMap.GetBool("in_active_period")
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]bool{"-80": false, "80-": true}, activeRecoveries)
+}
+
+func TestRecoveryNotAllowedByPolicy(t *testing.T) {
+	oldTs := ts
+	defer func() {
+		ts = oldTs
+	}()
+
+	db.ClearVTOrcDatabase()
+	defer func() {
+		db.ClearVTOrcDatabase()
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// The shard doesn't exist in the topo, so the recovery fails to lock it
+	// if it gets that far.
+	ts = memorytopo.NewServer(ctx, "zone1")
+
+	tablet := &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{
+			Cell: "zone1",
+			Uid:  100,
+		},
+		Hostname:      "localhost",
+		MysqlHostname: "localhost",
+		MysqlPort:     1200,
+		Keyspace:      "ks",
+		Shard:         "0",
+		Type:          topodatapb.TabletType_REPLICA,
+	}
+	err := inst.SaveTablet(tablet)
+	require.NoError(t, err)
+	newAnalysisEntry := func() *inst.ReplicationAnalysis {
+		return &inst.ReplicationAnalysis{
+			AnalyzedInstanceAlias: "zone1-0000000100",
+			AnalyzedKeyspace:      "ks",
+			AnalyzedShard:         "0",
+			ClusterDetails: inst.ClusterInfo{
+				Keyspace: "ks",
+				Shard:    "0",
+			},
+			Analysis: inst.ReplicationStopped,
+		}
+	}
+
+	setRecoveryPolicies(t, map[string]map[string]*topo.RecoveryPolicy{
+		"ks": {"": {AllowedRecoveries: []string{RecoverDeadPrimaryRecoveryName}}},
+	})
+	err = executeCheckAndRecoverFunction(newAnalysisEntry())
+	require.NoError(t, err)
+
+	setRecoveryPolicies(t, map[string]map[string]*topo.RecoveryPolicy{
+		"ks": {"": {AllowedRecoveries: []string{RecoverDeadPrimaryRecoveryName, FixReplicaRecoveryName}}},
+	})
+	err = executeCheckAndRecoverFunction(newAnalysisEntry())
+	require.ErrorContains(t, err, "node doesn't exist")
+}
@@ -59,6 +59,8 @@ var (
 func RegisterFlags(fs *pflag.FlagSet) {
 	fs.StringSliceVar(&clustersToWatch, "clusters_to_watch", clustersToWatch, "Comma-separated list of keyspaces or keyspace/shards that this instance will monitor and repair. Defaults to all clusters in the topology. Example: \"ks1,ks2/-80\"")
 	fs.DurationVar(&shutdownWaitTime, "shutdown_wait_time", shutdownWaitTime, "Maximum time to wait for VTOrc to release all the locks that it is holding before shutting down on SIGTERM")
+	fs.StringVar(&recoveryEventsWebhookURL, "recovery-events-webhook-url", recoveryEventsWebhookURL, "If set, VTOrc posts each of its recovery events (detections, skipped recoveries and recovery steps), as JSON, to this URL")
+	fs.DurationVar(&recoveryEventsWebhookTimeout, "recovery-events-webhook-timeout", recoveryEventsWebhookTimeout, "Maximum time to post a single recovery event to --recovery-events-webhook-url")
 }
 
 // OpenTabletDiscovery opens the vitess topo if enables and returns a ticker
@@ -29,6 +29,9 @@ import (
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/logutil"
 	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtorcdatapb "vitess.io/vitess/go/vt/proto/vtorcdata"
+	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vtctl/reparentutil"
 	"vitess.io/vitess/go/vt/vtorc/config"
@@ -48,6 +51,7 @@ const (
 	ElectNewPrimaryRecoveryName                      string = "ElectNewPrimary"
 	FixPrimaryRecoveryName                           string = "FixPrimary"
 	FixReplicaRecoveryName                           string = "FixReplica"
+	QuarantineErrantGTIDRecoveryName                 string = "QuarantineErrantGTID"
 )
 
 var (
@@ -57,6 +61,7 @@ var (
 		ElectNewPrimaryRecoveryName,
 		FixPrimaryRecoveryName,
 		FixReplicaRecoveryName,
+		QuarantineErrantGTIDRecoveryName,
 	}
 
 	countPendingRecoveries = stats.NewGauge("PendingRecoveries", "Count of the number of pending recoveries")
@@ -69,6 +74,12 @@ var (
 
 	// recoveriesFailureCounter counts the number of failed recoveries that VTOrc has performed
 	recoveriesFailureCounter = stats.NewCountersWithSingleLabel("FailedRecoveries", "Count of the different failed recoveries performed", "RecoveryType", actionableRecoveriesNames...)
+
+	// recoveriesSkippedInMaintenanceCounter counts the recoveries not run because of a maintenance window.
+	recoveriesSkippedInMaintenanceCounter = stats.NewCountersWithSingleLabel("RecoveriesSkippedInMaintenance", "Count of the different recoveries not performed because of a maintenance window", "RecoveryType", actionableRecoveriesNames...)
+
+	// errantGTIDQuarantinesCounter counts the replicas VTOrc quarantined because of their errant GTIDs.
+	errantGTIDQuarantinesCounter = stats.NewCountersWithMultiLabels("ErrantGTIDQuarantines", "Count of the replicas quarantined because of errant GTIDs", []string{"Keyspace", "Shard"})
 )
 
 // recoveryFunction is the code of the recovery function to be used
@@ -86,6 +97,7 @@ const (
 	electNewPrimaryFunc
 	fixPrimaryFunc
 	fixReplicaFunc
+	quarantineErrantGTIDFunc
 )
 
 // TopologyRecovery represents an entry in the topology_recovery table
@@ -170,6 +182,12 @@ func AuditTopologyRecovery(topologyRecovery *TopologyRecovery, message string) e
 		return nil
 	}
 
+	if recoveryEventsWanted() {
+		event := newRecoveryEvent(vtorcdatapb.RecoveryEvent_RECOVERY_STEP, &topologyRecovery.AnalysisEntry, "", message)
+		event.RecoveryId = topologyRecovery.ID
+		publishRecoveryEvent(event, false)
+	}
+
 	recoveryStep := NewTopologyRecoveryStep(topologyRecovery.UID, message)
 	return writeTopologyRecoveryStep(recoveryStep)
 }
@@ -230,6 +248,7 @@ func runEmergencyReparentOp(ctx context.Context, analysisEntry *inst.Replication
 		_ = resolveRecovery(topologyRecovery, promotedReplica)
 	}()
 
+	policy := getRecoveryPolicy(tablet.Keyspace, tablet.Shard)
 	ev, err := reparentutil.NewEmergencyReparenter(ts, tmclient.NewTabletManagerClient(), logutil.NewCallbackLogger(func(event *logutilpb.Event) {
 		level := event.GetLevel()
 		value := event.GetValue()
@@ -251,6 +270,9 @@ func runEmergencyReparentOp(ctx context.Context, analysisEntry *inst.Replication
 			WaitReplicasTimeout:       time.Duration(config.Config.WaitReplicasTimeoutSeconds) * time.Second,
 			PreventCrossCellPromotion: config.Config.PreventCrossDataCenterPrimaryFailover,
 			WaitAllTablets:            waitForAllTablets,
+			PromotionCells:            getPromotionCells(policy),
+			PromotionRules:            getPromotionRules(policy),
+			CandidateScoring:          getCandidateScoring(policy),
 		},
 	)
 	if err != nil {
@@ -420,6 +442,12 @@ func getCheckAndRecoverFunctionCode(analysisCode inst.AnalysisCode, tabletAlias
 	case inst.NotConnectedToPrimary, inst.ConnectedToWrongPrimary, inst.ReplicationStopped, inst.ReplicaIsWritable,
 		inst.ReplicaSemiSyncMustBeSet, inst.ReplicaSemiSyncMustNotBeSet:
 		return fixReplicaFunc
+	case inst.ErrantGTIDDetected:
+		if !config.ErrantGTIDQuarantineEnabled() {
+			log.Infof("VTOrc not configured to quarantine replicas with errant GTIDs, skipping recovering %v", analysisCode)
+			return noRecoveryFunc
+		}
+		return quarantineErrantGTIDFunc
 	// primary, non actionable
 	case inst.DeadPrimaryAndReplicas:
 		return recoverGenericProblemFunc
@@ -461,6 +489,8 @@ func hasActionableRecovery(recoveryFunctionCode recoveryFunction) bool {
 		return true
 	case fixReplicaFunc:
 		return true
+	case quarantineErrantGTIDFunc:
+		return true
 	default:
 		return false
 	}
@@ -489,6 +519,8 @@ func getCheckAndRecoverFunction(recoveryFunctionCode recoveryFunction) (
 		return fixPrimary
 	case fixReplicaFunc:
 		return fixReplica
+	case quarantineErrantGTIDFunc:
+		return quarantineErrantGTID
 	default:
 		return nil
 	}
@@ -516,6 +548,8 @@ func getRecoverFunctionName(recoveryFunctionCode recoveryFunction) string {
 		return FixPrimaryRecoveryName
 	case fixReplicaFunc:
 		return FixReplicaRecoveryName
+	case quarantineErrantGTIDFunc:
+		return QuarantineErrantGTIDRecoveryName
 	default:
 		return ""
 	}
@@ -557,6 +591,24 @@ func runEmergentOperations(analysisEntry *inst.ReplicationAnalysis) {
 	}
 }
 
+// skipRecoveryInMaintenance returns true, and logs it, if the analyzed tablet
+// is in a keyspace, a shard or a cell in maintenance.
+func skipRecoveryInMaintenance(analysisEntry *inst.ReplicationAnalysis, recoveryName string) bool {
+	var cell string
+	if alias, err := topoproto.ParseTabletAlias(analysisEntry.AnalyzedInstanceAlias); err == nil {
+		cell = alias.Cell
+	}
+	window := getMaintenanceWindow(analysisEntry.AnalyzedKeyspace, analysisEntry.AnalyzedShard, cell)
+	if window == nil {
+		return false
+	}
+	log.Infof("CheckAndRecover: Analysis: %+v, Tablet: %+v: NOT Recovering host (%v in maintenance until %v: %v)",
+		analysisEntry.Analysis, analysisEntry.AnalyzedInstanceAlias, window.Target(), window.Expires, window.Reason)
+	recoveriesSkippedInMaintenanceCounter.Add(recoveryName, 1)
+	publishSkippedRecovery(analysisEntry, recoveryName, fmt.Sprintf("%v in maintenance until %v: %v", window.Target(), window.Expires, window.Reason))
+	return true
+}
+
 // executeCheckAndRecoverFunction will choose the correct check & recovery function based on analysis.
 // It executes the function synchronuously
 func executeCheckAndRecoverFunction(analysisEntry *inst.ReplicationAnalysis) (err error) {
@@ -587,11 +639,14 @@ func executeCheckAndRecoverFunction(analysisEntry *inst.ReplicationAnalysis) (er
 	// At this point we have validated there's a failure scenario for which we have a recovery path.
 
 	// Initiate detection:
-	_, _, err = checkAndExecuteFailureDetectionProcesses(analysisEntry)
+	detected, _, err := checkAndExecuteFailureDetectionProcesses(analysisEntry)
 	if err != nil {
 		log.Errorf("executeCheckAndRecoverFunction: error on failure detection: %+v", err)
 		return err
 	}
+	if detected && recoveryEventsWanted() {
+		publishRecoveryEvent(newRecoveryEvent(vtorcdatapb.RecoveryEvent_DETECTION, analysisEntry, getRecoverFunctionName(checkAndRecoverFunctionCode), fmt.Sprintf("detected %v failure", analysisEntry.Analysis)), true)
+	}
 	// We don't mind whether detection really executed the processes or not
 	// (it may have been silenced due to previous detection). We only care there's no error.
 
@@ -604,10 +659,25 @@ func executeCheckAndRecoverFunction(analysisEntry *inst.ReplicationAnalysis) (er
 	} else if recoveryDisabledGlobally {
 		log.Infof("CheckAndRecover: Analysis: %+v, Tablet: %+v: NOT Recovering host (disabled globally)",
 			analysisEntry.Analysis, analysisEntry.AnalyzedInstanceAlias)
+		publishSkippedRecovery(analysisEntry, getRecoverFunctionName(checkAndRecoverFunctionCode), "recoveries disabled globally")
 
 		return err
 	}
 
+	// Check for the recovery being allowed by the recovery policy of the shard
+	if isActionableRecovery {
+		recoveryName := getRecoverFunctionName(checkAndRecoverFunctionCode)
+		if !getRecoveryPolicy(analysisEntry.AnalyzedKeyspace, analysisEntry.AnalyzedShard).IsRecoveryAllowed(recoveryName) {
+			log.Infof("CheckAndRecover: Analysis: %+v, Tablet: %+v: NOT Recovering host (%v not allowed by the recovery policy of %v/%v)",
+				analysisEntry.Analysis, analysisEntry.AnalyzedInstanceAlias, recoveryName, analysisEntry.AnalyzedKeyspace, analysisEntry.AnalyzedShard)
+			publishSkippedRecovery(analysisEntry, recoveryName, fmt.Sprintf("%v not allowed by the recovery policy of %v/%v", recoveryName, analysisEntry.AnalyzedKeyspace, analysisEntry.AnalyzedShard))
+			return nil
+		}
+		if skipRecoveryInMaintenance(analysisEntry, recoveryName) {
+			return nil
+		}
+	}
+
 	// We lock the shard here and then refresh the tablets information
 	ctx, unlock, err := LockShard(context.Background(), analysisEntry.AnalyzedInstanceAlias, getLockAction(analysisEntry.AnalyzedInstanceAlias, analysisEntry.Analysis))
 	if err != nil {
@@ -630,6 +700,13 @@ func executeCheckAndRecoverFunction(analysisEntry *inst.ReplicationAnalysis) (er
 		if err != nil {
 			return err
 		}
+		// The maintenance may have started since the windows were last read.
+		if err = refreshMaintenanceWindows(ctx); err != nil {
+			return err
+		}
+		if skipRecoveryInMaintenance(analysisEntry, getRecoverFunctionName(checkAndRecoverFunctionCode)) {
+			return nil
+		}
 		// If we are about to run a cluster-wide recovery, it is imperative to first refresh all the tablets
 		// of a shard because a new tablet could have been promoted, and we need to have this visibility before we
 		// run a cluster operation of our own.
@@ -671,6 +748,7 @@ func executeCheckAndRecoverFunction(analysisEntry *inst.ReplicationAnalysis) (er
 		}
 		if alreadyFixed {
 			log.Infof("Analysis: %v on tablet %v - No longer valid, some other agent must have fixed the problem.", analysisEntry.Analysis, analysisEntry.AnalyzedInstanceAlias)
+			publishSkippedRecovery(analysisEntry, getRecoverFunctionName(checkAndRecoverFunctionCode), "no longer valid, some other agent must have fixed the problem")
 			return nil
 		}
 	}
@@ -679,11 +757,24 @@ func executeCheckAndRecoverFunction(analysisEntry *inst.ReplicationAnalysis) (er
 	if isActionableRecovery || util.ClearToLog("executeCheckAndRecoverFunction: recovery", analysisEntry.AnalyzedInstanceAlias) {
 		log.Infof("executeCheckAndRecoverFunction: proceeding with %+v recovery on %+v; isRecoverable?: %+v", analysisEntry.Analysis, analysisEntry.AnalyzedInstanceAlias, isActionableRecovery)
 	}
+	recoveryName := getRecoverFunctionName(checkAndRecoverFunctionCode)
+	if recoveryEventsWanted() {
+		publishRecoveryEvent(newRecoveryEvent(vtorcdatapb.RecoveryEvent_RECOVERY_STARTED, analysisEntry, recoveryName, "starting the recovery"), true)
+	}
 	recoveryAttempted, topologyRecovery, err := getCheckAndRecoverFunction(checkAndRecoverFunctionCode)(ctx, analysisEntry)
 	if !recoveryAttempted {
+		reason := "recovery not attempted"
+		if err != nil {
+			reason = fmt.Sprintf("recovery not attempted: %v", err)
+		}
+		publishSkippedRecovery(analysisEntry, recoveryName, reason)
 		return err
 	}
-	recoveryName := getRecoverFunctionName(checkAndRecoverFunctionCode)
+	// The tablets are refreshed after the recovery, so the event of its end
+	// holds the topology the recovery left.
+	defer func() {
+		publishRecoveryFinished(analysisEntry, recoveryName, topologyRecovery, err)
+	}()
 	recoveriesCounter.Add(recoveryName, 1)
 	if err != nil {
 		recoveriesFailureCounter.Add(recoveryName, 1)
@@ -785,6 +876,7 @@ func electNewPrimary(ctx context.Context, analysisEntry *inst.ReplicationAnalysi
 	}
 	_ = AuditTopologyRecovery(topologyRecovery, "starting PlannedReparentShard for electing new primary.")
 
+	policy := getRecoveryPolicy(analyzedTablet.Keyspace, analyzedTablet.Shard)
 	ev, err := reparentutil.NewPlannedReparenter(ts, tmclient.NewTabletManagerClient(), logutil.NewCallbackLogger(func(event *logutilpb.Event) {
 		level := event.GetLevel()
 		value := event.GetValue()
@@ -801,6 +893,8 @@ func electNewPrimary(ctx context.Context, analysisEntry *inst.ReplicationAnalysi
 		analyzedTablet.Shard,
 		reparentutil.PlannedReparentOptions{
 			WaitReplicasTimeout: time.Duration(config.Config.WaitReplicasTimeoutSeconds) * time.Second,
+			PromotionCells:      getPromotionCells(policy),
+			PromotionRules:      getPromotionRules(policy),
 		},
 	)
 
@@ -882,3 +976,49 @@ func fixReplica(ctx context.Context, analysisEntry *inst.ReplicationAnalysis) (r
 	err = setReplicationSource(ctx, analyzedTablet, primaryTablet, reparentutil.IsReplicaSemiSync(durabilityPolicy, primaryTablet, analyzedTablet))
 	return true, topologyRecovery, err
 }
+
+// quarantineErrantGTID takes a replica with errant GTIDs out of serving, by changing its type
+// to the configured quarantine type, and tags it with its errant GTID set. The replica stays
+// quarantined until an operator fixes it with FixErrantGTID.
+func quarantineErrantGTID(ctx context.Context, analysisEntry *inst.ReplicationAnalysis) (recoveryAttempted bool, topologyRecovery *TopologyRecovery, err error) {
+	topologyRecovery, err = AttemptRecoveryRegistration(analysisEntry, false, true)
+	if topologyRecovery == nil {
+		_ = AuditTopologyRecovery(topologyRecovery, fmt.Sprintf("found an active or recent recovery on %+v. Will not issue another quarantineErrantGTID.", analysisEntry.AnalyzedInstanceAlias))
+		return false, nil, err
+	}
+	log.Infof("Analysis: %v, will quarantine replica %+v", analysisEntry.Analysis, analysisEntry.AnalyzedInstanceAlias)
+	// This has to be done in the end; whether successful or not, we should mark that the recovery is done.
+	// So that after the active period passes, we are able to run other recoveries.
+	defer func() {
+		_ = resolveRecovery(topologyRecovery, nil)
+	}()
+
+	analyzedTablet, err := inst.ReadTablet(analysisEntry.AnalyzedInstanceAlias)
+	if err != nil {
+		return false, topologyRecovery, err
+	}
+
+	err = tmc.ChangeType(ctx, analyzedTablet, config.ErrantGTIDQuarantineTabletType(), false)
+	if err != nil {
+		log.Errorf("Could not change the type of the tablet %v to %v - %v", analysisEntry.AnalyzedInstanceAlias, config.ErrantGTIDQuarantineTabletType(), err)
+		return true, topologyRecovery, err
+	}
+	_ = AuditTopologyRecovery(topologyRecovery, fmt.Sprintf("changed the type of %+v to %v", analysisEntry.AnalyzedInstanceAlias, config.ErrantGTIDQuarantineTabletType()))
+
+	_, err = ts.UpdateTabletFields(ctx, analyzedTablet.Alias, func(tablet *topodatapb.Tablet) error {
+		if tablet.Tags == nil {
+			tablet.Tags = make(map[string]string)
+		}
+		tablet.Tags[topo.ErrantGTIDQuarantineTag] = analysisEntry.GTIDErrant
+		return nil
+	})
+	if err != nil {
+		log.Errorf("Could not tag the tablet %v as quarantined - %v", analysisEntry.AnalyzedInstanceAlias, err)
+		return true, topologyRecovery, err
+	}
+
+	errantGTIDQuarantinesCounter.Add([]string{analyzedTablet.Keyspace, analyzedTablet.Shard}, 1)
+	log.Warningf("Quarantined replica %v of %v/%v because of its errant GTIDs %v, run FixErrantGTID to fix it", analysisEntry.AnalyzedInstanceAlias, analyzedTablet.Keyspace, analyzedTablet.Shard, analysisEntry.GTIDErrant)
+	_ = AuditTopologyRecovery(topologyRecovery, fmt.Sprintf("quarantined %+v with errant GTIDs %v", analysisEntry.AnalyzedInstanceAlias, analysisEntry.GTIDErrant))
+	return true, topologyRecovery, nil
+}
@@ -220,22 +220,52 @@ func AttemptRecoveryRegistration(analysisEntry *inst.ReplicationAnalysis, failIf
 }
 
 // ClearActiveRecoveries clears the "in_active_period" flag for old-enough recoveries, thereby allowing for
-// further recoveries on cleared instances.
+// further recoveries on cleared instances. The recoveries of a shard are old enough after the cooldown of
+// its recovery policy, if it has one, and after RecoveryPeriodBlockSeconds otherwise.
 func ClearActiveRecoveries() error {
-	_, err := db.ExecVTOrc(`
+	type keyspaceShard struct {
+		keyspace string
+		shard    string
+	}
+	var shards []keyspaceShard
+	err := db.QueryVTOrc(`
+			select distinct
+				keyspace,
+				shard
+			from
+				topology_recovery
+			where
+				in_active_period = 1
+			`, nil, func(m sqlutils.RowMap) error {
+		shards = append(shards, keyspaceShard{keyspace: m.GetString("keyspace"), shard: m.GetString("shard")})
+		return nil
+	})
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	for _, ks := range shards {
+		_, err = db.ExecVTOrc(`
 			update topology_recovery set
 				in_active_period = 0,
 				end_active_period_unixtime = UNIX_TIMESTAMP()
 			where
 				in_active_period = 1
+				AND keyspace = ?
+				AND shard = ?
 				AND start_active_period < NOW() - INTERVAL ? SECOND
 			`,
-		config.Config.RecoveryPeriodBlockSeconds,
-	)
-	if err != nil {
-		log.Error(err)
+			ks.keyspace,
+			ks.shard,
+			getRecoveryCooldownSeconds(ks.keyspace, ks.shard),
+		)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
 	}
-	return err
+	return nil
 }
 
 // RegisterBlockedRecoveries writes down currently blocked recoveries, and indicates what recovery they are blocked on.
@@ -510,11 +540,20 @@ func ReadInActivePeriodSuccessorInstanceRecovery(tabletAlias string) ([]*Topolog
 	return readRecoveries(whereClause, ``, sqlutils.Args(tabletAlias))
 }
 
-// ReadRecentRecoveries reads latest recovery entries from topology_recovery
-func ReadRecentRecoveries(unacknowledgedOnly bool, page int) ([]*TopologyRecovery, error) {
+// ReadRecentRecoveries reads latest recovery entries from topology_recovery,
+// optionally filtered by keyspace and shard
+func ReadRecentRecoveries(keyspace string, shard string, unacknowledgedOnly bool, page int) ([]*TopologyRecovery, error) {
 	whereConditions := []string{}
 	whereClause := ""
 	var args []any
+	if keyspace != "" {
+		whereConditions = append(whereConditions, `keyspace=?`)
+		args = append(args, keyspace)
+		if shard != "" {
+			whereConditions = append(whereConditions, `shard=?`)
+			args = append(args, shard)
+		}
+	}
 	if unacknowledgedOnly {
 		whereConditions = append(whereConditions, `acknowledged=0`)
 	}
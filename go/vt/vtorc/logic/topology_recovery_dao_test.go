@@ -59,12 +59,24 @@ func TestTopologyRecovery(t *testing.T) {
 	})
 
 	t.Run("read recoveries", func(t *testing.T) {
-		recoveries, err := ReadRecentRecoveries(false, 0)
+		recoveries, err := ReadRecentRecoveries("", "", false, 0)
 		require.NoError(t, err)
 		require.Len(t, recoveries, 1)
 		// Assert that the ID field matches the one that we just wrote
 		require.EqualValues(t, topologyRecovery.ID, recoveries[0].ID)
 	})
+
+	t.Run("read recoveries of a keyspace and shard", func(t *testing.T) {
+		recoveries, err := ReadRecentRecoveries(keyspace, shard, false, 0)
+		require.NoError(t, err)
+		require.Len(t, recoveries, 1)
+		recoveries, err = ReadRecentRecoveries(keyspace, "other", false, 0)
+		require.NoError(t, err)
+		require.Empty(t, recoveries)
+		recoveries, err = ReadRecentRecoveries("other", "", false, 0)
+		require.NoError(t, err)
+		require.Empty(t, recoveries)
+	})
 }
 
 // TestBlockedRecoveryInsertion tests that we are able to insert into the blocked_recovery table.
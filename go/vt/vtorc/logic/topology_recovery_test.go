@@ -197,6 +197,7 @@ func TestGetCheckAndRecoverFunctionCode(t *testing.T) {
 	tests := []struct {
 		name                 string
 		ersEnabled           bool
+		quarantineEnabled    bool
 		analysisCode         inst.AnalysisCode
 		wantRecoveryFunction recoveryFunction
 	}{
@@ -240,6 +241,16 @@ func TestGetCheckAndRecoverFunctionCode(t *testing.T) {
 			ersEnabled:           false,
 			analysisCode:         inst.PrimarySemiSyncMustBeSet,
 			wantRecoveryFunction: fixPrimaryFunc,
+		}, {
+			name:                 "ErrantGTIDDetected with quarantine enabled",
+			quarantineEnabled:    true,
+			analysisCode:         inst.ErrantGTIDDetected,
+			wantRecoveryFunction: quarantineErrantGTIDFunc,
+		}, {
+			name:                 "ErrantGTIDDetected with quarantine disabled",
+			quarantineEnabled:    false,
+			analysisCode:         inst.ErrantGTIDDetected,
+			wantRecoveryFunction: noRecoveryFunc,
 		},
 	}
 
@@ -254,6 +265,9 @@ func TestGetCheckAndRecoverFunctionCode(t *testing.T) {
 			prevVal := config.ERSEnabled()
 			config.SetERSEnabled(tt.ersEnabled)
 			defer config.SetERSEnabled(prevVal)
+			prevQuarantine := config.ErrantGTIDQuarantineEnabled()
+			config.SetErrantGTIDQuarantineEnabled(tt.quarantineEnabled)
+			defer config.SetErrantGTIDQuarantineEnabled(prevQuarantine)
 
 			gotFunc := getCheckAndRecoverFunctionCode(tt.analysisCode, "")
 			require.EqualValues(t, tt.wantRecoveryFunction, gotFunc)
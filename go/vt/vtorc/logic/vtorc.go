@@ -425,6 +425,13 @@ func ContinuousDiscovery() {
 				RefreshAllKeyspacesAndShards()
 			}()
 
+			// Refresh the maintenance windows.
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				RefreshMaintenanceWindows()
+			}()
+
 			// Refresh all tablets.
 			wg.Add(1)
 			go func() {
@@ -432,7 +439,7 @@ func ContinuousDiscovery() {
 				refreshAllTablets()
 			}()
 
-			// Wait for both the refreshes to complete
+			// Wait for all the refreshes to complete
 			wg.Wait()
 			// We have completed one discovery cycle in the entirety of it. We should update the process health.
 			process.FirstDiscoveryCycleComplete.Store(true)
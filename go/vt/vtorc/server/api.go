@@ -26,6 +26,7 @@ import (
 
 	"vitess.io/vitess/go/acl"
 	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/vtorc/collection"
 	"vitess.io/vitess/go/vt/vtorc/discovery"
 	"vitess.io/vitess/go/vt/vtorc/inst"
@@ -47,6 +48,7 @@ const (
 	replicationAnalysisAPI        = "/api/replication-analysis"
 	healthAPI                     = "/debug/health"
 	AggregatedDiscoveryMetricsAPI = "/api/aggregated-discovery-metrics"
+	maintenanceWindowsAPI         = "/api/maintenance-windows"
 
 	shardWithoutKeyspaceFilteringErrorStr = "Filtering by shard without keyspace isn't supported"
 	notAValidValueForSeconds              = "Invalid value for seconds"
@@ -62,6 +64,7 @@ var (
 		replicationAnalysisAPI,
 		healthAPI,
 		AggregatedDiscoveryMetricsAPI,
+		maintenanceWindowsAPI,
 	}
 )
 
@@ -88,6 +91,8 @@ func (v *vtorcAPI) ServeHTTP(response http.ResponseWriter, request *http.Request
 		replicationAnalysisAPIHandler(response, request)
 	case AggregatedDiscoveryMetricsAPI:
 		AggregatedDiscoveryMetricsAPIHandler(response, request)
+	case maintenanceWindowsAPI:
+		maintenanceWindowsAPIHandler(response, request)
 	default:
 		// This should be unreachable. Any endpoint which isn't registered is automatically redirected to /debug/status.
 		// This code will only be reachable if we register an API but don't handle it here. That will be a bug.
@@ -106,6 +111,8 @@ func getACLPermissionLevelForAPI(apiEndpoint string) string {
 		return acl.MONITORING
 	case healthAPI:
 		return acl.MONITORING
+	case maintenanceWindowsAPI:
+		return acl.MONITORING
 	}
 	return acl.ADMIN
 }
@@ -231,6 +238,15 @@ func replicationAnalysisAPIHandler(response http.ResponseWriter, request *http.R
 	returnAsJSON(response, http.StatusOK, analysis)
 }
 
+// maintenanceWindowsAPIHandler is the handler for the maintenanceWindowsAPI endpoint
+func maintenanceWindowsAPIHandler(response http.ResponseWriter, request *http.Request) {
+	windows := logic.GetMaintenanceWindows()
+	if windows == nil {
+		windows = []*topo.MaintenanceWindow{}
+	}
+	returnAsJSON(response, http.StatusOK, windows)
+}
+
 // healthAPIHandler is the handler for the healthAPI endpoint
 func healthAPIHandler(response http.ResponseWriter, request *http.Request) {
 	health, err := process.HealthTest()
@@ -31,6 +31,9 @@ func TestGetACLPermissionLevelForAPI(t *testing.T) {
 		}, {
 			apiEndpoint: healthAPI,
 			want:        acl.MONITORING,
+		}, {
+			apiEndpoint: maintenanceWindowsAPI,
+			want:        acl.MONITORING,
 		}, {
 			apiEndpoint: "gibberish",
 			want:        acl.ADMIN,
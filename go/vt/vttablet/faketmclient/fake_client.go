@@ -116,6 +116,26 @@ func (client *FakeTabletManagerClient) GetPermissions(ctx context.Context, table
 	return &tabletmanagerdatapb.Permissions{}, nil
 }
 
+// GetPlanCache is part of the tmclient.TabletManagerClient interface.
+func (client *FakeTabletManagerClient) GetPlanCache(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.GetPlanCacheRequest) (*tabletmanagerdatapb.GetPlanCacheResponse, error) {
+	return &tabletmanagerdatapb.GetPlanCacheResponse{}, nil
+}
+
+// GetTableStats is part of the tmclient.TabletManagerClient interface.
+func (client *FakeTabletManagerClient) GetTableStats(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.GetTableStatsRequest) (*tabletmanagerdatapb.GetTableStatsResponse, error) {
+	return &tabletmanagerdatapb.GetTableStatsResponse{}, nil
+}
+
+// GetUnresolvedTransactions is part of the tmclient.TabletManagerClient interface.
+func (client *FakeTabletManagerClient) GetUnresolvedTransactions(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.GetUnresolvedTransactionsRequest) (*tabletmanagerdatapb.GetUnresolvedTransactionsResponse, error) {
+	return &tabletmanagerdatapb.GetUnresolvedTransactionsResponse{}, nil
+}
+
+// ReadTransaction is part of the tmclient.TabletManagerClient interface.
+func (client *FakeTabletManagerClient) ReadTransaction(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.ReadTransactionRequest) (*tabletmanagerdatapb.ReadTransactionResponse, error) {
+	return &tabletmanagerdatapb.ReadTransactionResponse{}, nil
+}
+
 // LockTables is part of the tmclient.TabletManagerClient interface.
 func (client *FakeTabletManagerClient) LockTables(ctx context.Context, tablet *topodatapb.Tablet) error {
 	return nil
@@ -160,6 +180,16 @@ func (client *FakeTabletManagerClient) ReloadSchema(ctx context.Context, tablet
 	return nil
 }
 
+// InvalidatePlanCache is part of the tmclient.TabletManagerClient interface.
+func (client *FakeTabletManagerClient) InvalidatePlanCache(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.InvalidatePlanCacheRequest) (*tabletmanagerdatapb.InvalidatePlanCacheResponse, error) {
+	return &tabletmanagerdatapb.InvalidatePlanCacheResponse{}, nil
+}
+
+// ConcludeTransaction is part of the tmclient.TabletManagerClient interface.
+func (client *FakeTabletManagerClient) ConcludeTransaction(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.ConcludeTransactionRequest) (*tabletmanagerdatapb.ConcludeTransactionResponse, error) {
+	return &tabletmanagerdatapb.ConcludeTransactionResponse{}, nil
+}
+
 // PreflightSchema is part of the tmclient.TabletManagerClient interface.
 func (client *FakeTabletManagerClient) PreflightSchema(ctx context.Context, tablet *topodatapb.Tablet, changes []string) ([]*tabletmanagerdatapb.SchemaChangeResult, error) {
 	return make([]*tabletmanagerdatapb.SchemaChangeResult, len(changes)), nil
@@ -333,13 +363,24 @@ func (e *eofEventStream) Recv() (*logutilpb.Event, error) {
 	return nil, io.EOF
 }
 
+type eofRestoreFromBackupStream struct{}
+
+func (e *eofRestoreFromBackupStream) Recv() (*tabletmanagerdatapb.RestoreFromBackupResponse, error) {
+	return nil, io.EOF
+}
+
 // Backup is part of the tmclient.TabletManagerClient interface.
 func (client *FakeTabletManagerClient) Backup(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.BackupRequest) (logutil.EventStream, error) {
 	return &eofEventStream{}, nil
 }
 
 // RestoreFromBackup is part of the tmclient.TabletManagerClient interface.
-func (client *FakeTabletManagerClient) RestoreFromBackup(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.RestoreFromBackupRequest) (logutil.EventStream, error) {
+func (client *FakeTabletManagerClient) RestoreFromBackup(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.RestoreFromBackupRequest) (tmclient.RestoreFromBackupStream, error) {
+	return &eofRestoreFromBackupStream{}, nil
+}
+
+// UpgradeMysql is part of the tmclient.TabletManagerClient interface.
+func (client *FakeTabletManagerClient) UpgradeMysql(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.UpgradeMysqlRequest) (logutil.EventStream, error) {
 	return &eofEventStream{}, nil
 }
 
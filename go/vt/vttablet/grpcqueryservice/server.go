@@ -18,25 +18,92 @@ package grpcqueryservice
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
 
+	"github.com/spf13/pflag"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
 
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/stats"
 	"vitess.io/vitess/go/vt/callerid"
 	"vitess.io/vitess/go/vt/callinfo"
+	"vitess.io/vitess/go/vt/grpcclient"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vttablet/queryservice"
+	"vitess.io/vitess/go/vt/vttablet/vstreamcompression"
 
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	queryservicepb "vitess.io/vitess/go/vt/proto/queryservice"
 )
 
+var (
+	streamCompression string
+
+	streamCompressions = stats.NewCountersWithSingleLabel("QueryStreamCompressions", "Query result streams sent to the clients, per negotiated compressor", "Compressor")
+)
+
+func registerFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&streamCompression, "grpc_stream_compression", streamCompression, fmt.Sprintf("Comma-separated list of compressors, in order of preference, compressing the query result streams sent to the clients supporting them. The streams sent to other clients use the compression of their requests. Supported: %s", strings.Join(grpcclient.CompressorNames(), ", ")))
+}
+
+func init() {
+	servenv.OnParseFor("vttablet", registerFlags)
+}
+
 // query is the gRPC query service implementation.
 // It implements the queryservice.QueryServer interface.
 type query struct {
 	queryservicepb.UnimplementedQueryServer
 	server queryservice.QueryService
+
+	// streamCompressors are the compressors of --grpc_stream_compression.
+	streamCompressors []string
+}
+
+// parseStreamCompression returns the compressors of --grpc_stream_compression.
+func parseStreamCompression(value string) ([]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var compressors []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if encoding.GetCompressor(name) == nil {
+			return nil, fmt.Errorf("unsupported compressor %q, expected one of %s", name, strings.Join(grpcclient.CompressorNames(), ", "))
+		}
+		compressors = append(compressors, name)
+	}
+	return compressors, nil
+}
+
+// negotiateStreamCompression compresses the results streamed to the client with the first
+// compressor of --grpc_stream_compression the client advertised, if any.
+func (q *query) negotiateStreamCompression(ctx context.Context) {
+	if len(q.streamCompressors) == 0 {
+		return
+	}
+	advertised, err := grpc.ClientSupportedCompressors(ctx)
+	if err != nil {
+		return
+	}
+	for _, name := range q.streamCompressors {
+		if !slices.Contains(advertised, name) {
+			continue
+		}
+		if err := grpc.SetSendCompressor(ctx, name); err != nil {
+			log.Warningf("cannot compress the query result stream with %s: %v", name, err)
+			break
+		}
+		streamCompressions.Add(name, 1)
+		return
+	}
+	streamCompressions.Add("none", 1)
 }
 
 var _ queryservicepb.QueryServer = (*query)(nil)
@@ -60,6 +127,7 @@ func (q *query) Execute(ctx context.Context, request *querypb.ExecuteRequest) (r
 // StreamExecute is part of the queryservice.QueryServer interface
 func (q *query) StreamExecute(request *querypb.StreamExecuteRequest, stream queryservicepb.Query_StreamExecuteServer) (err error) {
 	defer q.server.HandlePanic(&err)
+	q.negotiateStreamCompression(stream.Context())
 	ctx := callerid.NewContext(callinfo.GRPCCallInfo(stream.Context()),
 		request.EffectiveCallerId,
 		request.ImmediateCallerId,
@@ -263,6 +331,7 @@ func (q *query) BeginExecute(ctx context.Context, request *querypb.BeginExecuteR
 // BeginStreamExecute is part of the queryservice.QueryServer interface
 func (q *query) BeginStreamExecute(request *querypb.BeginStreamExecuteRequest, stream queryservicepb.Query_BeginStreamExecuteServer) (err error) {
 	defer q.server.HandlePanic(&err)
+	q.negotiateStreamCompression(stream.Context())
 	ctx := callerid.NewContext(callinfo.GRPCCallInfo(stream.Context()),
 		request.EffectiveCallerId,
 		request.ImmediateCallerId,
@@ -333,6 +402,17 @@ func (q *query) VStream(request *binlogdatapb.VStreamRequest, stream queryservic
 		request.EffectiveCallerId,
 		request.ImmediateCallerId,
 	)
+	if vstreamcompression.Supported(request.Compression) {
+		sender, err := vstreamcompression.NewSender(request.Compression, stream.Send)
+		if err != nil {
+			return vterrors.ToGRPC(err)
+		}
+		err = q.server.VStream(ctx, request, sender.Send)
+		if closeErr := sender.Close(); err == nil {
+			err = closeErr
+		}
+		return vterrors.ToGRPC(err)
+	}
 	err = q.server.VStream(ctx, request, func(events []*binlogdatapb.VEvent) error {
 		return stream.Send(&binlogdatapb.VStreamResponse{
 			Events: events,
@@ -392,6 +472,7 @@ func (q *query) ReserveExecute(ctx context.Context, request *querypb.ReserveExec
 // ReserveStreamExecute is part of the queryservice.QueryServer interface
 func (q *query) ReserveStreamExecute(request *querypb.ReserveStreamExecuteRequest, stream queryservicepb.Query_ReserveStreamExecuteServer) (err error) {
 	defer q.server.HandlePanic(&err)
+	q.negotiateStreamCompression(stream.Context())
 	ctx := callerid.NewContext(callinfo.GRPCCallInfo(stream.Context()),
 		request.EffectiveCallerId,
 		request.ImmediateCallerId,
@@ -446,6 +527,7 @@ func (q *query) ReserveBeginExecute(ctx context.Context, request *querypb.Reserv
 // ReserveBeginStreamExecute is part of the queryservice.QueryServer interface
 func (q *query) ReserveBeginStreamExecute(request *querypb.ReserveBeginStreamExecuteRequest, stream queryservicepb.Query_ReserveBeginStreamExecuteServer) (err error) {
 	defer q.server.HandlePanic(&err)
+	q.negotiateStreamCompression(stream.Context())
 	ctx := callerid.NewContext(callinfo.GRPCCallInfo(stream.Context()),
 		request.EffectiveCallerId,
 		request.ImmediateCallerId,
@@ -492,5 +574,9 @@ func (q *query) GetSchema(request *querypb.GetSchemaRequest, stream queryservice
 
 // Register registers the implementation on the provide gRPC Server.
 func Register(s *grpc.Server, server queryservice.QueryService) {
-	queryservicepb.RegisterQueryServer(s, &query{server: server})
+	streamCompressors, err := parseStreamCompression(streamCompression)
+	if err != nil {
+		log.Exitf("--grpc_stream_compression: %v", err)
+	}
+	queryservicepb.RegisterQueryServer(s, &query{server: server, streamCompressors: streamCompressors})
 }
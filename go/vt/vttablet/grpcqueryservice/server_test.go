@@ -0,0 +1,103 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcqueryservice
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/vttablet/grpctabletconn"
+	"vitess.io/vitess/go/vt/vttablet/tabletconntest"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestParseStreamCompression(t *testing.T) {
+	compressors, err := parseStreamCompression("")
+	require.NoError(t, err)
+	assert.Empty(t, compressors)
+
+	compressors, err = parseStreamCompression("zstd, lz4")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"zstd", "lz4"}, compressors)
+
+	_, err = parseStreamCompression("zstd,brotli")
+	assert.EqualError(t, err, `unsupported compressor "brotli", expected one of snappy, lz4, zstd`)
+}
+
+func TestStreamCompression(t *testing.T) {
+	defer func(c string) { streamCompression = c }(streamCompression)
+
+	service := tabletconntest.CreateFakeServer(t)
+	for _, tcase := range []struct {
+		streamCompression string
+		want              string
+	}{{
+		streamCompression: "",
+	}, {
+		streamCompression: "zstd,lz4",
+		want:              "zstd",
+	}, {
+		streamCompression: "lz4",
+		want:              "lz4",
+	}} {
+		t.Run(tcase.streamCompression, func(t *testing.T) {
+			streamCompression = tcase.streamCompression
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			server := grpc.NewServer()
+			Register(server, service)
+			go server.Serve(listener)
+			defer server.Stop()
+
+			conn, err := grpctabletconn.DialTablet(&topodatapb.Tablet{
+				Hostname: listener.Addr().(*net.TCPAddr).IP.String(),
+				PortMap: map[string]int32{
+					"grpc": int32(listener.Addr().(*net.TCPAddr).Port),
+				},
+			}, false)
+			require.NoError(t, err)
+			defer conn.Close(context.Background())
+
+			before := streamCompressions.Counts()
+			ctx := callerid.NewContext(context.Background(), tabletconntest.TestCallerID, tabletconntest.TestVTGateCallerID)
+			var results []*sqltypes.Result
+			err = conn.StreamExecute(ctx, tabletconntest.TestTarget, tabletconntest.StreamExecuteQuery, tabletconntest.StreamExecuteBindVars, 0, 0, tabletconntest.TestExecuteOptions, func(qr *sqltypes.Result) error {
+				results = append(results, qr)
+				return nil
+			})
+			require.NoError(t, err)
+			require.Len(t, results, 2)
+			assert.Equal(t, len(tabletconntest.StreamExecuteQueryResult1.Fields), len(results[0].Fields))
+			assert.Equal(t, tabletconntest.StreamExecuteQueryResult2.Rows, results[1].Rows)
+
+			after := streamCompressions.Counts()
+			if tcase.want == "" {
+				assert.Equal(t, before, after)
+				return
+			}
+			assert.EqualValues(t, 1, after[tcase.want]-before[tcase.want])
+		})
+	}
+}
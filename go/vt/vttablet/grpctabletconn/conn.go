@@ -31,6 +31,7 @@ import (
 	"vitess.io/vitess/go/vt/servenv"
 	"vitess.io/vitess/go/vt/vttablet/queryservice"
 	"vitess.io/vitess/go/vt/vttablet/tabletconn"
+	"vitess.io/vitess/go/vt/vttablet/vstreamcompression"
 
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	querypb "vitess.io/vitess/go/vt/proto/query"
@@ -664,6 +665,7 @@ func (conn *gRPCQueryClient) VStream(ctx context.Context, request *binlogdatapb.
 			Position:          request.Position,
 			Filter:            request.Filter,
 			TableLastPKs:      request.TableLastPKs,
+			Compression:       request.Compression,
 		}
 		stream, err := conn.c.VStream(ctx, req)
 		if err != nil {
@@ -684,7 +686,13 @@ func (conn *gRPCQueryClient) VStream(ctx context.Context, request *binlogdatapb.
 			return nil
 		default:
 		}
-		if err := send(r.Events); err != nil {
+		events := r.Events
+		if len(r.CompressedEvents) > 0 {
+			if events, err = vstreamcompression.Decompress(ctx, request.Compression, r.CompressedEvents); err != nil {
+				return err
+			}
+		}
+		if err := send(events); err != nil {
 			if err == io.EOF {
 				return nil
 			}
@@ -17,17 +17,26 @@ limitations under the License.
 package grpctabletconn
 
 import (
+	"context"
+	"fmt"
 	"io"
 	"net"
 	"os"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/grpcclient"
 	"vitess.io/vitess/go/vt/servenv"
 	"vitess.io/vitess/go/vt/vttablet/grpcqueryservice"
+	"vitess.io/vitess/go/vt/vttablet/queryservice"
 	"vitess.io/vitess/go/vt/vttablet/tabletconntest"
+	"vitess.io/vitess/go/vt/vttablet/vstreamcompression"
 
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
@@ -62,6 +71,76 @@ func TestGRPCTabletConn(t *testing.T) {
 	}, service, nil)
 }
 
+type fakeVStreamService struct {
+	queryservice.QueryService
+	request *binlogdatapb.VStreamRequest
+}
+
+func (f *fakeVStreamService) VStream(ctx context.Context, request *binlogdatapb.VStreamRequest, send func([]*binlogdatapb.VEvent) error) error {
+	f.request = request
+	for i := 0; i < 3; i++ {
+		err := send([]*binlogdatapb.VEvent{
+			{Type: binlogdatapb.VEventType_BEGIN},
+			{Type: binlogdatapb.VEventType_ROW, RowEvent: &binlogdatapb.RowEvent{TableName: "customer"}},
+			{Type: binlogdatapb.VEventType_COMMIT},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeVStreamService) HandlePanic(err *error) {
+	if x := recover(); x != nil {
+		*err = fmt.Errorf("caught test panic: %v", x)
+	}
+}
+
+// This test makes sure the VStream events are compressed when requested.
+func TestGRPCTabletConnVStreamCompression(t *testing.T) {
+	service := &fakeVStreamService{}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	grpcqueryservice.Register(server, service)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := DialTablet(&topodatapb.Tablet{
+		Alias:    tabletconntest.TestAlias,
+		Hostname: listener.Addr().(*net.TCPAddr).IP.String(),
+		PortMap: map[string]int32{
+			"grpc": int32(listener.Addr().(*net.TCPAddr).Port),
+		},
+	}, grpcclient.FailFast(false))
+	require.NoError(t, err)
+	defer conn.Close(context.Background())
+
+	for _, compression := range []string{"", vstreamcompression.Zstd} {
+		st := &vstreamcompression.Stats{
+			CompressedBytes:   stats.NewCounter("", ""),
+			UncompressedBytes: stats.NewCounter("", ""),
+		}
+		ctx := vstreamcompression.NewContext(context.Background(), st)
+		var events []*binlogdatapb.VEvent
+		err := conn.VStream(ctx, &binlogdatapb.VStreamRequest{Compression: compression}, func(evs []*binlogdatapb.VEvent) error {
+			events = append(events, evs...)
+			return nil
+		})
+		require.NoError(t, err, compression)
+		assert.Equal(t, compression, service.request.Compression)
+		require.Len(t, events, 9, compression)
+		assert.Equal(t, "customer", events[7].RowEvent.TableName)
+		if compression == "" {
+			assert.Zero(t, st.CompressedBytes.Get())
+		} else {
+			assert.NotZero(t, st.CompressedBytes.Get())
+			assert.NotZero(t, st.UncompressedBytes.Get())
+		}
+	}
+}
+
 // This test makes sure the go rpc client auth works
 func TestGRPCTabletAuthConn(t *testing.T) {
 	// fake service
@@ -287,6 +287,46 @@ func (client *Client) GetPermissions(ctx context.Context, tablet *topodatapb.Tab
 	return response.Permissions, nil
 }
 
+// GetPlanCache is part of the tmclient.TabletManagerClient interface.
+func (client *Client) GetPlanCache(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.GetPlanCacheRequest) (*tabletmanagerdatapb.GetPlanCacheResponse, error) {
+	c, closer, err := client.dialer.dial(ctx, tablet)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	return c.GetPlanCache(ctx, request)
+}
+
+// GetTableStats is part of the tmclient.TabletManagerClient interface.
+func (client *Client) GetTableStats(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.GetTableStatsRequest) (*tabletmanagerdatapb.GetTableStatsResponse, error) {
+	c, closer, err := client.dialer.dial(ctx, tablet)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	return c.GetTableStats(ctx, request)
+}
+
+// GetUnresolvedTransactions is part of the tmclient.TabletManagerClient interface.
+func (client *Client) GetUnresolvedTransactions(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.GetUnresolvedTransactionsRequest) (*tabletmanagerdatapb.GetUnresolvedTransactionsResponse, error) {
+	c, closer, err := client.dialer.dial(ctx, tablet)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	return c.GetUnresolvedTransactions(ctx, request)
+}
+
+// ReadTransaction is part of the tmclient.TabletManagerClient interface.
+func (client *Client) ReadTransaction(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.ReadTransactionRequest) (*tabletmanagerdatapb.ReadTransactionResponse, error) {
+	c, closer, err := client.dialer.dial(ctx, tablet)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	return c.ReadTransaction(ctx, request)
+}
+
 //
 // Various read-write methods
 //
@@ -362,6 +402,26 @@ func (client *Client) ReloadSchema(ctx context.Context, tablet *topodatapb.Table
 	return err
 }
 
+// InvalidatePlanCache is part of the tmclient.TabletManagerClient interface.
+func (client *Client) InvalidatePlanCache(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.InvalidatePlanCacheRequest) (*tabletmanagerdatapb.InvalidatePlanCacheResponse, error) {
+	c, closer, err := client.dialer.dial(ctx, tablet)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	return c.InvalidatePlanCache(ctx, request)
+}
+
+// ConcludeTransaction is part of the tmclient.TabletManagerClient interface.
+func (client *Client) ConcludeTransaction(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.ConcludeTransactionRequest) (*tabletmanagerdatapb.ConcludeTransactionResponse, error) {
+	c, closer, err := client.dialer.dial(ctx, tablet)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	return c.ConcludeTransaction(ctx, request)
+}
+
 func (client *Client) ResetSequences(ctx context.Context, tablet *topodatapb.Tablet, tables []string) error {
 	c, closer, err := client.dialer.dial(ctx, tablet)
 	if err != nil {
@@ -1020,17 +1080,17 @@ type restoreFromBackupStreamAdapter struct {
 	closer io.Closer
 }
 
-func (e *restoreFromBackupStreamAdapter) Recv() (*logutilpb.Event, error) {
+func (e *restoreFromBackupStreamAdapter) Recv() (*tabletmanagerdatapb.RestoreFromBackupResponse, error) {
 	br, err := e.stream.Recv()
 	if err != nil {
 		e.closer.Close()
 		return nil, err
 	}
-	return br.Event, nil
+	return br, nil
 }
 
 // RestoreFromBackup is part of the tmclient.TabletManagerClient interface.
-func (client *Client) RestoreFromBackup(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.RestoreFromBackupRequest) (logutil.EventStream, error) {
+func (client *Client) RestoreFromBackup(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.RestoreFromBackupRequest) (tmclient.RestoreFromBackupStream, error) {
 	c, closer, err := client.dialer.dial(ctx, tablet)
 	if err != nil {
 		return nil, err
@@ -1047,6 +1107,38 @@ func (client *Client) RestoreFromBackup(ctx context.Context, tablet *topodatapb.
 	}, nil
 }
 
+type upgradeMysqlStreamAdapter struct {
+	stream tabletmanagerservicepb.TabletManager_UpgradeMysqlClient
+	closer io.Closer
+}
+
+func (e *upgradeMysqlStreamAdapter) Recv() (*logutilpb.Event, error) {
+	ur, err := e.stream.Recv()
+	if err != nil {
+		e.closer.Close()
+		return nil, err
+	}
+	return ur.Event, nil
+}
+
+// UpgradeMysql is part of the tmclient.TabletManagerClient interface.
+func (client *Client) UpgradeMysql(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.UpgradeMysqlRequest) (logutil.EventStream, error) {
+	c, closer, err := client.dialer.dial(ctx, tablet)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := c.UpgradeMysql(ctx, req)
+	if err != nil {
+		closer.Close()
+		return nil, err
+	}
+	return &upgradeMysqlStreamAdapter{
+		stream: stream,
+		closer: closer,
+	}, nil
+}
+
 // Close is part of the tmclient.TabletManagerClient interface.
 func (client *Client) Close() {
 	client.dialer.Close()
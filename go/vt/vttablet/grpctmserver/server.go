@@ -18,6 +18,7 @@ package grpctmserver
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"google.golang.org/grpc"
@@ -98,6 +99,50 @@ func (s *server) GetPermissions(ctx context.Context, request *tabletmanagerdatap
 	return response, err
 }
 
+func (s *server) GetPlanCache(ctx context.Context, request *tabletmanagerdatapb.GetPlanCacheRequest) (response *tabletmanagerdatapb.GetPlanCacheResponse, err error) {
+	defer s.tm.HandleRPCPanic(ctx, "GetPlanCache", request, response, false /*verbose*/, &err)
+	ctx = callinfo.GRPCCallInfo(ctx)
+	response = &tabletmanagerdatapb.GetPlanCacheResponse{}
+	entries, err := s.tm.GetPlanCache(ctx, request.Tables, request.Fingerprints)
+	if err == nil {
+		response.Entries = entries
+	}
+	return response, err
+}
+
+func (s *server) GetTableStats(ctx context.Context, request *tabletmanagerdatapb.GetTableStatsRequest) (response *tabletmanagerdatapb.GetTableStatsResponse, err error) {
+	defer s.tm.HandleRPCPanic(ctx, "GetTableStats", request, response, false /*verbose*/, &err)
+	ctx = callinfo.GRPCCallInfo(ctx)
+	response = &tabletmanagerdatapb.GetTableStatsResponse{}
+	tableStats, err := s.tm.GetTableStats(ctx, request.Tables)
+	if err == nil {
+		response.TableStats = tableStats
+	}
+	return response, err
+}
+
+func (s *server) GetUnresolvedTransactions(ctx context.Context, request *tabletmanagerdatapb.GetUnresolvedTransactionsRequest) (response *tabletmanagerdatapb.GetUnresolvedTransactionsResponse, err error) {
+	defer s.tm.HandleRPCPanic(ctx, "GetUnresolvedTransactions", request, response, false /*verbose*/, &err)
+	ctx = callinfo.GRPCCallInfo(ctx)
+	response = &tabletmanagerdatapb.GetUnresolvedTransactionsResponse{}
+	transactions, err := s.tm.GetUnresolvedTransactions(ctx, time.Duration(request.AbandonAge)*time.Second)
+	if err == nil {
+		response.Transactions = transactions
+	}
+	return response, err
+}
+
+func (s *server) ReadTransaction(ctx context.Context, request *tabletmanagerdatapb.ReadTransactionRequest) (response *tabletmanagerdatapb.ReadTransactionResponse, err error) {
+	defer s.tm.HandleRPCPanic(ctx, "ReadTransaction", request, response, false /*verbose*/, &err)
+	ctx = callinfo.GRPCCallInfo(ctx)
+	response = &tabletmanagerdatapb.ReadTransactionResponse{}
+	transaction, err := s.tm.ReadTransaction(ctx, request.Dtid)
+	if err == nil {
+		response.Transaction = transaction
+	}
+	return response, err
+}
+
 //
 // Various read-write methods
 //
@@ -145,6 +190,25 @@ func (s *server) ReloadSchema(ctx context.Context, request *tabletmanagerdatapb.
 	return response, s.tm.ReloadSchema(ctx, request.WaitPosition)
 }
 
+func (s *server) InvalidatePlanCache(ctx context.Context, request *tabletmanagerdatapb.InvalidatePlanCacheRequest) (response *tabletmanagerdatapb.InvalidatePlanCacheResponse, err error) {
+	defer s.tm.HandleRPCPanic(ctx, "InvalidatePlanCache", request, response, true /*verbose*/, &err)
+	ctx = callinfo.GRPCCallInfo(ctx)
+	response = &tabletmanagerdatapb.InvalidatePlanCacheResponse{}
+	invalidated, err := s.tm.InvalidatePlanCache(ctx, request.Tables, request.Fingerprints, request.All)
+	if err == nil {
+		response.Invalidated = uint64(invalidated)
+	}
+	return response, err
+}
+
+func (s *server) ConcludeTransaction(ctx context.Context, request *tabletmanagerdatapb.ConcludeTransactionRequest) (response *tabletmanagerdatapb.ConcludeTransactionResponse, err error) {
+	defer s.tm.HandleRPCPanic(ctx, "ConcludeTransaction", request, response, true /*verbose*/, &err)
+	ctx = callinfo.GRPCCallInfo(ctx)
+	response = &tabletmanagerdatapb.ConcludeTransactionResponse{}
+	err = s.tm.ConcludeTransaction(ctx, request.Dtid, request.Mm, request.Commit)
+	return response, err
+}
+
 func (s *server) PreflightSchema(ctx context.Context, request *tabletmanagerdatapb.PreflightSchemaRequest) (response *tabletmanagerdatapb.PreflightSchemaResponse, err error) {
 	defer s.tm.HandleRPCPanic(ctx, "PreflightSchema", request, response, true /*verbose*/, &err)
 	ctx = callinfo.GRPCCallInfo(ctx)
@@ -531,17 +595,49 @@ func (s *server) RestoreFromBackup(request *tabletmanagerdatapb.RestoreFromBacku
 	defer s.tm.HandleRPCPanic(ctx, "RestoreFromBackup", request, nil, true /*verbose*/, &err)
 	ctx = callinfo.GRPCCallInfo(ctx)
 
+	// The files are restored concurrently, and the progress is sent from
+	// its own goroutine, so the sends to the stream are serialized.
+	var sendMu sync.Mutex
+	send := func(response *tabletmanagerdatapb.RestoreFromBackupResponse) {
+		sendMu.Lock()
+		defer sendMu.Unlock()
+		// If the client disconnects, we will just fail
+		// to send the log events and progress, but won't
+		// interrupt the restore.
+		stream.Send(response)
+	}
+
+	// create a logger, send the result back to the caller
+	logger := logutil.NewCallbackLogger(func(e *logutilpb.Event) {
+		send(&tabletmanagerdatapb.RestoreFromBackupResponse{
+			Event: e,
+		})
+	})
+	progress := func(p *tabletmanagerdatapb.RestoreProgress) {
+		send(&tabletmanagerdatapb.RestoreFromBackupResponse{
+			Progress: p,
+		})
+	}
+
+	return s.tm.RestoreFromBackup(ctx, logger, request, progress)
+}
+
+func (s *server) UpgradeMysql(request *tabletmanagerdatapb.UpgradeMysqlRequest, stream tabletmanagerservicepb.TabletManager_UpgradeMysqlServer) (err error) {
+	ctx := stream.Context()
+	defer s.tm.HandleRPCPanic(ctx, "UpgradeMysql", request, nil, true /*verbose*/, &err)
+	ctx = callinfo.GRPCCallInfo(ctx)
+
 	// create a logger, send the result back to the caller
 	logger := logutil.NewCallbackLogger(func(e *logutilpb.Event) {
 		// If the client disconnects, we will just fail
 		// to send the log events, but won't interrupt
-		// the backup.
-		stream.Send(&tabletmanagerdatapb.RestoreFromBackupResponse{
+		// the upgrade.
+		stream.Send(&tabletmanagerdatapb.UpgradeMysqlResponse{
 			Event: e,
 		})
 	})
 
-	return s.tm.RestoreFromBackup(ctx, logger, request)
+	return s.tm.UpgradeMysql(ctx, logger, request)
 }
 
 func (s *server) CheckThrottler(ctx context.Context, request *tabletmanagerdatapb.CheckThrottlerRequest) (response *tabletmanagerdatapb.CheckThrottlerResponse, err error) {
@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package onlineddl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/textutil"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/schema"
+)
+
+// migrationRowsDependencies maps the migration rows, which have a `depends_on` column, to the UUIDs of
+// the migrations they depend on, as given by `--depends-on`
+func migrationRowsDependencies(rows []sqltypes.RowNamedValues) map[string][]string {
+	dependsOn := make(map[string][]string, len(rows))
+	for _, row := range rows {
+		if uuids := textutil.SplitDelimitedList(row["depends_on"].ToString()); len(uuids) > 0 {
+			dependsOn[row["migration_uuid"].ToString()] = uuids
+		}
+	}
+	return dependsOn
+}
+
+// migrationDependencyCycle returns the cycle of dependencies which leads from the given migration back
+// to itself, or nil if there is none. dependsOn maps the queued migrations to the migrations they depend
+// on. Migrations past the queued state do not wait for dependencies, and cannot be part of a cycle.
+func migrationDependencyCycle(uuid string, dependsOn map[string][]string) []string {
+	visited := map[string]bool{}
+	var visit func(current string, path []string) []string
+	visit = func(current string, path []string) []string {
+		for _, dependencyUUID := range dependsOn[current] {
+			if dependencyUUID == uuid {
+				return append(path, dependencyUUID)
+			}
+			if visited[dependencyUUID] {
+				continue
+			}
+			visited[dependencyUUID] = true
+			if cycle := visit(dependencyUUID, append(path, dependencyUUID)); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+	return visit(uuid, []string{uuid})
+}
+
+// reviewMigrationDependencies checks the migrations which the given queued migration depends on. It
+// returns true when they all completed successfully, and the migration may launch. It fails the
+// migration when one of them does not exist, failed or was cancelled, or when the dependencies are
+// circular, since the migration could then never launch.
+func (e *Executor) reviewMigrationDependencies(ctx context.Context, uuid string, dependsOn map[string][]string) (satisfied bool, err error) {
+	failMigration := func(err error) (bool, error) {
+		log.Infof("Executor.reviewMigrationDependencies: failing migration %s: %v", uuid, err)
+		_ = e.failMigration(ctx, &schema.OnlineDDL{UUID: uuid}, err)
+		return false, nil
+	}
+	if cycle := migrationDependencyCycle(uuid, dependsOn); cycle != nil {
+		return failMigration(fmt.Errorf("circular migration dependencies: %s", strings.Join(cycle, " -> ")))
+	}
+	for _, dependencyUUID := range dependsOn[uuid] {
+		dependency, _, err := e.readMigration(ctx, dependencyUUID)
+		if err == ErrMigrationNotFound {
+			return failMigration(fmt.Errorf("depends on migration %s, which does not exist", dependencyUUID))
+		}
+		if err != nil {
+			return false, err
+		}
+		switch dependency.Status {
+		case schema.OnlineDDLStatusComplete:
+		case schema.OnlineDDLStatusFailed, schema.OnlineDDLStatusCancelled:
+			return failMigration(fmt.Errorf("depends on migration %s, which is %s", dependencyUUID, dependency.Status))
+		default:
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package onlineddl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"vitess.io/vitess/go/sqltypes"
+)
+
+func TestMigrationRowsDependencies(t *testing.T) {
+	rows := []sqltypes.RowNamedValues{
+		{"migration_uuid": sqltypes.NewVarChar("a"), "depends_on": sqltypes.NewVarChar("")},
+		{"migration_uuid": sqltypes.NewVarChar("b"), "depends_on": sqltypes.NewVarChar("a")},
+		{"migration_uuid": sqltypes.NewVarChar("c"), "depends_on": sqltypes.NewVarChar("a,b")},
+	}
+	assert.Equal(t, map[string][]string{
+		"b": {"a"},
+		"c": {"a", "b"},
+	}, migrationRowsDependencies(rows))
+}
+
+func TestMigrationDependencyCycle(t *testing.T) {
+	tt := []struct {
+		name        string
+		uuid        string
+		dependsOn   map[string][]string
+		expectCycle []string
+	}{
+		{
+			name: "no dependencies",
+			uuid: "a",
+		},
+		{
+			name:      "chain",
+			uuid:      "c",
+			dependsOn: map[string][]string{"c": {"b"}, "b": {"a"}},
+		},
+		{
+			name:      "diamond",
+			uuid:      "d",
+			dependsOn: map[string][]string{"d": {"b", "c"}, "b": {"a"}, "c": {"a"}},
+		},
+		{
+			name:        "self",
+			uuid:        "a",
+			dependsOn:   map[string][]string{"a": {"a"}},
+			expectCycle: []string{"a", "a"},
+		},
+		{
+			name:        "cycle",
+			uuid:        "a",
+			dependsOn:   map[string][]string{"a": {"x", "b"}, "b": {"c"}, "c": {"a"}},
+			expectCycle: []string{"a", "b", "c", "a"},
+		},
+		{
+			name:      "cycle not involving the migration",
+			uuid:      "a",
+			dependsOn: map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"b"}},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectCycle, migrationDependencyCycle(tc.uuid, tc.dependsOn))
+		})
+	}
+}
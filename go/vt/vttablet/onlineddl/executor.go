@@ -90,6 +90,8 @@ var (
 	retainOnlineDDLTables   = 24 * time.Hour
 	defaultCutOverThreshold = 10 * time.Second
 	maxConcurrentOnlineDDLs = 256
+
+	revertibleMySQLMigrationMaxTableSize int64 = 100 * 1024 * 1024
 )
 
 func init() {
@@ -103,6 +105,7 @@ func registerOnlineDDLFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&migrationCheckInterval, "migration_check_interval", migrationCheckInterval, "Interval between migration checks")
 	fs.DurationVar(&retainOnlineDDLTables, "retain_online_ddl_tables", retainOnlineDDLTables, "How long should vttablet keep an old migrated table before purging it")
 	fs.IntVar(&maxConcurrentOnlineDDLs, "max_concurrent_online_ddl", maxConcurrentOnlineDDLs, "Maximum number of online DDL changes that may run concurrently")
+	fs.Int64Var(&revertibleMySQLMigrationMaxTableSize, "revertible-mysql-migration-max-table-size", revertibleMySQLMigrationMaxTableSize, "Maximum estimated size, in bytes, of a table migrated with the 'mysql --revertible' ddl strategy. Writes to the table are blocked while such a migration copies it onto a shadow table, which the migration's REVERT uses")
 }
 
 var migrationNextCheckIntervals = []time.Duration{1 * time.Second, 5 * time.Second, 10 * time.Second, 20 * time.Second}
@@ -192,6 +195,10 @@ type Executor struct {
 	tickReentranceFlag            int64
 	reviewedRunningMigrationsFlag bool
 
+	// vreplicationRowsCopiedSamples has the last sample of rows copied by each running vreplication migration,
+	// from which the row-copy throughput is computed.
+	vreplicationRowsCopiedSamples map[string]*rowsCopiedSample
+
 	ticks  *timer.Timer
 	isOpen int64
 
@@ -329,6 +336,7 @@ func (e *Executor) Open() error {
 		return true
 	})
 	e.vreplicationLastError = make(map[string]*vterrors.LastError)
+	e.vreplicationRowsCopiedSamples = make(map[string]*rowsCopiedSample)
 
 	if sidecar.GetName() != sidecar.DefaultName {
 		e.execQuery = e.executeQueryWithSidecarDBReplacement
@@ -398,8 +406,10 @@ func (e *Executor) countOwnedRunningMigrations() (count int) {
 // allowConcurrentMigration checks if the given migration is allowed to run concurrently.
 // First, the migration itself must declare --allow-concurrent. But then, there's also some
 // restrictions on which migrations exactly are allowed such concurrency.
+// A --coordinated-cut-over migration implies --allow-concurrent, since the migrations of its batch
+// must all be running to be ready to cut over.
 func (e *Executor) allowConcurrentMigration(onlineDDL *schema.OnlineDDL) (action sqlparser.DDLAction, allowConcurrent bool) {
-	if !onlineDDL.StrategySetting().IsAllowConcurrent() {
+	if !onlineDDL.StrategySetting().IsAllowConcurrent() && !onlineDDL.StrategySetting().IsCoordinatedCutOverFlag() {
 		return action, false
 	}
 
@@ -677,43 +687,15 @@ func (e *Executor) doesConnectionInfoMatch(ctx context.Context, connID int64, su
 	return len(rs.Rows) == 1, nil
 }
 
-// tableParticipatesInForeignKeyRelationship checks if a given table is either a parent or a child in at least one foreign key constraint
-func (e *Executor) tableParticipatesInForeignKeyRelationship(ctx context.Context, schema string, table string) (bool, error) {
-	for _, fkQuery := range []string{selSelectCountFKParentConstraints, selSelectCountFKChildConstraints} {
-		query, err := sqlparser.ParseAndBind(fkQuery,
-			sqltypes.StringBindVariable(schema),
-			sqltypes.StringBindVariable(table),
-		)
-		if err != nil {
-			return false, err
-		}
-		r, err := e.execQuery(ctx, query)
-		if err != nil {
-			return false, err
-		}
-		row := r.Named().Row()
-		if row == nil {
-			return false, vterrors.Errorf(vtrpcpb.Code_UNKNOWN, "unexpected result from INFORMATION_SCHEMA.KEY_COLUMN_USAGE query: %s", query)
-		}
-		countFKConstraints := row.AsInt64("num_fk_constraints", 0)
-		if countFKConstraints > 0 {
-			return true, nil
-		}
-	}
-	return false, nil
-}
-
 // validateTableForAlterAction checks whether a table is good to undergo a ALTER operation. It returns detailed error if not.
+// A table which participates in a foreign key relationship is good to go, as long as the cut-over can
+// safely swap it; see validateForeignKeyRelations.
 func (e *Executor) validateTableForAlterAction(ctx context.Context, onlineDDL *schema.OnlineDDL) (err error) {
-	if !onlineDDL.StrategySetting().IsAllowForeignKeysFlag() {
-		// Validate table does not participate in foreign key relationship:
-		participates, err := e.tableParticipatesInForeignKeyRelationship(ctx, onlineDDL.Schema, onlineDDL.Table)
-		if err != nil {
-			return vterrors.Wrapf(err, "error while attempting to validate whether table %s participates in FOREIGN KEY constraint", onlineDDL.Table)
-		}
-		if participates {
-			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "table %s participates in a FOREIGN KEY constraint and FOREIGN KEY constraints are not supported in Online DDL unless the *experimental and unsafe* --unsafe-allow-foreign-keys strategy flag is specified", onlineDDL.Table)
-		}
+	if onlineDDL.StrategySetting().IsAllowForeignKeysFlag() {
+		return nil
+	}
+	if _, err := e.validateTableForeignKeys(ctx, onlineDDL.Table); err != nil {
+		return vterrors.Wrapf(err, "table %s cannot be migrated unless the *experimental and unsafe* --unsafe-allow-foreign-keys strategy flag is specified", onlineDDL.Table)
 	}
 	return nil
 }
@@ -786,6 +768,14 @@ func (e *Executor) cutOverVReplMigration(ctx context.Context, s *VReplStream) er
 	isVreplicationTestSuite := onlineDDL.StrategySetting().IsVreplicationTestSuite()
 	e.updateMigrationStage(ctx, onlineDDL.UUID, "starting cut-over")
 
+	var foreignKeyRelations []*foreignKeyRelation
+	if !onlineDDL.StrategySetting().IsAllowForeignKeysFlag() {
+		// Foreign keys may have changed since the migration started. Validate again, while nothing has happened yet.
+		if foreignKeyRelations, err = e.validateTableForeignKeys(ctx, onlineDDL.Table); err != nil {
+			return err
+		}
+	}
+
 	var sentryTableName string
 
 	migrationCutOverThreshold := getMigrationCutOverThreshold(onlineDDL)
@@ -874,6 +864,14 @@ func (e *Executor) cutOverVReplMigration(ctx context.Context, s *VReplStream) er
 			renameConn.Kill("premature exit while renaming tables", 0)
 		}
 	}()
+	if isForeignKeyParent(onlineDDL.Table, foreignKeyRelations) {
+		// The children of the table must keep referencing the table name, and thus the shadow table
+		// once swapped in. Without this, the RENAME would have them follow the original table.
+		if _, err := renameConn.Exec(ctx, sqlEnablePreserveForeignKey, 1, false); err != nil {
+			return err
+		}
+		defer renameConn.Exec(ctx, sqlDisablePreserveForeignKey, 1, false)
+	}
 	renameQuery := sqlparser.BuildParsedQuery(sqlSwapTables, onlineDDL.Table, sentryTableName, vreplTable, onlineDDL.Table, sentryTableName, vreplTable)
 
 	waitForRenameProcess := func() error {
@@ -1063,6 +1061,13 @@ func (e *Executor) cutOverVReplMigration(ctx context.Context, s *VReplStream) er
 	e.updateMigrationStage(ctx, onlineDDL.UUID, "cut-over complete")
 	e.ownedRunningMigrations.Delete(onlineDDL.UUID)
 
+	if constraintNames := childForeignKeyNames(onlineDDL.Table, foreignKeyRelations); len(constraintNames) > 0 && !isVreplicationTestSuite {
+		// The original table is now vreplTable
+		if err := e.dropSwappedTableForeignKeys(ctx, onlineDDL.UUID, vreplTable, constraintNames); err != nil {
+			log.Errorf("cutOverVReplMigration %v: error dropping foreign keys of swapped table %s: %v", s.workflow, vreplTable, err)
+		}
+	}
+
 	go func() {
 		// Tables are swapped! Let's take the opportunity to ReloadSchema now
 		// We do this in a goroutine because it might take time on a schema with thousands of tables, and we don't want to delay
@@ -1158,18 +1163,14 @@ func (e *Executor) newConstraintName(onlineDDL *schema.OnlineDDL, constraintType
 }
 
 // validateAndEditCreateTableStatement inspects the CreateTable AST and does the following:
-// - extra validation (no FKs for now...)
-// - generate new and unique names for all constraints (CHECK and FK; yes, why not handle FK names; even as we don't support FKs today, we may in the future)
+// - generate new and unique names for all constraints (CHECK and FK). The foreign keys of the table
+// itself are validated by validateTableForAlterAction
 func (e *Executor) validateAndEditCreateTableStatement(ctx context.Context, onlineDDL *schema.OnlineDDL, createTable *sqlparser.CreateTable) (constraintMap map[string]string, err error) {
 	constraintMap = map[string]string{}
 	hashExists := map[string]bool{}
 
 	validateWalk := func(node sqlparser.SQLNode) (kontinue bool, err error) {
 		switch node := node.(type) {
-		case *sqlparser.ForeignKeyDefinition:
-			if !onlineDDL.StrategySetting().IsAllowForeignKeysFlag() {
-				return false, schema.ErrForeignKeyFound
-			}
 		case *sqlparser.ConstraintDefinition:
 			oldName := node.Name.String()
 			newName := e.newConstraintName(onlineDDL, GetConstraintType(node.Details), hashExists, sqlparser.CanonicalString(node.Details), oldName)
@@ -1192,8 +1193,8 @@ func (e *Executor) validateAndEditAlterTableStatement(ctx context.Context, onlin
 	validateWalk := func(node sqlparser.SQLNode) (kontinue bool, err error) {
 		switch node := node.(type) {
 		case *sqlparser.DropKey:
-			if node.Type == sqlparser.CheckKeyType {
-				// drop a check constraint
+			if node.Type == sqlparser.CheckKeyType || node.Type == sqlparser.ForeignKeyType {
+				// drop a check or a foreign key constraint
 				mappedName, ok := constraintMap[node.Name.String()]
 				if !ok {
 					return false, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "Found DROP CONSTRAINT: %v, but could not find constraint name in map", sqlparser.CanonicalString(node))
@@ -2177,7 +2178,9 @@ func (e *Executor) UnthrottleAllMigrations(ctx context.Context) (result *sqltype
 // scheduleNextMigration attemps to schedule a single migration to run next.
 // possibly there are migrations to run.
 // The effect of this function is to move a migration from 'queued' state to 'ready' state, is all.
-func (e *Executor) scheduleNextMigration(ctx context.Context) error {
+// Migrations of a higher priority class are scheduled first. Migrations with postpone_launch are
+// launched while the launch window of the scheduler configuration is open.
+func (e *Executor) scheduleNextMigration(ctx context.Context, schedulerConfig *topodatapb.OnlineDDLSchedulerConfig) error {
 	e.migrationMutex.Lock()
 	defer e.migrationMutex.Unlock()
 
@@ -2187,13 +2190,40 @@ func (e *Executor) scheduleNextMigration(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	for _, row := range r.Named().Rows {
+	rows := r.Named().Rows
+	sortMigrationRowsByPriority(schedulerConfig, rows)
+	launchWindowOpen := isLaunchWindowOpen(schedulerConfig, time.Now())
+	dependsOn := migrationRowsDependencies(rows)
+	for _, row := range rows {
 		uuid := row["migration_uuid"].ToString()
+		if len(dependsOn[uuid]) > 0 {
+			satisfied, err := e.reviewMigrationDependencies(ctx, uuid, dependsOn)
+			if err != nil {
+				return err
+			}
+			if !satisfied {
+				// The migration waits for the migrations it depends on to complete, or has just failed
+				continue
+			}
+		}
 		postponeLaunch := row.AsBool("postpone_launch", false)
 		postponeCompletion := row.AsBool("postpone_completion", false)
 		readyToComplete := row.AsBool("ready_to_complete", false)
 		isImmediateOperation := row.AsBool("is_immediate_operation", false)
 
+		if postponeLaunch && launchWindowOpen {
+			query, err := sqlparser.ParseAndBind(sqlUpdateLaunchMigration,
+				sqltypes.StringBindVariable(uuid),
+			)
+			if err != nil {
+				return err
+			}
+			if _, err := e.execQuery(ctx, query); err != nil {
+				return err
+			}
+			log.Infof("Executor.scheduleNextMigration: launch window is open; migration %s marked as unpostponed", uuid)
+			postponeLaunch = false
+		}
 		if postponeLaunch {
 			// We don't even look into this migration until its postpone_launch flag is cleared
 			continue
@@ -2372,6 +2402,13 @@ func (e *Executor) reviewQueuedMigrations(ctx context.Context) error {
 				e.failMigration(ctx, onlineDDL, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "--allow-zero-in-date not supported in 'mysql' strategy"))
 			}
 		}
+		if onlineDDL.StrategySetting().IsCoordinatedCutOverFlag() {
+			switch onlineDDL.Strategy {
+			case schema.DDLStrategyOnline, schema.DDLStrategyVitess:
+			default:
+				e.failMigration(ctx, onlineDDL, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "--coordinated-cut-over not supported in '%s' strategy", onlineDDL.Strategy))
+			}
+		}
 
 		// The review is complete. We've backfilled details on the migration row. We mark
 		// the migration as having been reviewed. The function scheduleNextMigration() will then
@@ -2392,8 +2429,19 @@ func (e *Executor) validateMigrationRevertible(ctx context.Context, revertMigrat
 	}
 	switch action {
 	case sqlparser.AlterDDLAction:
-		if revertMigration.Strategy != schema.DDLStrategyOnline && revertMigration.Strategy != schema.DDLStrategyVitess {
-			return fmt.Errorf("can only revert a %s strategy migration. Migration %s has %s strategy", schema.DDLStrategyOnline, revertMigration.UUID, revertMigration.Strategy)
+		switch {
+		case revertMigration.Strategy == schema.DDLStrategyOnline, revertMigration.Strategy == schema.DDLStrategyVitess:
+		case revertMigration.Strategy == schema.DDLStrategyMySQL && revertMigration.StrategySetting().IsRevertibleFlag():
+			// The migration captured a shadow table, which the revert uses just as it would a vitess migration's
+		default:
+			return fmt.Errorf("can only revert a %s strategy migration, or a %s --revertible migration. Migration %s has %s strategy", schema.DDLStrategyOnline, schema.DDLStrategyMySQL, revertMigration.UUID, revertMigration.Strategy)
+		}
+		_, row, err := e.readMigration(ctx, revertMigration.UUID)
+		if err != nil {
+			return err
+		}
+		if strings.Contains(row.AsString("revertible_notes", ""), droppedForeignKeysRevertibleNote) {
+			return fmt.Errorf("cannot revert migration %s: its cut-over dropped the foreign keys of the original table %s", revertMigration.UUID, revertMigration.Table)
 		}
 	case sqlparser.RevertDDLAction:
 	case sqlparser.CreateDDLAction:
@@ -3043,6 +3091,12 @@ func (e *Executor) executeAlterDDLActionMigration(ctx context.Context, onlineDDL
 			return failMigration(err)
 		}
 	case schema.DDLStrategyMySQL:
+		if onlineDDL.StrategySetting().IsRevertibleFlag() {
+			if err := e.executeDirectlyWithShadowCapture(ctx, onlineDDL); err != nil {
+				return failMigration(err)
+			}
+			return nil
+		}
 		if _, err := e.executeDirectly(ctx, onlineDDL); err != nil {
 			return failMigration(err)
 		}
@@ -3098,22 +3152,24 @@ func (e *Executor) executeMigration(ctx context.Context, onlineDDL *schema.Onlin
 			// This DROP is declarative, meaning it may:
 			// - actually DROP a table, if that table exists, or
 			// - Implicitly do nothing, if the table does not exist
-			{
-				// Sanity: reject IF NOT EXISTS statements, because they don't make sense (or are ambiguous) in declarative mode
-				ddlStmt, _, err := schema.ParseOnlineDDLStatement(onlineDDL.SQL)
-				if err != nil {
-					return failMigration(err)
-				}
-				if ddlStmt.GetIfExists() {
-					return failMigration(vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "strategy is declarative. IF EXISTS does not work in declarative mode for migration %v", onlineDDL.UUID))
-				}
+			// Sanity: reject IF NOT EXISTS statements, because they don't make sense (or are ambiguous) in declarative mode
+			ddlStmt, _, err := schema.ParseOnlineDDLStatement(onlineDDL.SQL)
+			if err != nil {
+				return failMigration(err)
+			}
+			if ddlStmt.GetIfExists() {
+				return failMigration(vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "strategy is declarative. IF EXISTS does not work in declarative mode for migration %v", onlineDDL.UUID))
 			}
 			exists, err := e.tableExists(ctx, onlineDDL.Table)
 			if err != nil {
 				return failMigration(err)
 			}
 			if exists {
-				// table does exist, so this declarative DROP turns out to really be an actual DROP. No further action is needed here
+				// table does exist, so this declarative DROP turns out to really be an actual DROP.
+				// Dropping a table loses its data, which requires an explicit acknowledgment.
+				if changes := schema.DestructiveChanges(ddlStmt); len(changes) > 0 && !onlineDDL.StrategySetting().IsAllowDestructiveFlag() {
+					return failMigration(vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "strategy is declarative. Destructive changes (%s) require --allow-destructive for migration %v", strings.Join(changes, ", "), onlineDDL.UUID))
+				}
 			} else {
 				// table does not exist. We mark this DROP as implicitly sucessful
 				_ = e.onSchemaMigrationStatus(ctx, onlineDDL.UUID, schema.OnlineDDLStatusComplete, false, progressPctFull, etaSecondsNow, rowsCopiedUnknown, emptyHint)
@@ -3153,6 +3209,9 @@ func (e *Executor) executeMigration(ctx context.Context, onlineDDL *schema.Onlin
 					_ = e.updateMigrationMessage(ctx, onlineDDL.UUID, "no change")
 					return nil
 				}
+				if changes := schema.DestructiveChanges(diff.Statement()); len(changes) > 0 && !onlineDDL.StrategySetting().IsAllowDestructiveFlag() {
+					return failMigration(vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "strategy is declarative. Destructive changes (%s) require --allow-destructive for migration %v", strings.Join(changes, ", "), onlineDDL.UUID))
+				}
 				// alterClause is non empty. We convert this migration into an ALTER
 				if err := e.updateDDLAction(ctx, onlineDDL.UUID, sqlparser.AlterStr); err != nil {
 					return failMigration(err)
@@ -3204,7 +3263,9 @@ func (e *Executor) executeMigration(ctx context.Context, onlineDDL *schema.Onlin
 // - multiple migrations are 'ready' -- we just handle one here
 // Note that per the above breakdown, and due to potential conflicts, it is possible to have one or
 // more 'ready' migration, and still none is executed.
-func (e *Executor) runNextMigration(ctx context.Context) error {
+// Migrations of a higher priority class are looked at first, and the scheduler configuration
+// may lower the number of migrations allowed to run concurrently.
+func (e *Executor) runNextMigration(ctx context.Context, schedulerConfig *topodatapb.OnlineDDLSchedulerConfig) error {
 	e.migrationMutex.Lock()
 	defer e.migrationMutex.Unlock()
 
@@ -3222,6 +3283,7 @@ func (e *Executor) runNextMigration(ctx context.Context) error {
 	// Conflicts are:
 	// - a migration is 'ready' but is not set to run _concurrently_, and there's a running migration that is also non-concurrent
 	// - a migration is 'ready' but there's another migration 'running' on the exact same table
+	// - a migration is 'ready' but there's another migration 'running' on a parent or a child table
 	getNonConflictingMigration := func() (*schema.OnlineDDL, error) {
 		pendingMigrationsUUIDs, err := e.readPendingMigrationsUUIDs(ctx)
 		if err != nil {
@@ -3231,7 +3293,9 @@ func (e *Executor) runNextMigration(ctx context.Context) error {
 		if err != nil {
 			return nil, err
 		}
-		for _, row := range r.Named().Rows {
+		rows := r.Named().Rows
+		sortMigrationRowsByPriority(schedulerConfig, rows)
+		for _, row := range rows {
 			uuid := row["migration_uuid"].ToString()
 			onlineDDL, migrationRow, err := e.readMigration(ctx, uuid)
 			if err != nil {
@@ -3242,7 +3306,12 @@ func (e *Executor) runNextMigration(ctx context.Context) error {
 			if conflictFound, _ := e.isAnyConflictingMigrationRunning(onlineDDL); conflictFound {
 				continue // this migration conflicts with a running one
 			}
-			if e.countOwnedRunningMigrations() >= maxConcurrentOnlineDDLs {
+			if conflictFound, err := e.isAnyForeignKeyRelatedMigrationRunning(ctx, onlineDDL); err != nil {
+				return nil, err
+			} else if conflictFound {
+				continue // a migration runs on a parent or a child of this migration's table
+			}
+			if e.countOwnedRunningMigrations() >= maxConcurrentMigrations(schedulerConfig) {
 				continue // too many running migrations
 			}
 			if isImmediateOperation && onlineDDL.StrategySetting().IsInOrderCompletion() {
@@ -3399,20 +3468,7 @@ func (e *Executor) isVReplMigrationReadyToCutOver(ctx context.Context, onlineDDL
 		// Both time_updated and transaction_timestamp must be in close priximity to each
 		// other and to the time now, otherwise that means we're lagging and it's not a good time
 		// to cut-over
-		durationDiff := func(t1, t2 time.Time) time.Duration {
-			return t1.Sub(t2).Abs()
-		}
-		migrationCutOverThreshold := getMigrationCutOverThreshold(onlineDDL)
-
-		timeNow := time.Now()
-		timeUpdated := time.Unix(s.timeUpdated, 0)
-		if durationDiff(timeNow, timeUpdated) > migrationCutOverThreshold {
-			return false, nil
-		}
-		// Let's look at transaction timestamp. This gets written by any ongoing
-		// writes on the server (whether on this table or any other table)
-		transactionTimestamp := time.Unix(s.transactionTimestamp, 0)
-		if durationDiff(timeNow, transactionTimestamp) > migrationCutOverThreshold {
+		if s.lag(time.Now()) > getMigrationCutOverThreshold(onlineDDL) {
 			return false, nil
 		}
 	}
@@ -3552,6 +3608,8 @@ func (e *Executor) reviewRunningMigrations(ctx context.Context) (countRunnning i
 					_ = e.updateRowsCopied(ctx, uuid, s.rowsCopied)
 					_ = e.updateMigrationProgressByRowsCopied(ctx, uuid, s.rowsCopied)
 					_ = e.updateMigrationETASecondsByProgress(ctx, uuid)
+					_ = e.updateMigrationRowsCopiedPerSecond(ctx, uuid, e.sampleRowsCopied(uuid, s.rowsCopied, time.Now()))
+					_ = e.updateMigrationVReplicationLagSeconds(ctx, uuid, int64(s.lag(time.Now()).Seconds()))
 					_ = e.updateMigrationLastThrottled(ctx, uuid, time.Unix(s.timeThrottled, 0), s.componentThrottled)
 
 					isReady, err := e.isVReplMigrationReadyToCutOver(ctx, onlineDDL, s)
@@ -3580,6 +3638,16 @@ func (e *Executor) reviewRunningMigrations(ctx context.Context) (countRunnning i
 							isReady = false
 						}
 					}
+					if isReady && onlineDDL.StrategySetting().IsCoordinatedCutOverFlag() {
+						batchReady, cancelMessage, err := e.isCoordinatedCutOverBatchReady(ctx, onlineDDL)
+						if err != nil {
+							return countRunnning, cancellable, err
+						}
+						if cancelMessage != "" {
+							cancellable = append(cancellable, newCancellableMigration(uuid, cancelMessage))
+						}
+						isReady = batchReady
+					}
 					if isReady {
 						if err := e.cutOverVReplMigration(ctx, s); err != nil {
 							_ = e.updateMigrationMessage(ctx, uuid, err.Error())
@@ -3651,12 +3719,53 @@ func (e *Executor) reviewRunningMigrations(ctx context.Context) (countRunnning i
 			}
 			return true
 		})
+		for uuid := range e.vreplicationRowsCopiedSamples {
+			if !uuidsFoundRunning[uuid] {
+				delete(e.vreplicationRowsCopiedSamples, uuid)
+			}
+		}
 	}
 
 	e.reviewedRunningMigrationsFlag = true
 	return countRunnning, cancellable, nil
 }
 
+// isCoordinatedCutOverBatchReady checks the other --coordinated-cut-over migrations submitted with the given
+// migration, i.e. with the same migration context. The migration may only cut over once all of them are ready
+// to complete, or complete. If any of them failed or was cancelled, the migration must be cancelled as well,
+// and a cancel message is returned.
+func (e *Executor) isCoordinatedCutOverBatchReady(ctx context.Context, onlineDDL *schema.OnlineDDL) (isReady bool, cancelMessage string, err error) {
+	query, err := sqlparser.ParseAndBind(sqlSelectOtherMigrationsByContext,
+		sqltypes.StringBindVariable(e.keyspace),
+		sqltypes.StringBindVariable(onlineDDL.MigrationContext),
+		sqltypes.StringBindVariable(onlineDDL.UUID),
+	)
+	if err != nil {
+		return false, "", err
+	}
+	r, err := e.execQuery(ctx, query)
+	if err != nil {
+		return false, "", err
+	}
+	isReady = true
+	for _, row := range r.Named().Rows {
+		strategySetting := schema.NewDDLStrategySetting(schema.DDLStrategy(row["strategy"].ToString()), row["options"].ToString())
+		if !strategySetting.IsCoordinatedCutOverFlag() {
+			continue
+		}
+		switch status := schema.OnlineDDLStatus(row["migration_status"].ToString()); status {
+		case schema.OnlineDDLStatusComplete:
+		case schema.OnlineDDLStatusFailed, schema.OnlineDDLStatusCancelled:
+			return false, fmt.Sprintf("migration %s of the coordinated cut-over batch %s is %s", row["migration_uuid"].ToString(), onlineDDL.MigrationContext, status), nil
+		default:
+			if !row.AsBool("ready_to_complete", false) {
+				isReady = false
+			}
+		}
+	}
+	return isReady, "", nil
+}
+
 // reviewStaleMigrations marks as 'failed' migrations whose status is 'running' but which have
 // shown no liveness in past X minutes. It also attempts to terminate them
 func (e *Executor) reviewStaleMigrations(ctx context.Context) error {
@@ -3880,16 +3989,17 @@ func (e *Executor) onMigrationCheckTick() {
 	}
 
 	ctx := context.Background()
+	schedulerConfig := e.readSchedulerConfig(ctx)
 	if err := e.retryTabletFailureMigrations(ctx); err != nil {
 		log.Error(err)
 	}
 	if err := e.reviewQueuedMigrations(ctx); err != nil {
 		log.Error(err)
 	}
-	if err := e.scheduleNextMigration(ctx); err != nil {
+	if err := e.scheduleNextMigration(ctx, schedulerConfig); err != nil {
 		log.Error(err)
 	}
-	if err := e.runNextMigration(ctx); err != nil {
+	if err := e.runNextMigration(ctx, schedulerConfig); err != nil {
 		log.Error(err)
 	}
 	if _, cancellable, err := e.reviewRunningMigrations(ctx); err != nil {
@@ -4253,6 +4363,30 @@ func (e *Executor) updateRowsCopied(ctx context.Context, uuid string, rowsCopied
 	return err
 }
 
+func (e *Executor) updateMigrationRowsCopiedPerSecond(ctx context.Context, uuid string, rowsCopiedPerSecond float64) error {
+	query, err := sqlparser.ParseAndBind(sqlUpdateMigrationRowsCopiedPerSecond,
+		sqltypes.Float64BindVariable(rowsCopiedPerSecond),
+		sqltypes.StringBindVariable(uuid),
+	)
+	if err != nil {
+		return err
+	}
+	_, err = e.execQuery(ctx, query)
+	return err
+}
+
+func (e *Executor) updateMigrationVReplicationLagSeconds(ctx context.Context, uuid string, vreplicationLagSeconds int64) error {
+	query, err := sqlparser.ParseAndBind(sqlUpdateMigrationVReplicationLagSeconds,
+		sqltypes.Int64BindVariable(vreplicationLagSeconds),
+		sqltypes.StringBindVariable(uuid),
+	)
+	if err != nil {
+		return err
+	}
+	_, err = e.execQuery(ctx, query)
+	return err
+}
+
 func (e *Executor) updateVitessLivenessIndicator(ctx context.Context, uuid string, livenessIndicator int64) error {
 	query, err := sqlparser.ParseAndBind(sqlUpdateMigrationVitessLivenessIndicator,
 		sqltypes.Int64BindVariable(livenessIndicator),
@@ -4687,6 +4821,7 @@ func (e *Executor) SubmitMigration(
 		sqltypes.BoolBindVariable(allowConcurrentMigration),
 		sqltypes.StringBindVariable(revertedUUID),
 		sqltypes.BoolBindVariable(onlineDDL.IsView()),
+		sqltypes.StringBindVariable(strings.Join(onlineDDL.StrategySetting().DependsOn(), ",")),
 	)
 	if err != nil {
 		return nil, err
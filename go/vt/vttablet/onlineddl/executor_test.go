@@ -52,7 +52,7 @@ func TestValidateAndEditCreateTableStatement(t *testing.T) {
 		countConstraints int
 	}{
 		{
-			name: "table with FK, not allowed",
+			name: "table with FK",
 			query: `
 				create table onlineddl_test (
 						id int auto_increment,
@@ -63,7 +63,6 @@ func TestValidateAndEditCreateTableStatement(t *testing.T) {
 					)
 				`,
 			countConstraints: 1,
-			expectError:      schema.ErrForeignKeyFound.Error(),
 		},
 		{
 			name: "table with FK, allowed",
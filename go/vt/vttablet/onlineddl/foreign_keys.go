@@ -0,0 +1,218 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package onlineddl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// droppedForeignKeysRevertibleNote is the revertible note of a migration whose cut-over dropped the
+// foreign keys of the original table. Such a migration cannot be reverted.
+const droppedForeignKeysRevertibleNote = "foreign keys dropped from original table at cut-over"
+
+// foreignKeyRelation is a FOREIGN KEY constraint, as found in INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS
+type foreignKeyRelation struct {
+	constraintName string
+	childTable     string
+	parentTable    string
+	updateRule     string
+	deleteRule     string
+}
+
+// isCascadingReferentialAction returns true for referential actions that change the rows of the child
+// table. InnoDB applies such changes internally, and does not write them to the binary log.
+func isCascadingReferentialAction(rule string) bool {
+	switch strings.ToUpper(rule) {
+	case "CASCADE", "SET NULL", "SET DEFAULT":
+		return true
+	}
+	return false
+}
+
+// readForeignKeyRelations reads the FOREIGN KEY constraints in which the given table is either the parent or the child
+func (e *Executor) readForeignKeyRelations(ctx context.Context, table string) (relations []*foreignKeyRelation, err error) {
+	query, err := sqlparser.ParseAndBind(sqlSelectForeignKeyRelations,
+		sqltypes.StringBindVariable(e.dbName),
+		sqltypes.StringBindVariable(table),
+		sqltypes.StringBindVariable(table),
+	)
+	if err != nil {
+		return nil, err
+	}
+	r, err := e.execQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range r.Named().Rows {
+		relations = append(relations, &foreignKeyRelation{
+			constraintName: row.AsString("constraint_name", ""),
+			childTable:     row.AsString("table_name", ""),
+			parentTable:    row.AsString("referenced_table_name", ""),
+			updateRule:     row.AsString("update_rule", ""),
+			deleteRule:     row.AsString("delete_rule", ""),
+		})
+	}
+	return relations, nil
+}
+
+// isPreserveForeignKeySupported checks whether the server has the rename_table_preserve_foreign_key
+// variable, which makes RENAME TABLE keep the foreign keys of child tables pointing at the parent table name.
+func (e *Executor) isPreserveForeignKeySupported(ctx context.Context) (bool, error) {
+	rs, err := e.execQuery(ctx, sqlShowVariablesLikePreserveForeignKey)
+	if err != nil {
+		return false, err
+	}
+	return len(rs.Rows) > 0, nil
+}
+
+// isForeignKeyParent returns true when the table is the parent of any of the relations
+func isForeignKeyParent(table string, relations []*foreignKeyRelation) bool {
+	for _, relation := range relations {
+		if relation.parentTable == table {
+			return true
+		}
+	}
+	return false
+}
+
+// childForeignKeyNames returns the names of the constraints in which the table is the child
+func childForeignKeyNames(table string, relations []*foreignKeyRelation) (names []string) {
+	for _, relation := range relations {
+		if relation.childTable == table {
+			names = append(names, relation.constraintName)
+		}
+	}
+	return names
+}
+
+// foreignKeyRelatedTables returns the parents and children of the table, not including the table itself
+func foreignKeyRelatedTables(table string, relations []*foreignKeyRelation) map[string]bool {
+	related := map[string]bool{}
+	for _, relation := range relations {
+		for _, relatedTable := range []string{relation.parentTable, relation.childTable} {
+			if relatedTable != table {
+				related[relatedTable] = true
+			}
+		}
+	}
+	return related
+}
+
+// validateForeignKeyRelations checks whether a vreplication migration can safely alter a table that
+// participates in the given foreign key relations:
+//   - a child table is fine, as long as its parents do not cascade changes onto it: cascaded changes are
+//     not written to the binary log, and so cannot be applied onto the shadow table.
+//   - a parent table is fine, as long as the cut-over is able to keep its children pointing at the table
+//     name, which requires rename_table_preserve_foreign_key.
+//   - a self referencing table is not supported, as the shadow table would reference the original table.
+func validateForeignKeyRelations(table string, relations []*foreignKeyRelation, preserveForeignKeySupported bool) error {
+	for _, relation := range relations {
+		if relation.childTable == table && relation.parentTable == table {
+			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %s has a self referencing FOREIGN KEY constraint %s, which is not supported in Online DDL", table, relation.constraintName)
+		}
+		if relation.childTable == table {
+			for _, rule := range []string{relation.updateRule, relation.deleteRule} {
+				if isCascadingReferentialAction(rule) {
+					return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %s is a child of %s in FOREIGN KEY constraint %s with %s referential action; cascaded changes are not written to the binary log and cannot be applied by Online DDL", table, relation.parentTable, relation.constraintName, strings.ToUpper(rule))
+				}
+			}
+		}
+		if relation.parentTable == table && !preserveForeignKeySupported {
+			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %s is the parent of %s in FOREIGN KEY constraint %s, and the server does not support rename_table_preserve_foreign_key, without which the cut-over would leave %s referencing the original table", table, relation.childTable, relation.constraintName, relation.childTable)
+		}
+	}
+	return nil
+}
+
+// validateTableForeignKeys reads the foreign key relations of the migrated table, and validates that
+// the migration can safely cut over. It returns the relations.
+func (e *Executor) validateTableForeignKeys(ctx context.Context, table string) (relations []*foreignKeyRelation, err error) {
+	relations, err = e.readForeignKeyRelations(ctx, table)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "error while reading FOREIGN KEY constraints of table %s", table)
+	}
+	preserveForeignKeySupported := false
+	if isForeignKeyParent(table, relations) {
+		if preserveForeignKeySupported, err = e.isPreserveForeignKeySupported(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateForeignKeyRelations(table, relations, preserveForeignKeySupported); err != nil {
+		return nil, err
+	}
+	return relations, nil
+}
+
+// dropSwappedTableForeignKeys drops the given foreign keys from the table which a cut-over swapped out.
+// The table is retained as an artifact, and its foreign keys would otherwise keep restricting the
+// parent tables by rows which are no longer in the migrated table.
+func (e *Executor) dropSwappedTableForeignKeys(ctx context.Context, uuid string, swappedTable string, constraintNames []string) error {
+	for _, constraintName := range constraintNames {
+		parsed := sqlparser.BuildParsedQuery(sqlDropForeignKey, swappedTable, constraintName)
+		if _, err := e.execQuery(ctx, parsed.Query); err != nil {
+			return err
+		}
+	}
+	query, err := sqlparser.ParseAndBind(sqlAppendRevertibleNotes,
+		sqltypes.StringBindVariable(fmt.Sprintf("%s: %s", droppedForeignKeysRevertibleNote, strings.Join(constraintNames, ", "))),
+		sqltypes.StringBindVariable(uuid),
+	)
+	if err != nil {
+		return err
+	}
+	_, err = e.execQuery(ctx, query)
+	return err
+}
+
+// isAnyForeignKeyRelatedMigrationRunning checks whether there's a running migration on a parent or a
+// child of the table of the given migration. Such migrations run one at a time, so that the cut-over
+// of one does not take place while another copies or swaps a related table.
+func (e *Executor) isAnyForeignKeyRelatedMigrationRunning(ctx context.Context, onlineDDL *schema.OnlineDDL) (bool, error) {
+	if e.countOwnedRunningMigrations() == 0 {
+		return false, nil
+	}
+	relations, err := e.readForeignKeyRelations(ctx, onlineDDL.Table)
+	if err != nil {
+		return false, err
+	}
+	relatedTables := foreignKeyRelatedTables(onlineDDL.Table, relations)
+	if len(relatedTables) == 0 {
+		return false, nil
+	}
+	conflictFound := false
+	e.ownedRunningMigrations.Range(func(_, val any) bool {
+		runningMigration, ok := val.(*schema.OnlineDDL)
+		if !ok {
+			return true // continue iteration
+		}
+		if relatedTables[runningMigration.Table] {
+			conflictFound = true
+			return false // stop iteration
+		}
+		return true // continue iteration
+	})
+	return conflictFound, nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package onlineddl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateForeignKeyRelations(t *testing.T) {
+	relation := func(constraintName, child, parent, updateRule, deleteRule string) *foreignKeyRelation {
+		return &foreignKeyRelation{
+			constraintName: constraintName,
+			childTable:     child,
+			parentTable:    parent,
+			updateRule:     updateRule,
+			deleteRule:     deleteRule,
+		}
+	}
+	tt := []struct {
+		name         string
+		relations    []*foreignKeyRelation
+		preserveFK   bool
+		expectError  string
+		isParent     bool
+		childFKNames []string
+		related      map[string]bool
+	}{
+		{
+			name:    "no relations",
+			related: map[string]bool{},
+		},
+		{
+			name: "child, restrict",
+			relations: []*foreignKeyRelation{
+				relation("fk1", "t", "p1", "RESTRICT", "NO ACTION"),
+				relation("fk2", "t", "p2", "NO ACTION", "RESTRICT"),
+			},
+			childFKNames: []string{"fk1", "fk2"},
+			related:      map[string]bool{"p1": true, "p2": true},
+		},
+		{
+			name: "child, cascade",
+			relations: []*foreignKeyRelation{
+				relation("fk1", "t", "p1", "RESTRICT", "CASCADE"),
+			},
+			expectError:  "with CASCADE referential action",
+			childFKNames: []string{"fk1"},
+			related:      map[string]bool{"p1": true},
+		},
+		{
+			name: "child, set null",
+			relations: []*foreignKeyRelation{
+				relation("fk1", "t", "p1", "SET NULL", "RESTRICT"),
+			},
+			expectError:  "with SET NULL referential action",
+			childFKNames: []string{"fk1"},
+			related:      map[string]bool{"p1": true},
+		},
+		{
+			name: "parent, cascade, with preserve",
+			relations: []*foreignKeyRelation{
+				relation("fk1", "c1", "t", "CASCADE", "CASCADE"),
+			},
+			preserveFK: true,
+			isParent:   true,
+			related:    map[string]bool{"c1": true},
+		},
+		{
+			name: "parent, without preserve",
+			relations: []*foreignKeyRelation{
+				relation("fk1", "c1", "t", "RESTRICT", "RESTRICT"),
+			},
+			expectError: "does not support rename_table_preserve_foreign_key",
+			isParent:    true,
+			related:     map[string]bool{"c1": true},
+		},
+		{
+			name: "parent and child",
+			relations: []*foreignKeyRelation{
+				relation("fk1", "c1", "t", "RESTRICT", "RESTRICT"),
+				relation("fk2", "t", "p1", "RESTRICT", "RESTRICT"),
+			},
+			preserveFK:   true,
+			isParent:     true,
+			childFKNames: []string{"fk2"},
+			related:      map[string]bool{"c1": true, "p1": true},
+		},
+		{
+			name: "self referencing",
+			relations: []*foreignKeyRelation{
+				relation("fk1", "t", "t", "RESTRICT", "RESTRICT"),
+			},
+			preserveFK:   true,
+			expectError:  "self referencing",
+			isParent:     true,
+			childFKNames: []string{"fk1"},
+			related:      map[string]bool{},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateForeignKeyRelations("t", tc.relations, tc.preserveFK)
+			if tc.expectError != "" {
+				assert.ErrorContains(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.isParent, isForeignKeyParent("t", tc.relations))
+			assert.Equal(t, tc.childFKNames, childForeignKeyNames("t", tc.relations))
+			assert.Equal(t, tc.related, foreignKeyRelatedTables("t", tc.relations))
+		})
+	}
+}
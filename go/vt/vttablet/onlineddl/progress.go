@@ -0,0 +1,49 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package onlineddl
+
+import (
+	"time"
+)
+
+// rowsCopiedSample is the number of rows a vreplication migration had copied at a point in time
+type rowsCopiedSample struct {
+	rowsCopied int64
+	sampledAt  time.Time
+}
+
+// rowsCopiedPerSecond returns the row-copy throughput between two samples. It returns 0 when there is no
+// previous sample, or when the number of rows went down, as happens when the copy restarts.
+func rowsCopiedPerSecond(previous, current *rowsCopiedSample) float64 {
+	if previous == nil {
+		return 0
+	}
+	elapsed := current.sampledAt.Sub(previous.sampledAt).Seconds()
+	if elapsed <= 0 || current.rowsCopied < previous.rowsCopied {
+		return 0
+	}
+	return float64(current.rowsCopied-previous.rowsCopied) / elapsed
+}
+
+// sampleRowsCopied records the rows copied by the given migration, and returns the row-copy throughput since
+// the previous review of the migration. It is called by reviewRunningMigrations, under migrationMutex.
+func (e *Executor) sampleRowsCopied(uuid string, rowsCopied int64, now time.Time) float64 {
+	current := &rowsCopiedSample{rowsCopied: rowsCopied, sampledAt: now}
+	previous := e.vreplicationRowsCopiedSamples[uuid]
+	e.vreplicationRowsCopiedSamples[uuid] = current
+	return rowsCopiedPerSecond(previous, current)
+}
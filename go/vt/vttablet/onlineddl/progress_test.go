@@ -0,0 +1,77 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package onlineddl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampleRowsCopied(t *testing.T) {
+	e := &Executor{vreplicationRowsCopiedSamples: map[string]*rowsCopiedSample{}}
+	now := time.Now()
+
+	assert.Equal(t, float64(0), e.sampleRowsCopied("uuid1", 100, now))
+	assert.Equal(t, float64(50), e.sampleRowsCopied("uuid1", 600, now.Add(10*time.Second)))
+	assert.Equal(t, float64(0), e.sampleRowsCopied("uuid1", 600, now.Add(20*time.Second)))
+	assert.Equal(t, float64(0), e.sampleRowsCopied("uuid1", 700, now.Add(20*time.Second)))
+	// the copy restarted
+	assert.Equal(t, float64(0), e.sampleRowsCopied("uuid1", 10, now.Add(30*time.Second)))
+	assert.Equal(t, float64(1), e.sampleRowsCopied("uuid1", 20, now.Add(40*time.Second)))
+
+	assert.Equal(t, float64(0), e.sampleRowsCopied("uuid2", 1000, now.Add(40*time.Second)))
+	assert.Len(t, e.vreplicationRowsCopiedSamples, 2)
+}
+
+func TestVReplStreamLag(t *testing.T) {
+	now := time.Now()
+	tt := []struct {
+		name                 string
+		timeUpdated          time.Time
+		transactionTimestamp time.Time
+		expectLag            time.Duration
+	}{
+		{
+			name:                 "up to date",
+			timeUpdated:          now,
+			transactionTimestamp: now,
+		},
+		{
+			name:                 "transaction timestamp behind",
+			timeUpdated:          now.Add(-time.Second),
+			transactionTimestamp: now.Add(-time.Minute),
+			expectLag:            time.Minute,
+		},
+		{
+			name:                 "time updated behind",
+			timeUpdated:          now.Add(-time.Hour),
+			transactionTimestamp: now.Add(-time.Minute),
+			expectLag:            time.Hour,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &VReplStream{
+				timeUpdated:          tc.timeUpdated.Unix(),
+				transactionTimestamp: tc.transactionTimestamp.Unix(),
+			}
+			assert.Equal(t, tc.expectLag, s.lag(time.Unix(now.Unix(), 0)))
+		})
+	}
+}
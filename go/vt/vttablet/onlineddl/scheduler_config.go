@@ -0,0 +1,95 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package onlineddl
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/topo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// readSchedulerConfig reads the Online DDL scheduler configuration of the keyspace from the
+// SrvKeyspace of the tablet's cell. It returns nil when the keyspace has no configuration,
+// or when it cannot be read, in which case the scheduler runs with its defaults.
+func (e *Executor) readSchedulerConfig(ctx context.Context) *topodatapb.OnlineDDLSchedulerConfig {
+	if e.ts == nil || e.tabletAlias == nil {
+		return nil
+	}
+	srvKeyspace, err := e.ts.GetSrvKeyspace(ctx, e.tabletAlias.Cell, e.keyspace)
+	if err != nil {
+		if !topo.IsErrType(err, topo.NoNode) {
+			log.Errorf("Executor.readSchedulerConfig: cannot read SrvKeyspace %s in cell %s: %v", e.keyspace, e.tabletAlias.Cell, err)
+		}
+		return nil
+	}
+	return srvKeyspace.OnlineDdlSchedulerConfig
+}
+
+// maxConcurrentMigrations returns the number of migrations allowed to run concurrently: the
+// --max_concurrent_online_ddl flag, unless the scheduler configuration sets a lower limit.
+func maxConcurrentMigrations(config *topodatapb.OnlineDDLSchedulerConfig) int {
+	if limit := int(config.GetMaxConcurrentMigrations()); limit > 0 && limit < maxConcurrentOnlineDDLs {
+		return limit
+	}
+	return maxConcurrentOnlineDDLs
+}
+
+// migrationPriority returns the priority of the --priority-class of a migration, given its
+// strategy options. Migrations of no or of an unknown priority class have priority 0.
+func migrationPriority(config *topodatapb.OnlineDDLSchedulerConfig, options string) int32 {
+	priorityClass := schema.NewDDLStrategySetting(schema.DDLStrategyVitess, options).PriorityClass()
+	if priorityClass == "" {
+		return 0
+	}
+	return config.GetPriorityClasses()[priorityClass]
+}
+
+// sortMigrationRowsByPriority sorts migration rows, which have an `options` column, by the
+// priority of their priority class, highest first. Rows of equal priority keep their order.
+func sortMigrationRowsByPriority(config *topodatapb.OnlineDDLSchedulerConfig, rows []sqltypes.RowNamedValues) {
+	if len(config.GetPriorityClasses()) == 0 {
+		return
+	}
+	priorities := make(map[string]int32, len(rows))
+	for _, row := range rows {
+		priorities[row["migration_uuid"].ToString()] = migrationPriority(config, row["options"].ToString())
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return priorities[rows[i]["migration_uuid"].ToString()] > priorities[rows[j]["migration_uuid"].ToString()]
+	})
+}
+
+// isLaunchWindowOpen returns true when the scheduler configuration has a launch window, and
+// the given time is within it.
+func isLaunchWindowOpen(config *topodatapb.OnlineDDLSchedulerConfig, now time.Time) bool {
+	if config.GetLaunchWindow() == "" {
+		return false
+	}
+	launchWindow, err := schema.ParseLaunchWindow(config.GetLaunchWindow())
+	if err != nil {
+		log.Errorf("Executor: invalid Online DDL launch window: %v", err)
+		return false
+	}
+	return launchWindow.IsOpen(now)
+}
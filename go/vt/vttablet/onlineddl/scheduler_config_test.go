@@ -0,0 +1,80 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package onlineddl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestMaxConcurrentMigrations(t *testing.T) {
+	assert.Equal(t, maxConcurrentOnlineDDLs, maxConcurrentMigrations(nil))
+	assert.Equal(t, maxConcurrentOnlineDDLs, maxConcurrentMigrations(&topodatapb.OnlineDDLSchedulerConfig{}))
+	assert.Equal(t, 2, maxConcurrentMigrations(&topodatapb.OnlineDDLSchedulerConfig{MaxConcurrentMigrations: 2}))
+	// The configuration cannot raise the limit of the tablet flag
+	assert.Equal(t, maxConcurrentOnlineDDLs, maxConcurrentMigrations(&topodatapb.OnlineDDLSchedulerConfig{MaxConcurrentMigrations: int32(maxConcurrentOnlineDDLs + 1)}))
+}
+
+func TestSortMigrationRowsByPriority(t *testing.T) {
+	config := &topodatapb.OnlineDDLSchedulerConfig{
+		PriorityClasses: map[string]int32{"urgent": 10, "batch": -1},
+	}
+	row := func(uuid, options string) sqltypes.RowNamedValues {
+		return sqltypes.RowNamedValues{
+			"migration_uuid": sqltypes.NewVarChar(uuid),
+			"options":        sqltypes.NewVarChar(options),
+		}
+	}
+	uuids := func(rows []sqltypes.RowNamedValues) (uuids []string) {
+		for _, row := range rows {
+			uuids = append(uuids, row["migration_uuid"].ToString())
+		}
+		return uuids
+	}
+	newRows := func() []sqltypes.RowNamedValues {
+		return []sqltypes.RowNamedValues{
+			row("a", "--priority-class=batch"),
+			row("b", ""),
+			row("c", "--postpone-launch --priority-class=urgent"),
+			row("d", "--priority-class=unknown"),
+			row("e", `--priority-class="urgent"`),
+		}
+	}
+
+	rows := newRows()
+	sortMigrationRowsByPriority(config, rows)
+	assert.Equal(t, []string{"c", "e", "b", "d", "a"}, uuids(rows))
+
+	rows = newRows()
+	sortMigrationRowsByPriority(nil, rows)
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, uuids(rows))
+}
+
+func TestIsLaunchWindowOpen(t *testing.T) {
+	now := time.Date(2023, time.July, 3, 2, 30, 0, 0, time.UTC)
+	assert.False(t, isLaunchWindowOpen(nil, now))
+	assert.False(t, isLaunchWindowOpen(&topodatapb.OnlineDDLSchedulerConfig{}, now))
+	assert.True(t, isLaunchWindowOpen(&topodatapb.OnlineDDLSchedulerConfig{LaunchWindow: "* 1-5 * * *"}, now))
+	assert.False(t, isLaunchWindowOpen(&topodatapb.OnlineDDLSchedulerConfig{LaunchWindow: "* 6-8 * * *"}, now))
+	assert.False(t, isLaunchWindowOpen(&topodatapb.OnlineDDLSchedulerConfig{LaunchWindow: "invalid"}, now))
+}
@@ -36,9 +36,10 @@ const (
 		postpone_completion,
 		allow_concurrent,
 		reverted_uuid,
-		is_view
+		is_view,
+		depends_on
 	) VALUES (
-		%a, %a, %a, %a, %a, %a, %a, %a, %a, NOW(6), %a, %a, %a, %a, %a, %a, %a, %a, %a
+		%a, %a, %a, %a, %a, %a, %a, %a, %a, NOW(6), %a, %a, %a, %a, %a, %a, %a, %a, %a, %a
 	)`
 
 	sqlSelectQueuedMigrations = `SELECT
@@ -48,7 +49,9 @@ const (
 			is_immediate_operation,
 			postpone_launch,
 			postpone_completion,
-			ready_to_complete
+			ready_to_complete,
+			options,
+			depends_on
 		FROM _vt.schema_migrations
 		WHERE
 			migration_status='queued'
@@ -85,6 +88,16 @@ const (
 		WHERE
 			migration_uuid=%a
 	`
+	sqlUpdateMigrationRowsCopiedPerSecond = `UPDATE _vt.schema_migrations
+			SET rows_copied_per_second=%a
+		WHERE
+			migration_uuid=%a
+	`
+	sqlUpdateMigrationVReplicationLagSeconds = `UPDATE _vt.schema_migrations
+			SET vreplication_lag_seconds=%a
+		WHERE
+			migration_uuid=%a
+	`
 	sqlUpdateMigrationIsView = `UPDATE _vt.schema_migrations
 			SET is_view=%a
 		WHERE
@@ -206,6 +219,11 @@ const (
 		WHERE
 			migration_uuid=%a
 	`
+	sqlAppendRevertibleNotes = `UPDATE _vt.schema_migrations
+			SET revertible_notes=CONCAT_WS('\n', NULLIF(revertible_notes, ''), %a)
+		WHERE
+			migration_uuid=%a
+	`
 	sqlUpdateMigrationTableRows = `UPDATE _vt.schema_migrations
 			SET table_rows=%a
 		WHERE
@@ -313,6 +331,18 @@ const (
 			AND migration_statement=%a
 		LIMIT 1
 	`
+	sqlSelectOtherMigrationsByContext = `SELECT
+			migration_uuid,
+			migration_status,
+			ready_to_complete,
+			strategy,
+			options
+		FROM _vt.schema_migrations
+		WHERE
+			keyspace=%a
+			AND migration_context=%a
+			AND migration_uuid!=%a
+	`
 	sqlSelectStaleMigrations = `SELECT
 			migration_uuid
 		FROM _vt.schema_migrations
@@ -403,13 +433,15 @@ const (
 			postpone_launch,
 			postpone_completion,
 			is_immediate_operation,
+			revertible_notes,
 			reviewed_timestamp
 		FROM _vt.schema_migrations
 		WHERE
 			migration_uuid=%a
 	`
 	sqlSelectReadyMigrations = `SELECT
-			migration_uuid
+			migration_uuid,
+			options
 		FROM _vt.schema_migrations
 		WHERE
 			migration_status='ready'
@@ -435,21 +467,17 @@ const (
 				table_schema=%a
 				and table_name=%a
 		`
-	selSelectCountFKParentConstraints = `
+	sqlSelectForeignKeyRelations = `
 		SELECT
-			COUNT(*) as num_fk_constraints
-		FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE
-		WHERE
-			REFERENCED_TABLE_SCHEMA=%a AND REFERENCED_TABLE_NAME=%a
-			AND REFERENCED_TABLE_NAME IS NOT NULL
-		`
-	selSelectCountFKChildConstraints = `
-		SELECT
-			COUNT(*) as num_fk_constraints
-		FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE
-		WHERE
-			TABLE_SCHEMA=%a AND TABLE_NAME=%a
-			AND REFERENCED_TABLE_NAME IS NOT NULL
+			CONSTRAINT_NAME as constraint_name,
+			TABLE_NAME as table_name,
+			REFERENCED_TABLE_NAME as referenced_table_name,
+			UPDATE_RULE as update_rule,
+			DELETE_RULE as delete_rule
+		FROM INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS
+		WHERE
+			CONSTRAINT_SCHEMA=%a
+			AND (TABLE_NAME=%a OR REFERENCED_TABLE_NAME=%a)
 		`
 	sqlSelectUniqueKeys = `
 	SELECT
@@ -568,6 +596,14 @@ const (
 	sqlUnlockTables       = "UNLOCK TABLES"
 	sqlCreateSentryTable  = "CREATE TABLE IF NOT EXISTS `%a` (id INT PRIMARY KEY)"
 	sqlFindProcess        = "SELECT id, Info as info FROM information_schema.processlist WHERE id=%a AND Info LIKE %a"
+
+	sqlInsertIntoShadowTable = "INSERT INTO `%a` (%s) SELECT %s FROM `%a`"
+	sqlSelectAllFrom         = "SELECT * FROM `%a`"
+
+	sqlShowVariablesLikePreserveForeignKey = "SHOW GLOBAL VARIABLES LIKE 'rename_table_preserve_foreign_key'"
+	sqlEnablePreserveForeignKey            = "SET @@session.rename_table_preserve_foreign_key = 1"
+	sqlDisablePreserveForeignKey           = "SET @@session.rename_table_preserve_foreign_key = 0"
+	sqlDropForeignKey                      = "ALTER TABLE `%a` DROP FOREIGN KEY `%a`"
 )
 
 var (
@@ -0,0 +1,156 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package onlineddl
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/dbconnpool"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// validateShadowCaptureTableSize checks that a table is small enough for a `mysql --revertible` migration, which
+// blocks writes to the table while it copies it.
+func validateShadowCaptureTableSize(table string, tableSize int64, maxTableSize int64) error {
+	if tableSize > maxTableSize {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %s has an estimated size of %d bytes, which exceeds --revertible-mysql-migration-max-table-size=%d. A revertible mysql migration blocks writes to the table while copying it; use the vitess strategy, or drop --revertible", table, tableSize, maxTableSize)
+	}
+	return nil
+}
+
+// readTableSize returns the estimated size of the given table, data and indexes, in bytes
+func (e *Executor) readTableSize(ctx context.Context, conn *dbconnpool.DBConnection, tableName string) (tableSize int64, err error) {
+	parsed := sqlparser.BuildParsedQuery(sqlShowTableStatus, tableName)
+	rs, err := conn.ExecuteFetch(parsed.Query, math.MaxInt64, true)
+	if err != nil {
+		return 0, err
+	}
+	row := rs.Named().Row()
+	if row == nil {
+		return 0, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "Cannot SHOW TABLE STATUS LIKE '%s'", tableName)
+	}
+	return row.AsInt64("Data_length", 0) + row.AsInt64("Index_length", 0), nil
+}
+
+// executeDirectlyWithShadowCapture runs a `mysql --revertible` ALTER TABLE such that it can be reverted just like
+// a `vitess` migration. With the table write-locked, it copies the table onto a shadow table, runs the ALTER, and
+// reads the position. It then records the shadow table and the position in a stopped vreplication stream, which
+// is what a REVERT looks for: the revert replays the changes made to the table since that position onto the
+// shadow table, and then swaps the two. The shadow table is retained, and cleaned up, as an artifact.
+// Writes to the table are blocked throughout the copy, which is why the table size is limited.
+func (e *Executor) executeDirectlyWithShadowCapture(ctx context.Context, onlineDDL *schema.OnlineDDL) error {
+	conn, err := dbconnpool.NewDBConnection(ctx, e.env.Config().DB.DbaWithDB())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	restoreSQLModeFunc, err := e.initMigrationSQLMode(ctx, onlineDDL, conn)
+	defer restoreSQLModeFunc()
+	if err != nil {
+		return err
+	}
+
+	tableSize, err := e.readTableSize(ctx, conn, onlineDDL.Table)
+	if err != nil {
+		return err
+	}
+	if err := validateShadowCaptureTableSize(onlineDDL.Table, tableSize, revertibleMySQLMigrationMaxTableSize); err != nil {
+		return err
+	}
+
+	_ = e.onSchemaMigrationStatus(ctx, onlineDDL.UUID, schema.OnlineDDLStatusRunning, false, progressPctStarted, etaSecondsUnknown, rowsCopiedUnknown, emptyHint)
+
+	shadowTableName := fmt.Sprintf("_%s_%s_vrepl", onlineDDL.UUID, ReadableTimestamp())
+	if err := e.updateArtifacts(ctx, onlineDDL.UUID, shadowTableName); err != nil {
+		return err
+	}
+	if _, err := e.createTableLike(ctx, shadowTableName, onlineDDL, conn); err != nil {
+		return err
+	}
+	v := NewVRepl(onlineDDL.UUID, e.keyspace, e.shard, e.dbName, onlineDDL.Table, shadowTableName, "", false)
+	columns, virtualColumns, _, err := v.readTableColumns(ctx, conn, onlineDDL.Table)
+	if err != nil {
+		return err
+	}
+	var escapedColumnNames []string
+	for _, name := range columns.Difference(virtualColumns).Names() {
+		escapedColumnNames = append(escapedColumnNames, escapeName(name))
+	}
+	copyColumns := strings.Join(escapedColumnNames, ",")
+
+	pos, err := func() (replication.Position, error) {
+		lockQuery := sqlparser.BuildParsedQuery(sqlLockTwoTablesWrite, onlineDDL.Table, shadowTableName).Query
+		if _, err := conn.ExecuteFetch(lockQuery, 0, false); err != nil {
+			return replication.Position{}, err
+		}
+		defer func() {
+			if _, err := conn.ExecuteFetch(sqlUnlockTables, 0, false); err != nil {
+				log.Errorf("executeDirectlyWithShadowCapture: failed to unlock tables for migration %s: %v", onlineDDL.UUID, err)
+			}
+		}()
+		copyQuery := sqlparser.BuildParsedQuery(sqlInsertIntoShadowTable, shadowTableName, copyColumns, copyColumns, onlineDDL.Table).Query
+		if _, err := conn.ExecuteFetch(copyQuery, 0, false); err != nil {
+			return replication.Position{}, err
+		}
+		if _, err := conn.ExecuteFetch(onlineDDL.SQL, 0, false); err != nil {
+			return replication.Position{}, err
+		}
+		// No write took place on the table since the copy, other than the ALTER itself
+		return conn.PrimaryPosition()
+	}()
+	if err != nil {
+		return err
+	}
+	defer e.reloadSchema(ctx)
+
+	v.pos = replication.EncodePosition(pos)
+	v.bls = &binlogdatapb.BinlogSource{
+		Keyspace: e.keyspace,
+		Shard:    e.shard,
+		Filter: &binlogdatapb.Filter{
+			Rules: []*binlogdatapb.Rule{{
+				Match:  shadowTableName,
+				Filter: sqlparser.BuildParsedQuery(sqlSelectAllFrom, onlineDDL.Table).Query,
+			}},
+		},
+	}
+	tablet, err := e.ts.GetTablet(ctx, e.tabletAlias)
+	if err != nil {
+		return err
+	}
+	insertVReplicationQuery, err := v.generateInsertStatement(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := e.vreplicationExec(ctx, tablet.Tablet, insertVReplicationQuery); err != nil {
+		return err
+	}
+
+	_ = e.onSchemaMigrationStatus(ctx, onlineDDL.UUID, schema.OnlineDDLStatusComplete, false, progressPctFull, etaSecondsNow, rowsCopiedUnknown, emptyHint)
+	return nil
+}
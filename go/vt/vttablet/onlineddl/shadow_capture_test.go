@@ -0,0 +1,44 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package onlineddl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateShadowCaptureTableSize(t *testing.T) {
+	tt := []struct {
+		tableSize    int64
+		maxTableSize int64
+		expectError  bool
+	}{
+		{tableSize: 0, maxTableSize: 0},
+		{tableSize: 1024, maxTableSize: 1024},
+		{tableSize: 1025, maxTableSize: 1024, expectError: true},
+		{tableSize: 16384, maxTableSize: 0, expectError: true},
+	}
+	for _, tc := range tt {
+		err := validateShadowCaptureTableSize("t", tc.tableSize, tc.maxTableSize)
+		if tc.expectError {
+			assert.ErrorContains(t, err, "--revertible-mysql-migration-max-table-size")
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+}
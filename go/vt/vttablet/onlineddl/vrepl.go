@@ -30,6 +30,7 @@ import (
 	"math"
 	"strconv"
 	"strings"
+	"time"
 
 	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/mysql/collations/charset"
@@ -94,6 +95,18 @@ func (v *VReplStream) hasError() (isTerminal bool, vreplError error) {
 	return false, nil
 }
 
+// lag returns the replication lag of the workflow, as of the given time: how far time_updated and
+// transaction_timestamp are behind. transaction_timestamp gets written by any ongoing writes on the server
+// (whether on this table or any other table), and time_updated by any of the workflow's liveness indicators.
+func (v *VReplStream) lag(now time.Time) time.Duration {
+	durationDiff := func(t1, t2 time.Time) time.Duration {
+		return t1.Sub(t2).Abs()
+	}
+	timeUpdatedLag := durationDiff(now, time.Unix(v.timeUpdated, 0))
+	transactionTimestampLag := durationDiff(now, time.Unix(v.transactionTimestamp, 0))
+	return max(timeUpdatedLag, transactionTimestampLag)
+}
+
 // VRepl is an online DDL helper for VReplication based migrations (ddl_strategy="online")
 type VRepl struct {
 	workflow    string
@@ -156,14 +156,14 @@ func (tm *TabletManager) RestoreData(ctx context.Context, logger logutil.Logger,
 	req := &tabletmanagerdatapb.RestoreFromBackupRequest{
 		BackupTime: logutil.TimeToProto(backupTime),
 	}
-	err = tm.restoreDataLocked(ctx, logger, waitForBackupInterval, deleteBeforeRestore, req)
+	err = tm.restoreDataLocked(ctx, logger, waitForBackupInterval, deleteBeforeRestore, req, nil /* progress */)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (tm *TabletManager) restoreDataLocked(ctx context.Context, logger logutil.Logger, waitForBackupInterval time.Duration, deleteBeforeRestore bool, request *tabletmanagerdatapb.RestoreFromBackupRequest) error {
+func (tm *TabletManager) restoreDataLocked(ctx context.Context, logger logutil.Logger, waitForBackupInterval time.Duration, deleteBeforeRestore bool, request *tabletmanagerdatapb.RestoreFromBackupRequest, progress func(*tabletmanagerdatapb.RestoreProgress)) error {
 
 	tablet := tm.Tablet()
 	originalType := tablet.Type
@@ -192,11 +192,16 @@ func (tm *TabletManager) restoreDataLocked(ctx context.Context, logger logutil.L
 		startTime = logutil.ProtoToTime(keyspaceInfo.SnapshotTime)
 	}
 
+	concurrency := restoreConcurrency
+	if request.Concurrency > 0 {
+		concurrency = int(request.Concurrency)
+	}
+
 	params := mysqlctl.RestoreParams{
 		Cnf:                 tm.Cnf,
 		Mysqld:              tm.MysqlDaemon,
 		Logger:              logger,
-		Concurrency:         restoreConcurrency,
+		Concurrency:         concurrency,
 		HookExtraEnv:        tm.hookExtraEnv(),
 		DeleteBeforeRestore: deleteBeforeRestore,
 		DbName:              topoproto.TabletDbName(tablet),
@@ -205,6 +210,7 @@ func (tm *TabletManager) restoreDataLocked(ctx context.Context, logger logutil.L
 		StartTime:           startTime,
 		DryRun:              request.DryRun,
 		Stats:               backupstats.RestoreStats(),
+		Progress:            progress,
 	}
 	if request.RestoreToPos != "" && !logutil.ProtoToTime(request.RestoreToTimestamp).IsZero() {
 		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "--restore_to_pos and --restore_to_timestamp are mutually exclusive")
@@ -43,6 +43,14 @@ type RPCTM interface {
 
 	GetPermissions(ctx context.Context) (*tabletmanagerdatapb.Permissions, error)
 
+	GetPlanCache(ctx context.Context, tables, fingerprints []string) ([]*tabletmanagerdatapb.PlanCacheEntry, error)
+
+	GetTableStats(ctx context.Context, tables []string) ([]*tabletmanagerdatapb.TableStats, error)
+
+	GetUnresolvedTransactions(ctx context.Context, abandonAge time.Duration) ([]*querypb.TransactionMetadata, error)
+
+	ReadTransaction(ctx context.Context, dtid string) (*querypb.TransactionMetadata, error)
+
 	// Various read-write methods
 
 	SetReadOnly(ctx context.Context, rdonly bool) error
@@ -59,6 +67,10 @@ type RPCTM interface {
 
 	ReloadSchema(ctx context.Context, waitPosition string) error
 
+	InvalidatePlanCache(ctx context.Context, tables, fingerprints []string, all bool) (int, error)
+
+	ConcludeTransaction(ctx context.Context, dtid string, mm, commit bool) error
+
 	PreflightSchema(ctx context.Context, changes []string) ([]*tabletmanagerdatapb.SchemaChangeResult, error)
 
 	ApplySchema(ctx context.Context, change *tmutils.SchemaChange) (*tabletmanagerdatapb.SchemaChangeResult, error)
@@ -139,7 +151,9 @@ type RPCTM interface {
 
 	Backup(ctx context.Context, logger logutil.Logger, request *tabletmanagerdatapb.BackupRequest) error
 
-	RestoreFromBackup(ctx context.Context, logger logutil.Logger, request *tabletmanagerdatapb.RestoreFromBackupRequest) error
+	RestoreFromBackup(ctx context.Context, logger logutil.Logger, request *tabletmanagerdatapb.RestoreFromBackupRequest, progress func(*tabletmanagerdatapb.RestoreProgress)) error
+
+	UpgradeMysql(ctx context.Context, logger logutil.Logger, request *tabletmanagerdatapb.UpgradeMysqlRequest) error
 
 	// HandleRPCPanic is to be called in a defer statement in each
 	// RPC input point.
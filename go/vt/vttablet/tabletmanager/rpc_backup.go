@@ -170,8 +170,9 @@ func (tm *TabletManager) Backup(ctx context.Context, logger logutil.Logger, req
 }
 
 // RestoreFromBackup deletes all local data and then restores the data from the latest backup [at
-// or before the backupTime value if specified]
-func (tm *TabletManager) RestoreFromBackup(ctx context.Context, logger logutil.Logger, request *tabletmanagerdatapb.RestoreFromBackupRequest) error {
+// or before the backupTime value if specified]. If progress is set, it is called periodically
+// with the progress of the restore.
+func (tm *TabletManager) RestoreFromBackup(ctx context.Context, logger logutil.Logger, request *tabletmanagerdatapb.RestoreFromBackupRequest, progress func(*tabletmanagerdatapb.RestoreProgress)) error {
 	if err := tm.lock(ctx); err != nil {
 		return err
 	}
@@ -189,7 +190,7 @@ func (tm *TabletManager) RestoreFromBackup(ctx context.Context, logger logutil.L
 	l := logutil.NewTeeLogger(logutil.NewConsoleLogger(), logger)
 
 	// Now we can run restore.
-	err = tm.restoreDataLocked(ctx, l, 0 /* waitForBackupInterval */, true /* deleteBeforeRestore */, request)
+	err = tm.restoreDataLocked(ctx, l, 0 /* waitForBackupInterval */, true /* deleteBeforeRestore */, request, progress)
 
 	// Re-run health check to be sure to capture any replication delay.
 	tm.QueryServiceControl.BroadcastHealth()
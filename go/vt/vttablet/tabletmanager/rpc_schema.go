@@ -58,6 +58,25 @@ func (tm *TabletManager) ReloadSchema(ctx context.Context, waitPosition string)
 	return tm.QueryServiceControl.ReloadSchema(ctx)
 }
 
+// GetPlanCache returns the query plans of the plan cache of the tabletserver.
+func (tm *TabletManager) GetPlanCache(ctx context.Context, tables, fingerprints []string) ([]*tabletmanagerdatapb.PlanCacheEntry, error) {
+	return tm.QueryServiceControl.GetPlanCache(tables, fingerprints), nil
+}
+
+// GetTableStats returns the row and byte accounting of the queries per table
+// of the tabletserver.
+func (tm *TabletManager) GetTableStats(ctx context.Context, tables []string) ([]*tabletmanagerdatapb.TableStats, error) {
+	return tm.QueryServiceControl.GetTableStats(tables), nil
+}
+
+// InvalidatePlanCache removes query plans from the plan cache of the tabletserver,
+// so that they are built again with the current schema. This doesn't need the
+// action mutex, the plans are also removed by the schema reloads.
+func (tm *TabletManager) InvalidatePlanCache(ctx context.Context, tables, fingerprints []string, all bool) (int, error) {
+	log.Infof("InvalidatePlanCache requested via RPC")
+	return tm.QueryServiceControl.InvalidatePlanCache(tables, fingerprints, all), nil
+}
+
 // ResetSequences will reset the auto-inc counters on the specified tables.
 func (tm *TabletManager) ResetSequences(ctx context.Context, tables []string) error {
 	return tm.QueryServiceControl.SchemaEngine().ResetSequences(tables)
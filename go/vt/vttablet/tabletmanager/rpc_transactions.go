@@ -0,0 +1,56 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletmanager
+
+import (
+	"context"
+	"time"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// GetUnresolvedTransactions returns the metadata of the distributed transactions
+// created more than abandonAge ago, of which the tablet is the metadata manager.
+func (tm *TabletManager) GetUnresolvedTransactions(ctx context.Context, abandonAge time.Duration) ([]*querypb.TransactionMetadata, error) {
+	return tm.QueryServiceControl.UnresolvedTransactions(ctx, abandonAge)
+}
+
+// ReadTransaction returns the metadata of a distributed transaction, with an
+// empty dtid if the tablet is not its metadata manager.
+func (tm *TabletManager) ReadTransaction(ctx context.Context, dtid string) (*querypb.TransactionMetadata, error) {
+	return tm.QueryServiceControl.QueryService().ReadTransaction(ctx, tm.target(), dtid)
+}
+
+// ConcludeTransaction deletes the metadata of a distributed transaction if mm
+// is set, or else commits or rolls back its prepared transaction on the tablet.
+func (tm *TabletManager) ConcludeTransaction(ctx context.Context, dtid string, mm, commit bool) error {
+	qs := tm.QueryServiceControl.QueryService()
+	switch {
+	case mm:
+		return qs.ConcludeTransaction(ctx, tm.target(), dtid)
+	case commit:
+		return qs.CommitPrepared(ctx, tm.target(), dtid)
+	default:
+		return qs.RollbackPrepared(ctx, tm.target(), dtid, 0)
+	}
+}
+
+// target returns the query service target of the tablet.
+func (tm *TabletManager) target() *querypb.Target {
+	tablet := tm.Tablet()
+	return &querypb.Target{Keyspace: tablet.Keyspace, Shard: tablet.Shard, TabletType: tablet.Type}
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletmanager
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// upgradeMysqlSteps is the number of steps of UpgradeMysql, reported in its
+// logs along with the current step.
+const upgradeMysqlSteps = 10
+
+// upgradeMysqlAllowedRoots lists the MySQL distributions UpgradeMysql may
+// switch mysqld to. Since mysqld and mysql_upgrade are run from the requested
+// distribution, any other one is rejected.
+var upgradeMysqlAllowedRoots []string
+
+func registerUpgradeMysqlFlags(fs *pflag.FlagSet) {
+	fs.StringSliceVar(&upgradeMysqlAllowedRoots, "upgrade-mysql-allowed-roots", upgradeMysqlAllowedRoots, "Comma-separated list of the absolute paths of the MySQL distributions an UpgradeMysql request may switch mysqld to. Requests for any other distribution are rejected, and only the binaries upgraded in place can be used when it is empty.")
+}
+
+func init() {
+	servenv.OnParseFor("vtcombo", registerUpgradeMysqlFlags)
+	servenv.OnParseFor("vttablet", registerUpgradeMysqlFlags)
+}
+
+// allowedMysqlRoot returns the cleaned path of the MySQL distribution, or an
+// error if it is not listed in --upgrade-mysql-allowed-roots.
+func allowedMysqlRoot(root string) (string, error) {
+	cleaned := filepath.Clean(root)
+	if filepath.IsAbs(cleaned) {
+		for _, allowed := range upgradeMysqlAllowedRoots {
+			if filepath.Clean(allowed) == cleaned {
+				return cleaned, nil
+			}
+		}
+	}
+	return "", vterrors.Errorf(vtrpcpb.Code_PERMISSION_DENIED, "MySQL distribution %v is not allowed, it must be listed in --upgrade-mysql-allowed-roots", root)
+}
+
+// UpgradeMysql upgrades mysqld in place. The tablet is drained, mysqld is
+// shut down cleanly and started from the upgraded MySQL binaries, its system
+// tables are upgraded, and the tablet rejoins its shard with its original
+// type. Each step is logged.
+func (tm *TabletManager) UpgradeMysql(ctx context.Context, logger logutil.Logger, req *tabletmanagerdatapb.UpgradeMysqlRequest) error {
+	if tm.Cnf == nil {
+		return fmt.Errorf("cannot upgrade mysqld without my.cnf, please restart vttablet with a my.cnf file specified")
+	}
+
+	if err := tm.lock(ctx); err != nil {
+		return err
+	}
+	defer tm.unlock()
+
+	tm.mutex.Lock()
+	backupRunning := tm._isBackupRunning
+	tm.mutex.Unlock()
+	if backupRunning {
+		return fmt.Errorf("a backup is running on tablet %v, cannot upgrade mysqld", tm.tabletAlias)
+	}
+
+	// Create the logger: tee to console and source.
+	l := logutil.NewTeeLogger(logutil.NewConsoleLogger(), logger)
+	n := 0
+	step := func(format string, args ...any) {
+		n++
+		l.Infof("UpgradeMysql step %d/%d: %s", n, upgradeMysqlSteps, fmt.Sprintf(format, args...))
+	}
+
+	tablet, err := tm.TopoServer.GetTablet(ctx, tm.tabletAlias)
+	if err != nil {
+		return err
+	}
+	if !req.AllowPrimary && tablet.Type == topodatapb.TabletType_PRIMARY {
+		return fmt.Errorf("type PRIMARY cannot be upgraded in place, if you really need to do this, rerun the command with --allow-primary")
+	}
+
+	var mysqlRoot string
+	if req.MysqlRoot != "" {
+		if mysqlRoot, err = allowedMysqlRoot(req.MysqlRoot); err != nil {
+			return err
+		}
+	}
+
+	step("checking the MySQL versions")
+	from, to, err := tm.upgradeMysqlVersions(ctx, mysqlRoot, req.ExpectedVersion)
+	if err != nil {
+		return err
+	}
+	l.Infof("Upgrading mysqld from %v to %v", from, to)
+
+	replStatus, err := tm.MysqlDaemon.ReplicationStatus()
+	replicating := false
+	switch err {
+	case nil:
+		replicating = replStatus.Healthy()
+	case mysql.ErrNotReplica:
+	default:
+		return vterrors.Wrap(err, "can't get replica status")
+	}
+	superReadOnly, err := tm.MysqlDaemon.IsSuperReadOnly()
+	if err != nil {
+		return vterrors.Wrap(err, "can't get super_read_only status")
+	}
+	semiSyncSource, semiSyncReplica := tm.MysqlDaemon.SemiSyncEnabled()
+
+	step("draining the tablet")
+	originalType := tablet.Type
+	if err := tm.changeTypeLocked(ctx, topodatapb.TabletType_DRAINED, DBActionNone, SemiSyncActionUnset); err != nil {
+		return err
+	}
+	defer func() {
+		// Change our type back to the original value, even if the upgrade
+		// failed, as Backup does.
+		if err := tm.changeTypeLocked(context.Background(), originalType, DBActionNone, SemiSyncActionNone); err != nil {
+			l.Errorf("Failed to change tablet type from %v to %v, error: %v", topodatapb.TabletType_DRAINED, originalType, err)
+		}
+		// Re-run health check to be sure to capture any replication delay.
+		tm.QueryServiceControl.BroadcastHealth()
+	}()
+
+	if replicating {
+		step("stopping replication")
+		if err := tm.MysqlDaemon.StopReplication(tm.hookExtraEnv()); err != nil {
+			return vterrors.Wrap(err, "can't stop replication")
+		}
+	} else {
+		step("replication is not running")
+	}
+
+	// A slow shutdown flushes all changes to the data files, which an upgrade
+	// across major versions requires.
+	step("shutting down mysqld")
+	if _, err := tm.MysqlDaemon.FetchSuperQuery(ctx, "SET GLOBAL innodb_fast_shutdown=0"); err != nil {
+		return vterrors.Wrap(err, "failed to disable fast shutdown")
+	}
+	if err := tm.MysqlDaemon.Shutdown(ctx, tm.Cnf, true); err != nil {
+		return vterrors.Wrap(err, "can't shutdown mysqld")
+	}
+
+	if mysqlRoot != "" {
+		step("switching to the MySQL distribution in %v", mysqlRoot)
+		if err := tm.MysqlDaemon.SetMysqlRoot(mysqlRoot); err != nil {
+			// Restart the previous mysqld, so that the tablet can serve again.
+			if startErr := tm.MysqlDaemon.Start(context.Background(), tm.Cnf); startErr != nil {
+				l.Errorf("Failed to restart mysqld, error: %v", startErr)
+			}
+			return vterrors.Wrap(err, "can't switch the MySQL distribution")
+		}
+	} else {
+		step("using the MySQL binaries upgraded in place")
+	}
+
+	// As for a restore, mysqld is started without grant tables, since their
+	// structure may differ across versions until mysql_upgrade ran. Networking
+	// is skipped since anyone can connect without grant tables.
+	step("starting mysqld for mysql_upgrade")
+	if err := tm.MysqlDaemon.Start(context.Background(), tm.Cnf, "--skip-grant-tables", "--skip-networking"); err != nil {
+		return vterrors.Wrap(err, "can't start mysqld")
+	}
+
+	step("running mysql_upgrade")
+	if err := tm.MysqlDaemon.RunMysqlUpgrade(ctx); err != nil {
+		return vterrors.Wrap(err, "mysql_upgrade failed")
+	}
+
+	// The MySQL manual recommends restarting mysqld after running
+	// mysql_upgrade, so that any changes made to system tables take effect.
+	step("restarting mysqld")
+	if err := tm.MysqlDaemon.Shutdown(context.Background(), tm.Cnf, true); err != nil {
+		return vterrors.Wrap(err, "can't shutdown mysqld")
+	}
+	if err := tm.MysqlDaemon.Start(context.Background(), tm.Cnf); err != nil {
+		return vterrors.Wrap(err, "can't restart mysqld")
+	}
+
+	step("checking the upgraded version")
+	version, err := mysqlctl.GetServerVersion(ctx, tm.MysqlDaemon)
+	if err != nil {
+		return vterrors.Wrap(err, "can't get the MySQL version")
+	}
+	if version != to {
+		return fmt.Errorf("mysqld runs version %v after the upgrade, expected %v", version, to)
+	}
+
+	step("rejoining the shard")
+	if _, err := tm.MysqlDaemon.SetSuperReadOnly(superReadOnly); err != nil {
+		return vterrors.Wrap(err, "can't restore super_read_only")
+	}
+	// Only restore semi-sync if one of them was on, since both being off
+	// could mean the plugin isn't even loaded.
+	if semiSyncSource || semiSyncReplica {
+		if err := tm.MysqlDaemon.SetSemiSyncEnabled(semiSyncSource, semiSyncReplica); err != nil {
+			return vterrors.Wrap(err, "can't restore semi-sync settings")
+		}
+	}
+	if replicating {
+		if err := tm.MysqlDaemon.StartReplication(tm.hookExtraEnv()); err != nil {
+			return vterrors.Wrap(err, "can't restart replication")
+		}
+	}
+
+	l.Infof("Upgraded mysqld from %v to %v", from, to)
+	return nil
+}
+
+// upgradeMysqlVersions returns the version mysqld runs, and the version of
+// the binaries it is upgraded to, checking that the upgrade is possible.
+func (tm *TabletManager) upgradeMysqlVersions(ctx context.Context, mysqlRoot, expectedVersion string) (from, to mysqlctl.ServerVersion, err error) {
+	from, err = mysqlctl.GetServerVersion(ctx, tm.MysqlDaemon)
+	if err != nil {
+		return from, to, vterrors.Wrap(err, "can't get the MySQL version")
+	}
+
+	var toVersion string
+	if mysqlRoot != "" {
+		toVersion, err = mysqlctl.GetMysqldVersion(mysqlRoot)
+	} else {
+		toVersion, err = tm.MysqlDaemon.GetVersionString(ctx)
+	}
+	if err != nil {
+		return from, to, vterrors.Wrap(err, "can't get the version of the upgraded MySQL binaries")
+	}
+	if _, to, err = mysqlctl.ParseVersionString(toVersion); err != nil {
+		return from, to, err
+	}
+
+	if expectedVersion != "" {
+		expected, err := mysqlctl.ParseServerVersion(expectedVersion)
+		if err != nil {
+			return from, to, err
+		}
+		if to != expected {
+			return from, to, fmt.Errorf("the upgraded MySQL binaries are version %v, expected %v", to, expected)
+		}
+	}
+	return from, to, mysqlctl.CheckUpgrade(from, to)
+}
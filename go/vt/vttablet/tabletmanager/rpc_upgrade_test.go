@@ -0,0 +1,194 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletmanager
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestUpgradeMysql(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// A MySQL distribution whose mysqld has the given version.
+	newMysqlRoot := func(t *testing.T, version string) string {
+		root := t.TempDir()
+		require.NoError(t, os.Mkdir(path.Join(root, "bin"), 0755))
+		script := "#!/bin/sh\necho 'mysqld  Ver " + version + " for Linux on x86_64 (MySQL Community Server - GPL)'\n"
+		require.NoError(t, os.WriteFile(path.Join(root, "bin", "mysqld"), []byte(script), 0755))
+		return root
+	}
+
+	tests := []struct {
+		name          string
+		tabletType    topodatapb.TabletType
+		binaryVersion string
+		useMysqlRoot  bool
+		// mysqlRootSuffix is appended to the requested MySQL root.
+		mysqlRootSuffix string
+		// disallowMysqlRoot leaves the MySQL root out of the allowed ones.
+		disallowMysqlRoot bool
+		req               *tabletmanagerdatapb.UpgradeMysqlRequest
+		wantErr           string
+	}{
+		{
+			name:          "binaries upgraded in place",
+			tabletType:    topodatapb.TabletType_REPLICA,
+			binaryVersion: "mysqld  Ver 8.0.36 for Linux on x86_64",
+			req:           &tabletmanagerdatapb.UpgradeMysqlRequest{},
+		},
+		{
+			name:         "new mysql root",
+			tabletType:   topodatapb.TabletType_REPLICA,
+			useMysqlRoot: true,
+			req: &tabletmanagerdatapb.UpgradeMysqlRequest{
+				ExpectedVersion: "8.0.36",
+			},
+		},
+		{
+			name:            "new mysql root not clean",
+			tabletType:      topodatapb.TabletType_REPLICA,
+			useMysqlRoot:    true,
+			mysqlRootSuffix: "/bin/../",
+			req:             &tabletmanagerdatapb.UpgradeMysqlRequest{},
+		},
+		{
+			name:              "mysql root not allowed",
+			tabletType:        topodatapb.TabletType_REPLICA,
+			useMysqlRoot:      true,
+			disallowMysqlRoot: true,
+			req:               &tabletmanagerdatapb.UpgradeMysqlRequest{},
+			wantErr:           "is not allowed, it must be listed in --upgrade-mysql-allowed-roots",
+		},
+		{
+			name:            "relative mysql root",
+			tabletType:      topodatapb.TabletType_REPLICA,
+			useMysqlRoot:    true,
+			mysqlRootSuffix: "relative",
+			req:             &tabletmanagerdatapb.UpgradeMysqlRequest{},
+			wantErr:         "is not allowed, it must be listed in --upgrade-mysql-allowed-roots",
+		},
+		{
+			name:          "downgrade",
+			tabletType:    topodatapb.TabletType_REPLICA,
+			binaryVersion: "mysqld  Ver 8.0.35 for Linux on x86_64",
+			req:           &tabletmanagerdatapb.UpgradeMysqlRequest{},
+			wantErr:       "cannot downgrade MySQL from 8.0.36 to 8.0.35 in place",
+		},
+		{
+			name:          "unexpected version",
+			tabletType:    topodatapb.TabletType_REPLICA,
+			binaryVersion: "mysqld  Ver 8.0.36 for Linux on x86_64",
+			req: &tabletmanagerdatapb.UpgradeMysqlRequest{
+				ExpectedVersion: "8.0.37",
+			},
+			wantErr: "the upgraded MySQL binaries are version 8.0.36, expected 8.0.37",
+		},
+		{
+			name:          "primary",
+			tabletType:    topodatapb.TabletType_PRIMARY,
+			binaryVersion: "mysqld  Ver 8.0.36 for Linux on x86_64",
+			req:           &tabletmanagerdatapb.UpgradeMysqlRequest{},
+			wantErr:       "type PRIMARY cannot be upgraded in place",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := memorytopo.NewServer(ctx, "cell1")
+			tm := newTestTM(t, ts, 1, "ks", "0")
+			defer tm.Stop()
+			tm.Cnf = &mysqlctl.Mycnf{}
+
+			if tt.tabletType != topodatapb.TabletType_REPLICA {
+				_, err := ts.UpdateTabletFields(ctx, tm.tabletAlias, func(tablet *topodatapb.Tablet) error {
+					tablet.Type = tt.tabletType
+					return nil
+				})
+				require.NoError(t, err)
+			}
+
+			fmd := tm.MysqlDaemon.(*mysqlctl.FakeMysqlDaemon)
+			fmd.Version = tt.binaryVersion
+			fmd.Replicating = true
+			fmd.SuperReadOnly.Store(true)
+			fmd.SemiSyncReplicaEnabled = true
+			fmd.FetchSuperQueryMap = map[string]*sqltypes.Result{
+				"SELECT @@global.version":           sqltypes.MakeTestResult(sqltypes.MakeTestFields("@@global.version", "varchar"), "8.0.36"),
+				"SET GLOBAL innodb_fast_shutdown=0": {},
+			}
+			fmd.ExpectedExecuteSuperQueryList = []string{
+				"STOP SLAVE",
+				"START SLAVE",
+			}
+			var mysqlRoot string
+			if tt.useMysqlRoot {
+				mysqlRoot = newMysqlRoot(t, "8.0.36")
+				tt.req.MysqlRoot = mysqlRoot + tt.mysqlRootSuffix
+				if tt.mysqlRootSuffix == "relative" {
+					// The allowed roots must be absolute paths.
+					mysqlRoot = "relative"
+					tt.req.MysqlRoot = mysqlRoot
+				}
+				saveAllowedRoots := upgradeMysqlAllowedRoots
+				defer func() { upgradeMysqlAllowedRoots = saveAllowedRoots }()
+				upgradeMysqlAllowedRoots = []string{"/usr/local/mysql"}
+				if !tt.disallowMysqlRoot {
+					upgradeMysqlAllowedRoots = append(upgradeMysqlAllowedRoots, mysqlRoot)
+				}
+			}
+
+			logger := logutil.NewMemoryLogger()
+			err := tm.UpgradeMysql(ctx, logger, tt.req)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				// Nothing was changed.
+				assert.True(t, fmd.Running)
+				assert.True(t, fmd.Replicating)
+				assert.Zero(t, fmd.ExpectedExecuteSuperQueryCurrent)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Contains(t, logger.String(), "UpgradeMysql step 10/10: rejoining the shard")
+			assert.Contains(t, logger.String(), "Upgraded mysqld from 8.0.36 to 8.0.36")
+			assert.NoError(t, fmd.CheckSuperQueryList())
+			assert.True(t, fmd.Running)
+			assert.True(t, fmd.Replicating)
+			assert.True(t, fmd.SuperReadOnly.Load())
+			assert.True(t, fmd.SemiSyncReplicaEnabled)
+			assert.Equal(t, mysqlRoot, fmd.MysqlRoot)
+
+			tablet, err := ts.GetTablet(ctx, tm.tabletAlias)
+			require.NoError(t, err)
+			assert.Equal(t, topodatapb.TabletType_REPLICA, tablet.Type)
+		})
+	}
+}
@@ -47,9 +47,9 @@ const (
 	// Delete VReplication records for the given workflow.
 	sqlDeleteVReplicationWorkflow = "delete from %s.vreplication where workflow = %a and db_name = %a"
 	// Retrieve the current configuration values for a workflow's vreplication stream.
-	sqlSelectVReplicationWorkflowConfig = "select id, source, cell, tablet_types, state, message from %s.vreplication where workflow = %a"
+	sqlSelectVReplicationWorkflowConfig = "select id, source, cell, tablet_types, state, message, max_replication_lag from %s.vreplication where workflow = %a"
 	// Update the configuration values for a workflow's vreplication stream.
-	sqlUpdateVReplicationWorkflowConfig = "update %s.vreplication set state = %a, source = %a, cell = %a, tablet_types = %a where id = %a"
+	sqlUpdateVReplicationWorkflowConfig = "update %s.vreplication set state = %a, source = %a, cell = %a, tablet_types = %a, max_replication_lag = %a where id = %a"
 )
 
 func (tm *TabletManager) CreateVReplicationWorkflow(ctx context.Context, req *tabletmanagerdatapb.CreateVReplicationWorkflowRequest) (*tabletmanagerdatapb.CreateVReplicationWorkflowResponse, error) {
@@ -269,6 +269,7 @@ func (tm *TabletManager) UpdateVReplicationWorkflow(ctx context.Context, req *ta
 	source := row.AsBytes("source", []byte{})
 	state := row.AsString("state", "")
 	message := row.AsString("message", "")
+	maxReplicationLag := row.AsInt64("max_replication_lag", 0)
 	if req.State == binlogdatapb.VReplicationWorkflowState_Running && strings.ToUpper(message) == workflow.Frozen {
 		return &tabletmanagerdatapb.UpdateVReplicationWorkflowResponse{Result: nil},
 			vterrors.New(vtrpcpb.Code_FAILED_PRECONDITION, "cannot start a workflow when it is frozen")
@@ -302,14 +303,21 @@ func (tm *TabletManager) UpdateVReplicationWorkflow(ctx context.Context, req *ta
 	if !textutil.ValueIsSimulatedNull(req.State) {
 		state = binlogdatapb.VReplicationWorkflowState_name[int32(req.State)]
 	}
+	if !textutil.ValueIsSimulatedNull(req.MaxReplicationLag) {
+		if req.MaxReplicationLag < 0 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid max replication lag: %d", req.MaxReplicationLag)
+		}
+		maxReplicationLag = req.MaxReplicationLag
+	}
 	bindVars = map[string]*querypb.BindVariable{
 		"st": sqltypes.StringBindVariable(state),
 		"sc": sqltypes.StringBindVariable(string(source)),
 		"cl": sqltypes.StringBindVariable(strings.Join(cells, ",")),
 		"tt": sqltypes.StringBindVariable(tabletTypesStr),
+		"ml": sqltypes.Int64BindVariable(maxReplicationLag),
 		"id": sqltypes.Int64BindVariable(id),
 	}
-	parsed = sqlparser.BuildParsedQuery(sqlUpdateVReplicationWorkflowConfig, sidecar.GetIdentifier(), ":st", ":sc", ":cl", ":tt", ":id")
+	parsed = sqlparser.BuildParsedQuery(sqlUpdateVReplicationWorkflowConfig, sidecar.GetIdentifier(), ":st", ":sc", ":cl", ":tt", ":ml", ":id")
 	stmt, err = parsed.GenerateQuery(bindVars, nil)
 	if err != nil {
 		return nil, err
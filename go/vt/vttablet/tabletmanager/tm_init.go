@@ -753,6 +753,10 @@ func (tm *TabletManager) initTablet(ctx context.Context) error {
 		}
 		log.Infof("Successfully updated tablet replication data for alias: %v", topoproto.TabletAliasString(tablet.Alias))
 
+		// Keep the tags that were added to the record through vtctld, but
+		// apply our own tags again.
+		tablet.Tags = mergeTags(oldTablet.Tags, tablet.Tags)
+
 		// Then overwrite everything, ignoring version mismatch.
 		if err := tm.TopoServer.UpdateTablet(ctx, topo.NewTabletInfo(tablet, nil)); err != nil {
 			return vterrors.Wrap(err, "UpdateTablet failed")
@@ -19,6 +19,7 @@ package tabletmanager
 import (
 	"context"
 	"fmt"
+	"maps"
 	"strings"
 	"sync"
 	"syscall"
@@ -386,15 +387,7 @@ func (ts *tmState) publishStateLocked(ctx context.Context) {
 	// Fast path: publish immediately.
 	ctx, cancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
 	defer cancel()
-	_, err := ts.tm.TopoServer.UpdateTabletFields(ctx, ts.tm.tabletAlias, func(tablet *topodatapb.Tablet) error {
-		if err := topotools.CheckOwnership(tablet, ts.tablet); err != nil {
-			log.Error(err)
-			return topo.NewError(topo.NoUpdateNeeded, "")
-		}
-		proto.Reset(tablet)
-		proto.Merge(tablet, ts.tablet)
-		return nil
-	})
+	_, err := ts.tm.TopoServer.UpdateTabletFields(ctx, ts.tm.tabletAlias, ts.updateTabletRecord)
 	if err != nil {
 		if topo.IsErrType(err, topo.NoNode) { // Someone deleted the tablet record under us. Shut down gracefully.
 			log.Error("Tablet record has disappeared, shutting down")
@@ -408,6 +401,25 @@ func (ts *tmState) publishStateLocked(ctx context.Context) {
 	}
 }
 
+// updateTabletRecord overwrites the tablet record with the tablet, unless
+// another tablet took over the record. The tags of the record are kept, since
+// they can be changed through vtctld once the tablet is running.
+func (ts *tmState) updateTabletRecord(tablet *topodatapb.Tablet) error {
+	if err := topotools.CheckOwnership(tablet, ts.tablet); err != nil {
+		log.Error(err)
+		return topo.NewError(topo.NoUpdateNeeded, "")
+	}
+	tags := tablet.Tags
+	proto.Reset(tablet)
+	proto.Merge(tablet, ts.tablet)
+	tablet.Tags = tags
+	if !maps.Equal(ts.tablet.Tags, tags) {
+		ts.tablet.Tags = maps.Clone(tags)
+		ts.publishForDisplay()
+	}
+	return nil
+}
+
 func (ts *tmState) retryPublish() {
 	ts.mu.Lock()
 	defer ts.mu.Unlock()
@@ -418,15 +430,7 @@ func (ts *tmState) retryPublish() {
 		// Retry immediately the first time because the previous failure might have been
 		// due to an expired context.
 		ctx, cancel := context.WithTimeout(ts.ctx, topo.RemoteOperationTimeout)
-		_, err := ts.tm.TopoServer.UpdateTabletFields(ctx, ts.tm.tabletAlias, func(tablet *topodatapb.Tablet) error {
-			if err := topotools.CheckOwnership(tablet, ts.tablet); err != nil {
-				log.Error(err)
-				return topo.NewError(topo.NoUpdateNeeded, "")
-			}
-			proto.Reset(tablet)
-			proto.Merge(tablet, ts.tablet)
-			return nil
-		})
+		_, err := ts.tm.TopoServer.UpdateTabletFields(ctx, ts.tm.tabletAlias, ts.updateTabletRecord)
 		cancel()
 		if err != nil {
 			if topo.IsErrType(err, topo.NoNode) { // Someone deleted the tablet record under us. Shut down gracefully.
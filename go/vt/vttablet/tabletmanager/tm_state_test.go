@@ -576,6 +576,30 @@ func TestPublishStateNew(t *testing.T) {
 	utils.MustMatch(t, tab2, ttablet.Tablet)
 }
 
+func TestPublishStateKeepsTags(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	tm := newTestTM(t, ts, 42, "ks", "0")
+	defer tm.Stop()
+
+	// Tags added to the record through vtctld while the tablet is running.
+	_, err := ts.UpdateTabletFields(ctx, tm.tabletAlias, func(tablet *topodatapb.Tablet) error {
+		tablet.Tags = map[string]string{"zone": "az1"}
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = tm.tmState.ChangeTabletType(ctx, topodatapb.TabletType_RDONLY, DBActionNone)
+	require.NoError(t, err)
+
+	ttablet, err := ts.GetTablet(ctx, tm.tabletAlias)
+	require.NoError(t, err)
+	assert.Equal(t, topodatapb.TabletType_RDONLY, ttablet.Type)
+	assert.Equal(t, map[string]string{"zone": "az1"}, ttablet.Tags)
+	assert.Equal(t, map[string]string{"zone": "az1"}, tm.Tablet().Tags)
+}
+
 func TestPublishDeleted(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
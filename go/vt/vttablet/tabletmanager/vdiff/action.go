@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 
@@ -170,6 +171,16 @@ func (vde *Engine) handleCreateResumeAction(ctx context.Context, dbClient binlog
 			return fmt.Errorf("vdiff found with invalid id on tablet %v: %w",
 				vde.thisTablet.Alias, err)
 		}
+		if req.UpdateOptions {
+			var running bool
+			if options, running, err = vde.updateOptions(ctx, dbClient, resp.Id, options); err != nil {
+				return err
+			}
+			if running {
+				resp.VdiffUuid = req.VdiffUuid
+				return nil
+			}
+		}
 	}
 	if options, err = vde.fixupOptions(options); err != nil {
 		return err
@@ -234,6 +245,62 @@ func (vde *Engine) handleCreateResumeAction(ctx context.Context, dbClient binlog
 	return nil
 }
 
+// updateOptions applies the non-zero max diff duration and parallelism of the
+// update to the options of a vdiff. If the vdiff is running, its new options
+// are saved and applied to it right away, and true is returned. Otherwise the
+// new options are returned, for the vdiff to be resumed with them.
+func (vde *Engine) updateOptions(ctx context.Context, dbClient binlogplayer.DBClient, id int64, update *tabletmanagerdatapb.VDiffOptions) (*tabletmanagerdatapb.VDiffOptions, bool, error) {
+	qr, err := vde.getVDiffByID(ctx, dbClient, id)
+	if err != nil {
+		return nil, false, err
+	}
+	row := qr.Named().Row()
+	options := &tabletmanagerdatapb.VDiffOptions{}
+	if err := json.Unmarshal(row.AsBytes("options", []byte("{}")), options); err != nil {
+		return nil, false, err
+	}
+	if options.CoreOptions == nil {
+		options.CoreOptions = &tabletmanagerdatapb.VDiffCoreOptions{}
+	}
+	if options.PickerOptions == nil {
+		options.PickerOptions = &tabletmanagerdatapb.VDiffPickerOptions{}
+	}
+	if update.GetCoreOptions().GetMaxDiffSeconds() != 0 {
+		options.CoreOptions.MaxDiffSeconds = update.CoreOptions.MaxDiffSeconds
+	}
+	if update.GetCoreOptions().GetParallelism() != 0 {
+		options.CoreOptions.Parallelism = update.CoreOptions.Parallelism
+	}
+
+	switch VDiffState(strings.ToLower(row.AsString("state", ""))) {
+	case PendingState, StartedState:
+	default:
+		return options, false, nil
+	}
+	optionsJSON, err := json.Marshal(options)
+	if err != nil {
+		return nil, false, err
+	}
+	query, err := sqlparser.ParseAndBind(sqlUpdateVDiffOptions,
+		sqltypes.StringBindVariable(string(optionsJSON)),
+		sqltypes.Int64BindVariable(id),
+	)
+	if err != nil {
+		return nil, false, err
+	}
+	if _, err := dbClient.ExecuteFetch(query, 1); err != nil {
+		return nil, false, err
+	}
+	vde.mu.Lock()
+	defer vde.mu.Unlock()
+	if ct, ok := vde.controllers[id]; ok {
+		ct.updateOptions(options.CoreOptions)
+	}
+	insertVDiffLog(ctx, dbClient, id, fmt.Sprintf("Options updated: max_diff_seconds=%d, parallelism=%d",
+		options.CoreOptions.MaxDiffSeconds, options.CoreOptions.Parallelism))
+	return options, true, nil
+}
+
 func (vde *Engine) handleShowAction(ctx context.Context, dbClient binlogplayer.DBClient, action VDiffAction, req *tabletmanagerdatapb.VDiffRequest, resp *tabletmanagerdatapb.VDiffResponse) error {
 	var qr *sqltypes.Result
 	var err error
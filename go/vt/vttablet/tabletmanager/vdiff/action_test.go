@@ -24,9 +24,12 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/vterrors"
 )
@@ -97,3 +100,64 @@ func TestPerformVDiffAction(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateVDiffOptions(t *testing.T) {
+	ctx := context.Background()
+	uuid := uuid.New().String()
+	update := &tabletmanagerdatapb.VDiffOptions{
+		CoreOptions: &tabletmanagerdatapb.VDiffCoreOptions{
+			Parallelism: 4,
+		},
+	}
+	tests := []struct {
+		state       VDiffState
+		wantRunning bool
+	}{
+		{state: StartedState, wantRunning: true},
+		{state: PendingState, wantRunning: true},
+		{state: StoppedState, wantRunning: false},
+		{state: CompletedState, wantRunning: false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.state), func(t *testing.T) {
+			dbClient := binlogplayer.NewMockDBClient(t)
+			ct := &controller{
+				id: 1,
+				options: &tabletmanagerdatapb.VDiffOptions{
+					CoreOptions: &tabletmanagerdatapb.VDiffCoreOptions{Tables: "t1", MaxDiffSeconds: 3600},
+				},
+				optionsUpdated: make(chan struct{}, 1),
+			}
+			vde := &Engine{
+				controllers: map[int64]*controller{1: ct},
+				thisTablet:  &topodatapb.Tablet{},
+			}
+			dbClient.ExpectRequest("select * from _vt.vdiff where id = 1", sqltypes.MakeTestResult(sqltypes.MakeTestFields(
+				vdiffTestCols,
+				vdiffTestColTypes,
+			),
+				fmt.Sprintf(`1|%s|wf|ks|0|vt_ks|%s|{"core_options": {"tables": "t1", "max_diff_seconds": 3600}}|`, uuid, tt.state),
+			), nil)
+			if tt.wantRunning {
+				dbClient.ExpectRequest(`update _vt.vdiff set options = '{\"picker_options\":{},\"core_options\":{\"tables\":\"t1\",\"max_diff_seconds\":3600,\"parallelism\":4}}' where id = 1`, singleRowAffected, nil)
+				dbClient.ExpectRequest("insert into _vt.vdiff_log(vdiff_id, message) values (1, 'Options updated: max_diff_seconds=3600, parallelism=4')", singleRowAffected, nil)
+			}
+
+			options, running, err := vde.updateOptions(ctx, dbClient, 1, update)
+			require.NoError(t, err)
+			dbClient.Wait()
+			require.Equal(t, tt.wantRunning, running)
+			require.Equal(t, "t1", options.CoreOptions.Tables)
+			require.Equal(t, int64(3600), options.CoreOptions.MaxDiffSeconds)
+			require.Equal(t, int64(4), options.CoreOptions.Parallelism)
+			if tt.wantRunning {
+				require.Equal(t, 4, ct.parallelism())
+				require.Equal(t, time.Hour, ct.maxDiffDuration())
+				require.Len(t, ct.optionsUpdated, 1)
+			} else {
+				require.Equal(t, 1, ct.parallelism())
+				require.Len(t, ct.optionsUpdated, 0)
+			}
+		})
+	}
+}
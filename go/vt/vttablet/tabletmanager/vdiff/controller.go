@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"vitess.io/vitess/go/mysql/replication"
@@ -64,14 +65,18 @@ type controller struct {
 	vde             *Engine // the singleton vdiff engine
 	done            chan struct{}
 
-	sources        map[string]*migrationSource // currently picked source tablets for this shard's data
+	sources        map[string]*migrationSource // source shards and streams of this shard's data, copied by each table differ
 	workflowFilter string
 	sourceKeyspace string
 	tmc            tmclient.TabletManagerClient
 
-	targetShardStreamer *shardStreamer
-	filter              *binlogdatapb.Filter            // vreplication row filter
-	options             *tabletmanagerdata.VDiffOptions // options initially from vtctld command and later from _vt.vdiff
+	filter  *binlogdatapb.Filter            // vreplication row filter
+	options *tabletmanagerdata.VDiffOptions // options initially from vtctld command and later from _vt.vdiff
+
+	// optionsMu guards the options that can be updated while the vdiff runs.
+	optionsMu sync.Mutex
+	// optionsUpdated is signaled when the options are updated while the vdiff runs.
+	optionsUpdated chan struct{}
 
 	sourceTimeZone, targetTimeZone string // named time zones if conversions are necessary for datetime values
 
@@ -95,6 +100,7 @@ func newController(ctx context.Context, row sqltypes.RowNamedValues, dbClientFac
 		tmc:             vde.tmClientFactory(),
 		sources:         make(map[string]*migrationSource),
 		options:         options,
+		optionsUpdated:  make(chan struct{}, 1),
 	}
 	ctx, ct.cancel = context.WithCancel(ctx)
 	go ct.run(ctx)
@@ -102,6 +108,38 @@ func newController(ctx context.Context, row sqltypes.RowNamedValues, dbClientFac
 	return ct, nil
 }
 
+// updateOptions applies the options that can be updated while the vdiff runs.
+// A new max diff duration applies to the table diffs that are running, and a
+// new parallelism to the table diffs that are started next.
+func (ct *controller) updateOptions(coreOptions *tabletmanagerdata.VDiffCoreOptions) {
+	ct.optionsMu.Lock()
+	ct.options.CoreOptions.MaxDiffSeconds = coreOptions.MaxDiffSeconds
+	ct.options.CoreOptions.Parallelism = coreOptions.Parallelism
+	ct.optionsMu.Unlock()
+	select {
+	case ct.optionsUpdated <- struct{}{}:
+	default:
+	}
+}
+
+// maxDiffDuration returns how long a table is diffed before its diff is
+// restarted from its last checkpoint, or 0 if there is no limit.
+func (ct *controller) maxDiffDuration() time.Duration {
+	ct.optionsMu.Lock()
+	defer ct.optionsMu.Unlock()
+	return time.Duration(ct.options.CoreOptions.MaxDiffSeconds) * time.Second
+}
+
+// parallelism returns the number of tables diffed at the same time.
+func (ct *controller) parallelism() int {
+	ct.optionsMu.Lock()
+	defer ct.optionsMu.Unlock()
+	if ct.options.CoreOptions.Parallelism < 1 {
+		return 1
+	}
+	return int(ct.options.CoreOptions.Parallelism)
+}
+
 func (ct *controller) Stop() {
 	ct.cancel()
 	<-ct.done
@@ -52,6 +52,10 @@ import (
 // how long to wait for background operations to complete
 var BackgroundOperationTimeout = topo.RemoteOperationTimeout * 4
 
+// errMaxDiffDurationExceeded is returned by a table diff that ran for longer than
+// the max diff duration, so that it is restarted from its last checkpoint.
+var errMaxDiffDurationExceeded = vterrors.Errorf(vtrpcpb.Code_DEADLINE_EXCEEDED, "table diff was stopped due to exceeding the max-diff-duration time")
+
 // compareColInfo contains the metadata for a column of the table being diffed
 type compareColInfo struct {
 	colIndex  int           // index of the column in the filter's select
@@ -73,10 +77,22 @@ type tableDiffer struct {
 	sourceQuery string
 	table       *tabletmanagerdatapb.TableDefinition
 	lastPK      *querypb.QueryResult
+
+	// sources and targetShardStreamer stream the rows of this table, so that
+	// several tables can be diffed at the same time.
+	sources             map[string]*migrationSource
+	targetShardStreamer *shardStreamer
 }
 
 func newTableDiffer(wd *workflowDiffer, table *tabletmanagerdatapb.TableDefinition, sourceQuery string) *tableDiffer {
-	return &tableDiffer{wd: wd, table: table, sourceQuery: sourceQuery}
+	sources := make(map[string]*migrationSource, len(wd.ct.sources))
+	for shard, source := range wd.ct.sources {
+		sources[shard] = &migrationSource{
+			shardStreamer: &shardStreamer{shard: source.shard},
+			vrID:          source.vrID,
+		}
+	}
+	return &tableDiffer{wd: wd, table: table, sourceQuery: sourceQuery, sources: sources}
 }
 
 // initialize
@@ -175,17 +191,16 @@ func (td *tableDiffer) stopTargetVReplicationStreams(ctx context.Context, dbClie
 		if err := prototext.Unmarshal(sourceBytes, &bls); err != nil {
 			return err
 		}
-		ct.sources[bls.Shard].position = mpos
+		td.sources[bls.Shard].position = mpos
 	}
 
 	return nil
 }
 
 func (td *tableDiffer) forEachSource(cb func(source *migrationSource) error) error {
-	ct := td.wd.ct
 	var wg sync.WaitGroup
 	allErrors := &concurrency.AllErrorRecorder{}
-	for _, source := range ct.sources {
+	for _, source := range td.sources {
 		wg.Add(1)
 		go func(source *migrationSource) {
 			defer wg.Done()
@@ -236,7 +251,7 @@ func (td *tableDiffer) selectTablets(ctx context.Context, cell, tabletTypes stri
 		if err2 != nil {
 			return
 		}
-		ct.targetShardStreamer = &shardStreamer{
+		td.targetShardStreamer = &shardStreamer{
 			tablet: tablet,
 			shard:  tablet.Shard,
 		}
@@ -298,16 +313,15 @@ func (td *tableDiffer) syncTargetStreams(ctx context.Context) error {
 }
 
 func (td *tableDiffer) startTargetDataStream(ctx context.Context) error {
-	ct := td.wd.ct
 	gtidch := make(chan string, 1)
-	ct.targetShardStreamer.result = make(chan *sqltypes.Result, 1)
-	go td.streamOneShard(ctx, ct.targetShardStreamer, td.tablePlan.targetQuery, td.lastPK, gtidch)
+	td.targetShardStreamer.result = make(chan *sqltypes.Result, 1)
+	go td.streamOneShard(ctx, td.targetShardStreamer, td.tablePlan.targetQuery, td.lastPK, gtidch)
 	gtid, ok := <-gtidch
 	if !ok {
-		log.Infof("streaming error: %v", ct.targetShardStreamer.err)
-		return ct.targetShardStreamer.err
+		log.Infof("streaming error: %v", td.targetShardStreamer.err)
+		return td.targetShardStreamer.err
 	}
-	ct.targetShardStreamer.snapshotPosition = gtid
+	td.targetShardStreamer.snapshotPosition = gtid
 	return nil
 }
 
@@ -412,14 +426,14 @@ func (td *tableDiffer) streamOneShard(ctx context.Context, participant *shardStr
 func (td *tableDiffer) setupRowSorters() {
 	// Combine all sources into a slice and create a merge sorter for it.
 	sources := make(map[string]*shardStreamer)
-	for shard, source := range td.wd.ct.sources {
+	for shard, source := range td.sources {
 		sources[shard] = source.shardStreamer
 	}
 	td.sourcePrimitive = newMergeSorter(sources, td.tablePlan.comparePKs)
 
 	// Create a merge sorter for the target.
 	targets := make(map[string]*shardStreamer)
-	targets[td.wd.ct.targetShardStreamer.shard] = td.wd.ct.targetShardStreamer
+	targets[td.targetShardStreamer.shard] = td.targetShardStreamer
 	td.targetPrimitive = newMergeSorter(targets, td.tablePlan.comparePKs)
 
 	// If there were aggregate expressions, we have to re-aggregate
@@ -476,6 +490,7 @@ func (td *tableDiffer) diff(ctx context.Context, rowsToCompare int64, debug, onl
 	var sourceRow, lastProcessedRow, targetRow []sqltypes.Value
 	advanceSource := true
 	advanceTarget := true
+	startedAt := time.Now()
 
 	// Save our progress when we finish the run
 	defer func() {
@@ -493,6 +508,14 @@ func (td *tableDiffer) diff(ctx context.Context, rowsToCompare int64, debug, onl
 		default:
 		}
 
+		// The diff can only be restarted from the last processed row when
+		// both the source and the target rows have been processed.
+		if advanceSource && advanceTarget {
+			if maxDuration := td.wd.ct.maxDiffDuration(); maxDuration > 0 && time.Since(startedAt) > maxDuration {
+				return dr, errMaxDiffDurationExceeded
+			}
+		}
+
 		if !mismatch && dr.MismatchedRows > 0 {
 			mismatch = true
 			log.Infof("Flagging mismatch for %s: %+v", td.table.Name, dr)
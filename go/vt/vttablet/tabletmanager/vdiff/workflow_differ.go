@@ -18,12 +18,15 @@ package vdiff
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
 	"google.golang.org/protobuf/encoding/prototext"
 
+	"vitess.io/vitess/go/maps2"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/binlog/binlogplayer"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
@@ -144,14 +147,22 @@ func (wd *workflowDiffer) diffTable(ctx context.Context, dbClient binlogplayer.D
 	if err := td.updateTableState(ctx, dbClient, StartedState); err != nil {
 		return err
 	}
-	if err := td.initialize(ctx); err != nil {
-		return err
-	}
-	log.Infof("Table initialization done on table %s for vdiff %s", td.table.Name, wd.ct.uuid)
-	dr, err := td.diff(ctx, wd.opts.CoreOptions.MaxRows, wd.opts.ReportOptions.DebugQuery, wd.opts.ReportOptions.OnlyPks, wd.opts.CoreOptions.MaxExtraRowsToCompare)
-	if err != nil {
-		log.Errorf("Encountered an error diffing table %s for vdiff %s: %v", td.table.Name, wd.ct.uuid, err)
-		return err
+	var dr *DiffReport
+	for {
+		var err error
+		dr, err = wd.diffTableRun(ctx, td)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, errMaxDiffDurationExceeded) {
+			log.Errorf("Encountered an error diffing table %s for vdiff %s: %v", td.table.Name, wd.ct.uuid, err)
+			return err
+		}
+		// Release the snapshots, and continue from the last checkpoint with new ones.
+		log.Infof("Restarting the diff of table %s for vdiff %s from its last checkpoint: %v", td.table.Name, wd.ct.uuid, err)
+		if td.lastPK, err = wd.getTableLastPK(dbClient, td.table.Name); err != nil {
+			return err
+		}
 	}
 	log.Infof("Table diff done on table %s for vdiff %s with report: %+v", td.table.Name, wd.ct.uuid, dr)
 	if dr.ExtraRowsSource > 0 || dr.ExtraRowsTarget > 0 {
@@ -174,6 +185,18 @@ func (wd *workflowDiffer) diffTable(ctx context.Context, dbClient binlogplayer.D
 	return nil
 }
 
+// diffTableRun diffs a table from its last checkpoint with new snapshots of the
+// source and target shards, which are released when the run ends.
+func (wd *workflowDiffer) diffTableRun(ctx context.Context, td *tableDiffer) (*DiffReport, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if err := td.initialize(ctx); err != nil {
+		return nil, err
+	}
+	log.Infof("Table initialization done on table %s for vdiff %s", td.table.Name, wd.ct.uuid)
+	return td.diff(ctx, wd.opts.CoreOptions.MaxRows, wd.opts.ReportOptions.DebugQuery, wd.opts.ReportOptions.OnlyPks, wd.opts.CoreOptions.MaxExtraRowsToCompare)
+}
+
 func (wd *workflowDiffer) diff(ctx context.Context) error {
 	dbClient := wd.ct.dbClientFactory()
 	if err := dbClient.Connect(); err != nil {
@@ -199,44 +222,91 @@ func (wd *workflowDiffer) diff(ctx context.Context) error {
 	if err := wd.initVDiffTables(dbClient); err != nil {
 		return err
 	}
-	for _, td := range wd.tableDiffers {
-		select {
-		case <-ctx.Done():
-			return vterrors.Errorf(vtrpcpb.Code_CANCELED, "context has expired")
-		default:
-		}
-		query, err := sqlparser.ParseAndBind(sqlGetVDiffTable,
-			sqltypes.Int64BindVariable(wd.ct.id),
-			sqltypes.StringBindVariable(td.table.Name),
-		)
-		if err != nil {
-			return err
-		}
-		qr, err := dbClient.ExecuteFetch(query, 1)
-		if err != nil {
-			return err
-		}
-		if len(qr.Rows) == 0 {
-			return fmt.Errorf("no vdiff table found for %s on tablet %v",
-				td.table.Name, wd.ct.vde.thisTablet.Alias)
-		}
+	tableNames := maps2.Keys(wd.tableDiffers)
+	sort.Strings(tableNames)
 
-		log.Infof("Starting diff of table %s for vdiff %s", td.table.Name, wd.ct.uuid)
-		if err := wd.diffTable(ctx, dbClient, td); err != nil {
-			if err := td.updateTableState(ctx, dbClient, ErrorState); err != nil {
-				return err
+	// The tables are diffed in parallel, and the parallelism can be updated
+	// while they are diffed. A failure stops the diffs of the other tables.
+	diffCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make(chan error)
+	running := 0
+	var diffErr error
+	for next := 0; next < len(tableNames) || running > 0; {
+		if diffErr == nil && next < len(tableNames) && running < wd.ct.parallelism() {
+			td := wd.tableDiffers[tableNames[next]]
+			go func() {
+				results <- wd.diffTableAndUpdateState(diffCtx, td)
+			}()
+			next++
+			running++
+			continue
+		}
+		select {
+		case err := <-results:
+			running--
+			if err != nil && diffErr == nil {
+				diffErr = err
+				cancel()
 			}
-			insertVDiffLog(ctx, dbClient, wd.ct.id, fmt.Sprintf("Table %s Error: %s", td.table.Name, err))
-			return err
+		case <-wd.ct.optionsUpdated:
 		}
-		if err := td.updateTableState(ctx, dbClient, CompletedState); err != nil {
+	}
+	if diffErr != nil {
+		return diffErr
+	}
+	if err := wd.markIfCompleted(ctx, dbClient); err != nil {
+		return err
+	}
+	return nil
+}
+
+// diffTableAndUpdateState diffs a table, unless it was already completed before
+// the vdiff was restarted, and records its state.
+func (wd *workflowDiffer) diffTableAndUpdateState(ctx context.Context, td *tableDiffer) error {
+	dbClient := wd.ct.dbClientFactory()
+	if err := dbClient.Connect(); err != nil {
+		return err
+	}
+	defer dbClient.Close()
+
+	select {
+	case <-ctx.Done():
+		return vterrors.Errorf(vtrpcpb.Code_CANCELED, "context has expired")
+	default:
+	}
+	query, err := sqlparser.ParseAndBind(sqlGetVDiffTable,
+		sqltypes.Int64BindVariable(wd.ct.id),
+		sqltypes.StringBindVariable(td.table.Name),
+	)
+	if err != nil {
+		return err
+	}
+	qr, err := dbClient.ExecuteFetch(query, 1)
+	if err != nil {
+		return err
+	}
+	if len(qr.Rows) == 0 {
+		return fmt.Errorf("no vdiff table found for %s on tablet %v",
+			td.table.Name, wd.ct.vde.thisTablet.Alias)
+	}
+	if VDiffState(qr.Named().Row().AsString("state", "")) == CompletedState {
+		log.Infof("Skipping the diff of table %s for vdiff %s, which was already completed", td.table.Name, wd.ct.uuid)
+		return nil
+	}
+
+	log.Infof("Starting diff of table %s for vdiff %s", td.table.Name, wd.ct.uuid)
+	if err := wd.diffTable(ctx, dbClient, td); err != nil {
+		if err := td.updateTableState(ctx, dbClient, ErrorState); err != nil {
 			return err
 		}
-		log.Infof("Completed diff of table %s for vdiff %s", td.table.Name, wd.ct.uuid)
+		insertVDiffLog(ctx, dbClient, wd.ct.id, fmt.Sprintf("Table %s Error: %s", td.table.Name, err))
+		return err
 	}
-	if err := wd.markIfCompleted(ctx, dbClient); err != nil {
+	if err := td.updateTableState(ctx, dbClient, CompletedState); err != nil {
 		return err
 	}
+	log.Infof("Completed diff of table %s for vdiff %s", td.table.Name, wd.ct.uuid)
 	return nil
 }
 
@@ -580,7 +580,7 @@ func TestBuildPlanInclude(t *testing.T) {
 		wd, err := newWorkflowDiffer(ct, vdiffenv.opts)
 		require.NoError(t, err)
 		for _, table := range tcase.tables {
-			query := fmt.Sprintf(`select vdt.lastpk as lastpk, vdt.mismatch as mismatch, vdt.report as report
+			query := fmt.Sprintf(`select vdt.lastpk as lastpk, vdt.mismatch as mismatch, vdt.report as report, vdt.state as state
 						from _vt.vdiff as vd inner join _vt.vdiff_table as vdt on (vd.id = vdt.vdiff_id)
 						where vdt.vdiff_id = 1 and vdt.table_name = '%s'`, table)
 			dbc.ExpectRequest(query, noResults, nil)
@@ -61,6 +61,13 @@ type controller struct {
 	stopPos      string
 	tabletPicker *discovery.TabletPicker
 
+	// vtgateAddress is the vtgate of the external Vitess cluster of the source,
+	// if the binlog events are streamed through it, in the cells and with the
+	// tablet types of the tablet picker.
+	vtgateAddress     string
+	vtgateCells       string
+	vtgateTabletTypes string
+
 	cancel context.CancelFunc
 	done   chan struct{}
 
@@ -130,6 +137,13 @@ func newController(ctx context.Context, params map[string]string, dbClientFactor
 			if err != nil {
 				return nil, err
 			}
+			vc, err := ts.GetExternalVitessCluster(ctx, ct.source.ExternalCluster)
+			if err != nil {
+				return nil, err
+			}
+			if vc.VtgateAddress != "" {
+				ct.vtgateAddress, ct.vtgateCells, ct.vtgateTabletTypes = vc.VtgateAddress, cell, tabletTypesStr
+			}
 		}
 		tp, err := discovery.NewTabletPicker(ctx, sourceTopo, cells, ct.vre.cell, ct.source.Keyspace, ct.source.Shard, tabletTypesStr, discovery.TabletPickerOptions{})
 		if err != nil {
@@ -242,8 +256,10 @@ func (ct *controller) runBlp(ctx context.Context) (err error) {
 			if err != nil {
 				return err
 			}
+		} else if ct.vtgateAddress != "" {
+			vsClient = newVTGateConnector(ct.vtgateAddress, tablet, ct.vtgateTabletTypes, ct.vtgateCells)
 		} else {
-			vsClient = newTabletConnector(tablet)
+			vsClient = newTabletConnector(tablet, ct.blpStats)
 		}
 		if err := vsClient.Open(ctx); err != nil {
 			return err
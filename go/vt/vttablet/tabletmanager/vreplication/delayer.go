@@ -0,0 +1,153 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"context"
+	"time"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/log"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+)
+
+// eventDelayer holds back the events of the stream of a DelayedReplica
+// workflow until they are older than its delay. The events up to the
+// fast-forward position or timestamp of the stream are not held back.
+//
+// The events are only held back at transaction boundaries, so that the
+// vplayer never keeps a transaction open while waiting. While it waits,
+// the vstreamer of the source waits too.
+type eventDelayer struct {
+	id                   int32
+	delay                time.Duration
+	fastForwardPos       replication.Position
+	fastForwardTimestamp int64
+
+	// pos is the position of the last transaction sent to the relay log.
+	pos           replication.Position
+	inTransaction bool
+	fastForwarded bool
+
+	now func() time.Time
+}
+
+// newEventDelayer returns the delayer of the events of a stream, or nil if
+// the stream has no delay.
+func newEventDelayer(id int32, source *binlogdatapb.BinlogSource, startPos replication.Position) (*eventDelayer, error) {
+	if source.DelaySeconds <= 0 {
+		return nil, nil
+	}
+	d := &eventDelayer{
+		id:                   id,
+		delay:                time.Duration(source.DelaySeconds) * time.Second,
+		fastForwardTimestamp: source.FastForwardTimestamp,
+		pos:                  startPos,
+		now:                  time.Now,
+	}
+	if source.FastForwardPosition != "" {
+		pos, err := binlogplayer.DecodePosition(source.FastForwardPosition)
+		if err != nil {
+			return nil, err
+		}
+		d.fastForwardPos = pos
+	}
+	return d, nil
+}
+
+// send sends the events with the send function as they become due.
+func (d *eventDelayer) send(ctx context.Context, events []*binlogdatapb.VEvent, send func([]*binlogdatapb.VEvent) error) error {
+	received := d.now()
+	start := 0
+	for i, event := range events {
+		if wait := d.wait(event); wait > 0 {
+			if i > start {
+				if err := send(d.adjustCurrentTime(events[start:i], received)); err != nil {
+					return err
+				}
+				start = i
+			}
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+		switch event.Type {
+		case binlogdatapb.VEventType_BEGIN:
+			d.inTransaction = true
+		case binlogdatapb.VEventType_COMMIT, binlogdatapb.VEventType_ROLLBACK:
+			d.inTransaction = false
+		case binlogdatapb.VEventType_GTID:
+			pos, err := binlogplayer.DecodePosition(event.Gtid)
+			if err != nil {
+				return err
+			}
+			d.pos = pos
+		}
+	}
+	if start == len(events) {
+		return nil
+	}
+	return send(d.adjustCurrentTime(events[start:], received))
+}
+
+// wait returns how long the event must be held back. Only the events starting
+// a transaction, or a DDL or other statement outside a transaction, which the
+// vstreamer starts with its GTID, are held back.
+func (d *eventDelayer) wait(event *binlogdatapb.VEvent) time.Duration {
+	if d.inTransaction || event.Timestamp == 0 {
+		return 0
+	}
+	if event.Type != binlogdatapb.VEventType_BEGIN && event.Type != binlogdatapb.VEventType_GTID {
+		return 0
+	}
+	if d.fastForwarding(event) {
+		return 0
+	}
+	return time.Unix(event.Timestamp, 0).Add(d.delay).Sub(d.now())
+}
+
+// fastForwarding returns true if the event is before the fast-forward
+// position or timestamp.
+func (d *eventDelayer) fastForwarding(event *binlogdatapb.VEvent) bool {
+	forward := (!d.fastForwardPos.IsZero() && !d.pos.AtLeast(d.fastForwardPos)) ||
+		(d.fastForwardTimestamp != 0 && event.Timestamp <= d.fastForwardTimestamp)
+	if forward != d.fastForwarded {
+		d.fastForwarded = forward
+		if !forward {
+			log.Infof("Stream %d fast-forwarded to position %v, timestamp %v: its events are delayed by %v again", d.id, d.pos, event.Timestamp, d.delay)
+		}
+	}
+	return forward
+}
+
+// adjustCurrentTime adds the time the events were held back to their current
+// time, so that the replication lag of the stream includes its delay.
+func (d *eventDelayer) adjustCurrentTime(events []*binlogdatapb.VEvent, received time.Time) []*binlogdatapb.VEvent {
+	held := d.now().Sub(received).Nanoseconds()
+	for _, event := range events {
+		if event.CurrentTime != 0 {
+			event.CurrentTime += held
+		}
+	}
+	return events
+}
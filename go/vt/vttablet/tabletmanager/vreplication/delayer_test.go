@@ -0,0 +1,110 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+)
+
+const delayerTestGTID = "MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-"
+
+func delayerTestTransaction(timestamp int64, gtid string) []*binlogdatapb.VEvent {
+	return []*binlogdatapb.VEvent{
+		{Type: binlogdatapb.VEventType_BEGIN, Timestamp: timestamp},
+		{Type: binlogdatapb.VEventType_ROW, Timestamp: timestamp, CurrentTime: timestamp * 1e9},
+		{Type: binlogdatapb.VEventType_GTID, Timestamp: timestamp, Gtid: delayerTestGTID + gtid},
+		{Type: binlogdatapb.VEventType_COMMIT, Timestamp: timestamp},
+	}
+}
+
+// newTestEventDelayer returns a delayer whose clock starts 100ms before the
+// events of timestamp 100 are due.
+func newTestEventDelayer(t *testing.T, source *binlogdatapb.BinlogSource) *eventDelayer {
+	source.DelaySeconds = 10
+	d, err := newEventDelayer(1, source, replication.Position{})
+	require.NoError(t, err)
+	base := time.Unix(110, 0).Add(-100 * time.Millisecond)
+	start := time.Now()
+	d.now = func() time.Time {
+		return base.Add(time.Since(start))
+	}
+	return d
+}
+
+func TestEventDelayer(t *testing.T) {
+	ctx := context.Background()
+	d := newTestEventDelayer(t, &binlogdatapb.BinlogSource{})
+	var sent [][]*binlogdatapb.VEvent
+	send := func(events []*binlogdatapb.VEvent) error {
+		sent = append(sent, events)
+		return nil
+	}
+
+	// The first transaction is due, the second one is held back until it is
+	// due, and sent separately.
+	events := append(delayerTestTransaction(90, "10"), delayerTestTransaction(100, "11")...)
+	start := time.Now()
+	require.NoError(t, d.send(ctx, events, send))
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	require.Len(t, sent, 2)
+	assert.Equal(t, events[:4], sent[0])
+	assert.Equal(t, events[4:], sent[1])
+	// The time the second transaction was held back is part of its lag.
+	assert.GreaterOrEqual(t, sent[1][1].CurrentTime, int64(100e9+100*time.Millisecond))
+	wantPos, err := binlogplayer.DecodePosition(delayerTestGTID + "11")
+	require.NoError(t, err)
+	assert.True(t, d.pos.Equal(wantPos))
+
+	// The events of a transaction are never held back.
+	d.inTransaction = true
+	assert.Zero(t, d.wait(&binlogdatapb.VEvent{Type: binlogdatapb.VEventType_ROW, Timestamp: 200}))
+	d.inTransaction = false
+	assert.Greater(t, d.wait(&binlogdatapb.VEvent{Type: binlogdatapb.VEventType_GTID, Timestamp: 200}), 90*time.Second)
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.Equal(t, context.Canceled, d.send(ctx, delayerTestTransaction(200, "12"), send))
+}
+
+func TestEventDelayerFastForward(t *testing.T) {
+	d := newTestEventDelayer(t, &binlogdatapb.BinlogSource{FastForwardTimestamp: 105})
+	assert.Zero(t, d.wait(&binlogdatapb.VEvent{Type: binlogdatapb.VEventType_GTID, Timestamp: 105}))
+	assert.Greater(t, d.wait(&binlogdatapb.VEvent{Type: binlogdatapb.VEventType_GTID, Timestamp: 106}), 5*time.Second)
+
+	d = newTestEventDelayer(t, &binlogdatapb.BinlogSource{FastForwardPosition: delayerTestGTID + "20"})
+	require.NoError(t, d.send(context.Background(), delayerTestTransaction(200, "20"), func([]*binlogdatapb.VEvent) error {
+		return nil
+	}))
+	// The fast-forward position is reached: the events are held back again.
+	assert.Greater(t, d.wait(&binlogdatapb.VEvent{Type: binlogdatapb.VEventType_GTID, Timestamp: 200}), 90*time.Second)
+
+	d, err := newEventDelayer(1, &binlogdatapb.BinlogSource{}, replication.Position{})
+	require.NoError(t, err)
+	assert.Nil(t, d)
+	_, err = newEventDelayer(1, &binlogdatapb.BinlogSource{DelaySeconds: 10, FastForwardPosition: "MySQL56/bad"}, replication.Position{})
+	assert.Error(t, err)
+}
@@ -108,6 +108,9 @@ type Engine struct {
 
 	throttlerClient *throttle.Client
 
+	// notifier posts the workflow events to the webhooks, if any.
+	notifier *workflowNotifier
+
 	// This should only be set in Test Engines in order to short
 	// curcuit functions as needed in unit tests. It's automatically
 	// enabled in NewSimpleTestEngine. This should NOT be used in
@@ -137,7 +140,8 @@ func NewEngine(config *tabletenv.TabletConfig, ts *topo.Server, cell string, mys
 		mysqld:          mysqld,
 		journaler:       make(map[string]*journalEvent),
 		ec:              newExternalConnector(config.ExternalConnections),
-		throttlerClient: throttle.NewBackgroundClient(lagThrottler, throttlerapp.VReplicationName, throttle.ThrottleCheckPrimaryWrite),
+		throttlerClient: throttle.NewBackgroundClient(lagThrottler, throttlerapp.VReplicationName, throttle.ThrottleCheckShard),
+		notifier:        newWorkflowNotifier(workflowWebhookURLs, workflowWebhookEvents, workflowWebhookTimeout),
 	}
 
 	return vre
@@ -473,6 +477,18 @@ func (vre *Engine) exec(query string, runAsAdmin bool) (*sqltypes.Result, error)
 			if err := insertLog(vdbc, LogStateChange, id, params["state"], ""); err != nil {
 				return nil, err
 			}
+			// The writes of the workflow were switched if this froze the stream.
+			if params["message"] == frozenMessage {
+				vre.notify(&WorkflowEvent{
+					Type:     WorkflowEventSwitched,
+					Workflow: ct.workflow,
+					StreamID: id,
+					Keyspace: ct.source.Keyspace,
+					Shard:    ct.source.Shard,
+					State:    params["state"],
+					Message:  params["message"],
+				})
+			}
 		}
 		return qr, nil
 	case deleteQuery:
@@ -22,6 +22,7 @@ import (
 	"context"
 
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
 	"vitess.io/vitess/go/vt/dbconfigs"
 	"vitess.io/vitess/go/vt/grpcclient"
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
@@ -35,6 +36,7 @@ import (
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/vstreamer"
+	"vitess.io/vitess/go/vt/vttablet/vstreamcompression"
 )
 
 var (
@@ -145,13 +147,14 @@ func (c *mysqlConnector) VStreamRows(ctx context.Context, query string, lastpk *
 //-----------------------------------------------------------
 
 type tabletConnector struct {
-	tablet *topodatapb.Tablet
-	target *querypb.Target
-	qs     queryservice.QueryService
+	tablet           *topodatapb.Tablet
+	target           *querypb.Target
+	qs               queryservice.QueryService
+	compressionStats *vstreamcompression.Stats
 }
 
-func newTabletConnector(tablet *topodatapb.Tablet) *tabletConnector {
-	return &tabletConnector{
+func newTabletConnector(tablet *topodatapb.Tablet, stats *binlogplayer.Stats) *tabletConnector {
+	tc := &tabletConnector{
 		tablet: tablet,
 		target: &querypb.Target{
 			Keyspace:   tablet.Keyspace,
@@ -159,6 +162,13 @@ func newTabletConnector(tablet *topodatapb.Tablet) *tabletConnector {
 			TabletType: tablet.Type,
 		},
 	}
+	if stats != nil {
+		tc.compressionStats = &vstreamcompression.Stats{
+			CompressedBytes:   stats.VStreamCompressedBytes,
+			UncompressedBytes: stats.VStreamUncompressedBytes,
+		}
+	}
+	return tc
 }
 
 func (tc *tabletConnector) Open(ctx context.Context) error {
@@ -172,7 +182,10 @@ func (tc *tabletConnector) Close(ctx context.Context) error {
 }
 
 func (tc *tabletConnector) VStream(ctx context.Context, startPos string, tablePKs []*binlogdatapb.TableLastPK, filter *binlogdatapb.Filter, send func([]*binlogdatapb.VEvent) error) error {
-	req := &binlogdatapb.VStreamRequest{Target: tc.target, Position: startPos, TableLastPKs: tablePKs, Filter: filter}
+	req := &binlogdatapb.VStreamRequest{Target: tc.target, Position: startPos, TableLastPKs: tablePKs, Filter: filter, Compression: vstreamCompression}
+	if tc.compressionStats != nil {
+		ctx = vstreamcompression.NewContext(ctx, tc.compressionStats)
+	}
 	return tc.qs.VStream(ctx, req, send)
 }
 
@@ -17,6 +17,8 @@ limitations under the License.
 package vreplication
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/spf13/pflag"
@@ -40,6 +42,13 @@ var (
 
 	vreplicationStoreCompressedGTID   = false
 	vreplicationParallelInsertWorkers = 1
+
+	workflowWebhookURLs    []string
+	workflowWebhookEvents  []string
+	workflowWebhookTimeout = 10 * time.Second
+	lagAlertThreshold      time.Duration
+
+	vstreamCompression string
 )
 
 func registerVReplicationFlags(fs *pflag.FlagSet) {
@@ -70,6 +79,13 @@ func registerVReplicationFlags(fs *pflag.FlagSet) {
 	fs.Duration("vreplication_healthcheck_timeout", 1*time.Minute, "healthcheck retry delay")
 
 	fs.IntVar(&vreplicationParallelInsertWorkers, "vreplication-parallel-insert-workers", vreplicationParallelInsertWorkers, "Number of parallel insertion workers to use during copy phase. Set <= 1 to disable parallelism, or > 1 to enable concurrent insertion during copy phase.")
+
+	fs.StringSliceVar(&workflowWebhookURLs, "vreplication-webhook-urls", workflowWebhookURLs, "URLs to which the workflow events (state changes, copy completion, errors, replication lag alerts, switches) are posted as JSON")
+	fs.StringSliceVar(&workflowWebhookEvents, "vreplication-webhook-events", workflowWebhookEvents, fmt.Sprintf("types of the workflow events posted to the webhooks, all of them by default: %s", strings.Join(workflowEventTypes, ", ")))
+	fs.DurationVar(&workflowWebhookTimeout, "vreplication-webhook-timeout", workflowWebhookTimeout, "timeout of the requests to the workflow webhooks")
+	fs.DurationVar(&lagAlertThreshold, "vreplication-lag-alert-threshold", lagAlertThreshold, "replication lag above which a stream sends a lag_threshold_breached workflow event, and a lag_recovered one once back below it; 0 disables the lag events")
+
+	fs.StringVar(&vstreamCompression, "vreplication-compression", vstreamCompression, "compression of the events streamed from the source tablets: zstd, or empty for none. The events are batched and compressed by the source tablets that support it, which reduces the bandwidth used by wide rows")
 }
 
 func init() {
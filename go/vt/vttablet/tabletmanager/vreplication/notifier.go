@@ -0,0 +1,282 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/streamlog"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+)
+
+// The types of the workflow events.
+const (
+	// WorkflowEventStateChanged is sent when the state of a stream changes.
+	WorkflowEventStateChanged = "state_changed"
+	// WorkflowEventCopyCompleted is sent when the copy phase of a stream ends.
+	WorkflowEventCopyCompleted = "copy_completed"
+	// WorkflowEventError is sent when a stream goes into the Error state.
+	WorkflowEventError = "error"
+	// WorkflowEventLagThresholdBreached is sent when the replication lag of a
+	// stream goes above --vreplication-lag-alert-threshold.
+	WorkflowEventLagThresholdBreached = "lag_threshold_breached"
+	// WorkflowEventLagRecovered is sent when the replication lag of a stream
+	// goes back below --vreplication-lag-alert-threshold.
+	WorkflowEventLagRecovered = "lag_recovered"
+	// WorkflowEventSwitched is sent when the writes of a workflow are switched
+	// and its streams are frozen.
+	WorkflowEventSwitched = "switched"
+)
+
+var workflowEventTypes = []string{
+	WorkflowEventStateChanged,
+	WorkflowEventCopyCompleted,
+	WorkflowEventError,
+	WorkflowEventLagThresholdBreached,
+	WorkflowEventLagRecovered,
+	WorkflowEventSwitched,
+}
+
+const (
+	// frozenMessage is the message of the streams of a workflow whose writes
+	// were switched, which is workflow.Frozen.
+	frozenMessage = "FROZEN"
+
+	// workflowEventsQueueSize is the number of events waiting to be sent to
+	// the webhooks, after which new events are dropped.
+	workflowEventsQueueSize = 1000
+)
+
+var (
+	workflowEventsLogger  = streamlog.New[*WorkflowEvent]("VReplicationWorkflowEvents", 50)
+	workflowEventsCount   = stats.NewCountersWithSingleLabel("VReplicationWorkflowEvents", "Number of workflow events by type", "type")
+	workflowEventsDropped = stats.NewCounter("VReplicationWorkflowEventsDropped", "Number of workflow events not sent to the webhooks because their queue was full")
+	workflowWebhookErrors = stats.NewCounter("VReplicationWorkflowWebhookErrors", "Number of failed requests to the workflow webhooks")
+)
+
+// WorkflowEvent is a state transition of a vreplication stream. It is posted
+// as JSON to the workflow webhooks, and streamed at /debug/vreplication_events.
+type WorkflowEvent struct {
+	Type     string    `json:"type"`
+	Time     time.Time `json:"time"`
+	Cell     string    `json:"cell"`
+	DBName   string    `json:"db_name"`
+	Workflow string    `json:"workflow"`
+	StreamID int32     `json:"stream_id"`
+	Keyspace string    `json:"source_keyspace,omitempty"`
+	Shard    string    `json:"source_shard,omitempty"`
+	State    string    `json:"state,omitempty"`
+	Message  string    `json:"message,omitempty"`
+
+	ReplicationLagSeconds int64 `json:"replication_lag_seconds,omitempty"`
+}
+
+// workflowNotifier posts the workflow events to webhooks. The events are
+// queued, so that a slow webhook never blocks vreplication, and dropped when
+// the queue is full.
+type workflowNotifier struct {
+	urls   []string
+	types  map[string]bool
+	client *http.Client
+	events chan *WorkflowEvent
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// newWorkflowNotifier returns a notifier posting the events of the types to
+// the urls, or all the events if types is empty. It returns nil if there are
+// no urls.
+func newWorkflowNotifier(urls, types []string, timeout time.Duration) *workflowNotifier {
+	if len(urls) == 0 {
+		return nil
+	}
+	n := &workflowNotifier{
+		urls:   urls,
+		client: &http.Client{Timeout: timeout},
+		events: make(chan *WorkflowEvent, workflowEventsQueueSize),
+	}
+	if len(types) > 0 {
+		n.types = make(map[string]bool)
+		for _, typ := range types {
+			if !slices.Contains(workflowEventTypes, typ) {
+				log.Warningf("Unknown workflow event type %q, must be one of %s", typ, strings.Join(workflowEventTypes, ", "))
+			}
+			n.types[typ] = true
+		}
+	}
+	var ctx context.Context
+	ctx, n.cancel = context.WithCancel(context.Background())
+	n.wg.Add(1)
+	go n.run(ctx)
+	return n
+}
+
+// send queues the event for the webhooks.
+func (n *workflowNotifier) send(event *WorkflowEvent) {
+	if n == nil || (n.types != nil && !n.types[event.Type]) {
+		return
+	}
+	select {
+	case n.events <- event:
+	default:
+		workflowEventsDropped.Add(1)
+		log.Warningf("Workflow event queue is full, dropping %s event of workflow %s", event.Type, event.Workflow)
+	}
+}
+
+func (n *workflowNotifier) run(ctx context.Context) {
+	defer n.wg.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-n.events:
+			body, err := json.Marshal(event)
+			if err != nil {
+				log.Errorf("Cannot marshal workflow event %v: %v", event, err)
+				continue
+			}
+			for _, url := range n.urls {
+				if err := n.post(ctx, url, body); err != nil {
+					workflowWebhookErrors.Add(1)
+					log.Warningf("Cannot send %s event of workflow %s to %s: %v", event.Type, event.Workflow, url, err)
+				}
+			}
+		}
+	}
+}
+
+func (n *workflowNotifier) post(ctx context.Context, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// close stops the notifier. The queued events are not sent.
+func (n *workflowNotifier) close() {
+	if n == nil {
+		return
+	}
+	n.cancel()
+	n.wg.Wait()
+}
+
+// notify records the event of a stream, and sends it to the webhooks.
+func (vre *Engine) notify(event *WorkflowEvent) {
+	event.Time = time.Now()
+	if vre != nil {
+		event.Cell = vre.cell
+		event.DBName = vre.dbName
+	}
+	workflowEventsCount.Add(event.Type, 1)
+	workflowEventsLogger.Send(event)
+	if vre != nil {
+		vre.notifier.send(event)
+	}
+}
+
+// notify sends an event of the stream of the vreplicator.
+func (vr *vreplicator) notify(typ, message string) {
+	vr.vre.notify(vr.workflowEvent(typ, message))
+}
+
+func (vr *vreplicator) workflowEvent(typ, message string) *WorkflowEvent {
+	event := &WorkflowEvent{
+		Type:     typ,
+		Workflow: vr.WorkflowName,
+		StreamID: vr.id,
+		State:    vr.state.String(),
+		Message:  message,
+	}
+	if vr.source != nil {
+		event.Keyspace = vr.source.Keyspace
+		event.Shard = vr.source.Shard
+	}
+	return event
+}
+
+// checkLagThreshold sends an event when the replication lag of the stream
+// goes above or back below --vreplication-lag-alert-threshold.
+func (vr *vreplicator) checkLagThreshold(lagSeconds int64) {
+	if lagAlertThreshold <= 0 {
+		return
+	}
+	breached := lagSeconds > int64(lagAlertThreshold.Seconds())
+	if breached == vr.lagThresholdBreached {
+		return
+	}
+	vr.lagThresholdBreached = breached
+	typ, message := WorkflowEventLagRecovered, fmt.Sprintf("replication lag is back below %v", lagAlertThreshold)
+	if breached {
+		typ, message = WorkflowEventLagThresholdBreached, fmt.Sprintf("replication lag is above %v", lagAlertThreshold)
+	}
+	event := vr.workflowEvent(typ, message)
+	event.ReplicationLagSeconds = lagSeconds
+	vr.vre.notify(event)
+}
+
+func init() {
+	servenv.HTTPHandleFunc("/debug/vreplication_events", func(w http.ResponseWriter, r *http.Request) {
+		ch := workflowEventsLogger.Subscribe("vreplication_events")
+		defer workflowEventsLogger.Unsubscribe(ch)
+		workflowEventsHandler(ch, w, r)
+	})
+}
+
+// workflowEventsHandler streams the workflow events as JSON lines.
+func workflowEventsHandler(ch chan *WorkflowEvent, w http.ResponseWriter, r *http.Request) {
+	timeout, limit := parseTimeoutLimitParams(r)
+	tmr := time.NewTimer(timeout)
+	defer tmr.Stop()
+	enc := json.NewEncoder(w)
+	for i := 0; i < limit; i++ {
+		select {
+		case event := <-ch:
+			if err := enc.Encode(event); err != nil {
+				log.Errorf("vreplication_events: couldn't encode event: %v", err)
+				return
+			}
+			if f, ok := w.(http.Flusher); ok {
+				f.Flush()
+			}
+		case <-tmr.C:
+			return
+		}
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+)
+
+func TestWorkflowNotifier(t *testing.T) {
+	received := make(chan *WorkflowEvent, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		event := &WorkflowEvent{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(event))
+		received <- event
+	}))
+	defer server.Close()
+
+	vre := &Engine{
+		cell:     "zone1",
+		dbName:   "vt_customer",
+		notifier: newWorkflowNotifier([]string{server.URL}, []string{WorkflowEventCopyCompleted, WorkflowEventError}, time.Second),
+	}
+	defer vre.notifier.close()
+	vr := &vreplicator{
+		vre:          vre,
+		id:           1,
+		source:       &binlogdatapb.BinlogSource{Keyspace: "commerce", Shard: "0"},
+		WorkflowName: "commerce2customer",
+		state:        binlogdatapb.VReplicationWorkflowState_Running,
+	}
+
+	// State changes are not posted, copy completions are.
+	vr.notify(WorkflowEventStateChanged, "")
+	vr.notify(WorkflowEventCopyCompleted, "Copy phase completed at gtid MySQL56/a:1-5")
+	select {
+	case event := <-received:
+		assert.False(t, event.Time.IsZero())
+		event.Time = time.Time{}
+		assert.Equal(t, &WorkflowEvent{
+			Type:     WorkflowEventCopyCompleted,
+			Cell:     "zone1",
+			DBName:   "vt_customer",
+			Workflow: "commerce2customer",
+			StreamID: 1,
+			Keyspace: "commerce",
+			Shard:    "0",
+			State:    "Running",
+			Message:  "Copy phase completed at gtid MySQL56/a:1-5",
+		}, event)
+	case <-time.After(10 * time.Second):
+		require.FailNow(t, "the event was not posted")
+	}
+
+	// Without webhooks, the events are only recorded.
+	assert.Nil(t, newWorkflowNotifier(nil, nil, time.Second))
+	before := workflowEventsCount.Counts()[WorkflowEventError]
+	(&Engine{}).notify(&WorkflowEvent{Type: WorkflowEventError})
+	assert.Equal(t, before+1, workflowEventsCount.Counts()[WorkflowEventError])
+}
+
+func TestCheckLagThreshold(t *testing.T) {
+	defer func(threshold time.Duration) {
+		lagAlertThreshold = threshold
+	}(lagAlertThreshold)
+	lagAlertThreshold = 30 * time.Second
+
+	ch := workflowEventsLogger.Subscribe("test")
+	defer workflowEventsLogger.Unsubscribe(ch)
+	vr := &vreplicator{vre: &Engine{}, id: 1, WorkflowName: "wf"}
+
+	next := func() *WorkflowEvent {
+		select {
+		case event := <-ch:
+			return event
+		case <-time.After(10 * time.Second):
+			require.FailNow(t, "no event")
+			return nil
+		}
+	}
+
+	vr.checkLagThreshold(10)
+	vr.checkLagThreshold(31)
+	event := next()
+	assert.Equal(t, WorkflowEventLagThresholdBreached, event.Type)
+	assert.EqualValues(t, 31, event.ReplicationLagSeconds)
+
+	// The breach is only reported once.
+	vr.checkLagThreshold(60)
+	vr.checkLagThreshold(30)
+	event = next()
+	assert.Equal(t, WorkflowEventLagRecovered, event.Type)
+	assert.EqualValues(t, 30, event.ReplicationLagSeconds)
+
+	select {
+	case event := <-ch:
+		assert.Failf(t, "unexpected event", "%v", event)
+	default:
+	}
+}
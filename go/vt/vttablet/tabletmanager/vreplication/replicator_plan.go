@@ -58,6 +58,7 @@ type ReplicatorPlan struct {
 	VStreamFilter *binlogdatapb.Filter
 	TargetTables  map[string]*TablePlan
 	TablePlans    map[string]*TablePlan
+	JoinPlans     map[string]*JoinPlan
 	ColInfoMap    map[string][]*ColumnInfo
 	stats         *binlogplayer.Stats
 	Source        *binlogdatapb.BinlogSource
@@ -84,6 +85,9 @@ func (rp *ReplicatorPlan) buildExecutionPlan(fieldEvent *binlogdatapb.FieldEvent
 			trimmed.Name = strings.Trim(trimmed.Name, "`")
 			tplanv.Fields = append(tplanv.Fields, trimmed)
 		}
+		if err := tplanv.compileRowFilter(); err != nil {
+			return nil, err
+		}
 		return &tplanv, nil
 	}
 	// select * construct was used. We need to use the field names.
@@ -92,6 +96,11 @@ func (rp *ReplicatorPlan) buildExecutionPlan(fieldEvent *binlogdatapb.FieldEvent
 		return nil, err
 	}
 	tplan.Fields = fieldEvent.Fields
+	tplan.RowFilter = prelim.RowFilter
+	tplan.JoinPlans = prelim.JoinPlans
+	if err := tplan.compileRowFilter(); err != nil {
+		return nil, err
+	}
 	return tplan, nil
 }
 
@@ -151,10 +160,12 @@ func (rp *ReplicatorPlan) MarshalJSON() ([]byte, error) {
 		VStreamFilter *binlogdatapb.Filter
 		TargetTables  []string
 		TablePlans    map[string]*TablePlan
+		JoinPlans     map[string]*JoinPlan `json:",omitempty"`
 	}{
 		VStreamFilter: rp.VStreamFilter,
 		TargetTables:  targets,
 		TablePlans:    rp.TablePlans,
+		JoinPlans:     rp.JoinPlans,
 	}
 	return json.Marshal(&v)
 }
@@ -210,6 +221,13 @@ type TablePlan struct {
 	FieldsToSkip            map[string]bool
 	ConvertCharset          map[string](*binlogdatapb.CharsetConversion)
 	HasExtraSourcePkColumns bool
+	// RowFilter contains the constraints of the filter that the source
+	// cannot evaluate. The rows that do not match it are not applied.
+	RowFilter sqlparser.Expr
+	// rowFilter is RowFilter compiled against Fields.
+	rowFilter evalengine.Expr
+	// JoinPlans are the plans of the tables that join this table.
+	JoinPlans []*JoinPlan
 
 	TablePlanBuilder *tablePlanBuilder
 	// PartialInserts is a dynamically generated cache of insert ParsedQueries, which update only some columns.
@@ -232,6 +250,7 @@ func (tp *TablePlan) MarshalJSON() ([]byte, error) {
 		Update       *sqlparser.ParsedQuery `json:",omitempty"`
 		Delete       *sqlparser.ParsedQuery `json:",omitempty"`
 		PKReferences []string               `json:",omitempty"`
+		RowFilter    string                 `json:",omitempty"`
 	}{
 		TargetName:   tp.TargetName,
 		SendRule:     tp.SendRule.Match,
@@ -243,10 +262,70 @@ func (tp *TablePlan) MarshalJSON() ([]byte, error) {
 		Delete:       tp.Delete,
 		PKReferences: tp.PKReferences,
 	}
+	if tp.RowFilter != nil {
+		v.RowFilter = sqlparser.String(tp.RowFilter)
+	}
 	return json.Marshal(&v)
 }
 
+// compileRowFilter compiles the RowFilter, if any, against the Fields of the plan.
+func (tp *TablePlan) compileRowFilter() error {
+	if tp.RowFilter == nil {
+		return nil
+	}
+	expr, err := evalengine.Translate(tp.RowFilter, &evalengine.Config{
+		ResolveColumn: func(col *sqlparser.ColName) (int, error) {
+			for i, field := range tp.Fields {
+				if col.Name.EqualString(field.Name) {
+					return i, nil
+				}
+			}
+			return 0, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown column %v in the row filter of table %s", sqlparser.String(col), tp.TargetName)
+		},
+		Collation: collations.Default(),
+	})
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot evaluate the row filter %v of table %s", sqlparser.String(tp.RowFilter), tp.TargetName)
+	}
+	tp.rowFilter = expr
+	return nil
+}
+
+// matchesRowFilter returns true if the row is not nil and matches the
+// row filter of the plan, if any.
+func (tp *TablePlan) matchesRowFilter(env *evalengine.ExpressionEnv, row *querypb.Row) (bool, error) {
+	if row == nil {
+		return false, nil
+	}
+	if tp.rowFilter == nil {
+		return true, nil
+	}
+	env.Row = sqltypes.MakeRowTrusted(tp.Fields, row)
+	result, err := env.Evaluate(tp.rowFilter)
+	if err != nil {
+		return false, vterrors.Wrapf(err, "cannot evaluate the row filter %v of table %s", sqlparser.String(tp.RowFilter), tp.TargetName)
+	}
+	return result.ToBoolean(), nil
+}
+
 func (tp *TablePlan) applyBulkInsert(sqlbuffer *bytes2.Buffer, rows []*querypb.Row, executor func(string) (*sqltypes.Result, error)) (*sqltypes.Result, error) {
+	if tp.rowFilter != nil {
+		env := evalengine.EmptyExpressionEnv()
+		matched := make([]*querypb.Row, 0, len(rows))
+		for _, row := range rows {
+			ok, err := tp.matchesRowFilter(env, row)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				matched = append(matched, row)
+			}
+		}
+		if len(matched) == 0 {
+			return &sqltypes.Result{}, nil
+		}
+		rows = matched
+	}
 	sqlbuffer.Reset()
 	sqlbuffer.WriteString(tp.BulkInsertFront.Query)
 	sqlbuffer.WriteString(" values ")
@@ -359,6 +438,12 @@ func (tp *TablePlan) bindFieldVal(field *querypb.Field, val *sqltypes.Value) (*q
 }
 
 func (tp *TablePlan) applyChange(rowChange *binlogdatapb.RowChange, executor func(string) (*sqltypes.Result, error)) (*sqltypes.Result, error) {
+	if tp.rowFilter != nil {
+		var err error
+		if rowChange, err = tp.filterRowChange(rowChange); err != nil || rowChange == nil {
+			return nil, err
+		}
+	}
 	// MakeRowTrusted is needed here because Proto3ToResult is not convenient.
 	var before, after bool
 	bindvars := make(map[string]*querypb.BindVariable, len(tp.Fields))
@@ -395,6 +480,18 @@ func (tp *TablePlan) applyChange(rowChange *binlogdatapb.RowChange, executor fun
 			bindvars["a_"+field.Name] = bindVar
 		}
 	}
+	qr, err := tp.applyBindVars(rowChange, bindvars, before, after, executor)
+	if err != nil || len(tp.JoinPlans) == 0 {
+		return qr, err
+	}
+	if err := tp.refreshJoins(bindvars, before, after, executor); err != nil {
+		return nil, err
+	}
+	return qr, nil
+}
+
+// applyBindVars applies a row change, whose values are bound in bindvars.
+func (tp *TablePlan) applyBindVars(rowChange *binlogdatapb.RowChange, bindvars map[string]*querypb.BindVariable, before, after bool, executor func(string) (*sqltypes.Result, error)) (*sqltypes.Result, error) {
 	switch {
 	case !before && after:
 		// only apply inserts for rows whose primary keys are within the range of rows already copied
@@ -443,6 +540,34 @@ func (tp *TablePlan) applyChange(rowChange *binlogdatapb.RowChange, executor fun
 	return nil, nil
 }
 
+// filterRowChange returns the change to apply for a row change given the row
+// filter of the plan, or nil if there is none: an update whose before image
+// matches the filter but not its after image deletes the row, and inversely
+// inserts it.
+func (tp *TablePlan) filterRowChange(rowChange *binlogdatapb.RowChange) (*binlogdatapb.RowChange, error) {
+	if tp.isPartial(rowChange) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the row filter of table %s cannot be evaluated on partial row images", tp.TargetName)
+	}
+	env := evalengine.EmptyExpressionEnv()
+	before, err := tp.matchesRowFilter(env, rowChange.Before)
+	if err != nil {
+		return nil, err
+	}
+	after, err := tp.matchesRowFilter(env, rowChange.After)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case before && after:
+		return rowChange, nil
+	case before:
+		return &binlogdatapb.RowChange{Before: rowChange.Before}, nil
+	case after:
+		return &binlogdatapb.RowChange{After: rowChange.After}, nil
+	}
+	return nil, nil
+}
+
 func getQuery(pq *sqlparser.ParsedQuery, bindvars map[string]*querypb.BindVariable) (string, error) {
 	sql, err := pq.GenerateQuery(bindvars, nil)
 	if err != nil {
@@ -18,15 +18,20 @@ package vreplication
 
 import (
 	"encoding/json"
+	"sort"
 	"strings"
 	"testing"
 
 	"vitess.io/vitess/go/vt/binlog/binlogplayer"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"vitess.io/vitess/go/bytes2"
 	"vitess.io/vitess/go/sqltypes"
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
 )
 
 type TestReplicatorPlan struct {
@@ -45,6 +50,7 @@ type TestTablePlan struct {
 	Update       string   `json:",omitempty"`
 	Delete       string   `json:",omitempty"`
 	PKReferences []string `json:",omitempty"`
+	RowFilter    string   `json:",omitempty"`
 }
 
 func TestBuildPlayerPlan(t *testing.T) {
@@ -514,6 +520,107 @@ func TestBuildPlayerPlan(t *testing.T) {
 				},
 			},
 		},
+	}, {
+		// row filter
+		input: &binlogdatapb.Filter{
+			Rules: []*binlogdatapb.Rule{{
+				Match:  "t1",
+				Filter: "select c1, c2 from t1 where in_keyrange('-80') and c3 in (1, 2) and c4 > 'a' and (c2 < 10 or c5 is null)",
+			}},
+		},
+		plan: &TestReplicatorPlan{
+			VStreamFilter: &binlogdatapb.Filter{
+				Rules: []*binlogdatapb.Rule{{
+					Match:  "t1",
+					Filter: "select c1, c2, c3, c5 from t1 where in_keyrange('-80') and c4 > 'a'",
+				}},
+			},
+			TargetTables: []string{"t1"},
+			TablePlans: map[string]*TestTablePlan{
+				"t1": {
+					TargetName:   "t1",
+					SendRule:     "t1",
+					PKReferences: []string{"c1"},
+					InsertFront:  "insert into t1(c1,c2)",
+					InsertValues: "(:a_c1,:a_c2)",
+					Insert:       "insert into t1(c1,c2) values (:a_c1,:a_c2)",
+					Update:       "update t1 set c2=:a_c2 where c1=:b_c1",
+					Delete:       "delete from t1 where c1=:b_c1",
+					RowFilter:    "c3 in (1, 2) and (c2 < 10 or c5 is null)",
+				},
+			},
+		},
+		planpk: &TestReplicatorPlan{
+			VStreamFilter: &binlogdatapb.Filter{
+				Rules: []*binlogdatapb.Rule{{
+					Match:  "t1",
+					Filter: "select c1, c2, pk1, pk2, c3, c5 from t1 where in_keyrange('-80') and c4 > 'a'",
+				}},
+			},
+			TargetTables: []string{"t1"},
+			TablePlans: map[string]*TestTablePlan{
+				"t1": {
+					TargetName:   "t1",
+					SendRule:     "t1",
+					PKReferences: []string{"c1", "pk1", "pk2"},
+					InsertFront:  "insert into t1(c1,c2)",
+					InsertValues: "(:a_c1,:a_c2)",
+					Insert:       "insert into t1(c1,c2) select :a_c1, :a_c2 from dual where (:a_pk1,:a_pk2) <= (1,'aaa')",
+					Update:       "update t1 set c2=:a_c2 where c1=:b_c1 and (:b_pk1,:b_pk2) <= (1,'aaa')",
+					Delete:       "delete from t1 where c1=:b_c1 and (:b_pk1,:b_pk2) <= (1,'aaa')",
+					RowFilter:    "c3 in (1, 2) and (c2 < 10 or c5 is null)",
+				},
+			},
+		},
+	}, {
+		// row filter with select *
+		input: &binlogdatapb.Filter{
+			Rules: []*binlogdatapb.Rule{{
+				Match:  "t1",
+				Filter: "select * from t1 where c1 = 1 and created_at > now() - interval 1 day",
+			}},
+		},
+		plan: &TestReplicatorPlan{
+			VStreamFilter: &binlogdatapb.Filter{
+				Rules: []*binlogdatapb.Rule{{
+					Match:  "t1",
+					Filter: "select * from t1 where c1 = 1",
+				}},
+			},
+			TargetTables: []string{"t1"},
+			TablePlans: map[string]*TestTablePlan{
+				"t1": {
+					TargetName: "t1",
+					SendRule:   "t1",
+					RowFilter:  "created_at > now() - interval 1 day",
+				},
+			},
+		},
+		planpk: &TestReplicatorPlan{
+			VStreamFilter: &binlogdatapb.Filter{
+				Rules: []*binlogdatapb.Rule{{
+					Match:  "t1",
+					Filter: "select * from t1 where c1 = 1",
+				}},
+			},
+			TargetTables: []string{"t1"},
+			TablePlans: map[string]*TestTablePlan{
+				"t1": {
+					TargetName: "t1",
+					SendRule:   "t1",
+					RowFilter:  "created_at > now() - interval 1 day",
+				},
+			},
+		},
+	}, {
+		// no subqueries in the row filter
+		input: &binlogdatapb.Filter{
+			Rules: []*binlogdatapb.Rule{{
+				Match:  "t1",
+				Filter: "select c1 from t1 where c1 in (select c1 from t2)",
+			}},
+		},
+		err: "unsupported subquery: (select c1 from t2)",
 	}, {
 		// syntax error
 		input: &binlogdatapb.Filter{
@@ -772,3 +879,199 @@ func TestBuildPlayerPlanExclude(t *testing.T) {
 	wantPlan, _ := json.Marshal(want)
 	assert.Equal(t, string(gotPlan), string(wantPlan))
 }
+
+func TestTablePlanRowFilter(t *testing.T) {
+	PrimaryKeyInfos := map[string][]*ColumnInfo{
+		"t1": {&ColumnInfo{Name: "c1", IsPK: true}},
+	}
+	input := &binlogdatapb.Filter{
+		Rules: []*binlogdatapb.Rule{{
+			Match:  "t1",
+			Filter: "select c1, c2 from t1 where c3 in ('a', 'b') and c1 > 0",
+		}},
+	}
+	plan, err := buildReplicatorPlan(getSource(input), PrimaryKeyInfos, nil, binlogplayer.NewStats())
+	require.NoError(t, err)
+	fields := sqltypes.MakeTestFields("c1|c2|c3", "int64|varchar|varchar")
+	tplan, err := plan.buildExecutionPlan(&binlogdatapb.FieldEvent{TableName: "t1", Fields: fields})
+	require.NoError(t, err)
+
+	row := func(values ...string) *querypb.Row {
+		return sqltypes.RowToProto3(sqltypes.MakeTestResult(fields, strings.Join(values, "|")).Rows[0])
+	}
+	var queries []string
+	executor := func(query string) (*sqltypes.Result, error) {
+		queries = append(queries, query)
+		return &sqltypes.Result{}, nil
+	}
+
+	testcases := []struct {
+		change *binlogdatapb.RowChange
+		want   []string
+	}{{
+		change: &binlogdatapb.RowChange{After: row("1", "x", "a")},
+		want:   []string{"insert into t1(c1,c2) values (1,'x')"},
+	}, {
+		change: &binlogdatapb.RowChange{After: row("2", "x", "c")},
+	}, {
+		change: &binlogdatapb.RowChange{Before: row("1", "x", "a"), After: row("1", "y", "b")},
+		want:   []string{"update t1 set c2='y' where c1=1"},
+	}, {
+		// The row no longer matches the filter.
+		change: &binlogdatapb.RowChange{Before: row("1", "y", "b"), After: row("1", "y", "c")},
+		want:   []string{"delete from t1 where c1=1"},
+	}, {
+		// The row now matches the filter.
+		change: &binlogdatapb.RowChange{Before: row("2", "x", "c"), After: row("2", "x", "a")},
+		want:   []string{"insert into t1(c1,c2) values (2,'x')"},
+	}, {
+		change: &binlogdatapb.RowChange{Before: row("2", "x", "c")},
+	}, {
+		change: &binlogdatapb.RowChange{Before: row("2", "x", "a")},
+		want:   []string{"delete from t1 where c1=2"},
+	}}
+	for _, tcase := range testcases {
+		queries = nil
+		_, err := tplan.applyChange(tcase.change, executor)
+		require.NoError(t, err)
+		assert.Equal(t, tcase.want, queries, "change: %v", tcase.change)
+	}
+
+	var sqlbuffer bytes2.Buffer
+	queries = nil
+	_, err = tplan.applyBulkInsert(&sqlbuffer, []*querypb.Row{row("1", "x", "a"), row("2", "x", "c"), row("3", "z", "b")}, executor)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"insert into t1(c1,c2) values (1,'x'), (3,'z')"}, queries)
+
+	queries = nil
+	_, err = tplan.applyBulkInsert(&sqlbuffer, []*querypb.Row{row("2", "x", "c")}, executor)
+	require.NoError(t, err)
+	assert.Empty(t, queries)
+}
+
+func TestBuildJoinPlan(t *testing.T) {
+	colInfoMap := map[string][]*ColumnInfo{
+		"orders":     {{Name: "id", IsPK: true}, {Name: "customer_id"}, {Name: "amount"}},
+		"customers":  {{Name: "id", IsPK: true}, {Name: "name"}},
+		"order_view": {{Name: "order_id", IsPK: true}, {Name: "customer_id"}, {Name: "amount"}, {Name: "name"}},
+	}
+	joinFilter := "select o.id as order_id, c.id as customer_id, o.amount, c.name from orders as o join customers as c on o.customer_id = c.id where o.amount > 10"
+	input := &binlogdatapb.Filter{
+		Rules: []*binlogdatapb.Rule{{
+			Match:  "order_view",
+			Filter: joinFilter,
+		}, {
+			Match:  "orders",
+			Filter: "select * from orders",
+		}, {
+			Match:  "customers",
+			Filter: "select id, name from customers",
+		}},
+	}
+	plan, err := buildReplicatorPlan(getSource(input), colInfoMap, nil, binlogplayer.NewStats())
+	require.NoError(t, err)
+
+	// The join is not streamed.
+	assert.Equal(t, []string{"customers", "orders"}, func() []string {
+		var tables []string
+		for _, rule := range plan.VStreamFilter.Rules {
+			tables = append(tables, rule.Match)
+		}
+		sort.Strings(tables)
+		return tables
+	}())
+	joinPlan := plan.JoinPlans["order_view"]
+	require.NotNil(t, joinPlan)
+	assert.Equal(t, "delete from order_view", joinPlan.Delete.Query)
+	assert.Equal(t, "insert into order_view(order_id,customer_id,amount,`name`) select o.id as order_id, c.id as customer_id, o.amount, c.`name` from orders as o join customers as c on o.customer_id = c.id where o.amount > 10", joinPlan.Insert.Query)
+	want := map[string]*JoinRefresh{
+		"orders": {
+			PKColumns: []string{"id"},
+			Delete:    sqlparser.BuildParsedQuery("delete from order_view where order_id = :pk_id"),
+			Insert:    sqlparser.BuildParsedQuery("insert into order_view(order_id,customer_id,amount,`name`) select o.id as order_id, c.id as customer_id, o.amount, c.`name` from orders as o join customers as c on o.customer_id = c.id where o.amount > 10 and o.id = :pk_id"),
+		},
+		"customers": {
+			PKColumns: []string{"id"},
+			Delete:    sqlparser.BuildParsedQuery("delete from order_view where customer_id = :pk_id"),
+			Insert:    sqlparser.BuildParsedQuery("insert into order_view(order_id,customer_id,amount,`name`) select o.id as order_id, c.id as customer_id, o.amount, c.`name` from orders as o join customers as c on o.customer_id = c.id where o.amount > 10 and c.id = :pk_id"),
+		},
+	}
+	for table, refresh := range want {
+		assert.Equal(t, refresh.PKColumns, joinPlan.Refreshes[table].PKColumns, table)
+		assert.Equal(t, refresh.Delete.Query, joinPlan.Refreshes[table].Delete.Query, table)
+		assert.Equal(t, refresh.Insert.Query, joinPlan.Refreshes[table].Insert.Query, table)
+	}
+	assert.Equal(t, []*JoinPlan{joinPlan}, plan.TargetTables["orders"].JoinPlans)
+	assert.Equal(t, []*JoinPlan{joinPlan}, plan.TargetTables["customers"].JoinPlans)
+
+	// Once the join is copied, the changes of the joined tables refresh it.
+	fields := sqltypes.MakeTestFields("id|customer_id|amount", "int64|int64|int64")
+	tplan, err := plan.buildExecutionPlan(&binlogdatapb.FieldEvent{TableName: "orders", Fields: fields})
+	require.NoError(t, err)
+	row := func(values string) *querypb.Row {
+		return sqltypes.RowToProto3(sqltypes.MakeTestResult(fields, values).Rows[0])
+	}
+	var queries []string
+	_, err = tplan.applyChange(&binlogdatapb.RowChange{Before: row("1|2|20"), After: row("1|3|20")}, func(query string) (*sqltypes.Result, error) {
+		queries = append(queries, query)
+		return &sqltypes.Result{}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"update orders set customer_id=3, amount=20 where id=1",
+		"delete from order_view where order_id = 1",
+		"insert into order_view(order_id,customer_id,amount,`name`) select o.id as order_id, c.id as customer_id, o.amount, c.`name` from orders as o join customers as c on o.customer_id = c.id where o.amount > 10 and o.id = 1",
+	}, queries)
+
+	// The join is not refreshed until it is copied.
+	copyState := map[string]*sqltypes.Result{"order_view": nil}
+	plan, err = buildReplicatorPlan(getSource(input), colInfoMap, copyState, binlogplayer.NewStats())
+	require.NoError(t, err)
+	assert.Empty(t, plan.JoinPlans)
+	assert.Empty(t, plan.TargetTables["orders"].JoinPlans)
+
+	testcases := []struct {
+		filter string
+		err    string
+	}{{
+		filter: "select o.id as order_id, o.amount from orders as o join payments as p on o.id = p.order_id",
+		err:    "table payments joined by order_view not found in schema",
+	}, {
+		filter: "select o.id as order_id, o.amount, c.name from orders as o join customers as c on o.customer_id = c.id",
+		err:    "column c.id is not selected: the primary key columns of table customers must be selected by the join of order_view",
+	}, {
+		filter: "select o.id as order_id, c.id as customer_id from orders as o left join customers as c on o.customer_id = c.id",
+		err:    "unsupported join, only inner joins with an on condition are supported: orders as o left join customers as c on o.customer_id = c.id",
+	}, {
+		filter: "select o.id as order_id, count(*) as n from orders as o join customers as c on o.customer_id = c.id",
+		err:    "unexpected: count(*)",
+	}, {
+		filter: "select o.id as order_id, p.id as order_id2 from orders as o join orders as p on o.id = p.id",
+		err:    "unsupported: table orders is joined more than once",
+	}}
+	for _, tcase := range testcases {
+		input := &binlogdatapb.Filter{
+			Rules: []*binlogdatapb.Rule{{
+				Match:  "order_view",
+				Filter: tcase.filter,
+			}, {
+				Match: "orders",
+			}, {
+				Match: "customers",
+			}},
+		}
+		_, err := buildReplicatorPlan(getSource(input), colInfoMap, nil, binlogplayer.NewStats())
+		assert.EqualError(t, err, tcase.err, tcase.filter)
+	}
+
+	input = &binlogdatapb.Filter{
+		Rules: []*binlogdatapb.Rule{{
+			Match:  "order_view",
+			Filter: joinFilter,
+		}, {
+			Match: "orders",
+		}},
+	}
+	_, err = buildReplicatorPlan(getSource(input), colInfoMap, nil, binlogplayer.NewStats())
+	assert.EqualError(t, err, "table customers joined by order_view is not replicated by the workflow")
+}
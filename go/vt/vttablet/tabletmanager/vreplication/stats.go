@@ -301,6 +301,33 @@ func (st *vrStats) register() {
 			return result
 		})
 
+	stats.NewGaugesFuncWithMultiLabels(
+		"VReplicationVStreamCompressedBytes",
+		"Bytes of the compressed events received from the vstreamer per stream",
+		[]string{"source_keyspace", "source_shard", "workflow", "counts"},
+		func() map[string]int64 {
+			st.mu.Lock()
+			defer st.mu.Unlock()
+			result := make(map[string]int64, len(st.controllers))
+			for _, ct := range st.controllers {
+				result[ct.source.Keyspace+"."+ct.source.Shard+"."+ct.workflow+"."+fmt.Sprintf("%v", ct.id)] = ct.blpStats.VStreamCompressedBytes.Get()
+			}
+			return result
+		})
+	stats.NewGaugesFuncWithMultiLabels(
+		"VReplicationVStreamUncompressedBytes",
+		"Bytes of the compressed events received from the vstreamer per stream, once decompressed",
+		[]string{"source_keyspace", "source_shard", "workflow", "counts"},
+		func() map[string]int64 {
+			st.mu.Lock()
+			defer st.mu.Unlock()
+			result := make(map[string]int64, len(st.controllers))
+			for _, ct := range st.controllers {
+				result[ct.source.Keyspace+"."+ct.source.Shard+"."+ct.workflow+"."+fmt.Sprintf("%v", ct.id)] = ct.blpStats.VStreamUncompressedBytes.Get()
+			}
+			return result
+		})
+
 	stats.NewCounterFunc(
 		"VReplicationCopyRowCountTotal",
 		"vreplication rows copied in copy phase aggregated across all streams",
@@ -131,6 +131,7 @@ func buildReplicatorPlan(source *binlogdatapb.BinlogSource, colInfoMap map[strin
 		VStreamFilter: &binlogdatapb.Filter{FieldEventMode: filter.FieldEventMode},
 		TargetTables:  make(map[string]*TablePlan),
 		TablePlans:    make(map[string]*TablePlan),
+		JoinPlans:     make(map[string]*JoinPlan),
 		ColInfoMap:    colInfoMap,
 		stats:         stats,
 		Source:        source,
@@ -152,6 +153,14 @@ func buildReplicatorPlan(source *binlogdatapb.BinlogSource, colInfoMap map[strin
 		if !ok {
 			return nil, fmt.Errorf("table %s not found in schema", tableName)
 		}
+		joinPlan, err := buildJoinPlan(tableName, rule, colInfoMap)
+		if err != nil {
+			return nil, err
+		}
+		if joinPlan != nil {
+			plan.JoinPlans[tableName] = joinPlan
+			continue
+		}
 		tablePlan, err := buildTablePlan(tableName, rule, colInfos, lastpk, stats, source)
 		if err != nil {
 			return nil, err
@@ -167,6 +176,9 @@ func buildReplicatorPlan(source *binlogdatapb.BinlogSource, colInfoMap map[strin
 		plan.TargetTables[tableName] = tablePlan
 		plan.TablePlans[tablePlan.SendRule.Match] = tablePlan
 	}
+	if err := plan.linkJoinPlans(); err != nil {
+		return nil, err
+	}
 	return plan, nil
 }
 
@@ -233,9 +245,18 @@ func buildTablePlan(tableName string, rule *binlogdatapb.Rule, colInfos []*Colum
 			return nil, fmt.Errorf("unsupported qualifier for '*' expression: %v", sqlparser.String(expr))
 		}
 		sendRule.Filter = query
+		sourceWhere, rowFilter, err := splitWhere(sel.Where)
+		if err != nil {
+			return nil, err
+		}
+		if rowFilter != nil {
+			sel.Where = sourceWhere
+			sendRule.Filter = sqlparser.String(sel)
+		}
 		tablePlan := &TablePlan{
 			TargetName:       tableName,
 			SendRule:         sendRule,
+			RowFilter:        rowFilter,
 			Lastpk:           lastpk,
 			Stats:            stats,
 			EnumValuesMap:    enumValuesMap,
@@ -246,11 +267,15 @@ func buildTablePlan(tableName string, rule *binlogdatapb.Rule, colInfos []*Colum
 		return tablePlan, nil
 	}
 
+	sourceWhere, rowFilter, err := splitWhere(sel.Where)
+	if err != nil {
+		return nil, err
+	}
 	tpb := &tablePlanBuilder{
 		name: sqlparser.NewIdentifierCS(tableName),
 		sendSelect: &sqlparser.Select{
 			From:  sel.From,
-			Where: sel.Where,
+			Where: sourceWhere,
 		},
 		lastpk:   lastpk,
 		colInfos: colInfos,
@@ -273,6 +298,9 @@ func buildTablePlan(tableName string, rule *binlogdatapb.Rule, colInfos []*Colum
 			tpb.addCol(sqlparser.NewIdentifierCI(f.Name))
 		}
 	}
+	if err := tpb.addRowFilterCols(rowFilter); err != nil {
+		return nil, err
+	}
 	if err := tpb.analyzeGroupBy(sel.GroupBy); err != nil {
 		return nil, err
 	}
@@ -316,6 +344,7 @@ func buildTablePlan(tableName string, rule *binlogdatapb.Rule, colInfos []*Colum
 
 	tablePlan := tpb.generate()
 	tablePlan.SendRule = sendRule
+	tablePlan.RowFilter = rowFilter
 	tablePlan.EnumValuesMap = enumValuesMap
 	tablePlan.ConvertCharset = rule.ConvertCharset
 	tablePlan.ConvertIntToEnum = rule.ConvertIntToEnum
@@ -506,6 +535,97 @@ func (tpb *tablePlanBuilder) addCol(ident sqlparser.IdentifierCI) {
 	})
 }
 
+// splitWhere splits the where clause of a filter into the constraints that
+// the source can evaluate, and the row filter made of the other constraints,
+// which the target evaluates on the rows it receives from the source.
+// vstreamer only supports in_keyrange and the comparisons of a column with
+// an int or a string literal.
+func splitWhere(where *sqlparser.Where) (*sqlparser.Where, sqlparser.Expr, error) {
+	if where == nil {
+		return nil, nil, nil
+	}
+	var sourceExprs, rowExprs []sqlparser.Expr
+	for _, expr := range sqlparser.SplitAndExpression(nil, where.Expr) {
+		if isSourceConstraint(expr) {
+			sourceExprs = append(sourceExprs, expr)
+			continue
+		}
+		err := sqlparser.Walk(func(node sqlparser.SQLNode) (kontinue bool, err error) {
+			switch node := node.(type) {
+			case *sqlparser.ColName:
+				if !node.Qualifier.IsEmpty() {
+					return false, fmt.Errorf("unsupported qualifier for column: %v", sqlparser.String(node))
+				}
+			case *sqlparser.Subquery:
+				return false, fmt.Errorf("unsupported subquery: %v", sqlparser.String(node))
+			case sqlparser.AggrFunc:
+				return false, fmt.Errorf("unexpected: %v", sqlparser.String(node))
+			}
+			return true, nil
+		}, expr)
+		if err != nil {
+			return nil, nil, err
+		}
+		rowExprs = append(rowExprs, expr)
+	}
+	var sourceWhere *sqlparser.Where
+	if len(sourceExprs) > 0 {
+		sourceWhere = sqlparser.NewWhere(sqlparser.WhereClause, sqlparser.AndExpressions(sourceExprs...))
+	}
+	return sourceWhere, sqlparser.AndExpressions(rowExprs...), nil
+}
+
+// isSourceConstraint returns true if vstreamer can evaluate the constraint.
+func isSourceConstraint(expr sqlparser.Expr) bool {
+	switch expr := expr.(type) {
+	case *sqlparser.FuncExpr:
+		return expr.Name.EqualString("in_keyrange")
+	case *sqlparser.ComparisonExpr:
+		switch expr.Operator {
+		case sqlparser.EqualOp, sqlparser.NotEqualOp, sqlparser.LessThanOp, sqlparser.LessEqualOp,
+			sqlparser.GreaterThanOp, sqlparser.GreaterEqualOp:
+		default:
+			return false
+		}
+		col, ok := expr.Left.(*sqlparser.ColName)
+		if !ok || !col.Qualifier.IsEmpty() {
+			return false
+		}
+		val, ok := expr.Right.(*sqlparser.Literal)
+		return ok && (val.Type == sqlparser.IntVal || val.Type == sqlparser.StrVal)
+	}
+	return false
+}
+
+// addRowFilterCols adds the columns that the row filter references
+// to the send query, unless they are already sent.
+func (tpb *tablePlanBuilder) addRowFilterCols(rowFilter sqlparser.Expr) error {
+	if rowFilter == nil {
+		return nil
+	}
+	return sqlparser.Walk(func(node sqlparser.SQLNode) (kontinue bool, err error) {
+		col, ok := node.(*sqlparser.ColName)
+		if !ok {
+			return true, nil
+		}
+		for _, selExpr := range tpb.sendSelect.SelectExprs {
+			aliased, ok := selExpr.(*sqlparser.AliasedExpr)
+			if !ok {
+				continue
+			}
+			if sentCol, ok := aliased.Expr.(*sqlparser.ColName); ok && aliased.As.IsEmpty() && sentCol.Name.Equal(col.Name) {
+				return false, nil
+			}
+			if aliased.As.Equal(col.Name) {
+				// The column is sent converted to utf8mb4.
+				return false, nil
+			}
+		}
+		tpb.addCol(col.Name)
+		return false, nil
+	}, rowFilter)
+}
+
 func (tpb *tablePlanBuilder) analyzeGroupBy(groupBy sqlparser.GroupBy) error {
 	if groupBy == nil {
 		// If there's no grouping, the it's an insertNormal.
@@ -0,0 +1,334 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// JoinPlan is the plan of a target table whose filter joins other tables.
+// The joined tables must be replicated to the target by the same workflow,
+// and the join is evaluated on the target against them: the table is copied
+// by inserting all the rows of the join once the joined tables are copied,
+// and, afterwards, the rows of the table that involve a row of a joined table
+// are replaced every time that row changes.
+// JoinPlans are built by buildReplicatorPlan, and each TablePlan of a joined
+// table references the JoinPlans that join it.
+type JoinPlan struct {
+	TargetName string
+	// Delete deletes all the rows of the table, and Insert inserts all
+	// the rows of the join. Both are used to copy the table.
+	Delete *sqlparser.ParsedQuery
+	Insert *sqlparser.ParsedQuery
+	// Refreshes contains the JoinRefresh of every joined table.
+	Refreshes map[string]*JoinRefresh
+}
+
+// JoinRefresh replaces the rows of the table of a JoinPlan that involve a row
+// of a joined table, identified by the values of its primary key columns,
+// which are bound as pk_<column>.
+type JoinRefresh struct {
+	PKColumns []string
+	Delete    *sqlparser.ParsedQuery
+	Insert    *sqlparser.ParsedQuery
+}
+
+// MarshalJSON performs a custom JSON Marshalling.
+func (jp *JoinPlan) MarshalJSON() ([]byte, error) {
+	v := struct {
+		TargetName string
+		Delete     *sqlparser.ParsedQuery
+		Insert     *sqlparser.ParsedQuery
+		Refreshes  map[string]*JoinRefresh
+	}{
+		TargetName: jp.TargetName,
+		Delete:     jp.Delete,
+		Insert:     jp.Insert,
+		Refreshes:  jp.Refreshes,
+	}
+	return json.Marshal(&v)
+}
+
+// buildJoinPlan returns the JoinPlan of a table if the filter of its rule
+// joins other tables, and nil otherwise.
+func buildJoinPlan(tableName string, rule *binlogdatapb.Rule, colInfoMap map[string][]*ColumnInfo) (*JoinPlan, error) {
+	if rule.Filter == "" || rule.Filter == ExcludeStr || key.IsValidKeyRange(rule.Filter) {
+		return nil, nil
+	}
+	statement, err := sqlparser.Parse(rule.Filter)
+	if err != nil {
+		// buildTablePlan reports the error.
+		return nil, nil
+	}
+	sel, ok := statement.(*sqlparser.Select)
+	if !ok || len(sel.From) != 1 {
+		return nil, nil
+	}
+	join, ok := sel.From[0].(*sqlparser.JoinTableExpr)
+	if !ok {
+		return nil, nil
+	}
+	for _, selExpr := range sel.SelectExprs {
+		if _, ok := selExpr.(*sqlparser.StarExpr); ok {
+			// buildTablePlan rejects the join.
+			return nil, nil
+		}
+	}
+	if sel.Distinct || sel.GroupBy != nil || sel.Having != nil || sel.OrderBy != nil || sel.Limit != nil {
+		return nil, fmt.Errorf("unexpected: %v", sqlparser.String(sel))
+	}
+	// tables maps the aliases of the joined tables to their names.
+	tables := make(map[string]string)
+	if err := analyzeJoinTables(join, tables); err != nil {
+		return nil, err
+	}
+	columns, err := analyzeJoinExprs(sel.SelectExprs)
+	if err != nil {
+		return nil, err
+	}
+
+	target := sqlparser.NewIdentifierCS(tableName)
+	jplan := &JoinPlan{
+		TargetName: tableName,
+		Refreshes:  make(map[string]*JoinRefresh, len(tables)),
+	}
+	buf := sqlparser.NewTrackedBuffer(nil)
+	buf.Myprintf("delete from %v", target)
+	jplan.Delete = buf.ParsedQuery()
+	jplan.Insert = generateJoinInsert(target, columns, sel)
+
+	for alias, table := range tables {
+		colInfos, ok := colInfoMap[table]
+		if !ok {
+			return nil, fmt.Errorf("table %s joined by %s not found in schema", table, tableName)
+		}
+		refresh := &JoinRefresh{}
+		var deleteExprs, insertExprs []sqlparser.Expr
+		for _, colInfo := range colInfos {
+			if !colInfo.IsPK {
+				continue
+			}
+			column, err := findJoinColumn(sel.SelectExprs, columns, alias, colInfo.Name)
+			if err != nil {
+				return nil, fmt.Errorf("%v: the primary key columns of table %s must be selected by the join of %s", err, table, tableName)
+			}
+			arg := sqlparser.NewArgument("pk_" + colInfo.Name)
+			deleteExprs = append(deleteExprs, &sqlparser.ComparisonExpr{
+				Operator: sqlparser.EqualOp,
+				Left:     &sqlparser.ColName{Name: column},
+				Right:    arg,
+			})
+			insertExprs = append(insertExprs, &sqlparser.ComparisonExpr{
+				Operator: sqlparser.EqualOp,
+				Left:     sqlparser.NewColNameWithQualifier(colInfo.Name, sqlparser.TableName{Name: sqlparser.NewIdentifierCS(alias)}),
+				Right:    arg,
+			})
+			refresh.PKColumns = append(refresh.PKColumns, colInfo.Name)
+		}
+		if len(refresh.PKColumns) == 0 {
+			return nil, fmt.Errorf("table %s joined by %s does not have a primary key", table, tableName)
+		}
+		buf := sqlparser.NewTrackedBuffer(nil)
+		buf.Myprintf("delete from %v where %v", target, sqlparser.AndExpressions(deleteExprs...))
+		refresh.Delete = buf.ParsedQuery()
+
+		refreshSel := sqlparser.CloneRefOfSelect(sel)
+		if refreshSel.Where != nil {
+			insertExprs = append([]sqlparser.Expr{refreshSel.Where.Expr}, insertExprs...)
+		}
+		refreshSel.Where = sqlparser.NewWhere(sqlparser.WhereClause, sqlparser.AndExpressions(insertExprs...))
+		refresh.Insert = generateJoinInsert(target, columns, refreshSel)
+		jplan.Refreshes[table] = refresh
+	}
+	return jplan, nil
+}
+
+// analyzeJoinTables adds the tables of an inner join to tables.
+func analyzeJoinTables(expr sqlparser.TableExpr, tables map[string]string) error {
+	switch expr := expr.(type) {
+	case *sqlparser.JoinTableExpr:
+		if expr.Join != sqlparser.NormalJoinType || expr.Condition == nil || expr.Condition.On == nil {
+			return fmt.Errorf("unsupported join, only inner joins with an on condition are supported: %v", sqlparser.String(expr))
+		}
+		if err := analyzeJoinTables(expr.LeftExpr, tables); err != nil {
+			return err
+		}
+		return analyzeJoinTables(expr.RightExpr, tables)
+	case *sqlparser.ParenTableExpr:
+		for _, expr := range expr.Exprs {
+			if err := analyzeJoinTables(expr, tables); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *sqlparser.AliasedTableExpr:
+		tableName, ok := expr.Expr.(sqlparser.TableName)
+		if !ok || !tableName.Qualifier.IsEmpty() {
+			return fmt.Errorf("unexpected: %v", sqlparser.String(expr))
+		}
+		alias := tableName.Name.String()
+		if !expr.As.IsEmpty() {
+			alias = expr.As.String()
+		}
+		if _, ok := tables[alias]; ok {
+			return fmt.Errorf("unsupported: alias %s is used more than once", alias)
+		}
+		for _, table := range tables {
+			if table == tableName.Name.String() {
+				return fmt.Errorf("unsupported: table %s is joined more than once", table)
+			}
+		}
+		tables[alias] = tableName.Name.String()
+		return nil
+	}
+	return fmt.Errorf("unexpected: %v", sqlparser.String(expr))
+}
+
+// analyzeJoinExprs returns the names of the target columns of the select
+// expressions of a join.
+func analyzeJoinExprs(selExprs sqlparser.SelectExprs) ([]sqlparser.IdentifierCI, error) {
+	columns := make([]sqlparser.IdentifierCI, 0, len(selExprs))
+	for _, selExpr := range selExprs {
+		aliased, ok := selExpr.(*sqlparser.AliasedExpr)
+		if !ok {
+			return nil, fmt.Errorf("unexpected: %v", sqlparser.String(selExpr))
+		}
+		as := aliased.As
+		if as.IsEmpty() {
+			col, ok := aliased.Expr.(*sqlparser.ColName)
+			if !ok {
+				return nil, fmt.Errorf("expression needs an alias: %v", sqlparser.String(aliased))
+			}
+			as = col.Name
+		}
+		err := sqlparser.Walk(func(node sqlparser.SQLNode) (kontinue bool, err error) {
+			switch node := node.(type) {
+			case *sqlparser.Subquery:
+				return false, fmt.Errorf("unsupported subquery: %v", sqlparser.String(node))
+			case sqlparser.AggrFunc:
+				return false, fmt.Errorf("unexpected: %v", sqlparser.String(node))
+			}
+			return true, nil
+		}, aliased.Expr)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, as)
+	}
+	return columns, nil
+}
+
+// findJoinColumn returns the target column of the select expression
+// which selects a column of the joined table with the given alias.
+func findJoinColumn(selExprs sqlparser.SelectExprs, columns []sqlparser.IdentifierCI, alias, name string) (sqlparser.IdentifierCI, error) {
+	for i, selExpr := range selExprs {
+		col, ok := selExpr.(*sqlparser.AliasedExpr).Expr.(*sqlparser.ColName)
+		if ok && col.Qualifier.Name.String() == alias && col.Name.EqualString(name) {
+			return columns[i], nil
+		}
+	}
+	return sqlparser.IdentifierCI{}, fmt.Errorf("column %s.%s is not selected", alias, name)
+}
+
+func generateJoinInsert(target sqlparser.IdentifierCS, columns []sqlparser.IdentifierCI, sel *sqlparser.Select) *sqlparser.ParsedQuery {
+	buf := sqlparser.NewTrackedBuffer(nil)
+	buf.Myprintf("insert into %v(", target)
+	for i, column := range columns {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.Myprintf("%v", column)
+	}
+	buf.Myprintf(") %v", sel)
+	return buf.ParsedQuery()
+}
+
+// linkJoinPlans adds the JoinPlans of the plan to the TablePlans of the tables
+// they join, which must be replicated by the workflow.
+func (rp *ReplicatorPlan) linkJoinPlans() error {
+	names := make([]string, 0, len(rp.JoinPlans))
+	for name := range rp.JoinPlans {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		jplan := rp.JoinPlans[name]
+		for table := range jplan.Refreshes {
+			tplan := rp.TargetTables[table]
+			if tplan == nil {
+				return fmt.Errorf("table %s joined by %s is not replicated by the workflow", table, name)
+			}
+			tplan.JoinPlans = append(tplan.JoinPlans, jplan)
+		}
+	}
+	return nil
+}
+
+// refreshJoins replaces the rows of the tables that join the table of the plan
+// that involve the row of a change, once the change is applied.
+func (tp *TablePlan) refreshJoins(bindvars map[string]*querypb.BindVariable, before, after bool, executor func(string) (*sqltypes.Result, error)) error {
+	for _, jplan := range tp.JoinPlans {
+		refresh := jplan.Refreshes[tp.TargetName]
+		if before {
+			pkvars, err := tp.joinBindVars(refresh, bindvars, "b_")
+			if err != nil {
+				return err
+			}
+			if _, err := execParsedQuery(refresh.Delete, pkvars, executor); err != nil {
+				return err
+			}
+		}
+		if after {
+			pkvars, err := tp.joinBindVars(refresh, bindvars, "a_")
+			if err != nil {
+				return err
+			}
+			if _, err := execParsedQuery(refresh.Insert, pkvars, executor); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// joinBindVars returns the bind variables of a JoinRefresh for the before
+// or the after image of a row, depending on the prefix.
+func (tp *TablePlan) joinBindVars(refresh *JoinRefresh, bindvars map[string]*querypb.BindVariable, prefix string) (map[string]*querypb.BindVariable, error) {
+	pkvars := make(map[string]*querypb.BindVariable, len(refresh.PKColumns))
+	for _, pkcol := range refresh.PKColumns {
+		cexpr := tp.TablePlanBuilder.findCol(sqlparser.NewIdentifierCI(pkcol))
+		if cexpr == nil {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "primary key column %s of table %s is not replicated", pkcol, tp.TargetName)
+		}
+		col, ok := cexpr.expr.(*sqlparser.ColName)
+		if !ok || cexpr.operation != opExpr {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "primary key column %s of table %s must be replicated as is to refresh the tables joining it", pkcol, tp.TargetName)
+		}
+		pkvars["pk_"+pkcol] = bindvars[prefix+col.Name.String()]
+	}
+	return pkvars, nil
+}
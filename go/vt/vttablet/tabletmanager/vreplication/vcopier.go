@@ -235,6 +235,10 @@ func (vc *vcopier) initTablesForCopy(ctx context.Context) error {
 			fmt.Fprintf(&buf, "%s(%d, %s)", prefix, vc.vr.id, encodeString(name))
 			prefix = ", "
 		}
+		for name := range plan.JoinPlans {
+			fmt.Fprintf(&buf, "%s(%d, %s)", prefix, vc.vr.id, encodeString(name))
+			prefix = ", "
+		}
 		if _, err := vc.vr.dbClient.Execute(buf.String()); err != nil {
 			return err
 		}
@@ -242,7 +246,7 @@ func (vc *vcopier) initTablesForCopy(ctx context.Context) error {
 			return err
 		}
 		if err := vc.vr.insertLog(LogCopyStart, fmt.Sprintf("Copy phase started for %d table(s)",
-			len(plan.TargetTables))); err != nil {
+			len(plan.TargetTables)+len(plan.JoinPlans))); err != nil {
 			return err
 		}
 
@@ -298,12 +302,17 @@ func (vc *vcopier) copyNext(ctx context.Context, settings binlogplayer.VRSetting
 	if err != nil {
 		return err
 	}
+	plan, err := buildReplicatorPlan(vc.vr.source, vc.vr.colInfoMap, nil, vc.vr.stats)
+	if err != nil {
+		return err
+	}
 	var tableToCopy string
 	copyState := make(map[string]*sqltypes.Result)
 	for _, row := range qr.Rows {
 		tableName := row[0].ToString()
 		lastpk := row[1].ToString()
-		if tableToCopy == "" {
+		// The tables materialized from joins are copied once the tables they join are copied.
+		if tableToCopy == "" || plan.JoinPlans[tableToCopy] != nil && plan.JoinPlans[tableName] == nil {
 			tableToCopy = tableName
 		}
 		copyState[tableName] = nil
@@ -321,9 +330,47 @@ func (vc *vcopier) copyNext(ctx context.Context, settings binlogplayer.VRSetting
 	if err := vc.catchup(ctx, copyState); err != nil {
 		return err
 	}
+	if joinPlan := plan.JoinPlans[tableToCopy]; joinPlan != nil {
+		return vc.copyJoinTable(ctx, joinPlan)
+	}
 	return vc.copyTable(ctx, tableToCopy, copyState)
 }
 
+// copyJoinTable copies a table materialized from a join of other tables of the
+// workflow, which are all copied, by replacing its rows with all the rows of the
+// join in a single transaction. The other streams that replicate to the tables
+// of the join may not have copied them yet: each of them replaces the rows of the
+// table again when it copies it, and the last one leaves it consistent.
+func (vc *vcopier) copyJoinTable(ctx context.Context, joinPlan *JoinPlan) error {
+	defer vc.vr.dbClient.Rollback()
+	defer vc.vr.stats.PhaseTimings.Record("copy", time.Now())
+	defer vc.vr.stats.CopyLoopCount.Add(1)
+
+	log.Infof("Copying table %s from its join", joinPlan.TargetName)
+	if err := vc.vr.dbClient.Begin(); err != nil {
+		return err
+	}
+	if _, err := vc.vr.dbClient.ExecuteWithRetry(ctx, joinPlan.Delete.Query); err != nil {
+		return err
+	}
+	qr, err := vc.vr.dbClient.ExecuteWithRetry(ctx, joinPlan.Insert.Query)
+	if err != nil {
+		return err
+	}
+	vc.vr.stats.CopyRowCount.Add(int64(qr.RowsAffected))
+	vc.vr.stats.TableCopyRowCounts.Add(joinPlan.TargetName, int64(qr.RowsAffected))
+	buf := sqlparser.NewTrackedBuffer(nil)
+	buf.Myprintf("delete from _vt.%s where vrepl_id=%d and table_name=%s", copyStateTableName, vc.vr.id, encodeString(joinPlan.TargetName))
+	if _, err := vc.vr.dbClient.Execute(buf.String()); err != nil {
+		return err
+	}
+	if err := vc.vr.dbClient.Commit(); err != nil {
+		return err
+	}
+	log.Infof("Copy of %s finished with %d row(s)", joinPlan.TargetName, qr.RowsAffected)
+	return nil
+}
+
 // catchup replays events to the subset of the tables that have been copied
 // until replication is caught up. In order to stop, the seconds behind primary has
 // to fall below replicationLagTolerance.
@@ -467,7 +514,7 @@ func (vc *vcopier) copyTable(ctx context.Context, tableName string, copyState ma
 				return nil
 			}
 			// verify throttler is happy, otherwise keep looping
-			if vc.vr.vre.throttlerClient.ThrottleCheckOKOrWaitAppName(ctx, throttlerapp.Name(vc.throttlerAppName)) {
+			if vc.vr.throttlerClient.ThrottleCheckOKOrWaitAppName(ctx, throttlerapp.Name(vc.throttlerAppName)) {
 				break // out of 'for' loop
 			} else { // we're throttled
 				_ = vc.vr.updateTimeThrottled(throttlerapp.VCopierName)
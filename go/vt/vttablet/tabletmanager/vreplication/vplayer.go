@@ -68,6 +68,9 @@ type vplayer struct {
 	phase string
 
 	throttlerAppName string
+
+	// delayer holds back the events of a DelayedReplica stream, once copied.
+	delayer *eventDelayer
 }
 
 // newVPlayer creates a new vplayer. Parameters:
@@ -121,6 +124,12 @@ func (vp *vplayer) play(ctx context.Context) error {
 	}
 	vp.replicatorPlan = plan
 
+	if vp.phase == "replicate" {
+		if vp.delayer, err = newEventDelayer(vp.vr.id, vp.vr.source, vp.startPos); err != nil {
+			return err
+		}
+	}
+
 	// We can't run in statement mode if there are filters defined.
 	vp.canAcceptStmtEvents = true
 	for _, rule := range vp.vr.source.Filter.Rules {
@@ -154,6 +163,9 @@ func (vp *vplayer) fetchAndApply(ctx context.Context) (err error) {
 	streamErr := make(chan error, 1)
 	go func() {
 		streamErr <- vp.vr.sourceVStreamer.VStream(ctx, replication.EncodePosition(vp.startPos), nil, vp.replicatorPlan.VStreamFilter, func(events []*binlogdatapb.VEvent) error {
+			if vp.delayer != nil {
+				return vp.delayer.send(ctx, events, relay.Send)
+			}
 			return relay.Send(events)
 		})
 	}()
@@ -336,7 +348,7 @@ func (vp *vplayer) applyEvents(ctx context.Context, relay *relayLog) error {
 			return ctx.Err()
 		}
 		// check throttler.
-		if !vp.vr.vre.throttlerClient.ThrottleCheckOKOrWaitAppName(ctx, throttlerapp.Name(vp.throttlerAppName)) {
+		if !vp.vr.throttlerClient.ThrottleCheckOKOrWaitAppName(ctx, throttlerapp.Name(vp.throttlerAppName)) {
 			_ = vp.vr.updateTimeThrottled(throttlerapp.VPlayerName)
 			continue
 		}
@@ -351,6 +363,7 @@ func (vp *vplayer) applyEvents(ctx context.Context, relay *relayLog) error {
 			behind := time.Now().UnixNano() - vp.lastTimestampNs - vp.timeOffsetNs
 			vp.vr.stats.ReplicationLagSeconds.Store(behind / 1e9)
 			vp.vr.stats.VReplicationLags.Add(strconv.Itoa(int(vp.vr.id)), time.Duration(behind/1e9)*time.Second)
+			vp.vr.checkLagThreshold(behind / 1e9)
 		}
 		// Empty transactions are saved at most once every idleTimeout.
 		// This covers two situations:
@@ -407,6 +420,7 @@ func (vp *vplayer) applyEvents(ctx context.Context, relay *relayLog) error {
 		if sbm >= 0 {
 			vp.vr.stats.ReplicationLagSeconds.Store(sbm)
 			vp.vr.stats.VReplicationLags.Add(strconv.Itoa(int(vp.vr.id)), time.Duration(sbm)*time.Second)
+			vp.vr.checkLagThreshold(sbm)
 		}
 
 	}
@@ -31,7 +31,9 @@ import (
 	"vitess.io/vitess/go/timer"
 	"vitess.io/vitess/go/vt/schema"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/throttler"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
@@ -111,7 +113,14 @@ type vreplicator struct {
 	WorkflowType int32
 	WorkflowName string
 
+	// throttlerClient checks the replication lag of the replicas of the target
+	// shard, at the max_replication_lag of the workflow if it has one.
+	throttlerClient            *throttle.Client
 	throttleUpdatesRateLimiter *timer.RateLimiter
+
+	// lagThresholdBreached is set while the replication lag is above
+	// --vreplication-lag-alert-threshold.
+	lagThresholdBreached bool
 }
 
 // newVReplicator creates a new vreplicator. The valid fields from the source are:
@@ -280,9 +289,11 @@ func (vr *vreplicator) replicate(ctx context.Context) error {
 				return err
 			}
 			if numTablesToCopy == 0 {
-				if err := vr.insertLog(LogCopyEnd, fmt.Sprintf("Copy phase completed at gtid %s", settings.StartPos)); err != nil {
+				message := fmt.Sprintf("Copy phase completed at gtid %s", settings.StartPos)
+				if err := vr.insertLog(LogCopyEnd, message); err != nil {
 					return err
 				}
+				vr.notify(WorkflowEventCopyCompleted, message)
 			}
 		case settings.StartPos.IsZero():
 			if err := newVCopier(vr).initTablesForCopy(ctx); err != nil {
@@ -407,6 +418,10 @@ func (vr *vreplicator) loadSettings(ctx context.Context, dbClient *vdbClient) (s
 	if err == nil {
 		vr.WorkflowType = int32(settings.WorkflowType)
 		vr.WorkflowName = settings.WorkflowName
+		vr.throttlerClient = vr.vre.throttlerClient
+		if lag := settings.MaxReplicationLag; lag > 0 && lag != throttler.ReplicationLagModuleDisabled {
+			vr.throttlerClient = vr.vre.throttlerClient.WithOverrideThreshold(float64(lag))
+		}
 	}
 	return settings, numTablesToCopy, err
 }
@@ -473,6 +488,10 @@ func (vr *vreplicator) setState(state binlogdatapb.VReplicationWorkflowState, me
 		return err
 	}
 	vr.state = state
+	vr.notify(WorkflowEventStateChanged, message)
+	if state == binlogdatapb.VReplicationWorkflowState_Error {
+		vr.notify(WorkflowEventError, message)
+	}
 
 	return nil
 }
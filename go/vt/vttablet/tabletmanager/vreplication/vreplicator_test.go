@@ -205,7 +205,7 @@ func TestDeferSecondaryKeys(t *testing.T) {
 		Filter:   filter,
 	}
 	id := int32(1)
-	vsclient := newTabletConnector(tablet)
+	vsclient := newTabletConnector(tablet, nil)
 	stats := binlogplayer.NewStats()
 	defer stats.Stop()
 	dbClient := playerEngine.dbClientFactoryFiltered()
@@ -537,7 +537,7 @@ func TestCancelledDeferSecondaryKeys(t *testing.T) {
 	// filtered connections.
 	dbconfigs.GlobalDBConfigs.Filtered.User = "vt_dba"
 	id := int32(1)
-	vsclient := newTabletConnector(tablet)
+	vsclient := newTabletConnector(tablet, nil)
 	stats := binlogplayer.NewStats()
 	defer stats.Stop()
 	dbaconn := playerEngine.dbClientFactoryDba()
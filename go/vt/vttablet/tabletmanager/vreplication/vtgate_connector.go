@@ -0,0 +1,146 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"context"
+	"strings"
+
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vtgateconn"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+var _ VStreamerClient = (*vtgateConnector)(nil)
+
+// vtgateConnector streams the binlog events of a shard of an external Vitess
+// cluster through one of the vtgates of the cluster, which follows the reparents
+// and the tablet changes of the cluster. The rows of the copy phase are still
+// streamed from a tablet of the shard, because vtgate cannot stream them with
+// the positions that the copy phase needs.
+//
+// The VGTIDs of the vtgate are turned into the positions of the shard, and the
+// positions of the shard into VGTIDs, so that the positions of the stream remain
+// the positions of the source shard whatever the connector.
+type vtgateConnector struct {
+	*tabletConnector
+
+	address    string
+	tabletType topodatapb.TabletType
+	cells      string
+	conn       *vtgateconn.VTGateConn
+}
+
+func newVTGateConnector(address string, tablet *topodatapb.Tablet, tabletTypesStr, cells string) *vtgateConnector {
+	tabletType := topodatapb.TabletType_PRIMARY
+	if tabletTypes, _, err := discovery.ParseTabletTypesAndOrder(tabletTypesStr); err == nil && len(tabletTypes) > 0 {
+		tabletType = tabletTypes[0]
+	}
+	return &vtgateConnector{
+		tabletConnector: newTabletConnector(tablet, nil),
+		address:         address,
+		tabletType:      tabletType,
+		cells:           cells,
+	}
+}
+
+func (vc *vtgateConnector) Open(ctx context.Context) error {
+	if err := vc.tabletConnector.Open(ctx); err != nil {
+		return err
+	}
+	var err error
+	vc.conn, err = vtgateconn.DialProtocol(ctx, vtgateconn.GetVTGateProtocol(), vc.address)
+	if err != nil {
+		vc.tabletConnector.Close(ctx)
+		return vterrors.Wrapf(err, "cannot connect to vtgate %s", vc.address)
+	}
+	return nil
+}
+
+func (vc *vtgateConnector) Close(ctx context.Context) error {
+	vc.conn.Close()
+	return vc.tabletConnector.Close(ctx)
+}
+
+func (vc *vtgateConnector) VStream(ctx context.Context, startPos string, tablePKs []*binlogdatapb.TableLastPK, filter *binlogdatapb.Filter, send func([]*binlogdatapb.VEvent) error) error {
+	if startPos == "" || len(tablePKs) > 0 {
+		// The copy phase streams from the tablet.
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "cannot stream from vtgate %s without a position", vc.address)
+	}
+	vgtid := &binlogdatapb.VGtid{
+		ShardGtids: []*binlogdatapb.ShardGtid{{
+			Keyspace: vc.target.Keyspace,
+			Shard:    vc.target.Shard,
+			Gtid:     startPos,
+		}},
+	}
+	flags := &vtgatepb.VStreamFlags{
+		HeartbeatInterval: 1,
+		StopOnReshard:     true,
+		Cells:             vc.cells,
+	}
+	reader, err := vc.conn.VStream(ctx, vc.tabletType, vgtid, filter, flags)
+	if err != nil {
+		return err
+	}
+	for {
+		events, err := reader.Recv()
+		if err != nil {
+			return err
+		}
+		for i, event := range events {
+			if events[i], err = vc.shardEvent(event); err != nil {
+				return err
+			}
+		}
+		if err := send(events); err != nil {
+			return err
+		}
+	}
+}
+
+// shardEvent returns the event of the shard for an event of the vtgate.
+// The VGTIDs become the GTIDs of the shard, and the names of the tables
+// lose the keyspace that the vtgate prefixes them with.
+func (vc *vtgateConnector) shardEvent(event *binlogdatapb.VEvent) (*binlogdatapb.VEvent, error) {
+	switch event.Type {
+	case binlogdatapb.VEventType_VGTID:
+		for _, sgtid := range event.Vgtid.GetShardGtids() {
+			if sgtid.Keyspace == vc.target.Keyspace && sgtid.Shard == vc.target.Shard {
+				return &binlogdatapb.VEvent{
+					Type:        binlogdatapb.VEventType_GTID,
+					Gtid:        sgtid.Gtid,
+					Timestamp:   event.Timestamp,
+					CurrentTime: event.CurrentTime,
+					Keyspace:    event.Keyspace,
+					Shard:       event.Shard,
+				}, nil
+			}
+		}
+		return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "VGTID of vtgate %s does not have a position for %s/%s: %v", vc.address, vc.target.Keyspace, vc.target.Shard, event.Vgtid)
+	case binlogdatapb.VEventType_FIELD:
+		event.FieldEvent.TableName = strings.TrimPrefix(event.FieldEvent.TableName, vc.target.Keyspace+".")
+	case binlogdatapb.VEventType_ROW:
+		event.RowEvent.TableName = strings.TrimPrefix(event.RowEvent.TableName, vc.target.Keyspace+".")
+	}
+	return event, nil
+}
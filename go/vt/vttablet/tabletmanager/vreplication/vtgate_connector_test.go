@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestVTGateConnectorTabletType(t *testing.T) {
+	tablet := &topodatapb.Tablet{Keyspace: "ks", Shard: "-80", Type: topodatapb.TabletType_REPLICA}
+	testcases := []struct {
+		tabletTypes string
+		want        topodatapb.TabletType
+	}{
+		{"", topodatapb.TabletType_PRIMARY},
+		{"rdonly", topodatapb.TabletType_RDONLY},
+		{"in_order:replica,primary", topodatapb.TabletType_REPLICA},
+		{"invalid", topodatapb.TabletType_PRIMARY},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.tabletTypes, func(t *testing.T) {
+			vc := newVTGateConnector("vtgate:15991", tablet, tc.tabletTypes, "zone1")
+			assert.Equal(t, tc.want, vc.tabletType)
+		})
+	}
+}
+
+func TestVTGateConnectorShardEvent(t *testing.T) {
+	vc := newVTGateConnector("vtgate:15991", &topodatapb.Tablet{Keyspace: "ks", Shard: "-80"}, "", "")
+
+	event, err := vc.shardEvent(&binlogdatapb.VEvent{
+		Type: binlogdatapb.VEventType_VGTID,
+		Vgtid: &binlogdatapb.VGtid{ShardGtids: []*binlogdatapb.ShardGtid{
+			{Keyspace: "other", Shard: "-80", Gtid: "MySQL56/00000000-0000-0000-0000-000000000000:1-5"},
+			{Keyspace: "ks", Shard: "-80", Gtid: "MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-615"},
+		}},
+		Keyspace: "ks",
+		Shard:    "-80",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &binlogdatapb.VEvent{
+		Type:     binlogdatapb.VEventType_GTID,
+		Gtid:     "MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-615",
+		Keyspace: "ks",
+		Shard:    "-80",
+	}, event)
+
+	_, err = vc.shardEvent(&binlogdatapb.VEvent{
+		Type: binlogdatapb.VEventType_VGTID,
+		Vgtid: &binlogdatapb.VGtid{ShardGtids: []*binlogdatapb.ShardGtid{
+			{Keyspace: "ks", Shard: "80-", Gtid: "MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-615"},
+		}},
+	})
+	assert.ErrorContains(t, err, "does not have a position for ks/-80")
+
+	event, err = vc.shardEvent(&binlogdatapb.VEvent{
+		Type:       binlogdatapb.VEventType_FIELD,
+		FieldEvent: &binlogdatapb.FieldEvent{TableName: "ks.t1"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "t1", event.FieldEvent.TableName)
+
+	event, err = vc.shardEvent(&binlogdatapb.VEvent{
+		Type:     binlogdatapb.VEventType_ROW,
+		RowEvent: &binlogdatapb.RowEvent{TableName: "ks.t1"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "t1", event.RowEvent.TableName)
+
+	commit := &binlogdatapb.VEvent{Type: binlogdatapb.VEventType_COMMIT}
+	event, err = vc.shardEvent(commit)
+	require.NoError(t, err)
+	assert.Equal(t, commit, event)
+}
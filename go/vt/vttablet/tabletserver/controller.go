@@ -30,6 +30,7 @@ import (
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
@@ -66,6 +67,22 @@ type Controller interface {
 	// ClearQueryPlanCache clears internal query plan cache
 	ClearQueryPlanCache()
 
+	// GetPlanCache returns the query plans of the plan cache, optionally
+	// filtered by tables and by the fingerprints of their queries
+	GetPlanCache(tables, fingerprints []string) []*tabletmanagerdatapb.PlanCacheEntry
+
+	// GetTableStats returns the rows and bytes read, returned and affected
+	// by the queries per table, optionally filtered by tables
+	GetTableStats(tables []string) []*tabletmanagerdatapb.TableStats
+
+	// InvalidatePlanCache removes query plans from the plan cache, and
+	// returns their number
+	InvalidatePlanCache(tables, fingerprints []string, all bool) int
+
+	// UnresolvedTransactions returns the metadata of the distributed transactions
+	// created more than abandonAge ago that are not resolved yet
+	UnresolvedTransactions(ctx context.Context, abandonAge time.Duration) ([]*querypb.TransactionMetadata, error)
+
 	// ReloadSchema makes the quey service reload its schema cache
 	ReloadSchema(ctx context.Context) error
 
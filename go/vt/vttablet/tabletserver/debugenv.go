@@ -135,6 +135,12 @@ func debugEnvHandler(tsv *TabletServer, w http.ResponseWriter, r *http.Request)
 		case "Consolidator":
 			tsv.SetConsolidatorMode(value)
 			msg = fmt.Sprintf("Setting %v to: %v", varname, value)
+		case "HotRowProtectionTableConfig":
+			if err := tsv.SetHotRowProtectionTableConfigs(value); err != nil {
+				msg = fmt.Sprintf("Failed setting value for %v: %v", varname, err)
+			} else {
+				msg = fmt.Sprintf("Setting %v to: %v", varname, value)
+			}
 		}
 	}
 
@@ -153,6 +159,7 @@ func debugEnvHandler(tsv *TabletServer, w http.ResponseWriter, r *http.Request)
 		Name:  "Consolidator",
 		Value: tsv.ConsolidatorMode(),
 	})
+	vars = addVar(vars, "HotRowProtectionTableConfig", tsv.HotRowProtectionTableConfigs)
 
 	format := r.FormValue("format")
 	if format == "json" {
@@ -327,6 +327,7 @@ func (hs *healthStreamer) MakePrimary(serving bool) {
 				log.Errorf("periodic schema reload failed in health stream: %v", err)
 			}
 		}, false)
+		hs.se.RegisterRoutineNotifier("healthStreamer", hs.reloadRoutines)
 	}
 }
 
@@ -395,6 +396,35 @@ func (hs *healthStreamer) reload(full map[string]*schema.Table, created, altered
 	return nil
 }
 
+// reloadRoutines signals the stored procedures and functions that have schema changes.
+func (hs *healthStreamer) reloadRoutines(changed []*schema.Routine) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	if !hs.isServingPrimary {
+		return
+	}
+
+	var procedures, functions []string
+	for _, routine := range changed {
+		switch routine.Type {
+		case schema.Procedure:
+			procedures = append(procedures, routine.Name)
+		case schema.Function:
+			functions = append(functions, routine.Name)
+		}
+	}
+	if len(procedures) == 0 && len(functions) == 0 {
+		return
+	}
+
+	hs.state.RealtimeStats.ProcedureSchemaChanged = procedures
+	hs.state.RealtimeStats.FunctionSchemaChanged = functions
+	shr := proto.Clone(hs.state).(*querypb.StreamHealthResponse)
+	hs.broadCastToClients(shr)
+	hs.state.RealtimeStats.ProcedureSchemaChanged = nil
+	hs.state.RealtimeStats.FunctionSchemaChanged = nil
+}
+
 func (hs *healthStreamer) reloadTables(ctx context.Context, conn *connpool.DBConn, tableNames []string) error {
 	if len(tableNames) == 0 {
 		return nil
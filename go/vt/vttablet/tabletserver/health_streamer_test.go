@@ -273,6 +273,8 @@ func TestReloadSchema(t *testing.T) {
 				"product|id",
 				"users|id",
 			))
+			db.AddQuery(mysql.BaseShowUniqueKeys, sqltypes.MakeTestResult(mysql.ShowUniqueKeysFields))
+			db.AddQuery(mysql.BaseShowRoutines, sqltypes.MakeTestResult(mysql.ShowRoutinesFields))
 
 			hs.InitDBConfig(target, configs.DbaWithDB())
 			se.InitDBConfig(configs.DbaWithDB())
@@ -383,6 +385,8 @@ func TestReloadView(t *testing.T) {
 	db.AddQuery(mysql.BaseShowPrimary, sqltypes.MakeTestResult(
 		sqltypes.MakeTestFields("table_name | column_name", "varchar|varchar"),
 	))
+	db.AddQuery(mysql.BaseShowUniqueKeys, sqltypes.MakeTestResult(mysql.ShowUniqueKeysFields))
+	db.AddQuery(mysql.BaseShowRoutines, sqltypes.MakeTestResult(mysql.ShowRoutinesFields))
 	db.AddQueryPattern(".*SELECT table_name, view_definition.*views.*", &sqltypes.Result{})
 	db.AddQuery("SELECT TABLE_NAME, CREATE_TIME FROM _vt.`tables`", &sqltypes.Result{})
 
@@ -541,3 +545,88 @@ func testStream(hs *healthStreamer) (<-chan *querypb.StreamHealthResponse, conte
 func testBlpFunc() (int64, int32) {
 	return 1, 2
 }
+
+// TestReloadRoutines tests that the health streamer tracks the changes of the stored procedures and functions.
+func TestReloadRoutines(t *testing.T) {
+	db := fakesqldb.New(t)
+	defer db.Close()
+	config := newConfig(db)
+	config.SignalWhenSchemaChange = true
+	_ = config.SchemaReloadIntervalSeconds.Set("100ms")
+
+	env := tabletenv.NewEnv(config, "TestReloadRoutines")
+	alias := &topodatapb.TabletAlias{Cell: "cell", Uid: 1}
+	se := schema.NewEngine(env)
+	hs := newHealthStreamer(env, alias, se)
+
+	target := &querypb.Target{TabletType: topodatapb.TabletType_PRIMARY}
+	configs := config.DB
+
+	db.AddQueryPattern("SELECT UNIX_TIMESTAMP()"+".*", sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"UNIX_TIMESTAMP(now())",
+			"varchar",
+		),
+		"1684759138",
+	))
+	db.AddQueryPattern("SELECT .* information_schema.innodb_tablespaces .*",
+		sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields(
+				"TABLE_NAME | TABLE_TYPE | UNIX_TIMESTAMP(t.create_time) | TABLE_COMMENT | SUM(i.file_size) | SUM(i.allocated_size)",
+				"varchar|varchar|int64|varchar|int64|int64",
+			),
+		))
+	db.AddQuery(mysql.ShowRowsRead, sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("Variable_name|Value", "varchar|int32"),
+		"Innodb_rows_read|50"))
+	db.AddQuery(mysql.BaseShowPrimary, sqltypes.MakeTestResult(mysql.ShowPrimaryFields))
+	db.AddQuery(mysql.BaseShowUniqueKeys, sqltypes.MakeTestResult(mysql.ShowUniqueKeysFields))
+	db.AddQueryPattern(".*SELECT table_name, view_definition.*views.*", &sqltypes.Result{})
+	db.AddQuery("SELECT TABLE_NAME, CREATE_TIME FROM _vt.`tables`", &sqltypes.Result{})
+	db.AddQuery(mysql.BaseShowRoutines, &sqltypes.Result{
+		Fields: mysql.ShowRoutinesFields,
+		Rows: [][]sqltypes.Value{
+			mysql.ShowRoutinesRow(schema.Function, "f1", "2023-06-01 10:00:00", "2023-06-01 10:00:00", "return 1"),
+			mysql.ShowRoutinesRow(schema.Procedure, "p1", "2023-06-01 10:00:00", "2023-06-01 10:00:00", "select 1"),
+		},
+	})
+
+	hs.InitDBConfig(target, configs.DbaWithDB())
+	se.InitDBConfig(configs.DbaWithDB())
+	hs.Open()
+	defer hs.Close()
+	err := se.Open()
+	require.NoError(t, err)
+	se.MakePrimary(true)
+	defer se.Close()
+	// Start schema notifications.
+	hs.MakePrimary(true)
+
+	ch, cancel := testStream(hs)
+	defer cancel()
+
+	// p1 is altered, p2 is created and f1 is dropped.
+	db.AddQuery(mysql.BaseShowRoutines, &sqltypes.Result{
+		Fields: mysql.ShowRoutinesFields,
+		Rows: [][]sqltypes.Value{
+			mysql.ShowRoutinesRow(schema.Procedure, "p1", "2023-06-01 10:00:00", "2023-06-01 11:00:00", "select 2"),
+			mysql.ShowRoutinesRow(schema.Procedure, "p2", "2023-06-01 11:00:00", "2023-06-01 11:00:00", "select 3"),
+		},
+	})
+
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case shr := <-ch:
+			if shr.RealtimeStats.ProcedureSchemaChanged == nil && shr.RealtimeStats.FunctionSchemaChanged == nil {
+				continue
+			}
+			sort.Strings(shr.RealtimeStats.ProcedureSchemaChanged)
+			assert.Equal(t, []string{"p1", "p2"}, shr.RealtimeStats.ProcedureSchemaChanged)
+			assert.Equal(t, []string{"f1"}, shr.RealtimeStats.FunctionSchemaChanged)
+			return
+		case <-timeout:
+			t.Fatalf("timed out")
+		}
+	}
+}
@@ -60,6 +60,9 @@ func analyzeSelect(sel *sqlparser.Select, tables map[string]*schema.Table) (plan
 		plan.PlanID = PlanSelectLockFunc
 		plan.NeedsReservedConn = true
 	}
+	if plan.PlanID == PlanSelect {
+		plan.PagedQuery = GeneratePagedQuery(sel)
+	}
 	return plan, nil
 }
 
@@ -75,6 +78,7 @@ func analyzeUpdate(upd *sqlparser.Update, tables map[string]*schema.Table) (plan
 		buf := sqlparser.NewTrackedBuffer(nil)
 		buf.Myprintf("%v", upd.Where)
 		plan.WhereClause = buf.ParsedQuery()
+		plan.HotRowWhereClause = hotRowWhereClause(upd.Where, plan.Table)
 	}
 
 	// Situations when we pass-through:
@@ -93,6 +97,63 @@ func analyzeUpdate(upd *sqlparser.Update, tables map[string]*schema.Table) (plan
 	return plan, nil
 }
 
+// hotRowWhereClause returns the WHERE clause of the first unique key of the table,
+// starting with the primary key, whose columns are all compared for equality to
+// a value by the WHERE clause of the DML. It returns nil if there is none.
+func hotRowWhereClause(where *sqlparser.Where, table *schema.Table) *sqlparser.ParsedQuery {
+	if table == nil {
+		return nil
+	}
+	values := make(map[int]sqlparser.Expr)
+	for _, expr := range sqlparser.SplitAndExpression(nil, where.Expr) {
+		cmp, ok := expr.(*sqlparser.ComparisonExpr)
+		if !ok || cmp.Operator != sqlparser.EqualOp {
+			continue
+		}
+		col, val := cmp.Left, cmp.Right
+		if _, ok := col.(*sqlparser.ColName); !ok {
+			col, val = val, col
+		}
+		colName, ok := col.(*sqlparser.ColName)
+		if !ok {
+			continue
+		}
+		switch val.(type) {
+		case *sqlparser.Literal, *sqlparser.Argument:
+		default:
+			continue
+		}
+		if index := table.FindColumn(colName.Name); index >= 0 {
+			values[index] = val
+		}
+	}
+
+	keys := append([][]int{table.PKColumns}, table.UniqueKeys...)
+	for _, key := range keys {
+		if len(key) == 0 {
+			continue
+		}
+		exprs := make([]sqlparser.Expr, 0, len(key))
+		for _, index := range key {
+			val, ok := values[index]
+			if !ok {
+				break
+			}
+			exprs = append(exprs, &sqlparser.ComparisonExpr{
+				Operator: sqlparser.EqualOp,
+				Left:     sqlparser.NewColName(table.Fields[index].Name),
+				Right:    val,
+			})
+		}
+		if len(exprs) == len(key) {
+			buf := sqlparser.NewTrackedBuffer(nil)
+			buf.Myprintf("%v", sqlparser.NewWhere(sqlparser.WhereClause, sqlparser.AndExpressions(exprs...)))
+			return buf.ParsedQuery()
+		}
+	}
+	return nil
+}
+
 // analyzeDelete code is almost identical to analyzeUpdate.
 func analyzeDelete(del *sqlparser.Delete, tables map[string]*schema.Table) (plan *Plan, err error) {
 	plan = &Plan{
@@ -104,6 +165,7 @@ func analyzeDelete(del *sqlparser.Delete, tables map[string]*schema.Table) (plan
 		buf := sqlparser.NewTrackedBuffer(nil)
 		buf.Myprintf("%v", del.Where)
 		plan.WhereClause = buf.ParsedQuery()
+		plan.HotRowWhereClause = hotRowWhereClause(del.Where, plan.Table)
 	}
 
 	if PassthroughDMLs || plan.Table == nil || del.Limit != nil {
@@ -41,7 +41,7 @@ func (cached *Plan) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(128)
+		size += int64(160)
 	}
 	// field Table *vitess.io/vitess/go/vt/vttablet/tabletserver/schema.Table
 	size += cached.Table.CachedSize(true)
@@ -61,12 +61,18 @@ func (cached *Plan) CachedSize(alloc bool) int64 {
 	}
 	// field FullQuery *vitess.io/vitess/go/vt/sqlparser.ParsedQuery
 	size += cached.FullQuery.CachedSize(true)
+	// field PagedQuery *vitess.io/vitess/go/vt/sqlparser.ParsedQuery
+	size += cached.PagedQuery.CachedSize(true)
 	// field NextCount vitess.io/vitess/go/vt/vtgate/evalengine.Expr
 	if cc, ok := cached.NextCount.(cachedObject); ok {
 		size += cc.CachedSize(true)
 	}
 	// field WhereClause *vitess.io/vitess/go/vt/sqlparser.ParsedQuery
 	size += cached.WhereClause.CachedSize(true)
+	// field HotRowWhereClause *vitess.io/vitess/go/vt/sqlparser.ParsedQuery
+	size += cached.HotRowWhereClause.CachedSize(true)
+	// field QueryPool string
+	size += hack.RuntimeAllocSize(int64(len(cached.QueryPool)))
 	// field FullStmt vitess.io/vitess/go/vt/sqlparser.Statement
 	if cc, ok := cached.FullStmt.(cachedObject); ok {
 		size += cc.CachedSize(true)
@@ -33,6 +33,9 @@ import (
 var (
 	execLimit = &sqlparser.Limit{Rowcount: sqlparser.NewArgument("#maxLimit")}
 
+	// pagedLimit skips the rows of the previous pages of a paged select.
+	pagedLimit = &sqlparser.Limit{Offset: sqlparser.NewArgument("#offset"), Rowcount: sqlparser.NewArgument("#maxLimit")}
+
 	// PassthroughDMLs will return plans that pass-through the DMLs without changing them.
 	PassthroughDMLs = false
 )
@@ -166,6 +169,10 @@ type Plan struct {
 	// FullQuery will be set for all plans.
 	FullQuery *sqlparser.ParsedQuery
 
+	// PagedQuery is set for the selects without a limit, whose results
+	// can be paged. It fetches the rows after the #offset first rows.
+	PagedQuery *sqlparser.ParsedQuery
+
 	// NextCount stores the count for "select next".
 	NextCount evalengine.Expr
 
@@ -173,6 +180,14 @@ type Plan struct {
 	// to serialize e.g. UPDATEs going to the same row.
 	WhereClause *sqlparser.ParsedQuery
 
+	// HotRowWhereClause is set for the DMLs pinning a unique key of their table.
+	// It restricts the WHERE clause to the unique key, so that the hot row protection
+	// serializes the DMLs going to the same row regardless of their other predicates.
+	HotRowWhereClause *sqlparser.ParsedQuery
+
+	// QueryPool is the named query pool selected by the QUERY_POOL directive of the query.
+	QueryPool string
+
 	// FullStmt can be used when the query does not operate on tables
 	FullStmt sqlparser.Statement
 
@@ -206,8 +221,9 @@ func Build(statement sqlparser.Statement, tables map[string]*schema.Table, dbNam
 	switch stmt := statement.(type) {
 	case *sqlparser.Union:
 		plan, err = &Plan{
-			PlanID:    PlanSelect,
-			FullQuery: GenerateLimitQuery(stmt),
+			PlanID:     PlanSelect,
+			FullQuery:  GenerateLimitQuery(stmt),
+			PagedQuery: GeneratePagedQuery(stmt),
 		}, nil
 	case *sqlparser.Select:
 		plan, err = analyzeSelect(stmt, tables)
@@ -256,6 +272,7 @@ func Build(statement sqlparser.Statement, tables map[string]*schema.Table, dbNam
 		return nil, err
 	}
 	plan.Permissions = BuildPermissions(statement)
+	plan.QueryPool = sqlparser.GetQueryPoolFromStatement(statement)
 	return plan, nil
 }
 
@@ -270,6 +287,7 @@ func BuildStreaming(sql string, tables map[string]*schema.Table) (*Plan, error)
 		PlanID:      PlanSelectStream,
 		FullQuery:   GenerateFullQuery(statement),
 		Permissions: BuildPermissions(statement),
+		QueryPool:   sqlparser.GetQueryPoolFromStatement(statement),
 	}
 
 	switch stmt := statement.(type) {
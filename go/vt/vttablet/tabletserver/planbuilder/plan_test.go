@@ -35,6 +35,8 @@ import (
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/tableacl"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/schema"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
 )
 
 // MarshalJSON returns a JSON of the given Plan.
@@ -150,6 +152,90 @@ func TestPlanInReservedConn(t *testing.T) {
 	}
 }
 
+func TestHotRowWhereClause(t *testing.T) {
+	tables := map[string]*schema.Table{
+		"t": {
+			Name: sqlparser.NewIdentifierCS("t"),
+			Fields: []*querypb.Field{
+				{Name: "id"},
+				{Name: "eid"},
+				{Name: "email"},
+				{Name: "name"},
+			},
+			PKColumns:  []int{0, 1},
+			UniqueKeys: [][]int{{2}},
+		},
+	}
+	tcases := []struct {
+		query string
+		where string
+	}{{
+		query: "update t set name = 'a' where name = 'b' and eid = :eid and id = 1",
+		where: " where id = 1 and eid = :eid",
+	}, {
+		query: "delete from t where email = 'a@b.c' and id > 1",
+		where: " where email = 'a@b.c'",
+	}, {
+		query: "update t set name = 'a' where 'a@b.c' = email",
+		where: " where email = 'a@b.c'",
+	}, {
+		query: "update t set name = 'a' where id = 1 or email = 'a@b.c'",
+	}, {
+		query: "delete from t where id = 1",
+	}, {
+		query: "delete from t where email = name",
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.query, func(t *testing.T) {
+			statement, err := sqlparser.Parse(tcase.query)
+			require.NoError(t, err)
+			plan, err := Build(statement, tables, "dbName", false)
+			require.NoError(t, err)
+			if tcase.where == "" {
+				require.Nil(t, plan.HotRowWhereClause)
+				return
+			}
+			require.NotNil(t, plan.HotRowWhereClause)
+			require.Equal(t, tcase.where, plan.HotRowWhereClause.Query)
+		})
+	}
+}
+
+func TestPagedQuery(t *testing.T) {
+	tcases := []struct {
+		query string
+		paged string
+	}{{
+		query: "select * from a order by id",
+		paged: "select * from a order by id asc limit :#offset, :#maxLimit",
+	}, {
+		query: "select id from a union select id from b",
+		paged: "select id from a union select id from b limit :#offset, :#maxLimit",
+	}, {
+		query: "select * from a limit 10",
+	}, {
+		query: "select * from a where 1 != 1",
+	}, {
+		query: "select get_lock('a', 10) from dual",
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.query, func(t *testing.T) {
+			statement, err := sqlparser.Parse(tcase.query)
+			require.NoError(t, err)
+			plan, err := Build(statement, map[string]*schema.Table{}, "dbName", false)
+			require.NoError(t, err)
+			if tcase.paged == "" {
+				require.Nil(t, plan.PagedQuery)
+				return
+			}
+			require.NotNil(t, plan.PagedQuery)
+			require.Equal(t, tcase.paged, plan.PagedQuery.Query)
+			// The full query is not altered by the paged query.
+			require.NotContains(t, plan.FullQuery.Query, "#offset")
+		})
+	}
+}
+
 func TestCustom(t *testing.T) {
 	testSchemas, _ := filepath.Glob("testdata/*_schema.json")
 	if len(testSchemas) == 0 {
@@ -64,3 +64,14 @@ func GenerateLimitQuery(selStmt sqlparser.SelectStatement) *sqlparser.ParsedQuer
 	buf.Myprintf("%v", selStmt)
 	return buf.ParsedQuery()
 }
+
+// GeneratePagedQuery generates a select query fetching a page of its rows,
+// or returns nil if the query has its own limit clause.
+func GeneratePagedQuery(selStmt sqlparser.SelectStatement) *sqlparser.ParsedQuery {
+	if selStmt.GetLimit() != nil {
+		return nil
+	}
+	selStmt.SetLimit(pagedLimit)
+	defer selStmt.SetLimit(nil)
+	return GenerateFullQuery(selStmt)
+}
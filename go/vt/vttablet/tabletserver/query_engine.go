@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -31,6 +32,7 @@ import (
 	"vitess.io/vitess/go/cache"
 	"vitess.io/vitess/go/mysql/sqlerror"
 	"vitess.io/vitess/go/pools"
+	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/stats"
 	"vitess.io/vitess/go/streamlog"
 	"vitess.io/vitess/go/sync2"
@@ -38,6 +40,7 @@ import (
 	"vitess.io/vitess/go/vt/dbconnpool"
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/logutil"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	"vitess.io/vitess/go/vt/proto/topodata"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/sqlparser"
@@ -91,6 +94,21 @@ func (ep *TabletPlan) Stats() (queryCount uint64, duration, mysqlTime time.Durat
 	return
 }
 
+// fingerprint returns the fingerprint of the query of the plan.
+func (ep *TabletPlan) fingerprint() string {
+	return queryFingerprint(ep.Original)
+}
+
+// queryFingerprint returns the fingerprint of a query, or the query itself if it
+// cannot be parsed. A fingerprint is its own fingerprint.
+func queryFingerprint(query string) string {
+	stmt, err := sqlparser.Parse(query)
+	if err != nil {
+		return query
+	}
+	return sqlparser.Fingerprint(stmt)
+}
+
 // buildAuthorized builds 'Authorized', which is the runtime part for 'Permissions'.
 func (ep *TabletPlan) buildAuthorized() {
 	ep.Authorized = make([]*tableacl.ACLResult, len(ep.Permissions))
@@ -143,6 +161,7 @@ type QueryEngine struct {
 	// Pools
 	conns       *connpool.Pool
 	streamConns *connpool.Pool
+	queryPools  *queryPools
 
 	// Services
 	consolidator       sync2.Consolidator
@@ -173,6 +192,8 @@ type QueryEngine struct {
 	// stats
 	// Note: queryErrorCountsWithCode is similar to queryErrorCounts except it contains error code as an additional dimension
 	queryCounts, queryCountsWithTabletType, queryTimes, queryErrorCounts, queryErrorCountsWithCode, queryRowsAffected, queryRowsReturned *stats.CountersWithMultiLabels
+	// Note: the table stats are aggregated per table only, for capacity planning
+	tableRowsRead, tableRowsReturned, tableRowsAffected, tableBytesSent *stats.CountersWithSingleLabel
 
 	// stats flags
 	enablePerWorkloadTableMetrics bool
@@ -203,6 +224,7 @@ func NewQueryEngine(env tabletenv.Env, se *schema.Engine) *QueryEngine {
 
 	qe.conns = connpool.NewPool(env, "ConnPool", config.OltpReadPool)
 	qe.streamConns = connpool.NewPool(env, "StreamConnPool", config.OlapReadPool)
+	qe.queryPools = newQueryPools(env)
 	qe.consolidatorMode.Store(config.Consolidator)
 	qe.consolidator = sync2.NewConsolidator()
 	if config.ConsolidatorStreamTotalSize > 0 && config.ConsolidatorStreamQuerySize > 0 {
@@ -265,6 +287,11 @@ func NewQueryEngine(env tabletenv.Env, se *schema.Engine) *QueryEngine {
 	qe.queryErrorCounts = env.Exporter().NewCountersWithMultiLabels("QueryErrorCounts", "query error counts", labels)
 	qe.queryErrorCountsWithCode = env.Exporter().NewCountersWithMultiLabels("QueryErrorCountsWithCode", "query error counts with error code", []string{"Table", "Plan", "Code"})
 
+	qe.tableRowsRead = env.Exporter().NewCountersWithSingleLabel("TableRowsRead", "rows read from mysql per table", "Table")
+	qe.tableRowsReturned = env.Exporter().NewCountersWithSingleLabel("TableRowsReturned", "rows returned to the clients per table", "Table")
+	qe.tableRowsAffected = env.Exporter().NewCountersWithSingleLabel("TableRowsAffected", "rows affected per table", "Table")
+	qe.tableBytesSent = env.Exporter().NewCountersWithSingleLabel("TableBytesSent", "bytes of the rows returned to the clients per table", "Table")
+
 	env.Exporter().HandleFunc("/debug/hotrows", qe.txSerializer.ServeHTTP)
 	env.Exporter().HandleFunc("/debug/tablet_plans", qe.handleHTTPQueryPlans)
 	env.Exporter().HandleFunc("/debug/query_stats", qe.handleHTTPQueryStats)
@@ -300,6 +327,7 @@ func (qe *QueryEngine) Open() error {
 	}
 
 	qe.streamConns.Open(qe.env.Config().DB.AppWithDB(), qe.env.Config().DB.DbaWithDB(), qe.env.Config().DB.AppDebugWithDB())
+	qe.queryPools.open(qe.env.Config().DB.AppWithDB(), qe.env.Config().DB.DbaWithDB(), qe.env.Config().DB.AppDebugWithDB())
 	qe.se.RegisterNotifier("qe", qe.schemaChanged, true)
 	qe.isOpen = true
 	return nil
@@ -316,6 +344,7 @@ func (qe *QueryEngine) Close() {
 	qe.se.UnregisterNotifier("qe")
 	qe.plans.Close()
 	qe.tables = make(map[string]*schema.Table)
+	qe.queryPools.close()
 	qe.streamConns.Close()
 	qe.conns.Close()
 	qe.isOpen = false
@@ -490,6 +519,111 @@ func (qe *QueryEngine) QueryPlanCacheLen() int {
 	return qe.plans.Len()
 }
 
+// planCacheFilter selects the plans of the plan cache by their tables, or by
+// the fingerprints of their queries.
+type planCacheFilter struct {
+	tables       map[string]bool
+	fingerprints map[string]bool
+}
+
+func newPlanCacheFilter(tables, fingerprints []string) *planCacheFilter {
+	filter := &planCacheFilter{
+		tables:       make(map[string]bool, len(tables)),
+		fingerprints: make(map[string]bool, len(fingerprints)),
+	}
+	for _, table := range tables {
+		filter.tables[table] = true
+	}
+	// The queries are accepted in place of their fingerprints.
+	for _, fingerprint := range fingerprints {
+		filter.fingerprints[queryFingerprint(fingerprint)] = true
+	}
+	return filter
+}
+
+func (filter *planCacheFilter) matchesTables(plan *TabletPlan) bool {
+	for _, table := range plan.TableNames() {
+		if filter.tables[table] {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPlanCache returns the plans of the plan cache, the most used first. If tables
+// are set, only the plans of these tables are returned, and if fingerprints are set,
+// only the plans of the queries with these fingerprints.
+func (qe *QueryEngine) GetPlanCache(tables, fingerprints []string) []*tabletmanagerdatapb.PlanCacheEntry {
+	filter := newPlanCacheFilter(tables, fingerprints)
+	qe.plans.Wait()
+	var entries []*tabletmanagerdatapb.PlanCacheEntry
+	qe.plans.ForEach(func(value any) bool {
+		plan, ok := value.(*TabletPlan)
+		if !ok {
+			return true
+		}
+		if len(filter.tables) > 0 && !filter.matchesTables(plan) {
+			return true
+		}
+		fingerprint := plan.fingerprint()
+		if len(filter.fingerprints) > 0 && !filter.fingerprints[fingerprint] {
+			return true
+		}
+		queryCount, duration, mysqlTime, rowsAffected, rowsReturned, errorCount := plan.Stats()
+		entry := &tabletmanagerdatapb.PlanCacheEntry{
+			Query:        sqlparser.TruncateForUI(plan.Original),
+			Fingerprint:  fingerprint,
+			PlanType:     plan.PlanID.String(),
+			Tables:       plan.TableNames(),
+			QueryCount:   queryCount,
+			RowsAffected: rowsAffected,
+			RowsReturned: rowsReturned,
+			ErrorCount:   errorCount,
+		}
+		if queryCount > 0 {
+			entry.AverageTime = protoutil.DurationToProto(duration / time.Duration(queryCount))
+			entry.AverageMysqlTime = protoutil.DurationToProto(mysqlTime / time.Duration(queryCount))
+		}
+		entries = append(entries, entry)
+		return true
+	})
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].QueryCount != entries[j].QueryCount {
+			return entries[i].QueryCount > entries[j].QueryCount
+		}
+		return entries[i].Query < entries[j].Query
+	})
+	return entries
+}
+
+// InvalidatePlanCache removes from the plan cache the plans of the tables and the
+// plans of the queries with the fingerprints, or all the plans, and returns the
+// number of removed plans. The removed plans are built again when their queries
+// are executed next.
+func (qe *QueryEngine) InvalidatePlanCache(tables, fingerprints []string, all bool) int {
+	filter := newPlanCacheFilter(tables, fingerprints)
+	qe.plans.Wait()
+	var queries []string
+	qe.plans.ForEach(func(value any) bool {
+		plan, ok := value.(*TabletPlan)
+		if !ok {
+			return true
+		}
+		if all || filter.matchesTables(plan) || (len(filter.fingerprints) > 0 && filter.fingerprints[plan.fingerprint()]) {
+			queries = append(queries, plan.Original)
+		}
+		return true
+	})
+	if all {
+		qe.plans.Clear()
+		return len(queries)
+	}
+	for _, query := range queries {
+		qe.plans.Delete(query)
+	}
+	return len(queries)
+}
+
 // AddStats adds the given stats for the planName.tableName
 func (qe *QueryEngine) AddStats(planType planbuilder.PlanType, tableName, workload string, tabletType topodata.TabletType, queryCount int64, duration, mysqlTime time.Duration, rowsAffected, rowsReturned, errorCount int64, errorCode string) {
 	// table names can contain "." characters, replace them!
@@ -528,6 +662,47 @@ func (qe *QueryEngine) AddStats(planType planbuilder.PlanType, tableName, worklo
 	}
 }
 
+// AddTableStats adds the rows and bytes of a query to the stats of its table.
+// The rows read are the rows fetched from MySQL, which are fewer than the rows
+// returned when the consolidator shares a result between several clients.
+func (qe *QueryEngine) AddTableStats(tableName string, rowsRead, rowsReturned, rowsAffected, bytesSent int64) {
+	qe.tableRowsRead.Add(tableName, rowsRead)
+	qe.tableRowsReturned.Add(tableName, rowsReturned)
+	qe.tableRowsAffected.Add(tableName, rowsAffected)
+	qe.tableBytesSent.Add(tableName, bytesSent)
+}
+
+// GetTableStats returns the stats of the tables, or of all the tables if none
+// is specified, sorted by table name.
+func (qe *QueryEngine) GetTableStats(tables []string) []*tabletmanagerdatapb.TableStats {
+	rowsRead := qe.tableRowsRead.Counts()
+	rowsReturned := qe.tableRowsReturned.Counts()
+	rowsAffected := qe.tableRowsAffected.Counts()
+	bytesSent := qe.tableBytesSent.Counts()
+	if len(tables) == 0 {
+		for table := range rowsRead {
+			tables = append(tables, table)
+		}
+	}
+	var tableStats []*tabletmanagerdatapb.TableStats
+	for _, table := range tables {
+		if _, ok := rowsRead[table]; !ok {
+			continue
+		}
+		tableStats = append(tableStats, &tabletmanagerdatapb.TableStats{
+			Name:         table,
+			RowsRead:     uint64(rowsRead[table]),
+			RowsReturned: uint64(rowsReturned[table]),
+			RowsAffected: uint64(rowsAffected[table]),
+			BytesSent:    uint64(bytesSent[table]),
+		})
+	}
+	sort.Slice(tableStats, func(i, j int) bool {
+		return tableStats[i].Name < tableStats[j].Name
+	})
+	return tableStats
+}
+
 type perQueryStats struct {
 	Query        string
 	Table        string
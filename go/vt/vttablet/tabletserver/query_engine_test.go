@@ -192,7 +192,7 @@ func TestQueryPlanCache(t *testing.T) {
 	logStats := tabletenv.NewLogStats(ctx, "GetPlanStats")
 	if cache.DefaultConfig.LFU {
 		// this cache capacity is in bytes
-		qe.SetQueryPlanCacheCap(528)
+		qe.SetQueryPlanCacheCap(800)
 	} else {
 		// this cache capacity is in number of elements
 		qe.SetQueryPlanCacheCap(1)
@@ -268,6 +268,60 @@ func TestNoQueryPlanCacheDirective(t *testing.T) {
 	qe.ClearQueryPlanCache()
 }
 
+func TestGetAndInvalidatePlanCache(t *testing.T) {
+	db := fakesqldb.New(t)
+	defer db.Close()
+	schematest.AddDefaultQueries(db)
+
+	queries := []string{
+		"select * from test_table_01 where pk = 1",
+		"select * from test_table_01 where pk = 2",
+		"select * from test_table_02",
+	}
+	db.AddQuery("select * from test_table_01 where 1 != 1", &sqltypes.Result{})
+	db.AddQuery("select * from test_table_02 where 1 != 1", &sqltypes.Result{})
+	addSchemaEngineQueries(db)
+
+	qe := newTestQueryEngine(10*time.Second, true, newDBConfigs(db))
+	qe.se.Open()
+	qe.Open()
+	defer qe.Close()
+
+	ctx := context.Background()
+	logStats := tabletenv.NewLogStats(ctx, "GetPlanStats")
+	qe.SetQueryPlanCacheCap(1024 * 1024)
+	for i, query := range queries {
+		plan, err := qe.GetPlan(ctx, logStats, query, false)
+		require.NoError(t, err)
+		plan.AddStats(uint64(i+1), time.Duration(i+1)*time.Second, 0, 0, 0, 0)
+	}
+
+	entries := qe.GetPlanCache(nil, nil)
+	require.Len(t, entries, 3)
+	// The most used plans come first.
+	assert.Equal(t, queries[2], entries[0].Query)
+	assert.Equal(t, uint64(3), entries[0].QueryCount)
+	assert.Equal(t, "Select", entries[0].PlanType)
+	assert.Equal(t, []string{"test_table_02"}, entries[0].Tables)
+	assert.Equal(t, int64(1), entries[0].AverageTime.Seconds)
+
+	entries = qe.GetPlanCache([]string{"test_table_01"}, nil)
+	require.Len(t, entries, 2)
+	assert.Equal(t, entries[0].Fingerprint, entries[1].Fingerprint)
+
+	// A query is accepted in place of its fingerprint.
+	entries = qe.GetPlanCache(nil, []string{"select * from test_table_01 where pk = 3"})
+	require.Len(t, entries, 2)
+	entries = qe.GetPlanCache([]string{"test_table_02"}, []string{entries[0].Fingerprint})
+	assert.Empty(t, entries)
+
+	assert.Equal(t, 0, qe.InvalidatePlanCache([]string{"unknown"}, nil, false))
+	assert.Equal(t, 2, qe.InvalidatePlanCache(nil, []string{"select * from test_table_01 where pk = 3"}, false))
+	assertPlanCacheSize(t, qe, 1)
+	assert.Equal(t, 1, qe.InvalidatePlanCache(nil, nil, true))
+	assertPlanCacheSize(t, qe, 0)
+}
+
 func TestStatsURL(t *testing.T) {
 	db := fakesqldb.New(t)
 	defer db.Close()
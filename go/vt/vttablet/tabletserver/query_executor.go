@@ -66,6 +66,8 @@ type QueryExecutor struct {
 	tsv            *TabletServer
 	tabletType     topodatapb.TabletType
 	setting        *pools.Setting
+	// queryPool is the named query pool running the query, if any.
+	queryPool *queryPool
 }
 
 const (
@@ -148,11 +150,20 @@ func (qre *QueryExecutor) Execute() (reply *sqltypes.Result, err error) {
 		qre.logStats.RowsAffected = int(reply.RowsAffected)
 		qre.logStats.Rows = reply.Rows
 		qre.tsv.Stats().ResultHistogram.Add(int64(len(reply.Rows)))
+
+		rowsRead := int64(len(reply.Rows))
+		if qre.logStats.QuerySources&tabletenv.QuerySourceConsolidator != 0 {
+			rowsRead = 0
+		}
+		qre.tsv.qe.AddTableStats(tableName, rowsRead, int64(len(reply.Rows)), int64(reply.RowsAffected), int64(qre.logStats.SizeOfResponse()))
 	}(time.Now())
 
 	if err = qre.checkPermissions(); err != nil {
 		return nil, err
 	}
+	if err = qre.selectQueryPool(); err != nil {
+		return nil, err
+	}
 
 	if qre.plan.PlanID == p.PlanNextval {
 		return qre.execNextval()
@@ -176,19 +187,7 @@ func (qre *QueryExecutor) Execute() (reply *sqltypes.Result, err error) {
 
 	switch qre.plan.PlanID {
 	case p.PlanSelect, p.PlanSelectImpossible, p.PlanShow:
-		maxrows := qre.getSelectLimit()
-		qre.bindVars["#maxLimit"] = sqltypes.Int64BindVariable(maxrows + 1)
-		if qre.bindVars[sqltypes.BvReplaceSchemaName] != nil {
-			qre.bindVars[sqltypes.BvSchemaName] = sqltypes.StringBindVariable(qre.tsv.config.DB.DBName)
-		}
-		qr, err := qre.execSelect()
-		if err != nil {
-			return nil, err
-		}
-		if err := qre.verifyRowCount(int64(len(qr.Rows)), maxrows); err != nil {
-			return nil, err
-		}
-		return qr, nil
+		return qre.execSelectLimit(qre.execSelect)
 	case p.PlanOtherRead, p.PlanOtherAdmin, p.PlanFlush, p.PlanSavepoint, p.PlanRelease, p.PlanSRollback:
 		return qre.execOther()
 	case p.PlanInsert, p.PlanUpdate, p.PlanDelete, p.PlanInsertMessage, p.PlanDDL, p.PlanLoad:
@@ -286,19 +285,9 @@ func (qre *QueryExecutor) txConnExec(conn *StatefulConnection) (*sqltypes.Result
 	case p.PlanSavepoint, p.PlanRelease, p.PlanSRollback:
 		return qre.execStatefulConn(conn, qre.query, true)
 	case p.PlanSelect, p.PlanSelectImpossible, p.PlanShow, p.PlanSelectLockFunc:
-		maxrows := qre.getSelectLimit()
-		qre.bindVars["#maxLimit"] = sqltypes.Int64BindVariable(maxrows + 1)
-		if qre.bindVars[sqltypes.BvReplaceSchemaName] != nil {
-			qre.bindVars[sqltypes.BvSchemaName] = sqltypes.StringBindVariable(qre.tsv.config.DB.DBName)
-		}
-		qr, err := qre.txFetch(conn, false)
-		if err != nil {
-			return nil, err
-		}
-		if err := qre.verifyRowCount(int64(len(qr.Rows)), maxrows); err != nil {
-			return nil, err
-		}
-		return qr, nil
+		return qre.execSelectLimit(func(query *sqlparser.ParsedQuery) (*sqltypes.Result, error) {
+			return qre.txFetchQuery(conn, query, false)
+		})
 	case p.PlanDDL:
 		return qre.execDDL(conn)
 	case p.PlanLoad:
@@ -313,15 +302,40 @@ func (qre *QueryExecutor) txConnExec(conn *StatefulConnection) (*sqltypes.Result
 func (qre *QueryExecutor) Stream(callback StreamCallback) error {
 	qre.logStats.PlanType = qre.plan.PlanID.String()
 
+	var rowsReturned, bytesSent int64
 	defer func(start time.Time) {
 		qre.tsv.stats.QueryTimings.Record(qre.plan.PlanID.String(), start)
 		qre.tsv.stats.QueryTimingsByTabletType.Record(qre.tabletType.String(), start)
 		qre.recordUserQuery("Stream", int64(time.Since(start)))
+
+		tableName := qre.plan.TableName().String()
+		if tableName == "" {
+			tableName = "Join"
+		}
+		rowsRead := rowsReturned
+		if qre.logStats.QuerySources&tabletenv.QuerySourceConsolidator != 0 {
+			rowsRead = 0
+		}
+		qre.tsv.qe.AddTableStats(tableName, rowsRead, rowsReturned, 0, bytesSent)
 	}(time.Now())
 
+	streamCallback := callback
+	callback = func(result *sqltypes.Result) error {
+		rowsReturned += int64(len(result.Rows))
+		for _, row := range result.Rows {
+			for _, value := range row {
+				bytesSent += int64(value.Len())
+			}
+		}
+		return streamCallback(result)
+	}
+
 	if err := qre.checkPermissions(); err != nil {
 		return err
 	}
+	if err := qre.selectQueryPool(); err != nil {
+		return err
+	}
 
 	switch qre.plan.PlanID {
 	case p.PlanSelectStream:
@@ -687,10 +701,49 @@ func (qre *QueryExecutor) execNextval() (*sqltypes.Result, error) {
 	}, nil
 }
 
+// execSelectLimit fetches the rows of a select up to the max result size, and
+// fails if the select returns more rows. If the results of the select are paged,
+// it returns the rows of the requested page instead, along with the continuation
+// token of the next page if the page is full.
+func (qre *QueryExecutor) execSelectLimit(fetch func(query *sqlparser.ParsedQuery) (*sqltypes.Result, error)) (*sqltypes.Result, error) {
+	page, err := qre.resultPage()
+	if err != nil {
+		return nil, err
+	}
+	maxrows := qre.getSelectLimit()
+	if qre.bindVars[sqltypes.BvReplaceSchemaName] != nil {
+		qre.bindVars[sqltypes.BvSchemaName] = sqltypes.StringBindVariable(qre.tsv.config.DB.DBName)
+	}
+	if page != nil {
+		qre.bindVars["#offset"] = sqltypes.Int64BindVariable(page.offset)
+		qre.bindVars["#maxLimit"] = sqltypes.Int64BindVariable(maxrows)
+		qr, err := fetch(qre.plan.PagedQuery)
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(qr.Rows)) == maxrows {
+			// The result may be shared with consolidated queries.
+			qr = qr.ShallowCopy()
+			qr.ContinuationToken = page.continuationToken(maxrows)
+			qre.tsv.Stats().PagedResults.Add(1)
+		}
+		return qr, nil
+	}
+	qre.bindVars["#maxLimit"] = sqltypes.Int64BindVariable(maxrows + 1)
+	qr, err := fetch(qre.plan.FullQuery)
+	if err != nil {
+		return nil, err
+	}
+	if err := qre.verifyRowCount(int64(len(qr.Rows)), maxrows); err != nil {
+		return nil, err
+	}
+	return qr, nil
+}
+
 // execSelect sends a query to mysql only if another identical query is not running. Otherwise, it waits and
 // reuses the result. If the plan is missing field info, it sends the query to mysql requesting full info.
-func (qre *QueryExecutor) execSelect() (*sqltypes.Result, error) {
-	sql, sqlWithoutComments, err := qre.generateFinalSQL(qre.plan.FullQuery, qre.bindVars)
+func (qre *QueryExecutor) execSelect(query *sqlparser.ParsedQuery) (*sqltypes.Result, error) {
+	sql, sqlWithoutComments, err := qre.generateFinalSQL(query, qre.bindVars)
 	if err != nil {
 		return nil, err
 	}
@@ -774,8 +827,12 @@ func (qre *QueryExecutor) getConn() (*connpool.DBConn, error) {
 	span, ctx := trace.NewSpan(qre.ctx, "QueryExecutor.getConn")
 	defer span.Finish()
 
+	conns := qre.tsv.qe.conns
+	if qre.queryPool != nil {
+		conns = qre.queryPool.conns
+	}
 	start := time.Now()
-	conn, err := qre.tsv.qe.conns.Get(ctx, qre.setting)
+	conn, err := conns.Get(ctx, qre.setting)
 
 	switch err {
 	case nil:
@@ -791,8 +848,12 @@ func (qre *QueryExecutor) getStreamConn() (*connpool.DBConn, error) {
 	span, ctx := trace.NewSpan(qre.ctx, "QueryExecutor.getStreamConn")
 	defer span.Finish()
 
+	conns := qre.tsv.qe.streamConns
+	if qre.queryPool != nil {
+		conns = qre.queryPool.conns
+	}
 	start := time.Now()
-	conn, err := qre.tsv.qe.streamConns.Get(ctx, qre.setting)
+	conn, err := conns.Get(ctx, qre.setting)
 	switch err {
 	case nil:
 		qre.logStats.WaitingForConnection += time.Since(start)
@@ -803,9 +864,37 @@ func (qre *QueryExecutor) getStreamConn() (*connpool.DBConn, error) {
 	return nil, err
 }
 
+// selectQueryPool selects the named query pool of a query run outside of a transaction:
+// the pool of its QUERY_POOL directive, or else the pool of its user.
+func (qre *QueryExecutor) selectQueryPool() error {
+	if qre.connID != 0 {
+		return nil
+	}
+	user := callerid.GetUsername(callerid.ImmediateCallerIDFromContext(qre.ctx))
+	pool, err := qre.tsv.qe.queryPools.get(qre.plan.QueryPool, user)
+	if err != nil {
+		return err
+	}
+	qre.queryPool = pool
+	return nil
+}
+
+// withQueryPoolTimeout applies the query timeout of the named query pool of the
+// query to the context, so that the query is killed once it expires.
+func (qre *QueryExecutor) withQueryPoolTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if qre.queryPool == nil || qre.queryPool.queryTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, qre.queryPool.queryTimeout)
+}
+
 // txFetch fetches from a TxConnection.
 func (qre *QueryExecutor) txFetch(conn *StatefulConnection, record bool) (*sqltypes.Result, error) {
-	sql, _, err := qre.generateFinalSQL(qre.plan.FullQuery, qre.bindVars)
+	return qre.txFetchQuery(conn, qre.plan.FullQuery, record)
+}
+
+func (qre *QueryExecutor) txFetchQuery(conn *StatefulConnection, query *sqlparser.ParsedQuery, record bool) (*sqltypes.Result, error) {
+	sql, _, err := qre.generateFinalSQL(query, qre.bindVars)
 	if err != nil {
 		return nil, err
 	}
@@ -1075,6 +1164,8 @@ func (qre *QueryExecutor) execDBConn(conn *connpool.DBConn, sql string, wantfiel
 	qre.tsv.statelessql.Add(qd)
 	defer qre.tsv.statelessql.Remove(qd)
 
+	ctx, cancel := qre.withQueryPoolTimeout(ctx)
+	defer cancel()
 	return conn.Exec(ctx, sql, int(qre.tsv.qe.maxResultSize.Load()), wantfields)
 }
 
@@ -1115,6 +1206,8 @@ func (qre *QueryExecutor) execStreamSQL(conn *connpool.DBConn, isTransaction boo
 	}
 	qre.tsv.olapql.Add(qd)
 	defer qre.tsv.olapql.Remove(qd)
+	ctx, cancel := qre.withQueryPoolTimeout(ctx)
+	defer cancel()
 	return conn.Stream(ctx, sql, callBackClosingSpan, allocStreamResult, int(qre.tsv.qe.streamBufferSize.Load()), sqltypes.IncludeFieldsOrDefault(qre.options))
 }
 
@@ -1136,6 +1229,10 @@ func (qre *QueryExecutor) GetSchemaDefinitions(tableType querypb.SchemaTableType
 		return qre.getTableDefinitions(tableNames, callback)
 	case querypb.SchemaTableType_ALL:
 		return qre.getAllDefinitions(tableNames, callback)
+	case querypb.SchemaTableType_PROCEDURES:
+		return qre.getRoutineDefinitions(eschema.Procedure, tableNames, callback)
+	case querypb.SchemaTableType_FUNCTIONS:
+		return qre.getRoutineDefinitions(eschema.Function, tableNames, callback)
 	}
 	return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid table type %v", tableType)
 }
@@ -1164,6 +1261,39 @@ func (qre *QueryExecutor) getAllDefinitions(tableNames []string, callback func(s
 	return qre.executeGetSchemaQuery(query, callback)
 }
 
+// getRoutineDefinitions returns the create statements of the stored procedures or functions.
+// They are not stored in the sidecar database, so they are read from MySQL.
+func (qre *QueryExecutor) getRoutineDefinitions(routineType string, routineNames []string, callback func(schemaRes *querypb.GetSchemaResponse) error) error {
+	query, err := eschema.GetFetchRoutineQuery(routineType, routineNames)
+	if err != nil {
+		return err
+	}
+	conn, err := qre.getStreamConn()
+	if err != nil {
+		return err
+	}
+	defer conn.Recycle()
+
+	qr, err := qre.execDBConn(conn, query, false)
+	if err != nil {
+		return err
+	}
+	schemaDef := make(map[string]string, len(qr.Rows))
+	for _, row := range qr.Rows {
+		routineName := row[0].ToString()
+		res, err := qre.execDBConn(conn, eschema.GetCreateRoutineQuery(routineType, routineName), false)
+		if err != nil {
+			return err
+		}
+		// The create statement is NULL if the user cannot see the body of the routine.
+		if len(res.Rows) != 1 || len(res.Rows[0]) < 3 || res.Rows[0][2].IsNull() {
+			continue
+		}
+		schemaDef[routineName] = res.Rows[0][2].ToString()
+	}
+	return callback(&querypb.GetSchemaResponse{TableDefinition: schemaDef})
+}
+
 func (qre *QueryExecutor) executeGetSchemaQuery(query string, callback func(schemaRes *querypb.GetSchemaResponse) error) error {
 	conn, err := qre.getStreamConn()
 	if err != nil {
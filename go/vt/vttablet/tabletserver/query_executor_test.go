@@ -23,6 +23,7 @@ import (
 	"math/rand"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -47,6 +48,7 @@ import (
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	tableaclpb "vitess.io/vitess/go/vt/proto/tableacl"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
@@ -644,6 +646,107 @@ func TestQueryExecutorLimitFailure(t *testing.T) {
 	}
 }
 
+func TestQueryExecutorResultPaging(t *testing.T) {
+	db := setUpQueryExecutorTest(t)
+	defer db.Close()
+	fields := sqltypes.MakeTestFields("a|b", "int64|varchar")
+	db.AddQuery("select * from t where 1 != 1", sqltypes.MakeTestResult(fields))
+	db.AddQuery("select * from t order by a asc limit 0, 2", sqltypes.MakeTestResult(fields, "1|aaa", "2|bbb"))
+	db.AddQuery("select * from t order by a asc limit 2, 2", sqltypes.MakeTestResult(fields, "3|ccc"))
+	db.AddQuery("select * from t order by a asc limit 3", sqltypes.MakeTestResult(fields, "1|aaa", "2|bbb", "3|ccc"))
+	db.AddQuery("select * from t limit 5", sqltypes.MakeTestResult(fields, "1|aaa", "2|bbb", "3|ccc"))
+
+	ctx := context.Background()
+	tsv := newTestTabletServer(ctx, smallResultSize|enableResultPaging, db)
+	defer tsv.StopService()
+
+	fetchPages := func(t *testing.T, txID int64) {
+		qre := newTestQueryExecutor(ctx, tsv, "select * from t order by a", txID)
+		qre.options = &querypb.ExecuteOptions{ResultPaging: true}
+		qr, err := qre.Execute()
+		require.NoError(t, err)
+		assert.Equal(t, sqltypes.MakeTestResult(fields, "1|aaa", "2|bbb").Rows, qr.Rows)
+		require.NotEmpty(t, qr.ContinuationToken)
+		assert.Equal(t, "select * from t order by a asc limit 0, 2", qre.logStats.RewrittenSQL())
+
+		qre = newTestQueryExecutor(ctx, tsv, "select * from t order by a", txID)
+		qre.options = &querypb.ExecuteOptions{ContinuationToken: qr.ContinuationToken}
+		qr, err = qre.Execute()
+		require.NoError(t, err)
+		assert.Equal(t, sqltypes.MakeTestResult(fields, "3|ccc").Rows, qr.Rows)
+		assert.Empty(t, qr.ContinuationToken)
+		assert.Equal(t, "select * from t order by a asc limit 2, 2", qre.logStats.RewrittenSQL())
+	}
+	t.Run("outside a transaction", func(t *testing.T) {
+		fetchPages(t, 0)
+	})
+	t.Run("inside a transaction", func(t *testing.T) {
+		txID := newTransaction(tsv, nil)
+		defer tsv.Commit(ctx, tsv.sm.Target(), txID)
+		fetchPages(t, txID)
+	})
+
+	t.Run("without paging", func(t *testing.T) {
+		qre := newTestQueryExecutor(ctx, tsv, "select * from t order by a", 0)
+		_, err := qre.Execute()
+		assert.ErrorContains(t, err, "Row count exceeded 2")
+	})
+
+	t.Run("token of another query", func(t *testing.T) {
+		qre := newTestQueryExecutor(ctx, tsv, "select * from t order by a", 0)
+		qre.options = &querypb.ExecuteOptions{ContinuationToken: resultPage{offset: 2, query: 1}.continuationToken(0)}
+		_, err := qre.Execute()
+		assert.ErrorContains(t, err, "the continuation token does not belong to this query")
+	})
+
+	t.Run("query with a limit", func(t *testing.T) {
+		// The results of the queries with their own limit are not paged.
+		qre := newTestQueryExecutor(ctx, tsv, "select * from t limit 5", 0)
+		qre.options = &querypb.ExecuteOptions{ResultPaging: true}
+		_, err := qre.Execute()
+		assert.ErrorContains(t, err, "Row count exceeded 2")
+
+		qre = newTestQueryExecutor(ctx, tsv, "select * from t limit 5", 0)
+		qre.options = &querypb.ExecuteOptions{ContinuationToken: resultPage{offset: 2}.continuationToken(0)}
+		_, err = qre.Execute()
+		assert.ErrorContains(t, err, "the results of this query cannot be paged")
+	})
+
+	t.Run("paging disabled", func(t *testing.T) {
+		tsv := newTestTabletServer(ctx, smallResultSize, db)
+		defer tsv.StopService()
+
+		qre := newTestQueryExecutor(ctx, tsv, "select * from t order by a", 0)
+		qre.options = &querypb.ExecuteOptions{ResultPaging: true}
+		_, err := qre.Execute()
+		assert.ErrorContains(t, err, "Row count exceeded 2")
+
+		qre = newTestQueryExecutor(ctx, tsv, "select * from t order by a", 0)
+		qre.options = &querypb.ExecuteOptions{ContinuationToken: resultPage{offset: 2}.continuationToken(0)}
+		_, err = qre.Execute()
+		assert.ErrorContains(t, err, "result paging is disabled on this tablet")
+	})
+}
+
+func TestContinuationToken(t *testing.T) {
+	bindVars := map[string]*querypb.BindVariable{"id": sqltypes.Int64BindVariable(1)}
+	page := resultPage{offset: 100, query: queryHash("select * from t where id = :id", bindVars)}
+	next, err := decodeContinuationToken(page.continuationToken(50))
+	require.NoError(t, err)
+	assert.Equal(t, resultPage{offset: 150, query: page.query}, next)
+
+	// The bind variables set by the tablet are not part of the hash.
+	bindVars["#maxLimit"] = sqltypes.Int64BindVariable(10001)
+	assert.Equal(t, page.query, queryHash("select * from t where id = :id", bindVars))
+	bindVars["id"] = sqltypes.Int64BindVariable(2)
+	assert.NotEqual(t, page.query, queryHash("select * from t where id = :id", bindVars))
+
+	for _, token := range []string{"not a token", "AAAA", resultPage{offset: -1}.continuationToken(0)} {
+		_, err := decodeContinuationToken(token)
+		assert.ErrorContains(t, err, "invalid continuation token", token)
+	}
+}
+
 func TestQueryExecutorPlanPassSelectWithLockOutsideATransaction(t *testing.T) {
 	db := setUpQueryExecutorTest(t)
 	defer db.Close()
@@ -1295,6 +1398,144 @@ func TestReplaceSchemaName(t *testing.T) {
 	}
 }
 
+func TestGetSchemaRoutines(t *testing.T) {
+	db := setUpQueryExecutorTest(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	tsv := newTestTabletServer(ctx, noFlags, db)
+	defer tsv.StopService()
+
+	db.AddQuery("select routine_name from information_schema.routines where routine_schema = database() and routine_type = 'PROCEDURE'",
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("routine_name", "varchar"), "p1", "p2"))
+	showCreateFields := sqltypes.MakeTestFields(
+		"Procedure|sql_mode|Create Procedure|character_set_client|collation_connection|Database Collation",
+		"varchar|varchar|text|varchar|varchar|varchar")
+	db.AddQuery("show create procedure p1", sqltypes.MakeTestResult(showCreateFields,
+		"p1||create procedure p1() select 1|utf8mb4|utf8mb4_general_ci|utf8mb4_general_ci"))
+	// The create statement is NULL if the user cannot see the body of the routine.
+	db.AddQuery("show create procedure p2", sqltypes.MakeTestResult(showCreateFields,
+		"p2||null|utf8mb4|utf8mb4_general_ci|utf8mb4_general_ci"))
+
+	target := &querypb.Target{TabletType: topodatapb.TabletType_PRIMARY}
+	var got map[string]string
+	err := tsv.GetSchema(ctx, target, querypb.SchemaTableType_PROCEDURES, nil, func(schemaRes *querypb.GetSchemaResponse) error {
+		got = schemaRes.TableDefinition
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"p1": "create procedure p1() select 1"}, got)
+}
+
+func TestQueryExecutorTableStats(t *testing.T) {
+	db := setUpQueryExecutorTest(t)
+	defer db.Close()
+
+	fields := sqltypes.MakeTestFields("a|b", "int64|varchar")
+	db.AddQuery("select * from test_table limit 10001", sqltypes.MakeTestResult(fields, "1|aaa", "2|bbb"))
+	db.AddQuery("select * from test_table", sqltypes.MakeTestResult(fields, "3|ccc"))
+	db.AddQuery("update test_table set b = 'ddd' limit 10001", &sqltypes.Result{RowsAffected: 3})
+
+	ctx := context.Background()
+	tsv := newTestTabletServer(ctx, noFlags, db)
+	defer tsv.StopService()
+
+	// The counters are shared by the tabletservers of the tests.
+	before := map[string]*tabletmanagerdatapb.TableStats{"test_table": {Name: "test_table"}}
+	for _, ts := range tsv.GetTableStats([]string{"test_table"}) {
+		before[ts.Name] = ts
+	}
+
+	qre := newTestQueryExecutor(ctx, tsv, "select * from test_table", 0)
+	_, err := qre.Execute()
+	require.NoError(t, err)
+	qre = newTestQueryExecutor(ctx, tsv, "update test_table set b = 'ddd'", 0)
+	_, err = qre.Execute()
+	require.NoError(t, err)
+	err = tsv.StreamExecute(ctx, tsv.sm.Target(), "select * from test_table", nil, 0, 0, nil, func(*sqltypes.Result) error { return nil })
+	require.NoError(t, err)
+
+	tableStats := tsv.GetTableStats([]string{"test_table", "unknown"})
+	require.Len(t, tableStats, 1)
+	got := tableStats[0]
+	assert.Equal(t, "test_table", got.Name)
+	assert.EqualValues(t, 3, got.RowsRead-before["test_table"].RowsRead)
+	assert.EqualValues(t, 3, got.RowsReturned-before["test_table"].RowsReturned)
+	assert.EqualValues(t, 3, got.RowsAffected-before["test_table"].RowsAffected)
+	assert.EqualValues(t, 12, got.BytesSent-before["test_table"].BytesSent)
+}
+
+func TestQueryExecutorQueryPools(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config := tabletenv.NewDefaultConfig()
+	config.QueryPools = tabletenv.QueryPoolsConfig{Pools: "olap:2::30m,admin:1::", Users: "analytics:olap"}
+	db, tsv := setupTabletServerTestCustom(t, ctx, config, "")
+	defer tsv.StopService()
+	defer db.Close()
+	olap := tsv.qe.queryPools.pools["olap"]
+	admin := tsv.qe.queryPools.pools["admin"]
+
+	db.AddQuery("select /*vt+ QUERY_POOL=admin */ * from test_table limit 10001", &sqltypes.Result{})
+	db.AddQuery("select * from test_table limit 10001", &sqltypes.Result{})
+	analyticsCtx := callerid.NewContext(ctx, nil, callerid.NewImmediateCallerID("analytics"))
+
+	tcases := []struct {
+		ctx  context.Context
+		sql  string
+		pool *queryPool
+		err  string
+	}{{
+		ctx: ctx,
+		sql: "select * from test_table",
+	}, {
+		ctx:  ctx,
+		sql:  "select /*vt+ QUERY_POOL=admin */ * from test_table",
+		pool: admin,
+	}, {
+		ctx:  analyticsCtx,
+		sql:  "select * from test_table",
+		pool: olap,
+	}, {
+		// The directive takes precedence over the user.
+		ctx:  analyticsCtx,
+		sql:  "select /*vt+ QUERY_POOL=admin */ * from test_table",
+		pool: admin,
+	}, {
+		ctx: ctx,
+		sql: "select /*vt+ QUERY_POOL=unknown */ * from test_table",
+		err: "unknown query pool unknown",
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.sql, func(t *testing.T) {
+			conns := tsv.qe.conns
+			if tcase.pool != nil {
+				conns = tcase.pool.conns
+			}
+			before := conns.GetCount()
+
+			qre := newTestQueryExecutor(tcase.ctx, tsv, tcase.sql, 0)
+			_, err := qre.Execute()
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tcase.pool, qre.queryPool)
+			assert.Equal(t, before+1, conns.GetCount())
+		})
+	}
+
+	// The queries of a pool with a query timeout are killed once it expires.
+	qre := newTestQueryExecutor(analyticsCtx, tsv, "select * from test_table", 0)
+	require.NoError(t, qre.selectQueryPool())
+	poolCtx, poolCancel := qre.withQueryPoolTimeout(ctx)
+	defer poolCancel()
+	deadline, ok := poolCtx.Deadline()
+	require.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(30*time.Minute), deadline, time.Minute)
+}
+
 func TestQueryExecutorShouldConsolidate(t *testing.T) {
 	testCases := []struct {
 		// whether or not the consolidator is enabled by default on the tablet
@@ -1444,6 +1685,7 @@ const (
 	smallResultSize
 	disableOnlineDDL
 	enableConsolidator
+	enableResultPaging
 )
 
 // newTestQueryExecutor uses a package level variable testTabletServer defined in tabletserver_test.go
@@ -1479,6 +1721,9 @@ func newTestTabletServer(ctx context.Context, flags executorFlags, db *fakesqldb
 	if flags&smallResultSize > 0 {
 		config.Oltp.MaxRows = 2
 	}
+	if flags&enableResultPaging > 0 {
+		config.Oltp.ResultPaging = true
+	}
 	if flags&enableConsolidator > 0 {
 		config.Consolidator = tabletenv.Enable
 	} else {
@@ -1651,6 +1896,12 @@ func addQueryExecutorSupportedQueries(db *fakesqldb.DB) {
 				mysql.ShowPrimaryRow("msg", "id"),
 			},
 		},
+		mysql.BaseShowUniqueKeys: {
+			Fields: mysql.ShowUniqueKeysFields,
+		},
+		mysql.BaseShowRoutines: {
+			Fields: mysql.ShowRoutinesFields,
+		},
 		"begin":    {},
 		"commit":   {},
 		"rollback": {},
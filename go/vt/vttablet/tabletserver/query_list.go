@@ -125,6 +125,34 @@ func (ql *QueryList) TerminateAll() {
 	}
 }
 
+// runningSince returns the queries started before the given time.
+func (ql *QueryList) runningSince(before time.Time) []*QueryDetail {
+	ql.mu.Lock()
+	defer ql.mu.Unlock()
+	var running []*QueryDetail
+	for _, qds := range ql.queryDetails {
+		for _, qd := range qds {
+			if qd.start.Before(before) {
+				running = append(running, qd)
+			}
+		}
+	}
+	return running
+}
+
+// kill kills the connection of the query if the query is still in the list.
+func (ql *QueryList) kill(qd *QueryDetail, reason string) bool {
+	ql.mu.Lock()
+	defer ql.mu.Unlock()
+	for _, q := range ql.queryDetails[qd.connID] {
+		if q == qd {
+			_ = qd.conn.Kill(reason, time.Since(qd.start))
+			return true
+		}
+	}
+	return false
+}
+
 // QueryDetailzRow is used for rendering QueryDetail in a template
 type QueryDetailzRow struct {
 	Type              string
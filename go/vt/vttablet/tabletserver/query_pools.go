@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/vt/dbconfigs"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/connpool"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// queryPool is a named pool serving the non-transactional queries of a workload,
+// so that e.g. the ad-hoc scans cannot exhaust the query pool of the OLTP queries.
+type queryPool struct {
+	name  string
+	conns *connpool.Pool
+	// queryTimeout kills the queries of the pool running longer if > 0.
+	queryTimeout time.Duration
+}
+
+// queryPools are the named query pools of --queryserver-config-query-pools,
+// and the users sent to them.
+type queryPools struct {
+	pools map[string]*queryPool
+	users map[string]string
+}
+
+func newQueryPools(env tabletenv.Env) *queryPools {
+	config := env.Config()
+	// The query pools were validated with the config.
+	poolConfigs, users, _ := tabletenv.ParseQueryPools(config.QueryPools)
+	qp := &queryPools{
+		pools: make(map[string]*queryPool, len(poolConfigs)),
+		users: users,
+	}
+	for _, pc := range poolConfigs {
+		// The named pools inherit the other settings of the query pool.
+		cfg := config.OltpReadPool
+		cfg.Size = pc.Size
+		cfg.PrefillParallelism = 0
+		if pc.Timeout > 0 {
+			_ = cfg.TimeoutSeconds.Set(pc.Timeout.String())
+		}
+		qp.pools[pc.Name] = &queryPool{
+			name:         pc.Name,
+			conns:        connpool.NewPool(env, "QueryPool"+strings.ToUpper(pc.Name[:1])+pc.Name[1:], cfg),
+			queryTimeout: pc.QueryTimeout,
+		}
+	}
+	return qp
+}
+
+func (qp *queryPools) open(appParams, dbaParams, appDebugParams dbconfigs.Connector) {
+	for _, pool := range qp.pools {
+		pool.conns.Open(appParams, dbaParams, appDebugParams)
+	}
+}
+
+func (qp *queryPools) close() {
+	for _, pool := range qp.pools {
+		pool.conns.Close()
+	}
+}
+
+// get returns the pool of a query: the pool selected by its QUERY_POOL directive,
+// or else the pool of its user. It returns nil if the query runs in the default pools.
+func (qp *queryPools) get(name, user string) (*queryPool, error) {
+	if name == "" {
+		name = qp.users[user]
+		if name == "" {
+			return nil, nil
+		}
+	}
+	pool := qp.pools[name]
+	if pool == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown query pool %s", name)
+	}
+	return pool, nil
+}
@@ -0,0 +1,289 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/timer"
+	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// queryReaperWebhookTimeout limits the notification of a killed query.
+const queryReaperWebhookTimeout = 10 * time.Second
+
+// queryReaper kills the queries running longer than their threshold, which
+// is the threshold of their user, or else the lowest threshold of their tables,
+// or else the default threshold. The queries of the allowlist are never killed.
+type queryReaper struct {
+	env    tabletenv.Env
+	alias  string
+	lists  []*QueryList
+	ticks  *timer.Timer
+	client *http.Client
+
+	threshold       time.Duration
+	tableThresholds map[string]time.Duration
+	userThresholds  map[string]time.Duration
+	// minThreshold is the lowest threshold > 0, or 0 if no query is ever killed.
+	minThreshold time.Duration
+	allowlist    map[string]bool
+
+	kills *stats.CountersWithMultiLabels
+}
+
+// queryReaperKill is the notification of a killed query.
+type queryReaperKill struct {
+	Time            time.Time `json:"time"`
+	Tablet          string    `json:"tablet"`
+	Type            string    `json:"type"`
+	ConnID          int64     `json:"connId"`
+	Query           string    `json:"query"`
+	Fingerprint     string    `json:"fingerprint"`
+	Tables          []string  `json:"tables,omitempty"`
+	Elapsed         string    `json:"elapsed"`
+	Threshold       string    `json:"threshold"`
+	User            string    `json:"user"`
+	Principal       string    `json:"principal,omitempty"`
+	Component       string    `json:"component,omitempty"`
+	Subcomponent    string    `json:"subcomponent,omitempty"`
+	sanitizedQuery  string
+	thresholdSource string
+}
+
+func newQueryReaper(env tabletenv.Env, alias *topodatapb.TabletAlias, lists ...*QueryList) *queryReaper {
+	config := env.Config().QueryReaper
+	qr := &queryReaper{
+		env:       env,
+		alias:     topoproto.TabletAliasString(alias),
+		lists:     lists,
+		threshold: config.Threshold,
+		kills:     env.Exporter().NewCountersWithMultiLabels("QueryReaperKills", "Queries killed by the query reaper", []string{"Table", "User"}),
+	}
+	// The thresholds were validated with the config.
+	qr.tableThresholds, _ = tabletenv.ParseQueryReaperThresholds(config.TableThresholds)
+	qr.userThresholds, _ = tabletenv.ParseQueryReaperThresholds(config.UserThresholds)
+	qr.minThreshold = qr.threshold
+	for _, thresholds := range []map[string]time.Duration{qr.tableThresholds, qr.userThresholds} {
+		for _, d := range thresholds {
+			if d > 0 && (qr.minThreshold == 0 || d < qr.minThreshold) {
+				qr.minThreshold = d
+			}
+		}
+	}
+	if config.Interval > 0 {
+		qr.ticks = timer.NewTimer(config.Interval)
+	}
+	if config.Webhook != "" {
+		qr.client = &http.Client{Timeout: queryReaperWebhookTimeout}
+	}
+	return qr
+}
+
+// open loads the allowlist and starts the query reaper if it is enabled.
+func (qr *queryReaper) open() error {
+	if qr.ticks == nil {
+		return nil
+	}
+	allowlist, err := loadQueryReaperAllowlist(qr.env.Config().QueryReaper.AllowlistFile)
+	if err != nil {
+		return err
+	}
+	qr.allowlist = allowlist
+	qr.ticks.Start(qr.reap)
+	return nil
+}
+
+func (qr *queryReaper) close() {
+	if qr.ticks == nil {
+		return
+	}
+	qr.ticks.Stop()
+}
+
+// loadQueryReaperAllowlist returns the fingerprints of the queries of the allowlist file,
+// which lists one query per line. Empty lines and lines starting with # are ignored.
+func loadQueryReaperAllowlist(path string) (map[string]bool, error) {
+	allowlist := make(map[string]bool)
+	if path == "" {
+		return allowlist, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the query reaper allowlist: %v", err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, err := sqlparser.Parse(line); err != nil {
+			return nil, fmt.Errorf("invalid query %q in the query reaper allowlist: %v", line, err)
+		}
+		allowlist[queryFingerprint(line)] = true
+	}
+	return allowlist, nil
+}
+
+// reap kills the queries running longer than their threshold.
+func (qr *queryReaper) reap() {
+	if qr.minThreshold == 0 {
+		return
+	}
+	now := time.Now()
+	for _, ql := range qr.lists {
+		for _, qd := range ql.runningSince(now.Add(-qr.minThreshold)) {
+			kill := qr.check(qd, now)
+			if kill == nil {
+				continue
+			}
+			kill.Type = ql.name
+			if !ql.kill(qd, fmt.Sprintf("query reaper (threshold %s)", kill.Threshold)) {
+				// The query completed in the meantime.
+				continue
+			}
+			qr.notify(kill)
+		}
+	}
+}
+
+// check returns the kill of the query if it runs longer than its threshold
+// and it is not in the allowlist, or nil otherwise.
+func (qr *queryReaper) check(qd *QueryDetail, now time.Time) *queryReaperKill {
+	query := qd.conn.Current()
+	if query == "" {
+		return nil
+	}
+	user := callerid.GetUsername(callerid.ImmediateCallerIDFromContext(qd.ctx))
+
+	var tables []string
+	fingerprint := query
+	if stmt, err := sqlparser.Parse(query); err == nil {
+		tables = queryReaperTables(stmt)
+		fingerprint = sqlparser.Fingerprint(stmt)
+	}
+	if qr.allowlist[fingerprint] {
+		return nil
+	}
+
+	threshold, source := qr.threshold, "default"
+	if d, ok := qr.userThresholds[user]; ok {
+		threshold, source = d, "user"
+	} else {
+		found := false
+		for _, table := range tables {
+			d, ok := qr.tableThresholds[table]
+			if !ok {
+				continue
+			}
+			if !found || d < threshold {
+				threshold, source = d, "table:"+table
+				found = true
+			}
+		}
+	}
+	elapsed := now.Sub(qd.start)
+	if threshold == 0 || elapsed <= threshold {
+		return nil
+	}
+
+	ef := callerid.EffectiveCallerIDFromContext(qd.ctx)
+	kill := &queryReaperKill{
+		Time:            now,
+		Tablet:          qr.alias,
+		ConnID:          qd.connID,
+		Query:           query,
+		Fingerprint:     fingerprint,
+		Tables:          tables,
+		Elapsed:         elapsed.Round(time.Millisecond).String(),
+		Threshold:       threshold.String(),
+		User:            user,
+		Principal:       callerid.GetPrincipal(ef),
+		Component:       callerid.GetComponent(ef),
+		Subcomponent:    callerid.GetSubcomponent(ef),
+		thresholdSource: source,
+	}
+	if qr.env.Config().SanitizeLogMessages {
+		kill.sanitizedQuery, _ = sqlparser.RedactSQLQuery(query)
+	} else {
+		kill.sanitizedQuery = query
+	}
+	return kill
+}
+
+// queryReaperTables returns the tables of a query.
+func queryReaperTables(stmt sqlparser.Statement) []string {
+	var tables []string
+	seen := make(map[string]bool)
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if ate, ok := node.(*sqlparser.AliasedTableExpr); ok {
+			if tn, ok := ate.Expr.(sqlparser.TableName); ok {
+				if name := tn.Name.String(); name != "" && !seen[name] {
+					seen[name] = true
+					tables = append(tables, name)
+				}
+			}
+		}
+		return true, nil
+	}, stmt)
+	return tables
+}
+
+// notify logs the kill, counts it and posts it to the webhook.
+func (qr *queryReaper) notify(kill *queryReaperKill) {
+	log.Warningf("Query reaper killed %s query ID %d of user %q (principal %q) running for %s, longer than the %s threshold %s: %s",
+		kill.Type, kill.ConnID, kill.User, kill.Principal, kill.Elapsed, kill.thresholdSource, kill.Threshold, kill.sanitizedQuery)
+	table := ""
+	if len(kill.Tables) > 0 {
+		table = kill.Tables[0]
+	}
+	qr.kills.Add([]string{table, kill.User}, 1)
+	if qr.client == nil {
+		return
+	}
+	go qr.post(kill)
+}
+
+func (qr *queryReaper) post(kill *queryReaperKill) {
+	body, err := json.Marshal(kill)
+	if err != nil {
+		log.Errorf("Query reaper cannot marshal the kill of query ID %d: %v", kill.ConnID, err)
+		return
+	}
+	resp, err := qr.client.Post(qr.env.Config().QueryReaper.Webhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.Errorf("Query reaper cannot notify the kill of query ID %d: %v", kill.ConnID, err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		log.Errorf("Query reaper cannot notify the kill of query ID %d: webhook returned %s", kill.ConnID, resp.Status)
+	}
+}
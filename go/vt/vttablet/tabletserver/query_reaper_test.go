@@ -0,0 +1,139 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func addReaperQuery(ql *QueryList, id int64, user, query string, elapsed time.Duration) *testConn {
+	ctx := callerid.NewContext(context.Background(), callerid.NewEffectiveCallerID("principal", "component", ""), callerid.NewImmediateCallerID(user))
+	conn := &testConn{id: id, query: query}
+	qd := NewQueryDetail(ctx, conn)
+	qd.start = time.Now().Add(-elapsed)
+	ql.Add(qd)
+	return conn
+}
+
+func TestQueryReaper(t *testing.T) {
+	allowlistFile := filepath.Join(t.TempDir(), "allowlist")
+	err := os.WriteFile(allowlistFile, []byte("# reports\nselect * from report where id = 1\n\n"), 0o644)
+	require.NoError(t, err)
+
+	config := tabletenv.NewDefaultConfig()
+	config.QueryReaper.Interval = time.Hour
+	config.QueryReaper.Threshold = time.Minute
+	config.QueryReaper.TableThresholds = "corder:10s,customer:20s,archive:0s"
+	config.QueryReaper.UserThresholds = "analytics:1h"
+	config.QueryReaper.AllowlistFile = allowlistFile
+	env := tabletenv.NewEnv(config, "QueryReaperTest")
+	ql := NewQueryList("test")
+	qr := newQueryReaper(env, &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}, ql)
+	require.NoError(t, qr.open())
+	defer qr.close()
+	assert.Equal(t, 10*time.Second, qr.minThreshold)
+
+	defaultShort := addReaperQuery(ql, 1, "app", "select * from item", 30*time.Second)
+	defaultLong := addReaperQuery(ql, 2, "app", "select * from item", 2*time.Minute)
+	tableLong := addReaperQuery(ql, 3, "app", "select * from corder", 15*time.Second)
+	// The lowest threshold of the tables applies.
+	joinLong := addReaperQuery(ql, 4, "app", "select * from customer join corder on customer.id = corder.customer_id", 15*time.Second)
+	tableShort := addReaperQuery(ql, 5, "app", "select * from customer", 15*time.Second)
+	// The threshold of the user takes precedence.
+	userShort := addReaperQuery(ql, 6, "analytics", "select * from corder", 30*time.Minute)
+	userLong := addReaperQuery(ql, 7, "analytics", "select * from corder", 2*time.Hour)
+	// The queries of the tables with a threshold of 0 are never killed.
+	archive := addReaperQuery(ql, 8, "app", "select * from archive", 2*time.Hour)
+	// The queries of the allowlist are never killed, whatever their literals.
+	allowed := addReaperQuery(ql, 9, "app", "select * from report where id = 42", 2*time.Hour)
+
+	qr.reap()
+
+	assert.False(t, defaultShort.IsKilled())
+	assert.True(t, defaultLong.IsKilled())
+	assert.True(t, tableLong.IsKilled())
+	assert.True(t, joinLong.IsKilled())
+	assert.False(t, tableShort.IsKilled())
+	assert.False(t, userShort.IsKilled())
+	assert.True(t, userLong.IsKilled())
+	assert.False(t, archive.IsKilled())
+	assert.False(t, allowed.IsKilled())
+
+	assert.Equal(t, map[string]int64{"item.app": 1, "corder.app": 1, "customer.app": 1, "corder.analytics": 1}, qr.kills.Counts())
+}
+
+func TestQueryReaperWebhook(t *testing.T) {
+	kills := make(chan queryReaperKill, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var kill queryReaperKill
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&kill))
+		kills <- kill
+	}))
+	defer server.Close()
+
+	config := tabletenv.NewDefaultConfig()
+	config.QueryReaper.Threshold = time.Minute
+	config.QueryReaper.Webhook = server.URL
+	env := tabletenv.NewEnv(config, "QueryReaperWebhookTest")
+	ql := NewQueryList("test")
+	qr := newQueryReaper(env, &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}, ql)
+
+	conn := addReaperQuery(ql, 1, "app", "select * from corder where id = 1", 2*time.Minute)
+	qr.reap()
+	require.True(t, conn.IsKilled())
+
+	select {
+	case kill := <-kills:
+		assert.Equal(t, "zone1-0000000100", kill.Tablet)
+		assert.Equal(t, "test", kill.Type)
+		assert.EqualValues(t, 1, kill.ConnID)
+		assert.Equal(t, "select * from corder where id = 1", kill.Query)
+		assert.Equal(t, "SELECT * FROM `corder` WHERE `id` = :?", kill.Fingerprint)
+		assert.Equal(t, []string{"corder"}, kill.Tables)
+		assert.Equal(t, "1m0s", kill.Threshold)
+		assert.Equal(t, "app", kill.User)
+		assert.Equal(t, "principal", kill.Principal)
+		assert.Equal(t, "component", kill.Component)
+	case <-time.After(10 * time.Second):
+		t.Fatal("webhook not notified")
+	}
+}
+
+func TestQueryReaperAllowlistErrors(t *testing.T) {
+	_, err := loadQueryReaperAllowlist(filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorContains(t, err, "cannot read the query reaper allowlist")
+
+	allowlistFile := filepath.Join(t.TempDir(), "allowlist")
+	require.NoError(t, os.WriteFile(allowlistFile, []byte("select * from\n"), 0o644))
+	_, err = loadQueryReaperAllowlist(allowlistFile)
+	assert.ErrorContains(t, err, `invalid query "select * from" in the query reaper allowlist`)
+}
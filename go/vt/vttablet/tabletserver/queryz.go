@@ -29,6 +29,7 @@ import (
 	"vitess.io/vitess/go/vt/logz"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/planbuilder"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
 )
 
 var (
@@ -69,6 +70,30 @@ var (
 			<td>{{.ErrorsPQ}}</td>
 		</tr>
 	`))
+	hotRowsHeader = []byte(`
+<h3>Hot rows</h3>
+<table class="gridtable">
+	<thead>
+		<tr>
+			<th>Row (range)</th>
+			<th>Table</th>
+			<th>Queued</th>
+			<th>Max queued</th>
+			<th>Transactions</th>
+			<th>Age</th>
+		</tr>
+	</thead>
+	`)
+	hotRowsTmpl = template.Must(template.New("hotrows").Parse(`
+		<tr>
+			<td>{{.Key}}</td>
+			<td>{{.Table}}</td>
+			<td>{{.Size}}</td>
+			<td>{{.Max}}</td>
+			<td>{{.Count}}</td>
+			<td>{{.Age}}</td>
+		</tr>
+	`))
 )
 
 // queryzRow is used for rendering query stats
@@ -143,7 +168,6 @@ func queryzHandler(qe *QueryEngine, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	logz.StartHTMLTable(w)
-	defer logz.EndHTMLTable(w)
 	w.Write(queryzHeader)
 
 	sorter := queryzSorter{
@@ -183,4 +207,18 @@ func queryzHandler(qe *QueryEngine, w http.ResponseWriter, r *http.Request) {
 			log.Errorf("queryz: couldn't execute template: %v", err)
 		}
 	}
+	logz.EndHTMLTable(w)
+
+	// The transactions currently queued by the hot row protection.
+	if qe.env.Config().HotRowProtection.Mode == tabletenv.Disable {
+		return
+	}
+	w.Write(hotRowsHeader)
+	for _, queue := range qe.txSerializer.Queues() {
+		queue.Key = logz.Wrappable(queue.Key)
+		if err := hotRowsTmpl.Execute(w, queue); err != nil {
+			log.Errorf("queryz: couldn't execute template: %v", err)
+		}
+	}
+	w.Write([]byte("</table>\n"))
 }
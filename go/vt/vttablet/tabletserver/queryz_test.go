@@ -17,19 +17,25 @@ limitations under the License.
 package tabletserver
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"regexp"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	"vitess.io/vitess/go/vt/dbconfigs"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/planbuilder"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/schema"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
 )
 
 func TestQueryzHandler(t *testing.T) {
@@ -165,6 +171,57 @@ func TestQueryzHandler(t *testing.T) {
 	checkQueryzHasPlan(t, planPattern4, plan4, body)
 }
 
+func TestQueryzHandlerHotRows(t *testing.T) {
+	config := tabletenv.NewDefaultConfig()
+	config.HotRowProtection.Mode = tabletenv.Enable
+	config.HotRowProtection.MaxConcurrency = 1
+	qe := NewQueryEngine(tabletenv.NewEnv(config, "TabletServerTest"), schema.NewEngine(tabletenv.NewEnv(config, "TabletServerTest")))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done, _, err := qe.txSerializer.Wait(ctx, "test_table where pk = 1", "test_table")
+	require.NoError(t, err)
+	defer done()
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, _, err := qe.txSerializer.Wait(ctx, "test_table where pk = 1", "test_table")
+		assert.ErrorIs(t, err, context.Canceled)
+	}()
+	defer wg.Wait()
+	defer cancel()
+	for qe.txSerializer.Pending("test_table where pk = 1") != 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	resp := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/queryz", nil)
+	queryzHandler(qe, resp, req)
+	body, _ := io.ReadAll(resp.Body)
+	hotRowPattern := []string{
+		`<h3>Hot rows</h3>`,
+		`<table class="gridtable">`,
+		`<thead>`,
+		`<tr>`,
+		`<th>Row \(range\)</th>`,
+		`<th>Table</th>`,
+		`<th>Queued</th>`,
+		`<th>Max queued</th>`,
+		`<th>Transactions</th>`,
+		`<th>Age</th>`,
+		`</tr>`,
+		`</thead>`,
+		`<tr>`,
+		`<td>test_table\s+where\s+pk\s+=\s+1</td>`,
+		`<td>test_table</td>`,
+		`<td>2</td>`,
+		`<td>2</td>`,
+		`<td>2</td>`,
+	}
+	matcher := regexp.MustCompile(strings.Join(hotRowPattern, `\s*`))
+	assert.True(t, matcher.Match(body), "queryz page does not contain the hot row:\n%s", body)
+}
+
 func checkQueryzHasPlan(t *testing.T, planPattern []string, plan *TabletPlan, page []byte) {
 	matcher := regexp.MustCompile(strings.Join(planPattern, `\s*`))
 	if !matcher.Match(page) {
@@ -0,0 +1,116 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// resultPage is the page of the results of a paged select.
+// The continuation token of a page is only valid for the query,
+// with the same bind variables, whose results it pages.
+type resultPage struct {
+	// offset is the number of rows of the previous pages.
+	offset int64
+	// query is the hash of the query and of its bind variables.
+	query uint64
+}
+
+// continuationToken returns the token of the page following the page of n rows.
+func (rp resultPage) continuationToken(n int64) string {
+	var buf [16]byte
+	binary.BigEndian.PutUint64(buf[:8], uint64(rp.offset+n))
+	binary.BigEndian.PutUint64(buf[8:], rp.query)
+	return base64.RawURLEncoding.EncodeToString(buf[:])
+}
+
+// decodeContinuationToken returns the page of a continuation token.
+func decodeContinuationToken(token string) (resultPage, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(buf) != 16 || int64(binary.BigEndian.Uint64(buf[:8])) < 0 {
+		return resultPage{}, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid continuation token: %s", token)
+	}
+	return resultPage{
+		offset: int64(binary.BigEndian.Uint64(buf[:8])),
+		query:  binary.BigEndian.Uint64(buf[8:]),
+	}, nil
+}
+
+// queryHash returns the hash of a query and of its bind variables, except the
+// bind variables that the tablet sets, whose names start with #.
+func queryHash(query string, bindVars map[string]*querypb.BindVariable) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(query))
+	names := make([]string, 0, len(bindVars))
+	for name := range bindVars {
+		if !strings.HasPrefix(name, "#") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		h.Write([]byte{0})
+		h.Write([]byte(name))
+		b, _ := bindVars[name].MarshalVT()
+		h.Write(b)
+	}
+	return h.Sum64()
+}
+
+// resultPage returns the page of the results of the select that the client
+// asked for, or nil if its results are not paged. The results of the selects
+// are paged if the client asks for it, the tablet allows it, and the select
+// does not have its own limit.
+func (qre *QueryExecutor) resultPage() (*resultPage, error) {
+	token := qre.options.GetContinuationToken()
+	if !qre.options.GetResultPaging() && token == "" {
+		return nil, nil
+	}
+	if !qre.tsv.config.Oltp.ResultPaging {
+		if token != "" {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "result paging is disabled on this tablet")
+		}
+		return nil, nil
+	}
+	if qre.plan.PagedQuery == nil {
+		if token != "" {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the results of this query cannot be paged: %s", qre.query)
+		}
+		return nil, nil
+	}
+	page := resultPage{query: queryHash(qre.query, qre.bindVars)}
+	if token != "" {
+		previous, err := decodeContinuationToken(token)
+		if err != nil {
+			return nil, err
+		}
+		if previous.query != page.query {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the continuation token does not belong to this query: %s", qre.query)
+		}
+		page.offset = previous.offset
+	}
+	return &page, nil
+}
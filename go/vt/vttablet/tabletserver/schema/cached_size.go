@@ -42,7 +42,7 @@ func (cached *Table) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(112)
+		size += int64(136)
 	}
 	// field Name vitess.io/vitess/go/vt/sqlparser.IdentifierCS
 	size += cached.Name.CachedSize(false)
@@ -57,6 +57,15 @@ func (cached *Table) CachedSize(alloc bool) int64 {
 	{
 		size += hack.RuntimeAllocSize(int64(cap(cached.PKColumns)) * int64(8))
 	}
+	// field UniqueKeys [][]int
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.UniqueKeys)) * int64(24))
+		for _, elem := range cached.UniqueKeys {
+			{
+				size += hack.RuntimeAllocSize(int64(cap(elem)) * int64(8))
+			}
+		}
+	}
 	// field SequenceInfo *vitess.io/vitess/go/vt/vttablet/tabletserver/schema.SequenceInfo
 	if cached.SequenceInfo != nil {
 		size += hack.RuntimeAllocSize(int64(24))
@@ -18,6 +18,7 @@ package schema
 
 import (
 	"context"
+	"strings"
 
 	"vitess.io/vitess/go/constants/sidecar"
 	"vitess.io/vitess/go/sqltypes"
@@ -86,6 +87,15 @@ where table_schema = database() and table_name in ::viewNames`
 
 	// fetchTablesAndViews queries fetches all information about tables and views
 	fetchTablesAndViews = `select table_name, create_statement from %s.tables where table_schema = database() union select table_name, create_statement from %s.views where table_schema = database()`
+
+	// fetchUpdatedRoutines queries fetches the names of the updated routines of a type
+	fetchUpdatedRoutines = `select routine_name from information_schema.routines where routine_schema = database() and routine_type = :routineType and routine_name in ::routineNames`
+
+	// fetchRoutines queries fetches the names of all the routines of a type
+	fetchRoutines = `select routine_name from information_schema.routines where routine_schema = database() and routine_type = :routineType`
+
+	// fetchCreateRoutineStatement retrieves the create statement of a routine.
+	fetchCreateRoutineStatement = `show create %s %v`
 )
 
 // reloadTablesDataInDB reloads teh tables information we have stored in our database we use for schema-tracking.
@@ -459,3 +469,29 @@ func GetFetchTableAndViewsQuery(tableNames []string) (string, error) {
 	}
 	return parsedQuery.GenerateQuery(bv, nil)
 }
+
+// GetFetchRoutineQuery gets the fetch query to run for getting the names of the listed routines of the type.
+// If no routines are provided, then all the routines of the type are fetched.
+func GetFetchRoutineQuery(routineType string, routineNames []string) (string, error) {
+	bv := map[string]*querypb.BindVariable{"routineType": sqltypes.StringBindVariable(routineType)}
+	query := fetchRoutines
+	if len(routineNames) > 0 {
+		routinesBV, err := sqltypes.BuildBindVariable(routineNames)
+		if err != nil {
+			return "", err
+		}
+		bv["routineNames"] = routinesBV
+		query = fetchUpdatedRoutines
+	}
+
+	parsedQuery, err := generateFullQuery(query)
+	if err != nil {
+		return "", err
+	}
+	return parsedQuery.GenerateQuery(bv, nil)
+}
+
+// GetCreateRoutineQuery gets the query to run for getting the create statement of the routine.
+func GetCreateRoutineQuery(routineType, routineName string) string {
+	return sqlparser.BuildParsedQuery(fetchCreateRoutineStatement, strings.ToLower(routineType), sqlparser.NewIdentifierCS(routineName)).Query
+}
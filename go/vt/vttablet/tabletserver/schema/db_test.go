@@ -980,3 +980,36 @@ func TestGetFetchTableAndViewsQuery(t *testing.T) {
 		})
 	}
 }
+
+// TestGetFetchRoutineQuery tests the functionality for getting the fetch query to retrieve routines.
+func TestGetFetchRoutineQuery(t *testing.T) {
+	testcases := []struct {
+		name          string
+		routineType   string
+		routineNames  []string
+		expectedQuery string
+	}{
+		{
+			name:          "No procedures provided",
+			routineType:   Procedure,
+			routineNames:  []string{},
+			expectedQuery: "select routine_name from information_schema.routines where routine_schema = database() and routine_type = 'PROCEDURE'",
+		}, {
+			name:          "Few functions provided",
+			routineType:   Function,
+			routineNames:  []string{"f1", "f2", "lead"},
+			expectedQuery: "select routine_name from information_schema.routines where routine_schema = database() and routine_type = 'FUNCTION' and routine_name in ('f1', 'f2', 'lead')",
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			query, err := GetFetchRoutineQuery(testcase.routineType, testcase.routineNames)
+			require.NoError(t, err)
+			require.Equal(t, testcase.expectedQuery, query)
+		})
+	}
+
+	require.Equal(t, "show create procedure p1", GetCreateRoutineQuery(Procedure, "p1"))
+	require.Equal(t, "show create function `lead`", GetCreateRoutineQuery(Function, "lead"))
+}
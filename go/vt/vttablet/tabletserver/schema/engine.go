@@ -58,6 +58,10 @@ const maxTableCount = 10000
 
 type notifier func(full map[string]*Table, created, altered, dropped []*Table)
 
+// routineNotifier is called with the stored procedures and functions that
+// were created, altered or dropped since the last reload.
+type routineNotifier func(changed []*Routine)
+
 // Engine stores the schema info and performs operations that
 // keep itself up-to-date.
 type Engine struct {
@@ -65,14 +69,17 @@ type Engine struct {
 	cp  dbconfigs.Connector
 
 	// mu protects the following fields.
-	mu         sync.Mutex
-	isOpen     bool
-	tables     map[string]*Table
+	mu     sync.Mutex
+	isOpen bool
+	tables map[string]*Table
+	// routines are keyed by type and name, see Routine.key.
+	routines   map[string]*Routine
 	lastChange int64
 	// the position at which the schema was last loaded. it is only used in conjunction with ReloadAt
-	reloadAtPos replication.Position
-	notifierMu  sync.Mutex
-	notifiers   map[string]notifier
+	reloadAtPos      replication.Position
+	notifierMu       sync.Mutex
+	notifiers        map[string]notifier
+	routineNotifiers map[string]routineNotifier
 	// isServingPrimary stores if this tablet is currently the serving primary or not.
 	isServingPrimary bool
 	// schemaCopy stores if the user has requested signals on schema changes. If they have, then we
@@ -253,7 +260,9 @@ func (se *Engine) Open() error {
 	se.tables = map[string]*Table{
 		"dual": NewTable("dual", NoType),
 	}
+	se.routines = make(map[string]*Routine)
 	se.notifiers = make(map[string]notifier)
+	se.routineNotifiers = make(map[string]routineNotifier)
 
 	if err := se.reload(ctx, true); err != nil {
 		return err
@@ -312,8 +321,10 @@ func (se *Engine) closeLocked() {
 	se.conns.Close()
 
 	se.tables = make(map[string]*Table)
+	se.routines = make(map[string]*Routine)
 	se.lastChange = 0
 	se.notifiers = make(map[string]notifier)
+	se.routineNotifiers = make(map[string]routineNotifier)
 	se.isOpen = false
 
 	// Unlock the mutex. If there is a tick blocked on this lock,
@@ -529,6 +540,17 @@ func (se *Engine) reload(ctx context.Context, includeStats bool) error {
 	if err := se.populatePrimaryKeys(ctx, conn, changedTables); err != nil {
 		return err
 	}
+	// Populate UniqueKeys for changed tables. They are only used by the hot row
+	// protection, so failing to load them does not fail the reload.
+	if err := se.populateUniqueKeys(ctx, conn, changedTables); err != nil {
+		log.Warningf("could not load the unique keys: %v", err)
+	}
+	// The stored procedures and functions are only tracked to signal their
+	// changes, so failing to load them does not fail the reload either.
+	changedRoutines, err := se.reloadRoutines(ctx, conn)
+	if err != nil {
+		log.Warningf("could not load the stored procedures and functions: %v", err)
+	}
 
 	// If this tablet is the primary and schema tracking is required, we should reload the information in our database.
 	if shouldUseDatabase {
@@ -549,6 +571,7 @@ func (se *Engine) reload(ctx context.Context, includeStats bool) error {
 		log.Infof("schema engine created %v, altered %v, dropped %v", extractNamesFromTablesList(created), extractNamesFromTablesList(altered), extractNamesFromTablesList(dropped))
 	}
 	se.broadcast(created, altered, dropped)
+	se.broadcastRoutines(changedRoutines)
 	return nil
 }
 
@@ -656,6 +679,83 @@ func (se *Engine) populatePrimaryKeys(ctx context.Context, conn *connpool.DBConn
 	return nil
 }
 
+// populateUniqueKeys populates the UniqueKeys for the specified tables.
+func (se *Engine) populateUniqueKeys(ctx context.Context, conn *connpool.DBConn, tables map[string]*Table) error {
+	ukData, err := conn.Exec(ctx, mysql.BaseShowUniqueKeys, maxTableCount, false)
+	if err != nil {
+		return vterrors.Errorf(vtrpcpb.Code_UNKNOWN, "could not get table unique key info: %v", err)
+	}
+	type uniqueKey struct {
+		table   *Table
+		columns []int
+		invalid bool
+	}
+	var uks []*uniqueKey
+	var uk *uniqueKey
+	lastTable, lastIndex := "", ""
+	for _, row := range ukData.Rows {
+		tableName := row[0].ToString()
+		table, ok := tables[tableName]
+		if !ok {
+			continue
+		}
+		indexName := row[1].ToString()
+		if uk == nil || tableName != lastTable || indexName != lastIndex {
+			uk = &uniqueKey{table: table}
+			uks = append(uks, uk)
+			lastTable, lastIndex = tableName, indexName
+		}
+		index := table.FindColumn(sqlparser.NewIdentifierCI(row[2].ToString()))
+		if index < 0 {
+			// Functional key parts have no column, so the key cannot be matched on its columns.
+			uk.invalid = true
+			continue
+		}
+		uk.columns = append(uk.columns, index)
+	}
+	for _, uk := range uks {
+		if !uk.invalid {
+			uk.table.UniqueKeys = append(uk.table.UniqueKeys, uk.columns)
+		}
+	}
+	return nil
+}
+
+// reloadRoutines reloads the stored procedures and functions, and returns the ones
+// that were created, altered or dropped since the last reload.
+func (se *Engine) reloadRoutines(ctx context.Context, conn *connpool.DBConn) ([]*Routine, error) {
+	routineData, err := conn.Exec(ctx, mysql.BaseShowRoutines, maxTableCount, false)
+	if err != nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_UNKNOWN, "could not get routine info: %v", err)
+	}
+	routines := make(map[string]*Routine, len(routineData.Rows))
+	var changed []*Routine
+	for _, row := range routineData.Rows {
+		routine := &Routine{
+			Type:        row[0].ToString(),
+			Name:        row[1].ToString(),
+			Created:     row[2].ToString(),
+			LastAltered: row[3].ToString(),
+			Definition:  row[4].ToString(),
+		}
+		key := routine.key()
+		routines[key] = routine
+		if old, ok := se.routines[key]; !ok || *old != *routine {
+			changed = append(changed, routine)
+		}
+	}
+	for key, routine := range se.routines {
+		if _, ok := routines[key]; !ok {
+			changed = append(changed, routine)
+		}
+	}
+	se.routines = routines
+	if len(changed) > 0 {
+		log.Infof("schema engine changed routines %v", extractNamesFromRoutinesList(changed))
+	}
+	return changed, nil
+}
+
 // RegisterVersionEvent is called by the vstream when it encounters a version event (an
 // insert into the schema_tracking table). It triggers the historian to load the newer
 // rows from the database to update its cache.
@@ -723,9 +823,36 @@ func (se *Engine) UnregisterNotifier(name string) {
 	defer se.notifierMu.Unlock()
 
 	delete(se.notifiers, name)
+	delete(se.routineNotifiers, name)
 	log.Infof("schema Engine - finished UnregisterNotifier")
 }
 
+// RegisterRoutineNotifier registers the function for schema engine events
+// on the stored procedures and functions. It is unregistered by UnregisterNotifier.
+func (se *Engine) RegisterRoutineNotifier(name string, f routineNotifier) {
+	if !se.isOpen {
+		return
+	}
+
+	se.notifierMu.Lock()
+	defer se.notifierMu.Unlock()
+
+	se.routineNotifiers[name] = f
+}
+
+// broadcastRoutines must be called while holding a lock on se.mu.
+func (se *Engine) broadcastRoutines(changed []*Routine) {
+	if !se.isOpen || len(changed) == 0 {
+		return
+	}
+
+	se.notifierMu.Lock()
+	defer se.notifierMu.Unlock()
+	for _, f := range se.routineNotifiers {
+		f(changed)
+	}
+}
+
 // broadcast must be called while holding a lock on se.mu.
 func (se *Engine) broadcast(created, altered, dropped []*Table) {
 	if !se.isOpen {
@@ -854,6 +981,14 @@ func extractNamesFromTablesList(tables []*Table) []string {
 	return tableNames
 }
 
+func extractNamesFromRoutinesList(routines []*Routine) []string {
+	var routineNames []string
+	for _, routine := range routines {
+		routineNames = append(routineNames, routine.key())
+	}
+	return routineNames
+}
+
 func (se *Engine) ResetSequences(tables []string) error {
 	se.mu.Lock()
 	defer se.mu.Unlock()
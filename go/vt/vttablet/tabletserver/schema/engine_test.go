@@ -1214,6 +1214,11 @@ func TestEngineReload(t *testing.T) {
 		"t2|col1",
 		"t3|col1",
 	))
+	// Unique key information. The functional key of t2 has no column.
+	db.AddQuery(mysql.BaseShowUniqueKeys, sqltypes.MakeTestResult(mysql.ShowUniqueKeysFields,
+		"t2|uk_expr|",
+		"t3|uk|col1",
+	))
 
 	// Queries for reloading the tables' information.
 	{
@@ -1258,6 +1263,12 @@ func TestEngineReload(t *testing.T) {
 		db.AddQuery("insert into _vt.views(TABLE_SCHEMA, TABLE_NAME, CREATE_STATEMENT, VIEW_DEFINITION) values (database(), 'v3', 'create_table_v3', 'select_v3')", &sqltypes.Result{})
 	}
 
+	// Procedure p1 is created.
+	db.AddQuery(mysql.BaseShowRoutines, &sqltypes.Result{
+		Fields: mysql.ShowRoutinesFields,
+		Rows:   [][]sqltypes.Value{mysql.ShowRoutinesRow("PROCEDURE", "p1", "2023-01-01 00:00:00", "2023-01-01 00:00:00", "select 1")},
+	})
+
 	// Verify the list of created, altered and dropped tables seen.
 	se.RegisterNotifier("test", func(full map[string]*Table, created, altered, dropped []*Table) {
 		require.ElementsMatch(t, extractNamesFromTablesList(created), []string{"t3", "v3"})
@@ -1269,4 +1280,7 @@ func TestEngineReload(t *testing.T) {
 	err = se.reload(context.Background(), false)
 	require.NoError(t, err)
 	require.NoError(t, db.LastError())
+	require.Empty(t, se.tables["t2"].UniqueKeys)
+	require.Equal(t, [][]int{{0}}, se.tables["t3"].UniqueKeys)
+	require.Contains(t, se.routines, "PROCEDURE.p1")
 }
@@ -36,6 +36,8 @@ func getTestSchemaEngine(t *testing.T, schemaMaxAgeSeconds int64) (*Engine, *fak
 	))
 	db.AddQueryPattern(baseShowTablesPattern, &sqltypes.Result{})
 	db.AddQuery(mysql.BaseShowPrimary, &sqltypes.Result{})
+	db.AddQuery(mysql.BaseShowUniqueKeys, &sqltypes.Result{})
+	db.AddQuery(mysql.BaseShowRoutines, &sqltypes.Result{})
 	AddFakeInnoDBReadRowsResult(db, 1)
 	se := newEngine(10, 10*time.Second, 10*time.Second, schemaMaxAgeSeconds, db)
 	require.NoError(t, se.Open())
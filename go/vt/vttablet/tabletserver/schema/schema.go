@@ -49,7 +49,9 @@ type Table struct {
 	Name      sqlparser.IdentifierCS
 	Fields    []*querypb.Field
 	PKColumns []int
-	Type      int
+	// UniqueKeys contains the columns of the secondary unique keys, in index name order.
+	UniqueKeys [][]int
+	Type       int
 
 	// SequenceInfo contains info for sequence tables.
 	SequenceInfo *SequenceInfo
@@ -155,3 +157,27 @@ func (ta *Table) GetPKColumn(index int) *querypb.Field {
 func (ta *Table) HasPrimary() bool {
 	return len(ta.PKColumns) != 0
 }
+
+// Routine types, as reported by information_schema.routines.
+const (
+	Procedure = "PROCEDURE"
+	Function  = "FUNCTION"
+)
+
+// Routine contains info about a stored procedure or function.
+type Routine struct {
+	Name string
+	Type string
+
+	// Created, LastAltered and Definition are only used to
+	// detect the changes of the routine.
+	Created     string
+	LastAltered string
+	Definition  string
+}
+
+// key returns the key of the routine. Procedures and functions
+// do not share a namespace, so the key includes the type.
+func (r *Routine) key() string {
+	return r.Type + "." + r.Name
+}
@@ -74,6 +74,14 @@ func AddDefaultQueries(db *fakesqldb.DB) {
 		},
 	})
 
+	db.AddQuery(mysql.BaseShowUniqueKeys, &sqltypes.Result{
+		Fields: mysql.ShowUniqueKeysFields,
+	})
+
+	db.AddQuery(mysql.BaseShowRoutines, &sqltypes.Result{
+		Fields: mysql.ShowRoutinesFields,
+	})
+
 	db.MockQueriesForTable("test_table_01", &sqltypes.Result{
 		Fields: []*querypb.Field{{
 			Name: "pk",
@@ -20,6 +20,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -135,6 +137,7 @@ func registerTabletEnvFlags(fs *pflag.FlagSet) {
 	fs.Var(&currentConfig.GracePeriods.ShutdownSeconds, currentConfig.GracePeriods.ShutdownSeconds.Name(), "how long to wait (in seconds) for queries and transactions to complete during graceful shutdown.")
 	fs.IntVar(&currentConfig.Oltp.MaxRows, "queryserver-config-max-result-size", defaultConfig.Oltp.MaxRows, "query server max result size, maximum number of rows allowed to return from vttablet for non-streaming queries.")
 	fs.IntVar(&currentConfig.Oltp.WarnRows, "queryserver-config-warn-result-size", defaultConfig.Oltp.WarnRows, "query server result size warning threshold, warn if number of rows returned from vttablet for non-streaming queries exceeds this")
+	fs.BoolVar(&currentConfig.Oltp.ResultPaging, "queryserver-config-result-paging", defaultConfig.Oltp.ResultPaging, "Allow the clients asking for it to page the results of the non-streaming selects exceeding --queryserver-config-max-result-size with continuation tokens, instead of failing them. Each page runs the query again with an offset, so the query should order its rows by a unique key.")
 	fs.BoolVar(&currentConfig.PassthroughDML, "queryserver-config-passthrough-dmls", defaultConfig.PassthroughDML, "query server pass through all dml statements without rewriting")
 
 	fs.IntVar(&currentConfig.StreamBufferSize, "queryserver-config-stream-buffer-size", defaultConfig.StreamBufferSize, "query server stream buffer size, the maximum number of bytes sent from vttablet for each stream call. It's recommended to keep this value in sync with vtgate's stream_buffer_size.")
@@ -166,6 +169,14 @@ func registerTabletEnvFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&currentConfig.OltpReadPool.MaxWaiters, "queryserver-config-query-pool-waiter-cap", defaultConfig.OltpReadPool.MaxWaiters, "query server query pool waiter limit, this is the maximum number of queries that can be queued waiting to get a connection")
 	fs.IntVar(&currentConfig.OlapReadPool.MaxWaiters, "queryserver-config-stream-pool-waiter-cap", defaultConfig.OlapReadPool.MaxWaiters, "query server stream pool waiter limit, this is the maximum number of streaming queries that can be queued waiting to get a connection")
 	fs.IntVar(&currentConfig.TxPool.MaxWaiters, "queryserver-config-txpool-waiter-cap", defaultConfig.TxPool.MaxWaiters, "query server transaction pool waiter limit, this is the maximum number of transactions that can be queued waiting to get a connection")
+	fs.StringVar(&currentConfig.QueryPools.Pools, "queryserver-config-query-pools", defaultConfig.QueryPools.Pools, "Comma-separated list of name:size:timeout:query_timeout defining named query pools next to the query pool, e.g. 'olap:4:1s:30m,admin:2::'. The non-transactional queries run in a named pool when they select it with the QUERY_POOL comment directive or when their user is sent to it. The timeout limits the wait for a connection, and the queries running longer than the query timeout are killed. The other settings are those of the query pool.")
+	fs.StringVar(&currentConfig.QueryPools.Users, "queryserver-config-query-pool-users", defaultConfig.QueryPools.Users, "Comma-separated list of user:pool sending the non-transactional queries of the users to the named query pools, e.g. 'analytics:olap'. The QUERY_POOL comment directive takes precedence.")
+	fs.DurationVar(&currentConfig.QueryReaper.Interval, "queryserver-config-query-reaper-interval", defaultConfig.QueryReaper.Interval, "How often the query reaper looks for long-running queries to kill. The query reaper is disabled if set to 0 (default).")
+	fs.DurationVar(&currentConfig.QueryReaper.Threshold, "queryserver-config-query-reaper-threshold", defaultConfig.QueryReaper.Threshold, "The query reaper kills the queries running longer than this threshold, unless a table or user threshold applies. Queries are not killed by default if set to 0.")
+	fs.StringVar(&currentConfig.QueryReaper.TableThresholds, "queryserver-config-query-reaper-table-thresholds", defaultConfig.QueryReaper.TableThresholds, "Comma-separated list of table:threshold overriding the query reaper threshold for the queries of the tables, e.g. 'corder:30s,report:1h'. The lowest threshold applies to the queries of several tables, and a threshold of 0 lets the queries of the table run.")
+	fs.StringVar(&currentConfig.QueryReaper.UserThresholds, "queryserver-config-query-reaper-user-thresholds", defaultConfig.QueryReaper.UserThresholds, "Comma-separated list of user:threshold overriding the query reaper threshold and the table thresholds for the queries of the users, e.g. 'analytics:2h,app:10s'.")
+	fs.StringVar(&currentConfig.QueryReaper.AllowlistFile, "queryserver-config-query-reaper-allowlist-file", defaultConfig.QueryReaper.AllowlistFile, "Path of a file listing the queries, one per line, that the query reaper never kills. The queries are matched by fingerprint, so that their literals do not matter.")
+	fs.StringVar(&currentConfig.QueryReaper.Webhook, "queryserver-config-query-reaper-webhook", defaultConfig.QueryReaper.Webhook, "URL the query reaper posts a JSON notification to for each killed query, with the full query and the caller identity.")
 	// tableacl related configurations.
 	fs.BoolVar(&currentConfig.StrictTableACL, "queryserver-config-strict-table-acl", defaultConfig.StrictTableACL, "only allow queries that pass table acl checks")
 	fs.BoolVar(&currentConfig.EnableTableACLDryRun, "queryserver-config-enable-table-acl-dry-run", defaultConfig.EnableTableACLDryRun, "If this flag is enabled, tabletserver will emit monitoring metrics and let the request pass regardless of table acl check results")
@@ -187,12 +198,15 @@ func registerTabletEnvFlags(fs *pflag.FlagSet) {
 	fs.Var(currentConfig.TxThrottlerTabletTypes, "tx-throttler-tablet-types", "A comma-separated list of tablet types. Only tablets of this type are monitored for replication lag by the transaction throttler. Supported types are replica and/or rdonly.")
 	fs.BoolVar(&currentConfig.TxThrottlerDryRun, "tx-throttler-dry-run", defaultConfig.TxThrottlerDryRun, "If present, the transaction throttler only records metrics about requests received and throttled, but does not actually throttle any requests.")
 	fs.DurationVar(&currentConfig.TxThrottlerTopoRefreshInterval, "tx-throttler-topo-refresh-interval", time.Minute*5, "The rate that the transaction throttler will refresh the topology to find cells.")
+	flagutil.StringListVar(fs, &currentConfig.TxThrottlerReplicaSets, "tx-throttler-replica-sets", defaultConfig.TxThrottlerReplicaSets, "A comma-separated list of cell:tablet_type pairs, e.g. 'zone1:replica,zone2:rdonly'. If set, only the tablets of these cells and types are monitored for replication lag by the transaction throttler, in place of --tx-throttler-tablet-types, and only these cells are watched unless --tx_throttler_healthcheck_cells is set.")
+	flagutil.StringListVar(fs, &currentConfig.TxThrottlerExemptWorkloads, "tx-throttler-exempt-workloads", defaultConfig.TxThrottlerExemptWorkloads, "A comma-separated list of workloads, as named by the WORKLOAD_NAME comment directive, whose transactions are never throttled by the transaction throttler.")
 
 	fs.BoolVar(&enableHotRowProtection, "enable_hot_row_protection", false, "If true, incoming transactions for the same row (range) will be queued and cannot consume all txpool slots.")
 	fs.BoolVar(&enableHotRowProtectionDryRun, "enable_hot_row_protection_dry_run", false, "If true, hot row protection is not enforced but logs if transactions would have been queued.")
 	fs.IntVar(&currentConfig.HotRowProtection.MaxQueueSize, "hot_row_protection_max_queue_size", defaultConfig.HotRowProtection.MaxQueueSize, "Maximum number of BeginExecute RPCs which will be queued for the same row (range).")
 	fs.IntVar(&currentConfig.HotRowProtection.MaxGlobalQueueSize, "hot_row_protection_max_global_queue_size", defaultConfig.HotRowProtection.MaxGlobalQueueSize, "Global queue limit across all row (ranges). Useful to prevent that the queue can grow unbounded.")
 	fs.IntVar(&currentConfig.HotRowProtection.MaxConcurrency, "hot_row_protection_concurrent_transactions", defaultConfig.HotRowProtection.MaxConcurrency, "Number of concurrent transactions let through to the txpool/MySQL for the same hot row. Should be > 1 to have enough 'ready' transactions in MySQL and benefit from a pipelining effect.")
+	fs.StringVar(&currentConfig.HotRowProtection.TableConfigs, "hot_row_protection_table_config", defaultConfig.HotRowProtection.TableConfigs, "Comma-separated list of table:max_queue_size:timeout overriding the queue size per row (range) and limiting the time spent in the queue for the tables, e.g. 'corder:5:500ms,customer::1s'. Can be changed at runtime in /debug/env.")
 
 	fs.BoolVar(&currentConfig.EnableTransactionLimit, "enable_transaction_limit", defaultConfig.EnableTransactionLimit, "If true, limit on number of transactions open at the same time will be enforced for all users. User trying to open a new transaction after exhausting their limit will receive an error immediately, regardless of whether there are available slots or not.")
 	fs.BoolVar(&currentConfig.EnableTransactionLimitDryRun, "enable_transaction_limit_dry_run", defaultConfig.EnableTransactionLimitDryRun, "If true, limit on number of transactions open at the same time will be tracked for all users, but not enforced.")
@@ -324,6 +338,8 @@ type TabletConfig struct {
 	Olap             OlapConfig             `json:"olap,omitempty"`
 	Oltp             OltpConfig             `json:"oltp,omitempty"`
 	HotRowProtection HotRowProtectionConfig `json:"hotRowProtection,omitempty"`
+	QueryPools       QueryPoolsConfig       `json:"queryPools,omitempty"`
+	QueryReaper      QueryReaperConfig      `json:"queryReaper,omitempty"`
 
 	Healthcheck  HealthcheckConfig  `json:"healthcheck,omitempty"`
 	GracePeriods GracePeriodsConfig `json:"gracePeriods,omitempty"`
@@ -368,6 +384,8 @@ type TabletConfig struct {
 	TxThrottlerTabletTypes         *topoproto.TabletTypeListFlag `json:"-"`
 	TxThrottlerTopoRefreshInterval time.Duration                 `json:"-"`
 	TxThrottlerDryRun              bool                          `json:"-"`
+	TxThrottlerReplicaSets         []string                      `json:"-"`
+	TxThrottlerExemptWorkloads     []string                      `json:"-"`
 
 	EnableTableGC bool `json:"-"` // can be turned off programmatically by tests
 
@@ -472,6 +490,7 @@ type OltpConfig struct {
 	TxTimeoutSeconds    flagutil.DeprecatedFloat64Seconds `json:"txTimeoutSeconds,omitempty"`
 	MaxRows             int                               `json:"maxRows,omitempty"`
 	WarnRows            int                               `json:"warnRows,omitempty"`
+	ResultPaging        bool                              `json:"resultPaging,omitempty"`
 }
 
 func (cfg *OltpConfig) MarshalJSON() ([]byte, error) {
@@ -503,6 +522,204 @@ type HotRowProtectionConfig struct {
 	MaxQueueSize       int    `json:"maxQueueSize,omitempty"`
 	MaxGlobalQueueSize int    `json:"maxGlobalQueueSize,omitempty"`
 	MaxConcurrency     int    `json:"maxConcurrency,omitempty"`
+	// TableConfigs overrides the queue size and limits the wait per table,
+	// see ParseHotRowProtectionTableConfigs.
+	TableConfigs string `json:"tableConfigs,omitempty"`
+}
+
+// HotRowProtectionTableConfig is the hot row protection config of a table.
+type HotRowProtectionTableConfig struct {
+	// MaxQueueSize overrides the queue size per row (range) if > 0.
+	MaxQueueSize int
+	// Timeout limits the time a transaction waits in the queue if > 0.
+	// The wait is otherwise only limited by the query timeout.
+	Timeout time.Duration
+}
+
+// ParseHotRowProtectionTableConfigs parses a comma-separated list of
+// table:max_queue_size:timeout, where max_queue_size and timeout can be empty.
+func ParseHotRowProtectionTableConfigs(s string) (map[string]HotRowProtectionTableConfig, error) {
+	configs := make(map[string]HotRowProtectionTableConfig)
+	if s == "" {
+		return configs, nil
+	}
+	for _, entry := range strings.Split(s, ",") {
+		parts := strings.Split(strings.TrimSpace(entry), ":")
+		if len(parts) != 3 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid hot row protection table config %q, expected table:max_queue_size:timeout", entry)
+		}
+		var config HotRowProtectionTableConfig
+		if parts[1] != "" {
+			size, err := strconv.Atoi(parts[1])
+			if err != nil || size < 0 {
+				return nil, fmt.Errorf("invalid max queue size %q for table %s", parts[1], parts[0])
+			}
+			config.MaxQueueSize = size
+		}
+		if parts[2] != "" {
+			timeout, err := time.ParseDuration(parts[2])
+			if err != nil || timeout < 0 {
+				return nil, fmt.Errorf("invalid timeout %q for table %s", parts[2], parts[0])
+			}
+			config.Timeout = timeout
+		}
+		configs[parts[0]] = config
+	}
+	return configs, nil
+}
+
+// QueryPoolsConfig contains the config for the named query pools.
+type QueryPoolsConfig struct {
+	// Pools is a comma-separated list of name:size:timeout:query_timeout,
+	// see ParseQueryPools.
+	Pools string `json:"pools,omitempty"`
+	// Users is a comma-separated list of user:pool.
+	Users string `json:"users,omitempty"`
+}
+
+// QueryPoolConfig is the config of a named query pool.
+type QueryPoolConfig struct {
+	Name string
+	Size int
+	// Timeout limits the wait for a connection of the pool if > 0.
+	Timeout time.Duration
+	// QueryTimeout kills the queries of the pool running longer if > 0.
+	QueryTimeout time.Duration
+}
+
+// ParseQueryPools parses the named query pools, given as a comma-separated list of
+// name:size:timeout:query_timeout where timeout and query_timeout can be empty, and
+// the users sent to them, given as a comma-separated list of user:pool.
+func ParseQueryPools(c QueryPoolsConfig) ([]QueryPoolConfig, map[string]string, error) {
+	var pools []QueryPoolConfig
+	names := make(map[string]bool)
+	if c.Pools != "" {
+		for _, entry := range strings.Split(c.Pools, ",") {
+			parts := strings.Split(strings.TrimSpace(entry), ":")
+			if len(parts) != 4 || parts[0] == "" {
+				return nil, nil, fmt.Errorf("invalid query pool %q, expected name:size:timeout:query_timeout", entry)
+			}
+			pool := QueryPoolConfig{Name: parts[0]}
+			if !validQueryPoolName(pool.Name) {
+				return nil, nil, fmt.Errorf("invalid query pool name %q, expected letters, digits and underscores", pool.Name)
+			}
+			if names[pool.Name] {
+				return nil, nil, fmt.Errorf("duplicate query pool %s", pool.Name)
+			}
+			names[pool.Name] = true
+			size, err := strconv.Atoi(parts[1])
+			if err != nil || size <= 0 {
+				return nil, nil, fmt.Errorf("invalid size %q for query pool %s", parts[1], pool.Name)
+			}
+			pool.Size = size
+			if pool.Timeout, err = parseQueryPoolTimeout(parts[2]); err != nil {
+				return nil, nil, fmt.Errorf("invalid timeout %q for query pool %s", parts[2], pool.Name)
+			}
+			if pool.QueryTimeout, err = parseQueryPoolTimeout(parts[3]); err != nil {
+				return nil, nil, fmt.Errorf("invalid query timeout %q for query pool %s", parts[3], pool.Name)
+			}
+			pools = append(pools, pool)
+		}
+	}
+
+	users := make(map[string]string)
+	if c.Users != "" {
+		for _, entry := range strings.Split(c.Users, ",") {
+			user, pool, ok := strings.Cut(strings.TrimSpace(entry), ":")
+			if !ok || user == "" || pool == "" {
+				return nil, nil, fmt.Errorf("invalid query pool user %q, expected user:pool", entry)
+			}
+			if !names[pool] {
+				return nil, nil, fmt.Errorf("unknown query pool %s for user %s", pool, user)
+			}
+			users[user] = pool
+		}
+	}
+	return pools, users, nil
+}
+
+// validQueryPoolName returns true if the name can be used in the names of the stats of the pool.
+func validQueryPoolName(name string) bool {
+	for _, c := range name {
+		if !(c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseQueryPoolTimeout(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err == nil && d < 0 {
+		err = fmt.Errorf("negative duration %v", d)
+	}
+	return d, err
+}
+
+// QueryReaperConfig contains the config for the query reaper, which kills
+// the long-running queries.
+type QueryReaperConfig struct {
+	// Interval is how often the query reaper runs. It is disabled if 0.
+	Interval time.Duration `json:"-"`
+	// Threshold is the default threshold. Queries are not killed if 0.
+	Threshold time.Duration `json:"-"`
+	// TableThresholds is a comma-separated list of table:threshold,
+	// see ParseQueryReaperThresholds.
+	TableThresholds string `json:"tableThresholds,omitempty"`
+	// UserThresholds is a comma-separated list of user:threshold.
+	UserThresholds string `json:"userThresholds,omitempty"`
+	// AllowlistFile lists the queries that are never killed, one per line.
+	AllowlistFile string `json:"allowlistFile,omitempty"`
+	// Webhook is the URL notified of each killed query.
+	Webhook string `json:"webhook,omitempty"`
+}
+
+func (cfg QueryReaperConfig) MarshalJSON() ([]byte, error) {
+	type QRCProxy QueryReaperConfig
+
+	tmp := struct {
+		QRCProxy
+		Interval  string `json:"interval,omitempty"`
+		Threshold string `json:"threshold,omitempty"`
+	}{
+		QRCProxy: QRCProxy(cfg),
+	}
+
+	if d := cfg.Interval; d != 0 {
+		tmp.Interval = d.String()
+	}
+	if d := cfg.Threshold; d != 0 {
+		tmp.Threshold = d.String()
+	}
+
+	return json.Marshal(&tmp)
+}
+
+// ParseQueryReaperThresholds parses the query reaper thresholds of the tables
+// or the users, given as a comma-separated list of name:threshold.
+func ParseQueryReaperThresholds(thresholds string) (map[string]time.Duration, error) {
+	result := make(map[string]time.Duration)
+	if thresholds == "" {
+		return result, nil
+	}
+	for _, entry := range strings.Split(thresholds, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid query reaper threshold %q, expected name:threshold", entry)
+		}
+		if _, ok := result[name]; ok {
+			return nil, fmt.Errorf("duplicate query reaper threshold for %s", name)
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid query reaper threshold %q for %s", value, name)
+		}
+		result[name] = d
+	}
+	return result, nil
 }
 
 // HealthcheckConfig contains the config for healthcheck.
@@ -681,6 +898,24 @@ func (c *TabletConfig) Verify() error {
 	if v := c.HotRowProtection.MaxConcurrency; v <= 0 {
 		return fmt.Errorf("--hot_row_protection_concurrent_transactions must be > 0 (specified value: %v)", v)
 	}
+	if _, err := ParseHotRowProtectionTableConfigs(c.HotRowProtection.TableConfigs); err != nil {
+		return fmt.Errorf("--hot_row_protection_table_config: %v", err)
+	}
+	if _, _, err := ParseQueryPools(c.QueryPools); err != nil {
+		return fmt.Errorf("--queryserver-config-query-pools: %v", err)
+	}
+	if v := c.QueryReaper.Interval; v < 0 {
+		return fmt.Errorf("--queryserver-config-query-reaper-interval must be >= 0 (specified value: %v)", v)
+	}
+	if v := c.QueryReaper.Threshold; v < 0 {
+		return fmt.Errorf("--queryserver-config-query-reaper-threshold must be >= 0 (specified value: %v)", v)
+	}
+	if _, err := ParseQueryReaperThresholds(c.QueryReaper.TableThresholds); err != nil {
+		return fmt.Errorf("--queryserver-config-query-reaper-table-thresholds: %v", err)
+	}
+	if _, err := ParseQueryReaperThresholds(c.QueryReaper.UserThresholds); err != nil {
+		return fmt.Errorf("--queryserver-config-query-reaper-user-thresholds: %v", err)
+	}
 	return nil
 }
 
@@ -741,9 +976,43 @@ func (c *TabletConfig) verifyTxThrottlerConfig() error {
 		}
 	}
 
+	if _, err := ParseTxThrottlerReplicaSets(c.TxThrottlerReplicaSets); err != nil {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "--tx-throttler-replica-sets: %v", err)
+	}
+
 	return nil
 }
 
+// ParseTxThrottlerReplicaSets parses the replica sets monitored by the transaction
+// throttler, given as cell:tablet_type pairs, into the tablet types monitored in
+// every cell. It returns nil if there are none.
+func ParseTxThrottlerReplicaSets(sets []string) (map[string]map[topodatapb.TabletType]bool, error) {
+	if len(sets) == 0 {
+		return nil, nil
+	}
+	replicaSets := make(map[string]map[topodatapb.TabletType]bool)
+	for _, set := range sets {
+		cell, typ, ok := strings.Cut(strings.TrimSpace(set), ":")
+		if !ok || cell == "" || typ == "" {
+			return nil, fmt.Errorf("invalid replica set %q, expected cell:tablet_type", set)
+		}
+		tabletType, err := topoproto.ParseTabletType(typ)
+		if err != nil {
+			return nil, err
+		}
+		switch tabletType {
+		case topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY:
+		default:
+			return nil, fmt.Errorf("unsupported tablet type %q in replica set %q", typ, set)
+		}
+		if replicaSets[cell] == nil {
+			replicaSets[cell] = make(map[topodatapb.TabletType]bool)
+		}
+		replicaSets[cell][tabletType] = true
+	}
+	return replicaSets, nil
+}
+
 // Some of these values are for documentation purposes.
 // They actually get overwritten during Init.
 var defaultConfig = TabletConfig{
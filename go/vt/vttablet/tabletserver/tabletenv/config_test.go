@@ -92,6 +92,8 @@ oltpReadPool:
   prefillParallelism: 30
   size: 16
   timeoutSeconds: 10s
+queryPools: {}
+queryReaper: {}
 replicationTracker: {}
 rowStreamer:
   maxInnoDBTrxHistLen: 1000
@@ -157,6 +159,8 @@ oltpReadPool:
 queryCacheLFU: true
 queryCacheMemory: 33554432
 queryCacheSize: 5000
+queryPools: {}
+queryReaper: {}
 replicationTracker:
   heartbeatIntervalSeconds: 250ms
   mode: disable
@@ -367,6 +371,7 @@ func TestVerifyTxThrottlerConfig(t *testing.T) {
 		TxThrottlerHealthCheckCells []string
 		TxThrottlerTabletTypes      *topoproto.TabletTypeListFlag
 		TxThrottlerDefaultPriority  int
+		TxThrottlerReplicaSets      []string
 	}
 
 	tests := []testConfig{
@@ -427,6 +432,29 @@ func TestVerifyTxThrottlerConfig(t *testing.T) {
 			TxThrottlerDefaultPriority:  12345,
 			TxThrottlerHealthCheckCells: []string{"cell1"},
 		},
+		{
+			// enabled + replica sets
+			Name:                   "enabled replica sets",
+			EnableTxThrottler:      true,
+			TxThrottlerConfig:      &TxThrottlerConfigFlag{defaultMaxReplicationLagModuleConfig},
+			TxThrottlerReplicaSets: []string{"cell1:replica", "cell2:rdonly"},
+		},
+		{
+			// enabled + invalid replica set
+			Name:                   "enabled invalid replica set",
+			ExpectedErrorCode:      vtrpcpb.Code_INVALID_ARGUMENT,
+			EnableTxThrottler:      true,
+			TxThrottlerConfig:      &TxThrottlerConfigFlag{defaultMaxReplicationLagModuleConfig},
+			TxThrottlerReplicaSets: []string{"cell1"},
+		},
+		{
+			// enabled + disallowed replica set tablet type
+			Name:                   "enabled disallowed replica set tablet type",
+			ExpectedErrorCode:      vtrpcpb.Code_INVALID_ARGUMENT,
+			EnableTxThrottler:      true,
+			TxThrottlerConfig:      &TxThrottlerConfigFlag{defaultMaxReplicationLagModuleConfig},
+			TxThrottlerReplicaSets: []string{"cell1:primary"},
+		},
 	}
 
 	for _, test := range tests {
@@ -442,6 +470,7 @@ func TestVerifyTxThrottlerConfig(t *testing.T) {
 			config.TxThrottlerConfig = test.TxThrottlerConfig
 			config.TxThrottlerHealthCheckCells = test.TxThrottlerHealthCheckCells
 			config.TxThrottlerDefaultPriority = test.TxThrottlerDefaultPriority
+			config.TxThrottlerReplicaSets = test.TxThrottlerReplicaSets
 			if test.TxThrottlerTabletTypes != nil {
 				config.TxThrottlerTabletTypes = test.TxThrottlerTabletTypes
 			}
@@ -456,3 +485,145 @@ func TestVerifyTxThrottlerConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestParseTxThrottlerReplicaSets(t *testing.T) {
+	replicaSets, err := ParseTxThrottlerReplicaSets(nil)
+	require.NoError(t, err)
+	assert.Nil(t, replicaSets)
+
+	replicaSets, err = ParseTxThrottlerReplicaSets([]string{"cell1:replica", " cell1:rdonly", "cell2:REPLICA"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[topodatapb.TabletType]bool{
+		"cell1": {topodatapb.TabletType_REPLICA: true, topodatapb.TabletType_RDONLY: true},
+		"cell2": {topodatapb.TabletType_REPLICA: true},
+	}, replicaSets)
+
+	for _, sets := range [][]string{{"cell1"}, {":replica"}, {"cell1:"}, {"cell1:unknown"}, {"cell1:primary"}} {
+		_, err = ParseTxThrottlerReplicaSets(sets)
+		assert.Error(t, err, "%v", sets)
+	}
+}
+
+func TestParseHotRowProtectionTableConfigs(t *testing.T) {
+	configs, err := ParseHotRowProtectionTableConfigs("")
+	require.NoError(t, err)
+	assert.Empty(t, configs)
+
+	configs, err = ParseHotRowProtectionTableConfigs("corder:5:500ms, customer::1s,item:2:")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]HotRowProtectionTableConfig{
+		"corder":   {MaxQueueSize: 5, Timeout: 500 * time.Millisecond},
+		"customer": {Timeout: time.Second},
+		"item":     {MaxQueueSize: 2},
+	}, configs)
+
+	_, err = ParseHotRowProtectionTableConfigs("corder:5")
+	assert.EqualError(t, err, `invalid hot row protection table config "corder:5", expected table:max_queue_size:timeout`)
+	_, err = ParseHotRowProtectionTableConfigs("corder:-1:")
+	assert.EqualError(t, err, `invalid max queue size "-1" for table corder`)
+	_, err = ParseHotRowProtectionTableConfigs("corder::1")
+	assert.EqualError(t, err, `invalid timeout "1" for table corder`)
+
+	config := NewDefaultConfig()
+	config.HotRowProtection.TableConfigs = ":1:"
+	assert.EqualError(t, config.Verify(), `--hot_row_protection_table_config: invalid hot row protection table config ":1:", expected table:max_queue_size:timeout`)
+}
+
+func TestParseQueryPools(t *testing.T) {
+	pools, users, err := ParseQueryPools(QueryPoolsConfig{})
+	require.NoError(t, err)
+	assert.Empty(t, pools)
+	assert.Empty(t, users)
+
+	pools, users, err = ParseQueryPools(QueryPoolsConfig{
+		Pools: "olap:4:1s:30m, admin:2::",
+		Users: "analytics:olap,root:admin",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []QueryPoolConfig{
+		{Name: "olap", Size: 4, Timeout: time.Second, QueryTimeout: 30 * time.Minute},
+		{Name: "admin", Size: 2},
+	}, pools)
+	assert.Equal(t, map[string]string{"analytics": "olap", "root": "admin"}, users)
+
+	for _, tcase := range []struct {
+		config QueryPoolsConfig
+		err    string
+	}{{
+		config: QueryPoolsConfig{Pools: "olap:4:1s"},
+		err:    `invalid query pool "olap:4:1s", expected name:size:timeout:query_timeout`,
+	}, {
+		config: QueryPoolsConfig{Pools: "ol-ap:4::"},
+		err:    `invalid query pool name "ol-ap", expected letters, digits and underscores`,
+	}, {
+		config: QueryPoolsConfig{Pools: "olap:4::,olap:2::"},
+		err:    `duplicate query pool olap`,
+	}, {
+		config: QueryPoolsConfig{Pools: "olap:0::"},
+		err:    `invalid size "0" for query pool olap`,
+	}, {
+		config: QueryPoolsConfig{Pools: "olap:4:1:"},
+		err:    `invalid timeout "1" for query pool olap`,
+	}, {
+		config: QueryPoolsConfig{Pools: "olap:4::-1s"},
+		err:    `invalid query timeout "-1s" for query pool olap`,
+	}, {
+		config: QueryPoolsConfig{Pools: "olap:4::", Users: "analytics"},
+		err:    `invalid query pool user "analytics", expected user:pool`,
+	}, {
+		config: QueryPoolsConfig{Pools: "olap:4::", Users: "analytics:admin"},
+		err:    `unknown query pool admin for user analytics`,
+	}} {
+		_, _, err := ParseQueryPools(tcase.config)
+		assert.EqualError(t, err, tcase.err)
+	}
+
+	config := NewDefaultConfig()
+	config.QueryPools.Pools = "olap:4"
+	assert.EqualError(t, config.Verify(), `--queryserver-config-query-pools: invalid query pool "olap:4", expected name:size:timeout:query_timeout`)
+}
+
+func TestParseQueryReaperThresholds(t *testing.T) {
+	thresholds, err := ParseQueryReaperThresholds("")
+	require.NoError(t, err)
+	assert.Empty(t, thresholds)
+
+	thresholds, err = ParseQueryReaperThresholds("corder:30s, report:0s,analytics:2h")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{"corder": 30 * time.Second, "report": 0, "analytics": 2 * time.Hour}, thresholds)
+
+	for _, tcase := range []struct {
+		thresholds string
+		err        string
+	}{{
+		thresholds: "corder",
+		err:        `invalid query reaper threshold "corder", expected name:threshold`,
+	}, {
+		thresholds: ":30s",
+		err:        `invalid query reaper threshold ":30s", expected name:threshold`,
+	}, {
+		thresholds: "corder:30s,corder:1m",
+		err:        `duplicate query reaper threshold for corder`,
+	}, {
+		thresholds: "corder:30",
+		err:        `invalid query reaper threshold "30" for corder`,
+	}, {
+		thresholds: "corder:-1s",
+		err:        `invalid query reaper threshold "-1s" for corder`,
+	}} {
+		_, err := ParseQueryReaperThresholds(tcase.thresholds)
+		assert.EqualError(t, err, tcase.err)
+	}
+
+	config := NewDefaultConfig()
+	config.QueryReaper.TableThresholds = "corder"
+	assert.EqualError(t, config.Verify(), `--queryserver-config-query-reaper-table-thresholds: invalid query reaper threshold "corder", expected name:threshold`)
+
+	config = NewDefaultConfig()
+	config.QueryReaper.UserThresholds = "analytics:1"
+	assert.EqualError(t, config.Verify(), `--queryserver-config-query-reaper-user-thresholds: invalid query reaper threshold "1" for analytics`)
+
+	config = NewDefaultConfig()
+	config.QueryReaper.Interval = -time.Second
+	assert.EqualError(t, config.Verify(), `--queryserver-config-query-reaper-interval must be >= 0 (specified value: -1s)`)
+}
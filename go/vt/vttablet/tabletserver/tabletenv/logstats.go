@@ -77,7 +77,9 @@ func NewLogStats(ctx context.Context, methodName string) *LogStats {
 // Send finalizes a record and sends it
 func (stats *LogStats) Send() {
 	stats.EndTime = time.Now()
-	StatsLogger.Send(stats)
+	if streamlog.ShouldSampleQuery() {
+		StatsLogger.Send(stats)
+	}
 }
 
 // ImmediateCaller returns the immediate caller stored in LogStats.Ctx
@@ -177,7 +179,7 @@ func (stats *LogStats) CallInfo() (string, string) {
 // Logf formats the log record to the given writer, either as
 // tab-separated list of logged fields or as JSON.
 func (stats *LogStats) Logf(w io.Writer, params url.Values) error {
-	if !streamlog.ShouldEmitLog(stats.OriginalSQL, uint64(stats.RowsAffected), uint64(len(stats.Rows))) {
+	if !streamlog.ShouldEmitLog(stats.OriginalSQL, uint64(stats.RowsAffected), uint64(len(stats.Rows)), stats.TotalTime(), stats.Error != nil) {
 		return nil
 	}
 
@@ -72,6 +72,7 @@ import (
 
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
@@ -108,6 +109,7 @@ type TabletServer struct {
 	statelessql  *QueryList
 	statefulql   *QueryList
 	olapql       *QueryList
+	reaper       *queryReaper
 	se           *schema.Engine
 	rt           *repltracker.ReplTracker
 	vstreamer    *vstreamer.Engine
@@ -177,6 +179,7 @@ func NewTabletServer(ctx context.Context, name string, config *tabletenv.TabletC
 	tsv.statelessql = NewQueryList("oltp-stateless")
 	tsv.statefulql = NewQueryList("oltp-stateful")
 	tsv.olapql = NewQueryList("olap")
+	tsv.reaper = newQueryReaper(tsv, alias, tsv.statelessql, tsv.statefulql, tsv.olapql)
 	tsv.se = schema.NewEngine(tsv)
 	tsv.hs = newHealthStreamer(tsv, alias, tsv.se)
 	tsv.rt = repltracker.NewReplTracker(tsv, alias)
@@ -277,7 +280,7 @@ func (tsv *TabletServer) InitDBConfig(target *querypb.Target, dbcfgs *dbconfigs.
 	tsv.onlineDDLExecutor.InitDBConfig(target.Keyspace, target.Shard, dbcfgs.DBName)
 	tsv.lagThrottler.InitDBConfig(target.Keyspace, target.Shard)
 	tsv.tableGC.InitDBConfig(target.Keyspace, target.Shard, dbcfgs.DBName)
-	return nil
+	return tsv.reaper.open()
 }
 
 // Register prepares TabletServer for serving by calling
@@ -397,6 +400,7 @@ func (tsv *TabletServer) StartService(target *querypb.Target, dbcfgs *dbconfigs.
 // Under normal circumstances, SetServingType should be called.
 func (tsv *TabletServer) StopService() {
 	tsv.sm.StopService()
+	tsv.reaper.close()
 }
 
 // IsHealthy returns nil for non-serving types or if the query service is healthy (able to
@@ -457,6 +461,23 @@ func (tsv *TabletServer) ClearQueryPlanCache() {
 	tsv.qe.ClearQueryPlanCache()
 }
 
+// GetPlanCache returns the query plans of the plan cache.
+func (tsv *TabletServer) GetPlanCache(tables, fingerprints []string) []*tabletmanagerdatapb.PlanCacheEntry {
+	return tsv.qe.GetPlanCache(tables, fingerprints)
+}
+
+// GetTableStats returns the row and byte accounting of the queries per table.
+func (tsv *TabletServer) GetTableStats(tables []string) []*tabletmanagerdatapb.TableStats {
+	return tsv.qe.GetTableStats(tables)
+}
+
+// InvalidatePlanCache removes query plans from the plan cache.
+func (tsv *TabletServer) InvalidatePlanCache(tables, fingerprints []string, all bool) int {
+	n := tsv.qe.InvalidatePlanCache(tables, fingerprints, all)
+	log.Infof("Invalidated %d query plans (tables: %v, fingerprints: %v, all: %v)", n, tables, fingerprints, all)
+	return n
+}
+
 // QueryService returns the QueryService part of TabletServer.
 func (tsv *TabletServer) QueryService() queryservice.QueryService {
 	return tsv
@@ -746,6 +767,26 @@ func (tsv *TabletServer) ReadTransaction(ctx context.Context, target *querypb.Ta
 	return metadata, err
 }
 
+// UnresolvedTransactions returns the metadata of the distributed transactions
+// created more than abandonAge ago that are not resolved yet.
+func (tsv *TabletServer) UnresolvedTransactions(ctx context.Context, abandonAge time.Duration) (transactions []*querypb.TransactionMetadata, err error) {
+	err = tsv.execRequest(
+		ctx, tsv.loadQueryTimeout(),
+		"UnresolvedTransactions", "unresolved_transactions", nil,
+		tsv.sm.Target(), nil, true, /* allowOnShutdown */
+		func(ctx context.Context, logStats *tabletenv.LogStats) error {
+			txe := &TxExecutor{
+				ctx:      ctx,
+				logStats: logStats,
+				te:       tsv.te,
+			}
+			transactions, err = txe.UnresolvedTransactions(abandonAge)
+			return err
+		},
+	)
+	return transactions, err
+}
+
 // Execute executes the query and returns the result as response.
 func (tsv *TabletServer) Execute(ctx context.Context, target *querypb.Target, sql string, bindVariables map[string]*querypb.BindVariable, transactionID, reservedID int64, options *querypb.ExecuteOptions) (result *sqltypes.Result, err error) {
 	span, ctx := trace.NewSpan(ctx, "TabletServer.Execute")
@@ -816,6 +857,9 @@ func (tsv *TabletServer) execute(ctx context.Context, target *querypb.Target, sq
 				tabletType:     target.GetTabletType(),
 				setting:        connSetting,
 			}
+			if err := tsv.waitForGTIDSet(ctx, options); err != nil {
+				return err
+			}
 			result, err = qre.Execute()
 			if err != nil {
 				return err
@@ -917,11 +961,42 @@ func (tsv *TabletServer) streamExecute(ctx context.Context, target *querypb.Targ
 				tsv:            tsv,
 				setting:        connSetting,
 			}
+			if err := tsv.waitForGTIDSet(ctx, options); err != nil {
+				return err
+			}
 			return qre.Stream(callback)
 		},
 	)
 }
 
+// waitForGTIDSet blocks until MySQL has executed the GTID set requested by the
+// options, so that a replica can answer the reads that follow a write on the primary.
+func (tsv *TabletServer) waitForGTIDSet(ctx context.Context, options *querypb.ExecuteOptions) error {
+	gtidSet := options.GetWaitForGtidSet()
+	if gtidSet == "" {
+		return nil
+	}
+	query := fmt.Sprintf("select wait_for_executed_gtid_set(%s)", sqltypes.EncodeStringSQL(gtidSet))
+	if timeout := options.GetWaitForGtidSetTimeout(); timeout > 0 {
+		query = fmt.Sprintf("select wait_for_executed_gtid_set(%s, %v)", sqltypes.EncodeStringSQL(gtidSet), timeout)
+	}
+
+	conn, err := tsv.qe.conns.Get(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Recycle()
+	qr, err := conn.Exec(ctx, query, 1, false)
+	if err != nil {
+		return err
+	}
+	// wait_for_executed_gtid_set returns 1 when the timeout expired.
+	if len(qr.Rows) != 1 || qr.Rows[0][0].ToString() != "0" {
+		return vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "timed out waiting for GTID set %s", gtidSet)
+	}
+	return nil
+}
+
 // BeginExecute combines Begin and Execute.
 func (tsv *TabletServer) BeginExecute(ctx context.Context, target *querypb.Target, preQueries []string, sql string, bindVariables map[string]*querypb.BindVariable, reservedID int64, options *querypb.ExecuteOptions) (queryservice.TransactionState, *sqltypes.Result, error) {
 
@@ -1032,7 +1107,13 @@ func (tsv *TabletServer) computeTxSerializerKey(ctx context.Context, logStats *t
 		return "", ""
 	}
 
-	where, err := plan.WhereClause.GenerateQuery(bindVariables, nil)
+	// The DMLs pinning a unique key are serialized on that key, so that the
+	// DMLs on the same row share the same queue whatever their other predicates.
+	whereClause := plan.WhereClause
+	if plan.HotRowWhereClause != nil {
+		whereClause = plan.HotRowWhereClause
+	}
+	where, err := whereClause.GenerateQuery(bindVariables, nil)
 	if err != nil {
 		logComputeRowSerializerKey.Errorf("failed to substitute bind vars in where clause: %v query: %v bind vars: %v", err, sql, bindVariables)
 		return "", ""
@@ -2024,6 +2105,17 @@ func (tsv *TabletServer) ConsolidatorMode() string {
 	return tsv.qe.consolidatorMode.Load().(string)
 }
 
+// SetHotRowProtectionTableConfigs changes the hot row protection config of the tables,
+// given as a comma-separated list of table:max_queue_size:timeout.
+func (tsv *TabletServer) SetHotRowProtectionTableConfigs(configs string) error {
+	return tsv.qe.txSerializer.SetTableConfigs(configs)
+}
+
+// HotRowProtectionTableConfigs returns the hot row protection config of the tables.
+func (tsv *TabletServer) HotRowProtectionTableConfigs() string {
+	return tsv.qe.txSerializer.TableConfigs()
+}
+
 // queryAsString returns a readable normalized version of the query.
 // If sanitize is false it also includes the bind variables.
 // If truncateForLog is true, it truncates the sql query and the
@@ -769,6 +769,32 @@ func TestTabletServerStreamExecute(t *testing.T) {
 	}
 }
 
+func TestTabletServerExecuteWaitForGTIDSet(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	db, tsv := setupTabletServerTest(t, ctx, "")
+	defer tsv.StopService()
+	defer db.Close()
+
+	executeSQL := "select * from test_table limit 1000"
+	db.AddQuery(executeSQL, sqltypes.MakeTestResult(sqltypes.MakeTestFields("a", "varbinary"), "row01"))
+	gtidSet := "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5"
+	db.AddQuery("select wait_for_executed_gtid_set('"+gtidSet+"')", sqltypes.MakeTestResult(sqltypes.MakeTestFields("wait", "int64"), "0"))
+	db.AddQuery("select wait_for_executed_gtid_set('"+gtidSet+"', 0.5)", sqltypes.MakeTestResult(sqltypes.MakeTestFields("wait", "int64"), "1"))
+
+	target := querypb.Target{TabletType: topodatapb.TabletType_PRIMARY}
+	options := &querypb.ExecuteOptions{WaitForGtidSet: gtidSet}
+	_, err := tsv.Execute(ctx, &target, executeSQL, nil, 0, 0, options)
+	require.NoError(t, err)
+	err = tsv.StreamExecute(ctx, &target, executeSQL, nil, 0, 0, options, func(*sqltypes.Result) error { return nil })
+	require.NoError(t, err)
+
+	options.WaitForGtidSetTimeout = 0.5
+	_, err = tsv.Execute(ctx, &target, executeSQL, nil, 0, 0, options)
+	require.EqualError(t, err, "timed out waiting for GTID set "+gtidSet)
+	require.Equal(t, vtrpcpb.Code_UNAVAILABLE, vterrors.Code(err))
+}
+
 func TestTabletServerStreamExecuteComments(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -916,7 +942,7 @@ func TestSerializeTransactionsSameRow(t *testing.T) {
 	db.SetBeforeFunc("update test_table set name_string = 'tx1' where pk = 1 and `name` = 1 limit 10001",
 		func() {
 			close(tx1Started)
-			if err := waitForTxSerializationPendingQueries(tsv, "test_table where pk = 1 and `name` = 1", 2); err != nil {
+			if err := waitForTxSerializationPendingQueries(tsv, "test_table where pk = 1", 2); err != nil {
 				t.Fatal(err)
 			}
 		})
@@ -1005,6 +1031,43 @@ func TestDMLQueryWithoutWhereClause(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestTxSerializerKeyUniqueKeys(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	db, tsv := setupTabletServerTest(t, ctx, "")
+	defer tsv.StopService()
+	defer db.Close()
+
+	tcases := []struct {
+		sql string
+		bv  map[string]*querypb.BindVariable
+		key string
+	}{{
+		// The primary key takes precedence over the other predicates.
+		sql: "update test_table set name_string = 'a' where pk = :pk and `name` = 1 and addr > 2",
+		bv:  map[string]*querypb.BindVariable{"pk": sqltypes.Int64BindVariable(1)},
+		key: "test_table where pk = 1",
+	}, {
+		// The secondary unique key on name.
+		sql: "delete from test_table where addr = 2 and `name` = :name",
+		bv:  map[string]*querypb.BindVariable{"name": sqltypes.Int64BindVariable(3)},
+		key: "test_table where `name` = 3",
+	}, {
+		// No unique key is pinned.
+		sql: "update test_table set name_string = 'a' where addr = 2",
+		key: "test_table where addr = 2",
+	}, {
+		sql: "select * from test_table where pk = 1",
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.sql, func(t *testing.T) {
+			logStats := tabletenv.NewLogStats(ctx, "TestTxSerializerKeyUniqueKeys")
+			key, _ := tsv.computeTxSerializerKey(ctx, logStats, tcase.sql, tcase.bv)
+			assert.Equal(t, tcase.key, key)
+		})
+	}
+}
+
 func TestSerializeTransactionsSameRow_ConcurrentTransactions(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -1114,7 +1177,7 @@ func TestSerializeTransactionsSameRow_ConcurrentTransactions(t *testing.T) {
 	// transactions via db.SetBeforeFunc() for the same reason as mentioned
 	// in TestSerializeTransactionsSameRow: The MySQL C client does not seem
 	// to allow more than connection attempt at a time.
-	err := waitForTxSerializationPendingQueries(tsv, "test_table where pk = 1 and `name` = 1", 3)
+	err := waitForTxSerializationPendingQueries(tsv, "test_table where pk = 1", 3)
 	require.NoError(t, err)
 	close(allQueriesPending)
 
@@ -1210,7 +1273,7 @@ func TestSerializeTransactionsSameRow_TooManyPendingRequests(t *testing.T) {
 
 		<-tx1Started
 		_, _, err := tsv.BeginExecute(ctx, &target, nil, q2, bvTx2, 0, nil)
-		if err == nil || vterrors.Code(err) != vtrpcpb.Code_RESOURCE_EXHAUSTED || err.Error() != "hot row protection: too many queued transactions (1 >= 1) for the same row (table + WHERE clause: 'test_table where pk = 1 and `name` = 1')" {
+		if err == nil || vterrors.Code(err) != vtrpcpb.Code_RESOURCE_EXHAUSTED || err.Error() != "hot row protection: too many queued transactions (1 >= 1) for the same row (table + WHERE clause: 'test_table where pk = 1')" {
 			t.Errorf("tx2 should have failed because there are too many pending requests: %v", err)
 		}
 		// No commit necessary because the Begin failed.
@@ -1315,7 +1378,7 @@ func TestSerializeTransactionsSameRow_RequestCanceled(t *testing.T) {
 		defer wg.Done()
 
 		// Wait until tx1 and tx2 are pending to make the test deterministic.
-		if err := waitForTxSerializationPendingQueries(tsv, "test_table where pk = 1 and `name` = 1", 2); err != nil {
+		if err := waitForTxSerializationPendingQueries(tsv, "test_table where pk = 1", 2); err != nil {
 			t.Error(err)
 		}
 
@@ -1330,7 +1393,7 @@ func TestSerializeTransactionsSameRow_RequestCanceled(t *testing.T) {
 	}()
 
 	// Wait until tx1, 2 and 3 are pending.
-	err := waitForTxSerializationPendingQueries(tsv, "test_table where pk = 1 and `name` = 1", 3)
+	err := waitForTxSerializationPendingQueries(tsv, "test_table where pk = 1", 3)
 	require.NoError(t, err)
 	// Now unblock tx2 and cancel it.
 	cancelTx2()
@@ -2132,6 +2195,41 @@ func TestReserveExecute_WithoutTx(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// TestReserveExecute_ReleaseAndReserveAgain covers the queries of a vtgate session that
+// multiplexes its reserved connections: each query reserves a new connection with the
+// system variables of the session, which is released after the query.
+func TestReserveExecute_ReleaseAndReserveAgain(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	db, tsv := setupTabletServerTest(t, ctx, "")
+	defer tsv.StopService()
+	defer db.Close()
+	target := querypb.Target{TabletType: topodatapb.TabletType_PRIMARY}
+	db.AddQuery("set @@sql_mode = ''", &sqltypes.Result{})
+
+	// Without the settings pool, the connections are really reserved, and
+	// the system variables are set again on each new connection.
+	tsv.config.EnableSettingsPool = false
+	var reservedIDs []int64
+	for i := 0; i < 2; i++ {
+		db.ResetQueryLog()
+		state, _, err := tsv.ReserveExecute(ctx, &target, []string{"set @@sql_mode = ''"}, "select 42", nil, 0, &querypb.ExecuteOptions{})
+		require.NoError(t, err)
+		require.NotZero(t, state.ReservedID)
+		assert.Contains(t, strings.Split(db.QueryLog(), ";"), "set @@sql_mode = ''")
+		require.NoError(t, tsv.Release(ctx, &target, 0, state.ReservedID))
+		reservedIDs = append(reservedIDs, state.ReservedID)
+	}
+	assert.NotEqual(t, reservedIDs[0], reservedIDs[1])
+	assert.Zero(t, tsv.te.txPool.scp.active.Size())
+
+	// With the settings pool, no connection is reserved, so there is nothing to release.
+	tsv.config.EnableSettingsPool = true
+	state, _, err := tsv.ReserveExecute(ctx, &target, []string{"set @@sql_mode = ''"}, "select 42", nil, 0, &querypb.ExecuteOptions{})
+	require.NoError(t, err)
+	assert.Zero(t, state.ReservedID)
+}
+
 func TestReserveExecute_WithTx(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -2582,6 +2680,15 @@ func addTabletServerSupportedQueries(db *fakesqldb.DB) {
 				mysql.ShowPrimaryRow("msg", "id"),
 			},
 		},
+		mysql.BaseShowUniqueKeys: {
+			Fields: mysql.ShowUniqueKeysFields,
+			Rows: [][]sqltypes.Value{
+				mysql.ShowUniqueKeysRow("test_table", "name", "name"),
+			},
+		},
+		mysql.BaseShowRoutines: {
+			Fields: mysql.ShowRoutinesFields,
+		},
 		// queries for TestReserve*
 		"select 42 from dual where 1 != 1": {
 			Fields: []*querypb.Field{{
@@ -82,6 +82,23 @@ func NewBackgroundClient(throttler *Throttler, appName throttlerapp.Name, checkT
 	}
 }
 
+// WithOverrideThreshold returns a client of the same throttler, app and check type, which is
+// throttled at the given threshold rather than at the threshold of the throttler.
+// A non-positive threshold keeps the threshold of the throttler.
+func (c *Client) WithOverrideThreshold(threshold float64) *Client {
+	if c == nil {
+		return nil
+	}
+	client := &Client{
+		throttler: c.throttler,
+		appName:   c.appName,
+		checkType: c.checkType,
+		flags:     c.flags,
+	}
+	client.flags.OverrideThreshold = threshold
+	return client
+}
+
 // ThrottleCheckOK checks the throttler, and returns 'true' when the throttler is satisfied.
 // It does not sleep.
 // The function caches results for a brief amount of time, hence it's safe and efficient to
@@ -0,0 +1,43 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package throttle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
+)
+
+func TestClientWithOverrideThreshold(t *testing.T) {
+	var nilClient *Client
+	assert.Nil(t, nilClient.WithOverrideThreshold(5))
+	assert.True(t, nilClient.WithOverrideThreshold(5).ThrottleCheckOK(context.Background(), ""))
+
+	throttler := &Throttler{}
+	client := NewBackgroundClient(throttler, throttlerapp.VReplicationName, ThrottleCheckShard)
+	overridden := client.WithOverrideThreshold(5)
+	assert.Equal(t, 5.0, overridden.flags.OverrideThreshold)
+	assert.True(t, overridden.flags.LowPriority)
+	assert.Equal(t, ThrottleCheckShard, overridden.checkType)
+	assert.Equal(t, throttlerapp.VReplicationName, overridden.appName)
+	assert.Same(t, throttler, overridden.throttler)
+	// The original client keeps the threshold of the throttler.
+	assert.Zero(t, client.flags.OverrideThreshold)
+}
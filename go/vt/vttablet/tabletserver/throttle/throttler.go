@@ -108,6 +108,9 @@ const (
 	ThrottleCheckPrimaryWrite ThrottleCheckType = iota
 	// ThrottleCheckSelf indicates a check on a specific server health
 	ThrottleCheckSelf
+	// ThrottleCheckShard indicates a check on the health of the replicas of the shard, made on the primary
+	// on behalf of writes that the replicas must then apply, even when the throttler checks as check-self
+	ThrottleCheckShard
 )
 
 func init() {
@@ -1163,6 +1166,8 @@ func (throttler *Throttler) CheckByType(ctx context.Context, appName string, rem
 			return throttler.checkSelf(ctx, appName, remoteAddr, flags)
 		}
 		return throttler.checkShard(ctx, appName, remoteAddr, flags)
+	case ThrottleCheckShard:
+		return throttler.checkShard(ctx, appName, remoteAddr, flags)
 	default:
 		return invalidCheckTypeCheckResult
 	}
@@ -58,29 +58,36 @@ const (
 	from %s.dt_state t
   join %s.dt_participant p on t.dtid = p.dtid
 	order by t.dtid, p.id`
+
+	sqlReadUnresolvedTransactions = `select t.dtid, t.state, t.time_created, p.keyspace, p.shard
+	from %s.dt_state t
+  join %s.dt_participant p on t.dtid = p.dtid
+	where t.time_created < %a
+	order by t.dtid, p.id`
 )
 
 // TwoPC performs 2PC metadata management (MM) functions.
 type TwoPC struct {
 	readPool *connpool.Pool
 
-	insertRedoTx        *sqlparser.ParsedQuery
-	insertRedoStmt      *sqlparser.ParsedQuery
-	updateRedoTx        *sqlparser.ParsedQuery
-	deleteRedoTx        *sqlparser.ParsedQuery
-	deleteRedoStmt      *sqlparser.ParsedQuery
-	readAllRedo         string
-	countUnresolvedRedo *sqlparser.ParsedQuery
-
-	insertTransaction   *sqlparser.ParsedQuery
-	insertParticipants  *sqlparser.ParsedQuery
-	transition          *sqlparser.ParsedQuery
-	deleteTransaction   *sqlparser.ParsedQuery
-	deleteParticipants  *sqlparser.ParsedQuery
-	readTransaction     *sqlparser.ParsedQuery
-	readParticipants    *sqlparser.ParsedQuery
-	readAbandoned       *sqlparser.ParsedQuery
-	readAllTransactions string
+	insertRedoTx       *sqlparser.ParsedQuery
+	insertRedoStmt     *sqlparser.ParsedQuery
+	updateRedoTx       *sqlparser.ParsedQuery
+	deleteRedoTx       *sqlparser.ParsedQuery
+	deleteRedoStmt     *sqlparser.ParsedQuery
+	readAllRedo        string
+	readUnresolvedRedo *sqlparser.ParsedQuery
+
+	insertTransaction          *sqlparser.ParsedQuery
+	insertParticipants         *sqlparser.ParsedQuery
+	transition                 *sqlparser.ParsedQuery
+	deleteTransaction          *sqlparser.ParsedQuery
+	deleteParticipants         *sqlparser.ParsedQuery
+	readTransaction            *sqlparser.ParsedQuery
+	readParticipants           *sqlparser.ParsedQuery
+	readAbandoned              *sqlparser.ParsedQuery
+	readAllTransactions        string
+	readUnresolvedTransactions *sqlparser.ParsedQuery
 }
 
 // NewTwoPC creates a TwoPC variable.
@@ -103,8 +110,8 @@ func NewTwoPC(readPool *connpool.Pool) *TwoPC {
 		"delete from %s.redo_statement where dtid = %a",
 		dbname, ":dtid")
 	tpc.readAllRedo = fmt.Sprintf(sqlReadAllRedo, dbname, dbname)
-	tpc.countUnresolvedRedo = sqlparser.BuildParsedQuery(
-		"select count(*) from %s.redo_state where time_created < %a",
+	tpc.readUnresolvedRedo = sqlparser.BuildParsedQuery(
+		"select dtid, time_created from %s.redo_state where time_created < %a",
 		dbname, ":time_created")
 
 	tpc.insertTransaction = sqlparser.BuildParsedQuery(
@@ -132,6 +139,7 @@ func NewTwoPC(readPool *connpool.Pool) *TwoPC {
 		"select dtid, time_created from %s.dt_state where time_created < %a",
 		dbname, ":time_created")
 	tpc.readAllTransactions = fmt.Sprintf(sqlReadAllTransactions, dbname, dbname)
+	tpc.readUnresolvedTransactions = sqlparser.BuildParsedQuery(sqlReadUnresolvedTransactions, dbname, dbname, ":time_created")
 	return tpc
 }
 
@@ -250,26 +258,10 @@ func (tpc *TwoPC) ReadAllRedo(ctx context.Context) (prepared, failed []*tx.Prepa
 	return prepared, failed, nil
 }
 
-// CountUnresolvedRedo returns the number of prepared transactions that are still unresolved.
-func (tpc *TwoPC) CountUnresolvedRedo(ctx context.Context, unresolvedTime time.Time) (int64, error) {
-	conn, err := tpc.readPool.Get(ctx, nil)
-	if err != nil {
-		return 0, err
-	}
-	defer conn.Recycle()
-
-	bindVars := map[string]*querypb.BindVariable{
-		"time_created": sqltypes.Int64BindVariable(unresolvedTime.UnixNano()),
-	}
-	qr, err := tpc.read(ctx, conn, tpc.countUnresolvedRedo, bindVars)
-	if err != nil {
-		return 0, err
-	}
-	if len(qr.Rows) < 1 {
-		return 0, nil
-	}
-	v, _ := qr.Rows[0][0].ToCastInt64()
-	return v, nil
+// ReadUnresolvedRedo returns the prepared transactions created before
+// unresolvedTime that are still unresolved, and their creation time.
+func (tpc *TwoPC) ReadUnresolvedRedo(ctx context.Context, unresolvedTime time.Time) (map[string]time.Time, error) {
+	return tpc.readCreated(ctx, tpc.readUnresolvedRedo, unresolvedTime)
 }
 
 // CreateTransaction saves the metadata of a 2pc transaction as Prepared.
@@ -387,6 +379,12 @@ func (tpc *TwoPC) ReadTransaction(ctx context.Context, dtid string) (*querypb.Tr
 // ReadAbandoned returns the list of abandoned transactions
 // and their associated start time.
 func (tpc *TwoPC) ReadAbandoned(ctx context.Context, abandonTime time.Time) (map[string]time.Time, error) {
+	return tpc.readCreated(ctx, tpc.readAbandoned, abandonTime)
+}
+
+// readCreated returns the dtids and creation times read by a query
+// of the transactions created before a time.
+func (tpc *TwoPC) readCreated(ctx context.Context, pq *sqlparser.ParsedQuery, created time.Time) (map[string]time.Time, error) {
 	conn, err := tpc.readPool.Get(ctx, nil)
 	if err != nil {
 		return nil, err
@@ -394,9 +392,9 @@ func (tpc *TwoPC) ReadAbandoned(ctx context.Context, abandonTime time.Time) (map
 	defer conn.Recycle()
 
 	bindVars := map[string]*querypb.BindVariable{
-		"time_created": sqltypes.Int64BindVariable(abandonTime.UnixNano()),
+		"time_created": sqltypes.Int64BindVariable(created.UnixNano()),
 	}
-	qr, err := tpc.read(ctx, conn, tpc.readAbandoned, bindVars)
+	qr, err := tpc.read(ctx, conn, pq, bindVars)
 	if err != nil {
 		return nil, err
 	}
@@ -411,6 +409,51 @@ func (tpc *TwoPC) ReadAbandoned(ctx context.Context, abandonTime time.Time) (map
 	return txs, nil
 }
 
+// ReadUnresolvedTransactions returns the metadata of the distributed
+// transactions created before abandonTime, ordered by dtid.
+func (tpc *TwoPC) ReadUnresolvedTransactions(ctx context.Context, abandonTime time.Time) ([]*querypb.TransactionMetadata, error) {
+	conn, err := tpc.readPool.Get(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Recycle()
+
+	bindVars := map[string]*querypb.BindVariable{
+		"time_created": sqltypes.Int64BindVariable(abandonTime.UnixNano()),
+	}
+	qr, err := tpc.read(ctx, conn, tpc.readUnresolvedTransactions, bindVars)
+	if err != nil {
+		return nil, err
+	}
+
+	var curTx *querypb.TransactionMetadata
+	var transactions []*querypb.TransactionMetadata
+	for _, row := range qr.Rows {
+		dtid := row[0].ToString()
+		if curTx == nil || dtid != curTx.Dtid {
+			st, err := row[1].ToCastInt64()
+			if err != nil {
+				return nil, vterrors.Wrapf(err, "error parsing state for dtid %s", dtid)
+			}
+			// A failure in time parsing will show up as a very old time,
+			// which is harmless.
+			tm, _ := row[2].ToCastInt64()
+			curTx = &querypb.TransactionMetadata{
+				Dtid:        dtid,
+				State:       querypb.TransactionState(st),
+				TimeCreated: tm,
+			}
+			transactions = append(transactions, curTx)
+		}
+		curTx.Participants = append(curTx.Participants, &querypb.Target{
+			Keyspace:   row[3].ToString(),
+			Shard:      row[4].ToString(),
+			TabletType: topodatapb.TabletType_PRIMARY,
+		})
+	}
+	return transactions, nil
+}
+
 // ReadAllTransactions returns info about all distributed transactions.
 func (tpc *TwoPC) ReadAllTransactions(ctx context.Context) ([]*tx.DistributedTx, error) {
 	conn, err := tpc.readPool.Get(ctx, nil)
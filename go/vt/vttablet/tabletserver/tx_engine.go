@@ -454,29 +454,33 @@ func (te *TxEngine) rollbackPrepared() {
 }
 
 // startWatchdog starts the watchdog goroutine, which looks for abandoned
-// transactions and calls the notifier on them.
+// transactions and dangling prepares, and calls the notifier on them.
 func (te *TxEngine) startWatchdog() {
 	te.ticks.Start(func() {
 		ctx, cancel := context.WithTimeout(tabletenv.LocalContext(), te.abandonAge/4)
 		defer cancel()
+		now := time.Now()
+		dtids := make(map[string]bool)
 
-		// Raise alerts on prepares that have been unresolved for too long.
+		// Raise alerts on prepares that have been unresolved for too long,
+		// and resolve them: the metadata manager of their transaction may
+		// have lost track of them.
 		// Use 5x abandonAge to give opportunity for watchdog to resolve these.
-		count, err := te.twoPC.CountUnresolvedRedo(ctx, time.Now().Add(-te.abandonAge*5))
+		prepares, err := te.twoPC.ReadUnresolvedRedo(ctx, now.Add(-te.abandonAge*5))
 		if err != nil {
 			te.env.Stats().InternalErrors.Add("WatchdogFail", 1)
 			log.Errorf("Error reading unresolved prepares: '%v': %v", te.coordinatorAddress, err)
 		}
-		te.env.Stats().Unresolved.Set("Prepares", count)
+		te.setUnresolved("Prepares", prepares, now, dtids)
 
 		// Resolve lingering distributed transactions.
-		txs, err := te.twoPC.ReadAbandoned(ctx, time.Now().Add(-te.abandonAge))
+		txs, err := te.twoPC.ReadAbandoned(ctx, now.Add(-te.abandonAge))
 		if err != nil {
 			te.env.Stats().InternalErrors.Add("WatchdogFail", 1)
 			log.Errorf("Error reading transactions for 2pc watchdog: %v", err)
-			return
 		}
-		if len(txs) == 0 {
+		te.setUnresolved("Transactions", txs, now, dtids)
+		if len(dtids) == 0 {
 			return
 		}
 
@@ -489,7 +493,7 @@ func (te *TxEngine) startWatchdog() {
 		defer coordConn.Close()
 
 		var wg sync.WaitGroup
-		for tx := range txs {
+		for dtid := range dtids {
 			wg.Add(1)
 			go func(dtid string) {
 				defer wg.Done()
@@ -497,12 +501,26 @@ func (te *TxEngine) startWatchdog() {
 					te.env.Stats().InternalErrors.Add("WatchdogFail", 1)
 					log.Errorf("Error notifying for dtid %s: %v", dtid, err)
 				}
-			}(tx)
+			}(dtid)
 		}
 		wg.Wait()
 	})
 }
 
+// setUnresolved exports the number of unresolved items of a type and the age
+// of the oldest one, and adds their dtids to the transactions to resolve.
+func (te *TxEngine) setUnresolved(itemType string, created map[string]time.Time, now time.Time, dtids map[string]bool) {
+	var oldest time.Duration
+	for dtid, t := range created {
+		if age := now.Sub(t); age > oldest {
+			oldest = age
+		}
+		dtids[dtid] = true
+	}
+	te.env.Stats().Unresolved.Set(itemType, int64(len(created)))
+	te.env.Stats().UnresolvedAge.Set(itemType, int64(oldest.Seconds()))
+}
+
 // stopWatchdog stops the watchdog goroutine.
 func (te *TxEngine) stopWatchdog() {
 	te.ticks.Stop()
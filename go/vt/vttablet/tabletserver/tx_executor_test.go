@@ -26,11 +26,13 @@ import (
 
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tx"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/proto"
 
 	"vitess.io/vitess/go/mysql/fakesqldb"
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
 	"vitess.io/vitess/go/vt/vtgate/fakerpcvtgateconn"
 	"vitess.io/vitess/go/vt/vtgate/vtgateconn"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
@@ -441,8 +443,12 @@ type FakeVTGateConn struct {
 }
 
 func (conn *FakeVTGateConn) ResolveTransaction(ctx context.Context, dtid string) error {
-	dtidCh <- dtid
-	return nil
+	select {
+	case dtidCh <- dtid:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 func TestExecutorResolveTransaction(t *testing.T) {
@@ -481,6 +487,114 @@ func TestExecutorResolveTransaction(t *testing.T) {
 	}
 }
 
+func TestExecutorResolveDanglingPrepare(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	protocol := "resolveDanglingTest"
+	oldValue := vtgateconn.GetVTGateProtocol()
+	vtgateconn.SetVTGateProtocol(protocol)
+	defer func() {
+		vtgateconn.SetVTGateProtocol(oldValue)
+	}()
+	vtgateconn.RegisterDialer(protocol, func(context.Context, string) (vtgateconn.Impl, error) {
+		return &FakeVTGateConn{
+			FakeVTGateConn: fakerpcvtgateconn.FakeVTGateConn{},
+		}, nil
+	})
+	_, tsv, db := newShortAgeExecutor(t, ctx)
+	defer db.Close()
+	defer tsv.StopService()
+	want := "bb"
+	db.AddQueryPattern(
+		"select dtid, time_created from _vt\\.redo_state where time_created.*",
+		&sqltypes.Result{
+			Fields: []*querypb.Field{
+				{Type: sqltypes.VarChar},
+				{Type: sqltypes.Int64},
+			},
+			Rows: [][]sqltypes.Value{{
+				sqltypes.NewVarBinary(want),
+				sqltypes.NewVarBinary("1"),
+			}},
+		})
+	db.AddQueryPattern("select dtid, time_created from _vt\\.dt_state where time_created.*", &sqltypes.Result{})
+	got := <-dtidCh
+	if got != want {
+		t.Errorf("ResolveTransaction: %s, want %s", got, want)
+	}
+	assert.EqualValues(t, 1, tsv.stats.Unresolved.Counts()["Prepares"])
+	assert.EqualValues(t, 0, tsv.stats.Unresolved.Counts()["Transactions"])
+	assert.Greater(t, tsv.stats.UnresolvedAge.Counts()["Prepares"], int64(0))
+}
+
+func TestExecutorUnresolvedTransactions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	txe, tsv, db := newTestTxExecutor(t, ctx)
+	defer db.Close()
+	defer tsv.StopService()
+
+	db.AddQueryPattern("(?s)select t\\.dtid, t\\.state, t\\.time_created, p\\.keyspace, p\\.shard\\s+from _vt\\.dt_state.*", &sqltypes.Result{})
+	got, err := txe.UnresolvedTransactions(time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	db.AddQueryPattern("(?s)select t\\.dtid, t\\.state, t\\.time_created, p\\.keyspace, p\\.shard\\s+from _vt\\.dt_state.*", &sqltypes.Result{
+		Fields: []*querypb.Field{
+			{Type: sqltypes.VarChar},
+			{Type: sqltypes.Int64},
+			{Type: sqltypes.Int64},
+			{Type: sqltypes.VarChar},
+			{Type: sqltypes.VarChar},
+		},
+		Rows: [][]sqltypes.Value{{
+			sqltypes.NewVarBinary("aa"),
+			sqltypes.NewInt64(int64(querypb.TransactionState_COMMIT)),
+			sqltypes.NewVarBinary("1"),
+			sqltypes.NewVarBinary("ks"),
+			sqltypes.NewVarBinary("-80"),
+		}, {
+			sqltypes.NewVarBinary("aa"),
+			sqltypes.NewInt64(int64(querypb.TransactionState_COMMIT)),
+			sqltypes.NewVarBinary("1"),
+			sqltypes.NewVarBinary("ks"),
+			sqltypes.NewVarBinary("80-"),
+		}, {
+			sqltypes.NewVarBinary("bb"),
+			sqltypes.NewInt64(int64(querypb.TransactionState_ROLLBACK)),
+			sqltypes.NewVarBinary("2"),
+			sqltypes.NewVarBinary("ks"),
+			sqltypes.NewVarBinary("80-"),
+		}},
+	})
+	got, err = txe.UnresolvedTransactions(time.Minute)
+	require.NoError(t, err)
+	want := []*querypb.TransactionMetadata{{
+		Dtid:        "aa",
+		State:       querypb.TransactionState_COMMIT,
+		TimeCreated: 1,
+		Participants: []*querypb.Target{{
+			Keyspace:   "ks",
+			Shard:      "-80",
+			TabletType: topodatapb.TabletType_PRIMARY,
+		}, {
+			Keyspace:   "ks",
+			Shard:      "80-",
+			TabletType: topodatapb.TabletType_PRIMARY,
+		}},
+	}, {
+		Dtid:        "bb",
+		State:       querypb.TransactionState_ROLLBACK,
+		TimeCreated: 2,
+		Participants: []*querypb.Target{{
+			Keyspace:   "ks",
+			Shard:      "80-",
+			TabletType: topodatapb.TabletType_PRIMARY,
+		}},
+	}}
+	utils.MustMatch(t, want, got)
+}
+
 func TestNoTwopc(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -518,6 +632,12 @@ func TestNoTwopc(t *testing.T) {
 			_, err := txe.ReadTransaction("aa")
 			return err
 		},
+	}, {
+		desc: "UnresolvedTransactions",
+		fun: func() error {
+			_, err := txe.UnresolvedTransactions(time.Minute)
+			return err
+		},
 	}, {
 		desc: "ReadAllTransactions",
 		fun: func() error {
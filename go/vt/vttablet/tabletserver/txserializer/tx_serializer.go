@@ -21,6 +21,8 @@ package txserializer
 import (
 	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -78,8 +80,11 @@ type TxSerializer struct {
 	// been rejected due to exceeding the max queue size per row (range).
 	//
 	// globalQueueExceeded is the same as queueExceeded but for the global queue.
-	waits, waitsDryRun, queueExceeded, queueExceededDryRun *stats.CountersWithSingleLabel
-	globalQueueExceeded, globalQueueExceededDryRun         *stats.Counter
+	//
+	// queueTimeouts counts per table how many transactions were rejected because
+	// they waited longer than the queue timeout of the table.
+	waits, waitsDryRun, queueExceeded, queueExceededDryRun, queueTimeouts *stats.CountersWithSingleLabel
+	globalQueueExceeded, globalQueueExceededDryRun                        *stats.Counter
 
 	log                          *logutil.ThrottledLogger
 	logDryRun                    *logutil.ThrottledLogger
@@ -90,12 +95,16 @@ type TxSerializer struct {
 	mu         sync.Mutex
 	queues     map[string]*queue
 	globalSize int
+	// tableConfigs override the queue size and limit the wait per table.
+	tableConfigs map[string]tabletenv.HotRowProtectionTableConfig
 }
 
 // New returns a TxSerializer object.
 func New(env tabletenv.Env) *TxSerializer {
 	config := env.Config()
-	return &TxSerializer{
+	// The table configs were validated with the config.
+	tableConfigs, _ := tabletenv.ParseHotRowProtectionTableConfigs(config.HotRowProtection.TableConfigs)
+	txs := &TxSerializer{
 		env:                    env,
 		ConsolidatorCache:      sync2.NewConsolidatorCache(1000),
 		dryRun:                 config.HotRowProtection.Mode == tabletenv.Dryrun,
@@ -118,6 +127,10 @@ func New(env tabletenv.Env) *TxSerializer {
 			"TxSerializerQueueExceededDryRun",
 			"Dry-run Number of transactions that were rejected because the max queue size was exceeded",
 			"table_name"),
+		queueTimeouts: env.Exporter().NewCountersWithSingleLabel(
+			"TxSerializerQueueTimeouts",
+			"Number of transactions that were rejected because they waited longer than the queue timeout of their table",
+			"table_name"),
 		globalQueueExceeded: env.Exporter().NewCounter(
 			"TxSerializerGlobalQueueExceeded",
 			"Number of transactions that were rejected on the global queue because of exceeding the max queue size per row range"),
@@ -130,8 +143,68 @@ func New(env tabletenv.Env) *TxSerializer {
 		logQueueExceededDryRun:       logutil.NewThrottledLogger("HotRowProtection QueueExceeded DryRun", 5*time.Second),
 		logGlobalQueueExceededDryRun: logutil.NewThrottledLogger("HotRowProtection GlobalQueueExceeded DryRun", 5*time.Second),
 		queues:                       make(map[string]*queue),
+		tableConfigs:                 tableConfigs,
+	}
+	env.Exporter().NewGaugesFuncWithMultiLabels(
+		"TxSerializerQueued",
+		"Number of transactions queued or in flight for the hot row ranges, by table",
+		[]string{"table_name"},
+		func() map[string]int64 { return txs.tableGauges(func(q *queue) int64 { return int64(q.size) }) })
+	env.Exporter().NewGaugesFuncWithMultiLabels(
+		"TxSerializerHotRows",
+		"Number of hot row ranges with queued transactions, by table",
+		[]string{"table_name"},
+		func() map[string]int64 { return txs.tableGauges(func(q *queue) int64 { return 1 }) })
+	return txs
+}
+
+// tableGauges sums the values of the hot row queues per table.
+func (txs *TxSerializer) tableGauges(value func(q *queue) int64) map[string]int64 {
+	txs.mu.Lock()
+	defer txs.mu.Unlock()
+
+	gauges := make(map[string]int64)
+	for _, q := range txs.queues {
+		if q.max > 1 {
+			gauges[q.table] += value(q)
+		}
 	}
+	return gauges
+}
+
+// SetTableConfigs replaces the per table configs, given as a comma-separated
+// list of table:max_queue_size:timeout. It applies to the transactions queued from now on.
+func (txs *TxSerializer) SetTableConfigs(s string) error {
+	tableConfigs, err := tabletenv.ParseHotRowProtectionTableConfigs(s)
+	if err != nil {
+		return err
+	}
+
+	txs.mu.Lock()
+	defer txs.mu.Unlock()
+	txs.tableConfigs = tableConfigs
+	return nil
+}
+
+// TableConfigs returns the per table configs in the format of SetTableConfigs.
+func (txs *TxSerializer) TableConfigs() string {
+	txs.mu.Lock()
+	defer txs.mu.Unlock()
 
+	entries := make([]string, 0, len(txs.tableConfigs))
+	for table, config := range txs.tableConfigs {
+		entry := table + ":"
+		if config.MaxQueueSize > 0 {
+			entry += strconv.Itoa(config.MaxQueueSize)
+		}
+		entry += ":"
+		if config.Timeout > 0 {
+			entry += config.Timeout.String()
+		}
+		entries = append(entries, entry)
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
 }
 
 // DoneFunc is returned by Wait() and must be called by the caller.
@@ -166,11 +239,17 @@ func (txs *TxSerializer) lockLocked(ctx context.Context, key, table string) (boo
 	q, ok := txs.queues[key]
 	if !ok {
 		// First transaction in the queue i.e. we don't wait and return immediately.
-		txs.queues[key] = newQueueForFirstTransaction(txs.concurrentTransactions)
+		txs.queues[key] = newQueueForFirstTransaction(table, txs.concurrentTransactions)
 		txs.globalSize++
 		return false, nil
 	}
 
+	maxQueueSize := txs.maxQueueSize
+	tableConfig := txs.tableConfigs[table]
+	if tableConfig.MaxQueueSize > 0 {
+		maxQueueSize = tableConfig.MaxQueueSize
+	}
+
 	if txs.globalSize >= txs.maxGlobalQueueSize {
 		if txs.dryRun {
 			txs.globalQueueExceededDryRun.Add(1)
@@ -182,22 +261,22 @@ func (txs *TxSerializer) lockLocked(ctx context.Context, key, table string) (boo
 		}
 	}
 
-	if q.size >= txs.maxQueueSize {
+	if q.size >= maxQueueSize {
 		if txs.dryRun {
 			txs.queueExceededDryRun.Add(table, 1)
 			if txs.env.Config().SanitizeLogMessages {
-				txs.logQueueExceededDryRun.Warningf("Would have rejected BeginExecute RPC because there are too many queued transactions (%d >= %d) for the same row (table + WHERE clause: '%v')", q.size, txs.maxQueueSize, txs.sanitizeKey(key))
+				txs.logQueueExceededDryRun.Warningf("Would have rejected BeginExecute RPC because there are too many queued transactions (%d >= %d) for the same row (table + WHERE clause: '%v')", q.size, maxQueueSize, txs.sanitizeKey(key))
 			} else {
-				txs.logQueueExceededDryRun.Warningf("Would have rejected BeginExecute RPC because there are too many queued transactions (%d >= %d) for the same row (table + WHERE clause: '%v')", q.size, txs.maxQueueSize, key)
+				txs.logQueueExceededDryRun.Warningf("Would have rejected BeginExecute RPC because there are too many queued transactions (%d >= %d) for the same row (table + WHERE clause: '%v')", q.size, maxQueueSize, key)
 			}
 		} else {
 			txs.queueExceeded.Add(table, 1)
 			if txs.env.Config().TerseErrors {
 				return false, vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED,
-					"hot row protection: too many queued transactions (%d >= %d) for the same row (table + WHERE clause: '%v')", q.size, txs.maxQueueSize, txs.sanitizeKey(key))
+					"hot row protection: too many queued transactions (%d >= %d) for the same row (table + WHERE clause: '%v')", q.size, maxQueueSize, txs.sanitizeKey(key))
 			}
 			return false, vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED,
-				"hot row protection: too many queued transactions (%d >= %d) for the same row (table + WHERE clause: '%v')", q.size, txs.maxQueueSize, key)
+				"hot row protection: too many queued transactions (%d >= %d) for the same row (table + WHERE clause: '%v')", q.size, maxQueueSize, key)
 		}
 	}
 
@@ -248,11 +327,21 @@ func (txs *TxSerializer) lockLocked(ctx context.Context, key, table string) (boo
 
 	// Blocking wait for the next available slot.
 	txs.waits.Add(table, 1)
+	var timeout <-chan time.Time
+	if tableConfig.Timeout > 0 {
+		timer := time.NewTimer(tableConfig.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
 	select {
 	case q.availableSlots <- struct{}{}:
 		return true, nil
 	case <-ctx.Done():
 		return true, ctx.Err()
+	case <-timeout:
+		txs.queueTimeouts.Add(table, 1)
+		return true, vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED,
+			"hot row protection: timed out after %v waiting for the queued transactions on the same row", tableConfig.Timeout)
 	}
 }
 
@@ -323,6 +412,56 @@ func (txs *TxSerializer) Pending(key string) int {
 	return q.size
 }
 
+// QueueStatus is the live status of the queue of a hot row (range).
+type QueueStatus struct {
+	// Key is the table name and WHERE clause of the row (range), redacted
+	// if the queries of the debug UI are redacted.
+	Key   string
+	Table string
+	// Size is the number of transactions queued or in flight.
+	Size int
+	// Max is the maximum Size of the queue, and Count the number of
+	// transactions which went through the queue.
+	Max, Count int
+	// Age is the time since the first transaction of the queue started.
+	Age time.Duration
+}
+
+// Queues returns the status of the queues of the hot rows i.e. the rows
+// (ranges) which had more than one transaction at the same time, by decreasing size.
+func (txs *TxSerializer) Queues() []QueueStatus {
+	redact := streamlog.GetRedactDebugUIQueries()
+	now := time.Now()
+
+	txs.mu.Lock()
+	var queues []QueueStatus
+	for key, q := range txs.queues {
+		if q.max <= 1 {
+			continue
+		}
+		if redact {
+			key = txs.sanitizeKey(key)
+		}
+		queues = append(queues, QueueStatus{
+			Key:   key,
+			Table: q.table,
+			Size:  q.size,
+			Max:   q.max,
+			Count: q.count,
+			Age:   now.Sub(q.created),
+		})
+	}
+	txs.mu.Unlock()
+
+	sort.Slice(queues, func(i, j int) bool {
+		if queues[i].Size != queues[j].Size {
+			return queues[i].Size > queues[j].Size
+		}
+		return queues[i].Key < queues[j].Key
+	})
+	return queues
+}
+
 // ServeHTTP lists the most recent, cached queries and their count.
 func (txs *TxSerializer) ServeHTTP(response http.ResponseWriter, request *http.Request) {
 	if streamlog.GetRedactDebugUIQueries() {
@@ -363,6 +502,10 @@ func (txs *TxSerializer) ServeHTTP(response http.ResponseWriter, request *http.R
 // transactions which can access the tx pool). All queued transactions are
 // competing for these slots and try to add themselves to the channel.
 type queue struct {
+	// table and created are immutable.
+	table   string
+	created time.Time
+
 	// NOTE: The following fields are guarded by TxSerializer.mu.
 	// size counts how many transactions are currently queued/in flight (includes
 	// the transactions which are not waiting.)
@@ -383,11 +526,13 @@ type queue struct {
 	availableSlots chan struct{}
 }
 
-func newQueueForFirstTransaction(concurrentTransactions int) *queue {
+func newQueueForFirstTransaction(table string, concurrentTransactions int) *queue {
 	return &queue{
-		size:  1,
-		count: 1,
-		max:   1,
+		table:   table,
+		created: time.Now(),
+		size:    1,
+		count:   1,
+		max:     1,
 	}
 }
 
@@ -27,6 +27,9 @@ import (
 
 	"context"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	"vitess.io/vitess/go/streamlog"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
@@ -39,6 +42,7 @@ func resetVariables(txs *TxSerializer) {
 	txs.waitsDryRun.ResetAll()
 	txs.queueExceeded.ResetAll()
 	txs.queueExceededDryRun.ResetAll()
+	txs.queueTimeouts.ResetAll()
 	txs.globalQueueExceeded.Reset()
 	txs.globalQueueExceededDryRun.Reset()
 }
@@ -540,6 +544,97 @@ func TestTxSerializerPending(t *testing.T) {
 	}
 }
 
+func TestTxSerializerTableConfigs(t *testing.T) {
+	config := tabletenv.NewDefaultConfig()
+	config.HotRowProtection.MaxQueueSize = 3
+	config.HotRowProtection.MaxGlobalQueueSize = 10
+	config.HotRowProtection.MaxConcurrency = 1
+	config.HotRowProtection.TableConfigs = "t1:2:"
+	txs := New(tabletenv.NewEnv(config, "TxSerializerTest"))
+	resetVariables(txs)
+	require.Equal(t, "t1:2:", txs.TableConfigs())
+
+	// tx1.
+	done1, _, err := txs.Wait(context.Background(), "t1 where1", "t1")
+	require.NoError(t, err)
+
+	// tx2 gets queued.
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		done2, waited2, err2 := txs.Wait(context.Background(), "t1 where1", "t1")
+		assert.NoError(t, err2)
+		assert.True(t, waited2)
+		done2()
+	}()
+	require.NoError(t, waitForPending(txs, "t1 where1", 2))
+
+	// tx3 is rejected by the queue size of the table.
+	_, _, err = txs.Wait(context.Background(), "t1 where1", "t1")
+	require.EqualError(t, err, "hot row protection: too many queued transactions (2 >= 2) for the same row (table + WHERE clause: 't1 where1')")
+
+	// The new timeout applies to tx4, which gives up waiting.
+	require.NoError(t, txs.SetTableConfigs("t1::10ms"))
+	require.Equal(t, "t1::10ms", txs.TableConfigs())
+	_, waited, err := txs.Wait(context.Background(), "t1 where1", "t1")
+	require.EqualError(t, err, "hot row protection: timed out after 10ms waiting for the queued transactions on the same row")
+	assert.Equal(t, vtrpcpb.Code_RESOURCE_EXHAUSTED, vterrors.Code(err))
+	assert.True(t, waited)
+	assert.Equal(t, int64(1), txs.queueTimeouts.Counts()["t1"])
+
+	require.EqualError(t, txs.SetTableConfigs("t1:x:"), `invalid max queue size "x" for table t1`)
+	require.Equal(t, "t1::10ms", txs.TableConfigs())
+
+	done1()
+	wg.Wait()
+}
+
+func TestTxSerializerQueues(t *testing.T) {
+	config := tabletenv.NewDefaultConfig()
+	config.HotRowProtection.MaxQueueSize = 3
+	config.HotRowProtection.MaxGlobalQueueSize = 10
+	config.HotRowProtection.MaxConcurrency = 1
+	txs := New(tabletenv.NewEnv(config, "TxSerializerTest"))
+
+	done1, _, err := txs.Wait(context.Background(), "t1 where1", "t1")
+	require.NoError(t, err)
+	done2, _, err := txs.Wait(context.Background(), "t2 where2", "t2")
+	require.NoError(t, err)
+	// The rows with a single transaction are not hot.
+	assert.Empty(t, txs.Queues())
+	assert.Empty(t, txs.tableGauges(func(q *queue) int64 { return int64(q.size) }))
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		done, _, err := txs.Wait(context.Background(), "t1 where1", "t1")
+		assert.NoError(t, err)
+		done()
+	}()
+	require.NoError(t, waitForPending(txs, "t1 where1", 2))
+
+	queues := txs.Queues()
+	require.Len(t, queues, 1)
+	assert.Equal(t, "t1 where1", queues[0].Key)
+	assert.Equal(t, "t1", queues[0].Table)
+	assert.Equal(t, 2, queues[0].Size)
+	assert.Equal(t, 2, queues[0].Max)
+	assert.Equal(t, map[string]int64{"t1": 2}, txs.tableGauges(func(q *queue) int64 { return int64(q.size) }))
+
+	streamlog.SetRedactDebugUIQueries(true)
+	defer streamlog.SetRedactDebugUIQueries(false)
+	assert.Equal(t, "t1 ... [REDACTED]", txs.Queues()[0].Key)
+
+	done1()
+	done2()
+	wg.Wait()
+	assert.Empty(t, txs.Queues())
+}
+
 func BenchmarkTxSerializer_NoHotRow(b *testing.B) {
 	config := tabletenv.NewDefaultConfig()
 	config.HotRowProtection.MaxQueueSize = 1
@@ -0,0 +1,73 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package txthrottler
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/streamlog"
+)
+
+// The reasons of the decisions of the transaction throttler.
+const (
+	ReasonExemptWorkload = "exempt workload"
+	ReasonNoLag          = "no replication lag"
+	ReasonPriority       = "priority"
+	ReasonLag            = "replication lag"
+)
+
+var (
+	// eventLogger streams the decisions of the transaction throttler.
+	eventLogger = streamlog.New[*ThrottleEvent]("TxThrottler", 50)
+
+	serveEventsOnce sync.Once
+)
+
+// ThrottleEvent is a decision of the transaction throttler on a transaction,
+// streamed as JSON at /debug/tx_throttler_events.
+type ThrottleEvent struct {
+	Time     time.Time
+	Workload string
+	Priority int
+	// Throttled is true if the transaction was throttled, or would have been
+	// throttled if DryRun.
+	Throttled bool
+	DryRun    bool
+	Reason    string
+}
+
+// Logf formats the event for the streamlog.
+func (event *ThrottleEvent) Logf(w io.Writer, params url.Values) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// serveEvents streams the decisions of the transaction throttler at
+// /debug/tx_throttler_events.
+func serveEvents() {
+	serveEventsOnce.Do(func() {
+		eventLogger.ServeLogs("/debug/tx_throttler_events", streamlog.GetFormatter(eventLogger))
+	})
+}
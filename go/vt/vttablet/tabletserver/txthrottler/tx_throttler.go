@@ -20,6 +20,7 @@ import (
 	"context"
 	"math/rand"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -157,6 +158,10 @@ type txThrottler struct {
 	healthChecksRecordedTotal *stats.CountersWithMultiLabels
 	requestsTotal             *stats.CountersWithSingleLabel
 	requestsThrottled         *stats.CountersWithSingleLabel
+	requestsExempted          *stats.CountersWithSingleLabel
+
+	// exemptWorkloads are the workloads never throttled.
+	exemptWorkloads map[string]bool
 }
 
 type txThrottlerState interface {
@@ -184,6 +189,9 @@ type txThrottlerStateImpl struct {
 
 	// tabletTypes stores the tablet types for throttling
 	tabletTypes map[topodatapb.TabletType]bool
+	// replicaSets stores the tablet types for throttling of every cell,
+	// in place of tabletTypes if set.
+	replicaSets map[string]map[topodatapb.TabletType]bool
 }
 
 // NewTxThrottler tries to construct a txThrottler from the relevant
@@ -200,6 +208,12 @@ func NewTxThrottler(env tabletenv.Env, topoServer *topo.Server) TxThrottler {
 				config.TxThrottlerTabletTypes, config.TxThrottlerHealthCheckCells, config.TxThrottlerConfig.Get(),
 			)
 		}
+		serveEvents()
+	}
+
+	exemptWorkloads := make(map[string]bool, len(config.TxThrottlerExemptWorkloads))
+	for _, workload := range config.TxThrottlerExemptWorkloads {
+		exemptWorkloads[workload] = true
 	}
 
 	return &txThrottler{
@@ -213,6 +227,8 @@ func NewTxThrottler(env tabletenv.Env, topoServer *topo.Server) TxThrottler {
 			[]string{"cell", "DbType"}),
 		requestsTotal:     env.Exporter().NewCountersWithSingleLabel(TxThrottlerName+"Requests", "transaction throttler requests", "workload"),
 		requestsThrottled: env.Exporter().NewCountersWithSingleLabel(TxThrottlerName+"Throttled", "transaction throttler requests throttled", "workload"),
+		requestsExempted:  env.Exporter().NewCountersWithSingleLabel(TxThrottlerName+"Exempted", "transaction throttler requests of exempt workloads", "workload"),
+		exemptWorkloads:   exemptWorkloads,
 	}
 }
 
@@ -263,14 +279,37 @@ func (t *txThrottler) Throttle(priority int, workload string) (result bool) {
 		return false
 	}
 
+	t.requestsTotal.Add(workload, 1)
+	event := &ThrottleEvent{
+		Time:     time.Now(),
+		Workload: workload,
+		Priority: priority,
+		DryRun:   t.config.TxThrottlerDryRun,
+	}
+	defer eventLogger.Send(event)
+
+	if t.exemptWorkloads[workload] {
+		t.requestsExempted.Add(workload, 1)
+		event.Reason = ReasonExemptWorkload
+		return false
+	}
+
 	// Throttle according to both what the throttler state says and the priority. Workloads with lower priority value
 	// are less likely to be throttled.
-	result = t.state.throttle() && rand.Intn(sqlparser.MaxPriorityValue) < priority
+	switch {
+	case !t.state.throttle():
+		event.Reason = ReasonNoLag
+	case rand.Intn(sqlparser.MaxPriorityValue) >= priority:
+		event.Reason = ReasonPriority
+	default:
+		event.Reason = ReasonLag
+		result = true
+	}
 
-	t.requestsTotal.Add(workload, 1)
 	if result {
 		t.requestsThrottled.Add(workload, 1)
 	}
+	event.Throttled = result
 
 	return result && !t.config.TxThrottlerDryRun
 }
@@ -297,17 +336,26 @@ func newTxThrottlerState(txThrottler *txThrottler, config *tabletenv.TabletConfi
 	for _, tabletType := range *config.TxThrottlerTabletTypes {
 		tabletTypes[tabletType] = true
 	}
+	// The replica sets were validated with the config.
+	replicaSets, _ := tabletenv.ParseTxThrottlerReplicaSets(config.TxThrottlerReplicaSets)
 
 	state := &txThrottlerStateImpl{
 		config:           config,
 		healthCheckCells: config.TxThrottlerHealthCheckCells,
 		tabletTypes:      tabletTypes,
+		replicaSets:      replicaSets,
 		throttler:        t,
 		txThrottler:      txThrottler,
 	}
 
-	// get cells from topo if none defined in tabletenv config
-	if len(state.healthCheckCells) == 0 {
+	// watch the cells of the replica sets, or else get cells from topo if
+	// none defined in tabletenv config
+	if len(state.healthCheckCells) == 0 && len(replicaSets) > 0 {
+		for cell := range replicaSets {
+			state.healthCheckCells = append(state.healthCheckCells, cell)
+		}
+		sort.Strings(state.healthCheckCells)
+	} else if len(state.healthCheckCells) == 0 {
 		ctx, cancel := context.WithTimeout(context.Background(), topo.RemoteOperationTimeout)
 		defer cancel()
 		state.healthCheckCells = fetchKnownCells(ctx, txThrottler.topoServer, target)
@@ -409,6 +457,15 @@ func (ts *txThrottlerStateImpl) deallocateResources() {
 	ts.throttler = nil
 }
 
+// monitored returns true if the replication lag of the tablets of the cell and
+// tablet type counts for throttling.
+func (ts *txThrottlerStateImpl) monitored(cell string, tabletType topodatapb.TabletType) bool {
+	if ts.replicaSets != nil {
+		return ts.replicaSets[cell][tabletType]
+	}
+	return ts.tabletTypes[tabletType]
+}
+
 // StatsUpdate updates the health of a tablet with the given healthcheck.
 func (ts *txThrottlerStateImpl) StatsUpdate(tabletStats *discovery.TabletHealth) {
 	if len(ts.tabletTypes) == 0 {
@@ -420,8 +477,9 @@ func (ts *txThrottlerStateImpl) StatsUpdate(tabletStats *discovery.TabletHealth)
 	ts.txThrottler.healthChecksReadTotal.Add(metricLabels, 1)
 
 	// Monitor tablets for replication lag if they have a tablet
-	// type specified by the --tx-throttler-tablet-types flag.
-	if ts.tabletTypes[tabletType] {
+	// type specified by the --tx-throttler-tablet-types flag, or
+	// if they belong to the --tx-throttler-replica-sets.
+	if ts.monitored(tabletStats.Target.Cell, tabletType) {
 		ts.throttler.RecordReplicationLag(time.Now(), tabletStats)
 		ts.txThrottler.healthChecksRecordedTotal.Add(metricLabels, 1)
 	}
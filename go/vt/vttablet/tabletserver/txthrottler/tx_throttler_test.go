@@ -23,6 +23,7 @@ package txthrottler
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -216,6 +217,115 @@ func TestDryRunThrottler(t *testing.T) {
 	}
 }
 
+func TestExemptWorkloads(t *testing.T) {
+	config := tabletenv.NewDefaultConfig()
+	env := tabletenv.NewEnv(config, t.Name())
+
+	aTxThrottler := &txThrottler{
+		config: &tabletenv.TabletConfig{
+			EnableTxThrottler: true,
+		},
+		state:             &mockTxThrottlerState{shouldThrottle: true},
+		requestsTotal:     env.Exporter().NewCountersWithSingleLabel("TransactionThrottlerRequests", "transaction throttler requests", "workload"),
+		requestsThrottled: env.Exporter().NewCountersWithSingleLabel("TransactionThrottlerThrottled", "transaction throttler requests throttled", "workload"),
+		requestsExempted:  env.Exporter().NewCountersWithSingleLabel("TransactionThrottlerExempted", "transaction throttler requests of exempt workloads", "workload"),
+		exemptWorkloads:   map[string]bool{"batch": true},
+	}
+
+	events := eventLogger.Subscribe(t.Name())
+	defer eventLogger.Unsubscribe(events)
+
+	assert.False(t, aTxThrottler.Throttle(100, "batch"))
+	assert.True(t, aTxThrottler.Throttle(100, "oltp"))
+	assert.False(t, aTxThrottler.Throttle(0, "oltp"))
+	assert.Equal(t, map[string]int64{"batch": 1, "oltp": 2}, aTxThrottler.requestsTotal.Counts())
+	assert.Equal(t, map[string]int64{"oltp": 1}, aTxThrottler.requestsThrottled.Counts())
+	assert.Equal(t, map[string]int64{"batch": 1}, aTxThrottler.requestsExempted.Counts())
+
+	event := <-events
+	assert.Equal(t, "batch", event.Workload)
+	assert.False(t, event.Throttled)
+	assert.Equal(t, ReasonExemptWorkload, event.Reason)
+	event = <-events
+	assert.Equal(t, "oltp", event.Workload)
+	assert.Equal(t, 100, event.Priority)
+	assert.True(t, event.Throttled)
+	assert.Equal(t, ReasonLag, event.Reason)
+	event = <-events
+	assert.False(t, event.Throttled)
+	assert.Equal(t, ReasonPriority, event.Reason)
+
+	var b strings.Builder
+	assert.NoError(t, event.Logf(&b, nil))
+	assert.Contains(t, b.String(), `"Workload":"oltp","Priority":0,"Throttled":false,"DryRun":false,"Reason":"priority"}`)
+}
+
+func TestReplicaSets(t *testing.T) {
+	state := &txThrottlerStateImpl{
+		tabletTypes: map[topodatapb.TabletType]bool{topodatapb.TabletType_REPLICA: true},
+	}
+	assert.True(t, state.monitored("cell1", topodatapb.TabletType_REPLICA))
+	assert.True(t, state.monitored("cell2", topodatapb.TabletType_REPLICA))
+	assert.False(t, state.monitored("cell1", topodatapb.TabletType_RDONLY))
+
+	state.replicaSets = map[string]map[topodatapb.TabletType]bool{
+		"cell1": {topodatapb.TabletType_REPLICA: true},
+		"cell2": {topodatapb.TabletType_RDONLY: true},
+	}
+	assert.True(t, state.monitored("cell1", topodatapb.TabletType_REPLICA))
+	assert.False(t, state.monitored("cell2", topodatapb.TabletType_REPLICA))
+	assert.True(t, state.monitored("cell2", topodatapb.TabletType_RDONLY))
+	assert.False(t, state.monitored("cell3", topodatapb.TabletType_RDONLY))
+}
+
+func TestReplicaSetsCells(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	defer resetTxThrottlerFactories()
+	ts := memorytopo.NewServer(ctx, "cell1", "cell2", "cell3")
+
+	mockHealthCheck := NewMockHealthCheck(mockCtrl)
+	mockHealthCheck.EXPECT().Subscribe()
+	mockHealthCheck.EXPECT().Close()
+	healthCheckFactory = func(topoServer *topo.Server, cell string, cellsToWatch []string) discovery.HealthCheck {
+		assert.Equal(t, []string{"cell2", "cell3"}, cellsToWatch)
+		return mockHealthCheck
+	}
+	topologyWatcherFactory = func(topoServer *topo.Server, hc discovery.HealthCheck, cell, keyspace, shard string, refreshInterval time.Duration, topoReadConcurrency int) TopologyWatcherInterface {
+		result := NewMockTopologyWatcherInterface(mockCtrl)
+		result.EXPECT().Stop()
+		return result
+	}
+	mockThrottler := NewMockThrottlerInterface(mockCtrl)
+	throttlerFactory = func(name, unit string, threadCount int, maxRate int64, maxReplicationLagConfig throttler.MaxReplicationLagModuleConfig) (ThrottlerInterface, error) {
+		return mockThrottler, nil
+	}
+	mockThrottler.EXPECT().UpdateConfiguration(gomock.Any(), true /* copyZeroValues */)
+	mockThrottler.EXPECT().Close()
+
+	config := tabletenv.NewDefaultConfig()
+	config.EnableTxThrottler = true
+	config.TxThrottlerReplicaSets = []string{"cell3:rdonly", "cell2:replica"}
+
+	env := tabletenv.NewEnv(config, t.Name())
+	throttler := NewTxThrottler(env, ts)
+	throttlerImpl, _ := throttler.(*txThrottler)
+	throttler.InitDBConfig(&querypb.Target{
+		Cell:     "cell1",
+		Keyspace: "keyspace",
+		Shard:    "shard",
+	})
+
+	assert.Nil(t, throttlerImpl.Open())
+	throttlerStateImpl := throttlerImpl.state.(*txThrottlerStateImpl)
+	assert.False(t, throttlerStateImpl.cellsFromTopo)
+	assert.Equal(t, map[string]int64{"cell2": 1, "cell3": 1}, throttlerImpl.topoWatchers.Counts())
+	throttlerImpl.Close()
+}
+
 type mockTxThrottlerState struct {
 	shouldThrottle bool
 }
@@ -79,6 +79,14 @@ const (
 	GreaterThanEqual
 	// NotEqual is used to filter a comparable column if != specific value
 	NotEqual
+	// IsNull is used to filter a column if it is null
+	IsNull
+	// IsNotNull is used to filter a column if it is not null
+	IsNotNull
+	// In is used to filter a comparable column if it is equal to one of a list of values
+	In
+	// NotIn is used to filter a comparable column if it is equal to none of a list of values
+	NotIn
 )
 
 // Filter contains opcodes for filtering.
@@ -86,6 +94,8 @@ type Filter struct {
 	Opcode Opcode
 	ColNum int
 	Value  sqltypes.Value
+	// Values contains the list of values for In and NotIn.
+	Values []sqltypes.Value
 
 	// Parameters for VindexMatch.
 	// Vindex, VindexColumns and KeyRange, if set, will be used
@@ -156,6 +166,10 @@ func getOpcode(comparison *sqlparser.ComparisonExpr) (Opcode, error) {
 		opcode = GreaterThanEqual
 	case sqlparser.NotEqualOp:
 		opcode = NotEqual
+	case sqlparser.InOp:
+		opcode = In
+	case sqlparser.NotInOp:
+		opcode = NotIn
 	default:
 		return -1, fmt.Errorf("comparison operator %s not supported", comparison.Operator.ToString())
 	}
@@ -206,6 +220,25 @@ func compare(comparison Opcode, columnValue, filterValue sqltypes.Value, charset
 	return false, nil
 }
 
+// compareList returns true after applying the In or NotIn comparison specified in the Filter
+// to the actual data in the column
+func compareList(comparison Opcode, columnValue sqltypes.Value, filterValues []sqltypes.Value, charset collations.ID) (bool, error) {
+	// use null semantics: a null column value is neither in nor not in the list
+	if columnValue.IsNull() {
+		return false, nil
+	}
+	for _, filterValue := range filterValues {
+		match, err := compare(Equal, columnValue, filterValue, charset)
+		if err != nil {
+			return false, err
+		}
+		if match {
+			return comparison == In, nil
+		}
+	}
+	return comparison == NotIn, nil
+}
+
 // filter filters the row against the plan. It returns false if the row did not match.
 // The output of the filtering operation is stored in the 'result' argument because
 // filtering cannot be performed in-place. The result argument must be a slice of
@@ -224,6 +257,22 @@ func (plan *Plan) filter(values, result []sqltypes.Value, charsets []collations.
 			if !key.KeyRangeContains(filter.KeyRange, ksid) {
 				return false, nil
 			}
+		case IsNull:
+			if !values[filter.ColNum].IsNull() {
+				return false, nil
+			}
+		case IsNotNull:
+			if values[filter.ColNum].IsNull() {
+				return false, nil
+			}
+		case In, NotIn:
+			match, err := compareList(filter.Opcode, values[filter.ColNum], filter.Values, charsets[filter.ColNum])
+			if err != nil {
+				return false, err
+			}
+			if !match {
+				return false, nil
+			}
 		default:
 			match, err := compare(filter.Opcode, values[filter.ColNum], filter.Value, charsets[filter.ColNum])
 			if err != nil {
@@ -510,38 +559,50 @@ func (plan *Plan) analyzeWhere(vschema *localVSchema, where *sqlparser.Where) er
 			if err != nil {
 				return err
 			}
-			qualifiedName, ok := expr.Left.(*sqlparser.ColName)
-			if !ok {
-				return fmt.Errorf("unexpected: %v", sqlparser.String(expr))
-			}
-			if !qualifiedName.Qualifier.IsEmpty() {
-				return fmt.Errorf("unsupported qualifier for column: %v", sqlparser.String(qualifiedName))
-			}
-			colnum, err := findColumn(plan.Table, qualifiedName.Name)
+			colnum, err := plan.whereColumn(expr.Left, expr)
 			if err != nil {
 				return err
 			}
-			val, ok := expr.Right.(*sqlparser.Literal)
-			if !ok {
-				return fmt.Errorf("unexpected: %v", sqlparser.String(expr))
+			filter := Filter{
+				Opcode: opcode,
+				ColNum: colnum,
 			}
-			//StrVal is varbinary, we do not support varchar since we would have to implement all collation types
-			if val.Type != sqlparser.IntVal && val.Type != sqlparser.StrVal {
-				return fmt.Errorf("unexpected: %v", sqlparser.String(expr))
+			switch opcode {
+			case In, NotIn:
+				tuple, ok := expr.Right.(sqlparser.ValTuple)
+				if !ok {
+					return fmt.Errorf("unexpected: %v", sqlparser.String(expr))
+				}
+				for _, elem := range tuple {
+					val, err := whereValue(elem, expr)
+					if err != nil {
+						return err
+					}
+					filter.Values = append(filter.Values, val)
+				}
+			default:
+				if filter.Value, err = whereValue(expr.Right, expr); err != nil {
+					return err
+				}
 			}
-			pv, err := evalengine.Translate(val, nil)
+			plan.Filters = append(plan.Filters, filter)
+		case *sqlparser.IsExpr:
+			colnum, err := plan.whereColumn(expr.Left, expr)
 			if err != nil {
 				return err
 			}
-			env := evalengine.EmptyExpressionEnv()
-			resolved, err := env.Evaluate(pv)
-			if err != nil {
-				return err
+			var opcode Opcode
+			switch expr.Right {
+			case sqlparser.IsNullOp:
+				opcode = IsNull
+			case sqlparser.IsNotNullOp:
+				opcode = IsNotNull
+			default:
+				return fmt.Errorf("unsupported constraint: %v", sqlparser.String(expr))
 			}
 			plan.Filters = append(plan.Filters, Filter{
 				Opcode: opcode,
 				ColNum: colnum,
-				Value:  resolved.Value(collations.Default()),
 			})
 		case *sqlparser.FuncExpr:
 			if !expr.Name.EqualString("in_keyrange") {
@@ -557,6 +618,41 @@ func (plan *Plan) analyzeWhere(vschema *localVSchema, where *sqlparser.Where) er
 	return nil
 }
 
+// whereColumn returns the column number of the unqualified column
+// on the left side of a constraint of the where clause.
+func (plan *Plan) whereColumn(left sqlparser.Expr, constraint sqlparser.Expr) (int, error) {
+	qualifiedName, ok := left.(*sqlparser.ColName)
+	if !ok {
+		return 0, fmt.Errorf("unexpected: %v", sqlparser.String(constraint))
+	}
+	if !qualifiedName.Qualifier.IsEmpty() {
+		return 0, fmt.Errorf("unsupported qualifier for column: %v", sqlparser.String(qualifiedName))
+	}
+	return findColumn(plan.Table, qualifiedName.Name)
+}
+
+// whereValue returns the value of a literal of a constraint of the where clause.
+func whereValue(expr sqlparser.Expr, constraint sqlparser.Expr) (sqltypes.Value, error) {
+	val, ok := expr.(*sqlparser.Literal)
+	if !ok {
+		return sqltypes.NULL, fmt.Errorf("unexpected: %v", sqlparser.String(constraint))
+	}
+	//StrVal is varbinary, we do not support varchar since we would have to implement all collation types
+	if val.Type != sqlparser.IntVal && val.Type != sqlparser.StrVal {
+		return sqltypes.NULL, fmt.Errorf("unexpected: %v", sqlparser.String(constraint))
+	}
+	pv, err := evalengine.Translate(val, nil)
+	if err != nil {
+		return sqltypes.NULL, err
+	}
+	env := evalengine.EmptyExpressionEnv()
+	resolved, err := env.Evaluate(pv)
+	if err != nil {
+		return sqltypes.NULL, err
+	}
+	return resolved.Value(collations.Default()), nil
+}
+
 // splitAndExpression breaks up the Expr into AND-separated conditions
 // and appends them to filters, which can be shuffled and recombined
 // as needed.
@@ -704,6 +704,35 @@ func TestPlanBuilderFilterComparison(t *testing.T) {
 			{Opcode: Equal, ColNum: 0, Value: sqltypes.NewInt64(2)},
 			{Opcode: NotEqual, ColNum: 1, Value: sqltypes.NewVarChar("xyz")},
 		},
+	}, {
+		name:     "in",
+		inFilter: "select * from t1 where id in (1, 2)",
+		outFilters: []Filter{{Opcode: In, ColNum: 0, Values: []sqltypes.Value{
+			sqltypes.NewInt64(1), sqltypes.NewInt64(2),
+		}}},
+	}, {
+		name:       "not-in",
+		inFilter:   "select * from t1 where val not in ('abc')",
+		outFilters: []Filter{{Opcode: NotIn, ColNum: 1, Values: []sqltypes.Value{sqltypes.NewVarChar("abc")}}},
+	}, {
+		name:     "is-null-and-is-not-null",
+		inFilter: "select * from t1 where val is null and id is not null",
+		outFilters: []Filter{
+			{Opcode: IsNull, ColNum: 1},
+			{Opcode: IsNotNull, ColNum: 0},
+		},
+	}, {
+		name:     "in-with-column",
+		inFilter: "select * from t1 where id in (1, val)",
+		outErr:   "unexpected: id in (1, val)",
+	}, {
+		name:     "is-true",
+		inFilter: "select * from t1 where id is true",
+		outErr:   "unsupported constraint: id is true",
+	}, {
+		name:     "like",
+		inFilter: "select * from t1 where val like 'a%'",
+		outErr:   "comparison operator like not supported",
 	}}
 
 	for _, tcase := range testcases {
@@ -758,3 +787,29 @@ func TestCompare(t *testing.T) {
 		})
 	}
 }
+
+func TestCompareList(t *testing.T) {
+	int1 := sqltypes.NewInt32(1)
+	int2 := sqltypes.NewInt32(2)
+	int3 := sqltypes.NewInt32(3)
+	testcases := []struct {
+		opcode       Opcode
+		columnValue  sqltypes.Value
+		filterValues []sqltypes.Value
+		want         bool
+	}{
+		{opcode: In, columnValue: int1, filterValues: []sqltypes.Value{int1, int2}, want: true},
+		{opcode: In, columnValue: int3, filterValues: []sqltypes.Value{int1, int2}, want: false},
+		{opcode: In, columnValue: sqltypes.NULL, filterValues: []sqltypes.Value{int1, int2}, want: false},
+		{opcode: NotIn, columnValue: int1, filterValues: []sqltypes.Value{int1, int2}, want: false},
+		{opcode: NotIn, columnValue: int3, filterValues: []sqltypes.Value{int1, int2}, want: true},
+		{opcode: NotIn, columnValue: sqltypes.NULL, filterValues: []sqltypes.Value{int1, int2}, want: false},
+	}
+	for _, tc := range testcases {
+		t.Run("", func(t *testing.T) {
+			got, err := compareList(tc.opcode, tc.columnValue, tc.filterValues, collations.CollationUtf8mb4ID)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -35,6 +35,7 @@ import (
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
@@ -169,6 +170,11 @@ func (tqsc *Controller) IsHealthy() error {
 	return nil
 }
 
+// UnresolvedTransactions is part of the tabletserver.Controller interface
+func (tqsc *Controller) UnresolvedTransactions(ctx context.Context, abandonAge time.Duration) ([]*querypb.TransactionMetadata, error) {
+	return nil, nil
+}
+
 // ReloadSchema is part of the tabletserver.Controller interface
 func (tqsc *Controller) ReloadSchema(ctx context.Context) error {
 	return nil
@@ -178,6 +184,21 @@ func (tqsc *Controller) ReloadSchema(ctx context.Context) error {
 func (tqsc *Controller) ClearQueryPlanCache() {
 }
 
+// GetPlanCache is part of the tabletserver.Controller interface
+func (tqsc *Controller) GetPlanCache(tables, fingerprints []string) []*tabletmanagerdatapb.PlanCacheEntry {
+	return nil
+}
+
+// GetTableStats is part of the tabletserver.Controller interface
+func (tqsc *Controller) GetTableStats(tables []string) []*tabletmanagerdatapb.TableStats {
+	return nil
+}
+
+// InvalidatePlanCache is part of the tabletserver.Controller interface
+func (tqsc *Controller) InvalidatePlanCache(tables, fingerprints []string, all bool) int {
+	return 0
+}
+
 // RegisterQueryRuleSource is part of the tabletserver.Controller interface
 func (tqsc *Controller) RegisterQueryRuleSource(ruleSource string) {
 }
@@ -0,0 +1,216 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tmclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc/status"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// RetryPolicy controls how a client returned by NewRetryingClient retries the
+// tablet manager RPCs it wraps.
+type RetryPolicy struct {
+	// MaxAttempts is the number of times an RPC is attempted, including the
+	// first one. Values of 1 or less disable retries.
+	MaxAttempts int
+	// InitialBackoff is the time to wait before the first retry. It doubles
+	// after each retry, up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// RetryableCodes are the error codes that get retried. Other errors are
+	// returned right away.
+	RetryableCodes []vtrpcpb.Code
+}
+
+var (
+	retryPolicy = RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+		RetryableCodes: []vtrpcpb.Code{vtrpcpb.Code_UNAVAILABLE},
+	}
+
+	retries = stats.NewCountersWithSingleLabel("TabletManagerClientRetries", "Number of tablet manager RPCs that were retried, by method", "Method")
+)
+
+func init() {
+	for _, cmd := range []string{"vtcombo", "vtctld"} {
+		servenv.OnParseFor(cmd, registerRetryFlags)
+	}
+}
+
+func registerRetryFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&retryPolicy.MaxAttempts, "tablet-rpc-retry-max-attempts", retryPolicy.MaxAttempts, "Number of times the RefreshState and ReloadSchema tablet manager RPCs are attempted before giving up on a tablet, including the first attempt. Set to 1 to disable retries.")
+	fs.DurationVar(&retryPolicy.InitialBackoff, "tablet-rpc-retry-initial-backoff", retryPolicy.InitialBackoff, "Time to wait before the first retry of a tablet manager RPC. It doubles after each retry, up to --tablet-rpc-retry-max-backoff.")
+	fs.DurationVar(&retryPolicy.MaxBackoff, "tablet-rpc-retry-max-backoff", retryPolicy.MaxBackoff, "Maximum time to wait between two attempts of a tablet manager RPC.")
+	fs.Var((*retryableCodesFlag)(&retryPolicy.RetryableCodes), "tablet-rpc-retry-codes", "Comma-separated list of the error codes that tablet manager RPCs are retried on, e.g. UNAVAILABLE,DEADLINE_EXCEEDED.")
+}
+
+// retryableCodesFlag is a pflag.Value for a list of error codes.
+type retryableCodesFlag []vtrpcpb.Code
+
+// Set is part of the pflag.Value interface.
+func (f *retryableCodesFlag) Set(v string) error {
+	var codes []vtrpcpb.Code
+	for _, name := range strings.Split(v, ",") {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		code, ok := vtrpcpb.Code_value[name]
+		if !ok {
+			return fmt.Errorf("unknown error code %q", name)
+		}
+		codes = append(codes, vtrpcpb.Code(code))
+	}
+
+	*f = codes
+	return nil
+}
+
+// String is part of the pflag.Value interface.
+func (f *retryableCodesFlag) String() string {
+	names := make([]string, len(*f))
+	for i, code := range *f {
+		names[i] = code.String()
+	}
+
+	return strings.Join(names, ",")
+}
+
+// Type is part of the pflag.Value interface.
+func (f *retryableCodesFlag) Type() string {
+	return "strings"
+}
+
+// RetryPolicyFromFlags returns the retry policy configured with the
+// --tablet-rpc-retry-* flags.
+func RetryPolicyFromFlags() RetryPolicy {
+	policy := retryPolicy
+	policy.RetryableCodes = append([]vtrpcpb.Code(nil), retryPolicy.RetryableCodes...)
+	return policy
+}
+
+// IsRetryable returns whether the policy retries the given error.
+func (p RetryPolicy) IsRetryable(err error) bool {
+	code := vterrors.Code(err)
+	if s, ok := status.FromError(err); ok {
+		// Errors returned by the gRPC tablet manager client are not vterrors.
+		code = vtrpcpb.Code(s.Code())
+	}
+
+	for _, retryable := range p.RetryableCodes {
+		if code == retryable {
+			return true
+		}
+	}
+
+	return false
+}
+
+// backoff returns the time to wait before the given retry, starting at 1.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 1; i < retry && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+
+	return backoff
+}
+
+// Do runs f until it succeeds, returns an error the policy does not retry,
+// or runs out of attempts, waiting between attempts. It also gives up when
+// ctx is done, returning the last error of f.
+func (p RetryPolicy) Do(ctx context.Context, method string, tablet *topodatapb.Tablet, f func() error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = f()
+		if err == nil || attempt >= p.MaxAttempts || !p.IsRetryable(err) {
+			return err
+		}
+
+		backoff := p.backoff(attempt)
+		log.Warningf("%s on tablet %s failed (attempt %d/%d), retrying in %v: %v", method, topoproto.TabletAliasString(tablet.Alias), attempt, p.MaxAttempts, backoff, err)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		retries.Add(method, 1)
+	}
+}
+
+// retryingClient is a TabletManagerClient that retries the RPCs that vtctld
+// fans out to many tablets at once, so that a single transiently unreachable
+// tablet does not fail the whole operation.
+type retryingClient struct {
+	TabletManagerClient
+	policy RetryPolicy
+}
+
+// NewRetryingClient returns a TabletManagerClient that retries the
+// idempotent RefreshState and ReloadSchema RPCs of tmc according to the
+// policy. The other RPCs are passed through as-is: an UNAVAILABLE error does
+// not mean that the tablet did not run them, e.g. ExecuteFetchAsDba could
+// run a statement twice.
+func NewRetryingClient(tmc TabletManagerClient, policy RetryPolicy) TabletManagerClient {
+	if policy.MaxAttempts <= 1 {
+		return tmc
+	}
+
+	return &retryingClient{
+		TabletManagerClient: tmc,
+		policy:              policy,
+	}
+}
+
+// RefreshState is part of the TabletManagerClient interface.
+func (c *retryingClient) RefreshState(ctx context.Context, tablet *topodatapb.Tablet) error {
+	return c.policy.Do(ctx, "RefreshState", tablet, func() error {
+		return c.TabletManagerClient.RefreshState(ctx, tablet)
+	})
+}
+
+// ReloadSchema is part of the TabletManagerClient interface.
+func (c *retryingClient) ReloadSchema(ctx context.Context, tablet *topodatapb.Tablet, waitPosition string) error {
+	return c.policy.Do(ctx, "ReloadSchema", tablet, func() error {
+		return c.TabletManagerClient.ReloadSchema(ctx, tablet, waitPosition)
+	})
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tmclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// flakyClient fails the first calls of each RPC with the given errors.
+type flakyClient struct {
+	TabletManagerClient
+	errs  []error
+	calls int
+}
+
+func (c *flakyClient) next() error {
+	c.calls++
+	if c.calls <= len(c.errs) {
+		return c.errs[c.calls-1]
+	}
+	return nil
+}
+
+func (c *flakyClient) RefreshState(ctx context.Context, tablet *topodatapb.Tablet) error {
+	return c.next()
+}
+
+func (c *flakyClient) ReloadSchema(ctx context.Context, tablet *topodatapb.Tablet, waitPosition string) error {
+	return c.next()
+}
+
+func (c *flakyClient) ExecuteFetchAsDba(ctx context.Context, tablet *topodatapb.Tablet, usePool bool, req *tabletmanagerdatapb.ExecuteFetchAsDbaRequest) (*querypb.QueryResult, error) {
+	if err := c.next(); err != nil {
+		return nil, err
+	}
+	return &querypb.QueryResult{RowsAffected: 1}, nil
+}
+
+func TestRetryingClient(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+		RetryableCodes: []vtrpcpb.Code{vtrpcpb.Code_UNAVAILABLE},
+	}
+	tablet := &topodatapb.Tablet{Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}}
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+	failedPrecondition := vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "bad query")
+
+	tests := []struct {
+		name          string
+		errs          []error
+		expectedErr   error
+		expectedCalls int
+	}{
+		{
+			name:          "success",
+			expectedCalls: 1,
+		},
+		{
+			name:          "transient gRPC error",
+			errs:          []error{unavailable, unavailable},
+			expectedCalls: 3,
+		},
+		{
+			name:          "transient vterror",
+			errs:          []error{vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "tablet is restarting")},
+			expectedCalls: 2,
+		},
+		{
+			name:          "out of attempts",
+			errs:          []error{unavailable, unavailable, unavailable},
+			expectedErr:   unavailable,
+			expectedCalls: 3,
+		},
+		{
+			name:          "error not retried",
+			errs:          []error{failedPrecondition},
+			expectedErr:   failedPrecondition,
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &flakyClient{errs: tt.errs}
+			tmc := NewRetryingClient(fake, policy)
+
+			err := tmc.RefreshState(context.Background(), tablet)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedCalls, fake.calls)
+
+			fake = &flakyClient{errs: tt.errs}
+			tmc = NewRetryingClient(fake, policy)
+
+			err = tmc.ReloadSchema(context.Background(), tablet, "")
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedCalls, fake.calls)
+		})
+	}
+
+	t.Run("ExecuteFetchAsDba not retried", func(t *testing.T) {
+		// The statement may have run even though the tablet was unavailable.
+		fake := &flakyClient{errs: []error{unavailable}}
+		tmc := NewRetryingClient(fake, policy)
+		_, err := tmc.ExecuteFetchAsDba(context.Background(), tablet, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{})
+		assert.Equal(t, unavailable, err)
+		assert.Equal(t, 1, fake.calls)
+	})
+
+	t.Run("retries disabled", func(t *testing.T) {
+		fake := &flakyClient{errs: []error{unavailable}}
+		tmc := NewRetryingClient(fake, RetryPolicy{MaxAttempts: 1})
+		assert.Same(t, fake, tmc)
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		fake := &flakyClient{errs: []error{unavailable}}
+		tmc := NewRetryingClient(fake, policy)
+		err := tmc.RefreshState(ctx, tablet)
+		assert.True(t, errors.Is(err, unavailable))
+		assert.Equal(t, 1, fake.calls)
+	})
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+	}
+
+	assert.Equal(t, 100*time.Millisecond, policy.backoff(1))
+	assert.Equal(t, 200*time.Millisecond, policy.backoff(2))
+	assert.Equal(t, 400*time.Millisecond, policy.backoff(3))
+	assert.Equal(t, 800*time.Millisecond, policy.backoff(4))
+	assert.Equal(t, time.Second, policy.backoff(5))
+	assert.Equal(t, time.Second, policy.backoff(10))
+}
+
+func TestRetryableCodesFlag(t *testing.T) {
+	var codes []vtrpcpb.Code
+	f := (*retryableCodesFlag)(&codes)
+
+	require.NoError(t, f.Set("unavailable, DEADLINE_EXCEEDED"))
+	assert.Equal(t, []vtrpcpb.Code{vtrpcpb.Code_UNAVAILABLE, vtrpcpb.Code_DEADLINE_EXCEEDED}, codes)
+	assert.Equal(t, "UNAVAILABLE,DEADLINE_EXCEEDED", f.String())
+
+	assert.Error(t, f.Set("UNAVAILABLE,NOT_A_CODE"))
+}
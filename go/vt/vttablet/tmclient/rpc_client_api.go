@@ -74,6 +74,18 @@ type TabletManagerClient interface {
 	// GetPermissions asks the remote tablet for its permissions list
 	GetPermissions(ctx context.Context, tablet *topodatapb.Tablet) (*tabletmanagerdatapb.Permissions, error)
 
+	// GetPlanCache asks the remote tablet for the query plans of its plan cache
+	GetPlanCache(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.GetPlanCacheRequest) (*tabletmanagerdatapb.GetPlanCacheResponse, error)
+
+	// GetTableStats asks the remote tablet for the row and byte accounting of its queries per table
+	GetTableStats(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.GetTableStatsRequest) (*tabletmanagerdatapb.GetTableStatsResponse, error)
+
+	// GetUnresolvedTransactions asks the remote tablet for the distributed transactions of which it is the metadata manager
+	GetUnresolvedTransactions(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.GetUnresolvedTransactionsRequest) (*tabletmanagerdatapb.GetUnresolvedTransactionsResponse, error)
+
+	// ReadTransaction asks the remote tablet for the metadata of a distributed transaction
+	ReadTransaction(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.ReadTransactionRequest) (*tabletmanagerdatapb.ReadTransactionResponse, error)
+
 	//
 	// Various read-write methods
 	//
@@ -105,6 +117,12 @@ type TabletManagerClient interface {
 	// ReloadSchema asks the remote tablet to reload its schema
 	ReloadSchema(ctx context.Context, tablet *topodatapb.Tablet, waitPosition string) error
 
+	// InvalidatePlanCache asks the remote tablet to remove query plans from its plan cache
+	InvalidatePlanCache(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.InvalidatePlanCacheRequest) (*tabletmanagerdatapb.InvalidatePlanCacheResponse, error)
+
+	// ConcludeTransaction asks the remote tablet to resolve its part of a distributed transaction
+	ConcludeTransaction(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.ConcludeTransactionRequest) (*tabletmanagerdatapb.ConcludeTransactionResponse, error)
+
 	// PreflightSchema will test a list of schema changes.
 	PreflightSchema(ctx context.Context, tablet *topodatapb.Tablet, changes []string) ([]*tabletmanagerdatapb.SchemaChangeResult, error)
 
@@ -243,8 +261,12 @@ type TabletManagerClient interface {
 	// Backup creates a database backup
 	Backup(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.BackupRequest) (logutil.EventStream, error)
 
-	// RestoreFromBackup deletes local data and restores database from backup
-	RestoreFromBackup(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.RestoreFromBackupRequest) (logutil.EventStream, error)
+	// RestoreFromBackup deletes local data and restores database from backup.
+	// The stream sends the logs and the progress of the restore.
+	RestoreFromBackup(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.RestoreFromBackupRequest) (RestoreFromBackupStream, error)
+
+	// UpgradeMysql upgrades mysqld in place, logging each step of the upgrade.
+	UpgradeMysql(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.UpgradeMysqlRequest) (logutil.EventStream, error)
 
 	// Throttler
 	CheckThrottler(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.CheckThrottlerRequest) (*tabletmanagerdatapb.CheckThrottlerResponse, error)
@@ -258,6 +280,13 @@ type TabletManagerClient interface {
 	Close()
 }
 
+// RestoreFromBackupStream is the stream returned by RestoreFromBackup. Each
+// response holds either a log event or the progress of the restore.
+type RestoreFromBackupStream interface {
+	// Recv returns the next response, or io.EOF when the restore is done.
+	Recv() (*tabletmanagerdatapb.RestoreFromBackupResponse, error)
+}
+
 // TabletManagerClientFactory is the factory method to create
 // TabletManagerClient objects.
 type TabletManagerClientFactory func() TabletManagerClient
@@ -42,6 +42,7 @@ import (
 	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/proto/vttime"
 )
 
 // fakeRPCTM implements tabletmanager.RPCTM and fills in all
@@ -367,6 +368,128 @@ func tmRPCTestGetPermissionsPanic(ctx context.Context, t *testing.T, client tmcl
 	expectHandleRPCPanic(t, "GetPermissions", false /*verbose*/, err)
 }
 
+var testGetPlanCacheReq = &tabletmanagerdatapb.GetPlanCacheRequest{
+	Tables:       []string{"table1"},
+	Fingerprints: []string{"select * from table1 where id = :id"},
+}
+var testGetPlanCacheReply = []*tabletmanagerdatapb.PlanCacheEntry{
+	{
+		Query:       "select * from table1 where id = 1",
+		Fingerprint: "select * from table1 where id = :id",
+		PlanType:    "Select",
+		Tables:      []string{"table1"},
+		QueryCount:  12,
+		AverageTime: &vttime.Duration{Nanos: 1000000},
+	},
+}
+
+func (fra *fakeRPCTM) GetPlanCache(ctx context.Context, tables, fingerprints []string) ([]*tabletmanagerdatapb.PlanCacheEntry, error) {
+	if fra.panics {
+		panic(fmt.Errorf("test-triggered panic"))
+	}
+	compare(fra.t, "GetPlanCache tables", tables, testGetPlanCacheReq.Tables)
+	compare(fra.t, "GetPlanCache fingerprints", fingerprints, testGetPlanCacheReq.Fingerprints)
+	return testGetPlanCacheReply, nil
+}
+
+func tmRPCTestGetPlanCache(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	result, err := client.GetPlanCache(ctx, tablet, testGetPlanCacheReq)
+	if err != nil {
+		t.Errorf("GetPlanCache failed: %v", err)
+		return
+	}
+	compare(t, "GetPlanCache result", result.Entries, testGetPlanCacheReply)
+}
+
+func tmRPCTestGetPlanCachePanic(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	_, err := client.GetPlanCache(ctx, tablet, testGetPlanCacheReq)
+	expectHandleRPCPanic(t, "GetPlanCache", false /*verbose*/, err)
+}
+
+var testGetTableStatsReq = &tabletmanagerdatapb.GetTableStatsRequest{
+	Tables: []string{"table1"},
+}
+var testGetTableStatsReply = []*tabletmanagerdatapb.TableStats{
+	{
+		Name:         "table1",
+		RowsRead:     100,
+		RowsReturned: 120,
+		RowsAffected: 3,
+		BytesSent:    4096,
+	},
+}
+
+func (fra *fakeRPCTM) GetTableStats(ctx context.Context, tables []string) ([]*tabletmanagerdatapb.TableStats, error) {
+	if fra.panics {
+		panic(fmt.Errorf("test-triggered panic"))
+	}
+	compare(fra.t, "GetTableStats tables", tables, testGetTableStatsReq.Tables)
+	return testGetTableStatsReply, nil
+}
+
+func tmRPCTestGetTableStats(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	result, err := client.GetTableStats(ctx, tablet, testGetTableStatsReq)
+	if err != nil {
+		t.Errorf("GetTableStats failed: %v", err)
+		return
+	}
+	compare(t, "GetTableStats result", result.TableStats, testGetTableStatsReply)
+}
+
+func tmRPCTestGetTableStatsPanic(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	_, err := client.GetTableStats(ctx, tablet, testGetTableStatsReq)
+	expectHandleRPCPanic(t, "GetTableStats", false /*verbose*/, err)
+}
+
+var testTransactionMetadata = &querypb.TransactionMetadata{
+	Dtid:        "ks:0:1234",
+	State:       querypb.TransactionState_COMMIT,
+	TimeCreated: 1000,
+	Participants: []*querypb.Target{{
+		Keyspace:   "ks",
+		Shard:      "80-",
+		TabletType: topodatapb.TabletType_PRIMARY,
+	}},
+}
+
+func (fra *fakeRPCTM) GetUnresolvedTransactions(ctx context.Context, abandonAge time.Duration) ([]*querypb.TransactionMetadata, error) {
+	if fra.panics {
+		panic(fmt.Errorf("test-triggered panic"))
+	}
+	compare(fra.t, "GetUnresolvedTransactions abandonAge", abandonAge, 5*time.Minute)
+	return []*querypb.TransactionMetadata{testTransactionMetadata}, nil
+}
+
+func tmRPCTestGetUnresolvedTransactions(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	result, err := client.GetUnresolvedTransactions(ctx, tablet, &tabletmanagerdatapb.GetUnresolvedTransactionsRequest{AbandonAge: 300})
+	compareError(t, "GetUnresolvedTransactions", err, result, &tabletmanagerdatapb.GetUnresolvedTransactionsResponse{
+		Transactions: []*querypb.TransactionMetadata{testTransactionMetadata},
+	})
+}
+
+func tmRPCTestGetUnresolvedTransactionsPanic(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	_, err := client.GetUnresolvedTransactions(ctx, tablet, &tabletmanagerdatapb.GetUnresolvedTransactionsRequest{AbandonAge: 300})
+	expectHandleRPCPanic(t, "GetUnresolvedTransactions", false /*verbose*/, err)
+}
+
+func (fra *fakeRPCTM) ReadTransaction(ctx context.Context, dtid string) (*querypb.TransactionMetadata, error) {
+	if fra.panics {
+		panic(fmt.Errorf("test-triggered panic"))
+	}
+	compare(fra.t, "ReadTransaction dtid", dtid, testTransactionMetadata.Dtid)
+	return testTransactionMetadata, nil
+}
+
+func tmRPCTestReadTransaction(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	result, err := client.ReadTransaction(ctx, tablet, &tabletmanagerdatapb.ReadTransactionRequest{Dtid: testTransactionMetadata.Dtid})
+	compareError(t, "ReadTransaction", err, result, &tabletmanagerdatapb.ReadTransactionResponse{Transaction: testTransactionMetadata})
+}
+
+func tmRPCTestReadTransactionPanic(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	_, err := client.ReadTransaction(ctx, tablet, &tabletmanagerdatapb.ReadTransactionRequest{Dtid: testTransactionMetadata.Dtid})
+	expectHandleRPCPanic(t, "ReadTransaction", false /*verbose*/, err)
+}
+
 //
 // Various read-write methods
 //
@@ -552,6 +675,55 @@ func tmRPCTestReloadSchemaPanic(ctx context.Context, t *testing.T, client tmclie
 	expectHandleRPCPanic(t, "ReloadSchema", false /*verbose*/, err)
 }
 
+var testInvalidatePlanCacheReq = &tabletmanagerdatapb.InvalidatePlanCacheRequest{
+	Tables: []string{"table1", "table2"},
+}
+
+func (fra *fakeRPCTM) InvalidatePlanCache(ctx context.Context, tables, fingerprints []string, all bool) (int, error) {
+	if fra.panics {
+		panic(fmt.Errorf("test-triggered panic"))
+	}
+	compare(fra.t, "InvalidatePlanCache tables", tables, testInvalidatePlanCacheReq.Tables)
+	compare(fra.t, "InvalidatePlanCache fingerprints", len(fingerprints), 0)
+	compare(fra.t, "InvalidatePlanCache all", all, false)
+	return 3, nil
+}
+
+func tmRPCTestInvalidatePlanCache(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	result, err := client.InvalidatePlanCache(ctx, tablet, testInvalidatePlanCacheReq)
+	compareError(t, "InvalidatePlanCache", err, result, &tabletmanagerdatapb.InvalidatePlanCacheResponse{Invalidated: 3})
+}
+
+func tmRPCTestInvalidatePlanCachePanic(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	_, err := client.InvalidatePlanCache(ctx, tablet, testInvalidatePlanCacheReq)
+	expectHandleRPCPanic(t, "InvalidatePlanCache", true /*verbose*/, err)
+}
+
+var testConcludeTransactionReq = &tabletmanagerdatapb.ConcludeTransactionRequest{
+	Dtid:   "ks:0:1234",
+	Commit: true,
+}
+
+func (fra *fakeRPCTM) ConcludeTransaction(ctx context.Context, dtid string, mm, commit bool) error {
+	if fra.panics {
+		panic(fmt.Errorf("test-triggered panic"))
+	}
+	compare(fra.t, "ConcludeTransaction dtid", dtid, testConcludeTransactionReq.Dtid)
+	compare(fra.t, "ConcludeTransaction mm", mm, false)
+	compare(fra.t, "ConcludeTransaction commit", commit, true)
+	return nil
+}
+
+func tmRPCTestConcludeTransaction(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	result, err := client.ConcludeTransaction(ctx, tablet, testConcludeTransactionReq)
+	compareError(t, "ConcludeTransaction", err, result, &tabletmanagerdatapb.ConcludeTransactionResponse{})
+}
+
+func tmRPCTestConcludeTransactionPanic(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	_, err := client.ConcludeTransaction(ctx, tablet, testConcludeTransactionReq)
+	expectHandleRPCPanic(t, "ConcludeTransaction", true /*verbose*/, err)
+}
+
 var testPreflightSchema = []string{"change table add table cloth"}
 var testSchemaChangeResult = []*tabletmanagerdatapb.SchemaChangeResult{
 	{
@@ -1243,6 +1415,15 @@ var testBackupConcurrency = int64(24)
 var testBackupAllowPrimary = false
 var testBackupCalled = false
 var testRestoreFromBackupCalled = false
+var testRestoreConcurrency = int64(12)
+var testRestoreProgress = &tabletmanagerdatapb.RestoreProgress{
+	BackupName:    "2023-10-15.094500.zone1-0000000100",
+	BytesRestored: 1024,
+	TotalBytes:    4096,
+	FilesRestored: 1,
+	TotalFiles:    4,
+	EtaSeconds:    30,
+}
 
 func (fra *fakeRPCTM) Backup(ctx context.Context, logger logutil.Logger, request *tabletmanagerdatapb.BackupRequest) error {
 	if fra.panics {
@@ -1278,11 +1459,13 @@ func tmRPCTestBackupPanic(ctx context.Context, t *testing.T, client tmclient.Tab
 	expectHandleRPCPanic(t, "Backup", true /*verbose*/, err)
 }
 
-func (fra *fakeRPCTM) RestoreFromBackup(ctx context.Context, logger logutil.Logger, request *tabletmanagerdatapb.RestoreFromBackupRequest) error {
+func (fra *fakeRPCTM) RestoreFromBackup(ctx context.Context, logger logutil.Logger, request *tabletmanagerdatapb.RestoreFromBackupRequest, progress func(*tabletmanagerdatapb.RestoreProgress)) error {
 	if fra.panics {
 		panic(fmt.Errorf("test-triggered panic"))
 	}
+	compare(fra.t, "RestoreFromBackup args", request.Concurrency, testRestoreConcurrency)
 	logStuff(logger, 10)
+	progress(testRestoreProgress)
 	testRestoreFromBackupCalled = true
 	return nil
 }
@@ -1301,7 +1484,25 @@ func tmRPCTestRestoreFromBackup(ctx context.Context, t *testing.T, client tmclie
 	if err != nil {
 		t.Fatalf("RestoreFromBackup failed: %v", err)
 	}
-	err = compareLoggedStuff(t, "RestoreFromBackup", stream, 10)
+	for i := 0; i < 10; i++ {
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("No logged value for RestoreFromBackup/%v: %v", i, err)
+		}
+		compare(t, "RestoreFromBackup log", resp.Event.GetValue(), testLogString)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("No progress for RestoreFromBackup: %v", err)
+	}
+	compare(t, "RestoreFromBackup progress", resp.Progress, testRestoreProgress)
+	_, err = stream.Recv()
+	if err == nil {
+		t.Fatalf("log channel wasn't closed for RestoreFromBackup")
+	}
+	if err == io.EOF {
+		err = nil
+	}
 	compareError(t, "RestoreFromBackup", err, true, testRestoreFromBackupCalled)
 }
 
@@ -1317,6 +1518,40 @@ func tmRPCTestRestoreFromBackupPanic(ctx context.Context, t *testing.T, client t
 	expectHandleRPCPanic(t, "RestoreFromBackup", true /*verbose*/, err)
 }
 
+var testUpgradeMysqlRoot = "/opt/mysql-8.0.36"
+var testUpgradeMysqlCalled = false
+
+func (fra *fakeRPCTM) UpgradeMysql(ctx context.Context, logger logutil.Logger, request *tabletmanagerdatapb.UpgradeMysqlRequest) error {
+	if fra.panics {
+		panic(fmt.Errorf("test-triggered panic"))
+	}
+	compare(fra.t, "UpgradeMysql args", request.MysqlRoot, testUpgradeMysqlRoot)
+	logStuff(logger, 10)
+	testUpgradeMysqlCalled = true
+	return nil
+}
+
+func tmRPCTestUpgradeMysql(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	stream, err := client.UpgradeMysql(ctx, tablet, &tabletmanagerdatapb.UpgradeMysqlRequest{MysqlRoot: testUpgradeMysqlRoot})
+	if err != nil {
+		t.Fatalf("UpgradeMysql failed: %v", err)
+	}
+	err = compareLoggedStuff(t, "UpgradeMysql", stream, 10)
+	compareError(t, "UpgradeMysql", err, true, testUpgradeMysqlCalled)
+}
+
+func tmRPCTestUpgradeMysqlPanic(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	stream, err := client.UpgradeMysql(ctx, tablet, &tabletmanagerdatapb.UpgradeMysqlRequest{MysqlRoot: testUpgradeMysqlRoot})
+	if err != nil {
+		t.Fatalf("UpgradeMysql failed: %v", err)
+	}
+	e, err := stream.Recv()
+	if err == nil {
+		t.Fatalf("Unexpected UpgradeMysql logs: %v", e)
+	}
+	expectHandleRPCPanic(t, "UpgradeMysql", true /*verbose*/, err)
+}
+
 func tmRPCTestCheckThrottler(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.CheckThrottlerRequest) {
 	_, err := client.CheckThrottler(ctx, tablet, req)
 	expectHandleRPCPanic(t, "CheckThrottler", true /*verbose*/, err)
@@ -1344,7 +1579,8 @@ func Run(t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.T
 	ctx := context.Background()
 
 	restoreFromBackupRequest := &tabletmanagerdatapb.RestoreFromBackupRequest{
-		BackupTime: protoutil.TimeToProto(time.Time{}),
+		BackupTime:  protoutil.TimeToProto(time.Time{}),
+		Concurrency: testRestoreConcurrency,
 	}
 	checkThrottlerRequest := &tabletmanagerdatapb.CheckThrottlerRequest{
 		AppName: "test",
@@ -1358,6 +1594,10 @@ func Run(t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.T
 	tmRPCTestPing(ctx, t, client, tablet)
 	tmRPCTestGetSchema(ctx, t, client, tablet)
 	tmRPCTestGetPermissions(ctx, t, client, tablet)
+	tmRPCTestGetPlanCache(ctx, t, client, tablet)
+	tmRPCTestGetTableStats(ctx, t, client, tablet)
+	tmRPCTestGetUnresolvedTransactions(ctx, t, client, tablet)
+	tmRPCTestReadTransaction(ctx, t, client, tablet)
 
 	// Various read-write methods
 	tmRPCTestSetReadOnly(ctx, t, client, tablet)
@@ -1367,6 +1607,8 @@ func Run(t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.T
 	tmRPCTestRefreshState(ctx, t, client, tablet)
 	tmRPCTestRunHealthCheck(ctx, t, client, tablet)
 	tmRPCTestReloadSchema(ctx, t, client, tablet)
+	tmRPCTestInvalidatePlanCache(ctx, t, client, tablet)
+	tmRPCTestConcludeTransaction(ctx, t, client, tablet)
 	tmRPCTestPreflightSchema(ctx, t, client, tablet)
 	tmRPCTestApplySchema(ctx, t, client, tablet)
 	tmRPCTestExecuteFetch(ctx, t, client, tablet)
@@ -1405,6 +1647,7 @@ func Run(t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.T
 	// Backup / restore related methods
 	tmRPCTestBackup(ctx, t, client, tablet)
 	tmRPCTestRestoreFromBackup(ctx, t, client, tablet, restoreFromBackupRequest)
+	tmRPCTestUpgradeMysql(ctx, t, client, tablet)
 
 	// Throttler related methods
 	tmRPCTestCheckThrottler(ctx, t, client, tablet, checkThrottlerRequest)
@@ -1418,6 +1661,10 @@ func Run(t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.T
 	tmRPCTestPingPanic(ctx, t, client, tablet)
 	tmRPCTestGetSchemaPanic(ctx, t, client, tablet)
 	tmRPCTestGetPermissionsPanic(ctx, t, client, tablet)
+	tmRPCTestGetPlanCachePanic(ctx, t, client, tablet)
+	tmRPCTestGetTableStatsPanic(ctx, t, client, tablet)
+	tmRPCTestGetUnresolvedTransactionsPanic(ctx, t, client, tablet)
+	tmRPCTestReadTransactionPanic(ctx, t, client, tablet)
 
 	// Various read-write methods
 	tmRPCTestSetReadOnlyPanic(ctx, t, client, tablet)
@@ -1427,6 +1674,8 @@ func Run(t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.T
 	tmRPCTestRefreshStatePanic(ctx, t, client, tablet)
 	tmRPCTestRunHealthCheckPanic(ctx, t, client, tablet)
 	tmRPCTestReloadSchemaPanic(ctx, t, client, tablet)
+	tmRPCTestInvalidatePlanCachePanic(ctx, t, client, tablet)
+	tmRPCTestConcludeTransactionPanic(ctx, t, client, tablet)
 	tmRPCTestPreflightSchemaPanic(ctx, t, client, tablet)
 	tmRPCTestApplySchemaPanic(ctx, t, client, tablet)
 	tmRPCTestExecuteFetchPanic(ctx, t, client, tablet)
@@ -1460,6 +1709,7 @@ func Run(t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.T
 	// Backup / restore related methods
 	tmRPCTestBackupPanic(ctx, t, client, tablet)
 	tmRPCTestRestoreFromBackupPanic(ctx, t, client, tablet, restoreFromBackupRequest)
+	tmRPCTestUpgradeMysqlPanic(ctx, t, client, tablet)
 
 	client.Close()
 }
@@ -0,0 +1,208 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package vstreamcompression batches and compresses the events of a VStream
+// between a vstreamer and its client.
+//
+// The client requests the compression in the VStreamRequest. A server that
+// supports it sends the events in the CompressedEvents of the responses, and
+// an older server ignores it and keeps sending uncompressed events, which the
+// client still accepts.
+package vstreamcompression
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/servenv"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+)
+
+// Zstd is the Zstandard compression of the events.
+const Zstd = "zstd"
+
+// maxBatchSize is the size of the events waiting to be compressed and sent,
+// above which the vstreamer waits.
+var maxBatchSize = 4 * 1024 * 1024
+
+func registerFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&maxBatchSize, "vstream-compression-max-batch-size", maxBatchSize, "Maximum size in bytes of the events batched in a compressed VStream response, when the client of the VStream requested the compression. The events are batched while the previous response is being sent.")
+}
+
+func init() {
+	servenv.OnParseFor("vtcombo", registerFlags)
+	servenv.OnParseFor("vttablet", registerFlags)
+}
+
+var (
+	compressedBytes   = stats.NewCounter("VStreamCompressedBytes", "Number of bytes of the compressed VStream responses sent")
+	uncompressedBytes = stats.NewCounter("VStreamUncompressedBytes", "Number of bytes of the events of the compressed VStream responses sent, before their compression")
+	compressedBatches = stats.NewCounter("VStreamCompressedBatches", "Number of compressed VStream responses sent")
+)
+
+// The encoder and decoder are shared: EncodeAll and DecodeAll can be called concurrently.
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+	zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+)
+
+// Supported returns true if the compression is supported.
+func Supported(compression string) bool {
+	return compression == Zstd
+}
+
+// Stats counts the bytes of the compressed events received by a client.
+type Stats struct {
+	CompressedBytes   *stats.Counter
+	UncompressedBytes *stats.Counter
+}
+
+type statsKey struct{}
+
+// NewContext returns a context where the decompressed events of the VStreams
+// are counted in the stats.
+func NewContext(ctx context.Context, st *Stats) context.Context {
+	return context.WithValue(ctx, statsKey{}, st)
+}
+
+// Decompress returns the events of a compressed response.
+func Decompress(ctx context.Context, compression string, compressed []byte) ([]*binlogdatapb.VEvent, error) {
+	if !Supported(compression) {
+		return nil, fmt.Errorf("unsupported VStream compression %q", compression)
+	}
+	b, err := zstdDecoder.DecodeAll(compressed, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp := &binlogdatapb.VStreamResponse{}
+	if err := resp.UnmarshalVT(b); err != nil {
+		return nil, err
+	}
+	if st, ok := ctx.Value(statsKey{}).(*Stats); ok && st != nil {
+		st.CompressedBytes.Add(int64(len(compressed)))
+		st.UncompressedBytes.Add(int64(len(b)))
+	}
+	return resp.Events, nil
+}
+
+// Sender batches the events of a vstreamer, and sends them compressed. The
+// events are batched while the previous batch is compressed and sent, so that
+// a slow client gets fewer and larger responses, which compress better, and a
+// fast one gets them without delay.
+type Sender struct {
+	send func(*binlogdatapb.VStreamResponse) error
+
+	mu          sync.Mutex
+	cond        *sync.Cond
+	pending     []*binlogdatapb.VEvent
+	pendingSize int
+	closed      bool
+	err         error
+	done        chan struct{}
+}
+
+// NewSender returns a sender of compressed responses. It must be closed.
+func NewSender(compression string, send func(*binlogdatapb.VStreamResponse) error) (*Sender, error) {
+	if !Supported(compression) {
+		return nil, fmt.Errorf("unsupported VStream compression %q", compression)
+	}
+	s := &Sender{
+		send: send,
+		done: make(chan struct{}),
+	}
+	s.cond = sync.NewCond(&s.mu)
+	go s.run()
+	return s, nil
+}
+
+// Send queues the events to be sent. It waits while the queued events are
+// larger than --vstream-compression-max-batch-size, and returns the error of
+// a previous send.
+func (s *Sender) Send(events []*binlogdatapb.VEvent) error {
+	size := 0
+	for _, event := range events {
+		size += event.SizeVT()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for s.err == nil && s.pendingSize > 0 && s.pendingSize+size > maxBatchSize {
+		s.cond.Wait()
+	}
+	if s.err != nil {
+		return s.err
+	}
+	s.pending = append(s.pending, events...)
+	s.pendingSize += size
+	s.cond.Broadcast()
+	return nil
+}
+
+func (s *Sender) run() {
+	defer close(s.done)
+	for {
+		s.mu.Lock()
+		for len(s.pending) == 0 && !s.closed {
+			s.cond.Wait()
+		}
+		if len(s.pending) == 0 {
+			s.mu.Unlock()
+			return
+		}
+		events := s.pending
+		s.pending = nil
+		s.pendingSize = 0
+		s.cond.Broadcast()
+		s.mu.Unlock()
+
+		if err := s.sendCompressed(events); err != nil {
+			s.mu.Lock()
+			s.err = err
+			s.cond.Broadcast()
+			s.mu.Unlock()
+			return
+		}
+	}
+}
+
+func (s *Sender) sendCompressed(events []*binlogdatapb.VEvent) error {
+	b, err := (&binlogdatapb.VStreamResponse{Events: events}).MarshalVT()
+	if err != nil {
+		return err
+	}
+	compressed := zstdEncoder.EncodeAll(b, nil)
+	compressedBatches.Add(1)
+	compressedBytes.Add(int64(len(compressed)))
+	uncompressedBytes.Add(int64(len(b)))
+	return s.send(&binlogdatapb.VStreamResponse{CompressedEvents: compressed})
+}
+
+// Close sends the queued events, and returns the first error of the sends.
+func (s *Sender) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	s.cond.Broadcast()
+	s.mu.Unlock()
+	<-s.done
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
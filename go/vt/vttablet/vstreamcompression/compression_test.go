@@ -0,0 +1,133 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vstreamcompression
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/stats"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+)
+
+func rowEvent(i int) *binlogdatapb.VEvent {
+	return &binlogdatapb.VEvent{
+		Type:      binlogdatapb.VEventType_ROW,
+		Timestamp: int64(i),
+		RowEvent: &binlogdatapb.RowEvent{
+			TableName: "customer",
+			Keyspace:  "commerce",
+			Shard:     "0",
+		},
+		Statement: fmt.Sprintf("%d %s", i, strings.Repeat("wide row ", 100)),
+	}
+}
+
+func TestSenderAndDecompress(t *testing.T) {
+	ctx := context.Background()
+	var responses []*binlogdatapb.VStreamResponse
+	sent := make(chan struct{})
+	s, err := NewSender(Zstd, func(resp *binlogdatapb.VStreamResponse) error {
+		<-sent
+		responses = append(responses, resp)
+		return nil
+	})
+	require.NoError(t, err)
+
+	// The events sent while the first response is being sent are batched.
+	var events []*binlogdatapb.VEvent
+	for i := 0; i < 10; i++ {
+		events = append(events, rowEvent(i))
+		require.NoError(t, s.Send([]*binlogdatapb.VEvent{events[i]}))
+	}
+	close(sent)
+	require.NoError(t, s.Close())
+	assert.Less(t, len(responses), 10)
+
+	st := &Stats{
+		CompressedBytes:   stats.NewCounter("", ""),
+		UncompressedBytes: stats.NewCounter("", ""),
+	}
+	ctx = NewContext(ctx, st)
+	var got []*binlogdatapb.VEvent
+	for _, resp := range responses {
+		assert.Empty(t, resp.Events)
+		decompressed, err := Decompress(ctx, Zstd, resp.CompressedEvents)
+		require.NoError(t, err)
+		got = append(got, decompressed...)
+	}
+	require.Len(t, got, len(events))
+	for i := range events {
+		assert.True(t, proto.Equal(events[i], got[i]), "event %d", i)
+	}
+	assert.Less(t, st.CompressedBytes.Get(), st.UncompressedBytes.Get()/5)
+
+	_, err = Decompress(ctx, "snappy", responses[0].CompressedEvents)
+	assert.EqualError(t, err, `unsupported VStream compression "snappy"`)
+	_, err = NewSender("snappy", nil)
+	assert.EqualError(t, err, `unsupported VStream compression "snappy"`)
+}
+
+func TestSenderError(t *testing.T) {
+	s, err := NewSender(Zstd, func(resp *binlogdatapb.VStreamResponse) error {
+		return errors.New("stream closed")
+	})
+	require.NoError(t, err)
+	require.NoError(t, s.Send([]*binlogdatapb.VEvent{rowEvent(1)}))
+	// The error of the send is returned by the next sends, once it happened.
+	assert.Eventually(t, func() bool {
+		return s.Send([]*binlogdatapb.VEvent{rowEvent(2)}) != nil
+	}, 10*time.Second, time.Millisecond)
+	assert.EqualError(t, s.Close(), "stream closed")
+}
+
+func TestSenderMaxBatchSize(t *testing.T) {
+	defer func(size int) {
+		maxBatchSize = size
+	}(maxBatchSize)
+	maxBatchSize = rowEvent(1).SizeVT() + 1
+
+	sent := make(chan struct{})
+	count := 0
+	s, err := NewSender(Zstd, func(resp *binlogdatapb.VStreamResponse) error {
+		<-sent
+		count++
+		return nil
+	})
+	require.NoError(t, err)
+	go func() {
+		for i := 0; i < 5; i++ {
+			sent <- struct{}{}
+		}
+	}()
+	// A batch never holds more than one event, but an event larger than the
+	// max batch size is sent anyway.
+	for i := 0; i < 5; i++ {
+		require.NoError(t, s.Send([]*binlogdatapb.VEvent{rowEvent(i)}))
+	}
+	require.NoError(t, s.Close())
+	assert.Equal(t, 5, count)
+}
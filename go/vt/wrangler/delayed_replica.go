@@ -0,0 +1,174 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/prototext"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// CreateDelayedReplica creates and starts a DelayedReplica workflow, which
+// copies tables of the source keyspace to the target keyspace, or all of them
+// if tables is empty, and then applies the changes of the source keyspace with
+// the delay. The target keyspace is a recovery tier for mistaken writes: the
+// tables can be read there as they were before the mistake, until the
+// mistake is applied.
+func (wr *Wrangler) CreateDelayedReplica(ctx context.Context, workflow, sourceKeyspace, targetKeyspace string, tables []string,
+	delay time.Duration, cell, tabletTypesStr string) error {
+	if delay < time.Second {
+		return fmt.Errorf("the delay of a DelayedReplica workflow must be at least one second: %v", delay)
+	}
+	if sourceKeyspace == targetKeyspace {
+		return fmt.Errorf("the source and target keyspaces of a DelayedReplica workflow must be different: %s", sourceKeyspace)
+	}
+	ksTables, err := wr.getKeyspaceTables(ctx, sourceKeyspace, wr.ts)
+	if err != nil {
+		return err
+	}
+	if len(tables) > 0 {
+		if err := wr.validateSourceTablesExist(ctx, sourceKeyspace, ksTables, tables); err != nil {
+			return err
+		}
+	} else {
+		for _, table := range ksTables {
+			if shouldInclude(table, nil) {
+				tables = append(tables, table)
+			}
+		}
+	}
+	if len(tables) == 0 {
+		return fmt.Errorf("no tables to replicate")
+	}
+	tabletTypes, inorder, err := discovery.ParseTabletTypesAndOrder(tabletTypesStr)
+	if err != nil {
+		return err
+	}
+	tsp := tabletmanagerdatapb.TabletSelectionPreference_ANY
+	if inorder {
+		tsp = tabletmanagerdatapb.TabletSelectionPreference_INORDER
+	}
+	ms := &vtctldatapb.MaterializeSettings{
+		Workflow:                  workflow,
+		MaterializationIntent:     vtctldatapb.MaterializationIntent_DELAYEDREPLICA,
+		SourceKeyspace:            sourceKeyspace,
+		TargetKeyspace:            targetKeyspace,
+		Cell:                      cell,
+		TabletTypes:               topoproto.MakeStringTypeCSV(tabletTypes),
+		TabletSelectionPreference: tsp,
+		DelaySeconds:              int64(delay / time.Second),
+	}
+	for _, table := range tables {
+		buf := sqlparser.NewTrackedBuffer(nil)
+		buf.Myprintf("select * from %v", sqlparser.NewIdentifierCS(table))
+		ms.TableSettings = append(ms.TableSettings, &vtctldatapb.TableMaterializeSettings{
+			TargetTable:      table,
+			SourceExpression: buf.String(),
+			CreateDdl:        createDDLAsCopy,
+		})
+	}
+	return wr.Materialize(ctx, ms)
+}
+
+// FastForwardDelayedReplica makes the streams of a DelayedReplica workflow
+// apply the events of the source without delay up to the position, or up to
+// the timestamp if position is empty. The events after them are delayed again.
+// The streams are restarted.
+func (wr *Wrangler) FastForwardDelayedReplica(ctx context.Context, targetKeyspace, workflow, position string, timestamp time.Time) error {
+	if (position == "") == timestamp.IsZero() {
+		return fmt.Errorf("exactly one of a position or a timestamp must be provided to fast-forward workflow %s.%s", targetKeyspace, workflow)
+	}
+	if position != "" {
+		if _, err := binlogplayer.DecodePosition(position); err != nil {
+			return vterrors.Wrapf(err, "invalid position %s", position)
+		}
+	}
+	targetShards, err := wr.ts.GetServingShards(ctx, targetKeyspace)
+	if err != nil {
+		return err
+	}
+	var updated int
+	var mu sync.Mutex
+	err = forAllShards(targetShards, func(targetShard *topo.ShardInfo) error {
+		targetPrimary, err := wr.ts.GetTablet(ctx, targetShard.PrimaryAlias)
+		if err != nil {
+			return vterrors.Wrapf(err, "GetTablet(%v) failed", targetShard.PrimaryAlias)
+		}
+		p3qr, err := wr.tmc.VReplicationExec(ctx, targetPrimary.Tablet, fmt.Sprintf("select id, source, workflow_type from _vt.vreplication where workflow=%s and db_name=%s",
+			encodeString(workflow), encodeString(targetPrimary.DbName())))
+		if err != nil {
+			return err
+		}
+		qr := sqltypes.Proto3ToResult(p3qr)
+		for _, row := range qr.Rows {
+			id, err := row[0].ToCastInt64()
+			if err != nil {
+				return err
+			}
+			workflowType, err := row[2].ToInt64()
+			if err != nil {
+				return err
+			}
+			if binlogdatapb.VReplicationWorkflowType(workflowType) != binlogdatapb.VReplicationWorkflowType_DelayedReplica {
+				return fmt.Errorf("workflow %s.%s is not a DelayedReplica workflow: %s", targetKeyspace, workflow, binlogdatapb.VReplicationWorkflowType(workflowType))
+			}
+			sourceBytes, err := row[1].ToBytes()
+			if err != nil {
+				return err
+			}
+			bls := &binlogdatapb.BinlogSource{}
+			if err := prototext.Unmarshal(sourceBytes, bls); err != nil {
+				return err
+			}
+			bls.FastForwardPosition = position
+			bls.FastForwardTimestamp = 0
+			if !timestamp.IsZero() {
+				bls.FastForwardTimestamp = timestamp.Unix()
+			}
+			query := fmt.Sprintf("update _vt.vreplication set source=%s where id=%d", encodeString(bls.String()), id)
+			if _, err := wr.tmc.VReplicationExec(ctx, targetPrimary.Tablet, query); err != nil {
+				return err
+			}
+			mu.Lock()
+			updated++
+			mu.Unlock()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return fmt.Errorf("no streams found for workflow %s.%s", targetKeyspace, workflow)
+	}
+	return nil
+}
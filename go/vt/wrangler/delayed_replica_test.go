@@ -0,0 +1,112 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+func TestCreateDelayedReplica(t *testing.T) {
+	ms := &vtctldatapb.MaterializeSettings{
+		Workflow:       "workflow",
+		SourceKeyspace: "sourceks",
+		TargetKeyspace: "targetks",
+		TableSettings: []*vtctldatapb.TableMaterializeSettings{{
+			TargetTable:      "t1",
+			SourceExpression: "select * from t1",
+		}},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env := newTestMaterializerEnv(t, ctx, ms, []string{"0"}, []string{"0"})
+	defer env.close()
+
+	env.tmc.expectVRQuery(200, mzSelectFrozenQuery, &sqltypes.Result{})
+	env.tmc.expectVRQuery(
+		200,
+		insertPrefix+
+			`\('workflow', 'keyspace:\\"sourceks\\" shard:\\"0\\" filter:{rules:{match:\\"t1\\" filter:\\"select \* from t1\\"}} delay_seconds:3600', `+
+			`'', [0-9]*, [0-9]*, 'cell', 'in_order:replica,primary', [0-9]*, 0, 'Stopped', 'vt_targetks', 6, 0, false\)`+eol,
+		&sqltypes.Result{},
+	)
+	env.tmc.expectVRQuery(200, mzUpdateQuery, &sqltypes.Result{})
+
+	err := env.wr.CreateDelayedReplica(ctx, "workflow", "sourceks", "targetks", nil, time.Hour, "cell", "in_order:REPLICA,PRIMARY")
+	require.NoError(t, err)
+	env.tmc.verifyQueries(t)
+
+	err = env.wr.CreateDelayedReplica(ctx, "workflow", "sourceks", "targetks", []string{"t2"}, time.Hour, "", "")
+	assert.EqualError(t, err, "table(s) not found in source keyspace sourceks: t2")
+	err = env.wr.CreateDelayedReplica(ctx, "workflow", "sourceks", "targetks", nil, time.Millisecond, "", "")
+	assert.EqualError(t, err, "the delay of a DelayedReplica workflow must be at least one second: 1ms")
+}
+
+func TestFastForwardDelayedReplica(t *testing.T) {
+	ms := &vtctldatapb.MaterializeSettings{
+		SourceKeyspace: "sourceks",
+		TargetKeyspace: "targetks",
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env := newTestMaterializerEnv(t, ctx, ms, []string{"0"}, []string{"-80", "80-"})
+	defer env.close()
+
+	const selectQuery = "select id, source, workflow_type from _vt.vreplication where workflow='workflow' and db_name='vt_targetks'"
+	fields := sqltypes.MakeTestFields("id|source|workflow_type", "int64|varbinary|int64")
+	source := `keyspace:"sourceks" shard:"0" filter:{rules:{match:"t1" filter:"select * from t1"}} delay_seconds:3600`
+	streams := sqltypes.MakeTestResult(fields, "1|"+source+"|6")
+	env.tmc.expectVRQuery(200, selectQuery, streams)
+	env.tmc.expectVRQuery(200, `/update _vt.vreplication set source='.*delay_seconds:3600 fast_forward_timestamp:1690000000' where id=1`, &sqltypes.Result{})
+	env.tmc.expectVRQuery(210, selectQuery, streams)
+	env.tmc.expectVRQuery(210, `/update _vt.vreplication set source='.*delay_seconds:3600 fast_forward_timestamp:1690000000' where id=1`, &sqltypes.Result{})
+
+	err := env.wr.FastForwardDelayedReplica(ctx, "targetks", "workflow", "", time.Unix(1690000000, 0))
+	require.NoError(t, err)
+	env.tmc.verifyQueries(t)
+
+	// The fast-forward position replaces the fast-forward timestamp.
+	position := "MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-615"
+	forwarded := sqltypes.MakeTestResult(fields, "1|"+source+" fast_forward_timestamp:1690000000|6")
+	env.tmc.expectVRQuery(200, selectQuery, forwarded)
+	env.tmc.expectVRQuery(200, `/update _vt.vreplication set source='.*delay_seconds:3600 fast_forward_position:\\"MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-615\\"' where id=1`, &sqltypes.Result{})
+	env.tmc.expectVRQuery(210, selectQuery, forwarded)
+	env.tmc.expectVRQuery(210, `/update _vt.vreplication set source='.*delay_seconds:3600 fast_forward_position:\\"MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-615\\"' where id=1`, &sqltypes.Result{})
+
+	err = env.wr.FastForwardDelayedReplica(ctx, "targetks", "workflow", position, time.Time{})
+	require.NoError(t, err)
+	env.tmc.verifyQueries(t)
+
+	moveTables := sqltypes.MakeTestResult(fields, "1|"+source+"|1")
+	env.tmc.expectVRQuery(200, selectQuery, moveTables)
+	env.tmc.expectVRQuery(210, selectQuery, moveTables)
+	err = env.wr.FastForwardDelayedReplica(ctx, "targetks", "workflow", position, time.Time{})
+	assert.ErrorContains(t, err, "workflow targetks.workflow is not a DelayedReplica workflow: MoveTables")
+
+	err = env.wr.FastForwardDelayedReplica(ctx, "targetks", "workflow", position, time.Unix(1690000000, 0))
+	assert.EqualError(t, err, "exactly one of a position or a timestamp must be provided to fast-forward workflow targetks.workflow")
+	err = env.wr.FastForwardDelayedReplica(ctx, "targetks", "workflow", "MySQL56/bad", time.Time{})
+	assert.ErrorContains(t, err, "invalid position MySQL56/bad")
+}
@@ -23,8 +23,9 @@ import (
 	"vitess.io/vitess/go/vt/proto/topodata"
 )
 
-// MountExternalVitessCluster adds a topo record for cluster with specified parameters so that it is available to a Migrate command
-func (wr *Wrangler) MountExternalVitessCluster(ctx context.Context, clusterName, topoType, topoServer, topoRoot string) error {
+// MountExternalVitessCluster adds a topo record for cluster with specified parameters so that it is available to a Migrate command.
+// If vtgateAddress is set, the workflows whose source is the cluster stream the binlog events through that vtgate.
+func (wr *Wrangler) MountExternalVitessCluster(ctx context.Context, clusterName, topoType, topoServer, topoRoot, vtgateAddress string) error {
 	vci, err := wr.TopoServer().GetExternalVitessCluster(ctx, clusterName)
 	if err != nil {
 		return err
@@ -38,6 +39,7 @@ func (wr *Wrangler) MountExternalVitessCluster(ctx context.Context, clusterName,
 			Server:   topoServer,
 			Root:     topoRoot,
 		},
+		VtgateAddress: vtgateAddress,
 	}
 	return wr.TopoServer().CreateExternalVitessCluster(ctx, clusterName, vc)
 }
@@ -19,7 +19,7 @@ func TestVitessCluster(t *testing.T) {
 	ts := memorytopo.NewServer(ctx, "zone1")
 	tmc := newTestWranglerTMClient()
 	wr := New(logutil.NewConsoleLogger(), ts, tmc)
-	name, topoType, topoServer, topoRoot := "c1", "x", "y", "z"
+	name, topoType, topoServer, topoRoot, vtgateAddress := "c1", "x", "y", "z", "w"
 
 	t.Run("Zero clusters to start", func(t *testing.T) {
 		clusters, err := ts.GetExternalVitessClusters(ctx)
@@ -27,7 +27,7 @@ func TestVitessCluster(t *testing.T) {
 		require.Equal(t, 0, len(clusters))
 	})
 	t.Run("Mount first cluster", func(t *testing.T) {
-		err := wr.MountExternalVitessCluster(ctx, name, topoType, topoServer, topoRoot)
+		err := wr.MountExternalVitessCluster(ctx, name, topoType, topoServer, topoRoot, vtgateAddress)
 		require.NoError(t, err)
 		vci, err := ts.GetExternalVitessCluster(ctx, name)
 		require.NoError(t, err)
@@ -38,13 +38,14 @@ func TestVitessCluster(t *testing.T) {
 				Server:   topoServer,
 				Root:     topoRoot,
 			},
+			VtgateAddress: vtgateAddress,
 		}
 		utils.MustMatch(t, expectedVc, vci.ExternalVitessCluster)
 	})
 
 	t.Run("Mount second cluster", func(t *testing.T) {
 		name2 := "c2"
-		err := wr.MountExternalVitessCluster(ctx, name2, topoType, topoServer, topoRoot)
+		err := wr.MountExternalVitessCluster(ctx, name2, topoType, topoServer, topoRoot, "")
 		require.NoError(t, err)
 	})
 
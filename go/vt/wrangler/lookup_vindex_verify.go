@@ -0,0 +1,296 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+const (
+	// DefaultLookupVindexVerifySampleSize is the default number of rows
+	// sampled from each source shard to verify a lookup vindex.
+	DefaultLookupVindexVerifySampleSize = 1000
+
+	// maxReportedLookupMismatches is the number of mismatches returned by the
+	// verification of a lookup vindex, after which they are only counted.
+	maxReportedLookupMismatches = 100
+)
+
+// LookupVindexVerification is the result of the verification of a backfilled
+// lookup vindex.
+type LookupVindexVerification struct {
+	// Sampled is the number of source rows that were verified.
+	Sampled int
+	// MismatchCount is the number of sampled rows whose lookup row is
+	// missing or wrong.
+	MismatchCount int
+	// Mismatches describes the first mismatches.
+	Mismatches []string
+}
+
+// String returns a report of the verification.
+func (v *LookupVindexVerification) String() string {
+	if v.MismatchCount == 0 {
+		return fmt.Sprintf("%d sampled rows verified, no mismatch found", v.Sampled)
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d sampled rows verified, %d mismatches found:", v.Sampled, v.MismatchCount)
+	for _, mismatch := range v.Mismatches {
+		fmt.Fprintf(&sb, "\n  %s", mismatch)
+	}
+	if v.MismatchCount > len(v.Mismatches) {
+		fmt.Fprintf(&sb, "\n  ... and %d more", v.MismatchCount-len(v.Mismatches))
+	}
+	return sb.String()
+}
+
+func (v *LookupVindexVerification) addMismatch(format string, args ...any) {
+	v.MismatchCount++
+	if len(v.Mismatches) < maxReportedLookupMismatches {
+		v.Mismatches = append(v.Mismatches, fmt.Sprintf(format, args...))
+	}
+}
+
+// VerifyLookupVindex verifies a backfilled lookup vindex by sampling up to
+// sampleSize rows of its table on each source shard, and checking that the
+// lookup table has their rows: the keyspace_id of the lookup rows must be
+// in the key range of the source shard, and the other to columns must match.
+func (wr *Wrangler) VerifyLookupVindex(ctx context.Context, qualifiedVindexName string, sampleSize int) (*LookupVindexVerification, error) {
+	if sampleSize <= 0 {
+		return nil, fmt.Errorf("sample size must be positive: %d", sampleSize)
+	}
+	splits := strings.Split(qualifiedVindexName, ".")
+	if len(splits) != 2 {
+		return nil, fmt.Errorf("vindex name should be of the form keyspace.vindex: %s", qualifiedVindexName)
+	}
+	sourceKeyspace, vindexName := splits[0], splits[1]
+	sourceVSchema, err := wr.ts.GetVSchema(ctx, sourceKeyspace)
+	if err != nil {
+		return nil, err
+	}
+	vindex := sourceVSchema.Vindexes[vindexName]
+	if vindex == nil {
+		return nil, fmt.Errorf("vindex %s not found in vschema", qualifiedVindexName)
+	}
+	targetKeyspace, targetTableName, err := sqlparser.ParseTable(vindex.Params["table"])
+	if err != nil || targetKeyspace == "" {
+		return nil, fmt.Errorf("vindex table name must be in the form <keyspace>.<table>. Got: %v", vindex.Params["table"])
+	}
+	sourceTableName, sourceColumns, err := lookupVindexSourceColumns(sourceVSchema, vindexName, vindex)
+	if err != nil {
+		return nil, err
+	}
+	fromColumns := strings.Split(vindex.Params["from"], ",")
+	if len(fromColumns) != len(sourceColumns) {
+		return nil, fmt.Errorf("length of table columns differs from length of vindex columns: %v vs %v", sourceColumns, fromColumns)
+	}
+	toColumn := vindex.Params["to"]
+	// The to column of these vindexes is the keyspace_id of the source rows,
+	// as backfilled by CreateLookupVindex.
+	isKeyspaceID := strings.EqualFold(toColumn, "keyspace_id") || strings.EqualFold(vindex.Type, "consistent_lookup_unique") || strings.EqualFold(vindex.Type, "consistent_lookup")
+
+	sourceShards, err := wr.ts.GetServingShards(ctx, sourceKeyspace)
+	if err != nil {
+		return nil, err
+	}
+	targetShards, err := wr.ts.GetServingShards(ctx, targetKeyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	verification := &LookupVindexVerification{}
+	var mu sync.Mutex
+	err = forAllShards(sourceShards, func(sourceShard *topo.ShardInfo) error {
+		buf := sqlparser.NewTrackedBuffer(nil)
+		buf.Myprintf("select ")
+		for i, col := range sourceColumns {
+			buf.Myprintf("%v as %v, ", sqlparser.NewIdentifierCI(col), sqlparser.NewIdentifierCI(fromColumns[i]))
+		}
+		if isKeyspaceID {
+			// Any column will do, only the source shard of the rows is verified.
+			buf.Myprintf("1 ")
+		} else {
+			buf.Myprintf("%v ", sqlparser.NewIdentifierCI(toColumn))
+		}
+		buf.Myprintf("from %v order by rand() limit %d", sqlparser.NewIdentifierCS(sourceTableName), sampleSize)
+		sampled, err := wr.queryShardPrimary(ctx, sourceShard, buf.String(), sampleSize)
+		if err != nil {
+			return err
+		}
+		if len(sampled.Rows) == 0 {
+			return nil
+		}
+
+		lookupRows := make(map[string]sqltypes.Value)
+		query := lookupVindexTableQuery(targetTableName, fromColumns, toColumn, sampled.Rows)
+		err = forAllShards(targetShards, func(targetShard *topo.ShardInfo) error {
+			qr, err := wr.queryShardPrimary(ctx, targetShard, query, len(sampled.Rows))
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, row := range qr.Rows {
+				lookupRows[lookupVindexRowKey(row[:len(fromColumns)])] = row[len(fromColumns)]
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		verification.Sampled += len(sampled.Rows)
+		for _, row := range sampled.Rows {
+			rowKey := lookupVindexRowKey(row[:len(fromColumns)])
+			to, ok := lookupRows[rowKey]
+			switch {
+			case !ok:
+				verification.addMismatch("row (%s) of table %s in shard %s/%s is missing from lookup table %s.%s",
+					rowKey, sourceTableName, sourceKeyspace, sourceShard.ShardName(), targetKeyspace, targetTableName)
+			case isKeyspaceID:
+				ksid, err := to.ToBytes()
+				if err != nil || !key.KeyRangeContains(sourceShard.KeyRange, ksid) {
+					verification.addMismatch("row (%s) of table %s in shard %s/%s has keyspace_id %x in lookup table %s.%s, which is not in the key range of the shard",
+						rowKey, sourceTableName, sourceKeyspace, sourceShard.ShardName(), ksid, targetKeyspace, targetTableName)
+				}
+			case to.ToString() != row[len(fromColumns)].ToString():
+				verification.addMismatch("row (%s) of table %s in shard %s/%s has %s=%s in lookup table %s.%s, want %s",
+					rowKey, sourceTableName, sourceKeyspace, sourceShard.ShardName(), toColumn, to.ToString(), targetKeyspace, targetTableName, row[len(fromColumns)].ToString())
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(verification.Mismatches)
+	return verification, nil
+}
+
+// lookupVindexSourceColumns returns the owner table of the lookup vindex, or
+// the first table using it, and the columns of its column vindex.
+func lookupVindexSourceColumns(sourceVSchema *vschemapb.Keyspace, vindexName string, vindex *vschemapb.Vindex) (string, []string, error) {
+	tableNames := make([]string, 0, len(sourceVSchema.Tables))
+	for tableName := range sourceVSchema.Tables {
+		tableNames = append(tableNames, tableName)
+	}
+	sort.Strings(tableNames)
+	if vindex.Owner != "" {
+		tableNames = []string{vindex.Owner}
+	}
+	for _, tableName := range tableNames {
+		table := sourceVSchema.Tables[tableName]
+		if table == nil {
+			continue
+		}
+		for _, colVindex := range table.ColumnVindexes {
+			if colVindex.Name != vindexName {
+				continue
+			}
+			if len(colVindex.Columns) != 0 {
+				return tableName, colVindex.Columns, nil
+			}
+			return tableName, []string{colVindex.Column}, nil
+		}
+	}
+	return "", nil, fmt.Errorf("no table of the vschema uses vindex %s", vindexName)
+}
+
+// lookupVindexTableQuery returns the query selecting the lookup rows of the
+// from values of the sampled rows.
+func lookupVindexTableQuery(tableName string, fromColumns []string, toColumn string, rows [][]sqltypes.Value) string {
+	var sb strings.Builder
+	sb.WriteString("select ")
+	escaped := make([]string, 0, len(fromColumns))
+	for _, col := range fromColumns {
+		escaped = append(escaped, sqlescape.EscapeID(col))
+	}
+	sb.WriteString(strings.Join(escaped, ", "))
+	fmt.Fprintf(&sb, ", %s from %s where (%s) in (", sqlescape.EscapeID(toColumn), sqlescape.EscapeID(tableName), strings.Join(escaped, ", "))
+	for i, row := range rows {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteByte('(')
+		for j, value := range row[:len(fromColumns)] {
+			if j > 0 {
+				sb.WriteString(", ")
+			}
+			value.EncodeSQLStringBuilder(&sb)
+		}
+		sb.WriteByte(')')
+	}
+	sb.WriteByte(')')
+	return sb.String()
+}
+
+func lookupVindexRowKey(values []sqltypes.Value) string {
+	strs := make([]string, 0, len(values))
+	for _, value := range values {
+		strs = append(strs, value.ToString())
+	}
+	return strings.Join(strs, ", ")
+}
+
+func (wr *Wrangler) queryShardPrimary(ctx context.Context, shard *topo.ShardInfo, query string, maxRows int) (*sqltypes.Result, error) {
+	primary, err := wr.ts.GetTablet(ctx, shard.PrimaryAlias)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "GetTablet(%v) failed", shard.PrimaryAlias)
+	}
+	qr, err := wr.tmc.ExecuteFetchAsApp(ctx, primary.Tablet, false, &tabletmanagerdatapb.ExecuteFetchAsAppRequest{
+		Query:   []byte(query),
+		MaxRows: uint64(maxRows),
+	})
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "ExecuteFetchAsApp(%v, %s)", primary.Alias, query)
+	}
+	return sqltypes.Proto3ToResult(qr), nil
+}
+
+// forAllShards runs f for all the shards in parallel.
+func forAllShards(shards []*topo.ShardInfo, f func(*topo.ShardInfo) error) error {
+	var wg sync.WaitGroup
+	allErrors := &concurrency.AllErrorRecorder{}
+	for _, shard := range shards {
+		wg.Add(1)
+		go func(shard *topo.ShardInfo) {
+			defer wg.Done()
+			if err := f(shard); err != nil {
+				allErrors.RecordError(err)
+			}
+		}(shard)
+	}
+	wg.Wait()
+	return allErrors.AggrError(vterrors.Aggregate)
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+func newLookupVindexVerifyEnv(t *testing.T, ctx context.Context) *testMaterializerEnv {
+	ms := &vtctldatapb.MaterializeSettings{
+		SourceKeyspace: "sourceks",
+		TargetKeyspace: "targetks",
+	}
+	env := newTestMaterializerEnv(t, ctx, ms, []string{"-80", "80-"}, []string{"0"})
+	sourceVSchema := &vschemapb.Keyspace{
+		Sharded: true,
+		Vindexes: map[string]*vschemapb.Vindex{
+			"hash": {
+				Type: "hash",
+			},
+			"owned": {
+				Type: "consistent_lookup_unique",
+				Params: map[string]string{
+					"table":      "targetks.lkp",
+					"from":       "c1",
+					"to":         "c2",
+					"write_only": "true",
+				},
+				Owner: "t1",
+			},
+			"unowned": {
+				Type: "lookup_unique",
+				Params: map[string]string{
+					"table":      "targetks.lkp2",
+					"from":       "c1",
+					"to":         "name",
+					"write_only": "true",
+				},
+			},
+		},
+		Tables: map[string]*vschemapb.Table{
+			"t1": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{
+					Name:   "hash",
+					Column: "col1",
+				}, {
+					Name:   "owned",
+					Column: "col2",
+				}},
+			},
+			"t2": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{
+					Name:   "hash",
+					Column: "col1",
+				}, {
+					Name:   "unowned",
+					Column: "col2",
+				}},
+			},
+		},
+	}
+	require.NoError(t, env.topoServ.SaveVSchema(ctx, ms.SourceKeyspace, sourceVSchema))
+	return env
+}
+
+func TestVerifyLookupVindex(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env := newLookupVindexVerifyEnv(t, ctx)
+	defer env.close()
+
+	sourceFields := sqltypes.MakeTestFields("c1|1", "int64|int64")
+	lookupFields := sqltypes.MakeTestFields("c1|c2", "int64|varbinary")
+	env.tmc.expectVRQuery(100, "select col2 as c1, 1 from t1 order by rand() limit 10", sqltypes.MakeTestResult(sourceFields, "1|1", "2|1"))
+	env.tmc.expectVRQuery(110, "select col2 as c1, 1 from t1 order by rand() limit 10", sqltypes.MakeTestResult(sourceFields, "3|1"))
+	// The source shards are verified in parallel, so that the order of the
+	// queries of the lookup table is not known.
+	lookupResult := sqltypes.MakeTestResult(lookupFields, "1|\x16k@\xb4J\xbaK\xd6", "2|\x90")
+	env.tmc.expectVRQuery(200, "/select `c1`, `c2` from `lkp` where \\(`c1`\\) in \\(\\((1|3)\\)", lookupResult)
+	env.tmc.expectVRQuery(200, "/select `c1`, `c2` from `lkp` where \\(`c1`\\) in \\(\\((1|3)\\)", lookupResult)
+
+	verification, err := env.wr.VerifyLookupVindex(ctx, "sourceks.owned", 10)
+	require.NoError(t, err)
+	assert.Equal(t, 3, verification.Sampled)
+	assert.Equal(t, 2, verification.MismatchCount)
+	assert.Equal(t, []string{
+		"row (2) of table t1 in shard sourceks/-80 has keyspace_id 90 in lookup table targetks.lkp, which is not in the key range of the shard",
+		"row (3) of table t1 in shard sourceks/80- is missing from lookup table targetks.lkp",
+	}, verification.Mismatches)
+	env.tmc.verifyQueries(t)
+
+	// The to column of the other lookup vindexes is compared.
+	sourceFields = sqltypes.MakeTestFields("c1|name", "int64|varchar")
+	lookupFields = sqltypes.MakeTestFields("c1|name", "int64|varchar")
+	env.tmc.expectVRQuery(100, "select col2 as c1, `name` from t2 order by rand() limit 10", sqltypes.MakeTestResult(sourceFields, "1|a"))
+	env.tmc.expectVRQuery(110, "select col2 as c1, `name` from t2 order by rand() limit 10", sqltypes.MakeTestResult(sourceFields, "2|b"))
+	env.tmc.expectVRQuery(200, "/select `c1`, `name` from `lkp2` where", sqltypes.MakeTestResult(lookupFields, "1|a", "2|c"))
+	env.tmc.expectVRQuery(200, "/select `c1`, `name` from `lkp2` where", sqltypes.MakeTestResult(lookupFields, "1|a", "2|c"))
+
+	verification, err = env.wr.VerifyLookupVindex(ctx, "sourceks.unowned", 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, verification.Sampled)
+	assert.Equal(t, []string{
+		"row (2) of table t2 in shard sourceks/80- has name=c in lookup table targetks.lkp2, want b",
+	}, verification.Mismatches)
+	assert.Equal(t, "2 sampled rows verified, 1 mismatches found:\n  row (2) of table t2 in shard sourceks/80- has name=c in lookup table targetks.lkp2, want b", verification.String())
+	env.tmc.verifyQueries(t)
+
+	_, err = env.wr.VerifyLookupVindex(ctx, "sourceks.hash", 0)
+	assert.EqualError(t, err, "sample size must be positive: 0")
+	_, err = env.wr.VerifyLookupVindex(ctx, "sourceks.absent", 10)
+	assert.EqualError(t, err, "vindex sourceks.absent not found in vschema")
+}
+
+func TestExternalizeVindexVerification(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env := newLookupVindexVerifyEnv(t, ctx)
+	defer env.close()
+
+	fields := sqltypes.MakeTestFields(
+		"id|state|message|source",
+		"int64|varbinary|varbinary|blob",
+	)
+	validationQuery := "select id, state, message, source from _vt.vreplication where workflow='lkp2_vdx' and db_name='vt_targetks'"
+	running := sqltypes.MakeTestResult(fields, `1|Running|msg|keyspace:"sourceks" shard:"-80"`, `2|Running|msg|keyspace:"sourceks" shard:"80-"`)
+	sourceFields := sqltypes.MakeTestFields("c1|name", "int64|varchar")
+	lookupFields := sqltypes.MakeTestFields("c1|name", "int64|varchar")
+	env.tmc.expectVRQuery(200, validationQuery, running)
+	env.tmc.expectVRQuery(100, "select col2 as c1, `name` from t2 order by rand() limit 5", sqltypes.MakeTestResult(sourceFields, "1|a"))
+	env.tmc.expectVRQuery(110, "select col2 as c1, `name` from t2 order by rand() limit 5", sqltypes.MakeTestResult(sourceFields))
+	env.tmc.expectVRQuery(200, "select `c1`, `name` from `lkp2` where (`c1`) in ((1))", sqltypes.MakeTestResult(lookupFields))
+
+	err := env.wr.ExternalizeVindex(ctx, "sourceks.unowned", 5)
+	assert.EqualError(t, err, "vindex sourceks.unowned cannot be externalized, its verification failed: 1 sampled rows verified, 1 mismatches found:\n  row (1) of table t2 in shard sourceks/-80 is missing from lookup table targetks.lkp2")
+	vschema, err := env.topoServ.GetVSchema(ctx, "sourceks")
+	require.NoError(t, err)
+	assert.Equal(t, "true", vschema.Vindexes["unowned"].Params["write_only"])
+
+	env.tmc.expectVRQuery(200, validationQuery, running)
+	env.tmc.expectVRQuery(100, "select col2 as c1, `name` from t2 order by rand() limit 5", sqltypes.MakeTestResult(sourceFields, "1|a"))
+	env.tmc.expectVRQuery(110, "select col2 as c1, `name` from t2 order by rand() limit 5", sqltypes.MakeTestResult(sourceFields))
+	env.tmc.expectVRQuery(200, "select `c1`, `name` from `lkp2` where (`c1`) in ((1))", sqltypes.MakeTestResult(lookupFields, "1|a"))
+
+	require.NoError(t, env.wr.ExternalizeVindex(ctx, "sourceks.unowned", 5))
+	vschema, err = env.topoServ.GetVSchema(ctx, "sourceks")
+	require.NoError(t, err)
+	assert.NotContains(t, vschema.Vindexes["unowned"].Params, "write_only")
+	env.tmc.verifyQueries(t)
+}
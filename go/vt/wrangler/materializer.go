@@ -54,6 +54,7 @@ import (
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
 type materializer struct {
@@ -71,6 +72,10 @@ const (
 	createDDLAsCopyDropForeignKeys = "copy:drop_foreign_keys"
 )
 
+const (
+	sqlCreateSequenceTable = "create table if not exists %a (id int, next_id bigint, cache bigint, primary key(id)) comment 'vitess_sequence'"
+)
+
 // addTablesToVSchema adds tables to an (unsharded) vschema if they are not already defined.
 // If copyVSchema is true then we copy over the vschema table definitions from the source,
 // otherwise we create empty ones.
@@ -106,6 +111,150 @@ func (wr *Wrangler) addTablesToVSchema(ctx context.Context, sourceKeyspace strin
 	return nil
 }
 
+// createAutoSequences creates, in the unsharded source keyspace, a backing sequence
+// table for each moved table with an auto_increment column that is not generated by
+// a sequence in the sharded target keyspace yet. The sequence tables are initialized
+// past the current max value of their column and are used for the auto_increment
+// columns of the tables in the target vschema, which is updated in place.
+func (wr *Wrangler) createAutoSequences(ctx context.Context, sourceKeyspace string, targetVSchema *vschemapb.Keyspace, tables []string) error {
+	if !targetVSchema.Sharded {
+		wr.Logger().Infof("The target keyspace is not sharded, its tables do not need sequences")
+		return nil
+	}
+	srcVSchema, err := wr.ts.GetVSchema(ctx, sourceKeyspace)
+	if err != nil {
+		return vterrors.Wrapf(err, "failed to get vschema for source keyspace %s", sourceKeyspace)
+	}
+	if srcVSchema.Sharded {
+		return fmt.Errorf("the sequence tables must be created in an unsharded keyspace and the %s source keyspace is sharded", sourceKeyspace)
+	}
+	if srcVSchema.Tables == nil {
+		srcVSchema.Tables = make(map[string]*vschemapb.Table)
+	}
+	sourceShard, err := wr.ts.GetOnlyShard(ctx, sourceKeyspace)
+	if err != nil {
+		return err
+	}
+	if sourceShard.PrimaryAlias == nil {
+		return fmt.Errorf("source shard has no primary: %v", sourceShard.ShardName())
+	}
+	primary, err := wr.ts.GetTablet(ctx, sourceShard.PrimaryAlias)
+	if err != nil {
+		return err
+	}
+	sequenceTables := make([]string, 0, len(tables))
+	for _, table := range tables {
+		sequenceTables = append(sequenceTables, table+"_seq")
+	}
+	req := &tabletmanagerdatapb.GetSchemaRequest{Tables: append(append([]string{}, tables...), sequenceTables...)}
+	sourceSchema, err := schematools.GetSchema(ctx, wr.ts, wr.tmc, sourceShard.PrimaryAlias, req)
+	if err != nil {
+		return err
+	}
+	tableDefs := make(map[string]*tabletmanagerdatapb.TableDefinition, len(sourceSchema.TableDefinitions))
+	for _, td := range sourceSchema.TableDefinitions {
+		tableDefs[td.Name] = td
+	}
+	dbName := topoproto.TabletDbName(primary.Tablet)
+	exec := func(query string) (*sqltypes.Result, error) {
+		qr, err := wr.tmc.ExecuteFetchAsDba(ctx, primary.Tablet, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+			Query:        []byte(query),
+			DbName:       dbName,
+			MaxRows:      1,
+			ReloadSchema: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return sqltypes.Proto3ToResult(qr), nil
+	}
+
+	var report strings.Builder
+	sortedTables := append([]string{}, tables...)
+	sort.Strings(sortedTables)
+	for _, table := range sortedTables {
+		td := tableDefs[table]
+		if td == nil {
+			continue
+		}
+		column, err := autoIncrementColumn(td.Schema)
+		if err != nil {
+			return vterrors.Wrapf(err, "failed to parse the schema of table %s", table)
+		}
+		if column == "" {
+			continue
+		}
+		vtable := targetVSchema.Tables[table]
+		if vtable == nil {
+			wr.Logger().Warningf("Table %s is not in the vschema of the sharded target keyspace, no sequence is created for its %s column", table, column)
+			continue
+		}
+		if vtable.AutoIncrement != nil {
+			continue
+		}
+		sequenceTable := table + "_seq"
+		if vtable := srcVSchema.Tables[sequenceTable]; tableDefs[sequenceTable] != nil && (vtable == nil || vtable.Type != vindexes.TypeSequence) {
+			return fmt.Errorf("cannot create the sequence table %s for table %s: a table with the same name that is not a sequence exists in the %s keyspace",
+				sequenceTable, table, sourceKeyspace)
+		}
+		query := sqlparser.BuildParsedQuery(sqlCreateSequenceTable, sqlescape.EscapeID(sequenceTable))
+		if _, err := exec(query.Query); err != nil {
+			return vterrors.Wrapf(err, "failed to create the sequence table %s in the %s keyspace", sequenceTable, sourceKeyspace)
+		}
+		query = sqlparser.BuildParsedQuery(sqlGetMaxSequenceVal, sqlescape.EscapeID(column), sqlescape.EscapeID(dbName), sqlescape.EscapeID(table))
+		qr, err := exec(query.Query)
+		if err != nil || len(qr.Rows) != 1 {
+			return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "failed to get the max value of %s.%s in order to initialize the sequence table %s: %v",
+				table, column, sequenceTable, err)
+		}
+		var maxID int64
+		if !qr.Rows[0][0].IsNull() {
+			if maxID, err = qr.Rows[0][0].ToInt64(); err != nil {
+				return vterrors.Wrapf(err, "failed to get the max value of %s.%s in order to initialize the sequence table %s", table, column, sequenceTable)
+			}
+		}
+		nextID := maxID + 1
+		query = sqlparser.BuildParsedQuery(sqlInitSequenceTable, sqlescape.EscapeID(dbName), sqlescape.EscapeID(sequenceTable), nextID, nextID, nextID)
+		if _, err := exec(query.Query); err != nil {
+			return vterrors.Wrapf(err, "failed to initialize the sequence table %s in the %s keyspace", sequenceTable, sourceKeyspace)
+		}
+		srcVSchema.Tables[sequenceTable] = &vschemapb.Table{Type: vindexes.TypeSequence}
+		vtable.AutoIncrement = &vschemapb.AutoIncrement{
+			Column:   column,
+			Sequence: sourceKeyspace + "." + sequenceTable,
+		}
+		fmt.Fprintf(&report, "  %s.%s for the %s column of table %s, starting at %d\n", sourceKeyspace, sequenceTable, column, table, nextID)
+	}
+	if report.Len() == 0 {
+		wr.Logger().Printf("No auto_increment column needs a sequence in the target keyspace\n")
+		return nil
+	}
+	if err := wr.ts.SaveVSchema(ctx, sourceKeyspace, srcVSchema); err != nil {
+		return vterrors.Wrapf(err, "failed to save vschema for source keyspace %s", sourceKeyspace)
+	}
+	wr.Logger().Printf("Created the following sequence tables, which the target vschema now uses:\n%s", report.String())
+	wr.Logger().Printf("Use --initialize-target-sequences when switching writes so that they start past the rows inserted in the meantime\n")
+	return nil
+}
+
+// autoIncrementColumn returns the auto_increment column of a table, if it has one.
+func autoIncrementColumn(createTable string) (string, error) {
+	stmt, err := sqlparser.ParseStrictDDL(createTable)
+	if err != nil {
+		return "", err
+	}
+	create, ok := stmt.(*sqlparser.CreateTable)
+	if !ok || create.TableSpec == nil {
+		return "", fmt.Errorf("not a create table statement: %s", createTable)
+	}
+	for _, col := range create.TableSpec.Columns {
+		if col.Type.Options != nil && col.Type.Options.Autoincrement {
+			return col.Name.String(), nil
+		}
+	}
+	return "", nil
+}
+
 func shouldInclude(table string, excludes []string) bool {
 	// We filter out internal tables elsewhere when processing SchemaDefinition
 	// structures built from the GetSchema database related API calls. In this
@@ -129,11 +278,15 @@ func shouldInclude(table string, excludes []string) bool {
 // MoveTables initiates moving table(s) over to another keyspace
 func (wr *Wrangler) MoveTables(ctx context.Context, workflow, sourceKeyspace, targetKeyspace, tableSpecs,
 	cell, tabletTypesStr string, allTables bool, excludeTables string, autoStart, stopAfterCopy bool,
-	externalCluster string, dropForeignKeys, deferSecondaryKeys bool, sourceTimeZone, onDDL string, sourceShards []string) (err error) {
+	externalCluster string, dropForeignKeys, deferSecondaryKeys bool, sourceTimeZone, onDDL string, sourceShards []string, autoSequences bool) (err error) {
 	//FIXME validate tableSpecs, allTables, excludeTables
 	var tables []string
 	var externalTopo *topo.Server
 
+	if autoSequences && externalCluster != "" {
+		return fmt.Errorf("sequences cannot be created automatically when moving tables from an external cluster")
+	}
+
 	if externalCluster != "" { // when the source is an external mysql cluster mounted using the Mount command
 		externalTopo, err = wr.ts.OpenExternalVitessClusterServer(ctx, externalCluster)
 		if err != nil {
@@ -311,6 +464,15 @@ func (wr *Wrangler) MoveTables(ctx context.Context, workflow, sourceKeyspace, ta
 			return err
 		}
 
+		if autoSequences {
+			if origVSchema == nil {
+				origVSchema = proto.Clone(vschema).(*vschemapb.Keyspace)
+			}
+			if err := wr.createAutoSequences(ctx, sourceKeyspace, vschema, tables); err != nil {
+				return err
+			}
+		}
+
 		if vschema != nil {
 			// We added to the vschema.
 			if err := wr.ts.SaveVSchema(ctx, targetKeyspace, vschema); err != nil {
@@ -793,7 +955,9 @@ func generateColDef(lines []string, sourceVindexCol, vindexFromCol string) (stri
 }
 
 // ExternalizeVindex externalizes a lookup vindex that's finished backfilling or has caught up.
-func (wr *Wrangler) ExternalizeVindex(ctx context.Context, qualifiedVindexName string) error {
+// Unless verifySampleSize is 0, the lookup vindex is first verified by VerifyLookupVindex,
+// and it is not externalized if mismatches are found.
+func (wr *Wrangler) ExternalizeVindex(ctx context.Context, qualifiedVindexName string, verifySampleSize int) error {
 	splits := strings.Split(qualifiedVindexName, ".")
 	if len(splits) != 2 {
 		return fmt.Errorf("vindex name should be of the form keyspace.vindex: %s", qualifiedVindexName)
@@ -880,6 +1044,17 @@ func (wr *Wrangler) ExternalizeVindex(ctx context.Context, qualifiedVindexName s
 		return err
 	}
 
+	if verifySampleSize > 0 {
+		verification, err := wr.VerifyLookupVindex(ctx, qualifiedVindexName, verifySampleSize)
+		if err != nil {
+			return vterrors.Wrapf(err, "failed to verify vindex %s", qualifiedVindexName)
+		}
+		if verification.MismatchCount > 0 {
+			return fmt.Errorf("vindex %s cannot be externalized, its verification failed: %s", qualifiedVindexName, verification)
+		}
+		wr.Logger().Infof("Verified vindex %s: %s", qualifiedVindexName, verification)
+	}
+
 	if sourceVindex.Owner != "" {
 		// If there is an owner, we have to delete the streams.
 		err := forAllTargets(func(targetShard *topo.ShardInfo) error {
@@ -1077,14 +1252,18 @@ func (wr *Wrangler) buildMaterializer(ctx context.Context, ms *vtctldatapb.Mater
 		return nil, fmt.Errorf("no target shards specified for workflow %s ", ms.Workflow)
 	}
 
-	return &materializer{
+	mz := &materializer{
 		wr:            wr,
 		ms:            ms,
 		targetVSchema: targetVSchema,
 		sourceShards:  sourceShards,
 		targetShards:  targetShards,
 		isPartial:     isPartial,
-	}, nil
+	}
+	if err := mz.validateJoins(); err != nil {
+		return nil, err
+	}
+	return mz, nil
 }
 
 func (mz *materializer) getSourceTableDDLs(ctx context.Context) (map[string]string, error) {
@@ -1291,6 +1470,7 @@ func (mz *materializer) generateInserts(ctx context.Context, targetShard *topo.S
 			SourceTimeZone:  mz.ms.SourceTimeZone,
 			TargetTimeZone:  mz.ms.TargetTimeZone,
 			OnDdl:           binlogdatapb.OnDDLAction(binlogdatapb.OnDDLAction_value[mz.ms.OnDdl]),
+			DelaySeconds:    mz.ms.DelaySeconds,
 		}
 		for _, ts := range mz.ms.TableSettings {
 			rule := &binlogdatapb.Rule{
@@ -1312,7 +1492,9 @@ func (mz *materializer) generateInserts(ctx context.Context, targetShard *topo.S
 				return "", fmt.Errorf("unrecognized statement: %s", ts.SourceExpression)
 			}
 			filter := ts.SourceExpression
-			if mz.targetVSchema.Keyspace.Sharded && mz.targetVSchema.Tables[ts.TargetTable].Type != vindexes.TypeReference {
+			// The rows of a join are evaluated by the target from the joined tables,
+			// which are already filtered by their own keyranges.
+			if mz.targetVSchema.Keyspace.Sharded && mz.targetVSchema.Tables[ts.TargetTable].Type != vindexes.TypeReference && !isJoin(sel) {
 				cv, err := vindexes.FindBestColVindex(mz.targetVSchema.Tables[ts.TargetTable])
 				if err != nil {
 					return "", err
@@ -1370,6 +1552,8 @@ func (mz *materializer) generateInserts(ctx context.Context, targetShard *topo.S
 			workflowType = binlogdatapb.VReplicationWorkflowType_MoveTables
 		case vtctldatapb.MaterializationIntent_CREATELOOKUPINDEX:
 			workflowType = binlogdatapb.VReplicationWorkflowType_CreateLookupIndex
+		case vtctldatapb.MaterializationIntent_DELAYEDREPLICA:
+			workflowType = binlogdatapb.VReplicationWorkflowType_DelayedReplica
 		}
 
 		tabletTypeStr := mz.ms.TabletTypes
@@ -1415,6 +1599,176 @@ func matchColInSelect(col sqlparser.IdentifierCI, sel *sqlparser.Select) (*sqlpa
 	return nil, fmt.Errorf("could not find vindex column %v", sqlparser.String(col))
 }
 
+// isJoin returns true if the rows of the select come from a join.
+func isJoin(sel *sqlparser.Select) bool {
+	if len(sel.From) != 1 {
+		return false
+	}
+	_, ok := sel.From[0].(*sqlparser.JoinTableExpr)
+	return ok
+}
+
+// validateJoins validates the tables that are materialized from a join.
+// The target evaluates the join against the tables that the workflow
+// materializes, so the joined tables must be materialized by the workflow
+// under their own names. If the target keyspace is sharded, the rows that
+// join must also be on the same shard: the joined tables must be sharded
+// by the same vindex as the target table, and joined on their vindex columns.
+func (mz *materializer) validateJoins() error {
+	materialized := make(map[string]bool)
+	for _, ts := range mz.ms.TableSettings {
+		if ts.SourceExpression == "" {
+			materialized[ts.TargetTable] = true
+			continue
+		}
+		if table, err := sqlparser.TableFromStatement(ts.SourceExpression); err == nil && table.Name.String() == ts.TargetTable {
+			materialized[ts.TargetTable] = true
+		}
+	}
+	for _, ts := range mz.ms.TableSettings {
+		if ts.SourceExpression == "" {
+			continue
+		}
+		stmt, err := sqlparser.Parse(ts.SourceExpression)
+		if err != nil {
+			// The error is reported when the streams are created.
+			continue
+		}
+		sel, ok := stmt.(*sqlparser.Select)
+		if !ok || !isJoin(sel) {
+			continue
+		}
+		tables := make(map[string]string)
+		var conditions []sqlparser.Expr
+		if err := collectJoin(sel.From[0], tables, &conditions); err != nil {
+			return fmt.Errorf("%v: %v", ts.TargetTable, err)
+		}
+		for _, table := range tables {
+			if !materialized[table] {
+				return fmt.Errorf("table %s joined by %s must be materialized by the workflow from the source table of the same name", table, ts.TargetTable)
+			}
+		}
+		if mz.targetVSchema.Keyspace.Sharded && mz.targetVSchema.Tables[ts.TargetTable].Type != vindexes.TypeReference {
+			if err := mz.validateShardedJoin(ts.TargetTable, sel, tables, conditions); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// collectJoin collects the tables of a join by their aliases,
+// and the conditions that join them.
+func collectJoin(tableExpr sqlparser.TableExpr, tables map[string]string, conditions *[]sqlparser.Expr) error {
+	switch tableExpr := tableExpr.(type) {
+	case *sqlparser.JoinTableExpr:
+		if err := collectJoin(tableExpr.LeftExpr, tables, conditions); err != nil {
+			return err
+		}
+		if err := collectJoin(tableExpr.RightExpr, tables, conditions); err != nil {
+			return err
+		}
+		if tableExpr.Condition != nil && tableExpr.Condition.On != nil {
+			*conditions = sqlparser.SplitAndExpression(*conditions, tableExpr.Condition.On)
+		}
+		return nil
+	case *sqlparser.AliasedTableExpr:
+		tableName, ok := tableExpr.Expr.(sqlparser.TableName)
+		if !ok {
+			return fmt.Errorf("unsupported table expression in join: %v", sqlparser.String(tableExpr))
+		}
+		alias := tableExpr.As.String()
+		if alias == "" {
+			alias = tableName.Name.String()
+		}
+		tables[alias] = tableName.Name.String()
+		return nil
+	}
+	return fmt.Errorf("unsupported table expression in join: %v", sqlparser.String(tableExpr))
+}
+
+// validateShardedJoin validates that the rows of the join of a sharded target
+// keyspace are on the same shard as the rows that they are joined from.
+// Reference tables are on every shard, so they can be joined on any column.
+func (mz *materializer) validateShardedJoin(targetTable string, sel *sqlparser.Select, tables map[string]string, conditions []sqlparser.Expr) error {
+	cv, err := vindexes.FindBestColVindex(mz.targetVSchema.Tables[targetTable])
+	if err != nil {
+		return err
+	}
+	if len(cv.Columns) != 1 {
+		return fmt.Errorf("table %s must be sharded by a vindex with a single column to be materialized from a join", targetTable)
+	}
+	vindexCols := make(map[string]sqlparser.IdentifierCI)
+	for alias, table := range tables {
+		vt := mz.targetVSchema.Tables[table]
+		if vt == nil {
+			return fmt.Errorf("table %s not found in vschema for keyspace %s", table, mz.ms.TargetKeyspace)
+		}
+		if vt.Type == vindexes.TypeReference {
+			continue
+		}
+		jcv, err := vindexes.FindBestColVindex(vt)
+		if err != nil {
+			return err
+		}
+		if jcv.Name != cv.Name || len(jcv.Columns) != 1 {
+			return fmt.Errorf("table %s joined by %s must be sharded by the vindex %s of %s", table, targetTable, cv.Name, targetTable)
+		}
+		vindexCols[alias] = jcv.Columns[0]
+	}
+	isVindexCol := func(expr sqlparser.Expr) (string, bool) {
+		col, ok := expr.(*sqlparser.ColName)
+		if !ok {
+			return "", false
+		}
+		alias := col.Qualifier.Name.String()
+		vindexCol, ok := vindexCols[alias]
+		return alias, ok && col.Name.Equal(vindexCol)
+	}
+
+	// The sharded tables must all be joined to each other by equalities of their vindex columns.
+	linked := make(map[string]string)
+	var find func(alias string) string
+	find = func(alias string) string {
+		if parent, ok := linked[alias]; ok && parent != alias {
+			return find(parent)
+		}
+		return alias
+	}
+	for _, cond := range conditions {
+		cmp, ok := cond.(*sqlparser.ComparisonExpr)
+		if !ok || cmp.Operator != sqlparser.EqualOp {
+			continue
+		}
+		left, ok := isVindexCol(cmp.Left)
+		if !ok {
+			continue
+		}
+		right, ok := isVindexCol(cmp.Right)
+		if !ok {
+			continue
+		}
+		linked[find(left)] = find(right)
+	}
+	root := ""
+	for alias := range vindexCols {
+		if root == "" {
+			root = find(alias)
+		} else if find(alias) != root {
+			return fmt.Errorf("the tables joined by %s must be joined on the columns of their vindex %s", targetTable, cv.Name)
+		}
+	}
+
+	col, err := matchColInSelect(cv.Columns[0], sel)
+	if err != nil {
+		return err
+	}
+	if alias, ok := isVindexCol(col); !ok || alias == "" {
+		return fmt.Errorf("the vindex column %s of %s must be selected from the vindex column of a joined table: %v", cv.Columns[0].String(), targetTable, sqlparser.String(col))
+	}
+	return nil
+}
+
 func (mz *materializer) createStreams(ctx context.Context, insertsMap map[string]string) error {
 	return mz.forAllTargets(func(target *topo.ShardInfo) error {
 		inserts := insertsMap[target.ShardName()]
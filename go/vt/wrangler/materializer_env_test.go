@@ -320,6 +320,11 @@ func (tmc *testMaterializerTMClient) ExecuteFetchAsDba(ctx context.Context, tabl
 	return tmc.VReplicationExec(ctx, tablet, string(req.Query))
 }
 
+func (tmc *testMaterializerTMClient) ExecuteFetchAsApp(ctx context.Context, tablet *topodatapb.Tablet, usePool bool, req *tabletmanagerdatapb.ExecuteFetchAsAppRequest) (*querypb.QueryResult, error) {
+	// Reuse VReplicationExec
+	return tmc.VReplicationExec(ctx, tablet, string(req.Query))
+}
+
 func (tmc *testMaterializerTMClient) verifyQueries(t *testing.T) {
 	t.Helper()
 
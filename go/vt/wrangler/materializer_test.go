@@ -70,7 +70,7 @@ func TestMigrateTables(t *testing.T) {
 	env.tmc.expectVRQuery(200, mzSelectIDQuery, &sqltypes.Result{})
 	env.tmc.expectVRQuery(200, mzUpdateQuery, &sqltypes.Result{})
 
-	err := env.wr.MoveTables(ctx, "workflow", "sourceks", "targetks", "t1", "", "", false, "", true, false, "", false, false, "", defaultOnDDL, nil)
+	err := env.wr.MoveTables(ctx, "workflow", "sourceks", "targetks", "t1", "", "", false, "", true, false, "", false, false, "", defaultOnDDL, nil, false)
 	require.NoError(t, err)
 	vschema, err := env.wr.ts.GetSrvVSchema(ctx, env.cell)
 	require.NoError(t, err)
@@ -113,11 +113,11 @@ func TestMissingTables(t *testing.T) {
 	env.tmc.expectVRQuery(200, mzSelectIDQuery, &sqltypes.Result{})
 	env.tmc.expectVRQuery(200, mzUpdateQuery, &sqltypes.Result{})
 
-	err := env.wr.MoveTables(ctx, "workflow", "sourceks", "targetks", "t1,tyt", "", "", false, "", true, false, "", false, false, "", defaultOnDDL, nil)
+	err := env.wr.MoveTables(ctx, "workflow", "sourceks", "targetks", "t1,tyt", "", "", false, "", true, false, "", false, false, "", defaultOnDDL, nil, false)
 	require.EqualError(t, err, "table(s) not found in source keyspace sourceks: tyt")
-	err = env.wr.MoveTables(ctx, "workflow", "sourceks", "targetks", "t1,tyt,t2,txt", "", "", false, "", true, false, "", false, false, "", defaultOnDDL, nil)
+	err = env.wr.MoveTables(ctx, "workflow", "sourceks", "targetks", "t1,tyt,t2,txt", "", "", false, "", true, false, "", false, false, "", defaultOnDDL, nil, false)
 	require.EqualError(t, err, "table(s) not found in source keyspace sourceks: tyt,txt")
-	err = env.wr.MoveTables(ctx, "workflow", "sourceks", "targetks", "t1", "", "", false, "", true, false, "", false, false, "", defaultOnDDL, nil)
+	err = env.wr.MoveTables(ctx, "workflow", "sourceks", "targetks", "t1", "", "", false, "", true, false, "", false, false, "", defaultOnDDL, nil, false)
 	require.NoError(t, err)
 }
 
@@ -175,7 +175,7 @@ func TestMoveTablesAllAndExclude(t *testing.T) {
 			env.tmc.expectVRQuery(200, insertPrefix, &sqltypes.Result{})
 			env.tmc.expectVRQuery(200, mzSelectIDQuery, &sqltypes.Result{})
 			env.tmc.expectVRQuery(200, mzUpdateQuery, &sqltypes.Result{})
-			err = env.wr.MoveTables(ctx, "workflow", "sourceks", "targetks", "", "", "", tcase.allTables, tcase.excludeTables, true, false, "", false, false, "", defaultOnDDL, nil)
+			err = env.wr.MoveTables(ctx, "workflow", "sourceks", "targetks", "", "", "", tcase.allTables, tcase.excludeTables, true, false, "", false, false, "", defaultOnDDL, nil, false)
 			require.NoError(t, err)
 			require.EqualValues(t, tcase.want, targetTables(ctx, env))
 		})
@@ -211,7 +211,7 @@ func TestMoveTablesStopFlags(t *testing.T) {
 		env.tmc.expectVRQuery(200, mzSelectIDQuery, &sqltypes.Result{})
 		// -auto_start=false is tested by NOT expecting the update query which sets state to RUNNING
 		err = env.wr.MoveTables(ctx, "workflow", "sourceks", "targetks", "t1", "",
-			"", false, "", false, true, "", false, false, "", defaultOnDDL, nil)
+			"", false, "", false, true, "", false, false, "", defaultOnDDL, nil, false)
 		require.NoError(t, err)
 		env.tmc.verifyQueries(t)
 	})
@@ -239,7 +239,7 @@ func TestMigrateVSchema(t *testing.T) {
 	env.tmc.expectVRQuery(200, mzSelectIDQuery, &sqltypes.Result{})
 	env.tmc.expectVRQuery(200, mzUpdateQuery, &sqltypes.Result{})
 
-	err := env.wr.MoveTables(ctx, "workflow", "sourceks", "targetks", `{"t1":{}}`, "", "", false, "", true, false, "", false, false, "", defaultOnDDL, nil)
+	err := env.wr.MoveTables(ctx, "workflow", "sourceks", "targetks", `{"t1":{}}`, "", "", false, "", true, false, "", false, false, "", defaultOnDDL, nil, false)
 	require.NoError(t, err)
 	vschema, err := env.wr.ts.GetSrvVSchema(ctx, env.cell)
 	require.NoError(t, err)
@@ -254,6 +254,88 @@ func TestMigrateVSchema(t *testing.T) {
 	}
 }
 
+func TestMoveTablesAutoSequences(t *testing.T) {
+	ms := &vtctldatapb.MaterializeSettings{
+		Workflow:       "workflow",
+		SourceKeyspace: "sourceks",
+		TargetKeyspace: "targetks",
+		TableSettings: []*vtctldatapb.TableMaterializeSettings{{
+			TargetTable:      "t1",
+			SourceExpression: "select * from t1",
+		}, {
+			TargetTable:      "t2",
+			SourceExpression: "select * from t2",
+		}},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	env := newTestMaterializerEnv(t, ctx, ms, []string{"0"}, []string{"0"})
+	defer env.close()
+
+	env.tmc.schema["sourceks.t1"].TableDefinitions[0].Schema = "create table t1 (id bigint not null auto_increment, val varchar(64), primary key (id))"
+	env.tmc.schema["sourceks.t2"].TableDefinitions[0].Schema = "create table t2 (id bigint not null, val varchar(64), primary key (id))"
+	err := env.wr.ts.SaveVSchema(ctx, "sourceks", &vschemapb.Keyspace{})
+	require.NoError(t, err)
+	err = env.wr.ts.SaveVSchema(ctx, "targetks", &vschemapb.Keyspace{
+		Sharded: true,
+		Vindexes: map[string]*vschemapb.Vindex{
+			"hash": {Type: "hash"},
+		},
+		Tables: map[string]*vschemapb.Table{
+			"t1": {ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "id", Name: "hash"}}},
+			"t2": {ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "id", Name: "hash"}}},
+		},
+	})
+	require.NoError(t, err)
+
+	env.tmc.expectVRQuery(100, "create table if not exists `t1_seq` (id int, next_id bigint, cache bigint, primary key(id)) comment 'vitess_sequence'", &sqltypes.Result{})
+	env.tmc.expectVRQuery(100, "select max(`id`) as maxval from `vt_sourceks`.`t1`", sqltypes.MakeTestResult(sqltypes.MakeTestFields("maxval", "int64"), "10"))
+	env.tmc.expectVRQuery(100, "insert into `vt_sourceks`.`t1_seq` (id, next_id, cache) values (0, 11, 1000) on duplicate key update next_id = if(next_id < 11, 11, next_id)", &sqltypes.Result{RowsAffected: 1})
+	env.tmc.expectVRQuery(100, mzCheckJournal, &sqltypes.Result{})
+	env.tmc.expectVRQuery(200, mzSelectFrozenQuery, &sqltypes.Result{})
+	env.tmc.expectVRQuery(200, insertPrefix, &sqltypes.Result{})
+	env.tmc.expectVRQuery(200, mzSelectIDQuery, &sqltypes.Result{})
+	env.tmc.expectVRQuery(200, mzUpdateQuery, &sqltypes.Result{})
+
+	err = env.wr.MoveTables(ctx, "workflow", "sourceks", "targetks", "t1,t2", "", "", false, "", true, false, "", false, false, "", defaultOnDDL, nil, true)
+	require.NoError(t, err)
+	env.tmc.verifyQueries(t)
+
+	sourceVSchema, err := env.wr.ts.GetVSchema(ctx, "sourceks")
+	require.NoError(t, err)
+	require.Equal(t, map[string]*vschemapb.Table{"t1_seq": {Type: vindexes.TypeSequence}}, sourceVSchema.Tables)
+	targetVSchema, err := env.wr.ts.GetVSchema(ctx, "targetks")
+	require.NoError(t, err)
+	require.Equal(t, &vschemapb.AutoIncrement{Column: "id", Sequence: "sourceks.t1_seq"}, targetVSchema.Tables["t1"].AutoIncrement)
+	require.Nil(t, targetVSchema.Tables["t2"].AutoIncrement)
+
+	err = env.wr.MoveTables(ctx, "workflow", "sourceks", "targetks", "t1", "", "", false, "", true, false, "ext1", false, false, "", defaultOnDDL, nil, true)
+	require.EqualError(t, err, "sequences cannot be created automatically when moving tables from an external cluster")
+}
+
+func TestAutoIncrementColumn(t *testing.T) {
+	testcases := []struct {
+		createTable string
+		want        string
+		wantErr     bool
+	}{
+		{createTable: "create table t1 (id bigint not null auto_increment, val varchar(64), primary key (id))", want: "id"},
+		{createTable: "create table t1 (c1 int, c2 bigint unsigned auto_increment, key (c2))", want: "c2"},
+		{createTable: "create table t1 (id bigint not null, primary key (id))", want: ""},
+		{createTable: "drop table t1", wantErr: true},
+	}
+	for _, tc := range testcases {
+		got, err := autoIncrementColumn(tc.createTable)
+		if tc.wantErr {
+			require.Error(t, err, tc.createTable)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, tc.want, got, tc.createTable)
+	}
+}
+
 func TestCreateLookupVindexFull(t *testing.T) {
 	ms := &vtctldatapb.MaterializeSettings{
 		Workflow:       "lkp_vdx",
@@ -1770,7 +1852,7 @@ func TestExternalizeVindex(t *testing.T) {
 			env.tmc.expectVRQuery(210, deleteQuery, &sqltypes.Result{})
 		}
 
-		err := env.wr.ExternalizeVindex(context.Background(), tcase.input)
+		err := env.wr.ExternalizeVindex(context.Background(), tcase.input, 0)
 		if tcase.err != "" {
 			if err == nil || !strings.Contains(err.Error(), tcase.err) {
 				t.Errorf("ExternalizeVindex(%s) err: %v, must contain %v", tcase.input, err, tcase.err)
@@ -2664,6 +2746,193 @@ func TestMaterializerNoVindexInExpression(t *testing.T) {
 	require.EqualError(t, err, "could not find vindex column c1")
 }
 
+func TestMaterializerJoin(t *testing.T) {
+	ms := &vtctldatapb.MaterializeSettings{
+		Workflow:       "workflow",
+		SourceKeyspace: "sourceks",
+		TargetKeyspace: "targetks",
+		TableSettings: []*vtctldatapb.TableMaterializeSettings{{
+			TargetTable:      "orders",
+			SourceExpression: "select * from orders",
+			CreateDdl:        "t1ddl",
+		}, {
+			TargetTable:      "customer",
+			SourceExpression: "",
+			CreateDdl:        "t2ddl",
+		}, {
+			TargetTable:      "order_view",
+			SourceExpression: "select o.id as order_id, c.id as customer_id, c.name from orders as o join customer as c on o.customer_id = c.id",
+			CreateDdl:        "t3ddl",
+		}},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	env := newTestMaterializerEnv(t, ctx, ms, []string{"0"}, []string{"-80", "80-"})
+	defer env.close()
+
+	vs := &vschemapb.Keyspace{
+		Sharded: true,
+		Vindexes: map[string]*vschemapb.Vindex{
+			"hash": {
+				Type: "hash",
+			},
+		},
+		Tables: map[string]*vschemapb.Table{
+			"orders": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{
+					Column: "customer_id",
+					Name:   "hash",
+				}},
+			},
+			"customer": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{
+					Column: "id",
+					Name:   "hash",
+				}},
+			},
+			"order_view": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{
+					Column: "customer_id",
+					Name:   "hash",
+				}},
+			},
+		},
+	}
+
+	if err := env.topoServ.SaveVSchema(context.Background(), "targetks", vs); err != nil {
+		t.Fatal(err)
+	}
+
+	env.tmc.expectVRQuery(200, mzSelectFrozenQuery, &sqltypes.Result{})
+	env.tmc.expectVRQuery(210, mzSelectFrozenQuery, &sqltypes.Result{})
+	env.tmc.expectVRQuery(
+		200,
+		insertPrefix+
+			`.*match:\\"orders\\" filter:\\"select.*orders where in_keyrange\(customer_id.*targetks\.hash.*-80.*`+
+			`match:\\"order_view\\" filter:\\"select o.id as order_id, c.id as customer_id, c.name from orders as o join customer as c on o.customer_id = c.id\\"}`,
+		&sqltypes.Result{},
+	)
+	env.tmc.expectVRQuery(
+		210,
+		insertPrefix+
+			`.*match:\\"orders\\" filter:\\"select.*orders where in_keyrange\(customer_id.*targetks\.hash.*80-.*`+
+			`match:\\"order_view\\" filter:\\"select o.id as order_id, c.id as customer_id, c.name from orders as o join customer as c on o.customer_id = c.id\\"}`,
+		&sqltypes.Result{},
+	)
+	env.tmc.expectVRQuery(200, mzUpdateQuery, &sqltypes.Result{})
+	env.tmc.expectVRQuery(210, mzUpdateQuery, &sqltypes.Result{})
+
+	err := env.wr.Materialize(ctx, ms)
+	require.NoError(t, err)
+	env.tmc.verifyQueries(t)
+}
+
+func TestMaterializerJoinErrors(t *testing.T) {
+	vs := &vschemapb.Keyspace{
+		Sharded: true,
+		Vindexes: map[string]*vschemapb.Vindex{
+			"hash": {
+				Type: "hash",
+			},
+			"xxhash": {
+				Type: "xxhash",
+			},
+		},
+		Tables: map[string]*vschemapb.Table{
+			"orders": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{
+					Column: "customer_id",
+					Name:   "hash",
+				}},
+			},
+			"customer": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{
+					Column: "id",
+					Name:   "hash",
+				}},
+			},
+			"product": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{
+					Column: "id",
+					Name:   "xxhash",
+				}},
+			},
+			"order_view": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{
+					Column: "customer_id",
+					Name:   "hash",
+				}},
+			},
+		},
+	}
+	testcases := []struct {
+		name   string
+		tables []string
+		join   string
+		err    string
+	}{{
+		name:   "joined table not materialized",
+		tables: []string{"orders"},
+		join:   "select o.id as order_id, c.id as customer_id from orders as o join customer as c on o.customer_id = c.id",
+		err:    "table customer joined by order_view must be materialized by the workflow from the source table of the same name",
+	}, {
+		name:   "different vindex",
+		tables: []string{"orders", "product"},
+		join:   "select o.id as order_id, o.customer_id, p.id as product_id from orders as o join product as p on o.product_id = p.id",
+		err:    "table product joined by order_view must be sharded by the vindex hash of order_view",
+	}, {
+		name:   "not joined on the vindex columns",
+		tables: []string{"orders", "customer"},
+		join:   "select o.id as order_id, c.id as customer_id from orders as o join customer as c on o.id = c.id",
+		err:    "the tables joined by order_view must be joined on the columns of their vindex hash",
+	}, {
+		name:   "vindex column not selected from a vindex column",
+		tables: []string{"orders", "customer"},
+		join:   "select o.id as order_id, o.id as customer_id, c.id as cid from orders as o join customer as c on o.customer_id = c.id",
+		err:    "the vindex column customer_id of order_view must be selected from the vindex column of a joined table: o.id",
+	}, {
+		name:   "derived table",
+		tables: []string{"orders"},
+		join:   "select o.id as order_id, c.id as customer_id from orders as o join (select id from orders) as c on o.customer_id = c.id",
+		err:    "order_view: unsupported table expression in join: (select id from orders) as c",
+	}}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ms := &vtctldatapb.MaterializeSettings{
+				Workflow:       "workflow",
+				SourceKeyspace: "sourceks",
+				TargetKeyspace: "targetks",
+			}
+			for _, table := range tc.tables {
+				ms.TableSettings = append(ms.TableSettings, &vtctldatapb.TableMaterializeSettings{
+					TargetTable: table,
+					CreateDdl:   "t1ddl",
+				})
+			}
+			ms.TableSettings = append(ms.TableSettings, &vtctldatapb.TableMaterializeSettings{
+				TargetTable:      "order_view",
+				SourceExpression: tc.join,
+				CreateDdl:        "t2ddl",
+			})
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			env := newTestMaterializerEnv(t, ctx, ms, []string{"0"}, []string{"-80", "80-"})
+			defer env.close()
+
+			if err := env.topoServ.SaveVSchema(context.Background(), "targetks", vs); err != nil {
+				t.Fatal(err)
+			}
+
+			env.tmc.expectVRQuery(200, mzSelectFrozenQuery, &sqltypes.Result{})
+			env.tmc.expectVRQuery(210, mzSelectFrozenQuery, &sqltypes.Result{})
+			err := env.wr.Materialize(ctx, ms)
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
 func TestStripForeignKeys(t *testing.T) {
 	tcs := []struct {
 		desc string
@@ -2942,7 +3211,7 @@ func TestMoveTablesDDLFlag(t *testing.T) {
 			env.tmc.expectVRQuery(200, mzUpdateQuery, &sqltypes.Result{})
 
 			err := env.wr.MoveTables(ctx, "workflow", "sourceks", "targetks", "t1", "",
-				"", false, "", false, true, "", false, false, "", onDDLAction, nil)
+				"", false, "", false, true, "", false, false, "", onDDLAction, nil, false)
 			require.NoError(t, err)
 		})
 	}
@@ -18,7 +18,7 @@ package wrangler
 
 import (
 	"fmt"
-	"sort"
+	"strings"
 	"sync"
 
 	"context"
@@ -27,9 +27,11 @@ import (
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
 	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/localvtctldclient"
 
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
 // GetPermissions returns the permissions set on a remote tablet
@@ -101,58 +103,48 @@ func (wr *Wrangler) ValidatePermissionsShard(ctx context.Context, keyspace, shar
 // ValidatePermissionsKeyspace validates all the permissions are the same
 // in a keyspace
 func (wr *Wrangler) ValidatePermissionsKeyspace(ctx context.Context, keyspace string) error {
-	// find all the shards
-	shards, err := wr.ts.GetShardNames(ctx, keyspace)
+	resp, err := wr.ValidatePermissionsKeyspaceWithProgress(ctx, &vtctldatapb.ValidatePermissionsKeyspaceRequest{
+		Keyspace: keyspace,
+	})
 	if err != nil {
 		return err
 	}
 
-	// corner cases
-	if len(shards) == 0 {
-		return fmt.Errorf("no shards in keyspace %v", keyspace)
-	}
-	sort.Strings(shards)
-	if len(shards) == 1 {
-		return wr.ValidatePermissionsShard(ctx, keyspace, shards[0])
+	if len(resp.Results) > 0 {
+		return fmt.Errorf("permissions diffs: %v", strings.Join(resp.Results, ", "))
 	}
+	return nil
+}
 
-	// find the reference permissions using the first shard's primary
-	si, err := wr.ts.GetShard(ctx, keyspace, shards[0])
+// ValidatePermissionsKeyspaceWithProgress validates the permissions of all the
+// tablets in a keyspace, and logs the outcome of each tablet as soon as it is
+// validated.
+func (wr *Wrangler) ValidatePermissionsKeyspaceWithProgress(ctx context.Context, req *vtctldatapb.ValidatePermissionsKeyspaceRequest) (*vtctldatapb.ValidatePermissionsKeyspaceResponse, error) {
+	stream, err := localvtctldclient.New(wr.VtctldServer()).ValidatePermissionsKeyspace(ctx, req)
 	if err != nil {
-		return err
-	}
-	if !si.HasPrimary() {
-		return fmt.Errorf("no primary in shard %v/%v", keyspace, shards[0])
-	}
-	referenceAlias := si.PrimaryAlias
-	log.Infof("Gathering permissions for reference primary %v", topoproto.TabletAliasString(referenceAlias))
-	referencePermissions, err := wr.GetPermissions(ctx, si.PrimaryAlias)
-	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// then diff with all tablets but primary 0
-	er := concurrency.AllErrorRecorder{}
-	wg := sync.WaitGroup{}
-	for _, shard := range shards {
-		aliases, err := wr.ts.FindAllTabletAliasesInShard(ctx, keyspace, shard)
+	for {
+		resp, err := stream.Recv()
 		if err != nil {
-			er.RecordError(err)
-			continue
+			return nil, err
 		}
 
-		for _, alias := range aliases {
-			if topoproto.TabletAliasEqual(alias, si.PrimaryAlias) {
-				continue
-			}
-
-			wg.Add(1)
-			go wr.diffPermissions(ctx, referencePermissions, referenceAlias, alias, &wg, &er)
+		if resp.Progress == nil {
+			return resp, nil
 		}
+		wr.logValidateProgress(req.Keyspace, resp.Progress)
 	}
-	wg.Wait()
-	if er.HasErrors() {
-		return fmt.Errorf("permissions diffs: %v", er.Error().Error())
+}
+
+// logValidateProgress logs the outcome of the validation of a single tablet.
+func (wr *Wrangler) logValidateProgress(keyspace string, progress *vtctldatapb.ValidateProgress) {
+	alias := topoproto.TabletAliasString(progress.TabletAlias)
+	if len(progress.Results) == 0 {
+		wr.Logger().Infof("[%d/%d] %v/%v %v is consistent", progress.Completed, progress.Total, keyspace, progress.Shard, alias)
+		return
 	}
-	return nil
+
+	wr.Logger().Warningf("[%d/%d] %v/%v %v has %d difference(s)", progress.Completed, progress.Total, keyspace, progress.Shard, alias, len(progress.Results))
 }
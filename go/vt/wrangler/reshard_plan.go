@@ -0,0 +1,410 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"vitess.io/vitess/go/vt/grpcclient"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vttablet/tabletconn"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+const (
+	// DefaultReshardPlanSampleSize is the default number of rows sampled from
+	// each table of each source shard to plan a reshard.
+	DefaultReshardPlanSampleSize = 1000
+
+	// ReshardPlanBalanceData balances the target shards by the size of their
+	// data.
+	ReshardPlanBalanceData = "data"
+	// ReshardPlanBalanceQPS balances the target shards by their queries per
+	// second, assuming that the queries of a source shard are spread evenly
+	// over its data.
+	ReshardPlanBalanceQPS = "qps"
+)
+
+// ReshardPlan is the recommended target shards of a reshard.
+type ReshardPlan struct {
+	Keyspace     string
+	Balance      string
+	SourceShards []string
+	// Tables are the tables whose rows were sampled.
+	Tables []string
+	// Sampled is the number of sampled rows.
+	Sampled      int
+	TargetShards []*ReshardPlanShard
+}
+
+// ReshardPlanShard is a recommended target shard.
+type ReshardPlanShard struct {
+	Name string
+	// Fraction is the estimated fraction of the data, or of the queries, of
+	// the source shards that the target shard gets.
+	Fraction float64
+}
+
+// TargetShardNames returns the names of the target shards.
+func (p *ReshardPlan) TargetShardNames() []string {
+	names := make([]string, 0, len(p.TargetShards))
+	for _, shard := range p.TargetShards {
+		names = append(names, shard.Name)
+	}
+	return names
+}
+
+// String returns a report of the plan, with the command creating the reshard.
+func (p *ReshardPlan) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d rows of tables %s sampled from shards %s of keyspace %s, target shards balanced by %s:\n",
+		p.Sampled, strings.Join(p.Tables, ", "), strings.Join(p.SourceShards, ","), p.Keyspace, p.Balance)
+	for _, shard := range p.TargetShards {
+		fmt.Fprintf(&sb, "  %s: %.1f%%\n", shard.Name, 100*shard.Fraction)
+	}
+	fmt.Fprintf(&sb, "Reshard -- --source_shards=%s --target_shards=%s Create %s.<workflow>",
+		strings.Join(p.SourceShards, ","), strings.Join(p.TargetShardNames(), ","), p.Keyspace)
+	return sb.String()
+}
+
+// reshardPlanTable is a table whose keyspace ids can be computed from its rows,
+// because its primary vindex is functional.
+type reshardPlanTable struct {
+	name    string
+	columns []string
+	vindex  vindexes.Vindex
+}
+
+// reshardPlanSample is a sampled keyspace id, and the weight of the rows it
+// stands for.
+type reshardPlanSample struct {
+	ksid   []byte
+	weight float64
+}
+
+// PlanReshard recommends the boundaries of targetShardCount shards replacing
+// the source shards of a keyspace, or all its serving shards if sourceShards
+// is empty, so that their data or their queries are evenly distributed. Up to
+// sampleSize rows of each table are sampled on each source shard, and are
+// weighted by the size of the table, and by the queries per second of the
+// shard if balance is qps. Only the tables whose primary vindex does not need
+// lookups are sampled.
+func (wr *Wrangler) PlanReshard(ctx context.Context, keyspace string, sourceShards []string, targetShardCount, sampleSize int, balance string) (*ReshardPlan, error) {
+	if sampleSize <= 0 {
+		return nil, fmt.Errorf("sample size must be positive: %d", sampleSize)
+	}
+	if balance != ReshardPlanBalanceData && balance != ReshardPlanBalanceQPS {
+		return nil, fmt.Errorf("invalid balance %q, must be %s or %s", balance, ReshardPlanBalanceData, ReshardPlanBalanceQPS)
+	}
+	vschema, err := wr.ts.GetVSchema(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	if !vschema.Sharded {
+		return nil, fmt.Errorf("keyspace %s is not sharded", keyspace)
+	}
+	tableNames := make([]string, 0, len(vschema.Tables))
+	for tableName := range vschema.Tables {
+		tableNames = append(tableNames, tableName)
+	}
+	sort.Strings(tableNames)
+	var tables []*reshardPlanTable
+	for _, tableName := range tableNames {
+		table := vschema.Tables[tableName]
+		if len(table.ColumnVindexes) == 0 {
+			continue
+		}
+		colVindex := table.ColumnVindexes[0]
+		vindexDef := vschema.Vindexes[colVindex.Name]
+		if vindexDef == nil {
+			return nil, fmt.Errorf("vindex %s of table %s not found in vschema", colVindex.Name, tableName)
+		}
+		vindex, err := vindexes.CreateVindex(vindexDef.Type, colVindex.Name, vindexDef.Params)
+		if err != nil {
+			return nil, err
+		}
+		if vindex.NeedsVCursor() {
+			wr.Logger().Warningf("Table %s is not sampled, its primary vindex %s needs lookups", tableName, colVindex.Name)
+			continue
+		}
+		columns := colVindex.Columns
+		if len(columns) == 0 {
+			columns = []string{colVindex.Column}
+		}
+		tables = append(tables, &reshardPlanTable{name: tableName, columns: columns, vindex: vindex})
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("no table of keyspace %s has a primary vindex without lookups", keyspace)
+	}
+
+	shards, keyRange, err := wr.reshardPlanSourceShards(ctx, keyspace, sourceShards)
+	if err != nil {
+		return nil, err
+	}
+	if targetShardCount == 0 {
+		targetShardCount = 2 * len(shards)
+	}
+	if targetShardCount < 0 {
+		return nil, fmt.Errorf("target shard count must be positive: %d", targetShardCount)
+	}
+
+	plan := &ReshardPlan{
+		Keyspace: keyspace,
+		Balance:  balance,
+	}
+	for _, shard := range shards {
+		plan.SourceShards = append(plan.SourceShards, shard.ShardName())
+	}
+	for _, table := range tables {
+		plan.Tables = append(plan.Tables, table.name)
+	}
+	var (
+		mu      sync.Mutex
+		samples []*reshardPlanSample
+	)
+	err = forAllShards(shards, func(shard *topo.ShardInfo) error {
+		shardSamples, err := wr.sampleReshardPlanShard(ctx, shard, tables, sampleSize, balance)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		samples = append(samples, shardSamples...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	plan.Sampled = len(samples)
+	plan.TargetShards, err = planReshardTargetShards(samples, keyRange, targetShardCount)
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// reshardPlanSourceShards returns the source shards sorted by key range, and
+// the key range they cover, which must be contiguous.
+func (wr *Wrangler) reshardPlanSourceShards(ctx context.Context, keyspace string, sourceShards []string) ([]*topo.ShardInfo, *topodatapb.KeyRange, error) {
+	var shards []*topo.ShardInfo
+	if len(sourceShards) == 0 {
+		var err error
+		if shards, err = wr.ts.GetServingShards(ctx, keyspace); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		for _, shardName := range sourceShards {
+			shard, err := wr.ts.GetShard(ctx, keyspace, shardName)
+			if err != nil {
+				return nil, nil, err
+			}
+			shards = append(shards, shard)
+		}
+	}
+	sort.Slice(shards, func(i, j int) bool {
+		return key.KeyRangeLess(shards[i].KeyRange, shards[j].KeyRange)
+	})
+	if len(shards) == 0 {
+		return nil, nil, fmt.Errorf("keyspace %s has no serving shard", keyspace)
+	}
+	keyRange := shards[0].KeyRange
+	for _, shard := range shards[1:] {
+		var ok bool
+		if keyRange, ok = key.KeyRangeAdd(keyRange, shard.KeyRange); !ok {
+			return nil, nil, fmt.Errorf("the key ranges of the source shards of keyspace %s are not contiguous at shard %s", keyspace, shard.ShardName())
+		}
+	}
+	if keyRange == nil {
+		keyRange = &topodatapb.KeyRange{}
+	}
+	return shards, keyRange, nil
+}
+
+// sampleReshardPlanShard samples the keyspace ids of the rows of the tables of
+// a source shard, weighted by the size of the tables, and by the queries per
+// second of the shard if balance is qps.
+func (wr *Wrangler) sampleReshardPlanShard(ctx context.Context, shard *topo.ShardInfo, tables []*reshardPlanTable, sampleSize int, balance string) ([]*reshardPlanSample, error) {
+	primary, err := wr.ts.GetTablet(ctx, shard.PrimaryAlias)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "GetTablet(%v) failed", shard.PrimaryAlias)
+	}
+	tableNames := make([]string, 0, len(tables))
+	for _, table := range tables {
+		tableNames = append(tableNames, table.name)
+	}
+	schema, err := wr.tmc.GetSchema(ctx, primary.Tablet, &tabletmanagerdatapb.GetSchemaRequest{Tables: tableNames, TableSchemaOnly: true})
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "GetSchema(%v) failed", primary.Alias)
+	}
+	dataLengths := make(map[string]uint64)
+	for _, td := range schema.TableDefinitions {
+		dataLengths[td.Name] = td.DataLength
+	}
+
+	var samples []*reshardPlanSample
+	var shardDataLength float64
+	for _, table := range tables {
+		buf := sqlparser.NewTrackedBuffer(nil)
+		buf.Myprintf("select ")
+		for i, col := range table.columns {
+			if i > 0 {
+				buf.Myprintf(", ")
+			}
+			buf.Myprintf("%v", sqlparser.NewIdentifierCI(col))
+		}
+		buf.Myprintf(" from %v order by rand() limit %d", sqlparser.NewIdentifierCS(table.name), sampleSize)
+		qr, err := wr.queryShardPrimary(ctx, shard, buf.String(), sampleSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(qr.Rows) == 0 {
+			continue
+		}
+		destinations, err := vindexes.Map(ctx, table.vindex, nil, qr.Rows)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "cannot map the rows of table %s", table.name)
+		}
+		// Without statistics, a sampled row stands for itself.
+		dataLength := float64(dataLengths[table.name])
+		if dataLength == 0 {
+			dataLength = float64(len(qr.Rows))
+		}
+		shardDataLength += dataLength
+		for _, destination := range destinations {
+			ksid, ok := destination.(key.DestinationKeyspaceID)
+			if !ok {
+				continue
+			}
+			samples = append(samples, &reshardPlanSample{
+				ksid:   ksid,
+				weight: dataLength / float64(len(destinations)),
+			})
+		}
+	}
+	if balance != ReshardPlanBalanceQPS || shardDataLength == 0 {
+		return samples, nil
+	}
+
+	qps, err := wr.tabletQPS(ctx, primary.Tablet)
+	if err != nil {
+		return nil, err
+	}
+	for _, sample := range samples {
+		sample.weight = qps * sample.weight / shardDataLength
+	}
+	return samples, nil
+}
+
+// tabletQPS returns the queries per second of a tablet, from its health stream.
+func (wr *Wrangler) tabletQPS(ctx context.Context, tablet *topodatapb.Tablet) (float64, error) {
+	conn, err := tabletconn.GetDialer()(tablet, grpcclient.FailFast(false))
+	if err != nil {
+		return 0, fmt.Errorf("cannot connect to tablet %v: %v", tablet.Alias, err)
+	}
+	defer conn.Close(ctx)
+	var qps float64
+	err = conn.StreamHealth(ctx, func(shr *querypb.StreamHealthResponse) error {
+		if shr.RealtimeStats == nil {
+			return fmt.Errorf("health record does not include RealtimeStats message. tablet: %v health record: %v", tablet.Alias, shr)
+		}
+		qps = shr.RealtimeStats.Qps
+		return io.EOF
+	})
+	if err != nil && err != io.EOF {
+		return 0, fmt.Errorf("could not stream health records from tablet: %v err: %v", tablet.Alias, err)
+	}
+	return qps, nil
+}
+
+// planReshardTargetShards splits the key range into count shards holding the
+// same weight of samples. Each boundary is the shortest prefix of the keyspace
+// id of the sample following the one at which the cumulated weight reaches a
+// fraction of the total weight, which separates the two samples.
+func planReshardTargetShards(samples []*reshardPlanSample, keyRange *topodatapb.KeyRange, count int) ([]*ReshardPlanShard, error) {
+	sort.SliceStable(samples, func(i, j int) bool {
+		return key.Less(samples[i].ksid, samples[j].ksid)
+	})
+	var total float64
+	for _, sample := range samples {
+		total += sample.weight
+	}
+	if count > 1 && total == 0 {
+		return nil, fmt.Errorf("no row was sampled, the target shards cannot be planned")
+	}
+
+	boundaries := [][]byte{keyRange.Start}
+	cumulated := 0.0
+	i := 0
+	for k := 1; k < count; k++ {
+		target := total * float64(k) / float64(count)
+		var boundary []byte
+		for ; i < len(samples)-1 && boundary == nil; i++ {
+			cumulated += samples[i].weight
+			if cumulated >= target {
+				boundary = shortestBoundary(samples[i].ksid, samples[i+1].ksid)
+			}
+		}
+		if boundary == nil {
+			return nil, fmt.Errorf("not enough distinct keyspace ids were sampled to plan %d target shards", count)
+		}
+		if !key.KeyRangeContains(keyRange, boundary) {
+			return nil, fmt.Errorf("sampled keyspace id %x is not in the key range of the source shards %s", boundary, key.KeyRangeString(keyRange))
+		}
+		boundaries = append(boundaries, boundary)
+	}
+	boundaries = append(boundaries, keyRange.End)
+
+	shards := make([]*ReshardPlanShard, 0, count)
+	for k := 0; k < count; k++ {
+		shardKeyRange := &topodatapb.KeyRange{Start: boundaries[k], End: boundaries[k+1]}
+		var weight float64
+		for _, sample := range samples {
+			if key.KeyRangeContains(shardKeyRange, sample.ksid) {
+				weight += sample.weight
+			}
+		}
+		shard := &ReshardPlanShard{Name: key.KeyRangeString(shardKeyRange)}
+		if total > 0 {
+			shard.Fraction = weight / total
+		}
+		shards = append(shards, shard)
+	}
+	return shards, nil
+}
+
+// shortestBoundary returns the shortest prefix of next that is greater than
+// ksid, or nil if next is not greater than ksid.
+func shortestBoundary(ksid, next []byte) []byte {
+	for l := 1; l <= len(next); l++ {
+		if key.Compare(next[:l], ksid) > 0 {
+			return next[:l]
+		}
+	}
+	return nil
+}
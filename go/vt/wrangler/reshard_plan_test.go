@@ -0,0 +1,139 @@
+/*
+Copyright 2023 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+func TestPlanReshard(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ms := &vtctldatapb.MaterializeSettings{
+		SourceKeyspace: "sourceks",
+		TargetKeyspace: "targetks",
+	}
+	env := newTestMaterializerEnv(t, ctx, ms, []string{"-80", "80-"}, []string{"0"})
+	defer env.close()
+	require.NoError(t, env.topoServ.SaveVSchema(ctx, "sourceks", &vschemapb.Keyspace{
+		Sharded: true,
+		Vindexes: map[string]*vschemapb.Vindex{
+			"hash": {
+				Type: "hash",
+			},
+			"lookup": {
+				Type: "lookup_unique",
+				Params: map[string]string{
+					"table": "targetks.lkp",
+					"from":  "c1",
+					"to":    "keyspace_id",
+				},
+			},
+		},
+		Tables: map[string]*vschemapb.Table{
+			"t1": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{
+					Name:   "hash",
+					Column: "col1",
+				}},
+			},
+			"t2": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{
+					Name:   "lookup",
+					Column: "col1",
+				}},
+			},
+		},
+	}))
+	env.tmc.schema["sourceks.t1"] = &tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{{
+			Name:       "t1",
+			DataLength: 300,
+		}},
+	}
+
+	// The hashes of 1, 2, 3 and 5 are in -80, the ones of 4 and 6 in 80-.
+	fields := sqltypes.MakeTestFields("col1", "int64")
+	env.tmc.expectVRQuery(100, "select col1 from t1 order by rand() limit 10", sqltypes.MakeTestResult(fields, "1", "2", "3", "5"))
+	env.tmc.expectVRQuery(110, "select col1 from t1 order by rand() limit 10", sqltypes.MakeTestResult(fields, "4", "6"))
+
+	plan, err := env.wr.PlanReshard(ctx, "sourceks", nil, 3, 10, ReshardPlanBalanceData)
+	require.NoError(t, err)
+	env.tmc.verifyQueries(t)
+	assert.Equal(t, []string{"-80", "80-"}, plan.SourceShards)
+	assert.Equal(t, []string{"t1"}, plan.Tables)
+	assert.Equal(t, 6, plan.Sampled)
+	// The samples of 80- weigh twice as much as the ones of -80: t1 has the
+	// same size on both shards, and half as many rows were sampled on 80-.
+	assert.Equal(t, []string{"-70", "70-f0", "f0-"}, plan.TargetShardNames())
+	assert.InDelta(t, 0.375, plan.TargetShards[0].Fraction, 0.001)
+	assert.InDelta(t, 0.375, plan.TargetShards[1].Fraction, 0.001)
+	assert.InDelta(t, 0.25, plan.TargetShards[2].Fraction, 0.001)
+	assert.Contains(t, plan.String(), "Reshard -- --source_shards=-80,80- --target_shards=-70,70-f0,f0- Create sourceks.<workflow>")
+
+	// A single source shard is split in two by default.
+	env.tmc.expectVRQuery(110, "select col1 from t1 order by rand() limit 10", sqltypes.MakeTestResult(fields, "4", "6"))
+	plan, err = env.wr.PlanReshard(ctx, "sourceks", []string{"80-"}, 0, 10, ReshardPlanBalanceData)
+	require.NoError(t, err)
+	env.tmc.verifyQueries(t)
+	assert.Equal(t, []string{"80-f0", "f0-"}, plan.TargetShardNames())
+
+	_, err = env.wr.PlanReshard(ctx, "sourceks", nil, 2, 10, "rows")
+	assert.EqualError(t, err, `invalid balance "rows", must be data or qps`)
+	_, err = env.wr.PlanReshard(ctx, "targetks", nil, 2, 10, ReshardPlanBalanceData)
+	assert.EqualError(t, err, "keyspace targetks is not sharded")
+}
+
+func TestPlanReshardTargetShards(t *testing.T) {
+	samples := func(ksids ...string) []*reshardPlanSample {
+		var samples []*reshardPlanSample
+		for _, ksid := range ksids {
+			samples = append(samples, &reshardPlanSample{ksid: []byte(ksid), weight: 1})
+		}
+		return samples
+	}
+
+	shards, err := planReshardTargetShards(samples("\x10\x01", "\x40\x01", "\x40\x02", "\x40\x03", "\xa0", "\xc0"), &topodatapb.KeyRange{}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []*ReshardPlanShard{{Name: "-4003", Fraction: 0.5}, {Name: "4003-", Fraction: 0.5}}, shards)
+
+	// Equal keyspace ids are not split.
+	shards, err = planReshardTargetShards(samples("\x10", "\x40", "\x40", "\x40", "\xa0", "\xc0"), &topodatapb.KeyRange{}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "-a0", shards[0].Name)
+	assert.InDelta(t, 4.0/6, shards[0].Fraction, 0.001)
+
+	shards, err = planReshardTargetShards(samples("\x90", "\xa0"), &topodatapb.KeyRange{Start: []byte{0x80}}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []*ReshardPlanShard{{Name: "80-a0", Fraction: 0.5}, {Name: "a0-", Fraction: 0.5}}, shards)
+
+	_, err = planReshardTargetShards(samples("\x40", "\x40"), &topodatapb.KeyRange{}, 2)
+	assert.EqualError(t, err, "not enough distinct keyspace ids were sampled to plan 2 target shards")
+	_, err = planReshardTargetShards(nil, &topodatapb.KeyRange{}, 2)
+	assert.EqualError(t, err, "no row was sampled, the target shards cannot be planned")
+}
@@ -31,6 +31,7 @@ import (
 	"vitess.io/vitess/go/vt/schema"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/localvtctldclient"
 	"vitess.io/vitess/go/vt/vtctl/schematools"
 	"vitess.io/vitess/go/vt/vttablet/tabletmanager/vreplication"
 
@@ -106,13 +107,16 @@ func (wr *Wrangler) ValidateSchemaShard(ctx context.Context, keyspace, shard str
 
 // ValidateSchemaKeyspace will diff the schema from all the tablets in the keyspace.
 func (wr *Wrangler) ValidateSchemaKeyspace(ctx context.Context, keyspace string, excludeTables []string, includeViews, skipNoPrimary bool, includeVSchema bool) error {
-	res, err := wr.VtctldServer().ValidateSchemaKeyspace(ctx, &vtctldatapb.ValidateSchemaKeyspaceRequest{
+	res, err := wr.ValidateSchemaKeyspaceWithProgress(ctx, &vtctldatapb.ValidateSchemaKeyspaceRequest{
 		Keyspace:       keyspace,
 		ExcludeTables:  excludeTables,
 		IncludeViews:   includeViews,
 		IncludeVschema: includeVSchema,
 		SkipNoPrimary:  skipNoPrimary,
 	})
+	if err != nil {
+		return err
+	}
 
 	for _, result := range res.Results {
 		wr.Logger().Printf("%s\n", result)
@@ -122,7 +126,29 @@ func (wr *Wrangler) ValidateSchemaKeyspace(ctx context.Context, keyspace string,
 		return fmt.Errorf("schema diffs: %v", res.Results)
 	}
 
-	return err
+	return nil
+}
+
+// ValidateSchemaKeyspaceWithProgress diffs the schema from all the tablets in
+// the keyspace, and logs the outcome of each tablet as soon as it is
+// validated.
+func (wr *Wrangler) ValidateSchemaKeyspaceWithProgress(ctx context.Context, req *vtctldatapb.ValidateSchemaKeyspaceRequest) (*vtctldatapb.ValidateSchemaKeyspaceStreamResponse, error) {
+	stream, err := localvtctldclient.New(wr.VtctldServer()).ValidateSchemaKeyspaceStream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.Progress == nil {
+			return resp, nil
+		}
+		wr.logValidateProgress(req.Keyspace, resp.Progress)
+	}
 }
 
 // ValidateVSchema compares the schema of each primary tablet in "keyspace/shards..." to the vschema and errs if there are differences
@@ -722,8 +722,10 @@ func (wr *Wrangler) DropTargets(ctx context.Context, targetKeyspace, workflow st
 	if err := wr.dropArtifacts(ctx, keepRoutingRules, sw); err != nil {
 		return nil, err
 	}
-	if err := ts.TopoServer().RebuildSrvVSchema(ctx, nil); err != nil {
-		return nil, err
+	if !dryRun {
+		if err := ts.TopoServer().RebuildSrvVSchema(ctx, nil); err != nil {
+			return nil, err
+		}
 	}
 	return sw.logs(), nil
 }
@@ -978,14 +980,20 @@ func (ts *trafficSwitcher) getSourceAndTargetShardsNames() ([]string, []string)
 	return sourceShards, targetShards
 }
 
-// isPartialMoveTables returns true if whe workflow is MoveTables, has the
-// same number of shards, is not covering the entire shard range, and has
-// one-to-one shards in source and target.
+// isPartialMoveTables returns true if whe workflow is MoveTables, and was
+// either created for a list of source shards, or has the same number of
+// shards, is not covering the entire shard range, and has one-to-one shards
+// in source and target.
 func (ts *trafficSwitcher) isPartialMoveTables(sourceShards, targetShards []string) (bool, error) {
 
 	if ts.MigrationType() != binlogdatapb.MigrationType_TABLES {
 		return false, nil
 	}
+	// A MoveTables created for a list of source shards is partial even if
+	// they cover the entire shard range, so that they can be switched one by one.
+	if ts.workflowSubType == binlogdatapb.VReplicationWorkflowSubType_Partial {
+		return true, nil
+	}
 
 	skr, tkr, err := getSourceAndTargetKeyRanges(sourceShards, targetShards)
 	if err != nil {
@@ -1373,7 +1381,12 @@ func (ts *trafficSwitcher) createReverseVReplication(ctx context.Context) error
 						// For non-reference tables we return an error if there's no primary
 						// vindex as it's not clear what to do.
 						if len(vtable.ColumnVindexes) > 0 && len(vtable.ColumnVindexes[0].Columns) > 0 {
-							inKeyrange = fmt.Sprintf(" where in_keyrange(%s, '%s.%s', '%s')", sqlparser.String(vtable.ColumnVindexes[0].Columns[0]),
+							// A multi-column primary vindex maps all of its columns to the keyspace id.
+							cols := make([]string, 0, len(vtable.ColumnVindexes[0].Columns))
+							for _, col := range vtable.ColumnVindexes[0].Columns {
+								cols = append(cols, sqlparser.String(col))
+							}
+							inKeyrange = fmt.Sprintf(" where in_keyrange(%s, '%s.%s', '%s')", strings.Join(cols, ", "),
 								ts.SourceKeyspaceName(), vtable.ColumnVindexes[0].Name, key.KeyRangeString(source.GetShard().KeyRange))
 						} else {
 							return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "no primary vindex found for the %s table in the %s keyspace",
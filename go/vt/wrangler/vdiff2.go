@@ -50,6 +50,31 @@ func (wr *Wrangler) VDiff2(ctx context.Context, keyspace, workflowName string, a
 		Options:   options,
 		VdiffUuid: uuid,
 	}
+	return wr.vdiff2(ctx, req)
+}
+
+// UpdateVDiff2Options applies the non-zero max diff duration and parallelism of
+// the options to a vdiff. A running vdiff is updated right away, and a stopped
+// or completed one is resumed with its updated options.
+func (wr *Wrangler) UpdateVDiff2Options(ctx context.Context, keyspace, workflowName, uuid string,
+	options *tabletmanagerdata.VDiffOptions) (*VDiffOutput, error) {
+
+	log.Infof("UpdateVDiff2Options called with %s, %s, %s, %+v", keyspace, workflowName, uuid, options)
+
+	req := &tabletmanagerdata.VDiffRequest{
+		Keyspace:      keyspace,
+		Workflow:      workflowName,
+		Action:        string(vdiff2.ResumeAction),
+		ActionArg:     uuid,
+		Options:       options,
+		VdiffUuid:     uuid,
+		UpdateOptions: true,
+	}
+	return wr.vdiff2(ctx, req)
+}
+
+func (wr *Wrangler) vdiff2(ctx context.Context, req *tabletmanagerdata.VDiffRequest) (*VDiffOutput, error) {
+	keyspace, workflowName, action := req.Keyspace, req.Workflow, vdiff2.VDiffAction(req.Action)
 	output := &VDiffOutput{
 		Request:   req,
 		Responses: make(map[string]*tabletmanagerdata.VDiffResponse),
@@ -414,8 +414,12 @@ func (wr *Wrangler) execWorkflowAction(ctx context.Context, workflow, keyspace,
 				changes = true
 				dryRunChanges.WriteString(fmt.Sprintf("  on_ddl=%q\n", binlogdatapb.OnDDLAction_name[int32(rpcReq.OnDdl)]))
 			}
+			if !textutil.ValueIsSimulatedNull(rpcReq.MaxReplicationLag) {
+				changes = true
+				dryRunChanges.WriteString(fmt.Sprintf("  max_replication_lag=%d\n", rpcReq.MaxReplicationLag))
+			}
 			if !changes {
-				return nil, fmt.Errorf("no updates were provided; use --cells, --tablet-types, or --on-ddl to specify new values")
+				return nil, fmt.Errorf("no updates were provided; use --cells, --tablet-types, --on-ddl, or --max-replication-lag to specify new values")
 			}
 			wr.Logger().Printf("The following workflow fields will be updated:\n%s", dryRunChanges.String())
 			wr.Logger().Printf("On the following tablets in the %s keyspace for workflow %s:\n",
@@ -475,57 +475,73 @@ func TestWorkflowUpdate(t *testing.T) {
 	wr := New(logger, env.topoServ, env.tmc)
 	nullSlice := textutil.SimulatedNullStringSlice                   // Used to represent a non-provided value
 	nullOnDDL := binlogdatapb.OnDDLAction(textutil.SimulatedNullInt) // Used to represent a non-provided value
+	nullMaxReplicationLag := int64(textutil.SimulatedNullInt)        // Used to represent a non-provided value
 	tests := []struct {
-		name        string
-		cells       []string
-		tabletTypes []topodatapb.TabletType
-		onDDL       binlogdatapb.OnDDLAction
-		output      string
-		wantErr     string
+		name              string
+		cells             []string
+		tabletTypes       []topodatapb.TabletType
+		onDDL             binlogdatapb.OnDDLAction
+		maxReplicationLag int64
+		output            string
+		wantErr           string
 	}{
 		{
-			name:        "no flags",
-			cells:       nullSlice,
-			tabletTypes: []topodatapb.TabletType{topodatapb.TabletType(textutil.SimulatedNullInt)},
-			onDDL:       nullOnDDL,
-			wantErr:     "no updates were provided; use --cells, --tablet-types, or --on-ddl to specify new values",
+			name:              "no flags",
+			cells:             nullSlice,
+			tabletTypes:       []topodatapb.TabletType{topodatapb.TabletType(textutil.SimulatedNullInt)},
+			onDDL:             nullOnDDL,
+			maxReplicationLag: nullMaxReplicationLag,
+			wantErr:           "no updates were provided; use --cells, --tablet-types, --on-ddl, or --max-replication-lag to specify new values",
+		},
+		{
+			name:              "only cells",
+			cells:             []string{"zone1"},
+			tabletTypes:       []topodatapb.TabletType{topodatapb.TabletType(textutil.SimulatedNullInt)},
+			onDDL:             nullOnDDL,
+			maxReplicationLag: nullMaxReplicationLag,
+			output:            "The following workflow fields will be updated:\n  cells=\"zone1\"\nOn the following tablets in the target keyspace for workflow wrWorkflow:\n  zone1-0000000200 (target/-80)\n  zone1-0000000210 (target/80-)\n",
 		},
 		{
-			name:        "only cells",
-			cells:       []string{"zone1"},
-			tabletTypes: []topodatapb.TabletType{topodatapb.TabletType(textutil.SimulatedNullInt)},
-			onDDL:       nullOnDDL,
-			output:      "The following workflow fields will be updated:\n  cells=\"zone1\"\nOn the following tablets in the target keyspace for workflow wrWorkflow:\n  zone1-0000000200 (target/-80)\n  zone1-0000000210 (target/80-)\n",
+			name:              "only tablet types",
+			cells:             nullSlice,
+			tabletTypes:       []topodatapb.TabletType{topodatapb.TabletType_PRIMARY, topodatapb.TabletType_REPLICA},
+			onDDL:             nullOnDDL,
+			maxReplicationLag: nullMaxReplicationLag,
+			output:            "The following workflow fields will be updated:\n  tablet_types=\"primary,replica\"\nOn the following tablets in the target keyspace for workflow wrWorkflow:\n  zone1-0000000200 (target/-80)\n  zone1-0000000210 (target/80-)\n",
 		},
 		{
-			name:        "only tablet types",
-			cells:       nullSlice,
-			tabletTypes: []topodatapb.TabletType{topodatapb.TabletType_PRIMARY, topodatapb.TabletType_REPLICA},
-			onDDL:       nullOnDDL,
-			output:      "The following workflow fields will be updated:\n  tablet_types=\"primary,replica\"\nOn the following tablets in the target keyspace for workflow wrWorkflow:\n  zone1-0000000200 (target/-80)\n  zone1-0000000210 (target/80-)\n",
+			name:              "only on-ddl",
+			cells:             nullSlice,
+			tabletTypes:       []topodatapb.TabletType{topodatapb.TabletType(textutil.SimulatedNullInt)},
+			onDDL:             binlogdatapb.OnDDLAction_EXEC_IGNORE,
+			maxReplicationLag: nullMaxReplicationLag,
+			output:            "The following workflow fields will be updated:\n  on_ddl=\"EXEC_IGNORE\"\nOn the following tablets in the target keyspace for workflow wrWorkflow:\n  zone1-0000000200 (target/-80)\n  zone1-0000000210 (target/80-)\n",
 		},
 		{
-			name:        "only on-ddl",
-			cells:       nullSlice,
-			tabletTypes: []topodatapb.TabletType{topodatapb.TabletType(textutil.SimulatedNullInt)},
-			onDDL:       binlogdatapb.OnDDLAction_EXEC_IGNORE,
-			output:      "The following workflow fields will be updated:\n  on_ddl=\"EXEC_IGNORE\"\nOn the following tablets in the target keyspace for workflow wrWorkflow:\n  zone1-0000000200 (target/-80)\n  zone1-0000000210 (target/80-)\n",
+			name:              "only max-replication-lag",
+			cells:             nullSlice,
+			tabletTypes:       []topodatapb.TabletType{topodatapb.TabletType(textutil.SimulatedNullInt)},
+			onDDL:             nullOnDDL,
+			maxReplicationLag: 0,
+			output:            "The following workflow fields will be updated:\n  max_replication_lag=0\nOn the following tablets in the target keyspace for workflow wrWorkflow:\n  zone1-0000000200 (target/-80)\n  zone1-0000000210 (target/80-)\n",
 		},
 		{
-			name:        "all flags",
-			cells:       []string{"zone1", "zone2"},
-			tabletTypes: []topodatapb.TabletType{topodatapb.TabletType_RDONLY, topodatapb.TabletType_SPARE},
-			onDDL:       binlogdatapb.OnDDLAction_EXEC,
-			output:      "The following workflow fields will be updated:\n  cells=\"zone1,zone2\"\n  tablet_types=\"rdonly,spare\"\n  on_ddl=\"EXEC\"\nOn the following tablets in the target keyspace for workflow wrWorkflow:\n  zone1-0000000200 (target/-80)\n  zone1-0000000210 (target/80-)\n",
+			name:              "all flags",
+			cells:             []string{"zone1", "zone2"},
+			tabletTypes:       []topodatapb.TabletType{topodatapb.TabletType_RDONLY, topodatapb.TabletType_SPARE},
+			onDDL:             binlogdatapb.OnDDLAction_EXEC,
+			maxReplicationLag: 30,
+			output:            "The following workflow fields will be updated:\n  cells=\"zone1,zone2\"\n  tablet_types=\"rdonly,spare\"\n  on_ddl=\"EXEC\"\n  max_replication_lag=30\nOn the following tablets in the target keyspace for workflow wrWorkflow:\n  zone1-0000000200 (target/-80)\n  zone1-0000000210 (target/80-)\n",
 		},
 	}
 
 	for _, tcase := range tests {
 		t.Run(tcase.name, func(t *testing.T) {
 			rpcReq := &tabletmanagerdatapb.UpdateVReplicationWorkflowRequest{
-				Cells:       tcase.cells,
-				TabletTypes: tcase.tabletTypes,
-				OnDdl:       tcase.onDDL,
+				Cells:             tcase.cells,
+				TabletTypes:       tcase.tabletTypes,
+				OnDdl:             tcase.onDDL,
+				MaxReplicationLag: tcase.maxReplicationLag,
 			}
 
 			_, err := wr.WorkflowAction(ctx, workflow, keyspace, "update", true, rpcReq)
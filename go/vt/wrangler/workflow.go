@@ -60,6 +60,7 @@ type VReplicationWorkflowParams struct {
 	SourceTimeZone            string
 	DropForeignKeys           bool
 	InitializeTargetSequences bool
+	AutoSequences             bool
 
 	// Reshard specific
 	SourceShards, TargetShards []string
@@ -374,23 +375,26 @@ func (vrw *VReplicationWorkflow) Complete() (*[]string, error) {
 	return dryRunResults, nil
 }
 
-// Cancel deletes all artifacts from a workflow which has not yet been switched
-func (vrw *VReplicationWorkflow) Cancel() error {
+// Cancel deletes all artifacts from a workflow which has not yet been switched.
+// If DryRun is set, it only returns the actions that would have been taken.
+func (vrw *VReplicationWorkflow) Cancel() (*[]string, error) {
 	ws := vrw.ws
 	if vrw.workflowType == MigrateWorkflow {
-		_, err := vrw.wr.finalizeMigrateWorkflow(vrw.ctx, ws.TargetKeyspace, ws.Workflow, "",
+		return vrw.wr.finalizeMigrateWorkflow(vrw.ctx, ws.TargetKeyspace, ws.Workflow, "",
 			true, vrw.params.KeepData, vrw.params.KeepRoutingRules, vrw.params.DryRun)
-		return err
 	}
 
 	if ws.WritesSwitched || len(ws.ReplicaCellsSwitched) > 0 || len(ws.RdonlyCellsSwitched) > 0 {
-		return fmt.Errorf(ErrWorkflowPartiallySwitched)
+		return nil, fmt.Errorf(ErrWorkflowPartiallySwitched)
 	}
-	if _, err := vrw.wr.DropTargets(vrw.ctx, vrw.ws.TargetKeyspace, vrw.ws.Workflow, vrw.params.KeepData, vrw.params.KeepRoutingRules, false); err != nil {
-		return err
+	dryRunResults, err := vrw.wr.DropTargets(vrw.ctx, vrw.ws.TargetKeyspace, vrw.ws.Workflow, vrw.params.KeepData, vrw.params.KeepRoutingRules, vrw.params.DryRun)
+	if err != nil {
+		return nil, err
 	}
-	vrw.ts = nil
-	return nil
+	if !vrw.params.DryRun {
+		vrw.ts = nil
+	}
+	return dryRunResults, nil
 }
 
 // endregion
@@ -433,7 +437,8 @@ func (vrw *VReplicationWorkflow) initMoveTables() error {
 	return vrw.wr.MoveTables(vrw.ctx, vrw.params.Workflow, vrw.params.SourceKeyspace, vrw.params.TargetKeyspace,
 		vrw.params.Tables, vrw.params.Cells, vrw.params.TabletTypes, vrw.params.AllTables, vrw.params.ExcludeTables,
 		vrw.params.AutoStart, vrw.params.StopAfterCopy, vrw.params.ExternalCluster, vrw.params.DropForeignKeys,
-		vrw.params.DeferSecondaryKeys, vrw.params.SourceTimeZone, vrw.params.OnDDL, vrw.params.SourceShards)
+		vrw.params.DeferSecondaryKeys, vrw.params.SourceTimeZone, vrw.params.OnDDL, vrw.params.SourceShards,
+		vrw.params.AutoSequences)
 }
 
 func (vrw *VReplicationWorkflow) initReshard() error {
@@ -74,7 +74,8 @@ func TestReshardingWorkflowErrorsAndMisc(t *testing.T) {
 	require.True(t, mtwf.Exists())
 	require.Errorf(t, testComplete(t, mtwf), ErrWorkflowNotFullySwitched)
 	mtwf.ws.WritesSwitched = true
-	require.Errorf(t, mtwf.Cancel(), ErrWorkflowPartiallySwitched)
+	_, err := mtwf.Cancel()
+	require.Errorf(t, err, ErrWorkflowPartiallySwitched)
 
 	tabletTypes, _, err := discovery.ParseTabletTypesAndOrder(mtwf.params.TabletTypes)
 	require.NoError(t, err)
@@ -527,7 +528,8 @@ func TestMoveTablesV2Cancel(t *testing.T) {
 	require.True(t, checkIfTableExistInVSchema(ctx, t, wf.wr.ts, "ks2", "t1"))
 	require.True(t, checkIfTableExistInVSchema(ctx, t, wf.wr.ts, "ks2", "t2"))
 
-	require.NoError(t, wf.Cancel())
+	_, err = wf.Cancel()
+	require.NoError(t, err)
 
 	validateRoutingRuleCount(ctx, t, wf.wr.ts, 0)
 
@@ -645,7 +647,44 @@ func TestReshardV2Cancel(t *testing.T) {
 	require.Equal(t, WorkflowStateNotSwitched, wf.CurrentState())
 	tme.expectNoPreviousJournals()
 	expectReshardQueries(t, tme, p)
-	require.NoError(t, wf.Cancel())
+	_, err = wf.Cancel()
+	require.NoError(t, err)
+}
+
+func TestReshardV2CancelDryRun(t *testing.T) {
+	ctx := context.Background()
+	sourceShards := []string{"-40", "40-"}
+	targetShards := []string{"-80", "80-"}
+	p := &VReplicationWorkflowParams{
+		Workflow:                        "test",
+		SourceKeyspace:                  "ks",
+		TargetKeyspace:                  "ks",
+		SourceShards:                    sourceShards,
+		TargetShards:                    targetShards,
+		Cells:                           "cell1,cell2",
+		TabletTypes:                     "replica,rdonly,primary",
+		Timeout:                         DefaultActionTimeout,
+		MaxAllowedTransactionLagSeconds: defaultMaxAllowedTransactionLagSeconds,
+		DryRun:                          true,
+	}
+	tme := newTestShardMigrater(ctx, t, sourceShards, targetShards)
+	defer tme.stopTablets(t)
+	wf, err := tme.wr.NewVReplicationWorkflow(ctx, ReshardWorkflow, p)
+	require.NoError(t, err)
+	require.NotNil(t, wf)
+	require.Equal(t, WorkflowStateNotSwitched, wf.CurrentState())
+	tme.expectNoPreviousJournals()
+	dryRunResults, err := wf.Cancel()
+	require.NoError(t, err)
+	require.NotNil(t, dryRunResults)
+	require.Contains(t, *dryRunResults, "Lock keyspace ks")
+	require.Contains(t, *dryRunResults, "Delete vreplication streams on target:")
+
+	// Nothing was actually cancelled, so the target shards must still exist.
+	for _, shard := range targetShards {
+		_, err := tme.ts.GetShard(ctx, "ks", shard)
+		require.NoError(t, err)
+	}
 }
 
 func expectReshardQueries(t *testing.T, tme *testShardMigraterEnv, params *VReplicationWorkflowParams) {
@@ -5,4 +5,5 @@ window.env = {
     'VITE_BUGSNAG_API_KEY': "",
     'VITE_DOCUMENT_TITLE': "",
     'VITE_READONLY_MODE': false,
+    'VITE_OIDC_LOGIN': false,
 };
@@ -24,9 +24,14 @@ import {
     MALFORMED_HTTP_RESPONSE_ERROR,
 } from '../errors/errorTypes';
 import * as errorHandler from '../errors/errorHandler';
+import * as login from '../util/login';
 import { describe, it, expect, vi } from 'vitest';
 
 vi.mock('../errors/errorHandler');
+vi.mock('../util/login', async () => ({
+    ...(await vi.importActual<typeof login>('../util/login')),
+    redirectToLogin: vi.fn(),
+}));
 
 // mockServerJson configures an HttpOkResponse containing the given `json`
 // for all requests made against the given `endpoint`.
@@ -119,6 +124,36 @@ describe('api/http', () => {
             }
         });
 
+        describe('OIDC login', () => {
+            it('redirects to login on unauthenticated responses', async () => {
+                import.meta.env.VITE_OIDC_LOGIN = 'true';
+
+                const endpoint = `/api/tablets`;
+                global.server.use(
+                    rest.get(`${import.meta.env.VITE_VTADMIN_API_ADDRESS}${endpoint}`, (req, res, ctx) =>
+                        res(ctx.status(401), ctx.body('unauthenticated'))
+                    )
+                );
+
+                await expect(api.vtfetch(endpoint)).rejects.toThrow('redirecting to login');
+                expect(login.redirectToLogin).toHaveBeenCalledTimes(1);
+
+                vi.mocked(login.redirectToLogin).mockClear();
+            });
+
+            it('does not redirect when OIDC login is disabled', async () => {
+                const endpoint = `/api/tablets`;
+                global.server.use(
+                    rest.get(`${import.meta.env.VITE_VTADMIN_API_ADDRESS}${endpoint}`, (req, res, ctx) =>
+                        res(ctx.status(401), ctx.body('unauthenticated'))
+                    )
+                );
+
+                await expect(api.vtfetch(endpoint)).rejects.toThrow();
+                expect(login.redirectToLogin).not.toHaveBeenCalled();
+            });
+        });
+
         describe('credentials', () => {
             it('uses the VITE_FETCH_CREDENTIALS env variable if specified', async () => {
                 import.meta.env.VITE_FETCH_CREDENTIALS = 'include';
@@ -20,6 +20,7 @@ import { HttpFetchError, HttpResponseNotOkError, MalformedHttpResponseError } fr
 import { HttpOkResponse } from './responseTypes';
 import { TabletDebugVars } from '../util/tabletDebugVars';
 import { env, isReadOnlyMode } from '../util/env';
+import { isOIDCLoginEnabled, redirectToLogin } from '../util/login';
 
 /**
  * vtfetch makes HTTP requests against the given vtadmin-api endpoint
@@ -56,6 +57,13 @@ export const vtfetch = async (endpoint: string, options: RequestInit = {}): Prom
             throw new HttpFetchError(url);
         }
 
+        if (response.status === 401 && isOIDCLoginEnabled()) {
+            // vtadmin-api rejected the request as unauthenticated; its login flow
+            // sets the id token cookie and then returns the user to this page.
+            redirectToLogin();
+            throw new Error(`[status 401] ${endpoint}: redirecting to login`);
+        }
+
         let json = null;
         try {
             json = await response.json();
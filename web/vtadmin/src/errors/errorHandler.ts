@@ -69,5 +69,6 @@ const sanitizeEnv = () =>
         'VITE_BUILD_SHA',
         'VITE_ENABLE_EXPERIMENTAL_TABLET_DEBUG_VARS',
         'VITE_FETCH_CREDENTIALS',
+        'VITE_OIDC_LOGIN',
         'VITE_VTADMIN_API_ADDRESS',
     ]);
@@ -0,0 +1,44 @@
+/**
+ * Copyright 2023 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+import { describe, it, expect } from 'vitest';
+import { isOIDCLoginEnabled, loginURL, logoutURL } from './login';
+
+describe('isOIDCLoginEnabled', () => {
+    it('is disabled by default', () => {
+        expect(isOIDCLoginEnabled()).toBe(false);
+    });
+
+    it('is enabled by VITE_OIDC_LOGIN', () => {
+        import.meta.env.VITE_OIDC_LOGIN = 'true';
+        expect(isOIDCLoginEnabled()).toBe(true);
+    });
+});
+
+describe('loginURL', () => {
+    it('points to the vtadmin-api login endpoint', () => {
+        expect(loginURL('http://vtadmin.example.com/keyspaces?foo=bar')).toEqual(
+            'http://test-api.com/oidc/login?redirect=http%3A%2F%2Fvtadmin.example.com%2Fkeyspaces%3Ffoo%3Dbar'
+        );
+    });
+});
+
+describe('logoutURL', () => {
+    it('points to the vtadmin-api logout endpoint', () => {
+        expect(logoutURL('http://vtadmin.example.com/')).toEqual(
+            'http://test-api.com/oidc/logout?redirect=http%3A%2F%2Fvtadmin.example.com%2F'
+        );
+    });
+});
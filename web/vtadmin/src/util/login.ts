@@ -0,0 +1,35 @@
+/**
+ * Copyright 2023 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+import { env } from './env';
+
+// isOIDCLoginEnabled returns whether vtadmin-api is configured with its OIDC
+// authenticator, in which case unauthenticated users are sent through its login flow.
+export const isOIDCLoginEnabled = (): boolean => env().VITE_OIDC_LOGIN === 'true';
+
+// loginURL returns the URL of vtadmin-api's OIDC login endpoint, which redirects
+// back to `redirect` once the user has logged in.
+export const loginURL = (redirect: string): string =>
+    `${env().VITE_VTADMIN_API_ADDRESS}/oidc/login?redirect=${encodeURIComponent(redirect)}`;
+
+// logoutURL returns the URL of vtadmin-api's OIDC logout endpoint.
+export const logoutURL = (redirect: string): string =>
+    `${env().VITE_VTADMIN_API_ADDRESS}/oidc/logout?redirect=${encodeURIComponent(redirect)}`;
+
+// redirectToLogin sends the user through the OIDC login flow, returning them
+// to the current page afterwards.
+export const redirectToLogin = (): void => {
+    window.location.assign(loginURL(window.location.href));
+};
@@ -43,6 +43,11 @@ declare namespace Vite {
       // to also configure vtadmin-api for role-based access control (RBAC) if needed;
       // see https://github.com/vitessio/vitess/blob/main/go/vt/vtadmin/rbac/rbac.go
       VITE_READONLY_MODE?: string;
+
+      // Optional. Defaults to "false". Set to "true" when vtadmin-api uses its "oidc" authenticator:
+      // unauthenticated requests then redirect the user to vtadmin-api's /oidc/login endpoint,
+      // which sets a cookie holding the user's id token. Requires VITE_FETCH_CREDENTIALS to be "include".
+      VITE_OIDC_LOGIN?: string;
   }
 }
 